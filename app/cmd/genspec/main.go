@@ -0,0 +1,54 @@
+// Command genspec writes the OpenAPI 3 document generated from the handler
+// annotations, which the server embeds and client SDKs are generated from.
+// Run it from the module root after changing an annotation.
+//
+// Usage:
+//
+//	genspec [-dir DIR] [-main FILE] [-out FILE] [-check]
+//
+// With -check nothing is written; genspec exits with status 1 when the
+// document on disk is out of date, so CI can fail the build.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"blog-platform/internal/infrastructure/openapi"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "module root holding the annotated packages")
+	mainFile := flag.String("main", openapi.MainFile, "file with the general API annotations, relative to -dir")
+	out := flag.String("out", openapi.OutputFile, "file the document is written to, relative to -dir")
+	check := flag.Bool("check", false, "fail if the document on disk is out of date instead of writing it")
+	flag.Parse()
+
+	doc, err := openapi.Generate(*dir, *mainFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc = append(doc, '\n')
+	path := filepath.Join(*dir, *out)
+
+	if *check {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !bytes.Equal(current, doc) {
+			fmt.Fprintf(os.Stderr, "%s is out of date; run go run ./cmd/genspec\n", path)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := os.WriteFile(path, doc, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("wrote %s\n", path)
+}
@@ -0,0 +1,130 @@
+// Command migrate applies, reverts and lists the database migrations
+// embedded in the server, using the same DB_* configuration as the server.
+//
+// Usage:
+//
+//	migrate up             apply every pending migration
+//	migrate down [N]       revert the latest N migrations (default 1)
+//	migrate status         list the migrations and whether they are applied
+//	migrate force VERSION  record migrations up to VERSION as applied without running them
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/database"
+	"blog-platform/internal/infrastructure/database/migrations"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, `Usage: migrate <command> [argument]
+
+Commands:
+  up             apply every pending migration
+  down [N]       revert the latest N migrations (default 1)
+  status         list the migrations and whether they are applied
+  force VERSION  record migrations up to VERSION as applied without running
+                 them, e.g. to adopt an existing database or clear a failed
+                 migration after fixing the schema by hand; 0 clears all`)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db.DB.DB, db.DriverName())
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	ctx := context.Background()
+	switch command := flag.Arg(0); command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		report("applied", applied)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "down":
+		steps := 1
+		if flag.NArg() > 1 {
+			if steps, err = strconv.Atoi(flag.Arg(1)); err != nil || steps <= 0 {
+				log.Fatalf("invalid number of migrations %q", flag.Arg(1))
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		report("reverted", reverted)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printStatus(statuses)
+	case "force":
+		if flag.NArg() < 2 {
+			log.Fatal("force needs a version")
+		}
+		version, err := strconv.Atoi(flag.Arg(1))
+		if err != nil || version < 0 {
+			log.Fatalf("invalid version %q", flag.Arg(1))
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("forced version %d\n", version)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// report prints the migrations a command ran
+func report(verb string, ran []migrations.Migration) {
+	if len(ran) == 0 {
+		fmt.Printf("no migrations %s\n", verb)
+		return
+	}
+	for _, m := range ran {
+		fmt.Printf("%s %s\n", verb, m)
+	}
+}
+
+// printStatus prints a table of the migrations
+func printStatus(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Applied:
+			state = "applied"
+		}
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -9,9 +9,6 @@
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 
-// @host localhost:8080
-// @BasePath /api/v1
-
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
@@ -20,19 +17,54 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Embedded time zone database, so post schedules in IANA time zones
+	// work in images without one
+	_ "time/tzdata"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"blog-platform/internal/infrastructure/cache"
 	"blog-platform/internal/infrastructure/config"
 	"blog-platform/internal/infrastructure/database"
+	"blog-platform/internal/infrastructure/diagnostics"
+	"blog-platform/internal/infrastructure/health"
 	http "blog-platform/internal/infrastructure/http"
+	"blog-platform/internal/infrastructure/importer"
 	"blog-platform/internal/infrastructure/logging"
+	"blog-platform/internal/infrastructure/mail"
+	"blog-platform/internal/infrastructure/queue"
+	"blog-platform/internal/infrastructure/ratelimit"
+	"blog-platform/internal/infrastructure/realtime"
+	"blog-platform/internal/infrastructure/redis"
+	"blog-platform/internal/infrastructure/related"
 	"blog-platform/internal/infrastructure/repository"
+	"blog-platform/internal/infrastructure/scheduler"
+	"blog-platform/internal/infrastructure/searchping"
+	"blog-platform/internal/infrastructure/shutdown"
+	"blog-platform/internal/infrastructure/storage"
+	"blog-platform/internal/infrastructure/stripe"
+	"blog-platform/internal/infrastructure/views"
+	"blog-platform/internal/infrastructure/webhooks"
 
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/scim"
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/tip"
+	"blog-platform/internal/domain/user"
 	infraauth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/auth/idp"
 )
 
 func main() {
@@ -44,53 +76,355 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
-	defer db.Close()
 
 	// Create Echo instance
 	e := echo.New()
 
-	// Basic middleware
-	e.Use(middleware.Logger())
+	// Basic middleware; requests are logged by the access logger of the routes
 	e.Use(middleware.Recover())
 
-	// Health check endpoint with database status
-	e.GET("/health", func(c echo.Context) error {
-		// Test database connection
-		if err := db.Ping(); err != nil {
-			return c.JSON(503, map[string]string{
-				"status":   "error",
-				"service":  "blog-platform",
-				"database": "disconnected",
-				"error":    err.Error(),
-			})
+	// Initialize logger with configuration
+	logger := logging.NewLogger(cfg)
+
+	// Apply pending migrations before anything reads the schema
+	if cfg.Database.AutoMigrate {
+		applied, err := db.Migrate(context.Background())
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+		for _, m := range applied {
+			logger.Info(context.Background(), "migration applied", "migration", m.String())
 		}
+	}
 
-		return c.JSON(200, map[string]string{
-			"status":   "ok",
-			"service":  "blog-platform",
-			"database": "connected",
-		})
+	// Components are stopped in reverse order of registration on exit, so
+	// each finishes its in-flight work before what it depends on closes
+	hooks := shutdown.New(time.Duration(cfg.Server.ShutdownHookTimeout)*time.Second, logger)
+	hooks.Register("database", func(ctx context.Context) error {
+		return db.Close()
 	})
 
-	// Initialize logger with configuration
-	logger := logging.NewLogger(cfg)
-
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	postRepo := repository.NewPostRepository(db.DB)
+	progressRepo := repository.NewReadingProgressRepository(db.DB)
+	bookmarkRepo := repository.NewBookmarkRepository(db.DB)
+	tagRepo := repository.NewTagRepository(db.DB)
+	seriesRepo := repository.NewSeriesRepository(db.DB)
+	preferenceRepo := repository.NewPreferenceRepository(db.DB)
 	commentRepo := repository.NewCommentRepository(db.DB)
+	commentReportRepo := repository.NewCommentReportRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	followRepo := repository.NewFollowRepository(db.DB)
+	profileRepo := repository.NewProfileRepository(db.DB)
+	redirectRepo := repository.NewRedirectRepository(db.DB)
+	changeRepo := repository.NewChangeRepository(db.DB)
+	mediaRepo := repository.NewMediaRepository(db.DB)
+	exportRepo := repository.NewExportRepository(db.DB)
+	emailChangeRepo := repository.NewEmailChangeRepository(db.DB)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db.DB)
+	identityRepo := repository.NewIdentityRepository(db.DB)
+	magicLinkRepo := repository.NewMagicLinkRepository(db.DB)
+	passkeyRepo := repository.NewPasskeyRepository(db.DB)
+	jobRepo := repository.NewJobRepository(db.DB)
+	announcementRepo := repository.NewAnnouncementRepository(db.DB)
+	bannerRepo := repository.NewBannerRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	passwordResetRepo := repository.NewPasswordResetRepository(db.DB)
+	scimAccountRepo := repository.NewScimAccountRepository(db.DB)
+	scimGroupRepo := repository.NewScimGroupRepository(db.DB)
+	tipRepo := repository.NewTipRepository(db.DB)
+	billingRepo := repository.NewBillingRepository(db.DB)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.DB)
+	importRepo := repository.NewImportRepository(db.DB)
+	txManager := repository.NewTxManager(db.DB)
+
+	// Initialize storage of uploaded files
+	objectStorage, err := storage.NewObjectStorage(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize object storage:", err)
+	}
+
+	// Initialize mailer
+	mailer := mail.NewMailer(cfg, logger)
+
+	// Initialize JWT service, token blacklist, login attempt and rate limit stores
+	jwtService, err := infraauth.NewJWTServiceFromConfig(cfg)
+	if err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+	redisClient := redis.NewClient(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	hooks.Register("redis", func(ctx context.Context) error {
+		return redisClient.Close()
+	})
+	tokenBlacklist := infraauth.NewTokenBlacklist(cfg, redisClient)
+	loginAttempts := infraauth.NewLoginAttemptStore(cfg, redisClient)
+	rateLimits := ratelimit.NewStore(cfg, redisClient)
+	// Hot reads are cached aside; writes through the services invalidate them
+	cacheStore := cache.New(cfg, redisClient)
+	cacheTTL := time.Duration(cfg.Cache.TTL) * time.Second
+
+	// Initialize two-factor storage with encrypted secrets
+	secretCipher, err := infraauth.NewSecretCipher(cfg.TwoFactor.EncryptionKey)
+	if err != nil {
+		log.Fatal("Failed to initialize two-factor encryption:", err)
+	}
+	twoFactorRepo := repository.NewTwoFactorRepository(db.DB, secretCipher)
+	ssoConnectionRepo := repository.NewSSOConnectionRepository(db.DB, secretCipher)
+	ssoMembershipRepo := repository.NewSSOMembershipRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB, secretCipher)
+
+	// Initialize the background job queue
+	jobQueue := queue.New(jobRepo, queue.Options{
+		BatchSize:   cfg.JobQueue.BatchSize,
+		MaxAttempts: cfg.JobQueue.MaxAttempts,
+	}, logger)
+	if cfg.SearchPing.Enabled {
+		jobQueue.Handle(service.JobSearchPing, searchping.NewNotifierFromConfig(cfg, logger).Handle)
+	}
+
+	// Page sizes shared by the services and the list endpoints
+	pagination := service.PaginationPolicy{
+		Posts:     service.PageLimits{Default: cfg.Pagination.Posts.DefaultLimit, Max: cfg.Pagination.Posts.MaxLimit},
+		Comments:  service.PageLimits{Default: cfg.Pagination.Comments.DefaultLimit, Max: cfg.Pagination.Comments.MaxLimit},
+		Users:     service.PageLimits{Default: cfg.Pagination.Users.DefaultLimit, Max: cfg.Pagination.Users.MaxLimit},
+		AuditLogs: service.PageLimits{Default: cfg.Pagination.AuditLogs.DefaultLimit, Max: cfg.Pagination.AuditLogs.MaxLimit},
+	}
+
+	// Initialize the audit log, recording sensitive actions of the services
+	auditService := service.NewAuditService(auditRepo, service.AuditSettings{Pagination: pagination.AuditLogs}, logger)
 
-	// Initialize JWT service
-	jwtService := infraauth.NewJWTService(cfg.JWT.Secret)
+	// Services publish domain events on the bus; notifications, webhooks
+	// and cache invalidation subscribe to them below
+	eventBus := service.NewEventBus(logger)
+
+	// Initialize security notifications, emailed by queued jobs
+	securitySettings := service.SecuritySettings{
+		BaseURL:          cfg.Server.BaseURL,
+		ReportLinkTTL:    time.Duration(cfg.Account.ReportLinkTTL) * time.Hour,
+		PasswordResetTTL: time.Duration(cfg.Account.PasswordResetTTL) * time.Hour,
+	}
+	securityService := service.NewSecurityService(userRepo, passwordResetRepo, infraauth.NewReportSigner(cfg.JWT.Secret), refreshTokenRepo, mailer, jobQueue, auditService, eventBus, securitySettings, logger)
+	jobQueue.Handle(service.JobSecurityNotification, securityService.SendNotification)
+
+	// Content events are sent to the webhooks admins registered through
+	// queued deliveries, retried as long as jobs are
+	webhookSettings := service.WebhookSettings{MaxAttempts: jobQueue.MaxAttempts()}
+	webhookService := service.NewWebhookService(webhookRepo, webhookRepo, webhooks.NewSenderFromConfig(cfg), jobQueue, auditService, webhookSettings, logger)
+	jobQueue.Handle(service.JobWebhookDelivery, webhookService.Deliver)
+	webhookService.Subscribe(eventBus)
 
 	// Initialize domain services
-	userService := service.NewUserService(userRepo, logger)
-	postService := service.NewPostService(postRepo, logger)
-	commentService := service.NewCommentService(commentRepo, logger)
-	authService := service.NewAuthService(userService, jwtService, logger)
+	emailNormalizer := user.NewEmailNormalizer(cfg.Email.CanonicalProviders)
+	userSettings := service.UserSettings{
+		BaseURL:              cfg.Server.BaseURL,
+		EmailChangeTTL:       time.Duration(cfg.Email.ChangeTokenTTL) * time.Hour,
+		DeletionGracePeriod:  time.Duration(cfg.Account.DeletionGracePeriod) * time.Hour,
+		MaxLoginAttempts:     cfg.Account.LockoutThreshold,
+		LockoutWindow:        time.Duration(cfg.Account.LockoutWindow) * time.Minute,
+		Pagination:           pagination.Users,
+		MagicLinkTTL:         time.Duration(cfg.MagicLink.TTL) * time.Minute,
+		MaxMagicLinkRequests: cfg.MagicLink.MaxRequests,
+		CacheTTL:             cacheTTL,
+	}
+	userService := service.NewUserService(userRepo, emailChangeRepo, userRepo, identityRepo, magicLinkRepo, loginAttempts, cacheStore, emailNormalizer, mailer, auditService, securityService, eventBus, userSettings, logger)
+	userService.Subscribe(eventBus)
+	postSettings := service.PostSettings{
+		NotifySearchEngines: cfg.SearchPing.Enabled,
+		Pagination:          pagination.Posts,
+		ConflictWindow:      time.Duration(cfg.Publishing.ConflictWindow) * time.Minute,
+		CacheTTL:            cacheTTL,
+		TrendingWindow:      time.Duration(cfg.Trending.Window) * time.Hour,
+		TrendingHalfLife:    time.Duration(cfg.Trending.HalfLife) * time.Hour,
+		TrendingRefresh:     time.Duration(cfg.Trending.RefreshInterval) * time.Minute,
+	}
+	// Post views are buffered in memory and flushed in batches
+	viewCounter := views.New(postRepo, logger)
+	// Plan limits only apply when subscriptions are paid through Stripe
+	var billingService billing.Service
+	var webhookVerifier billing.WebhookVerifier
+	var planGate billing.Gate
+	if cfg.Billing.Enabled {
+		billingService = service.NewBillingService(billingRepo, auditService, logger)
+		webhookVerifier = stripe.NewWebhookVerifier(cfg.Billing.StripeWebhookSecret, stripe.DefaultTolerance, cfg.Billing.PricePlans)
+		planGate = billingService
+	}
+	postService := service.NewPostService(postRepo, commentRepo, txManager, cacheStore, jobQueue, viewCounter, auditService, planGate, eventBus, related.New(cfg, postRepo), postSettings, logger)
+	postService.Subscribe(eventBus)
+	progressService := service.NewReadingProgressService(progressRepo, postRepo, logger)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, postRepo, logger)
+	// Tips are paid through Stripe Checkout to the Connect accounts of authors
+	var tipService tip.Service
+	var paymentProvider tip.PaymentProvider
+	if cfg.Tips.Enabled {
+		paymentWebhooks := stripe.NewWebhookVerifier(cfg.Tips.StripeWebhookSecret, stripe.DefaultTolerance, nil)
+		paymentProvider = stripe.NewPaymentProvider(cfg.Tips.StripeSecretKey, stripe.DefaultAPIURL, paymentWebhooks, nil)
+		tipSettings := service.TipSettings{
+			BaseURL:  cfg.Server.BaseURL,
+			Currency: cfg.Tips.Currency,
+			Limits:   tip.Limits{MinAmount: int64(cfg.Tips.MinAmount), MaxAmount: int64(cfg.Tips.MaxAmount)},
+		}
+		tipService = service.NewTipService(tipRepo, postService, paymentProvider, auditService, tipSettings, logger)
+	}
+	tagService := service.NewTagService(tagRepo, auditService, logger)
+	seriesService := service.NewSeriesService(seriesRepo, postRepo, txManager, eventBus, logger)
+	preferenceService := service.NewPreferenceService(preferenceRepo, logger)
+	commentSettings := service.CommentSettings{Pagination: pagination.Comments, RequireModeration: cfg.Comments.RequireModeration}
+	if cfg.Comments.TrustLevels {
+		trust := comment.DefaultTrustPolicy()
+		commentSettings.Trust = &trust
+	}
+	notificationService := service.NewNotificationService(notificationRepo, postRepo, commentRepo, eventBus, logger)
+	notificationService.Subscribe(eventBus)
+
+	// Notifications and new followers are pushed to the WebSocket
+	// connections of their users
+	realtimeHub := realtime.NewHub(realtime.SettingsFromConfig(cfg), logger)
+	service.SubscribeRealtime(eventBus, realtimeHub)
+	followService := service.NewFollowService(followRepo, userRepo, eventBus, logger)
+	profileService := service.NewProfileService(profileRepo, logger)
+	avatarService := service.NewAvatarService(profileRepo, objectStorage, logger)
+	mediaSettings := service.MediaSettings{
+		Quota:     cfg.Storage.MediaQuota,
+		OrphanAge: time.Duration(cfg.Storage.MediaOrphanAge) * time.Hour,
+	}
+	mediaService := service.NewMediaService(mediaRepo, objectStorage, planGate, mediaSettings, logger)
+	exportService := service.NewExportService(exportRepo, userRepo, profileRepo, auditService, logger)
+	redirectService := service.NewRedirectService(redirectRepo, postRepo, auditService, logger)
+	syncService := service.NewSyncService(changeRepo, logger)
+	idempotencySettings := service.IdempotencySettings{
+		TTL: time.Duration(cfg.Idempotency.TTL) * time.Hour,
+		// Requests take seconds at most; a key held for longer belongs to a
+		// server that stopped
+		LockTimeout: time.Minute,
+	}
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, idempotencySettings, logger)
+	commentService := service.NewCommentService(commentRepo, postRepo, eventBus, commentSettings, logger)
+	reportService := service.NewCommentReportService(commentReportRepo, commentRepo, service.ReportSettings{HideThreshold: cfg.Comments.ReportThreshold}, logger)
+	authSettings := service.AuthSettings{
+		AccessTokenTTL:  time.Duration(cfg.JWT.AccessTokenTTL) * time.Minute,
+		RefreshTokenTTL: time.Duration(cfg.JWT.RefreshTokenTTL) * time.Hour,
+		// Authenticator apps show the site name next to the account
+		TwoFactorChallengeTTL: time.Duration(cfg.TwoFactor.ChallengeTTL) * time.Minute,
+		TwoFactorIssuer:       cfg.ReadingView.SiteName,
+	}
+	// Passkeys also become a second factor, so they are only wired in when enabled
+	var passkeyService auth.PasskeyService
+	if cfg.WebAuthn.Enabled {
+		passkeySettings := service.PasskeySettings{
+			RelyingParty: auth.RelyingParty{
+				ID:      cfg.WebAuthn.RPID,
+				Name:    cfg.WebAuthn.RPName,
+				Origins: cfg.WebAuthn.Origins,
+			},
+			ChallengeTTL: time.Duration(cfg.WebAuthn.ChallengeTTL) * time.Minute,
+		}
+		passkeyChallenges := infraauth.NewPasskeyChallengeStore(cfg, redisClient)
+		passkeyService = service.NewPasskeyService(userService, passkeyRepo, passkeyChallenges, auditService, securityService, passkeySettings, logger)
+	}
+	// Single sign-on through the identity providers of organizations, which
+	// also restricts the other sign-in methods of their members
+	var ssoService sso.Service
+	if cfg.SSO.Enabled {
+		ssoProviders := idp.NewFactory(idp.Endpoints{BaseURL: cfg.Server.BaseURL}, nil)
+		ssoService = service.NewSSOService(userService, ssoConnectionRepo, ssoMembershipRepo, ssoProviders, auditService, logger)
+	}
+	authService := service.NewAuthService(userService, jwtService, refreshTokenRepo, tokenBlacklist, twoFactorRepo, passkeyService, ssoService, securityService, authSettings, logger)
+	// Provisioning by the identity providers of organizations
+	var scimService scim.Service
+	if cfg.SCIM.Enabled {
+		scimService = service.NewSCIMService(userService, scimAccountRepo, scimGroupRepo, refreshTokenRepo, auditService, txManager, service.SCIMSettings{Pagination: pagination.Users}, logger)
+	}
+	announcementSettings := service.AnnouncementSettings{
+		BaseURL:   cfg.Server.BaseURL,
+		SiteName:  cfg.ReadingView.SiteName,
+		BatchSize: cfg.Announcement.BatchSize,
+		Throttle:  time.Duration(cfg.Announcement.Throttle) * time.Millisecond,
+	}
+	unsubscribeSigner := infraauth.NewUnsubscribeSigner(cfg.JWT.Secret)
+	announcementService := service.NewAnnouncementService(announcementRepo, unsubscribeSigner, mailer, jobQueue, auditService, announcementSettings, logger)
+	jobQueue.Handle(service.JobAnnouncementBatch, announcementService.SendBatch)
+	importService := service.NewImportService(importRepo, importer.NewParser(), userService, postRepo, commentRepo, txManager, jobQueue, auditService, logger)
+	jobQueue.Handle(service.JobContentImport, importService.Run)
+	bannerService := service.NewBannerService(bannerRepo, auditService, logger)
+
+	// Report the state of the subsystems to admins
+	// Readiness checks of the dependencies; liveness checks none
+	checks := health.NewChecker()
+	checks.Register("database", health.DBCheck(db.DB.DB))
+	checks.Register("migrations", health.MigrationsCheck(db.DB.DB, db.DriverName()))
+	if cfg.UsesRedis() {
+		checks.Register("redis", redisClient.Ping)
+	}
+
+	diag := diagnostics.NewCollector()
+	diag.Register("database", diagnostics.DBPoolSource(db.DB.DB))
+	diag.Register("job_queue", func(ctx context.Context) (any, error) {
+		return jobQueue.Depths(ctx)
+	})
+	diag.Register("post_views", func(ctx context.Context) (any, error) {
+		return viewCounter.Stats(), nil
+	})
+	diag.Register("cache", func(ctx context.Context) (any, error) {
+		return cacheStore.Stats(), nil
+	})
+	diag.Register("realtime", func(ctx context.Context) (any, error) {
+		return realtimeHub.Stats(), nil
+	})
+	diag.Register("config", diagnostics.ConfigSource(cfg))
+
+	// Start background jobs
+	jobs := scheduler.New(logger)
+	jobs.Every("purge-deleted-accounts", time.Duration(cfg.Account.PurgeInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := userService.PurgeDeletedAccounts(ctx)
+		return err
+	})
+	jobs.Every("publish-scheduled-posts", time.Duration(cfg.Publishing.ScheduleInterval)*time.Second, func(ctx context.Context) error {
+		_, err := postService.PublishScheduledPosts(ctx)
+		return err
+	})
+	jobs.Every("collect-orphaned-media", time.Duration(cfg.Storage.MediaCleanupInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := mediaService.CollectOrphans(ctx)
+		return err
+	})
+	jobs.Every("purge-idempotency-keys", time.Duration(cfg.Idempotency.CleanupInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := idempotencyService.DeleteExpired(ctx)
+		return err
+	})
+	jobs.Every("job-queue", time.Duration(cfg.JobQueue.PollInterval)*time.Second, jobQueue.RunDue)
+	jobs.Every("flush-post-views", time.Duration(cfg.Views.FlushInterval)*time.Second, viewCounter.Flush)
+	jobs.Every("refresh-trending-posts", time.Duration(cfg.Trending.RefreshInterval)*time.Minute, func(ctx context.Context) error {
+		_, err := postService.RefreshTrending(ctx)
+		return err
+	})
+	if cfg.Comments.TrustLevels {
+		jobs.Every("evaluate-comment-trust", time.Duration(cfg.Comments.TrustInterval)*time.Minute, func(ctx context.Context) error {
+			_, err := commentService.EvaluateTrustLevels(ctx)
+			return err
+		})
+	}
+	jobs.Start()
+	hooks.Register("scheduler", jobs.Shutdown)
+	// Posts stored before reading stats were kept get them in the
+	// background; once done, later startups find none left
+	go func() {
+		if _, err := postService.ComputeMissingReadingStats(context.Background()); err != nil {
+			logger.Error(context.Background(), "failed to compute missing post reading stats", "error", err.Error())
+		}
+	}()
+	// Views counted by the last requests are written once the server has
+	// drained, while the database is still open
+	hooks.Register("post-views", viewCounter.Flush)
 
 	// Setup routes
-	http.SetupRoutes(e, cfg, userService, authService, postService, commentService, logger)
+	http.SetupRoutes(e, cfg, pagination, userService, authService, passkeyService, scimService, ssoService, postService, progressService, bookmarkService, tagService, seriesService, preferenceService, commentService, reportService, notificationService, realtimeHub, followService, profileService, avatarService, mediaService, exportService, redirectService, syncService, idempotencyService, announcementService, importService, bannerService, webhookService, auditService, securityService, billingService, webhookVerifier, tipService, paymentProvider, jwtService.JWKS(), rateLimits, checks, diag, logger)
+
+	// The server stops first, draining in-flight requests, so no new work
+	// arrives while the other components stop
+	hooks.Register("http-server", e.Shutdown)
+	// Hijacked WebSocket connections are not drained by the server; they
+	// are closed first so clients reconnect to another instance
+	hooks.Register("realtime", realtimeHub.Shutdown)
 
 	// Start server
 	port := cfg.Server.Port
@@ -98,8 +432,34 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Starting server on port %s", port)
-	if err := e.Start(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on port %s", port)
+		serverErr <- e.Start(":" + port)
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		log.Printf("Received shutdown signal")
+	case err := <-serverErr:
+		if !errors.Is(err, stdhttp.ErrServerClosed) {
+			log.Printf("Failed to start server: %v", err)
+			exitCode = 1
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := hooks.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown did not complete cleanly: %v", err)
+		exitCode = 1
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
 	}
 }
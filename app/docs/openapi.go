@@ -0,0 +1,10 @@
+// Package docs embeds the OpenAPI document of the API
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document generated from the handler annotations;
+// regenerate it with go run ./cmd/genspec
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"blog-platform/internal/domain/announcement"
+)
+
+const (
+	// defaultAnnouncementBatchSize is used when no batch size is configured
+	defaultAnnouncementBatchSize = 50
+	// reportFailureLimit caps the failed deliveries listed in a report
+	reportFailureLimit = 100
+)
+
+// AnnouncementSettings holds the configurable behaviour of the announcement
+// service
+type AnnouncementSettings struct {
+	// BaseURL is the public URL used to build unsubscribe links
+	BaseURL  string
+	SiteName string
+	// BatchSize is the number of recipients handled per queued job. A batch
+	// must be sent within the job lease, so BatchSize times Throttle should
+	// stay well below it.
+	BatchSize int
+	// Throttle is the pause between two messages, to respect the sending
+	// rate of the mail relay
+	Throttle time.Duration
+}
+
+// AnnouncementService implements the announcement.Service interface.
+// Announcements are delivered in batches by queued jobs; every recipient
+// gets a personal unsubscribe link.
+type AnnouncementService struct {
+	repo     announcement.Repository
+	tokens   announcement.UnsubscribeTokens
+	mailer   Mailer
+	jobs     JobQueue
+	audit    AuditLogger
+	settings AnnouncementSettings
+	logger   Logger
+}
+
+// NewAnnouncementService creates a new AnnouncementService instance
+func NewAnnouncementService(repo announcement.Repository, tokens announcement.UnsubscribeTokens, mailer Mailer, jobs JobQueue, audit AuditLogger, settings AnnouncementSettings, logger Logger) *AnnouncementService {
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = defaultAnnouncementBatchSize
+	}
+
+	return &AnnouncementService{
+		repo:     repo,
+		tokens:   tokens,
+		mailer:   mailer,
+		jobs:     jobs,
+		audit:    audit,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Create stores an announcement and queues its first batch
+func (s *AnnouncementService) Create(ctx context.Context, adminID int, subject, body string, segment announcement.Segment) (*announcement.Announcement, error) {
+	a, err := announcement.NewAnnouncement(adminID, subject, body, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Recipients, err = s.repo.CountRecipients(ctx, segment)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count announcement recipients", "adminID", adminID, "error", err.Error())
+		return nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		s.logger.Error(ctx, "failed to save announcement", "adminID", adminID, "error", err.Error())
+		return nil, fmt.Errorf("failed to create announcement: %w", err)
+	}
+
+	if err := s.jobs.Enqueue(ctx, JobAnnouncementBatch, AnnouncementBatchPayload{AnnouncementID: a.ID}); err != nil {
+		s.logger.Error(ctx, "failed to queue announcement", "announcementID", a.ID, "error", err.Error())
+		return nil, fmt.Errorf("failed to queue announcement: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionAnnouncementCreated,
+		UserID:   adminID,
+		Metadata: map[string]any{"announcement_id": a.ID, "recipients": a.Recipients},
+	})
+	s.logger.Info(ctx, "announcement queued", "announcementID", a.ID, "recipients", a.Recipients)
+	return a, nil
+}
+
+// GetReport returns the delivery counters and failures of an announcement
+func (s *AnnouncementService) GetReport(ctx context.Context, id int) (*announcement.Report, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := s.repo.ListDeliveries(ctx, id, announcement.DeliveryFailed, reportFailureLimit)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list failed deliveries", "announcementID", id, "error", err.Error())
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return &announcement.Report{Announcement: a, Failures: failures}, nil
+}
+
+// Unsubscribe stops announcements to the user a token was issued for
+func (s *AnnouncementService) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := s.tokens.Verify(token)
+	if err != nil {
+		return announcement.ErrInvalidUnsubscribeToken
+	}
+
+	if err := s.repo.Unsubscribe(ctx, userID); err != nil {
+		s.logger.Error(ctx, "failed to unsubscribe user", "userID", userID, "error", err.Error())
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+
+	s.logger.Info(ctx, "user unsubscribed from announcements", "userID", userID)
+	return nil
+}
+
+// SendBatch delivers the next batch of an announcement and queues the batch
+// after it. It handles JobAnnouncementBatch jobs. Undeliverable recipients
+// are recorded as failed rather than retried, so one bad address does not
+// hold up the rest; errors storing the outcome fail the job, which is then
+// retried and resumes with the recipients still pending.
+func (s *AnnouncementService) SendBatch(ctx context.Context, payload json.RawMessage) error {
+	var batch AnnouncementBatchPayload
+	if err := json.Unmarshal(payload, &batch); err != nil {
+		return fmt.Errorf("invalid announcement batch payload: %w", err)
+	}
+
+	a, err := s.repo.GetByID(ctx, batch.AnnouncementID)
+	if err != nil {
+		return err
+	}
+	if a.IsCompleted() {
+		return nil
+	}
+
+	recipients, err := s.repo.PendingRecipients(ctx, a, s.settings.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to load recipients: %w", err)
+	}
+
+	a.Status = announcement.StatusSending
+	for i, r := range recipients {
+		if i > 0 && s.settings.Throttle > 0 {
+			if err := sleep(ctx, s.settings.Throttle); err != nil {
+				return s.saveProgress(ctx, a, err)
+			}
+		}
+
+		delivery := s.deliver(ctx, a, r)
+		if err := s.repo.RecordDelivery(ctx, delivery); err != nil {
+			return s.saveProgress(ctx, a, fmt.Errorf("failed to record delivery: %w", err))
+		}
+		a.Record(delivery.Status)
+	}
+
+	if len(recipients) < s.settings.BatchSize {
+		a.Complete()
+		s.logger.Info(ctx, "announcement completed", "announcementID", a.ID, "sent", a.Sent, "failed", a.Failed, "skipped", a.Skipped)
+		return s.saveProgress(ctx, a, nil)
+	}
+
+	if err := s.saveProgress(ctx, a, nil); err != nil {
+		return err
+	}
+	return s.jobs.Enqueue(ctx, JobAnnouncementBatch, batch)
+}
+
+// deliver sends an announcement to a recipient unless they unsubscribed
+func (s *AnnouncementService) deliver(ctx context.Context, a *announcement.Announcement, r *announcement.Recipient) *announcement.Delivery {
+	delivery := &announcement.Delivery{
+		AnnouncementID: a.ID,
+		UserID:         r.UserID,
+		Email:          r.Email,
+		Status:         announcement.DeliverySent,
+		CreatedAt:      time.Now(),
+	}
+	if r.Unsubscribed {
+		delivery.Status = announcement.DeliverySkipped
+		return delivery
+	}
+
+	unsubscribeURL := s.settings.BaseURL + "/api/v1/announcements/unsubscribe?token=" + url.QueryEscape(s.tokens.Issue(r.UserID))
+	err := s.mailer.Send(ctx, EmailMessage{
+		To:      r.Email,
+		Subject: a.Subject,
+		Body: fmt.Sprintf("Hi %s,\n\n%s\n\n--\nYou receive announcements as a member of %s. To stop receiving them, open the link below:\n\n%s\n",
+			r.Name, a.Body, s.settings.SiteName, unsubscribeURL),
+		UnsubscribeURL: unsubscribeURL,
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to send announcement", "announcementID", a.ID, "userID", r.UserID, "error", err.Error())
+		delivery.Status = announcement.DeliveryFailed
+		delivery.Error = err.Error()
+	}
+	return delivery
+}
+
+// saveProgress stores the counters of an announcement and returns cause, or
+// the error storing them when there is no cause
+func (s *AnnouncementService) saveProgress(ctx context.Context, a *announcement.Announcement, cause error) error {
+	if err := s.repo.Update(ctx, a); err != nil {
+		s.logger.Error(ctx, "failed to save announcement progress", "announcementID", a.ID, "error", err.Error())
+		if cause == nil {
+			return fmt.Errorf("failed to update announcement: %w", err)
+		}
+	}
+	return cause
+}
+
+// sleep pauses for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
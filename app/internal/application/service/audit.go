@@ -0,0 +1,66 @@
+package service
+
+import "context"
+
+// Audit actions
+const (
+	AuditActionUserRegistered        = "user.registered"
+	AuditActionUserLogin             = "user.login"
+	AuditActionUserLoginFailed       = "user.login_failed"
+	AuditActionPasswordChanged       = "user.password_changed"
+	AuditActionUserDeleted           = "user.deleted"
+	AuditActionUserDeletionRequested = "user.deletion_requested"
+	AuditActionUserDeletionCancelled = "user.deletion_cancelled"
+	AuditActionUserPurged            = "user.purged"
+	AuditActionDataExported          = "user.data_exported"
+	AuditActionIdentityLinked        = "user.identity_linked"
+	AuditActionUserLocked            = "user.locked"
+	AuditActionChangeReported        = "user.change_reported"
+	AuditActionPasswordReset         = "user.password_reset"
+	AuditActionPasskeyAdded          = "user.passkey_added"
+	AuditActionPasskeyRemoved        = "user.passkey_removed"
+	AuditActionUserDeactivated       = "user.deactivated"
+	AuditActionUserReactivated       = "user.reactivated"
+	AuditActionUserProvisioned       = "user.provisioned"
+	AuditActionUserDeprovisioned     = "user.deprovisioned"
+	AuditActionRoleChanged           = "user.role_changed"
+	AuditActionSSOConnectionSaved    = "sso.connection_saved"
+	AuditActionSSOConnectionDeleted  = "sso.connection_deleted"
+	AuditActionPostDeleted           = "post.deleted"
+	AuditActionPostPinned            = "post.pinned"
+	AuditActionPostUnpinned          = "post.unpinned"
+	AuditActionPostFeatured          = "post.featured"
+	AuditActionPostUnfeatured        = "post.unfeatured"
+	AuditActionRedirectCreated       = "redirect.created"
+	AuditActionRedirectUpdated       = "redirect.updated"
+	AuditActionRedirectDeleted       = "redirect.deleted"
+	AuditActionTagRenamed            = "tag.renamed"
+	AuditActionTagsMerged            = "tag.merged"
+	AuditActionUnusedTagsDeleted     = "tag.unused_deleted"
+	AuditActionAnnouncementCreated   = "announcement.created"
+	AuditActionContentImported       = "content.imported"
+	AuditActionContentExported       = "content.exported"
+	AuditActionBannerCreated         = "banner.created"
+	AuditActionBannerUpdated         = "banner.updated"
+	AuditActionBannerDeleted         = "banner.deleted"
+	AuditActionWebhookCreated        = "webhook.created"
+	AuditActionWebhookUpdated        = "webhook.updated"
+	AuditActionWebhookDeleted        = "webhook.deleted"
+	AuditActionSubscriptionChanged   = "billing.subscription_changed"
+	AuditActionPayoutAccountLinked   = "billing.payout_account_linked"
+	AuditActionPayoutAccountUnlinked = "billing.payout_account_unlinked"
+)
+
+// AuditEvent describes a security-relevant action taken on an account
+type AuditEvent struct {
+	Action   string
+	UserID   int
+	Metadata map[string]any
+}
+
+// AuditLogger defines the interface for recording audit events.
+// Recording is best-effort: implementations report their own failures and
+// never fail the action being audited.
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent)
+}
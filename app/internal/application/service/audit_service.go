@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"blog-platform/internal/domain/audit"
+	"blog-platform/internal/domain/user"
+)
+
+// AuditSettings holds the configurable behaviour of the audit service
+type AuditSettings struct {
+	// Pagination bounds the page size of audit log lists
+	Pagination PageLimits
+}
+
+// AuditService implements the AuditLogger and audit.Service interfaces,
+// storing audit events in the audit log
+type AuditService struct {
+	repo     audit.Repository
+	settings AuditSettings
+	logger   Logger
+}
+
+// NewAuditService creates a new AuditService instance
+func NewAuditService(repo audit.Repository, settings AuditSettings, logger Logger) *AuditService {
+	return &AuditService{
+		repo:     repo,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Record stores an audit event with the client IP and ID of the request, if
+// known.
+// Events that cannot be stored are written to the application log instead.
+func (s *AuditService) Record(ctx context.Context, event AuditEvent) {
+	entry := &audit.Entry{
+		Action:    event.Action,
+		UserID:    event.UserID,
+		Metadata:  event.Metadata,
+		IPAddress: user.ClientIPFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Error(ctx, "failed to record audit event", "action", event.Action, "userID", event.UserID, "metadata", event.Metadata, "error", err.Error())
+	}
+}
+
+// ListEntries returns the audit log entries matching a filter, newest first
+func (s *AuditService) ListEntries(ctx context.Context, filter audit.Filter, limit, offset int) ([]*audit.Entry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	entries, err := s.repo.List(ctx, filter, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list audit log entries", "error", err.Error())
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	return entries, nil
+}
@@ -2,40 +2,90 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/sso"
 	"blog-platform/internal/domain/user"
 )
 
+// Default token lifetimes used when AuthSettings leaves them unset
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultChallengeTTL    = 5 * time.Minute
+	defaultTwoFactorIssuer = "Blog Platform"
+)
+
+// AuthSettings holds token lifetime configuration for the auth service
+type AuthSettings struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// TwoFactorChallengeTTL is how long the second step of a two-factor
+	// login may take
+	TwoFactorChallengeTTL time.Duration
+	// TwoFactorIssuer names the site in authenticator apps
+	TwoFactorIssuer string
+}
+
 // AuthService implements the auth.AuthService interface
 type AuthService struct {
-	userService  user.Service
-	tokenService auth.TokenService
-	logger       Logger
+	userService   user.Service
+	tokenService  auth.TokenService
+	refreshTokens auth.RefreshTokenRepository
+	blacklist     auth.TokenBlacklist
+	twoFactors    auth.TwoFactorRepository
+	// passkeys is nil when passkeys are disabled
+	passkeys      auth.PasskeyService
+	// sso is nil when single sign-on is disabled
+	sso           sso.Service
+	events        SecurityEvents
+	settings      AuthSettings
+	logger        Logger
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userService user.Service, tokenService auth.TokenService, logger Logger) auth.AuthService {
+func NewAuthService(userService user.Service, tokenService auth.TokenService, refreshTokens auth.RefreshTokenRepository, blacklist auth.TokenBlacklist, twoFactors auth.TwoFactorRepository, passkeys auth.PasskeyService, ssoService sso.Service, events SecurityEvents, settings AuthSettings, logger Logger) auth.AuthService {
+	if settings.AccessTokenTTL <= 0 {
+		settings.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if settings.RefreshTokenTTL <= 0 {
+		settings.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+	if settings.TwoFactorChallengeTTL <= 0 {
+		settings.TwoFactorChallengeTTL = defaultChallengeTTL
+	}
+	if settings.TwoFactorIssuer == "" {
+		settings.TwoFactorIssuer = defaultTwoFactorIssuer
+	}
+
 	return &AuthService{
-		userService:  userService,
-		tokenService: tokenService,
-		logger:       logger,
+		userService:   userService,
+		tokenService:  tokenService,
+		refreshTokens: refreshTokens,
+		blacklist:     blacklist,
+		twoFactors:    twoFactors,
+		passkeys:      passkeys,
+		sso:           ssoService,
+		events:        events,
+		settings:      settings,
+		logger:        logger,
 	}
 }
 
 // GenerateToken generates a JWT token for the given user
 func (a *AuthService) GenerateToken(ctx context.Context, user *user.User) (string, error) {
-	a.logger.Debug(ctx, "Generating token for user", "user_id", user.ID, "email", user.Email)
-	
 	if user == nil {
 		a.logger.Error(ctx, "Cannot generate token for nil user")
 		return "", fmt.Errorf("user cannot be nil")
 	}
 	
-	// Generate token with shorter 2 hour duration for security
-	token, err := a.tokenService.GenerateToken(user.ID, user.Email, 2*time.Hour)
+	a.logger.Debug(ctx, "Generating token for user", "user_id", user.ID, "email", user.Email)
+	
+	// Access tokens are short-lived; clients renew them with a refresh token
+	token, err := a.tokenService.GenerateToken(user.ID, user.Email, string(user.Role), a.settings.AccessTokenTTL)
 	if err != nil {
 		a.logger.Error(ctx, "Failed to generate token", "user_id", user.ID, "error", err)
 		return "", err
@@ -55,64 +105,636 @@ func (a *AuthService) ValidateToken(ctx context.Context, token string) (*auth.To
 		return nil, err
 	}
 	
+	// Tokens issued before token IDs were introduced cannot be revoked
+	if claims.ID != "" {
+		revoked, err := a.blacklist.Contains(ctx, claims.ID)
+		if err != nil {
+			// Fail closed: without the blacklist a logged-out token cannot be told apart
+			a.logger.Error(ctx, "Failed to check token blacklist", "error", err)
+			return nil, err
+		}
+		if revoked {
+			a.logger.Warn(ctx, "Revoked token presented", "user_id", claims.UserID)
+			return nil, auth.ErrTokenRevoked
+		}
+	}
+	
 	a.logger.Debug(ctx, "Token validated successfully", "user_id", claims.UserID)
 	return claims, nil
 }
 
-// Login authenticates a user and returns user data with token
-func (a *AuthService) Login(ctx context.Context, email, password string) (*user.User, string, error) {
+// Login authenticates a user and returns user data with a token pair
+func (a *AuthService) Login(ctx context.Context, email, password string) (*user.User, *auth.TokenPair, error) {
 	a.logger.Info(ctx, "User login attempt", "email", email)
 	
 	// Use the user service to authenticate
 	u, err := a.userService.Login(ctx, email, password)
 	if err != nil {
 		a.logger.Warn(ctx, "Login failed", "email", email, "error", err)
-		return nil, "", err
+		return nil, nil, err
 	}
 	
-	// Generate token for the authenticated user
-	token, err := a.GenerateToken(ctx, u)
+	if err := a.requireSSO(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	// Users with two-factor authentication get a challenge instead of tokens
+	if err := a.challengeTwoFactor(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	// Issue tokens for the authenticated user
+	tokens, err := a.issueTokens(ctx, u)
 	if err != nil {
 		a.logger.Error(ctx, "Failed to generate token after login", "error", err)
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 	
 	a.logger.Info(ctx, "User logged in successfully", "user_id", u.ID, "email", email)
-	return u, token, nil
+	return u, tokens, nil
 }
 
-// Register creates a new user and returns user data with token
-func (a *AuthService) Register(ctx context.Context, name, email, password string) (*user.User, string, error) {
+// Register creates a new user and returns user data with a token pair
+func (a *AuthService) Register(ctx context.Context, name, email, password string) (*user.User, *auth.TokenPair, error) {
 	a.logger.Info(ctx, "User registration attempt", "name", name, "email", email)
 	
+	// Addresses of organizations that require SSO register through it
+	if a.sso != nil {
+		if err := a.sso.CheckLogin(ctx, 0, email, user.DefaultRole); err != nil {
+			return nil, nil, err
+		}
+	}
+	
 	// Use the user service to register
 	u, err := a.userService.Register(ctx, name, email, password)
 	if err != nil {
 		a.logger.Warn(ctx, "Registration failed", "name", name, "email", email, "error", err)
-		return nil, "", err
+		return nil, nil, err
 	}
 	
-	// Generate token for the new user
-	token, err := a.GenerateToken(ctx, u)
+	// Issue tokens for the new user
+	tokens, err := a.issueTokens(ctx, u)
 	if err != nil {
 		a.logger.Error(ctx, "Failed to generate token after registration", "error", err)
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 	
 	a.logger.Info(ctx, "User registered successfully", "user_id", u.ID, "name", name, "email", email)
-	return u, token, nil
+	return u, tokens, nil
+}
+
+// LoginWithIdentity signs in with an external account and returns user data
+// with a token pair
+func (a *AuthService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, *auth.TokenPair, error) {
+	a.logger.Info(ctx, "External login attempt", "provider", external.Provider)
+	
+	u, err := a.userService.LoginWithIdentity(ctx, external)
+	if err != nil {
+		a.logger.Warn(ctx, "External login failed", "provider", external.Provider, "error", err)
+		return nil, nil, err
+	}
+	
+	if err := a.requireSSO(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	if err := a.challengeTwoFactor(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after external login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in through external provider", "user_id", u.ID, "provider", external.Provider)
+	return u, tokens, nil
+}
+
+// LoginWithMagicLink signs in with an emailed magic link and returns user
+// data with a token pair
+func (a *AuthService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, *auth.TokenPair, error) {
+	a.logger.Info(ctx, "Magic link login attempt", "ip", device.IP)
+	
+	u, err := a.userService.LoginWithMagicLink(ctx, token, device)
+	if err != nil {
+		a.logger.Warn(ctx, "Magic link login failed", "error", err)
+		return nil, nil, err
+	}
+	
+	if err := a.requireSSO(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	// The link stands in for the password only
+	if err := a.challengeTwoFactor(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after magic link login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in with magic link", "user_id", u.ID)
+	return u, tokens, nil
+}
+
+// LoginWithSSO signs in through the identity provider of an organization
+// and returns user data with a token pair. The identity provider is
+// responsible for the second factor, so there is no two-factor challenge.
+func (a *AuthService) LoginWithSSO(ctx context.Context, organization, state string, response sso.Response) (*user.User, *auth.TokenPair, error) {
+	if a.sso == nil {
+		return nil, nil, sso.ErrConnectionNotFound
+	}
+	a.logger.Info(ctx, "SSO login attempt", "organization", organization)
+	
+	u, err := a.sso.Authenticate(ctx, organization, state, response)
+	if err != nil {
+		a.logger.Warn(ctx, "SSO login failed", "organization", organization, "error", err)
+		return nil, nil, err
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after SSO login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in through SSO", "user_id", u.ID, "organization", organization)
+	return u, tokens, nil
+}
+
+// LoginTwoFactor exchanges a challenge token and a TOTP code for a token pair
+func (a *AuthService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*user.User, *auth.TokenPair, error) {
+	userID, err := a.tokenService.ValidateChallengeToken(challengeToken)
+	if err != nil {
+		a.logger.Warn(ctx, "Two-factor challenge validation failed", "error", err)
+		return nil, nil, err
+	}
+	
+	enrollment, err := a.twoFactors.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrTwoFactorNotFound) {
+			// Disabled since the challenge was issued
+			return nil, nil, auth.ErrInvalidToken
+		}
+		a.logger.Error(ctx, "Failed to load two-factor enrollment", "user_id", userID, "error", err)
+		return nil, nil, err
+	}
+	if !enrollment.IsEnabled() {
+		return nil, nil, auth.ErrInvalidToken
+	}
+	
+	if err := a.verifyTwoFactorCode(ctx, enrollment, code); err != nil {
+		return nil, nil, err
+	}
+	
+	u, err := a.userService.GetByID(ctx, userID)
+	if err != nil {
+		a.logger.Warn(ctx, "Two-factor login failed", "user_id", userID, "error", err)
+		return nil, nil, auth.ErrInvalidToken
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after two-factor login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in with two-factor authentication", "user_id", u.ID)
+	return u, tokens, nil
+}
+
+// BeginPasskeyTwoFactor validates a challenge token and starts answering it
+// with one of the passkeys of the user
+func (a *AuthService) BeginPasskeyTwoFactor(ctx context.Context, challengeToken string) (*auth.RequestOptions, error) {
+	if a.passkeys == nil {
+		return nil, auth.ErrInvalidToken
+	}
+	
+	userID, err := a.tokenService.ValidateChallengeToken(challengeToken)
+	if err != nil {
+		a.logger.Warn(ctx, "Two-factor challenge validation failed", "error", err)
+		return nil, err
+	}
+	
+	options, err := a.passkeys.BeginTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasskeyNotFound) {
+			// Removed since the challenge was issued
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return options, nil
+}
+
+// LoginTwoFactorWithPasskey completes a two-factor login with a passkey
+// assertion answering BeginPasskeyTwoFactor
+func (a *AuthService) LoginTwoFactorWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	if a.passkeys == nil {
+		return nil, nil, auth.ErrInvalidPasskeyChallenge
+	}
+	
+	userID, err := a.passkeys.FinishTwoFactor(ctx, credential)
+	if err != nil {
+		a.logger.Warn(ctx, "Passkey two-factor login failed", "error", err)
+		return nil, nil, err
+	}
+	
+	u, err := a.userService.GetByID(ctx, userID)
+	if err != nil {
+		a.logger.Warn(ctx, "Passkey two-factor login failed", "user_id", userID, "error", err)
+		return nil, nil, auth.ErrInvalidPasskeyAssertion
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after two-factor login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in with passkey as second factor", "user_id", u.ID)
+	return u, tokens, nil
+}
+
+// SignupWithPasskey creates a passkey-only account and returns user data
+// with a token pair
+func (a *AuthService) SignupWithPasskey(ctx context.Context, credential *auth.AttestationResponse) (*user.User, *auth.TokenPair, error) {
+	if a.passkeys == nil {
+		return nil, nil, auth.ErrInvalidPasskeyChallenge
+	}
+	
+	u, err := a.passkeys.FinishSignup(ctx, credential)
+	if err != nil {
+		a.logger.Warn(ctx, "Passkey signup failed", "error", err)
+		return nil, nil, err
+	}
+	
+	if err := a.requireSSO(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after passkey signup", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User registered with passkey", "user_id", u.ID)
+	return u, tokens, nil
+}
+
+// LoginWithPasskey signs in with a passkey and returns user data with a
+// token pair. Passkeys verify the user themselves, so there is no
+// two-factor challenge.
+func (a *AuthService) LoginWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	if a.passkeys == nil {
+		return nil, nil, auth.ErrInvalidPasskeyChallenge
+	}
+	
+	userID, err := a.passkeys.FinishLogin(ctx, credential)
+	if err != nil {
+		a.logger.Warn(ctx, "Passkey login failed", "error", err)
+		return nil, nil, err
+	}
+	
+	u, err := a.userService.CompleteLogin(ctx, userID, "passkey")
+	if err != nil {
+		a.logger.Warn(ctx, "Passkey login failed", "user_id", userID, "error", err)
+		return nil, nil, err
+	}
+	
+	if err := a.requireSSO(ctx, u); err != nil {
+		return nil, nil, err
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate token after passkey login", "error", err)
+		return nil, nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "User logged in with passkey", "user_id", u.ID)
+	return u, tokens, nil
+}
+
+// EnableTwoFactor starts a TOTP enrollment with a new secret. An earlier
+// unconfirmed enrollment is replaced.
+func (a *AuthService) EnableTwoFactor(ctx context.Context, userID int) (*auth.TwoFactorEnrollment, error) {
+	existing, err := a.twoFactors.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, auth.ErrTwoFactorNotFound) {
+		a.logger.Error(ctx, "Failed to load two-factor enrollment", "user_id", userID, "error", err)
+		return nil, err
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, auth.ErrTwoFactorAlreadyEnabled
+	}
+	
+	u, err := a.userService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	
+	enrollment, err := auth.NewTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.twoFactors.Save(ctx, enrollment); err != nil {
+		a.logger.Error(ctx, "Failed to save two-factor enrollment", "user_id", userID, "error", err)
+		return nil, err
+	}
+	
+	a.logger.Info(ctx, "Two-factor enrollment started", "user_id", userID)
+	return &auth.TwoFactorEnrollment{
+		Secret: enrollment.Secret,
+		URI:    enrollment.ProvisioningURI(a.settings.TwoFactorIssuer, u.Email),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves
+// their authenticator produces valid codes
+func (a *AuthService) ConfirmTwoFactor(ctx context.Context, userID int, code string) error {
+	enrollment, err := a.twoFactors.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if enrollment.IsEnabled() {
+		return auth.ErrTwoFactorAlreadyEnabled
+	}
+	
+	now := time.Now()
+	if !enrollment.Verify(code, now) {
+		a.logger.Warn(ctx, "Invalid two-factor confirmation code", "user_id", userID)
+		return auth.ErrInvalidTwoFactorCode
+	}
+	enrollment.ConfirmedAt = &now
+	
+	if err := a.twoFactors.Save(ctx, enrollment); err != nil {
+		a.logger.Error(ctx, "Failed to confirm two-factor enrollment", "user_id", userID, "error", err)
+		return err
+	}
+	
+	a.logger.Info(ctx, "Two-factor authentication enabled", "user_id", userID)
+	return nil
+}
+
+// DisableTwoFactor removes two-factor authentication; a current code is
+// required so a stolen session cannot turn it off
+func (a *AuthService) DisableTwoFactor(ctx context.Context, userID int, code string) error {
+	enrollment, err := a.twoFactors.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrTwoFactorNotFound) {
+			return auth.ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if !enrollment.IsEnabled() {
+		return auth.ErrTwoFactorNotEnabled
+	}
+	
+	if err := a.verifyTwoFactorCode(ctx, enrollment, code); err != nil {
+		return err
+	}
+	
+	if err := a.twoFactors.Delete(ctx, userID); err != nil {
+		a.logger.Error(ctx, "Failed to delete two-factor enrollment", "user_id", userID, "error", err)
+		return err
+	}
+	
+	a.events.Publish(ctx, SecurityEvent{Kind: SecurityEventTwoFactorDisabled, UserID: userID})
+	a.logger.Info(ctx, "Two-factor authentication disabled", "user_id", userID)
+	return nil
+}
+
+// requireSSO returns sso.ErrSSORequired when the organization of the user
+// only lets them sign in through single sign-on
+func (a *AuthService) requireSSO(ctx context.Context, u *user.User) error {
+	if a.sso == nil {
+		return nil
+	}
+	return a.sso.CheckLogin(ctx, u.ID, u.Email, u.Role)
+}
+
+// challengeTwoFactor returns a *auth.TwoFactorChallenge if the user has
+// two-factor authentication enabled or passkeys registered, and nil
+// otherwise
+func (a *AuthService) challengeTwoFactor(ctx context.Context, u *user.User) error {
+	var methods []string
+	
+	enrollment, err := a.twoFactors.GetByUserID(ctx, u.ID)
+	if err != nil && !errors.Is(err, auth.ErrTwoFactorNotFound) {
+		// Fail closed: the second factor cannot be skipped
+		a.logger.Error(ctx, "Failed to load two-factor enrollment", "user_id", u.ID, "error", err)
+		return err
+	}
+	if err == nil && enrollment.IsEnabled() {
+		methods = append(methods, auth.TwoFactorMethodTOTP)
+	}
+	
+	if a.passkeys != nil {
+		hasPasskeys, err := a.passkeys.HasPasskeys(ctx, u.ID)
+		if err != nil {
+			a.logger.Error(ctx, "Failed to check passkeys", "user_id", u.ID, "error", err)
+			return err
+		}
+		if hasPasskeys {
+			methods = append(methods, auth.TwoFactorMethodPasskey)
+		}
+	}
+	
+	if len(methods) == 0 {
+		return nil
+	}
+	
+	token, err := a.tokenService.GenerateChallengeToken(u.ID, a.settings.TwoFactorChallengeTTL)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to generate two-factor challenge", "user_id", u.ID, "error", err)
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	
+	a.logger.Info(ctx, "Two-factor challenge issued", "user_id", u.ID, "methods", methods)
+	return &auth.TwoFactorChallenge{
+		Token:     token,
+		ExpiresIn: a.settings.TwoFactorChallengeTTL,
+		Methods:   methods,
+	}
+}
+
+// verifyTwoFactorCode checks a code and stores its time step so the code
+// cannot be used again
+func (a *AuthService) verifyTwoFactorCode(ctx context.Context, enrollment *auth.TwoFactor, code string) error {
+	if !enrollment.Verify(code, time.Now()) {
+		a.logger.Warn(ctx, "Invalid two-factor code", "user_id", enrollment.UserID)
+		return auth.ErrInvalidTwoFactorCode
+	}
+	
+	if err := a.twoFactors.Save(ctx, enrollment); err != nil {
+		a.logger.Error(ctx, "Failed to record two-factor code use", "user_id", enrollment.UserID, "error", err)
+		return err
+	}
+	return nil
 }
 
-// RefreshToken refreshes an existing token
-func (a *AuthService) RefreshToken(ctx context.Context, token string) (string, error) {
+// RefreshToken rotates a refresh token: the presented token is revoked and a
+// new access/refresh pair is issued. Presenting a token that was already
+// rotated is treated as theft and revokes every refresh token of the user.
+func (a *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
 	a.logger.Debug(ctx, "Refreshing token")
 	
-	newToken, err := a.tokenService.RefreshToken(token)
+	stored, err := a.lookupRefreshToken(ctx, refreshToken)
 	if err != nil {
-		a.logger.Warn(ctx, "Token refresh failed", "error", err)
-		return "", err
+		return nil, err
+	}
+	
+	if stored.IsRevoked() {
+		a.revokeFamily(ctx, stored.UserID)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	if stored.IsExpired() {
+		a.logger.Warn(ctx, "Expired refresh token presented", "user_id", stored.UserID)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	if err := a.refreshTokens.Revoke(ctx, stored.ID); err != nil {
+		if err == auth.ErrRefreshTokenRevoked {
+			// Lost a race against another rotation of the same token
+			a.revokeFamily(ctx, stored.UserID)
+			return nil, auth.ErrInvalidRefreshToken
+		}
+		a.logger.Error(ctx, "Failed to revoke refresh token", "user_id", stored.UserID, "error", err)
+		return nil, err
+	}
+	
+	u, err := a.userService.GetByID(ctx, stored.UserID)
+	if err != nil {
+		a.logger.Warn(ctx, "Token refresh failed", "user_id", stored.UserID, "error", err)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	// Accounts pending deletion must log in again, which restores them
+	if u.IsPendingDeletion() {
+		a.logger.Warn(ctx, "Token refresh for account pending deletion", "user_id", u.ID)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	// Accounts locked after an unauthorized change must reset their password
+	if u.IsPasswordResetRequired() {
+		a.logger.Warn(ctx, "Token refresh for account awaiting password reset", "user_id", u.ID)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	// Deactivated accounts are signed out once their access token expires
+	if u.IsDeactivated() {
+		a.logger.Warn(ctx, "Token refresh for deactivated account", "user_id", u.ID)
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	
+	tokens, err := a.issueTokens(ctx, u)
+	if err != nil {
+		a.logger.Error(ctx, "Failed to issue tokens during refresh", "user_id", u.ID, "error", err)
+		return nil, err
+	}
+	
+	a.logger.Info(ctx, "Token refreshed successfully", "user_id", u.ID)
+	return tokens, nil
+}
+
+// Logout ends a session: the access token is blacklisted until it expires
+// and the refresh token is revoked. Either token may be empty, and revoking
+// an already revoked token is not an error.
+func (a *AuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	if accessToken != "" {
+		if err := a.revokeAccessToken(ctx, accessToken); err != nil {
+			return err
+		}
+	}
+	
+	if refreshToken != "" {
+		stored, err := a.lookupRefreshToken(ctx, refreshToken)
+		if err != nil {
+			return err
+		}
+		
+		if !stored.IsRevoked() {
+			if err := a.refreshTokens.Revoke(ctx, stored.ID); err != nil && err != auth.ErrRefreshTokenRevoked {
+				a.logger.Error(ctx, "Failed to revoke refresh token", "user_id", stored.UserID, "error", err)
+				return err
+			}
+		}
+	}
+	
+	a.logger.Info(ctx, "User logged out")
+	return nil
+}
+
+// revokeAccessToken adds a still valid access token to the blacklist.
+// Tokens that no longer validate need no revocation.
+func (a *AuthService) revokeAccessToken(ctx context.Context, accessToken string) error {
+	claims, err := a.tokenService.ValidateToken(accessToken)
+	if err != nil || claims.ID == "" {
+		return nil
+	}
+	
+	if err := a.blacklist.Add(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		a.logger.Error(ctx, "Failed to blacklist access token", "user_id", claims.UserID, "error", err)
+		return err
+	}
+	
+	a.logger.Info(ctx, "Access token revoked", "user_id", claims.UserID)
+	return nil
+}
+
+// issueTokens creates an access token and a new stored refresh token for the user
+func (a *AuthService) issueTokens(ctx context.Context, u *user.User) (*auth.TokenPair, error) {
+	accessToken, err := a.GenerateToken(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	
+	stored, refreshToken, err := auth.NewRefreshToken(u.ID, a.settings.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := a.refreshTokens.Create(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	
+	return &auth.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    a.settings.AccessTokenTTL,
+	}, nil
+}
+
+// lookupRefreshToken finds the stored record for a plain refresh token
+func (a *AuthService) lookupRefreshToken(ctx context.Context, refreshToken string) (*auth.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, auth.ErrInvalidRefreshToken
 	}
 	
-	a.logger.Info(ctx, "Token refreshed successfully")
-	return newToken, nil
+	stored, err := a.refreshTokens.GetByTokenHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if err == auth.ErrRefreshTokenNotFound {
+			a.logger.Warn(ctx, "Unknown refresh token presented")
+			return nil, auth.ErrInvalidRefreshToken
+		}
+		a.logger.Error(ctx, "Failed to look up refresh token", "error", err)
+		return nil, err
+	}
+	
+	return stored, nil
+}
+
+// revokeFamily revokes all refresh tokens of a user after a reuse was detected
+func (a *AuthService) revokeFamily(ctx context.Context, userID int) {
+	a.logger.Warn(ctx, "Refresh token reuse detected, revoking all sessions", "user_id", userID)
+	if err := a.refreshTokens.RevokeAllForUser(ctx, userID); err != nil {
+		a.logger.Error(ctx, "Failed to revoke refresh tokens", "user_id", userID, "error", err)
+	}
 }
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+	"io"
+	"strings"
+
+	"blog-platform/internal/domain/user"
+)
+
+// avatarQuality is the JPEG quality avatars are encoded with
+const avatarQuality = 85
+
+// AvatarService implements the user.AvatarService interface, storing
+// avatars in an ObjectStorage
+type AvatarService struct {
+	repo    user.ProfileRepository
+	storage ObjectStorage
+	logger  Logger
+}
+
+// NewAvatarService creates a new avatar service
+func NewAvatarService(repo user.ProfileRepository, storage ObjectStorage, logger Logger) *AvatarService {
+	return &AvatarService{
+		repo:    repo,
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// UploadAvatar decodes a JPEG, PNG or GIF image, stores it resized to each
+// of user.AvatarSizes as JPEG and links the profile to the largest. Each
+// upload is stored under new keys, so cached copies of the avatar it
+// replaces are never served for it.
+func (s *AvatarService) UploadAvatar(ctx context.Context, id int, r io.Reader) (*user.Avatar, error) {
+	profiles, err := s.repo.GetProfiles(ctx, []int{id})
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve profile", "userID", id, "error", err.Error())
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, user.ErrUserNotFound
+	}
+	bio, previous := profiles[0].Bio, profiles[0].AvatarURL
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	square, err := decodeAvatar(data)
+	if err != nil {
+		s.logger.Warn(ctx, "avatar rejected", "userID", id, "error", err.Error())
+		return nil, err
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate avatar key: %w", err)
+	}
+	prefix := fmt.Sprintf("avatars/%d/%s", id, hex.EncodeToString(token))
+
+	avatar := &user.Avatar{Sizes: make(map[int]string, len(user.AvatarSizes))}
+	var stored []string
+	for _, size := range user.AvatarSizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resizeSquare(square, size), &jpeg.Options{Quality: avatarQuality}); err != nil {
+			s.deleteObjects(ctx, stored)
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+
+		key := avatarKey(prefix, size)
+		if err := s.storage.Put(ctx, key, &buf, int64(buf.Len()), "image/jpeg"); err != nil {
+			s.logger.Error(ctx, "failed to store avatar", "userID", id, "key", key, "error", err.Error())
+			s.deleteObjects(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+		avatar.Sizes[size] = s.storage.URL(key)
+	}
+	avatar.URL = avatar.Sizes[user.AvatarSizes[len(user.AvatarSizes)-1]]
+
+	if err := s.repo.UpdateProfile(ctx, id, bio, avatar.URL); err != nil {
+		if err != user.ErrUserNotFound {
+			s.logger.Error(ctx, "failed to update profile avatar", "userID", id, "error", err.Error())
+		}
+		s.deleteObjects(ctx, stored)
+		return nil, err
+	}
+
+	if old, ok := s.uploadedPrefix(id, previous); ok {
+		var keys []string
+		for _, size := range user.AvatarSizes {
+			keys = append(keys, avatarKey(old, size))
+		}
+		s.deleteObjects(ctx, keys)
+	}
+
+	s.logger.Info(ctx, "avatar uploaded", "userID", id)
+	return avatar, nil
+}
+
+// uploadedPrefix returns the key prefix of an avatar URL when it points to
+// an avatar the account uploaded, rather than an external image
+func (s *AvatarService) uploadedPrefix(id int, avatarURL string) (string, bool) {
+	largest := user.AvatarSizes[len(user.AvatarSizes)-1]
+	dir := fmt.Sprintf("avatars/%d/", id)
+	base := s.storage.URL(dir)
+	if avatarURL == "" || !strings.HasPrefix(avatarURL, base) {
+		return "", false
+	}
+	token, ok := strings.CutSuffix(strings.TrimPrefix(avatarURL, base), fmt.Sprintf("-%d.jpg", largest))
+	if !ok || token == "" || strings.Contains(token, "/") {
+		return "", false
+	}
+	return dir + token, true
+}
+
+// deleteObjects removes stored objects on a best-effort basis; leftovers
+// only take up space
+func (s *AvatarService) deleteObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warn(ctx, "failed to delete avatar", "key", key, "error", err.Error())
+		}
+	}
+}
+
+// avatarKey returns the key of the image of one size of an avatar
+func avatarKey(prefix string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", prefix, size)
+}
+
+// decodeAvatar decodes an image and crops it to its centered square, with
+// transparent areas flattened onto white. The dimensions are checked
+// before the pixels are decoded.
+func decodeAvatar(data []byte) (*image.RGBA, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, user.ErrInvalidAvatar
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, user.ErrInvalidAvatar
+	}
+	if config.Width*config.Height > user.MaxAvatarPixels {
+		return nil, user.ErrAvatarTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, user.ErrInvalidAvatar
+	}
+
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(square, square.Bounds(), img, origin, draw.Over)
+	return square, nil
+}
+
+// resizeSquare resizes a square image by averaging the pixels each target
+// pixel covers; images smaller than the size are scaled up by repeating
+// pixels
+func resizeSquare(src *image.RGBA, size int) *image.RGBA {
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := y * n / size
+		y1 := max((y+1)*n/size, y0+1)
+		for x := 0; x < size; x++ {
+			x0 := x * n / size
+			x1 := max((x+1)*n/size, x0+1)
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					count++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(b / count)
+			dst.Pix[i+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+// Verify that AvatarService implements the user.AvatarService interface
+var _ user.AvatarService = (*AvatarService)(nil)
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"blog-platform/internal/domain/banner"
+)
+
+// BannerService implements the banner.Service interface
+type BannerService struct {
+	repo   banner.Repository
+	audit  AuditLogger
+	logger Logger
+}
+
+// NewBannerService creates a new BannerService instance
+func NewBannerService(repo banner.Repository, audit AuditLogger, logger Logger) *BannerService {
+	return &BannerService{
+		repo:   repo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// Create stores a banner
+func (s *BannerService) Create(ctx context.Context, adminID int, in banner.Input) (*banner.Banner, error) {
+	b, err := banner.NewBanner(adminID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, b); err != nil {
+		s.logger.Error(ctx, "failed to save banner", "adminID", adminID, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionBannerCreated,
+		UserID:   adminID,
+		Metadata: map[string]any{"banner_id": b.ID, "severity": string(b.Severity)},
+	})
+	s.logger.Info(ctx, "banner created", "bannerID", b.ID, "adminID", adminID)
+	return b, nil
+}
+
+// Update replaces the fields of a banner; users who dismissed it keep it
+// dismissed
+func (s *BannerService) Update(ctx context.Context, adminID, id int, in banner.Input) (*banner.Banner, error) {
+	b, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Update(in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, b); err != nil {
+		s.logger.Error(ctx, "failed to update banner", "bannerID", id, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionBannerUpdated,
+		UserID:   adminID,
+		Metadata: map[string]any{"banner_id": b.ID, "severity": string(b.Severity)},
+	})
+	s.logger.Info(ctx, "banner updated", "bannerID", b.ID, "adminID", adminID)
+	return b, nil
+}
+
+// Delete removes a banner and its dismissals
+func (s *BannerService) Delete(ctx context.Context, adminID, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionBannerDeleted,
+		UserID:   adminID,
+		Metadata: map[string]any{"banner_id": id},
+	})
+	s.logger.Info(ctx, "banner deleted", "bannerID", id, "adminID", adminID)
+	return nil
+}
+
+// List retrieves every banner, newest first
+func (s *BannerService) List(ctx context.Context) ([]*banner.Banner, error) {
+	return s.repo.List(ctx)
+}
+
+// ListActive retrieves the banners shown now, without those the user
+// dismissed
+func (s *BannerService) ListActive(ctx context.Context, userID int) ([]*banner.Banner, error) {
+	return s.repo.ListActive(ctx, time.Now(), userID)
+}
+
+// Dismiss stops showing a dismissible banner to a user. Banners that are
+// not shown right now can still be dismissed, e.g. by clients lagging
+// behind their end time.
+func (s *BannerService) Dismiss(ctx context.Context, id, userID int) error {
+	b, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !b.Dismissible {
+		return banner.ErrNotDismissible
+	}
+
+	if err := s.repo.Dismiss(ctx, id, userID); err != nil {
+		s.logger.Error(ctx, "failed to dismiss banner", "bannerID", id, "userID", userID, "error", err.Error())
+		return err
+	}
+	return nil
+}
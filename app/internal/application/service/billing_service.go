@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/billing"
+)
+
+// BillingService implements the billing.Service interface. Subscriptions
+// change only through Stripe events; the service keeps the latest state of
+// each and gates actions on the plan it grants.
+type BillingService struct {
+	repo   billing.Repository
+	audit  AuditLogger
+	logger Logger
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(repo billing.Repository, audit AuditLogger, logger Logger) *BillingService {
+	return &BillingService{
+		repo:   repo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// GetOverview returns the subscription of a user with the limits of the plan
+// it grants and the user's usage
+func (s *BillingService) GetOverview(ctx context.Context, userID int) (*billing.Overview, error) {
+	subscription, plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.repo.CountPosts(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count posts", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	return &billing.Overview{
+		Subscription: subscription,
+		Plan:         plan,
+		Limits:       billing.LimitsOf(plan),
+		Usage:        billing.Usage{Posts: posts},
+	}, nil
+}
+
+// ApplyEvent records a subscription change reported by Stripe. The user is
+// found by the Stripe customer, or by the user ID in the subscription's
+// metadata for a customer's first subscription. Events older than the last
+// one applied are ignored, as are endings of subscriptions the user has
+// since replaced.
+func (s *BillingService) ApplyEvent(ctx context.Context, event *billing.SubscriptionEvent) error {
+	existing, err := s.repo.GetByStripeCustomerID(ctx, event.StripeCustomerID)
+	if errors.Is(err, billing.ErrSubscriptionNotFound) {
+		if event.UserID == 0 {
+			s.logger.Warn(ctx, "subscription event for unknown customer", "eventID", event.EventID, "customer", event.StripeCustomerID)
+			return billing.ErrUnknownCustomer
+		}
+		existing, err = s.repo.GetByUserID(ctx, event.UserID)
+		if errors.Is(err, billing.ErrSubscriptionNotFound) {
+			existing, err = nil, nil
+		}
+	}
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve subscription", "eventID", event.EventID, "error", err.Error())
+		return err
+	}
+
+	now := time.Now()
+	subscription := &billing.Subscription{UserID: event.UserID, CreatedAt: now}
+	if existing != nil {
+		if event.OccurredAt.Before(existing.LastEventAt) {
+			s.logger.Info(ctx, "ignoring stale subscription event", "eventID", event.EventID, "userID", existing.UserID)
+			return nil
+		}
+		if existing.StripeSubscriptionID != "" && existing.StripeSubscriptionID != event.StripeSubscriptionID && !event.Status.Grants() {
+			s.logger.Info(ctx, "ignoring event of a replaced subscription", "eventID", event.EventID, "userID", existing.UserID)
+			return nil
+		}
+		copied := *existing
+		subscription = &copied
+	}
+
+	subscription.Plan = event.Plan
+	subscription.Status = event.Status
+	subscription.StripeCustomerID = event.StripeCustomerID
+	subscription.StripeSubscriptionID = event.StripeSubscriptionID
+	subscription.CurrentPeriodEnd = event.CurrentPeriodEnd
+	subscription.CancelAtPeriodEnd = event.CancelAtPeriodEnd
+	subscription.LastEventAt = event.OccurredAt
+	subscription.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, subscription); err != nil {
+		s.logger.Error(ctx, "failed to save subscription", "eventID", event.EventID, "userID", subscription.UserID, "error", err.Error())
+		return err
+	}
+
+	if existing == nil || existing.EffectivePlan() != subscription.EffectivePlan() || existing.Status != subscription.Status {
+		s.audit.Record(ctx, AuditEvent{
+			Action:   AuditActionSubscriptionChanged,
+			UserID:   subscription.UserID,
+			Metadata: map[string]any{"plan": string(subscription.Plan), "status": string(subscription.Status), "event_id": event.EventID},
+		})
+	}
+	s.logger.Info(ctx, "subscription updated", "userID", subscription.UserID, "plan", subscription.Plan, "status", subscription.Status)
+	return nil
+}
+
+// CheckPostLimit checks that the author is below the post limit of their plan
+func (s *BillingService) CheckPostLimit(ctx context.Context, userID int) error {
+	_, plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	limit := billing.LimitsOf(plan).MaxPosts
+	if limit == 0 {
+		return nil
+	}
+
+	posts, err := s.repo.CountPosts(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count posts", "userID", userID, "error", err.Error())
+		return err
+	}
+	if posts >= limit {
+		s.logger.Info(ctx, "post limit reached", "userID", userID, "plan", plan, "posts", posts)
+		return billing.PostLimitError(plan, limit)
+	}
+	return nil
+}
+
+// CheckUploadQuota checks that an upload of size bytes fits the upload quota
+// of the user's plan next to the used bytes
+func (s *BillingService) CheckUploadQuota(ctx context.Context, userID int, used, size int64) error {
+	_, plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	quota := billing.LimitsOf(plan).UploadQuota
+	if quota != 0 && used+size > quota {
+		s.logger.Info(ctx, "upload quota exceeded", "userID", userID, "plan", plan, "used", used, "size", size)
+		return billing.ErrUploadQuotaExceeded
+	}
+	return nil
+}
+
+// CheckCustomDomain checks that the user's plan includes custom domains
+func (s *BillingService) CheckCustomDomain(ctx context.Context, userID int) error {
+	_, plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !billing.LimitsOf(plan).CustomDomains {
+		return billing.ErrCustomDomainUnavailable
+	}
+	return nil
+}
+
+// CheckPaidPlan checks that the user's plan is a paid one
+func (s *BillingService) CheckPaidPlan(ctx context.Context, userID int) error {
+	_, plan, err := s.plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !plan.IsPaid() {
+		return billing.ErrPaidPlanRequired
+	}
+	return nil
+}
+
+// plan returns the subscription of a user, nil if there is none, and the
+// plan it grants
+func (s *BillingService) plan(ctx context.Context, userID int) (*billing.Subscription, billing.Plan, error) {
+	subscription, err := s.repo.GetByUserID(ctx, userID)
+	if errors.Is(err, billing.ErrSubscriptionNotFound) {
+		return nil, billing.PlanFree, nil
+	}
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve subscription", "userID", userID, "error", err.Error())
+		return nil, "", err
+	}
+	return subscription, subscription.EffectivePlan(), nil
+}
+
+// Verify that BillingService implements the billing.Service interface
+var _ billing.Service = (*BillingService)(nil)
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"blog-platform/internal/domain/post"
+)
+
+// BookmarkService implements the post.BookmarkService interface
+type BookmarkService struct {
+	bookmarks post.BookmarkRepository
+	posts     post.ReadRepository
+	logger    Logger
+}
+
+// NewBookmarkService creates a new bookmark service
+func NewBookmarkService(bookmarks post.BookmarkRepository, posts post.ReadRepository, logger Logger) *BookmarkService {
+	return &BookmarkService{
+		bookmarks: bookmarks,
+		posts:     posts,
+		logger:    logger,
+	}
+}
+
+// Bookmark adds a published post that is not private to the reading list
+// of a user
+func (s *BookmarkService) Bookmark(ctx context.Context, userID, postID int) error {
+	existing, err := s.posts.GetByID(ctx, postID)
+	if err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post for bookmark", "postID", postID, "error", err.Error())
+		}
+		return err
+	}
+	if !existing.IsVisibleTo(0, "") {
+		return post.ErrPostNotFound
+	}
+
+	if err := s.bookmarks.Add(ctx, userID, postID); err != nil {
+		s.logger.Error(ctx, "failed to bookmark post", "userID", userID, "postID", postID, "error", err.Error())
+		return err
+	}
+	s.logger.Info(ctx, "post bookmarked", "userID", userID, "postID", postID)
+	return nil
+}
+
+// Unbookmark removes a post from the reading list of a user. Posts
+// unpublished since can still be removed.
+func (s *BookmarkService) Unbookmark(ctx context.Context, userID, postID int) error {
+	if err := s.bookmarks.Remove(ctx, userID, postID); err != nil {
+		s.logger.Error(ctx, "failed to remove bookmark", "userID", userID, "postID", postID, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListBookmarks retrieves the reading list of a user, most recently
+// bookmarked first
+func (s *BookmarkService) ListBookmarks(ctx context.Context, userID int, limit, offset int) ([]*post.BookmarkEntry, error) {
+	entries, err := s.bookmarks.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list bookmarks", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	return entries, nil
+}
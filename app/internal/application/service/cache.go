@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"strconv"
+	"time"
+)
+
+// Cache stores encoded values for cache-aside reads. Entries may be evicted
+// before they expire, so a miss only costs a read of the source.
+type Cache interface {
+	// Get returns the value stored at key; found is false on a miss
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set stores a value at key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// cacheAside reads values through a cache, filling it on misses. Values are
+// gob-encoded, so fields hidden from JSON such as password hashes survive.
+// A nil cache or a zero TTL turns caching off, and cache failures are
+// logged and fall back to the source.
+type cacheAside struct {
+	cache  Cache
+	ttl    time.Duration
+	logger Logger
+}
+
+// enabled reports whether values are cached
+func (c cacheAside) enabled() bool {
+	return c.cache != nil && c.ttl > 0
+}
+
+// load decodes the value cached at key into dest and reports whether it
+// was found
+func (c cacheAside) load(ctx context.Context, key string, dest any) bool {
+	if !c.enabled() {
+		return false
+	}
+	value, found, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn(ctx, "failed to read cache", "key", key, "error", err.Error())
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(value)).Decode(dest); err != nil {
+		c.logger.Warn(ctx, "failed to decode cached value", "key", key, "error", err.Error())
+		return false
+	}
+	return true
+}
+
+// store caches value at key
+func (c cacheAside) store(ctx context.Context, key string, value any) {
+	if !c.enabled() {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		c.logger.Warn(ctx, "failed to encode value for cache", "key", key, "error", err.Error())
+		return
+	}
+	if err := c.cache.Set(ctx, key, buf.Bytes(), c.ttl); err != nil {
+		c.logger.Warn(ctx, "failed to write cache", "key", key, "error", err.Error())
+	}
+}
+
+// forget removes the values cached at keys
+func (c cacheAside) forget(ctx context.Context, keys ...string) {
+	if !c.enabled() || len(keys) == 0 {
+		return
+	}
+	if err := c.cache.Delete(ctx, keys...); err != nil {
+		c.logger.Error(ctx, "failed to invalidate cache", "keys", keys, "error", err.Error())
+	}
+}
+
+// generation returns the current generation of a group of entries stored
+// under key. Keys of the entries include it, so changing the generation
+// invalidates the whole group at once. An evicted generation is replaced by
+// a new one rather than reset, so entries of an old one never come back.
+func (c cacheAside) generation(ctx context.Context, key string) string {
+	var generation string
+	if c.load(ctx, key, &generation) {
+		return generation
+	}
+	generation = strconv.FormatInt(time.Now().UnixNano(), 36)
+	c.store(ctx, key, generation)
+	return generation
+}
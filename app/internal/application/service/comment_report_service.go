@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"blog-platform/internal/domain/comment"
+)
+
+// ReportSettings configures comment reporting
+type ReportSettings struct {
+	// HideThreshold is the number of reporters that hides a comment until
+	// an admin reviews it; zero never hides reported comments
+	HideThreshold int
+}
+
+// CommentReportService implements the comment.ReportService interface
+type CommentReportService struct {
+	reports  comment.ReportRepository
+	comments comment.Repository
+	settings ReportSettings
+	logger   Logger
+}
+
+// NewCommentReportService creates a new comment report service
+func NewCommentReportService(reports comment.ReportRepository, comments comment.Repository, settings ReportSettings, logger Logger) *CommentReportService {
+	return &CommentReportService{
+		reports:  reports,
+		comments: comments,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// ReportComment records a report on a public comment. The comment is held
+// as pending when its reporters reach the threshold; it is hidden only as
+// the threshold is reached, so comments an admin approves again stay up.
+func (s *CommentReportService) ReportComment(ctx context.Context, commentID, reporterID int, clientIP string, reason comment.ReportReason, details string) (*comment.Report, error) {
+	r, err := comment.NewReport(commentID, reporterID, clientIP, reason, details)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.comments.GetByID(ctx, commentID)
+	if err != nil {
+		if !errors.Is(err, comment.ErrCommentNotFound) {
+			s.logger.Error(ctx, "failed to retrieve reported comment", "commentID", commentID, "error", err.Error())
+		}
+		return nil, err
+	}
+	// Comments hidden from readers cannot be reported
+	if !c.IsApproved() {
+		return nil, comment.ErrCommentNotFound
+	}
+
+	created, err := s.reports.Create(ctx, r)
+	if err != nil {
+		s.logger.Error(ctx, "failed to save comment report", "commentID", commentID, "error", err.Error())
+		return nil, err
+	}
+	if !created {
+		s.logger.Debug(ctx, "comment already reported by reporter", "commentID", commentID, "reporterID", reporterID)
+		return r, nil
+	}
+	s.logger.Info(ctx, "comment reported", "commentID", commentID, "reporterID", reporterID, "reason", string(reason))
+
+	if s.settings.HideThreshold <= 0 {
+		return r, nil
+	}
+	count, err := s.reports.CountByComment(ctx, commentID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count comment reports", "commentID", commentID, "error", err.Error())
+		return nil, err
+	}
+	if count != s.settings.HideThreshold {
+		return r, nil
+	}
+
+	c.Status = comment.StatusPending
+	if err := s.comments.Update(ctx, c); err != nil {
+		s.logger.Error(ctx, "failed to hide reported comment", "commentID", commentID, "error", err.Error())
+		return nil, err
+	}
+	s.logger.Warn(ctx, "reported comment hidden for review", "commentID", commentID, "reports", count)
+	return r, nil
+}
+
+// ListReports retrieves the reports on a comment, oldest first
+func (s *CommentReportService) ListReports(ctx context.Context, commentID int) ([]*comment.Report, error) {
+	if _, err := s.comments.GetByID(ctx, commentID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.reports.ListByComment(ctx, commentID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list comment reports", "commentID", commentID, "error", err.Error())
+		return nil, err
+	}
+	return reports, nil
+}
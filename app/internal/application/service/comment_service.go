@@ -3,27 +3,59 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
+	"blog-platform/internal/domain/clientid"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
 )
 
+// CommentSettings holds the configurable behaviour of the comment service
+type CommentSettings struct {
+	// Pagination bounds the page size of comment lists and feeds
+	Pagination PageLimits
+	// RequireModeration holds new and edited comments as pending until an
+	// admin approves them
+	RequireModeration bool
+	// Trust limits comments by the trust level of their writer; nil turns
+	// trust levels off
+	Trust *comment.TrustPolicy
+}
+
 // CommentService implements the comment.Service interface
 type CommentService struct {
-	repo   comment.Repository
-	logger Logger
+	repo      comment.Repository
+	posts     post.ReadRepository
+	publisher EventPublisher
+	settings  CommentSettings
+	logger    Logger
 }
 
 // NewCommentService creates a new comment service
-func NewCommentService(repo comment.Repository, logger Logger) *CommentService {
+func NewCommentService(repo comment.Repository, posts post.ReadRepository, publisher EventPublisher, settings CommentSettings, logger Logger) *CommentService {
 	return &CommentService{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		posts:     posts,
+		publisher: publisher,
+		settings:  settings,
+		logger:    logger,
+	}
+}
+
+// AddComment creates a new comment with validation; userID is zero for
+// guest comments
+func (s *CommentService) AddComment(ctx context.Context, postID, userID int, role user.Role, authorName, content string) (*comment.Comment, error) {
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, err
 	}
+	return s.addComment(ctx, postID, userID, nil, authorName, content)
 }
 
-// AddComment creates a new comment with validation
-func (s *CommentService) AddComment(ctx context.Context, postID int, authorName, content string) (*comment.Comment, error) {
-	s.logger.Info(ctx, "adding comment", "postID", postID, "authorName", authorName)
+// addComment adds a top-level comment, under a client ID when one is given
+func (s *CommentService) addComment(ctx context.Context, postID, userID int, clientID *string, authorName, content string) (*comment.Comment, error) {
+	s.logger.Info(ctx, "adding comment", "postID", postID, "userID", userID, "authorName", authorName)
 	
 	// Create comment with validation
 	c, err := comment.NewComment(postID, authorName, content)
@@ -31,6 +63,12 @@ func (s *CommentService) AddComment(ctx context.Context, postID int, authorName,
 		s.logger.Error(ctx, "failed to create comment entity", "postID", postID, "authorName", authorName, "error", err.Error())
 		return nil, err
 	}
+	c.ClientID = clientID
+	c.PostedBy(userID)
+	s.holdForModeration(c)
+	if err := s.checkTrust(ctx, userID, c.Content, true); err != nil {
+		return nil, err
+	}
 
 	// Save to repository
 	err = s.repo.Create(ctx, c)
@@ -39,10 +77,129 @@ func (s *CommentService) AddComment(ctx context.Context, postID int, authorName,
 		return nil, err
 	}
 
+	if c.IsApproved() {
+		s.published(ctx, c)
+	}
+
 	s.logger.Info(ctx, "comment added successfully", "postID", postID, "commentID", c.ID, "authorName", authorName)
 	return c, nil
 }
 
+// ReplyToComment creates a reply to a comment; the parent must be on the
+// same post and not nested at the maximum depth
+func (s *CommentService) ReplyToComment(ctx context.Context, postID, parentID, userID int, role user.Role, authorName, content string) (*comment.Comment, error) {
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, err
+	}
+	return s.replyToComment(ctx, postID, parentID, userID, nil, authorName, content)
+}
+
+// replyToComment adds a reply, under a client ID when one is given
+func (s *CommentService) replyToComment(ctx context.Context, postID, parentID, userID int, clientID *string, authorName, content string) (*comment.Comment, error) {
+	s.logger.Info(ctx, "adding reply", "postID", postID, "parentID", parentID, "userID", userID, "authorName", authorName)
+
+	c, err := comment.NewComment(postID, authorName, content)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create comment entity", "postID", postID, "authorName", authorName, "error", err.Error())
+		return nil, err
+	}
+	c.ClientID = clientID
+	c.PostedBy(userID)
+	s.holdForModeration(c)
+	if err := s.checkTrust(ctx, userID, c.Content, true); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.repo.GetByID(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, comment.ErrCommentNotFound) {
+			return nil, comment.ErrParentNotFound
+		}
+		s.logger.Error(ctx, "failed to retrieve parent comment", "parentID", parentID, "error", err.Error())
+		return nil, err
+	}
+	// Comments awaiting or failing moderation are not public to reply to
+	if !parent.IsApproved() {
+		return nil, comment.ErrParentNotFound
+	}
+	if err := c.ReplyTo(parent); err != nil {
+		s.logger.Warn(ctx, "invalid reply", "postID", postID, "parentID", parentID, "error", err.Error())
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		s.logger.Error(ctx, "failed to save reply to repository", "postID", postID, "parentID", parentID, "error", err.Error())
+		return nil, err
+	}
+
+	if c.IsApproved() {
+		s.published(ctx, c)
+	}
+
+	s.logger.Info(ctx, "reply added successfully", "postID", postID, "commentID", c.ID, "parentID", parentID)
+	return c, nil
+}
+
+// AddClientComment adds a comment, or a reply to parentID when it is set,
+// under a UUID the client generated. Offline clients resend writes they got
+// no answer to, so a client ID already used on the post by the same
+// commenter returns the comment it created with created false; other uses
+// of the client ID conflict.
+func (s *CommentService) AddClientComment(ctx context.Context, postID, parentID, userID int, role user.Role, clientID, authorName, content string) (*comment.Comment, bool, error) {
+	clientID, err := clientid.Normalize(clientID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, false, err
+	}
+	if c, err := s.replayClientComment(ctx, postID, userID, clientID); err != nil || c != nil {
+		return c, false, err
+	}
+
+	var c *comment.Comment
+	if parentID > 0 {
+		c, err = s.replyToComment(ctx, postID, parentID, userID, &clientID, authorName, content)
+	} else {
+		c, err = s.addComment(ctx, postID, userID, &clientID, authorName, content)
+	}
+	if errors.Is(err, comment.ErrClientIDTaken) {
+		// A concurrent retry saved the comment first
+		if c, err := s.replayClientComment(ctx, postID, userID, clientID); err != nil || c != nil {
+			return c, false, err
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// replayClientComment returns the comment created under a client ID, or nil
+// when there is none yet
+func (s *CommentService) replayClientComment(ctx context.Context, postID, userID int, clientID string) (*comment.Comment, error) {
+	c, err := s.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, comment.ErrCommentNotFound) {
+			return nil, nil
+		}
+		s.logger.Error(ctx, "failed to retrieve comment by client ID", "clientID", clientID, "error", err.Error())
+		return nil, err
+	}
+	// Guests have no account to match, so their retries only match guest
+	// comments on the same post
+	poster := 0
+	if c.UserID != nil {
+		poster = *c.UserID
+	}
+	if !c.BelongsToPost(postID) || poster != userID {
+		s.logger.Warn(ctx, "client ID used by another comment", "postID", postID, "userID", userID, "clientID", clientID)
+		return nil, comment.ErrClientIDTaken
+	}
+	s.logger.Info(ctx, "comment already created under client ID", "commentID", c.ID, "clientID", clientID)
+	return c, nil
+}
+
 // GetComment retrieves a comment by ID
 func (s *CommentService) GetComment(ctx context.Context, id int) (*comment.Comment, error) {
 	s.logger.Debug(ctx, "retrieving comment", "commentID", id)
@@ -62,27 +219,111 @@ func (s *CommentService) GetComment(ctx context.Context, id int) (*comment.Comme
 	return comment, nil
 }
 
-// GetCommentsByPost retrieves comments for a post with pagination validation
-func (s *CommentService) GetCommentsByPost(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
+// GetCommentByClientID retrieves a comment by the client ID it was created
+// with
+func (s *CommentService) GetCommentByClientID(ctx context.Context, clientID string) (*comment.Comment, error) {
+	clientID, err := clientid.Normalize(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if !errors.Is(err, comment.ErrCommentNotFound) {
+			s.logger.Error(ctx, "failed to retrieve comment by client ID", "clientID", clientID, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// GetCommentsByPost retrieves comments for a post; the page is bounded by
+// the comment pagination limits
+func (s *CommentService) GetCommentsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*comment.Comment, error) {
 	// Validate post ID
 	if postID <= 0 {
 		return nil, errors.New("post ID must be positive")
 	}
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, err
+	}
+
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+	return s.repo.GetByPostID(ctx, postID, limit, offset)
+}
 
-	// Validate pagination parameters
-	if limit <= 0 || limit > 100 {
-		return nil, errors.New("limit must be between 1 and 100")
+// GetCommentsByPostAfter retrieves a page of the comments of a post after a
+// cursor, oldest first. The returned cursor continues after the page and is
+// zero on the last page.
+func (s *CommentService) GetCommentsByPostAfter(ctx context.Context, postID, userID int, role user.Role, after keyset.Cursor, limit int) ([]*comment.Comment, keyset.Cursor, error) {
+	if postID <= 0 {
+		return nil, keyset.Cursor{}, errors.New("post ID must be positive")
 	}
-	if offset < 0 {
-		return nil, errors.New("offset must be non-negative")
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, keyset.Cursor{}, err
 	}
 
-	return s.repo.GetByPostID(ctx, postID, limit, offset)
+	limit, _ = s.settings.Pagination.Normalize(limit, 0)
+
+	// One extra comment tells whether another page follows
+	comments, err := s.repo.GetByPostIDAfter(ctx, postID, after, limit+1)
+	if err != nil {
+		return nil, keyset.Cursor{}, err
+	}
+	if len(comments) <= limit {
+		return comments, keyset.Cursor{}, nil
+	}
+	comments = comments[:limit]
+	last := comments[limit-1]
+	return comments, keyset.After(last.CreatedAt, last.ID), nil
+}
+
+// GetThreadsByPost retrieves a page of the top-level comments of a post,
+// bounded by the comment pagination limits, with all replies in their threads
+func (s *CommentService) GetThreadsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*comment.Thread, error) {
+	if postID <= 0 {
+		return nil, errors.New("post ID must be positive")
+	}
+	if err := s.checkPostVisible(ctx, postID, userID, role); err != nil {
+		return nil, err
+	}
+
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+	roots, err := s.repo.GetRootsByPostID(ctx, postID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve top-level comments", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return []*comment.Thread{}, nil
+	}
+
+	rootIDs := make([]int, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.ID
+	}
+	replies, err := s.repo.GetByRootIDs(ctx, rootIDs)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve replies", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	return comment.BuildThreads(roots, replies), nil
+}
+
+// GetRecentComments retrieves the newest comments matching the filter
+func (s *CommentService) GetRecentComments(ctx context.Context, filter comment.RecentFilter, limit int) ([]*comment.Comment, error) {
+	if filter.PostID < 0 || filter.PostAuthorID < 0 {
+		return nil, errors.New("post ID and author ID must be positive")
+	}
+	limit, _ = s.settings.Pagination.Normalize(limit, 0)
+	return s.repo.ListRecent(ctx, filter, limit)
 }
 
 // UpdateComment updates a comment's content with authorization check
-func (s *CommentService) UpdateComment(ctx context.Context, id int, authorName, content string) (*comment.Comment, error) {
-	s.logger.Info(ctx, "updating comment", "commentID", id, "authorName", authorName)
+func (s *CommentService) UpdateComment(ctx context.Context, id, userID int, role user.Role, content string) (*comment.Comment, error) {
+	s.logger.Info(ctx, "updating comment", "commentID", id, "userID", userID)
 	
 	// Validate ID
 	if id <= 0 {
@@ -97,9 +338,9 @@ func (s *CommentService) UpdateComment(ctx context.Context, id int, authorName,
 		return nil, err
 	}
 
-	// Check authorization - only the author can update their comment
-	if !c.IsAuthor(authorName) {
-		s.logger.Warn(ctx, "unauthorized comment update attempt", "commentID", id, "requestedBy", authorName, "actualAuthor", c.AuthorName)
+	// Check authorization - only the author or an admin can update the comment
+	if !c.CanModify(userID, role) {
+		s.logger.Warn(ctx, "unauthorized comment update attempt", "commentID", id, "requestedBy", userID)
 		return nil, errors.New("unauthorized: only the author can update this comment")
 	}
 
@@ -109,6 +350,14 @@ func (s *CommentService) UpdateComment(ctx context.Context, id int, authorName,
 		s.logger.Error(ctx, "failed to update comment entity", "commentID", id, "error", err.Error())
 		return nil, err
 	}
+	// Edits are reviewed again, or approving a comment would approve
+	// whatever it is edited into; admin edits are trusted
+	if !role.IsAdmin() {
+		s.holdForModeration(c)
+		if err := s.checkTrust(ctx, userID, c.Content, false); err != nil {
+			return nil, err
+		}
+	}
 
 	// Save to repository
 	err = s.repo.Update(ctx, c)
@@ -117,13 +366,13 @@ func (s *CommentService) UpdateComment(ctx context.Context, id int, authorName,
 		return nil, err
 	}
 
-	s.logger.Info(ctx, "comment updated successfully", "commentID", id, "authorName", authorName)
+	s.logger.Info(ctx, "comment updated successfully", "commentID", id, "userID", userID)
 	return c, nil
 }
 
 // DeleteComment deletes a comment with authorization check
-func (s *CommentService) DeleteComment(ctx context.Context, id int, authorName string) error {
-	s.logger.Info(ctx, "deleting comment", "commentID", id, "authorName", authorName)
+func (s *CommentService) DeleteComment(ctx context.Context, id, userID int, role user.Role) error {
+	s.logger.Info(ctx, "deleting comment", "commentID", id, "userID", userID)
 	
 	// Validate ID
 	if id <= 0 {
@@ -138,9 +387,9 @@ func (s *CommentService) DeleteComment(ctx context.Context, id int, authorName s
 		return err
 	}
 
-	// Check authorization - only the author can delete their comment
-	if !c.IsAuthor(authorName) {
-		s.logger.Warn(ctx, "unauthorized comment deletion attempt", "commentID", id, "requestedBy", authorName, "actualAuthor", c.AuthorName)
+	// Check authorization - only the author or an admin can delete the comment
+	if !c.CanModify(userID, role) {
+		s.logger.Warn(ctx, "unauthorized comment deletion attempt", "commentID", id, "requestedBy", userID)
 		return errors.New("unauthorized: only the author can delete this comment")
 	}
 
@@ -150,6 +399,142 @@ func (s *CommentService) DeleteComment(ctx context.Context, id int, authorName s
 		return err
 	}
 	
-	s.logger.Info(ctx, "comment deleted successfully", "commentID", id, "authorName", authorName)
+	s.logger.Info(ctx, "comment deleted successfully", "commentID", id, "userID", userID)
 	return nil
 }
+
+// ListForModeration retrieves the comments with a moderation status with
+// pagination, oldest first, so the longest waiting are reviewed first
+func (s *CommentService) ListForModeration(ctx context.Context, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	if !status.IsValid() {
+		return nil, comment.ErrInvalidStatus
+	}
+
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+	comments, err := s.repo.ListByStatus(ctx, status, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list comments for moderation", "status", string(status), "error", err.Error())
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ModerateComment approves a comment or rejects it, possibly as spam.
+// Callers restrict moderation to admins.
+func (s *CommentService) ModerateComment(ctx context.Context, id, adminID int, status comment.Status) (*comment.Comment, error) {
+	s.logger.Info(ctx, "moderating comment", "commentID", id, "adminID", adminID, "status", string(status))
+
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve comment for moderation", "commentID", id, "error", err.Error())
+		return nil, err
+	}
+
+	// Comments becoming approved are published now
+	published := !c.IsApproved() && status == comment.StatusApproved
+	if err := c.Moderate(status); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, c); err != nil {
+		s.logger.Error(ctx, "failed to save moderated comment", "commentID", id, "error", err.Error())
+		return nil, err
+	}
+	if published {
+		s.published(ctx, c)
+	}
+
+	s.logger.Info(ctx, "comment moderated", "commentID", id, "adminID", adminID, "status", string(status))
+	return c, nil
+}
+
+// EvaluateTrustLevels moves commenters between trust levels by their
+// history and returns how many changed level. It is run periodically and
+// does nothing when trust levels are off.
+func (s *CommentService) EvaluateTrustLevels(ctx context.Context) (int, error) {
+	if s.settings.Trust == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	histories, err := s.repo.ListCommenterHistories(ctx, now.Add(-s.settings.Trust.FlagWindow))
+	if err != nil {
+		s.logger.Error(ctx, "failed to list commenter histories", "error", err.Error())
+		return 0, err
+	}
+
+	changed := 0
+	for _, h := range histories {
+		level := s.settings.Trust.Evaluate(*h, now)
+		if level == h.Level {
+			continue
+		}
+		if err := s.repo.SetTrustLevel(ctx, h.UserID, level); err != nil {
+			s.logger.Error(ctx, "failed to save commenter trust level", "userID", h.UserID, "error", err.Error())
+			return changed, err
+		}
+		s.logger.Info(ctx, "commenter trust level changed", "userID", h.UserID, "from", string(h.Level), "to", string(level))
+		changed++
+	}
+	return changed, nil
+}
+
+// checkTrust checks a comment against the limits of the trust level of its
+// writer, and the cooldown since their last comment when cooldown is set.
+// Guests are new commenters; as guests cannot be told apart, their pace is
+// left to the rate limits.
+func (s *CommentService) checkTrust(ctx context.Context, userID int, content string, cooldown bool) error {
+	if s.settings.Trust == nil {
+		return nil
+	}
+
+	level := comment.TrustNew
+	if userID > 0 {
+		var err error
+		if level, err = s.repo.GetTrustLevel(ctx, userID); err != nil {
+			s.logger.Error(ctx, "failed to retrieve commenter trust level", "userID", userID, "error", err.Error())
+			return err
+		}
+	}
+
+	limits := s.settings.Trust.LimitsFor(level)
+	if err := limits.CheckContent(content); err != nil {
+		s.logger.Warn(ctx, "comment exceeds trust level limits", "userID", userID, "level", string(level), "error", err.Error())
+		return err
+	}
+	if !cooldown || userID <= 0 {
+		return nil
+	}
+
+	last, err := s.repo.LastCommentAt(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve last comment time", "userID", userID, "error", err.Error())
+		return err
+	}
+	return limits.CheckCooldown(last, time.Now())
+}
+
+// checkPostVisible returns post.ErrPostNotFound unless the post is visible
+// to the user, so the comments of drafts and private posts stay hidden with
+// the post
+func (s *CommentService) checkPostVisible(ctx context.Context, postID, userID int, role user.Role) error {
+	filter := post.ViewFilter{AuthorID: userID, All: role.IsAdmin()}
+	if _, err := s.posts.GetVisibleByID(ctx, postID, filter); err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post for comments", "postID", postID, "error", err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// holdForModeration makes a comment pending when moderation is required
+func (s *CommentService) holdForModeration(c *comment.Comment) {
+	if s.settings.RequireModeration {
+		c.Status = comment.StatusPending
+	}
+}
+
+// published announces a comment that just became public
+func (s *CommentService) published(ctx context.Context, c *comment.Comment) {
+	s.publisher.Publish(ctx, CommentAdded{Comment: c})
+}
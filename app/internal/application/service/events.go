@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// Domain event names
+const (
+	EventPostCreated         = "post.created"
+	EventPostPublished       = "post.published"
+	EventCommentAdded        = "comment.added"
+	EventUserRegistered      = "user.registered"
+	EventAccountChanged      = "user.account_changed"
+	EventUserFollowed        = "user.followed"
+	EventNotificationCreated = "notification.created"
+	EventSeriesPostsChanged  = "series.posts_changed"
+)
+
+// Event is a change in the domain that other services react to
+type Event interface {
+	EventName() string
+}
+
+// PostCreated is published when a post is saved for the first time, as a
+// draft, scheduled or published right away
+type PostCreated struct {
+	Post *post.Post
+}
+
+// PostPublished is published when a post becomes public: a draft or
+// scheduled post is published, or an unlisted or private one made public.
+// Posts created published only raise PostCreated.
+type PostPublished struct {
+	Post *post.Post
+}
+
+// CommentAdded is published when a comment becomes public, when posted or
+// once approved by a moderator
+type CommentAdded struct {
+	Comment *comment.Comment
+}
+
+// UserRegistered is published when an account is created by registration
+type UserRegistered struct {
+	User *user.User
+}
+
+// AccountChanged is published when a service other than the user service
+// saves an account, such as when it is locked or its password reset
+type AccountChanged struct {
+	User *user.User
+}
+
+// UserFollowed is published when a user follows another, including when
+// they follow again
+type UserFollowed struct {
+	FollowerID int
+	FolloweeID int
+}
+
+// NotificationCreated is published when a notification is saved for a user
+type NotificationCreated struct {
+	Notification *notification.Notification
+}
+
+// SeriesPostsChanged is published when posts join, leave or move within a
+// series, which the series service saves without the post service
+type SeriesPostsChanged struct {
+	SeriesID int
+	PostIDs  []int
+}
+
+func (PostCreated) EventName() string         { return EventPostCreated }
+func (PostPublished) EventName() string       { return EventPostPublished }
+func (CommentAdded) EventName() string        { return EventCommentAdded }
+func (UserRegistered) EventName() string      { return EventUserRegistered }
+func (AccountChanged) EventName() string      { return EventAccountChanged }
+func (UserFollowed) EventName() string        { return EventUserFollowed }
+func (NotificationCreated) EventName() string { return EventNotificationCreated }
+func (SeriesPostsChanged) EventName() string  { return EventSeriesPostsChanged }
+
+// EventPublisher defines the interface for publishing domain events.
+// Publishing is best-effort: subscribers report their own failures and
+// never fail the change the event is about.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// EventHandler reacts to an event it is subscribed to
+type EventHandler func(ctx context.Context, event Event)
+
+// EventBus delivers published events to the handlers subscribed to them,
+// in the order they subscribed, before Publish returns. A panicking handler
+// is logged and does not keep the event from the others.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+	logger   Logger
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus(logger Logger) *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler), logger: logger}
+}
+
+// Subscribe registers a handler for the events with the given name
+func (b *EventBus) Subscribe(name string, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish delivers an event to its subscribers
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(ctx, event, handler)
+	}
+}
+
+// dispatch runs a handler, recovering from its panics
+func (b *EventBus) dispatch(ctx context.Context, event Event, handler EventHandler) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error(ctx, "event handler panicked", "event", event.EventName(), "error", fmt.Sprint(r))
+		}
+	}()
+	handler(ctx, event)
+}
+
+// Subscribe registers a handler for the events of type E
+func Subscribe[E Event](bus *EventBus, handler func(ctx context.Context, event E)) {
+	var zero E
+	bus.Subscribe(zero.EventName(), func(ctx context.Context, event Event) {
+		if e, ok := event.(E); ok {
+			handler(ctx, e)
+		}
+	})
+}
+
+var _ EventPublisher = (*EventBus)(nil)
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/user"
+)
+
+// exportBatchSize is the number of posts read at a time by site exports
+const exportBatchSize = 100
+
+// ExportService implements the export.Service interface
+type ExportService struct {
+	repo     export.Repository
+	users    user.ReadRepository
+	profiles user.ProfileRepository
+	audit    AuditLogger
+	logger   Logger
+}
+
+// NewExportService creates a new export service
+func NewExportService(repo export.Repository, users user.ReadRepository, profiles user.ProfileRepository, audit AuditLogger, logger Logger) *ExportService {
+	return &ExportService{
+		repo:     repo,
+		users:    users,
+		profiles: profiles,
+		audit:    audit,
+		logger:   logger,
+	}
+}
+
+// Export gathers the data of an account for its owner. Exports are audited,
+// since the archive holds everything the account wrote, drafts included.
+func (s *ExportService) Export(ctx context.Context, userID int) (*export.Archive, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for export", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	archive := &export.Archive{ExportedAt: time.Now(), Account: u}
+	profiles, err := s.profiles.GetProfiles(ctx, []int{userID})
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve profile for export", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	if len(profiles) > 0 {
+		archive.Profile = profiles[0]
+	}
+
+	if archive.Posts, err = s.repo.ListPosts(ctx, userID); err != nil {
+		s.logger.Error(ctx, "failed to list posts for export", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	if archive.Comments, err = s.repo.ListComments(ctx, userID); err != nil {
+		s.logger.Error(ctx, "failed to list comments for export", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionDataExported,
+		UserID:   userID,
+		Metadata: map[string]any{"posts": len(archive.Posts), "comments": len(archive.Comments)},
+	})
+	s.logger.Info(ctx, "account data exported", "userID", userID, "posts", len(archive.Posts), "comments", len(archive.Comments))
+	return archive, nil
+}
+
+// ExportSite streams the posts matching the filter with their authors and
+// comments to write. The export is audited when it is done; an export cut
+// short by write is not.
+func (s *ExportService) ExportSite(ctx context.Context, adminID int, filter export.SiteFilter, write func(*export.Entry) error) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	authors := make(map[int]*user.User)
+	if filter.AuthorID != 0 {
+		u, err := s.users.GetByID(ctx, filter.AuthorID)
+		if err != nil {
+			s.logger.Error(ctx, "failed to retrieve author for site export", "authorID", filter.AuthorID, "error", err.Error())
+			return err
+		}
+		authors[u.ID] = u
+	}
+
+	posts, comments, afterID := 0, 0, 0
+	for {
+		batch, err := s.repo.ListSitePosts(ctx, filter, afterID, exportBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "failed to list posts for site export", "afterID", afterID, "error", err.Error())
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		ids := make([]int, len(batch))
+		for i, p := range batch {
+			ids[i] = p.ID
+		}
+		batchComments, err := s.repo.ListPostComments(ctx, ids)
+		if err != nil {
+			s.logger.Error(ctx, "failed to list comments for site export", "afterID", afterID, "error", err.Error())
+			return err
+		}
+		byPost := make(map[int][]*comment.Comment, len(batch))
+		for _, c := range batchComments {
+			byPost[c.PostID] = append(byPost[c.PostID], c)
+		}
+
+		for _, p := range batch {
+			author, err := s.exportAuthor(ctx, authors, p.AuthorID)
+			if err != nil {
+				return err
+			}
+			if err := write(&export.Entry{Post: p, Author: author, Comments: byPost[p.ID]}); err != nil {
+				return err
+			}
+			posts++
+			comments += len(byPost[p.ID])
+		}
+
+		afterID = batch[len(batch)-1].ID
+		if len(batch) < exportBatchSize {
+			break
+		}
+	}
+
+	metadata := map[string]any{"posts": posts, "comments": comments}
+	if filter.AuthorID != 0 {
+		metadata["author_id"] = filter.AuthorID
+	}
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionContentExported,
+		UserID:   adminID,
+		Metadata: metadata,
+	})
+	s.logger.Info(ctx, "site content exported", "adminID", adminID, "posts", posts, "comments", comments)
+	return nil
+}
+
+// exportAuthor looks up the author of an exported post once per export;
+// authors whose account is gone are exported as nil
+func (s *ExportService) exportAuthor(ctx context.Context, authors map[int]*user.User, authorID int) (*user.User, error) {
+	if u, ok := authors[authorID]; ok {
+		return u, nil
+	}
+	u, err := s.users.GetByID(ctx, authorID)
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		s.logger.Error(ctx, "failed to retrieve author for site export", "authorID", authorID, "error", err.Error())
+		return nil, err
+	}
+	authors[authorID] = u
+	return u, nil
+}
+
+// Verify that ExportService implements the export.Service interface
+var _ export.Service = (*ExportService)(nil)
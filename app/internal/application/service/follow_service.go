@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"blog-platform/internal/domain/user"
+)
+
+// FollowService implements the user.FollowService interface
+type FollowService struct {
+	follows   user.FollowRepository
+	users     user.ReadRepository
+	publisher EventPublisher
+	logger    Logger
+}
+
+// NewFollowService creates a new follow service
+func NewFollowService(follows user.FollowRepository, users user.ReadRepository, publisher EventPublisher, logger Logger) *FollowService {
+	return &FollowService{
+		follows:   follows,
+		users:     users,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Follow makes the follower follow another active account
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID int) error {
+	if followerID == followeeID {
+		return user.ErrCannotFollowSelf
+	}
+	if err := s.checkActive(ctx, followeeID); err != nil {
+		return err
+	}
+
+	if err := s.follows.Follow(ctx, followerID, followeeID); err != nil {
+		s.logger.Error(ctx, "failed to follow user", "followerID", followerID, "followeeID", followeeID, "error", err.Error())
+		return err
+	}
+	s.publisher.Publish(ctx, UserFollowed{FollowerID: followerID, FolloweeID: followeeID})
+	s.logger.Info(ctx, "user followed", "followerID", followerID, "followeeID", followeeID)
+	return nil
+}
+
+// Unfollow stops the follower following a user. Accounts deleted since can
+// still be unfollowed.
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	if err := s.follows.Unfollow(ctx, followerID, followeeID); err != nil {
+		s.logger.Error(ctx, "failed to unfollow user", "followerID", followerID, "followeeID", followeeID, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListFollowers retrieves a page of the followers of an active account
+func (s *FollowService) ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, *user.FollowCounts, error) {
+	if err := s.checkActive(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	follows, err := s.follows.ListFollowers(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list followers", "userID", userID, "error", err.Error())
+		return nil, nil, err
+	}
+	return s.withCounts(ctx, userID, follows)
+}
+
+// ListFollowing retrieves a page of the users an active account follows
+func (s *FollowService) ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, *user.FollowCounts, error) {
+	if err := s.checkActive(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	follows, err := s.follows.ListFollowing(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list followed users", "userID", userID, "error", err.Error())
+		return nil, nil, err
+	}
+	return s.withCounts(ctx, userID, follows)
+}
+
+// withCounts adds the follow counts of the user to a page of follows
+func (s *FollowService) withCounts(ctx context.Context, userID int, follows []*user.Follow) ([]*user.Follow, *user.FollowCounts, error) {
+	counts, err := s.follows.Counts(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count follows", "userID", userID, "error", err.Error())
+		return nil, nil, err
+	}
+	return follows, counts, nil
+}
+
+// checkActive checks that an account exists and is neither deleted nor
+// deactivated; other accounts are not found
+func (s *FollowService) checkActive(ctx context.Context, id int) error {
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, user.ErrUserNotFound) {
+			s.logger.Error(ctx, "failed to retrieve user", "userID", id, "error", err.Error())
+		}
+		return err
+	}
+	if u.DeletedAt != nil || u.IsDeactivated() {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/idempotency"
+)
+
+// IdempotencySettings holds the configurable behaviour of the idempotency
+// service
+type IdempotencySettings struct {
+	// TTL is how long the response to a request is replayed for its key
+	TTL time.Duration
+	// LockTimeout is how long a request holds its key before a retry may
+	// take it over, in case the server processing it stopped
+	LockTimeout time.Duration
+}
+
+// IdempotencyService implements the idempotency.Service interface
+type IdempotencyService struct {
+	repo     idempotency.Repository
+	settings IdempotencySettings
+	logger   Logger
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(repo idempotency.Repository, settings IdempotencySettings, logger Logger) *IdempotencyService {
+	return &IdempotencyService{
+		repo:     repo,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Begin claims the key for a request. The insert is the claim, so only one
+// of concurrent requests with a key is processed; the others get
+// ErrKeyInUse. Expired records and the records of requests that held the
+// key past the lock timeout are replaced.
+func (s *IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*idempotency.Record, bool, error) {
+	if !idempotency.ValidKey(key) {
+		return nil, false, idempotency.ErrInvalidKey
+	}
+
+	now := time.Now()
+	record := &idempotency.Record{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.settings.TTL),
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.repo.Create(ctx, record)
+		if err == nil {
+			return record, false, nil
+		}
+		if !errors.Is(err, idempotency.ErrDuplicateKey) {
+			s.logger.Error(ctx, "failed to claim idempotency key", "scope", scope, "error", err.Error())
+			return nil, false, err
+		}
+
+		existing, err := s.repo.Get(ctx, scope, key)
+		if errors.Is(err, idempotency.ErrRecordNotFound) {
+			// Released or expired since the insert; try again
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		stale := !existing.Completed() && now.Sub(existing.CreatedAt) > s.settings.LockTimeout
+		switch {
+		case now.After(existing.ExpiresAt) || stale:
+			if err := s.repo.Delete(ctx, existing.ID); err != nil && !errors.Is(err, idempotency.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		case existing.RequestHash != requestHash:
+			s.logger.Warn(ctx, "idempotency key reused", "scope", scope)
+			return nil, false, idempotency.ErrKeyReused
+		case !existing.Completed():
+			return nil, false, idempotency.ErrKeyInUse
+		default:
+			return existing, true, nil
+		}
+	}
+	return nil, false, idempotency.ErrKeyInUse
+}
+
+// Complete stores the response of a request begun with Begin
+func (s *IdempotencyService) Complete(ctx context.Context, record *idempotency.Record, statusCode int, contentType string, body []byte) error {
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	if err := s.repo.Complete(ctx, record); err != nil {
+		s.logger.Error(ctx, "failed to store idempotent response", "scope", record.Scope, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// Release deletes the record of a request that failed
+func (s *IdempotencyService) Release(ctx context.Context, record *idempotency.Record) error {
+	err := s.repo.Delete(ctx, record.ID)
+	if err != nil && !errors.Is(err, idempotency.ErrRecordNotFound) {
+		s.logger.Error(ctx, "failed to release idempotency key", "scope", record.Scope, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired removes the records past their TTL
+func (s *IdempotencyService) DeleteExpired(ctx context.Context) (int, error) {
+	deleted, err := s.repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		s.logger.Error(ctx, "failed to delete expired idempotency keys", "error", err.Error())
+		return 0, err
+	}
+	if deleted > 0 {
+		s.logger.Info(ctx, "expired idempotency keys deleted", "count", deleted)
+	}
+	return deleted, nil
+}
+
+// Verify that IdempotencyService implements the idempotency.Service interface
+var _ idempotency.Service = (*IdempotencyService)(nil)
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
+)
+
+const (
+	// importBatchSize is the number of posts imported per queued job, so a
+	// batch is done well within the job lease
+	importBatchSize = 25
+	// maxImportTitleLength matches the longest title the API accepts
+	maxImportTitleLength = 500
+	// importSignupMethod is recorded with the accounts created for authors
+	importSignupMethod = "import"
+	// anonymousCommenter names imported comments without an author
+	anonymousCommenter = "Anonymous"
+)
+
+// ImportService implements the imports.Service interface. Uploaded exports
+// are parsed right away, so malformed files are rejected with the upload,
+// and imported in batches of posts by queued jobs.
+//
+// Imported content is written through the repositories rather than the
+// post and comment services: comments keep their exported moderation state,
+// and no events are published, so importing an archive does not notify
+// followers or call webhooks for every old post. Lists cached before the
+// import show the imported posts once they expire.
+type ImportService struct {
+	repo     imports.Repository
+	parser   imports.Parser
+	users    user.Service
+	posts    post.Repository
+	comments comment.Repository
+	tx       TxManager
+	jobs     JobQueue
+	audit    AuditLogger
+	logger   Logger
+}
+
+// NewImportService creates a new ImportService instance
+func NewImportService(repo imports.Repository, parser imports.Parser, users user.Service, posts post.Repository, comments comment.Repository, tx TxManager, jobs JobQueue, audit AuditLogger, logger Logger) *ImportService {
+	return &ImportService{
+		repo:     repo,
+		parser:   parser,
+		users:    users,
+		posts:    posts,
+		comments: comments,
+		tx:       tx,
+		jobs:     jobs,
+		audit:    audit,
+		logger:   logger,
+	}
+}
+
+// importRun holds what an import job learned about the document so far
+type importRun struct {
+	*imports.Import
+	// authors maps the logins of document authors with an account to it
+	authors map[string]int
+	// slugs holds the slugs a dry run would have taken
+	slugs map[string]bool
+}
+
+// Start parses an export file and queues its import
+func (s *ImportService) Start(ctx context.Context, adminID int, data []byte, dryRun bool) (*imports.Import, error) {
+	doc, err := s.parser.Parse(data)
+	if err != nil {
+		s.logger.Warn(ctx, "rejected import file", "adminID", adminID, "error", err.Error())
+		return nil, err
+	}
+
+	i, err := imports.NewImport(adminID, doc, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, i); err != nil {
+		s.logger.Error(ctx, "failed to save import", "adminID", adminID, "error", err.Error())
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	if err := s.jobs.Enqueue(ctx, JobContentImport, ContentImportPayload{ImportID: i.ID}); err != nil {
+		s.logger.Error(ctx, "failed to queue import", "importID", i.ID, "error", err.Error())
+		return nil, fmt.Errorf("failed to queue import: %w", err)
+	}
+
+	s.logger.Info(ctx, "import queued", "importID", i.ID, "source", i.Source, "posts", i.Total, "dryRun", dryRun)
+	return i, nil
+}
+
+// Get returns the status and progress of an import
+func (s *ImportService) Get(ctx context.Context, id int) (*imports.Import, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Run imports the next batch of posts of an import and queues the batch
+// after it; dry runs, which only read, go through the document at once. It
+// handles JobContentImport jobs. Posts that cannot be imported are counted
+// as failed rather than retried, so one bad post does not hold up the rest;
+// errors storing the progress fail the job, which is then retried and
+// resumes after the last post stored. A post imported before such an error
+// is skipped by the retry, its slug being taken.
+func (s *ImportService) Run(ctx context.Context, payload json.RawMessage) error {
+	var job ContentImportPayload
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("invalid content import payload: %w", err)
+	}
+
+	i, err := s.repo.GetByID(ctx, job.ImportID)
+	if err != nil {
+		return err
+	}
+	if i.IsCompleted() {
+		return nil
+	}
+
+	i.Status = imports.StatusRunning
+	run := &importRun{Import: i, authors: make(map[string]int), slugs: make(map[string]bool)}
+	for n := 0; i.Processed < len(i.Document.Posts); n++ {
+		if n == importBatchSize && !i.DryRun {
+			return s.jobs.Enqueue(ctx, JobContentImport, job)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.importPost(ctx, run, i.Processed)
+		i.Processed++
+		if err := s.saveProgress(ctx, i); err != nil {
+			return err
+		}
+	}
+
+	i.Complete()
+	if err := s.saveProgress(ctx, i); err != nil {
+		return err
+	}
+
+	if !i.DryRun {
+		s.audit.Record(ctx, AuditEvent{
+			Action: AuditActionContentImported,
+			UserID: i.CreatedBy,
+			Metadata: map[string]any{
+				"import_id": i.ID,
+				"source":    i.Source,
+				"users":     i.UsersCreated,
+				"posts":     i.PostsCreated,
+				"comments":  i.CommentsCreated,
+			},
+		})
+	}
+	s.logger.Info(ctx, "import completed", "importID", i.ID, "dryRun", i.DryRun, "posts", i.PostsCreated, "comments", i.CommentsCreated,
+		"users", i.UsersCreated, "skipped", i.Skipped, "failed", i.Failed)
+	return nil
+}
+
+// importPost imports a post of the document with its tags and comments,
+// counting the outcome on the import
+func (s *ImportService) importPost(ctx context.Context, run *importRun, index int) {
+	exported := run.Document.Posts[index]
+	label := fmt.Sprintf("post %d %q", index+1, exported.Title)
+
+	title := strings.TrimSpace(exported.Title)
+	if title == "" || utf8.RuneCountInString(title) > maxImportTitleLength {
+		run.Fail("%s: title must be between 1 and %d characters", label, maxImportTitleLength)
+		return
+	}
+	content := strings.TrimSpace(exported.Content)
+	if content == "" {
+		run.Fail("%s: content is empty", label)
+		return
+	}
+
+	slug := post.Slugify(title)
+	if strings.TrimSpace(exported.Slug) != "" {
+		slug = post.Slugify(exported.Slug)
+	}
+	if _, err := s.posts.GetBySlug(ctx, slug); err == nil || run.slugs[slug] {
+		run.Skipped++
+		return
+	} else if !errors.Is(err, post.ErrPostNotFound) {
+		s.logger.Error(ctx, "failed to check imported post slug", "importID", run.ID, "slug", slug, "error", err.Error())
+		run.Fail("%s: %v", label, err)
+		return
+	}
+
+	authorID, found, err := s.resolveAuthor(ctx, run, exported.AuthorLogin)
+	if err != nil {
+		run.Fail("%s: author %s: %v", label, exported.AuthorLogin, err)
+		return
+	}
+	if !found {
+		authorID = run.CreatedBy
+	}
+	commenters := make(map[string]int)
+	for _, c := range exported.Comments {
+		if c.AuthorLogin == "" {
+			continue
+		}
+		if id, found, err := s.resolveAuthor(ctx, run, c.AuthorLogin); err == nil && found {
+			commenters[c.AuthorLogin] = id
+		}
+	}
+
+	if run.DryRun {
+		run.slugs[slug] = true
+		run.PostsCreated++
+		for _, c := range exported.Comments {
+			if strings.TrimSpace(c.Content) != "" {
+				run.CommentsCreated++
+			}
+		}
+		return
+	}
+
+	var created int
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := newImportedPost(exported, title, content, authorID)
+		if err != nil {
+			return err
+		}
+		p.Slug = slug
+		if err := s.posts.Create(ctx, p); err != nil {
+			return err
+		}
+		if tags := importTags(exported.Tags); len(tags) > 0 {
+			if err := s.posts.SetTags(ctx, p.ID, tags); err != nil {
+				return err
+			}
+		}
+		created, err = s.createComments(ctx, p.ID, exported.Comments, commenters)
+		return err
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to import post", "importID", run.ID, "post", index+1, "error", err.Error())
+		run.Fail("%s: %v", label, err)
+		return
+	}
+
+	run.PostsCreated++
+	run.CommentsCreated += created
+}
+
+// resolveAuthor returns the account of a document author, matched by
+// email, creating one when there is none. Authors without an email are not
+// found. Dry runs count the accounts they would create.
+func (s *ImportService) resolveAuthor(ctx context.Context, run *importRun, login string) (int, bool, error) {
+	author, ok := run.Document.Author(login)
+	if !ok || author.Email == "" {
+		return 0, false, nil
+	}
+	key := strings.ToLower(author.Login)
+	if id, ok := run.authors[key]; ok {
+		return id, true, nil
+	}
+
+	var id int
+	u, err := s.users.GetByEmail(ctx, author.Email)
+	switch {
+	case err == nil:
+		id = u.ID
+	case errors.Is(err, user.ErrUserNotFound):
+		if !run.DryRun {
+			name := author.Name
+			if name == "" {
+				name = author.Login
+			}
+			u, err := s.users.RegisterPasswordless(ctx, name, author.Email, importSignupMethod)
+			if err != nil {
+				return 0, false, err
+			}
+			id = u.ID
+		}
+		run.UsersCreated++
+	default:
+		return 0, false, err
+	}
+
+	run.authors[key] = id
+	return id, true, nil
+}
+
+// newImportedPost creates a post, or a draft, keeping the exported
+// publication time
+func newImportedPost(exported imports.Post, title, content string, authorID int) (*post.Post, error) {
+	if exported.Draft {
+		return post.NewDraft(title, content, authorID)
+	}
+
+	p, err := post.NewPost(title, content, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if exported.PublishedAt != nil {
+		p.PublishedAt = exported.PublishedAt
+		p.CreatedAt = *exported.PublishedAt
+		p.UpdatedAt = *exported.PublishedAt
+	}
+	return p, nil
+}
+
+// createComments creates the comments of an imported post. Replies nested
+// deeper than comment.MaxDepth are attached to their deepest allowed
+// ancestor, and replies to comments missing from the export become
+// top-level comments.
+func (s *ImportService) createComments(ctx context.Context, postID int, exported []imports.Comment, commenters map[string]int) (int, error) {
+	byExportID := make(map[string]*comment.Comment)
+	byID := make(map[int]*comment.Comment)
+
+	created := 0
+	for _, e := range threadOrder(exported) {
+		content := strings.TrimSpace(e.Content)
+		if content == "" {
+			continue
+		}
+		name := strings.TrimSpace(e.AuthorName)
+		if name == "" {
+			name = anonymousCommenter
+		}
+
+		c, err := comment.NewComment(postID, name, content)
+		if err != nil {
+			return created, err
+		}
+		c.PostedBy(commenters[e.AuthorLogin])
+		switch {
+		case e.Spam:
+			c.Status = comment.StatusSpam
+		case !e.Approved:
+			c.Status = comment.StatusPending
+		}
+		if !e.CreatedAt.IsZero() {
+			c.CreatedAt = e.CreatedAt
+		}
+
+		if parent := byExportID[e.ParentID]; parent != nil {
+			for parent.Depth >= comment.MaxDepth {
+				parent = byID[*parent.ParentID]
+			}
+			if err := c.ReplyTo(parent); err != nil {
+				return created, err
+			}
+		}
+
+		if err := s.comments.Create(ctx, c); err != nil {
+			return created, err
+		}
+		if e.ID != "" {
+			byExportID[e.ID] = c
+		}
+		byID[c.ID] = c
+		created++
+	}
+	return created, nil
+}
+
+// threadOrder orders exported comments so that parents come before their
+// replies, keeping the export order otherwise
+func threadOrder(exported []imports.Comment) []imports.Comment {
+	index := make(map[string]int, len(exported))
+	for i, c := range exported {
+		if c.ID != "" {
+			index[c.ID] = i
+		}
+	}
+
+	ordered := make([]imports.Comment, 0, len(exported))
+	visited := make([]bool, len(exported))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		if parent, ok := index[exported[i].ParentID]; ok && exported[i].ParentID != "" {
+			visit(parent)
+		}
+		ordered = append(ordered, exported[i])
+	}
+	for i := range exported {
+		visit(i)
+	}
+	return ordered
+}
+
+// importTags normalizes the tags of an imported post, dropping the names
+// that are not valid tags and those past tag.MaxPerPost
+func importTags(names []string) []string {
+	tags := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		normalized, err := tag.Normalize(name)
+		if err != nil || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		tags = append(tags, normalized)
+		if len(tags) == tag.MaxPerPost {
+			break
+		}
+	}
+	return tags
+}
+
+// saveProgress stores the counters of an import
+func (s *ImportService) saveProgress(ctx context.Context, i *imports.Import) error {
+	if err := s.repo.Update(ctx, i); err != nil {
+		s.logger.Error(ctx, "failed to save import progress", "importID", i.ID, "error", err.Error())
+		return fmt.Errorf("failed to update import: %w", err)
+	}
+	return nil
+}
+
+var _ imports.Service = (*ImportService)(nil)
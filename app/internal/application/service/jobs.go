@@ -0,0 +1,45 @@
+package service
+
+import "context"
+
+// Job kinds
+const (
+	// JobSearchPing notifies search engines that a post URL changed
+	JobSearchPing = "search.ping"
+	// JobAnnouncementBatch sends the next batch of an announcement
+	JobAnnouncementBatch = "announcement.batch"
+	// JobSecurityNotification emails the owner of an account about a
+	// security event; the payload is the SecurityEvent
+	JobSecurityNotification = "security.notification"
+	// JobWebhookDelivery sends an event to a webhook
+	JobWebhookDelivery = "webhook.delivery"
+	// JobContentImport imports the posts of an uploaded export
+	JobContentImport = "content.import"
+)
+
+// SearchPingPayload identifies the post a search ping is about
+type SearchPingPayload struct {
+	PostID int    `json:"post_id"`
+	Slug   string `json:"slug"`
+}
+
+// AnnouncementBatchPayload identifies the announcement a batch belongs to
+type AnnouncementBatchPayload struct {
+	AnnouncementID int `json:"announcement_id"`
+}
+
+// WebhookDeliveryPayload identifies the delivery a webhook job sends
+type WebhookDeliveryPayload struct {
+	DeliveryID int `json:"delivery_id"`
+}
+
+// ContentImportPayload identifies the import a content import job runs
+type ContentImportPayload struct {
+	ImportID int `json:"import_id"`
+}
+
+// JobQueue defines the interface for deferring work to background workers.
+// Jobs are retried with backoff until their handler succeeds.
+type JobQueue interface {
+	Enqueue(ctx context.Context, kind string, payload any) error
+}
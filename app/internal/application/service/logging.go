@@ -9,3 +9,32 @@ type Logger interface {
 	Warn(ctx context.Context, msg string, args ...any)
 	Debug(ctx context.Context, msg string, args ...any)
 }
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request it serves,
+// so log lines and audit entries can be traced back to the response
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type userIDKey struct{}
+
+// WithUserID returns a context carrying the ID of the signed-in user a
+// request is served for, so log lines can be attributed to them
+func WithUserID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserIDFromContext returns the user ID set by WithUserID; ok is false for
+// anonymous requests
+func UserIDFromContext(ctx context.Context) (id int, ok bool) {
+	id, ok = ctx.Value(userIDKey{}).(int)
+	return id, ok
+}
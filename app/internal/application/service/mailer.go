@@ -0,0 +1,18 @@
+package service
+
+import "context"
+
+// EmailMessage represents a plain-text transactional email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+	// UnsubscribeURL is advertised through the List-Unsubscribe header of
+	// bulk email when set
+	UnsubscribeURL string
+}
+
+// Mailer defines the interface for sending transactional email
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
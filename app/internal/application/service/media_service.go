@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"time"
+
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/media"
+)
+
+// orphanBatch is the number of orphaned media collected per query
+const orphanBatch = 100
+
+// mediaExtensions maps the image formats accepted to their file extension
+var mediaExtensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
+// MediaSettings holds the configurable behaviour of the media service
+type MediaSettings struct {
+	// Quota caps the bytes the media of each user may take when billing is
+	// disabled; zero does not limit them. Plans set the quota otherwise.
+	Quota int64
+	// OrphanAge is how long uploads are kept before they are collected when
+	// no post references them, leaving time to save the post using them
+	OrphanAge time.Duration
+}
+
+// MediaService implements the media.Service interface, storing uploads in
+// an ObjectStorage
+type MediaService struct {
+	repo     media.Repository
+	storage  ObjectStorage
+	plans    billing.Gate
+	settings MediaSettings
+	logger   Logger
+}
+
+// NewMediaService creates a new media service. With a billing gate, the
+// plan of each user sets their quota.
+func NewMediaService(repo media.Repository, storage ObjectStorage, plans billing.Gate, settings MediaSettings, logger Logger) *MediaService {
+	return &MediaService{
+		repo:     repo,
+		storage:  storage,
+		plans:    plans,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Upload stores an image as is under a new key, so its URL never serves
+// other content and can be cached for good. The format is detected from
+// the content, and the quota of the user is checked before storing.
+func (s *MediaService) Upload(ctx context.Context, userID int, r io.Reader) (*media.Media, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media: %w", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		s.logger.Warn(ctx, "media rejected", "userID", userID, "error", err.Error())
+		return nil, media.ErrInvalidMedia
+	}
+	ext, ok := mediaExtensions[format]
+	if !ok || config.Width <= 0 || config.Height <= 0 {
+		return nil, media.ErrInvalidMedia
+	}
+	if config.Width*config.Height > media.MaxPixels {
+		return nil, media.ErrMediaTooLarge
+	}
+
+	if err := s.checkQuota(ctx, userID, int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate media key: %w", err)
+	}
+
+	m := &media.Media{
+		UserID:      userID,
+		Key:         fmt.Sprintf("media/%d/%s%s", userID, hex.EncodeToString(token), ext),
+		ContentType: "image/" + format,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.storage.Put(ctx, m.Key, bytes.NewReader(data), m.Size, m.ContentType); err != nil {
+		s.logger.Error(ctx, "failed to store media", "userID", userID, "key", m.Key, "error", err.Error())
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, m); err != nil {
+		s.logger.Error(ctx, "failed to save media", "userID", userID, "key", m.Key, "error", err.Error())
+		s.deleteObject(ctx, m.Key)
+		return nil, err
+	}
+	m.URL = s.storage.URL(m.Key)
+
+	s.logger.Info(ctx, "media uploaded", "userID", userID, "mediaID", m.ID, "size", m.Size)
+	return m, nil
+}
+
+// checkQuota checks that an upload of size bytes fits the quota of the user
+// next to the media they store already. Concurrent uploads may overshoot it
+// by a file each.
+func (s *MediaService) checkQuota(ctx context.Context, userID int, size int64) error {
+	if s.plans == nil && s.settings.Quota == 0 {
+		return nil
+	}
+
+	used, err := s.repo.UsageByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to get media usage", "userID", userID, "error", err.Error())
+		return err
+	}
+	if s.plans != nil {
+		return s.plans.CheckUploadQuota(ctx, userID, used, size)
+	}
+	if used+size > s.settings.Quota {
+		s.logger.Warn(ctx, "media quota exceeded", "userID", userID, "used", used, "size", size)
+		return media.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// CollectOrphans deletes the media older than the orphan age that no post
+// or post revision references. The file is deleted before the record, so a
+// failed deletion is retried on the next run.
+func (s *MediaService) CollectOrphans(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.settings.OrphanAge)
+	collected := 0
+	for {
+		orphans, err := s.repo.ListOrphans(ctx, before, orphanBatch)
+		if err != nil {
+			s.logger.Error(ctx, "failed to list orphaned media", "error", err.Error())
+			return collected, err
+		}
+
+		deleted := 0
+		for _, m := range orphans {
+			if err := s.storage.Delete(ctx, m.Key); err != nil {
+				s.logger.Warn(ctx, "failed to delete orphaned media", "mediaID", m.ID, "key", m.Key, "error", err.Error())
+				continue
+			}
+			if err := s.repo.Delete(ctx, m.ID); err != nil {
+				s.logger.Error(ctx, "failed to delete media record", "mediaID", m.ID, "error", err.Error())
+				return collected, err
+			}
+			deleted++
+		}
+		collected += deleted
+
+		// Stop on the last page, or when the storage fails for a whole page
+		// that would be listed again
+		if len(orphans) < orphanBatch || deleted == 0 {
+			break
+		}
+	}
+
+	if collected > 0 {
+		s.logger.Info(ctx, "orphaned media collected", "count", collected)
+	}
+	return collected, nil
+}
+
+// deleteObject removes a stored file on a best-effort basis
+func (s *MediaService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		s.logger.Warn(ctx, "failed to delete media", "key", key, "error", err.Error())
+	}
+}
+
+// Verify that MediaService implements the media.Service interface
+var _ media.Service = (*MediaService)(nil)
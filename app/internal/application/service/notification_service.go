@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/domain/post"
+)
+
+// NotificationService implements the notification.Service interface, and
+// notifies users of the comments published on the event bus
+type NotificationService struct {
+	repo      notification.Repository
+	posts     post.ReadRepository
+	comments  comment.ReadRepository
+	publisher EventPublisher
+	logger    Logger
+}
+
+// NewNotificationService creates a new NotificationService instance
+func NewNotificationService(repo notification.Repository, posts post.ReadRepository, comments comment.ReadRepository, publisher EventPublisher, logger Logger) *NotificationService {
+	return &NotificationService{
+		repo:      repo,
+		posts:     posts,
+		comments:  comments,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Subscribe notifies users of the comments added
+func (s *NotificationService) Subscribe(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, e CommentAdded) {
+		s.CommentPublished(ctx, e.Comment)
+	})
+}
+
+// CommentPublished notifies the author of the post and, for replies, the
+// writer of the parent comment. Nobody is notified of their own comments,
+// and a reply to the post author's comment only notifies them once, as a
+// reply.
+func (s *NotificationService) CommentPublished(ctx context.Context, c *comment.Comment) {
+	recipients := make(map[int]notification.Kind)
+	if c.ParentID != nil {
+		parent, err := s.comments.GetByID(ctx, *c.ParentID)
+		if err != nil {
+			s.logger.Error(ctx, "failed to retrieve parent comment for notification", "commentID", c.ID, "parentID", *c.ParentID, "error", err.Error())
+		} else if parent.UserID != nil {
+			recipients[*parent.UserID] = notification.KindReply
+		}
+	}
+
+	p, err := s.posts.GetByID(ctx, c.PostID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post for notification", "commentID", c.ID, "postID", c.PostID, "error", err.Error())
+	} else if _, ok := recipients[p.AuthorID]; !ok {
+		recipients[p.AuthorID] = notification.KindComment
+	}
+
+	for userID, kind := range recipients {
+		if c.IsPostedBy(userID) {
+			continue
+		}
+		n := notification.NewNotification(userID, kind, c.PostID, c.ID, c.AuthorName)
+		if err := s.repo.Create(ctx, n); err != nil {
+			s.logger.Error(ctx, "failed to save notification", "userID", userID, "commentID", c.ID, "error", err.Error())
+			continue
+		}
+		s.publisher.Publish(ctx, NotificationCreated{Notification: n})
+	}
+}
+
+// List retrieves a page of the notifications of a user with the unread
+// count
+func (s *NotificationService) List(ctx context.Context, userID int, limit, offset int) ([]*notification.Notification, int, error) {
+	notifications, err := s.repo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list notifications", "userID", userID, "error", err.Error())
+		return nil, 0, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count unread notifications", "userID", userID, "error", err.Error())
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+// MarkRead marks a notification of the user read
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID int) error {
+	if err := s.repo.MarkRead(ctx, id, userID, time.Now()); err != nil {
+		if !errors.Is(err, notification.ErrNotificationNotFound) {
+			s.logger.Error(ctx, "failed to mark notification read", "notificationID", id, "userID", userID, "error", err.Error())
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+// Built-in page sizes used when no policy is configured
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// PageLimits holds the default and maximum page size of a resource
+type PageLimits struct {
+	// Default is used when a request does not ask for a page size
+	Default int
+	// Max caps the page size a request may ask for
+	Max int
+}
+
+// Normalize applies the limits to a requested page. A missing (zero or
+// negative) limit becomes the default, larger limits are capped at the
+// maximum and negative offsets start at the first item.
+func (l PageLimits) Normalize(limit, offset int) (int, int) {
+	l = l.withDefaults()
+	if limit <= 0 {
+		limit = l.Default
+	}
+	if limit > l.Max {
+		limit = l.Max
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// withDefaults fills in unset limits with the built-in page sizes
+func (l PageLimits) withDefaults() PageLimits {
+	if l.Max <= 0 {
+		l.Max = maxPageLimit
+	}
+	if l.Default <= 0 {
+		l.Default = defaultPageLimit
+	}
+	if l.Default > l.Max {
+		l.Default = l.Max
+	}
+	return l
+}
+
+// PaginationPolicy holds the page limits of every listable resource, shared
+// by the services and the list endpoints
+type PaginationPolicy struct {
+	Posts     PageLimits
+	Comments  PageLimits
+	Users     PageLimits
+	AuditLogs PageLimits
+}
+
+// DefaultPaginationPolicy returns a policy with the built-in page sizes for
+// every resource
+func DefaultPaginationPolicy() PaginationPolicy {
+	limits := PageLimits{Default: defaultPageLimit, Max: maxPageLimit}
+	return PaginationPolicy{
+		Posts:     limits,
+		Comments:  limits,
+		Users:     limits,
+		AuditLogs: limits,
+	}
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+)
+
+// defaultPasskeyChallengeTTL is used when PasskeySettings leaves the
+// challenge lifetime unset
+const defaultPasskeyChallengeTTL = 5 * time.Minute
+
+// passkeyLoginMethod names passkey logins in audit events
+const passkeyLoginMethod = "passkey"
+
+// PasskeySettings holds the configurable behaviour of the passkey service
+type PasskeySettings struct {
+	RelyingParty auth.RelyingParty
+	// ChallengeTTL is how long a ceremony may take
+	ChallengeTTL time.Duration
+}
+
+// PasskeyService implements the auth.PasskeyService interface
+type PasskeyService struct {
+	userService user.Service
+	passkeys    auth.PasskeyRepository
+	challenges  auth.PasskeyChallengeStore
+	audit       AuditLogger
+	events      SecurityEvents
+	settings    PasskeySettings
+	logger      Logger
+}
+
+// NewPasskeyService creates a new passkey service
+func NewPasskeyService(userService user.Service, passkeys auth.PasskeyRepository, challenges auth.PasskeyChallengeStore, audit AuditLogger, events SecurityEvents, settings PasskeySettings, logger Logger) *PasskeyService {
+	if settings.ChallengeTTL <= 0 {
+		settings.ChallengeTTL = defaultPasskeyChallengeTTL
+	}
+
+	return &PasskeyService{
+		userService: userService,
+		passkeys:    passkeys,
+		challenges:  challenges,
+		audit:       audit,
+		events:      events,
+		settings:    settings,
+		logger:      logger,
+	}
+}
+
+// BeginSignup starts creating a passkey-only account. Addresses that
+// already have an account are refused before the ceremony.
+func (s *PasskeyService) BeginSignup(ctx context.Context, name, email string) (*auth.CreationOptions, error) {
+	if _, err := s.userService.GetByEmail(ctx, email); err == nil {
+		s.logger.Warn(ctx, "passkey signup for existing user", "email", email)
+		return nil, user.ErrUserExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+
+	handle, err := auth.NewUserHandle()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.saveChallenge(ctx, &auth.PasskeySession{
+		Ceremony:   auth.PasskeyCeremonySignup,
+		UserHandle: handle,
+		Name:       name,
+		Email:      email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.settings.RelyingParty.CreationOptions(challenge, handle, email, name, nil, s.settings.ChallengeTTL), nil
+}
+
+// FinishSignup verifies the passkey of a signup and creates the account
+// with it. The account is removed again if its passkey cannot be stored,
+// as it would have no way to sign in.
+func (s *PasskeyService) FinishSignup(ctx context.Context, credential *auth.AttestationResponse) (*user.User, error) {
+	session, challenge, err := s.takeChallenge(ctx, credential.Challenge, auth.PasskeyCeremonySignup)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.settings.RelyingParty.VerifyAttestation(credential, challenge, true)
+	if err != nil {
+		s.logger.Warn(ctx, "invalid passkey signup", "email", session.Email)
+		return nil, err
+	}
+
+	u, err := s.userService.RegisterPasswordless(ctx, session.Name, session.Email, passkeyLoginMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := auth.NewPasskey(u.ID, session.UserHandle, verified, "")
+	if err == nil {
+		err = s.passkeys.Create(ctx, passkey)
+	}
+	if err != nil {
+		s.logger.Error(ctx, "failed to save passkey of new account", "userID", u.ID, "error", err.Error())
+		if deleteErr := s.userService.Delete(ctx, u.ID); deleteErr != nil {
+			s.logger.Error(ctx, "failed to remove account without passkey", "userID", u.ID, "error", deleteErr.Error())
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPasskeyAdded, UserID: u.ID, Metadata: map[string]any{"passkey_id": passkey.ID}})
+	s.logger.Info(ctx, "account created with passkey", "userID", u.ID)
+	return u, nil
+}
+
+// BeginRegistration starts adding a passkey to an account. The passkeys the
+// account has are excluded, so an authenticator is not registered twice.
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID int, name string) (*auth.CreationOptions, error) {
+	name, err := auth.ValidatePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list passkeys", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	// All passkeys of a user share a handle, so authenticators recognize
+	// the account
+	var handle []byte
+	if len(existing) > 0 {
+		handle = existing[0].UserHandle
+	} else if handle, err = auth.NewUserHandle(); err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.saveChallenge(ctx, &auth.PasskeySession{
+		Ceremony:    auth.PasskeyCeremonyRegistration,
+		UserID:      userID,
+		UserHandle:  handle,
+		PasskeyName: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.settings.RelyingParty.CreationOptions(challenge, handle, u.Email, u.Name, existing, s.settings.ChallengeTTL), nil
+}
+
+// FinishRegistration verifies and stores a passkey of the signed-in user
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID int, credential *auth.AttestationResponse) (*auth.Passkey, error) {
+	session, challenge, err := s.takeChallenge(ctx, credential.Challenge, auth.PasskeyCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		s.logger.Warn(ctx, "passkey registration answered by another user", "userID", userID)
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+
+	verified, err := s.settings.RelyingParty.VerifyAttestation(credential, challenge, true)
+	if err != nil {
+		s.logger.Warn(ctx, "invalid passkey registration", "userID", userID)
+		return nil, err
+	}
+
+	passkey, err := auth.NewPasskey(userID, session.UserHandle, verified, session.PasskeyName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.passkeys.Create(ctx, passkey); err != nil {
+		s.logger.Error(ctx, "failed to save passkey", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPasskeyAdded, UserID: userID, Metadata: map[string]any{"passkey_id": passkey.ID}})
+	s.events.Publish(ctx, SecurityEvent{Kind: SecurityEventPasskeyAdded, UserID: userID, Detail: passkey.Name})
+	s.logger.Info(ctx, "passkey registered", "userID", userID, "passkeyID", passkey.ID)
+	return passkey, nil
+}
+
+// BeginLogin starts a passwordless login; the browser offers the
+// discoverable passkeys it has for the site
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*auth.RequestOptions, error) {
+	challenge, err := s.saveChallenge(ctx, &auth.PasskeySession{Ceremony: auth.PasskeyCeremonyLogin})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.settings.RelyingParty.RequestOptions(challenge, nil, "required", s.settings.ChallengeTTL), nil
+}
+
+// FinishLogin verifies a passwordless login and returns the user ID. The
+// user must be verified by the authenticator, so the passkey is both
+// factors.
+func (s *PasskeyService) FinishLogin(ctx context.Context, credential *auth.AssertionResponse) (int, error) {
+	_, challenge, err := s.takeChallenge(ctx, credential.Challenge, auth.PasskeyCeremonyLogin)
+	if err != nil {
+		return 0, err
+	}
+
+	passkey, err := s.verifyAssertion(ctx, credential, challenge, true)
+	if err != nil {
+		return 0, err
+	}
+	return passkey.UserID, nil
+}
+
+// BeginTwoFactor starts the second login step of a user, allowing only the
+// passkeys of the user
+func (s *PasskeyService) BeginTwoFactor(ctx context.Context, userID int) (*auth.RequestOptions, error) {
+	passkeys, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list passkeys", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	if len(passkeys) == 0 {
+		return nil, auth.ErrPasskeyNotFound
+	}
+
+	challenge, err := s.saveChallenge(ctx, &auth.PasskeySession{Ceremony: auth.PasskeyCeremonyTwoFactor, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	// The password was the first factor, so presence is enough
+	return s.settings.RelyingParty.RequestOptions(challenge, passkeys, "preferred", s.settings.ChallengeTTL), nil
+}
+
+// FinishTwoFactor verifies a second-factor assertion and returns the user ID
+// the login is for
+func (s *PasskeyService) FinishTwoFactor(ctx context.Context, credential *auth.AssertionResponse) (int, error) {
+	session, challenge, err := s.takeChallenge(ctx, credential.Challenge, auth.PasskeyCeremonyTwoFactor)
+	if err != nil {
+		return 0, err
+	}
+
+	passkey, err := s.verifyAssertion(ctx, credential, challenge, false)
+	if err != nil {
+		return 0, err
+	}
+	if passkey.UserID != session.UserID {
+		s.logger.Warn(ctx, "second factor answered with passkey of another user", "userID", session.UserID)
+		return 0, auth.ErrInvalidPasskeyAssertion
+	}
+	return passkey.UserID, nil
+}
+
+// HasPasskeys reports whether a user has registered passkeys
+func (s *PasskeyService) HasPasskeys(ctx context.Context, userID int) (bool, error) {
+	passkeys, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(passkeys) > 0, nil
+}
+
+// ListPasskeys returns the passkeys of a user
+func (s *PasskeyService) ListPasskeys(ctx context.Context, userID int) ([]*auth.Passkey, error) {
+	passkeys, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list passkeys", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	return passkeys, nil
+}
+
+// DeletePasskey removes a passkey of a user
+func (s *PasskeyService) DeletePasskey(ctx context.Context, userID, id int) error {
+	passkeys, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list passkeys", "userID", userID, "error", err.Error())
+		return err
+	}
+	var passkey *auth.Passkey
+	for _, p := range passkeys {
+		if p.ID == id {
+			passkey = p
+		}
+	}
+	if passkey == nil {
+		return auth.ErrPasskeyNotFound
+	}
+
+	if err := s.passkeys.Delete(ctx, userID, id); err != nil {
+		if !errors.Is(err, auth.ErrPasskeyNotFound) {
+			s.logger.Error(ctx, "failed to delete passkey", "userID", userID, "passkeyID", id, "error", err.Error())
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPasskeyRemoved, UserID: userID, Metadata: map[string]any{"passkey_id": id}})
+	s.events.Publish(ctx, SecurityEvent{Kind: SecurityEventPasskeyRemoved, UserID: userID, Detail: passkey.Name})
+	s.logger.Info(ctx, "passkey removed", "userID", userID, "passkeyID", id)
+	return nil
+}
+
+// saveChallenge generates a challenge and stores the session answered by it
+func (s *PasskeyService) saveChallenge(ctx context.Context, session *auth.PasskeySession) ([]byte, error) {
+	challenge, key, err := auth.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.challenges.Save(ctx, key, session, s.settings.ChallengeTTL); err != nil {
+		s.logger.Error(ctx, "failed to save passkey challenge", "ceremony", session.Ceremony, "error", err.Error())
+		return nil, fmt.Errorf("failed to save passkey challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// takeChallenge consumes the session of the challenge a credential answers
+// and checks it was issued for the ceremony
+func (s *PasskeyService) takeChallenge(ctx context.Context, challengeOf func() (string, error), ceremony auth.PasskeyCeremony) (*auth.PasskeySession, string, error) {
+	challenge, err := challengeOf()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := s.challenges.Take(ctx, challenge)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidPasskeyChallenge) {
+			s.logger.Error(ctx, "failed to load passkey challenge", "ceremony", ceremony, "error", err.Error())
+		}
+		return nil, "", err
+	}
+	if session.Ceremony != ceremony {
+		s.logger.Warn(ctx, "passkey challenge answered for another ceremony", "ceremony", ceremony, "issued_for", session.Ceremony)
+		return nil, "", auth.ErrInvalidPasskeyChallenge
+	}
+	return session, challenge, nil
+}
+
+// verifyAssertion checks an assertion with the passkey it names and records
+// the use of the passkey
+func (s *PasskeyService) verifyAssertion(ctx context.Context, credential *auth.AssertionResponse, challenge string, requireUserVerification bool) (*auth.Passkey, error) {
+	passkey, err := s.passkeys.GetByCredentialID(ctx, credential.RawID)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasskeyNotFound) {
+			s.logger.Warn(ctx, "assertion with unknown passkey")
+			return nil, auth.ErrInvalidPasskeyAssertion
+		}
+		s.logger.Error(ctx, "failed to load passkey", "error", err.Error())
+		return nil, err
+	}
+
+	signCount, err := s.settings.RelyingParty.VerifyAssertion(credential, challenge, passkey, requireUserVerification)
+	if err != nil {
+		s.logger.Warn(ctx, "invalid passkey assertion", "userID", passkey.UserID, "passkeyID", passkey.ID)
+		return nil, err
+	}
+
+	now := time.Now()
+	passkey.SignCount = signCount
+	passkey.LastUsedAt = &now
+	if err := s.passkeys.UpdateUsage(ctx, passkey); err != nil {
+		// The counter protects against cloned authenticators, so the login
+		// fails if it cannot be stored
+		s.logger.Error(ctx, "failed to record passkey use", "passkeyID", passkey.ID, "error", err.Error())
+		return nil, err
+	}
+	return passkey, nil
+}
@@ -1,26 +1,110 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
 
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/clientid"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
 )
 
+// postListGenerationKey is the cache key of the generation of the cached
+// post lists
+const postListGenerationKey = "posts:generation"
+
+// trendingKey is the cache key of the trending ranking
+const trendingKey = "posts:trending"
+
+// readingStatsBatchSize is the number of posts ComputeMissingReadingStats
+// reads at a time
+const readingStatsBatchSize = 100
+
+// maxSlugAttempts bounds how many numbered variants are tried when a post's
+// slug is already taken
+const maxSlugAttempts = 50
+
+// PostSettings holds the configurable behaviour of the post service
+type PostSettings struct {
+	// NotifySearchEngines queues a search ping when a post is published or updated
+	NotifySearchEngines bool
+	// Pagination bounds the page size of post lists
+	Pagination PageLimits
+	// ConflictWindow is how close two posts may be published before
+	// scheduling reports a conflict; zero disables conflict detection
+	ConflictWindow time.Duration
+	// CacheTTL is how long posts and first pages of the post list are
+	// cached; zero disables caching. Changes made through the service are
+	// seen at once, others such as view counts after at most CacheTTL.
+	CacheTTL time.Duration
+	// TrendingWindow is how far back activity counts towards trending
+	// scores; zero counts the last 72 hours
+	TrendingWindow time.Duration
+	// TrendingHalfLife is the age at which activity counts half; zero
+	// halves it every 24 hours
+	TrendingHalfLife time.Duration
+	// TrendingRefresh is how often the trending ranking is recalculated.
+	// The ranking stays cached for twice as long, so readers do not wait
+	// for it between refreshes; zero leaves it uncached.
+	TrendingRefresh time.Duration
+}
+
 // PostService implements the post.Service interface
 type PostService struct {
-	repo   post.Repository
-	logger Logger
+	repo      post.Repository
+	comments  comment.WriteRepository
+	tx        TxManager
+	cache     cacheAside
+	trending  cacheAside // keeps the trending ranking between refreshes
+	jobs      JobQueue
+	views     ViewCounter
+	audit     AuditLogger
+	plans     billing.Gate // gates post creation; nil when billing is disabled
+	publisher EventPublisher
+	related   post.RelatednessStrategy // finds related posts; nil lists none
+	settings  PostSettings
+	logger    Logger
 }
 
 // NewPostService creates a new PostService instance
-func NewPostService(repo post.Repository, logger Logger) *PostService {
+func NewPostService(repo post.Repository, comments comment.WriteRepository, tx TxManager, cache Cache, jobs JobQueue, views ViewCounter, audit AuditLogger, plans billing.Gate, publisher EventPublisher, related post.RelatednessStrategy, settings PostSettings, logger Logger) *PostService {
 	return &PostService{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		comments:  comments,
+		tx:        tx,
+		cache:     cacheAside{cache: cache, ttl: settings.CacheTTL, logger: logger},
+		trending:  cacheAside{cache: cache, ttl: 2 * settings.TrendingRefresh, logger: logger},
+		jobs:      jobs,
+		views:     views,
+		audit:     audit,
+		plans:     plans,
+		publisher: publisher,
+		related:   related,
+		settings:  settings,
+		logger:    logger,
 	}
 }
 
-// CreatePost creates a new post with validation
+// Subscribe keeps the cache in step with the posts other services change
+func (s *PostService) Subscribe(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, e SeriesPostsChanged) {
+		keys := make([]string, 0, len(e.PostIDs)+1)
+		for _, id := range e.PostIDs {
+			keys = append(keys, postCacheKey(id))
+		}
+		s.cache.forget(ctx, append(keys, postListGenerationKey)...)
+	})
+}
+
+// CreatePost creates a new published post with validation
 func (s *PostService) CreatePost(ctx context.Context, userID int, title, content string) (*post.Post, error) {
 	s.logger.Info(ctx, "creating post", "userID", userID, "title", title)
 	
@@ -31,59 +115,492 @@ func (s *PostService) CreatePost(ctx context.Context, userID int, title, content
 		return nil, err
 	}
 
+	return s.create(ctx, p)
+}
+
+// CreateDraft creates a new unpublished post with validation
+func (s *PostService) CreateDraft(ctx context.Context, userID int, title, content string) (*post.Post, error) {
+	s.logger.Info(ctx, "creating draft post", "userID", userID, "title", title)
+
+	p, err := post.NewDraft(title, content, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create post entity", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	return s.create(ctx, p)
+}
+
+// CreateClientPost creates a post, or a draft, under a UUID the client
+// generated. Offline clients resend writes they got no answer to, so a
+// client ID the author already used returns the post it created with
+// created false; client IDs used by another author conflict.
+func (s *PostService) CreateClientPost(ctx context.Context, userID int, clientID, title, content string, draft bool) (*post.Post, bool, error) {
+	clientID, err := clientid.Normalize(clientID)
+	if err != nil {
+		return nil, false, err
+	}
+	if p, err := s.replayClientPost(ctx, userID, clientID); err != nil || p != nil {
+		return p, false, err
+	}
+
+	s.logger.Info(ctx, "creating post", "userID", userID, "title", title, "clientID", clientID)
+
+	newPost := post.NewPost
+	if draft {
+		newPost = post.NewDraft
+	}
+	p, err := newPost(title, content, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create post entity", "userID", userID, "error", err.Error())
+		return nil, false, err
+	}
+	p.ClientID = &clientID
+
+	created, err := s.create(ctx, p)
+	if errors.Is(err, post.ErrClientIDTaken) {
+		// A concurrent retry saved the post first
+		if p, err := s.replayClientPost(ctx, userID, clientID); err != nil || p != nil {
+			return p, false, err
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return created, true, nil
+}
+
+// replayClientPost returns the post created under a client ID, or nil when
+// there is none yet. Posts of other authors are not revealed.
+func (s *PostService) replayClientPost(ctx context.Context, userID int, clientID string) (*post.Post, error) {
+	p, err := s.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, post.ErrPostNotFound) {
+			return nil, nil
+		}
+		s.logger.Error(ctx, "failed to retrieve post by client ID", "clientID", clientID, "error", err.Error())
+		return nil, err
+	}
+	if !p.IsAuthor(userID) {
+		s.logger.Warn(ctx, "client ID used by another author", "userID", userID, "clientID", clientID)
+		return nil, post.ErrClientIDTaken
+	}
+	s.logger.Info(ctx, "post already created under client ID", "userID", userID, "postID", p.ID, "clientID", clientID)
+	return p, nil
+}
+
+// create allocates a slug for a new post and saves it
+func (s *PostService) create(ctx context.Context, p *post.Post) (*post.Post, error) {
+	if s.plans != nil {
+		if err := s.plans.CheckPostLimit(ctx, p.AuthorID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Titles are not unique, so number the slug until it is
+	var err error
+	p.Slug, err = s.uniqueSlug(ctx, p.Slug)
+	if err != nil {
+		s.logger.Error(ctx, "failed to allocate post slug", "userID", p.AuthorID, "error", err.Error())
+		return nil, err
+	}
+
 	// Save to repository
 	err = s.repo.Create(ctx, p)
 	if err != nil {
-		s.logger.Error(ctx, "failed to save post to repository", "userID", userID, "postID", p.ID, "error", err.Error())
+		s.logger.Error(ctx, "failed to save post to repository", "userID", p.AuthorID, "postID", p.ID, "error", err.Error())
 		return nil, err
 	}
+	s.forgetPost(ctx, p.ID)
 
-	s.logger.Info(ctx, "post created successfully", "userID", userID, "postID", p.ID, "title", title)
+	s.logger.Info(ctx, "post created successfully", "userID", p.AuthorID, "postID", p.ID, "title", p.Title, "status", string(p.Status))
+	s.notifySearchEngines(ctx, p)
+	s.publisher.Publish(ctx, PostCreated{Post: p})
 	return p, nil
 }
 
 // GetPost retrieves a post by ID
 func (s *PostService) GetPost(ctx context.Context, id int) (*post.Post, error) {
 	s.logger.Debug(ctx, "retrieving post", "postID", id)
+
+	key := postCacheKey(id)
+	var cached post.Post
+	if s.cache.load(ctx, key, &cached) {
+		return &cached, nil
+	}
 	
-	post, err := s.repo.GetByID(ctx, id)
+	p, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		s.logger.Error(ctx, "failed to retrieve post", "postID", id, "error", err.Error())
 		return nil, err
 	}
+	s.cache.store(ctx, key, p)
 	
 	s.logger.Debug(ctx, "post retrieved successfully", "postID", id)
-	return post, nil
+	return p, nil
 }
 
-// GetPostsByAuthor retrieves posts by author ID with pagination
-func (s *PostService) GetPostsByAuthor(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
-	// Validate and normalize pagination parameters
-	if limit <= 0 || limit > 100 {
-		limit = 100
+// GetVisiblePost retrieves a post the user may read, from the cache like
+// GetPost. Pass a zero userID for anonymous readers.
+func (s *PostService) GetVisiblePost(ctx context.Context, userID int, role user.Role, id int) (*post.Post, error) {
+	key := postCacheKey(id)
+	var cached post.Post
+	if s.cache.load(ctx, key, &cached) {
+		// Cached posts are shared by all readers, so they are checked the
+		// way the lookup filters them
+		if !cached.IsVisibleTo(userID, role) {
+			return nil, post.ErrPostNotFound
+		}
+		return &cached, nil
 	}
-	if offset < 0 {
-		offset = 0
+
+	filter := post.ViewFilter{AuthorID: userID, All: role.IsAdmin()}
+	p, err := s.repo.GetVisibleByID(ctx, id, filter)
+	if err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post", "postID", id, "error", err.Error())
+		}
+		return nil, err
 	}
+	s.cache.store(ctx, key, p)
+	return p, nil
+}
+
+// GetPostBySlug retrieves a post by its slug
+func (s *PostService) GetPostBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	s.logger.Debug(ctx, "retrieving post by slug", "slug", slug)
+
+	p, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post by slug", "slug", slug, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetPostByClientID retrieves a post by the client ID it was created with
+func (s *PostService) GetPostByClientID(ctx context.Context, clientID string) (*post.Post, error) {
+	clientID, err := clientid.Normalize(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post by client ID", "clientID", clientID, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetPostsByAuthor retrieves posts by author ID with pagination
+func (s *PostService) GetPostsByAuthor(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
 
 	return s.repo.GetByAuthorID(ctx, authorID, limit, offset)
 }
 
-// ListPosts retrieves all posts with pagination
+// ListPosts retrieves published posts with pagination
 func (s *PostService) ListPosts(ctx context.Context, limit, offset int) ([]*post.Post, error) {
-	// Validate and normalize pagination parameters
-	if limit <= 0 || limit > 100 {
-		limit = 100
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	// Only the first page is cached; readers rarely page further
+	if offset > 0 {
+		return s.repo.ListPublished(ctx, limit, offset)
+	}
+
+	key := "posts:published:" + s.cache.generation(ctx, postListGenerationKey) + ":" + strconv.Itoa(limit)
+	var cached []*post.Post
+	if s.cache.load(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	posts, err := s.repo.ListPublished(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) > 0 {
+		s.cache.store(ctx, key, posts)
+	}
+	return posts, nil
+}
+
+// ListPublishedPosts retrieves the published posts matching the filter with
+// pagination, most recently published first
+func (s *PostService) ListPublishedPosts(ctx context.Context, filter post.PublishedFilter, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	if filter.Tag != "" {
+		normalized, err := tag.Normalize(filter.Tag)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tag = normalized
+	}
+	return s.repo.ListPublishedBy(ctx, filter, limit, offset)
+}
+
+// ListFeaturedPosts retrieves the featured published posts with pagination,
+// most recently featured first
+func (s *PostService) ListFeaturedPosts(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	posts, err := s.repo.ListFeatured(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list featured posts", "error", err.Error())
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPostsFor retrieves the posts a signed-in user may see with pagination:
+// published posts and their own drafts and scheduled posts, or every post
+// for admins
+func (s *PostService) ListPostsFor(ctx context.Context, userID int, role user.Role, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	if role.IsAdmin() {
+		return s.repo.List(ctx, limit, offset)
+	}
+	return s.repo.ListVisibleTo(ctx, userID, limit, offset)
+}
+
+// ListPostsByTag retrieves the posts carrying a tag with pagination, with
+// the same visibility as ListPostsFor; anonymous readers pass a zero userID
+func (s *PostService) ListPostsByTag(ctx context.Context, userID int, role user.Role, name string, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := post.TagFilter{Tag: normalized, AuthorID: userID, All: role.IsAdmin()}
+	return s.repo.ListByTag(ctx, filter, limit, offset)
+}
+
+// ListPostsMatching retrieves the posts matching the options with
+// pagination, with the same visibility as ListPostsByTag; an empty tag lists
+// every post
+func (s *PostService) ListPostsMatching(ctx context.Context, userID int, role user.Role, name string, options post.ListOptions, limit, offset int) ([]*post.Post, error) {
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	filter := post.ListFilter{AuthorID: userID, All: role.IsAdmin()}
+	if name != "" {
+		normalized, err := tag.Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tag = normalized
+	}
+
+	return s.repo.ListMatching(ctx, filter, options.WithDefaults(), limit, offset)
+}
+
+// ListPostsAfter retrieves a page of the posts after a cursor, newest first,
+// with the same visibility as ListPostsByTag; an empty tag lists every post.
+// The returned cursor continues after the page and is zero on the last page.
+func (s *PostService) ListPostsAfter(ctx context.Context, userID int, role user.Role, name string, after keyset.Cursor, limit int) ([]*post.Post, keyset.Cursor, error) {
+	limit, _ = s.settings.Pagination.Normalize(limit, 0)
+
+	filter := post.ListFilter{AuthorID: userID, All: role.IsAdmin()}
+	if name != "" {
+		normalized, err := tag.Normalize(name)
+		if err != nil {
+			return nil, keyset.Cursor{}, err
+		}
+		filter.Tag = normalized
+	}
+
+	// One extra post tells whether another page follows
+	posts, err := s.repo.ListAfter(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, keyset.Cursor{}, err
+	}
+	if len(posts) <= limit {
+		return posts, keyset.Cursor{}, nil
+	}
+	posts = posts[:limit]
+	last := posts[limit-1]
+	return posts, keyset.After(last.CreatedAt, last.ID), nil
+}
+
+// ListPopularPosts retrieves the published posts with the most views over
+// the last days, today included
+func (s *PostService) ListPopularPosts(ctx context.Context, days, limit int) ([]*post.PopularPost, error) {
+	if days < 1 || days > post.MaxPopularWindow {
+		return nil, post.ErrInvalidPopularWindow
 	}
-	if offset < 0 {
-		offset = 0
+	limit, _ = s.settings.Pagination.Normalize(limit, 0)
+
+	since := post.ViewDay(time.Now()).AddDate(0, 0, 1-days)
+	popular, err := s.repo.ListPopular(ctx, since, limit)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list popular posts", "days", days, "error", err.Error())
+		return nil, err
 	}
+	return popular, nil
+}
 
-	return s.repo.List(ctx, limit, offset)
+// ListTrendingPosts retrieves the published posts with the highest trending
+// scores. The ranking is read from the cache, where RefreshTrending keeps
+// it; it is only calculated on request when missing.
+func (s *PostService) ListTrendingPosts(ctx context.Context, limit int) ([]*post.TrendingPost, error) {
+	if limit <= 0 {
+		limit = post.DefaultTrendingLimit
+	}
+	limit = min(limit, post.MaxTrendingLimit)
+
+	var ranking []post.TrendingScore
+	if !s.trending.load(ctx, trendingKey, &ranking) {
+		var err error
+		if ranking, err = s.RefreshTrending(ctx); err != nil {
+			return nil, err
+		}
+	}
+	ids := make([]int, len(ranking))
+	for i, r := range ranking {
+		ids[i] = r.PostID
+	}
+	posts, err := s.repo.ListPublishedByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list trending posts", "error", err.Error())
+		return nil, err
+	}
+	byID := make(map[int]*post.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	// Posts deleted or unpublished since the ranking was calculated are
+	// left out before taking the limit, so the posts ranked after them
+	// fill their places
+	trending := make([]*post.TrendingPost, 0, limit)
+	for _, r := range ranking {
+		if len(trending) == limit {
+			break
+		}
+		if p, ok := byID[r.PostID]; ok {
+			trending = append(trending, &post.TrendingPost{Post: p, Score: r.Score})
+		}
+	}
+	return trending, nil
+}
+
+// RefreshTrending recalculates the trending ranking from the views,
+// bookmarks and comments of the trending window and caches it
+func (s *PostService) RefreshTrending(ctx context.Context) ([]post.TrendingScore, error) {
+	window := cmp.Or(s.settings.TrendingWindow, post.DefaultTrendingWindow)
+	halfLife := cmp.Or(s.settings.TrendingHalfLife, post.DefaultTrendingHalfLife)
+
+	now := time.Now()
+	activity, err := s.repo.ListActivity(ctx, now.Add(-window))
+	if err != nil {
+		s.logger.Error(ctx, "failed to list post activity", "error", err.Error())
+		return nil, err
+	}
+	ranking := post.RankTrending(activity, now, halfLife, post.MaxTrendingLimit)
+	s.trending.store(ctx, trendingKey, ranking)
+	return ranking, nil
+}
+
+// ListRelatedPosts retrieves the published posts related to a post, as the
+// relatedness strategy finds them
+func (s *PostService) ListRelatedPosts(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	if limit <= 0 {
+		limit = post.DefaultRelatedLimit
+	}
+	limit = min(limit, post.MaxRelatedLimit)
+	if s.related == nil {
+		return []*post.Post{}, nil
+	}
+
+	key := "posts:related:" + s.cache.generation(ctx, postListGenerationKey) + ":" + strconv.Itoa(p.ID) + ":" + strconv.Itoa(limit)
+	var cached []*post.Post
+	if s.cache.load(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	found, err := s.related.Related(ctx, p, limit)
+	if err != nil {
+		s.logger.Error(ctx, "failed to find related posts", "postID", p.ID, "error", err.Error())
+		return nil, err
+	}
+	// Strategies may be swapped for others; never list the post itself or
+	// posts left out of listings
+	related := make([]*post.Post, 0, len(found))
+	for _, candidate := range found {
+		if candidate.ID != p.ID && candidate.IsListed() && len(related) < limit {
+			related = append(related, candidate)
+		}
+	}
+	s.cache.store(ctx, key, related)
+	return related, nil
+}
+
+// GatePost returns the post as a reader may see it: the post itself when
+// they are entitled to its content, a locked teaser otherwise. Anonymous
+// readers pass a zero userID; their variant never depends on the reader, so
+// it is the one to put in shared caches.
+func (s *PostService) GatePost(ctx context.Context, userID int, role user.Role, p *post.Post) *post.Post {
+	return s.GatePosts(ctx, userID, role, []*post.Post{p})[0]
+}
+
+// GatePosts returns the posts as a reader may see them, like GatePost. The
+// posts are not modified; the plan of the reader is looked up at most once.
+func (s *PostService) GatePosts(ctx context.Context, userID int, role user.Role, posts []*post.Post) []*post.Post {
+	reader := post.Reader{UserID: userID, Role: role}
+	checked := false
+
+	gated := make([]*post.Post, len(posts))
+	for i, p := range posts {
+		if p.Access == post.AccessSubscribers && !checked && !p.IsReadableBy(reader) {
+			reader.Subscriber = s.isSubscriber(ctx, userID)
+			checked = true
+		}
+		if p.IsReadableBy(reader) {
+			gated[i] = p
+		} else {
+			gated[i] = p.Teaser()
+		}
+	}
+	return gated
+}
+
+// isSubscriber checks if a user is on a paid plan. Lookup failures lock
+// subscriber-only posts rather than fail the request.
+func (s *PostService) isSubscriber(ctx context.Context, userID int) bool {
+	if userID <= 0 || s.plans == nil {
+		return false
+	}
+	err := s.plans.CheckPaidPlan(ctx, userID)
+	if err != nil && !errors.Is(err, billing.ErrPlanLimit) {
+		s.logger.Error(ctx, "failed to check plan of reader", "userID", userID, "error", err.Error())
+	}
+	return err == nil
+}
+
+// RecordView counts a view of a post. Only published posts are counted, so
+// authors previewing their drafts do not inflate the count.
+func (s *PostService) RecordView(ctx context.Context, p *post.Post) {
+	if !p.IsPublished() {
+		return
+	}
+	s.views.Record(p.ID)
 }
 
 // UpdatePost updates a post with authorization checks
-func (s *PostService) UpdatePost(ctx context.Context, userID, postID int, title, content string) (*post.Post, error) {
+func (s *PostService) UpdatePost(ctx context.Context, userID int, role user.Role, postID int, title, content string) (*post.Post, error) {
 	s.logger.Info(ctx, "updating post", "userID", userID, "postID", postID)
 	
 	// Get the existing post
@@ -93,12 +610,15 @@ func (s *PostService) UpdatePost(ctx context.Context, userID, postID int, title,
 		return nil, err
 	}
 
-	// Check authorization - only the author can update the post
-	if !existingPost.IsAuthor(userID) {
+	// Check authorization - only the author or an admin can update the post
+	if !existingPost.CanModify(userID, role) {
 		s.logger.Warn(ctx, "unauthorized post update attempt", "userID", userID, "postID", postID, "authorID", existingPost.AuthorID)
 		return nil, post.ErrUnauthorized
 	}
 
+	// Keep the current version as a revision
+	revision := post.NewRevision(existingPost, userID)
+
 	// Update the post with validation
 	err = existingPost.Update(title, content)
 	if err != nil {
@@ -107,18 +627,494 @@ func (s *PostService) UpdatePost(ctx context.Context, userID, postID int, title,
 	}
 
 	// Save the updated post
-	err = s.repo.Update(ctx, existingPost)
+	err = s.repo.UpdateWithRevision(ctx, existingPost, revision)
 	if err != nil {
 		s.logger.Error(ctx, "failed to save updated post", "postID", postID, "error", err.Error())
 		return nil, err
 	}
+	s.forgetPost(ctx, postID)
 
 	s.logger.Info(ctx, "post updated successfully", "userID", userID, "postID", postID)
+	s.notifySearchEngines(ctx, existingPost)
+	return existingPost, nil
+}
+
+// SetNoIndex sets whether search engines may index a post, with the same
+// authorization checks as updates
+func (s *PostService) SetNoIndex(ctx context.Context, userID int, role user.Role, postID int, noindex bool) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post indexing", "userID", userID, "postID", postID, "noindex", noindex)
+
+	existingPost, err := s.repo.GetByID(ctx, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post for indexing change", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	if !existingPost.CanModify(userID, role) {
+		s.logger.Warn(ctx, "unauthorized post indexing change attempt", "userID", userID, "postID", postID, "authorID", existingPost.AuthorID)
+		return nil, post.ErrUnauthorized
+	}
+
+	existingPost.NoIndex = noindex
+	if err := s.repo.Update(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save post indexing change", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.notifySearchEngines(ctx, existingPost)
+	return existingPost, nil
+}
+
+// SetPinned pins a published post to the top of the post lists, or unpins
+// it. Only admins curate posts; the routes enforce it.
+func (s *PostService) SetPinned(ctx context.Context, adminID, postID int, pinned bool) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post pinned", "adminID", adminID, "postID", postID, "pinned", pinned)
+
+	action := AuditActionPostPinned
+	if !pinned {
+		action = AuditActionPostUnpinned
+	}
+	return s.curate(ctx, adminID, postID, action, func(p *post.Post) error {
+		return p.SetPinned(pinned, time.Now())
+	})
+}
+
+// SetFeatured adds a published post to the featured set, or removes it.
+// Only admins curate posts; the routes enforce it.
+func (s *PostService) SetFeatured(ctx context.Context, adminID, postID int, featured bool) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post featured", "adminID", adminID, "postID", postID, "featured", featured)
+
+	action := AuditActionPostFeatured
+	if !featured {
+		action = AuditActionPostUnfeatured
+	}
+	return s.curate(ctx, adminID, postID, action, func(p *post.Post) error {
+		return p.SetFeatured(featured, time.Now())
+	})
+}
+
+// curate applies a pin or feature change to a post, then saves and audits it
+func (s *PostService) curate(ctx context.Context, adminID, postID int, action string, apply func(p *post.Post) error) (*post.Post, error) {
+	existingPost, err := s.repo.GetByID(ctx, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post for curation", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	if err := apply(existingPost); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save post curation", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   action,
+		UserID:   adminID,
+		Metadata: map[string]any{"post_id": postID, "title": existingPost.Title},
+	})
+	return existingPost, nil
+}
+
+// SetAccess sets who may read the full content of a post, with the same
+// authorization checks as updates. Subscriber-only posts need billing.
+func (s *PostService) SetAccess(ctx context.Context, userID int, role user.Role, postID int, access post.Access) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post access", "userID", userID, "postID", postID, "access", string(access))
+
+	if !access.IsValid() {
+		return nil, post.ErrInvalidAccess
+	}
+	if access == post.AccessSubscribers && s.plans == nil {
+		return nil, post.ErrSubscribersUnavailable
+	}
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingPost.Access = access
+	if err := s.repo.Update(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save post access change", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.notifySearchEngines(ctx, existingPost)
+	return existingPost, nil
+}
+
+// SetVisibility makes a post public, unlisted or private, with the same
+// authorization checks as updates. A published post made public is
+// announced like a newly published one.
+func (s *PostService) SetVisibility(ctx context.Context, userID int, role user.Role, postID int, visibility post.Visibility) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post visibility", "userID", userID, "postID", postID, "visibility", string(visibility))
+
+	if !visibility.IsValid() {
+		return nil, post.ErrInvalidVisibility
+	}
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, err
+	}
+	if existingPost.Visibility == visibility {
+		return existingPost, nil
+	}
+
+	wasListed := existingPost.IsListed()
+	existingPost.Visibility = visibility
+	existingPost.UpdatedAt = time.Now()
+	if err := s.repo.SetVisibility(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save post visibility change", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.notifySearchEngines(ctx, existingPost)
+	if !wasListed {
+		s.announcePublished(ctx, existingPost)
+	}
+	return existingPost, nil
+}
+
+// ChangeSlug sets the slug of a post, with the same authorization checks as
+// updates. The previous slug redirects to the post.
+func (s *PostService) ChangeSlug(ctx context.Context, userID int, role user.Role, postID int, slug string) (*post.Post, error) {
+	s.logger.Info(ctx, "changing post slug", "userID", userID, "postID", postID, "slug", slug)
+
+	if !post.IsValidSlug(slug) {
+		return nil, post.ErrInvalidSlug
+	}
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, err
+	}
+	if existingPost.Slug == slug {
+		return existingPost, nil
+	}
+
+	if _, err := s.repo.GetBySlug(ctx, slug); err == nil {
+		return nil, post.ErrSlugTaken
+	} else if err != post.ErrPostNotFound {
+		s.logger.Error(ctx, "failed to check post slug", "slug", slug, "error", err.Error())
+		return nil, err
+	}
+
+	if err := s.repo.ChangeSlug(ctx, postID, slug); err != nil {
+		if err != post.ErrSlugTaken {
+			s.logger.Error(ctx, "failed to save post slug change", "postID", postID, "error", err.Error())
+		}
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.logger.Info(ctx, "post slug changed", "postID", postID, "from", existingPost.Slug, "to", slug)
+	existingPost.Slug = slug
+	s.notifySearchEngines(ctx, existingPost)
+	return existingPost, nil
+}
+
+// SetTags replaces the tags of a post, with the same authorization checks
+// as updates
+func (s *PostService) SetTags(ctx context.Context, userID int, role user.Role, postID int, tags []string) (*post.Post, error) {
+	s.logger.Info(ctx, "setting post tags", "userID", userID, "postID", postID, "tags", len(tags))
+
+	normalized, err := tag.NormalizeNames(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetTags(ctx, postID, normalized); err != nil {
+		s.logger.Error(ctx, "failed to save post tags", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	existingPost.Tags = sortedTags(normalized)
+	return existingPost, nil
+}
+
+// PublishPost publishes a draft or scheduled post right away, with the same
+// authorization checks as updates
+func (s *PostService) PublishPost(ctx context.Context, userID int, role user.Role, postID int) (*post.Post, error) {
+	s.logger.Info(ctx, "publishing post", "userID", userID, "postID", postID)
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := existingPost.Publish(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save published post", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.logger.Info(ctx, "post published", "userID", userID, "postID", postID)
+	s.notifySearchEngines(ctx, existingPost)
+	s.announcePublished(ctx, existingPost)
+	return existingPost, nil
+}
+
+// SchedulePost sets a draft or scheduled post to be published at publishAt
+// by PublishScheduledPosts, with the same authorization checks as updates.
+// Posts due within the conflict window are returned as warnings; they do
+// not prevent scheduling.
+func (s *PostService) SchedulePost(ctx context.Context, userID int, role user.Role, postID int, publishAt time.Time, timezone string) (*post.Post, []post.Conflict, error) {
+	s.logger.Info(ctx, "scheduling post", "userID", userID, "postID", postID, "publishAt", publishAt, "timezone", timezone)
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := existingPost.Schedule(publishAt, timezone, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if err := s.repo.Update(ctx, existingPost); err != nil {
+		s.logger.Error(ctx, "failed to save scheduled post", "postID", postID, "error", err.Error())
+		return nil, nil, err
+	}
+	s.forgetPost(ctx, postID)
+	s.logger.Info(ctx, "post scheduled", "userID", userID, "postID", postID, "publishAt", publishAt)
+
+	if s.settings.ConflictWindow <= 0 {
+		return existingPost, nil, nil
+	}
+	// The post is scheduled either way; a failed check only loses the warnings
+	window := s.settings.ConflictWindow
+	nearby, err := s.repo.ListPublishingBetween(ctx, publishAt.Add(-window), publishAt.Add(window))
+	if err != nil {
+		s.logger.Warn(ctx, "failed to check scheduling conflicts", "postID", postID, "error", err.Error())
+		return existingPost, nil, nil
+	}
+	return existingPost, s.conflicts(existingPost, nearby, userID, role), nil
+}
+
+// Calendar lists the scheduled and published posts the user may see with a
+// publish time in the range: published posts and their own scheduled posts,
+// or every post for admins. Conflicts are checked against every post, so
+// authors are warned about the plans of others without seeing them.
+func (s *PostService) Calendar(ctx context.Context, userID int, role user.Role, from, to time.Time) ([]*post.CalendarEntry, error) {
+	if !to.After(from) || to.Sub(from) > post.MaxCalendarSpan {
+		return nil, post.ErrInvalidCalendarRange
+	}
+
+	// Posts just outside the range can still conflict with those inside
+	window := max(s.settings.ConflictWindow, 0)
+	posts, err := s.repo.ListPublishingBetween(ctx, from.Add(-window), to.Add(window))
+	if err != nil {
+		s.logger.Error(ctx, "failed to list calendar posts", "error", err.Error())
+		return nil, err
+	}
+
+	entries := make([]*post.CalendarEntry, 0, len(posts))
+	for _, p := range posts {
+		if p.PublishedAt.Before(from) || p.PublishedAt.After(to) || !p.IsVisibleTo(userID, role) {
+			continue
+		}
+		entry := &post.CalendarEntry{Post: p}
+		if p.Status == post.StatusScheduled {
+			entry.Conflicts = s.conflicts(p, posts, userID, role)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// conflicts finds the posts due within the conflict window of a post,
+// hiding which posts they are when the user may not see them
+func (s *PostService) conflicts(p *post.Post, others []*post.Post, userID int, role user.Role) []post.Conflict {
+	var conflicts []post.Conflict
+	for _, other := range post.FindConflicts(p, others, s.settings.ConflictWindow) {
+		conflict := post.Conflict{PublishAt: *other.PublishedAt}
+		if other.IsVisibleTo(userID, role) {
+			conflict.PostID = other.ID
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// PublishScheduledPosts publishes the scheduled posts whose publish time has
+// come and returns how many were published. It is run periodically by the
+// scheduler.
+func (s *PostService) PublishScheduledPosts(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.repo.ListDueScheduled(ctx, now)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list scheduled posts", "error", err.Error())
+		return 0, err
+	}
+
+	published := 0
+	for _, p := range due {
+		if err := p.Publish(now); err != nil {
+			continue
+		}
+		if err := s.repo.Update(ctx, p); err != nil {
+			s.logger.Error(ctx, "failed to publish scheduled post", "postID", p.ID, "error", err.Error())
+			continue
+		}
+		s.forgetPost(ctx, p.ID)
+		published++
+		s.notifySearchEngines(ctx, p)
+		s.announcePublished(ctx, p)
+	}
+
+	if published > 0 {
+		s.logger.Info(ctx, "published scheduled posts", "count", published)
+	}
+	return published, nil
+}
+
+// ComputeMissingReadingStats computes the word count, reading time and
+// excerpt of posts stored before they were kept, and returns how many posts
+// were updated. Posts written since get them on every write; this is run
+// once on startup.
+func (s *PostService) ComputeMissingReadingStats(ctx context.Context) (int, error) {
+	computed, afterID := 0, 0
+	for {
+		batch, err := s.repo.ListMissingReadingStats(ctx, afterID, readingStatsBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "failed to list posts missing reading stats", "error", err.Error())
+			return computed, err
+		}
+		for _, p := range batch {
+			p.RefreshReadingStats()
+			if err := s.repo.SetReadingStats(ctx, p); err != nil {
+				s.logger.Error(ctx, "failed to save post reading stats", "postID", p.ID, "error", err.Error())
+				return computed, err
+			}
+			s.forgetPost(ctx, p.ID)
+			computed++
+			afterID = p.ID
+		}
+		if len(batch) < readingStatsBatchSize {
+			break
+		}
+	}
+
+	if computed > 0 {
+		s.logger.Info(ctx, "computed missing post reading stats", "count", computed)
+	}
+	return computed, nil
+}
+
+// ListRevisions retrieves a post and its earlier versions, newest first,
+// with the same authorization checks as updates
+func (s *PostService) ListRevisions(ctx context.Context, userID int, role user.Role, postID int) (*post.Post, []*post.Revision, error) {
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revisions, err := s.repo.ListRevisions(ctx, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list post revisions", "postID", postID, "error", err.Error())
+		return nil, nil, err
+	}
+
+	return existingPost, revisions, nil
+}
+
+// RestoreRevision brings back the title and content of an earlier version
+// of a post. Only the author may restore; the replaced version is kept as a
+// new revision, so a restore can be undone.
+func (s *PostService) RestoreRevision(ctx context.Context, userID int, role user.Role, postID, number int) (*post.Post, error) {
+	s.logger.Info(ctx, "restoring post revision", "userID", userID, "postID", postID, "revision", number)
+
+	existingPost, err := s.repo.GetByID(ctx, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post for restore", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	if !existingPost.IsAuthor(userID) {
+		s.logger.Warn(ctx, "unauthorized post restore attempt", "userID", userID, "postID", postID, "authorID", existingPost.AuthorID)
+		return nil, post.ErrUnauthorized
+	}
+
+	restored, err := s.repo.GetRevision(ctx, postID, number)
+	if err != nil {
+		if !errors.Is(err, post.ErrRevisionNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post revision", "postID", postID, "revision", number, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	revision := post.NewRevision(existingPost, userID)
+	if err := existingPost.Update(restored.Title, restored.Content); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateWithRevision(ctx, existingPost, revision); err != nil {
+		s.logger.Error(ctx, "failed to save restored post", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetPost(ctx, postID)
+
+	s.logger.Info(ctx, "post revision restored", "userID", userID, "postID", postID, "revision", number)
+	s.notifySearchEngines(ctx, existingPost)
 	return existingPost, nil
 }
 
+// TranslatePost saves the translation of a post to a language, with the
+// same authorization checks as updates. Translating to a language again
+// replaces the earlier translation.
+func (s *PostService) TranslatePost(ctx context.Context, userID int, role user.Role, postID int, language, title, content string) (*post.Post, bool, error) {
+	s.logger.Info(ctx, "translating post", "userID", userID, "postID", postID, "language", language)
+
+	existingPost, err := s.modifiablePost(ctx, userID, role, postID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	translation, err := post.NewTranslation(existingPost, language, title, content, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	_, replaced := slices.BinarySearch(existingPost.Translations, translation.Language)
+	if err := s.repo.SaveTranslation(ctx, translation); err != nil {
+		s.logger.Error(ctx, "failed to save post translation", "postID", postID, "language", translation.Language, "error", err.Error())
+		return nil, false, err
+	}
+	s.forgetPost(ctx, postID)
+
+	return existingPost.Translated(translation), !replaced, nil
+}
+
+// LocalizePost returns the post in the language that best matches an
+// Accept-Language header. Translations are not cached: each is read by few
+// of the readers of a post.
+func (s *PostService) LocalizePost(ctx context.Context, p *post.Post, acceptLanguage string) (*post.Post, error) {
+	language := p.NegotiateLanguage(acceptLanguage)
+	if language == p.Language {
+		return p, nil
+	}
+
+	translation, err := s.repo.GetTranslation(ctx, p.ID, language)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post translation", "postID", p.ID, "language", language, "error", err.Error())
+		return nil, err
+	}
+	return p.Translated(translation), nil
+}
+
 // DeletePost deletes a post with authorization checks
-func (s *PostService) DeletePost(ctx context.Context, userID, postID int) error {
+func (s *PostService) DeletePost(ctx context.Context, userID int, role user.Role, postID int) error {
 	s.logger.Info(ctx, "deleting post", "userID", userID, "postID", postID)
 	
 	// Get the existing post
@@ -128,19 +1124,112 @@ func (s *PostService) DeletePost(ctx context.Context, userID, postID int) error
 		return err
 	}
 
-	// Check authorization - only the author can delete the post
-	if !existingPost.IsAuthor(userID) {
+	// Check authorization - only the author or an admin can delete the post
+	if !existingPost.CanModify(userID, role) {
 		s.logger.Warn(ctx, "unauthorized post deletion attempt", "userID", userID, "postID", postID, "authorID", existingPost.AuthorID)
 		return post.ErrUnauthorized
 	}
 
-	// Delete the post
-	err = s.repo.Delete(ctx, postID)
+	// Delete the post with its comments, so no comment outlives its post
+	var deletedComments int
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		deletedComments, err = s.comments.DeleteByPostID(ctx, postID)
+		if err != nil {
+			s.logger.Error(ctx, "failed to delete comments of post", "postID", postID, "error", err.Error())
+			return err
+		}
+		if err := s.repo.Delete(ctx, postID); err != nil {
+			s.logger.Error(ctx, "failed to delete post", "postID", postID, "error", err.Error())
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.Error(ctx, "failed to delete post", "postID", postID, "error", err.Error())
 		return err
 	}
+	s.forgetPost(ctx, postID)
 	
-	s.logger.Info(ctx, "post deleted successfully", "userID", userID, "postID", postID)
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionPostDeleted,
+		UserID:   userID,
+		Metadata: map[string]any{"post_id": postID, "author_id": existingPost.AuthorID, "title": existingPost.Title, "comments": deletedComments},
+	})
+	s.logger.Info(ctx, "post deleted successfully", "userID", userID, "postID", postID, "comments", deletedComments)
 	return nil
 }
+
+// forgetPost invalidates the cached copy of a post and the cached post lists
+// after a change
+func (s *PostService) forgetPost(ctx context.Context, id int) {
+	s.cache.forget(ctx, postCacheKey(id), postListGenerationKey)
+}
+
+// postCacheKey is the cache key of a post
+func postCacheKey(id int) string {
+	return "post:" + strconv.Itoa(id)
+}
+
+// modifiablePost loads a post the user may modify
+func (s *PostService) modifiablePost(ctx context.Context, userID int, role user.Role, postID int) (*post.Post, error) {
+	existingPost, err := s.repo.GetByID(ctx, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve post", "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	if !existingPost.CanModify(userID, role) {
+		s.logger.Warn(ctx, "unauthorized post modification attempt", "userID", userID, "postID", postID, "authorID", existingPost.AuthorID)
+		return nil, post.ErrUnauthorized
+	}
+	return existingPost, nil
+}
+
+// announcePublished publishes PostPublished for a post that just became
+// public; unlisted and private posts are not announced
+func (s *PostService) announcePublished(ctx context.Context, p *post.Post) {
+	if !p.IsListed() {
+		return
+	}
+	s.publisher.Publish(ctx, PostPublished{Post: p})
+}
+
+// notifySearchEngines queues a search ping for a published, public and
+// indexable post. Queueing is best-effort; the post is already saved.
+func (s *PostService) notifySearchEngines(ctx context.Context, p *post.Post) {
+	if !s.settings.NotifySearchEngines || p.NoIndex || !p.IsListed() {
+		return
+	}
+
+	err := s.jobs.Enqueue(ctx, JobSearchPing, SearchPingPayload{PostID: p.ID, Slug: p.Slug})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to queue search ping", "postID", p.ID, "error", err.Error())
+	}
+}
+
+// uniqueSlug returns base, or base with the first free numeric suffix
+// ("my-post-2", "my-post-3", ...) when base is already in use
+func (s *PostService) uniqueSlug(ctx context.Context, base string) (string, error) {
+	slug := base
+	for n := 2; n <= maxSlugAttempts+1; n++ {
+		_, err := s.repo.GetBySlug(ctx, slug)
+		if errors.Is(err, post.ErrPostNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		slug = post.NumberedSlug(base, n)
+	}
+	return "", post.ErrSlugTaken
+}
+
+// sortedTags orders tag names the way the repository loads them
+func sortedTags(tags []string) []string {
+	sorted := slices.Clone(tags)
+	slices.Sort(sorted)
+	if len(sorted) == 0 {
+		return nil
+	}
+	return sorted
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+)
+
+// PreferenceService implements the preference.Service interface
+type PreferenceService struct {
+	repo   preference.Repository
+	logger Logger
+}
+
+// NewPreferenceService creates a new preference service
+func NewPreferenceService(repo preference.Repository, logger Logger) *PreferenceService {
+	return &PreferenceService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// AuthorFeed retrieves the feed preference of an author
+func (s *PreferenceService) AuthorFeed(ctx context.Context, authorID int) (*preference.Feed, error) {
+	return s.feed(ctx, preference.ScopeAuthor, preference.AuthorKey(authorID))
+}
+
+// TagFeed retrieves the feed preference of a tag
+func (s *PreferenceService) TagFeed(ctx context.Context, name string) (*preference.Feed, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.feed(ctx, preference.ScopeTag, normalized)
+}
+
+// SetAuthorFeed stores the title and description of the user's post feed
+func (s *PreferenceService) SetAuthorFeed(ctx context.Context, userID int, title, description string) (*preference.Feed, error) {
+	return s.saveFeed(ctx, preference.ScopeAuthor, preference.AuthorKey(userID), title, description, userID)
+}
+
+// SetTagFeed stores the title and description of a tag's post feed
+func (s *PreferenceService) SetTagFeed(ctx context.Context, adminID int, name, title, description string) (*preference.Feed, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveFeed(ctx, preference.ScopeTag, normalized, title, description, adminID)
+}
+
+// feed retrieves a feed preference, returning an empty one when none is stored
+func (s *PreferenceService) feed(ctx context.Context, scope, key string) (*preference.Feed, error) {
+	f, err := s.repo.GetFeed(ctx, scope, key)
+	if errors.Is(err, preference.ErrFeedNotFound) {
+		return &preference.Feed{Scope: scope, Key: key}, nil
+	}
+	if err != nil {
+		s.logger.Error(ctx, "failed to get feed preference", "scope", scope, "key", key, "error", err.Error())
+		return nil, err
+	}
+	return f, nil
+}
+
+// saveFeed validates and stores a feed preference
+func (s *PreferenceService) saveFeed(ctx context.Context, scope, key, title, description string, userID int) (*preference.Feed, error) {
+	f, err := preference.NewFeed(scope, key, title, description, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveFeed(ctx, f); err != nil {
+		s.logger.Error(ctx, "failed to save feed preference", "scope", scope, "key", key, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "feed preference updated", "scope", scope, "key", key, "userID", userID)
+	return f, nil
+}
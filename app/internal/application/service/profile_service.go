@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"blog-platform/internal/domain/user"
+)
+
+// ProfileService implements the user.ProfileService interface
+type ProfileService struct {
+	repo   user.ProfileRepository
+	logger Logger
+}
+
+// NewProfileService creates a new profile service
+func NewProfileService(repo user.ProfileRepository, logger Logger) *ProfileService {
+	return &ProfileService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetProfile retrieves the public profile of an active account
+func (s *ProfileService) GetProfile(ctx context.Context, id int) (*user.Profile, error) {
+	profiles, err := s.GetProfiles(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := profiles[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return p, nil
+}
+
+// GetProfiles retrieves the public profiles of the active accounts with the
+// IDs, once per account
+func (s *ProfileService) GetProfiles(ctx context.Context, ids []int) (map[int]*user.Profile, error) {
+	seen := make(map[int]bool, len(ids))
+	var unique []int
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	profiles, err := s.repo.GetProfiles(ctx, unique)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve profiles", "userIDs", unique, "error", err.Error())
+		return nil, err
+	}
+
+	byID := make(map[int]*user.Profile, len(profiles))
+	for _, p := range profiles {
+		byID[p.ID] = p
+	}
+	return byID, nil
+}
+
+// UpdateProfile sets the bio and avatar URL of an active account
+func (s *ProfileService) UpdateProfile(ctx context.Context, id int, bio, avatarURL string) (*user.Profile, error) {
+	bio, avatarURL, err := user.NormalizeProfile(bio, avatarURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateProfile(ctx, id, bio, avatarURL); err != nil {
+		if err != user.ErrUserNotFound {
+			s.logger.Error(ctx, "failed to update profile", "userID", id, "error", err.Error())
+		}
+		return nil, err
+	}
+	s.logger.Info(ctx, "profile updated", "userID", id)
+	return s.GetProfile(ctx, id)
+}
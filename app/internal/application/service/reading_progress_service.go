@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+// ReadingProgressService implements the post.ProgressService interface
+type ReadingProgressService struct {
+	progress post.ProgressRepository
+	posts    post.ReadRepository
+	logger   Logger
+}
+
+// NewReadingProgressService creates a new reading progress service
+func NewReadingProgressService(progress post.ProgressRepository, posts post.ReadRepository, logger Logger) *ReadingProgressService {
+	return &ReadingProgressService{
+		progress: progress,
+		posts:    posts,
+		logger:   logger,
+	}
+}
+
+// SaveProgress records a position on a published post. Updates recorded
+// before the stored position, e.g. from a device syncing late, leave it
+// unchanged; the stored position is returned either way so the device can
+// resume from it.
+func (s *ReadingProgressService) SaveProgress(ctx context.Context, userID, postID int, percent float64, anchor string, recordedAt time.Time) (*post.Progress, error) {
+	p, err := post.NewProgress(userID, postID, percent, anchor, recordedAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.posts.GetByID(ctx, postID)
+	if err != nil {
+		if !errors.Is(err, post.ErrPostNotFound) {
+			s.logger.Error(ctx, "failed to retrieve post for reading progress", "postID", postID, "error", err.Error())
+		}
+		return nil, err
+	}
+	if !existing.IsVisibleTo(0, "") {
+		return nil, post.ErrPostNotFound
+	}
+
+	if err := s.progress.Save(ctx, p); err != nil {
+		s.logger.Error(ctx, "failed to save reading progress", "userID", userID, "postID", postID, "error", err.Error())
+		return nil, err
+	}
+
+	stored, err := s.progress.Get(ctx, userID, postID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve reading progress", "userID", userID, "postID", postID, "error", err.Error())
+		return nil, err
+	}
+	if stored.RecordedAt.After(p.RecordedAt) {
+		s.logger.Debug(ctx, "stale reading progress ignored", "userID", userID, "postID", postID)
+	}
+	return stored, nil
+}
+
+// GetProgress retrieves the position of a user on a post
+func (s *ReadingProgressService) GetProgress(ctx context.Context, userID, postID int) (*post.Progress, error) {
+	p, err := s.progress.Get(ctx, userID, postID)
+	if err != nil && !errors.Is(err, post.ErrProgressNotFound) {
+		s.logger.Error(ctx, "failed to retrieve reading progress", "userID", userID, "postID", postID, "error", err.Error())
+	}
+	return p, err
+}
+
+// ListReadingHistory retrieves the posts a user read, most recently read
+// first, with their position
+func (s *ReadingProgressService) ListReadingHistory(ctx context.Context, userID int, limit, offset int) ([]*post.ReadingEntry, error) {
+	entries, err := s.progress.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list reading history", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"blog-platform/internal/domain/notification"
+)
+
+// Types of the messages pushed to connected clients
+const (
+	// RealtimeNotification carries a notification just saved for the user,
+	// a new comment on their post or a reply to their comment
+	RealtimeNotification = "notification"
+	// RealtimeFollower tells the user they have a new follower; followers
+	// are not kept in the notification list
+	RealtimeFollower = "follower"
+)
+
+// RealtimeMessage is pushed to the connected clients of a user as JSON
+type RealtimeMessage struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// RealtimeNotificationData is the data of notification messages; it matches
+// the notifications listed by the API
+type RealtimeNotificationData struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	PostID    int       `json:"post_id"`
+	CommentID int       `json:"comment_id"`
+	ActorName string    `json:"actor_name"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RealtimeFollowerData is the data of follower messages
+type RealtimeFollowerData struct {
+	FollowerID int       `json:"follower_id"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// Pusher defines the interface for pushing messages to the connected
+// clients of a user. Pushing is best-effort: users without a connection
+// miss the message, and clients too slow to keep up are disconnected
+// rather than waited for.
+type Pusher interface {
+	Push(userID int, msg RealtimeMessage)
+}
+
+// SubscribeRealtime pushes the notifications and new followers of users to
+// their connected clients
+func SubscribeRealtime(bus *EventBus, pusher Pusher) {
+	Subscribe(bus, func(ctx context.Context, e NotificationCreated) {
+		pusher.Push(e.Notification.UserID, RealtimeMessage{Type: RealtimeNotification, Data: newRealtimeNotificationData(e.Notification)})
+	})
+	Subscribe(bus, func(ctx context.Context, e UserFollowed) {
+		pusher.Push(e.FolloweeID, RealtimeMessage{Type: RealtimeFollower, Data: RealtimeFollowerData{FollowerID: e.FollowerID, FollowedAt: time.Now().UTC()}})
+	})
+}
+
+// newRealtimeNotificationData converts a notification to the data of
+// notification messages
+func newRealtimeNotificationData(n *notification.Notification) RealtimeNotificationData {
+	return RealtimeNotificationData{
+		ID:        n.ID,
+		Kind:      string(n.Kind),
+		PostID:    n.PostID,
+		CommentID: n.CommentID,
+		ActorName: n.ActorName,
+		Read:      n.IsRead(),
+		CreatedAt: n.CreatedAt,
+	}
+}
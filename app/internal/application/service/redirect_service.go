@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// RedirectService implements the post.RedirectService interface
+type RedirectService struct {
+	repo   post.RedirectRepository
+	posts  post.ReadRepository
+	audit  AuditLogger
+	logger Logger
+}
+
+// NewRedirectService creates a new redirect service
+func NewRedirectService(repo post.RedirectRepository, posts post.ReadRepository, audit AuditLogger, logger Logger) *RedirectService {
+	return &RedirectService{
+		repo:   repo,
+		posts:  posts,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// Resolve retrieves the post with the slug, falling back to the post an old
+// slug redirects to. Posts hidden from the user are not found, so old slugs
+// of unpublished posts do not reveal their current slug.
+func (s *RedirectService) Resolve(ctx context.Context, userID int, role user.Role, slug string) (*post.Post, error) {
+	filter := post.ViewFilter{AuthorID: userID, All: role.IsAdmin()}
+	p, err := s.posts.GetVisibleBySlug(ctx, slug, filter)
+	if err != post.ErrPostNotFound {
+		return p, err
+	}
+
+	rd, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		if err == post.ErrRedirectNotFound {
+			return nil, post.ErrPostNotFound
+		}
+		s.logger.Error(ctx, "failed to retrieve redirect", "slug", slug, "error", err.Error())
+		return nil, err
+	}
+	return s.posts.GetVisibleByID(ctx, rd.PostID, filter)
+}
+
+// ListRedirects retrieves redirects with pagination, most recent first
+func (s *RedirectService) ListRedirects(ctx context.Context, limit, offset int) ([]*post.Redirect, error) {
+	redirects, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list redirects", "error", err.Error())
+		return nil, err
+	}
+	return redirects, nil
+}
+
+// CreateRedirect adds a manual redirect from a slug no post uses to a post
+func (s *RedirectService) CreateRedirect(ctx context.Context, adminID int, fromSlug string, postID int) (*post.Redirect, error) {
+	if err := s.checkRedirect(ctx, fromSlug, postID); err != nil {
+		return nil, err
+	}
+
+	rd := &post.Redirect{FromSlug: fromSlug, PostID: postID, CreatedAt: time.Now()}
+	if err := s.repo.Create(ctx, rd); err != nil {
+		if err != post.ErrRedirectExists {
+			s.logger.Error(ctx, "failed to create redirect", "fromSlug", fromSlug, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionRedirectCreated,
+		UserID:   adminID,
+		Metadata: map[string]any{"redirect_id": rd.ID, "from_slug": fromSlug, "post_id": postID},
+	})
+	s.logger.Info(ctx, "redirect created", "redirectID", rd.ID, "adminID", adminID)
+	return rd, nil
+}
+
+// UpdateRedirect changes the slug or post of a redirect; the redirect is
+// manual from then on
+func (s *RedirectService) UpdateRedirect(ctx context.Context, adminID, id int, fromSlug string, postID int) (*post.Redirect, error) {
+	rd, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRedirect(ctx, fromSlug, postID); err != nil {
+		return nil, err
+	}
+
+	previous := *rd
+	rd.FromSlug, rd.PostID, rd.Automatic = fromSlug, postID, false
+	if err := s.repo.Update(ctx, rd); err != nil {
+		if err != post.ErrRedirectExists {
+			s.logger.Error(ctx, "failed to update redirect", "redirectID", id, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action: AuditActionRedirectUpdated,
+		UserID: adminID,
+		Metadata: map[string]any{
+			"redirect_id":        id,
+			"from_slug":          fromSlug,
+			"post_id":            postID,
+			"previous_from_slug": previous.FromSlug,
+			"previous_post_id":   previous.PostID,
+		},
+	})
+	return rd, nil
+}
+
+// DeleteRedirect removes a redirect; its slug stops resolving
+func (s *RedirectService) DeleteRedirect(ctx context.Context, adminID, id int) error {
+	rd, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err != post.ErrRedirectNotFound {
+			s.logger.Error(ctx, "failed to delete redirect", "redirectID", id, "error", err.Error())
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionRedirectDeleted,
+		UserID:   adminID,
+		Metadata: map[string]any{"redirect_id": id, "from_slug": rd.FromSlug, "post_id": rd.PostID},
+	})
+	return nil
+}
+
+// checkRedirect checks that a redirect would send a valid slug, not used
+// by any post, to an existing post
+func (s *RedirectService) checkRedirect(ctx context.Context, fromSlug string, postID int) error {
+	if !post.IsValidSlug(fromSlug) {
+		return post.ErrInvalidSlug
+	}
+
+	// Slugs of posts always resolve to the post, so a redirect would never
+	// be followed
+	if _, err := s.posts.GetBySlug(ctx, fromSlug); err == nil {
+		return post.ErrSlugTaken
+	} else if err != post.ErrPostNotFound {
+		s.logger.Error(ctx, "failed to check post slug", "slug", fromSlug, "error", err.Error())
+		return err
+	}
+
+	if _, err := s.posts.GetByID(ctx, postID); err != nil {
+		return err
+	}
+	return nil
+}
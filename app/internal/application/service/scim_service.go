@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/scim"
+	"blog-platform/internal/domain/user"
+)
+
+// scimLoginMethod names provisioned registrations in audit events
+const scimLoginMethod = "scim"
+
+// SCIMSettings holds the configurable behaviour of SCIM provisioning
+type SCIMSettings struct {
+	// Pagination bounds the page size of user and group lists
+	Pagination PageLimits
+}
+
+// SCIMService implements the scim.Service interface on top of the user
+// service, so provisioned accounts follow the same rules as other accounts
+type SCIMService struct {
+	userService   user.Service
+	accounts      scim.AccountRepository
+	groups        scim.GroupRepository
+	refreshTokens auth.RefreshTokenRepository
+	audit         AuditLogger
+	tx            TxManager
+	settings      SCIMSettings
+	logger        Logger
+}
+
+// NewSCIMService creates a new SCIM service
+func NewSCIMService(userService user.Service, accounts scim.AccountRepository, groups scim.GroupRepository, refreshTokens auth.RefreshTokenRepository, audit AuditLogger, tx TxManager, settings SCIMSettings, logger Logger) *SCIMService {
+	return &SCIMService{
+		userService:   userService,
+		accounts:      accounts,
+		groups:        groups,
+		refreshTokens: refreshTokens,
+		audit:         audit,
+		tx:            tx,
+		settings:      settings,
+		logger:        logger,
+	}
+}
+
+// CreateUser registers an account for a tenant. Addresses that already have
+// an account are refused rather than linked, so a tenant cannot take over
+// accounts it did not create.
+func (s *SCIMService) CreateUser(ctx context.Context, tenant string, attrs scim.UserAttributes) (*scim.User, error) {
+	if err := attrs.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUnclaimed(ctx, tenant, 0, attrs); err != nil {
+		return nil, err
+	}
+
+	// The user, its account link and its deactivation are stored together,
+	// so a failed link leaves no unlinked user behind
+	var u *user.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		u, err = s.userService.RegisterPasswordless(ctx, attrs.Name, attrs.Email, scimLoginMethod)
+		if err != nil {
+			if errors.Is(err, user.ErrUserExists) {
+				s.logger.Warn(ctx, "provisioning for existing user", "tenant", tenant, "email", attrs.Email)
+				return scim.ErrUserClaimed
+			}
+			return err
+		}
+
+		account := &scim.Account{Tenant: tenant, UserID: u.ID, UserName: attrs.UserName, ExternalID: attrs.ExternalID, CreatedAt: u.CreatedAt, UpdatedAt: u.CreatedAt}
+		if err := s.accounts.Create(ctx, account); err != nil {
+			s.logger.Error(ctx, "failed to link provisioned user", "tenant", tenant, "userID", u.ID, "error", err.Error())
+			return err
+		}
+
+		if !attrs.Active {
+			return s.deactivate(ctx, u.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserProvisioned, UserID: u.ID, Metadata: map[string]any{"tenant": tenant}})
+	s.logger.Info(ctx, "user provisioned", "tenant", tenant, "userID", u.ID)
+	return s.GetUser(ctx, tenant, u.ID)
+}
+
+// GetUser returns a user of a tenant
+func (s *SCIMService) GetUser(ctx context.Context, tenant string, id int) (*scim.User, error) {
+	account, err := s.accounts.Get(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toUser(ctx, account)
+}
+
+// ListUsers returns a page of the users of a tenant. Filters match at most
+// one user, as user names, external IDs and emails are unique.
+func (s *SCIMService) ListUsers(ctx context.Context, tenant string, filter *scim.Filter, page scim.Page) ([]*scim.User, int, error) {
+	if filter != nil {
+		account, err := s.findAccount(ctx, tenant, filter)
+		if errors.Is(err, scim.ErrUserNotFound) {
+			return []*scim.User{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		u, err := s.toUser(ctx, account)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*scim.User{u}, 1, nil
+	}
+
+	limit, offset := s.settings.Pagination.Normalize(page.Count, page.Offset())
+	accounts, total, err := s.accounts.List(ctx, tenant, offset, limit)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list provisioned users", "tenant", tenant, "error", err.Error())
+		return nil, 0, err
+	}
+
+	users := make([]*scim.User, 0, len(accounts))
+	for _, account := range accounts {
+		u, err := s.toUser(ctx, account)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, u)
+	}
+	return users, total, nil
+}
+
+// ReplaceUser syncs the attributes of a user: the profile is updated
+// without confirming a new address, and the account is deactivated or
+// reactivated to match the active attribute
+func (s *SCIMService) ReplaceUser(ctx context.Context, tenant string, id int, attrs scim.UserAttributes) (*scim.User, error) {
+	if err := attrs.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accounts.Get(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnclaimed(ctx, tenant, id, attrs); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Name != attrs.Name || u.Email != attrs.Email {
+		if _, err := s.userService.SyncProfile(ctx, id, attrs.Name, attrs.Email); err != nil {
+			if errors.Is(err, user.ErrUserExists) {
+				return nil, scim.ErrUserClaimed
+			}
+			return nil, err
+		}
+	}
+
+	switch {
+	case !attrs.Active && !u.IsDeactivated():
+		err = s.deactivate(ctx, id)
+	case attrs.Active && u.IsDeactivated():
+		err = s.userService.Reactivate(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserName != attrs.UserName || account.ExternalID != attrs.ExternalID {
+		account.UserName = attrs.UserName
+		account.ExternalID = attrs.ExternalID
+		if err := s.accounts.Update(ctx, account); err != nil {
+			s.logger.Error(ctx, "failed to update provisioned user", "tenant", tenant, "userID", id, "error", err.Error())
+			return nil, err
+		}
+	}
+
+	s.logger.Info(ctx, "provisioned user synced", "tenant", tenant, "userID", id, "active", attrs.Active)
+	return s.GetUser(ctx, tenant, id)
+}
+
+// PatchUser applies PATCH operations to the attributes of a user and syncs
+// the result
+func (s *SCIMService) PatchUser(ctx context.Context, tenant string, id int, ops []scim.PatchOperation) (*scim.User, error) {
+	current, err := s.GetUser(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := scim.UserAttributes{
+		ExternalID: current.ExternalID,
+		UserName:   current.UserName,
+		Name:       current.Name,
+		Email:      current.Email,
+		Active:     current.Active,
+	}
+	if err := attrs.ApplyPatch(ops); err != nil {
+		return nil, err
+	}
+	return s.ReplaceUser(ctx, tenant, id, attrs)
+}
+
+// DeleteUser deactivates a user and removes it from the tenant. The account
+// is kept, as its posts and comments belong to the blog.
+func (s *SCIMService) DeleteUser(ctx context.Context, tenant string, id int) error {
+	if _, err := s.accounts.Get(ctx, tenant, id); err != nil {
+		return err
+	}
+	if err := s.deactivate(ctx, id); err != nil {
+		return err
+	}
+	if err := s.accounts.Delete(ctx, tenant, id); err != nil {
+		s.logger.Error(ctx, "failed to unlink provisioned user", "tenant", tenant, "userID", id, "error", err.Error())
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserDeprovisioned, UserID: id, Metadata: map[string]any{"tenant": tenant}})
+	s.logger.Info(ctx, "user deprovisioned", "tenant", tenant, "userID", id)
+	return nil
+}
+
+// CreateGroup creates a group of a tenant
+func (s *SCIMService) CreateGroup(ctx context.Context, tenant, displayName, externalID string, members []int) (*scim.Group, error) {
+	g, err := scim.NewGroup(tenant, displayName, externalID, members)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMembers(ctx, tenant, g.Members); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.Create(ctx, g); err != nil {
+		if !errors.Is(err, scim.ErrGroupExists) {
+			s.logger.Error(ctx, "failed to create group", "tenant", tenant, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "group provisioned", "tenant", tenant, "groupID", g.ID)
+	return g, nil
+}
+
+// GetGroup returns a group of a tenant
+func (s *SCIMService) GetGroup(ctx context.Context, tenant string, id int) (*scim.Group, error) {
+	return s.groups.GetByID(ctx, tenant, id)
+}
+
+// ListGroups returns a page of the groups of a tenant. Only displayName
+// filters are supported.
+func (s *SCIMService) ListGroups(ctx context.Context, tenant string, filter *scim.Filter, page scim.Page) ([]*scim.Group, int, error) {
+	if filter != nil {
+		if filter.Attribute != scim.FilterDisplayName {
+			return nil, 0, scim.ErrInvalidFilter
+		}
+		g, err := s.groups.GetByDisplayName(ctx, tenant, filter.Value)
+		if errors.Is(err, scim.ErrGroupNotFound) {
+			return []*scim.Group{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*scim.Group{g}, 1, nil
+	}
+
+	limit, offset := s.settings.Pagination.Normalize(page.Count, page.Offset())
+	groups, total, err := s.groups.List(ctx, tenant, offset, limit)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list groups", "tenant", tenant, "error", err.Error())
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+// ReplaceGroup replaces the attributes and members of a group
+func (s *SCIMService) ReplaceGroup(ctx context.Context, tenant string, id int, displayName, externalID string, members []int) (*scim.Group, error) {
+	g, err := s.groups.GetByID(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Update(displayName, externalID, members); err != nil {
+		return nil, err
+	}
+	return s.saveGroup(ctx, g)
+}
+
+// PatchGroup applies PATCH operations to a group, typically adding or
+// removing members
+func (s *SCIMService) PatchGroup(ctx context.Context, tenant string, id int, ops []scim.PatchOperation) (*scim.Group, error) {
+	g, err := s.groups.GetByID(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.ApplyPatch(ops); err != nil {
+		return nil, err
+	}
+	return s.saveGroup(ctx, g)
+}
+
+// DeleteGroup removes a group; its members keep their accounts
+func (s *SCIMService) DeleteGroup(ctx context.Context, tenant string, id int) error {
+	if err := s.groups.Delete(ctx, tenant, id); err != nil {
+		if !errors.Is(err, scim.ErrGroupNotFound) {
+			s.logger.Error(ctx, "failed to delete group", "tenant", tenant, "groupID", id, "error", err.Error())
+		}
+		return err
+	}
+
+	s.logger.Info(ctx, "group removed", "tenant", tenant, "groupID", id)
+	return nil
+}
+
+// saveGroup stores a changed group after checking its members
+func (s *SCIMService) saveGroup(ctx context.Context, g *scim.Group) (*scim.Group, error) {
+	if err := s.checkMembers(ctx, g.Tenant, g.Members); err != nil {
+		return nil, err
+	}
+	if err := s.groups.Update(ctx, g); err != nil {
+		if !errors.Is(err, scim.ErrGroupExists) {
+			s.logger.Error(ctx, "failed to update group", "tenant", g.Tenant, "groupID", g.ID, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "group synced", "tenant", g.Tenant, "groupID", g.ID, "members", len(g.Members))
+	return g, nil
+}
+
+// checkMembers ensures groups only hold users of their tenant
+func (s *SCIMService) checkMembers(ctx context.Context, tenant string, members []int) error {
+	for _, id := range members {
+		if _, err := s.accounts.Get(ctx, tenant, id); err != nil {
+			if errors.Is(err, scim.ErrUserNotFound) {
+				s.logger.Warn(ctx, "group member of another tenant", "tenant", tenant, "userID", id)
+				return scim.ErrInvalidValue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUnclaimed ensures no other user of the tenant has the user name or
+// external ID
+func (s *SCIMService) checkUnclaimed(ctx context.Context, tenant string, id int, attrs scim.UserAttributes) error {
+	lookups := []struct {
+		value string
+		get   func(context.Context, string, string) (*scim.Account, error)
+	}{
+		{attrs.UserName, s.accounts.GetByUserName},
+		{attrs.ExternalID, s.accounts.GetByExternalID},
+	}
+	for _, lookup := range lookups {
+		if lookup.value == "" {
+			continue
+		}
+		account, err := lookup.get(ctx, tenant, lookup.value)
+		if err == nil && account.UserID != id {
+			return scim.ErrUserClaimed
+		}
+		if err != nil && !errors.Is(err, scim.ErrUserNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// findAccount returns the account of a tenant matching a filter
+func (s *SCIMService) findAccount(ctx context.Context, tenant string, filter *scim.Filter) (*scim.Account, error) {
+	switch filter.Attribute {
+	case scim.FilterUserName:
+		return s.accounts.GetByUserName(ctx, tenant, filter.Value)
+	case scim.FilterExternalID:
+		return s.accounts.GetByExternalID(ctx, tenant, filter.Value)
+	case scim.FilterEmail:
+		u, err := s.userService.GetByEmail(ctx, filter.Value)
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, scim.ErrUserNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s.accounts.Get(ctx, tenant, u.ID)
+	}
+	return nil, scim.ErrInvalidFilter
+}
+
+// deactivate turns an account off and ends its sessions. Access tokens
+// already issued stay valid until they expire.
+func (s *SCIMService) deactivate(ctx context.Context, id int) error {
+	if err := s.userService.Deactivate(ctx, id); err != nil {
+		return err
+	}
+	if err := s.refreshTokens.RevokeAllForUser(ctx, id); err != nil {
+		s.logger.Error(ctx, "failed to revoke refresh tokens of deactivated account", "userID", id, "error", err.Error())
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// toUser combines an account with the user and the groups it belongs to
+func (s *SCIMService) toUser(ctx context.Context, account *scim.Account) (*scim.User, error) {
+	u, err := s.userService.GetByID(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groups.ListByMember(ctx, account.Tenant, account.UserID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list groups of user", "tenant", account.Tenant, "userID", account.UserID, "error", err.Error())
+		return nil, err
+	}
+
+	modified := u.UpdatedAt
+	if account.UpdatedAt.After(modified) {
+		modified = account.UpdatedAt
+	}
+	return &scim.User{
+		ID:         u.ID,
+		ExternalID: account.ExternalID,
+		UserName:   account.UserName,
+		Name:       u.Name,
+		Email:      u.Email,
+		Active:     !u.IsDeactivated(),
+		Groups:     groups,
+		Created:    account.CreatedAt,
+		Modified:   modified,
+	}, nil
+}
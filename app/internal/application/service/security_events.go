@@ -0,0 +1,31 @@
+package service
+
+import "context"
+
+// Security event kinds
+const (
+	SecurityEventPasswordChanged   = "password_changed"
+	SecurityEventEmailChanged      = "email_changed"
+	SecurityEventTwoFactorDisabled = "two_factor_disabled"
+	SecurityEventPasskeyAdded      = "passkey_added"
+	SecurityEventPasskeyRemoved    = "passkey_removed"
+)
+
+// SecurityEvent describes a sensitive change to an account that its owner
+// is notified of
+type SecurityEvent struct {
+	Kind   string `json:"kind"`
+	UserID int    `json:"user_id"`
+	// Email is the address to notify; empty means the current address of
+	// the account
+	Email string `json:"email,omitempty"`
+	// Detail describes the change, such as the new email address
+	Detail string `json:"detail,omitempty"`
+}
+
+// SecurityEvents defines the interface for publishing security events.
+// Publishing is best-effort: implementations report their own failures and
+// never fail the change being published.
+type SecurityEvents interface {
+	Publish(ctx context.Context, event SecurityEvent)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+)
+
+// Default link lifetimes used when SecuritySettings leaves them unset
+const (
+	defaultReportLinkTTL    = 7 * 24 * time.Hour
+	defaultPasswordResetTTL = time.Hour
+)
+
+// SecuritySettings holds the configurable behaviour of security notifications
+type SecuritySettings struct {
+	// BaseURL is the public URL used to build the links in notifications
+	BaseURL string
+	// ReportLinkTTL is how long a "this wasn't me" link can lock the account
+	ReportLinkTTL time.Duration
+	// PasswordResetTTL is how long the reset link sent after a report stays valid
+	PasswordResetTTL time.Duration
+}
+
+// securityNotices holds the subject and description of each security event
+var securityNotices = map[string]struct{ subject, format string }{
+	SecurityEventPasswordChanged:   {"Your password was changed", "The password of your account was changed."},
+	SecurityEventEmailChanged:      {"Your email address was changed", "The email on your account was changed to %s."},
+	SecurityEventTwoFactorDisabled: {"Two-factor authentication was turned off", "Two-factor authentication was turned off for your account."},
+	SecurityEventPasskeyAdded:      {"A passkey was added to your account", "The passkey %q can now be used to sign in to your account."},
+	SecurityEventPasskeyRemoved:    {"A passkey was removed from your account", "The passkey %q was removed from your account."},
+}
+
+// SecurityService implements the user.SecurityService and SecurityEvents
+// interfaces. Published events are emailed to the account owner by queued
+// jobs; each email carries a "this wasn't me" link that locks the account
+// until its password is reset.
+type SecurityService struct {
+	repo          user.Repository
+	resets        user.PasswordResetRepository
+	tokens        user.ReportTokens
+	refreshTokens auth.RefreshTokenRepository
+	mailer        Mailer
+	jobs          JobQueue
+	audit         AuditLogger
+	publisher     EventPublisher
+	settings      SecuritySettings
+	logger        Logger
+}
+
+// NewSecurityService creates a new SecurityService instance
+func NewSecurityService(repo user.Repository, resets user.PasswordResetRepository, tokens user.ReportTokens, refreshTokens auth.RefreshTokenRepository, mailer Mailer, jobs JobQueue, audit AuditLogger, publisher EventPublisher, settings SecuritySettings, logger Logger) *SecurityService {
+	if settings.ReportLinkTTL <= 0 {
+		settings.ReportLinkTTL = defaultReportLinkTTL
+	}
+	if settings.PasswordResetTTL <= 0 {
+		settings.PasswordResetTTL = defaultPasswordResetTTL
+	}
+
+	return &SecurityService{
+		repo:          repo,
+		resets:        resets,
+		tokens:        tokens,
+		refreshTokens: refreshTokens,
+		mailer:        mailer,
+		jobs:          jobs,
+		audit:         audit,
+		publisher:     publisher,
+		settings:      settings,
+		logger:        logger,
+	}
+}
+
+// Publish queues the notification email of a security event
+func (s *SecurityService) Publish(ctx context.Context, event SecurityEvent) {
+	if err := s.jobs.Enqueue(ctx, JobSecurityNotification, event); err != nil {
+		s.logger.Error(ctx, "failed to queue security notification", "userID", event.UserID, "kind", event.Kind, "error", err.Error())
+	}
+}
+
+// SendNotification emails the owner of an account about a security event.
+// It handles JobSecurityNotification jobs; send failures fail the job so it
+// is retried.
+func (s *SecurityService) SendNotification(ctx context.Context, payload json.RawMessage) error {
+	var event SecurityEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("invalid security notification payload: %w", err)
+	}
+
+	notice, ok := securityNotices[event.Kind]
+	if !ok {
+		s.logger.Warn(ctx, "unknown security event", "userID", event.UserID, "kind", event.Kind)
+		return nil
+	}
+
+	u, err := s.repo.GetByID(ctx, event.UserID)
+	if err != nil {
+		if err == user.ErrUserNotFound {
+			s.logger.Warn(ctx, "security notification for deleted user", "userID", event.UserID, "kind", event.Kind)
+			return nil
+		}
+		return err
+	}
+
+	to := event.Email
+	if to == "" {
+		to = u.Email
+	}
+	description := notice.format
+	if event.Detail != "" {
+		description = fmt.Sprintf(notice.format, event.Detail)
+	}
+
+	expiresAt := time.Now().Add(s.settings.ReportLinkTTL)
+	reportURL := s.settings.BaseURL + "/api/v1/users/security/report?token=" + url.QueryEscape(s.tokens.Issue(u.ID, expiresAt))
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      to,
+		Subject: notice.subject,
+		Body: fmt.Sprintf("Hi %s,\n\n%s\n\nIf you made this change, no action is needed.\nIf you did not, open the link below to lock your account and reset your password:\n\n%s\n\nThe link expires at %s.\n",
+			u.Name, description, reportURL, expiresAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to send security notification", "userID", u.ID, "kind", event.Kind, "error", err.Error())
+		return fmt.Errorf("failed to send security notification: %w", err)
+	}
+
+	s.logger.Info(ctx, "security notification sent", "userID", u.ID, "kind", event.Kind)
+	return nil
+}
+
+// ReportUnauthorizedChange locks the account a "this wasn't me" link was
+// issued for, revokes its refresh tokens and emails a password reset link.
+// Access tokens already issued stay valid until they expire.
+func (s *SecurityService) ReportUnauthorizedChange(ctx context.Context, token string) error {
+	userID, err := s.tokens.Verify(token)
+	if err != nil {
+		return user.ErrInvalidReportToken
+	}
+
+	u, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for security report", "userID", userID, "error", err.Error())
+		return err
+	}
+
+	if !u.IsPasswordResetRequired() {
+		u.RequirePasswordReset()
+		if err := s.repo.Update(ctx, u); err != nil {
+			s.logger.Error(ctx, "failed to lock account", "userID", u.ID, "error", err.Error())
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		s.publisher.Publish(ctx, AccountChanged{User: u})
+	}
+
+	if err := s.refreshTokens.RevokeAllForUser(ctx, u.ID); err != nil {
+		s.logger.Error(ctx, "failed to revoke refresh tokens of locked account", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionChangeReported, UserID: u.ID})
+
+	reset, resetToken, err := user.NewPasswordReset(u.ID, s.settings.PasswordResetTTL)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create password reset entity", "userID", u.ID, "error", err.Error())
+		return err
+	}
+	if err := s.resets.Create(ctx, reset); err != nil {
+		s.logger.Error(ctx, "failed to save password reset", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	resetURL := s.settings.BaseURL + "/api/v1/users/password/reset?token=" + resetToken
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      u.Email,
+		Subject: "Your account was locked",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account was locked and signed out everywhere because a change was reported as not made by you.\nTo unlock it, choose a new password using the link below:\n\n%s\n\nThe link expires at %s.\n",
+			u.Name, resetURL, reset.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to send password reset link", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	s.logger.Warn(ctx, "account locked after unauthorized change report", "userID", u.ID)
+	return nil
+}
+
+// ResetPassword sets a new password using the emailed reset token and
+// unlocks the account
+func (s *SecurityService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	reset, err := s.resets.GetByTokenHash(ctx, user.HashToken(token))
+	if err != nil {
+		s.logger.Warn(ctx, "password reset with unknown token", "error", err.Error())
+		return err
+	}
+
+	if reset.IsExpired() {
+		s.logger.Warn(ctx, "password reset with expired token", "userID", reset.UserID)
+		if err := s.resets.Delete(ctx, reset.ID); err != nil {
+			s.logger.Warn(ctx, "failed to delete expired password reset", "userID", reset.UserID, "error", err.Error())
+		}
+		return user.ErrPasswordResetExpired
+	}
+
+	u, err := s.repo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for password reset", "userID", reset.UserID, "error", err.Error())
+		return err
+	}
+
+	if err := u.UpdatePassword(newPassword); err != nil {
+		s.logger.Error(ctx, "failed to update password entity", "userID", u.ID, "error", err.Error())
+		return err
+	}
+
+	if err := s.resets.Complete(ctx, reset, u.PasswordHash); err != nil {
+		s.logger.Error(ctx, "failed to complete password reset", "userID", u.ID, "error", err.Error())
+		return err
+	}
+
+	s.publisher.Publish(ctx, AccountChanged{User: u})
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPasswordReset, UserID: u.ID})
+	s.logger.Info(ctx, "password reset, account unlocked", "userID", u.ID)
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+)
+
+// SeriesService implements the series.Service interface
+type SeriesService struct {
+	repo      series.Repository
+	posts     post.ReadRepository
+	tx        TxManager
+	publisher EventPublisher
+	logger    Logger
+}
+
+// NewSeriesService creates a new SeriesService instance
+func NewSeriesService(repo series.Repository, posts post.ReadRepository, tx TxManager, publisher EventPublisher, logger Logger) *SeriesService {
+	return &SeriesService{
+		repo:      repo,
+		posts:     posts,
+		tx:        tx,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Create stores an empty series of the author
+func (s *SeriesService) Create(ctx context.Context, authorID int, in series.Input) (*series.Series, error) {
+	sr, err := series.NewSeries(authorID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, sr); err != nil {
+		s.logger.Error(ctx, "failed to save series", "authorID", authorID, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "series created", "seriesID", sr.ID, "authorID", authorID)
+	return sr, nil
+}
+
+// Update replaces the title and description of a series
+func (s *SeriesService) Update(ctx context.Context, userID int, role user.Role, id int, in series.Input) (*series.Series, error) {
+	sr, err := s.modifiableSeries(ctx, userID, role, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := sr.Update(in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, sr); err != nil {
+		s.logger.Error(ctx, "failed to update series", "seriesID", id, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "series updated", "seriesID", id, "userID", userID)
+	return sr, nil
+}
+
+// Delete removes a series; its posts are kept outside any series
+func (s *SeriesService) Delete(ctx context.Context, userID int, role user.Role, id int) error {
+	if _, err := s.modifiableSeries(ctx, userID, role, id); err != nil {
+		return err
+	}
+	posts, err := s.repo.ListPosts(ctx, id, post.ViewFilter{All: true})
+	if err != nil {
+		return err
+	}
+
+	if err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.repo.Delete(ctx, id)
+	}); err != nil {
+		s.logger.Error(ctx, "failed to delete series", "seriesID", id, "error", err.Error())
+		return err
+	}
+
+	s.postsChanged(ctx, id, idsOf(posts))
+	s.logger.Info(ctx, "series deleted", "seriesID", id, "userID", userID)
+	return nil
+}
+
+// Get retrieves a series with the posts the user may see. Drafts and
+// scheduled posts only show to those who can modify them, and readers
+// move through the series past them.
+func (s *SeriesService) Get(ctx context.Context, userID int, role user.Role, id int) (*series.Series, []series.Entry, error) {
+	sr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := s.entries(ctx, userID, role, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sr, entries, nil
+}
+
+// ListByAuthor retrieves the series of an author, newest first
+func (s *SeriesService) ListByAuthor(ctx context.Context, authorID int) ([]*series.Series, error) {
+	return s.repo.ListByAuthor(ctx, authorID)
+}
+
+// SetPosts orders the posts of a series as given. The posts must be by the
+// author of the series; posts of another series of the author move over.
+func (s *SeriesService) SetPosts(ctx context.Context, userID int, role user.Role, id int, postIDs []int) (*series.Series, []series.Entry, error) {
+	sr, err := s.modifiableSeries(ctx, userID, role, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := series.CheckOrder(postIDs); err != nil {
+		return nil, nil, err
+	}
+
+	for _, postID := range postIDs {
+		p, err := s.posts.GetByID(ctx, postID)
+		if errors.Is(err, post.ErrPostNotFound) || (err == nil && p.AuthorID != sr.AuthorID) {
+			return nil, nil, series.ErrForeignPost
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	previous, err := s.repo.ListPosts(ctx, id, post.ViewFilter{All: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.repo.SetPosts(ctx, id, postIDs)
+	}); err != nil {
+		s.logger.Error(ctx, "failed to set series posts", "seriesID", id, "error", err.Error())
+		return nil, nil, err
+	}
+	s.postsChanged(ctx, id, append(slices.Clone(postIDs), idsOf(previous)...))
+	s.logger.Info(ctx, "series posts set", "seriesID", id, "posts", len(postIDs), "userID", userID)
+
+	entries, err := s.entries(ctx, userID, role, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sr, entries, nil
+}
+
+// entries lists the posts of a series the user may see, linked to their
+// neighbours
+func (s *SeriesService) entries(ctx context.Context, userID int, role user.Role, id int) ([]series.Entry, error) {
+	filter := post.ViewFilter{AuthorID: userID, All: role.IsAdmin()}
+	posts, err := s.repo.ListPosts(ctx, id, filter)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list series posts", "seriesID", id, "error", err.Error())
+		return nil, err
+	}
+	return series.Navigate(posts), nil
+}
+
+// modifiableSeries loads a series the user may modify
+func (s *SeriesService) modifiableSeries(ctx context.Context, userID int, role user.Role, id int) (*series.Series, error) {
+	sr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sr.CanModify(userID, role) {
+		s.logger.Warn(ctx, "unauthorized series modification attempt", "seriesID", id, "userID", userID)
+		return nil, series.ErrUnauthorized
+	}
+	return sr, nil
+}
+
+// postsChanged tells other services which posts joined, left or moved
+// within a series
+func (s *SeriesService) postsChanged(ctx context.Context, id int, postIDs []int) {
+	if len(postIDs) > 0 {
+		s.publisher.Publish(ctx, SeriesPostsChanged{SeriesID: id, PostIDs: postIDs})
+	}
+}
+
+// idsOf returns the IDs of posts
+func idsOf(posts []*post.Post) []int {
+	ids := make([]int, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+var _ series.Service = (*SeriesService)(nil)
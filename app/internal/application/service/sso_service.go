@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/user"
+)
+
+// SSOService implements the sso.Service interface. Members sign in through
+// the user service like other external logins, with the identity provider
+// of their organization as the provider.
+type SSOService struct {
+	userService user.Service
+	connections sso.ConnectionRepository
+	memberships sso.MembershipRepository
+	providers   sso.ProviderFactory
+	audit       AuditLogger
+	logger      Logger
+}
+
+// NewSSOService creates a new SSO service
+func NewSSOService(userService user.Service, connections sso.ConnectionRepository, memberships sso.MembershipRepository, providers sso.ProviderFactory, audit AuditLogger, logger Logger) *SSOService {
+	return &SSOService{
+		userService: userService,
+		connections: connections,
+		memberships: memberships,
+		providers:   providers,
+		audit:       audit,
+		logger:      logger,
+	}
+}
+
+// SaveConnection creates or replaces the connection of an organization. The
+// provider is built before saving, so unusable SAML metadata is refused.
+func (s *SSOService) SaveConnection(ctx context.Context, c *sso.Connection) (*sso.Connection, error) {
+	existing, err := s.connections.GetByOrganization(ctx, c.Organization)
+	if err != nil && !errors.Is(err, sso.ErrConnectionNotFound) {
+		s.logger.Error(ctx, "failed to retrieve sso connection", "organization", c.Organization, "error", err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+	c.CreatedAt = now
+	if existing != nil {
+		c.CreatedAt = existing.CreatedAt
+		if c.ClientSecret == "" && c.Protocol == existing.Protocol {
+			c.ClientSecret = existing.ClientSecret
+		}
+	}
+	c.UpdatedAt = now
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.providers.Provider(c); err != nil {
+		return nil, err
+	}
+
+	if err := s.connections.Save(ctx, c); err != nil {
+		if !errors.Is(err, sso.ErrDomainClaimed) {
+			s.logger.Error(ctx, "failed to save sso connection", "organization", c.Organization, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionSSOConnectionSaved, Metadata: map[string]any{"organization": c.Organization, "protocol": string(c.Protocol), "enforcement": string(c.Enforcement)}})
+	s.logger.Info(ctx, "sso connection saved", "organization", c.Organization, "protocol", c.Protocol)
+	return c, nil
+}
+
+// GetConnection returns the connection of an organization
+func (s *SSOService) GetConnection(ctx context.Context, organization string) (*sso.Connection, error) {
+	return s.connections.GetByOrganization(ctx, organization)
+}
+
+// ListConnections returns the connections of all organizations
+func (s *SSOService) ListConnections(ctx context.Context) ([]*sso.Connection, error) {
+	connections, err := s.connections.List(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list sso connections", "error", err.Error())
+		return nil, err
+	}
+	return connections, nil
+}
+
+// DeleteConnection removes the connection of an organization. Linked
+// identities are kept, so members can still sign in with other methods.
+func (s *SSOService) DeleteConnection(ctx context.Context, organization string) error {
+	if err := s.connections.Delete(ctx, organization); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionSSOConnectionDeleted, Metadata: map[string]any{"organization": organization}})
+	s.logger.Info(ctx, "sso connection deleted", "organization", organization)
+	return nil
+}
+
+// Resolve returns the connection of the organization, or the connection
+// claiming the domain of the email when no organization is given
+func (s *SSOService) Resolve(ctx context.Context, organization, email string) (*sso.Connection, error) {
+	if organization != "" {
+		return s.connections.GetByOrganization(ctx, organization)
+	}
+
+	domain := sso.DomainOf(email)
+	if domain == "" {
+		return nil, sso.ErrConnectionNotFound
+	}
+	return s.connections.GetByDomain(ctx, domain)
+}
+
+// AuthRequestURL returns the URL that starts a sign-in at the identity
+// provider of the organization
+func (s *SSOService) AuthRequestURL(ctx context.Context, organization, state string) (string, error) {
+	_, provider, err := s.provider(ctx, organization)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthRequestURL(ctx, state)
+	if err != nil {
+		s.logger.Error(ctx, "failed to build sso request", "organization", organization, "error", err.Error())
+		return "", err
+	}
+	return authURL, nil
+}
+
+// Authenticate verifies the identity provider's response and signs the
+// member in. Only addresses in the domains of the organization are accepted,
+// and the role mapped from the member's groups replaces their role.
+func (s *SSOService) Authenticate(ctx context.Context, organization, state string, response sso.Response) (*user.User, error) {
+	conn, provider, err := s.provider(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := provider.Verify(ctx, state, response)
+	if err != nil {
+		s.logger.Warn(ctx, "sso response rejected", "organization", organization, "error", err.Error())
+		return nil, err
+	}
+	if !conn.Covers(assertion.Email) {
+		s.logger.Warn(ctx, "sso login outside organization domains", "organization", organization, "email", assertion.Email)
+		return nil, sso.ErrForeignEmail
+	}
+
+	u, err := s.userService.LoginWithIdentity(ctx, user.ExternalIdentity{
+		Provider:      conn.IdentityProvider(),
+		Subject:       assertion.Subject,
+		Email:         assertion.Email,
+		EmailVerified: true,
+		Name:          assertion.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	role := conn.RoleFor(assertion.Groups)
+	if role != "" && role != u.Role {
+		if u, err = s.userService.ChangeRole(ctx, u.ID, role); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	membership := &sso.Membership{Organization: conn.Organization, UserID: u.ID, Role: role, CreatedAt: now, LastLoginAt: now}
+	if err := s.memberships.Save(ctx, membership); err != nil {
+		s.logger.Error(ctx, "failed to save sso membership", "organization", organization, "userID", u.ID, "error", err.Error())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "sso login successful", "organization", organization, "userID", u.ID)
+	return u, nil
+}
+
+// CheckLogin returns sso.ErrSSORequired when the account must sign in
+// through SSO, either because a connection requiring it claims the domain of
+// the email or because the user is a member of such an organization
+func (s *SSOService) CheckLogin(ctx context.Context, userID int, email string, role user.Role) error {
+	if domain := sso.DomainOf(email); domain != "" {
+		conn, err := s.connections.GetByDomain(ctx, domain)
+		switch {
+		case err == nil:
+			if conn.Requires(role) {
+				return s.refuse(ctx, conn, userID)
+			}
+		case !errors.Is(err, sso.ErrConnectionNotFound):
+			s.logger.Error(ctx, "failed to look up sso connection", "domain", domain, "error", err.Error())
+			return err
+		}
+	}
+	if userID == 0 {
+		return nil
+	}
+
+	memberships, err := s.memberships.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list sso memberships", "userID", userID, "error", err.Error())
+		return err
+	}
+	for _, m := range memberships {
+		conn, err := s.connections.GetByOrganization(ctx, m.Organization)
+		if errors.Is(err, sso.ErrConnectionNotFound) {
+			continue
+		}
+		if err != nil {
+			s.logger.Error(ctx, "failed to retrieve sso connection", "organization", m.Organization, "error", err.Error())
+			return err
+		}
+		if conn.Requires(role) {
+			return s.refuse(ctx, conn, userID)
+		}
+	}
+	return nil
+}
+
+// refuse logs a sign-in refused because the organization requires SSO
+func (s *SSOService) refuse(ctx context.Context, conn *sso.Connection, userID int) error {
+	s.logger.Warn(ctx, "login refused: sso required", "organization", conn.Organization, "userID", userID)
+	return sso.ErrSSORequired
+}
+
+// provider returns the connection of an organization and its provider
+func (s *SSOService) provider(ctx context.Context, organization string) (*sso.Connection, sso.Provider, error) {
+	conn, err := s.connections.GetByOrganization(ctx, organization)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := s.providers.Provider(conn)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create sso provider", "organization", organization, "error", err.Error())
+		return nil, nil, err
+	}
+	return conn, provider, nil
+}
+
+// Verify that SSOService implements the sso.Service interface
+var _ sso.Service = (*SSOService)(nil)
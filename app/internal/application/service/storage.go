@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"io"
+)
+
+// ObjectStorage defines the interface for storing uploaded files. Objects
+// are written under keys like "avatars/1/abc-256.jpg" and served publicly.
+// Implementations must be safe for concurrent use.
+type ObjectStorage interface {
+	// Put stores size bytes of content under the key, replacing any object
+	// stored under it
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Delete removes the object stored under the key; missing objects are
+	// not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the object stored under the key
+	URL(key string) string
+}
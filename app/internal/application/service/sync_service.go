@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/comment"
+)
+
+// SyncService implements the change.Service interface
+type SyncService struct {
+	repo   change.Repository
+	logger Logger
+}
+
+// NewSyncService creates a new sync service
+func NewSyncService(repo change.Repository, logger Logger) *SyncService {
+	return &SyncService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Changes retrieves up to limit changed posts, changed comments and
+// deletions after the checkpoint. Comments no longer approved are reported
+// as deleted, since clients only hold approved ones.
+func (s *SyncService) Changes(ctx context.Context, since change.Checkpoint, limit int) (*change.Set, error) {
+	// Reading one more than the limit tells whether more remain
+	posts, err := s.repo.ListPosts(ctx, since.Posts, limit+1)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list changed posts", "error", err.Error())
+		return nil, err
+	}
+	comments, err := s.repo.ListComments(ctx, since.Comments, limit+1)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list changed comments", "error", err.Error())
+		return nil, err
+	}
+	tombstones, err := s.repo.ListTombstones(ctx, since.Deletions, limit+1)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list tombstones", "error", err.Error())
+		return nil, err
+	}
+
+	set := &change.Set{Checkpoint: since}
+	if len(posts) > limit || len(comments) > limit || len(tombstones) > limit {
+		set.HasMore = true
+		posts = posts[:min(len(posts), limit)]
+		comments = comments[:min(len(comments), limit)]
+		tombstones = tombstones[:min(len(tombstones), limit)]
+	}
+
+	set.Posts = posts
+	if len(posts) > 0 {
+		last := posts[len(posts)-1]
+		set.Checkpoint.Posts = change.Position{At: last.UpdatedAt, ID: last.ID}
+	}
+
+	for _, c := range comments {
+		if c.Status == comment.StatusApproved {
+			set.Comments = append(set.Comments, &c.Comment)
+		} else {
+			set.DeletedComments = append(set.DeletedComments, c.ID)
+		}
+	}
+	if len(comments) > 0 {
+		last := comments[len(comments)-1]
+		set.Checkpoint.Comments = change.Position{At: last.UpdatedAt, ID: last.ID}
+	}
+
+	for _, t := range tombstones {
+		switch t.Kind {
+		case change.KindPost:
+			set.DeletedPosts = append(set.DeletedPosts, t.EntityID)
+		case change.KindComment:
+			set.DeletedComments = append(set.DeletedComments, t.EntityID)
+		}
+	}
+	if len(tombstones) > 0 {
+		last := tombstones[len(tombstones)-1]
+		set.Checkpoint.Deletions = change.Position{At: last.DeletedAt, ID: last.ID}
+	}
+
+	return set, nil
+}
+
+// Verify that SyncService implements the change.Service interface
+var _ change.Service = (*SyncService)(nil)
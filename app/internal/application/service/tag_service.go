@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"blog-platform/internal/domain/tag"
+)
+
+// TagService implements the tag.Service interface
+type TagService struct {
+	repo   tag.Repository
+	audit  AuditLogger
+	logger Logger
+}
+
+// NewTagService creates a new tag service
+func NewTagService(repo tag.Repository, audit AuditLogger, logger Logger) *TagService {
+	return &TagService{
+		repo:   repo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// ListTags retrieves the tags of published posts with their post counts
+func (s *TagService) ListTags(ctx context.Context) ([]*tag.Tag, error) {
+	tags, err := s.repo.ListUsed(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list tags", "error", err.Error())
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ListAllTags retrieves every tag with the number of posts of any status
+// carrying it
+func (s *TagService) ListAllTags(ctx context.Context) ([]*tag.Tag, error) {
+	tags, err := s.repo.ListAll(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list all tags", "error", err.Error())
+		return nil, err
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag. The new name is normalized like the tags of
+// posts; renaming to the name of another tag is refused in favour of a
+// merge.
+func (s *TagService) RenameTag(ctx context.Context, adminID int, name, newName string, dryRun bool) (*tag.Change, error) {
+	t, err := s.getTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := tag.Normalize(newName)
+	if err != nil {
+		return nil, err
+	}
+	if normalized != t.Name {
+		if _, err := s.repo.GetByName(ctx, normalized); err == nil {
+			return nil, tag.ErrTagExists
+		}
+	}
+
+	change := &tag.Change{
+		Tags:          []*tag.Tag{t},
+		Target:        &tag.Tag{ID: t.ID, Name: normalized, PostCount: t.PostCount},
+		AffectedPosts: t.PostCount,
+		DryRun:        dryRun,
+	}
+	if dryRun || normalized == t.Name {
+		return change, nil
+	}
+
+	if err := s.repo.Rename(ctx, t.ID, normalized); err != nil {
+		if !errors.Is(err, tag.ErrTagExists) {
+			s.logger.Error(ctx, "failed to rename tag", "tag", t.Name, "error", err.Error())
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionTagRenamed,
+		UserID:   adminID,
+		Metadata: map[string]any{"tag_id": t.ID, "from": t.Name, "to": normalized, "posts": t.PostCount},
+	})
+	s.logger.Info(ctx, "tag renamed", "from", t.Name, "to", normalized, "adminID", adminID)
+	return change, nil
+}
+
+// MergeTags moves the posts of the source tags to the target tag and
+// deletes the source tags
+func (s *TagService) MergeTags(ctx context.Context, adminID int, sources []string, target string, dryRun bool) (*tag.Change, error) {
+	into, err := s.getTag(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &tag.Change{Target: into, DryRun: dryRun}
+	seen := make(map[int]bool, len(sources))
+	var ids []int
+	for _, name := range sources {
+		t, err := s.getTag(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if t.ID == into.ID {
+			return nil, tag.ErrMergeSelf
+		}
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		ids = append(ids, t.ID)
+		change.Tags = append(change.Tags, t)
+	}
+	if len(ids) == 0 {
+		return nil, tag.ErrInvalidTag
+	}
+
+	change.AffectedPosts, err = s.repo.CountPosts(ctx, ids)
+	if err != nil {
+		s.logger.Error(ctx, "failed to count posts of merged tags", "error", err.Error())
+		return nil, err
+	}
+	if dryRun {
+		return change, nil
+	}
+
+	if err := s.repo.Merge(ctx, ids, into.ID); err != nil {
+		s.logger.Error(ctx, "failed to merge tags", "target", into.Name, "error", err.Error())
+		return nil, err
+	}
+
+	names := make([]string, len(change.Tags))
+	for i, t := range change.Tags {
+		names[i] = t.Name
+	}
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionTagsMerged,
+		UserID:   adminID,
+		Metadata: map[string]any{"tags": names, "into": into.Name, "posts": change.AffectedPosts},
+	})
+	s.logger.Info(ctx, "tags merged", "tags", names, "into", into.Name, "adminID", adminID)
+	return change, nil
+}
+
+// DeleteUnusedTags deletes the tags no post carries, drafts and scheduled
+// posts included
+func (s *TagService) DeleteUnusedTags(ctx context.Context, adminID int, dryRun bool) (*tag.Change, error) {
+	tags, err := s.repo.ListAll(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list all tags", "error", err.Error())
+		return nil, err
+	}
+
+	change := &tag.Change{DryRun: dryRun}
+	for _, t := range tags {
+		if t.PostCount == 0 {
+			change.Tags = append(change.Tags, t)
+		}
+	}
+	if dryRun || len(change.Tags) == 0 {
+		return change, nil
+	}
+
+	deleted, err := s.repo.DeleteUnused(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to delete unused tags", "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionUnusedTagsDeleted,
+		UserID:   adminID,
+		Metadata: map[string]any{"deleted": deleted},
+	})
+	s.logger.Info(ctx, "unused tags deleted", "deleted", deleted, "adminID", adminID)
+	return change, nil
+}
+
+// getTag retrieves a tag by a name normalized like the tags of posts
+func (s *TagService) getTag(ctx context.Context, name string) (*tag.Tag, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, tag.ErrTagNotFound
+	}
+	t, err := s.repo.GetByName(ctx, normalized)
+	if err != nil && !errors.Is(err, tag.ErrTagNotFound) {
+		s.logger.Error(ctx, "failed to retrieve tag", "tag", normalized, "error", err.Error())
+	}
+	return t, err
+}
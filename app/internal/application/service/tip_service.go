@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tip"
+)
+
+// TipSettings configures tipping
+type TipSettings struct {
+	// BaseURL is the public URL readers return to at /p/{slug} after paying
+	BaseURL string
+	// Currency is the ISO currency code tips are paid in, e.g. usd
+	Currency string
+	Limits   tip.Limits
+}
+
+// TipService implements the tip.Service interface. Tips are recorded as
+// pending when their checkout starts and settled by the provider's events;
+// only succeeded tips count on the post.
+type TipService struct {
+	repo     tip.Repository
+	posts    post.Service
+	provider tip.PaymentProvider
+	audit    AuditLogger
+	settings TipSettings
+	logger   Logger
+}
+
+// NewTipService creates a new tip service
+func NewTipService(repo tip.Repository, posts post.Service, provider tip.PaymentProvider, audit AuditLogger, settings TipSettings, logger Logger) *TipService {
+	return &TipService{
+		repo:     repo,
+		posts:    posts,
+		provider: provider,
+		audit:    audit,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// StartTip records a pending tip to the author of a published post and
+// starts its checkout with the provider
+func (s *TipService) StartTip(ctx context.Context, postID, tipperID int, amount int64) (*tip.Tip, *tip.Checkout, error) {
+	if err := s.settings.Limits.Check(amount); err != nil {
+		return nil, nil, err
+	}
+
+	p, err := s.posts.GetPost(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.IsVisibleTo(0, "") {
+		return nil, nil, post.ErrPostNotFound
+	}
+	if tipperID > 0 && p.IsAuthor(tipperID) {
+		return nil, nil, tip.ErrOwnPost
+	}
+
+	account, err := s.repo.GetPayoutAccount(ctx, p.AuthorID)
+	if errors.Is(err, tip.ErrPayoutAccountNotFound) || (err == nil && account.Provider != s.provider.Name()) {
+		return nil, nil, tip.ErrNotPayable
+	}
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve payout account", "userID", p.AuthorID, "error", err.Error())
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	t := &tip.Tip{
+		PostID:    p.ID,
+		AuthorID:  p.AuthorID,
+		Amount:    amount,
+		Currency:  s.settings.Currency,
+		Status:    tip.StatusPending,
+		Provider:  s.provider.Name(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if tipperID > 0 {
+		t.TipperID = &tipperID
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		s.logger.Error(ctx, "failed to create tip", "postID", postID, "error", err.Error())
+		return nil, nil, err
+	}
+
+	checkout, err := s.provider.CreateCheckout(ctx, tip.CheckoutRequest{
+		TipID:       t.ID,
+		PostTitle:   p.Title,
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Destination: account.AccountID,
+		ReturnURL:   strings.TrimRight(s.settings.BaseURL, "/") + "/p/" + url.PathEscape(p.Slug) + "?tip=" + strconv.Itoa(t.ID),
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to create tip checkout", "tipID", t.ID, "provider", t.Provider, "error", err.Error())
+		if _, settleErr := s.repo.Settle(ctx, t.ID, tip.StatusFailed); settleErr != nil {
+			s.logger.Error(ctx, "failed to settle tip", "tipID", t.ID, "error", settleErr.Error())
+		}
+		return nil, nil, tip.ErrProviderUnavailable
+	}
+
+	t.PaymentID = checkout.PaymentID
+	if err := s.repo.SetPaymentID(ctx, t.ID, checkout.PaymentID); err != nil {
+		s.logger.Error(ctx, "failed to record tip payment", "tipID", t.ID, "error", err.Error())
+		return nil, nil, err
+	}
+
+	s.logger.Info(ctx, "tip started", "tipID", t.ID, "postID", t.PostID, "amount", t.Amount, "currency", t.Currency)
+	return t, checkout, nil
+}
+
+// ApplyEvent settles a tip reported by the payment provider. Events for
+// settled tips are ignored, so redelivered events are harmless and a late
+// failure does not undo a payment.
+func (s *TipService) ApplyEvent(ctx context.Context, event *tip.PaymentEvent) error {
+	t, err := s.repo.GetByID(ctx, event.TipID)
+	if err != nil {
+		if errors.Is(err, tip.ErrTipNotFound) {
+			s.logger.Warn(ctx, "payment event for unknown tip", "eventID", event.EventID, "tipID", event.TipID)
+		} else {
+			s.logger.Error(ctx, "failed to retrieve tip", "eventID", event.EventID, "error", err.Error())
+		}
+		return err
+	}
+	if t.PaymentID != "" && event.PaymentID != "" && t.PaymentID != event.PaymentID {
+		s.logger.Warn(ctx, "payment event for another payment of the tip", "eventID", event.EventID, "tipID", t.ID)
+		return tip.ErrInvalidEvent
+	}
+
+	settled, err := s.repo.Settle(ctx, t.ID, event.Status)
+	if err != nil {
+		s.logger.Error(ctx, "failed to settle tip", "eventID", event.EventID, "tipID", t.ID, "error", err.Error())
+		return err
+	}
+	if !settled {
+		s.logger.Info(ctx, "ignoring event of a settled tip", "eventID", event.EventID, "tipID", t.ID)
+		return nil
+	}
+
+	s.logger.Info(ctx, "tip settled", "tipID", t.ID, "postID", t.PostID, "status", event.Status)
+	return nil
+}
+
+// GetPayoutAccount returns the payout account linked to a user
+func (s *TipService) GetPayoutAccount(ctx context.Context, userID int) (*tip.PayoutAccount, error) {
+	return s.repo.GetPayoutAccount(ctx, userID)
+}
+
+// LinkPayoutAccount verifies an account with the provider and links it to
+// the user
+func (s *TipService) LinkPayoutAccount(ctx context.Context, userID int, accountID string) (*tip.PayoutAccount, error) {
+	accountID = strings.TrimSpace(accountID)
+	if err := s.provider.VerifyAccount(ctx, accountID); err != nil {
+		if !errors.Is(err, tip.ErrInvalidAccount) {
+			s.logger.Error(ctx, "failed to verify payout account", "userID", userID, "error", err.Error())
+			return nil, tip.ErrProviderUnavailable
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	account := &tip.PayoutAccount{
+		UserID:    userID,
+		Provider:  s.provider.Name(),
+		AccountID: accountID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.SavePayoutAccount(ctx, account); err != nil {
+		s.logger.Error(ctx, "failed to save payout account", "userID", userID, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionPayoutAccountLinked,
+		UserID:   userID,
+		Metadata: map[string]any{"provider": account.Provider, "account_id": account.AccountID},
+	})
+	return account, nil
+}
+
+// UnlinkPayoutAccount removes the payout account of a user, who no longer
+// accepts tips
+func (s *TipService) UnlinkPayoutAccount(ctx context.Context, userID int) error {
+	if err := s.repo.DeletePayoutAccount(ctx, userID); err != nil {
+		if !errors.Is(err, tip.ErrPayoutAccountNotFound) {
+			s.logger.Error(ctx, "failed to delete payout account", "userID", userID, "error", err.Error())
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPayoutAccountUnlinked, UserID: userID})
+	return nil
+}
+
+// Verify that TipService implements the tip.Service interface
+var _ tip.Service = (*TipService)(nil)
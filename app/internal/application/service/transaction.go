@@ -0,0 +1,11 @@
+package service
+
+import "context"
+
+// TxManager runs units of work touching several repositories in a single
+// database transaction. Repositories called with the context fn receives
+// take part in the transaction, which commits when fn returns nil and rolls
+// back when it returns an error.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,31 +2,132 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
 	"blog-platform/internal/domain/user"
 )
 
+// UserSettings holds tunables for account flows
+type UserSettings struct {
+	// BaseURL is the public URL used to build links in account emails
+	BaseURL string
+	// EmailChangeTTL is how long an email change confirmation stays valid
+	EmailChangeTTL time.Duration
+	// DeletionGracePeriod is how long a deleted account can still be restored by logging in
+	DeletionGracePeriod time.Duration
+	// MaxLoginAttempts is the number of failed logins, per account and per
+	// client IP, after which logins are locked; zero disables the lockout
+	MaxLoginAttempts int
+	// LockoutWindow is how long failures are counted and a lockout lasts
+	LockoutWindow time.Duration
+	// Pagination bounds the page size of user lists
+	Pagination PageLimits
+	// MagicLinkTTL is how long a passwordless login link stays valid
+	MagicLinkTTL time.Duration
+	// MaxMagicLinkRequests is the number of login links an account may be
+	// sent per hour; zero removes the limit
+	MaxMagicLinkRequests int
+	// CacheTTL is how long users looked up by email are cached; zero
+	// disables caching. Logins always read the database.
+	CacheTTL time.Duration
+}
+
+// purgeBatchSize bounds how many accounts a single purge run processes
+const purgeBatchSize = 100
+
+// magicLinkRequestWindow is the period MaxMagicLinkRequests applies to
+const magicLinkRequestWindow = time.Hour
+
 // UserService implements the user.Service interface
 type UserService struct {
-	repo   user.Repository
-	logger Logger
+	repo         user.Repository
+	emailChanges user.EmailChangeRepository
+	deletions    user.DeletionRepository
+	identities   user.IdentityRepository
+	magicLinks   user.MagicLinkRepository
+	attempts     user.LoginAttemptStore
+	cache        cacheAside
+	normalizer   *user.EmailNormalizer
+	mailer       Mailer
+	audit        AuditLogger
+	events       SecurityEvents
+	publisher    EventPublisher
+	settings     UserSettings
+	logger       Logger
 }
 
 // NewUserService creates a new UserService instance
-func NewUserService(repo user.Repository, logger Logger) *UserService {
+func NewUserService(repo user.Repository, emailChanges user.EmailChangeRepository, deletions user.DeletionRepository, identities user.IdentityRepository, magicLinks user.MagicLinkRepository, attempts user.LoginAttemptStore, cache Cache, normalizer *user.EmailNormalizer, mailer Mailer, audit AuditLogger, events SecurityEvents, publisher EventPublisher, settings UserSettings, logger Logger) *UserService {
 	return &UserService{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		emailChanges: emailChanges,
+		deletions:    deletions,
+		identities:   identities,
+		magicLinks:   magicLinks,
+		attempts:     attempts,
+		cache:        cacheAside{cache: cache, ttl: settings.CacheTTL, logger: logger},
+		normalizer:   normalizer,
+		mailer:       mailer,
+		audit:        audit,
+		events:       events,
+		publisher:    publisher,
+		settings:     settings,
+		logger:       logger,
+	}
+}
+
+// Subscribe invalidates the cached lookups of accounts changed by other
+// services
+func (s *UserService) Subscribe(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, e AccountChanged) {
+		s.forgetUser(ctx, e.User.Email)
+	})
+}
+
+// forgetUser invalidates the cached lookups of a user by its addresses
+func (s *UserService) forgetUser(ctx context.Context, emails ...string) {
+	keys := make([]string, 0, len(emails))
+	for _, email := range emails {
+		if email != "" {
+			keys = append(keys, userEmailCacheKey(s.normalizer.Normalize(email)))
+		}
 	}
+	s.cache.forget(ctx, keys...)
+}
+
+// userEmailCacheKey is the cache key of a user looked up by normalized email
+func userEmailCacheKey(normalizedEmail string) string {
+	return "user:email:" + normalizedEmail
 }
 
 // Register creates a new user account
 func (s *UserService) Register(ctx context.Context, name, email, password string) (*user.User, error) {
+	return s.register(ctx, name, email, password, nil)
+}
+
+// RegisterPasswordless creates an account that signs in with another
+// method, such as a passkey. The account gets a random password, so it
+// cannot sign in with a password until the password is reset.
+func (s *UserService) RegisterPasswordless(ctx context.Context, name, email, method string) (*user.User, error) {
+	password, err := randomPassword()
+	if err != nil {
+		s.logger.Error(ctx, "failed to generate password for passwordless account", "error", err.Error())
+		return nil, err
+	}
+	return s.register(ctx, name, email, password, map[string]any{"method": method})
+}
+
+// register creates a user account, recording metadata with the audit event
+func (s *UserService) register(ctx context.Context, name, email, password string, metadata map[string]any) (*user.User, error) {
 	s.logger.Info(ctx, "registering new user", "email", email, "name", name)
 	
-	// Check if user already exists
-	existingUser, err := s.repo.GetByEmail(ctx, email)
+	// Check if user already exists, comparing canonical addresses
+	normalizedEmail := s.normalizer.Normalize(email)
+	existingUser, err := s.repo.GetByEmail(ctx, normalizedEmail)
 	if err == nil && existingUser != nil {
 		s.logger.Warn(ctx, "registration attempt for existing user", "email", email)
 		return nil, user.ErrUserExists
@@ -42,6 +143,7 @@ func (s *UserService) Register(ctx context.Context, name, email, password string
 		s.logger.Error(ctx, "failed to create user entity", "email", email, "name", name, "error", err.Error())
 		return nil, err
 	}
+	u.NormalizedEmail = normalizedEmail
 
 	// Save to repository
 	err = s.repo.Create(ctx, u)
@@ -50,6 +152,8 @@ func (s *UserService) Register(ctx context.Context, name, email, password string
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserRegistered, UserID: u.ID, Metadata: metadata})
+	s.publisher.Publish(ctx, UserRegistered{User: u})
 	s.logger.Info(ctx, "user registered successfully", "email", email, "userID", u.ID)
 	return u, nil
 }
@@ -58,27 +162,443 @@ func (s *UserService) Register(ctx context.Context, name, email, password string
 func (s *UserService) Login(ctx context.Context, email, password string) (*user.User, error) {
 	s.logger.Info(ctx, "user login attempt", "email", email)
 	
-	// Get user by email
-	u, err := s.repo.GetByEmail(ctx, email)
+	// Failures are counted per client IP as well, so guessing across many
+	// accounts is throttled too
+	var clientKey string
+	if ip := user.ClientIPFromContext(ctx); ip != "" {
+		clientKey = user.ClientAttemptKey(ip)
+	}
+	if err := s.checkLockout(ctx, clientKey); err != nil {
+		s.logger.Warn(ctx, "login attempt from locked client", "email", email)
+		return nil, err
+	}
+	
+	u, err := s.getBySignInEmail(ctx, email)
 	if err != nil {
 		if err == user.ErrUserNotFound {
 			s.logger.Warn(ctx, "login attempt with non-existent email", "email", email)
+			s.recordLoginFailure(ctx, clientKey)
 			return nil, user.ErrInvalidCredentials
 		}
 		s.logger.Error(ctx, "failed to retrieve user during login", "email", email, "error", err.Error())
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	
+	accountKey := user.AccountAttemptKey(u.ID)
+	if err := s.checkLockout(ctx, accountKey); err != nil {
+		s.logger.Warn(ctx, "login attempt for locked account", "email", email, "userID", u.ID)
+		return nil, err
+	}
 
 	// Validate password
 	if !u.ValidatePassword(password) {
 		s.logger.Warn(ctx, "login attempt with invalid password", "email", email, "userID", u.ID)
+		s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLoginFailed, UserID: u.ID})
+		s.recordLoginFailure(ctx, clientKey)
+		if s.recordLoginFailure(ctx, accountKey) {
+			s.audit.Record(ctx, AuditEvent{
+				Action:   AuditActionUserLocked,
+				UserID:   u.ID,
+				Metadata: map[string]any{"window": s.settings.LockoutWindow.String()},
+			})
+		}
 		return nil, user.ErrInvalidCredentials
 	}
 
+	if s.settings.MaxLoginAttempts > 0 {
+		if err := s.attempts.Reset(ctx, accountKey); err != nil {
+			s.logger.Error(ctx, "failed to reset login attempts", "userID", u.ID, "error", err.Error())
+		}
+	}
+
+	// Checked after the password so the lock is only revealed to the owner
+	if u.IsPasswordResetRequired() {
+		s.logger.Warn(ctx, "login attempt for account awaiting password reset", "email", email, "userID", u.ID)
+		return nil, user.ErrPasswordResetRequired
+	}
+	if u.IsDeactivated() {
+		s.logger.Warn(ctx, "login attempt for deactivated account", "email", email, "userID", u.ID)
+		return nil, user.ErrAccountDeactivated
+	}
+
+	if err := s.restorePendingDeletion(ctx, u); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLogin, UserID: u.ID, Metadata: map[string]any{"method": "password"}})
 	s.logger.Info(ctx, "user login successful", "email", email, "userID", u.ID)
 	return u, nil
 }
 
+// getBySignInEmail finds the account signing in with an address. Accounts
+// flagged by the email normalization migration share their canonical address
+// with an older account, so they are matched on the exact address they
+// registered with until they change it.
+func (s *UserService) getBySignInEmail(ctx context.Context, email string) (*user.User, error) {
+	u, err := s.repo.GetConflictedByEmail(ctx, strings.TrimSpace(email))
+	if err != user.ErrUserNotFound {
+		return u, err
+	}
+	return s.repo.GetByEmail(ctx, s.normalizer.Normalize(email))
+}
+
+// checkLockout returns a *user.LockoutError if key has reached the maximum
+// number of failed logins. The lockout fails open: when the attempt store
+// is unavailable, logins still require the password.
+func (s *UserService) checkLockout(ctx context.Context, key string) error {
+	if key == "" || s.settings.MaxLoginAttempts <= 0 {
+		return nil
+	}
+
+	failures, expiresAt, err := s.attempts.Failures(ctx, key)
+	if err != nil {
+		s.logger.Error(ctx, "failed to read login attempts", "key", key, "error", err.Error())
+		return nil
+	}
+	if failures >= s.settings.MaxLoginAttempts {
+		return &user.LockoutError{Until: expiresAt}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login for key and reports whether the
+// failure locked it
+func (s *UserService) recordLoginFailure(ctx context.Context, key string) bool {
+	if key == "" || s.settings.MaxLoginAttempts <= 0 {
+		return false
+	}
+
+	failures, err := s.attempts.RecordFailure(ctx, key, s.settings.LockoutWindow)
+	if err != nil {
+		s.logger.Error(ctx, "failed to record login attempt", "key", key, "error", err.Error())
+		return false
+	}
+	if failures == s.settings.MaxLoginAttempts {
+		s.logger.Warn(ctx, "login locked after repeated failures", "key", key, "failures", failures, "window", s.settings.LockoutWindow.String())
+		return true
+	}
+	return false
+}
+
+// LoginWithIdentity signs in the user linked to an external account. An
+// unlinked account is linked to the user with the same verified email, or a
+// new user is registered for it.
+func (s *UserService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, error) {
+	s.logger.Info(ctx, "external login attempt", "provider", external.Provider, "email", external.Email)
+
+	identity, err := s.identities.GetByProviderSubject(ctx, external.Provider, external.Subject)
+	if err == nil {
+		u, err := s.repo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			s.logger.Error(ctx, "failed to retrieve user for linked identity", "provider", external.Provider, "userID", identity.UserID, "error", err.Error())
+			return nil, err
+		}
+		if u.IsPasswordResetRequired() {
+			s.logger.Warn(ctx, "external login for account awaiting password reset", "provider", external.Provider, "userID", u.ID)
+			return nil, user.ErrPasswordResetRequired
+		}
+		if u.IsDeactivated() {
+			s.logger.Warn(ctx, "external login for deactivated account", "provider", external.Provider, "userID", u.ID)
+			return nil, user.ErrAccountDeactivated
+		}
+		if err := s.restorePendingDeletion(ctx, u); err != nil {
+			return nil, err
+		}
+		s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLogin, UserID: u.ID, Metadata: map[string]any{"method": external.Provider}})
+		s.logger.Info(ctx, "external login successful", "provider", external.Provider, "userID", u.ID)
+		return u, nil
+	}
+	if err != user.ErrIdentityNotFound {
+		s.logger.Error(ctx, "failed to look up identity", "provider", external.Provider, "error", err.Error())
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	// Matching or registering by email is only safe for addresses the
+	// provider has verified
+	if !external.EmailVerified || external.Email == "" {
+		s.logger.Warn(ctx, "external login without verified email", "provider", external.Provider)
+		return nil, user.ErrUnverifiedEmail
+	}
+	if err := user.ValidateEmail(external.Email); err != nil {
+		s.logger.Warn(ctx, "external login with malformed email", "provider", external.Provider)
+		return nil, err
+	}
+
+	normalizedEmail := s.normalizer.Normalize(external.Email)
+	u, err := s.repo.GetByEmail(ctx, normalizedEmail)
+	switch {
+	case err == nil:
+		if u.IsPasswordResetRequired() {
+			s.logger.Warn(ctx, "external login for account awaiting password reset", "provider", external.Provider, "userID", u.ID)
+			return nil, user.ErrPasswordResetRequired
+		}
+		if u.IsDeactivated() {
+			s.logger.Warn(ctx, "external login for deactivated account", "provider", external.Provider, "userID", u.ID)
+			return nil, user.ErrAccountDeactivated
+		}
+		if err := s.restorePendingDeletion(ctx, u); err != nil {
+			return nil, err
+		}
+	case err == user.ErrUserNotFound:
+		u, err = s.registerExternal(ctx, external, normalizedEmail)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		s.logger.Error(ctx, "failed to check existing user during external login", "email", external.Email, "error", err.Error())
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+
+	if err := s.linkIdentity(ctx, u, external); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLogin, UserID: u.ID, Metadata: map[string]any{"method": external.Provider}})
+	s.logger.Info(ctx, "external login successful", "provider", external.Provider, "userID", u.ID)
+	return u, nil
+}
+
+// registerExternal creates a user for an external account. The account gets
+// a random password, so it can only sign in through the provider until the
+// password is reset.
+func (s *UserService) registerExternal(ctx context.Context, external user.ExternalIdentity, normalizedEmail string) (*user.User, error) {
+	name := external.Name
+	if name == "" {
+		name = external.Email[:strings.LastIndex(external.Email, "@")]
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		s.logger.Error(ctx, "failed to generate password for external account", "error", err.Error())
+		return nil, err
+	}
+
+	u, err := user.NewUser(name, external.Email, password)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create user entity for external account", "email", external.Email, "error", err.Error())
+		return nil, err
+	}
+	u.NormalizedEmail = normalizedEmail
+
+	if err := s.repo.Create(ctx, u); err != nil {
+		s.logger.Error(ctx, "failed to save user for external account", "email", external.Email, "error", err.Error())
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserRegistered, UserID: u.ID, Metadata: map[string]any{"provider": external.Provider}})
+	s.logger.Info(ctx, "user registered through external provider", "provider", external.Provider, "userID", u.ID)
+	return u, nil
+}
+
+// linkIdentity stores the link between a user and an external account and
+// notifies the user
+func (s *UserService) linkIdentity(ctx context.Context, u *user.User, external user.ExternalIdentity) error {
+	identity, err := user.NewIdentity(u.ID, external)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create identity entity", "userID", u.ID, "error", err.Error())
+		return err
+	}
+
+	if err := s.identities.Create(ctx, identity); err != nil {
+		s.logger.Error(ctx, "failed to save identity", "userID", u.ID, "provider", external.Provider, "error", err.Error())
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionIdentityLinked,
+		UserID:   u.ID,
+		Metadata: map[string]any{"provider": external.Provider},
+	})
+
+	// The notice is best-effort; the link is already stored
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      u.Email,
+		Subject: "A sign-in method was added to your account",
+		Body: fmt.Sprintf("Hi %s,\n\nYour %s account can now be used to sign in.\nIf you did not do this, please contact support.\n",
+			u.Name, external.Provider),
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to send identity link notice", "userID", u.ID, "error", err.Error())
+	}
+
+	return nil
+}
+
+// RequestMagicLink emails a single-use login link to the account with the
+// address. Unknown addresses, locked accounts and accounts over their
+// request limit are skipped without an error, so the response does not
+// reveal which addresses have accounts.
+func (s *UserService) RequestMagicLink(ctx context.Context, email string, device user.Device) error {
+	s.logger.Info(ctx, "magic link requested", "email", email, "ip", device.IP)
+
+	u, err := s.getBySignInEmail(ctx, email)
+	if err != nil {
+		if err == user.ErrUserNotFound {
+			s.logger.Warn(ctx, "magic link requested for non-existent email", "email", email)
+			return nil
+		}
+		s.logger.Error(ctx, "failed to retrieve user for magic link", "email", email, "error", err.Error())
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if u.IsPasswordResetRequired() || u.IsDeactivated() {
+		s.logger.Warn(ctx, "magic link requested for account that cannot sign in", "userID", u.ID)
+		return nil
+	}
+
+	// Limits the emails anyone can have sent to an address; requests from
+	// one client are also limited by the auth rate limit
+	if s.settings.MaxMagicLinkRequests > 0 {
+		requests, err := s.attempts.RecordFailure(ctx, user.MagicLinkAttemptKey(u.ID), magicLinkRequestWindow)
+		if err != nil {
+			s.logger.Error(ctx, "failed to count magic link requests", "userID", u.ID, "error", err.Error())
+		} else if requests > s.settings.MaxMagicLinkRequests {
+			s.logger.Warn(ctx, "magic link request limit reached", "userID", u.ID, "requests", requests)
+			return nil
+		}
+	}
+
+	link, token, err := user.NewMagicLink(u.ID, device, s.settings.MagicLinkTTL)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create magic link entity", "userID", u.ID, "error", err.Error())
+		return err
+	}
+	if err := s.magicLinks.Create(ctx, link); err != nil {
+		s.logger.Error(ctx, "failed to save magic link", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+
+	loginURL := s.settings.BaseURL + "/api/v1/auth/magic-link/verify?token=" + token
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      u.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to sign in:\n\n%s\n\nThe link works once and expires at %s.\nIt was requested from %s.\nIf you did not request it, you can ignore this email.\n",
+			u.Name, loginURL, link.ExpiresAt.UTC().Format(time.RFC1123), describeDevice(device)),
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to send magic link", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to send magic link email: %w", err)
+	}
+
+	s.logger.Info(ctx, "magic link sent", "userID", u.ID)
+	return nil
+}
+
+// LoginWithMagicLink signs in with the token of an emailed magic link. The
+// link is used up even when the login fails afterwards.
+func (s *UserService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, error) {
+	link, err := s.magicLinks.Consume(ctx, user.HashToken(token), device)
+	if err != nil {
+		if err == user.ErrInvalidMagicLink {
+			s.logger.Warn(ctx, "login with invalid magic link", "ip", device.IP)
+		} else {
+			s.logger.Error(ctx, "failed to consume magic link", "error", err.Error())
+		}
+		return nil, err
+	}
+
+	u, err := s.repo.GetByID(ctx, link.UserID)
+	if err != nil {
+		if err == user.ErrUserNotFound {
+			return nil, user.ErrInvalidMagicLink
+		}
+		s.logger.Error(ctx, "failed to retrieve user for magic link", "userID", link.UserID, "error", err.Error())
+		return nil, err
+	}
+
+	if u.IsPasswordResetRequired() {
+		s.logger.Warn(ctx, "magic link login for account awaiting password reset", "userID", u.ID)
+		return nil, user.ErrPasswordResetRequired
+	}
+	if u.IsDeactivated() {
+		s.logger.Warn(ctx, "magic link login for deactivated account", "userID", u.ID)
+		return nil, user.ErrAccountDeactivated
+	}
+	if err := s.restorePendingDeletion(ctx, u); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLogin, UserID: u.ID, Metadata: map[string]any{
+		"method":             "magic_link",
+		"ip":                 device.IP,
+		"user_agent":         device.UserAgent,
+		"request_ip":         link.RequestIP,
+		"request_user_agent": link.RequestUserAgent,
+	}})
+	s.logger.Info(ctx, "magic link login successful", "userID", u.ID)
+	return u, nil
+}
+
+// CompleteLogin signs in a user whose credentials were verified by another
+// service, such as a passkey. Like a password login it is refused while a
+// password reset is required or the account is deactivated, and restores
+// accounts pending deletion.
+func (s *UserService) CompleteLogin(ctx context.Context, id int, method string) (*user.User, error) {
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for login", "userID", id, "error", err.Error())
+		return nil, err
+	}
+
+	if u.IsPasswordResetRequired() {
+		s.logger.Warn(ctx, "login for account awaiting password reset", "userID", u.ID, "method", method)
+		return nil, user.ErrPasswordResetRequired
+	}
+	if u.IsDeactivated() {
+		s.logger.Warn(ctx, "login for deactivated account", "userID", u.ID, "method", method)
+		return nil, user.ErrAccountDeactivated
+	}
+	if err := s.restorePendingDeletion(ctx, u); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserLogin, UserID: u.ID, Metadata: map[string]any{"method": method}})
+	s.logger.Info(ctx, "login successful", "userID", u.ID, "method", method)
+	return u, nil
+}
+
+// describeDevice describes a client for account emails
+func describeDevice(device user.Device) string {
+	switch {
+	case device.IP == "" && device.UserAgent == "":
+		return "an unknown device"
+	case device.UserAgent == "":
+		return device.IP
+	case device.IP == "":
+		return device.UserAgent
+	default:
+		return fmt.Sprintf("%s (%s)", device.IP, device.UserAgent)
+	}
+}
+
+// restorePendingDeletion cancels a scheduled deletion; signing in during the
+// grace period restores the account
+func (s *UserService) restorePendingDeletion(ctx context.Context, u *user.User) error {
+	if !u.IsPendingDeletion() {
+		return nil
+	}
+
+	u.CancelDeletion()
+	if err := s.repo.Update(ctx, u); err != nil {
+		s.logger.Error(ctx, "failed to restore account pending deletion", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to restore account: %w", err)
+	}
+	s.forgetUser(ctx, u.Email)
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserDeletionCancelled, UserID: u.ID})
+	s.logger.Info(ctx, "account deletion cancelled by login", "userID", u.ID)
+	return nil
+}
+
+// randomPassword generates an unguessable password for accounts created
+// without one
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // GetByID retrieves a user by their ID
 func (s *UserService) GetByID(ctx context.Context, id int) (*user.User, error) {
 	s.logger.Debug(ctx, "retrieving user by ID", "userID", id)
@@ -96,18 +616,28 @@ func (s *UserService) GetByID(ctx context.Context, id int) (*user.User, error) {
 // GetByEmail retrieves a user by their email
 func (s *UserService) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	s.logger.Debug(ctx, "retrieving user by email", "email", email)
+
+	normalizedEmail := s.normalizer.Normalize(email)
+	key := userEmailCacheKey(normalizedEmail)
+	var cached user.User
+	if s.cache.load(ctx, key, &cached) {
+		return &cached, nil
+	}
 	
-	u, err := s.repo.GetByEmail(ctx, email)
+	u, err := s.repo.GetByEmail(ctx, normalizedEmail)
 	if err != nil {
 		s.logger.Error(ctx, "failed to retrieve user by email", "email", email, "error", err.Error())
 		return nil, err
 	}
+	s.cache.store(ctx, key, u)
 	
 	s.logger.Debug(ctx, "user retrieved successfully by email", "email", email, "userID", u.ID)
 	return u, nil
 }
 
-// UpdateProfile updates a user's profile information
+// UpdateProfile updates a user's profile information.
+// The name is updated immediately; a different email starts the email change
+// flow and the current address stays active until the new one is confirmed.
 func (s *UserService) UpdateProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
 	s.logger.Info(ctx, "updating user profile", "userID", id, "newEmail", email, "newName", name)
 	
@@ -118,22 +648,16 @@ func (s *UserService) UpdateProfile(ctx context.Context, id int, name, email str
 		return nil, err
 	}
 
-	// Check if email is being changed and if new email already exists
-	if u.Email != email {
-		s.logger.Debug(ctx, "email change detected, checking for conflicts", "userID", id, "oldEmail", u.Email, "newEmail", email)
-		existingUser, err := s.repo.GetByEmail(ctx, email)
-		if err == nil && existingUser != nil && existingUser.ID != id {
-			s.logger.Warn(ctx, "profile update attempt with existing email", "userID", id, "conflictingEmail", email, "existingUserID", existingUser.ID)
-			return nil, user.ErrUserExists
-		}
-		if err != nil && err != user.ErrUserNotFound {
-			s.logger.Error(ctx, "failed to check email conflict during profile update", "userID", id, "email", email, "error", err.Error())
-			return nil, fmt.Errorf("failed to check existing email: %w", err)
+	// Start the re-verification flow if the email is being changed
+	if s.normalizer.Normalize(email) != u.NormalizedEmail {
+		s.logger.Debug(ctx, "email change detected, requesting confirmation", "userID", id, "oldEmail", u.Email, "newEmail", email)
+		if err := s.requestEmailChange(ctx, u, email); err != nil {
+			return nil, err
 		}
 	}
 
-	// Update profile with validation
-	err = u.UpdateProfile(name, email)
+	// Update profile with validation, keeping the current email
+	err = u.UpdateProfile(name, u.Email)
 	if err != nil {
 		s.logger.Error(ctx, "failed to update user profile entity", "userID", id, "error", err.Error())
 		return nil, err
@@ -145,8 +669,246 @@ func (s *UserService) UpdateProfile(ctx context.Context, id int, name, email str
 		s.logger.Error(ctx, "failed to save profile updates", "userID", id, "error", err.Error())
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
+	s.forgetUser(ctx, u.Email)
+
+	s.logger.Info(ctx, "user profile updated successfully", "userID", id, "email", u.Email)
+	return u, nil
+}
+
+// SyncProfile sets the name and email of an account managed by an identity
+// provider. The provider has verified the address, so it is changed without
+// a confirmation; the previous address is still notified.
+func (s *UserService) SyncProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
+	s.logger.Info(ctx, "syncing user profile", "userID", id, "email", email)
+
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for profile sync", "userID", id, "error", err.Error())
+		return nil, err
+	}
+
+	oldEmail := u.Email
+	normalizedEmail := s.normalizer.Normalize(email)
+	emailChanged := normalizedEmail != u.NormalizedEmail
+	if emailChanged {
+		existing, err := s.repo.GetByEmail(ctx, normalizedEmail)
+		if err == nil && existing.ID != u.ID {
+			s.logger.Warn(ctx, "profile sync to address of another user", "userID", id, "email", email)
+			return nil, user.ErrUserExists
+		}
+		if err != nil && err != user.ErrUserNotFound {
+			s.logger.Error(ctx, "failed to check existing user during profile sync", "email", email, "error", err.Error())
+			return nil, fmt.Errorf("failed to check existing user: %w", err)
+		}
+	}
+
+	if err := u.UpdateProfile(name, email); err != nil {
+		s.logger.Error(ctx, "failed to update user profile entity", "userID", id, "error", err.Error())
+		return nil, err
+	}
+	u.NormalizedEmail = normalizedEmail
+	u.EmailConflict = false
+
+	// The unique index rejects the address if it was claimed meanwhile
+	if err := s.repo.Update(ctx, u); err != nil {
+		if err == user.ErrUserExists {
+			return nil, err
+		}
+		s.logger.Error(ctx, "failed to save synced profile", "userID", id, "error", err.Error())
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	s.forgetUser(ctx, oldEmail, u.Email)
+
+	if emailChanged {
+		s.events.Publish(ctx, SecurityEvent{Kind: SecurityEventEmailChanged, UserID: u.ID, Email: oldEmail, Detail: u.Email})
+	}
+
+	s.logger.Info(ctx, "user profile synced", "userID", id, "email", u.Email)
+	return u, nil
+}
+
+// Deactivate turns an account off; it cannot sign in or refresh its
+// sessions until reactivated. Deactivating twice is not an error.
+func (s *UserService) Deactivate(ctx context.Context, id int) error {
+	return s.setActive(ctx, id, false)
+}
+
+// Reactivate turns a deactivated account back on
+func (s *UserService) Reactivate(ctx context.Context, id int) error {
+	return s.setActive(ctx, id, true)
+}
+
+// ChangeRole sets the role of an account, auditing the change
+func (s *UserService) ChangeRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	if !role.IsValid() {
+		return nil, user.ErrInvalidRole
+	}
+
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for role change", "userID", id, "error", err.Error())
+		return nil, err
+	}
+	if u.Role == role {
+		return u, nil
+	}
+
+	previous := u.Role
+	u.Role = role
+	if err := s.repo.Update(ctx, u); err != nil {
+		s.logger.Error(ctx, "failed to save role change", "userID", id, "error", err.Error())
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	s.forgetUser(ctx, u.Email)
 
-	s.logger.Info(ctx, "user profile updated successfully", "userID", id, "email", email)
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionRoleChanged, UserID: id, Metadata: map[string]any{"from": string(previous), "to": string(role)}})
+	s.logger.Info(ctx, "user role changed", "userID", id, "role", role)
+	return u, nil
+}
+
+// setActive deactivates or reactivates an account, auditing the change
+func (s *UserService) setActive(ctx context.Context, id int, active bool) error {
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for activation change", "userID", id, "error", err.Error())
+		return err
+	}
+	if u.IsDeactivated() != active {
+		return nil
+	}
+
+	action := AuditActionUserReactivated
+	if active {
+		u.Reactivate()
+	} else {
+		u.Deactivate()
+		action = AuditActionUserDeactivated
+	}
+	if err := s.repo.Update(ctx, u); err != nil {
+		s.logger.Error(ctx, "failed to save activation change", "userID", id, "error", err.Error())
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	s.forgetUser(ctx, u.Email)
+
+	s.audit.Record(ctx, AuditEvent{Action: action, UserID: id})
+	s.logger.Info(ctx, "user activation changed", "userID", id, "active", active)
+	return nil
+}
+
+// RequestEmailChange starts an email change by sending a confirmation link to
+// the new address and a notice to the current one
+func (s *UserService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	s.logger.Info(ctx, "email change requested", "userID", id, "newEmail", newEmail)
+
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for email change", "userID", id, "error", err.Error())
+		return err
+	}
+
+	return s.requestEmailChange(ctx, u, newEmail)
+}
+
+// requestEmailChange validates the new address, stores the pending change and sends the emails
+func (s *UserService) requestEmailChange(ctx context.Context, u *user.User, newEmail string) error {
+	if err := user.ValidateEmail(newEmail); err != nil {
+		return err
+	}
+	normalizedEmail := s.normalizer.Normalize(newEmail)
+	if normalizedEmail == u.NormalizedEmail {
+		return user.ErrEmailUnchanged
+	}
+
+	// Reject addresses that already belong to another account
+	existingUser, err := s.repo.GetByEmail(ctx, normalizedEmail)
+	if err == nil && existingUser != nil && existingUser.ID != u.ID {
+		s.logger.Warn(ctx, "email change attempt with existing email", "userID", u.ID, "conflictingEmail", newEmail, "existingUserID", existingUser.ID)
+		return user.ErrUserExists
+	}
+	if err != nil && err != user.ErrUserNotFound {
+		s.logger.Error(ctx, "failed to check email conflict during email change", "userID", u.ID, "email", newEmail, "error", err.Error())
+		return fmt.Errorf("failed to check existing email: %w", err)
+	}
+
+	change, token, err := user.NewEmailChange(u.ID, newEmail, normalizedEmail, s.settings.EmailChangeTTL)
+	if err != nil {
+		s.logger.Error(ctx, "failed to create email change entity", "userID", u.ID, "error", err.Error())
+		return err
+	}
+
+	if err := s.emailChanges.Create(ctx, change); err != nil {
+		s.logger.Error(ctx, "failed to save email change request", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to create email change: %w", err)
+	}
+
+	confirmURL := s.settings.BaseURL + "/api/v1/users/email/confirm?token=" + token
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your new email address by opening the link below:\n\n%s\n\nThe link expires at %s.\n",
+			u.Name, confirmURL, change.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to send email change confirmation", "userID", u.ID, "error", err.Error())
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	// Notifying the current address is best-effort
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      u.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf("Hi %s,\n\nA request was made to change the email on your account to %s.\nYour current address stays active until the change is confirmed.\nIf you did not request this, please change your password.\n",
+			u.Name, newEmail),
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to notify current address of email change", "userID", u.ID, "error", err.Error())
+	}
+
+	s.logger.Info(ctx, "email change confirmation sent", "userID", u.ID, "newEmail", newEmail)
+	return nil
+}
+
+// ConfirmEmailChange completes a pending email change using the emailed token
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) (*user.User, error) {
+	s.logger.Info(ctx, "confirming email change")
+
+	change, err := s.emailChanges.GetByTokenHash(ctx, user.HashToken(token))
+	if err != nil {
+		s.logger.Warn(ctx, "email change confirmation with unknown token", "error", err.Error())
+		return nil, err
+	}
+
+	if change.IsExpired() {
+		s.logger.Warn(ctx, "email change confirmation with expired token", "userID", change.UserID)
+		if err := s.emailChanges.Delete(ctx, change.ID); err != nil {
+			s.logger.Warn(ctx, "failed to delete expired email change", "userID", change.UserID, "error", err.Error())
+		}
+		return nil, user.ErrEmailChangeExpired
+	}
+
+	u, err := s.repo.GetByID(ctx, change.UserID)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for email change confirmation", "userID", change.UserID, "error", err.Error())
+		return nil, err
+	}
+	oldEmail := u.Email
+
+	// Swap the address and drop the request atomically; the unique index
+	// rejects the change if the address was claimed in the meantime
+	if err := s.emailChanges.Complete(ctx, change); err != nil {
+		s.logger.Warn(ctx, "failed to complete email change", "userID", u.ID, "error", err.Error())
+		return nil, err
+	}
+	s.forgetUser(ctx, oldEmail, change.NewEmail)
+
+	u.Email = change.NewEmail
+	u.NormalizedEmail = change.NewEmailNormalized
+	u.EmailConflict = false
+
+	// The previous address is notified, in case the account was taken over
+	s.events.Publish(ctx, SecurityEvent{Kind: SecurityEventEmailChanged, UserID: u.ID, Email: oldEmail, Detail: change.NewEmail})
+
+	s.logger.Info(ctx, "email change confirmed", "userID", u.ID, "email", u.Email)
 	return u, nil
 }
 
@@ -180,7 +942,10 @@ func (s *UserService) UpdatePassword(ctx context.Context, id int, currentPasswor
 		s.logger.Error(ctx, "failed to save password update", "userID", id, "error", err.Error())
 		return fmt.Errorf("failed to update password: %w", err)
 	}
+	s.forgetUser(ctx, u.Email)
 
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionPasswordChanged, UserID: id})
+	s.events.Publish(ctx, SecurityEvent{Kind: SecurityEventPasswordChanged, UserID: id})
 	s.logger.Info(ctx, "user password updated successfully", "userID", id)
 	return nil
 }
@@ -188,34 +953,111 @@ func (s *UserService) UpdatePassword(ctx context.Context, id int, currentPasswor
 // Delete removes a user account
 func (s *UserService) Delete(ctx context.Context, id int) error {
 	s.logger.Info(ctx, "deleting user account", "userID", id)
+
+	// The address is needed to invalidate the cached account
+	var email string
+	if s.cache.enabled() {
+		if u, err := s.repo.GetByID(ctx, id); err == nil {
+			email = u.Email
+		}
+	}
 	
 	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		s.logger.Error(ctx, "failed to delete user account", "userID", id, "error", err.Error())
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	s.forgetUser(ctx, email)
 	
+	s.audit.Record(ctx, AuditEvent{Action: AuditActionUserDeleted, UserID: id})
 	s.logger.Info(ctx, "user account deleted successfully", "userID", id)
 	return nil
 }
 
+// ScheduleDeletion marks an account for deletion. The account stays usable
+// for the configured grace period and is restored by the next login; after
+// that the purge job anonymizes it. Scheduling an already pending account
+// keeps the original date.
+func (s *UserService) ScheduleDeletion(ctx context.Context, id int) (*user.User, error) {
+	s.logger.Info(ctx, "scheduling account deletion", "userID", id)
+
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error(ctx, "failed to retrieve user for deletion", "userID", id, "error", err.Error())
+		return nil, err
+	}
+
+	if u.IsPendingDeletion() {
+		s.logger.Debug(ctx, "account deletion already scheduled", "userID", id)
+		return u, nil
+	}
+
+	u.ScheduleDeletion(s.settings.DeletionGracePeriod)
+	if err := s.repo.Update(ctx, u); err != nil {
+		s.logger.Error(ctx, "failed to save account deletion", "userID", id, "error", err.Error())
+		return nil, fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+	s.forgetUser(ctx, u.Email)
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionUserDeletionRequested,
+		UserID:   u.ID,
+		Metadata: map[string]any{"purgeAt": u.DeletionScheduledAt.UTC().Format(time.RFC3339)},
+	})
+
+	// The notice is best-effort; the deletion is already scheduled
+	err = s.mailer.Send(ctx, EmailMessage{
+		To:      u.Email,
+		Subject: "Your account is scheduled for deletion",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account will be permanently deleted on %s.\nTo keep your account, simply log in before then.\n",
+			u.Name, u.DeletionScheduledAt.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "failed to send account deletion notice", "userID", u.ID, "error", err.Error())
+	}
+
+	s.logger.Info(ctx, "account deletion scheduled", "userID", u.ID, "purgeAt", u.DeletionScheduledAt)
+	return u, nil
+}
+
+// PurgeDeletedAccounts anonymizes accounts whose deletion grace period has
+// ended and returns how many were purged. It is run by the scheduler.
+func (s *UserService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	due, err := s.deletions.ListDueForPurge(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list accounts due for purge", "error", err.Error())
+		return 0, fmt.Errorf("failed to list accounts due for purge: %w", err)
+	}
+
+	purged := 0
+	for _, u := range due {
+		if err := s.deletions.Purge(ctx, u.ID); err != nil {
+			if err == user.ErrDeletionNotScheduled {
+				s.logger.Debug(ctx, "account restored before purge", "userID", u.ID)
+				continue
+			}
+			s.logger.Error(ctx, "failed to purge account", "userID", u.ID, "error", err.Error())
+			continue
+		}
+		s.forgetUser(ctx, u.Email)
+
+		purged++
+		s.audit.Record(ctx, AuditEvent{Action: AuditActionUserPurged, UserID: u.ID})
+		s.logger.Info(ctx, "account purged", "userID", u.ID)
+	}
+
+	return purged, nil
+}
+
 // List retrieves a paginated list of users
 func (s *UserService) List(ctx context.Context, limit, offset int) ([]*user.User, error) {
 	s.logger.Debug(ctx, "listing users", "limit", limit, "offset", offset)
 	
-	// Validate pagination parameters
-	originalLimit := limit
-	if limit <= 0 {
-		limit = 10 // Default limit
-	}
-	if limit > 100 {
-		limit = 100 // Maximum limit
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	// Apply the user pagination limits
+	originalLimit, originalOffset := limit, offset
+	limit, offset = s.settings.Pagination.Normalize(limit, offset)
 	
-	if originalLimit != limit || offset < 0 {
+	if originalLimit != limit || originalOffset != offset {
 		s.logger.Debug(ctx, "pagination parameters adjusted", "originalLimit", originalLimit, "adjustedLimit", limit, "adjustedOffset", offset)
 	}
 
@@ -0,0 +1,8 @@
+package service
+
+// ViewCounter defines the interface for counting post views. Views are
+// buffered and written in batches, so Record returns without waiting on
+// storage and counts may be lost if the process crashes.
+type ViewCounter interface {
+	Record(postID int)
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/domain/webhook"
+)
+
+// webhookSecretPrefix marks webhook secrets, so they are recognized when
+// they leak
+const webhookSecretPrefix = "whsec_"
+
+// WebhookSettings holds the configurable behaviour of webhook deliveries
+type WebhookSettings struct {
+	// MaxAttempts is the number of requests made for a delivery before it
+	// is marked failed; it matches the attempts of the job queue
+	MaxAttempts int
+}
+
+// WebhookEnvelope is the JSON body sent to webhooks
+type WebhookEnvelope struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// WebhookPost is the data of post events
+type WebhookPost struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	AuthorID    int        `json:"author_id"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// WebhookComment is the data of comment events
+type WebhookComment struct {
+	ID         int       `json:"id"`
+	PostID     int       `json:"post_id"`
+	ParentID   *int      `json:"parent_id,omitempty"`
+	UserID     *int      `json:"user_id,omitempty"`
+	AuthorName string    `json:"author_name"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookUser is the data of user events; email addresses are left out
+type WebhookUser struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewWebhookPost converts a post to the data of post events
+func NewWebhookPost(p *post.Post) WebhookPost {
+	return WebhookPost{ID: p.ID, Title: p.Title, Slug: p.Slug, AuthorID: p.AuthorID, PublishedAt: p.PublishedAt}
+}
+
+// NewWebhookComment converts a comment to the data of comment events
+func NewWebhookComment(c *comment.Comment) WebhookComment {
+	return WebhookComment{
+		ID:         c.ID,
+		PostID:     c.PostID,
+		ParentID:   c.ParentID,
+		UserID:     c.UserID,
+		AuthorName: c.AuthorName,
+		Content:    c.Content,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+// NewWebhookUser converts a user to the data of user events
+func NewWebhookUser(u *user.User) WebhookUser {
+	return WebhookUser{ID: u.ID, Name: u.Name, Role: string(u.Role), CreatedAt: u.CreatedAt}
+}
+
+// WebhookService implements the webhook.Service interface, and sends events
+// to webhooks through queued jobs
+type WebhookService struct {
+	repo       webhook.Repository
+	deliveries webhook.DeliveryRepository
+	sender     webhook.Sender
+	jobs       JobQueue
+	audit      AuditLogger
+	settings   WebhookSettings
+	logger     Logger
+}
+
+// NewWebhookService creates a new WebhookService instance
+func NewWebhookService(repo webhook.Repository, deliveries webhook.DeliveryRepository, sender webhook.Sender, jobs JobQueue, audit AuditLogger, settings WebhookSettings, logger Logger) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		deliveries: deliveries,
+		sender:     sender,
+		jobs:       jobs,
+		audit:      audit,
+		settings:   settings,
+		logger:     logger,
+	}
+}
+
+// Create registers a webhook with a new secret
+func (s *WebhookService) Create(ctx context.Context, adminID int, in webhook.Input) (*webhook.Webhook, error) {
+	secret, err := randomWebhookSecret()
+	if err != nil {
+		s.logger.Error(ctx, "failed to generate webhook secret", "error", err.Error())
+		return nil, err
+	}
+	w, err := webhook.NewWebhook(adminID, secret, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, w); err != nil {
+		s.logger.Error(ctx, "failed to save webhook", "adminID", adminID, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionWebhookCreated,
+		UserID:   adminID,
+		Metadata: map[string]any{"webhook_id": w.ID, "url": w.URL, "events": w.Events},
+	})
+	s.logger.Info(ctx, "webhook created", "webhookID", w.ID, "adminID", adminID)
+	return w, nil
+}
+
+// Get retrieves a webhook by its ID
+func (s *WebhookService) Get(ctx context.Context, id int) (*webhook.Webhook, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Update replaces the URL, events and state of a webhook; its secret and
+// pending deliveries are kept
+func (s *WebhookService) Update(ctx context.Context, adminID, id int, in webhook.Input) (*webhook.Webhook, error) {
+	w, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Update(in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, w); err != nil {
+		s.logger.Error(ctx, "failed to update webhook", "webhookID", id, "error", err.Error())
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionWebhookUpdated,
+		UserID:   adminID,
+		Metadata: map[string]any{"webhook_id": w.ID, "url": w.URL, "events": w.Events, "active": w.Active},
+	})
+	s.logger.Info(ctx, "webhook updated", "webhookID", w.ID, "adminID", adminID)
+	return w, nil
+}
+
+// Delete removes a webhook and its delivery log; queued deliveries are
+// dropped
+func (s *WebhookService) Delete(ctx context.Context, adminID, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEvent{
+		Action:   AuditActionWebhookDeleted,
+		UserID:   adminID,
+		Metadata: map[string]any{"webhook_id": id},
+	})
+	s.logger.Info(ctx, "webhook deleted", "webhookID", id, "adminID", adminID)
+	return nil
+}
+
+// List retrieves every webhook, newest first
+func (s *WebhookService) List(ctx context.Context) ([]*webhook.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+// ListDeliveries retrieves a page of the delivery log of a webhook, newest
+// first
+func (s *WebhookService) ListDeliveries(ctx context.Context, id, limit, offset int) ([]*webhook.Delivery, int, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.deliveries.ListDeliveries(ctx, id, limit, offset)
+}
+
+// Subscribe sends the domain events webhooks can subscribe to. Posts are
+// announced once public, whether created published or published later.
+func (s *WebhookService) Subscribe(bus *EventBus) {
+	Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		if e.Post.IsListed() {
+			s.Publish(ctx, webhook.EventPostPublished, NewWebhookPost(e.Post))
+		}
+	})
+	Subscribe(bus, func(ctx context.Context, e PostPublished) {
+		s.Publish(ctx, webhook.EventPostPublished, NewWebhookPost(e.Post))
+	})
+	Subscribe(bus, func(ctx context.Context, e CommentAdded) {
+		s.Publish(ctx, webhook.EventCommentCreated, NewWebhookComment(e.Comment))
+	})
+	Subscribe(bus, func(ctx context.Context, e UserRegistered) {
+		s.Publish(ctx, webhook.EventUserRegistered, NewWebhookUser(e.User))
+	})
+}
+
+// Publish records a delivery of an event to every active webhook
+// subscribed to it and queues them. Failures are logged; the change the
+// event is about is already saved.
+func (s *WebhookService) Publish(ctx context.Context, event string, data any) {
+	hooks, err := s.repo.ListActive(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "failed to list webhooks for event", "event", event, "error", err.Error())
+		return
+	}
+
+	var payload []byte
+	for _, w := range hooks {
+		if !w.Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(WebhookEnvelope{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				s.logger.Error(ctx, "failed to encode webhook payload", "event", event, "error", err.Error())
+				return
+			}
+		}
+
+		d := webhook.NewDelivery(w.ID, event, string(payload))
+		if err := s.deliveries.CreateDelivery(ctx, d); err != nil {
+			s.logger.Error(ctx, "failed to record webhook delivery", "webhookID", w.ID, "event", event, "error", err.Error())
+			continue
+		}
+		if err := s.jobs.Enqueue(ctx, JobWebhookDelivery, WebhookDeliveryPayload{DeliveryID: d.ID}); err != nil {
+			s.logger.Error(ctx, "failed to queue webhook delivery", "webhookID", w.ID, "deliveryID", d.ID, "error", err.Error())
+		}
+	}
+}
+
+// Deliver sends a delivery queued as a JobWebhookDelivery job and records
+// the outcome. Failed attempts fail the job, so it is retried with backoff,
+// until the last attempt marks the delivery failed. Deliveries of deleted
+// webhooks, and those already delivered, are dropped.
+func (s *WebhookService) Deliver(ctx context.Context, payload json.RawMessage) error {
+	var job WebhookDeliveryPayload
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("invalid webhook delivery payload: %w", err)
+	}
+
+	d, err := s.deliveries.GetDelivery(ctx, job.DeliveryID)
+	if errors.Is(err, webhook.ErrDeliveryNotFound) {
+		s.logger.Info(ctx, "webhook delivery dropped", "deliveryID", job.DeliveryID, "reason", "deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if d.Status != webhook.DeliveryPending {
+		return nil
+	}
+	w, err := s.repo.GetByID(ctx, d.WebhookID)
+	if errors.Is(err, webhook.ErrWebhookNotFound) {
+		s.logger.Info(ctx, "webhook delivery dropped", "deliveryID", d.ID, "reason", "webhook deleted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	status, sendErr := s.sender.Send(ctx, w, d)
+	d.Attempts++
+	d.ResponseStatus = status
+	d.Error = ""
+	if sendErr == nil {
+		now := time.Now()
+		d.Status = webhook.DeliveryDelivered
+		d.DeliveredAt = &now
+	} else {
+		d.Error = sendErr.Error()
+		if d.Attempts >= s.settings.MaxAttempts {
+			d.Status = webhook.DeliveryFailed
+		}
+	}
+	if err := s.deliveries.UpdateDelivery(ctx, d); err != nil {
+		s.logger.Error(ctx, "failed to record webhook delivery attempt", "deliveryID", d.ID, "error", err.Error())
+		return err
+	}
+
+	if sendErr != nil {
+		s.logger.Warn(ctx, "webhook delivery failed", "webhookID", w.ID, "deliveryID", d.ID, "event", d.Event, "attempt", d.Attempts, "status", status, "error", sendErr.Error())
+		if d.Status == webhook.DeliveryFailed {
+			return nil
+		}
+		return fmt.Errorf("webhook delivery %d to %s: %w", d.ID, w.URL, sendErr)
+	}
+	s.logger.Info(ctx, "webhook delivered", "webhookID", w.ID, "deliveryID", d.ID, "event", d.Event, "attempt", d.Attempts, "status", status, "duration", time.Since(started).String())
+	return nil
+}
+
+// randomWebhookSecret generates the secret a webhook's payloads are signed
+// with
+func randomWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+var _ webhook.Service = (*WebhookService)(nil)
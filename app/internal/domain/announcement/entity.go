@@ -0,0 +1,148 @@
+package announcement
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Announcement statuses
+const (
+	StatusQueued    = "queued"
+	StatusSending   = "sending"
+	StatusCompleted = "completed"
+)
+
+// Delivery statuses
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+	// DeliverySkipped is recorded for recipients who unsubscribed
+	DeliverySkipped = "skipped"
+)
+
+// Content limits
+const (
+	maxSubjectLength = 200
+	maxBodyLength    = 20000
+)
+
+// Announcement errors
+var (
+	ErrAnnouncementNotFound    = errors.New("announcement not found")
+	ErrInvalidSubject          = errors.New("invalid announcement: subject must be between 1 and 200 characters")
+	ErrInvalidBody             = errors.New("invalid announcement: body must be between 1 and 20000 characters")
+	ErrInvalidSegment          = errors.New("invalid announcement segment: unknown role")
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+)
+
+// Segment selects the recipients of an announcement. The zero value
+// selects every active account.
+type Segment struct {
+	// Roles limits recipients to users with one of the roles
+	Roles []user.Role `json:"roles,omitempty"`
+	// RegisteredBefore limits recipients to accounts created before the time
+	RegisteredBefore *time.Time `json:"registered_before,omitempty"`
+}
+
+// Validate checks that the segment only references known roles
+func (s Segment) Validate() error {
+	for _, role := range s.Roles {
+		if !role.IsValid() {
+			return ErrInvalidSegment
+		}
+	}
+	return nil
+}
+
+// Announcement is an email sent by an administrator to a segment of users.
+// Recipients are counted when it is created; the delivery counters grow as
+// batches are sent.
+type Announcement struct {
+	ID          int        `db:"id"`
+	Subject     string     `db:"subject"`
+	Body        string     `db:"body"`
+	Segment     Segment    `db:"-"`
+	CreatedBy   int        `db:"created_by"`
+	Status      string     `db:"status"`
+	Recipients  int        `db:"recipients"`
+	Sent        int        `db:"sent"`
+	Failed      int        `db:"failed"`
+	Skipped     int        `db:"skipped"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
+
+// NewAnnouncement creates a queued announcement
+func NewAnnouncement(createdBy int, subject, body string, segment Segment) (*Announcement, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" || len(subject) > maxSubjectLength || strings.ContainsAny(subject, "\r\n") {
+		return nil, ErrInvalidSubject
+	}
+	body = strings.TrimSpace(body)
+	if body == "" || len(body) > maxBodyLength {
+		return nil, ErrInvalidBody
+	}
+	if err := segment.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Announcement{
+		Subject:   subject,
+		Body:      body,
+		Segment:   segment,
+		CreatedBy: createdBy,
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Record counts the outcome of a delivery
+func (a *Announcement) Record(status string) {
+	switch status {
+	case DeliverySent:
+		a.Sent++
+	case DeliveryFailed:
+		a.Failed++
+	case DeliverySkipped:
+		a.Skipped++
+	}
+	a.UpdatedAt = time.Now()
+}
+
+// Complete marks every recipient as processed
+func (a *Announcement) Complete() {
+	now := time.Now()
+	a.Status = StatusCompleted
+	a.CompletedAt = &now
+	a.UpdatedAt = now
+}
+
+// IsCompleted checks if every recipient has been processed
+func (a *Announcement) IsCompleted() bool {
+	return a.Status == StatusCompleted
+}
+
+// Recipient is a user an announcement is still to be delivered to
+type Recipient struct {
+	UserID int    `db:"id"`
+	Name   string `db:"name"`
+	Email  string `db:"email"`
+	// Unsubscribed users are skipped
+	Unsubscribed bool `db:"unsubscribed"`
+}
+
+// Delivery records the outcome of sending an announcement to a user
+type Delivery struct {
+	AnnouncementID int       `db:"announcement_id"`
+	UserID         int       `db:"user_id"`
+	Email          string    `db:"email"`
+	Status         string    `db:"status"`
+	Error          string    `db:"error"`
+	CreatedAt      time.Time `db:"created_at"`
+}
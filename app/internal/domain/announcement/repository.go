@@ -0,0 +1,32 @@
+package announcement
+
+import "context"
+
+// Repository defines the interface for announcement data access
+type Repository interface {
+	Create(ctx context.Context, a *Announcement) error
+	GetByID(ctx context.Context, id int) (*Announcement, error)
+	// Update stores the status and delivery counters
+	Update(ctx context.Context, a *Announcement) error
+	// CountRecipients counts the active accounts in a segment
+	CountRecipients(ctx context.Context, segment Segment) (int, error)
+	// PendingRecipients returns up to limit recipients in the segment of the
+	// announcement without a recorded delivery, ordered by user ID
+	PendingRecipients(ctx context.Context, a *Announcement, limit int) ([]*Recipient, error)
+	// RecordDelivery stores a delivery; each user has at most one per
+	// announcement
+	RecordDelivery(ctx context.Context, d *Delivery) error
+	// ListDeliveries returns up to limit deliveries of an announcement with
+	// the given status
+	ListDeliveries(ctx context.Context, announcementID int, status string, limit int) ([]*Delivery, error)
+	// Unsubscribe stops announcements to a user
+	Unsubscribe(ctx context.Context, userID int) error
+}
+
+// UnsubscribeTokens issues the tokens of the unsubscribe links in
+// announcement emails
+type UnsubscribeTokens interface {
+	Issue(userID int) string
+	// Verify returns the user a token was issued for
+	Verify(token string) (int, error)
+}
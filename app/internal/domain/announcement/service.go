@@ -0,0 +1,20 @@
+package announcement
+
+import "context"
+
+// Report summarizes the delivery of an announcement
+type Report struct {
+	Announcement *Announcement
+	// Failures lists the recipients the mailer could not deliver to
+	Failures []*Delivery
+}
+
+// Service defines the interface for announcement business logic
+type Service interface {
+	// Create stores an announcement and queues its delivery
+	Create(ctx context.Context, adminID int, subject, body string, segment Segment) (*Announcement, error)
+	GetReport(ctx context.Context, id int) (*Report, error)
+	// Unsubscribe stops announcements to the user an unsubscribe token was
+	// issued for
+	Unsubscribe(ctx context.Context, token string) error
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"errors"
+	"time"
+)
+
+// Audit errors
+var (
+	ErrInvalidTimeRange = errors.New("invalid audit log filter: from must be before to")
+)
+
+// Entry is a recorded security-relevant action
+type Entry struct {
+	ID     int    `json:"id" db:"id"`
+	Action string `json:"action" db:"action"`
+	// UserID is the account that performed the action or, for actions on
+	// an account such as a purge, the account acted on
+	UserID   int            `json:"user_id" db:"user_id"`
+	Metadata map[string]any `json:"metadata" db:"-"`
+	// IPAddress is the client the action came from, when known
+	IPAddress string `json:"ip_address" db:"ip_address"`
+	// RequestID is the X-Request-ID of the request the action was made in,
+	// when it was made in one
+	RequestID string    `json:"request_id" db:"request_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Filter selects audit log entries; zero fields do not restrict them
+type Filter struct {
+	UserID    int
+	Action    string
+	RequestID string
+	// From and To bound the creation time; From is inclusive, To exclusive
+	From *time.Time
+	To   *time.Time
+}
+
+// Validate checks that the time range is not inverted
+func (f Filter) Validate() error {
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+// Repository defines the interface for audit log persistence
+type Repository interface {
+	Create(ctx context.Context, entry *Entry) error
+	// List returns the entries matching a filter, newest first
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Entry, error)
+}
@@ -0,0 +1,8 @@
+package audit
+
+import "context"
+
+// Service defines the interface for reading the audit log
+type Service interface {
+	ListEntries(ctx context.Context, filter Filter, limit, offset int) ([]*Entry, error)
+}
@@ -2,8 +2,10 @@ package auth
 
 // TokenClaims represents the JWT token claims
 type TokenClaims struct {
+	ID        string `json:"jti"`
 	UserID    int    `json:"user_id"`
 	Email     string `json:"email"`
+	Role      string `json:"role"`
 	IssuedAt  int64  `json:"iat"`
 	ExpiresAt int64  `json:"exp"`
 }
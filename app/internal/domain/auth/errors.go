@@ -24,6 +24,7 @@ var (
 	ErrInvalidDuration  = errors.New("invalid duration")
 	ErrEmptyToken       = errors.New("empty token")
 	ErrTokenExpired     = errors.New("token expired")
+	ErrTokenRevoked     = errors.New("token has been revoked")
 )
 
 // Password validation errors
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Passkey errors
+var (
+	ErrPasskeyNotFound            = errors.New("passkey not found")
+	ErrPasskeyExists              = errors.New("passkey already exists")
+	ErrInvalidPasskeyChallenge    = errors.New("invalid or expired passkey challenge")
+	ErrInvalidPasskeyRegistration = errors.New("invalid passkey registration")
+	ErrInvalidPasskeyAssertion    = errors.New("invalid passkey assertion")
+	ErrInvalidPasskeyName         = errors.New("invalid passkey name: must be at most 100 characters")
+)
+
+// Passkey limits
+const (
+	maxPasskeyNameLength = 100
+	// userHandleSize is the size of the random WebAuthn user handles
+	userHandleSize = 32
+	// DefaultPasskeyName names passkeys registered without a name
+	DefaultPasskeyName = "Passkey"
+)
+
+// Passkey is a WebAuthn credential a user signs in with. The public key is
+// stored in its COSE encoding, as returned by the authenticator.
+type Passkey struct {
+	ID           int    `json:"id" db:"id"`
+	UserID       int    `json:"-" db:"user_id"`
+	CredentialID []byte `json:"-" db:"credential_id"`
+	// UserHandle is the WebAuthn user ID the credential was created for;
+	// all passkeys of a user share it
+	UserHandle []byte     `json:"-" db:"user_handle"`
+	PublicKey  []byte     `json:"-" db:"public_key"`
+	SignCount  uint32     `json:"-" db:"sign_count"`
+	Name       string     `json:"name" db:"name"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// NewPasskey creates a passkey from a verified registration
+func NewPasskey(userID int, userHandle []byte, credential *Credential, name string) (*Passkey, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidUserID
+	}
+	name, err := ValidatePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Passkey{
+		UserID:       userID,
+		CredentialID: credential.ID,
+		UserHandle:   userHandle,
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.SignCount,
+		Name:         name,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// ValidatePasskeyName trims a passkey name, defaulting empty names
+func ValidatePasskeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return DefaultPasskeyName, nil
+	}
+	if len([]rune(name)) > maxPasskeyNameLength {
+		return "", ErrInvalidPasskeyName
+	}
+	return name, nil
+}
+
+// NewUserHandle generates a random WebAuthn user handle. Handles carry no
+// personal data, as authenticators may reveal them.
+func NewUserHandle() ([]byte, error) {
+	b := make([]byte, userHandleSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate user handle: %w", err)
+	}
+	return b, nil
+}
+
+// PasskeyRepository defines the interface for passkey storage
+type PasskeyRepository interface {
+	// Create stores a passkey; it returns ErrPasskeyExists for a credential
+	// ID that is already registered
+	Create(ctx context.Context, passkey *Passkey) error
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*Passkey, error)
+	ListByUser(ctx context.Context, userID int) ([]*Passkey, error)
+	// UpdateUsage stores the signature counter and last use of a passkey
+	UpdateUsage(ctx context.Context, passkey *Passkey) error
+	// Delete removes a passkey of the user
+	Delete(ctx context.Context, userID, id int) error
+}
+
+// PasskeyCeremony names the purpose a WebAuthn challenge was issued for
+type PasskeyCeremony string
+
+// Passkey ceremonies
+const (
+	// PasskeyCeremonySignup creates an account with a passkey
+	PasskeyCeremonySignup PasskeyCeremony = "signup"
+	// PasskeyCeremonyRegistration adds a passkey to a signed-in account
+	PasskeyCeremonyRegistration PasskeyCeremony = "registration"
+	// PasskeyCeremonyLogin signs in with a passkey alone
+	PasskeyCeremonyLogin PasskeyCeremony = "login"
+	// PasskeyCeremonyTwoFactor completes a login with a passkey as second factor
+	PasskeyCeremonyTwoFactor PasskeyCeremony = "two_factor"
+)
+
+// PasskeySession is the server state of a ceremony, kept with its challenge
+// until the client answers
+type PasskeySession struct {
+	Ceremony PasskeyCeremony `json:"ceremony"`
+	// UserID is the account the ceremony is for; zero for signups and
+	// passwordless logins
+	UserID     int    `json:"user_id,omitempty"`
+	UserHandle []byte `json:"user_handle,omitempty"`
+	// PasskeyName names the passkey being registered
+	PasskeyName string `json:"passkey_name,omitempty"`
+	// Name and Email describe the account created by a signup
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// PasskeyChallengeStore keeps ceremony sessions by challenge until they are
+// answered or expire
+type PasskeyChallengeStore interface {
+	Save(ctx context.Context, challenge string, session *PasskeySession, ttl time.Duration) error
+	// Take returns and removes the session of a challenge, so a challenge
+	// can only be answered once. It returns ErrInvalidPasskeyChallenge for
+	// unknown and expired challenges.
+	Take(ctx context.Context, challenge string) (*PasskeySession, error)
+}
+
+// PasskeyService defines the interface for the WebAuthn ceremonies. Begin
+// methods return the options passed to the browser's WebAuthn API; Finish
+// methods verify the credential it returns.
+type PasskeyService interface {
+	// BeginSignup starts creating a passkey-only account
+	BeginSignup(ctx context.Context, name, email string) (*CreationOptions, error)
+	// FinishSignup creates the account and its passkey
+	FinishSignup(ctx context.Context, credential *AttestationResponse) (*user.User, error)
+	// BeginRegistration starts adding a passkey to an account
+	BeginRegistration(ctx context.Context, userID int, name string) (*CreationOptions, error)
+	FinishRegistration(ctx context.Context, userID int, credential *AttestationResponse) (*Passkey, error)
+	// BeginLogin starts a passwordless login with a discoverable passkey
+	BeginLogin(ctx context.Context) (*RequestOptions, error)
+	// FinishLogin verifies a login assertion and returns the user ID
+	FinishLogin(ctx context.Context, credential *AssertionResponse) (int, error)
+	// BeginTwoFactor starts the second login step of a user with passkeys
+	BeginTwoFactor(ctx context.Context, userID int) (*RequestOptions, error)
+	// FinishTwoFactor verifies a second-factor assertion and returns the
+	// user ID
+	FinishTwoFactor(ctx context.Context, credential *AssertionResponse) (int, error)
+	HasPasskeys(ctx context.Context, userID int) (bool, error)
+	ListPasskeys(ctx context.Context, userID int) ([]*Passkey, error)
+	DeletePasskey(ctx context.Context, userID, id int) error
+}
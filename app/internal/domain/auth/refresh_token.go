@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Refresh token errors
+var (
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token already revoked")
+)
+
+// TokenPair is the set of credentials handed to a client after authentication
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn is the access token lifetime
+	ExpiresIn time.Duration
+}
+
+// RefreshToken represents a long-lived, single-use credential that can be
+// exchanged for a new token pair. Only the hash of the token is stored.
+type RefreshToken struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// NewRefreshToken creates a refresh token for the user and returns it together
+// with the plain token value
+func NewRefreshToken(userID int, ttl time.Duration) (*RefreshToken, string, error) {
+	if userID <= 0 {
+		return nil, "", ErrInvalidUserID
+	}
+	if ttl <= 0 {
+		return nil, "", ErrInvalidDuration
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", ErrTokenGeneration
+	}
+	token := hex.EncodeToString(b)
+
+	now := time.Now()
+	return &RefreshToken{
+		UserID:    userID,
+		TokenHash: HashRefreshToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// IsExpired checks if the refresh token lifetime has passed
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsRevoked checks if the refresh token has been used or revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// HashRefreshToken returns the hex-encoded SHA-256 hash of a refresh token
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshTokenRepository defines the interface for refresh token storage
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// Revoke marks a token as revoked. It returns ErrRefreshTokenRevoked if
+	// the token was already revoked, so concurrent rotations cannot both win.
+	Revoke(ctx context.Context, id int) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"blog-platform/internal/domain/sso"
 	"blog-platform/internal/domain/user"
 )
 
@@ -18,14 +19,48 @@ type PasswordValidator interface {
 type AuthService interface {
 	GenerateToken(ctx context.Context, user *user.User) (string, error)
 	ValidateToken(ctx context.Context, token string) (*TokenClaims, error)
-	Login(ctx context.Context, email, password string) (*user.User, string, error)
-	Register(ctx context.Context, name, email, password string) (*user.User, string, error)
-	RefreshToken(ctx context.Context, token string) (string, error)
+	Login(ctx context.Context, email, password string) (*user.User, *TokenPair, error)
+	Register(ctx context.Context, name, email, password string) (*user.User, *TokenPair, error)
+	// LoginWithIdentity signs in with an account at an external identity provider
+	LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, *TokenPair, error)
+	// LoginWithMagicLink signs in with the token of an emailed login link
+	LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, *TokenPair, error)
+	// LoginWithSSO signs in with the identity provider's response to a
+	// sign-in started for an organization
+	LoginWithSSO(ctx context.Context, organization, state string, response sso.Response) (*user.User, *TokenPair, error)
+	// RefreshToken exchanges a refresh token for a new pair; the old refresh token is revoked
+	RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error)
+	// Logout blacklists the access token and revokes the refresh token; either may be empty
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	// LoginTwoFactor completes a login that returned a *TwoFactorChallenge
+	LoginTwoFactor(ctx context.Context, challengeToken, code string) (*user.User, *TokenPair, error)
+	// BeginPasskeyTwoFactor starts answering a *TwoFactorChallenge with a passkey
+	BeginPasskeyTwoFactor(ctx context.Context, challengeToken string) (*RequestOptions, error)
+	// LoginTwoFactorWithPasskey completes a login with a passkey as second factor
+	LoginTwoFactorWithPasskey(ctx context.Context, credential *AssertionResponse) (*user.User, *TokenPair, error)
+	// SignupWithPasskey creates a passkey-only account
+	SignupWithPasskey(ctx context.Context, credential *AttestationResponse) (*user.User, *TokenPair, error)
+	// LoginWithPasskey signs in with a passkey alone
+	LoginWithPasskey(ctx context.Context, credential *AssertionResponse) (*user.User, *TokenPair, error)
+	// EnableTwoFactor starts a TOTP enrollment; it takes effect once confirmed
+	EnableTwoFactor(ctx context.Context, userID int) (*TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID int, code string) error
+	DisableTwoFactor(ctx context.Context, userID int, code string) error
 }
 
 // TokenService defines the interface for JWT token operations
 type TokenService interface {
-	GenerateToken(userID int, email string, duration time.Duration) (string, error)
+	GenerateToken(userID int, email, role string, duration time.Duration) (string, error)
 	ValidateToken(token string) (*TokenClaims, error)
 	RefreshToken(token string) (string, error)
+	// GenerateChallengeToken issues a token that only proves the password
+	// step of a two-factor login; it is not accepted as an access token
+	GenerateChallengeToken(userID int, duration time.Duration) (string, error)
+	ValidateChallengeToken(token string) (int, error)
+}
+
+// TokenBlacklist stores revoked access tokens by token ID until they expire
+type TokenBlacklist interface {
+	Add(ctx context.Context, tokenID string, expiresAt time.Time) error
+	Contains(ctx context.Context, tokenID string) (bool, error)
 }
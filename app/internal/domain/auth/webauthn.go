@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// WebAuthn (https://www.w3.org/TR/webauthn-2/) parameters
+const (
+	challengeSize = 32
+	// minRSAKeyBits rejects weak RSA credentials
+	minRSAKeyBits = 2048
+)
+
+// supportedAlgorithms are the COSE algorithms of the signatures passkeys may
+// use, in order of preference
+var supportedAlgorithms = []webauthncose.COSEAlgorithmIdentifier{
+	webauthncose.AlgES256,
+	webauthncose.AlgEdDSA,
+	webauthncose.AlgRS256,
+}
+
+// Base64URL is binary data encoded as unpadded base64url in JSON, the
+// encoding the WebAuthn JSON serialization uses
+type Base64URL []byte
+
+// MarshalJSON encodes the data as an unpadded base64url string
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a base64url string, with or without padding
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return fmt.Errorf("invalid base64url data: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// RelyingParty is the site credentials are scoped to
+type RelyingParty struct {
+	// ID is the domain credentials are bound to, e.g. "example.com"
+	ID   string
+	Name string
+	// Origins are the origins ceremonies may run on, e.g.
+	// "https://example.com"
+	Origins []string
+}
+
+// RelyingPartyEntity identifies the site in creation options
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity identifies the account in creation options
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+// CredentialParameter is a credential type and algorithm the site accepts
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CredentialDescriptor refers to a registered credential
+type CredentialDescriptor struct {
+	Type string    `json:"type"`
+	ID   Base64URL `json:"id"`
+}
+
+// AuthenticatorSelection states the authenticator features a registration
+// requires
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions are the PublicKeyCredentialCreationOptions of a
+// registration, in the JSON form accepted by
+// PublicKeyCredential.parseCreationOptionsFromJSON
+type CreationOptions struct {
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              Base64URL              `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are the PublicKeyCredentialRequestOptions of a login, in
+// the JSON form accepted by PublicKeyCredential.parseRequestOptionsFromJSON
+type RequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// AttestationResponse is the JSON form of the PublicKeyCredential returned
+// by navigator.credentials.create()
+type AttestationResponse struct {
+	ID       string    `json:"id"`
+	RawID    Base64URL `json:"rawId"`
+	Type     string    `json:"type"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AttestationObject Base64URL `json:"attestationObject"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON form of the PublicKeyCredential returned by
+// navigator.credentials.get()
+type AssertionResponse struct {
+	ID       string    `json:"id"`
+	RawID    Base64URL `json:"rawId"`
+	Type     string    `json:"type"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AuthenticatorData Base64URL `json:"authenticatorData"`
+		Signature         Base64URL `json:"signature"`
+		UserHandle        Base64URL `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// Credential is a public key credential created by a registration
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE-encoded public key
+	PublicKey []byte
+	SignCount uint32
+}
+
+// NewChallenge generates a random challenge and returns it with the key
+// its session is stored under
+func NewChallenge() ([]byte, string, error) {
+	b := make([]byte, challengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	return b, base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CreationOptions builds the options of a registration. Passkeys must be
+// discoverable and verify the user; attestation is not requested.
+func (rp *RelyingParty) CreationOptions(challenge, userHandle []byte, name, displayName string, exclude []*Passkey, timeout time.Duration) *CreationOptions {
+	params := make([]CredentialParameter, len(supportedAlgorithms))
+	for i, alg := range supportedAlgorithms {
+		params[i] = CredentialParameter{Type: string(protocol.PublicKeyCredentialType), Alg: int(alg)}
+	}
+	return &CreationOptions{
+		RP:                 RelyingPartyEntity{ID: rp.ID, Name: rp.Name},
+		User:               UserEntity{ID: userHandle, Name: name, DisplayName: displayName},
+		Challenge:          challenge,
+		PubKeyCredParams:   params,
+		Timeout:            timeout.Milliseconds(),
+		ExcludeCredentials: descriptors(exclude),
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions builds the options of a login. Without allowed passkeys
+// the browser offers the discoverable passkeys of the site.
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow []*Passkey, userVerification string, timeout time.Duration) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          timeout.Milliseconds(),
+		RPID:             rp.ID,
+		AllowCredentials: descriptors(allow),
+		UserVerification: userVerification,
+	}
+}
+
+// descriptors refers to passkeys in ceremony options
+func descriptors(passkeys []*Passkey) []CredentialDescriptor {
+	result := make([]CredentialDescriptor, 0, len(passkeys))
+	for _, p := range passkeys {
+		result = append(result, CredentialDescriptor{Type: "public-key", ID: p.CredentialID})
+	}
+	return result
+}
+
+// Challenge returns the challenge the registration answers
+func (r *AttestationResponse) Challenge() (string, error) {
+	data, err := parseClientData(r.Response.ClientDataJSON, protocol.CreateCeremony)
+	if err != nil {
+		return "", ErrInvalidPasskeyRegistration
+	}
+	return data.Challenge, nil
+}
+
+// Challenge returns the challenge the assertion answers
+func (r *AssertionResponse) Challenge() (string, error) {
+	data, err := parseClientData(r.Response.ClientDataJSON, protocol.AssertCeremony)
+	if err != nil {
+		return "", ErrInvalidPasskeyAssertion
+	}
+	return data.Challenge, nil
+}
+
+// VerifyAttestation verifies a registration answering challenge and returns
+// the new credential. Attestation is not requested, so authenticators
+// answer with the "none" format; other formats are checked without trust
+// anchors, as the site accepts any authenticator.
+func (rp *RelyingParty) VerifyAttestation(r *AttestationResponse, challenge string, requireUserVerification bool) (*Credential, error) {
+	response := protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: r.ID, Type: r.Type},
+			RawID:      protocol.URLEncodedBase64(r.RawID),
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: protocol.URLEncodedBase64(r.Response.ClientDataJSON)},
+			AttestationObject:     protocol.URLEncodedBase64(r.Response.AttestationObject),
+		},
+	}
+	parsed, err := response.Parse()
+	if err != nil {
+		return nil, ErrInvalidPasskeyRegistration
+	}
+	params := make([]protocol.CredentialParameter, len(supportedAlgorithms))
+	for i, alg := range supportedAlgorithms {
+		params[i] = protocol.CredentialParameter{Type: protocol.PublicKeyCredentialType, Algorithm: alg}
+	}
+	if _, err := parsed.Verify(challenge, requireUserVerification, true, rp.ID, rp.Origins, nil, protocol.TopOriginIgnoreVerificationMode, nil, params); err != nil {
+		return nil, ErrInvalidPasskeyRegistration
+	}
+
+	authData := parsed.Response.AttestationObject.AuthData
+	credential := authData.AttData
+	if len(r.RawID) > 0 && !bytes.Equal(r.RawID, credential.CredentialID) {
+		return nil, ErrInvalidPasskeyRegistration
+	}
+	key, err := webauthncose.ParsePublicKey(credential.CredentialPublicKey)
+	if err != nil {
+		return nil, ErrInvalidPasskeyRegistration
+	}
+	if rsaKey, ok := key.(webauthncose.RSAPublicKeyData); ok && len(rsaKey.Modulus)*8 < minRSAKeyBits {
+		return nil, ErrInvalidPasskeyRegistration
+	}
+
+	return &Credential{
+		ID:        credential.CredentialID,
+		PublicKey: credential.CredentialPublicKey,
+		SignCount: authData.Counter,
+	}, nil
+}
+
+// VerifyAssertion verifies a login with a passkey answering challenge and
+// returns the new signature counter. An assertion whose counter did not
+// increase is rejected, as it comes from a cloned authenticator; counters
+// that stay zero mean the authenticator does not count.
+func (rp *RelyingParty) VerifyAssertion(r *AssertionResponse, challenge string, passkey *Passkey, requireUserVerification bool) (uint32, error) {
+	if !bytes.Equal(r.RawID, passkey.CredentialID) {
+		return 0, ErrInvalidPasskeyAssertion
+	}
+	if len(r.Response.UserHandle) > 0 && !bytes.Equal(r.Response.UserHandle, passkey.UserHandle) {
+		return 0, ErrInvalidPasskeyAssertion
+	}
+
+	response := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: r.ID, Type: r.Type},
+			RawID:      protocol.URLEncodedBase64(r.RawID),
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: protocol.URLEncodedBase64(r.Response.ClientDataJSON)},
+			AuthenticatorData:     protocol.URLEncodedBase64(r.Response.AuthenticatorData),
+			Signature:             protocol.URLEncodedBase64(r.Response.Signature),
+			UserHandle:            protocol.URLEncodedBase64(r.Response.UserHandle),
+		},
+	}
+	parsed, err := response.Parse()
+	if err != nil {
+		return 0, ErrInvalidPasskeyAssertion
+	}
+	if err := parsed.Verify(challenge, rp.ID, rp.Origins, nil, protocol.TopOriginIgnoreVerificationMode, "", requireUserVerification, true, passkey.PublicKey); err != nil {
+		return 0, ErrInvalidPasskeyAssertion
+	}
+
+	signCount := parsed.Response.AuthenticatorData.Counter
+	if (signCount != 0 || passkey.SignCount != 0) && signCount <= passkey.SignCount {
+		return 0, ErrInvalidPasskeyAssertion
+	}
+	return signCount, nil
+}
+
+// parseClientData decodes client data of the given ceremony type
+func parseClientData(raw []byte, ceremonyType protocol.CeremonyType) (*protocol.CollectedClientData, error) {
+	var data protocol.CollectedClientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	if data.Type != ceremonyType || data.Challenge == "" {
+		return nil, fmt.Errorf("unexpected client data type %q", data.Type)
+	}
+	return &data, nil
+}
@@ -0,0 +1,109 @@
+package banner
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Severity tells clients how prominently to show a banner
+type Severity string
+
+// Available severities
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// maxMessageLength bounds the message of a banner
+const maxMessageLength = 500
+
+// Banner errors
+var (
+	ErrBannerNotFound  = errors.New("banner not found")
+	ErrInvalidMessage  = errors.New("invalid banner: message must be between 1 and 500 characters")
+	ErrInvalidSeverity = errors.New("invalid banner severity: must be info, warning or critical")
+	ErrInvalidSchedule = errors.New("invalid banner schedule: ends_at must be after starts_at")
+	ErrNotDismissible  = errors.New("invalid dismissal: the banner cannot be dismissed")
+)
+
+// IsValid checks if the severity is known
+func (s Severity) IsValid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+// Banner is a site-wide message shown to every reader between its start
+// and end time
+type Banner struct {
+	ID       int       `db:"id"`
+	Message  string    `db:"message"`
+	Severity Severity  `db:"severity"`
+	StartsAt time.Time `db:"starts_at"`
+	// EndsAt is nil for banners shown until they are deleted
+	EndsAt *time.Time `db:"ends_at"`
+	// Dismissible banners can be closed by signed-in users, after which
+	// they are no longer shown to them
+	Dismissible bool `db:"dismissible"`
+	// CreatedBy is zero once the admin who created the banner is deleted
+	CreatedBy int       `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Input holds the fields admins set on a banner; a nil StartsAt starts the
+// banner right away
+type Input struct {
+	Message     string
+	Severity    Severity
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Dismissible bool
+}
+
+// NewBanner creates a banner
+func NewBanner(createdBy int, in Input) (*Banner, error) {
+	b := &Banner{CreatedBy: createdBy, CreatedAt: time.Now()}
+	if err := b.Update(in); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Update replaces the fields of the banner
+func (b *Banner) Update(in Input) error {
+	message := strings.TrimSpace(in.Message)
+	if message == "" || utf8.RuneCountInString(message) > maxMessageLength {
+		return ErrInvalidMessage
+	}
+	if !in.Severity.IsValid() {
+		return ErrInvalidSeverity
+	}
+
+	now := time.Now()
+	startsAt := now
+	if in.StartsAt != nil {
+		startsAt = *in.StartsAt
+	}
+	if in.EndsAt != nil && !in.EndsAt.After(startsAt) {
+		return ErrInvalidSchedule
+	}
+
+	b.Message = message
+	b.Severity = in.Severity
+	b.StartsAt = startsAt
+	b.EndsAt = in.EndsAt
+	b.Dismissible = in.Dismissible
+	b.UpdatedAt = now
+	return nil
+}
+
+// IsActive checks if the banner is shown at the given time
+func (b *Banner) IsActive(now time.Time) bool {
+	return !now.Before(b.StartsAt) && (b.EndsAt == nil || now.Before(*b.EndsAt))
+}
@@ -0,0 +1,23 @@
+package banner
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for banner data access
+type Repository interface {
+	Create(ctx context.Context, banner *Banner) error
+	GetByID(ctx context.Context, id int) (*Banner, error)
+	// List returns every banner, newest first
+	List(ctx context.Context) ([]*Banner, error)
+	Update(ctx context.Context, banner *Banner) error
+	Delete(ctx context.Context, id int) error
+	// ListActive returns the banners shown at the given time, most severe
+	// and then newest first, leaving out those the user dismissed; userID
+	// is zero for guests
+	ListActive(ctx context.Context, now time.Time, userID int) ([]*Banner, error)
+	// Dismiss records that a user closed a banner; dismissing a banner
+	// again is not an error
+	Dismiss(ctx context.Context, bannerID, userID int) error
+}
@@ -0,0 +1,18 @@
+package banner
+
+import "context"
+
+// Service defines the interface for banner business logic
+type Service interface {
+	// Create stores a banner; adminID is the admin creating it
+	Create(ctx context.Context, adminID int, in Input) (*Banner, error)
+	Update(ctx context.Context, adminID, id int, in Input) (*Banner, error)
+	Delete(ctx context.Context, adminID, id int) error
+	// List returns every banner, including scheduled and ended ones
+	List(ctx context.Context) ([]*Banner, error)
+	// ListActive returns the banners to show to a user, or to guests when
+	// userID is zero
+	ListActive(ctx context.Context, userID int) ([]*Banner, error)
+	// Dismiss stops showing a dismissible banner to a user
+	Dismiss(ctx context.Context, id, userID int) error
+}
@@ -0,0 +1,204 @@
+// Package billing models the plans users subscribe to and the limits each
+// plan sets. Payments are handled by Stripe; the platform learns about
+// subscriptions from its webhook events.
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Plan is a subscription tier
+type Plan string
+
+// Plans
+const (
+	PlanFree Plan = "free"
+	PlanPro  Plan = "pro"
+	PlanOrg  Plan = "org"
+)
+
+// IsValid checks if the plan is known
+func (p Plan) IsValid() bool {
+	_, ok := planLimits[p]
+	return ok
+}
+
+// IsPaid checks if the plan is a paid one; subscribers of paid plans may
+// read subscriber-only posts
+func (p Plan) IsPaid() bool {
+	return p.IsValid() && p != PlanFree
+}
+
+// Status is the state of a subscription, as reported by Stripe
+type Status string
+
+// Subscription statuses
+const (
+	StatusIncomplete        Status = "incomplete"
+	StatusIncompleteExpired Status = "incomplete_expired"
+	StatusTrialing          Status = "trialing"
+	StatusActive            Status = "active"
+	StatusPastDue           Status = "past_due"
+	StatusCanceled          Status = "canceled"
+	StatusUnpaid            Status = "unpaid"
+	StatusPaused            Status = "paused"
+)
+
+// Grants checks if a subscription with the status gets the features of its
+// plan. Past due subscriptions keep them while Stripe retries the payment.
+func (s Status) Grants() bool {
+	return s == StatusActive || s == StatusTrialing || s == StatusPastDue
+}
+
+// Limits are what a plan allows; zero numbers mean no limit
+type Limits struct {
+	// MaxPosts caps the posts of an author, drafts included
+	MaxPosts int `json:"max_posts"`
+	// UploadQuota caps the bytes an author may store in uploads
+	UploadQuota int64 `json:"upload_quota"`
+	// CustomDomains allows serving the blog on the author's own domain
+	CustomDomains bool `json:"custom_domains"`
+}
+
+const (
+	megabyte = int64(1) << 20
+	gigabyte = int64(1) << 30
+)
+
+var planLimits = map[Plan]Limits{
+	PlanFree: {MaxPosts: 10, UploadQuota: 100 * megabyte},
+	PlanPro:  {UploadQuota: 10 * gigabyte, CustomDomains: true},
+	PlanOrg:  {UploadQuota: 100 * gigabyte, CustomDomains: true},
+}
+
+// LimitsOf returns the limits of a plan; unknown plans get the free limits
+func LimitsOf(p Plan) Limits {
+	if limits, ok := planLimits[p]; ok {
+		return limits
+	}
+	return planLimits[PlanFree]
+}
+
+// Billing errors
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrInvalidEvent         = errors.New("invalid subscription event")
+	// ErrUnknownCustomer is returned for events that name neither a user nor
+	// a customer linked to one; Stripe retries them
+	ErrUnknownCustomer = errors.New("invalid subscription event: customer is not linked to a user")
+	// ErrPlanLimit is wrapped by the errors of the plan gates
+	ErrPlanLimit = errors.New("plan limit reached")
+)
+
+// Plan gate errors
+var (
+	ErrUploadQuotaExceeded     = fmt.Errorf("%w: the upload quota of the plan is used up", ErrPlanLimit)
+	ErrCustomDomainUnavailable = fmt.Errorf("%w: custom domains need a paid plan", ErrPlanLimit)
+	ErrPaidPlanRequired        = fmt.Errorf("%w: subscriber-only posts need a paid plan", ErrPlanLimit)
+)
+
+// PostLimitError returns the error for an author at the post limit of a plan
+func PostLimitError(p Plan, limit int) error {
+	return fmt.Errorf("%w: the %s plan allows %d posts", ErrPlanLimit, p, limit)
+}
+
+// Subscription is the plan a user pays for. Users without a subscription
+// are on the free plan.
+type Subscription struct {
+	UserID               int        `db:"user_id"`
+	Plan                 Plan       `db:"plan"`
+	Status               Status     `db:"status"`
+	StripeCustomerID     string     `db:"stripe_customer_id"`
+	StripeSubscriptionID string     `db:"stripe_subscription_id"`
+	CurrentPeriodEnd     *time.Time `db:"current_period_end"`
+	CancelAtPeriodEnd    bool       `db:"cancel_at_period_end"`
+	// LastEventAt is when the latest applied Stripe event happened, so
+	// events delivered out of order do not undo newer ones
+	LastEventAt time.Time `db:"last_event_at"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// EffectivePlan returns the plan whose features the user gets: the
+// subscribed plan while the subscription grants it, the free plan otherwise
+func (s *Subscription) EffectivePlan() Plan {
+	if s == nil || !s.Status.Grants() || !s.Plan.IsValid() {
+		return PlanFree
+	}
+	return s.Plan
+}
+
+// SubscriptionEvent is a change of a subscription reported by Stripe
+type SubscriptionEvent struct {
+	EventID    string
+	OccurredAt time.Time
+	// UserID is set when the subscription was created with the user's ID
+	// in its metadata
+	UserID               int
+	StripeCustomerID     string
+	StripeSubscriptionID string
+	Plan                 Plan
+	Status               Status
+	CurrentPeriodEnd     *time.Time
+	CancelAtPeriodEnd    bool
+}
+
+// Usage is what a user currently uses of the plan limits
+type Usage struct {
+	Posts int `json:"posts"`
+}
+
+// Overview is a user's subscription with the limits of the effective plan
+type Overview struct {
+	// Subscription is nil for users who never subscribed
+	Subscription *Subscription
+	Plan         Plan
+	Limits       Limits
+	Usage        Usage
+}
+
+// Repository stores subscriptions
+type Repository interface {
+	GetByUserID(ctx context.Context, userID int) (*Subscription, error)
+	GetByStripeCustomerID(ctx context.Context, customerID string) (*Subscription, error)
+	// Save creates or replaces the subscription of a user
+	Save(ctx context.Context, s *Subscription) error
+	// CountPosts counts the posts of an author, drafts included
+	CountPosts(ctx context.Context, userID int) (int, error)
+}
+
+// Gate checks actions against the plan of the user taking them. Gates
+// return errors wrapping ErrPlanLimit.
+type Gate interface {
+	// CheckPostLimit checks that the author may create another post
+	CheckPostLimit(ctx context.Context, userID int) error
+	// CheckUploadQuota checks that an upload of size bytes fits the quota
+	// of the user, who stores used bytes already
+	CheckUploadQuota(ctx context.Context, userID int, used, size int64) error
+	// CheckCustomDomain checks that the user may serve a custom domain
+	CheckCustomDomain(ctx context.Context, userID int) error
+	// CheckPaidPlan checks that the user is on a paid plan, which entitles
+	// them to subscriber-only posts
+	CheckPaidPlan(ctx context.Context, userID int) error
+}
+
+// WebhookVerifier authenticates and decodes the webhook events of the
+// payment provider
+type WebhookVerifier interface {
+	// ParseEvent verifies the signature of a payload and decodes it. Events
+	// that do not change subscriptions decode to nil.
+	ParseEvent(payload []byte, signature string) (*SubscriptionEvent, error)
+}
+
+// Service defines the billing use cases
+type Service interface {
+	Gate
+	// GetOverview returns the subscription, plan limits and usage of a user
+	GetOverview(ctx context.Context, userID int) (*Overview, error)
+	// ApplyEvent records a subscription change reported by Stripe
+	ApplyEvent(ctx context.Context, event *SubscriptionEvent) error
+}
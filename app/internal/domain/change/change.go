@@ -0,0 +1,141 @@
+// Package change describes the posts and comments changed since a
+// checkpoint, so offline clients sync without fetching everything again
+package change
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+)
+
+// ErrInvalidCheckpoint is returned for checkpoints that were not issued by
+// Encode
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// Kind names what a tombstone stands for
+type Kind string
+
+const (
+	KindPost    Kind = "post"
+	KindComment Kind = "comment"
+)
+
+// Tombstone records the deletion of a post or comment, since the row
+// itself is gone
+type Tombstone struct {
+	ID        int       `db:"id"`
+	Kind      Kind      `db:"kind"`
+	EntityID  int       `db:"entity_id"`
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+// CommentChange is a comment with when it last changed
+type CommentChange struct {
+	comment.Comment
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Position is the time and ID of the last change read of one kind; changes
+// are read in (time, ID) order
+type Position struct {
+	At time.Time
+	ID int
+}
+
+// Checkpoint marks how far a client has synced. Posts, comments and
+// deletions are read separately, so each keeps its own position.
+type Checkpoint struct {
+	Posts     Position
+	Comments  Position
+	Deletions Position
+}
+
+// Since returns the checkpoint taking in every change made at or after t
+func Since(t time.Time) Checkpoint {
+	p := Position{At: t}
+	return Checkpoint{Posts: p, Comments: p, Deletions: p}
+}
+
+// Start returns the checkpoint of a client that never synced
+func Start() Checkpoint {
+	return Since(time.Unix(0, 0).UTC())
+}
+
+// Encode returns the checkpoint as an opaque, URL safe string
+func (c Checkpoint) Encode() string {
+	parts := make([]string, 3)
+	for i, p := range []Position{c.Posts, c.Comments, c.Deletions} {
+		parts[i] = strconv.FormatInt(p.At.UnixNano(), 10) + ":" + strconv.Itoa(p.ID)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ",")))
+}
+
+// DecodeCheckpoint parses a checkpoint returned by Encode
+func DecodeCheckpoint(s string) (Checkpoint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Checkpoint{}, ErrInvalidCheckpoint
+	}
+	parts := strings.Split(string(raw), ",")
+	if len(parts) != 3 {
+		return Checkpoint{}, ErrInvalidCheckpoint
+	}
+
+	positions := make([]Position, 3)
+	for i, part := range parts {
+		nanos, id, ok := strings.Cut(part, ":")
+		if !ok {
+			return Checkpoint{}, ErrInvalidCheckpoint
+		}
+		at, err := strconv.ParseInt(nanos, 10, 64)
+		if err != nil {
+			return Checkpoint{}, ErrInvalidCheckpoint
+		}
+		itemID, err := strconv.Atoi(id)
+		if err != nil || itemID < 0 {
+			return Checkpoint{}, ErrInvalidCheckpoint
+		}
+		positions[i] = Position{At: time.Unix(0, at).UTC(), ID: itemID}
+	}
+	return Checkpoint{Posts: positions[0], Comments: positions[1], Deletions: positions[2]}, nil
+}
+
+// Set is a page of the changes after a checkpoint
+type Set struct {
+	// Posts holds the published posts created or updated
+	Posts []*post.Post
+	// Comments holds the approved comments created or updated
+	Comments []*comment.Comment
+	// DeletedPosts holds the IDs of deleted posts
+	DeletedPosts []int
+	// DeletedComments holds the IDs of comments deleted, or no longer
+	// approved, e.g. hidden after reports
+	DeletedComments []int
+	// Checkpoint is where the next sync resumes
+	Checkpoint Checkpoint
+	// HasMore is set when changes remain after the checkpoint
+	HasMore bool
+}
+
+// Repository defines the interface for reading changes. Each method returns
+// the changes after the position in (time, ID) order.
+type Repository interface {
+	// ListPosts returns the published posts changed after the position
+	ListPosts(ctx context.Context, after Position, limit int) ([]*post.Post, error)
+	// ListComments returns the comments changed after the position,
+	// whatever their status
+	ListComments(ctx context.Context, after Position, limit int) ([]*CommentChange, error)
+	ListTombstones(ctx context.Context, after Position, limit int) ([]*Tombstone, error)
+}
+
+// Service defines the interface for syncing clients
+type Service interface {
+	// Changes returns up to limit changes of each kind after the checkpoint
+	Changes(ctx context.Context, since Checkpoint, limit int) (*Set, error)
+}
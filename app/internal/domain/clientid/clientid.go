@@ -0,0 +1,46 @@
+// Package clientid validates the IDs offline clients generate for the posts
+// and comments they create, so retried writes are recognized and new
+// resources can be referenced before the server assigns their IDs
+package clientid
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for client IDs that are not UUIDs
+var ErrInvalid = errors.New("invalid client ID: must be a UUID like 123e4567-e89b-12d3-a456-426614174000")
+
+// Normalize checks that s is a UUID in its canonical 8-4-4-4-12 hex form
+// and returns it lowercased, so the same ID is always stored the same way
+func Normalize(s string) (string, error) {
+	if !Valid(s) {
+		return "", ErrInvalid
+	}
+	return strings.ToLower(s), nil
+}
+
+// Valid reports whether s is a UUID in its canonical form, in either case
+func Valid(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !isHex(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -4,18 +4,35 @@ import (
 	"errors"
 	"strings"
 	"time"
+
+	"blog-platform/internal/domain/user"
 )
 
 // Comment represents a comment entity in the domain
 type Comment struct {
-	ID         int       `json:"id" db:"id"`
-	PostID     int       `json:"post_id" db:"post_id"`
-	AuthorName string    `json:"author_name" db:"author_name"`
-	Content    string    `json:"content" db:"content"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ID int `json:"id" db:"id"`
+	// ClientID is the UUID an offline client created the comment under;
+	// nil for comments created without one
+	ClientID *string `json:"client_id,omitempty" db:"client_id"`
+	PostID   int     `json:"post_id" db:"post_id"`
+	// ParentID is the comment this one replies to; nil for top-level comments
+	ParentID *int `json:"parent_id,omitempty" db:"parent_id"`
+	// RootID is the top-level comment of the thread; nil for top-level comments
+	RootID *int `json:"-" db:"root_id"`
+	// Depth is the nesting level, 0 for top-level comments
+	Depth int `json:"-" db:"depth"`
+	// UserID is the registered user who wrote the comment; nil for guest
+	// comments
+	UserID     *int   `json:"user_id,omitempty" db:"user_id"`
+	AuthorName string `json:"author_name" db:"author_name"`
+	Content    string `json:"content" db:"content"`
+	// Status is the moderation state; only approved comments are public
+	Status    Status    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
-// NewComment creates a new comment instance
+// NewComment creates a new comment instance, approved right away; the
+// service holds it for moderation when moderation is required
 func NewComment(postID int, authorName, content string) (*Comment, error) {
 	// Validate post ID
 	if postID <= 0 {
@@ -26,6 +43,7 @@ func NewComment(postID int, authorName, content string) (*Comment, error) {
 		PostID:     postID,
 		AuthorName: strings.TrimSpace(authorName),
 		Content:    strings.TrimSpace(content),
+		Status:     StatusApproved,
 		CreatedAt:  time.Now(),
 	}, nil
 }
@@ -41,6 +59,26 @@ func (c *Comment) IsAuthor(authorName string) bool {
 	return c.AuthorName == authorName
 }
 
+// PostedBy links the comment to the registered user writing it; zero user
+// IDs leave it a guest comment
+func (c *Comment) PostedBy(userID int) {
+	if userID > 0 {
+		c.UserID = &userID
+	}
+}
+
+// IsPostedBy checks if the comment was written by the given registered user
+func (c *Comment) IsPostedBy(userID int) bool {
+	return c.UserID != nil && *c.UserID == userID
+}
+
+// CanModify checks if the given user may edit or delete the comment: the
+// registered user who wrote it always can, admins can modify any comment.
+// Author names prove nothing, so guest comments are left to admins.
+func (c *Comment) CanModify(userID int, role user.Role) bool {
+	return c.IsPostedBy(userID) || role.IsAdmin()
+}
+
 // BelongsToPost checks if the comment belongs to the specified post
 func (c *Comment) BelongsToPost(postID int) bool {
 	return c.PostID == postID
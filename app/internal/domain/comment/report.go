@@ -0,0 +1,110 @@
+package comment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportReason is why a comment was reported
+type ReportReason string
+
+// Available report reasons
+const (
+	ReasonSpam     ReportReason = "spam"
+	ReasonAbuse    ReportReason = "abuse"
+	ReasonOffTopic ReportReason = "off_topic"
+	ReasonOther    ReportReason = "other"
+)
+
+// MaxReportDetailsLength bounds the free text of a report
+const MaxReportDetailsLength = 500
+
+var (
+	// ErrInvalidReportReason is returned for unknown report reasons
+	ErrInvalidReportReason = errors.New("invalid report reason: must be spam, abuse, off_topic or other")
+	// ErrReportDetailsTooLong is returned for report details over
+	// MaxReportDetailsLength
+	ErrReportDetailsTooLong = errors.New("report details cannot exceed 500 characters")
+)
+
+// IsValid checks if the reason is known
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReasonSpam, ReasonAbuse, ReasonOffTopic, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Report is an abuse flag raised on a comment by a reader
+type Report struct {
+	ID        int `json:"id" db:"id"`
+	CommentID int `json:"comment_id" db:"comment_id"`
+	// ReporterID is the registered user who reported the comment; nil for
+	// guests
+	ReporterID *int `json:"reporter_id,omitempty" db:"reporter_id"`
+	// ReporterKey tells reporters apart so each counts once per comment:
+	// the user ID of registered users, a hash of the client IP of guests
+	ReporterKey string       `json:"-" db:"reporter_key"`
+	Reason      ReportReason `json:"reason" db:"reason"`
+	Details     string       `json:"details" db:"details"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+}
+
+// NewReport creates a report on a comment by a registered user, or by a
+// guest from clientIP when reporterID is zero
+func NewReport(commentID, reporterID int, clientIP string, reason ReportReason, details string) (*Report, error) {
+	if commentID <= 0 {
+		return nil, errors.New("comment ID must be positive")
+	}
+	if !reason.IsValid() {
+		return nil, ErrInvalidReportReason
+	}
+	details = strings.TrimSpace(details)
+	if len([]rune(details)) > MaxReportDetailsLength {
+		return nil, ErrReportDetailsTooLong
+	}
+
+	r := &Report{
+		CommentID: commentID,
+		Reason:    reason,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+	if reporterID > 0 {
+		r.ReporterID = &reporterID
+		r.ReporterKey = "user:" + strconv.Itoa(reporterID)
+	} else {
+		// Only a hash of the address is kept; it is enough to count a
+		// guest once
+		sum := sha256.Sum256([]byte(clientIP))
+		r.ReporterKey = "ip:" + hex.EncodeToString(sum[:])
+	}
+	return r, nil
+}
+
+// ReportRepository defines the interface for comment report data access
+type ReportRepository interface {
+	// Create stores a report; it returns false, leaving the report
+	// unsaved, when the reporter already reported the comment
+	Create(ctx context.Context, report *Report) (bool, error)
+	// CountByComment counts the reporters of a comment
+	CountByComment(ctx context.Context, commentID int) (int, error)
+	// ListByComment returns the reports on a comment, oldest first
+	ListByComment(ctx context.Context, commentID int) ([]*Report, error)
+}
+
+// ReportService defines the interface for reporting comments
+type ReportService interface {
+	// ReportComment reports a public comment; comments reaching the report
+	// threshold are hidden until an admin reviews them. Repeated reports by
+	// the same reporter are accepted but count once.
+	ReportComment(ctx context.Context, commentID, reporterID int, clientIP string, reason ReportReason, details string) (*Report, error)
+	// ListReports returns the reports on a comment for admins to review
+	ListReports(ctx context.Context, commentID int) ([]*Report, error)
+}
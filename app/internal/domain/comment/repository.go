@@ -3,18 +3,72 @@ package comment
 import (
 	"context"
 	"errors"
+	"time"
+
+	"blog-platform/internal/domain/keyset"
 )
 
 var (
 	// ErrCommentNotFound is returned when a comment is not found
 	ErrCommentNotFound = errors.New("comment not found")
+	// ErrClientIDTaken is returned when another comment was created under
+	// the client ID
+	ErrClientIDTaken = errors.New("comment client ID already exists")
 )
 
-// Repository defines the interface for comment data access
-type Repository interface {
-	Create(ctx context.Context, comment *Comment) error
+// RecentFilter selects the comments of ListRecent; zero fields do not filter
+type RecentFilter struct {
+	PostID int
+	// PostAuthorID selects comments on the published public posts of an author
+	PostAuthorID int
+}
+
+// ReadRepository defines the read side of comment data access. Listings of the
+// comments of posts only return approved comments.
+type ReadRepository interface {
 	GetByID(ctx context.Context, id int) (*Comment, error)
+	// GetByClientID retrieves a comment by the client ID it was created with
+	GetByClientID(ctx context.Context, clientID string) (*Comment, error)
 	GetByPostID(ctx context.Context, postID int, limit, offset int) ([]*Comment, error)
+	// GetByPostIDAfter returns the comments of a post that come after the
+	// cursor, oldest first
+	GetByPostIDAfter(ctx context.Context, postID int, after keyset.Cursor, limit int) ([]*Comment, error)
+	// GetRootsByPostID returns the top-level comments of a post, oldest first
+	GetRootsByPostID(ctx context.Context, postID int, limit, offset int) ([]*Comment, error)
+	// GetByRootIDs returns the replies in the threads of top-level comments,
+	// oldest first
+	GetByRootIDs(ctx context.Context, rootIDs []int) ([]*Comment, error)
+	// ListRecent returns the newest comments first
+	ListRecent(ctx context.Context, filter RecentFilter, limit int) ([]*Comment, error)
+	// ListByStatus returns the comments with a moderation status, oldest
+	// first
+	ListByStatus(ctx context.Context, status Status, limit, offset int) ([]*Comment, error)
+	// LastCommentAt returns when a user last commented; zero when the user
+	// never did
+	LastCommentAt(ctx context.Context, userID int) (time.Time, error)
+	// GetTrustLevel returns the trust level of a commenter; commenters not
+	// evaluated yet are new
+	GetTrustLevel(ctx context.Context, userID int) (TrustLevel, error)
+	// ListCommenterHistories returns the history of every registered user
+	// who commented; comments rejected or marked as spam since flaggedSince
+	// count as flagged
+	ListCommenterHistories(ctx context.Context, flaggedSince time.Time) ([]*CommenterHistory, error)
+}
+
+// WriteRepository defines the write side of comment data access
+type WriteRepository interface {
+	Create(ctx context.Context, comment *Comment) error
 	Update(ctx context.Context, comment *Comment) error
 	Delete(ctx context.Context, id int) error
+	// DeleteByPostID removes every comment of a post and returns how many
+	// it removed
+	DeleteByPostID(ctx context.Context, postID int) (int, error)
+	// SetTrustLevel stores the trust level of a commenter
+	SetTrustLevel(ctx context.Context, userID int, level TrustLevel) error
+}
+
+// Repository defines the interface for comment data access
+type Repository interface {
+	ReadRepository
+	WriteRepository
 }
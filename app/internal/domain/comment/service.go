@@ -2,13 +2,43 @@ package comment
 
 import (
 	"context"
+
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/user"
 )
 
-// Service defines the interface for comment business logic
+// Service defines the interface for comment business logic. Adding and
+// listing the comments of a post the user cannot see fails with the post's
+// not found error.
 type Service interface {
-	AddComment(ctx context.Context, postID int, authorName, content string) (*Comment, error)
+	// AddComment adds a comment by a registered user, or by a guest when
+	// userID is zero. The comment is pending when moderation is required.
+	AddComment(ctx context.Context, postID, userID int, role user.Role, authorName, content string) (*Comment, error)
+	// ReplyToComment adds a reply to a comment on the same post
+	ReplyToComment(ctx context.Context, postID, parentID, userID int, role user.Role, authorName, content string) (*Comment, error)
+	// AddClientComment adds a comment, or a reply to parentID when it is
+	// set, under a UUID the client generated. Retrying with the same client
+	// ID returns the comment created first with created false, so offline
+	// clients can resend safely.
+	AddClientComment(ctx context.Context, postID, parentID, userID int, role user.Role, clientID, authorName, content string) (c *Comment, created bool, err error)
 	GetComment(ctx context.Context, id int) (*Comment, error)
-	GetCommentsByPost(ctx context.Context, postID int, limit, offset int) ([]*Comment, error)
-	UpdateComment(ctx context.Context, id int, authorName, content string) (*Comment, error)
-	DeleteComment(ctx context.Context, id int, authorName string) error
+	// GetCommentByClientID retrieves a comment by the client ID it was
+	// created with
+	GetCommentByClientID(ctx context.Context, clientID string) (*Comment, error)
+	GetCommentsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*Comment, error)
+	// GetCommentsByPostAfter returns the page of comments after the cursor
+	// and the cursor of the next page, which is zero on the last page
+	GetCommentsByPostAfter(ctx context.Context, postID, userID int, role user.Role, after keyset.Cursor, limit int) ([]*Comment, keyset.Cursor, error)
+	// GetThreadsByPost returns a page of the top-level comments of a post
+	// with their replies arranged as trees
+	GetThreadsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*Thread, error)
+	// GetRecentComments returns the newest comments first, e.g. for feeds
+	GetRecentComments(ctx context.Context, filter RecentFilter, limit int) ([]*Comment, error)
+	UpdateComment(ctx context.Context, id, userID int, role user.Role, content string) (*Comment, error)
+	DeleteComment(ctx context.Context, id, userID int, role user.Role) error
+	// ListForModeration returns the comments with a moderation status,
+	// oldest first, for admins to review
+	ListForModeration(ctx context.Context, status Status, limit, offset int) ([]*Comment, error)
+	// ModerateComment sets the moderation status of a comment
+	ModerateComment(ctx context.Context, id, adminID int, status Status) (*Comment, error)
 }
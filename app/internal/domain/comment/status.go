@@ -0,0 +1,42 @@
+package comment
+
+import "errors"
+
+// Status is the moderation state of a comment; only approved comments are
+// public
+type Status string
+
+// Available statuses
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusSpam     Status = "spam"
+)
+
+// ErrInvalidStatus is returned for unknown moderation statuses
+var ErrInvalidStatus = errors.New("invalid comment status: must be pending, approved, rejected or spam")
+
+// IsValid checks if the status is known
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusRejected, StatusSpam:
+		return true
+	}
+	return false
+}
+
+// IsApproved checks if the comment is public
+func (c *Comment) IsApproved() bool {
+	return c.Status == StatusApproved
+}
+
+// Moderate sets the moderation status of the comment; moderating a comment
+// to the status it has is allowed, so retried decisions succeed
+func (c *Comment) Moderate(status Status) error {
+	if !status.IsValid() {
+		return ErrInvalidStatus
+	}
+	c.Status = status
+	return nil
+}
@@ -0,0 +1,82 @@
+package comment
+
+import "errors"
+
+// MaxDepth is the deepest a reply may be nested: replies to replies are
+// allowed up to this level, top-level comments being at level 0
+const MaxDepth = 3
+
+var (
+	// ErrParentNotFound is returned when a reply names a comment that does not exist
+	ErrParentNotFound = errors.New("invalid parent comment: comment does not exist")
+	// ErrParentOtherPost is returned when a reply names a comment of another post
+	ErrParentOtherPost = errors.New("invalid parent comment: comment belongs to another post")
+	// ErrThreadTooDeep is returned when a reply would be nested deeper than MaxDepth
+	ErrThreadTooDeep = errors.New("invalid parent comment: replies cannot be nested more than 3 levels deep")
+)
+
+// ReplyTo makes the comment a reply to parent, which must be a comment on the
+// same post that is not nested at MaxDepth already
+func (c *Comment) ReplyTo(parent *Comment) error {
+	if !parent.BelongsToPost(c.PostID) {
+		return ErrParentOtherPost
+	}
+	if parent.Depth >= MaxDepth {
+		return ErrThreadTooDeep
+	}
+
+	rootID := parent.ID
+	if parent.RootID != nil {
+		rootID = *parent.RootID
+	}
+	parentID := parent.ID
+	c.ParentID = &parentID
+	c.RootID = &rootID
+	c.Depth = parent.Depth + 1
+	return nil
+}
+
+// IsReply checks if the comment replies to another comment
+func (c *Comment) IsReply() bool {
+	return c.ParentID != nil
+}
+
+// Thread is a comment with the replies to it, oldest first
+type Thread struct {
+	Comment *Comment
+	Replies []*Thread
+}
+
+// ReplyCount returns the number of replies in the thread at any depth
+func (t *Thread) ReplyCount() int {
+	count := len(t.Replies)
+	for _, reply := range t.Replies {
+		count += reply.ReplyCount()
+	}
+	return count
+}
+
+// BuildThreads arranges top-level comments and the replies in their threads
+// into trees, keeping the order of both. Replies whose parent is missing are
+// left out.
+func BuildThreads(roots, replies []*Comment) []*Thread {
+	threads := make([]*Thread, len(roots))
+	byID := make(map[int]*Thread, len(roots)+len(replies))
+	for i, root := range roots {
+		threads[i] = &Thread{Comment: root}
+		byID[root.ID] = threads[i]
+	}
+	for _, reply := range replies {
+		byID[reply.ID] = &Thread{Comment: reply}
+	}
+
+	for _, reply := range replies {
+		if reply.ParentID == nil {
+			continue
+		}
+		if parent, ok := byID[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, byID[reply.ID])
+		}
+	}
+	return threads
+}
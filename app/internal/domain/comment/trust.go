@@ -0,0 +1,151 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/user"
+)
+
+// TrustLevel is how far commenters are trusted, earned through the age of
+// their account and their approved comments
+type TrustLevel string
+
+// Available trust levels, from least to most trusted
+const (
+	TrustNew     TrustLevel = "new"
+	TrustBasic   TrustLevel = "basic"
+	TrustTrusted TrustLevel = "trusted"
+)
+
+var (
+	// ErrTooManyLinks is returned for comments with more links than the
+	// trust level of the commenter allows
+	ErrTooManyLinks = errors.New("invalid comment: too many links for your trust level")
+	// ErrCommentCooldown is returned when commenters comment again before
+	// the cooldown of their trust level has passed
+	ErrCommentCooldown = errors.New("comment cooldown: wait before commenting again")
+)
+
+// TrustLimits bounds the comments of a trust level; zero fields do not limit
+type TrustLimits struct {
+	// MaxLinks is the number of links a comment may hold; negative values
+	// allow none
+	MaxLinks int
+	// MaxLength is the number of characters a comment may hold
+	MaxLength int
+	// Cooldown is how long commenters wait between two comments
+	Cooldown time.Duration
+}
+
+// linkPattern matches the start of a link in comment content
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)`)
+
+// CountLinks counts the links in comment content
+func CountLinks(content string) int {
+	return len(linkPattern.FindAllStringIndex(content, -1))
+}
+
+// CheckContent checks comment content against the limits
+func (l TrustLimits) CheckContent(content string) error {
+	if l.MaxLength > 0 && utf8.RuneCountInString(content) > l.MaxLength {
+		return fmt.Errorf("comment content cannot exceed %d characters for your trust level", l.MaxLength)
+	}
+	if l.MaxLinks != 0 && CountLinks(content) > max(l.MaxLinks, 0) {
+		return ErrTooManyLinks
+	}
+	return nil
+}
+
+// CheckCooldown checks that the cooldown has passed since the last comment
+// of a commenter; a zero lastCommentAt is a first comment
+func (l TrustLimits) CheckCooldown(lastCommentAt, now time.Time) error {
+	if l.Cooldown > 0 && !lastCommentAt.IsZero() && now.Sub(lastCommentAt) < l.Cooldown {
+		return ErrCommentCooldown
+	}
+	return nil
+}
+
+// CommenterHistory is what the trust level of a commenter is derived from
+type CommenterHistory struct {
+	UserID int       `db:"user_id"`
+	Role   user.Role `db:"role"`
+	// Level is the trust level the commenter has now
+	Level            TrustLevel `db:"level"`
+	AccountCreatedAt time.Time  `db:"account_created_at"`
+	// Approved counts the approved comments of the commenter
+	Approved int `db:"approved"`
+	// Flagged counts the comments rejected or marked as spam within the
+	// flag window of the policy
+	Flagged int `db:"flagged"`
+}
+
+// TrustPolicy holds the limits of each trust level and the rules moving
+// commenters between them
+type TrustPolicy struct {
+	Limits map[TrustLevel]TrustLimits
+	// BasicMinAge and BasicMinApproved are what commenters need to become
+	// basic
+	BasicMinAge      time.Duration
+	BasicMinApproved int
+	// TrustedMinAge and TrustedMinApproved are what commenters need to
+	// become trusted; flagged comments within the window hold them back
+	TrustedMinAge      time.Duration
+	TrustedMinApproved int
+	// FlagWindow is how far back rejected and spam comments count
+	FlagWindow time.Duration
+	// MaxFlagged is the number of flagged comments within the window that
+	// drops commenters back to new
+	MaxFlagged int
+}
+
+// DefaultTrustPolicy returns the trust levels used unless configured
+// otherwise: new commenters may not post links and wait between comments
+func DefaultTrustPolicy() TrustPolicy {
+	return TrustPolicy{
+		Limits: map[TrustLevel]TrustLimits{
+			TrustNew:     {MaxLinks: -1, MaxLength: 1000, Cooldown: 2 * time.Minute},
+			TrustBasic:   {MaxLinks: 2, MaxLength: 5000, Cooldown: 30 * time.Second},
+			TrustTrusted: {MaxLinks: 10},
+		},
+		BasicMinAge:        3 * 24 * time.Hour,
+		BasicMinApproved:   3,
+		TrustedMinAge:      30 * 24 * time.Hour,
+		TrustedMinApproved: 20,
+		FlagWindow:         90 * 24 * time.Hour,
+		MaxFlagged:         2,
+	}
+}
+
+// LimitsFor returns the limits of a trust level; unknown levels get the
+// limits of new commenters
+func (p TrustPolicy) LimitsFor(level TrustLevel) TrustLimits {
+	if limits, ok := p.Limits[level]; ok {
+		return limits
+	}
+	return p.Limits[TrustNew]
+}
+
+// Evaluate derives the trust level a commenter has earned. Admins are
+// always trusted; commenters flagged too often drop back to new whatever
+// their history.
+func (p TrustPolicy) Evaluate(h CommenterHistory, now time.Time) TrustLevel {
+	if h.Role.IsAdmin() {
+		return TrustTrusted
+	}
+	if p.MaxFlagged > 0 && h.Flagged >= p.MaxFlagged {
+		return TrustNew
+	}
+
+	age := now.Sub(h.AccountCreatedAt)
+	switch {
+	case age >= p.TrustedMinAge && h.Approved >= p.TrustedMinApproved && h.Flagged == 0:
+		return TrustTrusted
+	case age >= p.BasicMinAge && h.Approved >= p.BasicMinApproved:
+		return TrustBasic
+	}
+	return TrustNew
+}
@@ -0,0 +1,84 @@
+// Package export gathers the data of an account so its owner can download
+// a copy of everything they wrote, and the content of the whole site for
+// backups and migrations
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// ErrInvalidRange is returned for site export filters whose range ends
+// before it starts
+var ErrInvalidRange = errors.New("invalid export range: from is after to")
+
+// Archive is the data of an account at the time of the export
+type Archive struct {
+	ExportedAt time.Time
+	Account    *user.User
+	// Profile is nil for accounts without a public profile, e.g. while
+	// deactivated
+	Profile *user.Profile
+	// Posts holds every post of the account, drafts included, oldest first
+	Posts []*post.Post
+	// Comments holds every comment of the account, whatever its moderation
+	// status, oldest first
+	Comments []*comment.Comment
+}
+
+// SiteFilter narrows a site export
+type SiteFilter struct {
+	// AuthorID limits the export to the posts of one author; 0 exports
+	// every author
+	AuthorID int
+	// From and To bound the creation time of the exported posts
+	From *time.Time
+	To   *time.Time
+}
+
+// Validate checks that the range of the filter does not end before it
+// starts
+func (f SiteFilter) Validate() error {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return ErrInvalidRange
+	}
+	return nil
+}
+
+// Entry is a post of a site export with its author and every comment on
+// it, whatever its moderation status, oldest first
+type Entry struct {
+	Post *post.Post
+	// Author is nil if the account of the author could not be found
+	Author   *user.User
+	Comments []*comment.Comment
+}
+
+// Repository defines the interface for reading the content of an account
+// and of the site
+type Repository interface {
+	// ListPosts returns the posts of an author with their tags, oldest first
+	ListPosts(ctx context.Context, authorID int) ([]*post.Post, error)
+	// ListComments returns the comments of a user, oldest first
+	ListComments(ctx context.Context, userID int) ([]*comment.Comment, error)
+	// ListSitePosts returns up to limit posts matching the filter with an ID
+	// above afterID, drafts included, with their tags, in ID order
+	ListSitePosts(ctx context.Context, filter SiteFilter, afterID, limit int) ([]*post.Post, error)
+	// ListPostComments returns the comments on the given posts, oldest first
+	ListPostComments(ctx context.Context, postIDs []int) ([]*comment.Comment, error)
+}
+
+// Service defines the interface for exporting accounts and the site
+type Service interface {
+	// Export gathers the account, profile, posts and comments of a user
+	Export(ctx context.Context, userID int) (*Archive, error)
+	// ExportSite passes every post matching the filter to write, in ID
+	// order, reading them in batches so the export is never held in memory
+	// at once. It stops at the first error write returns.
+	ExportSite(ctx context.Context, adminID int, filter SiteFilter, write func(*Entry) error) error
+}
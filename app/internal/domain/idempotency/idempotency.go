@@ -0,0 +1,86 @@
+// Package idempotency describes the write requests clients send with an
+// Idempotency-Key, so a retried request returns the response of the first
+// one instead of being applied twice
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MaxKeyLength bounds the length of idempotency keys
+const MaxKeyLength = 255
+
+// Idempotency errors
+var (
+	ErrRecordNotFound = errors.New("idempotency key not found")
+	ErrDuplicateKey   = errors.New("idempotency key already exists")
+	ErrInvalidKey     = errors.New("invalid idempotency key: must be 1 to 255 printable ASCII characters")
+	ErrKeyReused      = errors.New("idempotency key reused: the key was sent with a different request")
+	ErrKeyInUse       = errors.New("idempotency key in use: the first request with the key is still being processed")
+)
+
+// Record is a request made with an idempotency key and, once answered, its
+// response. Keys are scoped to the user who sent them, or to guests.
+type Record struct {
+	ID    int    `db:"id"`
+	Scope string `db:"scope"`
+	Key   string `db:"idempotency_key"`
+	// RequestHash identifies the request: its method, path and body
+	RequestHash string `db:"request_hash"`
+	// StatusCode is zero while the first request is being processed
+	StatusCode  int       `db:"status_code"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"response_body"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+// Completed reports whether the response of the request is stored
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// ValidKey reports whether key can be used as an idempotency key
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Repository defines the interface for idempotency record data access
+type Repository interface {
+	// Create inserts a record, returning ErrDuplicateKey when the scope
+	// already has one with the key
+	Create(ctx context.Context, record *Record) error
+	Get(ctx context.Context, scope, key string) (*Record, error)
+	// Complete stores the response of the record
+	Complete(ctx context.Context, record *Record) error
+	Delete(ctx context.Context, id int) error
+	// DeleteExpired removes the records that expired before the given
+	// time, returning how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
+
+// Service defines the interface for idempotent requests
+type Service interface {
+	// Begin claims the key for a request. It returns a new record for the
+	// caller to process the request and complete, or the completed record
+	// of an earlier request to replay.
+	Begin(ctx context.Context, scope, key, requestHash string) (record *Record, replay bool, err error)
+	// Complete stores the response of a request begun with Begin
+	Complete(ctx context.Context, record *Record, statusCode int, contentType string, body []byte) error
+	// Release gives up the key of a request that failed, so it can be
+	// retried
+	Release(ctx context.Context, record *Record) error
+	// DeleteExpired removes the expired records, returning how many were
+	// removed
+	DeleteExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,70 @@
+package imports
+
+import (
+	"strings"
+	"time"
+)
+
+// Document is the content of an export file, independent of its format
+type Document struct {
+	Source  string   `json:"source"`
+	Authors []Author `json:"authors,omitempty"`
+	Posts   []Post   `json:"posts"`
+}
+
+// Author is a user of the exporting blog. Authors are matched to accounts
+// by email; authors without one are credited to the importing admin.
+type Author struct {
+	// Login identifies the author within the document
+	Login string `json:"login"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// Post is an exported post
+type Post struct {
+	Title string `json:"title"`
+	// Slug is kept when it is free; empty slugs are derived from the title
+	Slug    string `json:"slug,omitempty"`
+	Content string `json:"content"`
+	// AuthorLogin references an author of the document
+	AuthorLogin string     `json:"author_login,omitempty"`
+	Draft       bool       `json:"draft,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Comments    []Comment  `json:"comments,omitempty"`
+}
+
+// Comment is an exported comment
+type Comment struct {
+	// ID identifies the comment within its post, so replies can reference it
+	ID string `json:"id,omitempty"`
+	// ParentID is the ID of the comment replied to; empty for top-level
+	// comments
+	ParentID   string `json:"parent_id,omitempty"`
+	AuthorName string `json:"author_name"`
+	// AuthorLogin references an author of the document for comments by
+	// registered users
+	AuthorLogin string    `json:"author_login,omitempty"`
+	Content     string    `json:"content"`
+	Approved    bool      `json:"approved"`
+	Spam        bool      `json:"spam,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Author returns the author of the document with a login
+func (d *Document) Author(login string) (Author, bool) {
+	for _, a := range d.Authors {
+		if strings.EqualFold(a.Login, login) {
+			return a, true
+		}
+	}
+	return Author{}, false
+}
+
+// Parser reads an export file into a document
+type Parser interface {
+	// Parse returns ErrUnsupportedFile for files in no known format and
+	// errors wrapping ErrInvalidFile for malformed ones
+	Parse(data []byte) (*Document, error)
+}
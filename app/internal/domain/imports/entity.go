@@ -0,0 +1,106 @@
+// Package imports brings content exported from other blogs into the
+// platform: WordPress exports (WXR) and zips of Markdown files with front
+// matter are mapped onto users, posts and comments.
+package imports
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Import sources
+const (
+	SourceWordPress = "wordpress"
+	SourceMarkdown  = "markdown"
+)
+
+// Import statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+)
+
+// maxErrors caps the errors kept on an import; the failed counter keeps
+// counting past it
+const maxErrors = 100
+
+// Import errors
+var (
+	ErrImportNotFound = errors.New("import not found")
+	// ErrInvalidFile is wrapped by the errors of parsers rejecting an upload
+	ErrInvalidFile     = errors.New("invalid import file")
+	ErrUnsupportedFile = fmt.Errorf("%w: must be a WordPress export (WXR) or a zip of Markdown files", ErrInvalidFile)
+	ErrNoPosts         = fmt.Errorf("%w: no posts found", ErrInvalidFile)
+)
+
+// Import is the import of an export file started by an administrator. The
+// posts of the document are imported one by one; Processed counts those
+// handled so far, so an interrupted import resumes where it stopped. A dry
+// run goes through the same steps without writing anything and counts what
+// an import would create.
+type Import struct {
+	ID        int       `db:"id"`
+	Source    string    `db:"source"`
+	DryRun    bool      `db:"dry_run"`
+	Status    string    `db:"status"`
+	CreatedBy int       `db:"created_by"`
+	Document  *Document `db:"-"`
+	// Total is the number of posts in the document
+	Total           int `db:"total"`
+	Processed       int `db:"processed"`
+	UsersCreated    int `db:"users_created"`
+	PostsCreated    int `db:"posts_created"`
+	CommentsCreated int `db:"comments_created"`
+	// Skipped counts posts whose slug is taken already
+	Skipped int `db:"skipped"`
+	// Failed counts posts that could not be imported; Errors says why
+	Failed      int        `db:"failed"`
+	Errors      []string   `db:"-"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
+
+// NewImport creates a queued import of a parsed document
+func NewImport(createdBy int, doc *Document, dryRun bool) (*Import, error) {
+	if len(doc.Posts) == 0 {
+		return nil, ErrNoPosts
+	}
+
+	now := time.Now()
+	return &Import{
+		Source:    doc.Source,
+		DryRun:    dryRun,
+		Status:    StatusQueued,
+		CreatedBy: createdBy,
+		Document:  doc,
+		Total:     len(doc.Posts),
+		Errors:    []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Fail counts a post that could not be imported
+func (i *Import) Fail(format string, args ...any) {
+	i.Failed++
+	if len(i.Errors) < maxErrors {
+		i.Errors = append(i.Errors, fmt.Sprintf(format, args...))
+	}
+	i.UpdatedAt = time.Now()
+}
+
+// Complete marks every post as processed
+func (i *Import) Complete() {
+	now := time.Now()
+	i.Status = StatusCompleted
+	i.CompletedAt = &now
+	i.UpdatedAt = now
+}
+
+// IsCompleted checks if every post has been processed
+func (i *Import) IsCompleted() bool {
+	return i.Status == StatusCompleted
+}
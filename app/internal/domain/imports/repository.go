@@ -0,0 +1,12 @@
+package imports
+
+import "context"
+
+// Repository defines the interface for import data access
+type Repository interface {
+	// Create stores an import with its document
+	Create(ctx context.Context, i *Import) error
+	GetByID(ctx context.Context, id int) (*Import, error)
+	// Update stores the status, progress counters and errors
+	Update(ctx context.Context, i *Import) error
+}
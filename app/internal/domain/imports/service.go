@@ -0,0 +1,12 @@
+package imports
+
+import "context"
+
+// Service defines the interface for import business logic
+type Service interface {
+	// Start parses an export file and queues its import; dry runs only
+	// report what the import would create
+	Start(ctx context.Context, adminID int, data []byte, dryRun bool) (*Import, error)
+	// Get returns the status and progress of an import
+	Get(ctx context.Context, id int) (*Import, error)
+}
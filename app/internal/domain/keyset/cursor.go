@@ -0,0 +1,65 @@
+// Package keyset implements cursors for keyset pagination, which resumes a
+// listing after the last item seen instead of skipping an offset
+package keyset
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued by Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last item of a page in (created_at, id) order. The zero
+// cursor starts at the first page.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// After returns the cursor continuing after an item
+func After(createdAt time.Time, id int) Cursor {
+	return Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// IsZero reports whether the cursor starts at the first page
+func (c Cursor) IsZero() bool {
+	return c.ID == 0
+}
+
+// Encode returns the cursor as an opaque, URL safe string
+func (c Cursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses a cursor returned by Encode; the empty string is the zero
+// cursor
+func Decode(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	itemID, err := strconv.Atoi(id)
+	if err != nil || itemID <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: time.Unix(0, createdAt).UTC(), ID: itemID}, nil
+}
@@ -0,0 +1,61 @@
+// Package media describes the images users upload to reference in their
+// posts
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// MaxPixels bounds the width times height of uploaded images, so small
+// files cannot decode to huge images
+const MaxPixels = 40_000_000
+
+// Media errors
+var (
+	ErrMediaNotFound = errors.New("media not found")
+	ErrInvalidMedia  = errors.New("invalid media: the file is not a JPEG, PNG or GIF image")
+	ErrMediaTooLarge = errors.New("invalid media: the image is too large")
+	ErrQuotaExceeded = errors.New("storage quota exceeded: remove unused images from your posts or upload a smaller file")
+)
+
+// Media is an uploaded image. The user who uploaded it owns it; it is
+// collected once no post or post revision references it.
+type Media struct {
+	ID     int `db:"id"`
+	UserID int `db:"user_id"`
+	// Key is where the file is kept in the object storage
+	Key         string `db:"storage_key"`
+	ContentType string `db:"content_type"`
+	// Size is the size of the file in bytes
+	Size      int64     `db:"size"`
+	Width     int       `db:"width"`
+	Height    int       `db:"height"`
+	CreatedAt time.Time `db:"created_at"`
+	// URL is where the file is served from; it never changes, so it can be
+	// cached for good
+	URL string `db:"-"`
+}
+
+// Repository defines the interface for media data access
+type Repository interface {
+	Create(ctx context.Context, m *Media) error
+	// UsageByUser returns the bytes taken by the media of a user
+	UsageByUser(ctx context.Context, userID int) (int64, error)
+	// ListOrphans returns media created before the given time that no post
+	// or post revision references, oldest first
+	ListOrphans(ctx context.Context, before time.Time, limit int) ([]*Media, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// Service defines the interface for media uploads
+type Service interface {
+	// Upload stores a JPEG, PNG or GIF image for the user to reference in
+	// posts, within the storage quota of the user
+	Upload(ctx context.Context, userID int, content io.Reader) (*Media, error)
+	// CollectOrphans deletes the media no post references once they are
+	// old enough, returning how many were deleted
+	CollectOrphans(ctx context.Context) (int, error)
+}
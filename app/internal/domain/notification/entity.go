@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+// Kind tells what a notification is about
+type Kind string
+
+// Available kinds
+const (
+	// KindComment is a new comment on a post of the user
+	KindComment Kind = "comment"
+	// KindReply is a reply to a comment of the user
+	KindReply Kind = "reply"
+)
+
+// Notification errors
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// Notification tells a user about activity on their posts and comments
+type Notification struct {
+	ID     int  `db:"id"`
+	UserID int  `db:"user_id"`
+	Kind   Kind `db:"kind"`
+	PostID int  `db:"post_id"`
+	// CommentID is the comment the notification is about
+	CommentID int `db:"comment_id"`
+	// ActorName is the author name of the comment
+	ActorName string `db:"actor_name"`
+	// ReadAt is nil until the user marks the notification read
+	ReadAt    *time.Time `db:"read_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// NewNotification creates an unread notification for a user
+func NewNotification(userID int, kind Kind, postID, commentID int, actorName string) *Notification {
+	return &Notification{
+		UserID:    userID,
+		Kind:      kind,
+		PostID:    postID,
+		CommentID: commentID,
+		ActorName: actorName,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsRead checks if the user marked the notification read
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
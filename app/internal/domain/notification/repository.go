@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for notification data access
+type Repository interface {
+	Create(ctx context.Context, notification *Notification) error
+	// ListByUser returns the notifications of a user, newest first
+	ListByUser(ctx context.Context, userID int, limit, offset int) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	// MarkRead marks a notification of the user read; marking it again
+	// keeps the first read time. Notifications of other users are not
+	// found.
+	MarkRead(ctx context.Context, id, userID int, readAt time.Time) error
+}
@@ -0,0 +1,11 @@
+package notification
+
+import "context"
+
+// Service defines the interface for notification business logic
+type Service interface {
+	// List returns a page of the notifications of a user, newest first,
+	// with how many are unread in total
+	List(ctx context.Context, userID int, limit, offset int) ([]*Notification, int, error)
+	MarkRead(ctx context.Context, id, userID int) error
+}
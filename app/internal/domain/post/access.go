@@ -0,0 +1,107 @@
+package post
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Access is who may read the full content of a published post
+type Access string
+
+// Available access levels
+const (
+	AccessPublic Access = "public"
+	// AccessMembers opens the post to every signed-in user
+	AccessMembers Access = "members"
+	// AccessSubscribers opens the post to users on a paid plan
+	AccessSubscribers Access = "subscribers"
+)
+
+// ExcerptLength is the maximum length in runes of the excerpt readers get
+// in place of content they are not entitled to
+const ExcerptLength = 280
+
+// Access errors
+var (
+	ErrInvalidAccess = errors.New("invalid access: must be public, members or subscribers")
+	// ErrSubscribersUnavailable is returned for subscriber-only posts while
+	// billing is disabled, since no reader could ever be entitled to them
+	ErrSubscribersUnavailable = errors.New("invalid access: subscriber-only posts need billing to be enabled")
+)
+
+// IsValid checks if the access level is known
+func (a Access) IsValid() bool {
+	switch a {
+	case AccessPublic, AccessMembers, AccessSubscribers:
+		return true
+	}
+	return false
+}
+
+// Reader is the user a post is served to. Anonymous readers have a zero
+// UserID; Subscriber is set for users on a paid plan.
+type Reader struct {
+	UserID     int
+	Role       user.Role
+	Subscriber bool
+}
+
+// IsRestricted checks if only some readers get the full content of the post
+func (p *Post) IsRestricted() bool {
+	return p.Access == AccessMembers || p.Access == AccessSubscribers
+}
+
+// IsReadableBy checks if the reader is entitled to the full content of the
+// post. Those who can modify the post always are.
+func (p *Post) IsReadableBy(r Reader) bool {
+	if r.UserID > 0 && p.CanModify(r.UserID, r.Role) {
+		return true
+	}
+	switch p.Access {
+	case AccessMembers:
+		return r.UserID > 0
+	case AccessSubscribers:
+		return r.UserID > 0 && r.Subscriber
+	default:
+		return true
+	}
+}
+
+// Teaser returns a locked copy of the post holding only an excerpt of its
+// content. The post itself is left untouched, so shared and cached posts
+// can be teased safely.
+func (p *Post) Teaser() *Post {
+	teaser := *p
+	teaser.Content = Excerpt(p.Content)
+	teaser.Locked = true
+	return &teaser
+}
+
+// Excerpt returns the first paragraph of the content, cut at a word
+// boundary when it is longer than ExcerptLength runes
+func Excerpt(content string) string {
+	text := strings.TrimSpace(content)
+	if paragraph, _, found := strings.Cut(text, "\n\n"); found {
+		text = strings.TrimSpace(paragraph)
+	}
+	if utf8.RuneCountInString(text) <= ExcerptLength {
+		return text
+	}
+
+	// Byte offset of the first rune past the limit
+	end, runes := len(text), 0
+	for i := range text {
+		if runes == ExcerptLength {
+			end = i
+			break
+		}
+		runes++
+	}
+	if cut := strings.LastIndexAny(text[:end], " \n\t"); cut > 0 {
+		end = cut
+	}
+	return strings.TrimSpace(text[:end]) + "…"
+}
@@ -0,0 +1,43 @@
+package post
+
+import (
+	"context"
+	"time"
+)
+
+// Bookmark is a post a user saved to their reading list
+type Bookmark struct {
+	UserID    int       `db:"user_id"`
+	PostID    int       `db:"post_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// BookmarkEntry is a post in the reading list of a user
+type BookmarkEntry struct {
+	Bookmark
+	Title    string `db:"title"`
+	Slug     string `db:"slug"`
+	AuthorID int    `db:"author_id"`
+}
+
+// BookmarkRepository defines the interface for bookmark data access
+type BookmarkRepository interface {
+	// Add bookmarks a post, keeping the original bookmark time when the
+	// post is already bookmarked
+	Add(ctx context.Context, userID, postID int) error
+	// Remove deletes a bookmark; removing a post not bookmarked is not an
+	// error
+	Remove(ctx context.Context, userID, postID int) error
+	// ListByUser returns the reading list of a user, most recently
+	// bookmarked first, leaving out posts no longer published
+	ListByUser(ctx context.Context, userID int, limit, offset int) ([]*BookmarkEntry, error)
+}
+
+// BookmarkService defines the interface for managing reading lists
+type BookmarkService interface {
+	// Bookmark adds a published post to the reading list of a user;
+	// bookmarking it again has no effect
+	Bookmark(ctx context.Context, userID, postID int) error
+	Unbookmark(ctx context.Context, userID, postID int) error
+	ListBookmarks(ctx context.Context, userID int, limit, offset int) ([]*BookmarkEntry, error)
+}
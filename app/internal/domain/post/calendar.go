@@ -0,0 +1,47 @@
+package post
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxCalendarSpan bounds the range of an editorial calendar request
+const MaxCalendarSpan = 92 * 24 * time.Hour
+
+// ErrInvalidCalendarRange is returned for empty, reversed or too long ranges
+var ErrInvalidCalendarRange = errors.New("invalid calendar range: from must be before to and at most 92 days apart")
+
+// Conflict is a post published or scheduled too close to a scheduled post
+type Conflict struct {
+	// PostID is zero for posts the user may not see
+	PostID    int
+	PublishAt time.Time
+}
+
+// CalendarEntry is a scheduled or published post on the editorial calendar
+type CalendarEntry struct {
+	Post *Post
+	// Conflicts lists the posts due within the conflict window of a
+	// scheduled post; published posts have none
+	Conflicts []Conflict
+}
+
+// FindConflicts returns the posts in others that are published or due less
+// than window before or after p. p itself is skipped when it is in others.
+func FindConflicts(p *Post, others []*Post, window time.Duration) []*Post {
+	if p.PublishedAt == nil || window <= 0 {
+		return nil
+	}
+
+	var conflicts []*Post
+	for _, other := range others {
+		if other.ID == p.ID || other.PublishedAt == nil {
+			continue
+		}
+		gap := other.PublishedAt.Sub(*p.PublishedAt)
+		if gap < window && gap > -window {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,52 @@
+package post
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotPublished is returned when pinning or featuring a post that is not
+// published
+var ErrNotPublished = errors.New("invalid post status: only published posts can be pinned or featured")
+
+// IsPinned checks if the post is pinned to the top of the post list
+func (p *Post) IsPinned() bool {
+	return p.PinnedAt != nil
+}
+
+// IsFeatured checks if the post is in the featured set
+func (p *Post) IsFeatured() bool {
+	return p.FeaturedAt != nil
+}
+
+// SetPinned pins a published post as of now, or unpins it. Pinning a pinned
+// post keeps its place.
+func (p *Post) SetPinned(pinned bool, now time.Time) error {
+	if !pinned {
+		p.PinnedAt = nil
+		return nil
+	}
+	if !p.IsPublished() {
+		return ErrNotPublished
+	}
+	if p.PinnedAt == nil {
+		p.PinnedAt = &now
+	}
+	return nil
+}
+
+// SetFeatured features a published post as of now, or removes it from the
+// featured set. Featuring a featured post keeps its place.
+func (p *Post) SetFeatured(featured bool, now time.Time) error {
+	if !featured {
+		p.FeaturedAt = nil
+		return nil
+	}
+	if !p.IsPublished() {
+		return ErrNotPublished
+	}
+	if p.FeaturedAt == nil {
+		p.FeaturedAt = &now
+	}
+	return nil
+}
@@ -9,35 +9,101 @@ import (
 
 // Post represents a blog post entity in the domain
 type Post struct {
-	ID        int        `json:"id" db:"id"`
-	Title     string     `json:"title" db:"title"`
-	Content   string     `json:"content" db:"content"`
-	AuthorID  int        `json:"author_id" db:"author_id"`
-	Author    *user.User `json:"author,omitempty"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	ID          int        `json:"id" db:"id"`
+	// ClientID is the UUID an offline client created the post under; nil
+	// for posts created without one
+	ClientID    *string    `json:"client_id,omitempty" db:"client_id"`
+	Title       string     `json:"title" db:"title"`
+	Slug        string     `json:"slug" db:"slug"`
+	Content     string     `json:"content" db:"content"`
+	AuthorID    int        `json:"author_id" db:"author_id"`
+	Author      *user.User `json:"author,omitempty"`
+	// NoIndex asks search engines not to index the post
+	NoIndex     bool       `json:"noindex" db:"noindex"`
+	// Access is who may read the full content; others get an excerpt
+	Access      Access     `json:"access" db:"access"`
+	// Visibility is who may find the post: public, unlisted or private
+	Visibility  Visibility `json:"visibility" db:"visibility"`
+	// Language is the language code of the title and content
+	Language    string     `json:"language" db:"language"`
+	// Translations are the other languages the post can be read in, sorted
+	Translations []string  `json:"translations,omitempty" db:"-"`
+	// Locked is set on teasers, whose content is only an excerpt
+	Locked      bool       `json:"locked,omitempty" db:"-"`
+	// Status is the publication state; only published posts are public
+	Status      Status     `json:"status" db:"status"`
+	// PublishedAt is when the post was published, or is scheduled to be
+	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
+	// Timezone is the IANA time zone the post was scheduled in; empty for UTC
+	Timezone    string     `json:"timezone,omitempty" db:"timezone"`
+	// Tags are the normalized tag names of the post, sorted by name
+	Tags        []string   `json:"tags,omitempty" db:"-"`
+	// ViewCount is the total number of views; recent views are added in
+	// batches, so it lags behind slightly
+	ViewCount   int64      `json:"view_count" db:"view_count"`
+	// TipCount is the number of paid tips readers gave the author on the post
+	TipCount    int64      `json:"tip_count" db:"tip_count"`
+	// WordCount, ReadingTime and Excerpt summarize the content for listings;
+	// they are computed whenever the content is written
+	WordCount   int        `json:"word_count" db:"word_count"`
+	// ReadingTime is the estimated reading time in minutes
+	ReadingTime int        `json:"reading_time_minutes" db:"reading_time_minutes"`
+	Excerpt     string     `json:"excerpt" db:"excerpt"`
+	// PinnedAt is when an admin pinned the post to the top of the post
+	// list; nil for posts that are not pinned
+	PinnedAt    *time.Time `json:"pinned_at,omitempty" db:"pinned_at"`
+	// FeaturedAt is when an admin featured the post; nil for posts that
+	// are not featured
+	FeaturedAt  *time.Time `json:"featured_at,omitempty" db:"featured_at"`
+	// SeriesID is the series the post belongs to, at SeriesPosition
+	// counted from 1; nil for posts outside any series
+	SeriesID    *int       `json:"series_id,omitempty" db:"series_id"`
+	SeriesPosition int     `json:"series_position,omitempty" db:"series_position"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 }
 
-// NewPost creates a new post instance
+// NewPost creates a new post instance, published right away
 func NewPost(title, content string, authorID int) (*Post, error) {
 	if authorID <= 0 {
 		return nil, errors.New("author ID must be positive")
 	}
 
 	now := time.Now()
-	return &Post{
-		Title:     strings.TrimSpace(title),
-		Content:   strings.TrimSpace(content),
-		AuthorID:  authorID,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}, nil
+	p := &Post{
+		Title:       strings.TrimSpace(title),
+		Slug:        Slugify(title),
+		Content:     strings.TrimSpace(content),
+		AuthorID:    authorID,
+		Access:      AccessPublic,
+		Visibility:  VisibilityPublic,
+		Language:    DefaultLanguage,
+		Status:      StatusPublished,
+		PublishedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	p.RefreshReadingStats()
+	return p, nil
+}
+
+// NewDraft creates a new post instance that is not published until
+// Publish or Schedule is called
+func NewDraft(title, content string, authorID int) (*Post, error) {
+	p, err := NewPost(title, content, authorID)
+	if err != nil {
+		return nil, err
+	}
+	p.Status = StatusDraft
+	p.PublishedAt = nil
+	return p, nil
 }
 
 // Update updates the post's title and content
 func (p *Post) Update(title, content string) error {
 	p.Title = strings.TrimSpace(title)
 	p.Content = strings.TrimSpace(content)
+	p.RefreshReadingStats()
 	p.UpdatedAt = time.Now()
 	return nil
 }
@@ -47,6 +113,12 @@ func (p *Post) IsAuthor(userID int) bool {
 	return p.AuthorID == userID
 }
 
+// CanModify checks if the given user may edit or delete the post:
+// the author always can, admins can modify any post
+func (p *Post) CanModify(userID int, role user.Role) bool {
+	return p.IsAuthor(userID) || role.IsAdmin()
+}
+
 // IsOwnedBy checks if the post is owned by the given user ID (alias for IsAuthor)
 func (p *Post) IsOwnedBy(userID int) bool {
 	return p.IsAuthor(userID)
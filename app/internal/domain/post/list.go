@@ -0,0 +1,85 @@
+package post
+
+import (
+	"errors"
+	"time"
+)
+
+// SortField names a column post listings can be sorted by
+type SortField string
+
+// Sort fields of post listings
+const (
+	SortCreatedAt SortField = "created_at"
+	SortUpdatedAt SortField = "updated_at"
+	SortTitle     SortField = "title"
+)
+
+// SortOrder is the direction of a post listing
+type SortOrder string
+
+// Sort orders of post listings
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// List option errors
+var (
+	ErrInvalidSort         = errors.New("invalid post sort: sort by created_at, updated_at or title in asc or desc order")
+	ErrInvalidCreatedRange = errors.New("invalid post filter: created_after must be before created_before")
+)
+
+// ListOptions narrows and orders a post listing. Zero fields match every
+// post; listings default to the newest posts first.
+type ListOptions struct {
+	// AuthorID only lists the posts of this author
+	AuthorID int
+	// CreatedAfter and CreatedBefore bound the creation time; CreatedAfter
+	// is inclusive, CreatedBefore exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          SortField
+	Order         SortOrder
+}
+
+// IsZero reports whether the options keep the default listing
+func (o ListOptions) IsZero() bool {
+	return o == ListOptions{}
+}
+
+// Validate checks the sort, the author and the creation time range
+func (o ListOptions) Validate() error {
+	switch o.Sort {
+	case "", SortCreatedAt, SortUpdatedAt, SortTitle:
+	default:
+		return ErrInvalidSort
+	}
+	switch o.Order {
+	case "", OrderAsc, OrderDesc:
+	default:
+		return ErrInvalidSort
+	}
+	if o.AuthorID < 0 {
+		return ErrInvalidPostData
+	}
+	if o.CreatedAfter != nil && o.CreatedBefore != nil && !o.CreatedAfter.Before(*o.CreatedBefore) {
+		return ErrInvalidCreatedRange
+	}
+	return nil
+}
+
+// WithDefaults fills in the default sort, newest first. Titles sort A to Z
+// unless an order is given.
+func (o ListOptions) WithDefaults() ListOptions {
+	if o.Sort == "" {
+		o.Sort = SortCreatedAt
+	}
+	if o.Order == "" {
+		o.Order = OrderDesc
+		if o.Sort == SortTitle {
+			o.Order = OrderAsc
+		}
+	}
+	return o
+}
@@ -0,0 +1,88 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxAnchorLength bounds the anchor of a reading position
+const maxAnchorLength = 255
+
+// Progress errors
+var (
+	ErrProgressNotFound = errors.New("reading progress not found")
+	ErrInvalidPercent   = errors.New("invalid progress: percent must be between 0 and 100")
+	ErrInvalidAnchor    = errors.New("invalid progress: anchor cannot exceed 255 characters")
+)
+
+// Progress is how far a user read a post, synced across their devices
+type Progress struct {
+	UserID  int     `db:"user_id"`
+	PostID  int     `db:"post_id"`
+	Percent float64 `db:"percent"`
+	// Anchor is an optional client-defined position in the post, such as
+	// the ID of the heading last read
+	Anchor string `db:"anchor"`
+	// RecordedAt is when the device read up to this position. Only newer
+	// positions replace a stored one, so it never moves backwards.
+	RecordedAt time.Time `db:"recorded_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// NewProgress creates a reading position recorded by a device at
+// recordedAt. A zero recordedAt, or one in the future of now, is taken as
+// now, so a device with a fast clock cannot hold off the others.
+func NewProgress(userID, postID int, percent float64, anchor string, recordedAt, now time.Time) (*Progress, error) {
+	if percent < 0 || percent > 100 {
+		return nil, ErrInvalidPercent
+	}
+	anchor = strings.TrimSpace(anchor)
+	if utf8.RuneCountInString(anchor) > maxAnchorLength {
+		return nil, ErrInvalidAnchor
+	}
+	if recordedAt.IsZero() || recordedAt.After(now) {
+		recordedAt = now
+	}
+	return &Progress{
+		UserID:     userID,
+		PostID:     postID,
+		Percent:    percent,
+		Anchor:     anchor,
+		RecordedAt: recordedAt,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// ReadingEntry is a post in the reading history of a user
+type ReadingEntry struct {
+	Progress
+	Title string `db:"title"`
+	Slug  string `db:"slug"`
+}
+
+// ProgressRepository defines the interface for reading progress data access
+type ProgressRepository interface {
+	// Save stores a position unless the one already stored was recorded
+	// at the same time or later; concurrent devices thus resolve to the
+	// latest position
+	Save(ctx context.Context, progress *Progress) error
+	Get(ctx context.Context, userID, postID int) (*Progress, error)
+	// ListByUser returns the reading history of a user, most recently read
+	// first, leaving out posts no longer published
+	ListByUser(ctx context.Context, userID int, limit, offset int) ([]*ReadingEntry, error)
+}
+
+// ProgressService defines the interface for syncing reading progress
+type ProgressService interface {
+	// SaveProgress records a position on a published post and returns the
+	// position stored afterwards, which is a newer one when the update was
+	// stale
+	SaveProgress(ctx context.Context, userID, postID int, percent float64, anchor string, recordedAt time.Time) (*Progress, error)
+	// GetProgress returns the position of a user on a post, or
+	// ErrProgressNotFound
+	GetProgress(ctx context.Context, userID, postID int) (*Progress, error)
+	ListReadingHistory(ctx context.Context, userID int, limit, offset int) ([]*ReadingEntry, error)
+}
@@ -0,0 +1,30 @@
+package post
+
+import (
+	"strings"
+)
+
+// WordsPerMinute is the reading speed reading times are estimated with
+const WordsPerMinute = 200
+
+// CountWords counts the whitespace separated words of the content
+func CountWords(content string) int {
+	return len(strings.Fields(content))
+}
+
+// ReadingTimeMinutes estimates the minutes needed to read a number of
+// words, rounded up; any content takes at least a minute
+func ReadingTimeMinutes(words int) int {
+	if words <= 0 {
+		return 0
+	}
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// RefreshReadingStats recomputes the word count, reading time and excerpt
+// from the content
+func (p *Post) RefreshReadingStats() {
+	p.WordCount = CountWords(p.Content)
+	p.ReadingTime = ReadingTimeMinutes(p.WordCount)
+	p.Excerpt = Excerpt(p.Content)
+}
@@ -0,0 +1,53 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Redirect errors
+var (
+	ErrInvalidSlug      = errors.New("invalid slug: use lowercase letters, digits and single hyphens")
+	ErrRedirectNotFound = errors.New("redirect not found")
+	ErrRedirectExists   = errors.New("redirect already exists for this slug")
+)
+
+// Redirect sends readers of an old slug to a post. Redirects are recorded
+// automatically when the slug of a post changes, or added by admins.
+type Redirect struct {
+	ID       int    `db:"id"`
+	FromSlug string `db:"from_slug"`
+	PostID   int    `db:"post_id"`
+	// Automatic is set for redirects recorded by slug changes
+	Automatic bool      `db:"automatic"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// RedirectRepository defines the interface for redirect data access.
+// Redirects are deleted with their post.
+type RedirectRepository interface {
+	Create(ctx context.Context, r *Redirect) error
+	GetByID(ctx context.Context, id int) (*Redirect, error)
+	// GetBySlug returns the redirect of an old slug
+	GetBySlug(ctx context.Context, slug string) (*Redirect, error)
+	// List lists redirects, most recently created first
+	List(ctx context.Context, limit, offset int) ([]*Redirect, error)
+	Update(ctx context.Context, r *Redirect) error
+	Delete(ctx context.Context, id int) error
+}
+
+// RedirectService defines the interface for resolving slugs and managing
+// redirects
+type RedirectService interface {
+	// Resolve returns the post with the slug, or the post an old slug
+	// redirects to, when the user may read it; callers redirect when the
+	// slug of the post differs. Pass a zero userID for anonymous readers.
+	Resolve(ctx context.Context, userID int, role user.Role, slug string) (*Post, error)
+	ListRedirects(ctx context.Context, limit, offset int) ([]*Redirect, error)
+	CreateRedirect(ctx context.Context, adminID int, fromSlug string, postID int) (*Redirect, error)
+	UpdateRedirect(ctx context.Context, adminID, id int, fromSlug string, postID int) (*Redirect, error)
+	DeleteRedirect(ctx context.Context, adminID, id int) error
+}
@@ -0,0 +1,16 @@
+package post
+
+import "context"
+
+// Limits of related post lists
+const (
+	DefaultRelatedLimit = 5
+	MaxRelatedLimit     = 20
+)
+
+// RelatednessStrategy finds the posts most related to a post. Strategies
+// only return published posts other than the post itself, most related
+// first; implementations may match tags, words or embeddings.
+type RelatednessStrategy interface {
+	Related(ctx context.Context, p *Post, limit int) ([]*Post, error)
+}
@@ -3,6 +3,9 @@ package post
 import (
 	"context"
 	"errors"
+	"time"
+
+	"blog-platform/internal/domain/keyset"
 )
 
 // Repository errors
@@ -10,14 +13,142 @@ var (
 	ErrPostNotFound     = errors.New("post not found")
 	ErrInvalidPostData  = errors.New("invalid post data")
 	ErrUnauthorized     = errors.New("unauthorized access to post")
+	ErrSlugTaken        = errors.New("post slug already exists")
+	ErrClientIDTaken    = errors.New("post client ID already exists")
 )
 
-// Repository defines the interface for post data access
-type Repository interface {
-	Create(ctx context.Context, post *Post) error
+// TagFilter selects the posts of ListByTag. Published public posts carrying
+// the tag are always listed; AuthorID adds the other posts of that author
+// and All adds every other post.
+type TagFilter struct {
+	Tag      string
+	AuthorID int
+	All      bool
+}
+
+// ListFilter selects the posts of ListAfter and ListMatching. Published public posts are always
+// listed; AuthorID adds the other posts of that author and All adds every
+// other post. A non-empty Tag only lists posts carrying it.
+type ListFilter struct {
+	Tag      string
+	AuthorID int
+	All      bool
+}
+
+// PublishedFilter selects published public posts by tag and author; zero fields
+// match every post. FollowedBy only matches the posts of authors the user
+// follows.
+type PublishedFilter struct {
+	Tag        string
+	AuthorID   int
+	FollowedBy int
+}
+
+// ViewFilter selects the posts a reader may look up. Published posts that
+// are not private are always found; AuthorID adds the other posts of that
+// author and All adds every other post.
+type ViewFilter struct {
+	AuthorID int
+	All      bool
+}
+
+// ReadRepository defines the read side of post data access. Consumers that only
+// read posts depend on it, so reads can be routed or cached separately.
+// Posts come with the languages they are translated to. Lookups find posts
+// of any visibility, except the GetVisible ones; listings and rankings of
+// published posts leave out unlisted and private posts.
+type ReadRepository interface {
 	GetByID(ctx context.Context, id int) (*Post, error)
+	GetBySlug(ctx context.Context, slug string) (*Post, error)
+	// GetVisibleByID retrieves a post by ID when the filter finds it, so
+	// posts hidden from the reader are not found
+	GetVisibleByID(ctx context.Context, id int, filter ViewFilter) (*Post, error)
+	// GetVisibleBySlug retrieves a post by slug when the filter finds it
+	GetVisibleBySlug(ctx context.Context, slug string, filter ViewFilter) (*Post, error)
+	// GetByClientID retrieves a post by the client ID it was created with
+	GetByClientID(ctx context.Context, clientID string) (*Post, error)
 	GetByAuthorID(ctx context.Context, authorID int, limit, offset int) ([]*Post, error)
+	// List lists all posts, pinned posts first, then the most recently
+	// created
 	List(ctx context.Context, limit, offset int) ([]*Post, error)
+	// ListAfter lists the posts matching the filter that come after the
+	// cursor, most recently created first
+	ListAfter(ctx context.Context, filter ListFilter, after keyset.Cursor, limit int) ([]*Post, error)
+	// ListMatching lists the posts matching the filter and options in the
+	// order the options give
+	ListMatching(ctx context.Context, filter ListFilter, options ListOptions, limit, offset int) ([]*Post, error)
+	// ListPublished lists published posts, pinned posts first, then the most
+	// recently published
+	ListPublished(ctx context.Context, limit, offset int) ([]*Post, error)
+	// ListPublishedBy lists the published posts matching the filter, most
+	// recently published first
+	ListPublishedBy(ctx context.Context, filter PublishedFilter, limit, offset int) ([]*Post, error)
+	// ListVisibleTo lists published public posts and the user's own other
+	// posts, pinned posts first
+	ListVisibleTo(ctx context.Context, userID int, limit, offset int) ([]*Post, error)
+	// ListByTag lists the posts carrying a tag, pinned posts first, then the
+	// most recently created
+	ListByTag(ctx context.Context, filter TagFilter, limit, offset int) ([]*Post, error)
+	// ListPublishingBetween lists the scheduled and published posts whose
+	// publish time is within [from, to], earliest first
+	ListPublishingBetween(ctx context.Context, from, to time.Time) ([]*Post, error)
+	// ListDueScheduled lists scheduled posts whose publish time is not after now
+	ListDueScheduled(ctx context.Context, now time.Time) ([]*Post, error)
+	// ListRevisions lists the revisions of a post, newest first
+	ListRevisions(ctx context.Context, postID int) ([]*Revision, error)
+	// GetRevision retrieves a revision of a post by its number
+	GetRevision(ctx context.Context, postID, number int) (*Revision, error)
+	// GetTranslation retrieves the translation of a post to a language
+	GetTranslation(ctx context.Context, postID int, language string) (*Translation, error)
+	// ListPopular lists the published posts viewed on or after the since
+	// day, most viewed first
+	ListPopular(ctx context.Context, since time.Time, limit int) ([]*PopularPost, error)
+	// ListFeatured lists the featured published posts, most recently
+	// featured first
+	ListFeatured(ctx context.Context, limit, offset int) ([]*Post, error)
+	// ListPublishedByIDs lists the published posts among the given IDs, in
+	// no particular order; unknown IDs are skipped
+	ListPublishedByIDs(ctx context.Context, ids []int) ([]*Post, error)
+	// ListActivity lists the views, bookmarks and approved comments of
+	// published posts since a time; views are listed per day from its day
+	ListActivity(ctx context.Context, since time.Time) ([]Activity, error)
+	// ListSharingTags lists the published posts sharing tags with a post,
+	// those sharing the most tags first, then the most recently published
+	ListSharingTags(ctx context.Context, postID, limit int) ([]*Post, error)
+	// ListMissingReadingStats lists posts after afterID stored before
+	// reading stats were computed, lowest ID first
+	ListMissingReadingStats(ctx context.Context, afterID, limit int) ([]*Post, error)
+}
+
+// WriteRepository defines the write side of post data access
+type WriteRepository interface {
+	Create(ctx context.Context, post *Post) error
 	Update(ctx context.Context, post *Post) error
+	// UpdateWithRevision saves the post together with a revision of its
+	// previous version, numbered after the post's latest revision
+	UpdateWithRevision(ctx context.Context, post *Post, revision *Revision) error
+	// SetTags replaces the tags of a post with the given normalized names
+	SetTags(ctx context.Context, postID int, tags []string) error
+	// ChangeSlug sets the slug of a post and redirects its previous slug to
+	// it; a redirect from the new slug is removed
+	ChangeSlug(ctx context.Context, id int, slug string) error
+	// SaveTranslation saves the translation of a post, replacing the one to
+	// the same language
+	SaveTranslation(ctx context.Context, t *Translation) error
+	// SetVisibility saves the visibility and update time of a post; synced
+	// clients are told to drop posts that stop being public
+	SetVisibility(ctx context.Context, post *Post) error
+	// AddViews adds view counts to the daily and total views of the posts;
+	// counts of deleted posts are dropped
+	AddViews(ctx context.Context, counts []ViewCount) error
+	// SetReadingStats saves the word count, reading time and excerpt of a
+	// post without changing its update time
+	SetReadingStats(ctx context.Context, post *Post) error
 	Delete(ctx context.Context, id int) error
 }
+
+// Repository defines the interface for post data access
+type Repository interface {
+	ReadRepository
+	WriteRepository
+}
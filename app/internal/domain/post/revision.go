@@ -0,0 +1,136 @@
+package post
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrRevisionNotFound is returned for unknown revision numbers
+var ErrRevisionNotFound = errors.New("post revision not found")
+
+// maxDiffCells bounds the work of DiffLines; larger changes are reported as
+// the whole old text removed and the whole new text added
+const maxDiffCells = 1_000_000
+
+// Revision is an earlier version of a post. A revision is saved with the
+// post's title and content every time they are replaced, and numbered from
+// 1 per post.
+type Revision struct {
+	ID      int    `json:"id" db:"id"`
+	PostID  int    `json:"post_id" db:"post_id"`
+	Number  int    `json:"number" db:"number"`
+	Title   string `json:"title" db:"title"`
+	Content string `json:"content" db:"content"`
+	// EditedBy is the user whose change replaced this version
+	EditedBy  int       `json:"edited_by" db:"edited_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewRevision captures the current version of a post before userID changes it.
+// The repository assigns the number when the revision is saved.
+func NewRevision(p *Post, userID int) *Revision {
+	return &Revision{
+		PostID:    p.ID,
+		Title:     p.Title,
+		Content:   p.Content,
+		EditedBy:  userID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// DiffOp tells whether a line was kept, added or removed
+type DiffOp string
+
+// Diff operations, in the notation of unified diffs
+const (
+	DiffEqual  DiffOp = "="
+	DiffInsert DiffOp = "+"
+	DiffDelete DiffOp = "-"
+)
+
+// DiffLine is a line of a line-based diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// DiffLines returns the line-based diff that turns from into to, keeping
+// the longest common sequence of lines
+func DiffLines(from, to string) []DiffLine {
+	a, b := splitLines(from), splitLines(to)
+
+	// Common leading and trailing lines need no comparison
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	diff = append(diff, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	return diff
+}
+
+// diffMiddle diffs the lines between the common prefix and suffix
+func diffMiddle(a, b []string) []DiffLine {
+	var diff []DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the longest common sequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		default:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		}
+	}
+	return diff
+}
+
+// splitLines splits text into lines; empty text has no lines
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
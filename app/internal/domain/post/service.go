@@ -2,14 +2,80 @@ package post
 
 import (
 	"context"
+	"time"
+
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/user"
 )
 
 // Service defines the interface for post business logic
 type Service interface {
 	CreatePost(ctx context.Context, userID int, title, content string) (*Post, error)
+	CreateDraft(ctx context.Context, userID int, title, content string) (*Post, error)
+	// CreateClientPost creates a post, or a draft, under a UUID the client
+	// generated. Retrying with the same client ID returns the post created
+	// first with created false, so offline clients can resend safely.
+	CreateClientPost(ctx context.Context, userID int, clientID, title, content string, draft bool) (p *Post, created bool, err error)
 	GetPost(ctx context.Context, id int) (*Post, error)
+	// GetVisiblePost retrieves a post the user may read; drafts, scheduled
+	// and private posts of others are not found
+	GetVisiblePost(ctx context.Context, userID int, role user.Role, id int) (*Post, error)
+	GetPostBySlug(ctx context.Context, slug string) (*Post, error)
+	// GetPostByClientID retrieves a post by the client ID it was created with
+	GetPostByClientID(ctx context.Context, clientID string) (*Post, error)
 	GetPostsByAuthor(ctx context.Context, authorID int, limit, offset int) ([]*Post, error)
 	ListPosts(ctx context.Context, limit, offset int) ([]*Post, error)
-	UpdatePost(ctx context.Context, userID, postID int, title, content string) (*Post, error)
-	DeletePost(ctx context.Context, userID, postID int) error
+	ListPublishedPosts(ctx context.Context, filter PublishedFilter, limit, offset int) ([]*Post, error)
+	ListPostsFor(ctx context.Context, userID int, role user.Role, limit, offset int) ([]*Post, error)
+	ListPostsByTag(ctx context.Context, userID int, role user.Role, tag string, limit, offset int) ([]*Post, error)
+	// ListPostsMatching returns the posts matching the tag and options,
+	// which may be empty, with the visibility of ListPostsByTag
+	ListPostsMatching(ctx context.Context, userID int, role user.Role, tag string, options ListOptions, limit, offset int) ([]*Post, error)
+	// ListPostsAfter returns the page of posts after the cursor and the
+	// cursor of the next page, which is zero on the last page
+	ListPostsAfter(ctx context.Context, userID int, role user.Role, tag string, after keyset.Cursor, limit int) ([]*Post, keyset.Cursor, error)
+	ListPopularPosts(ctx context.Context, days, limit int) ([]*PopularPost, error)
+	// ListFeaturedPosts lists the featured published posts, most recently
+	// featured first
+	ListFeaturedPosts(ctx context.Context, limit, offset int) ([]*Post, error)
+	// ListTrendingPosts lists the published posts with the highest trending
+	// scores, highest first
+	ListTrendingPosts(ctx context.Context, limit int) ([]*TrendingPost, error)
+	// ListRelatedPosts lists published posts related to a post, most
+	// related first
+	ListRelatedPosts(ctx context.Context, p *Post, limit int) ([]*Post, error)
+	// GatePost returns the post as the reader may see it: the post itself
+	// or a locked teaser. Shared caches must hold the anonymous variant,
+	// gated with a zero userID.
+	GatePost(ctx context.Context, userID int, role user.Role, p *Post) *Post
+	GatePosts(ctx context.Context, userID int, role user.Role, posts []*Post) []*Post
+	RecordView(ctx context.Context, p *Post)
+	UpdatePost(ctx context.Context, userID int, role user.Role, postID int, title, content string) (*Post, error)
+	SetNoIndex(ctx context.Context, userID int, role user.Role, postID int, noindex bool) (*Post, error)
+	SetAccess(ctx context.Context, userID int, role user.Role, postID int, access Access) (*Post, error)
+	// SetVisibility makes a post public, unlisted or private
+	SetVisibility(ctx context.Context, userID int, role user.Role, postID int, visibility Visibility) (*Post, error)
+	// SetPinned pins a published post to the top of the post lists, or
+	// unpins it (admins only)
+	SetPinned(ctx context.Context, adminID, postID int, pinned bool) (*Post, error)
+	// SetFeatured adds a published post to the featured set, or removes it
+	// (admins only)
+	SetFeatured(ctx context.Context, adminID, postID int, featured bool) (*Post, error)
+	// ChangeSlug sets the slug of a post; links to the previous slug keep
+	// working through a redirect
+	ChangeSlug(ctx context.Context, userID int, role user.Role, postID int, slug string) (*Post, error)
+	SetTags(ctx context.Context, userID int, role user.Role, postID int, tags []string) (*Post, error)
+	PublishPost(ctx context.Context, userID int, role user.Role, postID int) (*Post, error)
+	SchedulePost(ctx context.Context, userID int, role user.Role, postID int, publishAt time.Time, timezone string) (*Post, []Conflict, error)
+	Calendar(ctx context.Context, userID int, role user.Role, from, to time.Time) ([]*CalendarEntry, error)
+	ListRevisions(ctx context.Context, userID int, role user.Role, postID int) (*Post, []*Revision, error)
+	RestoreRevision(ctx context.Context, userID int, role user.Role, postID, number int) (*Post, error)
+	// TranslatePost saves the translation of a post to a language and
+	// returns the post in it; created is false when it replaced an earlier
+	// translation to that language
+	TranslatePost(ctx context.Context, userID int, role user.Role, postID int, language, title, content string) (p *Post, created bool, err error)
+	// LocalizePost returns the post in the language that best matches an
+	// Accept-Language header, or the post itself
+	LocalizePost(ctx context.Context, p *Post, acceptLanguage string) (*Post, error)
+	DeletePost(ctx context.Context, userID int, role user.Role, postID int) error
 }
@@ -0,0 +1,75 @@
+package post
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength bounds the length of generated slugs
+const maxSlugLength = 100
+
+// fallbackSlug is used for titles without any letters or digits
+const fallbackSlug = "post"
+
+// Slugify builds a URL-friendly slug from a title: lowercase ASCII letters
+// and digits separated by single hyphens, e.g. "Hello, World!" becomes
+// "hello-world".
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			pendingHyphen = true
+		}
+
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return fallbackSlug
+	}
+	return slug
+}
+
+// NumberedSlug returns the nth variant of a slug, used when the slug is
+// already taken: "hello-world" becomes "hello-world-2" for n = 2.
+func NumberedSlug(base string, n int) string {
+	return fmt.Sprintf("%s-%d", base, n)
+}
+
+// NextSlug returns the variant of base to try after slug turned out to be
+// taken: "hello-world" and "hello-world-2" are followed by "hello-world-2"
+// and "hello-world-3".
+func NextSlug(base, slug string) string {
+	n := 1
+	if suffix, ok := strings.CutPrefix(slug, base+"-"); ok {
+		if k, err := strconv.Atoi(suffix); err == nil && k > 1 {
+			n = k
+		}
+	}
+	return NumberedSlug(base, n+1)
+}
+
+// IsValidSlug reports whether a slug chosen by hand is one Slugify could
+// have produced: lowercase ASCII letters and digits separated by single
+// hyphens, at most maxSlugLength long
+func IsValidSlug(slug string) bool {
+	return slug != "" && len(slug) <= maxSlugLength && Slugify(slug) == slug
+}
@@ -0,0 +1,130 @@
+package post
+
+import (
+	"errors"
+	"time"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Status is the publication state of a post
+type Status string
+
+// Available statuses
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+	StatusScheduled Status = "scheduled"
+)
+
+// localTimeLayouts are the accepted layouts of publish times without a UTC
+// offset, read as wall-clock time in the post's time zone
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// Publication errors
+var (
+	ErrAlreadyPublished         = errors.New("post already published")
+	ErrInvalidPublishTime       = errors.New("invalid publish time: must be in the future")
+	ErrInvalidPublishTimeFormat = errors.New("invalid publish time: must be an RFC 3339 time or a local time like 2024-03-10T09:00")
+	ErrInvalidTimezone          = errors.New("invalid timezone: must be an IANA time zone name like Europe/Berlin")
+)
+
+// IsPublished checks if the post is public
+func (p *Post) IsPublished() bool {
+	return p.Status == StatusPublished
+}
+
+// IsVisibleTo checks if the given user may read the post: everyone can read
+// published posts that are not private, drafts, scheduled and private posts
+// only show to those who can modify them. Pass a zero userID for anonymous
+// readers.
+func (p *Post) IsVisibleTo(userID int, role user.Role) bool {
+	return (p.IsPublished() && !p.IsPrivate()) || (userID > 0 && p.CanModify(userID, role))
+}
+
+// Publish makes a draft or scheduled post public as of now
+func (p *Post) Publish(now time.Time) error {
+	if p.IsPublished() {
+		return ErrAlreadyPublished
+	}
+	p.Status = StatusPublished
+	p.PublishedAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+// Schedule sets a draft or scheduled post to be published at the given time.
+// The time zone is kept to show the publish time as it was planned.
+func (p *Post) Schedule(at time.Time, timezone string, now time.Time) error {
+	if p.IsPublished() {
+		return ErrAlreadyPublished
+	}
+	if _, err := LoadTimezone(timezone); err != nil {
+		return err
+	}
+	if !at.After(now) {
+		return ErrInvalidPublishTime
+	}
+	// Times are kept in UTC like the ones read from the database
+	at = at.UTC()
+	p.Status = StatusScheduled
+	p.PublishedAt = &at
+	p.Timezone = timezone
+	p.UpdatedAt = now
+	return nil
+}
+
+// LocalPublishedAt returns the publish time in the post's time zone
+func (p *Post) LocalPublishedAt() *time.Time {
+	if p.PublishedAt == nil {
+		return nil
+	}
+	loc, err := LoadTimezone(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := p.PublishedAt.In(loc)
+	return &local
+}
+
+// LoadTimezone returns the location of an IANA time zone name; the empty
+// name is UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// LoadLocation also accepts "Local", which depends on the server
+	if name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// ParsePublishTime parses a publish time. Times with a UTC offset are taken
+// as they are; local times are placed in the time zone, so a post planned
+// for 09:00 is published at 09:00 local time on either side of a daylight
+// saving change.
+func ParsePublishTime(value, timezone string) (time.Time, error) {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, ErrInvalidPublishTimeFormat
+}
+
+// IsDue checks if a scheduled post has reached its publish time
+func (p *Post) IsDue(now time.Time) bool {
+	return p.Status == StatusScheduled && p.PublishedAt != nil && !p.PublishedAt.After(now)
+}
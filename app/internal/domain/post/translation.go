@@ -0,0 +1,127 @@
+package post
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language posts are written in unless set
+// otherwise
+const DefaultLanguage = "en"
+
+// maxLanguageLength bounds the length of language codes
+const maxLanguageLength = 35
+
+// Translation errors
+var (
+	ErrInvalidLanguage     = errors.New("invalid language: must be a language code like en or pt-BR")
+	ErrSameLanguage        = errors.New("invalid translation: the post is written in that language")
+	ErrTranslationNotFound = errors.New("post translation not found")
+)
+
+// Translation is the title and content of a post in another language than
+// it was written in. A post has at most one translation per language.
+type Translation struct {
+	ID       int    `json:"id" db:"id"`
+	PostID   int    `json:"post_id" db:"post_id"`
+	Language string `json:"language" db:"language"`
+	Title    string `json:"title" db:"title"`
+	Content  string `json:"content" db:"content"`
+	// TranslatedBy is the user who last saved the translation
+	TranslatedBy int       `json:"translated_by" db:"translated_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ParseLanguage validates a BCP 47 language code and returns it in
+// canonical form, so pt-br and pt-BR name the same translation
+func ParseLanguage(code string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(code))
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLanguage
+	}
+	canonical := tag.String()
+	if len(canonical) > maxLanguageLength {
+		return "", ErrInvalidLanguage
+	}
+	return canonical, nil
+}
+
+// NewTranslation creates a translation of the post to the language by
+// userID
+func NewTranslation(p *Post, code, title, content string, userID int) (*Translation, error) {
+	lang, err := ParseLanguage(code)
+	if err != nil {
+		return nil, err
+	}
+	if lang == p.Language {
+		return nil, ErrSameLanguage
+	}
+
+	now := time.Now()
+	return &Translation{
+		PostID:       p.ID,
+		Language:     lang,
+		Title:        strings.TrimSpace(title),
+		Content:      strings.TrimSpace(content),
+		TranslatedBy: userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// Languages lists the languages the post can be read in, its own first
+func (p *Post) Languages() []string {
+	return append([]string{p.Language}, p.Translations...)
+}
+
+// NegotiateLanguage picks the language of the post that best matches an
+// Accept-Language header. The post's own language is picked when the header
+// is empty or invalid, or matches none of its translations.
+func (p *Post) NegotiateLanguage(acceptLanguage string) string {
+	if len(p.Translations) == 0 || strings.TrimSpace(acceptLanguage) == "" {
+		return p.Language
+	}
+	preferred, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(preferred) == 0 {
+		return p.Language
+	}
+
+	languages := p.Languages()
+	supported := make([]language.Tag, len(languages))
+	for i, lang := range languages {
+		supported[i] = language.Make(lang)
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No {
+		return p.Language
+	}
+	return languages[index]
+}
+
+// Translated returns a copy of the post with the title and content of the
+// translation, whose other languages include the post's own. The post
+// itself is left untouched, like with Teaser.
+func (p *Post) Translated(t *Translation) *Post {
+	translated := *p
+	translated.Language = t.Language
+	translated.Title = t.Title
+	translated.Content = t.Content
+	translated.RefreshReadingStats()
+
+	translated.Translations = []string{p.Language}
+	for _, lang := range p.Translations {
+		if lang != t.Language {
+			translated.Translations = append(translated.Translations, lang)
+		}
+	}
+	sort.Strings(translated.Translations)
+	if t.UpdatedAt.After(p.UpdatedAt) {
+		translated.UpdatedAt = t.UpdatedAt
+	}
+	return &translated
+}
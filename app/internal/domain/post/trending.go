@@ -0,0 +1,89 @@
+package post
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Limits and defaults of trending post rankings
+const (
+	DefaultTrendingLimit = 10
+	// MaxTrendingLimit bounds the number of trending posts ranked and listed
+	MaxTrendingLimit = 50
+	// DefaultTrendingWindow is how far back activity counts by default
+	DefaultTrendingWindow = 72 * time.Hour
+	// DefaultTrendingHalfLife is the age at which activity counts half by
+	// default
+	DefaultTrendingHalfLife = 24 * time.Hour
+)
+
+// ActivityKind is a kind of reader activity on a post
+type ActivityKind string
+
+// Kinds of reader activity counted towards trending scores
+const (
+	ActivityView     ActivityKind = "view"
+	ActivityBookmark ActivityKind = "bookmark"
+	ActivityComment  ActivityKind = "comment"
+)
+
+// trendingWeights is what one event of each kind adds to a trending score
+// before decay. Saving a post or commenting on it takes more interest than
+// opening it.
+var trendingWeights = map[ActivityKind]float64{
+	ActivityView:     1,
+	ActivityBookmark: 5,
+	ActivityComment:  10,
+}
+
+// Activity is Count events of a kind on a published post at At. Views are
+// counted per day, so their At is the start of their UTC day.
+type Activity struct {
+	PostID int
+	Kind   ActivityKind
+	At     time.Time
+	Count  int64
+}
+
+// TrendingScore is the trending score of a post
+type TrendingScore struct {
+	PostID int
+	Score  float64
+}
+
+// TrendingPost is a published post with its trending score
+type TrendingPost struct {
+	Post  *Post
+	Score float64
+}
+
+// RankTrending scores posts by their activity and returns the limit highest
+// scores, highest first. Each event is weighed by its kind and its weight
+// halves every halfLife, so recent activity counts the most.
+func RankTrending(activity []Activity, now time.Time, halfLife time.Duration, limit int) []TrendingScore {
+	scores := make(map[int]float64)
+	for _, a := range activity {
+		at := a.At
+		if a.Kind == ActivityView {
+			// A day of views is taken as viewed at its middle
+			at = at.Add(12 * time.Hour)
+		}
+		age := max(now.Sub(at), 0)
+		scores[a.PostID] += float64(a.Count) * trendingWeights[a.Kind] * math.Exp2(-age.Hours()/halfLife.Hours())
+	}
+
+	ranked := make([]TrendingScore, 0, len(scores))
+	for id, score := range scores {
+		if score > 0 {
+			ranked = append(ranked, TrendingScore{PostID: id, Score: score})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].PostID > ranked[j].PostID
+	})
+	return ranked[:min(limit, len(ranked))]
+}
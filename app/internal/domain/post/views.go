@@ -0,0 +1,31 @@
+package post
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxPopularWindow bounds the number of days popular posts are ranked over
+const MaxPopularWindow = 90
+
+// ErrInvalidPopularWindow is returned for windows outside 1 to 90 days
+var ErrInvalidPopularWindow = errors.New("invalid popular window: days must be between 1 and 90")
+
+// ViewCount is the number of views a post got on a day
+type ViewCount struct {
+	PostID int
+	// Day is the UTC date the views were counted on
+	Day   time.Time
+	Views int64
+}
+
+// PopularPost is a published post with its views over a window
+type PopularPost struct {
+	Post  *Post
+	Views int64
+}
+
+// ViewDay returns the UTC date views at t are counted on
+func ViewDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,38 @@
+package post
+
+import "errors"
+
+// Visibility is who may find and read a published post
+type Visibility string
+
+// Available visibilities
+const (
+	VisibilityPublic Visibility = "public"
+	// VisibilityUnlisted posts are read by link but left out of listings
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPrivate posts only show to their author and admins
+	VisibilityPrivate Visibility = "private"
+)
+
+// ErrInvalidVisibility is returned for unknown visibilities
+var ErrInvalidVisibility = errors.New("invalid visibility: must be public, unlisted or private")
+
+// IsValid checks if the visibility is known
+func (v Visibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// IsListed checks if the post belongs in listings, feeds and rankings:
+// only published public posts do
+func (p *Post) IsListed() bool {
+	return p.IsPublished() && p.Visibility == VisibilityPublic
+}
+
+// IsPrivate checks if the post only shows to its author and admins
+func (p *Post) IsPrivate() bool {
+	return p.Visibility == VisibilityPrivate
+}
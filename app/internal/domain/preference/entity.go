@@ -0,0 +1,68 @@
+// Package preference holds the settings authors and administrators choose
+// for how their content is presented
+package preference
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Feed scopes
+const (
+	ScopeAuthor = "author"
+	ScopeTag    = "tag"
+)
+
+// Feed limits
+const (
+	maxFeedTitleLength       = 200
+	maxFeedDescriptionLength = 1000
+)
+
+// Preference errors
+var (
+	ErrFeedNotFound           = errors.New("feed preference not found")
+	ErrInvalidFeedTitle       = errors.New("invalid feed title: must be at most 200 characters on a single line")
+	ErrInvalidFeedDescription = errors.New("invalid feed description: must be at most 1000 characters")
+)
+
+// Feed customizes the channel of an author's or a tag's RSS feed. Empty
+// fields keep the generated title and description.
+type Feed struct {
+	Scope string `db:"scope"`
+	// Key is the author's user ID or the normalized tag name
+	Key         string    `db:"scope_key"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	UpdatedBy   int       `db:"updated_by"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// NewFeed creates a feed preference, validating the title and description
+func NewFeed(scope, key, title, description string, updatedBy int) (*Feed, error) {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > maxFeedTitleLength || strings.ContainsAny(title, "\r\n") {
+		return nil, ErrInvalidFeedTitle
+	}
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > maxFeedDescriptionLength {
+		return nil, ErrInvalidFeedDescription
+	}
+
+	return &Feed{
+		Scope:       scope,
+		Key:         key,
+		Title:       title,
+		Description: description,
+		UpdatedBy:   updatedBy,
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// AuthorKey returns the key of an author's feed preference
+func AuthorKey(authorID int) string {
+	return strconv.Itoa(authorID)
+}
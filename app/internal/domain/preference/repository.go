@@ -0,0 +1,11 @@
+package preference
+
+import "context"
+
+// Repository defines the interface for preference data access
+type Repository interface {
+	// GetFeed retrieves the feed preference of an author or tag
+	GetFeed(ctx context.Context, scope, key string) (*Feed, error)
+	// SaveFeed creates or replaces the feed preference of its scope and key
+	SaveFeed(ctx context.Context, f *Feed) error
+}
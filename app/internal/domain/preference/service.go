@@ -0,0 +1,16 @@
+package preference
+
+import "context"
+
+// Service defines the interface for preference business logic
+type Service interface {
+	// AuthorFeed returns the feed preference of an author, or an empty one
+	// when the author has not customized their feed
+	AuthorFeed(ctx context.Context, authorID int) (*Feed, error)
+	// TagFeed returns the feed preference of a tag, or an empty one
+	TagFeed(ctx context.Context, tag string) (*Feed, error)
+	// SetAuthorFeed customizes the feed of the user's own posts
+	SetAuthorFeed(ctx context.Context, userID int, title, description string) (*Feed, error)
+	// SetTagFeed customizes the feed of a tag
+	SetTagFeed(ctx context.Context, adminID int, tag, title, description string) (*Feed, error)
+}
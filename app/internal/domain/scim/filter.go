@@ -0,0 +1,63 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Filterable attributes
+const (
+	FilterUserName    = "userName"
+	FilterExternalID  = "externalId"
+	FilterEmail       = "emails.value"
+	FilterDisplayName = "displayName"
+)
+
+// filterAttributes maps the lowercase filterable attributes to their names;
+// attribute names are case-insensitive
+var filterAttributes = map[string]string{
+	"username":     FilterUserName,
+	"externalid":   FilterExternalID,
+	"emails.value": FilterEmail,
+	"emails":       FilterEmail,
+	"displayname":  FilterDisplayName,
+}
+
+// Filter is an equality comparison of a list filter, e.g.
+// userName eq "jane@example.com". Identity providers use these to look up
+// a resource before creating it.
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter of the form `attribute eq "value"`
+func ParseFilter(s string) (*Filter, error) {
+	attribute, value, err := parseComparison(s)
+	if err != nil {
+		return nil, err
+	}
+	name, ok := filterAttributes[strings.ToLower(attribute)]
+	if !ok {
+		return nil, ErrInvalidFilter
+	}
+	return &Filter{Attribute: name, Value: value}, nil
+}
+
+// parseComparison splits an `attribute eq "value"` comparison
+func parseComparison(s string) (string, string, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return "", "", ErrInvalidFilter
+	}
+	operator, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return "", "", ErrInvalidFilter
+	}
+
+	var decoded string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &decoded); err != nil {
+		return "", "", ErrInvalidFilter
+	}
+	return attribute, decoded, nil
+}
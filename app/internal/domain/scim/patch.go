@@ -0,0 +1,284 @@
+package scim
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is an operation of a PATCH request (RFC 7644 section 3.5.2)
+type PatchOperation struct {
+	// Op is "add", "replace" or "remove"; some clients capitalize it
+	Op string `json:"op"`
+	// Path names the attribute; without a path the value is an object of
+	// attributes
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyPatch applies the operations of a PATCH request to the attributes.
+// Given and family names are accepted but not kept, as accounts have a
+// single display name.
+func (a *UserAttributes) ApplyPatch(ops []PatchOperation) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path != "" {
+				if err := a.set(op.Path, op.Value); err != nil {
+					return err
+				}
+				continue
+			}
+			values, err := attributeObject(op.Value)
+			if err != nil {
+				return err
+			}
+			for _, path := range sortedKeys(values) {
+				if err := a.set(path, values[path]); err != nil {
+					return err
+				}
+			}
+		case "remove":
+			if !strings.EqualFold(op.Path, "externalId") {
+				return ErrInvalidPath
+			}
+			a.ExternalID = ""
+		default:
+			return ErrInvalidValue
+		}
+	}
+	return nil
+}
+
+// set replaces the attribute at path
+func (a *UserAttributes) set(path string, value json.RawMessage) error {
+	path = strings.ToLower(path)
+	switch {
+	case path == "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		a.Active = active
+	case path == "username":
+		return decodeString(value, &a.UserName)
+	case path == "externalid":
+		return decodeString(value, &a.ExternalID)
+	case path == "displayname" || path == "name.formatted":
+		return decodeString(value, &a.Name)
+	case path == "name":
+		var name Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return ErrInvalidValue
+		}
+		if formatted := name.String(); formatted != "" {
+			a.Name = formatted
+		}
+	case path == "name.givenname" || path == "name.familyname":
+		return nil
+	case path == "emails":
+		var emails []Email
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return ErrInvalidValue
+		}
+		if email := PrimaryEmail(emails); email != "" {
+			a.Email = email
+		}
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value"):
+		return decodeString(value, &a.Email)
+	default:
+		return ErrInvalidPath
+	}
+	return nil
+}
+
+// Name is the name attribute of a user
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// String returns the formatted name, or the given and family names
+func (n Name) String() string {
+	if n.Formatted != "" {
+		return n.Formatted
+	}
+	return strings.TrimSpace(n.GivenName + " " + n.FamilyName)
+}
+
+// Email is an address of the emails attribute of a user
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// PrimaryEmail returns the primary address, or the first one
+func PrimaryEmail(emails []Email) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// ApplyPatch applies the operations of a PATCH request to the group
+func (g *Group) ApplyPatch(ops []PatchOperation) error {
+	displayName, externalID, members := g.DisplayName, g.ExternalID, slices.Clone(g.Members)
+
+	for _, op := range ops {
+		kind := strings.ToLower(op.Op)
+		path := strings.ToLower(op.Path)
+		switch {
+		case kind != "add" && kind != "replace" && kind != "remove":
+			return ErrInvalidValue
+		case path == "" && kind != "remove":
+			values, err := attributeObject(op.Value)
+			if err != nil {
+				return err
+			}
+			for key, value := range values {
+				switch strings.ToLower(key) {
+				case "displayname":
+					err = decodeString(value, &displayName)
+				case "externalid":
+					err = decodeString(value, &externalID)
+				case "members":
+					var ids []int
+					ids, err = parseMembers(value)
+					if kind == "add" {
+						ids = append(members, ids...)
+					}
+					members = ids
+				default:
+					err = ErrInvalidPath
+				}
+				if err != nil {
+					return err
+				}
+			}
+		case path == "displayname" && kind != "remove":
+			if err := decodeString(op.Value, &displayName); err != nil {
+				return err
+			}
+		case path == "externalid":
+			externalID = ""
+			if kind != "remove" {
+				if err := decodeString(op.Value, &externalID); err != nil {
+					return err
+				}
+			}
+		case path == "members":
+			var ids []int
+			if kind != "remove" || len(op.Value) > 0 {
+				var err error
+				if ids, err = parseMembers(op.Value); err != nil {
+					return err
+				}
+			}
+			switch {
+			case kind == "add":
+				members = append(members, ids...)
+			case kind == "replace":
+				members = ids
+			case len(ids) == 0:
+				members = nil
+			default:
+				members = slices.DeleteFunc(members, func(id int) bool { return slices.Contains(ids, id) })
+			}
+		case strings.HasPrefix(path, "members[") && kind == "remove":
+			id, err := memberFilterID(op.Path)
+			if err != nil {
+				return err
+			}
+			members = slices.DeleteFunc(members, func(member int) bool { return member == id })
+		default:
+			return ErrInvalidPath
+		}
+	}
+
+	return g.Update(displayName, externalID, members)
+}
+
+// memberFilterID parses the ID of a path like members[value eq "12"]
+func memberFilterID(path string) (int, error) {
+	attribute, value, err := parseComparison(strings.TrimSuffix(path[len("members["):], "]"))
+	if err != nil || !strings.EqualFold(attribute, "value") {
+		return 0, ErrInvalidPath
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, ErrInvalidValue
+	}
+	return id, nil
+}
+
+// parseMembers parses a list of member references, e.g. [{"value": "12"}]
+func parseMembers(value json.RawMessage) ([]int, error) {
+	var refs []struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(value, &refs); err != nil {
+		return nil, ErrInvalidValue
+	}
+	ids := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		id, err := strconv.Atoi(ref.Value)
+		if err != nil || id <= 0 {
+			return nil, ErrInvalidValue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// attributeObject decodes the value of an operation without a path
+func attributeObject(value json.RawMessage) (map[string]json.RawMessage, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(value, &values); err != nil {
+		return nil, ErrInvalidValue
+	}
+	return values, nil
+}
+
+// decodeString decodes a string value
+func decodeString(value json.RawMessage, dst *string) error {
+	if err := json.Unmarshal(value, dst); err != nil {
+		return ErrInvalidValue
+	}
+	return nil
+}
+
+// parseBool decodes a boolean; some clients send "True" and "False" strings
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, ErrInvalidValue
+	}
+	b, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, ErrInvalidValue
+	}
+	return b, nil
+}
+
+// sortedKeys returns the keys of an attribute object in a stable order
+func sortedKeys(values map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+// Package scim provisions accounts from the identity providers of
+// organizations using SCIM 2.0 (RFC 7643 and RFC 7644). Each organization is
+// a tenant that sees and manages only the users and groups it provisioned.
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// SCIM errors
+var (
+	ErrUserNotFound  = errors.New("provisioned user not found")
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrUserClaimed is returned when an account exists for the address but
+	// was not provisioned by the tenant
+	ErrUserClaimed  = errors.New("user already exists")
+	ErrGroupExists  = errors.New("group already exists")
+	ErrInvalidValue = errors.New("invalid attribute value")
+	// ErrInvalidFilter is returned for filters other than the supported
+	// equality comparisons
+	ErrInvalidFilter = errors.New("invalid filter: only eq comparisons of a single attribute are supported")
+	ErrInvalidPath   = errors.New("invalid patch path")
+)
+
+// Limits of provisioned attributes
+const (
+	maxExternalIDLength  = 255
+	maxUserNameLength    = 255
+	maxDisplayNameLength = 255
+)
+
+// Account links a user to the tenant that provisioned it. A user belongs to
+// one tenant at most.
+type Account struct {
+	Tenant string `db:"tenant"`
+	UserID int    `db:"user_id"`
+	// UserName is the identifier the identity provider knows the user by,
+	// usually the email address
+	UserName   string    `db:"user_name"`
+	ExternalID string    `db:"external_id"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// User is a provisioned account as SCIM clients see it
+type User struct {
+	ID         int
+	ExternalID string
+	UserName   string
+	Name       string
+	Email      string
+	Active     bool
+	Groups     []GroupRef
+	Created    time.Time
+	Modified   time.Time
+}
+
+// UserAttributes are the attributes of a user a client sets
+type UserAttributes struct {
+	ExternalID string
+	UserName   string
+	Name       string
+	Email      string
+	Active     bool
+}
+
+// Validate checks and normalizes the attributes. The email defaults to the
+// user name and the name to the email.
+func (a *UserAttributes) Validate() error {
+	a.UserName = strings.TrimSpace(a.UserName)
+	a.Email = strings.TrimSpace(a.Email)
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Email == "" {
+		a.Email = a.UserName
+	}
+	if a.Name == "" {
+		a.Name = a.Email
+	}
+
+	if a.UserName == "" || len(a.UserName) > maxUserNameLength || len(a.ExternalID) > maxExternalIDLength {
+		return ErrInvalidValue
+	}
+	if address, err := mail.ParseAddress(a.Email); err != nil || address.Address != a.Email {
+		return ErrInvalidValue
+	}
+	return nil
+}
+
+// Group is a named set of users of a tenant
+type Group struct {
+	ID          int       `db:"id"`
+	Tenant      string    `db:"tenant"`
+	DisplayName string    `db:"display_name"`
+	ExternalID  string    `db:"external_id"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	// Members are the IDs of the users in the group
+	Members []int `db:"-"`
+}
+
+// GroupRef refers to a group a user is a member of
+type GroupRef struct {
+	ID          int    `db:"id"`
+	DisplayName string `db:"display_name"`
+}
+
+// NewGroup creates a group of a tenant
+func NewGroup(tenant, displayName, externalID string, members []int) (*Group, error) {
+	g := &Group{Tenant: tenant, CreatedAt: time.Now()}
+	if err := g.Update(displayName, externalID, members); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Update replaces the attributes of a group
+func (g *Group) Update(displayName, externalID string, members []int) error {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" || len(displayName) > maxDisplayNameLength || len(externalID) > maxExternalIDLength {
+		return ErrInvalidValue
+	}
+
+	g.DisplayName = displayName
+	g.ExternalID = externalID
+	g.Members = uniqueIDs(members)
+	g.UpdatedAt = time.Now()
+	return nil
+}
+
+// uniqueIDs removes duplicate IDs, keeping the first occurrence
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Page selects a range of a list; StartIndex is 1-based as in SCIM
+type Page struct {
+	StartIndex int
+	Count      int
+}
+
+// Offset returns the number of items skipped by the page
+func (p Page) Offset() int {
+	if p.StartIndex < 1 {
+		return 0
+	}
+	return p.StartIndex - 1
+}
+
+// AccountRepository defines the interface for the storage of provisioned
+// accounts
+type AccountRepository interface {
+	// Create links a user to a tenant; it returns ErrUserClaimed if the
+	// user belongs to a tenant or the user name or external ID is taken
+	Create(ctx context.Context, account *Account) error
+	Get(ctx context.Context, tenant string, userID int) (*Account, error)
+	GetByUserName(ctx context.Context, tenant, userName string) (*Account, error)
+	GetByExternalID(ctx context.Context, tenant, externalID string) (*Account, error)
+	// List returns a page of the accounts of a tenant, oldest first, and
+	// the number of accounts
+	List(ctx context.Context, tenant string, offset, limit int) ([]*Account, int, error)
+	Update(ctx context.Context, account *Account) error
+	// Delete unlinks a user from a tenant and removes it from the groups
+	// of the tenant
+	Delete(ctx context.Context, tenant string, userID int) error
+}
+
+// GroupRepository defines the interface for group storage
+type GroupRepository interface {
+	// Create stores a group with its members; it returns ErrGroupExists
+	// for a display name the tenant already uses
+	Create(ctx context.Context, group *Group) error
+	// GetByID returns a group of a tenant with its members
+	GetByID(ctx context.Context, tenant string, id int) (*Group, error)
+	GetByDisplayName(ctx context.Context, tenant, displayName string) (*Group, error)
+	// List returns a page of the groups of a tenant with their members,
+	// oldest first, and the number of groups
+	List(ctx context.Context, tenant string, offset, limit int) ([]*Group, int, error)
+	// Update stores the attributes of a group and replaces its members
+	Update(ctx context.Context, group *Group) error
+	Delete(ctx context.Context, tenant string, id int) error
+	// ListByMember returns the groups of a tenant a user is a member of
+	ListByMember(ctx context.Context, tenant string, userID int) ([]GroupRef, error)
+}
+
+// Tenants authenticates the bearer tokens of SCIM clients
+type Tenants interface {
+	// Authenticate returns the tenant a token was issued to
+	Authenticate(token string) (string, bool)
+}
+
+// Service defines the interface for SCIM provisioning. Every method acts on
+// the users and groups of one tenant.
+type Service interface {
+	CreateUser(ctx context.Context, tenant string, attrs UserAttributes) (*User, error)
+	GetUser(ctx context.Context, tenant string, id int) (*User, error)
+	// ListUsers returns a page of users matching the filter, which may be
+	// nil, and the number of matching users
+	ListUsers(ctx context.Context, tenant string, filter *Filter, page Page) ([]*User, int, error)
+	// ReplaceUser syncs all attributes of a user; inactive users are
+	// deactivated and cannot sign in
+	ReplaceUser(ctx context.Context, tenant string, id int, attrs UserAttributes) (*User, error)
+	PatchUser(ctx context.Context, tenant string, id int, ops []PatchOperation) (*User, error)
+	// DeleteUser deactivates a user and removes it from the tenant. The
+	// account and its posts are kept.
+	DeleteUser(ctx context.Context, tenant string, id int) error
+	CreateGroup(ctx context.Context, tenant, displayName, externalID string, members []int) (*Group, error)
+	GetGroup(ctx context.Context, tenant string, id int) (*Group, error)
+	ListGroups(ctx context.Context, tenant string, filter *Filter, page Page) ([]*Group, int, error)
+	ReplaceGroup(ctx context.Context, tenant string, id int, displayName, externalID string, members []int) (*Group, error)
+	PatchGroup(ctx context.Context, tenant string, id int, ops []PatchOperation) (*Group, error)
+	DeleteGroup(ctx context.Context, tenant string, id int) error
+}
@@ -0,0 +1,116 @@
+package series
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// Series limits
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 1000
+	// MaxPosts bounds how many posts a series can hold
+	MaxPosts = 100
+)
+
+// Series errors
+var (
+	ErrSeriesNotFound     = errors.New("series not found")
+	ErrUnauthorized       = errors.New("unauthorized access to series")
+	ErrInvalidTitle       = errors.New("invalid series: title must be between 1 and 255 characters")
+	ErrInvalidDescription = errors.New("invalid series: description must be at most 1000 characters")
+	ErrTooManyPosts       = errors.New("invalid series: a series can hold at most 100 posts")
+	ErrDuplicatePost      = errors.New("invalid series: a post can only appear once")
+	ErrForeignPost        = errors.New("invalid series: posts must exist and be by the author of the series")
+)
+
+// Series groups posts of an author to be read in order
+type Series struct {
+	ID          int       `db:"id"`
+	AuthorID    int       `db:"author_id"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// Input holds the fields authors set on a series
+type Input struct {
+	Title       string
+	Description string
+}
+
+// NewSeries creates an empty series
+func NewSeries(authorID int, in Input) (*Series, error) {
+	s := &Series{AuthorID: authorID, CreatedAt: time.Now()}
+	if err := s.Update(in); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the title and description of the series
+func (s *Series) Update(in Input) error {
+	title := strings.TrimSpace(in.Title)
+	if title == "" || utf8.RuneCountInString(title) > maxTitleLength {
+		return ErrInvalidTitle
+	}
+	description := strings.TrimSpace(in.Description)
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return ErrInvalidDescription
+	}
+
+	s.Title = title
+	s.Description = description
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// CanModify checks if the user can change the series and its posts
+func (s *Series) CanModify(userID int, role user.Role) bool {
+	return s.AuthorID == userID || role.IsAdmin()
+}
+
+// CheckOrder checks the post IDs given to order a series
+func CheckOrder(postIDs []int) error {
+	if len(postIDs) > MaxPosts {
+		return ErrTooManyPosts
+	}
+	seen := make(map[int]bool, len(postIDs))
+	for _, id := range postIDs {
+		if seen[id] {
+			return ErrDuplicatePost
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Entry is a post of a series with the posts before and after it, for
+// readers to move through the series
+type Entry struct {
+	Post *post.Post
+	// Previous and Next are nil at the start and end of the series
+	Previous *post.Post
+	Next     *post.Post
+}
+
+// Navigate links each post, in series order, to its neighbours
+func Navigate(posts []*post.Post) []Entry {
+	entries := make([]Entry, len(posts))
+	for i, p := range posts {
+		entries[i].Post = p
+		if i > 0 {
+			entries[i].Previous = posts[i-1]
+		}
+		if i < len(posts)-1 {
+			entries[i].Next = posts[i+1]
+		}
+	}
+	return entries
+}
@@ -0,0 +1,25 @@
+package series
+
+import (
+	"context"
+
+	"blog-platform/internal/domain/post"
+)
+
+// Repository defines the interface for series data access
+type Repository interface {
+	Create(ctx context.Context, series *Series) error
+	GetByID(ctx context.Context, id int) (*Series, error)
+	// ListByAuthor returns the series of an author, newest first
+	ListByAuthor(ctx context.Context, authorID int) ([]*Series, error)
+	Update(ctx context.Context, series *Series) error
+	// Delete removes a series; its posts leave it
+	Delete(ctx context.Context, id int) error
+	// ListPosts returns the posts of a series the filter finds, in series
+	// order
+	ListPosts(ctx context.Context, id int, filter post.ViewFilter) ([]*post.Post, error)
+	// SetPosts makes the posts, in the given order, the posts of a series.
+	// Posts of the series left out leave it, and posts of other series
+	// move to it.
+	SetPosts(ctx context.Context, id int, postIDs []int) error
+}
@@ -0,0 +1,23 @@
+package series
+
+import (
+	"context"
+
+	"blog-platform/internal/domain/user"
+)
+
+// Service defines the interface for series business logic
+type Service interface {
+	// Create stores a series of the author's posts, empty at first
+	Create(ctx context.Context, authorID int, in Input) (*Series, error)
+	Update(ctx context.Context, userID int, role user.Role, id int, in Input) (*Series, error)
+	// Delete removes a series; its posts are kept
+	Delete(ctx context.Context, userID int, role user.Role, id int) error
+	// Get returns a series with the posts the user may see, in series
+	// order; userID is zero for guests
+	Get(ctx context.Context, userID int, role user.Role, id int) (*Series, []Entry, error)
+	// ListByAuthor returns the series of an author, newest first
+	ListByAuthor(ctx context.Context, authorID int) ([]*Series, error)
+	// SetPosts orders the posts of a series as given, like Get
+	SetPosts(ctx context.Context, userID int, role user.Role, id int, postIDs []int) (*Series, []Entry, error)
+}
@@ -0,0 +1,336 @@
+// Package sso signs the members of organizations in through the identity
+// provider of their organization, using OpenID Connect or SAML 2.0. Each
+// organization has one connection that claims the email domains of its
+// members and may require them to sign in through it.
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/user"
+)
+
+// SSO errors
+var (
+	ErrConnectionNotFound  = errors.New("sso connection not found")
+	ErrInvalidOrganization = errors.New("invalid organization: use up to 64 lower-case letters, digits and dashes")
+	ErrInvalidProtocol     = errors.New("invalid sso protocol: use oidc or saml")
+	ErrInvalidDomain       = errors.New("invalid sso domain")
+	ErrInvalidEnforcement  = errors.New("invalid sso enforcement: use optional, required or required_except_admins")
+	ErrInvalidRoleMapping  = errors.New("invalid sso role mapping")
+	ErrIncompleteOIDC      = errors.New("invalid sso connection: oidc needs an issuer URL, a client ID and a client secret")
+	ErrIncompleteSAML      = errors.New("invalid sso connection: saml needs the metadata of the identity provider")
+	ErrInvalidMetadata     = errors.New("invalid saml metadata")
+	// ErrDomainClaimed is returned when another organization's connection
+	// already claims one of the domains
+	ErrDomainClaimed = errors.New("sso domain already exists for another organization")
+	// ErrInvalidResponse is returned when the identity provider's response
+	// cannot be verified or does not belong to the sign-in
+	ErrInvalidResponse = errors.New("invalid sso response")
+	// ErrForeignEmail is returned for accounts whose email is outside the
+	// domains of the organization; signing them in would let the identity
+	// provider take over accounts it does not own
+	ErrForeignEmail = fmt.Errorf("%w: email outside the domains of the organization", ErrInvalidResponse)
+	// ErrSSORequired is returned for other sign-in methods when the
+	// organization of the account requires single sign-on
+	ErrSSORequired = errors.New("sso required: sign in through the single sign-on of your organization")
+)
+
+// Limits of connection settings
+const (
+	maxDomains       = 50
+	maxDomainLength  = 255
+	maxRoleMappings  = 100
+	maxGroupLength   = 255
+	maxMetadataBytes = 1 << 20
+)
+
+// organizationPattern matches organization identifiers, which appear in URLs
+var organizationPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// Protocol is the protocol spoken with an identity provider
+type Protocol string
+
+// Supported protocols
+const (
+	ProtocolOIDC Protocol = "oidc"
+	ProtocolSAML Protocol = "saml"
+)
+
+// Enforcement decides whether members may use other sign-in methods
+type Enforcement string
+
+// Enforcement options
+const (
+	// EnforcementOptional lets members also sign in with a password, a
+	// magic link, a passkey or a social login
+	EnforcementOptional Enforcement = "optional"
+	// EnforcementRequired only lets members sign in through SSO
+	EnforcementRequired Enforcement = "required"
+	// EnforcementRequiredExceptAdmins keeps the other methods for admins, so
+	// they can still sign in to repair a broken connection
+	EnforcementRequiredExceptAdmins Enforcement = "required_except_admins"
+)
+
+// Connection is the single sign-on configuration of an organization
+type Connection struct {
+	Organization string
+	Protocol     Protocol
+	// Domains are the email domains of the members. Accounts with these
+	// addresses are linked to the organization's identity provider, and
+	// Enforcement applies to them.
+	Domains     []string
+	Enforcement Enforcement
+	// DefaultRole is given to members that no role mapping matches; when
+	// empty their role is left alone
+	DefaultRole user.Role
+	// RoleMappings maps groups of the identity provider to roles. Members
+	// of several mapped groups get the most privileged role.
+	RoleMappings map[string]user.Role
+	// GroupsClaim names the ID token claim or SAML attribute that lists the
+	// groups of a member
+	GroupsClaim string
+
+	// Issuer, ClientID and ClientSecret register the platform with an
+	// OpenID Connect provider; the endpoints are discovered from the issuer
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+
+	// Metadata is the SAML metadata XML of the identity provider
+	Metadata string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks and normalizes the connection. Domains are lower-cased
+// and deduplicated, and the enforcement defaults to optional.
+func (c *Connection) Validate() error {
+	if !organizationPattern.MatchString(c.Organization) {
+		return ErrInvalidOrganization
+	}
+
+	if len(c.Domains) == 0 || len(c.Domains) > maxDomains {
+		return ErrInvalidDomain
+	}
+	domains := make([]string, 0, len(c.Domains))
+	for _, domain := range c.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !isDomain(domain) {
+			return ErrInvalidDomain
+		}
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	c.Domains = domains
+
+	if c.Enforcement == "" {
+		c.Enforcement = EnforcementOptional
+	}
+	switch c.Enforcement {
+	case EnforcementOptional, EnforcementRequired, EnforcementRequiredExceptAdmins:
+	default:
+		return ErrInvalidEnforcement
+	}
+
+	if c.DefaultRole != "" && !c.DefaultRole.IsValid() {
+		return user.ErrInvalidRole
+	}
+	if len(c.RoleMappings) > maxRoleMappings {
+		return ErrInvalidRoleMapping
+	}
+	for group, role := range c.RoleMappings {
+		if group == "" || len(group) > maxGroupLength || !role.IsValid() {
+			return ErrInvalidRoleMapping
+		}
+	}
+
+	switch c.Protocol {
+	case ProtocolOIDC:
+		c.Issuer = strings.TrimRight(strings.TrimSpace(c.Issuer), "/")
+		issuer, err := url.Parse(c.Issuer)
+		if err != nil || (issuer.Scheme != "https" && issuer.Scheme != "http") || issuer.Host == "" || c.ClientID == "" || c.ClientSecret == "" {
+			return ErrIncompleteOIDC
+		}
+		c.Metadata = ""
+	case ProtocolSAML:
+		if strings.TrimSpace(c.Metadata) == "" {
+			return ErrIncompleteSAML
+		}
+		if len(c.Metadata) > maxMetadataBytes {
+			return ErrInvalidMetadata
+		}
+		c.Issuer, c.ClientID, c.ClientSecret = "", "", ""
+	default:
+		return ErrInvalidProtocol
+	}
+
+	return nil
+}
+
+// isDomain checks that a lower-cased value looks like a DNS name with at
+// least two labels
+func isDomain(domain string) bool {
+	if len(domain) > maxDomainLength || !strings.Contains(domain, ".") {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// DomainOf returns the lower-cased domain of an email address
+func DomainOf(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// Covers reports whether an email address is in a domain of the organization
+func (c *Connection) Covers(email string) bool {
+	domain := DomainOf(email)
+	return domain != "" && slices.Contains(c.Domains, domain)
+}
+
+// Requires reports whether a member with the role must sign in through SSO
+func (c *Connection) Requires(role user.Role) bool {
+	switch c.Enforcement {
+	case EnforcementRequired:
+		return true
+	case EnforcementRequiredExceptAdmins:
+		return !role.IsAdmin()
+	default:
+		return false
+	}
+}
+
+// roleRank orders roles by privilege
+var roleRank = map[user.Role]int{user.RoleReader: 1, user.RoleAuthor: 2, user.RoleAdmin: 3}
+
+// RoleFor returns the role of a member of the groups: the most privileged
+// mapped role, or the default role when no mapping matches. An empty role
+// leaves the member's role alone.
+func (c *Connection) RoleFor(groups []string) user.Role {
+	var role user.Role
+	for _, group := range groups {
+		if mapped, ok := c.RoleMappings[group]; ok && roleRank[mapped] > roleRank[role] {
+			role = mapped
+		}
+	}
+	if role == "" {
+		return c.DefaultRole
+	}
+	return role
+}
+
+// IdentityProvider returns the provider name external identities of the
+// organization are linked under
+func (c *Connection) IdentityProvider() string {
+	return "sso:" + c.Organization
+}
+
+// Membership records that a user signed in through the connection of an
+// organization
+type Membership struct {
+	Organization string `db:"organization"`
+	UserID       int    `db:"user_id"`
+	// Role is the role the groups of the member mapped to at the last
+	// sign-in; empty when the connection maps no role
+	Role        user.Role `db:"role"`
+	CreatedAt   time.Time `db:"created_at"`
+	LastLoginAt time.Time `db:"last_login_at"`
+}
+
+// Assertion is an account the identity provider vouched for
+type Assertion struct {
+	// Subject is the provider's stable identifier for the account
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+}
+
+// Response is the identity provider's answer to a sign-in request: an
+// authorization code for OpenID Connect or a base64-encoded SAML response
+type Response struct {
+	Code         string
+	SAMLResponse string
+}
+
+// Provider speaks the protocol of a connection with its identity provider
+type Provider interface {
+	// AuthRequestURL returns the URL that starts a sign-in at the identity
+	// provider; state is handed back with the response
+	AuthRequestURL(ctx context.Context, state string) (string, error)
+	// Verify checks the response to the request started with state and
+	// returns the account it vouches for
+	Verify(ctx context.Context, state string, response Response) (*Assertion, error)
+}
+
+// ProviderFactory creates the provider of a connection
+type ProviderFactory interface {
+	// Provider returns the provider of a connection; it fails for SAML
+	// metadata that cannot be used
+	Provider(c *Connection) (Provider, error)
+}
+
+// ConnectionRepository stores connections. The domains of all connections
+// are unique.
+type ConnectionRepository interface {
+	// Save creates or replaces the connection of an organization
+	Save(ctx context.Context, c *Connection) error
+	GetByOrganization(ctx context.Context, organization string) (*Connection, error)
+	GetByDomain(ctx context.Context, domain string) (*Connection, error)
+	List(ctx context.Context) ([]*Connection, error)
+	// Delete removes a connection and its memberships
+	Delete(ctx context.Context, organization string) error
+}
+
+// MembershipRepository stores memberships
+type MembershipRepository interface {
+	// Save creates or updates the membership of a user
+	Save(ctx context.Context, m *Membership) error
+	ListByUser(ctx context.Context, userID int) ([]*Membership, error)
+}
+
+// Service manages connections and signs members in through them
+type Service interface {
+	// SaveConnection creates or replaces the connection of an organization.
+	// An empty client secret keeps the stored one.
+	SaveConnection(ctx context.Context, c *Connection) (*Connection, error)
+	GetConnection(ctx context.Context, organization string) (*Connection, error)
+	ListConnections(ctx context.Context) ([]*Connection, error)
+	DeleteConnection(ctx context.Context, organization string) error
+	// Resolve returns the connection of the organization, or the connection
+	// claiming the domain of the email when no organization is given
+	Resolve(ctx context.Context, organization, email string) (*Connection, error)
+	// AuthRequestURL returns the URL that starts a sign-in at the identity
+	// provider of the organization
+	AuthRequestURL(ctx context.Context, organization, state string) (string, error)
+	// Authenticate verifies the identity provider's response and signs the
+	// member in, linking or registering the account, recording the
+	// membership and applying the mapped role
+	Authenticate(ctx context.Context, organization, state string, response Response) (*user.User, error)
+	// CheckLogin returns ErrSSORequired when the account must sign in
+	// through SSO instead; userID is 0 for accounts being registered
+	CheckLogin(ctx context.Context, userID int, email string, role user.Role) error
+}
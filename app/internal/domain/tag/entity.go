@@ -0,0 +1,90 @@
+package tag
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tag limits
+const (
+	maxNameLength = 50
+	// MaxPerPost bounds how many tags a post can carry
+	MaxPerPost = 10
+)
+
+// Tag errors
+var (
+	ErrInvalidTag  = errors.New("invalid tag: must be 1 to 50 letters, digits or hyphens")
+	ErrTooManyTags = errors.New("invalid tags: a post can have at most 10 tags")
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag already exists: merge the tags instead")
+	ErrMergeSelf   = errors.New("invalid merge: a tag cannot be merged into itself")
+)
+
+// Tag labels posts by topic
+type Tag struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+	// PostCount is the number of published posts carrying the tag; in
+	// admin listings it counts drafts and scheduled posts too
+	PostCount int `db:"post_count"`
+}
+
+// Change describes a bulk tag change by an admin, applied or only
+// previewed
+type Change struct {
+	// Tags are the tags renamed, merged away or deleted, with their post
+	// counts before the change
+	Tags []*Tag
+	// Target is the tag after a rename, or the tag others were merged into
+	Target *Tag
+	// AffectedPosts counts the posts whose tags change
+	AffectedPosts int
+	// DryRun is set when the change was only previewed
+	DryRun bool
+}
+
+// Normalize lowercases a tag name and joins its words with hyphens, so
+// "Go Lang", "go_lang" and "go-lang" are the same tag
+func Normalize(name string) (string, error) {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	})
+	normalized := strings.Join(words, "-")
+
+	if normalized == "" || utf8.RuneCountInString(normalized) > maxNameLength {
+		return "", ErrInvalidTag
+	}
+	for _, r := range normalized {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return "", ErrInvalidTag
+		}
+	}
+	return normalized, nil
+}
+
+// NormalizeNames normalizes the tags of a post, dropping duplicates while
+// keeping their order
+func NormalizeNames(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		n, err := Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+
+	if len(normalized) > MaxPerPost {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
@@ -0,0 +1,27 @@
+package tag
+
+import "context"
+
+// Repository defines the interface for tag data access. Tags are attached
+// to posts through the post repository.
+type Repository interface {
+	// ListUsed lists the tags of published posts, most used first
+	ListUsed(ctx context.Context) ([]*Tag, error)
+	// ListAll lists every tag, including unused ones, with the number of
+	// posts of any status carrying it, most used first
+	ListAll(ctx context.Context) ([]*Tag, error)
+	// GetByName retrieves a tag with the number of posts of any status
+	// carrying it
+	GetByName(ctx context.Context, name string) (*Tag, error)
+	// CountPosts counts the posts carrying any of the tags
+	CountPosts(ctx context.Context, ids []int) (int, error)
+	// Rename changes the name of a tag; ErrTagExists is returned when the
+	// name is taken
+	Rename(ctx context.Context, id int, name string) error
+	// Merge moves the posts of the source tags to the target tag and
+	// deletes the source tags in a single transaction
+	Merge(ctx context.Context, sourceIDs []int, targetID int) error
+	// DeleteUnused deletes the tags no post carries and returns how many
+	// were deleted
+	DeleteUnused(ctx context.Context) (int, error)
+}
@@ -0,0 +1,20 @@
+package tag
+
+import "context"
+
+// Service defines the interface for tag business logic
+type Service interface {
+	// ListTags lists the tags of published posts with their post counts
+	ListTags(ctx context.Context) ([]*Tag, error)
+	// ListAllTags lists every tag for admins, including unused ones
+	ListAllTags(ctx context.Context) ([]*Tag, error)
+	// RenameTag renames a tag on every post carrying it; with dryRun the
+	// change is only previewed
+	RenameTag(ctx context.Context, adminID int, name, newName string, dryRun bool) (*Change, error)
+	// MergeTags moves the posts of duplicate tags to the target tag and
+	// deletes the duplicates; with dryRun the change is only previewed
+	MergeTags(ctx context.Context, adminID int, sources []string, target string, dryRun bool) (*Change, error)
+	// DeleteUnusedTags deletes the tags no post carries; with dryRun the
+	// change is only previewed
+	DeleteUnusedTags(ctx context.Context, adminID int, dryRun bool) (*Change, error)
+}
@@ -0,0 +1,153 @@
+// Package tip models one-time payments readers make to support the author
+// of a post. Payments go through a PaymentProvider straight to the payout
+// account the author linked; the platform records each tip and learns
+// whether it was paid from the provider's webhook events.
+package tip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status is the state of the payment of a tip
+type Status string
+
+// Tip statuses
+const (
+	// StatusPending tips wait for the reader to complete the checkout
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed tips were declined or their checkout expired
+	StatusFailed Status = "failed"
+)
+
+// Tip errors
+var (
+	ErrTipNotFound           = errors.New("tip not found")
+	ErrPayoutAccountNotFound = errors.New("payout account not found")
+	ErrInvalidAmount         = errors.New("invalid amount")
+	// ErrNotPayable is returned for tips to authors without a payout account
+	ErrNotPayable = errors.New("invalid tip: the author does not accept tips")
+	ErrOwnPost    = errors.New("invalid tip: authors cannot tip their own posts")
+	// ErrInvalidAccount is returned for payout accounts the provider does
+	// not know or that cannot receive payments yet
+	ErrInvalidAccount   = errors.New("invalid payout account")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidEvent     = errors.New("invalid payment event")
+	// ErrProviderUnavailable is returned when the payment provider fails;
+	// the details are logged rather than shown to readers
+	ErrProviderUnavailable = errors.New("payment provider unavailable")
+)
+
+// Limits bound the amount of a tip, in the smallest unit of the currency
+type Limits struct {
+	MinAmount int64
+	MaxAmount int64
+}
+
+// Check checks that an amount is within the limits
+func (l Limits) Check(amount int64) error {
+	if amount < l.MinAmount || (l.MaxAmount > 0 && amount > l.MaxAmount) {
+		return fmt.Errorf("%w: tips must be between %d and %d", ErrInvalidAmount, l.MinAmount, l.MaxAmount)
+	}
+	return nil
+}
+
+// Tip is a one-time payment to the author of a post
+type Tip struct {
+	ID       int `db:"id"`
+	PostID   int `db:"post_id"`
+	AuthorID int `db:"author_id"`
+	// TipperID is nil for tips by guests
+	TipperID *int `db:"tipper_id"`
+	// Amount is in the smallest unit of the currency, e.g. cents
+	Amount   int64  `db:"amount"`
+	Currency string `db:"currency"`
+	Status   Status `db:"status"`
+	// Provider names the payment provider and PaymentID the payment there
+	Provider  string    `db:"provider"`
+	PaymentID string    `db:"payment_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// PayoutAccount links an author to the account tips are paid out to
+type PayoutAccount struct {
+	UserID    int       `db:"user_id"`
+	Provider  string    `db:"provider"`
+	AccountID string    `db:"account_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// CheckoutRequest asks the provider for a payment page for a tip
+type CheckoutRequest struct {
+	TipID     int
+	PostTitle string
+	Amount    int64
+	Currency  string
+	// Destination is the payout account of the author
+	Destination string
+	// ReturnURL is where readers are sent after the checkout
+	ReturnURL string
+}
+
+// Checkout is the payment page a reader completes a tip on
+type Checkout struct {
+	PaymentID string
+	URL       string
+}
+
+// PaymentEvent is a settled payment reported by the provider
+type PaymentEvent struct {
+	EventID   string
+	TipID     int
+	PaymentID string
+	Status    Status
+}
+
+// PaymentProvider takes payments on behalf of authors. Implementations must
+// be safe for concurrent use.
+type PaymentProvider interface {
+	// Name identifies the provider in stored tips and payout accounts
+	Name() string
+	// VerifyAccount checks that a payout account exists and can receive
+	// payments, returning an error wrapping ErrInvalidAccount otherwise
+	VerifyAccount(ctx context.Context, accountID string) error
+	// CreateCheckout starts the payment of a tip
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error)
+	// ParseEvent verifies the signature of a webhook payload and decodes
+	// it. Events that do not settle a tip decode to nil.
+	ParseEvent(payload []byte, signature string) (*PaymentEvent, error)
+}
+
+// Repository stores tips and payout accounts
+type Repository interface {
+	Create(ctx context.Context, t *Tip) error
+	GetByID(ctx context.Context, id int) (*Tip, error)
+	// SetPaymentID records the provider payment of a tip
+	SetPaymentID(ctx context.Context, id int, paymentID string) error
+	// Settle moves a pending tip to a final status and counts succeeded
+	// tips on the post. It reports whether the tip was still pending.
+	Settle(ctx context.Context, id int, status Status) (bool, error)
+	GetPayoutAccount(ctx context.Context, userID int) (*PayoutAccount, error)
+	// SavePayoutAccount creates or replaces the payout account of a user
+	SavePayoutAccount(ctx context.Context, a *PayoutAccount) error
+	DeletePayoutAccount(ctx context.Context, userID int) error
+}
+
+// Service defines the tipping use cases
+type Service interface {
+	// StartTip records a pending tip on a published post and starts its
+	// checkout; tipperID is zero for guests
+	StartTip(ctx context.Context, postID, tipperID int, amount int64) (*Tip, *Checkout, error)
+	// ApplyEvent settles a tip reported by the payment provider
+	ApplyEvent(ctx context.Context, event *PaymentEvent) error
+	GetPayoutAccount(ctx context.Context, userID int) (*PayoutAccount, error)
+	// LinkPayoutAccount verifies an account with the provider and links it
+	// to the user, replacing any linked before
+	LinkPayoutAccount(ctx context.Context, userID int, accountID string) (*PayoutAccount, error)
+	UnlinkPayoutAccount(ctx context.Context, userID int) error
+}
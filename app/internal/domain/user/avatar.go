@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// AvatarSizes lists the sizes, in pixels, of the square images an uploaded
+// avatar is resized to; the profile links to the largest
+var AvatarSizes = []int{64, 128, 256}
+
+// MaxAvatarPixels bounds the width times height of uploaded avatars, so
+// small files cannot decode to huge images
+const MaxAvatarPixels = 40_000_000
+
+// Avatar errors
+var (
+	ErrInvalidAvatar  = errors.New("invalid avatar: the file is not a JPEG, PNG or GIF image")
+	ErrAvatarTooLarge = errors.New("invalid avatar: the image is too large")
+)
+
+// Avatar is an uploaded avatar resized to each of AvatarSizes
+type Avatar struct {
+	// URL is the URL of the largest size, set as the profile avatar
+	URL string
+	// Sizes maps each size to the URL of the image of that size
+	Sizes map[int]string
+}
+
+// AvatarService defines the interface for uploaded avatars
+type AvatarService interface {
+	// UploadAvatar crops the image to a centered square, stores it resized
+	// to each of AvatarSizes and sets it as the avatar of the account. The
+	// avatar uploaded before is deleted.
+	UploadAvatar(ctx context.Context, id int, image io.Reader) (*Avatar, error)
+}
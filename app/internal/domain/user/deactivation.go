@@ -0,0 +1,29 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// Account deactivation errors
+var (
+	ErrAccountDeactivated = errors.New("account deactivated: the account was turned off by its organization")
+)
+
+// Deactivate turns the account off; it cannot sign in until reactivated
+func (u *User) Deactivate() {
+	now := time.Now()
+	u.DeactivatedAt = &now
+	u.UpdatedAt = now
+}
+
+// Reactivate turns a deactivated account back on
+func (u *User) Reactivate() {
+	u.DeactivatedAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// IsDeactivated checks if the account was turned off
+func (u *User) IsDeactivated() bool {
+	return u.DeactivatedAt != nil
+}
@@ -0,0 +1,43 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Account deletion errors
+var (
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+)
+
+// ScheduleDeletion marks the account for deletion once the grace period has
+// passed. Until then the account can be restored by logging in.
+func (u *User) ScheduleDeletion(gracePeriod time.Duration) {
+	now := time.Now()
+	purgeAt := now.Add(gracePeriod)
+	u.DeletionScheduledAt = &purgeAt
+	u.UpdatedAt = now
+}
+
+// CancelDeletion restores an account that is pending deletion
+func (u *User) CancelDeletion() {
+	u.DeletionScheduledAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// IsPendingDeletion checks if the account is scheduled for deletion
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil && u.DeletedAt == nil
+}
+
+// DeletionRepository defines the interface for purging accounts whose
+// deletion grace period has ended
+type DeletionRepository interface {
+	// ListDueForPurge returns pending accounts scheduled for deletion before the given time
+	ListDueForPurge(ctx context.Context, before time.Time, limit int) ([]*User, error)
+	// Purge anonymizes the account and its comments and removes its
+	// credentials and sessions.
+	// It returns ErrDeletionNotScheduled if the account was restored meanwhile.
+	Purge(ctx context.Context, id int) error
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// ErrInvalidEmail is returned for addresses that are not a plain email
+// address
+var ErrInvalidEmail = errors.New("invalid email format")
+
+// DefaultCanonicalProviders lists the mail providers that ignore dots and
+// plus-tags in the local part of an address
+var DefaultCanonicalProviders = []string{"gmail.com", "googlemail.com"}
+
+// providerAliases maps alternate provider domains onto their canonical domain
+var providerAliases = map[string]string{
+	"googlemail.com": "gmail.com",
+}
+
+// ValidateEmail checks that an email is a plain address like
+// name@example.com, without a display name and with a dotted domain
+func ValidateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return ErrInvalidEmail
+	}
+	labels := strings.Split(email[strings.LastIndex(email, "@")+1:], ".")
+	if len(labels) < 2 {
+		return ErrInvalidEmail
+	}
+	for _, label := range labels {
+		if label == "" {
+			return ErrInvalidEmail
+		}
+	}
+	return nil
+}
+
+// EmailNormalizer computes the canonical form of an email address used for
+// uniqueness checks and lookups
+type EmailNormalizer struct {
+	providers map[string]bool
+}
+
+// NewEmailNormalizer creates a normalizer that applies provider-specific
+// canonicalization (dot and plus-tag removal) for the given domains
+func NewEmailNormalizer(providers []string) *EmailNormalizer {
+	n := &EmailNormalizer{providers: make(map[string]bool, len(providers))}
+	for _, p := range providers {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" {
+			n.providers[p] = true
+		}
+	}
+	return n
+}
+
+// Normalize returns the canonical form of the given email address.
+// All addresses are trimmed and lowercased; addresses at a configured
+// provider additionally have dots and "+tag" suffixes stripped from the
+// local part.
+func (n *EmailNormalizer) Normalize(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if n == nil || !n.providers[domain] {
+		return email
+	}
+
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	local = strings.ReplaceAll(local, ".", "")
+	if local == "" {
+		return email
+	}
+
+	if alias, ok := providerAliases[domain]; ok {
+		domain = alias
+	}
+
+	return local + "@" + domain
+}
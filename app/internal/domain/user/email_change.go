@@ -0,0 +1,82 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Email change errors
+var (
+	ErrEmailChangeNotFound = errors.New("email change request not found")
+	ErrEmailChangeExpired  = errors.New("email change request has expired")
+	ErrEmailUnchanged      = errors.New("new email must differ from the current email")
+)
+
+// EmailChange represents a pending request to move an account to a new email
+// address. The old address stays active until the new one is confirmed.
+type EmailChange struct {
+	ID                 int       `json:"id" db:"id"`
+	UserID             int       `json:"user_id" db:"user_id"`
+	NewEmail           string    `json:"new_email" db:"new_email"`
+	NewEmailNormalized string    `json:"-" db:"new_email_normalized"`
+	TokenHash          string    `json:"-" db:"token_hash"`
+	ExpiresAt          time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewEmailChange creates a pending email change and returns it together with
+// the plain confirmation token. Only the token hash is stored.
+func NewEmailChange(userID int, newEmail, newEmailNormalized string, ttl time.Duration) (*EmailChange, string, error) {
+	if userID <= 0 {
+		return nil, "", errors.New("user ID must be positive")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &EmailChange{
+		UserID:             userID,
+		NewEmail:           newEmail,
+		NewEmailNormalized: newEmailNormalized,
+		TokenHash:          HashToken(token),
+		ExpiresAt:          now.Add(ttl),
+		CreatedAt:          now,
+	}, token, nil
+}
+
+// IsExpired checks if the confirmation window has passed
+func (c *EmailChange) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a confirmation token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken creates a random URL-safe token
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New("failed to generate token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// EmailChangeRepository defines the interface for pending email change storage
+type EmailChangeRepository interface {
+	// Create stores a pending change, replacing any previous request for the user
+	Create(ctx context.Context, change *EmailChange) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*EmailChange, error)
+	// Complete applies the change to the user and removes the request atomically
+	Complete(ctx context.Context, change *EmailChange) error
+	Delete(ctx context.Context, id int) error
+}
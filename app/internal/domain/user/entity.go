@@ -9,12 +9,23 @@ import (
 
 // User represents a user entity in the domain
 type User struct {
-	ID           int       `json:"id" db:"id"`
-	Name         string    `json:"name" db:"name"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID                  int        `json:"id" db:"id"`
+	Name                string     `json:"name" db:"name"`
+	Email               string     `json:"email" db:"email"`
+	NormalizedEmail     string     `json:"-" db:"email_normalized"`
+	EmailConflict       bool       `json:"-" db:"email_conflict"`
+	PasswordHash        string     `json:"-" db:"password_hash"`
+	Role                Role       `json:"role" db:"role"`
+	DeletionScheduledAt *time.Time `json:"-" db:"deletion_scheduled_at"`
+	DeletedAt           *time.Time `json:"-" db:"deleted_at"`
+	// PasswordResetRequiredAt is set while the account is locked after a
+	// change was reported as unauthorized
+	PasswordResetRequiredAt *time.Time `json:"-" db:"password_reset_required_at"`
+	// DeactivatedAt is set while the account is turned off, e.g. by the
+	// identity provider of its organization
+	DeactivatedAt           *time.Time `json:"-" db:"deactivated_at"`
+	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // NewUser creates a new user instance with password hashing
@@ -29,6 +40,7 @@ func NewUser(name, email, password string) (*User, error) {
 		Name:         name,
 		Email:        email,
 		PasswordHash: string(hashedPassword),
+		Role:         DefaultRole,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}, nil
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Follow errors
+var (
+	ErrCannotFollowSelf = errors.New("invalid follow: users cannot follow themselves")
+)
+
+// Follow is a user in a follower or following list
+type Follow struct {
+	UserID int    `db:"user_id"`
+	Name   string `db:"name"`
+	// FollowedAt is when the follow started
+	FollowedAt time.Time `db:"followed_at"`
+}
+
+// FollowCounts holds how many users follow a user and how many they follow
+type FollowCounts struct {
+	Followers int `db:"followers"`
+	Following int `db:"following"`
+}
+
+// FollowRepository defines the interface for follow data access. Lists and
+// counts leave out deleted and deactivated accounts.
+type FollowRepository interface {
+	// Follow records that the follower follows the followee; following
+	// again is not an error
+	Follow(ctx context.Context, followerID, followeeID int) error
+	// Unfollow removes a follow; unfollowing a user not followed is not an
+	// error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	// ListFollowers returns the users following a user, most recent first
+	ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*Follow, error)
+	// ListFollowing returns the users a user follows, most recent first
+	ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*Follow, error)
+	Counts(ctx context.Context, userID int) (*FollowCounts, error)
+}
+
+// FollowService defines the interface for following users
+type FollowService interface {
+	// Follow makes the follower follow an active account
+	Follow(ctx context.Context, followerID, followeeID int) error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	// ListFollowers returns a page of the followers of an active account
+	// with the follow counts of the account
+	ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*Follow, *FollowCounts, error)
+	// ListFollowing returns a page of the users an active account follows
+	// with the follow counts of the account
+	ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*Follow, *FollowCounts, error)
+}
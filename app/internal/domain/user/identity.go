@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Identity errors
+var (
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityExists   = errors.New("identity already exists")
+	// ErrUnverifiedEmail is returned when a provider account without a
+	// verified email would have to be matched or registered by email
+	ErrUnverifiedEmail = errors.New("invalid external account: email address is not verified")
+)
+
+// ExternalIdentity is the account information returned by an external
+// identity provider after a successful sign-in
+type ExternalIdentity struct {
+	// Provider is the provider name, e.g. "google" or "github"
+	Provider string
+	// Subject is the provider's stable identifier for the account
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Identity links a user to an account at an external identity provider
+type Identity struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"-" db:"subject"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewIdentity creates a link between a user and an external account
+func NewIdentity(userID int, external ExternalIdentity) (*Identity, error) {
+	if userID <= 0 {
+		return nil, errors.New("user ID must be positive")
+	}
+	if external.Provider == "" || external.Subject == "" {
+		return nil, errors.New("provider and subject cannot be empty")
+	}
+
+	return &Identity{
+		UserID:    userID,
+		Provider:  external.Provider,
+		Subject:   external.Subject,
+		Email:     external.Email,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IdentityRepository defines the interface for linked identity storage
+type IdentityRepository interface {
+	// Create stores a link; it returns ErrIdentityExists if the external
+	// account is already linked
+	Create(ctx context.Context, identity *Identity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*Identity, error)
+	ListByUser(ctx context.Context, userID int) ([]*Identity, error)
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAccountLocked is returned by logins while too many recent attempts
+// have failed
+var ErrAccountLocked = errors.New("account temporarily locked after too many failed login attempts")
+
+// LockoutError is returned instead of ErrAccountLocked when the end of the
+// lockout is known
+type LockoutError struct {
+	Until time.Time
+}
+
+// Error implements the error interface
+func (e *LockoutError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+// Is reports the lockout as ErrAccountLocked
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// LoginAttemptStore counts failed logins per key. Counts expire a window
+// after the last failure.
+type LoginAttemptStore interface {
+	// RecordFailure counts a failure and returns the failures within the window
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Failures returns the current count and when it expires
+	Failures(ctx context.Context, key string) (int, time.Time, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// AccountAttemptKey is the attempt store key counting failures for an account
+func AccountAttemptKey(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// ClientAttemptKey is the attempt store key counting failures from a client IP
+func ClientAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+type clientIPKey struct{}
+
+// WithClientIP returns a context carrying the IP address of the client a
+// request came from, so logins can count failures per client
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the client IP set by WithClientIP, if any
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
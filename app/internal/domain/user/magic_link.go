@@ -0,0 +1,82 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidMagicLink is returned for unknown, used and expired magic links
+var ErrInvalidMagicLink = errors.New("invalid or expired magic link")
+
+// maxUserAgentLength bounds the user agents stored with magic links
+const maxUserAgentLength = 255
+
+// Device describes the client a request came from
+type Device struct {
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// NewDevice describes a client, shortening overly long user agents
+func NewDevice(ip, userAgent string) Device {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return Device{IP: ip, UserAgent: userAgent}
+}
+
+// MagicLink represents a single-use passwordless login link. Only the hash
+// of its token is stored, with the devices that requested and used it.
+type MagicLink struct {
+	ID               int        `json:"id" db:"id"`
+	UserID           int        `json:"user_id" db:"user_id"`
+	TokenHash        string     `json:"-" db:"token_hash"`
+	RequestIP        string     `json:"request_ip" db:"request_ip"`
+	RequestUserAgent string     `json:"request_user_agent" db:"request_user_agent"`
+	UsedIP           string     `json:"used_ip,omitempty" db:"used_ip"`
+	UsedUserAgent    string     `json:"used_user_agent,omitempty" db:"used_user_agent"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UsedAt           *time.Time `json:"used_at,omitempty" db:"used_at"`
+}
+
+// NewMagicLink creates a magic link requested from a device and returns it
+// together with the plain token
+func NewMagicLink(userID int, device Device, ttl time.Duration) (*MagicLink, string, error) {
+	if userID <= 0 {
+		return nil, "", errors.New("user ID must be positive")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &MagicLink{
+		UserID:           userID,
+		TokenHash:        HashToken(token),
+		RequestIP:        device.IP,
+		RequestUserAgent: device.UserAgent,
+		ExpiresAt:        now.Add(ttl),
+		CreatedAt:        now,
+	}, token, nil
+}
+
+// MagicLinkAttemptKey is the attempt store key counting the magic links
+// requested for an account
+func MagicLinkAttemptKey(userID int) string {
+	return fmt.Sprintf("magic-link:%d", userID)
+}
+
+// MagicLinkRepository defines the interface for magic link storage
+type MagicLinkRepository interface {
+	// Create stores a link, replacing the unused links of the user
+	Create(ctx context.Context, link *MagicLink) error
+	// Consume marks the unused, unexpired link with the token hash as used
+	// from the device and returns it. It returns ErrInvalidMagicLink
+	// otherwise, so concurrent requests cannot both use a link.
+	Consume(ctx context.Context, tokenHash string, device Device) (*MagicLink, error)
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxBioLength is the maximum length of a bio in characters
+const MaxBioLength = 500
+
+// Profile errors
+var (
+	ErrBioTooLong       = errors.New("invalid profile: bio is too long")
+	ErrInvalidAvatarURL = errors.New("invalid profile: avatar URL must be an https URL")
+)
+
+// Profile is the public view of an active account
+type Profile struct {
+	ID        int    `db:"id"`
+	Name      string `db:"name"`
+	Bio       string `db:"bio"`
+	AvatarURL string `db:"avatar_url"`
+	// JoinedAt is when the account was created
+	JoinedAt time.Time `db:"joined_at"`
+	// PostCount counts the published posts of the account
+	PostCount int `db:"post_count"`
+}
+
+// NormalizeProfile trims the bio and avatar URL of a profile and validates
+// them; both may be empty
+func NormalizeProfile(bio, avatarURL string) (string, string, error) {
+	bio = strings.TrimSpace(bio)
+	if utf8.RuneCountInString(bio) > MaxBioLength {
+		return "", "", ErrBioTooLong
+	}
+
+	avatarURL = strings.TrimSpace(avatarURL)
+	if avatarURL != "" {
+		u, err := url.Parse(avatarURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return "", "", ErrInvalidAvatarURL
+		}
+	}
+	return bio, avatarURL, nil
+}
+
+// ProfileRepository defines the interface for profile data access. Deleted
+// and deactivated accounts have no profile.
+type ProfileRepository interface {
+	// GetProfiles returns the profiles of the accounts with the IDs;
+	// accounts without a profile are left out
+	GetProfiles(ctx context.Context, ids []int) ([]*Profile, error)
+	UpdateProfile(ctx context.Context, id int, bio, avatarURL string) error
+}
+
+// ProfileService defines the interface for public profiles
+type ProfileService interface {
+	GetProfile(ctx context.Context, id int) (*Profile, error)
+	// GetProfiles returns the profiles of the accounts with the IDs by ID;
+	// accounts without a profile are left out
+	GetProfiles(ctx context.Context, ids []int) (map[int]*Profile, error)
+	// UpdateProfile sets the bio and avatar URL of an account
+	UpdateProfile(ctx context.Context, id int, bio, avatarURL string) (*Profile, error)
+}
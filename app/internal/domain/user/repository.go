@@ -13,12 +13,23 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 )
 
-// Repository defines the interface for user data access
-type Repository interface {
-	Create(ctx context.Context, user *User) error
+// ReadRepository defines the read side of user data access
+type ReadRepository interface {
 	GetByID(ctx context.Context, id int) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetConflictedByEmail(ctx context.Context, email string) (*User, error)
+	List(ctx context.Context, limit, offset int) ([]*User, error)
+}
+
+// WriteRepository defines the write side of user data access
+type WriteRepository interface {
+	Create(ctx context.Context, user *User) error
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, limit, offset int) ([]*User, error)
+}
+
+// Repository defines the interface for user data access
+type Repository interface {
+	ReadRepository
+	WriteRepository
 }
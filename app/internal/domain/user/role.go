@@ -0,0 +1,43 @@
+package user
+
+import "errors"
+
+// Role determines what a user is allowed to do
+type Role string
+
+// Available roles
+const (
+	RoleReader Role = "reader"
+	RoleAuthor Role = "author"
+	RoleAdmin  Role = "admin"
+)
+
+// DefaultRole is assigned to newly registered users
+const DefaultRole = RoleAuthor
+
+// ErrInvalidRole is returned for unknown role names
+var ErrInvalidRole = errors.New("invalid role")
+
+// ParseRole converts a role name into a Role
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if !role.IsValid() {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// IsValid checks if the role is one of the known roles
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleReader, RoleAuthor, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAdmin checks if the role grants administrative access
+func (r Role) IsAdmin() bool {
+	return r == RoleAdmin
+}
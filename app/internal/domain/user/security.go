@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Account security errors
+var (
+	ErrPasswordResetRequired = errors.New("password reset required: a change to this account was reported as unauthorized")
+	ErrInvalidReportToken    = errors.New("invalid or expired security report link")
+	ErrPasswordResetNotFound = errors.New("password reset request not found")
+	ErrPasswordResetExpired  = errors.New("password reset request has expired")
+)
+
+// RequirePasswordReset locks the account until its password is reset
+func (u *User) RequirePasswordReset() {
+	now := time.Now()
+	u.PasswordResetRequiredAt = &now
+	u.UpdatedAt = now
+}
+
+// IsPasswordResetRequired checks if the account is locked until its
+// password is reset
+func (u *User) IsPasswordResetRequired() bool {
+	return u.PasswordResetRequiredAt != nil
+}
+
+// PasswordReset represents a pending password reset of a locked account
+type PasswordReset struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewPasswordReset creates a pending password reset and returns it together
+// with the plain reset token. Only the token hash is stored.
+func NewPasswordReset(userID int, ttl time.Duration) (*PasswordReset, string, error) {
+	if userID <= 0 {
+		return nil, "", errors.New("user ID must be positive")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &PasswordReset{
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// IsExpired checks if the reset window has passed
+func (r *PasswordReset) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// PasswordResetRepository defines the interface for pending password reset storage
+type PasswordResetRepository interface {
+	// Create stores a pending reset, replacing any previous reset for the user
+	Create(ctx context.Context, reset *PasswordReset) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*PasswordReset, error)
+	// Complete stores the new password hash, unlocks the account and removes
+	// the reset atomically
+	Complete(ctx context.Context, reset *PasswordReset, passwordHash string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ReportTokens issues and verifies the tokens of "this wasn't me" links
+type ReportTokens interface {
+	Issue(userID int, expiresAt time.Time) string
+	// Verify returns the user a token was issued for, or ErrInvalidReportToken
+	Verify(token string) (int, error)
+}
+
+// SecurityService defines the interface for reacting to changes the account
+// owner did not make
+type SecurityService interface {
+	// ReportUnauthorizedChange locks the account of a "this wasn't me" link,
+	// ends its sessions and emails a password reset link
+	ReportUnauthorizedChange(ctx context.Context, token string) error
+	// ResetPassword sets a new password with an emailed reset token and
+	// unlocks the account
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
@@ -7,11 +7,38 @@ import (
 // Service defines the interface for user business logic
 type Service interface {
 	Register(ctx context.Context, name, email, password string) (*User, error)
+	// RegisterPasswordless creates an account that signs in with another
+	// method, such as a passkey, until a password is set by resetting it
+	RegisterPasswordless(ctx context.Context, name, email, method string) (*User, error)
 	Login(ctx context.Context, email, password string) (*User, error)
+	// LoginWithIdentity signs in with an external account, linking it to the
+	// user with the same verified email or registering a new user
+	LoginWithIdentity(ctx context.Context, external ExternalIdentity) (*User, error)
+	// RequestMagicLink emails a single-use login link to the account with
+	// the address, if there is one
+	RequestMagicLink(ctx context.Context, email string, device Device) error
+	// LoginWithMagicLink signs in with the token of an emailed login link
+	LoginWithMagicLink(ctx context.Context, token string, device Device) (*User, error)
+	// CompleteLogin signs in a user authenticated by another method, such
+	// as a passkey
+	CompleteLogin(ctx context.Context, id int, method string) (*User, error)
 	GetByID(ctx context.Context, id int) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	UpdateProfile(ctx context.Context, id int, name, email string) (*User, error)
+	// SyncProfile sets the name and email of an account managed by an
+	// identity provider, without confirming the new address
+	SyncProfile(ctx context.Context, id int, name, email string) (*User, error)
+	// Deactivate turns an account off, so it cannot sign in
+	Deactivate(ctx context.Context, id int) error
+	// Reactivate turns a deactivated account back on
+	Reactivate(ctx context.Context, id int) error
+	// ChangeRole sets the role of an account
+	ChangeRole(ctx context.Context, id int, role Role) (*User, error)
+	RequestEmailChange(ctx context.Context, id int, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, token string) (*User, error)
 	UpdatePassword(ctx context.Context, id int, currentPassword, newPassword string) error
 	Delete(ctx context.Context, id int) error
+	// ScheduleDeletion marks the account for deletion after the grace period
+	ScheduleDeletion(ctx context.Context, id int) (*User, error)
 	List(ctx context.Context, limit, offset int) ([]*User, error)
 }
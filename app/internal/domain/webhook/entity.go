@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Events webhooks can subscribe to
+const (
+	// EventPostPublished is sent when a post becomes public, when created
+	// published or once published or scheduled to go live
+	EventPostPublished = "post.published"
+	// EventCommentCreated is sent when a comment becomes public, when
+	// posted or once approved by a moderator
+	EventCommentCreated = "comment.created"
+	// EventUserRegistered is sent when an account is created
+	EventUserRegistered = "user.registered"
+)
+
+// Events lists every event webhooks can subscribe to
+var Events = []string{EventPostPublished, EventCommentCreated, EventUserRegistered}
+
+// DeliveryStatus is the state of a delivery
+type DeliveryStatus string
+
+// Available delivery statuses
+const (
+	// DeliveryPending deliveries are waiting for their first or next attempt
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered deliveries were answered with a 2xx status
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed deliveries ran out of attempts
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// maxURLLength bounds the URL of a webhook
+const maxURLLength = 2048
+
+// Webhook errors
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidURL       = errors.New("invalid webhook url: must be an absolute http or https URL of at most 2048 characters")
+	ErrInvalidEvents    = errors.New("invalid webhook events: must subscribe to at least one of post.published, comment.created or user.registered")
+)
+
+// Webhook is an endpoint admins registered to be sent the events it
+// subscribes to
+type Webhook struct {
+	ID  int
+	URL string
+	// Events are the events the webhook subscribes to, sorted by name
+	Events []string
+	// Secret signs the payloads sent to the webhook
+	Secret string
+	// Active webhooks are sent events; inactive ones are kept with their
+	// deliveries but sent nothing
+	Active bool
+	// CreatedBy is zero once the admin who created the webhook is deleted
+	CreatedBy int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Input holds the fields admins set on a webhook
+type Input struct {
+	URL    string
+	Events []string
+	Active bool
+}
+
+// NewWebhook creates a webhook signing its payloads with secret
+func NewWebhook(createdBy int, secret string, in Input) (*Webhook, error) {
+	w := &Webhook{Secret: secret, CreatedBy: createdBy, CreatedAt: time.Now()}
+	if err := w.Update(in); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Update replaces the fields of the webhook; the secret is kept
+func (w *Webhook) Update(in Input) error {
+	if !validURL(in.URL) {
+		return ErrInvalidURL
+	}
+	if len(in.Events) == 0 {
+		return ErrInvalidEvents
+	}
+	events := make([]string, 0, len(in.Events))
+	for _, event := range in.Events {
+		if !slices.Contains(Events, event) {
+			return ErrInvalidEvents
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	slices.Sort(events)
+
+	w.URL = in.URL
+	w.Events = events
+	w.Active = in.Active
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// Subscribes checks if the webhook is sent an event
+func (w *Webhook) Subscribes(event string) bool {
+	return w.Active && slices.Contains(w.Events, event)
+}
+
+// validURL checks that a webhook URL is an absolute http or https URL
+func validURL(raw string) bool {
+	if raw == "" || len(raw) > maxURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.User == nil
+}
+
+// Delivery is an event sent, or to be sent, to a webhook. Failed attempts
+// are retried with the same payload until one succeeds or they run out.
+type Delivery struct {
+	ID        int
+	WebhookID int
+	Event     string
+	// Payload is the JSON body sent to the webhook
+	Payload string
+	Status  DeliveryStatus
+	// Attempts is the number of requests made so far
+	Attempts int
+	// ResponseStatus is the HTTP status of the last attempt; zero when no
+	// response was received
+	ResponseStatus int
+	// Error describes why the last attempt failed
+	Error     string
+	CreatedAt time.Time
+	// DeliveredAt is when the webhook accepted the delivery
+	DeliveredAt *time.Time
+}
+
+// NewDelivery creates a pending delivery of an event to a webhook
+func NewDelivery(webhookID int, event, payload string) *Delivery {
+	return &Delivery{
+		WebhookID: webhookID,
+		Event:     event,
+		Payload:   payload,
+		Status:    DeliveryPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Sign returns the signature header of a payload sent at the given time:
+// the Unix timestamp and the hex HMAC-SHA256 of "timestamp.payload" keyed
+// with the webhook secret, as "t=1700000000,v1=5257a869...". Receivers
+// recompute it to check the payload came from us, and reject old
+// timestamps to stop replays.
+func Sign(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import "context"
+
+// Repository defines the interface for webhook data access
+type Repository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	GetByID(ctx context.Context, id int) (*Webhook, error)
+	// List returns every webhook, newest first
+	List(ctx context.Context) ([]*Webhook, error)
+	// ListActive returns the webhooks that are sent events
+	ListActive(ctx context.Context) ([]*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	// Delete removes a webhook; its deliveries go with it
+	Delete(ctx context.Context, id int) error
+}
+
+// DeliveryRepository defines the interface for webhook delivery data access
+type DeliveryRepository interface {
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, id int) (*Delivery, error)
+	// UpdateDelivery stores the outcome of an attempt
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	// ListDeliveries returns a page of the deliveries to a webhook, newest
+	// first, and their total count
+	ListDeliveries(ctx context.Context, webhookID, limit, offset int) ([]*Delivery, int, error)
+}
+
+// Sender makes the requests of deliveries
+type Sender interface {
+	// Send posts the payload of a delivery to the webhook, signed with its
+	// secret, and returns the response status; zero when no response was
+	// received. Statuses other than 2xx are errors.
+	Send(ctx context.Context, webhook *Webhook, delivery *Delivery) (int, error)
+}
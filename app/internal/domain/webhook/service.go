@@ -0,0 +1,18 @@
+package webhook
+
+import "context"
+
+// Service defines the interface for webhook business logic
+type Service interface {
+	// Create registers a webhook with a new secret; adminID is the admin
+	// registering it
+	Create(ctx context.Context, adminID int, in Input) (*Webhook, error)
+	Get(ctx context.Context, id int) (*Webhook, error)
+	Update(ctx context.Context, adminID, id int, in Input) (*Webhook, error)
+	Delete(ctx context.Context, adminID, id int) error
+	// List returns every webhook, newest first
+	List(ctx context.Context) ([]*Webhook, error)
+	// ListDeliveries returns a page of the delivery log of a webhook and
+	// its total count
+	ListDeliveries(ctx context.Context, id, limit, offset int) ([]*Delivery, int, error)
+}
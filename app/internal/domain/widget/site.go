@@ -0,0 +1,83 @@
+// Package widget describes the external sites allowed to embed the comments
+// widget
+package widget
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Site is an external website allowed to embed the comments widget
+type Site struct {
+	// Token is the public token in the site's embed code. It names the site
+	// rather than authenticating it: what keeps other sites out is that
+	// browsers only let the listed origins call the widget or frame it.
+	Token string
+	// Origins are the origins of the pages embedding the widget, e.g.
+	// https://example.com
+	Origins []string
+}
+
+// AllowsOrigin checks if a request origin belongs to the site
+func (s *Site) AllowsOrigin(origin string) bool {
+	origin, ok := NormalizeOrigin(origin)
+	if !ok {
+		return false
+	}
+	for _, allowed := range s.Origins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// Sites looks up embedding sites by their public token
+type Sites interface {
+	// Lookup returns the site a token was issued to
+	Lookup(token string) (*Site, bool)
+}
+
+// Registry holds a fixed set of sites and implements Sites
+type Registry struct {
+	sites map[string]*Site
+}
+
+// NewRegistry creates a registry from a map of tokens to origins. Malformed
+// origins are skipped, as are sites left without one.
+func NewRegistry(sites map[string][]string) *Registry {
+	r := &Registry{sites: make(map[string]*Site, len(sites))}
+	for token, origins := range sites {
+		site := &Site{Token: token}
+		for _, origin := range origins {
+			if normalized, ok := NormalizeOrigin(origin); ok {
+				site.Origins = append(site.Origins, normalized)
+			}
+		}
+		if token != "" && len(site.Origins) > 0 {
+			r.sites[token] = site
+		}
+	}
+	return r
+}
+
+// Lookup returns the site a token was issued to
+func (r *Registry) Lookup(token string) (*Site, bool) {
+	site, ok := r.sites[token]
+	return site, ok
+}
+
+// NormalizeOrigin reduces an origin to its lowercase scheme and host, the
+// form browsers send in the Origin header. Only http and https origins
+// without a path are accepted.
+func NormalizeOrigin(origin string) (string, bool) {
+	u, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+	if err != nil || u.Host == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", false
+	}
+	return scheme + "://" + strings.ToLower(u.Host), true
+}
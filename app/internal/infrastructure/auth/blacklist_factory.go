@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// NewTokenBlacklist creates a token blacklist based on configuration
+func NewTokenBlacklist(cfg *config.Config, client *redis.Client) auth.TokenBlacklist {
+	switch strings.ToLower(cfg.JWT.BlacklistDriver) {
+	case "redis":
+		return NewRedisTokenBlacklist(client)
+	default:
+		return NewMemoryTokenBlacklist()
+	}
+}
+
+// NewLoginAttemptStore creates a login attempt store based on configuration
+func NewLoginAttemptStore(cfg *config.Config, client *redis.Client) user.LoginAttemptStore {
+	switch strings.ToLower(cfg.Account.LoginAttemptsDriver) {
+	case "redis":
+		return NewRedisLoginAttemptStore(client)
+	default:
+		return NewMemoryLoginAttemptStore()
+	}
+}
+
+// NewPasskeyChallengeStore creates a passkey challenge store based on
+// configuration
+func NewPasskeyChallengeStore(cfg *config.Config, client *redis.Client) auth.PasskeyChallengeStore {
+	switch strings.ToLower(cfg.WebAuthn.ChallengeDriver) {
+	case "redis":
+		return NewRedisPasskeyChallengeStore(client)
+	default:
+		return NewMemoryPasskeyChallengeStore()
+	}
+}
+
+// NewJWTServiceFromConfig creates a JWT service with the keys configured in
+// cfg.JWT: tokens are signed with the signing key file, or with the JWT
+// secret when there is none, and verified with the verification key files
+func NewJWTServiceFromConfig(cfg *config.Config) (*JWTService, error) {
+	secret := NewHMACKey(cfg.JWT.Secret)
+	signing := secret
+	var verification []SigningKey
+
+	if cfg.JWT.SigningKeyFile != "" {
+		key, err := LoadPEMKey(cfg.JWT.SigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if !key.CanSign() {
+			return nil, fmt.Errorf("%s: signing key must be a private key", cfg.JWT.SigningKeyFile)
+		}
+		signing = key
+		if cfg.JWT.AcceptHS256 {
+			verification = append(verification, secret)
+		}
+	}
+
+	for _, path := range cfg.JWT.VerificationKeyFiles {
+		key, err := LoadPEMKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	return NewJWTServiceWithKeys(signing, verification...)
+}
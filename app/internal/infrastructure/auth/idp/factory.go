@@ -0,0 +1,98 @@
+// Package idp speaks OpenID Connect and SAML 2.0 with the identity
+// providers of organizations
+package idp
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"blog-platform/internal/domain/sso"
+)
+
+// httpTimeout bounds each request to a provider
+const httpTimeout = 10 * time.Second
+
+// ssoPath is the path of the SSO sign-in routes
+const ssoPath = "/api/v1/auth/sso"
+
+// Endpoints builds the platform URLs registered with identity providers
+type Endpoints struct {
+	// BaseURL is the public URL of the platform
+	BaseURL string
+}
+
+// StartURL returns the URL that starts a sign-in for an organization
+func (e Endpoints) StartURL(organization string) string {
+	return e.BaseURL + ssoPath + "?organization=" + organization
+}
+
+// CallbackURL returns the OpenID Connect redirect URL of an organization
+func (e Endpoints) CallbackURL(organization string) string {
+	return e.BaseURL + ssoPath + "/" + organization + "/callback"
+}
+
+// ACSURL returns the SAML assertion consumer service URL of an organization
+func (e Endpoints) ACSURL(organization string) string {
+	return e.BaseURL + ssoPath + "/" + organization + "/acs"
+}
+
+// MetadataURL returns the URL of the SAML service provider metadata of an
+// organization, which is also the platform's entity ID
+func (e Endpoints) MetadataURL(organization string) string {
+	return e.BaseURL + ssoPath + "/" + organization + "/metadata"
+}
+
+// Factory creates the providers of connections. Providers are cached until
+// their connection changes, so discovery documents and keys are reused.
+type Factory struct {
+	endpoints  Endpoints
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	providers map[string]cachedProvider
+}
+
+// cachedProvider is a provider with the version of its connection
+type cachedProvider struct {
+	updatedAt time.Time
+	provider  sso.Provider
+}
+
+// NewFactory creates a provider factory
+func NewFactory(endpoints Endpoints, httpClient *http.Client) *Factory {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: httpTimeout}
+	}
+	return &Factory{endpoints: endpoints, httpClient: httpClient, providers: make(map[string]cachedProvider)}
+}
+
+// Provider returns the provider of a connection
+func (f *Factory) Provider(c *sso.Connection) (sso.Provider, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cached, ok := f.providers[c.Organization]; ok && cached.updatedAt.Equal(c.UpdatedAt) {
+		return cached.provider, nil
+	}
+
+	var provider sso.Provider
+	switch c.Protocol {
+	case sso.ProtocolOIDC:
+		provider = NewOIDCProvider(c.Issuer, c.ClientID, c.ClientSecret, c.GroupsClaim, f.endpoints.CallbackURL(c.Organization), f.httpClient)
+	case sso.ProtocolSAML:
+		samlProvider, err := NewSAMLProvider(c.Metadata, c.GroupsClaim, f.endpoints.MetadataURL(c.Organization), f.endpoints.ACSURL(c.Organization))
+		if err != nil {
+			return nil, err
+		}
+		provider = samlProvider
+	default:
+		return nil, sso.ErrInvalidProtocol
+	}
+
+	f.providers[c.Organization] = cachedProvider{updatedAt: c.UpdatedAt, provider: provider}
+	return provider, nil
+}
+
+// Verify that Factory implements the sso.ProviderFactory interface
+var _ sso.ProviderFactory = (*Factory)(nil)
@@ -0,0 +1,387 @@
+package idp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"blog-platform/internal/domain/sso"
+)
+
+// Cache lifetimes of provider documents
+const (
+	discoveryTTL = time.Hour
+	// keysRefreshInterval limits how often unknown key IDs trigger a fetch
+	// of the key set, which providers rotate
+	keysRefreshInterval = time.Minute
+)
+
+// maxResponseSize bounds the size of provider responses that are decoded
+const maxResponseSize = 1 << 20
+
+// defaultGroupsClaim is read when the connection names no groups claim
+const defaultGroupsClaim = "groups"
+
+// oidcDiscovery is the part of the provider configuration the platform uses
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider signs members in with the OpenID Connect authorization code
+// flow. Endpoints are discovered from the issuer and ID tokens are verified
+// with the provider's published keys.
+type OIDCProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	groupsClaim  string
+	redirectURL  string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	discoveredAt  time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider creates a provider for a client registration at an issuer
+func NewOIDCProvider(issuer, clientID, clientSecret, groupsClaim, redirectURL string, httpClient *http.Client) *OIDCProvider {
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+	return &OIDCProvider{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		groupsClaim:  groupsClaim,
+		redirectURL:  redirectURL,
+		httpClient:   httpClient,
+		now:          time.Now,
+	}
+}
+
+// nonce derives the ID token nonce from the state, binding the token to the
+// sign-in the browser started
+func nonce(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthRequestURL returns the authorization endpoint URL of the provider
+func (p *OIDCProvider) AuthRequestURL(ctx context.Context, state string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"client_id":     {p.clientID},
+		"redirect_uri":  {p.redirectURL},
+		"response_type": {"code"},
+		"scope":         {"openid email profile"},
+		"state":         {state},
+		"nonce":         {nonce(state)},
+	}
+	sep := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return discovery.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// tokenResponse is the token endpoint response
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+// idTokenClaims are the ID token claims the platform reads; the groups
+// claim is looked up by name
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     *bool  `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// Verify exchanges the authorization code and verifies the ID token: its
+// signature, issuer, audience, expiry and nonce
+func (p *OIDCProvider) Verify(ctx context.Context, state string, response sso.Response) (*sso.Assertion, error) {
+	if response.Code == "" {
+		return nil, fmt.Errorf("%w: missing authorization code", sso.ErrInvalidResponse)
+	}
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {response.Code},
+		"redirect_uri": {p.redirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var token tokenResponse
+	if err := p.do(req, &token); err != nil {
+		return nil, err
+	}
+	if token.Error != "" || token.IDToken == "" {
+		return nil, fmt.Errorf("%w: token endpoint returned no ID token %s", sso.ErrInvalidResponse, token.Error)
+	}
+
+	var claims idTokenClaims
+	parsed, err := jwt.ParseWithClaims(token.IDToken, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil || !parsed.Valid {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	if claims.Nonce != nonce(state) {
+		return nil, fmt.Errorf("%w: ID token for another sign-in", sso.ErrInvalidResponse)
+	}
+	if claims.AuthorizedParty != "" && claims.AuthorizedParty != p.clientID {
+		return nil, fmt.Errorf("%w: ID token for another client", sso.ErrInvalidResponse)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: ID token without subject", sso.ErrInvalidResponse)
+	}
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, fmt.Errorf("%w: email not verified by the provider", sso.ErrInvalidResponse)
+	}
+	if claims.Email == "" {
+		return nil, fmt.Errorf("%w: ID token without email", sso.ErrInvalidResponse)
+	}
+
+	assertion := &sso.Assertion{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Name:    claims.Name,
+		Groups:  p.groups(token.IDToken),
+	}
+	if assertion.Name == "" {
+		assertion.Name = claims.PreferredUsername
+	}
+	return assertion, nil
+}
+
+// groups reads the groups claim of a verified ID token, which providers
+// send as a list or a single string
+func (p *OIDCProvider) groups(idToken string) []string {
+	var claims jwt.MapClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, &claims); err != nil {
+		return nil
+	}
+	switch value := claims[p.groupsClaim].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		groups := make([]string, 0, len(value))
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+// discover loads the provider configuration, caching it for discoveryTTL
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	if p.discovery != nil && p.now().Sub(p.discoveredAt) < discoveryTTL {
+		discovery := p.discovery
+		p.mu.Unlock()
+		return discovery, nil
+	}
+	p.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery oidcDiscovery
+	if err := p.do(req, &discovery); err != nil {
+		return nil, err
+	}
+	// The issuer must be the one configured, or tokens of another tenant
+	// of the same provider would be accepted
+	if strings.TrimRight(discovery.Issuer, "/") != p.issuer || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: provider configuration does not match the issuer", sso.ErrInvalidResponse)
+	}
+
+	p.mu.Lock()
+	p.discovery, p.discoveredAt = &discovery, p.now()
+	p.mu.Unlock()
+	return &discovery, nil
+}
+
+// jwk is a public key of the provider's key set
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// key returns the signing key with the ID, refetching the key set when the
+// key is unknown
+func (p *OIDCProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.lookupKey(kid)
+	stale := p.now().Sub(p.keysFetchedAt) >= keysRefreshInterval
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.do(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if publicKey, err := parseJWK(k); err == nil {
+			keys[k.KeyID] = publicKey
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys, p.keysFetchedAt = keys, p.now()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds a cached key; tokens without a key ID are accepted when
+// the set holds a single key. The caller holds the lock.
+func (p *OIDCProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// parseJWK decodes an RSA or P-256 public key
+func parseJWK(k jwk) (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n)*8 < 2048 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("unsupported RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if k.Curve != "P-256" || !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("unsupported EC key")
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+}
+
+// do sends a request and decodes the JSON response
+func (p *OIDCProvider) do(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", sso.ErrInvalidResponse, req.URL.Path, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	return nil
+}
+
+// Verify that OIDCProvider implements the sso.Provider interface
+var _ sso.Provider = (*OIDCProvider)(nil)
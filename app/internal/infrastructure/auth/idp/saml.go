@@ -0,0 +1,488 @@
+package idp
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+
+	"blog-platform/internal/domain/sso"
+)
+
+// SAML namespaces, bindings and formats
+const (
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+
+	bindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	bindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	statusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confirmBearer   = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	nameIDEmail     = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	nameIDAny       = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+)
+
+// clockSkew is the difference between the clocks of the platform and the
+// identity provider that validity windows tolerate
+const clockSkew = 2 * time.Minute
+
+// maxSAMLResponseSize bounds the size of decoded SAML responses
+const maxSAMLResponseSize = 1 << 20
+
+// Attribute names identity providers commonly send the email and name in
+var (
+	emailAttributes = []string{"email", "mail", "emailaddress", "urn:oid:0.9.2342.19200300.100.1.3", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"}
+	nameAttributes  = []string{"name", "displayname", "urn:oid:2.16.840.1.113730.3.1.241", "http://schemas.microsoft.com/identity/claims/displayname", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name"}
+)
+
+// defaultGroupsAttribute is read when the connection names no groups attribute
+const defaultGroupsAttribute = "groups"
+
+// entityDescriptor is the part of SAML metadata the platform uses
+type entityDescriptor struct {
+	XMLName  xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID string   `xml:"entityID,attr"`
+	IDP      []struct {
+		Keys []struct {
+			Use          string   `xml:"use,attr"`
+			Certificates []string `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo>X509Data>X509Certificate"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+		SingleSignOn []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleSignOnService"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+}
+
+// SAMLProvider signs members in with SAML 2.0 Web Browser SSO: requests go
+// out with the HTTP-Redirect binding and responses come back with the
+// HTTP-POST binding. Responses must answer a request of the platform;
+// unsolicited responses are rejected.
+type SAMLProvider struct {
+	// entityID, ssoURL and certificates come from the identity provider's
+	// metadata
+	entityID     string
+	ssoURL       string
+	certificates []*x509.Certificate
+	// spEntityID and acsURL identify the platform to the identity provider
+	spEntityID  string
+	acsURL      string
+	groupsClaim string
+	now         func() time.Time
+}
+
+// NewSAMLProvider creates a provider from the identity provider's metadata
+func NewSAMLProvider(metadata, groupsClaim, spEntityID, acsURL string) (*SAMLProvider, error) {
+	var descriptor entityDescriptor
+	if err := xml.Unmarshal([]byte(metadata), &descriptor); err != nil {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidMetadata, err)
+	}
+	if descriptor.EntityID == "" || len(descriptor.IDP) != 1 {
+		return nil, fmt.Errorf("%w: expected an entity ID and one IDPSSODescriptor", sso.ErrInvalidMetadata)
+	}
+
+	p := &SAMLProvider{
+		entityID:    descriptor.EntityID,
+		spEntityID:  spEntityID,
+		acsURL:      acsURL,
+		groupsClaim: groupsClaim,
+		now:         time.Now,
+	}
+	if p.groupsClaim == "" {
+		p.groupsClaim = defaultGroupsAttribute
+	}
+
+	idp := descriptor.IDP[0]
+	for _, service := range idp.SingleSignOn {
+		if service.Binding == bindingRedirect {
+			p.ssoURL = service.Location
+			break
+		}
+	}
+	if location, err := url.Parse(p.ssoURL); err != nil || (location.Scheme != "https" && location.Scheme != "http") {
+		return nil, fmt.Errorf("%w: expected a SingleSignOnService with the HTTP-Redirect binding", sso.ErrInvalidMetadata)
+	}
+
+	for _, key := range idp.Keys {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, encoded := range key.Certificates {
+			der, err := decodeBase64(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", sso.ErrInvalidMetadata, err)
+			}
+			// Identity providers pin their certificates through the
+			// metadata, so chains are not checked
+			certificate, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", sso.ErrInvalidMetadata, err)
+			}
+			p.certificates = append(p.certificates, certificate)
+		}
+	}
+	if len(p.certificates) == 0 {
+		return nil, fmt.Errorf("%w: expected a signing certificate", sso.ErrInvalidMetadata)
+	}
+
+	return p, nil
+}
+
+// requestID derives the ID of the AuthnRequest from the state, so the
+// response can be matched to the sign-in without storing requests
+func requestID(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return "_" + hex.EncodeToString(sum[:20])
+}
+
+// AuthRequestURL returns the identity provider URL with a deflated
+// AuthnRequest and the state as RelayState
+func (p *SAMLProvider) AuthRequestURL(ctx context.Context, state string) (string, error) {
+	doc := etree.NewDocument()
+	request := doc.CreateElement("samlp:AuthnRequest")
+	request.CreateAttr("xmlns:samlp", nsProtocol)
+	request.CreateAttr("xmlns:saml", nsAssertion)
+	request.CreateAttr("ID", requestID(state))
+	request.CreateAttr("Version", "2.0")
+	request.CreateAttr("IssueInstant", p.now().UTC().Format(time.RFC3339))
+	request.CreateAttr("Destination", p.ssoURL)
+	request.CreateAttr("AssertionConsumerServiceURL", p.acsURL)
+	request.CreateAttr("ProtocolBinding", bindingPOST)
+	request.CreateElement("saml:Issuer").SetText(p.spEntityID)
+	policy := request.CreateElement("samlp:NameIDPolicy")
+	policy.CreateAttr("Format", nameIDAny)
+	policy.CreateAttr("AllowCreate", "true")
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return "", err
+	}
+
+	var deflated bytes.Buffer
+	writer, err := flate.NewWriter(&deflated, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write(raw); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"SAMLRequest": {base64.StdEncoding.EncodeToString(deflated.Bytes())},
+		"RelayState":  {state},
+	}
+	sep := "?"
+	if strings.Contains(p.ssoURL, "?") {
+		sep = "&"
+	}
+	return p.ssoURL + sep + params.Encode(), nil
+}
+
+// Verify checks the signature, issuer, audience, recipient and validity of
+// the response to the request made with state. Either the response or the
+// assertion must be signed; encrypted assertions are not supported. Only the
+// parts of the document a signature covers are read, so content around a
+// signed element cannot stand in for it.
+func (p *SAMLProvider) Verify(ctx context.Context, state string, response sso.Response) (*sso.Assertion, error) {
+	raw, err := decodeBase64(response.SAMLResponse)
+	if err != nil || len(raw) == 0 || len(raw) > maxSAMLResponseSize {
+		return nil, fmt.Errorf("%w: malformed SAMLResponse", sso.ErrInvalidResponse)
+	}
+	root, err := parseXML(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	if !is(root, nsProtocol, "Response") {
+		return nil, fmt.Errorf("%w: not a SAML response", sso.ErrInvalidResponse)
+	}
+
+	id := requestID(state)
+	if root.SelectAttrValue("InResponseTo", "") != id {
+		return nil, fmt.Errorf("%w: response to another request", sso.ErrInvalidResponse)
+	}
+	if destination := root.SelectAttrValue("Destination", ""); destination != "" && destination != p.acsURL {
+		return nil, fmt.Errorf("%w: response for another destination", sso.ErrInvalidResponse)
+	}
+	if issuer := childElement(root, nsAssertion, "Issuer"); issuer != nil && strings.TrimSpace(issuer.Text()) != p.entityID {
+		return nil, fmt.Errorf("%w: response from another issuer", sso.ErrInvalidResponse)
+	}
+	var statusCode *etree.Element
+	if status := childElement(root, nsProtocol, "Status"); status != nil {
+		statusCode = childElement(status, nsProtocol, "StatusCode")
+	}
+	if statusCode == nil || statusCode.SelectAttrValue("Value", "") != statusSuccess {
+		return nil, fmt.Errorf("%w: identity provider reported a failure", sso.ErrInvalidResponse)
+	}
+
+	// A signed response vouches for the assertion in it
+	verified, err := p.verifySignature(root)
+	responseSigned := err == nil
+	if err != nil && !errors.Is(err, dsig.ErrMissingSignature) {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	if responseSigned {
+		root = verified
+	}
+	if len(childElements(root, nsAssertion, "EncryptedAssertion")) > 0 {
+		return nil, fmt.Errorf("%w: encrypted assertions are not supported", sso.ErrInvalidResponse)
+	}
+	assertion := childElement(root, nsAssertion, "Assertion")
+	if assertion == nil {
+		return nil, fmt.Errorf("%w: expected one assertion", sso.ErrInvalidResponse)
+	}
+	// Namespaces declared on the response are carried over, as the
+	// assertion is verified on its own
+	nsCtx, err := etreeutils.NSBuildParentContext(assertion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	if assertion, err = etreeutils.NSDetatch(nsCtx, assertion); err != nil {
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	}
+	verified, err = p.verifySignature(assertion)
+	switch {
+	case err == nil:
+		assertion = verified
+	case !errors.Is(err, dsig.ErrMissingSignature):
+		return nil, fmt.Errorf("%w: %v", sso.ErrInvalidResponse, err)
+	case !responseSigned:
+		return nil, fmt.Errorf("%w: neither the response nor the assertion is signed", sso.ErrInvalidResponse)
+	}
+
+	return p.readAssertion(assertion, id)
+}
+
+// verifySignature checks the signature enveloped in the element against the
+// certificates of the metadata, and returns the element as signed. The
+// signature must reference the element itself by its ID, so a valid
+// signature elsewhere in the document cannot vouch for it. It returns
+// dsig.ErrMissingSignature when the element has no signature.
+func (p *SAMLProvider) verifySignature(el *etree.Element) (*etree.Element, error) {
+	var err error
+	for _, certificate := range p.certificates {
+		validation := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+			Roots: []*x509.Certificate{certificate},
+		})
+		validation.Clock = dsig.NewFakeClockAt(p.now())
+		var verified *etree.Element
+		verified, err = validation.Validate(el)
+		if err == nil || errors.Is(err, dsig.ErrMissingSignature) {
+			return verified, err
+		}
+	}
+	return nil, err
+}
+
+// readAssertion checks the conditions of a verified assertion and reads the
+// account from it
+func (p *SAMLProvider) readAssertion(assertion *etree.Element, id string) (*sso.Assertion, error) {
+	now := p.now()
+
+	issuer := childElement(assertion, nsAssertion, "Issuer")
+	if issuer == nil || strings.TrimSpace(issuer.Text()) != p.entityID {
+		return nil, fmt.Errorf("%w: assertion from another issuer", sso.ErrInvalidResponse)
+	}
+
+	conditions := childElement(assertion, nsAssertion, "Conditions")
+	if conditions == nil {
+		return nil, fmt.Errorf("%w: assertion without conditions", sso.ErrInvalidResponse)
+	}
+	if !withinWindow(now, conditions.SelectAttrValue("NotBefore", ""), conditions.SelectAttrValue("NotOnOrAfter", "")) {
+		return nil, fmt.Errorf("%w: assertion expired or not yet valid", sso.ErrInvalidResponse)
+	}
+	restrictions := childElements(conditions, nsAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return nil, fmt.Errorf("%w: assertion without audience", sso.ErrInvalidResponse)
+	}
+	// Every restriction must admit the platform
+	for _, restriction := range restrictions {
+		admitted := false
+		for _, audience := range childElements(restriction, nsAssertion, "Audience") {
+			admitted = admitted || strings.TrimSpace(audience.Text()) == p.spEntityID
+		}
+		if !admitted {
+			return nil, fmt.Errorf("%w: assertion for another audience", sso.ErrInvalidResponse)
+		}
+	}
+
+	subject := childElement(assertion, nsAssertion, "Subject")
+	if subject == nil {
+		return nil, fmt.Errorf("%w: assertion without subject", sso.ErrInvalidResponse)
+	}
+	nameID := childElement(subject, nsAssertion, "NameID")
+	if nameID == nil || strings.TrimSpace(nameID.Text()) == "" {
+		return nil, fmt.Errorf("%w: assertion without name ID", sso.ErrInvalidResponse)
+	}
+	confirmed := false
+	for _, confirmation := range childElements(subject, nsAssertion, "SubjectConfirmation") {
+		data := childElement(confirmation, nsAssertion, "SubjectConfirmationData")
+		if confirmation.SelectAttrValue("Method", "") != confirmBearer || data == nil {
+			continue
+		}
+		notOnOrAfter := data.SelectAttrValue("NotOnOrAfter", "")
+		inResponseTo := data.SelectAttrValue("InResponseTo", "")
+		if data.SelectAttrValue("Recipient", "") == p.acsURL && notOnOrAfter != "" &&
+			withinWindow(now, data.SelectAttrValue("NotBefore", ""), notOnOrAfter) &&
+			(inResponseTo == "" || inResponseTo == id) {
+			confirmed = true
+			break
+		}
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("%w: no valid bearer confirmation", sso.ErrInvalidResponse)
+	}
+
+	attributes := map[string][]string{}
+	for _, statement := range childElements(assertion, nsAssertion, "AttributeStatement") {
+		for _, attribute := range childElements(statement, nsAssertion, "Attribute") {
+			name := strings.ToLower(attribute.SelectAttrValue("Name", ""))
+			for _, value := range childElements(attribute, nsAssertion, "AttributeValue") {
+				attributes[name] = append(attributes[name], strings.TrimSpace(value.Text()))
+			}
+		}
+	}
+
+	result := &sso.Assertion{
+		Subject: strings.TrimSpace(nameID.Text()),
+		Email:   firstAttribute(attributes, emailAttributes),
+		Name:    firstAttribute(attributes, nameAttributes),
+		Groups:  attributes[strings.ToLower(p.groupsClaim)],
+	}
+	if result.Email == "" && (nameID.SelectAttrValue("Format", "") == nameIDEmail || strings.Contains(result.Subject, "@")) {
+		result.Email = result.Subject
+	}
+	if result.Name == "" {
+		result.Name = strings.TrimSpace(firstAttribute(attributes, []string{"givenname", "urn:oid:2.5.4.42"}) + " " + firstAttribute(attributes, []string{"sn", "surname", "urn:oid:2.5.4.4"}))
+	}
+	if result.Email == "" {
+		return nil, fmt.Errorf("%w: assertion without email", sso.ErrInvalidResponse)
+	}
+
+	return result, nil
+}
+
+// firstAttribute returns the first value of the first attribute present
+func firstAttribute(attributes map[string][]string, names []string) string {
+	for _, name := range names {
+		if values := attributes[name]; len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// withinWindow checks that now is inside the optional validity window,
+// allowing for clock skew
+func withinWindow(now time.Time, notBefore, notOnOrAfter string) bool {
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil || now.Add(clockSkew).Before(t) {
+			return false
+		}
+	}
+	if notOnOrAfter != "" {
+		t, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil || !now.Add(-clockSkew).Before(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServiceProviderMetadata returns the SAML metadata that registers the
+// platform with an identity provider
+func ServiceProviderMetadata(entityID, acsURL string) []byte {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	descriptor := doc.CreateElement("md:EntityDescriptor")
+	descriptor.CreateAttr("xmlns:md", nsMetadata)
+	descriptor.CreateAttr("entityID", entityID)
+	sp := descriptor.CreateElement("md:SPSSODescriptor")
+	sp.CreateAttr("AuthnRequestsSigned", "false")
+	sp.CreateAttr("WantAssertionsSigned", "true")
+	sp.CreateAttr("protocolSupportEnumeration", nsProtocol)
+	for _, format := range []string{nameIDEmail, nameIDAny} {
+		sp.CreateElement("md:NameIDFormat").SetText(format)
+	}
+	acs := sp.CreateElement("md:AssertionConsumerService")
+	acs.CreateAttr("Binding", bindingPOST)
+	acs.CreateAttr("Location", acsURL)
+	acs.CreateAttr("index", "0")
+	acs.CreateAttr("isDefault", "true")
+
+	// Writing to memory cannot fail
+	metadata, _ := doc.WriteToBytes()
+	return metadata
+}
+
+// parseXML parses a document. Documents that do not survive an encoding/xml
+// round trip unchanged, or that have a DTD, are rejected, so the document
+// read is the document that was signed.
+func parseXML(data []byte) (*etree.Element, error) {
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+	for _, token := range doc.Child {
+		if _, ok := token.(*etree.Directive); ok {
+			return nil, errors.New("document type declarations are not allowed")
+		}
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("incomplete document")
+	}
+	return doc.Root(), nil
+}
+
+// is reports whether the element has the namespace and local name
+func is(el *etree.Element, namespace, local string) bool {
+	return el.Tag == local && el.NamespaceURI() == namespace
+}
+
+// childElements returns the child elements with the namespace and local name
+func childElements(el *etree.Element, namespace, local string) []*etree.Element {
+	var found []*etree.Element
+	for _, child := range el.ChildElements() {
+		if is(child, namespace, local) {
+			found = append(found, child)
+		}
+	}
+	return found
+}
+
+// childElement returns the only child element with the namespace and local
+// name, or nil when there is none or more than one
+func childElement(el *etree.Element, namespace, local string) *etree.Element {
+	if found := childElements(el, namespace, local); len(found) == 1 {
+		return found[0]
+	}
+	return nil
+}
+
+// decodeBase64 decodes standard base64 that may be wrapped across lines
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
+
+// Verify that SAMLProvider implements the sso.Provider interface
+var _ sso.Provider = (*SAMLProvider)(nil)
@@ -1,6 +1,10 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,27 +12,60 @@ import (
 	"blog-platform/internal/domain/auth"
 )
 
-// JWTService implements the auth.TokenService interface using JWT
+// Token audiences keep challenge tokens from being used as access tokens
+const (
+	accessAudience    = "blog-platform-api"
+	challengeAudience = "blog-platform-2fa"
+)
+
+// JWTService implements the auth.TokenService interface using JWT. Tokens
+// are signed with one key and verified with any of the configured keys,
+// selected by the kid header, so signing keys can be rotated without
+// invalidating issued tokens.
 type JWTService struct {
-	secretKey []byte
+	// signing is nil when no usable key is configured
+	signing *SigningKey
+	// keys holds the verification keys by ID; HS256 tokens have no kid
+	keys map[string]SigningKey
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a JWT service signing tokens with a shared secret
+// (HS256)
 func NewJWTService(secretKey string) *JWTService {
-	return &JWTService{
-		secretKey: []byte(secretKey),
+	service, err := NewJWTServiceWithKeys(NewHMACKey(secretKey))
+	if err != nil {
+		// Operations report auth.ErrInvalidSecretKey
+		return &JWTService{keys: map[string]SigningKey{}}
+	}
+	return service
+}
+
+// NewJWTServiceWithKeys creates a JWT service signing tokens with the
+// signing key and also accepting tokens signed with the verification keys
+func NewJWTServiceWithKeys(signing SigningKey, verification ...SigningKey) (*JWTService, error) {
+	if !signing.CanSign() {
+		return nil, auth.ErrInvalidSecretKey
+	}
+
+	keys := make(map[string]SigningKey, len(verification)+1)
+	for _, key := range verification {
+		keys[key.ID] = key
 	}
+	keys[signing.ID] = signing
+
+	return &JWTService{signing: &signing, keys: keys}, nil
 }
 
 // Claims represents the JWT claims structure
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken creates a JWT token with the given claims
-func (j *JWTService) GenerateToken(userID int, email string, duration time.Duration) (string, error) {
+func (j *JWTService) GenerateToken(userID int, email, role string, duration time.Duration) (string, error) {
 	// Validate inputs
 	if userID <= 0 {
 		return "", auth.ErrInvalidUserID
@@ -39,34 +76,34 @@ func (j *JWTService) GenerateToken(userID int, email string, duration time.Durat
 	if duration <= 0 {
 		return "", auth.ErrInvalidDuration
 	}
-	if len(j.secretKey) == 0 {
+	if j.signing == nil {
 		return "", auth.ErrInvalidSecretKey
 	}
 
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", auth.ErrTokenGeneration
+	}
+
 	now := time.Now()
 	expirationTime := now.Add(duration)
 
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "blog-platform",
 			Subject:   email,
-			Audience:  []string{"blog-platform-api"},
+			Audience:  []string{accessAudience},
 		},
 	}
 
-	// Use HS256 for security (HMAC with SHA-256)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(j.secretKey)
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
+	return j.sign(claims)
 }
 
 // ValidateToken validates a JWT token and returns claims
@@ -74,17 +111,11 @@ func (j *JWTService) ValidateToken(tokenString string) (*auth.TokenClaims, error
 	if tokenString == "" {
 		return nil, auth.ErrEmptyToken
 	}
-	if len(j.secretKey) == 0 {
+	if len(j.keys) == 0 {
 		return nil, auth.ErrInvalidSecretKey
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, auth.ErrInvalidToken
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey, jwt.WithAudience(accessAudience))
 
 	if err != nil {
 		// Check if token is expired by examining the error message
@@ -101,8 +132,10 @@ func (j *JWTService) ValidateToken(tokenString string) (*auth.TokenClaims, error
 	}
 
 	return &auth.TokenClaims{
+		ID:        claims.ID,
 		UserID:    claims.UserID,
 		Email:     claims.Email,
+		Role:      claims.Role,
 		IssuedAt:  claims.IssuedAt.Unix(),
 		ExpiresAt: claims.ExpiresAt.Unix(),
 	}, nil
@@ -118,5 +151,111 @@ func (j *JWTService) RefreshToken(tokenString string) (string, error) {
 	// Generate new token with same user info but extended expiry
 	// Add a small delay to ensure different issued at time
 	time.Sleep(1 * time.Millisecond)
-	return j.GenerateToken(claims.UserID, claims.Email, 24*time.Hour)
+	return j.GenerateToken(claims.UserID, claims.Email, claims.Role, 24*time.Hour)
+}
+
+// GenerateChallengeToken creates a token for the second step of a
+// two-factor login
+func (j *JWTService) GenerateChallengeToken(userID int, duration time.Duration) (string, error) {
+	if userID <= 0 {
+		return "", auth.ErrInvalidUserID
+	}
+	if duration <= 0 {
+		return "", auth.ErrInvalidDuration
+	}
+	if j.signing == nil {
+		return "", auth.ErrInvalidSecretKey
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "blog-platform",
+			Audience:  []string{challengeAudience},
+		},
+	}
+
+	return j.sign(claims)
+}
+
+// ValidateChallengeToken validates a challenge token and returns its user ID
+func (j *JWTService) ValidateChallengeToken(tokenString string) (int, error) {
+	if tokenString == "" {
+		return 0, auth.ErrEmptyToken
+	}
+	if len(j.keys) == 0 {
+		return 0, auth.ErrInvalidSecretKey
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey, jwt.WithAudience(challengeAudience))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, auth.ErrTokenExpired
+		}
+		return 0, auth.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.UserID <= 0 {
+		return 0, auth.ErrInvalidToken
+	}
+	return claims.UserID, nil
+}
+
+// JWKS returns the public keys tokens may be signed with, the signing key
+// first. HMAC secrets are left out.
+func (j *JWTService) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range j.keys {
+		if key.Publishable() {
+			set.Keys = append(set.Keys, key.JWK())
+		}
+	}
+
+	signingID := ""
+	if j.signing != nil {
+		signingID = j.signing.ID
+	}
+	sort.Slice(set.Keys, func(a, b int) bool {
+		if (set.Keys[a].KeyID == signingID) != (set.Keys[b].KeyID == signingID) {
+			return set.Keys[a].KeyID == signingID
+		}
+		return set.Keys[a].KeyID < set.Keys[b].KeyID
+	})
+	return set
+}
+
+// sign signs claims with the signing key, naming it in the kid header
+func (j *JWTService) sign(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(j.signing.Method, claims)
+	if j.signing.ID != "" {
+		token.Header["kid"] = j.signing.ID
+	}
+	return token.SignedString(j.signing.signKey)
+}
+
+// verificationKey selects the key named by a token's kid header. The token
+// must use the algorithm of that key, so a public key can never be used as
+// an HMAC secret.
+func (j *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, auth.ErrInvalidToken
+	}
+	return key.verifyKey, nil
+}
+
+// newTokenID generates a random identifier for the jti claim, used to revoke
+// individual tokens
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryTokenBlacklist implements auth.TokenBlacklist in process memory.
+// Entries are dropped once the token they refer to has expired. Revocations
+// are not shared between instances; use the Redis blacklist when running
+// more than one server.
+type MemoryTokenBlacklist struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+}
+
+// NewMemoryTokenBlacklist creates an in-memory blacklist with a background
+// cleanup of expired entries
+func NewMemoryTokenBlacklist() *MemoryTokenBlacklist {
+	b := &MemoryTokenBlacklist{
+		entries: make(map[string]time.Time),
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			b.cleanup()
+		}
+	}()
+
+	return b
+}
+
+// Add blacklists a token until the given expiry
+func (b *MemoryTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if !time.Now().Before(expiresAt) {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[tokenID] = expiresAt
+	return nil
+}
+
+// Contains reports whether a token is blacklisted
+func (b *MemoryTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	expiresAt, exists := b.entries[tokenID]
+	return exists && time.Now().Before(expiresAt), nil
+}
+
+// cleanup removes entries whose tokens have expired
+func (b *MemoryTokenBlacklist) cleanup() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for id, expiresAt := range b.entries {
+		if !now.Before(expiresAt) {
+			delete(b.entries, id)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// attemptCount is a failure count and when it expires
+type attemptCount struct {
+	failures  int
+	expiresAt time.Time
+}
+
+// MemoryLoginAttemptStore implements user.LoginAttemptStore in process
+// memory. Counts are not shared between instances; use the Redis store when
+// running more than one server.
+type MemoryLoginAttemptStore struct {
+	mu     sync.Mutex
+	counts map[string]attemptCount
+}
+
+// NewMemoryLoginAttemptStore creates an in-memory attempt store with a
+// background cleanup of expired counts
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	s := &MemoryLoginAttemptStore{
+		counts: make(map[string]attemptCount),
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.cleanup()
+		}
+	}()
+
+	return s
+}
+
+// RecordFailure counts a failure; the count expires window after it
+func (s *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	count := s.counts[key]
+	if !now.Before(count.expiresAt) {
+		count = attemptCount{}
+	}
+	count.failures++
+	count.expiresAt = now.Add(window)
+	s.counts[key] = count
+
+	return count.failures, nil
+}
+
+// Failures returns the current count and when it expires
+func (s *MemoryLoginAttemptStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, exists := s.counts[key]
+	if !exists || !time.Now().Before(count.expiresAt) {
+		return 0, time.Time{}, nil
+	}
+	return count.failures, count.expiresAt, nil
+}
+
+// Reset clears the count
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counts, key)
+	return nil
+}
+
+// cleanup removes expired counts
+func (s *MemoryLoginAttemptStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, count := range s.counts {
+		if !now.Before(count.expiresAt) {
+			delete(s.counts, key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"blog-platform/internal/domain/auth"
+)
+
+// memoryPasskeyChallenge is a ceremony session with its expiry
+type memoryPasskeyChallenge struct {
+	session   auth.PasskeySession
+	expiresAt time.Time
+}
+
+// MemoryPasskeyChallengeStore implements auth.PasskeyChallengeStore in
+// process memory. Challenges are not shared between instances; use the
+// Redis store when running more than one server.
+type MemoryPasskeyChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]memoryPasskeyChallenge
+}
+
+// NewMemoryPasskeyChallengeStore creates an in-memory challenge store with
+// a background cleanup of expired challenges
+func NewMemoryPasskeyChallengeStore() *MemoryPasskeyChallengeStore {
+	s := &MemoryPasskeyChallengeStore{
+		challenges: make(map[string]memoryPasskeyChallenge),
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.cleanup()
+		}
+	}()
+
+	return s
+}
+
+// Save keeps the session of a challenge for ttl
+func (s *MemoryPasskeyChallengeStore) Save(ctx context.Context, challenge string, session *auth.PasskeySession, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges[challenge] = memoryPasskeyChallenge{session: *session, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Take returns and removes the session of a challenge
+func (s *MemoryPasskeyChallengeStore) Take(ctx context.Context, challenge string) (*auth.PasskeySession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.challenges[challenge]
+	if !exists {
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+	delete(s.challenges, challenge)
+	if !time.Now().Before(entry.expiresAt) {
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+	return &entry.session, nil
+}
+
+// cleanup removes expired challenges
+func (s *MemoryPasskeyChallengeStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for challenge, entry := range s.challenges {
+		if !now.Before(entry.expiresAt) {
+			delete(s.challenges, challenge)
+		}
+	}
+}
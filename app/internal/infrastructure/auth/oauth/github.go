@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"blog-platform/internal/domain/user"
+)
+
+// GitHubEndpoints are the production GitHub OAuth and REST API endpoints
+var GitHubEndpoints = Endpoints{
+	AuthURL:     "https://github.com/login/oauth/authorize",
+	TokenURL:    "https://github.com/login/oauth/access_token",
+	UserInfoURL: "https://api.github.com/user",
+	EmailsURL:   "https://api.github.com/user/emails",
+}
+
+// GitHubProvider signs users in with their GitHub account
+type GitHubProvider struct {
+	client
+}
+
+// NewGitHubProvider creates a GitHub provider
+func NewGitHubProvider(credentials Credentials, endpoints Endpoints, httpClient *http.Client) *GitHubProvider {
+	return &GitHubProvider{client{
+		credentials: credentials,
+		endpoints:   endpoints,
+		scopes:      []string{"read:user", "user:email"},
+		httpClient:  httpClient,
+	}}
+}
+
+// githubUser is the authenticated user response
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// githubEmail is an entry of the user's email list
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// Name identifies the provider
+func (p *GitHubProvider) Name() string {
+	return "github"
+}
+
+// AuthCodeURL returns the GitHub authorization URL
+func (p *GitHubProvider) AuthCodeURL(state, redirectURL string) string {
+	return p.authCodeURL(state, redirectURL)
+}
+
+// Identity exchanges the code and loads the account with its primary
+// verified email. The public profile email is not used because GitHub does
+// not report whether it is verified.
+func (p *GitHubProvider) Identity(ctx context.Context, code, redirectURL string) (*user.ExternalIdentity, error) {
+	accessToken, err := p.exchange(ctx, code, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var account githubUser
+	if err := p.get(ctx, p.endpoints.UserInfoURL, accessToken, &account); err != nil {
+		return nil, err
+	}
+	if account.ID == 0 {
+		return nil, fmt.Errorf("%w: user without id", ErrExchangeFailed)
+	}
+
+	var emails []githubEmail
+	if err := p.get(ctx, p.endpoints.EmailsURL, accessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &user.ExternalIdentity{
+		Provider: p.Name(),
+		Subject:  strconv.FormatInt(account.ID, 10),
+		Name:     account.Name,
+	}
+	if identity.Name == "" {
+		identity.Name = account.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return identity, nil
+}
+
+// Verify that GitHubProvider implements the Provider interface
+var _ Provider = (*GitHubProvider)(nil)
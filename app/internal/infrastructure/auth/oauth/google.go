@@ -0,0 +1,76 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"blog-platform/internal/domain/user"
+)
+
+// GoogleEndpoints are the production Google OAuth2 and OpenID Connect endpoints
+var GoogleEndpoints = Endpoints{
+	AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+	TokenURL:    "https://oauth2.googleapis.com/token",
+	UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+}
+
+// GoogleProvider signs users in with their Google account
+type GoogleProvider struct {
+	client
+}
+
+// NewGoogleProvider creates a Google provider
+func NewGoogleProvider(credentials Credentials, endpoints Endpoints, httpClient *http.Client) *GoogleProvider {
+	return &GoogleProvider{client{
+		credentials: credentials,
+		endpoints:   endpoints,
+		scopes:      []string{"openid", "email", "profile"},
+		httpClient:  httpClient,
+	}}
+}
+
+// googleUserInfo is the OpenID Connect user info response
+type googleUserInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Name identifies the provider
+func (p *GoogleProvider) Name() string {
+	return "google"
+}
+
+// AuthCodeURL returns the Google consent screen URL
+func (p *GoogleProvider) AuthCodeURL(state, redirectURL string) string {
+	return p.authCodeURL(state, redirectURL)
+}
+
+// Identity exchanges the code and loads the account from the user info endpoint
+func (p *GoogleProvider) Identity(ctx context.Context, code, redirectURL string) (*user.ExternalIdentity, error) {
+	accessToken, err := p.exchange(ctx, code, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var info googleUserInfo
+	if err := p.get(ctx, p.endpoints.UserInfoURL, accessToken, &info); err != nil {
+		return nil, err
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("%w: user info without subject", ErrExchangeFailed)
+	}
+
+	return &user.ExternalIdentity{
+		Provider:      p.Name(),
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	}, nil
+}
+
+// Verify that GoogleProvider implements the Provider interface
+var _ Provider = (*GoogleProvider)(nil)
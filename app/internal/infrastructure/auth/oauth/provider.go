@@ -0,0 +1,149 @@
+// Package oauth implements sign-in through external OAuth2 identity providers
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"blog-platform/internal/domain/user"
+)
+
+// maxResponseSize bounds the size of provider responses that are decoded
+const maxResponseSize = 1 << 20
+
+var (
+	// ErrUnknownProvider is returned for providers that are not configured
+	ErrUnknownProvider = errors.New("oauth provider not found")
+	// ErrExchangeFailed is returned when the provider rejects the
+	// authorization code or the account lookup fails
+	ErrExchangeFailed = errors.New("oauth exchange failed")
+)
+
+// Provider signs users in through an external OAuth2 authorization server
+type Provider interface {
+	// Name identifies the provider in URLs and linked identities
+	Name() string
+	// AuthCodeURL returns the provider URL the user is redirected to
+	AuthCodeURL(state, redirectURL string) string
+	// Identity exchanges an authorization code for the user's account details
+	Identity(ctx context.Context, code, redirectURL string) (*user.ExternalIdentity, error)
+}
+
+// Credentials holds the client registration at a provider
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Endpoints holds the URLs of a provider
+type Endpoints struct {
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	// EmailsURL lists the account's email addresses where the user info
+	// does not include a verified address
+	EmailsURL string
+}
+
+// client performs the provider-independent parts of the authorization code flow
+type client struct {
+	credentials Credentials
+	endpoints   Endpoints
+	scopes      []string
+	httpClient  *http.Client
+}
+
+// authCodeURL builds the authorization request URL
+func (c *client) authCodeURL(state, redirectURL string) string {
+	params := url.Values{
+		"client_id":     {c.credentials.ClientID},
+		"redirect_uri":  {redirectURL},
+		"response_type": {"code"},
+		"scope":         {strings.Join(c.scopes, " ")},
+		"state":         {state},
+	}
+
+	sep := "?"
+	if strings.Contains(c.endpoints.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.endpoints.AuthURL + sep + params.Encode()
+}
+
+// tokenResponse is the token endpoint response
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Error       string `json:"error"`
+}
+
+// exchange trades an authorization code for an access token
+func (c *client) exchange(ctx context.Context, code, redirectURL string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {c.credentials.ClientID},
+		"client_secret": {c.credentials.ClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoints.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token tokenResponse
+	if err := c.do(req, &token); err != nil {
+		return "", err
+	}
+	if token.Error != "" {
+		return "", fmt.Errorf("%w: %s", ErrExchangeFailed, token.Error)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("%w: no access token in response", ErrExchangeFailed)
+	}
+
+	return token.AccessToken, nil
+}
+
+// get fetches a provider API resource with the access token
+func (c *client) get(ctx context.Context, endpoint, accessToken string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	return c.do(req, v)
+}
+
+// do sends a request and decodes the JSON response
+func (c *client) do(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrExchangeFailed, req.URL.Path, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"blog-platform/internal/infrastructure/config"
+)
+
+// httpTimeout bounds each request to a provider
+const httpTimeout = 10 * time.Second
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry with the given providers
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// NewRegistryFromConfig registers every provider that has a client ID configured
+func NewRegistryFromConfig(cfg *config.Config) *Registry {
+	httpClient := &http.Client{Timeout: httpTimeout}
+
+	var providers []Provider
+	if cfg.OAuth.GoogleClientID != "" {
+		providers = append(providers, NewGoogleProvider(Credentials{
+			ClientID:     cfg.OAuth.GoogleClientID,
+			ClientSecret: cfg.OAuth.GoogleClientSecret,
+		}, GoogleEndpoints, httpClient))
+	}
+	if cfg.OAuth.GitHubClientID != "" {
+		providers = append(providers, NewGitHubProvider(Credentials{
+			ClientID:     cfg.OAuth.GitHubClientID,
+			ClientSecret: cfg.OAuth.GitHubClientSecret,
+		}, GitHubEndpoints, httpClient))
+	}
+
+	return NewRegistry(providers...)
+}
+
+// Get returns a provider by name
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// Names returns the names of the configured providers in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
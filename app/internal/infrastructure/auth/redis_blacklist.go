@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// redisBlacklistPrefix namespaces blacklist keys in Redis
+const redisBlacklistPrefix = "auth:blacklist:"
+
+// RedisTokenBlacklist implements auth.TokenBlacklist on top of Redis so
+// revocations are shared by every server instance. Keys expire together
+// with the token they refer to.
+type RedisTokenBlacklist struct {
+	client *redis.Client
+}
+
+// NewRedisTokenBlacklist creates a Redis-backed blacklist
+func NewRedisTokenBlacklist(client *redis.Client) *RedisTokenBlacklist {
+	return &RedisTokenBlacklist{client: client}
+}
+
+// Add blacklists a token until the given expiry
+func (b *RedisTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return b.client.Set(ctx, redisBlacklistPrefix+tokenID, "1", ttl)
+}
+
+// Contains reports whether a token is blacklisted
+func (b *RedisTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	return b.client.Exists(ctx, redisBlacklistPrefix+tokenID)
+}
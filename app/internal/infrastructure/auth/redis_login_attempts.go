@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// redisAttemptsPrefix namespaces login attempt keys in Redis
+const redisAttemptsPrefix = "auth:attempts:"
+
+// RedisLoginAttemptStore implements user.LoginAttemptStore on top of Redis
+// so counts are shared by every server instance
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+// NewRedisLoginAttemptStore creates a Redis-backed attempt store
+func NewRedisLoginAttemptStore(client *redis.Client) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{client: client}
+}
+
+// RecordFailure counts a failure; the count expires window after it
+func (s *RedisLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	failures, err := s.client.Int(ctx, "INCR", redisAttemptsPrefix+key)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.client.Int(ctx, "PEXPIRE", redisAttemptsPrefix+key, strconv.FormatInt(window.Milliseconds(), 10)); err != nil {
+		return 0, err
+	}
+	return int(failures), nil
+}
+
+// Failures returns the current count and when it expires
+func (s *RedisLoginAttemptStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	value, err := s.client.Get(ctx, redisAttemptsPrefix+key)
+	if err == redis.ErrNil {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	failures, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	ttl, err := s.client.Int(ctx, "PTTL", redisAttemptsPrefix+key)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if ttl < 0 {
+		// Expired between the two commands
+		return 0, time.Time{}, nil
+	}
+	return failures, time.Now().Add(time.Duration(ttl) * time.Millisecond), nil
+}
+
+// Reset clears the count
+func (s *RedisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisAttemptsPrefix+key)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// redisPasskeyChallengePrefix namespaces passkey challenge keys in Redis
+const redisPasskeyChallengePrefix = "auth:passkey:"
+
+// RedisPasskeyChallengeStore implements auth.PasskeyChallengeStore on top
+// of Redis so a ceremony may finish on another server instance than the one
+// it began on. Sessions are stored as JSON and expire with their challenge.
+type RedisPasskeyChallengeStore struct {
+	client *redis.Client
+}
+
+// NewRedisPasskeyChallengeStore creates a Redis-backed challenge store
+func NewRedisPasskeyChallengeStore(client *redis.Client) *RedisPasskeyChallengeStore {
+	return &RedisPasskeyChallengeStore{client: client}
+}
+
+// Save keeps the session of a challenge for ttl
+func (s *RedisPasskeyChallengeStore) Save(ctx context.Context, challenge string, session *auth.PasskeySession, ttl time.Duration) error {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode passkey session: %w", err)
+	}
+	return s.client.Set(ctx, redisPasskeyChallengePrefix+challenge, string(value), ttl)
+}
+
+// Take returns and removes the session of a challenge. GETDEL (Redis 6.2)
+// makes reading and removing one step, so a challenge is answered once.
+func (s *RedisPasskeyChallengeStore) Take(ctx context.Context, challenge string) (*auth.PasskeySession, error) {
+	reply, err := s.client.Do(ctx, "GETDEL", redisPasskeyChallengePrefix+challenge)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected reply type %T", reply)
+	}
+
+	var session auth.PasskeySession
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, fmt.Errorf("failed to decode passkey session: %w", err)
+	}
+	return &session, nil
+}
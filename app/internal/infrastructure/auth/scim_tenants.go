@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// SCIMTenants authenticates SCIM clients by the bearer token configured for
+// their organization. Only token hashes are kept, compared in constant time.
+type SCIMTenants struct {
+	tokens map[string][sha256.Size]byte
+}
+
+// NewSCIMTenants creates an authenticator from a map of tenants to tokens;
+// tenants without a token are skipped
+func NewSCIMTenants(tokens map[string]string) *SCIMTenants {
+	t := &SCIMTenants{tokens: make(map[string][sha256.Size]byte, len(tokens))}
+	for tenant, token := range tokens {
+		if tenant != "" && token != "" {
+			t.tokens[tenant] = sha256.Sum256([]byte(token))
+		}
+	}
+	return t
+}
+
+// Authenticate returns the tenant a token was issued to. Every tenant is
+// compared so the time taken does not reveal which one matched.
+func (t *SCIMTenants) Authenticate(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	found := ""
+	for tenant, expected := range t.tokens {
+		if subtle.ConstantTimeCompare(hash[:], expected[:]) == 1 {
+			found = tenant
+		}
+	}
+	return found, found != ""
+}
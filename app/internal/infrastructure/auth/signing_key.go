@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing keys
+const minRSAKeyBits = 2048
+
+// ErrUnsupportedKey is returned for PEM keys that are neither RSA nor Ed25519
+var ErrUnsupportedKey = errors.New("unsupported signing key")
+
+// SigningKey is a key tokens are signed or verified with. Asymmetric keys
+// are identified by their RFC 7638 thumbprint, which tokens carry in the kid
+// header; HMAC keys have no ID.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// signKey is nil for keys that only verify tokens
+	signKey   any
+	verifyKey any
+}
+
+// NewHMACKey creates an HS256 key from a shared secret
+func NewHMACKey(secret string) SigningKey {
+	key := SigningKey{Method: jwt.SigningMethodHS256}
+	if secret != "" {
+		key.signKey = []byte(secret)
+		key.verifyKey = []byte(secret)
+	}
+	return key
+}
+
+// LoadPEMKey reads a signing key from a PEM file
+func LoadPEMKey(path string) (SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	key, err := ParsePEMKey(data)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePEMKey parses an RSA (RS256) or Ed25519 (EdDSA) key. Private keys
+// sign and verify tokens; public keys only verify them.
+func ParsePEMKey(data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("%w: no PEM data", ErrUnsupportedKey)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("%w: PEM type %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	var key SigningKey
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key = SigningKey{Method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}
+	case *rsa.PublicKey:
+		key = SigningKey{Method: jwt.SigningMethodRS256, verifyKey: k}
+	case ed25519.PrivateKey:
+		key = SigningKey{Method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}
+	case ed25519.PublicKey:
+		key = SigningKey{Method: jwt.SigningMethodEdDSA, verifyKey: k}
+	default:
+		return SigningKey{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, parsed)
+	}
+
+	if rsaKey, ok := key.verifyKey.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSAKeyBits {
+		return SigningKey{}, fmt.Errorf("%w: RSA keys need at least %d bits", ErrUnsupportedKey, minRSAKeyBits)
+	}
+
+	key.ID = thumbprint(key.JWK())
+	return key, nil
+}
+
+// CanSign reports whether the key can sign tokens
+func (k SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// Publishable reports whether the key is asymmetric; HMAC secrets are never
+// published
+func (k SigningKey) Publishable() bool {
+	_, hmac := k.Method.(*jwt.SigningMethodHMAC)
+	return !hmac
+}
+
+// JWK returns the public part of an asymmetric key
+func (k SigningKey) JWK() JWK {
+	jwk := JWK{Use: "sig", Algorithm: k.Method.Alg(), KeyID: k.ID}
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a key from its required
+// members in lexicographic order
+func thumbprint(jwk JWK) string {
+	var members any
+	if jwk.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
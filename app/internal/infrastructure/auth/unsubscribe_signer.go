@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"blog-platform/internal/domain/announcement"
+)
+
+// UnsubscribeSigner issues the tokens of announcement unsubscribe links.
+// Tokens are the user ID signed with HMAC-SHA256; they do not expire, so
+// links in old emails keep working.
+type UnsubscribeSigner struct {
+	secret []byte
+}
+
+// NewUnsubscribeSigner creates an unsubscribe token signer
+func NewUnsubscribeSigner(secret string) *UnsubscribeSigner {
+	return &UnsubscribeSigner{secret: []byte(secret)}
+}
+
+// Issue creates the unsubscribe token of a user
+func (s *UnsubscribeSigner) Issue(userID int) string {
+	payload := strconv.Itoa(userID)
+	return payload + "." + s.sign(payload)
+}
+
+// Verify returns the user an unsubscribe token was issued for
+func (s *UnsubscribeSigner) Verify(token string) (int, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return 0, announcement.ErrInvalidUnsubscribeToken
+	}
+
+	userID, err := strconv.Atoi(payload)
+	if err != nil || userID <= 0 {
+		return 0, announcement.ErrInvalidUnsubscribeToken
+	}
+	return userID, nil
+}
+
+// sign computes the signature of a payload
+func (s *UnsubscribeSigner) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify that UnsubscribeSigner implements the announcement.UnsubscribeTokens interface
+var _ announcement.UnsubscribeTokens = (*UnsubscribeSigner)(nil)
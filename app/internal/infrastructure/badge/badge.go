@@ -0,0 +1,194 @@
+// Package badge renders small SVG counters of post stats that authors embed
+// in external sites
+package badge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+const (
+	// ContentType is the media type of rendered badges
+	ContentType = "image/svg+xml"
+	// maxCacheEntries bounds the number of rendered badges kept in memory
+	maxCacheEntries = 10000
+)
+
+// Settings configures the rendered badges
+type Settings struct {
+	// CacheTTL is how long a rendered badge is served before its count is
+	// read again; zero disables caching
+	CacheTTL time.Duration
+}
+
+// Renderer renders the badges of posts and caches them, so embedding sites
+// with many visitors do not reach the database on every page view
+type Renderer struct {
+	posts    post.Service
+	settings Settings
+
+	mu     sync.Mutex
+	cache  map[string]cacheEntry
+	hits   uint64
+	misses uint64
+}
+
+// CacheStats reports how well the rendered badge cache is working
+type CacheStats struct {
+	Enabled bool    `json:"enabled"`
+	Entries int     `json:"entries"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewRenderer creates a new badge renderer
+func NewRenderer(posts post.Service, settings Settings) *Renderer {
+	return &Renderer{
+		posts:    posts,
+		settings: settings,
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+// CacheTTL returns how long clients may cache a badge
+func (r *Renderer) CacheTTL() time.Duration {
+	return r.settings.CacheTTL
+}
+
+// CacheStats returns the hit rate and size of the rendered badge cache
+func (r *Renderer) CacheStats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := CacheStats{
+		Enabled: r.settings.CacheTTL > 0,
+		Entries: len(r.cache),
+		Hits:    r.hits,
+		Misses:  r.misses,
+	}
+	if total := r.hits + r.misses; total > 0 {
+		stats.HitRate = float64(r.hits) / float64(total)
+	}
+	return stats
+}
+
+// PostViews returns the badge counting the views of a published post.
+// Unpublished and private posts answer ErrPostNotFound, so badges do not
+// leak them.
+func (r *Renderer) PostViews(ctx context.Context, postID int) ([]byte, error) {
+	return r.cached(fmt.Sprintf("views:%d", postID), func() ([]byte, error) {
+		p, err := r.posts.GetVisiblePost(ctx, 0, "", postID)
+		if err != nil {
+			return nil, err
+		}
+		return Render("views", Count(p.ViewCount)), nil
+	})
+}
+
+// cached returns the cached badge for the key, rendering and storing it
+// when it is missing or expired. Errors are not cached.
+func (r *Renderer) cached(key string, render func() ([]byte, error)) ([]byte, error) {
+	if r.settings.CacheTTL <= 0 {
+		return render()
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	fresh := ok && now.Before(entry.expiresAt)
+	if fresh {
+		r.hits++
+	} else {
+		r.misses++
+	}
+	r.mu.Unlock()
+	if fresh {
+		return entry.body, nil
+	}
+
+	body, err := render()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.cache) >= maxCacheEntries {
+		r.evictExpired(now)
+	}
+	if len(r.cache) >= maxCacheEntries {
+		// Every entry is fresh; start over rather than grow without bound
+		r.cache = make(map[string]cacheEntry)
+	}
+	r.cache[key] = cacheEntry{body: body, expiresAt: now.Add(r.settings.CacheTTL)}
+	return body, nil
+}
+
+// evictExpired removes expired entries; the caller must hold the lock
+func (r *Renderer) evictExpired(now time.Time) {
+	for key, entry := range r.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(r.cache, key)
+		}
+	}
+}
+
+// Count formats a counter compactly, e.g. 950, 1.2k or 3.4M
+func Count(n int64) string {
+	switch {
+	case n < 1000:
+		return strconv.FormatInt(n, 10)
+	case n < 1_000_000:
+		return compact(n, 1000, "k")
+	default:
+		return compact(n, 1_000_000, "M")
+	}
+}
+
+// compact formats n in units with a decimal below 100 units; the decimal is
+// dropped rather than rounded up so a badge never overstates its count
+func compact(n, unit int64, suffix string) string {
+	whole := n / unit
+	if whole >= 100 {
+		return strconv.FormatInt(whole, 10) + suffix
+	}
+	tenths := n % unit * 10 / unit
+	if tenths == 0 {
+		return strconv.FormatInt(whole, 10) + suffix
+	}
+	return fmt.Sprintf("%d.%d%s", whole, tenths, suffix)
+}
+
+// Render draws a flat two-part badge with a label and a value. Both are
+// plain ASCII the package produces, so they need no escaping.
+func Render(label, value string) []byte {
+	labelWidth := textWidth(label)
+	valueWidth := textWidth(value)
+	width := labelWidth + valueWidth
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[3]s">`+
+		`<title>%[2]s: %[3]s</title>`+
+		`<rect width="%[4]d" height="20" fill="#555"/>`+
+		`<rect x="%[4]d" width="%[5]d" height="20" fill="#007ec6"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[6]d" y="14">%[2]s</text>`+
+		`<text x="%[7]d" y="14">%[3]s</text>`+
+		`</g></svg>`,
+		width, label, value, labelWidth, valueWidth, labelWidth/2, labelWidth+valueWidth/2))
+}
+
+// textWidth approximates the width of a text in 11px Verdana with padding
+func textWidth(s string) int {
+	return len(s)*7 + 10
+}
@@ -0,0 +1,97 @@
+// Package cache implements the service.Cache interface in process memory or
+// in Redis, counting hits and misses for diagnostics
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// New creates a cache based on configuration
+func New(cfg *config.Config, client *redis.Client) *Metered {
+	switch strings.ToLower(cfg.Cache.Driver) {
+	case "redis":
+		return NewMetered(NewRedisCache(client))
+	default:
+		return NewMetered(NewMemoryCache(cfg.Cache.MaxEntries))
+	}
+}
+
+// Counts are the hits and misses of a group of keys
+type Counts struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// counters are the live Counts of a group of keys
+type counters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Metered wraps a cache, counting hits and misses per group of keys. The
+// group of a key is the part before its first colon, e.g. "post".
+type Metered struct {
+	cache service.Cache
+	// groups maps group names to their *counters
+	groups sync.Map
+}
+
+// NewMetered counts the hits and misses of cache
+func NewMetered(cache service.Cache) *Metered {
+	return &Metered{cache: cache}
+}
+
+// Get returns the value stored at key, counting a hit or a miss. Failed
+// reads count as misses.
+func (m *Metered) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found, err := m.cache.Get(ctx, key)
+	c := m.counters(key)
+	if found {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, found, err
+}
+
+// Set stores a value at key for ttl
+func (m *Metered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return m.cache.Set(ctx, key, value, ttl)
+}
+
+// Delete removes keys
+func (m *Metered) Delete(ctx context.Context, keys ...string) error {
+	return m.cache.Delete(ctx, keys...)
+}
+
+// Stats returns the hits and misses of every group of keys read so far
+func (m *Metered) Stats() map[string]Counts {
+	stats := make(map[string]Counts)
+	m.groups.Range(func(group, value any) bool {
+		c := value.(*counters)
+		stats[group.(string)] = Counts{Hits: c.hits.Load(), Misses: c.misses.Load()}
+		return true
+	})
+	return stats
+}
+
+// counters returns the counters of the group of key
+func (m *Metered) counters(key string) *counters {
+	group, _, _ := strings.Cut(key, ":")
+	if c, ok := m.groups.Load(group); ok {
+		return c.(*counters)
+	}
+	c, _ := m.groups.LoadOrStore(group, &counters{})
+	return c.(*counters)
+}
+
+// Verify that Metered implements the service.Cache interface
+var _ service.Cache = (*Metered)(nil)
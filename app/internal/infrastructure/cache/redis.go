@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// redisKeyPrefix namespaces cache keys in Redis
+const redisKeyPrefix = "cache:"
+
+// RedisCache implements service.Cache on top of Redis, so every server
+// instance shares the cache and sees invalidations at once
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates a Redis-backed cache
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+// Get returns the value stored at key
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, redisKeyPrefix+key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(value), true, nil
+}
+
+// Set stores a value at key for ttl
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, redisKeyPrefix+key, string(value), ttl)
+}
+
+// Delete removes keys
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisKeyPrefix + key
+	}
+	return c.client.Del(ctx, prefixed...)
+}
+
+// Verify that RedisCache implements the service.Cache interface
+var _ service.Cache = (*RedisCache)(nil)
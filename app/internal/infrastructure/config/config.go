@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -11,23 +12,65 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server      ServerConfig
-	Database    DatabaseConfig
-	JWT         JWTConfig
-	CORS        CORSConfig
-	Logging     LoggingConfig
-	RateLimit   RateLimitConfig
-	Compression CompressionConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	JWT          JWTConfig
+	CORS         CORSConfig
+	Logging      LoggingConfig
+	RateLimit    RateLimitConfig
+	Compression  CompressionConfig
+	BodyLimit    BodyLimitConfig
+	Email        EmailConfig
+	Mail         MailConfig
+	Redis        RedisConfig
+	Account      AccountConfig
+	ReadingView  ReadingViewConfig
+	OAuth        OAuthConfig
+	Robots       RobotsConfig
+	JobQueue     JobQueueConfig
+	SearchPing   SearchPingConfig
+	Webhooks     WebhooksConfig
+	Realtime     RealtimeConfig
+	TwoFactor    TwoFactorConfig
+	MagicLink    MagicLinkConfig
+	WebAuthn     WebAuthnConfig
+	SCIM         SCIMConfig
+	SSO          SSOConfig
+	Billing      BillingConfig
+	Tips         TipsConfig
+	Feed         FeedConfig
+	Badge        BadgeConfig
+	Pagination   PaginationConfig
+	Announcement AnnouncementConfig
+	Publishing   PublishingConfig
+	Views        ViewsConfig
+	Related      RelatedConfig
+	Trending     TrendingConfig
+	Cache        CacheConfig
+	Comments     CommentsConfig
+	Widget       WidgetConfig
+	Storage      StorageConfig
+	Docs         DocsConfig
+	Idempotency  IdempotencyConfig
+	Import       ImportConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Port string
 	Host string
+	// BaseURL is the public URL used to build links sent to users
+	BaseURL string
+	// ShutdownTimeout bounds the whole shutdown on exit (in seconds)
+	ShutdownTimeout int
+	// ShutdownHookTimeout bounds the teardown of each component (in seconds)
+	ShutdownHookTimeout int
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	// Driver is the database server: "mysql", "postgres" or "sqlite"
+	Driver   string
 	Host     string
 	Port     int
 	User     string
@@ -39,11 +82,26 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime int // in minutes
 	ConnMaxIdleTime int // in minutes
+	// AutoMigrate applies pending migrations on startup
+	AutoMigrate bool
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret string
+	Secret          string
+	AccessTokenTTL  int    // in minutes
+	RefreshTokenTTL int    // in hours
+	BlacklistDriver string // "memory" or "redis"
+	// SigningKeyFile is a PEM private key tokens are signed with: RSA for
+	// RS256 or Ed25519 for EdDSA. Without it tokens are signed with Secret
+	// (HS256).
+	SigningKeyFile string
+	// VerificationKeyFiles are PEM keys whose tokens are still accepted,
+	// e.g. the previous signing key during a rotation
+	VerificationKeyFiles []string
+	// AcceptHS256 keeps accepting tokens signed with Secret after moving to
+	// a signing key file
+	AcceptHS256 bool
 }
 
 // CORSConfig holds CORS configuration
@@ -65,6 +123,13 @@ type RateLimitConfig struct {
 	DefaultBurstSize         int
 	AuthRequestsPerSecond    float64
 	AuthBurstSize            int
+	UserRequestsPerSecond    float64 // Budget for authenticated users, keyed by user instead of IP
+	UserBurstSize            int
+	WriteRequestsPerSecond   float64 // Stricter budget for POST, PUT, PATCH and DELETE requests
+	WriteBurstSize           int
+	BadgeRequestsPerSecond   float64 // Budget per IP for the embeddable stats badges
+	BadgeBurstSize           int
+	Backend                  string // "memory" or "redis"; use redis to share limits between servers
 }
 
 // CompressionConfig holds compression configuration
@@ -72,6 +137,402 @@ type CompressionConfig struct {
 	Enabled   bool
 	Level     int
 	MinLength int
+	// ContentTypes lists the media types compressed, type/* covering every
+	// subtype; empty keeps the defaults of the compression middleware
+	ContentTypes []string
+}
+
+// BodyLimitConfig caps the size of request bodies (in bytes); 0 turns a
+// limit off. Uploads and webhooks are checked against their own limits.
+type BodyLimitConfig struct {
+	// Default applies to routes without a group limit
+	Default int64
+	// Groups overrides Default for the routes under a path prefix, the
+	// longest matching prefix winning
+	Groups map[string]int64
+}
+
+// EmailConfig holds email address handling configuration
+type EmailConfig struct {
+	// CanonicalProviders lists domains whose addresses ignore dots and plus-tags
+	CanonicalProviders []string
+	// ChangeTokenTTL is how long an email change confirmation link stays valid (in hours)
+	ChangeTokenTTL int
+}
+
+// MailConfig holds outgoing mail configuration
+type MailConfig struct {
+	Driver       string // "log" or "smtp"
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// AccountConfig holds account lifecycle configuration
+type AccountConfig struct {
+	// DeletionGracePeriod is how long a deleted account can be restored (in hours)
+	DeletionGracePeriod int
+	// PurgeInterval is how often accounts past their grace period are purged (in minutes)
+	PurgeInterval int
+	// LockoutThreshold is the number of failed logins after which an account
+	// or client IP is locked; zero disables the lockout
+	LockoutThreshold int
+	// LockoutWindow is how long failures are counted and a lockout lasts (in minutes)
+	LockoutWindow int
+	// LoginAttemptsDriver selects where failures are counted: "memory" or "redis"
+	LoginAttemptsDriver string
+	// ReportLinkTTL is how long the "this wasn't me" link of a security
+	// notification can lock the account (in hours)
+	ReportLinkTTL int
+	// PasswordResetTTL is how long a password reset link stays valid (in hours)
+	PasswordResetTTL int
+}
+
+// ReadingViewConfig holds configuration for the server-rendered HTML pages
+type ReadingViewConfig struct {
+	// Enabled serves posts as HTML at /p/:slug
+	Enabled  bool
+	SiteName string
+	// FeedURL is advertised through an RSS discovery link when set
+	FeedURL string
+	// ThemesDir holds theme directories that override the built-in templates and assets
+	ThemesDir string
+	// Theme is the theme used unless the request host has its own
+	Theme string
+	// HostThemes maps request hosts to themes, e.g. for organizations on their own domain
+	HostThemes map[string]string
+}
+
+// RobotsConfig holds the crawl rules served in robots.txt
+type RobotsConfig struct {
+	// Disallow lists the path prefixes crawlers may not fetch
+	Disallow []string
+	// HostDisallow replaces Disallow for the given hosts, e.g. for organizations on their own domain
+	HostDisallow map[string][]string
+}
+
+// JobQueueConfig holds configuration for the background job queue
+type JobQueueConfig struct {
+	// PollInterval is how often due jobs are claimed (in seconds)
+	PollInterval int
+	BatchSize    int
+	// MaxAttempts is the number of attempts before a job is marked failed
+	MaxAttempts int
+}
+
+// SearchPingConfig holds configuration for notifying search engines about
+// published and updated posts
+type SearchPingConfig struct {
+	Enabled bool
+	// IndexNowKey is served at /{key}.txt; IndexNow is skipped without it
+	IndexNowKey      string
+	IndexNowEndpoint string
+	// SitemapURL is the public sitemap; sitemap pings are skipped without it
+	SitemapURL string
+	// SitemapPingURLs are ping endpoints the escaped sitemap URL is appended to
+	SitemapPingURLs []string
+}
+
+// WebhooksConfig holds configuration for the delivery of content events to
+// the webhooks registered by admins; failed deliveries are retried by the
+// job queue
+type WebhooksConfig struct {
+	// Timeout bounds each request to a webhook (in seconds)
+	Timeout int
+}
+
+// RealtimeConfig holds configuration for the WebSocket connections pushing
+// notifications to signed-in users
+type RealtimeConfig struct {
+	// SendBuffer is how many messages are queued for a connection before
+	// it is closed as too slow
+	SendBuffer int
+	// MaxConnections bounds the open connections of each user
+	MaxConnections int
+	// PingInterval is how often idle connections are pinged (in seconds)
+	PingInterval int
+	// WriteTimeout bounds each write to a connection (in seconds)
+	WriteTimeout int
+}
+
+// OAuthConfig holds the client registrations for social login. Providers
+// without a client ID are disabled.
+type OAuthConfig struct {
+	GoogleClientID     string
+	GoogleClientSecret string
+	GitHubClientID     string
+	GitHubClientSecret string
+	// StateTTL is how long a sign-in may take at the provider (in minutes)
+	StateTTL int
+}
+
+// TwoFactorConfig holds TOTP two-factor authentication configuration
+type TwoFactorConfig struct {
+	// EncryptionKey encrypts stored TOTP secrets; it defaults to the JWT
+	// secret. Changing it invalidates existing enrollments.
+	EncryptionKey string
+	// ChallengeTTL is how long the second login step may take (in minutes)
+	ChallengeTTL int
+}
+
+// MagicLinkConfig holds configuration for passwordless login links
+type MagicLinkConfig struct {
+	// Enabled turns on the magic link endpoints
+	Enabled bool
+	// TTL is how long an emailed link stays valid (in minutes)
+	TTL int
+	// MaxRequests is the number of links an account may be sent per hour;
+	// zero removes the limit
+	MaxRequests int
+}
+
+// WebAuthnConfig holds configuration for passkey registration and login
+type WebAuthnConfig struct {
+	// Enabled turns on the passkey endpoints
+	Enabled bool
+	// RPID is the domain passkeys are bound to; it defaults to the host of
+	// APP_BASE_URL. Changing it invalidates existing passkeys.
+	RPID string
+	// RPName is the site name shown by authenticators
+	RPName string
+	// Origins are the origins ceremonies may run on; they default to
+	// APP_BASE_URL
+	Origins []string
+	// ChallengeTTL is how long a ceremony may take (in minutes)
+	ChallengeTTL int
+	// ChallengeDriver selects where challenges are kept: "memory" or "redis"
+	ChallengeDriver string
+}
+
+// SCIMConfig holds configuration for SCIM user provisioning
+type SCIMConfig struct {
+	// Enabled turns on the SCIM endpoints
+	Enabled bool
+	// Tenants maps the organizations allowed to provision accounts to the
+	// bearer token of their identity provider
+	Tenants map[string]string
+}
+
+// SSOConfig holds configuration for single sign-on. Connections are set up
+// per organization through the admin API.
+type SSOConfig struct {
+	// Enabled turns on the SSO endpoints and enforcement
+	Enabled bool
+	// StateTTL is how long a sign-in may take at the identity provider (in
+	// minutes)
+	StateTTL int
+}
+
+// BillingConfig holds configuration for subscription plans, paid through
+// Stripe
+type BillingConfig struct {
+	// Enabled turns on the Stripe webhook, the subscription endpoint and the
+	// plan limits; without it every user gets unlimited use
+	Enabled bool
+	// StripeWebhookSecret is the signing secret of the webhook endpoint
+	StripeWebhookSecret string
+	// PricePlans maps Stripe price IDs to the plans they buy; prices missing
+	// from it are matched by their lookup key
+	PricePlans map[string]string
+}
+
+// TipsConfig holds configuration for tips, one-time payments readers make
+// to authors through Stripe Checkout and Stripe Connect
+type TipsConfig struct {
+	// Enabled turns on the tip, payout account and payment webhook endpoints
+	Enabled bool
+	// Currency is the ISO currency code tips are paid in
+	Currency string
+	// MinAmount and MaxAmount bound a tip, in the smallest currency unit
+	MinAmount int
+	MaxAmount int
+	// StripeSecretKey is the secret API key of the platform account
+	StripeSecretKey string
+	// StripeWebhookSecret is the signing secret of the payment webhook
+	// endpoint, which is separate from the subscription one
+	StripeWebhookSecret string
+}
+
+// FeedConfig holds configuration for the RSS comment feeds
+type FeedConfig struct {
+	// ItemLimit caps the number of items per feed (at most the maximum
+	// comment page size)
+	ItemLimit int
+	// CacheTTL is how long a rendered feed is reused (in seconds)
+	CacheTTL int
+}
+
+// BadgeConfig holds configuration for the embeddable stats badges
+type BadgeConfig struct {
+	// CacheTTL is how long a rendered badge is reused and may be cached by
+	// clients and proxies (in seconds)
+	CacheTTL int
+}
+
+// PaginationConfig holds the page sizes of the list endpoints per resource
+type PaginationConfig struct {
+	Posts     PageSizeConfig
+	Comments  PageSizeConfig
+	Users     PageSizeConfig
+	AuditLogs PageSizeConfig
+}
+
+// PageSizeConfig holds the default and maximum page size of a resource
+type PageSizeConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// AnnouncementConfig holds configuration for administrative announcement emails
+type AnnouncementConfig struct {
+	// BatchSize is the number of recipients handled per background job
+	BatchSize int
+	// Throttle is the pause between two messages (in milliseconds)
+	Throttle int
+}
+
+// PublishingConfig holds configuration for scheduled post publishing
+type PublishingConfig struct {
+	// ScheduleInterval is how often scheduled posts are checked and published
+	// once their publish time has come (in seconds)
+	ScheduleInterval int
+	// ConflictWindow is how close two posts may be published before the
+	// editorial calendar warns about them (in minutes); zero turns warnings off
+	ConflictWindow int
+}
+
+// ViewsConfig holds configuration for post view counting
+type ViewsConfig struct {
+	// FlushInterval is how often buffered views are written to the
+	// database (in seconds)
+	FlushInterval int
+}
+
+// RelatedConfig holds configuration for related post suggestions
+type RelatedConfig struct {
+	// Strategy selects how related posts are found: "tags" (posts sharing
+	// the most tags, topped up with posts of similar titles) or "titles"
+	// (TF-IDF similarity of titles only)
+	Strategy string
+	// TitlePool is how many of the latest published posts titles are
+	// compared with
+	TitlePool int
+}
+
+// TrendingConfig holds configuration for trending post rankings
+type TrendingConfig struct {
+	// Window is how far back views, bookmarks and comments count towards
+	// trending scores (in hours)
+	Window int
+	// HalfLife is the age at which activity counts half (in hours)
+	HalfLife int
+	// RefreshInterval is how often trending scores are recalculated (in
+	// minutes)
+	RefreshInterval int
+}
+
+// CacheConfig holds configuration for the cache of hot reads
+type CacheConfig struct {
+	// Driver selects where values are cached: "memory" (per server) or
+	// "redis" (shared by all servers)
+	Driver string
+	// TTL is how long values are cached (in seconds); zero disables caching
+	TTL int
+	// MaxEntries bounds the number of values of the memory cache
+	MaxEntries int
+}
+
+// CommentsConfig holds configuration for comments
+type CommentsConfig struct {
+	// RequireModeration holds new comments for approval by an admin before
+	// they are shown
+	RequireModeration bool
+	// TrustLevels limits the links, length and pace of comments by the
+	// trust level commenters earned
+	TrustLevels bool
+	// TrustInterval is how often the trust levels of commenters are
+	// evaluated (in minutes)
+	TrustInterval int
+	// ReportThreshold is the number of readers reporting a comment that
+	// hides it until an admin reviews it; zero never hides reported comments
+	ReportThreshold int
+}
+
+// DocsConfig holds configuration for the API documentation
+type DocsConfig struct {
+	// SwaggerEnabled serves Swagger UI and the OpenAPI document below
+	// /swagger; the error catalog is served either way
+	SwaggerEnabled bool
+}
+
+// IdempotencyConfig holds configuration for the Idempotency-Key header of
+// write requests
+type IdempotencyConfig struct {
+	// TTL is how long the response to a request is replayed to retries
+	// sent with its key (in hours)
+	TTL int
+	// CleanupInterval is how often expired keys are deleted (in minutes)
+	CleanupInterval int
+}
+
+// ImportConfig holds configuration for content imports from other blogs
+type ImportConfig struct {
+	// MaxSize caps the size of uploaded export files (in bytes)
+	MaxSize int
+}
+
+// WidgetConfig holds configuration for the comments widget embedded on
+// external sites
+type WidgetConfig struct {
+	// Enabled turns on the widget endpoints
+	Enabled bool
+	// Sites maps the public token of each embedding site to the origins
+	// allowed to call the widget endpoints and frame its thread
+	Sites map[string][]string
+}
+
+// StorageConfig holds configuration for the storage of uploaded files
+type StorageConfig struct {
+	// Driver selects where uploads are kept: "local" (default) or "s3"
+	Driver string
+	// LocalDir is the directory the local driver writes uploads to
+	LocalDir string
+	// PublicURL is the base URL uploads are served from; the local driver
+	// defaults to APP_BASE_URL/uploads and the s3 driver to the bucket URL
+	PublicURL string
+	// S3 settings of the s3 driver, for AWS S3 or a compatible service
+	// such as MinIO or Cloudflare R2
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PathStyle addresses the bucket in the URL path rather than the
+	// host name
+	S3PathStyle bool
+	// MaxAvatarSize caps the size of avatar uploads (in bytes)
+	MaxAvatarSize int
+	// MaxMediaSize caps the size of post image uploads (in bytes)
+	MaxMediaSize int
+	// MediaQuota caps the bytes of post images each user may store when
+	// billing is disabled; 0 does not limit them. Plans set the quota
+	// otherwise.
+	MediaQuota int64
+	// MediaOrphanAge is how long post images are kept while no post
+	// references them (in hours)
+	MediaOrphanAge int
+	// MediaCleanupInterval is how often unreferenced post images are
+	// collected (in minutes)
+	MediaCleanupInterval int
+}
+
+// RedisConfig holds Redis connection configuration
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
 }
 
 // Load loads configuration from environment variables
@@ -81,6 +542,8 @@ func Load() *Config {
 		log.Printf("Warning: .env file not found or could not be loaded: %v", err)
 	}
 
+	jwtSecret := getEnv("JWT_SECRET", "your-secret-key")
+
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "3306"))
 	
 	// Parse CORS allowed origins
@@ -93,12 +556,32 @@ func Load() *Config {
 		}
 	}
 
+	// Parse provider domains that get dot/plus-tag canonicalization
+	canonicalProviders := parseList(getEnv("EMAIL_CANONICAL_PROVIDERS", "gmail.com,googlemail.com"))
+
+	defaultPageSize := PageSizeConfig{
+		DefaultLimit: parseInt(getEnv("PAGINATION_DEFAULT_LIMIT", "10"), 10),
+		MaxLimit:     parseInt(getEnv("PAGINATION_MAX_LIMIT", "100"), 100),
+	}
+
+	baseURL := strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/")
+	var baseHost string
+	if u, err := url.Parse(baseURL); err == nil {
+		baseHost = u.Hostname()
+	}
+
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Host: getEnv("HOST", "localhost"),
+			Port:    getEnv("PORT", "8080"),
+			Host:    getEnv("HOST", "localhost"),
+			BaseURL: baseURL,
+			// Keep within the grace period of the process manager, e.g.
+			// Kubernetes' terminationGracePeriodSeconds
+			ShutdownTimeout:     parseInt(getEnv("SHUTDOWN_TIMEOUT", "25"), 25),      // seconds
+			ShutdownHookTimeout: parseInt(getEnv("SHUTDOWN_HOOK_TIMEOUT", "10"), 10), // seconds
 		},
 		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", "mysql"),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     dbPort,
 			User:     getEnv("DB_USER", "root"),
@@ -110,9 +593,16 @@ func Load() *Config {
 			MaxIdleConns:    parseInt(getEnv("DB_MAX_IDLE_CONNS", "5"), 5),
 			ConnMaxLifetime: parseInt(getEnv("DB_CONN_MAX_LIFETIME", "5"), 5), // minutes
 			ConnMaxIdleTime: parseInt(getEnv("DB_CONN_MAX_IDLE_TIME", "1"), 1), // minutes
+			AutoMigrate:     parseBool(getEnv("DB_AUTO_MIGRATE", "false"), false),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-secret-key"),
+			Secret:               jwtSecret,
+			AccessTokenTTL:       parseInt(getEnv("JWT_ACCESS_TTL", "15"), 15),    // minutes
+			RefreshTokenTTL:      parseInt(getEnv("JWT_REFRESH_TTL", "720"), 720), // hours
+			BlacklistDriver:      getEnv("TOKEN_BLACKLIST_DRIVER", "memory"),
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: parseList(getEnv("JWT_VERIFICATION_KEY_FILES", "")),
+			AcceptHS256:          parseBool(getEnv("JWT_ACCEPT_HS256", "false"), false),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: allowedOrigins,
@@ -128,13 +618,250 @@ func Load() *Config {
 			DefaultBurstSize:         parseInt(getEnv("RATE_LIMIT_DEFAULT_BURST", "20"), 20),
 			AuthRequestsPerSecond:    parseFloat(getEnv("RATE_LIMIT_AUTH_RPS", "2"), 2),
 			AuthBurstSize:            parseInt(getEnv("RATE_LIMIT_AUTH_BURST", "5"), 5),
+			UserRequestsPerSecond:    parseFloat(getEnv("RATE_LIMIT_USER_RPS", "20"), 20),
+			UserBurstSize:            parseInt(getEnv("RATE_LIMIT_USER_BURST", "40"), 40),
+			WriteRequestsPerSecond:   parseFloat(getEnv("RATE_LIMIT_WRITE_RPS", "1"), 1),
+			WriteBurstSize:           parseInt(getEnv("RATE_LIMIT_WRITE_BURST", "10"), 10),
+			BadgeRequestsPerSecond:   parseFloat(getEnv("RATE_LIMIT_BADGE_RPS", "2"), 2),
+			BadgeBurstSize:           parseInt(getEnv("RATE_LIMIT_BADGE_BURST", "20"), 20),
+			Backend:                  getEnv("RATE_LIMIT_BACKEND", "memory"),
 		},
 		Compression: CompressionConfig{
-			Enabled:   parseBool(getEnv("COMPRESSION_ENABLED", "true"), true),
-			Level:     parseInt(getEnv("COMPRESSION_LEVEL", "6"), 6),
-			MinLength: parseInt(getEnv("COMPRESSION_MIN_LENGTH", "1024"), 1024),
+			Enabled:      parseBool(getEnv("COMPRESSION_ENABLED", "true"), true),
+			Level:        parseInt(getEnv("COMPRESSION_LEVEL", "6"), 6),
+			MinLength:    parseInt(getEnv("COMPRESSION_MIN_LENGTH", "1024"), 1024),
+			ContentTypes: parseList(getEnv("COMPRESSION_CONTENT_TYPES", "")),
+		},
+		BodyLimit: BodyLimitConfig{
+			Default: int64(parseInt(getEnv("BODY_LIMIT_DEFAULT", "1048576"), 1<<20)), // 1MB
+			Groups:  parseSizes(getEnv("BODY_LIMIT_GROUPS", "/api/v1/auth=131072")),
+		},
+		Email: EmailConfig{
+			CanonicalProviders: canonicalProviders,
+			ChangeTokenTTL:     parseInt(getEnv("EMAIL_CHANGE_TOKEN_TTL", "24"), 24), // hours
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@blog-platform.local"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     parseInt(getEnv("SMTP_PORT", "587"), 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Redis: RedisConfig{
+			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       parseInt(getEnv("REDIS_DB", "0"), 0),
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod: parseInt(getEnv("ACCOUNT_DELETION_GRACE_PERIOD", "720"), 720), // hours
+			PurgeInterval:       parseInt(getEnv("ACCOUNT_PURGE_INTERVAL", "60"), 60),          // minutes
+			LockoutThreshold:    parseInt(getEnv("ACCOUNT_LOCKOUT_THRESHOLD", "5"), 5),
+			LockoutWindow:       parseInt(getEnv("ACCOUNT_LOCKOUT_WINDOW", "15"), 15), // minutes
+			LoginAttemptsDriver: getEnv("LOGIN_ATTEMPTS_DRIVER", "memory"),
+			ReportLinkTTL:       parseInt(getEnv("ACCOUNT_REPORT_LINK_TTL", "168"), 168), // hours
+			PasswordResetTTL:    parseInt(getEnv("ACCOUNT_PASSWORD_RESET_TTL", "1"), 1),  // hours
+		},
+		ReadingView: ReadingViewConfig{
+			Enabled:    parseBool(getEnv("READING_VIEW_ENABLED", "false"), false),
+			SiteName:   getEnv("SITE_NAME", "Blog Platform"),
+			FeedURL:    getEnv("READING_VIEW_FEED_URL", ""),
+			ThemesDir:  getEnv("READING_VIEW_THEMES_DIR", ""),
+			Theme:      getEnv("READING_VIEW_THEME", "default"),
+			HostThemes: parseMap(getEnv("READING_VIEW_HOST_THEMES", "")),
+		},
+		OAuth: OAuthConfig{
+			GoogleClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+			GoogleClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+			GitHubClientID:     getEnv("OAUTH_GITHUB_CLIENT_ID", ""),
+			GitHubClientSecret: getEnv("OAUTH_GITHUB_CLIENT_SECRET", ""),
+			StateTTL:           parseInt(getEnv("OAUTH_STATE_TTL", "10"), 10), // minutes
+		},
+		Robots: RobotsConfig{
+			Disallow:     parseList(getEnv("ROBOTS_DISALLOW", "/api/")),
+			HostDisallow: parseMultiMap(getEnv("ROBOTS_HOST_DISALLOW", "")),
+		},
+		JobQueue: JobQueueConfig{
+			PollInterval: parseInt(getEnv("JOB_QUEUE_POLL_INTERVAL", "5"), 5), // seconds
+			BatchSize:    parseInt(getEnv("JOB_QUEUE_BATCH_SIZE", "10"), 10),
+			MaxAttempts:  parseInt(getEnv("JOB_QUEUE_MAX_ATTEMPTS", "8"), 8),
+		},
+		SearchPing: SearchPingConfig{
+			Enabled:          parseBool(getEnv("SEARCH_PING_ENABLED", "false"), false),
+			IndexNowKey:      getEnv("INDEXNOW_KEY", ""),
+			IndexNowEndpoint: getEnv("INDEXNOW_ENDPOINT", "https://api.indexnow.org/indexnow"),
+			SitemapURL:       getEnv("SEARCH_PING_SITEMAP_URL", ""),
+			SitemapPingURLs:  parseList(getEnv("SEARCH_PING_SITEMAP_ENDPOINTS", "")),
+		},
+		Webhooks: WebhooksConfig{
+			Timeout: parseInt(getEnv("WEBHOOK_TIMEOUT", "10"), 10), // seconds
+		},
+		Realtime: RealtimeConfig{
+			SendBuffer:     parseInt(getEnv("WS_SEND_BUFFER", "16"), 16),
+			MaxConnections: parseInt(getEnv("WS_MAX_CONNECTIONS_PER_USER", "5"), 5),
+			PingInterval:   parseInt(getEnv("WS_PING_INTERVAL", "30"), 30), // seconds
+			WriteTimeout:   parseInt(getEnv("WS_WRITE_TIMEOUT", "10"), 10), // seconds
+		},
+		TwoFactor: TwoFactorConfig{
+			EncryptionKey: getEnv("TWO_FACTOR_ENCRYPTION_KEY", jwtSecret),
+			ChallengeTTL:  parseInt(getEnv("TWO_FACTOR_CHALLENGE_TTL", "5"), 5), // minutes
+		},
+		MagicLink: MagicLinkConfig{
+			Enabled:     parseBool(getEnv("MAGIC_LINK_ENABLED", "false"), false),
+			TTL:         parseInt(getEnv("MAGIC_LINK_TTL", "15"), 15), // minutes
+			MaxRequests: parseInt(getEnv("MAGIC_LINK_MAX_REQUESTS", "5"), 5),
+		},
+		WebAuthn: WebAuthnConfig{
+			Enabled:         parseBool(getEnv("WEBAUTHN_ENABLED", "false"), false),
+			RPID:            getEnv("WEBAUTHN_RP_ID", baseHost),
+			RPName:          getEnv("WEBAUTHN_RP_NAME", getEnv("SITE_NAME", "Blog Platform")),
+			Origins:         parseList(getEnv("WEBAUTHN_ORIGINS", baseURL)),
+			ChallengeTTL:    parseInt(getEnv("WEBAUTHN_CHALLENGE_TTL", "5"), 5), // minutes
+			ChallengeDriver: getEnv("WEBAUTHN_CHALLENGE_DRIVER", "memory"),
+		},
+		SCIM: SCIMConfig{
+			Enabled: parseBool(getEnv("SCIM_ENABLED", "false"), false),
+			Tenants: parseMap(getEnv("SCIM_TENANTS", "")),
+		},
+		SSO: SSOConfig{
+			Enabled:  parseBool(getEnv("SSO_ENABLED", "false"), false),
+			StateTTL: parseInt(getEnv("SSO_STATE_TTL", "10"), 10), // minutes
+		},
+		Billing: BillingConfig{
+			Enabled:             parseBool(getEnv("BILLING_ENABLED", "false"), false),
+			StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			PricePlans:          parseMap(getEnv("STRIPE_PRICE_PLANS", "")),
+		},
+		Tips: TipsConfig{
+			Enabled:             parseBool(getEnv("TIPS_ENABLED", "false"), false),
+			Currency:            strings.ToLower(getEnv("TIPS_CURRENCY", "usd")),
+			MinAmount:           parseInt(getEnv("TIPS_MIN_AMOUNT", "100"), 100),     // smallest currency unit
+			MaxAmount:           parseInt(getEnv("TIPS_MAX_AMOUNT", "50000"), 50000), // smallest currency unit
+			StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
+			StripeWebhookSecret: getEnv("STRIPE_TIPS_WEBHOOK_SECRET", ""),
+		},
+		Feed: FeedConfig{
+			ItemLimit: parseInt(getEnv("FEED_ITEM_LIMIT", "50"), 50),
+			CacheTTL:  parseInt(getEnv("FEED_CACHE_TTL", "300"), 300), // seconds
+		},
+		Badge: BadgeConfig{
+			CacheTTL: parseInt(getEnv("BADGE_CACHE_TTL", "600"), 600), // seconds
+		},
+		Announcement: AnnouncementConfig{
+			BatchSize: parseInt(getEnv("ANNOUNCEMENT_BATCH_SIZE", "50"), 50),
+			Throttle:  parseInt(getEnv("ANNOUNCEMENT_THROTTLE", "200"), 200), // milliseconds
+		},
+		Pagination: PaginationConfig{
+			Posts:     parsePageSize("POSTS", defaultPageSize),
+			Comments:  parsePageSize("COMMENTS", defaultPageSize),
+			Users:     parsePageSize("USERS", defaultPageSize),
+			AuditLogs: parsePageSize("AUDIT_LOGS", defaultPageSize),
+		},
+		Publishing: PublishingConfig{
+			ScheduleInterval: parseInt(getEnv("POST_SCHEDULE_INTERVAL", "60"), 60), // seconds
+			ConflictWindow:   parseInt(getEnv("POST_SCHEDULE_CONFLICT_WINDOW", "60"), 60), // minutes
+		},
+		Views: ViewsConfig{
+			FlushInterval: parseInt(getEnv("POST_VIEW_FLUSH_INTERVAL", "30"), 30), // seconds
+		},
+		Related: RelatedConfig{
+			Strategy:  getEnv("RELATED_POSTS_STRATEGY", "tags"),
+			TitlePool: parseInt(getEnv("RELATED_POSTS_TITLE_POOL", "500"), 500),
+		},
+		Trending: TrendingConfig{
+			Window:          parseInt(getEnv("TRENDING_WINDOW", "72"), 72),           // hours
+			HalfLife:        parseInt(getEnv("TRENDING_HALF_LIFE", "24"), 24),        // hours
+			RefreshInterval: parseInt(getEnv("TRENDING_REFRESH_INTERVAL", "10"), 10), // minutes
+		},
+		Cache: CacheConfig{
+			Driver:     getEnv("CACHE_DRIVER", "memory"),
+			TTL:        parseInt(getEnv("CACHE_TTL", "30"), 30), // seconds
+			MaxEntries: parseInt(getEnv("CACHE_MAX_ENTRIES", "10000"), 10000),
 		},
+		Comments: CommentsConfig{
+			RequireModeration: parseBool(getEnv("COMMENTS_REQUIRE_MODERATION", "false"), false),
+			TrustLevels:       parseBool(getEnv("COMMENTS_TRUST_LEVELS", "false"), false),
+			TrustInterval:     parseInt(getEnv("COMMENTS_TRUST_INTERVAL", "60"), 60), // minutes
+			ReportThreshold:   parseInt(getEnv("COMMENTS_REPORT_THRESHOLD", "3"), 3),
+		},
+		Widget: WidgetConfig{
+			Enabled: parseBool(getEnv("WIDGET_ENABLED", "false"), false),
+			Sites:   parseMultiMap(getEnv("WIDGET_SITES", "")),
+		},
+		Storage: StorageConfig{
+			Driver:               getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:             getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PublicURL:            strings.TrimRight(getEnv("STORAGE_PUBLIC_URL", ""), "/"),
+			S3Endpoint:           getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			S3Region:             getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:             getEnv("S3_BUCKET", ""),
+			S3AccessKey:          getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretKey:          getEnv("S3_SECRET_ACCESS_KEY", ""),
+			S3PathStyle:          parseBool(getEnv("S3_PATH_STYLE", "false"), false),
+			MaxAvatarSize:        parseInt(getEnv("AVATAR_MAX_SIZE", "5242880"), 5242880),        // bytes
+			MaxMediaSize:         parseInt(getEnv("MEDIA_MAX_SIZE", "10485760"), 10485760),       // bytes
+			MediaQuota:           int64(parseInt(getEnv("MEDIA_QUOTA", "104857600"), 104857600)), // bytes
+			MediaOrphanAge:       parseInt(getEnv("MEDIA_ORPHAN_AGE", "24"), 24),                 // hours
+			MediaCleanupInterval: parseInt(getEnv("MEDIA_CLEANUP_INTERVAL", "60"), 60),           // minutes
+		},
+		Docs: DocsConfig{
+			SwaggerEnabled: parseBool(getEnv("SWAGGER_ENABLED", "true"), true),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:             parseInt(getEnv("IDEMPOTENCY_TTL", "24"), 24),                // hours
+			CleanupInterval: parseInt(getEnv("IDEMPOTENCY_CLEANUP_INTERVAL", "60"), 60), // minutes
+		},
+		Import: ImportConfig{
+			MaxSize: parseInt(getEnv("IMPORT_MAX_SIZE", "52428800"), 52428800), // bytes
+		},
+	}
+}
+
+// redactedValue replaces the secrets of a redacted configuration
+const redactedValue = "[REDACTED]"
+
+// UsesRedis reports whether any store is configured to keep its state in
+// Redis, making it a dependency the instance cannot serve without
+func (c *Config) UsesRedis() bool {
+	for _, driver := range []string{c.JWT.BlacklistDriver, c.RateLimit.Backend, c.Account.LoginAttemptsDriver, c.WebAuthn.ChallengeDriver, c.Cache.Driver} {
+		if strings.EqualFold(driver, "redis") {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the configuration with passwords, keys and
+// secrets replaced, safe to log or show to operators. Unset secrets stay
+// empty so they can be told apart from configured ones.
+func (c *Config) Redacted() Config {
+	r := *c
+	r.Database.Password = redact(r.Database.Password)
+	r.Database.DSN = redact(r.Database.DSN) // carries the password
+	r.JWT.Secret = redact(r.JWT.Secret)
+	r.Mail.SMTPPassword = redact(r.Mail.SMTPPassword)
+	r.Redis.Password = redact(r.Redis.Password)
+	r.OAuth.GoogleClientSecret = redact(r.OAuth.GoogleClientSecret)
+	r.OAuth.GitHubClientSecret = redact(r.OAuth.GitHubClientSecret)
+	r.TwoFactor.EncryptionKey = redact(r.TwoFactor.EncryptionKey)
+	r.SearchPing.IndexNowKey = redact(r.SearchPing.IndexNowKey)
+	r.Billing.StripeWebhookSecret = redact(r.Billing.StripeWebhookSecret)
+	r.Tips.StripeSecretKey = redact(r.Tips.StripeSecretKey)
+	r.Tips.StripeWebhookSecret = redact(r.Tips.StripeWebhookSecret)
+	r.Storage.S3SecretKey = redact(r.Storage.S3SecretKey)
+	r.SCIM.Tenants = make(map[string]string, len(c.SCIM.Tenants))
+	for tenant, token := range c.SCIM.Tenants {
+		r.SCIM.Tenants[tenant] = redact(token)
 	}
+	return r
+}
+
+// redact hides a secret value, keeping empty values empty
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
 }
 
 // getEnv gets an environment variable with a fallback value
@@ -145,6 +872,15 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// parsePageSize reads the PAGINATION_<RESOURCE>_DEFAULT_LIMIT and
+// PAGINATION_<RESOURCE>_MAX_LIMIT overrides, falling back to the shared sizes
+func parsePageSize(resource string, fallback PageSizeConfig) PageSizeConfig {
+	return PageSizeConfig{
+		DefaultLimit: parseInt(getEnv("PAGINATION_"+resource+"_DEFAULT_LIMIT", ""), fallback.DefaultLimit),
+		MaxLimit:     parseInt(getEnv("PAGINATION_"+resource+"_MAX_LIMIT", ""), fallback.MaxLimit),
+	}
+}
+
 // parseFloat parses a string to float64 with fallback
 func parseFloat(str string, fallback float64) float64 {
 	if value, err := strconv.ParseFloat(str, 64); err == nil {
@@ -168,3 +904,50 @@ func parseBool(str string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// parseList parses a comma-separated string into a trimmed list, skipping empty entries
+func parseList(str string) []string {
+	items := []string{}
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseMap parses a comma-separated list of key=value pairs, skipping malformed entries
+func parseMap(str string) map[string]string {
+	items := make(map[string]string)
+	for _, item := range parseList(str) {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			continue
+		}
+		items[key] = value
+	}
+	return items
+}
+
+// parseSizes parses key=bytes pairs like parseMap, skipping sizes that are
+// not numbers
+func parseSizes(str string) map[string]int64 {
+	sizes := make(map[string]int64)
+	for key, value := range parseMap(str) {
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			sizes[key] = size
+		}
+	}
+	return sizes
+}
+
+// parseMultiMap parses key=value pairs like parseMap, splitting each value
+// into space-separated items
+func parseMultiMap(str string) map[string][]string {
+	items := make(map[string][]string)
+	for key, value := range parseMap(str) {
+		items[key] = strings.Fields(value)
+	}
+	return items
+}
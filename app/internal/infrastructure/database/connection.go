@@ -1,14 +1,24 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/database/migrations"
 )
 
+func init() {
+	// The SQLite driver takes ? placeholders like MySQL
+	sqlx.BindDriver("sqlite", sqlx.QUESTION)
+}
+
 // Database wraps sqlx.DB with additional functionality
 type Database struct {
 	*sqlx.DB
@@ -16,7 +26,12 @@ type Database struct {
 
 // NewDatabase creates a new database connection
 func NewDatabase(cfg *config.Config) (*Database, error) {
-	db, err := sqlx.Connect("mysql", cfg.Database.DSN)
+	driver, err := DriverName(cfg.Database.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Connect(driver, cfg.Database.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -35,14 +50,32 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	return &Database{db}, nil
 }
 
+// DriverName returns the database/sql driver of a DB_DRIVER setting.
+// PostgreSQL is served by pgx, which sqlx binds with $1 placeholders, and
+// SQLite by the pure Go modernc.org/sqlite, so builds need no C compiler.
+func DriverName(driver string) (string, error) {
+	switch strings.ToLower(driver) {
+	case "", "mysql":
+		return "mysql", nil
+	case "postgres", "postgresql":
+		return "pgx", nil
+	case "sqlite", "sqlite3":
+		return "sqlite", nil
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.DB.Close()
 }
 
-// Migrate runs database migrations
-func (d *Database) Migrate(migrationsPath string) error {
-	// This will be implemented when we integrate with golang-migrate
-	// For now, we'll return nil as migrations will be run separately
-	return nil
+// Migrate applies the embedded migrations not applied yet and returns them
+func (d *Database) Migrate(ctx context.Context) ([]migrations.Migration, error) {
+	migrator, err := migrations.New(d.DB.DB, d.DriverName())
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Up(ctx)
 }
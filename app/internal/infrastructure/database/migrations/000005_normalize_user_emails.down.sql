@@ -0,0 +1,5 @@
+DROP INDEX idx_email_normalized ON users;
+
+ALTER TABLE users
+    DROP COLUMN email_conflict,
+    DROP COLUMN email_normalized;
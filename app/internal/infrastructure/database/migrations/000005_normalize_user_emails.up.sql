@@ -0,0 +1,28 @@
+-- Canonical email used for case-insensitive uniqueness checks and lookups
+ALTER TABLE users
+    ADD COLUMN email_normalized VARCHAR(255) NULL AFTER email,
+    ADD COLUMN email_conflict BOOLEAN NOT NULL DEFAULT FALSE AFTER email_normalized;
+
+-- Backfill: lowercase and trim every address
+UPDATE users SET email_normalized = LOWER(TRIM(email));
+
+-- Gmail ignores dots and plus-tags in the local part
+UPDATE users
+SET email_normalized = CONCAT(
+    REPLACE(SUBSTRING_INDEX(SUBSTRING_INDEX(email_normalized, '@', 1), '+', 1), '.', ''),
+    '@gmail.com'
+)
+WHERE SUBSTRING_INDEX(email_normalized, '@', -1) IN ('gmail.com', 'googlemail.com');
+
+-- Flag duplicates: the oldest account keeps the canonical address, later
+-- accounts sharing it are flagged for manual resolution
+UPDATE users u
+JOIN (
+    SELECT email_normalized, MIN(id) AS keep_id
+    FROM users
+    GROUP BY email_normalized
+    HAVING COUNT(*) > 1
+) dup ON u.email_normalized = dup.email_normalized AND u.id <> dup.keep_id
+SET u.email_conflict = TRUE, u.email_normalized = NULL;
+
+CREATE UNIQUE INDEX idx_email_normalized ON users (email_normalized);
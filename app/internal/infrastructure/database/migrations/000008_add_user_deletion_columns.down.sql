@@ -0,0 +1,5 @@
+DROP INDEX idx_deletion_scheduled_at ON users;
+
+ALTER TABLE users
+    DROP COLUMN deleted_at,
+    DROP COLUMN deletion_scheduled_at;
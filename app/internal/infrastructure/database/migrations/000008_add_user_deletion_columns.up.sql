@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN deletion_scheduled_at TIMESTAMP NULL DEFAULT NULL,
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX idx_deletion_scheduled_at (deletion_scheduled_at);
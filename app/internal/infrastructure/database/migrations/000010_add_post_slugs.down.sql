@@ -0,0 +1,4 @@
+DROP INDEX idx_posts_slug ON posts;
+
+ALTER TABLE posts
+    DROP COLUMN slug;
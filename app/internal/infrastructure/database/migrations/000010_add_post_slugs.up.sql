@@ -0,0 +1,11 @@
+-- URL slug used by the HTML reading view (/p/:slug)
+ALTER TABLE posts
+    ADD COLUMN slug VARCHAR(255) NULL AFTER title;
+
+-- Backfill: existing posts get a slug derived from their ID
+UPDATE posts SET slug = CONCAT('post-', id);
+
+ALTER TABLE posts
+    MODIFY COLUMN slug VARCHAR(255) NOT NULL;
+
+CREATE UNIQUE INDEX idx_posts_slug ON posts (slug);
@@ -0,0 +1,16 @@
+-- Background jobs run by the queue worker
+CREATE TABLE jobs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    run_at TIMESTAMP NOT NULL,
+    claim_token CHAR(32) NULL DEFAULT NULL,
+    locked_until TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_status_run_at (status, run_at),
+    INDEX idx_claim_token (claim_token)
+);
@@ -0,0 +1,10 @@
+-- TOTP enrollments; the secret is encrypted by the application
+CREATE TABLE user_two_factor (
+    user_id INT PRIMARY KEY,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    confirmed_at TIMESTAMP NULL DEFAULT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,38 @@
+-- Announcement emails sent by administrators; the segment is stored as JSON
+CREATE TABLE announcements (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    subject VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    segment JSON NOT NULL,
+    created_by INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    recipients INT NOT NULL DEFAULT 0,
+    sent INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- One row per recipient; pending recipients are the users without a row
+CREATE TABLE announcement_deliveries (
+    announcement_id INT NOT NULL,
+    user_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id),
+    INDEX idx_announcement_status (announcement_id, status),
+    FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Users who opted out of announcements through an unsubscribe link
+CREATE TABLE announcement_unsubscribes (
+    user_id INT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
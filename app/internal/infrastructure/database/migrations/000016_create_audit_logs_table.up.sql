@@ -0,0 +1,13 @@
+-- Security-relevant actions; entries outlive the accounts they refer to, so
+-- user_id has no foreign key
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    metadata JSON NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_logs_created_at (created_at),
+    INDEX idx_audit_logs_user_created_at (user_id, created_at),
+    INDEX idx_audit_logs_action_created_at (action, created_at)
+);
@@ -0,0 +1,13 @@
+ALTER TABLE users
+    ADD COLUMN password_reset_required_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE TABLE password_resets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_user_id (user_id),
+    UNIQUE INDEX idx_token_hash (token_hash)
+);
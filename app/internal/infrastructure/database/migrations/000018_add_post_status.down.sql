@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP INDEX idx_posts_status_published_at,
+    DROP COLUMN published_at,
+    DROP COLUMN status;
@@ -0,0 +1,9 @@
+-- Posts are drafts, scheduled for publishing or published; only published
+-- posts are public. Existing posts were public, so they are published as of
+-- their creation.
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' AFTER noindex,
+    ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER status,
+    ADD INDEX idx_posts_status_published_at (status, published_at);
+
+UPDATE posts SET published_at = created_at WHERE status = 'published';
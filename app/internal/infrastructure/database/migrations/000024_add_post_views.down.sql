@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_daily_views;
+ALTER TABLE posts DROP COLUMN view_count;
@@ -0,0 +1,13 @@
+-- Views are counted per day for ranking popular posts over a window, and
+-- in total on the post so showing a post needs no aggregation
+ALTER TABLE posts
+    ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0 AFTER timezone;
+
+CREATE TABLE post_daily_views (
+    post_id INT NOT NULL,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day),
+    INDEX idx_post_daily_views_day (day),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP INDEX idx_comments_post_created_at_id;
+ALTER TABLE posts DROP INDEX idx_posts_created_at_id;
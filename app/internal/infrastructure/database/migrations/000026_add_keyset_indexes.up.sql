@@ -0,0 +1,3 @@
+-- Keyset pagination orders posts and comments by (created_at, id)
+ALTER TABLE posts ADD INDEX idx_posts_created_at_id (created_at, id);
+ALTER TABLE comments ADD INDEX idx_comments_post_created_at_id (post_id, created_at, id);
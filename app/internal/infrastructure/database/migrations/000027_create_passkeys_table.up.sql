@@ -0,0 +1,16 @@
+-- WebAuthn credentials. The public key is stored in its COSE encoding; the
+-- user handle is the WebAuthn user ID shared by the passkeys of a user
+CREATE TABLE passkeys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    credential_id VARBINARY(1023) NOT NULL,
+    user_handle VARBINARY(64) NOT NULL,
+    public_key BLOB NOT NULL,
+    sign_count INT UNSIGNED NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_passkeys_user_id (user_id),
+    UNIQUE INDEX idx_passkeys_credential_id (credential_id)
+);
@@ -0,0 +1,36 @@
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP NULL DEFAULT NULL;
+
+-- Links users to the organization (tenant) that provisioned them over SCIM.
+-- External IDs are NULL when the identity provider sends none, so they do
+-- not collide.
+CREATE TABLE scim_accounts (
+    user_id INT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    user_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_scim_accounts_user_name (tenant, user_name),
+    UNIQUE INDEX idx_scim_accounts_external_id (tenant, external_id)
+);
+
+CREATE TABLE scim_groups (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_scim_groups_display_name (tenant, display_name)
+);
+
+CREATE TABLE scim_group_members (
+    group_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (group_id, user_id),
+    INDEX idx_scim_group_members_user_id (user_id),
+    FOREIGN KEY (group_id) REFERENCES scim_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
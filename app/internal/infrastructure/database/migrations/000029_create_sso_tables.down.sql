@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS sso_memberships;
+DROP TABLE IF EXISTS sso_domains;
+DROP TABLE IF EXISTS sso_connections;
+
+DELETE FROM user_identities WHERE provider LIKE 'sso:%';
+
+ALTER TABLE user_identities
+    MODIFY provider VARCHAR(32) NOT NULL;
@@ -0,0 +1,40 @@
+-- Identities of SSO members are linked under "sso:<organization>"
+ALTER TABLE user_identities
+    MODIFY provider VARCHAR(96) NOT NULL;
+
+-- The single sign-on connection of each organization. Role mappings are a
+-- JSON object of group names to roles; client secrets are encrypted.
+CREATE TABLE sso_connections (
+    organization VARCHAR(64) PRIMARY KEY,
+    protocol VARCHAR(16) NOT NULL,
+    enforcement VARCHAR(32) NOT NULL DEFAULT 'optional',
+    default_role VARCHAR(20) NOT NULL DEFAULT '',
+    role_mappings TEXT NOT NULL,
+    groups_claim VARCHAR(255) NOT NULL DEFAULT '',
+    issuer VARCHAR(2048) NOT NULL DEFAULT '',
+    client_id VARCHAR(255) NOT NULL DEFAULT '',
+    client_secret_encrypted TEXT NOT NULL,
+    metadata MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Each email domain belongs to at most one organization
+CREATE TABLE sso_domains (
+    domain VARCHAR(255) PRIMARY KEY,
+    organization VARCHAR(64) NOT NULL,
+    INDEX idx_sso_domains_organization (organization),
+    FOREIGN KEY (organization) REFERENCES sso_connections(organization) ON DELETE CASCADE
+);
+
+CREATE TABLE sso_memberships (
+    organization VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization, user_id),
+    INDEX idx_sso_memberships_user_id (user_id),
+    FOREIGN KEY (organization) REFERENCES sso_connections(organization) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
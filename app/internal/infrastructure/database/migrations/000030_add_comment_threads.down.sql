@@ -0,0 +1,11 @@
+-- Replies become top-level comments again
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_parent,
+    DROP FOREIGN KEY fk_comments_root;
+
+ALTER TABLE comments
+    DROP INDEX idx_comments_post_roots,
+    DROP INDEX idx_comments_root_created_at,
+    DROP COLUMN depth,
+    DROP COLUMN root_id,
+    DROP COLUMN parent_id;
@@ -0,0 +1,11 @@
+-- Replies name the comment they answer and the top-level comment of their
+-- thread, so a page of threads loads with one query. Deleting a comment
+-- deletes the replies to it.
+ALTER TABLE comments
+    ADD COLUMN parent_id INT NULL DEFAULT NULL AFTER post_id,
+    ADD COLUMN root_id INT NULL DEFAULT NULL AFTER parent_id,
+    ADD COLUMN depth TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER root_id,
+    ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_comments_root FOREIGN KEY (root_id) REFERENCES comments(id) ON DELETE CASCADE,
+    ADD INDEX idx_comments_post_roots (post_id, parent_id, created_at, id),
+    ADD INDEX idx_comments_root_created_at (root_id, created_at, id);
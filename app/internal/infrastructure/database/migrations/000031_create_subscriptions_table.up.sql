@@ -0,0 +1,16 @@
+-- The Stripe subscription of each user. Users without a row are on the free
+-- plan; last_event_at orders the webhook events applied to a row.
+CREATE TABLE subscriptions (
+    user_id INT PRIMARY KEY,
+    plan VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    stripe_customer_id VARCHAR(255) NOT NULL,
+    stripe_subscription_id VARCHAR(255) NOT NULL,
+    current_period_end TIMESTAMP NULL DEFAULT NULL,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    last_event_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_subscriptions_stripe_customer (stripe_customer_id)
+);
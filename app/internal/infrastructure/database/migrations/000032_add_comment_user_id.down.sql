@@ -0,0 +1,6 @@
+-- Comments go back to being identified by author name only
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_user;
+
+ALTER TABLE comments
+    DROP COLUMN user_id;
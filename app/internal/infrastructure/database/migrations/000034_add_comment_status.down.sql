@@ -0,0 +1,3 @@
+ALTER TABLE comments
+    DROP INDEX idx_comments_status_created_at,
+    DROP COLUMN status;
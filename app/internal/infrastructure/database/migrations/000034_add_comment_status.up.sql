@@ -0,0 +1,5 @@
+-- Comments can be held for moderation; only approved comments are public.
+-- Existing comments were public, so they are approved.
+ALTER TABLE comments
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'approved' AFTER content,
+    ADD INDEX idx_comments_status_created_at (status, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN tip_count;
+DROP TABLE IF EXISTS tips;
+DROP TABLE IF EXISTS payout_accounts;
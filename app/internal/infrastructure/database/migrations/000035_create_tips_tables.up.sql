@@ -0,0 +1,33 @@
+-- Accounts authors are paid tips out to, at the payment provider
+CREATE TABLE payout_accounts (
+    user_id INT PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- One-time payments to the author of a post. Tips are pending until the
+-- provider reports their payment; tips of deleted accounts become guest tips.
+CREATE TABLE tips (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    author_id INT NOT NULL,
+    tipper_id INT NULL DEFAULT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tipper_id) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_tips_post_status (post_id, status)
+);
+
+-- Succeeded tips per post, counted when their payment is reported
+ALTER TABLE posts
+    ADD COLUMN tip_count BIGINT NOT NULL DEFAULT 0 AFTER view_count;
@@ -0,0 +1,8 @@
+-- Trust levels of commenters, stored by the job evaluating them; commenters
+-- without a row are new
+CREATE TABLE commenter_trust (
+    user_id INT PRIMARY KEY,
+    level VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,24 @@
+-- Site-wide banners shown to readers between starts_at and ends_at
+CREATE TABLE banners (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    message VARCHAR(500) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP NULL DEFAULT NULL,
+    dismissible BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_banners_schedule (starts_at, ends_at)
+);
+
+-- Banners closed by users, so they are not shown to them again
+CREATE TABLE banner_dismissals (
+    banner_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (banner_id, user_id),
+    FOREIGN KEY (banner_id) REFERENCES banners(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+-- Notifications of activity on the posts and comments of a user
+CREATE TABLE notifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    post_id INT NOT NULL,
+    comment_id INT NOT NULL,
+    actor_name VARCHAR(255) NOT NULL,
+    read_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    INDEX idx_notifications_user (user_id, created_at),
+    INDEX idx_notifications_unread (user_id, read_at)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN avatar_url,
+    DROP COLUMN bio;
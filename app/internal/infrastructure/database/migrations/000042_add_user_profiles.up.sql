@@ -0,0 +1,4 @@
+-- Public profile fields shown on author pages
+ALTER TABLE users
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '' AFTER name,
+    ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '' AFTER bio;
@@ -0,0 +1,11 @@
+-- Old post slugs redirecting to the post, recorded on slug changes or added
+-- by admins
+CREATE TABLE post_redirects (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    from_slug VARCHAR(255) NOT NULL,
+    post_id INT NOT NULL,
+    automatic BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_post_redirects_from_slug (from_slug),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
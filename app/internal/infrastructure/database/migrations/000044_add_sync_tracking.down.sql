@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tombstones;
+ALTER TABLE posts DROP INDEX idx_posts_updated_at_id;
+ALTER TABLE comments
+    DROP INDEX idx_comments_updated_at_id,
+    DROP COLUMN updated_at;
@@ -0,0 +1,17 @@
+-- Offline clients sync the posts and comments changed since a checkpoint.
+-- Comments record when they last changed, and deleted posts and comments
+-- leave tombstones since their rows are gone.
+ALTER TABLE comments
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP AFTER created_at,
+    ADD INDEX idx_comments_updated_at_id (updated_at, id);
+UPDATE comments SET updated_at = created_at;
+
+ALTER TABLE posts ADD INDEX idx_posts_updated_at_id (updated_at, id);
+
+CREATE TABLE tombstones (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_tombstones_deleted_at_id (deleted_at, id)
+);
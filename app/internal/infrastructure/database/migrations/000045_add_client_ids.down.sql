@@ -0,0 +1,6 @@
+ALTER TABLE comments
+    DROP INDEX uq_comments_client_id,
+    DROP COLUMN client_id;
+ALTER TABLE posts
+    DROP INDEX uq_posts_client_id,
+    DROP COLUMN client_id;
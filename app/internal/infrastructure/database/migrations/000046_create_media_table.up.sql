@@ -0,0 +1,16 @@
+-- Images uploaded to reference in posts. Rows outlive their owner on
+-- purpose: the files of deleted accounts are collected with the other
+-- media no post references.
+CREATE TABLE media (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_media_storage_key (storage_key),
+    INDEX idx_media_user_id (user_id),
+    INDEX idx_media_created_at (created_at)
+);
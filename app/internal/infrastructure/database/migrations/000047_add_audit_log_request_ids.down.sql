@@ -0,0 +1,3 @@
+ALTER TABLE audit_logs
+    DROP INDEX idx_audit_logs_request_id,
+    DROP COLUMN request_id;
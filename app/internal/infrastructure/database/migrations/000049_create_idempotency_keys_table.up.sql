@@ -0,0 +1,16 @@
+-- Write requests sent with an Idempotency-Key and, once answered, their
+-- response, replayed to retries until the record expires; status_code is 0
+-- while the first request is processed
+CREATE TABLE idempotency_keys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    scope VARCHAR(64) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    response_body MEDIUMBLOB NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE KEY uq_idempotency_keys_scope_key (scope, idempotency_key),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
@@ -0,0 +1,23 @@
+-- Content imports started by administrators. The parsed export is stored
+-- as JSON so the import job can resume after a restart; processed counts
+-- the posts handled so far and errors lists the posts that failed.
+CREATE TABLE imports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(16) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    created_by INT NOT NULL,
+    document LONGTEXT NOT NULL,
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    users_created INT NOT NULL DEFAULT 0,
+    posts_created INT NOT NULL DEFAULT 0,
+    comments_created INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    errors JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP COLUMN word_count,
+    DROP COLUMN reading_time_minutes,
+    DROP COLUMN excerpt;
@@ -0,0 +1,7 @@
+-- Word count, estimated reading time and excerpt of the content, kept for
+-- post listings; posts written before these columns existed have a reading
+-- time of 0 until the server computes them on startup
+ALTER TABLE posts
+    ADD COLUMN word_count INT NOT NULL DEFAULT 0 AFTER timezone,
+    ADD COLUMN reading_time_minutes INT NOT NULL DEFAULT 0 AFTER word_count,
+    ADD COLUMN excerpt VARCHAR(300) NOT NULL DEFAULT '' AFTER reading_time_minutes;
@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP INDEX idx_posts_featured_at,
+    DROP COLUMN pinned_at,
+    DROP COLUMN featured_at;
@@ -0,0 +1,6 @@
+-- Admins pin posts to the top of the post list and feature posts in a
+-- curated list; the times order pinned and featured posts, latest first
+ALTER TABLE posts
+    ADD COLUMN pinned_at TIMESTAMP NULL DEFAULT NULL AFTER excerpt,
+    ADD COLUMN featured_at TIMESTAMP NULL DEFAULT NULL AFTER pinned_at,
+    ADD INDEX idx_posts_featured_at (featured_at);
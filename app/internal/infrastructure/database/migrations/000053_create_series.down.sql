@@ -0,0 +1,9 @@
+ALTER TABLE posts
+    DROP FOREIGN KEY fk_posts_series;
+
+ALTER TABLE posts
+    DROP INDEX idx_posts_series,
+    DROP COLUMN series_id,
+    DROP COLUMN series_position;
+
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,19 @@
+-- Series group posts of an author in reading order
+CREATE TABLE series (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    author_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_series_author_id (author_id)
+);
+
+-- A post belongs to at most one series, at a position counted from 1;
+-- posts outside any series keep position 0
+ALTER TABLE posts
+    ADD COLUMN series_id INT NULL DEFAULT NULL AFTER featured_at,
+    ADD COLUMN series_position INT NOT NULL DEFAULT 0 AFTER series_id,
+    ADD CONSTRAINT fk_posts_series FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE SET NULL,
+    ADD INDEX idx_posts_series (series_id, series_position);
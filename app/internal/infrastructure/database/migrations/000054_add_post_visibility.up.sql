@@ -0,0 +1,5 @@
+-- Unlisted posts are read by link but left out of listings and feeds;
+-- private posts show only to their author and admins. Existing posts stay
+-- public.
+ALTER TABLE posts
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' AFTER access;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS post_translations;
+
+ALTER TABLE posts
+    DROP COLUMN language;
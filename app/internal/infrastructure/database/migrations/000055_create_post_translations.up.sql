@@ -0,0 +1,20 @@
+-- Posts are written in one language, English for existing posts, and may
+-- be translated to others; readers get the translation their
+-- Accept-Language prefers
+ALTER TABLE posts
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT 'en' AFTER visibility;
+
+-- One translation per post and language. translated_by has no foreign key
+-- so translations survive the removal of a translator's account
+CREATE TABLE post_translations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    language VARCHAR(35) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL,
+    translated_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_post_translations_language (post_id, language)
+);
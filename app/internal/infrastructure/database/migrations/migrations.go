@@ -0,0 +1,591 @@
+// Package migrations applies the SQL migrations in this directory, which
+// are compiled into the binary. Each migration is a pair of
+// NNNNNN_name.up.sql and NNNNNN_name.down.sql files; applied versions are
+// recorded in the schema_migrations table. The MySQL migrations are at the
+// top level and their PostgreSQL and SQLite counterparts, with the same
+// versions and names, in postgres/ and sqlite/.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Files holds the MySQL migrations of this directory
+//
+//go:embed *.sql
+var Files embed.FS
+
+// postgresFiles holds the PostgreSQL migrations
+//
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// PostgresFiles holds the PostgreSQL migrations
+var PostgresFiles, _ = fs.Sub(postgresFiles, "postgres")
+
+// sqliteFiles holds the SQLite migrations
+//
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteFiles holds the SQLite migrations
+var SQLiteFiles, _ = fs.Sub(sqliteFiles, "sqlite")
+
+// lockName is the MySQL named lock held while migrating, so servers
+// starting together do not apply the same migration twice
+const lockName = "schema_migrations"
+
+// lockKey is the PostgreSQL advisory lock held while migrating
+const lockKey int64 = 7_302_604_851_230_521_958
+
+// lockTimeout is how long to wait for another process to finish migrating
+const lockTimeout = 5 * time.Minute
+
+// Errors returned by the migrator
+var (
+	ErrDirty          = errors.New("a migration failed part way; fix the schema by hand, then force its version")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrLocked         = errors.New("another process is migrating the database")
+)
+
+// Migration is a versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the file name stem of the migration, e.g. 000001_create_users_table
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// Status is the state of a migration in the database
+type Status struct {
+	Migration
+	Applied bool
+	// Dirty is set when the migration started but did not finish
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+// Load reads the migrations of a file system, in version order. Every
+// version needs both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, name := range names {
+		stem, direction, ok := cutDirection(name)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must end in .up.sql or .down.sql", name)
+		}
+		prefix, label, ok := strings.Cut(stem, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive version and an underscore", name)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration %d: files are named %s and %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s: both the up and down files are required", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// cutDirection splits a file name into its stem and direction
+func cutDirection(name string) (stem, direction string, ok bool) {
+	name = path.Base(name)
+	if stem, ok := strings.CutSuffix(name, ".up.sql"); ok {
+		return stem, "up", true
+	}
+	if stem, ok := strings.CutSuffix(name, ".down.sql"); ok {
+		return stem, "down", true
+	}
+	return "", "", false
+}
+
+// Split splits a migration into its statements. Semicolons inside quotes,
+// PostgreSQL dollar quotes, SQLite trigger bodies and comments do not end a
+// statement; comments are dropped.
+func Split(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			statements = append(statements, s)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted text; a doubled quote or a backslash escapes it
+			end := i + 1
+			for end < len(script) {
+				if script[end] == '\\' && c != '`' {
+					end += 2
+					continue
+				}
+				if script[end] == c {
+					if end+1 < len(script) && script[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end, len(script)-1)
+			current.WriteString(script[i : end+1])
+			i = end
+		case c == '$' && dollarTag(script[i:]) != "":
+			// Copy the dollar-quoted text, such as a function body
+			tag := dollarTag(script[i:])
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				end = len(script)
+			} else {
+				end += i + 2*len(tag)
+			}
+			current.WriteString(script[i:end])
+			i = end - 1
+		case c == '#' || (c == '-' && strings.HasPrefix(script[i:], "-- ")) || strings.HasPrefix(script[i:], "--\n"):
+			// Line comment
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end
+				current.WriteByte('\n')
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+				current.WriteByte(' ')
+			}
+		case c == ';' && inTriggerBody(current.String()):
+			current.WriteByte(c)
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+// dollarTag returns the PostgreSQL dollar quote opening s, such as $$ or
+// $body$, if any
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// inTriggerBody reports whether a statement so far creates a SQLite trigger
+// whose BEGIN ... END body is not closed yet
+func inTriggerBody(statement string) bool {
+	words := strings.Fields(strings.ToUpper(statement))
+	if len(words) < 2 || words[0] != "CREATE" || words[1] != "TRIGGER" {
+		return false
+	}
+	return slices.Contains(words, "BEGIN") && words[len(words)-1] != "END"
+}
+
+// Migrator applies migrations to a MySQL, PostgreSQL or SQLite database
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// New creates a migrator for the embedded migrations of a database/sql
+// driver, "mysql", "pgx" or "sqlite"
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	d, err := dialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(d.files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+// Up applies the migrations not applied yet, oldest first, and returns
+// them. It stops at the first failure, leaving that migration dirty.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		state, err := m.state(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := state[migration.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, migration, migration.Up); err != nil {
+				return err
+			}
+			if _, err := conn.ExecContext(ctx, m.dialect.bind(`UPDATE schema_migrations SET dirty = FALSE, applied_at = ? WHERE version = ?`), time.Now().UTC(), migration.Version); err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and
+// returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		state, err := m.state(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := state[migration.Version]; !ok {
+				continue
+			}
+			if err := m.run(ctx, conn, migration, migration.Down); err != nil {
+				return err
+			}
+			if _, err := conn.ExecContext(ctx, m.dialect.bind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version); err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns every known migration with whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	state, err := m.records(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if r, ok := state[migration.Version]; ok {
+			statuses[i].Applied = !r.dirty
+			statuses[i].Dirty = r.dirty
+			statuses[i].AppliedAt = r.appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Force records the migrations up to version as applied and the later ones
+// as not applied, without running any. It adopts databases created before
+// migrations were tracked, and clears a dirty migration once the schema
+// was fixed by hand. Version 0 marks every migration as not applied.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		if err := m.ensureTable(ctx, conn); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, m.dialect.bind(`DELETE FROM schema_migrations WHERE version > ? OR dirty`), version); err != nil {
+			return fmt.Errorf("failed to reset migrations: %w", err)
+		}
+		now := time.Now().UTC()
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			_, err := conn.ExecContext(ctx, m.dialect.bind(m.dialect.recordApplied), migration.Version, migration.Name, now)
+			if err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration, err)
+			}
+		}
+		return nil
+	})
+}
+
+// run executes the statements of a migration after marking it dirty, so a
+// failure part way is not mistaken for either state. MySQL commits schema
+// changes right away, so there is no transaction to roll back; statements
+// run one by one on PostgreSQL too, for the same dirty handling.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, migration Migration, script string) error {
+	_, err := conn.ExecContext(ctx, m.dialect.bind(m.dialect.markDirty), migration.Version, migration.Name)
+	if err != nil {
+		return fmt.Errorf("failed to mark migration %s: %w", migration, err)
+	}
+
+	for _, statement := range Split(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration, err)
+		}
+	}
+	return nil
+}
+
+// locked runs fn on a connection holding the migration lock
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	if err := m.dialect.lock(ctx, conn); err != nil {
+		return err
+	}
+	// The lock is released with the connection if the release fails
+	defer m.dialect.unlock(context.WithoutCancel(ctx), conn)
+
+	return fn(conn)
+}
+
+// state returns the recorded migrations, refusing to go on while one is
+// dirty
+func (m *Migrator) state(ctx context.Context, conn *sql.Conn) (map[int]record, error) {
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	records, err := m.records(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	for version, r := range records {
+		if r.dirty {
+			return nil, fmt.Errorf("migration %d: %w", version, ErrDirty)
+		}
+	}
+	return records, nil
+}
+
+// record is a row of schema_migrations
+type record struct {
+	dirty     bool
+	appliedAt *time.Time
+}
+
+// records reads schema_migrations; a missing table means nothing is applied
+func (m *Migrator) records(ctx context.Context, conn *sql.Conn) (map[int]record, error) {
+	var exists int
+	if err := conn.QueryRowContext(ctx, m.dialect.tableExists).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations: %w", err)
+	}
+	records := make(map[int]record)
+	if exists == 0 {
+		return records, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, dirty, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var r record
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &r.dirty, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+		if appliedAt.Valid {
+			r.appliedAt = &appliedAt.Time
+		}
+		records[version] = r
+	}
+	return records, rows.Err()
+}
+
+// ensureTable creates schema_migrations when it is missing
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// known reports whether a migration has the version
+func (m *Migrator) known(version int) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// dialect holds what the migrator does differently per database
+type dialect struct {
+	files fs.FS
+	// bind rewrites the ? placeholders of a query for the driver
+	bind          func(query string) string
+	tableExists   string
+	markDirty     string
+	recordApplied string
+	lock          func(ctx context.Context, conn *sql.Conn) error
+	unlock        func(ctx context.Context, conn *sql.Conn)
+}
+
+// dialectOf returns the dialect of a database/sql driver
+func dialectOf(driver string) (dialect, error) {
+	bind := func(query string) string { return sqlx.Rebind(sqlx.BindType(driver), query) }
+
+	switch driver {
+	case "mysql":
+		return dialect{
+			files: Files,
+			bind:  bind,
+			tableExists: `
+				SELECT COUNT(*) FROM information_schema.tables
+				WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'
+			`,
+			markDirty: `
+				INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, TRUE)
+				ON DUPLICATE KEY UPDATE dirty = TRUE
+			`,
+			recordApplied: `INSERT IGNORE INTO schema_migrations (version, name, dirty, applied_at) VALUES (?, ?, FALSE, ?)`,
+			lock: func(ctx context.Context, conn *sql.Conn) error {
+				var acquired sql.NullInt64
+				if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, int(lockTimeout.Seconds())).Scan(&acquired); err != nil {
+					return fmt.Errorf("failed to acquire migration lock: %w", err)
+				}
+				if acquired.Int64 != 1 {
+					return ErrLocked
+				}
+				return nil
+			},
+			unlock: func(ctx context.Context, conn *sql.Conn) {
+				conn.ExecContext(ctx, `SELECT RELEASE_LOCK(?)`, lockName)
+			},
+		}, nil
+	case "pgx", "postgres":
+		return dialect{
+			files: PostgresFiles,
+			bind:  bind,
+			tableExists: `
+				SELECT COUNT(*) FROM information_schema.tables
+				WHERE table_schema = current_schema() AND table_name = 'schema_migrations'
+			`,
+			markDirty: `
+				INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, TRUE)
+				ON CONFLICT (version) DO UPDATE SET dirty = TRUE
+			`,
+			recordApplied: `INSERT INTO schema_migrations (version, name, dirty, applied_at) VALUES (?, ?, FALSE, ?) ON CONFLICT (version) DO NOTHING`,
+			lock: func(ctx context.Context, conn *sql.Conn) error {
+				// Advisory locks wait without a timeout of their own
+				waitCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+				defer cancel()
+				if _, err := conn.ExecContext(waitCtx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+					if waitCtx.Err() != nil && ctx.Err() == nil {
+						return ErrLocked
+					}
+					return fmt.Errorf("failed to acquire migration lock: %w", err)
+				}
+				return nil
+			},
+			unlock: func(ctx context.Context, conn *sql.Conn) {
+				conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey)
+			},
+		}, nil
+	case "sqlite":
+		return dialect{
+			files: SQLiteFiles,
+			bind:  bind,
+			tableExists: `
+				SELECT COUNT(*) FROM sqlite_master
+				WHERE type = 'table' AND name = 'schema_migrations'
+			`,
+			markDirty: `
+				INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, TRUE)
+				ON CONFLICT (version) DO UPDATE SET dirty = TRUE
+			`,
+			recordApplied: `INSERT INTO schema_migrations (version, name, dirty, applied_at) VALUES (?, ?, FALSE, ?) ON CONFLICT (version) DO NOTHING`,
+			// A SQLite database is a file of a single server, so there is
+			// nobody to wait for
+			lock:   func(ctx context.Context, conn *sql.Conn) error { return nil },
+			unlock: func(ctx context.Context, conn *sql.Conn) {},
+		}, nil
+	default:
+		return dialect{}, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
@@ -0,0 +1,27 @@
+-- updated_at columns are kept current by triggers calling set_updated_at,
+-- as ON UPDATE CURRENT_TIMESTAMP does on MySQL: the column is set when a
+-- row changes and the update does not set it itself. Trigger arguments name
+-- columns whose changes alone, such as counters, leave it as is.
+CREATE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    IF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at
+        AND to_jsonb(NEW) - 'updated_at' - TG_ARGV IS DISTINCT FROM to_jsonb(OLD) - 'updated_at' - TG_ARGV THEN
+        NEW.updated_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_email ON users (email);
+
+CREATE TRIGGER users_set_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_email_normalized;
+
+ALTER TABLE users
+    DROP COLUMN email_conflict,
+    DROP COLUMN email_normalized;
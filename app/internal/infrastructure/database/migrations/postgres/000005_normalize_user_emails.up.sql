@@ -0,0 +1,26 @@
+-- Canonical email used for case-insensitive uniqueness checks and lookups
+ALTER TABLE users
+    ADD COLUMN email_normalized VARCHAR(255) NULL,
+    ADD COLUMN email_conflict BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Backfill: lowercase and trim every address
+UPDATE users SET email_normalized = LOWER(TRIM(email));
+
+-- Gmail ignores dots and plus-tags in the local part
+UPDATE users
+SET email_normalized = REPLACE(SPLIT_PART(SPLIT_PART(email_normalized, '@', 1), '+', 1), '.', '') || '@gmail.com'
+WHERE SPLIT_PART(email_normalized, '@', 2) IN ('gmail.com', 'googlemail.com');
+
+-- Flag duplicates: the oldest account keeps the canonical address, later
+-- accounts sharing it are flagged for manual resolution
+UPDATE users u
+SET email_conflict = TRUE, email_normalized = NULL
+FROM (
+    SELECT email_normalized, MIN(id) AS keep_id
+    FROM users
+    GROUP BY email_normalized
+    HAVING COUNT(*) > 1
+) dup
+WHERE u.email_normalized = dup.email_normalized AND u.id <> dup.keep_id;
+
+CREATE UNIQUE INDEX idx_users_email_normalized ON users (email_normalized);
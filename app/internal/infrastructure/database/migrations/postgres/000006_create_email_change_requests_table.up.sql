@@ -0,0 +1,13 @@
+CREATE TABLE email_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    new_email_normalized VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_email_change_requests_user_id ON email_change_requests (user_id);
+CREATE UNIQUE INDEX idx_email_change_requests_token_hash ON email_change_requests (token_hash);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN deletion_scheduled_at TIMESTAMPTZ NULL DEFAULT NULL,
+    ADD COLUMN deleted_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
@@ -0,0 +1,3 @@
+-- Existing users keep the ability to write posts
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'author';
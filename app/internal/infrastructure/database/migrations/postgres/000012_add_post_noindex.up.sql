@@ -0,0 +1,3 @@
+-- Posts flagged noindex get a robots meta tag and X-Robots-Tag header
+ALTER TABLE posts
+    ADD COLUMN noindex BOOLEAN NOT NULL DEFAULT FALSE;
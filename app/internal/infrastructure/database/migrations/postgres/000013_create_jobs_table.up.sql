@@ -0,0 +1,20 @@
+-- Background jobs run by the queue worker
+CREATE TABLE jobs (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL,
+    claim_token CHAR(32) NULL DEFAULT NULL,
+    locked_until TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs (status, run_at);
+CREATE INDEX idx_jobs_claim_token ON jobs (claim_token);
+
+CREATE TRIGGER jobs_set_updated_at BEFORE UPDATE ON jobs
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,13 @@
+-- TOTP enrollments; the secret is encrypted by the application
+CREATE TABLE user_two_factor (
+    user_id INT PRIMARY KEY,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    confirmed_at TIMESTAMPTZ NULL DEFAULT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER user_two_factor_set_updated_at BEFORE UPDATE ON user_two_factor
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
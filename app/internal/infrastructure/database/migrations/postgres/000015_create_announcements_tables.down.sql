@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS announcement_unsubscribes;
+DROP TABLE IF EXISTS announcement_deliveries;
+DROP TABLE IF EXISTS announcements;
@@ -0,0 +1,14 @@
+-- Security-relevant actions; entries outlive the accounts they refer to, so
+-- user_id has no foreign key
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    metadata JSONB NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
+CREATE INDEX idx_audit_logs_user_created_at ON audit_logs (user_id, created_at);
+CREATE INDEX idx_audit_logs_action_created_at ON audit_logs (action, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_status_published_at;
+
+ALTER TABLE posts
+    DROP COLUMN published_at,
+    DROP COLUMN status;
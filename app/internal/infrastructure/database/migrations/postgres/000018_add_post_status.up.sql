@@ -0,0 +1,10 @@
+-- Posts are drafts, scheduled for publishing or published; only published
+-- posts are public. Existing posts were public, so they are published as of
+-- their creation.
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN published_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+CREATE INDEX idx_posts_status_published_at ON posts (status, published_at);
+
+UPDATE posts SET published_at = created_at WHERE status = 'published';
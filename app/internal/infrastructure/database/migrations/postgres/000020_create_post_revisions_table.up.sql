@@ -0,0 +1,14 @@
+-- Earlier versions of a post, numbered per post. edited_by has no foreign
+-- key so the history survives the removal of an editor's account
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL,
+    number INT NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL,
+    edited_by INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_post_revisions_number ON post_revisions (post_id, number);
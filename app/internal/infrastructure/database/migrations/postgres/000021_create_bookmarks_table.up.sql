@@ -0,0 +1,11 @@
+-- Posts users saved to their reading list
+CREATE TABLE bookmarks (
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    created_at TIMESTAMPTZ(3) DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bookmarks_reading_list ON bookmarks (user_id, created_at);
@@ -0,0 +1,12 @@
+-- Custom titles and descriptions of author and tag feeds. scope_key is the
+-- author's user ID or the normalized tag name, so rows outlive their subject
+-- and apply again if a tag comes back into use
+CREATE TABLE feed_preferences (
+    scope VARCHAR(20) NOT NULL,
+    scope_key VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL DEFAULT '',
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    updated_by INT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, scope_key)
+);
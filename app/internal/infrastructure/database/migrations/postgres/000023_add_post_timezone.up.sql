@@ -0,0 +1,4 @@
+-- The IANA time zone a post was scheduled in, for showing its publish time
+-- as planned; empty for UTC
+ALTER TABLE posts
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS post_daily_views;
+
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE posts DROP COLUMN view_count;
@@ -0,0 +1,19 @@
+-- Views are counted per day for ranking popular posts over a window, and
+-- in total on the post so showing a post needs no aggregation
+ALTER TABLE posts
+    ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0;
+
+-- Counting views is not an edit, so it leaves updated_at as is
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at('view_count');
+
+CREATE TABLE post_daily_views (
+    post_id INT NOT NULL,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_daily_views_day ON post_daily_views (day);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS scim_group_members;
+DROP TABLE IF EXISTS scim_groups;
+DROP TABLE IF EXISTS scim_accounts;
+
+ALTER TABLE users
+    DROP COLUMN deactivated_at;
@@ -0,0 +1,10 @@
+-- Replies become top-level comments again
+DROP INDEX IF EXISTS idx_comments_post_roots;
+DROP INDEX IF EXISTS idx_comments_root_created_at;
+
+ALTER TABLE comments
+    DROP CONSTRAINT fk_comments_parent,
+    DROP CONSTRAINT fk_comments_root,
+    DROP COLUMN depth,
+    DROP COLUMN root_id,
+    DROP COLUMN parent_id;
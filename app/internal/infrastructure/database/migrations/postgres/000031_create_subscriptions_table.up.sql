@@ -0,0 +1,20 @@
+-- The Stripe subscription of each user. Users without a row are on the free
+-- plan; last_event_at orders the webhook events applied to a row.
+CREATE TABLE subscriptions (
+    user_id INT PRIMARY KEY,
+    plan VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    stripe_customer_id VARCHAR(255) NOT NULL,
+    stripe_subscription_id VARCHAR(255) NOT NULL,
+    current_period_end TIMESTAMPTZ NULL DEFAULT NULL,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    last_event_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_subscriptions_stripe_customer ON subscriptions (stripe_customer_id);
+
+CREATE TRIGGER subscriptions_set_updated_at BEFORE UPDATE ON subscriptions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
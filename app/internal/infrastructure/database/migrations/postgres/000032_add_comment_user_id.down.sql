@@ -0,0 +1,4 @@
+-- Comments go back to being identified by author name only
+ALTER TABLE comments
+    DROP CONSTRAINT fk_comments_user,
+    DROP COLUMN user_id;
@@ -0,0 +1,6 @@
+-- Comments by signed-in users name them, so edits and deletions are
+-- authorized by account rather than by the free-text author name. Guest
+-- comments keep a NULL user_id, as do comments of deleted accounts.
+ALTER TABLE comments
+    ADD COLUMN user_id INT NULL DEFAULT NULL,
+    ADD CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
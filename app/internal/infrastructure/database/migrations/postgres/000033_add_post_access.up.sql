@@ -0,0 +1,4 @@
+-- Members-only and subscriber-only posts show their full content to
+-- entitled readers; everyone else gets an excerpt. Existing posts stay public.
+ALTER TABLE posts
+    ADD COLUMN access VARCHAR(20) NOT NULL DEFAULT 'public';
@@ -0,0 +1,7 @@
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at('view_count');
+
+ALTER TABLE posts DROP COLUMN tip_count;
+DROP TABLE IF EXISTS tips;
+DROP TABLE IF EXISTS payout_accounts;
@@ -0,0 +1,45 @@
+-- Accounts authors are paid tips out to, at the payment provider
+CREATE TABLE payout_accounts (
+    user_id INT PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER payout_accounts_set_updated_at BEFORE UPDATE ON payout_accounts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- One-time payments to the author of a post. Tips are pending until the
+-- provider reports their payment; tips of deleted accounts become guest tips.
+CREATE TABLE tips (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL,
+    author_id INT NOT NULL,
+    tipper_id INT NULL DEFAULT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tipper_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_tips_post_status ON tips (post_id, status);
+
+CREATE TRIGGER tips_set_updated_at BEFORE UPDATE ON tips
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Succeeded tips per post, counted when their payment is reported; like
+-- views, counting them leaves updated_at as is
+ALTER TABLE posts
+    ADD COLUMN tip_count BIGINT NOT NULL DEFAULT 0;
+
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at('view_count', 'tip_count');
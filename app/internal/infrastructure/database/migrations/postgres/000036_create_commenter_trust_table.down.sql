@@ -0,0 +1 @@
+DROP TABLE IF EXISTS commenter_trust;
@@ -0,0 +1,11 @@
+-- Trust levels of commenters, stored by the job evaluating them; commenters
+-- without a row are new
+CREATE TABLE commenter_trust (
+    user_id INT PRIMARY KEY,
+    level VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER commenter_trust_set_updated_at BEFORE UPDATE ON commenter_trust
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
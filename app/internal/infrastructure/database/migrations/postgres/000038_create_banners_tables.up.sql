@@ -0,0 +1,28 @@
+-- Site-wide banners shown to readers between starts_at and ends_at
+CREATE TABLE banners (
+    id SERIAL PRIMARY KEY,
+    message VARCHAR(500) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMPTZ NULL DEFAULT NULL,
+    dismissible BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_banners_schedule ON banners (starts_at, ends_at);
+
+CREATE TRIGGER banners_set_updated_at BEFORE UPDATE ON banners
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Banners closed by users, so they are not shown to them again
+CREATE TABLE banner_dismissals (
+    banner_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (banner_id, user_id),
+    FOREIGN KEY (banner_id) REFERENCES banners(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+-- How far each user read a post, synced across their devices. recorded_at
+-- is when a device read up to the position; only later positions replace
+-- the stored one.
+CREATE TABLE reading_progress (
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    anchor VARCHAR(255) NOT NULL DEFAULT '',
+    recorded_at TIMESTAMPTZ(3) NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_progress_history ON reading_progress (user_id, recorded_at);
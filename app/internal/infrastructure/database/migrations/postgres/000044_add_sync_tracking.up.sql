@@ -0,0 +1,22 @@
+-- Offline clients sync the posts and comments changed since a checkpoint.
+-- Comments record when they last changed, and deleted posts and comments
+-- leave tombstones since their rows are gone.
+ALTER TABLE comments
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+UPDATE comments SET updated_at = created_at;
+
+CREATE INDEX idx_comments_updated_at_id ON comments (updated_at, id);
+
+CREATE TRIGGER comments_set_updated_at BEFORE UPDATE ON comments
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE INDEX idx_posts_updated_at_id ON posts (updated_at, id);
+
+CREATE TABLE tombstones (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tombstones_deleted_at_id ON tombstones (deleted_at, id);
@@ -0,0 +1,6 @@
+ALTER TABLE comments
+    DROP CONSTRAINT uq_comments_client_id,
+    DROP COLUMN client_id;
+ALTER TABLE posts
+    DROP CONSTRAINT uq_posts_client_id,
+    DROP COLUMN client_id;
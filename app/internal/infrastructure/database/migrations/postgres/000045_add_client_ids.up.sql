@@ -0,0 +1,10 @@
+-- Offline clients create posts and comments under IDs they generate, so
+-- retried writes are recognized and new resources can be referenced before
+-- the server assigns their IDs. Rows created online have none.
+ALTER TABLE posts
+    ADD COLUMN client_id CHAR(36) NULL,
+    ADD CONSTRAINT uq_posts_client_id UNIQUE (client_id);
+
+ALTER TABLE comments
+    ADD COLUMN client_id CHAR(36) NULL,
+    ADD CONSTRAINT uq_comments_client_id UNIQUE (client_id);
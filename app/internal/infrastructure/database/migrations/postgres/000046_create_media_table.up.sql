@@ -0,0 +1,17 @@
+-- Images uploaded to reference in posts. Rows outlive their owner on
+-- purpose: the files of deleted accounts are collected with the other
+-- media no post references.
+CREATE TABLE media (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_media_storage_key UNIQUE (storage_key)
+);
+
+CREATE INDEX idx_media_user_id ON media (user_id);
+CREATE INDEX idx_media_created_at ON media (created_at);
@@ -0,0 +1,8 @@
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at('view_count', 'tip_count');
+
+ALTER TABLE posts
+    DROP COLUMN word_count,
+    DROP COLUMN reading_time_minutes,
+    DROP COLUMN excerpt;
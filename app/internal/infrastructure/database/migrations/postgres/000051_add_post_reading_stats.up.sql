@@ -0,0 +1,12 @@
+-- Word count, estimated reading time and excerpt of the content, kept for
+-- post listings; posts written before these columns existed have a reading
+-- time of 0 until the server computes them on startup
+ALTER TABLE posts
+    ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reading_time_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN excerpt VARCHAR(300) NOT NULL DEFAULT '';
+
+-- The stats are derived from the content, so computing them is not an edit
+DROP TRIGGER posts_set_updated_at ON posts;
+CREATE TRIGGER posts_set_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at('view_count', 'tip_count', 'word_count', 'reading_time_minutes', 'excerpt');
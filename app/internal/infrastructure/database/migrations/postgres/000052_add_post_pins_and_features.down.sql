@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_featured_at;
+
+ALTER TABLE posts
+    DROP COLUMN pinned_at,
+    DROP COLUMN featured_at;
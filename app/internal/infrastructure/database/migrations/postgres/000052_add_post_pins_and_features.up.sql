@@ -0,0 +1,7 @@
+-- Admins pin posts to the top of the post list and feature posts in a
+-- curated list; the times order pinned and featured posts, latest first
+ALTER TABLE posts
+    ADD COLUMN pinned_at TIMESTAMPTZ NULL DEFAULT NULL,
+    ADD COLUMN featured_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+CREATE INDEX idx_posts_featured_at ON posts (featured_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_posts_series;
+
+ALTER TABLE posts
+    DROP CONSTRAINT fk_posts_series,
+    DROP COLUMN series_id,
+    DROP COLUMN series_position;
+
+DROP TABLE IF EXISTS series;
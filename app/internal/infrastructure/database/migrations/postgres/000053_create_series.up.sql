@@ -0,0 +1,24 @@
+-- Series group posts of an author in reading order
+CREATE TABLE series (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_series_author_id ON series (author_id);
+
+CREATE TRIGGER series_set_updated_at BEFORE UPDATE ON series
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- A post belongs to at most one series, at a position counted from 1;
+-- posts outside any series keep position 0
+ALTER TABLE posts
+    ADD COLUMN series_id INT NULL DEFAULT NULL,
+    ADD COLUMN series_position INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT fk_posts_series FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_posts_series ON posts (series_id, series_position);
@@ -0,0 +1,18 @@
+CREATE TABLE posts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL,
+    author_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_posts_author_id ON posts (author_id);
+CREATE INDEX idx_posts_created_at ON posts (created_at);
+
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
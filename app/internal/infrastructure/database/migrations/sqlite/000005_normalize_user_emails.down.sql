@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_email_normalized;
+
+ALTER TABLE users DROP COLUMN email_conflict;
+ALTER TABLE users DROP COLUMN email_normalized;
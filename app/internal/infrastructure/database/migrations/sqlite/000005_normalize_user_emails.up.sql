@@ -0,0 +1,23 @@
+-- Canonical email used for case-insensitive uniqueness checks and lookups
+ALTER TABLE users ADD COLUMN email_normalized VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN email_conflict BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Backfill: lowercase and trim every address
+UPDATE users SET email_normalized = LOWER(TRIM(email));
+
+-- Gmail ignores dots and plus-tags in the local part
+UPDATE users
+SET email_normalized = REPLACE(
+        CASE WHEN INSTR(SUBSTR(email_normalized, 1, INSTR(email_normalized, '@') - 1), '+') > 0
+            THEN SUBSTR(email_normalized, 1, INSTR(email_normalized, '+') - 1)
+            ELSE SUBSTR(email_normalized, 1, INSTR(email_normalized, '@') - 1)
+        END, '.', '') || '@gmail.com'
+WHERE SUBSTR(email_normalized, INSTR(email_normalized, '@') + 1) IN ('gmail.com', 'googlemail.com');
+
+-- Flag duplicates: the oldest account keeps the canonical address, later
+-- accounts sharing it are flagged for manual resolution
+UPDATE users
+SET email_conflict = TRUE, email_normalized = NULL
+WHERE id NOT IN (SELECT MIN(id) FROM users GROUP BY email_normalized);
+
+CREATE UNIQUE INDEX idx_users_email_normalized ON users (email_normalized);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_slug;
+
+ALTER TABLE posts
+    DROP COLUMN slug;
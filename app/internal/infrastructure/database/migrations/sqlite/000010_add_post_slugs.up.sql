@@ -0,0 +1,10 @@
+-- URL slug used by the HTML reading view (/p/:slug). SQLite cannot make an
+-- added column NOT NULL afterwards, so the application ensures every post
+-- has one.
+ALTER TABLE posts
+    ADD COLUMN slug VARCHAR(255) NULL;
+
+-- Backfill: existing posts get a slug derived from their ID
+UPDATE posts SET slug = 'post-' || id;
+
+CREATE UNIQUE INDEX idx_posts_slug ON posts (slug);
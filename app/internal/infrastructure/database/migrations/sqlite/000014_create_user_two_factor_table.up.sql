@@ -0,0 +1,16 @@
+-- TOTP enrollments; the secret is encrypted by the application
+CREATE TABLE user_two_factor (
+    user_id INT PRIMARY KEY,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    confirmed_at TIMESTAMP NULL DEFAULT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER user_two_factor_set_updated_at AFTER UPDATE ON user_two_factor
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE user_two_factor SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
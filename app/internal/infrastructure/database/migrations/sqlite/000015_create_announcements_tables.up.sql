@@ -0,0 +1,45 @@
+-- Announcement emails sent by administrators; the segment is stored as JSON
+CREATE TABLE announcements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subject VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    segment TEXT NOT NULL,
+    created_by INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    recipients INT NOT NULL DEFAULT 0,
+    sent INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TRIGGER announcements_set_updated_at AFTER UPDATE ON announcements
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE announcements SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- One row per recipient; pending recipients are the users without a row
+CREATE TABLE announcement_deliveries (
+    announcement_id INT NOT NULL,
+    user_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id),
+    FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_announcement_deliveries_status ON announcement_deliveries (announcement_id, status);
+
+-- Users who opted out of announcements through an unsubscribe link
+CREATE TABLE announcement_unsubscribes (
+    user_id INT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
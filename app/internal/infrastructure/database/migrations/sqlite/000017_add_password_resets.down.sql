@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS password_resets;
+
+ALTER TABLE users
+    DROP COLUMN password_reset_required_at;
@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN password_reset_required_at TIMESTAMP NULL DEFAULT NULL;
+
+CREATE TABLE password_resets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_password_resets_user_id ON password_resets (user_id);
+CREATE UNIQUE INDEX idx_password_resets_token_hash ON password_resets (token_hash);
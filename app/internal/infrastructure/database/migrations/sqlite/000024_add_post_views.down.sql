@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS post_daily_views;
+
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+ALTER TABLE posts DROP COLUMN view_count;
@@ -0,0 +1,22 @@
+-- Views are counted per day for ranking popular posts over a window, and
+-- in total on the post so showing a post needs no aggregation
+ALTER TABLE posts
+    ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0;
+
+-- Counting views is not an edit, so it leaves updated_at as is
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND NEW.view_count IS OLD.view_count
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE post_daily_views (
+    post_id INT NOT NULL,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_daily_views_day ON post_daily_views (day);
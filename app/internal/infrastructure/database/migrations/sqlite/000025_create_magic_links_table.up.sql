@@ -0,0 +1,18 @@
+-- Passwordless login links. Used links are kept with the device that used
+-- them, next to the device that requested them, for reviewing sign-ins
+CREATE TABLE magic_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    request_ip VARCHAR(45) NOT NULL DEFAULT '',
+    request_user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    used_ip VARCHAR(45) NOT NULL DEFAULT '',
+    used_user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_magic_links_user_id ON magic_links (user_id);
+CREATE UNIQUE INDEX idx_magic_links_token_hash ON magic_links (token_hash);
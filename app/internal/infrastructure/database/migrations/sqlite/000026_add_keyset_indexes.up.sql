@@ -0,0 +1,3 @@
+-- Keyset pagination orders posts and comments by (created_at, id)
+CREATE INDEX idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX idx_comments_post_created_at_id ON comments (post_id, created_at, id);
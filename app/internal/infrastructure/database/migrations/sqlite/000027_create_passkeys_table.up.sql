@@ -0,0 +1,17 @@
+-- WebAuthn credentials. The public key is stored in its COSE encoding; the
+-- user handle is the WebAuthn user ID shared by the passkeys of a user
+CREATE TABLE passkeys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INT NOT NULL,
+    credential_id BLOB NOT NULL,
+    user_handle BLOB NOT NULL,
+    public_key BLOB NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_passkeys_user_id ON passkeys (user_id);
+CREATE UNIQUE INDEX idx_passkeys_credential_id ON passkeys (credential_id);
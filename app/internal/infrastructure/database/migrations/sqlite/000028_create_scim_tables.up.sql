@@ -0,0 +1,51 @@
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP NULL DEFAULT NULL;
+
+-- Links users to the organization (tenant) that provisioned them over SCIM.
+-- External IDs are NULL when the identity provider sends none, so they do
+-- not collide.
+CREATE TABLE scim_accounts (
+    user_id INT PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL,
+    user_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_scim_accounts_user_name ON scim_accounts (tenant, user_name);
+CREATE UNIQUE INDEX idx_scim_accounts_external_id ON scim_accounts (tenant, external_id);
+
+CREATE TRIGGER scim_accounts_set_updated_at AFTER UPDATE ON scim_accounts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE scim_accounts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE scim_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant VARCHAR(64) NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_scim_groups_display_name ON scim_groups (tenant, display_name);
+
+CREATE TRIGGER scim_groups_set_updated_at AFTER UPDATE ON scim_groups
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE scim_groups SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TABLE scim_group_members (
+    group_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (group_id, user_id),
+    FOREIGN KEY (group_id) REFERENCES scim_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scim_group_members_user_id ON scim_group_members (user_id);
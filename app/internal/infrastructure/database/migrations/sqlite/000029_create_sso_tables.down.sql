@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS sso_memberships;
+DROP TABLE IF EXISTS sso_domains;
+DROP TABLE IF EXISTS sso_connections;
+
+DELETE FROM user_identities WHERE provider LIKE 'sso:%';
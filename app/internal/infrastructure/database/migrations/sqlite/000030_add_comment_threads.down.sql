@@ -0,0 +1,24 @@
+-- Replies become top-level comments again. SQLite cannot drop columns with
+-- foreign keys, so the table is rebuilt without them.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE comments_rebuild (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INT NOT NULL,
+    author_name VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+INSERT INTO comments_rebuild (id, post_id, author_name, content, created_at)
+SELECT id, post_id, author_name, content, created_at FROM comments;
+
+DROP TABLE comments;
+ALTER TABLE comments_rebuild RENAME TO comments;
+
+CREATE INDEX idx_comments_post_id ON comments (post_id);
+CREATE INDEX idx_comments_created_at ON comments (created_at);
+CREATE INDEX idx_comments_post_created_at_id ON comments (post_id, created_at, id);
+
+PRAGMA foreign_keys = ON;
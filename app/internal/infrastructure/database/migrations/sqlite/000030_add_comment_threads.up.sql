@@ -0,0 +1,12 @@
+-- Replies name the comment they answer and the top-level comment of their
+-- thread, so a page of threads loads with one query. Deleting a comment
+-- deletes the replies to it.
+ALTER TABLE comments
+    ADD COLUMN parent_id INT NULL DEFAULT NULL REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments
+    ADD COLUMN root_id INT NULL DEFAULT NULL REFERENCES comments(id) ON DELETE CASCADE;
+ALTER TABLE comments
+    ADD COLUMN depth SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_comments_post_roots ON comments (post_id, parent_id, created_at, id);
+CREATE INDEX idx_comments_root_created_at ON comments (root_id, created_at, id);
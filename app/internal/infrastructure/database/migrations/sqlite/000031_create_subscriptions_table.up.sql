@@ -0,0 +1,23 @@
+-- The Stripe subscription of each user. Users without a row are on the free
+-- plan; last_event_at orders the webhook events applied to a row.
+CREATE TABLE subscriptions (
+    user_id INT PRIMARY KEY,
+    plan VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    stripe_customer_id VARCHAR(255) NOT NULL,
+    stripe_subscription_id VARCHAR(255) NOT NULL,
+    current_period_end TIMESTAMP NULL DEFAULT NULL,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    last_event_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_subscriptions_stripe_customer ON subscriptions (stripe_customer_id);
+
+CREATE TRIGGER subscriptions_set_updated_at AFTER UPDATE ON subscriptions
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE subscriptions SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
@@ -0,0 +1,29 @@
+-- Comments go back to being identified by author name only. SQLite cannot
+-- drop a column with a foreign key, so the table is rebuilt without it.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE comments_rebuild (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INT NOT NULL,
+    author_name VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    parent_id INT NULL DEFAULT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    root_id INT NULL DEFAULT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    depth SMALLINT NOT NULL DEFAULT 0,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+INSERT INTO comments_rebuild (id, post_id, author_name, content, created_at, parent_id, root_id, depth)
+SELECT id, post_id, author_name, content, created_at, parent_id, root_id, depth FROM comments;
+
+DROP TABLE comments;
+ALTER TABLE comments_rebuild RENAME TO comments;
+
+CREATE INDEX idx_comments_post_id ON comments (post_id);
+CREATE INDEX idx_comments_created_at ON comments (created_at);
+CREATE INDEX idx_comments_post_created_at_id ON comments (post_id, created_at, id);
+CREATE INDEX idx_comments_post_roots ON comments (post_id, parent_id, created_at, id);
+CREATE INDEX idx_comments_root_created_at ON comments (root_id, created_at, id);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_status_created_at;
+
+ALTER TABLE comments
+    DROP COLUMN status;
@@ -0,0 +1,6 @@
+-- Comments can be held for moderation; only approved comments are public.
+-- Existing comments were public, so they are approved.
+ALTER TABLE comments
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'approved';
+
+CREATE INDEX idx_comments_status_created_at ON comments (status, created_at);
@@ -0,0 +1,10 @@
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND NEW.view_count IS OLD.view_count
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+ALTER TABLE posts DROP COLUMN tip_count;
+DROP TABLE IF EXISTS tips;
+DROP TABLE IF EXISTS payout_accounts;
@@ -0,0 +1,55 @@
+-- Accounts authors are paid tips out to, at the payment provider
+CREATE TABLE payout_accounts (
+    user_id INT PRIMARY KEY,
+    provider VARCHAR(32) NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER payout_accounts_set_updated_at AFTER UPDATE ON payout_accounts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE payout_accounts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- One-time payments to the author of a post. Tips are pending until the
+-- provider reports their payment; tips of deleted accounts become guest tips.
+CREATE TABLE tips (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INT NOT NULL,
+    author_id INT NOT NULL,
+    tipper_id INT NULL DEFAULT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tipper_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_tips_post_status ON tips (post_id, status);
+
+CREATE TRIGGER tips_set_updated_at AFTER UPDATE ON tips
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE tips SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- Succeeded tips per post, counted when their payment is reported; like
+-- views, counting them leaves updated_at as is
+ALTER TABLE posts
+    ADD COLUMN tip_count BIGINT NOT NULL DEFAULT 0;
+
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND NEW.view_count IS OLD.view_count
+        AND NEW.tip_count IS OLD.tip_count
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
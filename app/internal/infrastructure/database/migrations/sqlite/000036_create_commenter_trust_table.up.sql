@@ -0,0 +1,14 @@
+-- Trust levels of commenters, stored by the job evaluating them; commenters
+-- without a row are new
+CREATE TABLE commenter_trust (
+    user_id INT PRIMARY KEY,
+    level VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER commenter_trust_set_updated_at AFTER UPDATE ON commenter_trust
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE commenter_trust SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
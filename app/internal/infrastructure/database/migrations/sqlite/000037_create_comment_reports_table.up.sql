@@ -0,0 +1,15 @@
+-- Abuse reports on comments, one per reporter and comment. Reporters are
+-- told apart by reporter_key: the user ID of registered users, a hash of
+-- the client IP of guests.
+CREATE TABLE comment_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INT NOT NULL,
+    reporter_id INT NULL DEFAULT NULL,
+    reporter_key VARCHAR(80) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    details VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT uq_comment_reports_reporter UNIQUE (comment_id, reporter_key)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS banner_dismissals;
+DROP TABLE IF EXISTS banners;
@@ -0,0 +1,31 @@
+-- Site-wide banners shown to readers between starts_at and ends_at
+CREATE TABLE banners (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    message VARCHAR(500) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP NULL DEFAULT NULL,
+    dismissible BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_banners_schedule ON banners (starts_at, ends_at);
+
+CREATE TRIGGER banners_set_updated_at AFTER UPDATE ON banners
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE banners SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- Banners closed by users, so they are not shown to them again
+CREATE TABLE banner_dismissals (
+    banner_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (banner_id, user_id),
+    FOREIGN KEY (banner_id) REFERENCES banners(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
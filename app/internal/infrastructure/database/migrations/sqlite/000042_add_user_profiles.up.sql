@@ -0,0 +1,3 @@
+-- Public profile fields shown on author pages
+ALTER TABLE users ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '';
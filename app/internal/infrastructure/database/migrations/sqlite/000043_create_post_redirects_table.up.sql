@@ -0,0 +1,11 @@
+-- Old post slugs redirecting to the post, recorded on slug changes or added
+-- by admins
+CREATE TABLE post_redirects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    from_slug VARCHAR(255) NOT NULL,
+    post_id INT NOT NULL,
+    automatic BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_post_redirects_from_slug UNIQUE (from_slug),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS tombstones;
+DROP INDEX IF EXISTS idx_posts_updated_at_id;
+DROP TRIGGER IF EXISTS comments_set_updated_at;
+DROP TRIGGER IF EXISTS comments_default_updated_at;
+DROP INDEX IF EXISTS idx_comments_updated_at_id;
+ALTER TABLE comments DROP COLUMN updated_at;
@@ -0,0 +1,33 @@
+-- Offline clients sync the posts and comments changed since a checkpoint.
+-- Comments record when they last changed, and deleted posts and comments
+-- leave tombstones since their rows are gone. SQLite cannot add a column
+-- defaulting to the current time, so new comments get theirs from a
+-- trigger.
+ALTER TABLE comments
+    ADD COLUMN updated_at TIMESTAMP NULL DEFAULT NULL;
+UPDATE comments SET updated_at = created_at;
+
+CREATE INDEX idx_comments_updated_at_id ON comments (updated_at, id);
+
+CREATE TRIGGER comments_default_updated_at AFTER INSERT ON comments
+    FOR EACH ROW WHEN NEW.updated_at IS NULL
+BEGIN
+    UPDATE comments SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE TRIGGER comments_set_updated_at AFTER UPDATE ON comments
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE comments SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+CREATE INDEX idx_posts_updated_at_id ON posts (updated_at, id);
+
+CREATE TABLE tombstones (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tombstones_deleted_at_id ON tombstones (deleted_at, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_comments_client_id;
+ALTER TABLE comments DROP COLUMN client_id;
+DROP INDEX IF EXISTS uq_posts_client_id;
+ALTER TABLE posts DROP COLUMN client_id;
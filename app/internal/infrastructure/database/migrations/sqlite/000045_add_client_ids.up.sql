@@ -0,0 +1,10 @@
+-- Offline clients create posts and comments under IDs they generate, so
+-- retried writes are recognized and new resources can be referenced before
+-- the server assigns their IDs. Rows created online have none.
+ALTER TABLE posts
+    ADD COLUMN client_id CHAR(36) NULL;
+CREATE UNIQUE INDEX uq_posts_client_id ON posts (client_id);
+
+ALTER TABLE comments
+    ADD COLUMN client_id CHAR(36) NULL;
+CREATE UNIQUE INDEX uq_comments_client_id ON comments (client_id);
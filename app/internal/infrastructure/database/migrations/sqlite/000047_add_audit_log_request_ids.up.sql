@@ -0,0 +1,7 @@
+-- The ID of the request an audited action was made in, so support can
+-- find the entries of a request a user reports. Entries recorded outside
+-- requests, such as by background jobs, have none.
+ALTER TABLE audit_logs
+    ADD COLUMN request_id VARCHAR(128) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_audit_logs_request_id ON audit_logs (request_id);
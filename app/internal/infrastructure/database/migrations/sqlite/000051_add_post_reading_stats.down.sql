@@ -0,0 +1,11 @@
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND NEW.view_count IS OLD.view_count
+        AND NEW.tip_count IS OLD.tip_count
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+ALTER TABLE posts DROP COLUMN excerpt;
+ALTER TABLE posts DROP COLUMN reading_time_minutes;
+ALTER TABLE posts DROP COLUMN word_count;
@@ -0,0 +1,16 @@
+-- Word count, estimated reading time and excerpt of the content, kept for
+-- post listings; posts written before these columns existed have a reading
+-- time of 0 until the server computes them on startup
+ALTER TABLE posts ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN reading_time_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN excerpt VARCHAR(300) NOT NULL DEFAULT '';
+
+-- The stats are derived from the content, so computing them is not an edit
+DROP TRIGGER posts_set_updated_at;
+CREATE TRIGGER posts_set_updated_at AFTER UPDATE ON posts
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at AND NEW.view_count IS OLD.view_count
+        AND NEW.tip_count IS OLD.tip_count AND NEW.reading_time_minutes IS OLD.reading_time_minutes
+        AND NEW.word_count IS OLD.word_count AND NEW.excerpt IS OLD.excerpt
+BEGIN
+    UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_featured_at;
+
+ALTER TABLE posts DROP COLUMN featured_at;
+ALTER TABLE posts DROP COLUMN pinned_at;
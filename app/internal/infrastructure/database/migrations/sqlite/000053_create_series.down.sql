@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_posts_series;
+
+ALTER TABLE posts DROP COLUMN series_position;
+ALTER TABLE posts DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,27 @@
+-- Series group posts of an author in reading order
+CREATE TABLE series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    author_id INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_series_author_id ON series (author_id);
+
+CREATE TRIGGER series_set_updated_at AFTER UPDATE ON series
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE series SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+
+-- A post belongs to at most one series, at a position counted from 1;
+-- posts outside any series keep position 0. SQLite cannot drop a column
+-- with a foreign key, so series_id has none here; deleting a series
+-- clears it.
+ALTER TABLE posts ADD COLUMN series_id INT NULL DEFAULT NULL;
+ALTER TABLE posts ADD COLUMN series_position INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_posts_series ON posts (series_id, series_position);
@@ -0,0 +1,27 @@
+-- Posts are written in one language, English for existing posts, and may
+-- be translated to others; readers get the translation their
+-- Accept-Language prefers
+ALTER TABLE posts
+    ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT 'en';
+
+-- One translation per post and language. translated_by has no foreign key
+-- so translations survive the removal of a translator's account
+CREATE TABLE post_translations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id INT NOT NULL,
+    language VARCHAR(35) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL,
+    translated_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_post_translations_language ON post_translations (post_id, language);
+
+CREATE TRIGGER post_translations_set_updated_at AFTER UPDATE ON post_translations
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE post_translations SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
@@ -0,0 +1,206 @@
+// Package diagnostics gathers the runtime state of the subsystems into a
+// single report for incident triage
+package diagnostics
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+
+	"blog-platform/internal/infrastructure/config"
+)
+
+// sourceTimeout bounds each source, so a hanging subsystem cannot hold up
+// the report it is being diagnosed with
+const sourceTimeout = 2 * time.Second
+
+// Source reports the state of a subsystem as a JSON-encodable value
+type Source func(ctx context.Context) (any, error)
+
+// Collector aggregates the sources registered by the subsystems
+type Collector struct {
+	started time.Time
+
+	mu      sync.Mutex
+	names   []string
+	sources map[string]Source
+}
+
+// Report is a snapshot of the application's state
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Uptime      string    `json:"uptime"`
+	Runtime     Runtime   `json:"runtime"`
+	// Subsystems holds the report of every source that succeeded
+	Subsystems map[string]any `json:"subsystems"`
+	// Errors holds the error of every source that failed
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Runtime describes the Go runtime of the process
+type Runtime struct {
+	GoVersion  string `json:"go_version"`
+	Goroutines int    `json:"goroutines"`
+	CPUs       int    `json:"cpus"`
+	Memory     Memory `json:"memory"`
+}
+
+// Memory summarizes the memory statistics of the Go runtime, in bytes
+type Memory struct {
+	HeapAlloc    uint64 `json:"heap_alloc"`
+	HeapInuse    uint64 `json:"heap_inuse"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	StackInuse   uint64 `json:"stack_inuse"`
+	Sys          uint64 `json:"sys"`
+	TotalAlloc   uint64 `json:"total_alloc"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"gc_pause_total_ns"`
+}
+
+// NewCollector creates a collector; uptime is counted from its creation
+func NewCollector() *Collector {
+	return &Collector{
+		started: time.Now(),
+		sources: make(map[string]Source),
+	}
+}
+
+// Register adds the source of a subsystem, replacing an earlier source
+// with the same name
+func (c *Collector) Register(name string, source Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.sources[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.sources[name] = source
+}
+
+// Collect queries every source. A failing source is reported in Errors
+// without affecting the others.
+func (c *Collector) Collect(ctx context.Context) Report {
+	c.mu.Lock()
+	names := append([]string(nil), c.names...)
+	sources := make(map[string]Source, len(c.sources))
+	for name, source := range c.sources {
+		sources[name] = source
+	}
+	c.mu.Unlock()
+
+	now := time.Now()
+	report := Report{
+		GeneratedAt: now,
+		Uptime:      now.Sub(c.started).Round(time.Second).String(),
+		Runtime:     ReadRuntime(),
+		Subsystems:  make(map[string]any, len(names)),
+	}
+
+	for _, name := range names {
+		sourceCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+		value, err := sources[name](sourceCtx)
+		cancel()
+		if err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string)
+			}
+			report.Errors[name] = err.Error()
+			continue
+		}
+		report.Subsystems[name] = value
+	}
+	return report
+}
+
+// ReadRuntime reads the goroutine count and memory statistics
+func ReadRuntime() Runtime {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return Runtime{
+		GoVersion:  runtime.Version(),
+		Goroutines: runtime.NumGoroutine(),
+		CPUs:       runtime.NumCPU(),
+		Memory: Memory{
+			HeapAlloc:    m.HeapAlloc,
+			HeapInuse:    m.HeapInuse,
+			HeapObjects:  m.HeapObjects,
+			StackInuse:   m.StackInuse,
+			Sys:          m.Sys,
+			TotalAlloc:   m.TotalAlloc,
+			NumGC:        m.NumGC,
+			PauseTotalNs: m.PauseTotalNs,
+		},
+	}
+}
+
+// DBPool describes the database connection pool
+type DBPool struct {
+	MaxOpen int `json:"max_open"`
+	Open    int `json:"open"`
+	InUse   int `json:"in_use"`
+	Idle    int `json:"idle"`
+	// Utilization is the share of the maximum connections in use; zero
+	// without a maximum
+	Utilization float64 `json:"utilization"`
+	// WaitCount and WaitDuration show how often queries waited for a free
+	// connection since start
+	WaitCount         int64  `json:"wait_count"`
+	WaitDuration      string `json:"wait_duration"`
+	MaxIdleClosed     int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed int64  `json:"max_lifetime_closed"`
+}
+
+// DBPoolSource reports the connection pool statistics of a database
+func DBPoolSource(db *sql.DB) Source {
+	return func(ctx context.Context) (any, error) {
+		stats := db.Stats()
+		pool := DBPool{
+			MaxOpen:           stats.MaxOpenConnections,
+			Open:              stats.OpenConnections,
+			InUse:             stats.InUse,
+			Idle:              stats.Idle,
+			WaitCount:         stats.WaitCount,
+			WaitDuration:      stats.WaitDuration.String(),
+			MaxIdleClosed:     stats.MaxIdleClosed,
+			MaxLifetimeClosed: stats.MaxLifetimeClosed,
+		}
+		if stats.MaxOpenConnections > 0 {
+			pool.Utilization = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		}
+		return pool, nil
+	}
+}
+
+// ConfigFingerprints returns a short hash of every configuration section,
+// so instances running with different settings stand out. Secrets are
+// redacted before hashing: a changed secret only shows when it is set or
+// unset, and the hashes cannot be used to guess secrets.
+func ConfigFingerprints(cfg *config.Config) (map[string]string, error) {
+	redacted := reflect.ValueOf(cfg.Redacted())
+	fingerprints := make(map[string]string, redacted.NumField())
+
+	for i := 0; i < redacted.NumField(); i++ {
+		data, err := json.Marshal(redacted.Field(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s configuration: %w", redacted.Type().Field(i).Name, err)
+		}
+		sum := sha256.Sum256(data)
+		fingerprints[redacted.Type().Field(i).Name] = hex.EncodeToString(sum[:6])
+	}
+	return fingerprints, nil
+}
+
+// ConfigSource reports the configuration fingerprints
+func ConfigSource(cfg *config.Config) Source {
+	return func(ctx context.Context) (any, error) {
+		return ConfigFingerprints(cfg)
+	}
+}
@@ -0,0 +1,122 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+)
+
+// AtomContentType is the media type Atom feeds are served with
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+// Format is the syndication format a feed is rendered in
+type Format string
+
+// Feed formats
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+)
+
+// ContentType returns the media type feeds of the format are served with
+func (f Format) ContentType() string {
+	if f == FormatAtom {
+		return AtomContentType
+	}
+	return ContentType
+}
+
+// render encodes the channel in the format
+func (f Format) render(ch Channel) ([]byte, error) {
+	if f == FormatAtom {
+		return RenderAtom(ch)
+	}
+	return Render(ch)
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RenderAtom encodes the channel as an Atom 1.0 document. The feed is
+// identified by its self URL, falling back to its link, and updated when
+// its newest item was published; empty feeds are updated now. Item
+// descriptions are HTML content.
+func RenderAtom(ch Channel) ([]byte, error) {
+	doc := atomFeed{
+		ID:       ch.SelfURL,
+		Title:    ch.Title,
+		Subtitle: ch.Description,
+		Links:    []atomLink{{Href: ch.Link, Rel: "alternate", Type: "text/html"}},
+		Updated:  time.Now().UTC().Format(time.RFC3339),
+		Entries:  make([]atomEntry, 0, len(ch.Items)),
+	}
+	if ch.SelfURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: ch.SelfURL, Rel: "self", Type: "application/atom+xml"})
+	} else {
+		doc.ID = ch.Link
+	}
+	if len(ch.Items) > 0 {
+		// Items are newest first
+		doc.Updated = ch.Items[0].PublishedAt.UTC().Format(time.RFC3339)
+	}
+	for _, item := range ch.Items {
+		published := item.PublishedAt.UTC().Format(time.RFC3339)
+		entry := atomEntry{
+			ID:         item.GUID,
+			Title:      item.Title,
+			Links:      []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Categories: make([]atomCategory, 0, len(item.Categories)),
+			Published:  published,
+			Updated:    published,
+			Content:    atomContent{Type: "html", Value: item.Description},
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
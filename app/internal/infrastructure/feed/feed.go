@@ -0,0 +1,403 @@
+// Package feed generates RSS and Atom feeds so readers can follow posts and
+// discussions in feed readers
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+const (
+	// maxCacheEntries bounds the number of rendered feeds kept in memory
+	maxCacheEntries = 1000
+	// itemTitleLength is the maximum length of comment excerpts in titles
+	itemTitleLength = 80
+)
+
+// Settings configures the generated feeds
+type Settings struct {
+	SiteName string
+	// BaseURL is the public URL posts are served under at /p/{slug}
+	BaseURL string
+	// ItemLimit caps the number of items per feed; the comment service caps
+	// it further at the maximum comment page size
+	ItemLimit int
+	// CacheTTL is how long a rendered feed is served before it is rebuilt;
+	// zero disables caching
+	CacheTTL time.Duration
+}
+
+// Generator builds post and comment feeds and caches the rendered documents
+type Generator struct {
+	posts       post.Service
+	comments    comment.Service
+	users       user.Service
+	preferences preference.Service
+	settings    Settings
+
+	mu     sync.Mutex
+	cache  map[string]cacheEntry
+	hits   uint64
+	misses uint64
+}
+
+// PostFilter selects the posts of a post feed; zero fields match every
+// published post
+type PostFilter struct {
+	Tag      string
+	AuthorID int
+}
+
+// CacheStats reports how well the rendered feed cache is working
+type CacheStats struct {
+	Enabled bool    `json:"enabled"`
+	Entries int     `json:"entries"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewGenerator creates a new feed generator
+func NewGenerator(posts post.Service, comments comment.Service, users user.Service, preferences preference.Service, settings Settings) *Generator {
+	if settings.ItemLimit <= 0 {
+		settings.ItemLimit = 50
+	}
+	settings.BaseURL = strings.TrimRight(settings.BaseURL, "/")
+
+	return &Generator{
+		posts:       posts,
+		comments:    comments,
+		users:       users,
+		preferences: preferences,
+		settings:    settings,
+		cache:       make(map[string]cacheEntry),
+	}
+}
+
+// CacheTTL returns how long clients may cache a feed
+func (g *Generator) CacheTTL() time.Duration {
+	return g.settings.CacheTTL
+}
+
+// CacheStats returns the hit rate and size of the rendered feed cache
+func (g *Generator) CacheStats() CacheStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := CacheStats{
+		Enabled: g.settings.CacheTTL > 0,
+		Entries: len(g.cache),
+		Hits:    g.hits,
+		Misses:  g.misses,
+	}
+	if total := g.hits + g.misses; total > 0 {
+		stats.HitRate = float64(g.hits) / float64(total)
+	}
+	return stats
+}
+
+// Posts returns the feed of the published posts matching the filter in the
+// given format. The feeds of a single author or tag use the title and
+// description chosen in their feed preference.
+func (g *Generator) Posts(ctx context.Context, filter PostFilter, format Format) ([]byte, error) {
+	if filter.Tag != "" {
+		normalized, err := tag.Normalize(filter.Tag)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tag = normalized
+	}
+
+	return g.cached(fmt.Sprintf("posts:%s:%d:%s", format, filter.AuthorID, filter.Tag), func() ([]byte, error) {
+		ch := Channel{
+			Title:       g.settings.SiteName,
+			Link:        g.settings.BaseURL + "/",
+			Description: fmt.Sprintf("The latest posts on %s", g.settings.SiteName),
+			SelfURL:     g.apiURL(postFeedPath(filter, format)),
+		}
+
+		var author *user.User
+		if filter.AuthorID > 0 {
+			var err error
+			if author, err = g.users.GetByID(ctx, filter.AuthorID); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case author != nil && filter.Tag != "":
+			ch.Title = fmt.Sprintf("Posts tagged %s by %s", filter.Tag, author.Name)
+			ch.Description = fmt.Sprintf("The latest posts tagged %s by %s on %s", filter.Tag, author.Name, g.settings.SiteName)
+		case author != nil:
+			ch.Title = fmt.Sprintf("Posts by %s", author.Name)
+			ch.Description = fmt.Sprintf("The latest posts by %s on %s", author.Name, g.settings.SiteName)
+			pref, err := g.preferences.AuthorFeed(ctx, author.ID)
+			if err != nil {
+				return nil, err
+			}
+			customize(&ch, pref)
+		case filter.Tag != "":
+			ch.Title = fmt.Sprintf("Posts tagged %s", filter.Tag)
+			ch.Description = fmt.Sprintf("The latest posts tagged %s on %s", filter.Tag, g.settings.SiteName)
+			pref, err := g.preferences.TagFeed(ctx, filter.Tag)
+			if err != nil {
+				return nil, err
+			}
+			customize(&ch, pref)
+		}
+
+		posts, err := g.posts.ListPublishedPosts(ctx, post.PublishedFilter{Tag: filter.Tag, AuthorID: filter.AuthorID}, g.settings.ItemLimit, 0)
+		if err != nil {
+			return nil, err
+		}
+		// Feeds are cached and fetched without credentials, so restricted
+		// posts always carry their teaser
+		posts = g.posts.GatePosts(ctx, 0, "", posts)
+
+		authors := make(map[int]string)
+		if author != nil {
+			authors[author.ID] = author.Name
+		}
+		for _, p := range posts {
+			name, ok := authors[p.AuthorID]
+			if !ok {
+				a, err := g.users.GetByID(ctx, p.AuthorID)
+				if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+					return nil, err
+				}
+				// Posts of removed accounts are listed without an author
+				if a != nil {
+					name = a.Name
+				}
+				authors[p.AuthorID] = name
+			}
+
+			link := g.postURL(p)
+			item := Item{
+				Title:       p.Title,
+				Link:        link,
+				GUID:        link,
+				Author:      name,
+				Description: markdown.Render(p.Content),
+				Categories:  p.Tags,
+			}
+			if p.PublishedAt != nil {
+				item.PublishedAt = *p.PublishedAt
+			}
+			ch.Items = append(ch.Items, item)
+		}
+
+		return format.render(ch)
+	})
+}
+
+// PostComments returns the comment feed of a post
+func (g *Generator) PostComments(ctx context.Context, postID int) ([]byte, error) {
+	return g.cached(fmt.Sprintf("post:%d", postID), func() ([]byte, error) {
+		p, err := g.posts.GetVisiblePost(ctx, 0, "", postID)
+		if err != nil {
+			return nil, err
+		}
+
+		comments, err := g.comments.GetRecentComments(ctx, comment.RecentFilter{PostID: postID}, g.settings.ItemLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		posts := map[int]*post.Post{p.ID: p}
+		return Render(Channel{
+			Title:       fmt.Sprintf("Comments on %s", p.Title),
+			Link:        g.postURL(p),
+			Description: fmt.Sprintf("The latest comments on %s on %s", p.Title, g.settings.SiteName),
+			SelfURL:     g.apiURL(fmt.Sprintf("/api/v1/posts/%d/comments/feed.xml", p.ID)),
+			Items:       g.items(comments, posts),
+		})
+	})
+}
+
+// AuthorComments returns the feed of comments on all posts of an author
+func (g *Generator) AuthorComments(ctx context.Context, authorID int) ([]byte, error) {
+	return g.cached(fmt.Sprintf("author:%d", authorID), func() ([]byte, error) {
+		author, err := g.users.GetByID(ctx, authorID)
+		if err != nil {
+			return nil, err
+		}
+
+		comments, err := g.comments.GetRecentComments(ctx, comment.RecentFilter{PostAuthorID: authorID}, g.settings.ItemLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		posts := make(map[int]*post.Post)
+		for _, c := range comments {
+			if _, ok := posts[c.PostID]; ok {
+				continue
+			}
+			p, err := g.posts.GetVisiblePost(ctx, 0, "", c.PostID)
+			if err != nil {
+				return nil, err
+			}
+			posts[c.PostID] = p
+		}
+
+		return Render(Channel{
+			Title:       fmt.Sprintf("Comments on posts by %s", author.Name),
+			Link:        g.settings.BaseURL + "/",
+			Description: fmt.Sprintf("The latest comments on posts by %s on %s", author.Name, g.settings.SiteName),
+			SelfURL:     g.apiURL(fmt.Sprintf("/api/v1/users/%d/comments/feed.xml", author.ID)),
+			Items:       g.items(comments, posts),
+		})
+	})
+}
+
+// items converts comments into feed items linking to their posts
+func (g *Generator) items(comments []*comment.Comment, posts map[int]*post.Post) []Item {
+	items := make([]Item, 0, len(comments))
+	for _, c := range comments {
+		p := posts[c.PostID]
+		link := fmt.Sprintf("%s#comment-%d", g.postURL(p), c.ID)
+		items = append(items, Item{
+			Title:       fmt.Sprintf("%s on %s: %s", c.AuthorName, p.Title, excerpt(c.Content)),
+			Link:        link,
+			GUID:        link,
+			Author:      c.AuthorName,
+			Description: c.Content,
+			PublishedAt: c.CreatedAt,
+		})
+	}
+	return items
+}
+
+// cached returns the cached feed for the key, building and storing it when
+// it is missing or expired. Errors are not cached.
+func (g *Generator) cached(key string, build func() ([]byte, error)) ([]byte, error) {
+	if g.settings.CacheTTL <= 0 {
+		return build()
+	}
+
+	now := time.Now()
+	g.mu.Lock()
+	entry, ok := g.cache[key]
+	fresh := ok && now.Before(entry.expiresAt)
+	if fresh {
+		g.hits++
+	} else {
+		g.misses++
+	}
+	g.mu.Unlock()
+	if fresh {
+		return entry.body, nil
+	}
+
+	body, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.cache) >= maxCacheEntries {
+		g.evictExpired(now)
+	}
+	if len(g.cache) >= maxCacheEntries {
+		// Every entry is fresh; start over rather than grow without bound
+		g.cache = make(map[string]cacheEntry)
+	}
+	g.cache[key] = cacheEntry{body: body, expiresAt: now.Add(g.settings.CacheTTL)}
+	return body, nil
+}
+
+// evictExpired removes expired entries; the caller must hold the lock
+func (g *Generator) evictExpired(now time.Time) {
+	for key, entry := range g.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(g.cache, key)
+		}
+	}
+}
+
+// postURL returns the public reading view URL of a post
+func (g *Generator) postURL(p *post.Post) string {
+	return g.settings.BaseURL + "/p/" + url.PathEscape(p.Slug)
+}
+
+// apiURL returns the absolute URL of an API path
+func (g *Generator) apiURL(path string) string {
+	if g.settings.BaseURL == "" {
+		return ""
+	}
+	return g.settings.BaseURL + path
+}
+
+// postFeedPath returns the path a post feed is served at. RSS feeds of a
+// single author or tag have their own URL, Atom feeds of a single author
+// too; other filters use query parameters.
+func postFeedPath(filter PostFilter, format Format) string {
+	if format == FormatAtom {
+		if filter.AuthorID > 0 && filter.Tag == "" {
+			return fmt.Sprintf("/feeds/authors/%d.atom", filter.AuthorID)
+		}
+		query := url.Values{}
+		if filter.AuthorID > 0 {
+			query.Set("author", fmt.Sprint(filter.AuthorID))
+		}
+		if filter.Tag != "" {
+			query.Set("tag", filter.Tag)
+		}
+		if len(query) == 0 {
+			return "/feeds/posts.atom"
+		}
+		return "/feeds/posts.atom?" + query.Encode()
+	}
+
+	switch {
+	case filter.AuthorID > 0 && filter.Tag != "":
+		query := url.Values{"author": {fmt.Sprint(filter.AuthorID)}, "tag": {filter.Tag}}
+		return "/feed.xml?" + query.Encode()
+	case filter.AuthorID > 0:
+		return fmt.Sprintf("/api/v1/users/%d/feed.xml", filter.AuthorID)
+	case filter.Tag != "":
+		return "/api/v1/tags/" + url.PathEscape(filter.Tag) + "/feed.xml"
+	default:
+		return "/feed.xml"
+	}
+}
+
+// customize replaces the generated title and description of a channel with
+// those set in a feed preference
+func customize(ch *Channel, pref *preference.Feed) {
+	if pref.Title != "" {
+		ch.Title = pref.Title
+	}
+	if pref.Description != "" {
+		ch.Description = pref.Description
+	}
+}
+
+// excerpt returns the start of a comment as a single line for item titles
+func excerpt(content string) string {
+	text := strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(text) <= itemTitleLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:itemTitleLength]) + "…"
+}
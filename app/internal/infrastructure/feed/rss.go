@@ -0,0 +1,115 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+)
+
+// ContentType is the media type RSS feeds are served with
+const ContentType = "application/rss+xml; charset=utf-8"
+
+// Channel is a feed, rendered as an RSS 2.0 channel or an Atom feed
+type Channel struct {
+	Title       string
+	Link        string
+	Description string
+	// SelfURL is the URL the feed itself is served at
+	SelfURL string
+	Items   []Item
+}
+
+// Item is an entry of a channel
+type Item struct {
+	Title       string
+	Link        string
+	GUID        string
+	Author      string
+	Description string
+	// Categories are the tags of the item
+	Categories  []string
+	PublishedAt time.Time
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      *atomLink `xml:"atom:link,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	Author      string   `xml:"dc:creator,omitempty"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+// Render encodes the channel as an RSS 2.0 document. Item authors are
+// written as dc:creator because RSS author elements require an email
+// address.
+func Render(ch Channel) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       ch.Title,
+			Link:        ch.Link,
+			Description: ch.Description,
+			Items:       make([]rssItem, 0, len(ch.Items)),
+		},
+	}
+	if ch.SelfURL != "" {
+		doc.Channel.AtomLink = &atomLink{Href: ch.SelfURL, Rel: "self", Type: "application/rss+xml"}
+	}
+	if len(ch.Items) > 0 {
+		// Items are newest first
+		doc.Channel.LastBuildDate = ch.Items[0].PublishedAt.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range ch.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.GUID, IsPermaLink: item.GUID == item.Link},
+			Author:      item.Author,
+			Description: item.Description,
+			Categories:  item.Categories,
+			PubDate:     item.PublishedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,163 @@
+// Package health checks the dependencies an instance needs to serve
+// requests, for the readiness probes of orchestrators
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"blog-platform/internal/infrastructure/database/migrations"
+)
+
+// checkTimeout bounds each check, so a hanging dependency fails its check
+// instead of the probe timing out; probes should allow a little more
+const checkTimeout = time.Second
+
+// Statuses of checks and reports
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Check reports whether a dependency can serve requests
+type Check func(ctx context.Context) error
+
+// Checker runs the checks registered for the dependencies
+type Checker struct {
+	mu     sync.Mutex
+	names  []string
+	checks map[string]Check
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status    string  `json:"status" example:"ok"`
+	LatencyMs float64 `json:"latency_ms" example:"1.25"`
+	// Error tells why a check failed: "timeout" or "unavailable"
+	Error string `json:"error,omitempty" example:"timeout"`
+	// err is the error of a failed check, to be logged
+	err error
+}
+
+// Err returns the error of a failed check
+func (r Result) Err() error {
+	return r.err
+}
+
+// Report is the outcome of every check; its status is ok only when every
+// check passed
+type Report struct {
+	Status string            `json:"status" example:"ok"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every check passed
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// NewChecker creates a checker without checks
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Register adds the check of a dependency, replacing an earlier check with
+// the same name
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Check runs every check at once and reports their outcome
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.Unlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusError
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// run runs a check within checkTimeout and times it
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := Result{Status: StatusOK, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status, result.Error, result.err = StatusError, "unavailable", err
+		if ctx.Err() != nil {
+			result.Error = "timeout"
+		}
+	}
+	return result
+}
+
+// DBCheck pings a database
+func DBCheck(db *sql.DB) Check {
+	return db.PingContext
+}
+
+// MigrationsCheck fails until every migration embedded in the binary is
+// applied, e.g. while another instance is still running them. Once they
+// all are, the schema cannot fall behind the binary again, so later checks
+// pass without querying the database.
+func MigrationsCheck(db *sql.DB, driver string) Check {
+	var applied atomic.Bool
+	return func(ctx context.Context) error {
+		if applied.Load() {
+			return nil
+		}
+		migrator, err := migrations.New(db, driver)
+		if err != nil {
+			return err
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, status := range statuses {
+			if status.Dirty {
+				return fmt.Errorf("migration %s is dirty", status.Migration)
+			}
+			if !status.Applied {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d migrations are not applied", pending)
+		}
+		applied.Store(true)
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import "net/http"
+
+// ErrorDefinition describes an error code clients can receive from the API
+type ErrorDefinition struct {
+	Code        ErrorCode `json:"code"`
+	Status      int       `json:"status"`
+	Description string    `json:"description"`
+}
+
+// catalog lists every error code in the order it is documented
+var catalog = []ErrorDefinition{
+	{ErrCodeValidation, http.StatusBadRequest, "The request body or parameters failed validation; see details for the offending fields"},
+	{ErrCodeInvalidRequest, http.StatusBadRequest, "The request could not be parsed"},
+	{ErrCodeUnauthorized, http.StatusUnauthorized, "Authentication is missing, invalid, expired or revoked"},
+	{ErrCodeInvalidCredentials, http.StatusUnauthorized, "The email or password is incorrect"},
+	{ErrCodeForbidden, http.StatusForbidden, "The authenticated user is not allowed to perform this action"},
+	{ErrCodeNotFound, http.StatusNotFound, "The requested resource does not exist"},
+	{ErrCodeConflict, http.StatusConflict, "The request conflicts with the current state of a resource"},
+	{ErrCodeUserExists, http.StatusConflict, "A user with this email address already exists"},
+	{ErrCodeRateLimitExceeded, http.StatusTooManyRequests, "Too many requests; retry after the rate limit window"},
+	{ErrCodeAccountLocked, http.StatusLocked, "Logins are locked after too many failed attempts; retry after the time in the Retry-After header"},
+	{ErrCodePasswordResetRequired, http.StatusForbidden, "The account was locked after a change was reported as unauthorized; reset the password with the emailed link"},
+	{ErrCodeAccountDeactivated, http.StatusForbidden, "The account was turned off by the identity provider of its organization"},
+	{ErrCodeSSORequired, http.StatusForbidden, "The organization of the account requires signing in through its single sign-on; start at /api/v1/auth/sso"},
+	{ErrCodePlanLimitReached, http.StatusForbidden, "The plan of the account does not allow this; upgrade the subscription to raise the limit"},
+	{ErrCodePayloadTooLarge, http.StatusRequestEntityTooLarge, "The request body or uploaded file is larger than the endpoint accepts"},
+	{ErrCodeQuotaExceeded, http.StatusForbidden, "The upload would take the account over its storage quota"},
+	{ErrCodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "The Idempotency-Key was already sent with a different request; use a new key for a new request"},
+	{ErrCodeIdempotencyKeyInUse, http.StatusConflict, "The first request sent with the Idempotency-Key is still being processed; retry once it completes"},
+	{ErrCodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "The request or uploaded file is not of a type the endpoint accepts"},
+	{ErrCodeInternal, http.StatusInternalServerError, "An unexpected server error occurred"},
+	{ErrCodeDatabase, http.StatusInternalServerError, "The database could not complete the request"},
+	{ErrCodeService, http.StatusInternalServerError, "A downstream service could not complete the request"},
+}
+
+// Catalog returns the definitions of all error codes
+func Catalog() []ErrorDefinition {
+	definitions := make([]ErrorDefinition, len(catalog))
+	copy(definitions, catalog)
+	return definitions
+}
+
+// LookupErrorCode returns the definition of an error code
+func LookupErrorCode(code ErrorCode) (ErrorDefinition, bool) {
+	for _, definition := range catalog {
+		if definition.Code == code {
+			return definition, true
+		}
+	}
+	return ErrorDefinition{}, false
+}
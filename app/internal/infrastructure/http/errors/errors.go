@@ -1,8 +1,10 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -23,6 +25,16 @@ const (
 	ErrCodeUserExists     ErrorCode = "user_exists"
 	ErrCodeInvalidCredentials ErrorCode = "invalid_credentials"
 	ErrCodeRateLimitExceeded ErrorCode = "rate_limit_exceeded"
+	ErrCodeAccountLocked  ErrorCode = "account_locked"
+	ErrCodePasswordResetRequired ErrorCode = "password_reset_required"
+	ErrCodeAccountDeactivated ErrorCode = "account_deactivated"
+	ErrCodeSSORequired    ErrorCode = "sso_required"
+	ErrCodePlanLimitReached ErrorCode = "plan_limit_reached"
+	ErrCodePayloadTooLarge ErrorCode = "payload_too_large"
+	ErrCodeUnsupportedMediaType ErrorCode = "unsupported_media_type"
+	ErrCodeQuotaExceeded  ErrorCode = "quota_exceeded"
+	ErrCodeIdempotencyKeyReused ErrorCode = "idempotency_key_reused"
+	ErrCodeIdempotencyKeyInUse ErrorCode = "idempotency_key_in_use"
 	
 	// Server errors (5xx)
 	ErrCodeInternal       ErrorCode = "internal_error"
@@ -36,6 +48,16 @@ type APIError struct {
 	Message    string    `json:"message"`
 	Details    []string  `json:"details,omitempty"`
 	StatusCode int       `json:"-"`
+	// violations are the failed validation rules behind the details, so
+	// they can be described in the language of the client
+	violations []violation
+}
+
+// violation is a validation rule a request field failed
+type violation struct {
+	field string
+	tag   string
+	param string
 }
 
 // Error implements the error interface
@@ -48,6 +70,9 @@ type ErrorResponse struct {
 	Error   string   `json:"error"`
 	Message string   `json:"message"`
 	Details []string `json:"details,omitempty"`
+	// RequestID is the X-Request-ID of the response, for support to find
+	// the request in the logs
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // NewAPIError creates a new API error
@@ -62,11 +87,13 @@ func NewAPIError(code ErrorCode, message string, statusCode int) *APIError {
 // NewValidationError creates a validation error from validator errors
 func NewValidationError(err error) *APIError {
 	var details []string
+	var violations []violation
 	
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldError := range validationErrors {
 			detail := formatValidationError(fieldError)
 			details = append(details, detail)
+			violations = append(violations, violation{fieldError.Field(), fieldError.Tag(), fieldError.Param()})
 		}
 	} else {
 		details = append(details, err.Error())
@@ -77,6 +104,7 @@ func NewValidationError(err error) *APIError {
 		Message:    "Request validation failed",
 		Details:    details,
 		StatusCode: http.StatusBadRequest,
+		violations: violations,
 	}
 }
 
@@ -86,14 +114,34 @@ func NewDomainError(err error) *APIError {
 	
 	// Map common domain errors to appropriate HTTP status codes
 	switch {
+	case strings.Contains(message, "password reset required"):
+		return NewAPIError(ErrCodePasswordResetRequired, message, http.StatusForbidden)
+	case strings.Contains(message, "account deactivated"):
+		return NewAPIError(ErrCodeAccountDeactivated, message, http.StatusForbidden)
+	case strings.Contains(message, "sso required"):
+		return NewAPIError(ErrCodeSSORequired, message, http.StatusForbidden)
+	case strings.Contains(message, "plan limit reached"):
+		return NewAPIError(ErrCodePlanLimitReached, message, http.StatusForbidden)
+	case strings.Contains(message, "storage quota exceeded"):
+		return NewAPIError(ErrCodeQuotaExceeded, message, http.StatusForbidden)
+	case strings.Contains(message, "comment cooldown"):
+		return NewAPIError(ErrCodeRateLimitExceeded, message, http.StatusTooManyRequests)
+	case strings.Contains(message, "idempotency key reused"):
+		return NewAPIError(ErrCodeIdempotencyKeyReused, message, http.StatusUnprocessableEntity)
+	case strings.Contains(message, "idempotency key in use"):
+		return NewAPIError(ErrCodeIdempotencyKeyInUse, message, http.StatusConflict)
 	case strings.Contains(message, "not found"):
 		return NewAPIError(ErrCodeNotFound, message, http.StatusNotFound)
 	case strings.Contains(message, "unauthorized") || strings.Contains(message, "forbidden"):
 		return NewAPIError(ErrCodeForbidden, message, http.StatusForbidden)
-	case strings.Contains(message, "already exists"):
+	case strings.Contains(message, "already exists") || strings.Contains(message, "already published"):
 		return NewAPIError(ErrCodeConflict, message, http.StatusConflict)
 	case strings.Contains(message, "invalid credentials"):
 		return NewAPIError(ErrCodeInvalidCredentials, message, http.StatusUnauthorized)
+	case strings.Contains(message, "refresh token") || strings.Contains(message, "magic link") ||
+		strings.Contains(message, "passkey challenge") || strings.Contains(message, "passkey assertion") ||
+		strings.Contains(message, "sso response"):
+		return NewAPIError(ErrCodeUnauthorized, message, http.StatusUnauthorized)
 	default:
 		return NewAPIError(ErrCodeValidation, message, http.StatusBadRequest)
 	}
@@ -138,7 +186,8 @@ var (
 	)
 )
 
-// HandleError handles different types of errors and returns appropriate HTTP responses
+// HandleError handles different types of errors and returns appropriate HTTP responses.
+// Messages are translated to the language the Accept-Language header prefers.
 func HandleError(c echo.Context, err error) error {
 	var apiErr *APIError
 	
@@ -156,43 +205,86 @@ func HandleError(c echo.Context, err error) error {
 		}
 	}
 	
+	localizer := NewLocalizer(c.Request().Header.Get(acceptLanguageHeader))
 	response := ErrorResponse{
-		Error:   string(apiErr.Code),
-		Message: apiErr.Message,
-		Details: apiErr.Details,
+		Error:     string(apiErr.Code),
+		Message:   localizer.Message(apiErr.Message),
+		Details:   localizer.details(apiErr),
+		RequestID: RequestID(c),
+	}
+	
+	header := c.Response().Header()
+	header.Set(contentLanguageHeader, localizer.Language())
+	if !slices.Contains(header.Values(echo.HeaderVary), acceptLanguageHeader) {
+		header.Add(echo.HeaderVary, acceptLanguageHeader)
 	}
 	
 	return c.JSON(apiErr.StatusCode, response)
 }
 
+// RequestID returns the ID the RequestID middleware gave the request, if any
+func RequestID(c echo.Context) string {
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
+
+// HTTPErrorHandler writes the errors handlers and middleware return instead
+// of responding themselves, such as unknown routes or panics, in the
+// standard error format
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var httpErr *echo.HTTPError
+	if stderrors.As(err, &httpErr) {
+		err = fromHTTPError(httpErr)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			apiErr = ErrInternal
+		}
+		err = c.NoContent(apiErr.StatusCode)
+	} else {
+		err = HandleError(c, err)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// fromHTTPError converts an Echo error to an API error with the same status.
+// Server error messages are not passed on, as they may reveal internals.
+func fromHTTPError(httpErr *echo.HTTPError) *APIError {
+	status := httpErr.Code
+	if status >= http.StatusInternalServerError {
+		return NewAPIError(ErrInternal.Code, ErrInternal.Message, status)
+	}
+
+	code := ErrCodeInvalidRequest
+	switch status {
+	case http.StatusUnauthorized:
+		code = ErrCodeUnauthorized
+	case http.StatusForbidden:
+		code = ErrCodeForbidden
+	case http.StatusNotFound:
+		code = ErrCodeNotFound
+	case http.StatusConflict:
+		code = ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		code = ErrCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = ErrCodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		code = ErrCodeRateLimitExceeded
+	}
+	return NewAPIError(code, fmt.Sprint(httpErr.Message), status)
+}
+
 // formatValidationError formats a single validation error into a human-readable message
 func formatValidationError(fieldError validator.FieldError) string {
-	field := fieldError.Field()
-	tag := fieldError.Tag()
-	param := fieldError.Param()
-	
-	switch tag {
-	case "required":
-		return fmt.Sprintf("%s is required", field)
-	case "email":
-		return fmt.Sprintf("%s must be a valid email address", field)
-	case "min":
-		return fmt.Sprintf("%s must be at least %s characters long", field, param)
-	case "max":
-		return fmt.Sprintf("%s cannot exceed %s characters", field, param)
-	case "len":
-		return fmt.Sprintf("%s must be exactly %s characters long", field, param)
-	case "gte":
-		return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
-	case "lte":
-		return fmt.Sprintf("%s must be less than or equal to %s", field, param)
-	case "gt":
-		return fmt.Sprintf("%s must be greater than %s", field, param)
-	case "lt":
-		return fmt.Sprintf("%s must be less than %s", field, param)
-	default:
-		return fmt.Sprintf("%s validation failed for tag '%s'", field, tag)
-	}
+	return NewLocalizer(DefaultLanguage).validation(violation{fieldError.Field(), fieldError.Tag(), fieldError.Param()})
 }
 
 // isDomainError checks if an error is from the domain layer
@@ -206,10 +298,21 @@ func isDomainError(err error) bool {
 		"cannot exceed",
 		"must be positive",
 		"already exists",
+		"already published",
 		"not found",
 		"unauthorized",
 		"forbidden",
 		"invalid",
+		"expired",
+		"must differ",
+		"password reset required",
+		"account deactivated",
+		"sso required",
+		"plan limit reached",
+		"storage quota exceeded",
+		"comment cooldown",
+		"idempotency key reused",
+		"idempotency key in use",
 	}
 	
 	for _, pattern := range domainPatterns {
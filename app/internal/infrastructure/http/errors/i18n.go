@@ -0,0 +1,182 @@
+package errors
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language error messages are written in, and the
+// last language of every fallback chain
+const DefaultLanguage = "en"
+
+// Headers clients negotiate the language of error messages with
+const (
+	acceptLanguageHeader  = "Accept-Language"
+	contentLanguageHeader = "Content-Language"
+)
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// messageCatalog holds the translations of error messages to one language.
+// Messages are keyed by their English text; validation messages by the
+// validator tag, with {field}, {param} and {tag} in place of the failed
+// field, the parameter of the tag and the tag itself.
+type messageCatalog struct {
+	Messages   map[string]string `json:"messages"`
+	Validation map[string]string `json:"validation"`
+}
+
+// locales are the embedded catalogs by language code; supported lists the
+// codes for the matcher, the default language first
+var (
+	locales   = loadLocales()
+	supported = supportedLanguages()
+	matcher   = language.NewMatcher(tags(supported))
+)
+
+// loadLocales reads the embedded catalogs, named after their language like
+// locales/es.json. Broken catalogs are a build mistake, so they panic.
+func loadLocales() map[string]*messageCatalog {
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic("errors: failed to read locales: " + err.Error())
+	}
+
+	loaded := make(map[string]*messageCatalog, len(files))
+	for _, file := range files {
+		data, err := localeFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic("errors: failed to read locale " + file.Name() + ": " + err.Error())
+		}
+		var c messageCatalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic("errors: invalid locale " + file.Name() + ": " + err.Error())
+		}
+		lang := language.Make(strings.TrimSuffix(file.Name(), path.Ext(file.Name())))
+		loaded[lang.String()] = &c
+	}
+	if _, ok := loaded[DefaultLanguage]; !ok {
+		panic("errors: missing locale " + DefaultLanguage)
+	}
+	return loaded
+}
+
+// supportedLanguages lists the languages of the catalogs, the default first
+// so the matcher falls back to it
+func supportedLanguages() []string {
+	languages := []string{DefaultLanguage}
+	for lang := range locales {
+		if lang != DefaultLanguage {
+			languages = append(languages, lang)
+		}
+	}
+	slices.Sort(languages[1:])
+	return languages
+}
+
+// tags parses language codes into tags
+func tags(languages []string) []language.Tag {
+	parsed := make([]language.Tag, len(languages))
+	for i, lang := range languages {
+		parsed[i] = language.Make(lang)
+	}
+	return parsed
+}
+
+// Languages lists the languages error messages can be returned in
+func Languages() []string {
+	return slices.Clone(supported)
+}
+
+// Localizer translates error messages to the language a client prefers.
+// Messages missing from the catalog of that language are looked up in the
+// catalogs of its parent languages, then in the default language; messages
+// found nowhere are returned as they are.
+type Localizer struct {
+	language string
+	chain    []*messageCatalog
+}
+
+// NewLocalizer creates a localizer for the language that best matches an
+// Accept-Language header. Empty or invalid headers, and headers matching
+// none of the catalogs, get the default language.
+func NewLocalizer(acceptLanguage string) *Localizer {
+	lang := DefaultLanguage
+	if preferred, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(preferred) > 0 {
+		if _, index, confidence := matcher.Match(preferred...); confidence != language.No {
+			lang = supported[index]
+		}
+	}
+
+	l := &Localizer{language: lang}
+	for tag := language.Make(lang); tag != language.Und; tag = tag.Parent() {
+		if c, ok := locales[tag.String()]; ok {
+			l.chain = append(l.chain, c)
+		}
+	}
+	if lang != DefaultLanguage {
+		l.chain = append(l.chain, locales[DefaultLanguage])
+	}
+	return l
+}
+
+// Language returns the language code the localizer translates to
+func (l *Localizer) Language() string {
+	return l.language
+}
+
+// Message translates an error message
+func (l *Localizer) Message(message string) string {
+	for _, c := range l.chain {
+		if translated, ok := c.Messages[message]; ok {
+			return translated
+		}
+	}
+	return message
+}
+
+// validation describes a failed validation rule; rules without a message
+// of their own get the default one
+func (l *Localizer) validation(v violation) string {
+	template, ok := l.lookupValidation(v.tag)
+	if !ok {
+		template, _ = l.lookupValidation("default")
+	}
+	return strings.NewReplacer("{field}", v.field, "{param}", v.param, "{tag}", v.tag).Replace(template)
+}
+
+// lookupValidation finds the validation message for a tag along the chain
+func (l *Localizer) lookupValidation(tag string) (string, bool) {
+	for _, c := range l.chain {
+		if template, ok := c.Validation[tag]; ok {
+			return template, true
+		}
+	}
+	return "", false
+}
+
+// details translates the details of an API error. Failed validation rules
+// are described anew in the language, other details translated as messages.
+func (l *Localizer) details(apiErr *APIError) []string {
+	if len(apiErr.violations) > 0 {
+		details := make([]string, len(apiErr.violations))
+		for i, v := range apiErr.violations {
+			details[i] = l.validation(v)
+		}
+		return details
+	}
+	if len(apiErr.Details) == 0 {
+		return apiErr.Details
+	}
+	details := make([]string, len(apiErr.Details))
+	for i, detail := range apiErr.Details {
+		details[i] = l.Message(detail)
+	}
+	return details
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/announcement"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// AnnouncementHandler handles announcement email HTTP requests
+type AnnouncementHandler struct {
+	announcementService announcement.Service
+	logger              service.Logger
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcementService announcement.Service, logger service.Logger) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+		logger:              logger,
+	}
+}
+
+// AnnouncementSegmentRequest selects the recipients of an announcement;
+// omitted fields do not restrict them
+type AnnouncementSegmentRequest struct {
+	Roles            []string   `json:"roles,omitempty"`
+	RegisteredBefore *time.Time `json:"registered_before,omitempty"`
+}
+
+// CreateAnnouncementRequest represents the create announcement request payload
+type CreateAnnouncementRequest struct {
+	Subject string                     `json:"subject" validate:"required,min=1,max=200"`
+	Body    string                     `json:"body" validate:"required,min=1,max=20000"`
+	Segment AnnouncementSegmentRequest `json:"segment"`
+}
+
+// AnnouncementResponse represents an announcement and its delivery counters
+type AnnouncementResponse struct {
+	ID          int                        `json:"id"`
+	Subject     string                     `json:"subject"`
+	Segment     AnnouncementSegmentRequest `json:"segment"`
+	Status      string                     `json:"status"`
+	Recipients  int                        `json:"recipients"`
+	Sent        int                        `json:"sent"`
+	Failed      int                        `json:"failed"`
+	Skipped     int                        `json:"skipped"`
+	CreatedAt   string                     `json:"created_at"`
+	CompletedAt string                     `json:"completed_at,omitempty"`
+}
+
+// DeliveryFailureResponse represents a recipient an announcement could not
+// be delivered to
+type DeliveryFailureResponse struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+	Error  string `json:"error"`
+}
+
+// AnnouncementReportResponse represents the delivery report of an announcement
+type AnnouncementReportResponse struct {
+	AnnouncementResponse
+	Failures []DeliveryFailureResponse `json:"failures"`
+}
+
+// Create handles POST /api/v1/admin/announcements
+// @Summary Send an announcement
+// @Description Queue an announcement email to every active user, or to a segment by role and registration date. Emails are sent in throttled batches; unsubscribed users are skipped.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateAnnouncementRequest true "Announcement"
+// @Success 202 {object} AnnouncementResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/announcements [post]
+func (h *AnnouncementHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req CreateAnnouncementRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind announcement request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "announcement request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	segment := announcement.Segment{RegisteredBefore: req.Segment.RegisteredBefore}
+	for _, name := range req.Segment.Roles {
+		segment.Roles = append(segment.Roles, user.Role(name))
+	}
+
+	a, err := h.announcementService.Create(ctx, adminID, req.Subject, req.Body, segment)
+	if err != nil {
+		h.logger.Error(ctx, "failed to create announcement", "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "announcement queued", "announcementID", a.ID, "adminID", adminID)
+	return c.JSON(http.StatusAccepted, toAnnouncementResponse(a))
+}
+
+// GetReport handles GET /api/v1/admin/announcements/{id}
+// @Summary Get an announcement delivery report
+// @Description Get the delivery progress of an announcement and the recipients it could not be delivered to
+// @Tags admin
+// @Produce json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} AnnouncementReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/announcements/{id} [get]
+func (h *AnnouncementHandler) GetReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.logger.Warn(ctx, "invalid announcement ID in path", "id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	report, err := h.announcementService.GetReport(ctx, id)
+	if err != nil {
+		h.logger.Error(ctx, "failed to get announcement report", "announcementID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := AnnouncementReportResponse{
+		AnnouncementResponse: toAnnouncementResponse(report.Announcement),
+		Failures:             make([]DeliveryFailureResponse, 0, len(report.Failures)),
+	}
+	for _, d := range report.Failures {
+		response.Failures = append(response.Failures, DeliveryFailureResponse{
+			UserID: d.UserID,
+			Email:  d.Email,
+			Error:  d.Error,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Unsubscribe handles GET and POST /api/v1/announcements/unsubscribe
+// @Summary Unsubscribe from announcements
+// @Description Stop announcement emails using the token from an announcement's unsubscribe link. POST supports one-click unsubscribing from mail clients.
+// @Tags users
+// @Produce json
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse "Missing or invalid token"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/announcements/unsubscribe [get]
+func (h *AnnouncementHandler) Unsubscribe(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token := c.QueryParam("token")
+	if token == "" {
+		h.logger.Warn(ctx, "unsubscribe request without token")
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.announcementService.Unsubscribe(ctx, token); err != nil {
+		h.logger.Warn(ctx, "failed to unsubscribe from announcements", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, MessageResponse{Message: "You will no longer receive announcements"})
+}
+
+// toAnnouncementResponse converts an announcement into its API representation
+func toAnnouncementResponse(a *announcement.Announcement) AnnouncementResponse {
+	response := AnnouncementResponse{
+		ID:         a.ID,
+		Subject:    a.Subject,
+		Segment:    AnnouncementSegmentRequest{RegisteredBefore: a.Segment.RegisteredBefore},
+		Status:     a.Status,
+		Recipients: a.Recipients,
+		Sent:       a.Sent,
+		Failed:     a.Failed,
+		Skipped:    a.Skipped,
+		CreatedAt:  a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	for _, role := range a.Segment.Roles {
+		response.Segment.Roles = append(response.Segment.Roles, string(role))
+	}
+	if a.CompletedAt != nil {
+		response.CompletedAt = a.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the announcement routes
+func (h *AnnouncementHandler) RouteDocs() []RouteDoc {
+	example := AnnouncementResponse{
+		ID:         1,
+		Subject:    "New feature: comment feeds",
+		Segment:    AnnouncementSegmentRequest{Roles: []string{"author"}},
+		Status:     announcement.StatusQueued,
+		Recipients: 120,
+		CreatedAt:  "2024-01-15T10:30:00Z",
+	}
+	completed := example
+	completed.Status = announcement.StatusCompleted
+	completed.Sent, completed.Failed, completed.Skipped = 115, 1, 4
+	completed.CompletedAt = "2024-01-15T10:34:00Z"
+
+	unsubscribed := MessageResponse{Message: "You will no longer receive announcements"}
+
+	return []RouteDoc{
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/admin/announcements",
+			Summary: "Send an announcement",
+			RequestExample: CreateAnnouncementRequest{
+				Subject: example.Subject,
+				Body:    "You can now follow the comments on your posts in any feed reader.",
+				Segment: example.Segment,
+			},
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/admin/announcements/{id}",
+			Summary:        "Get an announcement delivery report",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: AnnouncementReportResponse{
+				AnnouncementResponse: completed,
+				Failures:             []DeliveryFailureResponse{{UserID: 42, Email: "jane@example.com", Error: "failed to send email: 550 mailbox unavailable"}},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/announcements/unsubscribe",
+			Summary:         "Unsubscribe from announcements",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: unsubscribed,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/announcements/unsubscribe",
+			Summary:         "Unsubscribe from announcements in one click",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: unsubscribed,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+	}
+}
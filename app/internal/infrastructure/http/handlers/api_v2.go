@@ -0,0 +1,285 @@
+package handlers
+
+import "time"
+
+// V2Mapper responds with the v2 representations: resources are wrapped in
+// an envelope holding them in data, with list metadata in meta, and
+// timestamps are typed RFC 3339 fields in UTC, null when unset. Errors keep
+// the format shared by all versions.
+type V2Mapper struct{}
+
+// Version returns APIv2
+func (V2Mapper) Version() APIVersion { return APIv2 }
+
+// Map converts the post representations and wraps any other body in the
+// envelope as it is
+func (V2Mapper) Map(body any) any {
+	switch b := body.(type) {
+	case PostResponse:
+		return PostEnvelope{Data: toPostV2Response(b)}
+	case PostListResponse:
+		response := PostListEnvelope{
+			Data: make([]PostV2Response, len(b.Posts)),
+			Meta: ListMeta{Limit: b.Limit, Offset: b.Offset, NextCursor: b.NextCursor},
+		}
+		for i, p := range b.Posts {
+			response.Data[i] = toPostV2Response(p)
+		}
+		return response
+	default:
+		return Envelope{Data: body}
+	}
+}
+
+// Envelope wraps a v2 response body
+type Envelope struct {
+	Data any `json:"data"`
+}
+
+// ListMeta describes a page of a v2 list. The list continues with
+// next_cursor, or the offset and limit of the following page when it is
+// offset paginated.
+type ListMeta struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// PostEnvelope represents a post in v2 responses
+type PostEnvelope struct {
+	Data PostV2Response `json:"data"`
+}
+
+// PostListEnvelope represents a page of posts in v2 responses
+type PostListEnvelope struct {
+	Data []PostV2Response `json:"data"`
+	Meta ListMeta         `json:"meta"`
+}
+
+// PostV2Response represents the post data in v2 responses. Fields are
+// those of PostResponse with typed timestamps.
+type PostV2Response struct {
+	ID       int    `json:"id"`
+	ClientID string `json:"client_id,omitempty"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Content  string `json:"content"`
+	// ContentFormat is html when the content was rendered with ?format=html
+	ContentFormat string   `json:"content_format,omitempty"`
+	AuthorID      int      `json:"author_id"`
+	NoIndex       bool     `json:"noindex"`
+	Access        string   `json:"access"`
+	Visibility    string   `json:"visibility"`
+	Language      string   `json:"language"`
+	Translations  []string `json:"translations"`
+	Locked        bool     `json:"locked"`
+	Status        string   `json:"status"`
+	// PublishedAt is null until the post is published
+	PublishedAt        *time.Time `json:"published_at" format:"date-time"`
+	Timezone           string     `json:"timezone,omitempty"`
+	Tags               []string   `json:"tags"`
+	ViewCount          int64      `json:"view_count"`
+	TipCount           int64      `json:"tip_count"`
+	WordCount          int        `json:"word_count"`
+	ReadingTimeMinutes int        `json:"reading_time_minutes"`
+	Excerpt            string     `json:"excerpt"`
+	Pinned             bool       `json:"pinned"`
+	Featured           bool       `json:"featured"`
+	SeriesID           *int       `json:"series_id,omitempty"`
+	SeriesPosition     int        `json:"series_position,omitempty"`
+	CreatedAt          time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt          time.Time  `json:"updated_at" format:"date-time"`
+	// Progress is where the signed-in reader left off, on single posts only
+	Progress *ReadingProgressV2Response `json:"progress,omitempty"`
+	// Author is the profile of the author, with ?include=author
+	Author *ProfileResponse `json:"author,omitempty"`
+}
+
+// ReadingProgressV2Response represents reading progress in v2 responses
+type ReadingProgressV2Response struct {
+	PostID     int       `json:"post_id"`
+	Percent    float64   `json:"percent"`
+	Anchor     string    `json:"anchor,omitempty"`
+	RecordedAt time.Time `json:"recorded_at" format:"date-time"`
+}
+
+// toPostV2Response converts the v1 representation of a post
+func toPostV2Response(p PostResponse) PostV2Response {
+	response := PostV2Response{
+		ID:                 p.ID,
+		ClientID:           p.ClientID,
+		Title:              p.Title,
+		Slug:               p.Slug,
+		Content:            p.Content,
+		ContentFormat:      p.ContentFormat,
+		AuthorID:           p.AuthorID,
+		NoIndex:            p.NoIndex,
+		Access:             p.Access,
+		Visibility:         p.Visibility,
+		Language:           p.Language,
+		Translations:       p.Translations,
+		Locked:             p.Locked,
+		Status:             p.Status,
+		Timezone:           p.Timezone,
+		Tags:               p.Tags,
+		ViewCount:          p.ViewCount,
+		TipCount:           p.TipCount,
+		WordCount:          p.WordCount,
+		ReadingTimeMinutes: p.ReadingTimeMinutes,
+		Excerpt:            p.Excerpt,
+		Pinned:             p.Pinned,
+		Featured:           p.Featured,
+		SeriesID:           p.SeriesID,
+		SeriesPosition:     p.SeriesPosition,
+		CreatedAt:          parseTimestamp(p.CreatedAt),
+		UpdatedAt:          parseTimestamp(p.UpdatedAt),
+		Author:             p.Author,
+	}
+	if p.PublishedAt != "" {
+		publishedAt := parseTimestamp(p.PublishedAt)
+		response.PublishedAt = &publishedAt
+	}
+	if p.Progress != nil {
+		response.Progress = &ReadingProgressV2Response{
+			PostID:     p.Progress.PostID,
+			Percent:    p.Progress.Percent,
+			Anchor:     p.Progress.Anchor,
+			RecordedAt: parseTimestamp(p.Progress.RecordedAt),
+		}
+	}
+	return response
+}
+
+// parseTimestamp parses a timestamp of a v1 representation, which are all
+// RFC 3339, into UTC
+func parseTimestamp(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t.UTC()
+}
+
+// The post routes of v2 are served by the PostHandler methods of v1 with
+// V2Mapper; the functions below only carry their OpenAPI annotations.
+
+// listPostsV2 documents GET /api/v2/posts, served by ListPosts
+// @Summary List posts with pagination
+// @Description Like GET /api/v1/posts, with the posts in data and the page in meta. The meta carries no total.
+// @Tags posts-v2
+// @Produce json
+// @Param tag query string false "Only list posts carrying this tag"
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of posts to skip (default: 0)"
+// @Param cursor query string false "Cursor pagination: empty for the first page, then meta.next_cursor of the previous page"
+// @Param sort query string false "Sort by created_at (default), updated_at or title; not with cursor" Enums(created_at, updated_at, title)
+// @Param order query string false "Sort order: asc or desc (default: desc, asc for title)" Enums(asc, desc)
+// @Param author_id query int false "Only list posts of this author"
+// @Param created_after query string false "Only list posts created at or after this RFC 3339 time"
+// @Param created_before query string false "Only list posts created before this RFC 3339 time"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Success 200 {object} PostListEnvelope
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/posts [get]
+func listPostsV2() {}
+
+// getPostV2 documents GET /api/v2/posts/{id}, served by GetPost
+// @Summary Get a post by ID
+// @Description Like GET /api/v1/posts/{id}, with the post in data.
+// @Tags posts-v2
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the copy the client has"
+// @Success 200 {object} PostEnvelope
+// @Success 304 "The post is unchanged"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/posts/{id} [get]
+func getPostV2() {}
+
+// getPostBySlugV2 documents GET /api/v2/posts/slug/{slug}, served by
+// GetPostBySlug
+// @Summary Get a post by slug
+// @Description Like GET /api/v1/posts/slug/{slug}, with the post in data. Old slugs redirect to the v2 route of the current slug.
+// @Tags posts-v2
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Success 200 {object} PostEnvelope
+// @Success 301 "Moved to the current slug of the post"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/posts/slug/{slug} [get]
+func getPostBySlugV2() {}
+
+// createPostV2 documents POST /api/v2/posts, served by CreatePost
+// @Summary Create a new post
+// @Description Like POST /api/v1/posts, with the post in data.
+// @Tags posts-v2
+// @Accept json
+// @Produce json
+// @Param request body CreatePostRequest true "Post creation data"
+// @Param Idempotency-Key header string false "Key of the request, so retries with it return the first response instead of creating duplicates"
+// @Success 201 {object} PostEnvelope
+// @Success 200 {object} PostEnvelope "Already created under the client ID"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse "Idempotency-Key already sent with a different request"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v2/posts [post]
+func createPostV2() {}
+
+// updatePostV2 documents PUT /api/v2/posts/{id}, served by UpdatePost
+// @Summary Update a post
+// @Description Like PUT /api/v1/posts/{id}, with the post in data.
+// @Tags posts-v2
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body UpdatePostRequest true "Post update data"
+// @Success 200 {object} PostEnvelope
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v2/posts/{id} [put]
+func updatePostV2() {}
+
+// publishPostV2 documents POST /api/v2/posts/{id}/publish, served by
+// PublishPost
+// @Summary Publish a post
+// @Description Like POST /api/v1/posts/{id}/publish, with the post in data.
+// @Tags posts-v2
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostEnvelope
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Post already published"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v2/posts/{id}/publish [post]
+func publishPostV2() {}
+
+// deletePostV2 documents DELETE /api/v2/posts/{id}, served by DeletePost
+// @Summary Delete a post
+// @Description Like DELETE /api/v1/posts/{id}.
+// @Tags posts-v2
+// @Param id path int true "Post ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v2/posts/{id} [delete]
+func deletePostV2() {}
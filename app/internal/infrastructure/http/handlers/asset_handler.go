@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/views"
+)
+
+const (
+	// immutableCacheControl is sent for asset URLs carrying the current content hash
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	// revalidateCacheControl is sent for unversioned asset URLs
+	revalidateCacheControl = "public, no-cache"
+)
+
+// AssetHandler serves the static files of the reading view themes
+type AssetHandler struct {
+	renderer *views.Renderer
+	logger   service.Logger
+}
+
+// NewAssetHandler creates a new theme asset handler
+func NewAssetHandler(renderer *views.Renderer, logger service.Logger) *AssetHandler {
+	return &AssetHandler{
+		renderer: renderer,
+		logger:   logger,
+	}
+}
+
+// ServeAsset serves a theme file with an ETag. Requests whose v parameter
+// matches the content hash may be cached indefinitely; others must
+// revalidate.
+func (h *AssetHandler) ServeAsset(c echo.Context) error {
+	theme, err := h.renderer.Theme(c.Param("theme"))
+	if err != nil {
+		return c.NoContent(http.StatusNotFound)
+	}
+	asset, ok := theme.Asset(c.Param("*"))
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	header := c.Response().Header()
+	header.Set("ETag", asset.ETag())
+	if c.QueryParam("v") == asset.Hash {
+		header.Set(echo.HeaderCacheControl, immutableCacheControl)
+	} else {
+		header.Set(echo.HeaderCacheControl, revalidateCacheControl)
+	}
+
+	if etagMatches(c.Request().Header.Get("If-None-Match"), asset.ETag()) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, asset.ContentType, asset.Content)
+}
+
+// etagMatches reports whether an If-None-Match header lists the entity tag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/audit"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditService audit.Service
+	pagination   service.PageLimits
+	logger       service.Logger
+}
+
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(auditService audit.Service, pagination service.PageLimits, logger service.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		pagination:   pagination,
+		logger:       logger,
+	}
+}
+
+// AuditLogResponse represents an audit log entry
+type AuditLogResponse struct {
+	ID        int            `json:"id"`
+	Action    string         `json:"action"`
+	UserID    int            `json:"user_id"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	IPAddress string         `json:"ip_address,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	CreatedAt string         `json:"created_at"`
+}
+
+// AuditLogListResponse represents a page of audit log entries
+type AuditLogListResponse struct {
+	Entries []AuditLogResponse `json:"entries"`
+	Total   int                `json:"total"`
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+}
+
+// ListAuditLogs handles GET /api/v1/admin/audit-logs
+// @Summary List audit log entries
+// @Description List security-relevant actions such as logins, registrations, password changes and deletions, newest first
+// @Tags admin
+// @Produce json
+// @Param user_id query int false "Only entries of this user"
+// @Param action query string false "Only entries with this action, e.g. user.login"
+// @Param request_id query string false "Only entries recorded in the request with this X-Request-ID"
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Param limit query int false "Number of entries to return" default(10)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {object} AuditLogListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AuditHandler) ListAuditLogs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		h.logger.Warn(ctx, "invalid audit log filter", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	limit, offset := parsePage(c, h.pagination)
+
+	entries, err := h.auditService.ListEntries(ctx, filter, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list audit log entries", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := AuditLogListResponse{
+		Entries: make([]AuditLogResponse, 0, len(entries)),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, AuditLogResponse{
+			ID:        entry.ID,
+			Action:    entry.Action,
+			UserID:    entry.UserID,
+			Metadata:  entry.Metadata,
+			IPAddress: entry.IPAddress,
+			RequestID: entry.RequestID,
+			CreatedAt: entry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	response.Total = len(response.Entries)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseAuditFilter reads the user_id, action, request_id, from and to query
+// parameters
+func parseAuditFilter(c echo.Context) (audit.Filter, error) {
+	filter := audit.Filter{Action: c.QueryParam("action"), RequestID: c.QueryParam("request_id")}
+
+	if value := c.QueryParam("user_id"); value != "" {
+		userID, err := strconv.Atoi(value)
+		if err != nil || userID <= 0 {
+			return filter, errors.ErrInvalidRequest
+		}
+		filter.UserID = userID
+	}
+
+	for param, bound := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.QueryParam(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, err
+			}
+			*bound = &t
+		}
+	}
+
+	return filter, nil
+}
+
+// RouteDocs returns examples and error codes for the audit log routes
+func (h *AuditHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/admin/audit-logs",
+			Summary:        "List audit log entries",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: AuditLogListResponse{
+				Entries: []AuditLogResponse{{
+					ID:        42,
+					Action:    service.AuditActionPostDeleted,
+					UserID:    1,
+					Metadata:  map[string]any{"post_id": 7, "author_id": 3, "title": "My First Post"},
+					IPAddress: "203.0.113.7",
+					RequestID: "4f1c9a7e2b3d4c5e8f9a0b1c2d3e4f5a",
+					CreatedAt: "2024-01-15T10:30:00Z",
+				}},
+				Total:  1,
+				Limit:  10,
+				Offset: 0,
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+	}
+}
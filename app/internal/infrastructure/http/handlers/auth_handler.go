@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	stderrors "errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/idempotency"
 	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/errors"
 	"blog-platform/internal/infrastructure/http/middleware"
@@ -30,21 +36,45 @@ func NewAuthHandler(userService user.Service, authService auth.AuthService, logg
 
 // RegisterRequest represents the registration request payload
 type RegisterRequest struct {
-	Name     string `json:"name" validate:"required,min=1,max=255,no_html,safe_string"`
-	Email    string `json:"email" validate:"required,email"`
+	Name     string `json:"name" validate:"required,min=1,max=255,no_html,safe_string" sanitize:"strict"`
+	Email    string `json:"email" validate:"required,email" sanitize:"strict"`
 	Password string `json:"password" validate:"required,min=6,strong_password"`
 }
 
 // LoginRequest represents the login request payload
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
+	Email    string `json:"email" validate:"required,email" sanitize:"strict"`
 	Password string `json:"password" validate:"required"`
 }
 
+// RefreshRequest represents the token refresh request payload
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest represents the logout request payload
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// MagicLinkRequest represents the magic link request payload
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email" sanitize:"strict"`
+}
+
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token"`
+	User         UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int          `json:"expires_in"` // access token lifetime in seconds
+}
+
+// TokenResponse represents the token refresh response
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token lifetime in seconds
 }
 
 // UserResponse represents the user data in responses
@@ -52,12 +82,14 @@ type UserResponse struct {
 	ID    int    `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 }
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error     string `json:"error"`
+	Message   string `json:"message,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // Register handles user registration
@@ -67,11 +99,13 @@ type ErrorResponse struct {
 // @Accept json
 // @Produce json
 // @Param user body RegisterRequest true "User registration data"
+// @Param Idempotency-Key header string false "Key of the request, so retries with it sign in the registered user instead of creating duplicates"
 // @Success 201 {object} AuthResponse "User successfully registered"
 // @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
 // @Failure 409 {object} ErrorResponse "User already exists"
+// @Failure 422 {object} ErrorResponse "Idempotency-Key already sent with a different request"
 // @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /auth/register [post]
+// @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(c echo.Context) error {
 	ctx := c.Request().Context()
 	h.logger.Info(ctx, "registration request received")
@@ -83,8 +117,7 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 	
 	// Sanitize input
-	req.Name = middleware.SanitizeInput(req.Name)
-	req.Email = middleware.SanitizeInput(req.Email)
+	middleware.SanitizeFields(&req)
 
 	if err := c.Validate(&req); err != nil {
 		h.logger.Error(ctx, "registration request validation failed", "error", err.Error())
@@ -92,23 +125,54 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 
 	// Register user
-	registeredUser, token, err := h.authService.Register(ctx, req.Name, req.Email, req.Password)
+	registeredUser, tokens, err := h.authService.Register(ctx, req.Name, req.Email, req.Password)
 	if err != nil {
 		h.logger.Error(ctx, "failed to register user", "error", err.Error(), "email", req.Email)
 		return errors.HandleError(c, err)
 	}
 
-	response := AuthResponse{
-		User: UserResponse{
-			ID:    registeredUser.ID,
-			Name:  registeredUser.Name,
-			Email: registeredUser.Email,
-		},
-		Token: token,
-	}
+	// Retries with an Idempotency-Key are answered by ReplayRegister; the
+	// tokens are not stored, only the new user
+	middleware.SetIdempotentResult(c, []byte(strconv.Itoa(registeredUser.ID)))
 
 	h.logger.Info(ctx, "user registered successfully", "userID", registeredUser.ID, "email", registeredUser.Email)
-	return c.JSON(http.StatusCreated, response)
+	return c.JSON(http.StatusCreated, newAuthResponse(registeredUser, tokens))
+}
+
+// ReplayRegister answers a retried registration sent with the Idempotency-Key
+// of one that succeeded. The tokens of the first response are not stored, so
+// the registered user is signed in again with the credentials of the request
+// for a fresh pair.
+func (h *AuthHandler) ReplayRegister(c echo.Context, record *idempotency.Record) error {
+	ctx := user.WithClientIP(c.Request().Context(), c.RealIP())
+
+	userID, err := strconv.Atoi(string(record.Body))
+	if err != nil {
+		h.logger.Error(ctx, "invalid stored registration", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	var req RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	middleware.SanitizeFields(&req)
+
+	u, tokens, err := h.authService.Login(ctx, req.Email, req.Password)
+	if err != nil {
+		if ok, respErr := respondTwoFactorChallenge(c, err); ok {
+			return respErr
+		}
+		h.logger.Warn(ctx, "failed to sign in replayed registration", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	if u.ID != userID {
+		h.logger.Warn(ctx, "replayed registration signed in another user", "userID", userID, "signedInUserID", u.ID)
+		return errors.HandleError(c, user.ErrUserExists)
+	}
+
+	h.logger.Info(ctx, "registration replayed", "userID", u.ID)
+	return c.JSON(record.StatusCode, newAuthResponse(u, tokens))
 }
 
 // Login handles user authentication
@@ -119,12 +183,16 @@ func (h *AuthHandler) Register(c echo.Context) error {
 // @Produce json
 // @Param credentials body LoginRequest true "User login credentials"
 // @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Success 202 {object} TwoFactorChallengeResponse "Password accepted; complete the login at /auth/login/2fa"
 // @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
 // @Failure 401 {object} ErrorResponse "Invalid credentials"
+// @Failure 403 {object} ErrorResponse "Account locked until its password is reset"
+// @Failure 423 {object} ErrorResponse "Too many failed attempts; logins are locked until Retry-After"
 // @Failure 500 {object} ErrorResponse "Internal server error"
-// @Router /auth/login [post]
+// @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
-	ctx := c.Request().Context()
+	// Failed logins are also counted per client IP
+	ctx := user.WithClientIP(c.Request().Context(), c.RealIP())
 	h.logger.Info(ctx, "login request received")
 
 	var req LoginRequest
@@ -134,7 +202,7 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	}
 	
 	// Sanitize input
-	req.Email = middleware.SanitizeInput(req.Email)
+	middleware.SanitizeFields(&req)
 
 	if err := c.Validate(&req); err != nil {
 		h.logger.Error(ctx, "login request validation failed", "error", err.Error())
@@ -142,9 +210,16 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	}
 
 	// Authenticate user
-	authenticatedUser, token, err := h.authService.Login(ctx, req.Email, req.Password)
+	authenticatedUser, tokens, err := h.authService.Login(ctx, req.Email, req.Password)
 	if err != nil {
+		if ok, respErr := respondTwoFactorChallenge(c, err); ok {
+			h.logger.Info(ctx, "two-factor code required", "email", req.Email)
+			return respErr
+		}
 		h.logger.Error(ctx, "user login failed", "email", req.Email, "error", err.Error())
+		if stderrors.Is(err, user.ErrAccountLocked) {
+			return h.respondLocked(c, err)
+		}
 		return errors.HandleError(c, err)
 	}
 
@@ -153,10 +228,258 @@ func (h *AuthHandler) Login(c echo.Context) error {
 			ID:    authenticatedUser.ID,
 			Name:  authenticatedUser.Name,
 			Email: authenticatedUser.Email,
+			Role:  string(authenticatedUser.Role),
 		},
-		Token: token,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
 	}
 
 	h.logger.Info(ctx, "user logged in successfully", "userID", authenticatedUser.ID, "email", authenticatedUser.Email)
 	return c.JSON(http.StatusOK, response)
 }
+
+// Refresh handles refresh token rotation
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and refresh token. The presented refresh token is revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token body RefreshRequest true "Refresh token"
+// @Success 200 {object} TokenResponse "New token pair issued"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 401 {object} ErrorResponse "Invalid, expired or revoked refresh token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	ctx := c.Request().Context()
+	h.logger.Info(ctx, "token refresh request received")
+
+	var req RefreshRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind refresh request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "refresh request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	tokens, err := h.authService.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		h.logger.Error(ctx, "token refresh failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := TokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	}
+
+	h.logger.Info(ctx, "token refreshed successfully")
+	return c.JSON(http.StatusOK, response)
+}
+
+// Logout handles session termination
+// @Summary Logout user
+// @Description Revoke the bearer access token (if sent) until it expires and the refresh token (if sent). At least one of them is required.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body LogoutRequest false "Refresh token"
+// @Success 204 "Session revoked"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 401 {object} ErrorResponse "Invalid refresh token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+	h.logger.Info(ctx, "logout request received")
+
+	var req LogoutRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind logout request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// The access token is optional so that clients holding an already expired
+	// access token can still revoke their refresh token
+	var accessToken string
+	if parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2); len(parts) == 2 && parts[0] == "Bearer" {
+		accessToken = parts[1]
+	}
+
+	if accessToken == "" && req.RefreshToken == "" {
+		h.logger.Error(ctx, "logout request without tokens")
+		return errors.HandleError(c, errors.NewAPIError(errors.ErrCodeValidation, "A bearer token or refresh_token is required", http.StatusBadRequest))
+	}
+
+	if err := h.authService.Logout(ctx, accessToken, req.RefreshToken); err != nil {
+		h.logger.Error(ctx, "logout failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged out successfully")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RequestMagicLink handles passwordless login requests
+// @Summary Request a magic link
+// @Description Email a single-use login link to the account. The response is the same whether or not the email belongs to an account.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkRequest true "Account email"
+// @Success 202 {object} MessageResponse "Login link sent if the account exists"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 429 {object} ErrorResponse "Too many requests"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c echo.Context) error {
+	ctx := c.Request().Context()
+	h.logger.Info(ctx, "magic link request received")
+
+	var req MagicLinkRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind magic link request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "magic link request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	device := user.NewDevice(c.RealIP(), c.Request().UserAgent())
+	if err := h.userService.RequestMagicLink(ctx, req.Email, device); err != nil {
+		h.logger.Error(ctx, "failed to send magic link", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, MessageResponse{Message: "If an account exists for this email, a login link has been sent"})
+}
+
+// VerifyMagicLink handles the links sent by RequestMagicLink
+// @Summary Login with a magic link
+// @Description Exchange the token of an emailed login link for a session. Each link works once and expires after a few minutes.
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Token from the login link"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Success 202 {object} TwoFactorChallengeResponse "Link accepted; complete the login at /auth/login/2fa"
+// @Failure 400 {object} ErrorResponse "Missing token"
+// @Failure 401 {object} ErrorResponse "Invalid, used or expired link"
+// @Failure 403 {object} ErrorResponse "Account locked until its password is reset"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/magic-link/verify [get]
+func (h *AuthHandler) VerifyMagicLink(c echo.Context) error {
+	ctx := c.Request().Context()
+	h.logger.Info(ctx, "magic link login request received")
+
+	token := c.QueryParam("token")
+	if token == "" {
+		h.logger.Error(ctx, "magic link login without token")
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	device := user.NewDevice(c.RealIP(), c.Request().UserAgent())
+	authenticatedUser, tokens, err := h.authService.LoginWithMagicLink(ctx, token, device)
+	if err != nil {
+		if ok, respErr := respondTwoFactorChallenge(c, err); ok {
+			h.logger.Info(ctx, "two-factor code required after magic link")
+			return respErr
+		}
+		h.logger.Error(ctx, "magic link login failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := AuthResponse{
+		User: UserResponse{
+			ID:    authenticatedUser.ID,
+			Name:  authenticatedUser.Name,
+			Email: authenticatedUser.Email,
+			Role:  string(authenticatedUser.Role),
+		},
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	}
+
+	h.logger.Info(ctx, "user logged in with magic link", "userID", authenticatedUser.ID)
+	return c.JSON(http.StatusOK, response)
+}
+
+// respondLocked rejects a login while the account or client is locked,
+// telling the client when to retry
+func (h *AuthHandler) respondLocked(c echo.Context, err error) error {
+	var lockout *user.LockoutError
+	if stderrors.As(err, &lockout) && !lockout.Until.IsZero() {
+		retryAfter := int(math.Ceil(time.Until(lockout.Until).Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	}
+	return errors.HandleError(c, errors.NewAPIError(errors.ErrCodeAccountLocked, user.ErrAccountLocked.Error(), http.StatusLocked))
+}
+
+// RouteDocs returns examples and error codes for the authentication routes
+func (h *AuthHandler) RouteDocs() []RouteDoc {
+	exampleUser := UserResponse{ID: 1, Name: "John Doe", Email: "john@example.com", Role: "author"}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/register",
+			Summary:         "Register a new user",
+			RequestExample:  RegisterRequest{Name: "John Doe", Email: "john@example.com", Password: "Password123!"},
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: AuthResponse{User: exampleUser, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeConflict, errors.ErrCodeSSORequired, errors.ErrCodeIdempotencyKeyReused, errors.ErrCodeIdempotencyKeyInUse),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/login",
+			Summary:         "Login user",
+			RequestExample:  LoginRequest{Email: "john@example.com", Password: "Password123!"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: AuthResponse{User: exampleUser, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeInvalidCredentials, errors.ErrCodeAccountLocked, errors.ErrCodePasswordResetRequired, errors.ErrCodeAccountDeactivated, errors.ErrCodeSSORequired),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/refresh",
+			Summary:         "Refresh access token",
+			RequestExample:  RefreshRequest{RefreshToken: "3f9c2d..."},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: TokenResponse{Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "8a41be...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/auth/logout",
+			Summary:        "Logout",
+			RequestExample: LogoutRequest{RefreshToken: "3f9c2d..."},
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/magic-link",
+			Summary:         "Request a magic link",
+			RequestExample:  MagicLinkRequest{Email: "john@example.com"},
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: MessageResponse{Message: "If an account exists for this email, a login link has been sent"},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/auth/magic-link/verify",
+			Summary:         "Login with a magic link",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: AuthResponse{User: exampleUser, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodePasswordResetRequired, errors.ErrCodeAccountDeactivated, errors.ErrCodeSSORequired),
+		},
+	}
+}
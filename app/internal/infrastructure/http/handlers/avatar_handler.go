@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// AvatarField is the multipart form field avatar uploads are sent in
+const AvatarField = "avatar"
+
+// AvatarTypes lists the content types accepted for avatars
+var AvatarTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// AvatarHandler handles avatar uploads
+type AvatarHandler struct {
+	avatarService user.AvatarService
+	logger        service.Logger
+}
+
+// NewAvatarHandler creates a new avatar handler
+func NewAvatarHandler(avatarService user.AvatarService, logger service.Logger) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+		logger:        logger,
+	}
+}
+
+// AvatarResponse represents an uploaded avatar
+type AvatarResponse struct {
+	// AvatarURL is the URL of the largest size, now the avatar of the
+	// profile
+	AvatarURL string `json:"avatar_url"`
+	// Sizes maps each size in pixels to the URL of the image of that size
+	Sizes map[string]string `json:"sizes"`
+}
+
+// UploadAvatar handles POST /api/v1/users/me/avatar
+// @Summary Upload your avatar
+// @Description Upload a JPEG, PNG or GIF image as the avatar of the authenticated user. The image is cropped to a centered square and resized to 64, 128 and 256 pixels; the profile links to the largest. The avatar uploaded before is deleted.
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param avatar formData file true "Image file (at most AVATAR_MAX_SIZE bytes, 5 MB by default)"
+// @Success 200 {object} AvatarResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/avatar [post]
+func (h *AvatarHandler) UploadAvatar(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	header, err := c.FormFile(AvatarField)
+	if err != nil {
+		h.logger.Warn(ctx, "avatar upload without file", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error(ctx, "failed to open uploaded avatar", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+	defer file.Close()
+
+	avatar, err := h.avatarService.UploadAvatar(ctx, userID, file)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAvatarResponse(avatar))
+}
+
+// toAvatarResponse converts an avatar to its response format
+func toAvatarResponse(a *user.Avatar) AvatarResponse {
+	sizes := make(map[string]string, len(a.Sizes))
+	for size, url := range a.Sizes {
+		sizes[strconv.Itoa(size)] = url
+	}
+	return AvatarResponse{AvatarURL: a.URL, Sizes: sizes}
+}
+
+// RouteDocs returns examples and error codes for the avatar route
+func (h *AvatarHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/users/me/avatar",
+			Summary:        "Upload your avatar",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: AvatarResponse{
+				AvatarURL: "https://cdn.example.com/avatars/1/9f86d081884c7d65-256.jpg",
+				Sizes: map[string]string{
+					"64":  "https://cdn.example.com/avatars/1/9f86d081884c7d65-64.jpg",
+					"128": "https://cdn.example.com/avatars/1/9f86d081884c7d65-128.jpg",
+					"256": "https://cdn.example.com/avatars/1/9f86d081884c7d65-256.jpg",
+				},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodePayloadTooLarge, errors.ErrCodeUnsupportedMediaType, errors.ErrCodeNotFound),
+		},
+	}
+}
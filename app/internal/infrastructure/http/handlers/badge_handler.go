@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/badge"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// BadgeHandler serves the SVG stats badges authors embed in external sites
+type BadgeHandler struct {
+	renderer *badge.Renderer
+	logger   service.Logger
+}
+
+// NewBadgeHandler creates a new badge handler
+func NewBadgeHandler(renderer *badge.Renderer, logger service.Logger) *BadgeHandler {
+	return &BadgeHandler{
+		renderer: renderer,
+		logger:   logger,
+	}
+}
+
+// PostViews handles GET /badges/posts/{id}/views.svg
+// @Summary Post views badge
+// @Description Get an SVG badge counting the views of a published post, to embed with an img tag. Badges are cached for BADGE_CACHE_TTL seconds and rate limited per IP.
+// @Tags badges
+// @Produce image/svg+xml
+// @Param id path int true "Post ID"
+// @Success 200 {string} string "SVG badge"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /badges/posts/{id}/views.svg [get]
+func (h *BadgeHandler) PostViews(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || postID <= 0 {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	body, err := h.renderer.PostViews(ctx, postID)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	return h.respond(c, body)
+}
+
+// respond writes a badge, letting clients and proxies cache it as long as
+// the server does
+func (h *BadgeHandler) respond(c echo.Context, body []byte) error {
+	if ttl := h.renderer.CacheTTL(); ttl > 0 {
+		c.Response().Header().Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+	}
+	return c.Blob(http.StatusOK, badge.ContentType, body)
+}
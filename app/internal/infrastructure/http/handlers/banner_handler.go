@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/banner"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// BannerHandler handles site-wide announcement banners: their management
+// by admins, and the active banners shown to readers
+type BannerHandler struct {
+	bannerService banner.Service
+	logger        service.Logger
+}
+
+// NewBannerHandler creates a new banner handler
+func NewBannerHandler(bannerService banner.Service, logger service.Logger) *BannerHandler {
+	return &BannerHandler{
+		bannerService: bannerService,
+		logger:        logger,
+	}
+}
+
+// BannerRequest represents the create and update banner request payload
+type BannerRequest struct {
+	Message  string `json:"message" validate:"required,max=500,no_html" sanitize:"basic"`
+	Severity string `json:"severity" validate:"required,oneof=info warning critical"`
+	// StartsAt defaults to now
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// EndsAt is left out for banners shown until they are deleted
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+	// Dismissible defaults to true
+	Dismissible *bool `json:"dismissible,omitempty"`
+}
+
+// BannerResponse represents a banner
+type BannerResponse struct {
+	ID          int        `json:"id"`
+	Message     string     `json:"message"`
+	Severity    string     `json:"severity"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Dismissible bool       `json:"dismissible"`
+	// Active tells whether the banner is shown right now
+	Active bool `json:"active"`
+}
+
+// BannerListResponse represents a list of banners
+type BannerListResponse struct {
+	Banners []BannerResponse `json:"banners"`
+	Total   int              `json:"total"`
+}
+
+// ListActive handles GET /api/v1/announcements/active
+// @Summary List the active banners
+// @Description List the site-wide banners shown right now, most severe first. Sent with a token, banners you dismissed are left out.
+// @Tags announcements
+// @Produce json
+// @Success 200 {object} BannerListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/announcements/active [get]
+func (h *BannerHandler) ListActive(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Guests see every active banner; they dismiss banners client-side
+	userID, _ := c.Get("user_id").(int)
+	banners, err := h.bannerService.ListActive(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list active banners", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toBannerListResponse(banners))
+}
+
+// Dismiss handles POST /api/v1/announcements/{id}/dismiss
+// @Summary Dismiss a banner
+// @Description Stop showing a dismissible banner to the authenticated user; dismissing a banner again has no effect
+// @Tags announcements
+// @Param id path int true "Banner ID"
+// @Security BearerAuth
+// @Success 204 "Banner dismissed"
+// @Failure 400 {object} ErrorResponse "The banner cannot be dismissed"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Banner not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/announcements/{id}/dismiss [post]
+func (h *BannerHandler) Dismiss(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid banner ID in path", "banner_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.bannerService.Dismiss(ctx, id, userID); err != nil {
+		h.logger.Warn(ctx, "failed to dismiss banner", "bannerID", id, "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// List handles GET /api/v1/admin/banners
+// @Summary List banners
+// @Description List every banner, including scheduled and ended ones, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} BannerListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/banners [get]
+func (h *BannerHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	banners, err := h.bannerService.List(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list banners", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toBannerListResponse(banners))
+}
+
+// Create handles POST /api/v1/admin/banners
+// @Summary Create a banner
+// @Description Create a site-wide banner shown between starts_at and ends_at (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body BannerRequest true "Banner"
+// @Success 201 {object} BannerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/banners [post]
+func (h *BannerHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	in, err := h.bindBanner(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	b, err := h.bannerService.Create(ctx, adminID, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to create banner", "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toBannerResponse(b, time.Now()))
+}
+
+// Update handles PUT /api/v1/admin/banners/{id}
+// @Summary Update a banner
+// @Description Replace the fields of a banner; users who dismissed it keep it dismissed (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Banner ID"
+// @Param request body BannerRequest true "Banner"
+// @Success 200 {object} BannerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/banners/{id} [put]
+func (h *BannerHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid banner ID in path", "banner_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	in, err := h.bindBanner(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	b, err := h.bannerService.Update(ctx, adminID, id, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to update banner", "bannerID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toBannerResponse(b, time.Now()))
+}
+
+// Delete handles DELETE /api/v1/admin/banners/{id}
+// @Summary Delete a banner
+// @Description Delete a banner and its dismissals (admins only)
+// @Tags admin
+// @Param id path int true "Banner ID"
+// @Success 204 "Banner deleted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/banners/{id} [delete]
+func (h *BannerHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid banner ID in path", "banner_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.bannerService.Delete(ctx, adminID, id); err != nil {
+		h.logger.Warn(ctx, "failed to delete banner", "bannerID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// bindBanner binds and validates a banner request
+func (h *BannerHandler) bindBanner(c echo.Context) (banner.Input, error) {
+	var req BannerRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(c.Request().Context(), "failed to bind banner request", "error", err.Error())
+		return banner.Input{}, errors.ErrInvalidRequest
+	}
+	middleware.SanitizeFields(&req)
+	if err := c.Validate(&req); err != nil {
+		return banner.Input{}, err
+	}
+
+	in := banner.Input{
+		Message:     req.Message,
+		Severity:    banner.Severity(req.Severity),
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Dismissible: true,
+	}
+	if req.Dismissible != nil {
+		in.Dismissible = *req.Dismissible
+	}
+	return in, nil
+}
+
+// toBannerResponse converts a banner to its response format
+func toBannerResponse(b *banner.Banner, now time.Time) BannerResponse {
+	return BannerResponse{
+		ID:          b.ID,
+		Message:     b.Message,
+		Severity:    string(b.Severity),
+		StartsAt:    b.StartsAt,
+		EndsAt:      b.EndsAt,
+		Dismissible: b.Dismissible,
+		Active:      b.IsActive(now),
+	}
+}
+
+// toBannerListResponse converts banners to their list response format
+func toBannerListResponse(banners []*banner.Banner) BannerListResponse {
+	now := time.Now()
+	response := BannerListResponse{
+		Banners: make([]BannerResponse, len(banners)),
+		Total:   len(banners),
+	}
+	for i, b := range banners {
+		response.Banners[i] = toBannerResponse(b, now)
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the banner routes
+func (h *BannerHandler) RouteDocs() []RouteDoc {
+	startsAt := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(2 * time.Hour)
+	maintenance := BannerResponse{
+		ID:          1,
+		Message:     "Scheduled maintenance tonight from 22:00 to 00:00 UTC",
+		Severity:    string(banner.SeverityWarning),
+		StartsAt:    startsAt,
+		EndsAt:      &endsAt,
+		Dismissible: true,
+		Active:      true,
+	}
+	dismissible := true
+	request := BannerRequest{
+		Message:     maintenance.Message,
+		Severity:    maintenance.Severity,
+		StartsAt:    &startsAt,
+		EndsAt:      &endsAt,
+		Dismissible: &dismissible,
+	}
+	list := BannerListResponse{Banners: []BannerResponse{maintenance}, Total: 1}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/announcements/active",
+			Summary:         "List the active banners",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: list,
+			Errors:          withCommonErrors(),
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/announcements/{id}/dismiss",
+			Summary:        "Dismiss a banner",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/banners",
+			Summary:         "List banners",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: list,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/banners",
+			Summary:         "Create a banner",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: maintenance,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/admin/banners/{id}",
+			Summary:         "Update a banner",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: maintenance,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/admin/banners/{id}",
+			Summary:        "Delete a banner",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// stripeWebhookMaxBodySize bounds the size of Stripe webhook payloads
+const stripeWebhookMaxBodySize = 1 << 16
+
+// BillingHandler handles subscription plans: the Stripe webhook keeping
+// subscriptions up to date and the subscription of the signed-in user
+type BillingHandler struct {
+	billingService billing.Service
+	webhooks       billing.WebhookVerifier
+	logger         service.Logger
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService billing.Service, webhooks billing.WebhookVerifier, logger service.Logger) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+		webhooks:       webhooks,
+		logger:         logger,
+	}
+}
+
+// SubscriptionResponse represents the plan of the authenticated user
+type SubscriptionResponse struct {
+	Plan              billing.Plan   `json:"plan"`
+	Status            billing.Status `json:"status,omitempty"`
+	CurrentPeriodEnd  *time.Time     `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd bool           `json:"cancel_at_period_end"`
+	Limits            billing.Limits `json:"limits"`
+	Usage             billing.Usage  `json:"usage"`
+}
+
+// WebhookResponse acknowledges a webhook event
+type WebhookResponse struct {
+	Received bool `json:"received"`
+}
+
+// GetSubscription handles GET /api/v1/users/me/subscription
+// @Summary Get the subscription
+// @Description Get the plan of the authenticated user with its limits and the current usage. Users without a subscription are on the free plan.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SubscriptionResponse "Subscription"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/subscription [get]
+func (h *BillingHandler) GetSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	overview, err := h.billingService.GetOverview(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to get subscription", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := SubscriptionResponse{
+		Plan:   overview.Plan,
+		Limits: overview.Limits,
+		Usage:  overview.Usage,
+	}
+	if s := overview.Subscription; s != nil {
+		response.Status = s.Status
+		response.CurrentPeriodEnd = s.CurrentPeriodEnd
+		response.CancelAtPeriodEnd = s.CancelAtPeriodEnd
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// StripeWebhook handles POST /api/v1/billing/stripe/webhook
+// @Summary Receive Stripe events
+// @Description Webhook endpoint for Stripe. Subscription created, updated and deleted events change the plan of the user they belong to; other events are acknowledged and ignored. Requests must carry a valid Stripe-Signature header.
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param Stripe-Signature header string true "Stripe webhook signature"
+// @Success 200 {object} WebhookResponse "Event received"
+// @Failure 400 {object} ErrorResponse "Invalid signature or event"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/billing/stripe/webhook [post]
+func (h *BillingHandler) StripeWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// The signature covers the exact payload, so it is read before decoding
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, stripeWebhookMaxBodySize))
+	if err != nil {
+		h.logger.Warn(ctx, "failed to read webhook payload", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	event, err := h.webhooks.ParseEvent(payload, c.Request().Header.Get("Stripe-Signature"))
+	if err != nil {
+		h.logger.Warn(ctx, "rejected webhook event", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	if event == nil {
+		return c.JSON(http.StatusOK, WebhookResponse{Received: true})
+	}
+
+	if err := h.billingService.ApplyEvent(ctx, event); err != nil {
+		h.logger.Error(ctx, "failed to apply subscription event", "eventID", event.EventID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, WebhookResponse{Received: true})
+}
+
+// RouteDocs returns examples and error codes for the billing routes
+func (h *BillingHandler) RouteDocs() []RouteDoc {
+	periodEnd := time.Date(2024, 2, 15, 10, 30, 0, 0, time.UTC)
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/me/subscription",
+			Summary:        "Get the subscription",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: SubscriptionResponse{
+				Plan:             billing.PlanPro,
+				Status:           billing.StatusActive,
+				CurrentPeriodEnd: &periodEnd,
+				Limits:           billing.LimitsOf(billing.PlanPro),
+				Usage:            billing.Usage{Posts: 42},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/billing/stripe/webhook",
+			Summary:         "Receive Stripe events",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: WebhookResponse{Received: true},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// BookmarkHandler handles the reading lists of users
+type BookmarkHandler struct {
+	bookmarkService post.BookmarkService
+	pagination      service.PageLimits
+	logger          service.Logger
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(bookmarkService post.BookmarkService, pagination service.PageLimits, logger service.Logger) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+		pagination:      pagination,
+		logger:          logger,
+	}
+}
+
+// BookmarkResponse represents a post in the reading list
+type BookmarkResponse struct {
+	PostID       int       `json:"post_id"`
+	Title        string    `json:"title"`
+	Slug         string    `json:"slug"`
+	AuthorID     int       `json:"author_id"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// BookmarkListResponse represents a page of the reading list
+type BookmarkListResponse struct {
+	Bookmarks []BookmarkResponse `json:"bookmarks"`
+	Total     int                `json:"total"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+}
+
+// Bookmark handles POST /api/v1/posts/{id}/bookmark
+// @Summary Bookmark a post
+// @Description Add a published post to the reading list of the authenticated user; bookmarking it again has no effect
+// @Tags posts
+// @Param id path int true "Post ID"
+// @Success 204 "Post bookmarked"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/bookmark [post]
+func (h *BookmarkHandler) Bookmark(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.bookmarkService.Bookmark(ctx, userID, postID); err != nil {
+		h.logger.Warn(ctx, "Failed to bookmark post", "post_id", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unbookmark handles DELETE /api/v1/posts/{id}/bookmark
+// @Summary Remove a bookmark
+// @Description Remove a post from the reading list of the authenticated user; removing a post not bookmarked has no effect
+// @Tags posts
+// @Param id path int true "Post ID"
+// @Success 204 "Bookmark removed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/bookmark [delete]
+func (h *BookmarkHandler) Unbookmark(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.bookmarkService.Unbookmark(ctx, userID, postID); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListBookmarks handles GET /api/v1/users/me/bookmarks
+// @Summary List bookmarks
+// @Description List the published posts in the reading list of the authenticated user, most recently bookmarked first
+// @Tags users
+// @Produce json
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of posts to skip (default: 0)"
+// @Success 200 {object} BookmarkListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/bookmarks [get]
+func (h *BookmarkHandler) ListBookmarks(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	limit, offset := parsePage(c, h.pagination)
+	entries, err := h.bookmarkService.ListBookmarks(ctx, userID, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to list bookmarks", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := BookmarkListResponse{
+		Bookmarks: make([]BookmarkResponse, len(entries)),
+		Total:     len(entries),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for i, e := range entries {
+		response.Bookmarks[i] = BookmarkResponse{
+			PostID:       e.PostID,
+			Title:        e.Title,
+			Slug:         e.Slug,
+			AuthorID:     e.AuthorID,
+			BookmarkedAt: e.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// RouteDocs returns examples and error codes for the bookmark routes
+func (h *BookmarkHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/posts/{id}/bookmark",
+			Summary:        "Bookmark a post",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/posts/{id}/bookmark",
+			Summary:        "Remove a bookmark",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/me/bookmarks",
+			Summary:        "List bookmarks",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: BookmarkListResponse{
+				Bookmarks: []BookmarkResponse{{PostID: 1, Title: "Getting Started with Go", Slug: "getting-started-with-go", AuthorID: 1, BookmarkedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)}},
+				Total:     1,
+				Limit:     10,
+				Offset:    0,
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,8 @@ import (
 
 	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/errors"
 	"blog-platform/internal/infrastructure/http/middleware"
 )
@@ -15,52 +18,107 @@ import (
 // CommentHandler handles HTTP requests for comment operations
 type CommentHandler struct {
 	commentService comment.Service
+	pagination     service.PageLimits
 	logger         service.Logger
 }
 
 // NewCommentHandler creates a new comment handler
-func NewCommentHandler(commentService comment.Service, logger service.Logger) *CommentHandler {
+func NewCommentHandler(commentService comment.Service, pagination service.PageLimits, logger service.Logger) *CommentHandler {
 	return &CommentHandler{
 		commentService: commentService,
+		pagination:     pagination,
 		logger:         logger,
 	}
 }
 
 // CreateCommentRequest represents the request payload for creating a comment
 type CreateCommentRequest struct {
-	AuthorName string `json:"author_name" validate:"required,min=1,max=255,no_html,safe_string"`
-	Content    string `json:"content" validate:"required,min=3,max=1000,no_html"`
+	AuthorName string `json:"author_name" validate:"required,min=1,max=255,no_html,safe_string" sanitize:"strict"`
+	Content    string `json:"content" validate:"required,min=3,max=1000,no_html" sanitize:"basic"`
+	// ParentID makes the comment a reply to another comment on the post
+	ParentID *int `json:"parent_id,omitempty" validate:"omitempty,min=1"`
+	// ParentClientID names the parent by the client ID it was created
+	// with, in place of ParentID
+	ParentClientID string `json:"parent_client_id,omitempty" validate:"omitempty,client_id"`
+	// ClientID is a UUID the client generated for the comment. Resending
+	// the request with it returns the comment already created instead of a
+	// copy.
+	ClientID string `json:"client_id,omitempty" validate:"omitempty,client_id"`
+}
+
+// UpdateCommentRequest represents the request payload for editing a comment
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,min=3,max=1000,no_html" sanitize:"basic"`
+}
+
+// RejectCommentRequest represents the request payload for rejecting a
+// comment; the body may be left out
+type RejectCommentRequest struct {
+	// Spam marks the comment as spam rather than just rejected
+	Spam bool `json:"spam"`
 }
 
 // CommentResponse represents a comment in API responses
 type CommentResponse struct {
 	ID         int    `json:"id"`
+	// ClientID is the UUID the client created the comment under, if any
+	ClientID   string `json:"client_id,omitempty"`
 	PostID     int    `json:"post_id"`
+	ParentID   *int   `json:"parent_id,omitempty"`
+	// UserID is set for comments by registered users
+	UserID     *int   `json:"user_id,omitempty"`
 	AuthorName string `json:"author_name"`
 	Content    string `json:"content"`
+	// Status is pending until an admin approves the comment when
+	// moderation is required
+	Status     string `json:"status"`
 	CreatedAt  string `json:"created_at"`
 }
 
+// ThreadedCommentResponse represents a comment with the replies to it
+type ThreadedCommentResponse struct {
+	CommentResponse
+	// ReplyCount counts the replies at any depth
+	ReplyCount int                       `json:"reply_count"`
+	Replies    []ThreadedCommentResponse `json:"replies"`
+}
+
+// ThreadedCommentListResponse represents the response for listing comments
+// as threads; pagination applies to the top-level comments
+type ThreadedCommentListResponse struct {
+	Comments []ThreadedCommentResponse `json:"comments"`
+	Total    int                       `json:"total"`
+	Limit    int                       `json:"limit"`
+	Offset   int                       `json:"offset"`
+}
+
 // CommentListResponse represents the response for listing comments
 type CommentListResponse struct {
 	Comments []CommentResponse `json:"comments"`
 	Total    int               `json:"total"`
 	Limit    int               `json:"limit"`
 	Offset   int               `json:"offset"`
+	// NextCursor continues a cursor paginated list; it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // CreateComment handles POST /api/v1/posts/{id}/comments
 // @Summary Create a new comment
-// @Description Create a new comment for a specific post
+// @Description Create a new comment for a specific post, or a reply to one of its comments with parent_id. Replies can be nested 3 levels deep. Comments sent with a valid token are linked to the signed-in user, who can then edit and delete them; comments without one are guest comments. When moderation is required, new and edited comments are pending until an admin approves them. Offline clients can send a client_id UUID: resending the request with it returns the comment already created with 200, and replies can name their parent by parent_client_id.
 // @Tags comments
 // @Accept json
 // @Produce json
-// @Param id path int true "Post ID"
+// @Param id path string true "Post ID or client ID"
 // @Param comment body CreateCommentRequest true "Comment data"
+// @Param Idempotency-Key header string false "Key of the request, so retries with it return the first response instead of creating duplicates"
 // @Success 201 {object} CommentResponse
+// @Success 200 {object} CommentResponse "Already created under the client ID"
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse "Idempotency-Key already sent with a different request"
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /api/v1/posts/{id}/comments [post]
 func (h *CommentHandler) CreateComment(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -81,45 +139,75 @@ func (h *CommentHandler) CreateComment(c echo.Context) error {
 	}
 	
 	// Sanitize input
-	req.AuthorName = middleware.SanitizeInput(req.AuthorName)
-	req.Content = middleware.SanitizeInput(req.Content)
+	middleware.SanitizeFields(&req)
 	
 	if err := c.Validate(&req); err != nil {
 		h.logger.Warn(ctx, "Comment validation failed", "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 	
-	h.logger.Info(ctx, "Creating comment", "post_id", postID, "author_name", req.AuthorName)
+	// The user is identified by the auth middleware when a valid token is
+	// sent; guests comment without one
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	h.logger.Info(ctx, "Creating comment", "post_id", postID, "user_id", userID, "author_name", req.AuthorName)
 	
+	// A parent created offline may only be known by its client ID
+	if req.ParentClientID != "" {
+		if req.ParentID != nil {
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
+		parent, err := h.commentService.GetCommentByClientID(ctx, req.ParentClientID)
+		if err != nil {
+			if stderrors.Is(err, comment.ErrCommentNotFound) {
+				err = comment.ErrParentNotFound
+			}
+			return errors.HandleError(c, err)
+		}
+		req.ParentID = &parent.ID
+	}
+
 	// Create comment
-	createdComment, err := h.commentService.AddComment(ctx, postID, req.AuthorName, req.Content)
+	var createdComment *comment.Comment
+	switch {
+	case req.ClientID != "":
+		parentID := 0
+		if req.ParentID != nil {
+			parentID = *req.ParentID
+		}
+		var created bool
+		createdComment, created, err = h.commentService.AddClientComment(ctx, postID, parentID, userID, role, req.ClientID, req.AuthorName, req.Content)
+		if err == nil && !created {
+			// A retried request: answer with the comment created the first time
+			return c.JSON(http.StatusOK, toCommentResponse(createdComment))
+		}
+	case req.ParentID != nil:
+		createdComment, err = h.commentService.ReplyToComment(ctx, postID, *req.ParentID, userID, role, req.AuthorName, req.Content)
+	default:
+		createdComment, err = h.commentService.AddComment(ctx, postID, userID, role, req.AuthorName, req.Content)
+	}
 	if err != nil {
 		h.logger.Error(ctx, "Failed to create comment", "error", err.Error(), "post_id", postID)
 		return errors.HandleError(c, err)
 	}
 	
-	response := CommentResponse{
-		ID:         createdComment.ID,
-		PostID:     createdComment.PostID,
-		AuthorName: createdComment.AuthorName,
-		Content:    createdComment.Content,
-		CreatedAt:  createdComment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-	
 	h.logger.Info(ctx, "Comment created successfully", "comment_id", createdComment.ID, "post_id", postID)
-	return c.JSON(http.StatusCreated, response)
+	return c.JSON(http.StatusCreated, toCommentResponse(createdComment))
 }
 
 // GetCommentsByPost handles GET /api/v1/posts/{id}/comments
 // @Summary Get comments for a post
-// @Description Get all comments for a specific post with pagination
+// @Description Get all comments for a specific post with pagination, oldest first. Sending the cursor parameter, empty for the first page, switches to cursor pagination: next_cursor fetches the following page. With view=threaded, pages of top-level comments are returned with their replies nested (offset pagination only).
 // @Tags comments
 // @Produce json
 // @Param id path int true "Post ID"
-// @Param limit query int false "Number of comments to return (default: 10, max: 100)"
+// @Param limit query int false "Number of comments to return (default: 10, max: 100, configurable)"
 // @Param offset query int false "Number of comments to skip (default: 0)"
+// @Param cursor query string false "Cursor pagination: empty for the first page, then next_cursor of the previous page"
+// @Param view query string false "flat (default) or threaded" Enums(flat, threaded)
 // @Success 200 {object} CommentListResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Post not found, or a draft or private post of another user"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/posts/{id}/comments [get]
 func (h *CommentHandler) GetCommentsByPost(c echo.Context) error {
@@ -134,24 +222,44 @@ func (h *CommentHandler) GetCommentsByPost(c echo.Context) error {
 	}
 	
 	// Parse pagination parameters
-	limit := 10 // default
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
+	limit, offset := parsePage(c, h.pagination)
+	cursor, cursorMode, err := parseCursor(c)
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment list cursor", "cursor", c.QueryParam("cursor"))
+		return errors.HandleError(c, err)
 	}
 	
-	offset := 0 // default
-	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
+	threaded := false
+	switch c.QueryParam("view") {
+	case "", "flat":
+	case "threaded":
+		threaded = true
+	default:
+		h.logger.Warn(ctx, "Invalid comment list view", "view", c.QueryParam("view"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if threaded {
+		if cursorMode {
+			h.logger.Warn(ctx, "Cursor pagination requested for threaded comments", "post_id", postID)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
 		}
+		return h.getThreadsByPost(c, postID, limit, offset)
 	}
 	
-	h.logger.Info(ctx, "Getting comments for post", "post_id", postID, "limit", limit, "offset", offset)
+	h.logger.Info(ctx, "Getting comments for post", "post_id", postID, "limit", limit, "offset", offset, "cursor_mode", cursorMode)
 	
-	// Get comments
-	comments, err := h.commentService.GetCommentsByPost(ctx, postID, limit, offset)
+	// Get comments; drafts and private posts only show theirs to their
+	// author and admins
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	var comments []*comment.Comment
+	var next keyset.Cursor
+	if cursorMode {
+		offset = 0
+		comments, next, err = h.commentService.GetCommentsByPostAfter(ctx, postID, userID, role, cursor, limit)
+	} else {
+		comments, err = h.commentService.GetCommentsByPost(ctx, postID, userID, role, limit, offset)
+	}
 	if err != nil {
 		h.logger.Error(ctx, "Failed to get comments", "error", err.Error(), "post_id", postID)
 		return errors.HandleError(c, err)
@@ -160,22 +268,378 @@ func (h *CommentHandler) GetCommentsByPost(c echo.Context) error {
 	// Convert to response format
 	commentResponses := make([]CommentResponse, len(comments))
 	for i, comment := range comments {
-		commentResponses[i] = CommentResponse{
-			ID:         comment.ID,
-			PostID:     comment.PostID,
-			AuthorName: comment.AuthorName,
-			Content:    comment.Content,
-			CreatedAt:  comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+		commentResponses[i] = toCommentResponse(comment)
 	}
 	
 	response := CommentListResponse{
-		Comments: commentResponses,
-		Total:    len(commentResponses),
-		Limit:    limit,
-		Offset:   offset,
+		Comments:   commentResponses,
+		Total:      len(commentResponses),
+		Limit:      limit,
+		Offset:     offset,
+		NextCursor: next.Encode(),
 	}
 	
 	h.logger.Info(ctx, "Comments retrieved successfully", "post_id", postID, "count", len(comments))
 	return c.JSON(http.StatusOK, response)
 }
+
+// getThreadsByPost responds with a page of top-level comments and their replies
+func (h *CommentHandler) getThreadsByPost(c echo.Context, postID, limit, offset int) error {
+	ctx := c.Request().Context()
+
+	h.logger.Info(ctx, "Getting comment threads for post", "post_id", postID, "limit", limit, "offset", offset)
+
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	threads, err := h.commentService.GetThreadsByPost(ctx, postID, userID, role, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to get comment threads", "error", err.Error(), "post_id", postID)
+		return errors.HandleError(c, err)
+	}
+
+	response := ThreadedCommentListResponse{
+		Comments: toThreadedCommentResponses(threads),
+		Total:    len(threads),
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	h.logger.Info(ctx, "Comment threads retrieved successfully", "post_id", postID, "count", len(threads))
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateComment handles PUT /api/v1/comments/{id}
+// @Summary Update a comment
+// @Description Edit the content of a comment (only by the registered user who wrote it or an admin). Guest comments can only be edited by admins.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param comment body UpdateCommentRequest true "Comment content"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) UpdateComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment ID in path", "comment_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Parse and validate request
+	var req UpdateCommentRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "Failed to bind comment update request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Sanitize input
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Warn(ctx, "Comment update validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Role is set by the auth middleware; admins may update any comment
+	role, _ := c.Get("user_role").(user.Role)
+	updatedComment, err := h.commentService.UpdateComment(ctx, commentID, userID, role, req.Content)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to update comment", "comment_id", commentID, "user_id", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "Comment updated successfully", "comment_id", commentID, "user_id", userID)
+	return c.JSON(http.StatusOK, toCommentResponse(updatedComment))
+}
+
+// DeleteComment handles DELETE /api/v1/comments/{id}
+// @Summary Delete a comment
+// @Description Delete a comment and the replies to it (only by the registered user who wrote it or an admin). Guest comments can only be deleted by admins.
+// @Tags comments
+// @Param id path int true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse comment ID
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment ID in path", "comment_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Role is set by the auth middleware; admins may delete any comment
+	role, _ := c.Get("user_role").(user.Role)
+	if err := h.commentService.DeleteComment(ctx, commentID, userID, role); err != nil {
+		h.logger.Error(ctx, "Failed to delete comment", "comment_id", commentID, "user_id", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "Comment deleted successfully", "comment_id", commentID, "user_id", userID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListModerationQueue handles GET /api/v1/admin/comments
+// @Summary List comments for moderation
+// @Description List the comments with a moderation status, oldest first, so the longest waiting are reviewed first (admins only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Moderation status (default: pending)" Enums(pending, approved, rejected, spam)
+// @Param limit query int false "Number of comments to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of comments to skip (default: 0)"
+// @Success 200 {object} CommentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/comments [get]
+func (h *CommentHandler) ListModerationQueue(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	status := comment.StatusPending
+	if value := c.QueryParam("status"); value != "" {
+		status = comment.Status(value)
+	}
+	limit, offset := parsePage(c, h.pagination)
+
+	comments, err := h.commentService.ListForModeration(ctx, status, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to list comments for moderation", "status", string(status), "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := CommentListResponse{
+		Comments: make([]CommentResponse, len(comments)),
+		Total:    len(comments),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	for i, cm := range comments {
+		response.Comments[i] = toCommentResponse(cm)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ApproveComment handles POST /api/v1/admin/comments/{id}/approve
+// @Summary Approve a comment
+// @Description Approve a comment so it is shown publicly (admins only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/comments/{id}/approve [post]
+func (h *CommentHandler) ApproveComment(c echo.Context) error {
+	return h.moderateComment(c, comment.StatusApproved)
+}
+
+// RejectComment handles POST /api/v1/admin/comments/{id}/reject
+// @Summary Reject a comment
+// @Description Reject a comment, or mark it as spam with spam set, hiding it from public listings (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body RejectCommentRequest false "Rejection details"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/comments/{id}/reject [post]
+func (h *CommentHandler) RejectComment(c echo.Context) error {
+	var req RejectCommentRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(c.Request().Context(), "Failed to bind comment rejection request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if req.Spam {
+		return h.moderateComment(c, comment.StatusSpam)
+	}
+	return h.moderateComment(c, comment.StatusRejected)
+}
+
+// moderateComment sets the moderation status of the comment in the path
+func (h *CommentHandler) moderateComment(c echo.Context, status comment.Status) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment ID in path", "comment_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	moderated, err := h.commentService.ModerateComment(ctx, commentID, adminID, status)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to moderate comment", "comment_id", commentID, "status", string(status), "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toCommentResponse(moderated))
+}
+
+// toCommentResponse converts a comment to its response format
+func toCommentResponse(c *comment.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:         c.ID,
+		PostID:     c.PostID,
+		ParentID:   c.ParentID,
+		UserID:     c.UserID,
+		AuthorName: c.AuthorName,
+		Content:    c.Content,
+		Status:     string(c.Status),
+		CreatedAt:  c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if c.ClientID != nil {
+		response.ClientID = *c.ClientID
+	}
+	return response
+}
+
+// toThreadedCommentResponses converts threads to their response format
+func toThreadedCommentResponses(threads []*comment.Thread) []ThreadedCommentResponse {
+	responses := make([]ThreadedCommentResponse, len(threads))
+	for i, thread := range threads {
+		responses[i] = ThreadedCommentResponse{
+			CommentResponse: toCommentResponse(thread.Comment),
+			ReplyCount:      thread.ReplyCount(),
+			Replies:         toThreadedCommentResponses(thread.Replies),
+		}
+	}
+	return responses
+}
+
+// RouteDocs returns examples and error codes for the comment routes
+func (h *CommentHandler) RouteDocs() []RouteDoc {
+	exampleComment := CommentResponse{
+		ID:         1,
+		PostID:     1,
+		AuthorName: "Jane Smith",
+		Content:    "Great post!",
+		Status:     string(comment.StatusApproved),
+		CreatedAt:  "2024-01-15T11:00:00Z",
+	}
+	parentID, userID := exampleComment.ID, 2
+	exampleReply := CommentResponse{
+		ID:         2,
+		PostID:     1,
+		ParentID:   &parentID,
+		UserID:     &userID,
+		AuthorName: "John Doe",
+		Content:    "Thanks, glad it helped!",
+		Status:     string(comment.StatusApproved),
+		CreatedAt:  "2024-01-15T11:30:00Z",
+	}
+	pendingComment := exampleComment
+	pendingComment.ID = 3
+	pendingComment.Status = string(comment.StatusPending)
+	rejectedComment := pendingComment
+	rejectedComment.Status = string(comment.StatusSpam)
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts/{id}/comments",
+			Summary:         "Create a comment on a post",
+			RequestExample:  CreateCommentRequest{AuthorName: exampleComment.AuthorName, Content: exampleComment.Content},
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: exampleComment,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeIdempotencyKeyReused, errors.ErrCodeIdempotencyKeyInUse),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/{id}/comments",
+			Summary:         "Get comments for a post; view=threaded nests replies under top-level comments",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: CommentListResponse{Comments: []CommentResponse{exampleComment, exampleReply}, Total: 2, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/comments/{id}",
+			Summary:         "Update a comment",
+			RequestExample:  UpdateCommentRequest{Content: exampleComment.Content},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleComment,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/comments/{id}",
+			Summary:        "Delete a comment",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/comments",
+			Summary:         "List comments for moderation, pending by default",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: CommentListResponse{Comments: []CommentResponse{pendingComment}, Total: 1, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/comments/{id}/approve",
+			Summary:         "Approve a comment",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleComment,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/comments/{id}/reject",
+			Summary:         "Reject a comment, or mark it as spam",
+			RequestExample:  RejectCommentRequest{Spam: true},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: rejectedComment,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
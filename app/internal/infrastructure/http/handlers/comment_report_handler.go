@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// CommentReportHandler handles abuse reports on comments
+type CommentReportHandler struct {
+	reportService comment.ReportService
+	logger        service.Logger
+}
+
+// NewCommentReportHandler creates a new comment report handler
+func NewCommentReportHandler(reportService comment.ReportService, logger service.Logger) *CommentReportHandler {
+	return &CommentReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// ReportCommentRequest represents the request payload for reporting a comment
+type ReportCommentRequest struct {
+	Reason  string `json:"reason" validate:"required,oneof=spam abuse off_topic other"`
+	Details string `json:"details,omitempty" validate:"max=500,no_html" sanitize:"basic"`
+}
+
+// CommentReportResponse represents a comment report in API responses
+type CommentReportResponse struct {
+	ID        int `json:"id"`
+	CommentID int `json:"comment_id"`
+	// ReporterID is set for reports by registered users
+	ReporterID *int   `json:"reporter_id,omitempty"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// CommentReportListResponse represents the reports on a comment
+type CommentReportListResponse struct {
+	Reports []CommentReportResponse `json:"reports"`
+	Total   int                     `json:"total"`
+}
+
+// ReportComment handles POST /api/v1/comments/{id}/report
+// @Summary Report a comment
+// @Description Report a comment as spam, abuse, off topic or for another reason; guests can report too. Each reader counts once per comment, and comments reaching the report threshold are hidden until an admin reviews them.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param report body ReportCommentRequest true "Report reason"
+// @Success 202 {object} CommentReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/comments/{id}/report [post]
+func (h *CommentReportHandler) ReportComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment ID in path", "comment_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req ReportCommentRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "Failed to bind comment report request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	middleware.SanitizeFields(&req)
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	// Guests report without a token and are told apart by their address
+	userID, _ := c.Get("user_id").(int)
+	report, err := h.reportService.ReportComment(ctx, commentID, userID, c.RealIP(), comment.ReportReason(req.Reason), req.Details)
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to report comment", "comment_id", commentID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, toCommentReportResponse(report))
+}
+
+// ListReports handles GET /api/v1/admin/comments/{id}/reports
+// @Summary List the reports on a comment
+// @Description List the reports on a comment, oldest first, to review it (admins only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Success 200 {object} CommentReportListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/comments/{id}/reports [get]
+func (h *CommentReportHandler) ListReports(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	commentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid comment ID in path", "comment_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	reports, err := h.reportService.ListReports(ctx, commentID)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to list comment reports", "comment_id", commentID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := CommentReportListResponse{
+		Reports: make([]CommentReportResponse, len(reports)),
+		Total:   len(reports),
+	}
+	for i, r := range reports {
+		response.Reports[i] = toCommentReportResponse(r)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// toCommentReportResponse converts a report to its response format
+func toCommentReportResponse(r *comment.Report) CommentReportResponse {
+	return CommentReportResponse{
+		ID:         r.ID,
+		CommentID:  r.CommentID,
+		ReporterID: r.ReporterID,
+		Reason:     string(r.Reason),
+		Details:    r.Details,
+		CreatedAt:  r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// RouteDocs returns examples and error codes for the comment report routes
+func (h *CommentReportHandler) RouteDocs() []RouteDoc {
+	guestReport := CommentReportResponse{
+		ID:        1,
+		CommentID: 3,
+		Reason:    string(comment.ReasonSpam),
+		Details:   "Links to a shop",
+		CreatedAt: "2024-01-15T12:00:00Z",
+	}
+	reporterID := 2
+	userReport := CommentReportResponse{
+		ID:         2,
+		CommentID:  3,
+		ReporterID: &reporterID,
+		Reason:     string(comment.ReasonAbuse),
+		CreatedAt:  "2024-01-15T12:30:00Z",
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/comments/{id}/report",
+			Summary:         "Report a comment",
+			RequestExample:  ReportCommentRequest{Reason: guestReport.Reason, Details: guestReport.Details},
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: guestReport,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/comments/{id}/reports",
+			Summary:         "List the reports on a comment",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: CommentReportListResponse{Reports: []CommentReportResponse{guestReport, userReport}, Total: 2},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// writeConditionalJSON responds with body as JSON, validated by an entity
+// tag hashed from tagged and by lastModified. tagged is the part of the body
+// that identifies the representation, leaving out counters that change on
+// every read. Requests whose If-None-Match or, without one,
+// If-Modified-Since header shows the client has the same representation get
+// 304 Not Modified instead. The tag is weak, as the compression middleware
+// may encode the body differently and counters may be stale.
+func writeConditionalJSON(c echo.Context, body, tagged any, lastModified time.Time) error {
+	encoded, err := json.Marshal(tagged)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(encoded)
+	opaque := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	header := c.Response().Header()
+	header.Set("ETag", "W/"+opaque)
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request(), opaque, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, body)
+}
+
+// notModified evaluates the conditional headers of a read. If-Modified-Since
+// is only considered without If-None-Match, which is more precise.
+func notModified(r *http.Request, opaque string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, opaque)
+	}
+	since, err := http.ParseTime(r.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+	return !lastModified.After(since)
+}
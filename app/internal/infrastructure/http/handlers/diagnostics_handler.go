@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/diagnostics"
+	"blog-platform/internal/infrastructure/feed"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/queue"
+)
+
+// DiagnosticsHandler reports the runtime state of the application to admins
+type DiagnosticsHandler struct {
+	collector *diagnostics.Collector
+	logger    service.Logger
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(collector *diagnostics.Collector, logger service.Logger) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		collector: collector,
+		logger:    logger,
+	}
+}
+
+// GetDiagnostics handles GET /api/v1/admin/diagnostics
+// @Summary Runtime diagnostics
+// @Description Report goroutines, memory, database pool utilization, cache hit rates, job queue depths and configuration fingerprints for incident triage. Secrets are redacted; subsystems that fail to report are listed under errors.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} diagnostics.Report
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/diagnostics [get]
+func (h *DiagnosticsHandler) GetDiagnostics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	report := h.collector.Collect(ctx)
+	for name, msg := range report.Errors {
+		h.logger.Warn(ctx, "diagnostics source failed", "source", name, "error", msg)
+	}
+
+	// Reports describe the moment they were taken
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, report)
+}
+
+// RouteDocs returns examples and error codes for the diagnostics routes
+func (h *DiagnosticsHandler) RouteDocs() []RouteDoc {
+	generatedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/admin/diagnostics",
+			Summary:        "Runtime diagnostics",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: diagnostics.Report{
+				GeneratedAt: generatedAt,
+				Uptime:      "72h14m5s",
+				Runtime: diagnostics.Runtime{
+					GoVersion:  "go1.24.0",
+					Goroutines: 42,
+					CPUs:       4,
+					Memory:     diagnostics.Memory{HeapAlloc: 12582912, HeapInuse: 16777216, HeapObjects: 81234, StackInuse: 983040, Sys: 41943040, TotalAlloc: 9663676416, NumGC: 1830, PauseTotalNs: 412000000},
+				},
+				Subsystems: map[string]any{
+					"database":   diagnostics.DBPool{MaxOpen: 25, Open: 6, InUse: 2, Idle: 4, Utilization: 0.08, WaitDuration: "0s"},
+					"feed_cache": feed.CacheStats{Enabled: true, Entries: 12, Hits: 930, Misses: 70, HitRate: 0.93},
+					"job_queue":  queue.Depths{Pending: 3, Due: 1},
+					"config":     map[string]string{"Server": "3f1c2a9b0d4e", "Database": "9a7e51c0b2f3"},
+				},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+	}
+}
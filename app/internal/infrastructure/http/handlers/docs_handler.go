@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// DocsHandler serves the error catalog and the OpenAPI document enriched
+// with the registered route examples
+type DocsHandler struct {
+	registry *RouteRegistry
+	spec     []byte
+	logger   service.Logger
+
+	once   sync.Once
+	doc    []byte
+	docErr error
+}
+
+// NewDocsHandler creates a new docs handler serving the OpenAPI 3 document
+// spec
+func NewDocsHandler(registry *RouteRegistry, spec []byte, logger service.Logger) *DocsHandler {
+	return &DocsHandler{
+		registry: registry,
+		spec:     spec,
+		logger:   logger,
+	}
+}
+
+// RouteErrors lists the error codes a single route can return
+type RouteErrors struct {
+	Method string             `json:"method"`
+	Path   string             `json:"path"`
+	Errors []errors.ErrorCode `json:"errors"`
+}
+
+// ErrorCatalogResponse represents the error catalog response
+type ErrorCatalogResponse struct {
+	Errors []errors.ErrorDefinition `json:"errors"`
+	Routes []RouteErrors            `json:"routes"`
+}
+
+// ListErrors godoc
+// @Summary List API error codes
+// @Description Retrieve every error code the API can return and the codes each route can produce
+// @Tags docs
+// @Produce json
+// @Success 200 {object} ErrorCatalogResponse
+// @Router /api/v1/errors [get]
+func (h *DocsHandler) ListErrors(c echo.Context) error {
+	routes := h.registry.Routes()
+	response := ErrorCatalogResponse{
+		Errors: errors.Catalog(),
+		Routes: make([]RouteErrors, 0, len(routes)),
+	}
+	for _, route := range routes {
+		response.Routes = append(response.Routes, RouteErrors{
+			Method: route.Method,
+			Path:   route.Path,
+			Errors: route.Errors,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// OpenAPI serves the OpenAPI document with route examples and error codes
+func (h *DocsHandler) OpenAPI(c echo.Context) error {
+	h.once.Do(func() {
+		h.doc, h.docErr = h.registry.ApplyToSpec(h.spec)
+	})
+	if h.docErr != nil {
+		h.logger.Error(c.Request().Context(), "failed to build OpenAPI document", "error", h.docErr)
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, h.doc)
+}
+
+// RouteDocs returns examples and error codes for the documentation routes
+func (h *DocsHandler) RouteDocs() []RouteDoc {
+	notFound, _ := errors.LookupErrorCode(errors.ErrCodeNotFound)
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/errors",
+			Summary:        "List API error codes",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: ErrorCatalogResponse{
+				Errors: []errors.ErrorDefinition{notFound},
+				Routes: []RouteErrors{{Method: http.MethodGet, Path: "/api/v1/posts/{id}", Errors: []errors.ErrorCode{errors.ErrCodeNotFound}}},
+			},
+			Errors: withCommonErrors(),
+		},
+	}
+}
@@ -0,0 +1,463 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// Export formats
+const (
+	ExportFormatJSON   = "json"
+	ExportFormatZIP    = "zip"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// MIMEApplicationNDJSON is the content type of newline-delimited JSON
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// ExportHandler handles downloads of the data of the current account
+type ExportHandler struct {
+	exportService export.Service
+	logger        service.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService export.Service, logger service.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// ExportResponse represents the data of an account
+type ExportResponse struct {
+	ExportedAt string        `json:"exported_at"`
+	Account    ExportAccount `json:"account"`
+	// Posts holds every post of the account, drafts included, oldest first
+	Posts []PostResponse `json:"posts"`
+	// Comments holds every comment of the account, whatever its moderation
+	// status, oldest first
+	Comments []CommentResponse `json:"comments"`
+}
+
+// ExportAccount represents the account and public profile of an export
+type ExportAccount struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Bio       string `json:"bio,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SiteExportPost represents a post of a site export, one per line of
+// newline-delimited JSON exports
+type SiteExportPost struct {
+	Post PostResponse `json:"post"`
+	// Author is left out for posts whose author account is gone
+	Author *SiteExportAuthor `json:"author,omitempty"`
+	// Comments holds every comment on the post, whatever its moderation
+	// status, oldest first
+	Comments []CommentResponse `json:"comments"`
+}
+
+// SiteExportAuthor represents the author of an exported post
+type SiteExportAuthor struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// exportFrontMatter is the YAML front matter of posts in Markdown exports,
+// in the fields POST /api/v1/admin/import reads back
+type exportFrontMatter struct {
+	Title       string     `yaml:"title"`
+	Slug        string     `yaml:"slug"`
+	Date        *time.Time `yaml:"date,omitempty"`
+	Draft       bool       `yaml:"draft,omitempty"`
+	Tags        []string   `yaml:"tags,omitempty"`
+	Author      string     `yaml:"author,omitempty"`
+	AuthorEmail string     `yaml:"author_email,omitempty"`
+}
+
+// Export handles GET /api/v1/users/me/export
+// @Summary Export your data
+// @Description Download your account, profile, posts (drafts included) and comments as a JSON document, or with format=zip as a ZIP archive of account.json, posts.json, comments.json and a Markdown file per post.
+// @Tags users
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} ExportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/export [get]
+func (h *ExportHandler) Export(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	if format != ExportFormatJSON && format != ExportFormatZIP {
+		h.logger.Warn(ctx, "invalid export format", "format", format)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	archive, err := h.exportService.Export(ctx, userID)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	response := toExportResponse(archive)
+
+	// The archive is streamed; a failure past this point can only cut the
+	// download short
+	name := fmt.Sprintf("export-%d-%s.%s", userID, archive.ExportedAt.UTC().Format("20060102"), format)
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	header.Set(echo.HeaderCacheControl, "no-store")
+	if format == ExportFormatZIP {
+		header.Set(echo.HeaderContentType, "application/zip")
+		c.Response().WriteHeader(http.StatusOK)
+		err = writeExportZIP(c.Response(), archive, response)
+	} else {
+		header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		err = writeExportJSON(c.Response(), response)
+	}
+	if err != nil {
+		h.logger.Error(ctx, "failed to write export", "userID", userID, "format", format, "error", err.Error())
+	}
+	return nil
+}
+
+// ExportSite handles GET /api/v1/admin/export
+// @Summary Export site content
+// @Description Download every post, drafts included, with its author and all its comments, for backups and migrations to static site generators. The export is streamed as newline-delimited JSON with a post per line, or with format=zip as a ZIP archive of a Markdown file per post (posts/{slug}.md) with YAML front matter (title, slug, date, draft, tags, author, author_email) that POST /admin/import reads back, and the comments of each post in comments/{slug}.json. Posts are exported in ID order.
+// @Tags admin
+// @Produce application/x-ndjson
+// @Produce application/zip
+// @Param format query string false "ndjson (default) or zip"
+// @Param author_id query int false "Only posts of this author"
+// @Param from query string false "Only posts created at or after this RFC 3339 time"
+// @Param to query string false "Only posts created at or before this RFC 3339 time"
+// @Success 200 {object} SiteExportPost
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/export [get]
+func (h *ExportHandler) ExportSite(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = ExportFormatNDJSON
+	}
+	if format != ExportFormatNDJSON && format != ExportFormatZIP {
+		h.logger.Warn(ctx, "invalid site export format", "format", format)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	filter, err := parseSiteExportFilter(c)
+	if err != nil {
+		h.logger.Warn(ctx, "invalid site export filter", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Nothing is written until the first post is exported, so errors before
+	// it still get an error response; later ones can only cut the download
+	// short
+	var zw *zip.Writer
+	started := false
+	start := func() {
+		started = true
+		name := fmt.Sprintf("site-export-%s.%s", time.Now().UTC().Format("20060102"), format)
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+		header.Set(echo.HeaderCacheControl, "no-store")
+		if format == ExportFormatZIP {
+			header.Set(echo.HeaderContentType, "application/zip")
+			zw = zip.NewWriter(c.Response())
+		} else {
+			header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		}
+		c.Response().WriteHeader(http.StatusOK)
+	}
+	encoder := json.NewEncoder(c.Response())
+
+	err = h.exportService.ExportSite(ctx, adminID, filter, func(entry *export.Entry) error {
+		if !started {
+			start()
+		}
+		if zw != nil {
+			return writeExportEntryZIP(zw, entry)
+		}
+		return encoder.Encode(toSiteExportPost(entry))
+	})
+	if err != nil && !started {
+		return errors.HandleError(c, err)
+	}
+	if err == nil && !started {
+		start()
+	}
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		h.logger.Error(ctx, "failed to write site export", "adminID", adminID, "format", format, "error", err.Error())
+	}
+	return nil
+}
+
+// parseSiteExportFilter reads the author_id, from and to query parameters
+func parseSiteExportFilter(c echo.Context) (export.SiteFilter, error) {
+	var filter export.SiteFilter
+
+	if value := c.QueryParam("author_id"); value != "" {
+		authorID, err := strconv.Atoi(value)
+		if err != nil || authorID <= 0 {
+			return filter, errors.ErrInvalidRequest
+		}
+		filter.AuthorID = authorID
+	}
+
+	for param, bound := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.QueryParam(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, err
+			}
+			*bound = &t
+		}
+	}
+
+	return filter, nil
+}
+
+// writeExportEntryZIP adds a post of a site export to a ZIP archive as
+// Markdown with front matter, followed by its comments if it has any
+func writeExportEntryZIP(zw *zip.Writer, entry *export.Entry) error {
+	p := entry.Post
+	meta := exportFrontMatter{
+		Title: p.Title,
+		Slug:  p.Slug,
+		Date:  p.PublishedAt,
+		Draft: p.Status != post.StatusPublished,
+		Tags:  p.Tags,
+	}
+	if entry.Author != nil {
+		meta.Author = entry.Author.Name
+		meta.AuthorEmail = entry.Author.Email
+	}
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	f, err := zw.Create(fmt.Sprintf("posts/%s.md", p.Slug))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "---\n%s---\n\n%s\n", header, p.Content); err != nil {
+		return err
+	}
+
+	if len(entry.Comments) == 0 {
+		return nil
+	}
+	f, err = zw.Create(fmt.Sprintf("comments/%s.json", p.Slug))
+	if err != nil {
+		return err
+	}
+	return writeExportJSON(f, toSiteExportPost(entry).Comments)
+}
+
+// toSiteExportPost converts a post of a site export to its response format
+func toSiteExportPost(entry *export.Entry) SiteExportPost {
+	response := SiteExportPost{
+		Post:     toPostResponse(entry.Post),
+		Comments: make([]CommentResponse, len(entry.Comments)),
+	}
+	if entry.Author != nil {
+		response.Author = &SiteExportAuthor{ID: entry.Author.ID, Name: entry.Author.Name, Email: entry.Author.Email}
+	}
+	for i, cm := range entry.Comments {
+		response.Comments[i] = toCommentResponse(cm)
+	}
+	return response
+}
+
+// writeExportJSON writes export data as indented JSON
+func writeExportJSON(w io.Writer, data any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// writeExportZIP writes the export as a ZIP archive with a JSON file per
+// kind of data and the content of each post as Markdown
+func writeExportZIP(w io.Writer, archive *export.Archive, response ExportResponse) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"account.json", response.Account},
+		{"posts.json", response.Posts},
+		{"comments.json", response.Comments},
+	}
+	for _, file := range files {
+		f, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if err := writeExportJSON(f, file.data); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range archive.Posts {
+		f, err := zw.Create(fmt.Sprintf("posts/%d-%s.md", p.ID, p.Slug))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(f, "# %s\n\n%s\n", p.Title, p.Content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// toExportResponse converts an archive to its response format
+func toExportResponse(archive *export.Archive) ExportResponse {
+	u := archive.Account
+	response := ExportResponse{
+		ExportedAt: archive.ExportedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Account: ExportAccount{
+			ID:        u.ID,
+			Name:      u.Name,
+			Email:     u.Email,
+			Role:      string(u.Role),
+			CreatedAt: u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		},
+		Posts:    make([]PostResponse, len(archive.Posts)),
+		Comments: make([]CommentResponse, len(archive.Comments)),
+	}
+	if archive.Profile != nil {
+		response.Account.Bio = archive.Profile.Bio
+		response.Account.AvatarURL = archive.Profile.AvatarURL
+	}
+	for i, p := range archive.Posts {
+		response.Posts[i] = toPostResponse(p)
+	}
+	for i, cm := range archive.Comments {
+		response.Comments[i] = toCommentResponse(cm)
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the export route
+func (h *ExportHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/me/export",
+			Summary:        "Export your data",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: ExportResponse{
+				ExportedAt: "2024-02-01T12:00:00Z",
+				Account: ExportAccount{
+					ID:        1,
+					Name:      "John Doe",
+					Email:     "john@example.com",
+					Role:      "author",
+					Bio:       "Writing about Go.",
+					CreatedAt: "2024-01-01T09:00:00Z",
+				},
+				Posts: []PostResponse{{
+					ID:        1,
+					Title:     "My First Post",
+					Slug:      "my-first-post",
+					Content:   "This is the content of my first post.",
+					AuthorID:  1,
+					Access:    "public",
+					Status:    "published",
+					Tags:      []string{"go"},
+					CreatedAt: "2024-01-15T10:30:00Z",
+					UpdatedAt: "2024-01-15T10:30:00Z",
+				}},
+				Comments: []CommentResponse{{
+					ID:         7,
+					PostID:     4,
+					AuthorName: "John Doe",
+					Content:    "Thanks for the write-up!",
+					Status:     "approved",
+					CreatedAt:  "2024-01-20T18:00:00Z",
+				}},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/admin/export",
+			Summary:        "Export site content",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: SiteExportPost{
+				Post: PostResponse{
+					ID:          1,
+					Title:       "My First Post",
+					Slug:        "my-first-post",
+					Content:     "This is the content of my first post.",
+					AuthorID:    1,
+					Access:      "public",
+					Status:      "published",
+					PublishedAt: "2024-01-15T10:30:00Z",
+					Timezone:    "UTC",
+					Tags:        []string{"go"},
+					CreatedAt:   "2024-01-15T10:30:00Z",
+					UpdatedAt:   "2024-01-15T10:30:00Z",
+				},
+				Author: &SiteExportAuthor{ID: 1, Name: "John Doe", Email: "john@example.com"},
+				Comments: []CommentResponse{{
+					ID:         7,
+					PostID:     1,
+					AuthorName: "Jane Smith",
+					Content:    "Thanks for the write-up!",
+					Status:     "approved",
+					CreatedAt:  "2024-01-20T18:00:00Z",
+				}},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/infrastructure/feed"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// FeedHandler serves RSS and Atom feeds of posts and comments and the preferences
+// customizing them
+type FeedHandler struct {
+	generator   *feed.Generator
+	preferences preference.Service
+	logger      service.Logger
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(generator *feed.Generator, preferences preference.Service, logger service.Logger) *FeedHandler {
+	return &FeedHandler{
+		generator:   generator,
+		preferences: preferences,
+		logger:      logger,
+	}
+}
+
+// FeedPreferenceRequest represents the feed preference request payload;
+// empty fields restore the generated title or description
+type FeedPreferenceRequest struct {
+	Title       string `json:"title" validate:"max=200,no_html,safe_string" sanitize:"strict"`
+	Description string `json:"description" validate:"max=1000,no_html" sanitize:"strict"`
+}
+
+// FeedPreferenceResponse represents the stored feed preference
+type FeedPreferenceResponse struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Posts handles GET /feed.xml and GET /feeds/posts.rss
+// @Summary Post feed
+// @Description Get the newest published posts as an RSS 2.0 feed, optionally only those carrying a tag, by an author, or both. Responses carry an ETag; sending it back in If-None-Match answers 304 while the feed is unchanged.
+// @Tags feeds
+// @Produce xml
+// @Param tag query string false "Tag name"
+// @Param author query int false "Author user ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {string} string "RSS feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Unknown author"
+// @Failure 500 {object} ErrorResponse
+// @Router /feed.xml [get]
+// @Router /feeds/posts.rss [get]
+func (h *FeedHandler) Posts(c echo.Context) error {
+	return h.filteredPosts(c, feed.FormatRSS)
+}
+
+// PostsAtom handles GET /feeds/posts.atom
+// @Summary Atom post feed
+// @Description Get the newest published posts as an Atom 1.0 feed, optionally only those carrying a tag, by an author, or both. Responses carry an ETag; sending it back in If-None-Match answers 304 while the feed is unchanged.
+// @Tags feeds
+// @Produce xml
+// @Param tag query string false "Tag name"
+// @Param author query int false "Author user ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Unknown author"
+// @Failure 500 {object} ErrorResponse
+// @Router /feeds/posts.atom [get]
+func (h *FeedHandler) PostsAtom(c echo.Context) error {
+	return h.filteredPosts(c, feed.FormatAtom)
+}
+
+// filteredPosts writes the post feed filtered by the tag and author query
+// parameters
+func (h *FeedHandler) filteredPosts(c echo.Context, format feed.Format) error {
+	ctx := c.Request().Context()
+
+	filter := feed.PostFilter{Tag: c.QueryParam("tag")}
+	if author := c.QueryParam("author"); author != "" {
+		authorID, err := strconv.Atoi(author)
+		if err != nil || authorID <= 0 {
+			h.logger.Warn(ctx, "Invalid author in feed query", "author", author)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
+		filter.AuthorID = authorID
+	}
+
+	return h.posts(c, filter, format)
+}
+
+// AuthorPosts handles GET /api/v1/users/{id}/feed.xml
+// @Summary Post feed of an author
+// @Description Get the newest published posts of an author as an RSS 2.0 feed, titled as set in the author's feed preference
+// @Tags feeds
+// @Produce xml
+// @Param id path int true "Author user ID"
+// @Success 200 {string} string "RSS feed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/feed.xml [get]
+func (h *FeedHandler) AuthorPosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || authorID <= 0 {
+		h.logger.Warn(ctx, "Invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	return h.posts(c, feed.PostFilter{AuthorID: authorID}, feed.FormatRSS)
+}
+
+// AuthorFeed handles GET /feeds/authors/{id}.rss and GET /feeds/authors/{id}.atom
+// @Summary Post feed of an author by format
+// @Description Get the newest published posts of an author as an RSS 2.0 or Atom 1.0 feed, chosen by the extension, titled as set in the author's feed preference. Responses carry an ETag; sending it back in If-None-Match answers 304 while the feed is unchanged.
+// @Tags feeds
+// @Produce xml
+// @Param feed path string true "Author user ID followed by .rss or .atom"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {string} string "RSS or Atom feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /feeds/authors/{feed} [get]
+func (h *FeedHandler) AuthorFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Echo parameters span the whole path segment, extension included
+	id, extension, _ := strings.Cut(c.Param("feed"), ".")
+	format := feed.Format(extension)
+	if format != feed.FormatRSS && format != feed.FormatAtom {
+		h.logger.Warn(ctx, "Unknown feed format in path", "feed", c.Param("feed"))
+		return errors.HandleError(c, errors.ErrNotFound)
+	}
+	authorID, err := strconv.Atoi(id)
+	if err != nil || authorID <= 0 {
+		h.logger.Warn(ctx, "Invalid user ID in path", "user_id", id)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	return h.posts(c, feed.PostFilter{AuthorID: authorID}, format)
+}
+
+// TagPosts handles GET /api/v1/tags/{name}/feed.xml
+// @Summary Post feed of a tag
+// @Description Get the newest published posts carrying a tag as an RSS 2.0 feed, titled as set in the tag's feed preference
+// @Tags feeds
+// @Produce xml
+// @Param name path string true "Tag name"
+// @Success 200 {string} string "RSS feed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tags/{name}/feed.xml [get]
+func (h *FeedHandler) TagPosts(c echo.Context) error {
+	return h.posts(c, feed.PostFilter{Tag: c.Param("name")}, feed.FormatRSS)
+}
+
+// posts writes the post feed matching the filter in the given format
+func (h *FeedHandler) posts(c echo.Context, filter feed.PostFilter, format feed.Format) error {
+	ctx := c.Request().Context()
+
+	body, err := h.generator.Posts(ctx, filter, format)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to build post feed", "error", err.Error(), "tag", filter.Tag, "author_id", filter.AuthorID, "format", format)
+		return errors.HandleError(c, err)
+	}
+
+	return h.respond(c, body, format.ContentType())
+}
+
+// SetAuthorFeed handles PUT /api/v1/users/me/feed
+// @Summary Customize your post feed
+// @Description Set the title and description of the feed of your posts. Feeds are cached, so changes can take up to the feed cache TTL to show.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param request body FeedPreferenceRequest true "Feed title and description"
+// @Success 200 {object} FeedPreferenceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/feed [put]
+func (h *FeedHandler) SetAuthorFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	req, err := h.bindPreference(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	pref, err := h.preferences.SetAuthorFeed(ctx, userID, req.Title, req.Description)
+	if err != nil {
+		h.logger.Error(ctx, "failed to set author feed preference", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toFeedPreferenceResponse(pref))
+}
+
+// SetTagFeed handles PUT /api/v1/admin/tags/{name}/feed
+// @Summary Customize a tag feed
+// @Description Set the title and description of the feed of a tag (admins only). Feeds are cached, so changes can take up to the feed cache TTL to show.
+// @Tags feeds
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param request body FeedPreferenceRequest true "Feed title and description"
+// @Success 200 {object} FeedPreferenceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/tags/{name}/feed [put]
+func (h *FeedHandler) SetTagFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	req, err := h.bindPreference(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	pref, err := h.preferences.SetTagFeed(ctx, userID, c.Param("name"), req.Title, req.Description)
+	if err != nil {
+		h.logger.Error(ctx, "failed to set tag feed preference", "userID", userID, "tag", c.Param("name"), "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toFeedPreferenceResponse(pref))
+}
+
+// bindPreference binds, sanitizes and validates a feed preference request
+func (h *FeedHandler) bindPreference(c echo.Context) (*FeedPreferenceRequest, error) {
+	ctx := c.Request().Context()
+
+	var req FeedPreferenceRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind feed preference request", "error", err.Error())
+		return nil, errors.ErrInvalidRequest
+	}
+
+	// Sanitize input
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "feed preference request validation failed", "error", err.Error())
+		return nil, err
+	}
+	return &req, nil
+}
+
+// toFeedPreferenceResponse converts a feed preference to its response
+func toFeedPreferenceResponse(pref *preference.Feed) FeedPreferenceResponse {
+	return FeedPreferenceResponse{
+		Scope:       pref.Scope,
+		Key:         pref.Key,
+		Title:       pref.Title,
+		Description: pref.Description,
+		UpdatedAt:   pref.UpdatedAt,
+	}
+}
+
+// PostComments handles GET /api/v1/posts/{id}/comments/feed.xml
+// @Summary Comment feed of a post
+// @Description Get the newest comments on a post as an RSS 2.0 feed
+// @Tags feeds
+// @Produce xml
+// @Param id path int true "Post ID"
+// @Success 200 {string} string "RSS feed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/posts/{id}/comments/feed.xml [get]
+func (h *FeedHandler) PostComments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || postID <= 0 {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	body, err := h.generator.PostComments(ctx, postID)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to build post comment feed", "error", err.Error(), "post_id", postID)
+		return errors.HandleError(c, err)
+	}
+
+	return h.respond(c, body, feed.ContentType)
+}
+
+// AuthorComments handles GET /api/v1/users/{id}/comments/feed.xml
+// @Summary Comment feed of an author
+// @Description Get the newest comments on all posts of an author as an RSS 2.0 feed
+// @Tags feeds
+// @Produce xml
+// @Param id path int true "Author user ID"
+// @Success 200 {string} string "RSS feed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/comments/feed.xml [get]
+func (h *FeedHandler) AuthorComments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || authorID <= 0 {
+		h.logger.Warn(ctx, "Invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	body, err := h.generator.AuthorComments(ctx, authorID)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to build author comment feed", "error", err.Error(), "user_id", authorID)
+		return errors.HandleError(c, err)
+	}
+
+	return h.respond(c, body, feed.ContentType)
+}
+
+// respond writes a feed, letting clients cache it as long as the server does
+// and revalidate it with an entity tag hashed from the document. Feed
+// readers polling an unchanged feed get 304 Not Modified instead. The tag
+// is weak, as the compression middleware may encode the body differently.
+func (h *FeedHandler) respond(c echo.Context, body []byte, contentType string) error {
+	header := c.Response().Header()
+	if ttl := h.generator.CacheTTL(); ttl > 0 {
+		header.Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+	}
+
+	sum := sha256.Sum256(body)
+	opaque := `"` + hex.EncodeToString(sum[:16]) + `"`
+	header.Set("ETag", "W/"+opaque)
+	if notModified(c.Request(), opaque, time.Time{}) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, contentType, body)
+}
+
+// RouteDocs describes the feed routes under /api/v1
+func (h *FeedHandler) RouteDocs() []RouteDoc {
+	examplePreference := FeedPreferenceResponse{
+		Scope:       preference.ScopeAuthor,
+		Key:         "1",
+		Title:       "Ada on Systems",
+		Description: "Notes on distributed systems and Go",
+		UpdatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+	exampleTagPreference := examplePreference
+	exampleTagPreference.Scope = preference.ScopeTag
+	exampleTagPreference.Key = "golang"
+	exampleTagPreference.Title = "Go on the Blog"
+	exampleTagPreference.Description = "Everything we write about Go"
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/{id}/feed.xml",
+			Summary:        "Post feed of an author",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/tags/{name}/feed.xml",
+			Summary:        "Post feed of a tag",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/users/me/feed",
+			Summary:         "Customize your post feed",
+			RequestExample:  FeedPreferenceRequest{Title: examplePreference.Title, Description: examplePreference.Description},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePreference,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/admin/tags/{name}/feed",
+			Summary:         "Customize a tag feed",
+			RequestExample:  FeedPreferenceRequest{Title: exampleTagPreference.Title, Description: exampleTagPreference.Description},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleTagPreference,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/posts/{id}/comments/feed.xml",
+			Summary:        "Comment feed of a post",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/{id}/comments/feed.xml",
+			Summary:        "Comment feed of an author",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// FollowHandler handles follows between users
+type FollowHandler struct {
+	followService user.FollowService
+	pagination    service.PageLimits
+	logger        service.Logger
+}
+
+// NewFollowHandler creates a new follow handler
+func NewFollowHandler(followService user.FollowService, pagination service.PageLimits, logger service.Logger) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+		pagination:    pagination,
+		logger:        logger,
+	}
+}
+
+// FollowResponse represents a user in a follower or following list
+type FollowResponse struct {
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// FollowListResponse represents a page of followers or followed users
+type FollowListResponse struct {
+	Users []FollowResponse `json:"users"`
+	// Followers and Following count every follow of the user, not just
+	// those on the page
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+	Limit     int `json:"limit"`
+	Offset    int `json:"offset"`
+}
+
+// Follow handles POST /api/v1/users/{id}/follow
+// @Summary Follow a user
+// @Description Follow a user so their posts appear in your feed; following again has no effect
+// @Tags users
+// @Param id path int true "User ID"
+// @Security BearerAuth
+// @Success 204 "User followed"
+// @Failure 400 {object} ErrorResponse "Invalid user ID or following yourself"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/{id}/follow [post]
+func (h *FollowHandler) Follow(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.followService.Follow(ctx, userID, id); err != nil {
+		h.logger.Warn(ctx, "failed to follow user", "followerID", userID, "followeeID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unfollow handles DELETE /api/v1/users/{id}/follow
+// @Summary Unfollow a user
+// @Description Stop following a user; unfollowing a user you do not follow has no effect
+// @Tags users
+// @Param id path int true "User ID"
+// @Security BearerAuth
+// @Success 204 "User unfollowed"
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/{id}/follow [delete]
+func (h *FollowHandler) Unfollow(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.followService.Unfollow(ctx, userID, id); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListFollowers handles GET /api/v1/users/{id}/followers
+// @Summary List the followers of a user
+// @Description List the users following a user, most recent first, with the follow counts of the user
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit query int false "Number of users to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of users to skip (default: 0)"
+// @Success 200 {object} FollowListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/followers [get]
+func (h *FollowHandler) ListFollowers(c echo.Context) error {
+	return h.list(c, h.followService.ListFollowers)
+}
+
+// ListFollowing handles GET /api/v1/users/{id}/following
+// @Summary List the users a user follows
+// @Description List the users a user follows, most recent first, with the follow counts of the user
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit query int false "Number of users to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of users to skip (default: 0)"
+// @Success 200 {object} FollowListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/following [get]
+func (h *FollowHandler) ListFollowing(c echo.Context) error {
+	return h.list(c, h.followService.ListFollowing)
+}
+
+// followLister lists a page of follows of a user with their follow counts
+type followLister func(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, *user.FollowCounts, error)
+
+// list writes a page of follows of the user in the path
+func (h *FollowHandler) list(c echo.Context, lister followLister) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	limit, offset := parsePage(c, h.pagination)
+	follows, counts, err := lister(ctx, id, limit, offset)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	response := FollowListResponse{
+		Users:     make([]FollowResponse, len(follows)),
+		Followers: counts.Followers,
+		Following: counts.Following,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for i, f := range follows {
+		response.Users[i] = FollowResponse{UserID: f.UserID, Name: f.Name, FollowedAt: f.FollowedAt}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// RouteDocs returns examples and error codes for the follow routes
+func (h *FollowHandler) RouteDocs() []RouteDoc {
+	exampleList := FollowListResponse{
+		Users: []FollowResponse{{
+			UserID:     2,
+			Name:       "Jane Reader",
+			FollowedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		}},
+		Followers: 1,
+		Following: 4,
+		Limit:     10,
+		Offset:    0,
+	}
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/users/{id}/follow",
+			Summary:        "Follow a user",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/users/{id}/follow",
+			Summary:        "Unfollow a user",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/{id}/followers",
+			Summary:         "List the followers of a user",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleList,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/{id}/following",
+			Summary:         "List the users a user follows",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleList,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
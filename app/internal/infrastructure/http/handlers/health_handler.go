@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/health"
+)
+
+// HealthHandler answers the liveness and readiness probes of orchestrators
+type HealthHandler struct {
+	checker *health.Checker
+	logger  service.Logger
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checker *health.Checker, logger service.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// LivenessResponse reports that the process is serving requests
+type LivenessResponse struct {
+	Status  string `json:"status" example:"ok"`
+	Service string `json:"service" example:"blog-platform"`
+}
+
+// Liveness handles GET /healthz
+// @Summary Liveness probe
+// @Description Report that the process is up and serving requests. Dependencies are not checked, so a database outage does not get the instance restarted; use /readyz to take it out of rotation instead.
+// @Tags health
+// @Produce json
+// @Success 200 {object} LivenessResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(http.StatusOK, LivenessResponse{Status: health.StatusOK, Service: "blog-platform"})
+}
+
+// Readiness handles GET /readyz
+// @Summary Readiness probe
+// @Description Check the database, the cache when it is shared in Redis, and that every migration is applied, reporting the status and latency of each. Any failing check answers 503, so the instance is taken out of rotation until it recovers.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	report := h.checker.Check(ctx)
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+		for name, result := range report.Checks {
+			if result.Err() != nil {
+				h.logger.Warn(ctx, "readiness check failed", "check", name, "error", result.Err().Error())
+			}
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(status, report)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// ImportField is the multipart form field export files are uploaded in
+const ImportField = "file"
+
+// ImportTypes lists the content types accepted for export files: WordPress
+// exports and zips of Markdown files
+var ImportTypes = []string{"text/xml; charset=utf-8", "application/zip"}
+
+// ImportHandler handles content imports from other blogs
+type ImportHandler struct {
+	importService imports.Service
+	logger        service.Logger
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService imports.Service, logger service.Logger) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// ImportResponse represents an import and its progress
+type ImportResponse struct {
+	ID     int    `json:"id"`
+	Source string `json:"source"`
+	DryRun bool   `json:"dry_run"`
+	Status string `json:"status"`
+	// Total is the number of posts in the export; Processed counts those
+	// handled so far
+	Total           int `json:"total"`
+	Processed       int `json:"processed"`
+	UsersCreated    int `json:"users_created"`
+	PostsCreated    int `json:"posts_created"`
+	CommentsCreated int `json:"comments_created"`
+	// Skipped counts posts whose slug is taken already
+	Skipped int `json:"skipped"`
+	// Failed counts posts that could not be imported; Errors says why for
+	// the first 100
+	Failed      int      `json:"failed"`
+	Errors      []string `json:"errors"`
+	CreatedAt   string   `json:"created_at"`
+	CompletedAt string   `json:"completed_at,omitempty"`
+}
+
+// Start handles POST /api/v1/admin/import
+// @Summary Import content
+// @Description Import a WordPress export (WXR) or a zip of Markdown files with YAML front matter (title, slug, date, draft, tags, author, author_email). The file is checked right away and imported in the background; poll the import for progress. Authors are matched to accounts by email, and accounts are created for unknown ones; posts whose slug is taken are skipped. With dry_run=true nothing is written and the counters report what the import would create.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Export file (at most IMPORT_MAX_SIZE bytes, 50 MB by default)"
+// @Param dry_run query bool false "Only report what the import would create"
+// @Success 202 {object} ImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/import [post]
+func (h *ImportHandler) Start(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	header, err := c.FormFile(ImportField)
+	if err != nil {
+		h.logger.Warn(ctx, "import upload without file", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error(ctx, "failed to open uploaded export", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.logger.Error(ctx, "failed to read uploaded export", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	i, err := h.importService.Start(ctx, adminID, data, dryRun)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start import", "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "import queued", "importID", i.ID, "adminID", adminID, "dryRun", dryRun)
+	return c.JSON(http.StatusAccepted, toImportResponse(i))
+}
+
+// Get handles GET /api/v1/admin/import/{id}
+// @Summary Get an import
+// @Description Get the status and progress of an import, with the reasons posts could not be imported
+// @Tags admin
+// @Produce json
+// @Param id path int true "Import ID"
+// @Success 200 {object} ImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/import/{id} [get]
+func (h *ImportHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.logger.Warn(ctx, "invalid import ID in path", "id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	i, err := h.importService.Get(ctx, id)
+	if err != nil {
+		h.logger.Error(ctx, "failed to get import", "importID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toImportResponse(i))
+}
+
+// toImportResponse converts an import into its API representation
+func toImportResponse(i *imports.Import) ImportResponse {
+	response := ImportResponse{
+		ID:              i.ID,
+		Source:          i.Source,
+		DryRun:          i.DryRun,
+		Status:          i.Status,
+		Total:           i.Total,
+		Processed:       i.Processed,
+		UsersCreated:    i.UsersCreated,
+		PostsCreated:    i.PostsCreated,
+		CommentsCreated: i.CommentsCreated,
+		Skipped:         i.Skipped,
+		Failed:          i.Failed,
+		Errors:          i.Errors,
+		CreatedAt:       i.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if response.Errors == nil {
+		response.Errors = []string{}
+	}
+	if i.CompletedAt != nil {
+		response.CompletedAt = i.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the import routes
+func (h *ImportHandler) RouteDocs() []RouteDoc {
+	example := ImportResponse{
+		ID:        1,
+		Source:    imports.SourceWordPress,
+		Status:    imports.StatusQueued,
+		Total:     42,
+		Errors:    []string{},
+		CreatedAt: "2024-01-15T10:30:00Z",
+	}
+	completed := example
+	completed.Status = imports.StatusCompleted
+	completed.Processed = 42
+	completed.UsersCreated, completed.PostsCreated, completed.CommentsCreated = 2, 40, 315
+	completed.Skipped, completed.Failed = 1, 1
+	completed.Errors = []string{`post 17 "": title must be between 1 and 500 characters`}
+	completed.CompletedAt = "2024-01-15T10:31:12Z"
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/import",
+			Summary:         "Import content",
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: example,
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation,
+				errors.ErrCodePayloadTooLarge, errors.ErrCodeUnsupportedMediaType),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/import/{id}",
+			Summary:         "Get an import",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: completed,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
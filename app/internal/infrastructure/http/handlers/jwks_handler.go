@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	infraauth "blog-platform/internal/infrastructure/auth"
+)
+
+// JWKSHandler publishes the public keys access tokens are signed with, so
+// other services can verify tokens without sharing a secret
+type JWKSHandler struct {
+	keys   infraauth.JWKSet
+	logger service.Logger
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(keys infraauth.JWKSet, logger service.Logger) *JWKSHandler {
+	return &JWKSHandler{
+		keys:   keys,
+		logger: logger,
+	}
+}
+
+// ServeJWKS handles GET /.well-known/jwks.json
+// @Summary Get the token signing keys
+// @Description Get the public keys that verify access tokens as a JSON Web Key Set. Tokens name their key in the kid header; the set is empty while tokens are signed with a shared secret.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) ServeJWKS(c echo.Context) error {
+	// Short caching lets verifiers pick up a new key soon after a rotation
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, h.keys)
+}
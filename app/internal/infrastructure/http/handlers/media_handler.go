@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/media"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// MediaField is the multipart form field media uploads are sent in
+const MediaField = "file"
+
+// MediaTypes lists the content types accepted for media
+var MediaTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// MediaHandler handles uploads of images referenced in posts
+type MediaHandler struct {
+	mediaService media.Service
+	logger       service.Logger
+}
+
+// NewMediaHandler creates a new media handler
+func NewMediaHandler(mediaService media.Service, logger service.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		logger:       logger,
+	}
+}
+
+// MediaResponse represents an uploaded image
+type MediaResponse struct {
+	ID int `json:"id"`
+	// URL is where the image is served from; it never changes, so it can
+	// be cached for good
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// UploadMedia handles POST /api/v1/media
+// @Summary Upload an image
+// @Description Upload a JPEG, PNG or GIF image to reference in posts by its URL. Uploads count towards the storage quota of the user; images no post references are deleted after MEDIA_ORPHAN_AGE hours.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image file (at most MEDIA_MAX_SIZE bytes, 10 MB by default)"
+// @Success 201 {object} MediaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/media [post]
+func (h *MediaHandler) UploadMedia(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	header, err := c.FormFile(MediaField)
+	if err != nil {
+		h.logger.Warn(ctx, "media upload without file", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error(ctx, "failed to open uploaded media", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+	defer file.Close()
+
+	m, err := h.mediaService.Upload(ctx, userID, file)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toMediaResponse(m))
+}
+
+// toMediaResponse converts media to its response format
+func toMediaResponse(m *media.Media) MediaResponse {
+	return MediaResponse{
+		ID:          m.ID,
+		URL:         m.URL,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		Width:       m.Width,
+		Height:      m.Height,
+		CreatedAt:   m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// RouteDocs returns examples and error codes for the media route
+func (h *MediaHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/media",
+			Summary:        "Upload an image",
+			ResponseStatus: http.StatusCreated,
+			ResponseExample: MediaResponse{
+				ID:          12,
+				URL:         "https://cdn.example.com/media/1/5d41402abc4b2a76b9719d911017c592.png",
+				ContentType: "image/png",
+				Size:        48213,
+				Width:       1200,
+				Height:      630,
+				CreatedAt:   "2024-01-15T10:30:00Z",
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeQuotaExceeded, errors.ErrCodePlanLimitReached, errors.ErrCodePayloadTooLarge, errors.ErrCodeUnsupportedMediaType),
+		},
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// NotificationHandler handles the notifications of signed-in users
+type NotificationHandler struct {
+	notificationService notification.Service
+	pagination          service.PageLimits
+	logger              service.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService notification.Service, pagination service.PageLimits, logger service.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		pagination:          pagination,
+		logger:              logger,
+	}
+}
+
+// NotificationResponse represents a notification
+type NotificationResponse struct {
+	ID int `json:"id"`
+	// Kind is comment for comments on your posts and reply for replies to
+	// your comments
+	Kind      string    `json:"kind"`
+	PostID    int       `json:"post_id"`
+	CommentID int       `json:"comment_id"`
+	ActorName string    `json:"actor_name"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationListResponse represents a page of notifications
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	// Unread counts every unread notification, not just those on the page
+	Unread int `json:"unread"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// List handles GET /api/v1/users/me/notifications
+// @Summary List your notifications
+// @Description List the notifications of the authenticated user, newest first, with the number of unread ones. Notifications are created for new comments on your posts and replies to your comments.
+// @Tags users
+// @Produce json
+// @Param limit query int false "Number of notifications to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of notifications to skip (default: 0)"
+// @Success 200 {object} NotificationListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/notifications [get]
+func (h *NotificationHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	limit, offset := parsePage(c, h.pagination)
+	notifications, unread, err := h.notificationService.List(ctx, userID, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list notifications", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := NotificationListResponse{
+		Notifications: make([]NotificationResponse, len(notifications)),
+		Unread:        unread,
+		Limit:         limit,
+		Offset:        offset,
+	}
+	for i, n := range notifications {
+		response.Notifications[i] = toNotificationResponse(n)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// MarkRead handles POST /api/v1/notifications/{id}/read
+// @Summary Mark a notification read
+// @Description Mark a notification of the authenticated user read; marking it again has no effect
+// @Tags users
+// @Param id path int true "Notification ID"
+// @Security BearerAuth
+// @Success 204 "Notification marked read"
+// @Failure 400 {object} ErrorResponse "Invalid notification ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Notification not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid notification ID in path", "notification_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.notificationService.MarkRead(ctx, id, userID); err != nil {
+		h.logger.Warn(ctx, "failed to mark notification read", "notificationID", id, "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toNotificationResponse converts a notification to its response format
+func toNotificationResponse(n *notification.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        n.ID,
+		Kind:      string(n.Kind),
+		PostID:    n.PostID,
+		CommentID: n.CommentID,
+		ActorName: n.ActorName,
+		Read:      n.IsRead(),
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// RouteDocs returns examples and error codes for the notification routes
+func (h *NotificationHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/me/notifications",
+			Summary:        "List your notifications",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: NotificationListResponse{
+				Notifications: []NotificationResponse{{
+					ID:        3,
+					Kind:      string(notification.KindReply),
+					PostID:    1,
+					CommentID: 12,
+					ActorName: "Jane Reader",
+					CreatedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+				}},
+				Unread: 1,
+				Limit:  10,
+				Offset: 0,
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/notifications/{id}/read",
+			Summary:        "Mark a notification read",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
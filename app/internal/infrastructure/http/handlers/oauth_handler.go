@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/infrastructure/auth/oauth"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// oauthStateCookie binds a sign-in to the browser that started it
+const oauthStateCookie = "oauth_state"
+
+// oauthCookiePath limits the state cookie to the OAuth routes
+const oauthCookiePath = "/api/v1/auth/oauth"
+
+// OAuthSettings configures the social login flow
+type OAuthSettings struct {
+	// BaseURL is the public URL used to build the provider callback URLs
+	BaseURL string
+}
+
+// OAuthHandler handles sign-in through external identity providers
+type OAuthHandler struct {
+	authService auth.AuthService
+	providers   *oauth.Registry
+	state       *oauth.StateSigner
+	settings    OAuthSettings
+	logger      service.Logger
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(authService auth.AuthService, providers *oauth.Registry, state *oauth.StateSigner, settings OAuthSettings, logger service.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		authService: authService,
+		providers:   providers,
+		state:       state,
+		settings:    settings,
+		logger:      logger,
+	}
+}
+
+// errOAuthFailed is returned when the provider rejects the sign-in
+var errOAuthFailed = errors.NewAPIError(errors.ErrCodeUnauthorized, "Sign-in with the provider failed", http.StatusUnauthorized)
+
+// errOAuthState is returned for callbacks with a missing or forged state
+var errOAuthState = errors.NewAPIError(errors.ErrCodeInvalidRequest, "Invalid or expired sign-in state", http.StatusBadRequest)
+
+// Start handles GET /api/v1/auth/oauth/{provider}
+// @Summary Start social login
+// @Description Redirect to the provider's sign-in page. After consent the provider redirects back to the callback.
+// @Tags Authentication
+// @Param provider path string true "Provider name (google or github)"
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} ErrorResponse "Provider not configured"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/oauth/{provider} [get]
+func (h *OAuthHandler) Start(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	provider, err := h.providers.Get(c.Param("provider"))
+	if err != nil {
+		h.logger.Warn(ctx, "social login with unknown provider", "provider", c.Param("provider"))
+		return errors.HandleError(c, errors.ErrNotFound)
+	}
+
+	state, err := h.state.Issue(provider.Name())
+	if err != nil {
+		h.logger.Error(ctx, "failed to issue oauth state", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     oauthCookiePath,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.settings.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.logger.Info(ctx, "redirecting to oauth provider", "provider", provider.Name())
+	return c.Redirect(http.StatusFound, provider.AuthCodeURL(state, h.callbackURL(provider.Name())))
+}
+
+// Callback handles GET /api/v1/auth/oauth/{provider}/callback
+// @Summary Complete social login
+// @Description Exchange the provider's authorization code for a platform token pair. The external account is linked to the user with the same verified email, or a new user is registered.
+// @Tags Authentication
+// @Produce json
+// @Param provider path string true "Provider name (google or github)"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State issued by the start endpoint"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Success 202 {object} TwoFactorChallengeResponse "Account has two-factor authentication; complete the login at /auth/login/2fa"
+// @Failure 400 {object} ErrorResponse "Invalid state or unverified provider email"
+// @Failure 401 {object} ErrorResponse "Provider sign-in failed"
+// @Failure 404 {object} ErrorResponse "Provider not configured"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	provider, err := h.providers.Get(c.Param("provider"))
+	if err != nil {
+		h.logger.Warn(ctx, "oauth callback for unknown provider", "provider", c.Param("provider"))
+		return errors.HandleError(c, errors.ErrNotFound)
+	}
+
+	// The state is single-use: clear the cookie whatever the outcome
+	c.SetCookie(&http.Cookie{Name: oauthStateCookie, Path: oauthCookiePath, MaxAge: -1})
+
+	state := c.QueryParam("state")
+	cookie, err := c.Cookie(oauthStateCookie)
+	if state == "" || err != nil || cookie.Value != state || h.state.Verify(state, provider.Name()) != nil {
+		h.logger.Warn(ctx, "oauth callback with invalid state", "provider", provider.Name())
+		return errors.HandleError(c, errOAuthState)
+	}
+
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		h.logger.Warn(ctx, "oauth provider returned an error", "provider", provider.Name(), "error", providerErr)
+		return errors.HandleError(c, errOAuthFailed)
+	}
+
+	code := c.QueryParam("code")
+	if code == "" {
+		h.logger.Warn(ctx, "oauth callback without code", "provider", provider.Name())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	external, err := provider.Identity(ctx, code, h.callbackURL(provider.Name()))
+	if err != nil {
+		h.logger.Error(ctx, "failed to load identity from provider", "provider", provider.Name(), "error", err.Error())
+		if stderrors.Is(err, oauth.ErrExchangeFailed) {
+			return errors.HandleError(c, errOAuthFailed)
+		}
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	u, tokens, err := h.authService.LoginWithIdentity(ctx, *external)
+	if err != nil {
+		if ok, respErr := respondTwoFactorChallenge(c, err); ok {
+			h.logger.Info(ctx, "two-factor code required", "provider", provider.Name())
+			return respErr
+		}
+		h.logger.Error(ctx, "social login failed", "provider", provider.Name(), "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged in through provider", "provider", provider.Name(), "userID", u.ID)
+	return c.JSON(http.StatusOK, AuthResponse{
+		User: UserResponse{
+			ID:    u.ID,
+			Name:  u.Name,
+			Email: u.Email,
+			Role:  string(u.Role),
+		},
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	})
+}
+
+// callbackURL returns the redirect URL registered with the provider
+func (h *OAuthHandler) callbackURL(provider string) string {
+	return h.settings.BaseURL + oauthCookiePath + "/" + provider + "/callback"
+}
+
+// RouteDocs returns examples and error codes for the social login routes
+func (h *OAuthHandler) RouteDocs() []RouteDoc {
+	exampleUser := UserResponse{ID: 1, Name: "John Doe", Email: "john@example.com", Role: "author"}
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/auth/oauth/{provider}",
+			Summary:        "Start social login",
+			ResponseStatus: http.StatusFound,
+			Errors:         withCommonErrors(errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/auth/oauth/{provider}/callback",
+			Summary:         "Complete social login",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: AuthResponse{User: exampleUser, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeNotFound, errors.ErrCodePasswordResetRequired, errors.ErrCodeAccountDeactivated, errors.ErrCodeSSORequired),
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/keyset"
+)
+
+// parsePage reads the limit and offset query parameters and applies the
+// resource's page limits. Missing or malformed values fall back to the
+// defaults, so list endpoints never reject a page request.
+func parsePage(c echo.Context, limits service.PageLimits) (int, int) {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 0
+	}
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil {
+		offset = 0
+	}
+	return limits.Normalize(limit, offset)
+}
+
+// parseCursor reports whether a list request opted into cursor pagination
+// by sending the cursor query parameter, which is empty for the first page,
+// and decodes the cursor
+func parseCursor(c echo.Context) (keyset.Cursor, bool, error) {
+	if !c.QueryParams().Has("cursor") {
+		return keyset.Cursor{}, false, nil
+	}
+	cursor, err := keyset.Decode(c.QueryParam("cursor"))
+	return cursor, true, err
+}
@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// PasskeyHandler handles the WebAuthn ceremonies and passkey management.
+// Begin endpoints return the options for the browser's WebAuthn API; finish
+// endpoints take the credential it returns, in its JSON form.
+type PasskeyHandler struct {
+	passkeyService auth.PasskeyService
+	authService    auth.AuthService
+	logger         service.Logger
+}
+
+// NewPasskeyHandler creates a new passkey handler
+func NewPasskeyHandler(passkeyService auth.PasskeyService, authService auth.AuthService, logger service.Logger) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		authService:    authService,
+		logger:         logger,
+	}
+}
+
+// PasskeySignupRequest represents the account details of a passkey signup
+type PasskeySignupRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=255,no_html,safe_string" sanitize:"strict"`
+	Email string `json:"email" validate:"required,email" sanitize:"strict"`
+}
+
+// PasskeyRegistrationRequest names a passkey being added to an account
+type PasskeyRegistrationRequest struct {
+	Name string `json:"name" validate:"max=100,no_html" sanitize:"strict"`
+}
+
+// PasskeyTwoFactorRequest carries the two-factor token of a login
+type PasskeyTwoFactorRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// PasskeyResponse represents a registered passkey
+type PasskeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// PasskeyListResponse represents the passkeys of the authenticated user
+type PasskeyListResponse struct {
+	Passkeys []PasskeyResponse `json:"passkeys"`
+}
+
+// BeginSignup handles POST /api/v1/auth/webauthn/signup/begin
+// @Summary Start a passkey signup
+// @Description Start creating an account that signs in with a passkey instead of a password. Pass the returned options to navigator.credentials.create().
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param account body PasskeySignupRequest true "Account details"
+// @Success 200 {object} auth.CreationOptions "Passkey creation options"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 409 {object} ErrorResponse "User already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/signup/begin [post]
+func (h *PasskeyHandler) BeginSignup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PasskeySignupRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey signup request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "passkey signup request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	options, err := h.passkeyService.BeginSignup(ctx, req.Name, req.Email)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start passkey signup", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishSignup handles POST /api/v1/auth/webauthn/signup/finish
+// @Summary Complete a passkey signup
+// @Description Create the account with the passkey returned by navigator.credentials.create()
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credential body auth.AttestationResponse true "Public key credential"
+// @Success 201 {object} AuthResponse "User successfully registered"
+// @Failure 400 {object} ErrorResponse "Invalid request data or passkey"
+// @Failure 401 {object} ErrorResponse "Invalid or expired challenge"
+// @Failure 409 {object} ErrorResponse "User or passkey already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/signup/finish [post]
+func (h *PasskeyHandler) FinishSignup(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var credential auth.AttestationResponse
+	if err := c.Bind(&credential); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey signup credential", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	u, tokens, err := h.authService.SignupWithPasskey(ctx, &credential)
+	if err != nil {
+		h.logger.Error(ctx, "passkey signup failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user registered with passkey", "userID", u.ID)
+	return c.JSON(http.StatusCreated, newAuthResponse(u, tokens))
+}
+
+// BeginLogin handles POST /api/v1/auth/webauthn/login/begin
+// @Summary Start a passkey login
+// @Description Start signing in with a passkey. Pass the returned options to navigator.credentials.get(); the browser offers the passkeys it has for the site.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} auth.RequestOptions "Passkey request options"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/login/begin [post]
+func (h *PasskeyHandler) BeginLogin(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	options, err := h.passkeyService.BeginLogin(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start passkey login", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishLogin handles POST /api/v1/auth/webauthn/login/finish
+// @Summary Complete a passkey login
+// @Description Exchange the assertion returned by navigator.credentials.get() for a JWT. Passkeys verify the user, so no second factor is asked for.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credential body auth.AssertionResponse true "Public key credential"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid assertion or expired challenge"
+// @Failure 403 {object} ErrorResponse "Account locked until its password is reset"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/login/finish [post]
+func (h *PasskeyHandler) FinishLogin(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var credential auth.AssertionResponse
+	if err := c.Bind(&credential); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey login credential", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	u, tokens, err := h.authService.LoginWithPasskey(ctx, &credential)
+	if err != nil {
+		h.logger.Error(ctx, "passkey login failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged in with passkey", "userID", u.ID)
+	return c.JSON(http.StatusOK, newAuthResponse(u, tokens))
+}
+
+// BeginTwoFactor handles POST /api/v1/auth/webauthn/2fa/begin
+// @Summary Start a passkey second factor
+// @Description Start answering the two-factor challenge of a login with one of the user's passkeys
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param challenge body PasskeyTwoFactorRequest true "Two-factor token"
+// @Success 200 {object} auth.RequestOptions "Passkey request options"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 401 {object} ErrorResponse "Invalid or expired challenge"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/2fa/begin [post]
+func (h *PasskeyHandler) BeginTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req PasskeyTwoFactorRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey two-factor request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "passkey two-factor request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	options, err := h.authService.BeginPasskeyTwoFactor(ctx, req.Token)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start passkey second factor", "error", err.Error())
+		switch {
+		case stderrors.Is(err, auth.ErrInvalidToken), stderrors.Is(err, auth.ErrTokenExpired), stderrors.Is(err, auth.ErrEmptyToken):
+			return errors.HandleError(c, errTwoFactorChallenge)
+		}
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishTwoFactor handles POST /api/v1/auth/webauthn/2fa/finish
+// @Summary Complete a login with a passkey second factor
+// @Description Exchange the assertion returned by navigator.credentials.get() for a JWT
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credential body auth.AssertionResponse true "Public key credential"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid assertion or expired challenge"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/2fa/finish [post]
+func (h *PasskeyHandler) FinishTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var credential auth.AssertionResponse
+	if err := c.Bind(&credential); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey two-factor credential", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	u, tokens, err := h.authService.LoginTwoFactorWithPasskey(ctx, &credential)
+	if err != nil {
+		h.logger.Error(ctx, "passkey two-factor login failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged in with passkey as second factor", "userID", u.ID)
+	return c.JSON(http.StatusOK, newAuthResponse(u, tokens))
+}
+
+// BeginRegistration handles POST /api/v1/auth/webauthn/register/begin
+// @Summary Start adding a passkey
+// @Description Start adding a passkey to the authenticated user's account. Pass the returned options to navigator.credentials.create().
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param passkey body PasskeyRegistrationRequest false "Passkey name"
+// @Success 200 {object} auth.CreationOptions "Passkey creation options"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/register/begin [post]
+func (h *PasskeyHandler) BeginRegistration(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req PasskeyRegistrationRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey registration request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "passkey registration request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	options, err := h.passkeyService.BeginRegistration(ctx, userID, req.Name)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start passkey registration", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishRegistration handles POST /api/v1/auth/webauthn/register/finish
+// @Summary Complete adding a passkey
+// @Description Store the passkey returned by navigator.credentials.create(). It can then be used to sign in and as a second factor.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param credential body auth.AttestationResponse true "Public key credential"
+// @Success 201 {object} PasskeyResponse "Passkey added"
+// @Failure 400 {object} ErrorResponse "Invalid request data or passkey"
+// @Failure 401 {object} ErrorResponse "Unauthorized or expired challenge"
+// @Failure 409 {object} ErrorResponse "Passkey already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/webauthn/register/finish [post]
+func (h *PasskeyHandler) FinishRegistration(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var credential auth.AttestationResponse
+	if err := c.Bind(&credential); err != nil {
+		h.logger.Error(ctx, "failed to bind passkey registration credential", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(ctx, userID, &credential)
+	if err != nil {
+		h.logger.Error(ctx, "failed to register passkey", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "passkey registered", "userID", userID, "passkeyID", passkey.ID)
+	return c.JSON(http.StatusCreated, toPasskeyResponse(passkey))
+}
+
+// ListPasskeys handles GET /api/v1/users/me/passkeys
+// @Summary List passkeys
+// @Description List the passkeys of the authenticated user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PasskeyListResponse "Passkeys"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/passkeys [get]
+func (h *PasskeyHandler) ListPasskeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	passkeys, err := h.passkeyService.ListPasskeys(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list passkeys", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := PasskeyListResponse{Passkeys: make([]PasskeyResponse, 0, len(passkeys))}
+	for _, p := range passkeys {
+		response.Passkeys = append(response.Passkeys, toPasskeyResponse(p))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeletePasskey handles DELETE /api/v1/users/me/passkeys/:id
+// @Summary Remove a passkey
+// @Description Remove a passkey of the authenticated user
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Passkey ID"
+// @Success 200 {object} MessageResponse "Passkey removed"
+// @Failure 400 {object} ErrorResponse "Invalid passkey ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Passkey not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/passkeys/{id} [delete]
+func (h *PasskeyHandler) DeletePasskey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.logger.Warn(ctx, "invalid passkey ID in path", "id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.passkeyService.DeletePasskey(ctx, userID, id); err != nil {
+		h.logger.Error(ctx, "failed to remove passkey", "userID", userID, "passkeyID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "passkey removed", "userID", userID, "passkeyID", id)
+	return c.JSON(http.StatusOK, MessageResponse{Message: "Passkey removed"})
+}
+
+// newAuthResponse builds the response of a completed login
+func newAuthResponse(u *user.User, tokens *auth.TokenPair) AuthResponse {
+	return AuthResponse{
+		User: UserResponse{
+			ID:    u.ID,
+			Name:  u.Name,
+			Email: u.Email,
+			Role:  string(u.Role),
+		},
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+// toPasskeyResponse converts a passkey to its response
+func toPasskeyResponse(p *auth.Passkey) PasskeyResponse {
+	return PasskeyResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		CreatedAt:  p.CreatedAt,
+		LastUsedAt: p.LastUsedAt,
+	}
+}
+
+// RouteDocs returns examples and error codes for the passkey routes
+func (h *PasskeyHandler) RouteDocs() []RouteDoc {
+	exampleAuth := AuthResponse{User: UserResponse{ID: 1, Name: "John Doe", Email: "john@example.com", Role: "author"}, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900}
+	exampleCreation := auth.CreationOptions{
+		RP:               auth.RelyingPartyEntity{ID: "example.com", Name: "Blog Platform"},
+		User:             auth.UserEntity{ID: auth.Base64URL("user-handle"), Name: "john@example.com", DisplayName: "John Doe"},
+		Challenge:        auth.Base64URL("random-challenge"),
+		PubKeyCredParams: []auth.CredentialParameter{{Type: "public-key", Alg: -7}, {Type: "public-key", Alg: -8}, {Type: "public-key", Alg: -257}},
+		Timeout:          300000,
+		AuthenticatorSelection: auth.AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		ExcludeCredentials: []auth.CredentialDescriptor{},
+		Attestation:        "none",
+	}
+	exampleRequest := auth.RequestOptions{
+		Challenge:        auth.Base64URL("random-challenge"),
+		Timeout:          300000,
+		RPID:             "example.com",
+		AllowCredentials: []auth.CredentialDescriptor{},
+		UserVerification: "required",
+	}
+	examplePasskey := PasskeyResponse{ID: 1, Name: "MacBook", CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/signup/begin",
+			Summary:         "Start a passkey signup",
+			RequestExample:  PasskeySignupRequest{Name: "John Doe", Email: "john@example.com"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleCreation,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/signup/finish",
+			Summary:         "Complete a passkey signup",
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: exampleAuth,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeConflict, errors.ErrCodeSSORequired),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/login/begin",
+			Summary:         "Start a passkey login",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleRequest,
+			Errors:          withCommonErrors(),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/login/finish",
+			Summary:         "Complete a passkey login",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAuth,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodePasswordResetRequired, errors.ErrCodeAccountDeactivated, errors.ErrCodeSSORequired),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/2fa/begin",
+			Summary:         "Start a passkey second factor",
+			RequestExample:  PasskeyTwoFactorRequest{Token: "eyJhbGciOiJIUzI1NiIs..."},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleRequest,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/2fa/finish",
+			Summary:         "Complete a login with a passkey second factor",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAuth,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/register/begin",
+			Summary:         "Start adding a passkey",
+			RequestExample:  PasskeyRegistrationRequest{Name: "MacBook"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleCreation,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/webauthn/register/finish",
+			Summary:         "Complete adding a passkey",
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: examplePasskey,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/me/passkeys",
+			Summary:         "List passkeys",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: PasskeyListResponse{Passkeys: []PasskeyResponse{examplePasskey}},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            "/api/v1/users/me/passkeys/{id}",
+			Summary:         "Remove a passkey",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: MessageResponse{Message: "Passkey removed"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
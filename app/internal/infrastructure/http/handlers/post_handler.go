@@ -1,51 +1,232 @@
 package handlers
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/errors"
 	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/markdown"
 )
 
+// Content formats of post responses: post content is stored as Markdown
+// and rendered to sanitized HTML with ?format=html
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+)
+
+// Headers of the language negotiation of posts
+const (
+	acceptLanguageHeader  = "Accept-Language"
+	contentLanguageHeader = "Content-Language"
+)
+
+// defaultPopularDays is the window popular posts are ranked over when the
+// request names none
+const defaultPopularDays = 7
+
 // PostHandler handles post-related HTTP requests
 type PostHandler struct {
-	postService post.Service
-	logger      service.Logger
+	postService     post.Service
+	progressService post.ProgressService
+	profileService  user.ProfileService
+	redirectService post.RedirectService
+	pagination      service.PageLimits
+	mapper          ResponseMapper
+	logger          service.Logger
 }
 
 // NewPostHandler creates a new post handler
-func NewPostHandler(postService post.Service, logger service.Logger) *PostHandler {
+func NewPostHandler(postService post.Service, progressService post.ProgressService, profileService user.ProfileService, redirectService post.RedirectService, pagination service.PageLimits, logger service.Logger) *PostHandler {
 	return &PostHandler{
-		postService: postService,
-		logger:      logger,
+		postService:     postService,
+		progressService: progressService,
+		profileService:  profileService,
+		redirectService: redirectService,
+		pagination:      pagination,
+		mapper:          V1Mapper{},
+		logger:          logger,
 	}
 }
 
+// WithMapper returns a copy of the handler responding with the
+// representations of the mapper, to serve the post routes of another API
+// version
+func (h *PostHandler) WithMapper(mapper ResponseMapper) *PostHandler {
+	mapped := *h
+	mapped.mapper = mapper
+	return &mapped
+}
+
 // CreatePostRequest represents the create post request payload
 type CreatePostRequest struct {
-	Title   string `json:"title" validate:"required,min=1,max=500,no_html,safe_string"`
-	Content string `json:"content" validate:"required,min=10,max=10000,no_html"`
+	Title   string `json:"title" validate:"required,min=1,max=500,no_html,safe_string" sanitize:"strict"`
+	// Content is Markdown; HTML in it is shown as text, never rendered
+	Content string `json:"content" validate:"required,min=10,max=10000" sanitize:"markdown"`
+	// Draft saves the post unpublished instead of publishing it right away
+	Draft bool `json:"draft"`
+	// Tags label the post by topic; they are lowercased and hyphenated
+	Tags []string `json:"tags"`
+	// ClientID is a UUID the client generated for the post. Resending the
+	// request with it returns the post already created instead of a copy,
+	// and the post can be addressed by it in place of its ID.
+	ClientID string `json:"client_id" validate:"omitempty,client_id"`
 }
 
 // UpdatePostRequest represents the update post request payload
 type UpdatePostRequest struct {
-	Title   string `json:"title" validate:"required,min=1,max=500,no_html,safe_string"`
-	Content string `json:"content" validate:"required,min=10,max=10000,no_html"`
+	Title   string `json:"title" validate:"required,min=1,max=500,no_html,safe_string" sanitize:"strict"`
+	// Content is Markdown; HTML in it is shown as text, never rendered
+	Content string `json:"content" validate:"required,min=10,max=10000" sanitize:"markdown"`
+	// Tags replace the tags of the post when present; an empty list clears them
+	Tags []string `json:"tags"`
+}
+
+// PostIndexingRequest represents the post indexing request payload
+type PostIndexingRequest struct {
+	NoIndex bool `json:"noindex"`
+}
+
+// PostSlugRequest represents the post slug request payload
+type PostSlugRequest struct {
+	// Slug is lowercase letters and digits separated by single hyphens
+	Slug string `json:"slug" validate:"required,max=100"`
+}
+
+// PostAccessRequest represents the post access request payload
+type PostAccessRequest struct {
+	// Access is public, members or subscribers
+	Access string `json:"access" validate:"required"`
+}
+
+// PostVisibilityRequest represents the post visibility request payload
+type PostVisibilityRequest struct {
+	// Visibility is public, unlisted or private
+	Visibility string `json:"visibility" validate:"required"`
+}
+
+// TranslatePostRequest represents the translate post request payload
+type TranslatePostRequest struct {
+	// Language is the language code of the translation, like fr or pt-BR
+	Language string `json:"language" validate:"required,max=35"`
+	Title    string `json:"title" validate:"required,min=1,max=500,no_html,safe_string" sanitize:"strict"`
+	// Content is Markdown; HTML in it is shown as text, never rendered
+	Content string `json:"content" validate:"required,min=10,max=10000" sanitize:"markdown"`
+}
+
+// SchedulePostRequest represents the schedule post request payload.
+// PublishAt is an RFC 3339 time, or a local time like 2024-03-10T09:00 in
+// the IANA Timezone (UTC when empty).
+type SchedulePostRequest struct {
+	PublishAt string `json:"publish_at" validate:"required"`
+	Timezone  string `json:"timezone" validate:"max=64"`
+}
+
+// SchedulePostResponse represents a scheduled post with the warnings about
+// posts due close to it
+type SchedulePostResponse struct {
+	PostResponse
+	Warnings []ScheduleWarning `json:"warnings"`
+}
+
+// ScheduleWarning reports a scheduled post due within the conflict window
+// of another post. ConflictsWith is left out for posts the user may not see.
+type ScheduleWarning struct {
+	PostID        int    `json:"post_id"`
+	ConflictsWith int    `json:"conflicts_with,omitempty"`
+	PublishAt     string `json:"publish_at"`
+	Message       string `json:"message"`
+}
+
+// CalendarEntryResponse represents a post on the editorial calendar
+type CalendarEntryResponse struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	AuthorID int    `json:"author_id"`
+	Status   string `json:"status"`
+	// PublishAt is in UTC; LocalPublishAt is in the post's time zone
+	PublishAt      string `json:"publish_at"`
+	LocalPublishAt string `json:"local_publish_at"`
+	Timezone       string `json:"timezone,omitempty"`
+}
+
+// CalendarResponse represents the editorial calendar of a time range
+type CalendarResponse struct {
+	From     string                  `json:"from"`
+	To       string                  `json:"to"`
+	Entries  []CalendarEntryResponse `json:"entries"`
+	Warnings []ScheduleWarning       `json:"warnings"`
 }
 
 // PostResponse represents the post data in responses
 type PostResponse struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	AuthorID  int    `json:"author_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID          int    `json:"id"`
+	// ClientID is the UUID the client created the post under, if any
+	ClientID    string `json:"client_id,omitempty"`
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	// Content is Markdown, or sanitized HTML rendered from it when the post
+	// was read with ?format=html
+	Content     string `json:"content"`
+	// ContentFormat is html when the content was rendered with ?format=html
+	ContentFormat string `json:"content_format,omitempty"`
+	AuthorID    int    `json:"author_id"`
+	NoIndex     bool   `json:"noindex"`
+	// Access is who may read the full content: public, members or subscribers
+	Access      string `json:"access"`
+	// Visibility is who may find the post: public, unlisted or private
+	Visibility  string `json:"visibility"`
+	// Language is the language code of the title and content; Translations
+	// are the other languages the post can be read in, picked with the
+	// Accept-Language header
+	Language    string   `json:"language"`
+	Translations []string `json:"translations"`
+	// Locked is set when the reader is not entitled to the post, whose
+	// content is then only an excerpt
+	Locked      bool     `json:"locked"`
+	Status      string   `json:"status"`
+	PublishedAt string   `json:"published_at,omitempty"`
+	// Timezone is the IANA time zone the post was scheduled in
+	Timezone    string   `json:"timezone,omitempty"`
+	Tags        []string `json:"tags"`
+	// ViewCount is the total number of views, updated in batches
+	ViewCount   int64    `json:"view_count"`
+	// TipCount is the number of paid tips on the post
+	TipCount    int64    `json:"tip_count"`
+	// WordCount, ReadingTimeMinutes and Excerpt summarize the content, so
+	// index pages can be built without it
+	WordCount   int      `json:"word_count"`
+	ReadingTimeMinutes int `json:"reading_time_minutes"`
+	// Excerpt is the start of the content, as Markdown
+	Excerpt     string   `json:"excerpt"`
+	// Pinned posts come first in the post lists; Featured posts are listed
+	// by GET /api/v1/posts/featured
+	Pinned      bool     `json:"pinned"`
+	Featured    bool     `json:"featured"`
+	// SeriesID is the series the post belongs to, listed with
+	// GET /api/v1/series/{id}, at SeriesPosition counted from 1
+	SeriesID    *int     `json:"series_id,omitempty"`
+	SeriesPosition int   `json:"series_position,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	// Progress is where the signed-in reader left off, on single posts only
+	Progress *ReadingProgressResponse `json:"progress,omitempty"`
+	// Author is the profile of the author, with ?include=author
+	Author *ProfileResponse `json:"author,omitempty"`
 }
 
 // PostListResponse represents the paginated post list response
@@ -54,18 +235,82 @@ type PostListResponse struct {
 	Total  int            `json:"total"`
 	Limit  int            `json:"limit"`
 	Offset int            `json:"offset"`
+	// NextCursor continues a cursor paginated list; it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// PopularPostResponse represents a post with its views over the window
+type PopularPostResponse struct {
+	PostResponse
+	Views int64 `json:"views"`
+}
+
+// PopularPostListResponse represents the most viewed posts of a window
+type PopularPostListResponse struct {
+	Posts []PopularPostResponse `json:"posts"`
+	Days  int                   `json:"days"`
+	Limit int                   `json:"limit"`
+}
+
+// TrendingPostResponse represents a post with its trending score
+type TrendingPostResponse struct {
+	PostResponse
+	Score float64 `json:"score"`
+}
+
+// TrendingPostListResponse represents the posts trending now
+type TrendingPostListResponse struct {
+	Posts []TrendingPostResponse `json:"posts"`
+	Limit int                    `json:"limit"`
+}
+
+// RelatedPostListResponse represents the posts related to a post
+type RelatedPostListResponse struct {
+	Posts []PostResponse `json:"posts"`
+	Limit int            `json:"limit"`
+}
+
+// PostRevisionResponse represents an earlier version of a post
+type PostRevisionResponse struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	EditedBy  int    `json:"edited_by"`
+	CreatedAt string `json:"created_at"`
+	// TitleChanged tells whether the next version has a different title
+	TitleChanged bool `json:"title_changed"`
+	// Changes is the line diff from this version to the next one
+	Changes []DiffLineResponse `json:"changes"`
+}
+
+// DiffLineResponse is a line of a content diff: op is "=" for kept lines,
+// "-" for removed lines and "+" for added lines
+type DiffLineResponse struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// PostRevisionListResponse represents the revision history of a post
+type PostRevisionListResponse struct {
+	PostID    int                    `json:"post_id"`
+	Revisions []PostRevisionResponse `json:"revisions"`
 }
 
 // CreatePost handles POST /api/v1/posts
 // @Summary Create a new post
-// @Description Create a new blog post
+// @Description Create a new blog post. Posts are published right away unless saved as a draft. When billing is enabled, authors on the free plan are limited to a number of posts. Offline clients can send a client_id UUID: resending the request with it returns the post already created with 200, and post routes accept it in place of the post ID.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param request body CreatePostRequest true "Post creation data"
+// @Param Idempotency-Key header string false "Key of the request, so retries with it return the first response instead of creating duplicates"
 // @Success 201 {object} PostResponse
+// @Success 200 {object} PostResponse "Already created under the client ID"
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse "Idempotency-Key already sent with a different request"
 // @Failure 500 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /api/v1/posts [post]
@@ -77,8 +322,9 @@ func (h *PostHandler) CreatePost(c echo.Context) error {
 	if !ok {
 		h.logger.Error(ctx, "user_id not found in context")
 		return c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error:   "Unauthorized",
-			Message: "User authentication required",
+			Error:     "Unauthorized",
+			Message:   "User authentication required",
+			RequestID: errors.RequestID(c),
 		})
 	}
 
@@ -90,42 +336,69 @@ func (h *PostHandler) CreatePost(c echo.Context) error {
 	}
 
 	// Sanitize input
-	req.Title = middleware.SanitizeInput(req.Title)
-	req.Content = middleware.SanitizeInput(req.Content)
+	middleware.SanitizeFields(&req)
 
 	if err := c.Validate(req); err != nil {
 		h.logger.Error(ctx, "create post request validation failed", "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 
+	// Check the tags before the post is saved
+	if _, err := tag.NormalizeNames(req.Tags); err != nil {
+		h.logger.Error(ctx, "create post request has invalid tags", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
 	// Create post
-	createdPost, err := h.postService.CreatePost(ctx, userID, req.Title, req.Content)
+	var createdPost *post.Post
+	var err error
+	if req.ClientID != "" {
+		var created bool
+		createdPost, created, err = h.postService.CreateClientPost(ctx, userID, req.ClientID, req.Title, req.Content, req.Draft)
+		if err == nil && !created {
+			// A retried request: answer with the post created the first time
+			return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(createdPost)))
+		}
+	} else {
+		create := h.postService.CreatePost
+		if req.Draft {
+			create = h.postService.CreateDraft
+		}
+		createdPost, err = create(ctx, userID, req.Title, req.Content)
+	}
 	if err != nil {
 		h.logger.Error(ctx, "failed to create post", "userID", userID, "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 
-	// Convert to response format
-	response := PostResponse{
-		ID:        createdPost.ID,
-		Title:     createdPost.Title,
-		Content:   createdPost.Content,
-		AuthorID:  createdPost.AuthorID,
-		CreatedAt: createdPost.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: createdPost.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	if len(req.Tags) > 0 {
+		role, _ := c.Get("user_role").(user.Role)
+		createdPost, err = h.postService.SetTags(ctx, userID, role, createdPost.ID, req.Tags)
+		if err != nil {
+			h.logger.Error(ctx, "failed to tag post", "userID", userID, "error", err.Error())
+			return errors.HandleError(c, err)
+		}
 	}
 
+	// Convert to response format
+	response := toPostResponse(createdPost)
+
 	h.logger.Info(ctx, "post created successfully", "postID", createdPost.ID, "userID", userID)
-	return c.JSON(http.StatusCreated, response)
+	return c.JSON(http.StatusCreated, h.mapper.Map(response))
 }
 
 // GetPost handles GET /api/v1/posts/{id}
 // @Summary Get a post by ID
-// @Description Retrieve a specific blog post by its ID
+// @Description Retrieve a specific blog post by its ID. Drafts, scheduled and private posts are only returned to their author and admins. Readers not entitled to members-only or subscriber-only posts get the first paragraph with locked set. Signed-in readers also get the progress they synced. Responses carry an ETag and Last-Modified; sending them back in If-None-Match or If-Modified-Since answers 304 while the post is unchanged.
 // @Tags posts
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the copy the client has"
 // @Success 200 {object} PostResponse
+// @Success 304 "The post is unchanged"
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -141,33 +414,135 @@ func (h *PostHandler) GetPost(c echo.Context) error {
 		return errors.HandleError(c, errors.ErrInvalidRequest)
 	}
 
-	// Get post
-	retrievedPost, err := h.postService.GetPost(ctx, postID)
+	// Unpublished and private posts are only found for those who can edit
+	// them; the user is set when the request carries a valid token
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	retrievedPost, err := h.postService.GetVisiblePost(ctx, userID, role, postID)
 	if err != nil {
 		h.logger.Error(ctx, "failed to get post", "postID", postID, "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 
+	return h.writePost(c, retrievedPost)
+}
+
+// GetPostBySlug handles GET /api/v1/posts/slug/{slug}
+// @Summary Get a post by slug
+// @Description Retrieve a blog post by its slug, like getting it by ID. Old slugs of a post answer with a 301 redirect to its current slug.
+// @Tags posts
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param Accept-Language header string false "Languages the reader prefers, like fr-CH, fr;q=0.9, en;q=0.8"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} PostResponse
+// @Success 301 "Moved to the current slug of the post"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/posts/slug/{slug} [get]
+func (h *PostHandler) GetPostBySlug(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.Param("slug")
+
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	p, err := h.redirectService.Resolve(ctx, userID, role, slug)
+	if err != nil {
+		if !stderrors.Is(err, post.ErrPostNotFound) {
+			h.logger.Error(ctx, "failed to get post by slug", "slug", slug, "error", err.Error())
+		}
+		return errors.HandleError(c, err)
+	}
+
+	if p.Slug != slug {
+		target := h.mapper.Version().Prefix() + "/posts/slug/" + p.Slug
+		if query := c.QueryString(); query != "" {
+			target += "?" + query
+		}
+		return c.Redirect(http.StatusMovedPermanently, target)
+	}
+	return h.writePost(c, p)
+}
+
+// writePost responds with a post the reader may see, recording the view
+func (h *PostHandler) writePost(c echo.Context, retrievedPost *post.Post) error {
+	ctx := c.Request().Context()
+	postID := retrievedPost.ID
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+
+	// Ask crawlers to skip posts flagged noindex and unlisted posts
+	if retrievedPost.NoIndex || !retrievedPost.IsListed() {
+		c.Response().Header().Set(noIndexHeader, noIndexValue)
+	}
+
+	h.postService.RecordView(ctx, retrievedPost)
+
+	// Readers get the translation their Accept-Language prefers
+	if len(retrievedPost.Translations) > 0 {
+		c.Response().Header().Add(echo.HeaderVary, acceptLanguageHeader)
+	}
+	retrievedPost, err := h.postService.LocalizePost(ctx, retrievedPost, c.Request().Header.Get(acceptLanguageHeader))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	c.Response().Header().Set(contentLanguageHeader, retrievedPost.Language)
+
+	// Readers not entitled to restricted posts get an excerpt
+	retrievedPost = h.postService.GatePost(ctx, userID, role, retrievedPost)
+	setReaderCaching(c, retrievedPost)
+
 	// Convert to response format
-	response := PostResponse{
-		ID:        retrievedPost.ID,
-		Title:     retrievedPost.Title,
-		Content:   retrievedPost.Content,
-		AuthorID:  retrievedPost.AuthorID,
-		CreatedAt: retrievedPost.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: retrievedPost.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	response := toPostResponse(retrievedPost)
+	lastModified := retrievedPost.UpdatedAt
+
+	// Signed-in readers resume where they left off on any device
+	if userID > 0 {
+		progress, err := h.progressService.GetProgress(ctx, userID, postID)
+		switch {
+		case err == nil:
+			resp := toReadingProgressResponse(progress)
+			response.Progress = &resp
+			if progress.UpdatedAt.After(lastModified) {
+				lastModified = progress.UpdatedAt
+			}
+		case !stderrors.Is(err, post.ErrProgressNotFound):
+			return errors.HandleError(c, err)
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, "private")
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
 	}
 
-	return c.JSON(http.StatusOK, response)
+	if err := h.renderContent(c, &response); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, &response); err != nil {
+		return errors.HandleError(c, err)
+	}
+	// Polling clients revalidate with the ETag or Last-Modified; views and
+	// tips alone do not make the post change for them
+	tagged := response
+	tagged.ViewCount, tagged.TipCount = 0, 0
+	return writeConditionalJSON(c, h.mapper.Map(response), h.mapper.Map(tagged), lastModified)
 }
 
 // ListPosts handles GET /api/v1/posts
 // @Summary List posts with pagination
-// @Description Retrieve a paginated list of blog posts
+// @Description Retrieve a paginated list of published blog posts. Signed-in users also see their own drafts and scheduled posts; admins see every post. Sending the cursor parameter, empty for the first page, switches to cursor pagination: posts are listed newest first and next_cursor fetches the following page. Sort and filter parameters only apply to offset pagination.
 // @Tags posts
 // @Produce json
-// @Param limit query int false "Number of posts to return (default: 10, max: 100)"
+// @Param tag query string false "Only list posts carrying this tag"
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
 // @Param offset query int false "Number of posts to skip (default: 0)"
+// @Param cursor query string false "Cursor pagination: empty for the first page, then next_cursor of the previous page"
+// @Param sort query string false "Sort by created_at (default), updated_at or title; not with cursor" Enums(created_at, updated_at, title)
+// @Param order query string false "Sort order: asc or desc (default: desc, asc for title)" Enums(asc, desc)
+// @Param author_id query int false "Only list posts of this author"
+// @Param created_after query string false "Only list posts created at or after this RFC 3339 time"
+// @Param created_before query string false "Only list posts created before this RFC 3339 time"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
 // @Success 200 {object} PostListResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -176,38 +551,47 @@ func (h *PostHandler) ListPosts(c echo.Context) error {
 	ctx := c.Request().Context()
 	
 	// Parse pagination parameters
-	limit := 10 // default
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	limit, offset := parsePage(c, h.pagination)
+	cursor, cursorMode, err := parseCursor(c)
+	if err != nil {
+		h.logger.Warn(ctx, "invalid post list cursor", "cursor", c.QueryParam("cursor"))
+		return errors.HandleError(c, err)
 	}
 
-	offset := 0 // default
-	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
+	options, err := parsePostListOptions(c)
+	if err != nil || (cursorMode && !options.IsZero()) {
+		h.logger.Warn(ctx, "invalid post list options", "query", c.QueryString())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
 	}
 
-	// Get posts
-	posts, err := h.postService.ListPosts(ctx, limit, offset)
+	// Get posts; the user is set when the request carries a valid token
+	var posts []*post.Post
+	userID, signedIn := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	if cursorMode {
+		return h.listPostsAfter(c, userID, role, cursor, limit)
+	}
+	switch name := c.QueryParam("tag"); {
+	case !options.IsZero():
+		posts, err = h.postService.ListPostsMatching(ctx, userID, role, name, options, limit, offset)
+	case name != "":
+		posts, err = h.postService.ListPostsByTag(ctx, userID, role, name, limit, offset)
+	case signedIn:
+		posts, err = h.postService.ListPostsFor(ctx, userID, role, limit, offset)
+	default:
+		posts, err = h.postService.ListPosts(ctx, limit, offset)
+	}
 	if err != nil {
 		h.logger.Error(ctx, "failed to list posts", "limit", limit, "offset", offset, "error", err.Error())
 		return errors.HandleError(c, err)
 	}
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+	setReaderCaching(c, posts...)
 
 	// Convert to response format
 	postResponses := make([]PostResponse, len(posts))
 	for i, p := range posts {
-		postResponses[i] = PostResponse{
-			ID:        p.ID,
-			Title:     p.Title,
-			Content:   p.Content,
-			AuthorID:  p.AuthorID,
-			CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+		postResponses[i] = toPostResponse(p)
 	}
 
 	response := PostListResponse{
@@ -217,118 +601,1609 @@ func (h *PostHandler) ListPosts(c echo.Context) error {
 		Offset: offset,
 	}
 
-	return c.JSON(http.StatusOK, response)
+	if err := h.renderContent(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
 }
 
-// UpdatePost handles PUT /api/v1/posts/{id}
-// @Summary Update a post
-// @Description Update an existing blog post (only by author)
+// parsePostListOptions reads the sort, order, author_id, created_after and
+// created_before query parameters of a post listing
+func parsePostListOptions(c echo.Context) (post.ListOptions, error) {
+	options := post.ListOptions{
+		Sort:  post.SortField(c.QueryParam("sort")),
+		Order: post.SortOrder(c.QueryParam("order")),
+	}
+
+	if value := c.QueryParam("author_id"); value != "" {
+		authorID, err := strconv.Atoi(value)
+		if err != nil || authorID <= 0 {
+			return options, errors.ErrInvalidRequest
+		}
+		options.AuthorID = authorID
+	}
+
+	for param, bound := range map[string]**time.Time{"created_after": &options.CreatedAfter, "created_before": &options.CreatedBefore} {
+		if value := c.QueryParam(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return options, err
+			}
+			*bound = &t
+		}
+	}
+
+	return options, options.Validate()
+}
+
+// listPostsAfter responds with a cursor paginated page of posts
+func (h *PostHandler) listPostsAfter(c echo.Context, userID int, role user.Role, cursor keyset.Cursor, limit int) error {
+	ctx := c.Request().Context()
+
+	posts, next, err := h.postService.ListPostsAfter(ctx, userID, role, c.QueryParam("tag"), cursor, limit)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list posts after cursor", "limit", limit, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+	setReaderCaching(c, posts...)
+
+	response := PostListResponse{
+		Posts:      make([]PostResponse, len(posts)),
+		Total:      len(posts),
+		Limit:      limit,
+		NextCursor: next.Encode(),
+	}
+	for i, p := range posts {
+		response.Posts[i] = toPostResponse(p)
+	}
+
+	if err := h.renderContent(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
+}
+
+// ListFeaturedPosts handles GET /api/v1/posts/featured
+// @Summary List featured posts
+// @Description List the published posts admins featured, most recently featured first
 // @Tags posts
-// @Accept json
 // @Produce json
-// @Param id path int true "Post ID"
-// @Param request body UpdatePostRequest true "Post update data"
-// @Success 200 {object} PostResponse
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of posts to skip (default: 0)"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} PostListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/posts/featured [get]
+func (h *PostHandler) ListFeaturedPosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, offset := parsePage(c, h.pagination)
+	posts, err := h.postService.ListFeaturedPosts(ctx, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list featured posts", "limit", limit, "offset", offset, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Featured posts are public, but restricted ones still need gating
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+	setReaderCaching(c, posts...)
+
+	response := PostListResponse{
+		Posts:  make([]PostResponse, len(posts)),
+		Total:  len(posts),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, p := range posts {
+		response.Posts[i] = toPostResponse(p)
+	}
+
+	if err := h.renderContent(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
+}
+
+// ListPopularPosts handles GET /api/v1/posts/popular
+// @Summary List popular posts
+// @Description List the published posts with the most views over the last days, today included, most viewed first. Views are counted in batches, so the latest views may be missing.
+// @Tags posts
+// @Produce json
+// @Param days query int false "Number of days to rank by (default: 7, max: 90)"
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} PopularPostListResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 403 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Security BearerAuth
-// @Router /api/v1/posts/{id} [put]
-func (h *PostHandler) UpdatePost(c echo.Context) error {
+// @Router /api/v1/posts/popular [get]
+func (h *PostHandler) ListPopularPosts(c echo.Context) error {
 	ctx := c.Request().Context()
-	
-	// Get user ID from context (set by auth middleware)
-	userID, ok := c.Get("user_id").(int)
-	if !ok {
-		h.logger.Error(ctx, "user_id not found in context")
-		return errors.HandleError(c, errors.ErrUnauthorized)
+
+	days := defaultPopularDays
+	if value := c.QueryParam("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil {
+			h.logger.Warn(ctx, "invalid popular window", "days", value)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
 	}
+	limit, _ := parsePage(c, h.pagination)
 
-	// Parse post ID
-	postIDStr := c.Param("id")
-	postID, err := strconv.Atoi(postIDStr)
+	popular, err := h.postService.ListPopularPosts(ctx, days, limit)
 	if err != nil {
-		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
-		return errors.HandleError(c, errors.ErrInvalidRequest)
+		h.logger.Error(ctx, "failed to list popular posts", "days", days, "limit", limit, "error", err.Error())
+		return errors.HandleError(c, err)
 	}
 
-	// Parse and validate request
-	var req UpdatePostRequest
-	if err := c.Bind(&req); err != nil {
-		h.logger.Error(ctx, "failed to bind update post request", "error", err.Error())
-		return errors.HandleError(c, errors.ErrInvalidRequest)
+	// Popular posts are public, but restricted ones still need gating
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	posts := make([]*post.Post, len(popular))
+	for i, p := range popular {
+		posts[i] = p.Post
 	}
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+	setReaderCaching(c, posts...)
 
-	// Sanitize input
-	req.Title = middleware.SanitizeInput(req.Title)
-	req.Content = middleware.SanitizeInput(req.Content)
+	response := PopularPostListResponse{
+		Posts: make([]PopularPostResponse, len(popular)),
+		Days:  days,
+		Limit: limit,
+	}
+	responses := make([]*PostResponse, len(popular))
+	for i, p := range popular {
+		response.Posts[i] = PopularPostResponse{PostResponse: toPostResponse(posts[i]), Views: p.Views}
+		responses[i] = &response.Posts[i].PostResponse
+	}
 
-	if err := c.Validate(req); err != nil {
-		h.logger.Error(ctx, "update post request validation failed", "error", err.Error())
+	if err := h.renderContent(c, responses...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, responses...); err != nil {
 		return errors.HandleError(c, err)
 	}
+	return c.JSON(http.StatusOK, response)
+}
 
-	// Update post
-	updatedPost, err := h.postService.UpdatePost(ctx, userID, postID, req.Title, req.Content)
+// ListTrendingPosts handles GET /api/v1/posts/trending
+// @Summary List trending posts
+// @Description List the published posts trending now, highest score first. Scores add up the recent views, bookmarks and comments of a post, recent activity counting the most, and are recalculated periodically.
+// @Tags posts
+// @Produce json
+// @Param limit query int false "Number of posts to return (default: 10, max: 50)"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} TrendingPostListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/posts/trending [get]
+func (h *PostHandler) ListTrendingPosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit := post.DefaultTrendingLimit
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			h.logger.Warn(ctx, "invalid trending posts limit", "limit", value)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
+	}
+
+	trending, err := h.postService.ListTrendingPosts(ctx, limit)
 	if err != nil {
-		h.logger.Error(ctx, "failed to update post", "userID", userID, "postID", postID, "error", err.Error())
+		h.logger.Error(ctx, "failed to list trending posts", "limit", limit, "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 
-	// Convert to response format
-	response := PostResponse{
-		ID:        updatedPost.ID,
-		Title:     updatedPost.Title,
-		Content:   updatedPost.Content,
-		AuthorID:  updatedPost.AuthorID,
-		CreatedAt: updatedPost.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: updatedPost.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	// Trending posts are public, but restricted ones still need gating
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	posts := make([]*post.Post, len(trending))
+	for i, p := range trending {
+		posts[i] = p.Post
 	}
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+	setReaderCaching(c, posts...)
 
-	h.logger.Info(ctx, "post updated successfully", "postID", postID, "userID", userID)
+	response := TrendingPostListResponse{
+		Posts: make([]TrendingPostResponse, len(trending)),
+		Limit: min(limit, post.MaxTrendingLimit),
+	}
+	responses := make([]*PostResponse, len(trending))
+	for i, p := range trending {
+		response.Posts[i] = TrendingPostResponse{PostResponse: toPostResponse(posts[i]), Score: p.Score}
+		responses[i] = &response.Posts[i].PostResponse
+	}
+
+	if err := h.renderContent(c, responses...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, responses...); err != nil {
+		return errors.HandleError(c, err)
+	}
 	return c.JSON(http.StatusOK, response)
 }
 
-// DeletePost handles DELETE /api/v1/posts/{id}
-// @Summary Delete a post
-// @Description Delete an existing blog post (only by author)
+// ListRelatedPosts handles GET /api/v1/posts/{id}/related
+// @Summary List related posts
+// @Description List published posts related to a post, most related first: by default those sharing the most tags, topped up with posts whose titles are most similar. Related posts of drafts and scheduled posts are only listed to their author and admins.
 // @Tags posts
+// @Produce json
 // @Param id path int true "Post ID"
-// @Success 204 "No Content"
+// @Param limit query int false "Number of posts to return (default: 5, max: 20)"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} RelatedPostListResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Security BearerAuth
-// @Router /api/v1/posts/{id} [delete]
-func (h *PostHandler) DeletePost(c echo.Context) error {
+// @Router /api/v1/posts/{id}/related [get]
+func (h *PostHandler) ListRelatedPosts(c echo.Context) error {
 	ctx := c.Request().Context()
-	
-	// Get user ID from context (set by auth middleware)
-	userID, ok := c.Get("user_id").(int)
-	if !ok {
-		h.logger.Error(ctx, "user_id not found in context")
-		return errors.HandleError(c, errors.ErrUnauthorized)
-	}
 
-	// Parse post ID
-	postIDStr := c.Param("id")
-	postID, err := strconv.Atoi(postIDStr)
+	postID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		h.logger.Warn(ctx, "invalid post ID", "postID", c.Param("id"))
 		return errors.HandleError(c, errors.ErrInvalidRequest)
 	}
+	limit := post.DefaultRelatedLimit
+	if value := c.QueryParam("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			h.logger.Warn(ctx, "invalid related posts limit", "limit", value)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
+	}
 
-	// Delete post
-	err = h.postService.DeletePost(ctx, userID, postID)
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	source, err := h.postService.GetVisiblePost(ctx, userID, role, postID)
 	if err != nil {
-		h.logger.Error(ctx, "failed to delete post", "userID", userID, "postID", postID, "error", err.Error())
+		h.logger.Error(ctx, "failed to get post", "postID", postID, "error", err.Error())
 		return errors.HandleError(c, err)
 	}
 
-	h.logger.Info(ctx, "post deleted successfully", "postID", postID, "userID", userID)
-	return c.NoContent(http.StatusNoContent)
+	related, err := h.postService.ListRelatedPosts(ctx, source, limit)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list related posts", "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	related = h.postService.GatePosts(ctx, userID, role, related)
+	setReaderCaching(c, related...)
+
+	response := RelatedPostListResponse{
+		Posts: make([]PostResponse, len(related)),
+		Limit: min(limit, post.MaxRelatedLimit),
+	}
+	responses := make([]*PostResponse, len(related))
+	for i, p := range related {
+		response.Posts[i] = toPostResponse(p)
+		responses[i] = &response.Posts[i]
+	}
+
+	if err := h.renderContent(c, responses...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, responses...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// Feed handles GET /api/v1/feed
+// @Summary Your personalized feed
+// @Description List the published posts of the authors the authenticated user follows, most recently published first
+// @Tags posts
+// @Produce json
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of posts to skip (default: 0)"
+// @Param include query string false "Set to author to embed the profile of the author" Enums(author)
+// @Param format query string false "Set to html to get the content rendered from Markdown to sanitized HTML" Enums(markdown, html)
+// @Success 200 {object} PostListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/feed [get]
+func (h *PostHandler) Feed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+	role, _ := c.Get("user_role").(user.Role)
+
+	limit, offset := parsePage(c, h.pagination)
+	posts, err := h.postService.ListPublishedPosts(ctx, post.PublishedFilter{FollowedBy: userID}, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list feed", "userID", userID, "limit", limit, "offset", offset, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	posts = h.postService.GatePosts(ctx, userID, role, posts)
+
+	// The feed differs for every reader
+	c.Response().Header().Set(echo.HeaderCacheControl, "private")
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+
+	response := PostListResponse{
+		Posts:  make([]PostResponse, len(posts)),
+		Total:  len(posts),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, p := range posts {
+		response.Posts[i] = toPostResponse(p)
+	}
+
+	if err := h.renderContent(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	if err := h.includeAuthors(c, response.postResponses()...); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
+}
+
+// UpdatePost handles PUT /api/v1/posts/{id}
+// @Summary Update a post
+// @Description Update an existing blog post (only by author or an admin)
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body UpdatePostRequest true "Post update data"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id} [put]
+func (h *PostHandler) UpdatePost(c echo.Context) error {
+	ctx := c.Request().Context()
+	
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Parse and validate request
+	var req UpdatePostRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind update post request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Sanitize input
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(req); err != nil {
+		h.logger.Error(ctx, "update post request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Check the tags before the post is saved
+	if _, err := tag.NormalizeNames(req.Tags); err != nil {
+		h.logger.Error(ctx, "update post request has invalid tags", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Update post
+	// Role is set by the auth middleware; admins may update any post
+	role, _ := c.Get("user_role").(user.Role)
+	updatedPost, err := h.postService.UpdatePost(ctx, userID, role, postID, req.Title, req.Content)
+	if err != nil {
+		h.logger.Error(ctx, "failed to update post", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	if req.Tags != nil {
+		updatedPost, err = h.postService.SetTags(ctx, userID, role, postID, req.Tags)
+		if err != nil {
+			h.logger.Error(ctx, "failed to tag post", "userID", userID, "postID", postID, "error", err.Error())
+			return errors.HandleError(c, err)
+		}
+	}
+
+	// Convert to response format
+	response := toPostResponse(updatedPost)
+
+	h.logger.Info(ctx, "post updated successfully", "postID", postID, "userID", userID)
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
+}
+
+// SetPostIndexing handles PUT /api/v1/posts/{id}/indexing
+// @Summary Set post indexing
+// @Description Set whether search engines may index a post (only by author or an admin). Posts flagged noindex get a robots meta tag in the reading view and an X-Robots-Tag header.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body PostIndexingRequest true "Indexing flag"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/indexing [put]
+func (h *PostHandler) SetPostIndexing(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req PostIndexingRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind post indexing request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Role is set by the auth middleware; admins may change any post
+	role, _ := c.Get("user_role").(user.Role)
+	updatedPost, err := h.postService.SetNoIndex(ctx, userID, role, postID, req.NoIndex)
+	if err != nil {
+		h.logger.Error(ctx, "failed to set post indexing", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := toPostResponse(updatedPost)
+
+	h.logger.Info(ctx, "post indexing updated", "postID", postID, "userID", userID, "noindex", req.NoIndex)
+	return c.JSON(http.StatusOK, h.mapper.Map(response))
+}
+
+// PinPost handles POST /api/v1/admin/posts/{id}/pin
+// @Summary Pin a post
+// @Description Pin a published post to the top of the post lists (admin only). Pinned posts come first, most recently pinned first; pinning a pinned post keeps its place.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/posts/{id}/pin [post]
+func (h *PostHandler) PinPost(c echo.Context) error {
+	return h.curate(c, "pin", func(ctx context.Context, adminID, postID int) (*post.Post, error) {
+		return h.postService.SetPinned(ctx, adminID, postID, true)
+	})
+}
+
+// UnpinPost handles DELETE /api/v1/admin/posts/{id}/pin
+// @Summary Unpin a post
+// @Description Return a pinned post to its place in the post lists (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/posts/{id}/pin [delete]
+func (h *PostHandler) UnpinPost(c echo.Context) error {
+	return h.curate(c, "unpin", func(ctx context.Context, adminID, postID int) (*post.Post, error) {
+		return h.postService.SetPinned(ctx, adminID, postID, false)
+	})
+}
+
+// FeaturePost handles POST /api/v1/admin/posts/{id}/feature
+// @Summary Feature a post
+// @Description Add a published post to the featured posts (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/posts/{id}/feature [post]
+func (h *PostHandler) FeaturePost(c echo.Context) error {
+	return h.curate(c, "feature", func(ctx context.Context, adminID, postID int) (*post.Post, error) {
+		return h.postService.SetFeatured(ctx, adminID, postID, true)
+	})
+}
+
+// UnfeaturePost handles DELETE /api/v1/admin/posts/{id}/feature
+// @Summary Unfeature a post
+// @Description Remove a post from the featured posts (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/posts/{id}/feature [delete]
+func (h *PostHandler) UnfeaturePost(c echo.Context) error {
+	return h.curate(c, "unfeature", func(ctx context.Context, adminID, postID int) (*post.Post, error) {
+		return h.postService.SetFeatured(ctx, adminID, postID, false)
+	})
+}
+
+// curate runs a pin or feature change of the post in the path for the
+// signed-in admin and responds with the post
+func (h *PostHandler) curate(c echo.Context, change string, apply func(ctx context.Context, adminID, postID int) (*post.Post, error)) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid post ID", "postID", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	curated, err := apply(ctx, adminID, postID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to "+change+" post", "adminID", adminID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post curated", "change", change, "postID", postID, "adminID", adminID)
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(curated)))
+}
+
+// ChangePostSlug handles PUT /api/v1/posts/{id}/slug
+// @Summary Change the slug of a post
+// @Description Set the slug of a post (only by author or an admin). The previous slug keeps working: the reading view and the slug lookup redirect it to the new slug with a 301.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body PostSlugRequest true "New slug"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Another post uses the slug"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/slug [put]
+func (h *PostHandler) ChangePostSlug(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req PostSlugRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind post slug request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	role, _ := c.Get("user_role").(user.Role)
+	updatedPost, err := h.postService.ChangeSlug(ctx, userID, role, postID, req.Slug)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to change post slug", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(updatedPost)))
+}
+
+// SetPostAccess handles PUT /api/v1/posts/{id}/access
+// @Summary Set post access
+// @Description Set who may read the full content of a post (only by author or an admin): everyone (public), signed-in users (members) or users on a paid plan (subscribers). Other readers get the first paragraph with locked set. Subscriber-only posts need billing to be enabled.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body PostAccessRequest true "Access level"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/access [put]
+func (h *PostHandler) SetPostAccess(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req PostAccessRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind post access request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "post access request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Role is set by the auth middleware; admins may change any post
+	role, _ := c.Get("user_role").(user.Role)
+	updatedPost, err := h.postService.SetAccess(ctx, userID, role, postID, post.Access(req.Access))
+	if err != nil {
+		h.logger.Error(ctx, "failed to set post access", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post access updated", "postID", postID, "userID", userID, "access", req.Access)
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(updatedPost)))
+}
+
+// SetPostVisibility handles PUT /api/v1/posts/{id}/visibility
+// @Summary Set post visibility
+// @Description Set who may find a post (only by author or an admin): everyone (public), readers with the link (unlisted) or only its author and admins (private). Unlisted and private posts are left out of post lists, feeds, tags and rankings.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body PostVisibilityRequest true "Visibility"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/visibility [put]
+func (h *PostHandler) SetPostVisibility(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req PostVisibilityRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind post visibility request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "post visibility request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Role is set by the auth middleware; admins may change any post
+	role, _ := c.Get("user_role").(user.Role)
+	updatedPost, err := h.postService.SetVisibility(ctx, userID, role, postID, post.Visibility(req.Visibility))
+	if err != nil {
+		h.logger.Error(ctx, "failed to set post visibility", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post visibility updated", "postID", postID, "userID", userID, "visibility", req.Visibility)
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(updatedPost)))
+}
+
+// PublishPost handles POST /api/v1/posts/{id}/publish
+// @Summary Publish a post
+// @Description Publish a draft or scheduled post right away (only by author or an admin)
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Post already published"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/publish [post]
+func (h *PostHandler) PublishPost(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Role is set by the auth middleware; admins may publish any post
+	role, _ := c.Get("user_role").(user.Role)
+	publishedPost, err := h.postService.PublishPost(ctx, userID, role, postID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to publish post", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post published successfully", "postID", postID, "userID", userID)
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(publishedPost)))
+}
+
+// SchedulePost handles POST /api/v1/posts/{id}/schedule
+// @Summary Schedule a post
+// @Description Schedule a draft or scheduled post to be published at a future time (only by author or an admin). The post stays hidden until then. Local times are read in the given IANA time zone. Posts due within the conflict window are listed as warnings.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body SchedulePostRequest true "Publish time"
+// @Success 200 {object} SchedulePostResponse
+// @Failure 400 {object} ErrorResponse "Invalid request, time zone or publish time not in the future"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Post already published"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/schedule [post]
+func (h *PostHandler) SchedulePost(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req SchedulePostRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind schedule post request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(req); err != nil {
+		h.logger.Error(ctx, "schedule post request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	publishAt, err := post.ParsePublishTime(req.PublishAt, req.Timezone)
+	if err != nil {
+		h.logger.Error(ctx, "invalid publish time", "publishAt", req.PublishAt, "timezone", req.Timezone, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Role is set by the auth middleware; admins may schedule any post
+	role, _ := c.Get("user_role").(user.Role)
+	scheduledPost, conflicts, err := h.postService.SchedulePost(ctx, userID, role, postID, publishAt, req.Timezone)
+	if err != nil {
+		h.logger.Error(ctx, "failed to schedule post", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post scheduled successfully", "postID", postID, "userID", userID, "publishAt", publishAt, "conflicts", len(conflicts))
+	return c.JSON(http.StatusOK, SchedulePostResponse{
+		PostResponse: toPostResponse(scheduledPost),
+		Warnings:     toScheduleWarnings(scheduledPost, conflicts),
+	})
+}
+
+// Calendar handles GET /api/v1/posts/calendar
+// @Summary Editorial calendar
+// @Description List the scheduled and published posts with a publish time in a range, earliest first, with warnings about scheduled posts due within the conflict window of another post. Authors see published posts and their own scheduled posts, and are warned about conflicts with other authors' plans without seeing them; admins see every post.
+// @Tags posts
+// @Produce json
+// @Param from query string false "Start of the range: RFC 3339 time or date (default now)"
+// @Param to query string false "End of the range: RFC 3339 time or date, at most 92 days after from (default 30 days after from)"
+// @Param timezone query string false "IANA time zone of dates in from and to (default UTC)"
+// @Success 200 {object} CalendarResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/calendar [get]
+func (h *PostHandler) Calendar(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	loc, err := post.LoadTimezone(c.QueryParam("timezone"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid calendar time zone", "timezone", c.QueryParam("timezone"))
+		return errors.HandleError(c, err)
+	}
+	from, err := parseCalendarTime(c.QueryParam("from"), loc, time.Now())
+	if err != nil {
+		h.logger.Warn(ctx, "invalid calendar start", "from", c.QueryParam("from"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	to, err := parseCalendarTime(c.QueryParam("to"), loc, from.AddDate(0, 0, 30))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid calendar end", "to", c.QueryParam("to"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Role is set by the auth middleware; admins see every post
+	role, _ := c.Get("user_role").(user.Role)
+	entries, err := h.postService.Calendar(ctx, userID, role, from, to)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list calendar", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := CalendarResponse{
+		From:     from.UTC().Format(time.RFC3339),
+		To:       to.UTC().Format(time.RFC3339),
+		Entries:  make([]CalendarEntryResponse, 0, len(entries)),
+		Warnings: []ScheduleWarning{},
+	}
+	for _, entry := range entries {
+		p := entry.Post
+		response.Entries = append(response.Entries, CalendarEntryResponse{
+			ID:             p.ID,
+			Title:          p.Title,
+			Slug:           p.Slug,
+			AuthorID:       p.AuthorID,
+			Status:         string(p.Status),
+			PublishAt:      p.PublishedAt.UTC().Format(time.RFC3339),
+			LocalPublishAt: p.LocalPublishedAt().Format(time.RFC3339),
+			Timezone:       p.Timezone,
+		})
+		response.Warnings = append(response.Warnings, toScheduleWarnings(p, entry.Conflicts)...)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseCalendarTime parses a calendar bound given as an RFC 3339 time or a
+// date starting at midnight in loc; empty values use the fallback
+func parseCalendarTime(value string, loc *time.Location, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, loc)
+}
+
+// toScheduleWarnings describes the conflicts of a scheduled post
+func toScheduleWarnings(p *post.Post, conflicts []post.Conflict) []ScheduleWarning {
+	warnings := make([]ScheduleWarning, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		gap := p.PublishedAt.Sub(conflict.PublishAt)
+		relation := "after"
+		if gap < 0 {
+			gap, relation = -gap, "before"
+		}
+
+		other := "another author's scheduled post"
+		if conflict.PostID > 0 {
+			other = fmt.Sprintf("post %d", conflict.PostID)
+		}
+		warnings = append(warnings, ScheduleWarning{
+			PostID:        p.ID,
+			ConflictsWith: conflict.PostID,
+			PublishAt:     p.PublishedAt.UTC().Format(time.RFC3339),
+			Message:       fmt.Sprintf("Post %d is published %s %s %s", p.ID, gap, relation, other),
+		})
+	}
+	return warnings
+}
+
+// ListRevisions handles GET /api/v1/posts/{id}/revisions
+// @Summary List post revisions
+// @Description List the earlier versions of a post, newest first (only by author or an admin). Each revision carries a line diff to the version that replaced it.
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostRevisionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/revisions [get]
+func (h *PostHandler) ListRevisions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Role is set by the auth middleware; admins may see the history of any post
+	role, _ := c.Get("user_role").(user.Role)
+	current, revisions, err := h.postService.ListRevisions(ctx, userID, role, postID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list post revisions", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toPostRevisionListResponse(current, revisions))
+}
+
+// RestoreRevision handles POST /api/v1/posts/{id}/revisions/{rev}/restore
+// @Summary Restore a post revision
+// @Description Bring back the title and content of an earlier version of a post (only by its author). The replaced version is kept as a new revision.
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/revisions/{rev}/restore [post]
+func (h *PostHandler) RestoreRevision(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID and revision number
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		h.logger.Error(ctx, "invalid revision number", "revision", c.Param("rev"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	role, _ := c.Get("user_role").(user.Role)
+	restoredPost, err := h.postService.RestoreRevision(ctx, userID, role, postID, number)
+	if err != nil {
+		h.logger.Error(ctx, "failed to restore post revision", "userID", userID, "postID", postID, "revision", number, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post revision restored successfully", "postID", postID, "userID", userID, "revision", number)
+	return c.JSON(http.StatusOK, h.mapper.Map(toPostResponse(restoredPost)))
+}
+
+// TranslatePost handles POST /api/v1/posts/{id}/translations
+// @Summary Translate a post
+// @Description Save the title and content of a post in another language (only by author or an admin). Translating to a language again replaces the earlier translation. Readers get the translation their Accept-Language header prefers.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body TranslatePostRequest true "Translation"
+// @Success 201 {object} PostResponse
+// @Success 200 {object} PostResponse "Replaced the earlier translation to the language"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/translations [post]
+func (h *PostHandler) TranslatePost(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req TranslatePostRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind translate post request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "translate post request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	// Role is set by the auth middleware; admins may translate any post
+	role, _ := c.Get("user_role").(user.Role)
+	translatedPost, created, err := h.postService.TranslatePost(ctx, userID, role, postID, req.Language, req.Title, req.Content)
+	if err != nil {
+		h.logger.Error(ctx, "failed to translate post", "userID", userID, "postID", postID, "language", req.Language, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post translated successfully", "postID", postID, "userID", userID, "language", translatedPost.Language)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.Response().Header().Set(contentLanguageHeader, translatedPost.Language)
+	return c.JSON(status, h.mapper.Map(toPostResponse(translatedPost)))
+}
+
+// DeletePost handles DELETE /api/v1/posts/{id}
+// @Summary Delete a post
+// @Description Delete an existing blog post with its comments (only by author or an admin)
+// @Tags posts
+// @Param id path int true "Post ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id} [delete]
+func (h *PostHandler) DeletePost(c echo.Context) error {
+	ctx := c.Request().Context()
+	
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	// Parse post ID
+	postIDStr := c.Param("id")
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "invalid post ID", "postID", postIDStr)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Delete post
+	// Role is set by the auth middleware; admins may delete any post
+	role, _ := c.Get("user_role").(user.Role)
+	err = h.postService.DeletePost(ctx, userID, role, postID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to delete post", "userID", userID, "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "post deleted successfully", "postID", postID, "userID", userID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toPostResponse converts a post into its API representation
+func toPostResponse(p *post.Post) PostResponse {
+	response := PostResponse{
+		ID:        p.ID,
+		Title:     p.Title,
+		Slug:      p.Slug,
+		Content:   p.Content,
+		AuthorID:  p.AuthorID,
+		NoIndex:   p.NoIndex,
+		Access:    string(p.Access),
+		Visibility: string(p.Visibility),
+		Language:  p.Language,
+		Translations: p.Translations,
+		Locked:    p.Locked,
+		Status:    string(p.Status),
+		Tags:      p.Tags,
+		ViewCount: p.ViewCount,
+		TipCount:  p.TipCount,
+		WordCount: p.WordCount,
+		ReadingTimeMinutes: p.ReadingTime,
+		Excerpt:   p.Excerpt,
+		Pinned:    p.IsPinned(),
+		Featured:  p.IsFeatured(),
+		SeriesID:  p.SeriesID,
+		SeriesPosition: p.SeriesPosition,
+		CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if p.ClientID != nil {
+		response.ClientID = *p.ClientID
+	}
+	if p.PublishedAt != nil {
+		response.PublishedAt = p.PublishedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.Timezone = p.Timezone
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if response.Translations == nil {
+		response.Translations = []string{}
+	}
+	return response
+}
+
+// setReaderCaching keeps responses holding restricted posts out of shared
+// caches, since their content depends on who is reading
+func setReaderCaching(c echo.Context, posts ...*post.Post) {
+	for _, p := range posts {
+		if p.IsRestricted() {
+			c.Response().Header().Set(echo.HeaderCacheControl, "private")
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+			return
+		}
+	}
+}
+
+// renderContent replaces the Markdown content of the post responses with
+// sanitized HTML when the request asks for it with ?format=html
+func (h *PostHandler) renderContent(c echo.Context, responses ...*PostResponse) error {
+	switch format := c.QueryParam("format"); format {
+	case "", ContentFormatMarkdown:
+		return nil
+	case ContentFormatHTML:
+		for _, r := range responses {
+			r.Content = markdown.Render(r.Content)
+			r.ContentFormat = ContentFormatHTML
+		}
+		return nil
+	default:
+		h.logger.Warn(c.Request().Context(), "invalid content format", "format", format)
+		return errors.ErrInvalidRequest
+	}
+}
+
+// includeAuthors embeds the profile of their author in the post responses
+// when the request asks for it with ?include=author, the only expansion.
+// Posts of deleted or deactivated authors are left without one.
+func (h *PostHandler) includeAuthors(c echo.Context, responses ...*PostResponse) error {
+	include := c.QueryParam("include")
+	if include == "" {
+		return nil
+	}
+	for _, name := range strings.Split(include, ",") {
+		if strings.TrimSpace(name) != "author" {
+			h.logger.Warn(c.Request().Context(), "invalid include parameter", "include", include)
+			return errors.ErrInvalidRequest
+		}
+	}
+	return h.addAuthors(c.Request().Context(), responses)
+}
+
+// addAuthors sets the author of the post responses
+func (h *PostHandler) addAuthors(ctx context.Context, responses []*PostResponse) error {
+	ids := make([]int, len(responses))
+	for i, r := range responses {
+		ids[i] = r.AuthorID
+	}
+
+	profiles, err := h.profileService.GetProfiles(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range responses {
+		if p, ok := profiles[r.AuthorID]; ok {
+			author := toProfileResponse(p)
+			r.Author = &author
+		}
+	}
+	return nil
+}
+
+// postResponses returns pointers to the posts of the list, to update them
+// in place
+func (l *PostListResponse) postResponses() []*PostResponse {
+	responses := make([]*PostResponse, len(l.Posts))
+	for i := range l.Posts {
+		responses[i] = &l.Posts[i]
+	}
+	return responses
+}
+
+// toPostRevisionListResponse converts a post's revisions, newest first, into
+// their API representation. Each revision is diffed against the version that
+// replaced it: the next newer revision, or the post itself for the newest.
+func toPostRevisionListResponse(current *post.Post, revisions []*post.Revision) PostRevisionListResponse {
+	response := PostRevisionListResponse{
+		PostID:    current.ID,
+		Revisions: make([]PostRevisionResponse, len(revisions)),
+	}
+
+	nextTitle, nextContent := current.Title, current.Content
+	for i, rev := range revisions {
+		diff := post.DiffLines(rev.Content, nextContent)
+		changes := make([]DiffLineResponse, len(diff))
+		for j, line := range diff {
+			changes[j] = DiffLineResponse{Op: string(line.Op), Text: line.Text}
+		}
+
+		response.Revisions[i] = PostRevisionResponse{
+			Number:       rev.Number,
+			Title:        rev.Title,
+			Content:      rev.Content,
+			EditedBy:     rev.EditedBy,
+			CreatedAt:    rev.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			TitleChanged: rev.Title != nextTitle,
+			Changes:      changes,
+		}
+		nextTitle, nextContent = rev.Title, rev.Content
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the post routes
+func (h *PostHandler) RouteDocs() []RouteDoc {
+	examplePost := PostResponse{
+		ID:          1,
+		Title:       "My First Blog Post",
+		Slug:        "my-first-blog-post",
+		Content:     "This is the content of my first blog post.",
+		AuthorID:    1,
+		Access:      string(post.AccessPublic),
+		Visibility:  string(post.VisibilityPublic),
+		Language:    post.DefaultLanguage,
+		Translations: []string{},
+		Status:      string(post.StatusPublished),
+		PublishedAt: "2024-01-15T10:30:00Z",
+		Tags:        []string{"golang", "web-development"},
+		CreatedAt:   "2024-01-15T10:30:00Z",
+		UpdatedAt:   "2024-01-15T10:30:00Z",
+	}
+	popularPost := examplePost
+	popularPost.ViewCount = 1843
+	popularPost.TipCount = 12
+	pinnedPost := examplePost
+	pinnedPost.Pinned = true
+	featuredPost := examplePost
+	featuredPost.Featured = true
+	scheduledPost := examplePost
+	scheduledPost.Status = string(post.StatusScheduled)
+	scheduledPost.PublishedAt = "2024-02-01T08:00:00Z"
+	scheduledPost.Timezone = "Europe/Berlin"
+	exampleWarning := ScheduleWarning{
+		PostID:        1,
+		ConflictsWith: 2,
+		PublishAt:     scheduledPost.PublishedAt,
+		Message:       "Post 1 is published 30m0s before post 2",
+	}
+	translatedPost := examplePost
+	translatedPost.Title = "Mon premier article"
+	translatedPost.Content = "Voici le contenu de mon premier article."
+	translatedPost.Language = "fr"
+	translatedPost.Translations = []string{post.DefaultLanguage}
+	exampleRevision := PostRevisionResponse{
+		Number:       1,
+		Title:        "My First Post",
+		Content:      "This is the content of my first post.",
+		EditedBy:     1,
+		CreatedAt:    "2024-01-16T08:00:00Z",
+		TitleChanged: true,
+		Changes: []DiffLineResponse{
+			{Op: string(post.DiffDelete), Text: "This is the content of my first post."},
+			{Op: string(post.DiffInsert), Text: examplePost.Content},
+		},
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts",
+			Summary:         "List posts with pagination",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: PostListResponse{Posts: []PostResponse{examplePost}, Total: 1, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/posts/popular",
+			Summary:        "List popular posts",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: PopularPostListResponse{
+				Posts: []PopularPostResponse{{PostResponse: popularPost, Views: 312}},
+				Days:  7,
+				Limit: 10,
+			},
+			Errors: withCommonErrors(errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/featured",
+			Summary:         "List featured posts",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: PostListResponse{Posts: []PostResponse{featuredPost}, Total: 1, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/trending",
+			Summary:         "List trending posts",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: TrendingPostListResponse{Posts: []TrendingPostResponse{{PostResponse: popularPost, Score: 87.5}}, Limit: post.DefaultTrendingLimit},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/{id}/related",
+			Summary:         "List related posts",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: RelatedPostListResponse{Posts: []PostResponse{examplePost}, Limit: post.DefaultRelatedLimit},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/feed",
+			Summary:         "Your personalized feed",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: PostListResponse{Posts: []PostResponse{examplePost}, Total: 1, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/slug/{slug}",
+			Summary:         "Get a post by slug",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/{id}",
+			Summary:         "Get a blog post by ID",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts",
+			Summary:         "Create a new blog post",
+			RequestExample:  CreatePostRequest{Title: examplePost.Title, Content: examplePost.Content, Tags: []string{"Golang", "Web Development"}},
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodePlanLimitReached, errors.ErrCodeIdempotencyKeyReused, errors.ErrCodeIdempotencyKeyInUse),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}",
+			Summary:         "Update a blog post",
+			RequestExample:  UpdatePostRequest{Title: "Updated Title", Content: "This is the updated content.", Tags: []string{"golang"}},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}/slug",
+			Summary:         "Change the slug of a post",
+			RequestExample:  PostSlugRequest{Slug: examplePost.Slug},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/posts/{id}/pin",
+			Summary:         "Pin a post",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: pinnedPost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            "/api/v1/admin/posts/{id}/pin",
+			Summary:         "Unpin a post",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/posts/{id}/feature",
+			Summary:         "Feature a post",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: featuredPost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            "/api/v1/admin/posts/{id}/feature",
+			Summary:         "Unfeature a post",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}/indexing",
+			Summary:         "Set post indexing",
+			RequestExample:  PostIndexingRequest{NoIndex: true},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}/access",
+			Summary:         "Set post access",
+			RequestExample:  PostAccessRequest{Access: string(post.AccessMembers)},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}/visibility",
+			Summary:         "Set post visibility",
+			RequestExample:  PostVisibilityRequest{Visibility: string(post.VisibilityUnlisted)},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts/{id}/publish",
+			Summary:         "Publish a post",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts/{id}/schedule",
+			Summary:         "Schedule a post",
+			RequestExample:  SchedulePostRequest{PublishAt: "2024-02-01T09:00", Timezone: scheduledPost.Timezone},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: SchedulePostResponse{PostResponse: scheduledPost, Warnings: []ScheduleWarning{exampleWarning}},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/posts/calendar",
+			Summary:        "Editorial calendar",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: CalendarResponse{
+				From: "2024-02-01T00:00:00Z",
+				To:   "2024-03-02T00:00:00Z",
+				Entries: []CalendarEntryResponse{
+					{ID: 1, Title: examplePost.Title, Slug: examplePost.Slug, AuthorID: 1, Status: scheduledPost.Status, PublishAt: scheduledPost.PublishedAt, LocalPublishAt: "2024-02-01T09:00:00+01:00", Timezone: scheduledPost.Timezone},
+					{ID: 2, Title: "Release Notes", Slug: "release-notes", AuthorID: 1, Status: scheduledPost.Status, PublishAt: "2024-02-01T08:30:00Z", LocalPublishAt: "2024-02-01T08:30:00Z"},
+				},
+				Warnings: []ScheduleWarning{exampleWarning},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/{id}/revisions",
+			Summary:         "List post revisions",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: PostRevisionListResponse{PostID: 1, Revisions: []PostRevisionResponse{exampleRevision}},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts/{id}/revisions/{rev}/restore",
+			Summary:         "Restore a post revision",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: examplePost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/posts/{id}/translations",
+			Summary:         "Translate a post",
+			RequestExample:  TranslatePostRequest{Language: translatedPost.Language, Title: translatedPost.Title, Content: translatedPost.Content},
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: translatedPost,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/posts/{id}",
+			Summary:        "Delete a blog post",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
 }
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// ProfileHandler handles public user profiles
+type ProfileHandler struct {
+	profileService user.ProfileService
+	logger         service.Logger
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profileService user.ProfileService, logger service.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// ProfileResponse represents the public profile of a user
+type ProfileResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
+	JoinedAt  time.Time `json:"joined_at"`
+	// PostCount counts the published posts of the user
+	PostCount int `json:"post_count"`
+}
+
+// UpdateProfileRequest represents the update profile request payload; empty
+// fields clear the bio or avatar
+type UpdateProfileRequest struct {
+	Bio       string `json:"bio" validate:"max=500,no_html" sanitize:"basic"`
+	AvatarURL string `json:"avatar_url" validate:"max=2048"`
+}
+
+// GetProfile handles GET /api/v1/users/{id}
+// @Summary Get the public profile of a user
+// @Description Get the name, bio, avatar, joined date and number of published posts of a user. Deleted and deactivated accounts are not found.
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} ProfileResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id} [get]
+func (h *ProfileHandler) GetProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	profile, err := h.profileService.GetProfile(ctx, id)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toProfileResponse(profile))
+}
+
+// UpdateProfile handles PUT /api/v1/users/me/profile
+// @Summary Update your public profile
+// @Description Set the bio and avatar of the authenticated user. The avatar is an https URL; empty fields clear them.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} ProfileResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/profile [put]
+func (h *ProfileHandler) UpdateProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind update profile request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	middleware.SanitizeFields(&req)
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	profile, err := h.profileService.UpdateProfile(ctx, userID, req.Bio, req.AvatarURL)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toProfileResponse(profile))
+}
+
+// toProfileResponse converts a profile to its response format
+func toProfileResponse(p *user.Profile) ProfileResponse {
+	return ProfileResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		Bio:       p.Bio,
+		AvatarURL: p.AvatarURL,
+		JoinedAt:  p.JoinedAt,
+		PostCount: p.PostCount,
+	}
+}
+
+// RouteDocs returns examples and error codes for the profile routes
+func (h *ProfileHandler) RouteDocs() []RouteDoc {
+	example := ProfileResponse{
+		ID:        1,
+		Name:      "John Doe",
+		Bio:       "Writes about Go and distributed systems.",
+		AvatarURL: "https://images.example.com/avatars/1.png",
+		JoinedAt:  time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		PostCount: 12,
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/{id}",
+			Summary:         "Get the public profile of a user",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/users/me/profile",
+			Summary:         "Update your public profile",
+			RequestExample:  UpdateProfileRequest{Bio: example.Bio, AvatarURL: example.AvatarURL},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+	}
+}
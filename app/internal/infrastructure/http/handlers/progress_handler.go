@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// ProgressHandler handles reading progress synced across devices
+type ProgressHandler struct {
+	progressService post.ProgressService
+	pagination      service.PageLimits
+	logger          service.Logger
+}
+
+// NewProgressHandler creates a new reading progress handler
+func NewProgressHandler(progressService post.ProgressService, pagination service.PageLimits, logger service.Logger) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+		pagination:      pagination,
+		logger:          logger,
+	}
+}
+
+// SaveProgressRequest represents the request payload for saving the reading
+// position on a post
+type SaveProgressRequest struct {
+	Percent *float64 `json:"percent" validate:"required,min=0,max=100"`
+	// Anchor is an optional position in the post, such as a heading ID
+	Anchor string `json:"anchor,omitempty" validate:"max=255,no_html" sanitize:"strict"`
+	// RecordedAt is when the device read up to the position; it defaults
+	// to now and lets devices syncing late lose to newer positions
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+}
+
+// ReadingProgressResponse represents a reading position in API responses
+type ReadingProgressResponse struct {
+	PostID     int     `json:"post_id"`
+	Percent    float64 `json:"percent"`
+	Anchor     string  `json:"anchor,omitempty"`
+	RecordedAt string  `json:"recorded_at"`
+}
+
+// ReadingHistoryEntryResponse represents a post in the reading history
+type ReadingHistoryEntryResponse struct {
+	ReadingProgressResponse
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// ReadingHistoryResponse represents a page of the reading history
+type ReadingHistoryResponse struct {
+	Entries []ReadingHistoryEntryResponse `json:"entries"`
+	Total   int                           `json:"total"`
+	Limit   int                           `json:"limit"`
+	Offset  int                           `json:"offset"`
+}
+
+// SaveProgress handles PUT /api/v1/posts/{id}/progress
+// @Summary Save reading progress
+// @Description Save how far the authenticated user read a published post, to resume on any device. The latest position wins: an update recorded before the stored position leaves it unchanged, and the stored position is returned either way.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param progress body SaveProgressRequest true "Reading position"
+// @Success 200 {object} ReadingProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/progress [put]
+func (h *ProgressHandler) SaveProgress(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req SaveProgressRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "Failed to bind reading progress request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	middleware.SanitizeFields(&req)
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	var recordedAt time.Time
+	if req.RecordedAt != nil {
+		recordedAt = *req.RecordedAt
+	}
+	progress, err := h.progressService.SaveProgress(ctx, userID, postID, *req.Percent, req.Anchor, recordedAt)
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to save reading progress", "post_id", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toReadingProgressResponse(progress))
+}
+
+// ReadingHistory handles GET /api/v1/users/me/reading-history
+// @Summary List reading history
+// @Description List the published posts the authenticated user read, most recently read first, with the position to resume from
+// @Tags users
+// @Produce json
+// @Param limit query int false "Number of posts to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of posts to skip (default: 0)"
+// @Success 200 {object} ReadingHistoryResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/reading-history [get]
+func (h *ProgressHandler) ReadingHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	limit, offset := parsePage(c, h.pagination)
+	entries, err := h.progressService.ListReadingHistory(ctx, userID, limit, offset)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to list reading history", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := ReadingHistoryResponse{
+		Entries: make([]ReadingHistoryEntryResponse, len(entries)),
+		Total:   len(entries),
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for i, e := range entries {
+		response.Entries[i] = ReadingHistoryEntryResponse{
+			ReadingProgressResponse: toReadingProgressResponse(&e.Progress),
+			Title:                   e.Title,
+			Slug:                    e.Slug,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// toReadingProgressResponse converts a reading position to its response
+// format
+func toReadingProgressResponse(p *post.Progress) ReadingProgressResponse {
+	return ReadingProgressResponse{
+		PostID:     p.PostID,
+		Percent:    p.Percent,
+		Anchor:     p.Anchor,
+		RecordedAt: p.RecordedAt.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+	}
+}
+
+// RouteDocs returns examples and error codes for the reading progress routes
+func (h *ProgressHandler) RouteDocs() []RouteDoc {
+	percent := 42.5
+	recordedAt, _ := time.Parse(time.RFC3339, "2024-01-15T12:00:00Z")
+	example := ReadingProgressResponse{
+		PostID:     1,
+		Percent:    percent,
+		Anchor:     "getting-started",
+		RecordedAt: "2024-01-15T12:00:00.000Z",
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/posts/{id}/progress",
+			Summary:         "Save reading progress",
+			RequestExample:  SaveProgressRequest{Percent: &percent, Anchor: example.Anchor, RecordedAt: &recordedAt},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/users/me/reading-history",
+			Summary:        "List reading history",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: ReadingHistoryResponse{
+				Entries: []ReadingHistoryEntryResponse{{ReadingProgressResponse: example, Title: "Getting Started with Go", Slug: "getting-started-with-go"}},
+				Total:   1,
+				Limit:   10,
+				Offset:  0,
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized),
+		},
+	}
+}
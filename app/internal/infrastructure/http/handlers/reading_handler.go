@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/views"
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+const (
+	// readingViewCommentLimit caps the comments shown below a post
+	readingViewCommentLimit = 100
+	// descriptionLength is the maximum length of meta descriptions
+	descriptionLength = 160
+)
+
+// ReadingSettings configures the HTML reading view
+type ReadingSettings struct {
+	SiteName string
+	// BaseURL is the public URL used to build canonical links
+	BaseURL string
+	// FeedURL is advertised through an RSS discovery link when set
+	FeedURL string
+}
+
+// ReadingHandler serves server-rendered HTML pages for readers
+type ReadingHandler struct {
+	postService     post.Service
+	redirectService post.RedirectService
+	commentService  comment.Service
+	settings        ReadingSettings
+	logger          service.Logger
+}
+
+// NewReadingHandler creates a new reading view handler
+func NewReadingHandler(postService post.Service, redirectService post.RedirectService, commentService comment.Service, settings ReadingSettings, logger service.Logger) *ReadingHandler {
+	return &ReadingHandler{
+		postService:     postService,
+		redirectService: redirectService,
+		commentService:  commentService,
+		settings:        settings,
+		logger:          logger,
+	}
+}
+
+// ShowPost renders a post and its comments as an HTML page. Old slugs of
+// a post are redirected to its current slug.
+func (h *ReadingHandler) ShowPost(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.Param("slug")
+
+	// Drafts, scheduled and private posts are not public
+	p, err := h.redirectService.Resolve(ctx, 0, "", slug)
+	if err != nil {
+		if stderrors.Is(err, post.ErrPostNotFound) {
+			return c.Render(http.StatusNotFound, "not_found.html", h.page("Not found", ""))
+		}
+		h.logger.Error(ctx, "failed to load post for reading view", "slug", slug, "error", err.Error())
+		return c.String(http.StatusInternalServerError, "An internal server error occurred")
+	}
+	if p.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, "/p/"+p.Slug)
+	}
+
+	comments, err := h.commentService.GetCommentsByPost(ctx, p.ID, 0, "", readingViewCommentLimit, 0)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load comments for reading view", "post_id", p.ID, "error", err.Error())
+		return c.String(http.StatusInternalServerError, "An internal server error occurred")
+	}
+
+	h.postService.RecordView(ctx, p)
+
+	// The user is only set when the request carries a valid token; everyone
+	// else gets the teaser of restricted posts
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	p = h.postService.GatePost(ctx, userID, role, p)
+	setReaderCaching(c, p)
+
+	publishedAt := p.CreatedAt
+	if p.PublishedAt != nil {
+		publishedAt = *p.PublishedAt
+	}
+
+	data := views.PostPage{
+		Page: h.page(p.Title, summarize(p.Content)),
+		Post: views.PostView{
+			Title:        p.Title,
+			ContentHTML:  template.HTML(markdown.Render(p.Content)),
+			PublishedAt:  publishedAt.Format("January 2, 2006"),
+			PublishedISO: publishedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Locked:       p.Locked,
+		},
+		Comments: make([]views.CommentView, 0, len(comments)),
+	}
+	if p.NoIndex || !p.IsListed() {
+		// Unlisted posts are read by link only, so they stay out of search
+		// results too. The header also covers themes whose layout omits the
+		// meta tag
+		data.NoIndex = true
+		c.Response().Header().Set(noIndexHeader, noIndexValue)
+	}
+	if h.settings.BaseURL != "" {
+		data.CanonicalURL = h.settings.BaseURL + "/p/" + p.Slug
+	}
+	for _, cm := range comments {
+		data.Comments = append(data.Comments, views.CommentView{
+			ID:          cm.ID,
+			AuthorName:  cm.AuthorName,
+			Content:     cm.Content,
+			ContentHTML: template.HTML(markdown.RenderBasic(cm.Content)),
+			CreatedAt:   cm.CreatedAt.Format("January 2, 2006"),
+		})
+	}
+
+	return c.Render(http.StatusOK, "post.html", data)
+}
+
+// page builds the shared page data
+func (h *ReadingHandler) page(title, description string) views.Page {
+	return views.Page{
+		SiteName:    h.settings.SiteName,
+		Title:       title,
+		Description: description,
+		FeedURL:     h.settings.FeedURL,
+	}
+}
+
+// summarize returns the start of the content as a single line, cut at a word
+// boundary, for use in meta descriptions
+func summarize(content string) string {
+	text := strings.Join(strings.Fields(content), " ")
+	if len(text) <= descriptionLength {
+		return text
+	}
+	cut := strings.LastIndex(text[:descriptionLength], " ")
+	if cut <= 0 {
+		cut = descriptionLength
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return text[:cut] + "…"
+}
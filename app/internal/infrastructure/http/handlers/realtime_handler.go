@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/realtime"
+)
+
+// RealtimeHandler serves the WebSocket connections pushing notifications to
+// signed-in users
+type RealtimeHandler struct {
+	hub    *realtime.Hub
+	logger service.Logger
+}
+
+// NewRealtimeHandler creates a new realtime handler
+func NewRealtimeHandler(hub *realtime.Hub, logger service.Logger) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// errRealtimeUnavailable answers connections while the server shuts down
+var errRealtimeUnavailable = errors.NewAPIError(errors.ErrCodeService, "Real-time updates are unavailable while the server restarts", http.StatusServiceUnavailable)
+
+// Connect handles GET /api/v1/ws
+// @Summary Receive notifications in real time
+// @Description Upgrade to a WebSocket pushing JSON messages {"type", "data"} to the authenticated user: "notification" with a notification as listed by /users/me/notifications, and "follower" with the follower_id of a new follower. Browsers, which cannot set the Authorization header, sign in by offering the subprotocols "bearer" and the access token: new WebSocket(url, ["bearer", token]). Clients have nothing to send; connections too slow to keep up are closed and should reconnect and list their notifications.
+// @Tags users
+// @Param Sec-WebSocket-Protocol header string false "bearer, followed by the access token, for browsers"
+// @Security BearerAuth
+// @Success 101 "Switching to the WebSocket protocol"
+// @Failure 400 {object} ErrorResponse "Not a WebSocket handshake"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 429 {object} ErrorResponse "Too many open connections"
+// @Failure 503 {object} ErrorResponse "Server shutting down"
+// @Router /api/v1/ws [get]
+func (h *RealtimeHandler) Connect(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	if !strings.EqualFold(c.Request().Header.Get(echo.HeaderUpgrade), "websocket") {
+		h.logger.Warn(ctx, "realtime request without websocket upgrade", "userID", userID)
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	session, err := h.hub.Accept(userID)
+	if stderrors.Is(err, realtime.ErrTooManyConnections) {
+		h.logger.Warn(ctx, "too many realtime connections", "userID", userID)
+		return errors.HandleError(c, errors.ErrTooManyRequests)
+	}
+	if err != nil {
+		return errors.HandleError(c, errRealtimeUnavailable)
+	}
+	defer session.Close()
+
+	started := time.Now()
+	server := websocket.Server{Handshake: selectWebSocketProtocol, Handler: session.Serve}
+	server.ServeHTTP(c.Response(), c.Request())
+	h.logger.Info(ctx, "realtime connection closed", "userID", userID, "duration", time.Since(started).String())
+	return nil
+}
+
+// selectWebSocketProtocol accepts handshakes from any origin, as they are
+// signed in with a token rather than cookies, and answers the bearer
+// subprotocol browsers offer
+func selectWebSocketProtocol(config *websocket.Config, req *http.Request) error {
+	offered := config.Protocol
+	config.Protocol = nil
+	for _, protocol := range offered {
+		if protocol == middleware.WebSocketProtocol {
+			config.Protocol = []string{protocol}
+		}
+	}
+	return nil
+}
+
+// RouteDocs returns examples and error codes for the realtime routes
+func (h *RealtimeHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/ws",
+			Summary:        "Receive notifications in real time",
+			ResponseStatus: http.StatusSwitchingProtocols,
+			ResponseExample: service.RealtimeMessage{
+				Type: service.RealtimeNotification,
+				Data: service.RealtimeNotificationData{
+					ID:        3,
+					Kind:      "reply",
+					PostID:    1,
+					CommentID: 12,
+					ActorName: "Jane Reader",
+					CreatedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeRateLimitExceeded, errors.ErrCodeService),
+		},
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// RedirectHandler handles the management of post slug redirects by admins
+type RedirectHandler struct {
+	redirectService post.RedirectService
+	pagination      service.PageLimits
+	logger          service.Logger
+}
+
+// NewRedirectHandler creates a new redirect handler
+func NewRedirectHandler(redirectService post.RedirectService, pagination service.PageLimits, logger service.Logger) *RedirectHandler {
+	return &RedirectHandler{
+		redirectService: redirectService,
+		pagination:      pagination,
+		logger:          logger,
+	}
+}
+
+// RedirectRequest represents the create and update redirect request payload
+type RedirectRequest struct {
+	// FromSlug is the old slug; no post may use it
+	FromSlug string `json:"from_slug" validate:"required,max=255"`
+	PostID   int    `json:"post_id" validate:"required,min=1"`
+}
+
+// RedirectResponse represents a redirect
+type RedirectResponse struct {
+	ID       int    `json:"id"`
+	FromSlug string `json:"from_slug"`
+	PostID   int    `json:"post_id"`
+	// Automatic is set for redirects recorded by slug changes
+	Automatic bool      `json:"automatic"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RedirectListResponse represents a page of redirects
+type RedirectListResponse struct {
+	Redirects []RedirectResponse `json:"redirects"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+}
+
+// List handles GET /api/v1/admin/redirects
+// @Summary List redirects
+// @Description List the post slug redirects, recorded by slug changes or added by admins, most recent first (admins only)
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of redirects to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of redirects to skip (default: 0)"
+// @Success 200 {object} RedirectListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/redirects [get]
+func (h *RedirectHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, offset := parsePage(c, h.pagination)
+	redirects, err := h.redirectService.ListRedirects(ctx, limit, offset)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	response := RedirectListResponse{
+		Redirects: make([]RedirectResponse, len(redirects)),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for i, rd := range redirects {
+		response.Redirects[i] = toRedirectResponse(rd)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// Create handles POST /api/v1/admin/redirects
+// @Summary Create a redirect
+// @Description Redirect a slug no post uses to a post, e.g. a slug from an earlier site (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body RedirectRequest true "Redirect"
+// @Success 201 {object} RedirectResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "The slug is used by a post or another redirect"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/redirects [post]
+func (h *RedirectHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	req, err := h.bindRedirect(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	rd, err := h.redirectService.CreateRedirect(ctx, adminID, req.FromSlug, req.PostID)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to create redirect", "fromSlug", req.FromSlug, "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toRedirectResponse(rd))
+}
+
+// Update handles PUT /api/v1/admin/redirects/{id}
+// @Summary Update a redirect
+// @Description Change the slug or post of a redirect (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Redirect ID"
+// @Param request body RedirectRequest true "Redirect"
+// @Success 200 {object} RedirectResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The slug is used by a post or another redirect"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/redirects/{id} [put]
+func (h *RedirectHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid redirect ID in path", "redirect_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	req, err := h.bindRedirect(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	rd, err := h.redirectService.UpdateRedirect(ctx, adminID, id, req.FromSlug, req.PostID)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to update redirect", "redirectID", id, "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toRedirectResponse(rd))
+}
+
+// Delete handles DELETE /api/v1/admin/redirects/{id}
+// @Summary Delete a redirect
+// @Description Delete a redirect; its slug stops resolving (admins only)
+// @Tags admin
+// @Param id path int true "Redirect ID"
+// @Success 204 "Redirect deleted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/redirects/{id} [delete]
+func (h *RedirectHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid redirect ID in path", "redirect_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.redirectService.DeleteRedirect(ctx, adminID, id); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// bindRedirect binds and validates a redirect request
+func (h *RedirectHandler) bindRedirect(c echo.Context) (*RedirectRequest, error) {
+	var req RedirectRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(c.Request().Context(), "failed to bind redirect request", "error", err.Error())
+		return nil, errors.ErrInvalidRequest
+	}
+	if err := c.Validate(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// toRedirectResponse converts a redirect to its response format
+func toRedirectResponse(rd *post.Redirect) RedirectResponse {
+	return RedirectResponse{
+		ID:        rd.ID,
+		FromSlug:  rd.FromSlug,
+		PostID:    rd.PostID,
+		Automatic: rd.Automatic,
+		CreatedAt: rd.CreatedAt,
+	}
+}
+
+// RouteDocs returns examples and error codes for the redirect routes
+func (h *RedirectHandler) RouteDocs() []RouteDoc {
+	exampleRequest := RedirectRequest{FromSlug: "my-first-post", PostID: 1}
+	example := RedirectResponse{
+		ID:        4,
+		FromSlug:  "my-first-post",
+		PostID:    1,
+		CreatedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/redirects",
+			Summary:         "List redirects",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: RedirectListResponse{Redirects: []RedirectResponse{example}, Limit: 10, Offset: 0},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/redirects",
+			Summary:         "Create a redirect",
+			RequestExample:  exampleRequest,
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/admin/redirects/{id}",
+			Summary:         "Update a redirect",
+			RequestExample:  exampleRequest,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: example,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/admin/redirects/{id}",
+			Summary:        "Delete a redirect",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
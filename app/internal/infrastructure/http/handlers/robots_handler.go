@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+)
+
+const (
+	// noIndexHeader carries crawl directives for responses that are not HTML
+	noIndexHeader = "X-Robots-Tag"
+	// noIndexValue keeps a page out of search results
+	noIndexValue = "noindex"
+)
+
+// RobotsSettings configures the generated robots.txt
+type RobotsSettings struct {
+	// Disallow lists the path prefixes crawlers may not fetch
+	Disallow []string
+	// HostDisallow replaces Disallow for requests to the given hosts, e.g.
+	// for organizations on their own domain
+	HostDisallow map[string][]string
+	// IndexNowKey is served at /{key}.txt so search engines can verify
+	// IndexNow submissions
+	IndexNowKey string
+}
+
+// RobotsHandler serves robots.txt
+type RobotsHandler struct {
+	settings RobotsSettings
+	logger   service.Logger
+}
+
+// NewRobotsHandler creates a new robots.txt handler
+func NewRobotsHandler(settings RobotsSettings, logger service.Logger) *RobotsHandler {
+	hostDisallow := make(map[string][]string, len(settings.HostDisallow))
+	for host, rules := range settings.HostDisallow {
+		hostDisallow[strings.ToLower(host)] = rules
+	}
+	settings.HostDisallow = hostDisallow
+
+	return &RobotsHandler{
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// ServeRobots handles GET /robots.txt
+func (h *RobotsHandler) ServeRobots(c echo.Context) error {
+	rules := h.rulesFor(c.Request().Host)
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(rules) == 0 {
+		// An empty rule allows everything
+		b.WriteString("Disallow:\n")
+	}
+	for _, rule := range rules {
+		b.WriteString("Disallow: " + rule + "\n")
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=3600")
+	return c.String(http.StatusOK, b.String())
+}
+
+// ServeIndexNowKey handles GET /{key}.txt
+func (h *RobotsHandler) ServeIndexNowKey(c echo.Context) error {
+	return c.String(http.StatusOK, h.settings.IndexNowKey)
+}
+
+// rulesFor selects the disallow rules for a request host, ignoring the port
+func (h *RobotsHandler) rulesFor(host string) []string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	if rules, ok := h.settings.HostDisallow[strings.ToLower(host)]; ok {
+		return rules
+	}
+	return h.settings.Disallow
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// RouteDoc describes the example payloads of a route and the error codes it
+// can return
+type RouteDoc struct {
+	Method          string             `json:"method"`
+	Path            string             `json:"path"`
+	Summary         string             `json:"summary"`
+	RequestExample  any                `json:"request_example,omitempty"`
+	ResponseStatus  int                `json:"response_status"`
+	ResponseExample any                `json:"response_example,omitempty"`
+	Errors          []errors.ErrorCode `json:"errors"`
+}
+
+// RouteRegistry collects route documentation from the handlers
+type RouteRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]RouteDoc
+}
+
+// NewRouteRegistry creates an empty route registry
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{routes: make(map[string]RouteDoc)}
+}
+
+// Register adds route documentation. Paths use OpenAPI syntax, e.g.
+// /api/v1/posts/{id}; registering the same route twice replaces it.
+func (r *RouteRegistry) Register(docs ...RouteDoc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, doc := range docs {
+		doc.Method = strings.ToUpper(doc.Method)
+		doc.Path = OpenAPIPath(doc.Path)
+		r.routes[routeKey(doc.Method, doc.Path)] = doc
+	}
+}
+
+// Lookup returns the documentation for a route. The path may use either
+// OpenAPI ({id}) or Echo (:id) parameter syntax.
+func (r *RouteRegistry) Lookup(method, path string) (RouteDoc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	doc, ok := r.routes[routeKey(strings.ToUpper(method), OpenAPIPath(path))]
+	return doc, ok
+}
+
+// Routes returns all registered routes sorted by path and method
+func (r *RouteRegistry) Routes() []RouteDoc {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	docs := make([]RouteDoc, 0, len(r.routes))
+	for _, doc := range r.routes {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if docs[i].Path != docs[j].Path {
+			return docs[i].Path < docs[j].Path
+		}
+		return docs[i].Method < docs[j].Method
+	})
+	return docs
+}
+
+// ApplyToSpec merges the registered examples and error codes into an
+// OpenAPI 3 JSON document. Operations missing from the document are added
+// so the spec covers every registered route.
+func (r *RouteRegistry) ApplyToSpec(spec []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	if paths == nil {
+		paths = make(map[string]any)
+		doc["paths"] = paths
+	}
+
+	for _, route := range r.Routes() {
+		item, _ := paths[route.Path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[route.Path] = item
+		}
+
+		method := strings.ToLower(route.Method)
+		operation, _ := item[method].(map[string]any)
+		if operation == nil {
+			operation = map[string]any{"summary": route.Summary}
+			item[method] = operation
+		}
+
+		applyRouteDoc(operation, route)
+	}
+
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// applyRouteDoc adds examples and error responses to a single operation
+func applyRouteDoc(operation map[string]any, route RouteDoc) {
+	responses, _ := operation["responses"].(map[string]any)
+	if responses == nil {
+		responses = make(map[string]any)
+		operation["responses"] = responses
+	}
+
+	if route.RequestExample != nil {
+		body, _ := operation["requestBody"].(map[string]any)
+		if body == nil {
+			body = make(map[string]any)
+			operation["requestBody"] = body
+		}
+		setExample(body, route.RequestExample)
+	}
+
+	if route.ResponseStatus != 0 {
+		status := strconv.Itoa(route.ResponseStatus)
+		response, _ := responses[status].(map[string]any)
+		if response == nil {
+			response = map[string]any{"description": http.StatusText(route.ResponseStatus)}
+			responses[status] = response
+		}
+		if route.ResponseExample != nil {
+			setExample(response, route.ResponseExample)
+		}
+	}
+
+	codes := make([]string, 0, len(route.Errors))
+	for _, code := range route.Errors {
+		codes = append(codes, string(code))
+
+		definition, ok := errors.LookupErrorCode(code)
+		if !ok {
+			continue
+		}
+		status := strconv.Itoa(definition.Status)
+		response, _ := responses[status].(map[string]any)
+		if response == nil {
+			response = map[string]any{
+				"description": http.StatusText(definition.Status),
+				"content": map[string]any{
+					echo.MIMEApplicationJSON: map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/handlers.ErrorResponse"},
+					},
+				},
+			}
+			responses[status] = response
+		}
+		existing, _ := response["x-error-codes"].([]any)
+		response["x-error-codes"] = append(existing, string(code))
+	}
+	operation["x-error-codes"] = codes
+}
+
+// setExample sets the JSON example of a request body or response, adding
+// JSON content when the body is documented with other media types only
+func setExample(body map[string]any, example any) {
+	content, _ := body["content"].(map[string]any)
+	if content == nil {
+		content = make(map[string]any)
+		body["content"] = content
+	}
+	media, _ := content[echo.MIMEApplicationJSON].(map[string]any)
+	if media == nil {
+		media = make(map[string]any)
+		content[echo.MIMEApplicationJSON] = media
+	}
+	media["example"] = example
+}
+
+// OpenAPIPath converts Echo path parameters (:id) to OpenAPI syntax ({id})
+func OpenAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// withCommonErrors appends the error codes every route can return
+func withCommonErrors(codes ...errors.ErrorCode) []errors.ErrorCode {
+	return append(codes, errors.ErrCodeRateLimitExceeded, errors.ErrCodeInternal)
+}
+
+// routeKey builds the registry key for a route
+func routeKey(method, path string) string {
+	return method + " " + path
+}
@@ -0,0 +1,558 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/scim"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// SCIM schema URNs (RFC 7643 and RFC 7644)
+const (
+	scimUserSchema          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimServiceConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimResourceTypeSchema  = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// scimContentType is the media type of SCIM requests and responses
+const scimContentType = "application/scim+json"
+
+// scimMaxBodySize bounds the size of SCIM request bodies
+const scimMaxBodySize = 1 << 20
+
+// SCIMSettings configures the SCIM handler
+type SCIMSettings struct {
+	// BaseURL is used to build the locations of resources
+	BaseURL string
+}
+
+// SCIMHandler serves the SCIM 2.0 Users and Groups endpoints identity
+// providers provision accounts with. Requests are authenticated by
+// middleware.SCIMAuth, which sets the tenant they act on.
+type SCIMHandler struct {
+	scimService scim.Service
+	settings    SCIMSettings
+	logger      service.Logger
+}
+
+// NewSCIMHandler creates a new SCIM handler
+func NewSCIMHandler(scimService scim.Service, settings SCIMSettings, logger service.Logger) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+		settings:    settings,
+		logger:      logger,
+	}
+}
+
+// SCIMMeta describes a SCIM resource
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// SCIMMember refers to a member of a group or a group of a user
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMUser represents a provisioned user
+type SCIMUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        scim.Name    `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Emails      []scim.Email `json:"emails"`
+	Active      bool         `json:"active"`
+	Groups      []SCIMMember `json:"groups"`
+	Meta        SCIMMeta     `json:"meta"`
+}
+
+// SCIMUserRequest represents the attributes of a user sent by a client.
+// Users are active unless active is false.
+type SCIMUserRequest struct {
+	ExternalID  string       `json:"externalId"`
+	UserName    string       `json:"userName"`
+	Name        scim.Name    `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Emails      []scim.Email `json:"emails"`
+	Active      *bool        `json:"active"`
+}
+
+// SCIMGroup represents a group
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+	Meta        SCIMMeta     `json:"meta"`
+}
+
+// SCIMGroupRequest represents the attributes of a group sent by a client
+type SCIMGroupRequest struct {
+	ExternalID  string       `json:"externalId"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+}
+
+// SCIMPatchRequest represents a PATCH request
+type SCIMPatchRequest struct {
+	Operations []scim.PatchOperation `json:"Operations"`
+}
+
+// SCIMListResponse represents a page of resources
+type SCIMListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// SCIMError represents a SCIM error response
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// ServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig
+func (h *SCIMHandler) ServiceProviderConfig(c echo.Context) error {
+	supported := func(ok bool) map[string]bool { return map[string]bool{"supported": ok} }
+	return h.respond(c, http.StatusOK, map[string]any{
+		"schemas":        []string{scimServiceConfigSchema},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": 1},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The token issued to the organization",
+			"primary":     true,
+		}},
+	})
+}
+
+// ResourceTypes handles GET /scim/v2/ResourceTypes
+func (h *SCIMHandler) ResourceTypes(c echo.Context) error {
+	resourceType := func(name, endpoint, schema string) map[string]any {
+		return map[string]any{
+			"schemas":  []string{scimResourceTypeSchema},
+			"id":       name,
+			"name":     name,
+			"endpoint": endpoint,
+			"schema":   schema,
+		}
+	}
+	types := []map[string]any{
+		resourceType("User", "/Users", scimUserSchema),
+		resourceType("Group", "/Groups", scimGroupSchema),
+	}
+	return h.respond(c, http.StatusOK, SCIMListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(types),
+		StartIndex:   1,
+		ItemsPerPage: len(types),
+		Resources:    types,
+	})
+}
+
+// ListUsers handles GET /scim/v2/Users
+func (h *SCIMHandler) ListUsers(c echo.Context) error {
+	filter, page, err := h.listParams(c)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	users, total, err := h.scimService.ListUsers(c.Request().Context(), h.tenant(c), filter, page)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	resources := make([]SCIMUser, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, h.toSCIMUser(u))
+	}
+	return h.respond(c, http.StatusOK, h.listResponse(page, total, len(resources), resources))
+}
+
+// CreateUser handles POST /scim/v2/Users
+func (h *SCIMHandler) CreateUser(c echo.Context) error {
+	var req SCIMUserRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	u, err := h.scimService.CreateUser(c.Request().Context(), h.tenant(c), req.attributes())
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	resource := h.toSCIMUser(u)
+	c.Response().Header().Set(echo.HeaderLocation, resource.Meta.Location)
+	return h.respond(c, http.StatusCreated, resource)
+}
+
+// GetUser handles GET /scim/v2/Users/{id}
+func (h *SCIMHandler) GetUser(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrUserNotFound)
+	}
+
+	u, err := h.scimService.GetUser(c.Request().Context(), h.tenant(c), id)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMUser(u))
+}
+
+// ReplaceUser handles PUT /scim/v2/Users/{id}
+func (h *SCIMHandler) ReplaceUser(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrUserNotFound)
+	}
+
+	var req SCIMUserRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	u, err := h.scimService.ReplaceUser(c.Request().Context(), h.tenant(c), id, req.attributes())
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMUser(u))
+}
+
+// PatchUser handles PATCH /scim/v2/Users/{id}
+func (h *SCIMHandler) PatchUser(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrUserNotFound)
+	}
+
+	var req SCIMPatchRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	u, err := h.scimService.PatchUser(c.Request().Context(), h.tenant(c), id, req.Operations)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMUser(u))
+}
+
+// DeleteUser handles DELETE /scim/v2/Users/{id}
+func (h *SCIMHandler) DeleteUser(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrUserNotFound)
+	}
+
+	if err := h.scimService.DeleteUser(c.Request().Context(), h.tenant(c), id); err != nil {
+		return h.fail(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListGroups handles GET /scim/v2/Groups
+func (h *SCIMHandler) ListGroups(c echo.Context) error {
+	filter, page, err := h.listParams(c)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	groups, total, err := h.scimService.ListGroups(c.Request().Context(), h.tenant(c), filter, page)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	resources := make([]SCIMGroup, 0, len(groups))
+	for _, g := range groups {
+		resources = append(resources, h.toSCIMGroup(g))
+	}
+	return h.respond(c, http.StatusOK, h.listResponse(page, total, len(resources), resources))
+}
+
+// CreateGroup handles POST /scim/v2/Groups
+func (h *SCIMHandler) CreateGroup(c echo.Context) error {
+	var req SCIMGroupRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+	members, err := memberIDs(req.Members)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	g, err := h.scimService.CreateGroup(c.Request().Context(), h.tenant(c), req.DisplayName, req.ExternalID, members)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	resource := h.toSCIMGroup(g)
+	c.Response().Header().Set(echo.HeaderLocation, resource.Meta.Location)
+	return h.respond(c, http.StatusCreated, resource)
+}
+
+// GetGroup handles GET /scim/v2/Groups/{id}
+func (h *SCIMHandler) GetGroup(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrGroupNotFound)
+	}
+
+	g, err := h.scimService.GetGroup(c.Request().Context(), h.tenant(c), id)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMGroup(g))
+}
+
+// ReplaceGroup handles PUT /scim/v2/Groups/{id}
+func (h *SCIMHandler) ReplaceGroup(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrGroupNotFound)
+	}
+
+	var req SCIMGroupRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+	members, err := memberIDs(req.Members)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	g, err := h.scimService.ReplaceGroup(c.Request().Context(), h.tenant(c), id, req.DisplayName, req.ExternalID, members)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMGroup(g))
+}
+
+// PatchGroup handles PATCH /scim/v2/Groups/{id}
+func (h *SCIMHandler) PatchGroup(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrGroupNotFound)
+	}
+
+	var req SCIMPatchRequest
+	if err := h.decode(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	g, err := h.scimService.PatchGroup(c.Request().Context(), h.tenant(c), id, req.Operations)
+	if err != nil {
+		return h.fail(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.toSCIMGroup(g))
+}
+
+// DeleteGroup handles DELETE /scim/v2/Groups/{id}
+func (h *SCIMHandler) DeleteGroup(c echo.Context) error {
+	id, ok := h.resourceID(c)
+	if !ok {
+		return h.fail(c, scim.ErrGroupNotFound)
+	}
+
+	if err := h.scimService.DeleteGroup(c.Request().Context(), h.tenant(c), id); err != nil {
+		return h.fail(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// attributes converts a user request to the attributes of a user
+func (r SCIMUserRequest) attributes() scim.UserAttributes {
+	name := r.Name.String()
+	if name == "" {
+		name = r.DisplayName
+	}
+	return scim.UserAttributes{
+		ExternalID: r.ExternalID,
+		UserName:   r.UserName,
+		Name:       name,
+		Email:      scim.PrimaryEmail(r.Emails),
+		Active:     r.Active == nil || *r.Active,
+	}
+}
+
+// errSCIMSyntax is returned for request bodies that are not valid JSON
+var errSCIMSyntax = stderrors.New("the request body is not valid JSON")
+
+// decode reads a JSON request body. Bind is not used as clients send
+// application/scim+json, which Echo does not decode.
+func (h *SCIMHandler) decode(c echo.Context, dst any) error {
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, scimMaxBodySize)
+	if err := json.NewDecoder(body).Decode(dst); err != nil {
+		return errSCIMSyntax
+	}
+	return nil
+}
+
+// listParams reads the filter and page of a list request
+func (h *SCIMHandler) listParams(c echo.Context) (*scim.Filter, scim.Page, error) {
+	page := scim.Page{StartIndex: 1}
+	if startIndex, err := strconv.Atoi(c.QueryParam("startIndex")); err == nil && startIndex > 1 {
+		page.StartIndex = startIndex
+	}
+	if count, err := strconv.Atoi(c.QueryParam("count")); err == nil && count > 0 {
+		page.Count = count
+	}
+
+	if c.QueryParam("filter") == "" {
+		return nil, page, nil
+	}
+	filter, err := scim.ParseFilter(c.QueryParam("filter"))
+	return filter, page, err
+}
+
+// listResponse wraps a page of resources
+func (h *SCIMHandler) listResponse(page scim.Page, total, count int, resources any) SCIMListResponse {
+	return SCIMListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: total,
+		StartIndex:   page.StartIndex,
+		ItemsPerPage: count,
+		Resources:    resources,
+	}
+}
+
+// tenant returns the tenant set by middleware.SCIMAuth
+func (h *SCIMHandler) tenant(c echo.Context) string {
+	tenant, _ := c.Get(middleware.SCIMTenantKey).(string)
+	return tenant
+}
+
+// resourceID parses the ID of the requested resource
+func (h *SCIMHandler) resourceID(c echo.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	return id, err == nil && id > 0
+}
+
+// toSCIMUser converts a provisioned user to its SCIM representation
+func (h *SCIMHandler) toSCIMUser(u *scim.User) SCIMUser {
+	groups := make([]SCIMMember, 0, len(u.Groups))
+	for _, g := range u.Groups {
+		groups = append(groups, SCIMMember{Value: strconv.Itoa(g.ID), Display: g.DisplayName})
+	}
+
+	id := strconv.Itoa(u.ID)
+	return SCIMUser{
+		Schemas:     []string{scimUserSchema},
+		ID:          id,
+		ExternalID:  u.ExternalID,
+		UserName:    u.UserName,
+		Name:        scim.Name{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []scim.Email{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      u.Active,
+		Groups:      groups,
+		Meta: SCIMMeta{
+			ResourceType: "User",
+			Created:      u.Created,
+			LastModified: u.Modified,
+			Location:     h.settings.BaseURL + "/scim/v2/Users/" + id,
+		},
+	}
+}
+
+// toSCIMGroup converts a group to its SCIM representation
+func (h *SCIMHandler) toSCIMGroup(g *scim.Group) SCIMGroup {
+	members := make([]SCIMMember, 0, len(g.Members))
+	for _, member := range g.Members {
+		members = append(members, SCIMMember{Value: strconv.Itoa(member)})
+	}
+
+	id := strconv.Itoa(g.ID)
+	return SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          id,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Members:     members,
+		Meta: SCIMMeta{
+			ResourceType: "Group",
+			Created:      g.CreatedAt,
+			LastModified: g.UpdatedAt,
+			Location:     h.settings.BaseURL + "/scim/v2/Groups/" + id,
+		},
+	}
+}
+
+// memberIDs parses the user IDs of group members
+func memberIDs(members []SCIMMember) ([]int, error) {
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.Atoi(member.Value)
+		if err != nil || id <= 0 {
+			return nil, scim.ErrInvalidValue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// respond writes a SCIM response
+func (h *SCIMHandler) respond(c echo.Context, status int, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, scimContentType, data)
+}
+
+// fail writes the SCIM error response for an error
+func (h *SCIMHandler) fail(c echo.Context, err error) error {
+	status, scimType, detail := http.StatusInternalServerError, "", "Internal server error"
+	switch {
+	case stderrors.Is(err, scim.ErrUserNotFound), stderrors.Is(err, user.ErrUserNotFound):
+		status, detail = http.StatusNotFound, "User not found"
+	case stderrors.Is(err, scim.ErrGroupNotFound):
+		status, detail = http.StatusNotFound, "Group not found"
+	case stderrors.Is(err, scim.ErrUserClaimed), stderrors.Is(err, scim.ErrGroupExists):
+		status, scimType, detail = http.StatusConflict, "uniqueness", err.Error()
+	case stderrors.Is(err, scim.ErrInvalidFilter):
+		status, scimType, detail = http.StatusBadRequest, "invalidFilter", err.Error()
+	case stderrors.Is(err, scim.ErrInvalidPath):
+		status, scimType, detail = http.StatusBadRequest, "invalidPath", err.Error()
+	case stderrors.Is(err, scim.ErrInvalidValue):
+		status, scimType, detail = http.StatusBadRequest, "invalidValue", err.Error()
+	case stderrors.Is(err, errSCIMSyntax):
+		status, scimType, detail = http.StatusBadRequest, "invalidSyntax", err.Error()
+	default:
+		h.logger.Error(c.Request().Context(), "SCIM request failed", "tenant", h.tenant(c), "error", err.Error())
+	}
+
+	return h.respond(c, status, SCIMError{
+		Schemas:  []string{scimErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// SecurityHandler handles the links in security notification emails
+type SecurityHandler struct {
+	securityService user.SecurityService
+	logger          service.Logger
+}
+
+// NewSecurityHandler creates a new security handler
+func NewSecurityHandler(securityService user.SecurityService, logger service.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		securityService: securityService,
+		logger:          logger,
+	}
+}
+
+// ResetPasswordRequest represents the password reset request payload
+type ResetPasswordRequest struct {
+	Password string `json:"password" validate:"required,min=6,strong_password"`
+}
+
+// ReportChange handles GET and POST /api/v1/users/security/report
+// @Summary Report a change you did not make
+// @Description Lock the account using the token from the "this wasn't me" link of a security notification. Sessions are ended and a password reset link is emailed; logins are refused until the password is reset.
+// @Tags users
+// @Produce json
+// @Param token query string true "Report token"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse "Missing, invalid or expired token"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/security/report [get]
+func (h *SecurityHandler) ReportChange(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token := c.QueryParam("token")
+	if token == "" {
+		h.logger.Warn(ctx, "security report without token")
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.securityService.ReportUnauthorizedChange(ctx, token); err != nil {
+		h.logger.Error(ctx, "failed to handle security report", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, MessageResponse{
+		Message: "Your account has been locked. Check your email to choose a new password",
+	})
+}
+
+// ResetPassword handles POST /api/v1/users/password/reset
+// @Summary Reset the password of a locked account
+// @Description Set a new password using the token from the password reset email and unlock the account
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param token query string true "Password reset token"
+// @Param request body ResetPasswordRequest true "New password"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse "Missing or expired token, or invalid password"
+// @Failure 404 {object} ErrorResponse "Unknown token"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/password/reset [post]
+func (h *SecurityHandler) ResetPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token := c.QueryParam("token")
+	if token == "" {
+		h.logger.Warn(ctx, "password reset without token")
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind password reset request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "password reset request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.securityService.ResetPassword(ctx, token, req.Password); err != nil {
+		h.logger.Error(ctx, "failed to reset password", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, MessageResponse{Message: "Your password has been reset. You can now log in"})
+}
+
+// RouteDocs returns examples and error codes for the security routes
+func (h *SecurityHandler) RouteDocs() []RouteDoc {
+	locked := MessageResponse{Message: "Your account has been locked. Check your email to choose a new password"}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/security/report",
+			Summary:         "Report a change you did not make",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: locked,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/security/report",
+			Summary:         "Report a change you did not make",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: locked,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/password/reset",
+			Summary:         "Reset the password of a locked account",
+			RequestExample:  ResetPasswordRequest{Password: "N3w-Password!"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: MessageResponse{Message: "Your password has been reset. You can now log in"},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,453 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// SeriesHandler handles series of posts: their management by authors, and
+// reading them in order
+type SeriesHandler struct {
+	seriesService series.Service
+	logger        service.Logger
+}
+
+// NewSeriesHandler creates a new series handler
+func NewSeriesHandler(seriesService series.Service, logger service.Logger) *SeriesHandler {
+	return &SeriesHandler{
+		seriesService: seriesService,
+		logger:        logger,
+	}
+}
+
+// SeriesRequest represents the create and update series request payload
+type SeriesRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=255,no_html,safe_string" sanitize:"strict"`
+	Description string `json:"description" validate:"max=1000,no_html" sanitize:"basic"`
+}
+
+// SeriesPostsRequest represents the posts of a series, in reading order
+type SeriesPostsRequest struct {
+	// PostIDs are the posts of the series, first to last; posts left out
+	// leave the series
+	PostIDs []int `json:"post_ids" validate:"max=100"`
+}
+
+// SeriesResponse represents a series
+type SeriesResponse struct {
+	ID          int       `json:"id"`
+	AuthorID    int       `json:"author_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SeriesListResponse represents a list of series
+type SeriesListResponse struct {
+	Series []SeriesResponse `json:"series"`
+	Total  int              `json:"total"`
+}
+
+// SeriesLinkResponse points to a post before or after another in a series
+type SeriesLinkResponse struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// SeriesPostResponse represents a post of a series with the posts before
+// and after it
+type SeriesPostResponse struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+	// Position is the place of the post in the series, counted from 1
+	Position           int        `json:"position"`
+	Status             string     `json:"status"`
+	PublishedAt        *time.Time `json:"published_at,omitempty"`
+	ReadingTimeMinutes int        `json:"reading_time_minutes"`
+	Excerpt            string     `json:"excerpt"`
+	// Previous and Next are left out at the start and end of the series
+	Previous *SeriesLinkResponse `json:"previous,omitempty"`
+	Next     *SeriesLinkResponse `json:"next,omitempty"`
+}
+
+// SeriesDetailResponse represents a series with its posts in order
+type SeriesDetailResponse struct {
+	SeriesResponse
+	Posts []SeriesPostResponse `json:"posts"`
+}
+
+// GetSeries handles GET /api/v1/series/{id}
+// @Summary Get a series
+// @Description Get a series with its published posts in reading order, each linking to the posts before and after it. Sent with a token, the author and admins also see drafts and scheduled posts.
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} SeriesDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/series/{id} [get]
+func (h *SeriesHandler) GetSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid series ID in path", "series_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	sr, entries, err := h.seriesService.Get(ctx, userID, role, id)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to get series", "seriesID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toSeriesDetailResponse(sr, entries))
+}
+
+// ListAuthorSeries handles GET /api/v1/users/{id}/series
+// @Summary List the series of an author
+// @Description List the series of an author, newest first
+// @Tags series
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {object} SeriesListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/series [get]
+func (h *SeriesHandler) ListAuthorSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid user ID in path", "user_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	list, err := h.seriesService.ListByAuthor(ctx, authorID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list series", "authorID", authorID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := SeriesListResponse{Series: make([]SeriesResponse, len(list)), Total: len(list)}
+	for i, sr := range list {
+		response.Series[i] = toSeriesResponse(sr)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateSeries handles POST /api/v1/series
+// @Summary Create a series
+// @Description Create an empty series of your posts (authors and admins)
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param request body SeriesRequest true "Series"
+// @Success 201 {object} SeriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/series [post]
+func (h *SeriesHandler) CreateSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	in, err := h.bindSeries(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	sr, err := h.seriesService.Create(ctx, userID, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to create series", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toSeriesResponse(sr))
+}
+
+// UpdateSeries handles PUT /api/v1/series/{id}
+// @Summary Update a series
+// @Description Replace the title and description of a series (author or admin)
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body SeriesRequest true "Series"
+// @Success 200 {object} SeriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, role, id, err := h.modification(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	in, err := h.bindSeries(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	sr, err := h.seriesService.Update(ctx, userID, role, id, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to update series", "seriesID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toSeriesResponse(sr))
+}
+
+// DeleteSeries handles DELETE /api/v1/series/{id}
+// @Summary Delete a series
+// @Description Delete a series; its posts are kept outside any series (author or admin)
+// @Tags series
+// @Param id path int true "Series ID"
+// @Success 204 "Series deleted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, role, id, err := h.modification(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.seriesService.Delete(ctx, userID, role, id); err != nil {
+		h.logger.Warn(ctx, "failed to delete series", "seriesID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetSeriesPosts handles PUT /api/v1/series/{id}/posts
+// @Summary Order the posts of a series
+// @Description Make the posts, in the given order, the posts of a series. Posts must be by the author of the series; posts left out leave the series and posts of another series move over (author or admin).
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body SeriesPostsRequest true "Posts in reading order"
+// @Success 200 {object} SeriesDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/series/{id}/posts [put]
+func (h *SeriesHandler) SetSeriesPosts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, role, id, err := h.modification(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	var req SeriesPostsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind series posts request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	sr, entries, err := h.seriesService.SetPosts(ctx, userID, role, id, req.PostIDs)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to set series posts", "seriesID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toSeriesDetailResponse(sr, entries))
+}
+
+// modification reads the user changing a series and the series ID
+func (h *SeriesHandler) modification(c echo.Context) (int, user.Role, int, error) {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return 0, "", 0, errors.ErrUnauthorized
+	}
+	role, _ := c.Get("user_role").(user.Role)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid series ID in path", "series_id", c.Param("id"))
+		return 0, "", 0, errors.ErrInvalidRequest
+	}
+	return userID, role, id, nil
+}
+
+// bindSeries binds and validates a series request
+func (h *SeriesHandler) bindSeries(c echo.Context) (series.Input, error) {
+	var req SeriesRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(c.Request().Context(), "failed to bind series request", "error", err.Error())
+		return series.Input{}, errors.ErrInvalidRequest
+	}
+	middleware.SanitizeFields(&req)
+	if err := c.Validate(&req); err != nil {
+		return series.Input{}, err
+	}
+	return series.Input{Title: req.Title, Description: req.Description}, nil
+}
+
+// toSeriesResponse converts a series to its response format
+func toSeriesResponse(sr *series.Series) SeriesResponse {
+	return SeriesResponse{
+		ID:          sr.ID,
+		AuthorID:    sr.AuthorID,
+		Title:       sr.Title,
+		Description: sr.Description,
+		CreatedAt:   sr.CreatedAt,
+		UpdatedAt:   sr.UpdatedAt,
+	}
+}
+
+// toSeriesDetailResponse converts a series and its posts to their response
+// format
+func toSeriesDetailResponse(sr *series.Series, entries []series.Entry) SeriesDetailResponse {
+	response := SeriesDetailResponse{
+		SeriesResponse: toSeriesResponse(sr),
+		Posts:          make([]SeriesPostResponse, len(entries)),
+	}
+	for i, entry := range entries {
+		p := entry.Post
+		response.Posts[i] = SeriesPostResponse{
+			ID:                 p.ID,
+			Title:              p.Title,
+			Slug:               p.Slug,
+			Position:           p.SeriesPosition,
+			Status:             string(p.Status),
+			PublishedAt:        p.PublishedAt,
+			ReadingTimeMinutes: p.ReadingTime,
+			Excerpt:            p.Excerpt,
+			Previous:           toSeriesLinkResponse(entry.Previous),
+			Next:               toSeriesLinkResponse(entry.Next),
+		}
+	}
+	return response
+}
+
+// toSeriesLinkResponse converts a neighbouring post to a link; nil stays nil
+func toSeriesLinkResponse(p *post.Post) *SeriesLinkResponse {
+	if p == nil {
+		return nil
+	}
+	return &SeriesLinkResponse{ID: p.ID, Title: p.Title, Slug: p.Slug}
+}
+
+// RouteDocs returns examples and error codes for the series routes
+func (h *SeriesHandler) RouteDocs() []RouteDoc {
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	publishedAt := createdAt.Add(24 * time.Hour)
+	request := SeriesRequest{
+		Title:       "Building a Blog in Go",
+		Description: "From an empty module to a deployed API, one step at a time.",
+	}
+	created := SeriesResponse{
+		ID:          1,
+		AuthorID:    1,
+		Title:       request.Title,
+		Description: request.Description,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	first := SeriesLinkResponse{ID: 10, Title: "Setting Up the Project", Slug: "setting-up-the-project"}
+	second := SeriesLinkResponse{ID: 12, Title: "Designing the Database", Slug: "designing-the-database"}
+	detail := SeriesDetailResponse{
+		SeriesResponse: created,
+		Posts: []SeriesPostResponse{
+			{ID: first.ID, Title: first.Title, Slug: first.Slug, Position: 1, Status: string(post.StatusPublished), PublishedAt: &publishedAt, ReadingTimeMinutes: 6, Excerpt: "Every project starts with a module.", Next: &second},
+			{ID: second.ID, Title: second.Title, Slug: second.Slug, Position: 2, Status: string(post.StatusPublished), PublishedAt: &publishedAt, ReadingTimeMinutes: 9, Excerpt: "Posts, users and comments need a home.", Previous: &first},
+		},
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/series/{id}",
+			Summary:         "Get a series",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: detail,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/{id}/series",
+			Summary:         "List the series of an author",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: SeriesListResponse{Series: []SeriesResponse{created}, Total: 1},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/series",
+			Summary:         "Create a series",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: created,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/series/{id}",
+			Summary:         "Update a series",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: created,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/series/{id}",
+			Summary:        "Delete a series",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/series/{id}/posts",
+			Summary:         "Order the posts of a series",
+			RequestExample:  SeriesPostsRequest{PostIDs: []int{first.ID, second.ID}},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: detail,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/auth/idp"
+	"blog-platform/internal/infrastructure/auth/oauth"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// ssoStateCookie binds a sign-in to the browser that started it
+const ssoStateCookie = "sso_state"
+
+// ssoCookiePath limits the state cookie to the SSO routes
+const ssoCookiePath = "/api/v1/auth/sso"
+
+// SSOSettings configures the single sign-on flow
+type SSOSettings struct {
+	// BaseURL is the public URL used to build the URLs registered with
+	// identity providers
+	BaseURL string
+}
+
+// SSOHandler handles sign-in through the identity providers of
+// organizations and the admin endpoints that configure them
+type SSOHandler struct {
+	authService auth.AuthService
+	ssoService  sso.Service
+	state       *oauth.StateSigner
+	endpoints   idp.Endpoints
+	settings    SSOSettings
+	logger      service.Logger
+}
+
+// NewSSOHandler creates a new SSO handler
+func NewSSOHandler(authService auth.AuthService, ssoService sso.Service, state *oauth.StateSigner, settings SSOSettings, logger service.Logger) *SSOHandler {
+	return &SSOHandler{
+		authService: authService,
+		ssoService:  ssoService,
+		state:       state,
+		endpoints:   idp.Endpoints{BaseURL: settings.BaseURL},
+		settings:    settings,
+		logger:      logger,
+	}
+}
+
+// SSOConnectionRequest represents the connection of an organization.
+// Leaving client_secret empty keeps the stored secret.
+type SSOConnectionRequest struct {
+	Protocol     string            `json:"protocol" validate:"required,oneof=oidc saml"`
+	Domains      []string          `json:"domains" validate:"required,min=1"`
+	Enforcement  string            `json:"enforcement,omitempty"`
+	DefaultRole  string            `json:"default_role,omitempty"`
+	RoleMappings map[string]string `json:"role_mappings,omitempty"`
+	GroupsClaim  string            `json:"groups_claim,omitempty"`
+	Issuer       string            `json:"issuer,omitempty"`
+	ClientID     string            `json:"client_id,omitempty"`
+	ClientSecret string            `json:"client_secret,omitempty"`
+	Metadata     string            `json:"metadata,omitempty"`
+}
+
+// SSOServiceProviderResponse lists the platform URLs to register with the
+// identity provider
+type SSOServiceProviderResponse struct {
+	StartURL    string `json:"start_url"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+	EntityID    string `json:"entity_id,omitempty"`
+	ACSURL      string `json:"acs_url,omitempty"`
+}
+
+// SSOConnectionResponse represents the connection of an organization. The
+// client secret is never returned.
+type SSOConnectionResponse struct {
+	Organization    string                     `json:"organization"`
+	Protocol        string                     `json:"protocol"`
+	Domains         []string                   `json:"domains"`
+	Enforcement     string                     `json:"enforcement"`
+	DefaultRole     string                     `json:"default_role,omitempty"`
+	RoleMappings    map[string]string          `json:"role_mappings,omitempty"`
+	GroupsClaim     string                     `json:"groups_claim,omitempty"`
+	Issuer          string                     `json:"issuer,omitempty"`
+	ClientID        string                     `json:"client_id,omitempty"`
+	ClientSecretSet bool                       `json:"client_secret_set"`
+	Metadata        string                     `json:"metadata,omitempty"`
+	ServiceProvider SSOServiceProviderResponse `json:"service_provider"`
+	CreatedAt       string                     `json:"created_at"`
+	UpdatedAt       string                     `json:"updated_at"`
+}
+
+// SSOConnectionListResponse represents the connections of all organizations
+type SSOConnectionListResponse struct {
+	Connections []SSOConnectionResponse `json:"connections"`
+}
+
+// errSSOState is returned for responses with a missing or forged state
+var errSSOState = errors.NewAPIError(errors.ErrCodeInvalidRequest, "Invalid or expired sign-in state", http.StatusBadRequest)
+
+// errSSOFailed is returned when the identity provider reports an error
+var errSSOFailed = errors.NewAPIError(errors.ErrCodeUnauthorized, "Sign-in with the identity provider failed", http.StatusUnauthorized)
+
+// Start handles GET /api/v1/auth/sso
+// @Summary Start single sign-on
+// @Description Redirect to the identity provider of an organization, chosen by its identifier or by the domain of an email address
+// @Tags Authentication
+// @Param organization query string false "Organization identifier"
+// @Param email query string false "Email address of the member, used when no organization is given"
+// @Success 302 "Redirect to the identity provider"
+// @Failure 400 {object} ErrorResponse "Neither organization nor email given"
+// @Failure 404 {object} ErrorResponse "No connection for the organization or email domain"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/sso [get]
+func (h *SSOHandler) Start(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	organization, email := c.QueryParam("organization"), c.QueryParam("email")
+	if organization == "" && email == "" {
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	conn, err := h.ssoService.Resolve(ctx, organization, email)
+	if err != nil {
+		h.logger.Warn(ctx, "sso sign-in without connection", "organization", organization, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	state, err := h.state.Issue(conn.IdentityProvider())
+	if err != nil {
+		h.logger.Error(ctx, "failed to issue sso state", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInternal)
+	}
+
+	authURL, err := h.ssoService.AuthRequestURL(ctx, conn.Organization, state)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start sso sign-in", "organization", conn.Organization, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.setStateCookie(c, state, 0)
+	h.logger.Info(ctx, "redirecting to identity provider", "organization", conn.Organization)
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback handles GET /api/v1/auth/sso/{organization}/callback
+// @Summary Complete OpenID Connect single sign-on
+// @Description Exchange the authorization code of the organization's OpenID Connect provider for a platform token pair. The member is linked to the account with the same email or registered, and the role mapped from their groups is applied.
+// @Tags Authentication
+// @Produce json
+// @Param organization path string true "Organization identifier"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State issued by the start endpoint"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Failure 400 {object} ErrorResponse "Invalid state"
+// @Failure 401 {object} ErrorResponse "Identity provider sign-in failed or the response could not be verified"
+// @Failure 403 {object} ErrorResponse "Account deactivated"
+// @Failure 404 {object} ErrorResponse "No connection for the organization"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/sso/{organization}/callback [get]
+func (h *SSOHandler) Callback(c echo.Context) error {
+	ctx := c.Request().Context()
+	organization := c.Param("organization")
+
+	state, ok := h.checkState(c, organization, c.QueryParam("state"))
+	if !ok {
+		return errors.HandleError(c, errSSOState)
+	}
+
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		h.logger.Warn(ctx, "identity provider returned an error", "organization", organization, "error", providerErr)
+		return errors.HandleError(c, errSSOFailed)
+	}
+
+	return h.login(c, organization, state, sso.Response{Code: c.QueryParam("code")})
+}
+
+// ACS handles POST /api/v1/auth/sso/{organization}/acs
+// @Summary Complete SAML single sign-on
+// @Description Assertion consumer service for the SAML identity provider of an organization. The signed response is verified and exchanged for a platform token pair.
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param organization path string true "Organization identifier"
+// @Param SAMLResponse formData string true "Base64-encoded SAML response"
+// @Param RelayState formData string true "State issued by the start endpoint"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Failure 400 {object} ErrorResponse "Invalid state"
+// @Failure 401 {object} ErrorResponse "The response could not be verified"
+// @Failure 403 {object} ErrorResponse "Account deactivated"
+// @Failure 404 {object} ErrorResponse "No connection for the organization"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/sso/{organization}/acs [post]
+func (h *SSOHandler) ACS(c echo.Context) error {
+	organization := c.Param("organization")
+
+	state, ok := h.checkState(c, organization, c.FormValue("RelayState"))
+	if !ok {
+		return errors.HandleError(c, errSSOState)
+	}
+
+	return h.login(c, organization, state, sso.Response{SAMLResponse: c.FormValue("SAMLResponse")})
+}
+
+// Metadata handles GET /api/v1/auth/sso/{organization}/metadata
+// @Summary Get SAML service provider metadata
+// @Description Metadata to register the platform with the SAML identity provider of an organization
+// @Tags Authentication
+// @Produce xml
+// @Param organization path string true "Organization identifier"
+// @Success 200 {string} string "SAML metadata"
+// @Failure 404 {object} ErrorResponse "No SAML connection for the organization"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/sso/{organization}/metadata [get]
+func (h *SSOHandler) Metadata(c echo.Context) error {
+	ctx := c.Request().Context()
+	organization := c.Param("organization")
+
+	conn, err := h.ssoService.GetConnection(ctx, organization)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	if conn.Protocol != sso.ProtocolSAML {
+		return errors.HandleError(c, errors.ErrNotFound)
+	}
+
+	metadata := idp.ServiceProviderMetadata(h.endpoints.MetadataURL(organization), h.endpoints.ACSURL(organization))
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, metadata)
+}
+
+// ListConnections handles GET /api/v1/admin/sso
+// @Summary List SSO connections
+// @Description List the single sign-on connections of all organizations
+// @Tags admin
+// @Produce json
+// @Success 200 {object} SSOConnectionListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/sso [get]
+func (h *SSOHandler) ListConnections(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	connections, err := h.ssoService.ListConnections(ctx)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	response := SSOConnectionListResponse{Connections: make([]SSOConnectionResponse, 0, len(connections))}
+	for _, conn := range connections {
+		response.Connections = append(response.Connections, h.toConnectionResponse(conn))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetConnection handles GET /api/v1/admin/sso/{organization}
+// @Summary Get an SSO connection
+// @Description Get the single sign-on connection of an organization and the URLs to register with its identity provider
+// @Tags admin
+// @Produce json
+// @Param organization path string true "Organization identifier"
+// @Success 200 {object} SSOConnectionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/sso/{organization} [get]
+func (h *SSOHandler) GetConnection(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	conn, err := h.ssoService.GetConnection(ctx, c.Param("organization"))
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, h.toConnectionResponse(conn))
+}
+
+// SaveConnection handles PUT /api/v1/admin/sso/{organization}
+// @Summary Configure SSO for an organization
+// @Description Create or replace the single sign-on connection of an organization: OpenID Connect with an issuer that supports discovery, or SAML with the metadata of the identity provider. The connection claims the email domains of the members; enforcement "required" or "required_except_admins" turns off the other sign-in methods for them. Groups of the identity provider are mapped to roles with role_mappings.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param organization path string true "Organization identifier"
+// @Param request body SSOConnectionRequest true "Connection"
+// @Success 200 {object} SSOConnectionResponse
+// @Failure 400 {object} ErrorResponse "Invalid connection or unusable SAML metadata"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "A domain belongs to another organization"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/sso/{organization} [put]
+func (h *SSOHandler) SaveConnection(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req SSOConnectionRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind sso connection request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "sso connection request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	conn := &sso.Connection{
+		Organization: c.Param("organization"),
+		Protocol:     sso.Protocol(req.Protocol),
+		Domains:      req.Domains,
+		Enforcement:  sso.Enforcement(req.Enforcement),
+		DefaultRole:  user.Role(req.DefaultRole),
+		GroupsClaim:  req.GroupsClaim,
+		Issuer:       req.Issuer,
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		Metadata:     req.Metadata,
+	}
+	if len(req.RoleMappings) > 0 {
+		conn.RoleMappings = make(map[string]user.Role, len(req.RoleMappings))
+		for group, role := range req.RoleMappings {
+			conn.RoleMappings[group] = user.Role(role)
+		}
+	}
+
+	saved, err := h.ssoService.SaveConnection(ctx, conn)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to save sso connection", "organization", conn.Organization, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, h.toConnectionResponse(saved))
+}
+
+// DeleteConnection handles DELETE /api/v1/admin/sso/{organization}
+// @Summary Remove SSO for an organization
+// @Description Delete the single sign-on connection of an organization. Members keep their accounts and can sign in with other methods again.
+// @Tags admin
+// @Param organization path string true "Organization identifier"
+// @Success 204 "Connection deleted"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/sso/{organization} [delete]
+func (h *SSOHandler) DeleteConnection(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.ssoService.DeleteConnection(ctx, c.Param("organization")); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// login exchanges the identity provider's response for a token pair
+func (h *SSOHandler) login(c echo.Context, organization, state string, response sso.Response) error {
+	ctx := c.Request().Context()
+
+	u, tokens, err := h.authService.LoginWithSSO(ctx, organization, state, response)
+	if err != nil {
+		if stderrors.Is(err, sso.ErrInvalidResponse) {
+			h.logger.Warn(ctx, "sso response rejected", "organization", organization, "error", err.Error())
+		} else {
+			h.logger.Error(ctx, "sso login failed", "organization", organization, "error", err.Error())
+		}
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged in through sso", "organization", organization, "userID", u.ID)
+	return c.JSON(http.StatusOK, AuthResponse{
+		User: UserResponse{
+			ID:    u.ID,
+			Name:  u.Name,
+			Email: u.Email,
+			Role:  string(u.Role),
+		},
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	})
+}
+
+// checkState verifies that the state was issued to this browser for the
+// organization. The state is single-use, so the cookie is cleared whatever
+// the outcome.
+func (h *SSOHandler) checkState(c echo.Context, organization, state string) (string, bool) {
+	h.setStateCookie(c, "", -1)
+
+	cookie, err := c.Cookie(ssoStateCookie)
+	if state == "" || err != nil || cookie.Value != state || h.state.Verify(state, "sso:"+organization) != nil {
+		h.logger.Warn(c.Request().Context(), "sso response with invalid state", "organization", organization)
+		return "", false
+	}
+	return state, true
+}
+
+// setStateCookie sets or clears the state cookie. SAML responses are posted
+// by the identity provider's page, so over HTTPS the cookie is sent with
+// cross-site requests; browsers only allow that for secure cookies.
+func (h *SSOHandler) setStateCookie(c echo.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(h.settings.BaseURL, "https://")
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    value,
+		Path:     ssoCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+	})
+}
+
+// toConnectionResponse converts a connection, adding the URLs the identity
+// provider needs
+func (h *SSOHandler) toConnectionResponse(conn *sso.Connection) SSOConnectionResponse {
+	response := SSOConnectionResponse{
+		Organization:    conn.Organization,
+		Protocol:        string(conn.Protocol),
+		Domains:         conn.Domains,
+		Enforcement:     string(conn.Enforcement),
+		DefaultRole:     string(conn.DefaultRole),
+		GroupsClaim:     conn.GroupsClaim,
+		Issuer:          conn.Issuer,
+		ClientID:        conn.ClientID,
+		ClientSecretSet: conn.ClientSecret != "",
+		Metadata:        conn.Metadata,
+		ServiceProvider: SSOServiceProviderResponse{StartURL: h.endpoints.StartURL(conn.Organization)},
+		CreatedAt:       conn.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       conn.UpdatedAt.Format(time.RFC3339),
+	}
+	if len(conn.RoleMappings) > 0 {
+		response.RoleMappings = make(map[string]string, len(conn.RoleMappings))
+		for group, role := range conn.RoleMappings {
+			response.RoleMappings[group] = string(role)
+		}
+	}
+
+	switch conn.Protocol {
+	case sso.ProtocolOIDC:
+		response.ServiceProvider.RedirectURI = h.endpoints.CallbackURL(conn.Organization)
+	case sso.ProtocolSAML:
+		response.ServiceProvider.EntityID = h.endpoints.MetadataURL(conn.Organization)
+		response.ServiceProvider.ACSURL = h.endpoints.ACSURL(conn.Organization)
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the SSO routes
+func (h *SSOHandler) RouteDocs() []RouteDoc {
+	exampleUser := UserResponse{ID: 1, Name: "John Doe", Email: "john@acme.example", Role: "author"}
+	exampleAuth := AuthResponse{User: exampleUser, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900}
+	exampleConnection := SSOConnectionResponse{
+		Organization:    "acme",
+		Protocol:        "oidc",
+		Domains:         []string{"acme.example"},
+		Enforcement:     "required_except_admins",
+		RoleMappings:    map[string]string{"blog-admins": "admin", "blog-readers": "reader"},
+		GroupsClaim:     "groups",
+		Issuer:          "https://login.acme.example",
+		ClientID:        "blog-platform",
+		ClientSecretSet: true,
+		ServiceProvider: SSOServiceProviderResponse{
+			StartURL:    "https://blog.example.com/api/v1/auth/sso?organization=acme",
+			RedirectURI: "https://blog.example.com/api/v1/auth/sso/acme/callback",
+		},
+		CreatedAt: "2024-01-01T00:00:00Z",
+		UpdatedAt: "2024-01-01T00:00:00Z",
+	}
+	signInErrors := withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodeNotFound, errors.ErrCodePasswordResetRequired, errors.ErrCodeAccountDeactivated)
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/auth/sso",
+			Summary:        "Start single sign-on",
+			ResponseStatus: http.StatusFound,
+			Errors:         withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/auth/sso/{organization}/callback",
+			Summary:         "Complete OpenID Connect single sign-on",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAuth,
+			Errors:          signInErrors,
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/sso/{organization}/acs",
+			Summary:         "Complete SAML single sign-on",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAuth,
+			Errors:          signInErrors,
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/auth/sso/{organization}/metadata",
+			Summary:        "Get SAML service provider metadata",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/sso",
+			Summary:         "List SSO connections",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: SSOConnectionListResponse{Connections: []SSOConnectionResponse{exampleConnection}},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/sso/{organization}",
+			Summary:         "Get an SSO connection",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleConnection,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeNotFound),
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/api/v1/admin/sso/{organization}",
+			Summary: "Configure SSO for an organization",
+			RequestExample: SSOConnectionRequest{
+				Protocol:     "oidc",
+				Domains:      []string{"acme.example"},
+				Enforcement:  "required_except_admins",
+				RoleMappings: map[string]string{"blog-admins": "admin", "blog-readers": "reader"},
+				GroupsClaim:  "groups",
+				Issuer:       "https://login.acme.example",
+				ClientID:     "blog-platform",
+				ClientSecret: "s3cr3t",
+			},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleConnection,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeConflict),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/admin/sso/{organization}",
+			Summary:        "Remove SSO for an organization",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// SyncHandler handles the differential sync of offline-first clients
+type SyncHandler struct {
+	syncService change.Service
+	postService post.Service
+	pagination  service.PageLimits
+	logger      service.Logger
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(syncService change.Service, postService post.Service, pagination service.PageLimits, logger service.Logger) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+		postService: postService,
+		pagination:  pagination,
+		logger:      logger,
+	}
+}
+
+// SyncResponse represents the changes since a checkpoint
+type SyncResponse struct {
+	// Posts holds the published posts created or updated, to replace the
+	// copies the client holds
+	Posts []PostResponse `json:"posts"`
+	// Comments holds the approved comments created or updated
+	Comments []CommentResponse `json:"comments"`
+	Deleted  SyncDeletions     `json:"deleted"`
+	// Checkpoint is passed as since on the next sync
+	Checkpoint string `json:"checkpoint"`
+	// HasMore is set when changes remain; sync again right away
+	HasMore bool `json:"has_more"`
+}
+
+// SyncDeletions lists the IDs of posts and comments to drop
+type SyncDeletions struct {
+	Posts    []int `json:"posts"`
+	Comments []int `json:"comments"`
+}
+
+// Sync handles GET /api/v1/sync
+// @Summary Sync changes since a checkpoint
+// @Description Return the published posts and approved comments created or updated since a checkpoint, and the IDs of those deleted, for offline-first clients. Start without since, or with an RFC 3339 time, then pass the returned checkpoint; repeat while has_more is set.
+// @Tags sync
+// @Produce json
+// @Param since query string false "Checkpoint from the previous sync, or an RFC 3339 time"
+// @Param limit query int false "Number of changes of each kind to return (default: 10, max: 100, configurable)"
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} ErrorResponse "Invalid checkpoint"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync [get]
+func (h *SyncHandler) Sync(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	since := change.Start()
+	if raw := c.QueryParam("since"); raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			since = change.Since(t)
+		} else if since, err = change.DecodeCheckpoint(raw); err != nil {
+			h.logger.Warn(ctx, "invalid sync checkpoint", "since", raw)
+			return errors.HandleError(c, errors.ErrInvalidRequest)
+		}
+	}
+	limit, _ := parsePage(c, h.pagination)
+
+	set, err := h.syncService.Changes(ctx, since, limit)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	// Restricted posts are gated for the reader, set when the request
+	// carries a valid token
+	userID, _ := c.Get("user_id").(int)
+	role, _ := c.Get("user_role").(user.Role)
+	posts := h.postService.GatePosts(ctx, userID, role, set.Posts)
+	setReaderCaching(c, posts...)
+
+	response := SyncResponse{
+		Posts:      make([]PostResponse, len(posts)),
+		Comments:   make([]CommentResponse, len(set.Comments)),
+		Deleted:    SyncDeletions{Posts: []int{}, Comments: []int{}},
+		Checkpoint: set.Checkpoint.Encode(),
+		HasMore:    set.HasMore,
+	}
+	for i, p := range posts {
+		response.Posts[i] = toPostResponse(p)
+	}
+	for i, cm := range set.Comments {
+		response.Comments[i] = toCommentResponse(cm)
+	}
+	response.Deleted.Posts = append(response.Deleted.Posts, set.DeletedPosts...)
+	response.Deleted.Comments = append(response.Deleted.Comments, set.DeletedComments...)
+	return c.JSON(http.StatusOK, response)
+}
+
+// RouteDocs returns examples and error codes for the sync route
+func (h *SyncHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/sync",
+			Summary:        "Sync changes since a checkpoint",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: SyncResponse{
+				Posts: []PostResponse{{
+					ID:        1,
+					Title:     "My First Post",
+					Slug:      "my-first-post",
+					Content:   "This is the content of my first post.",
+					AuthorID:  1,
+					Access:    "public",
+					Status:    "published",
+					Tags:      []string{"go"},
+					CreatedAt: "2024-01-15T10:30:00Z",
+					UpdatedAt: "2024-01-16T08:00:00Z",
+				}},
+				Comments: []CommentResponse{{
+					ID:         7,
+					PostID:     1,
+					AuthorName: "Jane Smith",
+					Content:    "Great post!",
+					Status:     "approved",
+					CreatedAt:  "2024-01-16T09:00:00Z",
+				}},
+				Deleted: SyncDeletions{Posts: []int{3}, Comments: []int{5}},
+				Checkpoint: change.Checkpoint{
+					Posts:     change.Position{At: time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC), ID: 1},
+					Comments:  change.Position{At: time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), ID: 7},
+					Deletions: change.Position{At: time.Date(2024, 1, 16, 9, 30, 0, 0, time.UTC), ID: 2},
+				}.Encode(),
+			},
+			Errors: withCommonErrors(errors.ErrCodeInvalidRequest),
+		},
+	}
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// TagHandler handles tag-related HTTP requests
+type TagHandler struct {
+	tagService tag.Service
+	logger     service.Logger
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService tag.Service, logger service.Logger) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// TagResponse represents a tag with the number of published posts carrying it
+type TagResponse struct {
+	Name      string `json:"name"`
+	PostCount int    `json:"post_count"`
+}
+
+// TagListResponse represents the tag list response
+type TagListResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// RenameTagRequest represents the request payload for renaming a tag
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// MergeTagsRequest represents the request payload for merging duplicate
+// tags into one
+type MergeTagsRequest struct {
+	// Sources are the duplicate tags, deleted once their posts are moved
+	Sources []string `json:"sources" validate:"required,min=1,max=50,dive,required,max=50"`
+	Target  string   `json:"target" validate:"required,max=50"`
+}
+
+// TagChangeResponse describes a tag change, applied or previewed with
+// dry_run
+type TagChangeResponse struct {
+	// Tags are the tags renamed, merged away or deleted, with their post
+	// counts before the change
+	Tags []TagResponse `json:"tags"`
+	// Target is the renamed tag, or the tag the others were merged into
+	Target        *TagResponse `json:"target,omitempty"`
+	AffectedPosts int          `json:"affected_posts"`
+	DryRun        bool         `json:"dry_run"`
+}
+
+// ListTags handles GET /api/v1/tags
+// @Summary List tags
+// @Description List the tags of published posts with their post counts, most used first. Filter posts by a tag with GET /api/v1/posts?tag={name}.
+// @Tags posts
+// @Produce json
+// @Success 200 {object} TagListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tags [get]
+func (h *TagHandler) ListTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tags, err := h.tagService.ListTags(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list tags", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := TagListResponse{Tags: make([]TagResponse, 0, len(tags))}
+	for _, t := range tags {
+		response.Tags = append(response.Tags, TagResponse{Name: t.Name, PostCount: t.PostCount})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListAllTags handles GET /api/v1/admin/tags
+// @Summary List all tags
+// @Description List every tag, including unused ones, with the number of posts of any status carrying it, most used first (admins only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} TagListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/tags [get]
+func (h *TagHandler) ListAllTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tags, err := h.tagService.ListAllTags(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list all tags", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := TagListResponse{Tags: make([]TagResponse, 0, len(tags))}
+	for _, t := range tags {
+		response.Tags = append(response.Tags, TagResponse{Name: t.Name, PostCount: t.PostCount})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RenameTag handles PUT /api/v1/admin/tags/{name}
+// @Summary Rename a tag
+// @Description Rename a tag on every post carrying it; the name is normalized like post tags. Renaming to an existing tag is refused; merge the tags instead. With dry_run=true the change is only previewed (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param dry_run query bool false "Preview the change without applying it"
+// @Param request body RenameTagRequest true "New name"
+// @Success 200 {object} TagChangeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/tags/{name} [put]
+func (h *TagHandler) RenameTag(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	var req RenameTagRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind rename tag request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	change, err := h.tagService.RenameTag(ctx, adminID, c.Param("name"), req.Name, dryRun)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to rename tag", "tag", c.Param("name"), "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTagChangeResponse(change))
+}
+
+// MergeTags handles POST /api/v1/admin/tags/merge
+// @Summary Merge tags
+// @Description Move the posts of duplicate tags to the target tag and delete the duplicates in a single transaction. With dry_run=true the change is only previewed (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Preview the change without applying it"
+// @Param request body MergeTagsRequest true "Tags to merge"
+// @Success 200 {object} TagChangeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/tags/merge [post]
+func (h *TagHandler) MergeTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	var req MergeTagsRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind merge tags request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	change, err := h.tagService.MergeTags(ctx, adminID, req.Sources, req.Target, dryRun)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to merge tags", "target", req.Target, "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTagChangeResponse(change))
+}
+
+// DeleteUnusedTags handles DELETE /api/v1/admin/tags/unused
+// @Summary Delete unused tags
+// @Description Delete the tags no post carries, drafts and scheduled posts included. With dry_run=true the tags are only listed (admins only)
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Preview the change without applying it"
+// @Success 200 {object} TagChangeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/tags/unused [delete]
+func (h *TagHandler) DeleteUnusedTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	change, err := h.tagService.DeleteUnusedTags(ctx, adminID, dryRun)
+	if err != nil {
+		h.logger.Error(ctx, "failed to delete unused tags", "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTagChangeResponse(change))
+}
+
+// parseDryRun reads the dry_run query parameter of a tag change
+func parseDryRun(c echo.Context) (bool, error) {
+	value := c.QueryParam("dry_run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.ErrInvalidRequest
+	}
+	return dryRun, nil
+}
+
+// toTagChangeResponse converts a tag change to its response format
+func toTagChangeResponse(change *tag.Change) TagChangeResponse {
+	response := TagChangeResponse{
+		Tags:          make([]TagResponse, 0, len(change.Tags)),
+		AffectedPosts: change.AffectedPosts,
+		DryRun:        change.DryRun,
+	}
+	for _, t := range change.Tags {
+		response.Tags = append(response.Tags, TagResponse{Name: t.Name, PostCount: t.PostCount})
+	}
+	if change.Target != nil {
+		response.Target = &TagResponse{Name: change.Target.Name, PostCount: change.Target.PostCount}
+	}
+	return response
+}
+
+// RouteDocs returns examples and error codes for the tag routes
+func (h *TagHandler) RouteDocs() []RouteDoc {
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/tags",
+			Summary:        "List tags",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: TagListResponse{Tags: []TagResponse{
+				{Name: "golang", PostCount: 12},
+				{Name: "web-development", PostCount: 4},
+			}},
+			Errors: withCommonErrors(),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/admin/tags",
+			Summary:        "List all tags",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: TagListResponse{Tags: []TagResponse{
+				{Name: "golang", PostCount: 14},
+				{Name: "go-lang", PostCount: 2},
+				{Name: "draft-ideas", PostCount: 0},
+			}},
+			Errors: withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:         http.MethodPut,
+			Path:           "/api/v1/admin/tags/{name}",
+			Summary:        "Rename a tag",
+			RequestExample: RenameTagRequest{Name: "Go"},
+			ResponseStatus: http.StatusOK,
+			ResponseExample: TagChangeResponse{
+				Tags:          []TagResponse{{Name: "golang", PostCount: 14}},
+				Target:        &TagResponse{Name: "go", PostCount: 14},
+				AffectedPosts: 14,
+			},
+			Errors: withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/admin/tags/merge",
+			Summary:        "Merge tags",
+			RequestExample: MergeTagsRequest{Sources: []string{"go-lang"}, Target: "golang"},
+			ResponseStatus: http.StatusOK,
+			ResponseExample: TagChangeResponse{
+				Tags:          []TagResponse{{Name: "go-lang", PostCount: 2}},
+				Target:        &TagResponse{Name: "golang", PostCount: 14},
+				AffectedPosts: 2,
+				DryRun:        true,
+			},
+			Errors: withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            "/api/v1/admin/tags/unused",
+			Summary:         "Delete unused tags",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: TagChangeResponse{Tags: []TagResponse{{Name: "draft-ideas", PostCount: 0}}},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+	}
+}
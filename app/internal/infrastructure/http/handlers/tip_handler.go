@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/tip"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// paymentWebhookMaxBodySize bounds the size of payment webhook payloads
+const paymentWebhookMaxBodySize = 1 << 16
+
+// TipHandler handles tips: starting the checkout of a tip on a post, the
+// payout account of the signed-in author and the payment webhook settling
+// tips
+type TipHandler struct {
+	tipService tip.Service
+	payments   tip.PaymentProvider
+	logger     service.Logger
+}
+
+// NewTipHandler creates a new tip handler
+func NewTipHandler(tipService tip.Service, payments tip.PaymentProvider, logger service.Logger) *TipHandler {
+	return &TipHandler{
+		tipService: tipService,
+		payments:   payments,
+		logger:     logger,
+	}
+}
+
+// CreateTipRequest represents the tip request payload
+type CreateTipRequest struct {
+	// Amount is in the smallest unit of the currency, e.g. cents
+	Amount int64 `json:"amount" validate:"required,gt=0"`
+}
+
+// TipResponse represents a started tip and the checkout to pay it on
+type TipResponse struct {
+	ID       int    `json:"id"`
+	PostID   int    `json:"post_id"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"`
+	// CheckoutURL is the payment page the reader is sent to
+	CheckoutURL string `json:"checkout_url"`
+}
+
+// LinkPayoutAccountRequest represents the payout account request payload
+type LinkPayoutAccountRequest struct {
+	// AccountID is the account at the payment provider, e.g. a Stripe
+	// Connect account ID
+	AccountID string `json:"account_id" validate:"required,max=255"`
+}
+
+// PayoutAccountResponse represents the payout account of the signed-in author
+type PayoutAccountResponse struct {
+	Provider  string    `json:"provider"`
+	AccountID string    `json:"account_id"`
+	LinkedAt  time.Time `json:"linked_at"`
+}
+
+// CreateTip handles POST /api/v1/posts/{id}/tips
+// @Summary Tip the author of a post
+// @Description Start a one-time payment to the author of a published post. The tip is pending until the payment on the returned checkout page succeeds; sent with a token, it is linked to your account. Authors must have linked a payout account.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param tip body CreateTipRequest true "Tip amount"
+// @Success 201 {object} TipResponse "Tip started"
+// @Failure 400 {object} ErrorResponse "Invalid amount, or the author does not accept tips"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/posts/{id}/tips [post]
+func (h *TipHandler) CreateTip(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid post ID in path", "post_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	var req CreateTipRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind tip request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	// Guests tip without a token
+	userID, _ := c.Get("user_id").(int)
+	t, checkout, err := h.tipService.StartTip(ctx, postID, userID, req.Amount)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to start tip", "postID", postID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, TipResponse{
+		ID:          t.ID,
+		PostID:      t.PostID,
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Status:      string(t.Status),
+		CheckoutURL: checkout.URL,
+	})
+}
+
+// GetPayoutAccount handles GET /api/v1/users/me/payout-account
+// @Summary Get the payout account
+// @Description Get the account tips to the authenticated author are paid out to
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PayoutAccountResponse "Payout account"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "No payout account linked"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/payout-account [get]
+func (h *TipHandler) GetPayoutAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	account, err := h.tipService.GetPayoutAccount(ctx, userID)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toPayoutAccountResponse(account))
+}
+
+// LinkPayoutAccount handles PUT /api/v1/users/me/payout-account
+// @Summary Link a payout account
+// @Description Link the account at the payment provider tips are paid out to, replacing any linked before. The provider must confirm the account can accept payments; for Stripe, this is a Connect account that completed onboarding.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param account body LinkPayoutAccountRequest true "Payout account"
+// @Success 200 {object} PayoutAccountResponse "Payout account linked"
+// @Failure 400 {object} ErrorResponse "Invalid payout account"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/payout-account [put]
+func (h *TipHandler) LinkPayoutAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req LinkPayoutAccountRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(ctx, "failed to bind payout account request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	account, err := h.tipService.LinkPayoutAccount(ctx, userID, req.AccountID)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to link payout account", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toPayoutAccountResponse(account))
+}
+
+// UnlinkPayoutAccount handles DELETE /api/v1/users/me/payout-account
+// @Summary Unlink the payout account
+// @Description Remove the payout account of the authenticated author, who then no longer accepts tips. Pending tips are still paid out.
+// @Tags users
+// @Security BearerAuth
+// @Success 204 "Payout account unlinked"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "No payout account linked"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/payout-account [delete]
+func (h *TipHandler) UnlinkPayoutAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	if err := h.tipService.UnlinkPayoutAccount(ctx, userID); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// StripeWebhook handles POST /api/v1/payments/stripe/webhook
+// @Summary Receive Stripe payment events
+// @Description Webhook endpoint for the checkout session events of tips. Paid checkouts settle their tip as succeeded and count it on the post; failed and expired ones as failed. Other events are acknowledged and ignored. Requests must carry a valid Stripe-Signature header.
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param Stripe-Signature header string true "Stripe webhook signature"
+// @Success 200 {object} WebhookResponse "Event received"
+// @Failure 400 {object} ErrorResponse "Invalid signature or event"
+// @Failure 404 {object} ErrorResponse "Tip not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/payments/stripe/webhook [post]
+func (h *TipHandler) StripeWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// The signature covers the exact payload, so it is read before decoding
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, paymentWebhookMaxBodySize))
+	if err != nil {
+		h.logger.Warn(ctx, "failed to read webhook payload", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	event, err := h.payments.ParseEvent(payload, c.Request().Header.Get("Stripe-Signature"))
+	if err != nil {
+		h.logger.Warn(ctx, "rejected payment event", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	if event == nil {
+		return c.JSON(http.StatusOK, WebhookResponse{Received: true})
+	}
+
+	if err := h.tipService.ApplyEvent(ctx, event); err != nil {
+		h.logger.Error(ctx, "failed to apply payment event", "eventID", event.EventID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, WebhookResponse{Received: true})
+}
+
+// toPayoutAccountResponse converts a payout account into its API representation
+func toPayoutAccountResponse(a *tip.PayoutAccount) PayoutAccountResponse {
+	return PayoutAccountResponse{
+		Provider:  a.Provider,
+		AccountID: a.AccountID,
+		LinkedAt:  a.UpdatedAt,
+	}
+}
+
+// RouteDocs returns examples and error codes for the tip routes
+func (h *TipHandler) RouteDocs() []RouteDoc {
+	exampleAccount := PayoutAccountResponse{
+		Provider:  "stripe",
+		AccountID: "acct_1OvXyZ2eZvKYlo2C",
+		LinkedAt:  time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/posts/{id}/tips",
+			Summary:        "Tip the author of a post",
+			RequestExample: CreateTipRequest{Amount: 500},
+			ResponseStatus: http.StatusCreated,
+			ResponseExample: TipResponse{
+				ID:          42,
+				PostID:      1,
+				Amount:      500,
+				Currency:    "usd",
+				Status:      string(tip.StatusPending),
+				CheckoutURL: "https://checkout.stripe.com/c/pay/cs_test_a1b2c3",
+			},
+			Errors: withCommonErrors(errors.ErrCodeValidation, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/me/payout-account",
+			Summary:         "Get the payout account",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAccount,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/users/me/payout-account",
+			Summary:         "Link a payout account",
+			RequestExample:  LinkPayoutAccountRequest{AccountID: exampleAccount.AccountID},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleAccount,
+			Errors:          withCommonErrors(errors.ErrCodeValidation, errors.ErrCodeInvalidRequest, errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/users/me/payout-account",
+			Summary:        "Unlink the payout account",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/payments/stripe/webhook",
+			Summary:         "Receive Stripe payment events",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: WebhookResponse{Received: true},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+	}
+}
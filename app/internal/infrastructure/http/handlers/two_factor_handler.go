@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// errTwoFactorChallenge is returned for challenge tokens that are missing,
+// forged or expired
+var errTwoFactorChallenge = errors.NewAPIError(errors.ErrCodeUnauthorized, "Invalid or expired two-factor challenge", http.StatusUnauthorized)
+
+// errTwoFactorCode is returned for a wrong code at login
+var errTwoFactorCode = errors.NewAPIError(errors.ErrCodeInvalidCredentials, "Invalid two-factor code", http.StatusUnauthorized)
+
+// TwoFactorHandler handles TOTP enrollment and the second login step
+type TwoFactorHandler struct {
+	authService auth.AuthService
+	logger      service.Logger
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(authService auth.AuthService, logger service.Logger) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// TwoFactorCodeRequest represents a request carrying a code from the
+// authenticator app
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorLoginRequest represents the second login step payload
+type TwoFactorLoginRequest struct {
+	Token string `json:"token" validate:"required"`
+	Code  string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorEnrollmentResponse represents a started enrollment
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth:// URI to render as a QR code
+	URI string `json:"uri"`
+}
+
+// TwoFactorChallengeResponse is returned by logins that need a code
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorToken    string `json:"two_factor_token"`
+	ExpiresIn         int    `json:"expires_in"` // challenge lifetime in seconds
+	// Methods lists the second factors the challenge can be answered with:
+	// "totp" at /auth/login/2fa and "passkey" at /auth/webauthn/2fa/*
+	Methods []string `json:"methods,omitempty"`
+}
+
+// Enable handles POST /api/v1/users/me/2fa/enable
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret for the authenticated user. Two-factor authentication is enabled once a code is confirmed.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TwoFactorEnrollmentResponse "Secret and otpauth URI for the authenticator app"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication already enabled"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	enrollment, err := h.authService.EnableTwoFactor(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to start two-factor enrollment", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "two-factor enrollment started", "userID", userID)
+	return c.JSON(http.StatusOK, TwoFactorEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	})
+}
+
+// Confirm handles POST /api/v1/users/me/2fa/confirm
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication with a code from the authenticator app
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body TwoFactorCodeRequest true "Current code"
+// @Success 200 {object} MessageResponse "Two-factor authentication enabled"
+// @Failure 400 {object} ErrorResponse "Invalid code or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "No enrollment started"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication already enabled"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, req, err := h.bindCode(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.authService.ConfirmTwoFactor(ctx, userID, req.Code); err != nil {
+		h.logger.Error(ctx, "failed to confirm two-factor enrollment", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "two-factor authentication enabled", "userID", userID)
+	return c.JSON(http.StatusOK, MessageResponse{Message: "Two-factor authentication enabled"})
+}
+
+// Disable handles POST /api/v1/users/me/2fa/disable
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication; a current code is required
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body TwoFactorCodeRequest true "Current code"
+// @Success 200 {object} MessageResponse "Two-factor authentication disabled"
+// @Failure 400 {object} ErrorResponse "Invalid code, not enabled or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/users/me/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, req, err := h.bindCode(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.authService.DisableTwoFactor(ctx, userID, req.Code); err != nil {
+		h.logger.Error(ctx, "failed to disable two-factor authentication", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "two-factor authentication disabled", "userID", userID)
+	return c.JSON(http.StatusOK, MessageResponse{Message: "Two-factor authentication disabled"})
+}
+
+// Login handles POST /api/v1/auth/login/2fa
+// @Summary Complete a two-factor login
+// @Description Exchange the two-factor token from /auth/login and a code from the authenticator app for a JWT
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param challenge body TwoFactorLoginRequest true "Two-factor token and code"
+// @Success 200 {object} AuthResponse "User successfully authenticated"
+// @Failure 400 {object} ErrorResponse "Invalid request data or validation error"
+// @Failure 401 {object} ErrorResponse "Invalid code or expired challenge"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/login/2fa [post]
+func (h *TwoFactorHandler) Login(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req TwoFactorLoginRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind two-factor login request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	req.Code = strings.TrimSpace(req.Code)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "two-factor login request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	u, tokens, err := h.authService.LoginTwoFactor(ctx, req.Token, req.Code)
+	if err != nil {
+		h.logger.Error(ctx, "two-factor login failed", "error", err.Error())
+		switch {
+		case stderrors.Is(err, auth.ErrInvalidTwoFactorCode):
+			return errors.HandleError(c, errTwoFactorCode)
+		case stderrors.Is(err, auth.ErrInvalidToken), stderrors.Is(err, auth.ErrTokenExpired), stderrors.Is(err, auth.ErrEmptyToken):
+			return errors.HandleError(c, errTwoFactorChallenge)
+		}
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "user logged in with two-factor authentication", "userID", u.ID)
+	return c.JSON(http.StatusOK, AuthResponse{
+		User: UserResponse{
+			ID:    u.ID,
+			Name:  u.Name,
+			Email: u.Email,
+			Role:  string(u.Role),
+		},
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	})
+}
+
+// bindCode reads the authenticated user and the code of a request. The
+// returned error is meant for errors.HandleError.
+func (h *TwoFactorHandler) bindCode(c echo.Context) (int, *TwoFactorCodeRequest, error) {
+	ctx := c.Request().Context()
+
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return 0, nil, errors.ErrUnauthorized
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind two-factor code request", "error", err.Error())
+		return 0, nil, errors.ErrInvalidRequest
+	}
+	req.Code = strings.TrimSpace(req.Code)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "two-factor code request validation failed", "error", err.Error())
+		return 0, nil, err
+	}
+
+	return userID, &req, nil
+}
+
+// respondTwoFactorChallenge answers a login with a pending two-factor step
+// if err is a challenge. It reports whether a response was written.
+func respondTwoFactorChallenge(c echo.Context, err error) (bool, error) {
+	var challenge *auth.TwoFactorChallenge
+	if !stderrors.As(err, &challenge) {
+		return false, nil
+	}
+
+	return true, c.JSON(http.StatusAccepted, TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		TwoFactorToken:    challenge.Token,
+		ExpiresIn:         int(challenge.ExpiresIn.Seconds()),
+		Methods:           challenge.Methods,
+	})
+}
+
+// RouteDocs returns examples and error codes for the two-factor routes
+func (h *TwoFactorHandler) RouteDocs() []RouteDoc {
+	codeRequest := TwoFactorCodeRequest{Code: "123456"}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/me/2fa/enable",
+			Summary:         "Start two-factor enrollment",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: TwoFactorEnrollmentResponse{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/Blog%20Platform:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Blog+Platform"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/me/2fa/confirm",
+			Summary:         "Confirm two-factor enrollment",
+			RequestExample:  codeRequest,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: MessageResponse{Message: "Two-factor authentication enabled"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/me/2fa/disable",
+			Summary:         "Disable two-factor authentication",
+			RequestExample:  codeRequest,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: MessageResponse{Message: "Two-factor authentication disabled"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/auth/login/2fa",
+			Summary:         "Complete a two-factor login",
+			RequestExample:  TwoFactorLoginRequest{Token: "eyJhbGciOiJIUzI1NiIs...", Code: "123456"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: AuthResponse{User: UserResponse{ID: 1, Name: "John Doe", Email: "john@example.com", Role: "author"}, Token: "eyJhbGciOiJIUzI1NiIs...", RefreshToken: "3f9c2d...", ExpiresIn: 900},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeUnauthorized, errors.ErrCodeInvalidCredentials),
+		},
+	}
+}
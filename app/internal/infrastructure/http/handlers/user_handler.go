@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// UserHandler handles user account HTTP requests
+type UserHandler struct {
+	userService user.Service
+	logger      service.Logger
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService user.Service, logger service.Logger) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// EmailChangeRequest represents the email change request payload
+type EmailChangeRequest struct {
+	Email string `json:"email" validate:"required,email" sanitize:"strict"`
+}
+
+// UpdateAccountRequest represents the account update request payload
+type UpdateAccountRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255,no_html,safe_string" sanitize:"strict"`
+	// Email is confirmed via an emailed link before it replaces the current one
+	Email string `json:"email" validate:"required,email" sanitize:"strict"`
+}
+
+// ChangePasswordRequest represents the password change request payload
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6,strong_password"`
+}
+
+// MessageResponse represents a simple acknowledgement response
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// DeletionResponse represents a scheduled account deletion
+type DeletionResponse struct {
+	Message string `json:"message"`
+	PurgeAt string `json:"purge_at"`
+}
+
+// RequestEmailChange handles POST /api/v1/users/me/email
+// @Summary Request an email change
+// @Description Send a confirmation link to the new address. The current address stays active until the change is confirmed.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body EmailChangeRequest true "New email address"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Email already in use"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/email [post]
+func (h *UserHandler) RequestEmailChange(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req EmailChangeRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind email change request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Sanitize input
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "email change request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.userService.RequestEmailChange(ctx, userID, req.Email); err != nil {
+		h.logger.Error(ctx, "failed to request email change", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "email change requested", "userID", userID)
+	return c.JSON(http.StatusAccepted, MessageResponse{
+		Message: "A confirmation link has been sent to the new email address",
+	})
+}
+
+// ConfirmEmailChange handles GET /api/v1/users/email/confirm
+// @Summary Confirm an email change
+// @Description Complete a pending email change using the token from the confirmation email
+// @Tags users
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse "Missing or expired token"
+// @Failure 404 {object} ErrorResponse "Unknown token"
+// @Failure 409 {object} ErrorResponse "Email already in use"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/email/confirm [get]
+func (h *UserHandler) ConfirmEmailChange(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token := c.QueryParam("token")
+	if token == "" {
+		h.logger.Warn(ctx, "email change confirmation without token")
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	u, err := h.userService.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		h.logger.Error(ctx, "failed to confirm email change", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "email change confirmed", "userID", u.ID)
+	return c.JSON(http.StatusOK, UserResponse{
+		ID:    u.ID,
+		Name:  u.Name,
+		Email: u.Email,
+		Role:  string(u.Role),
+	})
+}
+
+// GetAccount handles GET /api/v1/users/me
+// @Summary Get the current account
+// @Description Get the name, email and role of the signed-in user
+// @Tags users
+// @Produce json
+// @Success 200 {object} UserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me [get]
+func (h *UserHandler) GetAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	u, err := h.userService.GetByID(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to get account", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toUserResponse(u))
+}
+
+// UpdateAccount handles PUT /api/v1/users/me
+// @Summary Update the current account
+// @Description Change the name immediately. A different email sends a confirmation link to the new address; the current address stays active, and is returned, until the change is confirmed.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body UpdateAccountRequest true "Name and email"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Email already in use"
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me [put]
+func (h *UserHandler) UpdateAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req UpdateAccountRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind account update request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	// Sanitize input
+	middleware.SanitizeFields(&req)
+
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "account update request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	u, err := h.userService.UpdateProfile(ctx, userID, req.Name, req.Email)
+	if err != nil {
+		h.logger.Error(ctx, "failed to update account", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "account updated", "userID", userID)
+	return c.JSON(http.StatusOK, toUserResponse(u))
+}
+
+// ChangePassword handles PUT /api/v1/users/me/password
+// @Summary Change the password
+// @Description Replace the password of the current account; the current password is required
+// @Tags users
+// @Accept json
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "Missing token or wrong current password"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me/password [put]
+func (h *UserHandler) ChangePassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	var req ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Error(ctx, "failed to bind password change request", "error", err.Error())
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	if err := c.Validate(&req); err != nil {
+		h.logger.Error(ctx, "password change request validation failed", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	if err := h.userService.UpdatePassword(ctx, userID, req.CurrentPassword, req.NewPassword); err != nil {
+		h.logger.Warn(ctx, "failed to change password", "userID", userID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "password changed", "userID", userID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteAccount handles DELETE /api/v1/users/me
+// @Summary Delete the current account
+// @Description Schedule the account for deletion. Logging in during the grace period restores it; afterwards the account and the author name on its comments are anonymized. Download your data first with GET /users/me/export.
+// @Tags users
+// @Produce json
+// @Success 202 {object} DeletionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/users/me [delete]
+func (h *UserHandler) DeleteAccount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Get user ID from context (set by auth middleware)
+	userID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	u, err := h.userService.ScheduleDeletion(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to schedule account deletion", "error", err.Error(), "userID", userID)
+		return errors.HandleError(c, err)
+	}
+
+	h.logger.Info(ctx, "account deletion scheduled", "userID", userID)
+	return c.JSON(http.StatusAccepted, DeletionResponse{
+		Message: "Account scheduled for deletion. Log in before the purge date to restore it.",
+		PurgeAt: u.DeletionScheduledAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// toUserResponse converts a user to the account response format
+func toUserResponse(u *user.User) UserResponse {
+	return UserResponse{
+		ID:    u.ID,
+		Name:  u.Name,
+		Email: u.Email,
+		Role:  string(u.Role),
+	}
+}
+
+// RouteDocs returns examples and error codes for the account routes
+func (h *UserHandler) RouteDocs() []RouteDoc {
+	exampleUser := UserResponse{ID: 1, Name: "John Doe", Email: "john@example.com", Role: "author"}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/me",
+			Summary:         "Get the current account",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleUser,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/users/me",
+			Summary:         "Update the current account",
+			RequestExample:  UpdateAccountRequest{Name: "John Doe", Email: "john@example.com"},
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: exampleUser,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:         http.MethodPut,
+			Path:           "/api/v1/users/me/password",
+			Summary:        "Change the password",
+			RequestExample: ChangePasswordRequest{CurrentPassword: "OldPassw0rd!", NewPassword: "NewPassw0rd!"},
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeInvalidCredentials, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/users/me/email",
+			Summary:         "Request an email change",
+			RequestExample:  EmailChangeRequest{Email: "new@example.com"},
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: MessageResponse{Message: "A confirmation link has been sent to the new email address"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/users/email/confirm",
+			Summary:         "Confirm an email change",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: UserResponse{ID: 1, Name: "John Doe", Email: "new@example.com", Role: "author"},
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound, errors.ErrCodeConflict),
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            "/api/v1/users/me",
+			Summary:         "Delete account",
+			ResponseStatus:  http.StatusAccepted,
+			ResponseExample: DeletionResponse{Message: "Account scheduled for deletion. Log in before the purge date to restore it.", PurgeAt: "2024-02-14T10:30:00Z"},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeNotFound),
+		},
+	}
+}
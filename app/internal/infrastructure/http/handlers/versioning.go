@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIVersion is a major version of the API. Each version is served below
+// its own route group and keeps its response contracts; breaking changes
+// ship in a new version.
+type APIVersion int
+
+// API versions
+const (
+	APIv1 APIVersion = 1
+	APIv2 APIVersion = 2
+)
+
+// Prefix returns the path the routes of the version are served below
+func (v APIVersion) Prefix() string {
+	return "/api/v" + strconv.Itoa(int(v))
+}
+
+// ResponseMapper shapes the responses of handlers shared between API
+// versions. Handlers build the v1 representation of a response and the
+// mapper of their route group turns it into the contract of its version.
+type ResponseMapper interface {
+	// Version returns the API version the mapper responds for
+	Version() APIVersion
+	// Map returns the body to send for a v1 response body
+	Map(body any) any
+}
+
+// V1Mapper responds with the v1 representations unchanged
+type V1Mapper struct{}
+
+// Version returns APIv1
+func (V1Mapper) Version() APIVersion { return APIv1 }
+
+// Map returns body unchanged
+func (V1Mapper) Map(body any) any { return body }
+
+// VersionRouteDocs documents the routes of the mapper's version like the
+// v1 routes with the same path below the version prefix, with their
+// response examples mapped. v1 docs must be registered first; routes
+// without a v1 counterpart are left undocumented.
+func (r *RouteRegistry) VersionRouteDocs(mapper ResponseMapper, routes []*echo.Route) {
+	prefix := mapper.Version().Prefix()
+	var docs []RouteDoc
+	for _, route := range routes {
+		rest, ok := strings.CutPrefix(route.Path, prefix+"/")
+		if !ok {
+			continue
+		}
+		doc, ok := r.Lookup(route.Method, APIv1.Prefix()+"/"+rest)
+		if !ok {
+			continue
+		}
+		doc.Path = route.Path
+		if doc.ResponseExample != nil {
+			doc.ResponseExample = mapper.Map(doc.ResponseExample)
+		}
+		docs = append(docs, doc)
+	}
+	r.Register(docs...)
+}
@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/webhook"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// WebhookHandler handles the webhooks admins register to be sent content
+// events, and their delivery logs
+type WebhookHandler struct {
+	webhookService webhook.Service
+	pagination     service.PageLimits
+	logger         service.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService webhook.Service, pagination service.PageLimits, logger service.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		pagination:     pagination,
+		logger:         logger,
+	}
+}
+
+// WebhookEndpointRequest represents the create and update webhook request
+// payload
+type WebhookEndpointRequest struct {
+	URL string `json:"url" validate:"required,max=2048"`
+	// Events are post.published, comment.created or user.registered
+	Events []string `json:"events" validate:"required,min=1,max=10,dive,required"`
+	// Active defaults to true
+	Active *bool `json:"active,omitempty"`
+}
+
+// WebhookEndpointResponse represents a webhook
+type WebhookEndpointResponse struct {
+	ID     int      `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+	// Secret signs the payloads; it is only returned when the webhook is
+	// created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookEndpointListResponse represents a list of webhooks
+type WebhookEndpointListResponse struct {
+	Webhooks []WebhookEndpointResponse `json:"webhooks"`
+	Total    int                       `json:"total"`
+}
+
+// WebhookDeliveryResponse represents a delivery of an event to a webhook
+type WebhookDeliveryResponse struct {
+	ID       int             `json:"id"`
+	Event    string          `json:"event"`
+	Payload  json.RawMessage `json:"payload" swaggertype:"object"`
+	Status   string          `json:"status"`
+	Attempts int             `json:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt, left out when
+	// no response was received
+	ResponseStatus int        `json:"response_status,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryListResponse represents a page of the delivery log of a
+// webhook
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Total      int                       `json:"total"`
+	Limit      int                       `json:"limit"`
+	Offset     int                       `json:"offset"`
+}
+
+// List handles GET /api/v1/admin/webhooks
+// @Summary List webhooks
+// @Description List every webhook, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} WebhookEndpointListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks [get]
+func (h *WebhookHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	webhooks, err := h.webhookService.List(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list webhooks", "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := WebhookEndpointListResponse{
+		Webhooks: make([]WebhookEndpointResponse, len(webhooks)),
+		Total:    len(webhooks),
+	}
+	for i, w := range webhooks {
+		response.Webhooks[i] = toWebhookEndpointResponse(w)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// Create handles POST /api/v1/admin/webhooks
+// @Summary Register a webhook
+// @Description Register an endpoint to be sent the given events as signed JSON payloads. The response holds the signing secret, which is not shown again (admins only).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body WebhookEndpointRequest true "Webhook"
+// @Success 201 {object} WebhookEndpointResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks [post]
+func (h *WebhookHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	in, err := h.bindWebhook(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	w, err := h.webhookService.Create(ctx, adminID, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to create webhook", "adminID", adminID, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := toWebhookEndpointResponse(w)
+	response.Secret = w.Secret
+	return c.JSON(http.StatusCreated, response)
+}
+
+// Update handles PUT /api/v1/admin/webhooks/{id}
+// @Summary Update a webhook
+// @Description Replace the URL, events and state of a webhook; its secret is kept (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body WebhookEndpointRequest true "Webhook"
+// @Success 200 {object} WebhookEndpointResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{id} [put]
+func (h *WebhookHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid webhook ID in path", "webhook_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	in, err := h.bindWebhook(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	w, err := h.webhookService.Update(ctx, adminID, id, in)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to update webhook", "webhookID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.JSON(http.StatusOK, toWebhookEndpointResponse(w))
+}
+
+// Delete handles DELETE /api/v1/admin/webhooks/{id}
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log; queued deliveries are dropped (admins only)
+// @Tags admin
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	adminID, ok := c.Get("user_id").(int)
+	if !ok {
+		h.logger.Error(ctx, "user_id not found in context")
+		return errors.HandleError(c, errors.ErrUnauthorized)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid webhook ID in path", "webhook_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+
+	if err := h.webhookService.Delete(ctx, adminID, id); err != nil {
+		h.logger.Warn(ctx, "failed to delete webhook", "webhookID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/v1/admin/webhooks/{id}/deliveries
+// @Summary List the deliveries of a webhook
+// @Description List the events sent, or queued to be sent, to a webhook with the outcome of their last attempt, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param limit query int false "Number of deliveries to return" default(10)
+// @Param offset query int false "Number of deliveries to skip" default(0)
+// @Success 200 {object} WebhookDeliveryListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "invalid webhook ID in path", "webhook_id", c.Param("id"))
+		return errors.HandleError(c, errors.ErrInvalidRequest)
+	}
+	limit, offset := parsePage(c, h.pagination)
+
+	deliveries, total, err := h.webhookService.ListDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to list webhook deliveries", "webhookID", id, "error", err.Error())
+		return errors.HandleError(c, err)
+	}
+
+	response := WebhookDeliveryListResponse{
+		Deliveries: make([]WebhookDeliveryResponse, len(deliveries)),
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}
+	for i, d := range deliveries {
+		response.Deliveries[i] = toWebhookDeliveryResponse(d)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// bindWebhook binds and validates a webhook request
+func (h *WebhookHandler) bindWebhook(c echo.Context) (webhook.Input, error) {
+	var req WebhookEndpointRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(c.Request().Context(), "failed to bind webhook request", "error", err.Error())
+		return webhook.Input{}, errors.ErrInvalidRequest
+	}
+	if err := c.Validate(&req); err != nil {
+		return webhook.Input{}, err
+	}
+
+	in := webhook.Input{URL: req.URL, Events: req.Events, Active: true}
+	if req.Active != nil {
+		in.Active = *req.Active
+	}
+	return in, nil
+}
+
+// toWebhookEndpointResponse converts a webhook to its response format, without
+// its secret
+func toWebhookEndpointResponse(w *webhook.Webhook) WebhookEndpointResponse {
+	return WebhookEndpointResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// toWebhookDeliveryResponse converts a delivery to its response format
+func toWebhookDeliveryResponse(d *webhook.Delivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             d.ID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+// RouteDocs returns examples and error codes for the webhook routes
+func (h *WebhookHandler) RouteDocs() []RouteDoc {
+	createdAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	hook := WebhookEndpointResponse{
+		ID:        1,
+		URL:       "https://hooks.example.com/blog",
+		Events:    []string{webhook.EventCommentCreated, webhook.EventPostPublished},
+		Active:    true,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	created := hook
+	created.Secret = "whsec_3f9a1c5e7b2d4f6a8c0e1b3d5f7a9c1e3b5d7f9a1c3e5b7d"
+	active := true
+	request := WebhookEndpointRequest{URL: hook.URL, Events: hook.Events, Active: &active}
+
+	deliveredAt := createdAt.Add(time.Hour + 2*time.Second)
+	deliveries := WebhookDeliveryListResponse{
+		Deliveries: []WebhookDeliveryResponse{{
+			ID:             42,
+			Event:          webhook.EventPostPublished,
+			Payload:        json.RawMessage(`{"event":"post.published","occurred_at":"2024-03-01T10:00:00Z","data":{"id":7,"title":"Hello, world","slug":"hello-world","author_id":3,"published_at":"2024-03-01T10:00:00Z"}}`),
+			Status:         string(webhook.DeliveryDelivered),
+			Attempts:       2,
+			ResponseStatus: http.StatusOK,
+			CreatedAt:      createdAt.Add(time.Hour),
+			DeliveredAt:    &deliveredAt,
+		}},
+		Total:  1,
+		Limit:  10,
+		Offset: 0,
+	}
+
+	return []RouteDoc{
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/webhooks",
+			Summary:         "List webhooks",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: WebhookEndpointListResponse{Webhooks: []WebhookEndpointResponse{hook}, Total: 1},
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/admin/webhooks",
+			Summary:         "Register a webhook",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: created,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation),
+		},
+		{
+			Method:          http.MethodPut,
+			Path:            "/api/v1/admin/webhooks/{id}",
+			Summary:         "Update a webhook",
+			RequestExample:  request,
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: hook,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/api/v1/admin/webhooks/{id}",
+			Summary:        "Delete a webhook",
+			ResponseStatus: http.StatusNoContent,
+			Errors:         withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/admin/webhooks/{id}/deliveries",
+			Summary:         "List the deliveries of a webhook",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: deliveries,
+			Errors:          withCommonErrors(errors.ErrCodeUnauthorized, errors.ErrCodeForbidden, errors.ErrCodeInvalidRequest, errors.ErrCodeNotFound),
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/widget"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/http/views"
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+// WidgetSettings configures the embeddable comments widget
+type WidgetSettings struct {
+	SiteName string
+}
+
+// WidgetHandler serves the comments widget embedded on external sites. The
+// widget reads and writes comments like the comment routes, limited to
+// published posts; the sites embedding it are checked by
+// middleware.WidgetCORS.
+type WidgetHandler struct {
+	postService    post.Service
+	commentService comment.Service
+	comments       *CommentHandler
+	settings       WidgetSettings
+	logger         service.Logger
+}
+
+// NewWidgetHandler creates a new widget handler
+func NewWidgetHandler(postService post.Service, commentService comment.Service, pagination service.PageLimits, settings WidgetSettings, logger service.Logger) *WidgetHandler {
+	return &WidgetHandler{
+		postService:    postService,
+		commentService: commentService,
+		comments:       NewCommentHandler(commentService, pagination, logger),
+		settings:       settings,
+		logger:         logger,
+	}
+}
+
+// ListComments handles GET /api/v1/widget/posts/{id}/comments
+// @Summary List the comments of a post for the widget
+// @Description List pages of the top-level comments of a published post with their replies nested, for the comments widget of an embedding site
+// @Tags widget
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param token query string true "Public token of the embedding site"
+// @Param limit query int false "Number of top-level comments to return (default: 10, max: 100, configurable)"
+// @Param offset query int false "Number of top-level comments to skip (default: 0)"
+// @Success 200 {object} ThreadedCommentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Unknown site or origin not allowed"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/widget/posts/{id}/comments [get]
+func (h *WidgetHandler) ListComments(c echo.Context) error {
+	p, err := h.publishedPost(c)
+	if err != nil {
+		return errors.HandleError(c, err)
+	}
+
+	limit, offset := parsePage(c, h.comments.pagination)
+	return h.comments.getThreadsByPost(c, p.ID, limit, offset)
+}
+
+// CreateComment handles POST /api/v1/widget/posts/{id}/comments
+// @Summary Comment on a post from the widget
+// @Description Comment on a published post, or reply with parent_id, from the comments widget of an embedding site. Comments sent with the access token of a signed-in user, e.g. from social login, are linked to the user; comments without one are guest comments.
+// @Tags widget
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param token query string true "Public token of the embedding site"
+// @Param comment body CreateCommentRequest true "Comment data"
+// @Success 201 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Unknown site or origin not allowed"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/widget/posts/{id}/comments [post]
+func (h *WidgetHandler) CreateComment(c echo.Context) error {
+	if _, err := h.publishedPost(c); err != nil {
+		return errors.HandleError(c, err)
+	}
+	return h.comments.CreateComment(c)
+}
+
+// ShowThread renders the comments of a post as an HTML page for the
+// embedding site to show in an iframe
+func (h *WidgetHandler) ShowThread(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Only the pages of the embedding site may frame the thread
+	frameAncestors := "'none'"
+	if site, ok := c.Get(middleware.WidgetSiteKey).(*widget.Site); ok {
+		frameAncestors = strings.Join(site.Origins, " ")
+	}
+	header := c.Response().Header()
+	header.Del("X-Frame-Options")
+	header.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors "+frameAncestors)
+	header.Set(noIndexHeader, noIndexValue)
+
+	page := views.Page{SiteName: h.settings.SiteName, NoIndex: true, Embedded: true}
+
+	p, err := h.publishedPost(c)
+	switch {
+	case stderrors.Is(err, post.ErrPostNotFound):
+		page.Title = "Not found"
+		return c.Render(http.StatusNotFound, "not_found.html", page)
+	case stderrors.Is(err, errors.ErrInvalidRequest):
+		return c.String(http.StatusBadRequest, "Invalid post ID")
+	case err != nil:
+		return c.String(http.StatusInternalServerError, "An internal server error occurred")
+	}
+
+	threads, err := h.commentService.GetThreadsByPost(ctx, p.ID, 0, "", readingViewCommentLimit, 0)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load comments for widget", "post_id", p.ID, "error", err.Error())
+		return c.String(http.StatusInternalServerError, "An internal server error occurred")
+	}
+
+	page.Title = p.Title
+	data := views.PostPage{
+		Page:     page,
+		Post:     views.PostView{Title: p.Title},
+		Comments: toCommentViews(threads),
+	}
+	return c.Render(http.StatusOK, "widget.html", data)
+}
+
+// publishedPost loads the post of the path; drafts, scheduled and private
+// posts are reported as not found
+func (h *WidgetHandler) publishedPost(c echo.Context) (*post.Post, error) {
+	ctx := c.Request().Context()
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid post ID in path", "post_id", c.Param("id"))
+		return nil, errors.ErrInvalidRequest
+	}
+
+	p, err := h.postService.GetVisiblePost(ctx, 0, "", postID)
+	if err != nil {
+		if !stderrors.Is(err, post.ErrPostNotFound) {
+			h.logger.Error(ctx, "Failed to load post for widget", "post_id", postID, "error", err.Error())
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// toCommentViews converts threads to nested comment views
+func toCommentViews(threads []*comment.Thread) []views.CommentView {
+	comments := make([]views.CommentView, len(threads))
+	for i, thread := range threads {
+		comments[i] = views.CommentView{
+			ID:          thread.Comment.ID,
+			AuthorName:  thread.Comment.AuthorName,
+			Content:     thread.Comment.Content,
+			ContentHTML: template.HTML(markdown.RenderBasic(thread.Comment.Content)),
+			CreatedAt:   thread.Comment.CreatedAt.Format("January 2, 2006"),
+			Replies:     toCommentViews(thread.Replies),
+		}
+	}
+	return comments
+}
+
+// RouteDocs returns examples and error codes for the widget routes
+func (h *WidgetHandler) RouteDocs() []RouteDoc {
+	exampleComment := CommentResponse{
+		ID:         1,
+		PostID:     1,
+		AuthorName: "Jane Smith",
+		Content:    "Found this through your newsletter, great read!",
+		Status:     string(comment.StatusApproved),
+		CreatedAt:  "2024-01-15T11:00:00Z",
+	}
+	widgetErrors := []errors.ErrorCode{errors.ErrCodeInvalidRequest, errors.ErrCodeForbidden, errors.ErrCodeNotFound}
+
+	return []RouteDoc{
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/widget/posts/{id}/comments",
+			Summary:        "List the comments of a post for the widget",
+			ResponseStatus: http.StatusOK,
+			ResponseExample: ThreadedCommentListResponse{
+				Comments: []ThreadedCommentResponse{{CommentResponse: exampleComment, Replies: []ThreadedCommentResponse{}}},
+				Total:    1,
+				Limit:    10,
+				Offset:   0,
+			},
+			Errors: withCommonErrors(widgetErrors...),
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            "/api/v1/widget/posts/{id}/comments",
+			Summary:         "Comment on a post from the widget",
+			RequestExample:  CreateCommentRequest{AuthorName: exampleComment.AuthorName, Content: exampleComment.Content},
+			ResponseStatus:  http.StatusCreated,
+			ResponseExample: exampleComment,
+			Errors:          withCommonErrors(errors.ErrCodeInvalidRequest, errors.ErrCodeValidation, errors.ErrCodeForbidden, errors.ErrCodeNotFound),
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/api/v1/widget/posts/{id}/thread",
+			Summary:        "Comments of a post as an HTML page for iframes",
+			ResponseStatus: http.StatusOK,
+			Errors:         withCommonErrors(widgetErrors...),
+		},
+	}
+}
@@ -8,8 +8,15 @@ import (
 
 	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
 )
 
+// WebSocketProtocol is the WebSocket subprotocol browsers offer, followed
+// by their access token, to sign in a WebSocket connection: they cannot set
+// the Authorization header on the handshake
+const WebSocketProtocol = "bearer"
+
 // AuthMiddleware handles authentication for protected routes
 type AuthMiddleware struct {
 	authService auth.AuthService
@@ -24,9 +31,70 @@ func NewAuthMiddleware(authService auth.AuthService, logger service.Logger) *Aut
 	}
 }
 
+// Identify is a middleware that sets the user information in the context when
+// the request carries a valid bearer token. Unlike RequireAuth it never rejects
+// a request, so it can run ahead of middleware that treats signed-in users
+// differently, such as the rate limiter.
+func (m *AuthMiddleware) Identify(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+			return next(c)
+		}
+
+		claims, err := m.authService.ValidateToken(ctx, parts[1])
+		if err != nil {
+			m.logger.Debug(ctx, "request not identified", "error", err.Error())
+			return next(c)
+		}
+
+		setUser(c, claims)
+		return next(c)
+	}
+}
+
+// IdentifyWebSocket is a middleware that sets the user information in the
+// context for WebSocket handshakes offering the bearer subprotocol followed
+// by an access token, as in new WebSocket(url, ["bearer", token]). Like
+// Identify it never rejects a request; RequireAuth does.
+func (m *AuthMiddleware) IdentifyWebSocket(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, ok := c.Get("user_id").(int); ok {
+			return next(c)
+		}
+		ctx := c.Request().Context()
+
+		var protocols []string
+		for _, value := range c.Request().Header.Values("Sec-WebSocket-Protocol") {
+			for _, protocol := range strings.Split(value, ",") {
+				protocols = append(protocols, strings.TrimSpace(protocol))
+			}
+		}
+		if len(protocols) != 2 || protocols[0] != WebSocketProtocol || protocols[1] == "" {
+			return next(c)
+		}
+
+		claims, err := m.authService.ValidateToken(ctx, protocols[1])
+		if err != nil {
+			m.logger.Debug(ctx, "websocket handshake not identified", "error", err.Error())
+			return next(c)
+		}
+
+		setUser(c, claims)
+		return next(c)
+	}
+}
+
 // RequireAuth is a middleware that requires authentication
 func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		// The token was already validated by Identify
+		if _, ok := c.Get("user_id").(int); ok {
+			return next(c)
+		}
+
 		ctx := c.Request().Context()
 		
 		// Get Authorization header
@@ -34,8 +102,9 @@ func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 		if authHeader == "" {
 			m.logger.Warn(ctx, "missing authorization header")
 			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error":   "Unauthorized",
-				"message": "Authorization header required",
+				"error":      "Unauthorized",
+				"message":    "Authorization header required",
+				"request_id": errors.RequestID(c),
 			})
 		}
 
@@ -44,8 +113,9 @@ func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			m.logger.Warn(ctx, "invalid authorization header format")
 			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error":   "Unauthorized",
-				"message": "Invalid authorization header format",
+				"error":      "Unauthorized",
+				"message":    "Invalid authorization header format",
+				"request_id": errors.RequestID(c),
 			})
 		}
 
@@ -53,26 +123,68 @@ func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 		if token == "" {
 			m.logger.Warn(ctx, "empty bearer token")
 			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error":   "Unauthorized",
-				"message": "Bearer token required",
+				"error":      "Unauthorized",
+				"message":    "Bearer token required",
+				"request_id": errors.RequestID(c),
 			})
 		}
 
 		// Validate token
 		claims, err := m.authService.ValidateToken(ctx, token)
+		if err == auth.ErrTokenRevoked {
+			m.logger.Warn(ctx, "revoked token rejected")
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error":      "Unauthorized",
+				"message":    "Token has been revoked",
+				"request_id": errors.RequestID(c),
+			})
+		}
 		if err != nil {
 			m.logger.Warn(ctx, "token validation failed", "error", err.Error())
 			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error":   "Unauthorized",
-				"message": "Invalid or expired token",
+				"error":      "Unauthorized",
+				"message":    "Invalid or expired token",
+				"request_id": errors.RequestID(c),
 			})
 		}
 
 		// Set user information in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
+		setUser(c, claims)
 
 		m.logger.Debug(ctx, "user authenticated", "user_id", claims.UserID, "email", claims.Email)
 		return next(c)
 	}
 }
+
+// RequireRole is a middleware that only admits users holding one of the given
+// roles. It must run after RequireAuth.
+func (m *AuthMiddleware) RequireRole(roles ...user.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			role, _ := c.Get("user_role").(user.Role)
+			for _, allowed := range roles {
+				if role == allowed {
+					return next(c)
+				}
+			}
+
+			m.logger.Warn(ctx, "insufficient role", "user_id", c.Get("user_id"), "role", string(role))
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error":      "Forbidden",
+				"message":    "Insufficient permissions",
+				"request_id": errors.RequestID(c),
+			})
+		}
+	}
+}
+
+// setUser stores the authenticated user's information in the context
+func setUser(c echo.Context, claims *auth.TokenClaims) {
+	req := c.Request()
+	c.SetRequest(req.WithContext(service.WithUserID(req.Context(), claims.UserID)))
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", user.Role(claims.Role))
+}
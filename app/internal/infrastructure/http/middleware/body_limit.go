@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// BodyLimitPolicy caps the size of request bodies by route
+type BodyLimitPolicy struct {
+	// Default caps the bodies of routes no prefix covers; 0 turns it off
+	Default int64
+	// Prefixes caps the bodies of routes under a path prefix, the longest
+	// matching prefix winning; 0 turns the limit off for them
+	Prefixes map[string]int64
+}
+
+// limitFor returns the limit of requests to path
+func (p BodyLimitPolicy) limitFor(path string) int64 {
+	limit, matched := p.Default, -1
+	for prefix, prefixLimit := range p.Prefixes {
+		if len(prefix) > matched && (path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")) {
+			limit, matched = prefixLimit, len(prefix)
+		}
+	}
+	return limit
+}
+
+// BodyLimit rejects requests whose body is larger than the policy allows
+// with 413 Payload Too Large before anything reads them. Bodies of known
+// length are checked against it up front; bodies sent without a length are
+// read up to the limit first, so handlers never see a truncated body.
+func BodyLimit(policy BodyLimitPolicy, logger service.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			limit := policy.limitFor(req.URL.Path)
+			if limit <= 0 || req.Body == nil || req.Body == http.NoBody {
+				return next(c)
+			}
+
+			errTooLarge := errors.NewAPIError(errors.ErrCodePayloadTooLarge, fmt.Sprintf("Request body must be at most %d bytes", limit), http.StatusRequestEntityTooLarge)
+			if req.ContentLength > limit {
+				logger.Warn(req.Context(), "request body rejected", "reason", "too large", "size", req.ContentLength, "limit", limit)
+				return errors.HandleError(c, errTooLarge)
+			}
+			if req.ContentLength < 0 {
+				body, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+				req.Body.Close()
+				if err != nil {
+					return errors.HandleError(c, errors.NewAPIError(errors.ErrCodeInvalidRequest, "Request body could not be read", http.StatusBadRequest))
+				}
+				if int64(len(body)) > limit {
+					logger.Warn(req.Context(), "request body rejected", "reason", "too large", "limit", limit)
+					return errors.HandleError(c, errTooLarge)
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.ContentLength = int64(len(body))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/clientid"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// ClientIDParam is the path parameter that may hold a client ID instead of
+// the ID of the resource
+const ClientIDParam = "id"
+
+// PostClientIDs lets post routes address a post by the client ID it was
+// created with, so offline clients can queue writes to posts they created
+// before learning their IDs. A UUID in the id path parameter is replaced by
+// the ID of the post; handlers only ever see IDs.
+func PostClientIDs(postService post.Service, logger service.Logger) echo.MiddlewareFunc {
+	return resolveClientID(func(ctx context.Context, clientID string) (int, error) {
+		p, err := postService.GetPostByClientID(ctx, clientID)
+		if err != nil {
+			return 0, err
+		}
+		return p.ID, nil
+	}, logger)
+}
+
+// CommentClientIDs lets comment routes address a comment by the client ID
+// it was created with, like PostClientIDs does for posts
+func CommentClientIDs(commentService comment.Service, logger service.Logger) echo.MiddlewareFunc {
+	return resolveClientID(func(ctx context.Context, clientID string) (int, error) {
+		c, err := commentService.GetCommentByClientID(ctx, clientID)
+		if err != nil {
+			return 0, err
+		}
+		return c.ID, nil
+	}, logger)
+}
+
+// resolveClientID replaces a client ID in the id path parameter by the ID
+// resolve returns for it; other values are left to the handler
+func resolveClientID(resolve func(ctx context.Context, clientID string) (int, error), logger service.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			value, err := clientid.Normalize(c.Param(ClientIDParam))
+			if err != nil {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			id, err := resolve(ctx, value)
+			if err != nil {
+				logger.Debug(ctx, "failed to resolve client ID", "clientID", value, "error", err.Error())
+				return errors.HandleError(c, err)
+			}
+
+			names := c.ParamNames()
+			values := append([]string(nil), c.ParamValues()...)
+			for i, name := range names {
+				if name == ClientIDParam {
+					values[i] = strconv.Itoa(id)
+				}
+			}
+			c.SetParamValues(values...)
+			return next(c)
+		}
+	}
+}
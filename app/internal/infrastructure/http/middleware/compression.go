@@ -1,12 +1,71 @@
 package middleware
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"blog-platform/internal/infrastructure/config"
 )
 
+// Encoder compresses responses in one content coding
+type Encoder struct {
+	// Name is the coding as sent in Accept-Encoding and Content-Encoding
+	Name string
+	// NewWriter returns a writer compressing into w at level. Writers with
+	// a Reset(io.Writer) method are pooled between responses.
+	NewWriter func(w io.Writer, level int) (io.WriteCloser, error)
+}
+
+// BrotliEncoder compresses responses with brotli, which makes text smaller
+// than gzip at the same level
+var BrotliEncoder = Encoder{
+	Name: "br",
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return brotli.NewWriterLevel(w, level), nil
+	},
+}
+
+// GzipEncoder compresses responses with gzip
+var GzipEncoder = Encoder{
+	Name: "gzip",
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	},
+}
+
+// DeflateEncoder compresses responses with deflate
+var DeflateEncoder = Encoder{
+	Name: "deflate",
+	NewWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+		return flate.NewWriter(w, level)
+	},
+}
+
+// DefaultCompressionContentTypes lists the content types compressed by
+// default; the others, such as images, are mostly compressed already
+var DefaultCompressionContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/xml",
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/javascript",
+	"image/svg+xml",
+}
+
 // CompressionConfig holds compression configuration
 type CompressionConfig struct {
 	// Enabled controls whether compression is enabled
@@ -15,6 +74,12 @@ type CompressionConfig struct {
 	Level int
 	// MinLength defines the minimum response size to compress (in bytes)
 	MinLength int
+	// ContentTypes lists the media types compressed; a type/* entry covers
+	// every subtype
+	ContentTypes []string
+	// Encoders lists the codings offered, most preferred first. Clients
+	// choose among them with the quality values of Accept-Encoding.
+	Encoders []Encoder
 	// Skipper defines a function to skip compression for certain requests
 	Skipper middleware.Skipper
 }
@@ -22,68 +87,278 @@ type CompressionConfig struct {
 // DefaultCompressionConfig returns default compression configuration
 func DefaultCompressionConfig() CompressionConfig {
 	return CompressionConfig{
-		Enabled:   true,
-		Level:     6, // Good balance between compression ratio and speed
-		MinLength: 1024, // Only compress responses larger than 1KB
-		Skipper:   middleware.DefaultSkipper,
-	}
-}
-
-// GetCompressionConfig returns compression configuration based on application config
-func GetCompressionConfig(cfg *config.Config) middleware.GzipConfig {
-	return middleware.GzipConfig{
-		Level: cfg.Compression.Level,
-		Skipper: func(c echo.Context) bool {
-			// Skip compression if disabled
-			if !cfg.Compression.Enabled {
-				return true
-			}
-			
-			// Skip compression for certain content types that are already compressed
-			contentType := c.Response().Header().Get(echo.HeaderContentType)
-			switch contentType {
-			case "image/jpeg", "image/png", "image/gif", "image/webp",
-				 "video/mp4", "video/mpeg", "video/quicktime",
-				 "application/zip", "application/gzip", "application/x-gzip":
-				return true
-			}
-			
-			// Skip compression for small responses (will be checked after response is written)
-			return false
-		},
+		Enabled:      true,
+		Level:        6, // Good balance between compression ratio and speed
+		MinLength:    1024, // Only compress responses larger than 1KB
+		ContentTypes: DefaultCompressionContentTypes,
+		Encoders:     []Encoder{BrotliEncoder, GzipEncoder, DeflateEncoder},
+		Skipper:      middleware.DefaultSkipper,
 	}
 }
 
 // Compression returns compression middleware with configuration-based setup
 func Compression(cfg *config.Config) echo.MiddlewareFunc {
-	if !cfg.Compression.Enabled {
-		// Return a no-op middleware if compression is disabled
-		return func(next echo.HandlerFunc) echo.HandlerFunc {
-			return next
-		}
+	compression := DefaultCompressionConfig()
+	compression.Enabled = cfg.Compression.Enabled
+	compression.Level = cfg.Compression.Level
+	compression.MinLength = cfg.Compression.MinLength
+	if len(cfg.Compression.ContentTypes) > 0 {
+		compression.ContentTypes = cfg.Compression.ContentTypes
 	}
-	
-	return middleware.GzipWithConfig(GetCompressionConfig(cfg))
+	return CompressionWithConfig(compression)
 }
 
-// CompressionWithConfig returns compression middleware with custom configuration
+// CompressionWithConfig returns compression middleware with custom
+// configuration. Responses are buffered until they reach MinLength, so
+// shorter ones are sent as they are.
 func CompressionWithConfig(config CompressionConfig) echo.MiddlewareFunc {
-	if !config.Enabled {
+	if !config.Enabled || len(config.Encoders) == 0 {
 		// Return a no-op middleware if compression is disabled
 		return func(next echo.HandlerFunc) echo.HandlerFunc {
 			return next
 		}
 	}
-	
-	gzipConfig := middleware.GzipConfig{
-		Level:   config.Level,
-		Skipper: config.Skipper,
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	pools := make(map[string]*sync.Pool, len(config.Encoders))
+	for _, encoder := range config.Encoders {
+		pools[encoder.Name] = &sync.Pool{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			encoder, ok := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding), config.Encoders)
+			if !ok {
+				return next(c)
+			}
+
+			res := c.Response()
+			cw := &compressWriter{
+				ResponseWriter: res.Writer,
+				encoder:        encoder,
+				pool:           pools[encoder.Name],
+				config:         &config,
+				head:           c.Request().Method == http.MethodHead,
+			}
+			res.Writer = cw
+			defer func() {
+				cw.finish()
+				res.Writer = cw.ResponseWriter
+			}()
+			return next(c)
+		}
 	}
-	
-	return middleware.GzipWithConfig(gzipConfig)
 }
 
 // CompressionDefault returns compression middleware with default configuration
 func CompressionDefault() echo.MiddlewareFunc {
 	return CompressionWithConfig(DefaultCompressionConfig())
 }
+
+// negotiateEncoding picks the encoder the client accepts with the highest
+// quality, preferring earlier encoders on ties
+func negotiateEncoding(acceptEncoding string, encoders []Encoder) (Encoder, bool) {
+	if acceptEncoding == "" {
+		return Encoder{}, false
+	}
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		qualities[name] = quality
+	}
+
+	var best Encoder
+	bestQuality := 0.0
+	for _, encoder := range encoders {
+		quality, ok := qualities[encoder.Name]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoder, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// compressible reports whether responses of contentType are compressed
+func compressible(contentType string, types []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, accepted := range types {
+		if prefix, ok := strings.CutSuffix(accepted, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter holds back the response until it knows whether to compress
+// it: the status and the start of the body are buffered until the body
+// reaches the minimum length or ends.
+type compressWriter struct {
+	http.ResponseWriter
+	encoder Encoder
+	pool    *sync.Pool
+	config  *CompressionConfig
+	head    bool
+
+	status    int
+	buf       bytes.Buffer
+	buffering bool
+	decided   bool
+	compress  bool
+	writer    io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		return w.write(p)
+	}
+	if !w.buffering {
+		if header := w.Header(); header.Get(echo.HeaderContentType) == "" {
+			header.Set(echo.HeaderContentType, http.DetectContentType(p))
+		}
+		w.buffering = w.eligible()
+	}
+	if !w.buffering {
+		if err := w.decide(false); err != nil {
+			return 0, err
+		}
+		return w.write(p)
+	}
+	w.buf.Write(p)
+	if w.buf.Len() >= w.config.MinLength {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// eligible reports whether the response may be compressed once it is long
+// enough
+func (w *compressWriter) eligible() bool {
+	header := w.Header()
+	switch {
+	case w.head, w.status == http.StatusNoContent, w.status == http.StatusNotModified:
+		return false
+	case header.Get(echo.HeaderContentEncoding) != "":
+		return false
+	}
+	if !compressible(header.Get(echo.HeaderContentType), w.config.ContentTypes) {
+		return false
+	}
+	header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+	return true
+}
+
+// decide sends the status and headers, then the buffered body, compressed
+// or not
+func (w *compressWriter) decide(compress bool) error {
+	w.decided, w.compress = true, compress
+	if compress {
+		header := w.Header()
+		header.Set(echo.HeaderContentEncoding, w.encoder.Name)
+		header.Del(echo.HeaderContentLength)
+		writer, err := w.acquire()
+		if err != nil {
+			return err
+		}
+		w.writer = writer
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) write(p []byte) (int, error) {
+	if w.compress {
+		return w.writer.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// acquire returns a pooled writer of the encoder, or a new one
+func (w *compressWriter) acquire() (io.WriteCloser, error) {
+	if pooled, ok := w.pool.Get().(io.WriteCloser); ok {
+		pooled.(interface{ Reset(io.Writer) }).Reset(w.ResponseWriter)
+		return pooled, nil
+	}
+	return w.encoder.NewWriter(w.ResponseWriter, w.config.Level)
+}
+
+// finish sends what is still held back and completes the compressed
+// stream. Responses never written to are left alone, so errors returned by
+// handlers are written by the error handler afterwards.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if w.status == 0 && w.buf.Len() == 0 {
+			return
+		}
+		w.decide(false)
+	}
+	if !w.compress {
+		return
+	}
+	w.writer.Close()
+	if _, ok := w.writer.(interface{ Reset(io.Writer) }); ok {
+		w.pool.Put(w.writer)
+	}
+}
+
+// Flush sends the response so far, deciding on compression with what has
+// been buffered
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(w.buf.Len() >= w.config.MinLength && w.buf.Len() > 0)
+	}
+	if flusher, ok := w.writer.(interface{ Flush() error }); ok && w.compress {
+		flusher.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack hands the connection over, for WebSocket upgrades
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
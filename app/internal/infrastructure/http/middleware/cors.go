@@ -38,6 +38,11 @@ func GetCORSConfig(cfg *config.Config) middleware.CORSConfig {
 		MaxAge:           86400, // 24 hours
 	}
 
+	// The widget endpoints answer the origins of their own sites
+	if cfg.Widget.Enabled {
+		corsConfig.Skipper = IsWidgetRequest
+	}
+
 	// Use configured origins if available, otherwise use environment-based defaults
 	if len(cfg.CORS.AllowedOrigins) > 0 {
 		corsConfig.AllowOrigins = cfg.CORS.AllowedOrigins
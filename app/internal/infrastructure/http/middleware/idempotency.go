@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/domain/idempotency"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header naming a write request, so
+	// retries of it are not applied twice
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks responses replayed for a retry
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// idempotentResultKey names the context value holding what a handler stores
+// for its request in place of the response
+const idempotentResultKey = "idempotent_result"
+
+// ReplayFunc answers a retried request from the record of the first one,
+// whose body holds the result the handler stored with SetIdempotentResult
+type ReplayFunc func(c echo.Context, record *idempotency.Record) error
+
+// SetIdempotentResult stores result for the request in place of its response,
+// on routes behind IdempotencyWithReplay
+func SetIdempotentResult(c echo.Context, result []byte) {
+	c.Set(idempotentResultKey, result)
+}
+
+// Idempotency lets clients retry write requests safely. The first request
+// with an Idempotency-Key is processed and its successful response stored;
+// retries with the key and the same method, path and body get the stored
+// response back instead of being processed again. Keys are scoped to the
+// signed-in user or, for guests, the client IP, so the middleware goes
+// after the auth middleware. Requests without the header pass through.
+//
+// Failed responses are not stored and release the key, so the request can
+// be corrected or retried with it.
+func Idempotency(idempotencyService idempotency.Service) echo.MiddlewareFunc {
+	return idempotent(idempotencyService, nil)
+}
+
+// IdempotencyWithReplay is Idempotency for requests whose response is not to
+// be stored, such as registrations answered with tokens. The handler stores a
+// result of its own with SetIdempotentResult, and retries are answered by
+// replay from it. Responses the handler stores no result for release the key.
+func IdempotencyWithReplay(idempotencyService idempotency.Service, replay ReplayFunc) echo.MiddlewareFunc {
+	return idempotent(idempotencyService, replay)
+}
+
+func idempotent(idempotencyService idempotency.Service, replay ReplayFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return errors.HandleError(c, errors.ErrInvalidRequest)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+			record, replayed, err := idempotencyService.Begin(ctx, UserKeyGenerator(c), key, requestHash(req, body))
+			if err != nil {
+				return errors.HandleError(c, err)
+			}
+			if replayed {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				if replay != nil {
+					return replay(c, record)
+				}
+				if record.ContentType == "" {
+					return c.NoContent(record.StatusCode)
+				}
+				return c.Blob(record.StatusCode, record.ContentType, record.Body)
+			}
+
+			if replay != nil {
+				err = next(c)
+				result, _ := c.Get(idempotentResultKey).([]byte)
+				if err != nil || result == nil {
+					idempotencyService.Release(ctx, record)
+					return err
+				}
+				idempotencyService.Complete(ctx, record, c.Response().Status, "", result)
+				return nil
+			}
+
+			res := c.Response()
+			capture := &captureWriter{ResponseWriter: res.Writer}
+			res.Writer = capture
+			err = next(c)
+			res.Writer = capture.ResponseWriter
+
+			if err != nil || res.Status < http.StatusOK || res.Status >= http.StatusMultipleChoices {
+				idempotencyService.Release(ctx, record)
+				return err
+			}
+			idempotencyService.Complete(ctx, record, res.Status, res.Header().Get(echo.HeaderContentType), capture.body.Bytes())
+			return nil
+		}
+	}
+}
+
+// requestHash identifies a request by its method, path and body, so a key
+// sent again with a different request is told apart from a retry
+func requestHash(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, req.Method)
+	hash.Write([]byte{0})
+	io.WriteString(hash, req.URL.RequestURI())
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// captureWriter keeps a copy of the response body as it is written
+type captureWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
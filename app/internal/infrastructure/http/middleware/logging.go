@@ -1,10 +1,8 @@
 package middleware
 
 import (
-	"bufio"
-	"bytes"
-	"io"
-	"net"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,116 +10,52 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
 )
 
-// RequestResponseLogger creates middleware for comprehensive request/response logging
-func RequestResponseLogger(logger service.Logger) echo.MiddlewareFunc {
-	return echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
+// AccessLogger logs one structured line per request once it is answered,
+// with its method, route, status, latency and response size. Lines carry
+// the request ID and, for signed-in users, the user ID from the context like
+// every other line logged while serving the request, so it should come
+// right after RequestID. Bodies are never logged, as they hold passwords
+// and tokens.
+func AccessLogger(logger service.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			req := c.Request()
-			res := c.Response()
 			start := time.Now()
 
-			// Create a custom response writer to capture response body
-			resBody := new(bytes.Buffer)
-			mw := io.MultiWriter(res.Writer, resBody)
-			writer := &bodyDumpResponseWriter{Writer: mw, ResponseWriter: res.Writer}
-			res.Writer = writer
-
-			// Read and log request body for non-GET requests
-			var reqBody []byte
-			if req.Method != "GET" && req.Method != "DELETE" {
-				if req.Body != nil {
-					reqBody, _ = io.ReadAll(req.Body)
-					req.Body = io.NopCloser(bytes.NewBuffer(reqBody))
-				}
+			// Write errors here, so the line has the status sent for them
+			if err := next(c); err != nil {
+				c.Error(err)
 			}
 
-			// Log request
+			// The request now carries the user Identify found, if any
+			req := c.Request()
+			res := c.Response()
 			ctx := req.Context()
-			logger.Info(ctx, "HTTP request started",
+			fields := []any{
 				"method", req.Method,
+				"route", c.Path(),
 				"uri", req.RequestURI,
+				"status", res.Status,
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"bytes_in", req.ContentLength,
+				"bytes_out", res.Size,
 				"remote_addr", c.RealIP(),
 				"user_agent", req.UserAgent(),
-				"content_length", req.ContentLength,
-			)
-
-			// Log request body for debugging (be careful with sensitive data)
-			if len(reqBody) > 0 && len(reqBody) < 1024 { // Only log small bodies
-				logger.Debug(ctx, "Request body", "body", string(reqBody))
-			}
-
-			// Process request
-			err := next(c)
-
-			// Calculate duration
-			duration := time.Since(start)
-
-			// Log response
-			status := res.Status
-			responseSize := res.Size
-
-			logLevel := "info"
-			if status >= 400 && status < 500 {
-				logLevel = "warn"
-			} else if status >= 500 {
-				logLevel = "error"
 			}
 
-			logFields := []interface{}{
-				"method", req.Method,
-				"uri", req.RequestURI,
-				"status", status,
-				"duration_ms", duration.Milliseconds(),
-				"response_size", responseSize,
-				"remote_addr", c.RealIP(),
-			}
-
-			switch logLevel {
-			case "warn":
-				logger.Warn(ctx, "HTTP request completed with client error", logFields...)
-			case "error":
-				logger.Error(ctx, "HTTP request completed with server error", logFields...)
+			switch {
+			case res.Status >= http.StatusInternalServerError:
+				logger.Error(ctx, "HTTP request completed with server error", fields...)
+			case res.Status >= http.StatusBadRequest:
+				logger.Warn(ctx, "HTTP request completed with client error", fields...)
 			default:
-				logger.Info(ctx, "HTTP request completed", logFields...)
+				logger.Info(ctx, "HTTP request completed", fields...)
 			}
-
-			// Log response body for errors (be careful with sensitive data)
-			if status >= 400 && resBody.Len() > 0 && resBody.Len() < 1024 {
-				logger.Debug(ctx, "Response body", "body", resBody.String())
-			}
-
-			return err
+			return nil
 		}
-	})
-}
-
-// bodyDumpResponseWriter wraps the response writer to capture response body
-type bodyDumpResponseWriter struct {
-	io.Writer
-	http.ResponseWriter
-}
-
-func (w *bodyDumpResponseWriter) WriteHeader(code int) {
-	w.ResponseWriter.WriteHeader(code)
-}
-
-func (w *bodyDumpResponseWriter) Write(b []byte) (int, error) {
-	return w.Writer.Write(b)
-}
-
-func (w *bodyDumpResponseWriter) Flush() {
-	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
-		flusher.Flush()
-	}
-}
-
-func (w *bodyDumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
-		return hijacker.Hijack()
 	}
-	return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "hijacking not supported")
 }
 
 // SecurityHeaders middleware adds security headers to all responses
@@ -147,7 +81,11 @@ func SecurityHeaders() echo.MiddlewareFunc {
 	}
 }
 
-// RequestID middleware adds a unique request ID to each request
+// RequestID middleware adds a unique request ID to each request. An ID sent
+// by the client or a proxy in X-Request-ID is kept so traces span both
+// sides; the ID is returned in the response header, in error bodies, and
+// carried in the request context to logs and audit entries. It must be the
+// first middleware, so even rejected requests get one.
 func RequestID() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -155,19 +93,56 @@ func RequestID() echo.MiddlewareFunc {
 			res := c.Response()
 			
 			rid := req.Header.Get(echo.HeaderXRequestID)
-			if rid == "" {
+			if !validRequestID(rid) {
 				rid = generateRequestID()
 			}
 			
 			res.Header().Set(echo.HeaderXRequestID, rid)
 			c.Set("request_id", rid)
+			c.SetRequest(req.WithContext(service.WithRequestID(req.Context(), rid)))
 			
 			return next(c)
 		}
 	}
 }
 
-// generateRequestID generates a simple request ID
+// ClientIP adds the client IP of a request to its context, so services can
+// count failed logins per client and record it in the audit log
+func ClientIP() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(user.WithClientIP(req.Context(), c.RealIP())))
+			return next(c)
+		}
+	}
+}
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// validRequestID reports whether a client-sent request ID can be used as is;
+// IDs end up in logs, so only short IDs of plain characters are kept
+func validRequestID(rid string) bool {
+	if rid == "" || len(rid) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range rid {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// generateRequestID generates a random request ID, unique across servers
 func generateRequestID() string {
-	return strconv.FormatInt(time.Now().UnixNano(), 36)
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
 }
@@ -1,15 +1,16 @@
 package middleware
 
 import (
+	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"golang.org/x/time/rate"
 
+	"blog-platform/internal/application/service"
 	"blog-platform/internal/infrastructure/config"
 	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/ratelimit"
 )
 
 // RateLimiterConfig holds configuration for rate limiting
@@ -22,6 +23,47 @@ type RateLimiterConfig struct {
 	SkipSuccessful bool
 	// KeyGenerator generates the key for rate limiting (default: IP address)
 	KeyGenerator func(c echo.Context) string
+	// AuthenticatedRequestsPerSecond and AuthenticatedBurstSize define the budget
+	// for requests with a user_id in the context (default: same as anonymous)
+	AuthenticatedRequestsPerSecond float64
+	AuthenticatedBurstSize         int
+	// Skipper skips rate limiting for requests it returns true for
+	Skipper func(c echo.Context) bool
+	// Name namespaces the keys of this limiter in a shared Store
+	Name string
+	// Store counts the requests (default: a new in-memory store)
+	Store ratelimit.Store
+	// Logger reports store failures; requests are let through when the store fails
+	Logger service.Logger
+}
+
+// UserKeyGenerator keys requests by the authenticated user_id when present and
+// by IP address otherwise, so signed-in users behind a shared IP get their own
+// budget
+func UserKeyGenerator(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(int); ok {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return "ip:" + c.RealIP()
+}
+
+// SkipProbes skips the health probes, which orchestrators send from the
+// same few addresses on a schedule
+func SkipProbes(c echo.Context) bool {
+	switch c.Path() {
+	case "/healthz", "/readyz", "/health":
+		return true
+	}
+	return false
+}
+
+// SkipReads skips safe methods so only write requests are counted
+func SkipReads(c echo.Context) bool {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
 }
 
 // DefaultRateLimiterConfig returns default configuration
@@ -48,93 +90,116 @@ func AuthRateLimiterConfig() RateLimiterConfig {
 	}
 }
 
-// rateLimiterEntry represents a rate limiter instance
-type rateLimiterEntry struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
-// cleanupExpiredLimiters removes expired rate limiters
-func cleanupExpiredLimiters(limiters map[string]*rateLimiterEntry, mu *sync.RWMutex) {
-	mu.Lock()
-	defer mu.Unlock()
-	
-	cutoff := time.Now().Add(-time.Hour)
-	for key, entry := range limiters {
-		if entry.lastSeen.Before(cutoff) {
-			delete(limiters, key)
-		}
-	}
+// RateLimiterMiddleware creates a rate limiting middleware with configuration.
+// Authenticated users are limited per user with their own budget; it must run
+// after AuthMiddleware.Identify for the user_id to be known.
+func RateLimiterMiddleware(cfg *config.Config, store ratelimit.Store, logger service.Logger) echo.MiddlewareFunc {
+	return RateLimiterWithConfig(RateLimiterConfig{
+		RequestsPerSecond:              cfg.RateLimit.DefaultRequestsPerSecond,
+		BurstSize:                      cfg.RateLimit.DefaultBurstSize,
+		AuthenticatedRequestsPerSecond: cfg.RateLimit.UserRequestsPerSecond,
+		AuthenticatedBurstSize:         cfg.RateLimit.UserBurstSize,
+		KeyGenerator:                   UserKeyGenerator,
+		Skipper:                        SkipProbes,
+		Name:                           "default",
+		Store:                          store,
+		Logger:                         logger,
+	})
 }
 
-// RateLimiterMiddleware creates a rate limiting middleware with configuration
-func RateLimiterMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+// AuthRateLimiterMiddleware creates a rate limiting middleware for auth endpoints
+func AuthRateLimiterMiddleware(cfg *config.Config, store ratelimit.Store, logger service.Logger) echo.MiddlewareFunc {
 	return RateLimiterWithConfig(RateLimiterConfig{
-		RequestsPerSecond: cfg.RateLimit.DefaultRequestsPerSecond,
-		BurstSize:         cfg.RateLimit.DefaultBurstSize,
+		RequestsPerSecond: cfg.RateLimit.AuthRequestsPerSecond,
+		BurstSize:         cfg.RateLimit.AuthBurstSize,
 		KeyGenerator: func(c echo.Context) string {
 			return c.RealIP()
 		},
+		Name:   "auth",
+		Store:  store,
+		Logger: logger,
 	})
 }
 
-// AuthRateLimiterMiddleware creates a rate limiting middleware for auth endpoints
-func AuthRateLimiterMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+// WriteRateLimiterMiddleware creates a rate limiting middleware that only counts
+// write requests, keyed by user when authenticated
+func WriteRateLimiterMiddleware(cfg *config.Config, store ratelimit.Store, logger service.Logger) echo.MiddlewareFunc {
 	return RateLimiterWithConfig(RateLimiterConfig{
-		RequestsPerSecond: cfg.RateLimit.AuthRequestsPerSecond,
-		BurstSize:         cfg.RateLimit.AuthBurstSize,
+		RequestsPerSecond: cfg.RateLimit.WriteRequestsPerSecond,
+		BurstSize:         cfg.RateLimit.WriteBurstSize,
+		KeyGenerator:      UserKeyGenerator,
+		Skipper:           SkipReads,
+		Name:              "write",
+		Store:             store,
+		Logger:            logger,
+	})
+}
+
+// BadgeRateLimiterMiddleware creates a rate limiting middleware for the stats
+// badges, keyed by IP since badges are fetched without credentials. Badges
+// are cached, so the budget only needs to cover a page embedding a few.
+func BadgeRateLimiterMiddleware(cfg *config.Config, store ratelimit.Store, logger service.Logger) echo.MiddlewareFunc {
+	return RateLimiterWithConfig(RateLimiterConfig{
+		RequestsPerSecond: cfg.RateLimit.BadgeRequestsPerSecond,
+		BurstSize:         cfg.RateLimit.BadgeBurstSize,
 		KeyGenerator: func(c echo.Context) string {
 			return c.RealIP()
 		},
+		Name:   "badge",
+		Store:  store,
+		Logger: logger,
 	})
 }
 
 // RateLimiterWithConfig creates a rate limiting middleware with custom config
 func RateLimiterWithConfig(config RateLimiterConfig) echo.MiddlewareFunc {
-	limiterMap := make(map[string]*rateLimiterEntry)
-	mu := sync.RWMutex{}
-
-	// Cleanup goroutine to remove expired limiters
-	go func() {
-		ticker := time.NewTicker(time.Minute)
-		defer ticker.Stop()
-		for range ticker.C {
-			cleanupExpiredLimiters(limiterMap, &mu)
-		}
-	}()
+	if config.Store == nil {
+		config.Store = ratelimit.NewMemoryStore()
+	}
+	if config.AuthenticatedRequestsPerSecond == 0 {
+		config.AuthenticatedRequestsPerSecond = config.RequestsPerSecond
+	}
+	if config.AuthenticatedBurstSize == 0 {
+		config.AuthenticatedBurstSize = config.BurstSize
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			clientIP := config.KeyGenerator(c)
-
-			mu.Lock()
-			entry, exists := limiterMap[clientIP]
-			if !exists || time.Since(entry.lastSeen) > time.Hour {
-				entry = &rateLimiterEntry{
-					limiter:  rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.BurstSize),
-					lastSeen: time.Now(),
+			if config.Skipper != nil && config.Skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := config.KeyGenerator(c)
+			if config.Name != "" {
+				key = config.Name + ":" + key
+			}
+
+			limit := ratelimit.Limit{RequestsPerSecond: config.RequestsPerSecond, Burst: config.BurstSize}
+			if _, ok := c.Get("user_id").(int); ok {
+				limit = ratelimit.Limit{RequestsPerSecond: config.AuthenticatedRequestsPerSecond, Burst: config.AuthenticatedBurstSize}
+			}
+
+			result, err := config.Store.Allow(ctx, key, limit)
+			if err != nil {
+				// Fail open: an unavailable store must not take the API down
+				if config.Logger != nil {
+					config.Logger.Error(ctx, "rate limit store failed", "error", err.Error())
 				}
-				limiterMap[clientIP] = entry
-			} else {
-				entry.lastSeen = time.Now()
+				return next(c)
 			}
-			mu.Unlock()
 
-			if !entry.limiter.Allow() {
-				// Set rate limit headers
-				c.Response().Header().Set("X-RateLimit-Limit", strconv.FormatFloat(config.RequestsPerSecond, 'f', -1, 64))
-				c.Response().Header().Set("X-RateLimit-Remaining", "0")
-				c.Response().Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+			c.Response().Header().Set("X-RateLimit-Limit", strconv.FormatFloat(limit.RequestsPerSecond, 'f', -1, 64))
+			c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+			if !result.Allowed {
+				retryAfter := max(result.RetryAfter, time.Second)
+				c.Response().Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(retryAfter).Unix(), 10))
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
 
 				return errors.HandleError(c, errors.ErrTooManyRequests)
 			}
 
-			// Set rate limit headers for successful requests
-			c.Response().Header().Set("X-RateLimit-Limit", strconv.FormatFloat(config.RequestsPerSecond, 'f', -1, 64))
-			// Note: Getting exact remaining tokens from rate.Limiter is not straightforward
-			// This is an approximation
-			c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(config.BurstSize-1))
-
 			return next(c)
 		}
 	}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// SanitizePolicy names how much formatting a request field may hold. Fields
+// choose their policy with a sanitize struct tag, e.g. sanitize:"strict",
+// and SanitizeFields applies it; fields without the tag are left as sent.
+type SanitizePolicy string
+
+const (
+	// PolicyStrict keeps plain text on a single line, for names, titles,
+	// emails and other short values: control characters are removed and
+	// runs of whitespace, line breaks included, become a single space
+	PolicyStrict SanitizePolicy = "strict"
+	// PolicyBasic keeps paragraphs of text with the basic formatting subset
+	// of Markdown (inline code, strong, emphasis and links), for comments
+	// and other short bodies: line breaks are normalized, control characters
+	// other than line breaks and tabs are removed and trailing spaces are
+	// trimmed from each line
+	PolicyBasic SanitizePolicy = "basic"
+	// PolicyMarkdown keeps full Markdown, for post bodies: line breaks are
+	// normalized and null bytes removed, leaving indentation and trailing
+	// spaces, which are meaningful in Markdown, untouched
+	PolicyMarkdown SanitizePolicy = "markdown"
+)
+
+// sanitizeTag is the struct tag naming the policy of a field
+const sanitizeTag = "sanitize"
+
+// Sanitize cleans input according to a policy. Every policy removes null
+// bytes and surrounding whitespace, as SanitizeInput does; unknown policies
+// are treated as strict.
+func Sanitize(input string, policy SanitizePolicy) string {
+	switch policy {
+	case PolicyMarkdown:
+		return SanitizeInput(normalizeLineBreaks(input))
+	case PolicyBasic:
+		lines := strings.Split(normalizeLineBreaks(input), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRightFunc(strings.Map(func(r rune) rune {
+				if r != '\t' && unicode.IsControl(r) {
+					return -1
+				}
+				return r
+			}, line), unicode.IsSpace)
+		}
+		return SanitizeInput(strings.Join(lines, "\n"))
+	default:
+		return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, input)), " ")
+	}
+}
+
+// SanitizeFields applies the sanitize policies of the string fields of the
+// struct req points to, in place
+func SanitizeFields(req any) {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		policy, ok := field.Tag.Lookup(sanitizeTag)
+		if !ok || field.Type.Kind() != reflect.String || !v.Field(i).CanSet() {
+			continue
+		}
+		v.Field(i).SetString(Sanitize(v.Field(i).String(), SanitizePolicy(policy)))
+	}
+}
+
+// normalizeLineBreaks turns Windows and old Mac line breaks into newlines
+func normalizeLineBreaks(input string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(input, "\r\n", "\n"), "\r", "\n")
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/scim"
+)
+
+// SCIMTenantKey is the context key of the tenant of a SCIM request
+const SCIMTenantKey = "scim_tenant"
+
+// SCIMAuth authenticates SCIM clients by the bearer token of their tenant
+// and sets the tenant in the context. Failures are answered with SCIM error
+// responses, as identity providers expect.
+func SCIMAuth(tenants scim.Tenants, logger service.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+				if tenant, ok := tenants.Authenticate(parts[1]); ok {
+					c.Set(SCIMTenantKey, tenant)
+					return next(c)
+				}
+			}
+
+			logger.Warn(c.Request().Context(), "SCIM request with invalid token", "ip", c.RealIP())
+			c.Response().Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			return c.JSONBlob(http.StatusUnauthorized, []byte(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"401","detail":"Invalid bearer token"}`))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// multipartOverhead is the room left in the request body for the multipart
+// boundaries and headers around the uploaded file
+const multipartOverhead = 64 << 10
+
+// sniffLength is the number of bytes content types are detected from
+const sniffLength = 512
+
+// UploadPolicy describes the file a multipart upload must carry
+type UploadPolicy struct {
+	// Field is the name of the form field holding the file
+	Field string
+	// MaxSize caps the size of the file in bytes
+	MaxSize int64
+	// Types lists the content types accepted, detected from the content of
+	// the file rather than trusted from the client
+	Types []string
+}
+
+// BodyLimit returns the size of the largest request body carrying an
+// upload the policy accepts
+func (p UploadPolicy) BodyLimit() int64 {
+	return p.MaxSize + multipartOverhead
+}
+
+// ValidateUpload checks multipart uploads before the handler reads them:
+// the request must be multipart/form-data, the file no larger than the
+// policy allows and its content one of the accepted types. The body is cut
+// off past the limit, so oversized uploads are never read in full.
+func ValidateUpload(policy UploadPolicy, logger service.Logger) echo.MiddlewareFunc {
+	errMultipart := errors.NewAPIError(errors.ErrCodeUnsupportedMediaType, "Upload must be sent as multipart/form-data", http.StatusUnsupportedMediaType)
+	errMissing := errors.NewAPIError(errors.ErrCodeValidation, fmt.Sprintf("%s file is required", policy.Field), http.StatusBadRequest)
+	errTooLarge := errors.NewAPIError(errors.ErrCodePayloadTooLarge, fmt.Sprintf("%s file must be at most %d bytes", policy.Field, policy.MaxSize), http.StatusRequestEntityTooLarge)
+	errType := errors.NewAPIError(errors.ErrCodeUnsupportedMediaType, fmt.Sprintf("%s file type is not accepted", policy.Field), http.StatusUnsupportedMediaType)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+			if err != nil || mediaType != echo.MIMEMultipartForm {
+				return errors.HandleError(c, errMultipart)
+			}
+			if req.ContentLength > policy.BodyLimit() {
+				logger.Warn(ctx, "upload rejected", "reason", "too large", "size", req.ContentLength)
+				return errors.HandleError(c, errTooLarge)
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, policy.BodyLimit())
+
+			header, err := c.FormFile(policy.Field)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if stderrors.As(err, &maxBytesErr) {
+					logger.Warn(ctx, "upload rejected", "reason", "too large")
+					return errors.HandleError(c, errTooLarge)
+				}
+				return errors.HandleError(c, errMissing)
+			}
+			if header.Size > policy.MaxSize {
+				logger.Warn(ctx, "upload rejected", "reason", "too large", "size", header.Size)
+				return errors.HandleError(c, errTooLarge)
+			}
+
+			file, err := header.Open()
+			if err != nil {
+				return errors.HandleError(c, errMissing)
+			}
+			head := make([]byte, sniffLength)
+			n, err := io.ReadFull(file, head)
+			file.Close()
+			if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+				return errors.HandleError(c, errMissing)
+			}
+			if contentType := http.DetectContentType(head[:n]); !slices.Contains(policy.Types, contentType) {
+				logger.Warn(ctx, "upload rejected", "reason", "type", "contentType", contentType)
+				return errors.HandleError(c, errType)
+			}
+
+			return next(c)
+		}
+	}
+}
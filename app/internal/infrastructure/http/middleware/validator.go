@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"blog-platform/internal/domain/clientid"
 )
 
 // CustomValidator wraps the validator instance with custom validation rules
@@ -20,6 +22,7 @@ func NewValidator() *CustomValidator {
 	v.RegisterValidation("strong_password", validateStrongPassword)
 	v.RegisterValidation("no_html", validateNoHTML)
 	v.RegisterValidation("safe_string", validateSafeString)
+	v.RegisterValidation("client_id", validateClientID)
 	
 	return &CustomValidator{validator: v}
 }
@@ -63,6 +66,11 @@ func validateSafeString(fl validator.FieldLevel) bool {
 	return safePattern.MatchString(value)
 }
 
+// validateClientID validates that the field is a UUID, as client IDs are
+func validateClientID(fl validator.FieldLevel) bool {
+	return clientid.Valid(fl.Field().String())
+}
+
 // SanitizeInput removes potentially dangerous characters from input
 func SanitizeInput(input string) string {
 	// Remove null bytes
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/widget"
+	"blog-platform/internal/infrastructure/http/errors"
+)
+
+// WidgetPathPrefix is the path below which the comments widget endpoints
+// are served; the site-wide CORS rules do not apply to it
+const WidgetPathPrefix = "/api/v1/widget/"
+
+// WidgetSiteKey is the context key of the embedding site of a widget request
+const WidgetSiteKey = "widget_site"
+
+// WidgetTokenParam is the query parameter carrying the public token of the
+// embedding site. A query parameter rather than a header lets iframes and
+// CORS preflight requests, which carry no custom headers, send it too.
+const WidgetTokenParam = "token"
+
+var (
+	errUnknownWidgetSite = errors.NewAPIError(errors.ErrCodeForbidden, "Unknown widget site", http.StatusForbidden)
+	errWidgetOrigin      = errors.NewAPIError(errors.ErrCodeForbidden, "Origin not allowed for this widget site", http.StatusForbidden)
+)
+
+// WidgetCORS scopes the widget endpoints to the sites embedding them. The
+// token selects the site and only its origins receive CORS headers; writes
+// must come from one of them. Widget requests send bearer tokens rather
+// than cookies, so credentials are never allowed.
+func WidgetCORS(sites widget.Sites, logger service.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !IsWidgetRequest(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			ctx := req.Context()
+			site, ok := sites.Lookup(c.QueryParam(WidgetTokenParam))
+			if !ok {
+				logger.Warn(ctx, "widget request with unknown site token", "ip", c.RealIP())
+				return errors.HandleError(c, errUnknownWidgetSite)
+			}
+
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderOrigin)
+			origin := req.Header.Get(echo.HeaderOrigin)
+			switch {
+			case origin != "":
+				if !site.AllowsOrigin(origin) {
+					logger.Warn(ctx, "widget request from origin not allowed", "origin", origin)
+					return errors.HandleError(c, errWidgetOrigin)
+				}
+				res.Header().Set(echo.HeaderAccessControlAllowOrigin, origin)
+				res.Header().Set(echo.HeaderAccessControlExposeHeaders, "X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset")
+			case req.Method != http.MethodGet && req.Method != http.MethodHead:
+				// Browsers send the origin of every write; scripts posting
+				// from elsewhere cannot claim to be the site
+				logger.Warn(ctx, "widget write without origin", "method", req.Method)
+				return errors.HandleError(c, errWidgetOrigin)
+			}
+
+			if req.Method == http.MethodOptions {
+				res.Header().Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+				res.Header().Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+				res.Header().Set(echo.HeaderAccessControlAllowMethods, "GET, POST")
+				res.Header().Set(echo.HeaderAccessControlAllowHeaders, "Content-Type, Authorization")
+				res.Header().Set(echo.HeaderAccessControlMaxAge, "86400")
+				return c.NoContent(http.StatusNoContent)
+			}
+
+			c.Set(WidgetSiteKey, site)
+			return next(c)
+		}
+	}
+}
+
+// IsWidgetRequest reports whether a request is for the widget endpoints
+func IsWidgetRequest(c echo.Context) bool {
+	return strings.HasPrefix(c.Request().URL.Path, WidgetPathPrefix)
+}
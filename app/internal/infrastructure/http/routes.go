@@ -1,80 +1,607 @@
 package http
 
 import (
+	"context"
+	"maps"
+	"strings"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
-	_ "blog-platform/docs"
+	"blog-platform/docs"
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/announcement"
+	"blog-platform/internal/domain/banner"
+	"blog-platform/internal/domain/audit"
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/change"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/idempotency"
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/domain/media"
+	"blog-platform/internal/domain/notification"
 	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/scim"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/tip"
 	"blog-platform/internal/domain/user"
+	"blog-platform/internal/domain/webhook"
+	"blog-platform/internal/domain/widget"
+	infraauth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/badge"
+	"blog-platform/internal/infrastructure/auth/oauth"
 	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/diagnostics"
+	"blog-platform/internal/infrastructure/feed"
+	"blog-platform/internal/infrastructure/health"
+	"blog-platform/internal/infrastructure/http/errors"
 	"blog-platform/internal/infrastructure/http/handlers"
 	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/http/views"
+	"blog-platform/internal/infrastructure/ratelimit"
+	"blog-platform/internal/infrastructure/realtime"
+	"blog-platform/internal/infrastructure/storage"
 )
 
 // SetupRoutes configures all the routes for the application
-func SetupRoutes(e *echo.Echo, cfg *config.Config, userService user.Service, authService auth.AuthService, postService post.Service, commentService comment.Service, logger service.Logger) {
+func SetupRoutes(e *echo.Echo, cfg *config.Config, pagination service.PaginationPolicy, userService user.Service, authService auth.AuthService, passkeyService auth.PasskeyService, scimService scim.Service, ssoService sso.Service, postService post.Service, progressService post.ProgressService, bookmarkService post.BookmarkService, tagService tag.Service, seriesService series.Service, preferenceService preference.Service, commentService comment.Service, reportService comment.ReportService, notificationService notification.Service, realtimeHub *realtime.Hub, followService user.FollowService, profileService user.ProfileService, avatarService user.AvatarService, mediaService media.Service, exportService export.Service, redirectService post.RedirectService, syncService change.Service, idempotencyService idempotency.Service, announcementService announcement.Service, importService imports.Service, bannerService banner.Service, webhookService webhook.Service, auditService audit.Service, securityService user.SecurityService, billingService billing.Service, webhooks billing.WebhookVerifier, tipService tip.Service, payments tip.PaymentProvider, jwks infraauth.JWKSet, rateLimits ratelimit.Store, checks *health.Checker, diag *diagnostics.Collector, logger service.Logger) {
 	// Set up validator
 	e.Validator = middleware.NewValidator()
 	
-	// Apply CORS middleware with config
+	// Answer unknown routes and panics in the standard error format
+	e.HTTPErrorHandler = errors.HTTPErrorHandler
+	
+	// Tag every request with an ID first, so even requests rejected by
+	// later middleware return one
+	e.Use(middleware.RequestID())
+	
+	// Log every request once answered, rejected ones included
+	e.Use(middleware.AccessLogger(logger))
+	
+	// Reject oversized request bodies before anything reads them; the
+	// upload routes allow their files instead
+	avatarPolicy := middleware.UploadPolicy{
+		Field:   handlers.AvatarField,
+		MaxSize: int64(cfg.Storage.MaxAvatarSize),
+		Types:   handlers.AvatarTypes,
+	}
+	mediaPolicy := middleware.UploadPolicy{
+		Field:   handlers.MediaField,
+		MaxSize: int64(cfg.Storage.MaxMediaSize),
+		Types:   handlers.MediaTypes,
+	}
+	importPolicy := middleware.UploadPolicy{
+		Field:   handlers.ImportField,
+		MaxSize: int64(cfg.Import.MaxSize),
+		Types:   handlers.ImportTypes,
+	}
+	bodyLimits := middleware.BodyLimitPolicy{Default: cfg.BodyLimit.Default, Prefixes: maps.Clone(cfg.BodyLimit.Groups)}
+	if bodyLimits.Prefixes == nil {
+		bodyLimits.Prefixes = make(map[string]int64)
+	}
+	bodyLimits.Prefixes["/api/v1/users/me/avatar"] = avatarPolicy.BodyLimit()
+	bodyLimits.Prefixes["/api/v1/media"] = mediaPolicy.BodyLimit()
+	bodyLimits.Prefixes["/api/v1/admin/import"] = importPolicy.BodyLimit()
+	e.Use(middleware.BodyLimit(bodyLimits, logger))
+	
+	// Apply CORS middleware with config; the widget endpoints answer the
+	// origins of the sites embedding them instead
+	if cfg.Widget.Enabled {
+		e.Use(middleware.WidgetCORS(widget.NewRegistry(cfg.Widget.Sites), logger))
+	}
 	e.Use(middleware.CORS(cfg))
 	
 	// Apply compression middleware
 	e.Use(middleware.Compression(cfg))
 	
+	// Auth middleware for protected routes
+	authMiddleware := middleware.NewAuthMiddleware(authService, logger)
+	
+	// Identify signed-in users so they are rate limited per user
+	e.Use(authMiddleware.Identify)
+	
 	// Apply rate limiting middleware
-	e.Use(middleware.RateLimiterMiddleware(cfg))
+	e.Use(middleware.RateLimiterMiddleware(cfg, rateLimits, logger))
+	e.Use(middleware.WriteRateLimiterMiddleware(cfg, rateLimits, logger)) // Apply stricter rate limiting to write requests
 	
 	// Apply other middleware
 	e.Use(middleware.SecurityHeaders())
-	e.Use(middleware.RequestID())
-	e.Use(middleware.RequestResponseLogger(logger))
+	e.Use(middleware.ClientIP())
 	
 	// API v1 group
-	v1 := e.Group("/api/v1")
+	v1 := e.Group(handlers.APIv1.Prefix())
 	
-	// Health check
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{
-			"status": "ok",
-			"service": "blog-platform",
-		})
-	})
+	// Liveness and readiness probes; /health is kept for older probes
+	healthHandler := handlers.NewHealthHandler(checks, logger)
+	e.GET("/healthz", healthHandler.Liveness)  // GET /healthz
+	e.GET("/health", healthHandler.Liveness)   // GET /health (same as /healthz)
+	e.GET("/readyz", healthHandler.Readiness) // GET /readyz
 	
 	// Auth handlers
 	authHandler := handlers.NewAuthHandler(userService, authService, logger)
 	
+	// Social login handlers for the providers configured in cfg.OAuth
+	oauthHandler := handlers.NewOAuthHandler(
+		authService,
+		oauth.NewRegistryFromConfig(cfg),
+		oauth.NewStateSigner(cfg.JWT.Secret, time.Duration(cfg.OAuth.StateTTL)*time.Minute),
+		handlers.OAuthSettings{BaseURL: cfg.Server.BaseURL},
+		logger,
+	)
+	
+	// Public token signing keys
+	jwksHandler := handlers.NewJWKSHandler(jwks, logger)
+	
+	// Two-factor enrollment and second login step handlers
+	twoFactorHandler := handlers.NewTwoFactorHandler(authService, logger)
+	
+	// WebAuthn ceremony and passkey handlers; passkeyService is nil unless
+	// cfg.WebAuthn is enabled
+	passkeyHandler := handlers.NewPasskeyHandler(passkeyService, authService, logger)
+	
+	// SCIM provisioning handlers; scimService is nil unless cfg.SCIM is enabled
+	scimHandler := handlers.NewSCIMHandler(scimService, handlers.SCIMSettings{BaseURL: cfg.Server.BaseURL}, logger)
+	
+	// Single sign-on handlers; ssoService is nil unless cfg.SSO is enabled
+	ssoHandler := handlers.NewSSOHandler(
+		authService,
+		ssoService,
+		oauth.NewStateSigner(cfg.JWT.Secret, time.Duration(cfg.SSO.StateTTL)*time.Minute),
+		handlers.SSOSettings{BaseURL: cfg.Server.BaseURL},
+		logger,
+	)
+	
 	// Post handlers
-	postHandler := handlers.NewPostHandler(postService, logger)
+	postHandler := handlers.NewPostHandler(postService, progressService, profileService, redirectService, pagination.Posts, logger)
+	
+	// Reading progress handlers
+	progressHandler := handlers.NewProgressHandler(progressService, pagination.Posts, logger)
+	
+	// Bookmark handlers
+	bookmarkHandler := handlers.NewBookmarkHandler(bookmarkService, pagination.Posts, logger)
+	
+	// Tag handlers
+	tagHandler := handlers.NewTagHandler(tagService, logger)
+	
+	// Post series handlers
+	seriesHandler := handlers.NewSeriesHandler(seriesService, logger)
 	
 	// Comment handlers
-	commentHandler := handlers.NewCommentHandler(commentService, logger)
+	commentHandler := handlers.NewCommentHandler(commentService, pagination.Comments, logger)
+	commentReportHandler := handlers.NewCommentReportHandler(reportService, logger)
 	
-	// Auth middleware for protected routes
-	authMiddleware := middleware.NewAuthMiddleware(authService, logger)
+	// Notification handlers
+	notificationHandler := handlers.NewNotificationHandler(notificationService, pagination.Comments, logger)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeHub, logger)
+	
+	// Follow handlers
+	followHandler := handlers.NewFollowHandler(followService, pagination.Users, logger)
+	
+	// Public profile handlers
+	profileHandler := handlers.NewProfileHandler(profileService, logger)
+	
+	// Avatar upload handlers
+	avatarHandler := handlers.NewAvatarHandler(avatarService, logger)
+	avatarUpload := middleware.ValidateUpload(avatarPolicy, logger)
+	
+	// Post image upload handlers
+	mediaHandler := handlers.NewMediaHandler(mediaService, logger)
+	mediaUpload := middleware.ValidateUpload(mediaPolicy, logger)
+	
+	// Account data export handlers
+	exportHandler := handlers.NewExportHandler(exportService, logger)
+	
+	// Slug redirect handlers
+	redirectHandler := handlers.NewRedirectHandler(redirectService, pagination.Posts, logger)
+	
+	// Offline sync handlers
+	syncHandler := handlers.NewSyncHandler(syncService, postService, pagination.Posts, logger)
+	
+	// Comments widget handlers for external sites
+	widgetHandler := handlers.NewWidgetHandler(postService, commentService, pagination.Comments, handlers.WidgetSettings{
+		SiteName: cfg.ReadingView.SiteName,
+	}, logger)
+	
+	// Post and comment feed handlers
+	feedGenerator := feed.NewGenerator(postService, commentService, userService, preferenceService, feed.Settings{
+		SiteName:  cfg.ReadingView.SiteName,
+		BaseURL:   cfg.Server.BaseURL,
+		ItemLimit: cfg.Feed.ItemLimit,
+		CacheTTL:  time.Duration(cfg.Feed.CacheTTL) * time.Second,
+	})
+	feedHandler := handlers.NewFeedHandler(feedGenerator, preferenceService, logger)
+	diag.Register("feed_cache", func(ctx context.Context) (any, error) {
+		return feedGenerator.CacheStats(), nil
+	})
+	
+	// Embeddable stats badges
+	badgeRenderer := badge.NewRenderer(postService, badge.Settings{
+		CacheTTL: time.Duration(cfg.Badge.CacheTTL) * time.Second,
+	})
+	badgeHandler := handlers.NewBadgeHandler(badgeRenderer, logger)
+	diag.Register("badge_cache", func(ctx context.Context) (any, error) {
+		return badgeRenderer.CacheStats(), nil
+	})
+	
+	// User handlers
+	userHandler := handlers.NewUserHandler(userService, logger)
+	
+	// Security notification link handlers
+	securityHandler := handlers.NewSecurityHandler(securityService, logger)
+	
+	// Subscription and Stripe webhook handlers; billingService and webhooks
+	// are nil unless cfg.Billing is enabled
+	billingHandler := handlers.NewBillingHandler(billingService, webhooks, logger)
+	
+	// Tip, payout account and payment webhook handlers; tipService and
+	// payments are nil unless cfg.Tips is enabled
+	tipHandler := handlers.NewTipHandler(tipService, payments, logger)
+	
+	// Announcement email handlers
+	announcementHandler := handlers.NewAnnouncementHandler(announcementService, logger)
+	
+	// Content import handlers
+	importHandler := handlers.NewImportHandler(importService, logger)
+	importUpload := middleware.ValidateUpload(importPolicy, logger)
+	
+	// Site-wide banner handlers
+	bannerHandler := handlers.NewBannerHandler(bannerService, logger)
+	
+	// Outbound webhook handlers
+	webhookHandler := handlers.NewWebhookHandler(webhookService, pagination.AuditLogs, logger)
+	
+	// Audit log handlers
+	auditHandler := handlers.NewAuditHandler(auditService, pagination.AuditLogs, logger)
+	
+	// Runtime diagnostics handlers
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(diag, logger)
+	
+	// robots.txt handler
+	robotsHandler := handlers.NewRobotsHandler(handlers.RobotsSettings{
+		Disallow:     cfg.Robots.Disallow,
+		HostDisallow: cfg.Robots.HostDisallow,
+		IndexNowKey:  cfg.SearchPing.IndexNowKey,
+	}, logger)
+	
+	// Route documentation registry backing the error catalog and OpenAPI examples
+	routeDocs := handlers.NewRouteRegistry()
+	docsHandler := handlers.NewDocsHandler(routeDocs, docs.OpenAPI, logger)
+	routeDocs.Register(authHandler.RouteDocs()...)
+	routeDocs.Register(oauthHandler.RouteDocs()...)
+	routeDocs.Register(twoFactorHandler.RouteDocs()...)
+	routeDocs.Register(passkeyHandler.RouteDocs()...)
+	routeDocs.Register(ssoHandler.RouteDocs()...)
+	routeDocs.Register(postHandler.RouteDocs()...)
+	routeDocs.Register(progressHandler.RouteDocs()...)
+	routeDocs.Register(bookmarkHandler.RouteDocs()...)
+	routeDocs.Register(tagHandler.RouteDocs()...)
+	routeDocs.Register(seriesHandler.RouteDocs()...)
+	routeDocs.Register(commentHandler.RouteDocs()...)
+	routeDocs.Register(commentReportHandler.RouteDocs()...)
+	routeDocs.Register(notificationHandler.RouteDocs()...)
+	routeDocs.Register(realtimeHandler.RouteDocs()...)
+	routeDocs.Register(followHandler.RouteDocs()...)
+	routeDocs.Register(profileHandler.RouteDocs()...)
+	routeDocs.Register(avatarHandler.RouteDocs()...)
+	routeDocs.Register(mediaHandler.RouteDocs()...)
+	routeDocs.Register(redirectHandler.RouteDocs()...)
+	routeDocs.Register(syncHandler.RouteDocs()...)
+	routeDocs.Register(widgetHandler.RouteDocs()...)
+	routeDocs.Register(feedHandler.RouteDocs()...)
+	routeDocs.Register(userHandler.RouteDocs()...)
+	routeDocs.Register(exportHandler.RouteDocs()...)
+	routeDocs.Register(securityHandler.RouteDocs()...)
+	routeDocs.Register(billingHandler.RouteDocs()...)
+	routeDocs.Register(tipHandler.RouteDocs()...)
+	routeDocs.Register(announcementHandler.RouteDocs()...)
+	routeDocs.Register(importHandler.RouteDocs()...)
+	routeDocs.Register(bannerHandler.RouteDocs()...)
+	routeDocs.Register(webhookHandler.RouteDocs()...)
+	routeDocs.Register(auditHandler.RouteDocs()...)
+	routeDocs.Register(diagnosticsHandler.RouteDocs()...)
+	routeDocs.Register(docsHandler.RouteDocs()...)
+	
+	// Creating writes accept an Idempotency-Key, so retries return the
+	// response of the first request instead of creating duplicates.
+	// Registration responses hold tokens, which are not stored; retries sign
+	// the registered user in again instead.
+	idempotent := middleware.Idempotency(idempotencyService)
 	
 	// Auth routes with stricter rate limiting
 	auth := v1.Group("/auth")
-	auth.Use(middleware.AuthRateLimiterMiddleware(cfg)) // Apply stricter rate limiting to auth endpoints
-	auth.POST("/register", authHandler.Register)
+	auth.Use(middleware.AuthRateLimiterMiddleware(cfg, rateLimits, logger)) // Apply stricter rate limiting to auth endpoints
+	auth.POST("/register", authHandler.Register, middleware.IdempotencyWithReplay(idempotencyService, authHandler.ReplayRegister))
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/login/2fa", twoFactorHandler.Login) // POST /api/v1/auth/login/2fa
+	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/logout", authHandler.Logout)
+	auth.GET("/oauth/:provider", oauthHandler.Start)             // GET /api/v1/auth/oauth/{provider}
+	auth.GET("/oauth/:provider/callback", oauthHandler.Callback) // GET /api/v1/auth/oauth/{provider}/callback
+	if cfg.MagicLink.Enabled {
+		auth.POST("/magic-link", authHandler.RequestMagicLink)       // POST /api/v1/auth/magic-link
+		auth.GET("/magic-link/verify", authHandler.VerifyMagicLink) // GET /api/v1/auth/magic-link/verify
+	}
+	if cfg.WebAuthn.Enabled {
+		auth.POST("/webauthn/signup/begin", passkeyHandler.BeginSignup)                                    // POST /api/v1/auth/webauthn/signup/begin
+		auth.POST("/webauthn/signup/finish", passkeyHandler.FinishSignup)                                  // POST /api/v1/auth/webauthn/signup/finish
+		auth.POST("/webauthn/login/begin", passkeyHandler.BeginLogin)                                      // POST /api/v1/auth/webauthn/login/begin
+		auth.POST("/webauthn/login/finish", passkeyHandler.FinishLogin)                                    // POST /api/v1/auth/webauthn/login/finish
+		auth.POST("/webauthn/2fa/begin", passkeyHandler.BeginTwoFactor)                                    // POST /api/v1/auth/webauthn/2fa/begin
+		auth.POST("/webauthn/2fa/finish", passkeyHandler.FinishTwoFactor)                                  // POST /api/v1/auth/webauthn/2fa/finish
+		auth.POST("/webauthn/register/begin", passkeyHandler.BeginRegistration, authMiddleware.RequireAuth)   // POST /api/v1/auth/webauthn/register/begin (protected)
+		auth.POST("/webauthn/register/finish", passkeyHandler.FinishRegistration, authMiddleware.RequireAuth) // POST /api/v1/auth/webauthn/register/finish (protected)
+	}
+	if cfg.SSO.Enabled {
+		auth.GET("/sso", ssoHandler.Start)                             // GET /api/v1/auth/sso
+		auth.GET("/sso/:organization/callback", ssoHandler.Callback)  // GET /api/v1/auth/sso/{organization}/callback (OpenID Connect)
+		auth.POST("/sso/:organization/acs", ssoHandler.ACS)           // POST /api/v1/auth/sso/{organization}/acs (SAML)
+		auth.GET("/sso/:organization/metadata", ssoHandler.Metadata)  // GET /api/v1/auth/sso/{organization}/metadata (SAML)
+	}
 	
 	// Posts routes
-	posts := v1.Group("/posts")
+	v1.GET("/feed", postHandler.Feed, authMiddleware.RequireAuth) // GET /api/v1/feed (posts of followed authors, protected)
+	v1.GET("/sync", syncHandler.Sync)                             // GET /api/v1/sync (changes since ?since=, for offline clients)
+	v1.POST("/media", mediaHandler.UploadMedia, authMiddleware.RequireAuth, mediaUpload) // POST /api/v1/media (protected, multipart)
+	
+	// Posts and comments created offline can be addressed by their client ID
+	posts := v1.Group("/posts", middleware.PostClientIDs(postService, logger))
+	commentRef := middleware.CommentClientIDs(commentService, logger)
 	posts.GET("", postHandler.ListPosts)                                    // GET /api/v1/posts
+	posts.GET("/popular", postHandler.ListPopularPosts)                     // GET /api/v1/posts/popular
+	posts.GET("/trending", postHandler.ListTrendingPosts)                   // GET /api/v1/posts/trending
+	posts.GET("/featured", postHandler.ListFeaturedPosts)                   // GET /api/v1/posts/featured
+	posts.GET("/calendar", postHandler.Calendar, authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin)) // GET /api/v1/posts/calendar (authors and admins)
 	posts.GET("/:id", postHandler.GetPost)                                  // GET /api/v1/posts/{id}
-	posts.POST("", postHandler.CreatePost, authMiddleware.RequireAuth)      // POST /api/v1/posts (protected)
+	posts.GET("/:id/related", postHandler.ListRelatedPosts)                 // GET /api/v1/posts/{id}/related
+	posts.GET("/slug/:slug", postHandler.GetPostBySlug)                     // GET /api/v1/posts/slug/{slug} (old slugs redirect)
+	posts.POST("", postHandler.CreatePost, authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin), idempotent) // POST /api/v1/posts (authors and admins)
 	posts.PUT("/:id", postHandler.UpdatePost, authMiddleware.RequireAuth)   // PUT /api/v1/posts/{id} (protected)
 	posts.DELETE("/:id", postHandler.DeletePost, authMiddleware.RequireAuth) // DELETE /api/v1/posts/{id} (protected)
+	posts.PUT("/:id/indexing", postHandler.SetPostIndexing, authMiddleware.RequireAuth) // PUT /api/v1/posts/{id}/indexing (protected)
+	posts.PUT("/:id/slug", postHandler.ChangePostSlug, authMiddleware.RequireAuth)      // PUT /api/v1/posts/{id}/slug (protected)
+	posts.PUT("/:id/access", postHandler.SetPostAccess, authMiddleware.RequireAuth)     // PUT /api/v1/posts/{id}/access (protected)
+	posts.PUT("/:id/visibility", postHandler.SetPostVisibility, authMiddleware.RequireAuth)     // PUT /api/v1/posts/{id}/visibility (protected)
+	posts.PUT("/:id/progress", progressHandler.SaveProgress, authMiddleware.RequireAuth) // PUT /api/v1/posts/{id}/progress (protected)
+	posts.POST("/:id/bookmark", bookmarkHandler.Bookmark, authMiddleware.RequireAuth)     // POST /api/v1/posts/{id}/bookmark (protected)
+	posts.DELETE("/:id/bookmark", bookmarkHandler.Unbookmark, authMiddleware.RequireAuth) // DELETE /api/v1/posts/{id}/bookmark (protected)
+	posts.POST("/:id/publish", postHandler.PublishPost, authMiddleware.RequireAuth)    // POST /api/v1/posts/{id}/publish (protected)
+	posts.POST("/:id/schedule", postHandler.SchedulePost, authMiddleware.RequireAuth)  // POST /api/v1/posts/{id}/schedule (protected)
+	posts.GET("/:id/revisions", postHandler.ListRevisions, authMiddleware.RequireAuth)  // GET /api/v1/posts/{id}/revisions (protected)
+	posts.POST("/:id/revisions/:rev/restore", postHandler.RestoreRevision, authMiddleware.RequireAuth) // POST /api/v1/posts/{id}/revisions/{rev}/restore (author only)
+	posts.POST("/:id/translations", postHandler.TranslatePost, authMiddleware.RequireAuth) // POST /api/v1/posts/{id}/translations (protected)
+	
+	// API v2 posts, served by the v1 handlers with typed timestamps and
+	// response envelopes; v1 keeps its contracts
+	v2 := e.Group(handlers.APIv2.Prefix())
+	postHandlerV2 := postHandler.WithMapper(handlers.V2Mapper{})
+	postsV2 := v2.Group("/posts", middleware.PostClientIDs(postService, logger))
+	postsV2.GET("", postHandlerV2.ListPosts)                                  // GET /api/v2/posts
+	postsV2.GET("/:id", postHandlerV2.GetPost)                                // GET /api/v2/posts/{id}
+	postsV2.GET("/slug/:slug", postHandlerV2.GetPostBySlug)                   // GET /api/v2/posts/slug/{slug} (old slugs redirect)
+	postsV2.POST("", postHandlerV2.CreatePost, authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin), idempotent) // POST /api/v2/posts (authors and admins)
+	postsV2.PUT("/:id", postHandlerV2.UpdatePost, authMiddleware.RequireAuth)     // PUT /api/v2/posts/{id} (protected)
+	postsV2.DELETE("/:id", postHandlerV2.DeletePost, authMiddleware.RequireAuth)  // DELETE /api/v2/posts/{id} (protected)
+	postsV2.POST("/:id/publish", postHandlerV2.PublishPost, authMiddleware.RequireAuth) // POST /api/v2/posts/{id}/publish (protected)
+	routeDocs.VersionRouteDocs(handlers.V2Mapper{}, e.Routes())
+	
+	// Tag routes
+	v1.GET("/tags", tagHandler.ListTags)                  // GET /api/v1/tags
+	v1.GET("/tags/:name/feed.xml", feedHandler.TagPosts) // GET /api/v1/tags/{name}/feed.xml (RSS)
+	
+	// Series routes
+	v1.GET("/series/:id", seriesHandler.GetSeries)                                // GET /api/v1/series/{id}
+	v1.POST("/series", seriesHandler.CreateSeries, authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin)) // POST /api/v1/series (authors and admins)
+	v1.PUT("/series/:id", seriesHandler.UpdateSeries, authMiddleware.RequireAuth)    // PUT /api/v1/series/{id} (protected)
+	v1.DELETE("/series/:id", seriesHandler.DeleteSeries, authMiddleware.RequireAuth) // DELETE /api/v1/series/{id} (protected)
+	v1.PUT("/series/:id/posts", seriesHandler.SetSeriesPosts, authMiddleware.RequireAuth) // PUT /api/v1/series/{id}/posts (protected)
+	
+	// User account routes
+	users := v1.Group("/users")
+	users.GET("/me", userHandler.GetAccount, authMiddleware.RequireAuth)                // GET /api/v1/users/me (protected)
+	users.PUT("/me", userHandler.UpdateAccount, authMiddleware.RequireAuth)             // PUT /api/v1/users/me (protected)
+	users.DELETE("/me", userHandler.DeleteAccount, authMiddleware.RequireAuth)          // DELETE /api/v1/users/me (protected)
+	users.GET("/me/export", exportHandler.Export, authMiddleware.RequireAuth)           // GET /api/v1/users/me/export (protected)
+	users.PUT("/me/password", userHandler.ChangePassword, authMiddleware.RequireAuth)   // PUT /api/v1/users/me/password (protected)
+	users.POST("/me/email", userHandler.RequestEmailChange, authMiddleware.RequireAuth) // POST /api/v1/users/me/email (protected)
+	users.GET("/email/confirm", userHandler.ConfirmEmailChange)                         // GET /api/v1/users/email/confirm
+	users.GET("/:id/comments/feed.xml", feedHandler.AuthorComments)                     // GET /api/v1/users/{id}/comments/feed.xml (RSS)
+	users.GET("/:id/feed.xml", feedHandler.AuthorPosts)                                 // GET /api/v1/users/{id}/feed.xml (RSS)
+	users.PUT("/me/feed", feedHandler.SetAuthorFeed, authMiddleware.RequireAuth)        // PUT /api/v1/users/me/feed (protected)
+	users.GET("/me/reading-history", progressHandler.ReadingHistory, authMiddleware.RequireAuth) // GET /api/v1/users/me/reading-history (protected)
+	users.GET("/me/bookmarks", bookmarkHandler.ListBookmarks, authMiddleware.RequireAuth) // GET /api/v1/users/me/bookmarks (protected)
+	users.GET("/me/notifications", notificationHandler.List, authMiddleware.RequireAuth) // GET /api/v1/users/me/notifications (protected)
+	users.POST("/:id/follow", followHandler.Follow, authMiddleware.RequireAuth)         // POST /api/v1/users/{id}/follow (protected)
+	users.DELETE("/:id/follow", followHandler.Unfollow, authMiddleware.RequireAuth)     // DELETE /api/v1/users/{id}/follow (protected)
+	users.GET("/:id/followers", followHandler.ListFollowers)                            // GET /api/v1/users/{id}/followers
+	users.GET("/:id/following", followHandler.ListFollowing)                            // GET /api/v1/users/{id}/following
+	users.GET("/:id/series", seriesHandler.ListAuthorSeries)                            // GET /api/v1/users/{id}/series
+	users.GET("/:id", profileHandler.GetProfile)                                        // GET /api/v1/users/{id}
+	users.PUT("/me/profile", profileHandler.UpdateProfile, authMiddleware.RequireAuth)  // PUT /api/v1/users/me/profile (protected)
+	users.POST("/me/avatar", avatarHandler.UploadAvatar, authMiddleware.RequireAuth, avatarUpload) // POST /api/v1/users/me/avatar (protected, multipart)
+	users.POST("/me/2fa/enable", twoFactorHandler.Enable, authMiddleware.RequireAuth)   // POST /api/v1/users/me/2fa/enable (protected)
+	users.POST("/me/2fa/confirm", twoFactorHandler.Confirm, authMiddleware.RequireAuth) // POST /api/v1/users/me/2fa/confirm (protected)
+	users.POST("/me/2fa/disable", twoFactorHandler.Disable, authMiddleware.RequireAuth) // POST /api/v1/users/me/2fa/disable (protected)
+	if cfg.WebAuthn.Enabled {
+		users.GET("/me/passkeys", passkeyHandler.ListPasskeys, authMiddleware.RequireAuth)           // GET /api/v1/users/me/passkeys (protected)
+		users.DELETE("/me/passkeys/:id", passkeyHandler.DeletePasskey, authMiddleware.RequireAuth) // DELETE /api/v1/users/me/passkeys/{id} (protected)
+	}
+	if cfg.Billing.Enabled {
+		users.GET("/me/subscription", billingHandler.GetSubscription, authMiddleware.RequireAuth) // GET /api/v1/users/me/subscription (protected)
+	}
+	if cfg.Tips.Enabled {
+		payoutAuthors := []echo.MiddlewareFunc{authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin)}
+		users.GET("/me/payout-account", tipHandler.GetPayoutAccount, payoutAuthors...)       // GET /api/v1/users/me/payout-account (authors and admins)
+		users.PUT("/me/payout-account", tipHandler.LinkPayoutAccount, payoutAuthors...)      // PUT /api/v1/users/me/payout-account (authors and admins)
+		users.DELETE("/me/payout-account", tipHandler.UnlinkPayoutAccount, payoutAuthors...) // DELETE /api/v1/users/me/payout-account (authors and admins)
+	}
+	users.GET("/security/report", securityHandler.ReportChange)                         // GET /api/v1/users/security/report
+	users.POST("/security/report", securityHandler.ReportChange)                        // POST /api/v1/users/security/report
+	users.POST("/password/reset", securityHandler.ResetPassword)                        // POST /api/v1/users/password/reset
+	
+	// Admin routes
+	admin := v1.Group("/admin", authMiddleware.RequireAuth, authMiddleware.RequireRole(user.RoleAdmin))
+	admin.POST("/announcements", announcementHandler.Create)             // POST /api/v1/admin/announcements (admins)
+	admin.GET("/announcements/:id", announcementHandler.GetReport)       // GET /api/v1/admin/announcements/{id} (admins)
+	admin.GET("/audit-logs", auditHandler.ListAuditLogs)                 // GET /api/v1/admin/audit-logs (admins)
+	admin.GET("/banners", bannerHandler.List)                            // GET /api/v1/admin/banners (admins)
+	admin.POST("/banners", bannerHandler.Create)                         // POST /api/v1/admin/banners (admins)
+	admin.PUT("/banners/:id", bannerHandler.Update)                      // PUT /api/v1/admin/banners/{id} (admins)
+	admin.DELETE("/banners/:id", bannerHandler.Delete)                   // DELETE /api/v1/admin/banners/{id} (admins)
+	admin.GET("/comments", commentHandler.ListModerationQueue)           // GET /api/v1/admin/comments (admins)
+	admin.POST("/comments/:id/approve", commentHandler.ApproveComment)   // POST /api/v1/admin/comments/{id}/approve (admins)
+	admin.POST("/comments/:id/reject", commentHandler.RejectComment)     // POST /api/v1/admin/comments/{id}/reject (admins)
+	admin.GET("/comments/:id/reports", commentReportHandler.ListReports) // GET /api/v1/admin/comments/{id}/reports (admins)
+	admin.GET("/diagnostics", diagnosticsHandler.GetDiagnostics)         // GET /api/v1/admin/diagnostics (admins)
+	admin.GET("/export", exportHandler.ExportSite)                       // GET /api/v1/admin/export (admins, streamed)
+	admin.POST("/import", importHandler.Start, importUpload)             // POST /api/v1/admin/import (admins, multipart)
+	admin.GET("/import/:id", importHandler.Get)                          // GET /api/v1/admin/import/{id} (admins)
+	admin.POST("/posts/:id/pin", postHandler.PinPost)                    // POST /api/v1/admin/posts/{id}/pin (admins)
+	admin.DELETE("/posts/:id/pin", postHandler.UnpinPost)                // DELETE /api/v1/admin/posts/{id}/pin (admins)
+	admin.POST("/posts/:id/feature", postHandler.FeaturePost)            // POST /api/v1/admin/posts/{id}/feature (admins)
+	admin.DELETE("/posts/:id/feature", postHandler.UnfeaturePost)        // DELETE /api/v1/admin/posts/{id}/feature (admins)
+	admin.GET("/redirects", redirectHandler.List)                        // GET /api/v1/admin/redirects (admins)
+	admin.POST("/redirects", redirectHandler.Create)                     // POST /api/v1/admin/redirects (admins)
+	admin.PUT("/redirects/:id", redirectHandler.Update)                  // PUT /api/v1/admin/redirects/{id} (admins)
+	admin.DELETE("/redirects/:id", redirectHandler.Delete)               // DELETE /api/v1/admin/redirects/{id} (admins)
+	admin.GET("/tags", tagHandler.ListAllTags)                           // GET /api/v1/admin/tags (admins)
+	admin.PUT("/tags/:name", tagHandler.RenameTag)                       // PUT /api/v1/admin/tags/{name} (admins)
+	admin.POST("/tags/merge", tagHandler.MergeTags)                      // POST /api/v1/admin/tags/merge (admins)
+	admin.DELETE("/tags/unused", tagHandler.DeleteUnusedTags)            // DELETE /api/v1/admin/tags/unused (admins)
+	admin.PUT("/tags/:name/feed", feedHandler.SetTagFeed)                // PUT /api/v1/admin/tags/{name}/feed (admins)
+	admin.GET("/webhooks", webhookHandler.List)                          // GET /api/v1/admin/webhooks (admins)
+	admin.POST("/webhooks", webhookHandler.Create)                       // POST /api/v1/admin/webhooks (admins)
+	admin.PUT("/webhooks/:id", webhookHandler.Update)                    // PUT /api/v1/admin/webhooks/{id} (admins)
+	admin.DELETE("/webhooks/:id", webhookHandler.Delete)                 // DELETE /api/v1/admin/webhooks/{id} (admins)
+	admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries) // GET /api/v1/admin/webhooks/{id}/deliveries (admins)
+	if cfg.SSO.Enabled {
+		admin.GET("/sso", ssoHandler.ListConnections)                   // GET /api/v1/admin/sso (admins)
+		admin.GET("/sso/:organization", ssoHandler.GetConnection)       // GET /api/v1/admin/sso/{organization} (admins)
+		admin.PUT("/sso/:organization", ssoHandler.SaveConnection)      // PUT /api/v1/admin/sso/{organization} (admins)
+		admin.DELETE("/sso/:organization", ssoHandler.DeleteConnection) // DELETE /api/v1/admin/sso/{organization} (admins)
+	}
+	
+	// Stripe webhook, authenticated by its signature
+	if cfg.Billing.Enabled {
+		v1.POST("/billing/stripe/webhook", billingHandler.StripeWebhook) // POST /api/v1/billing/stripe/webhook
+	}
+	
+	// Stripe payment webhook settling tips, authenticated by its signature
+	if cfg.Tips.Enabled {
+		v1.POST("/payments/stripe/webhook", tipHandler.StripeWebhook) // POST /api/v1/payments/stripe/webhook
+	}
+	
+	// Announcement unsubscribe links; POST supports one-click unsubscribing
+	v1.GET("/announcements/unsubscribe", announcementHandler.Unsubscribe)  // GET /api/v1/announcements/unsubscribe
+	v1.POST("/announcements/unsubscribe", announcementHandler.Unsubscribe) // POST /api/v1/announcements/unsubscribe
+	
+	// Site-wide banners; signed-in users can dismiss them
+	v1.GET("/announcements/active", bannerHandler.ListActive)                                // GET /api/v1/announcements/active (banners dismissed by the signed-in user are left out)
+	v1.POST("/announcements/:id/dismiss", bannerHandler.Dismiss, authMiddleware.RequireAuth) // POST /api/v1/announcements/{id}/dismiss (protected)
+	
+	// Notification routes
+	v1.POST("/notifications/:id/read", notificationHandler.MarkRead, authMiddleware.RequireAuth) // POST /api/v1/notifications/{id}/read (protected)
+	v1.GET("/ws", realtimeHandler.Connect, authMiddleware.IdentifyWebSocket, authMiddleware.RequireAuth) // GET /api/v1/ws (protected, WebSocket)
 	
 	// Comment routes (nested under posts)
-	posts.POST("/:id/comments", commentHandler.CreateComment)               // POST /api/v1/posts/{id}/comments
+	posts.POST("/:id/comments", commentHandler.CreateComment, idempotent)   // POST /api/v1/posts/{id}/comments (guests, or linked to the signed-in user)
 	posts.GET("/:id/comments", commentHandler.GetCommentsByPost)            // GET /api/v1/posts/{id}/comments
+	posts.GET("/:id/comments/feed.xml", feedHandler.PostComments)           // GET /api/v1/posts/{id}/comments/feed.xml (RSS)
+	if cfg.Tips.Enabled {
+		posts.POST("/:id/tips", tipHandler.CreateTip) // POST /api/v1/posts/{id}/tips (guests, or linked to the signed-in user)
+	}
+	v1.PUT("/comments/:id", commentHandler.UpdateComment, commentRef, authMiddleware.RequireAuth)    // PUT /api/v1/comments/{id} (author or admin)
+	v1.DELETE("/comments/:id", commentHandler.DeleteComment, commentRef, authMiddleware.RequireAuth) // DELETE /api/v1/comments/{id} (author or admin)
+	v1.POST("/comments/:id/report", commentReportHandler.ReportComment, commentRef)                  // POST /api/v1/comments/{id}/report (guests, or linked to the signed-in user)
+	
+	// Comments widget for external sites; requests carry the site token and
+	// are checked against its origins by middleware.WidgetCORS
+	widgetRoutes := v1.Group("/widget")
+	if cfg.Widget.Enabled {
+		widgetRoutes.GET("/posts/:id/comments", widgetHandler.ListComments)   // GET /api/v1/widget/posts/{id}/comments (embedding sites)
+		widgetRoutes.POST("/posts/:id/comments", widgetHandler.CreateComment) // POST /api/v1/widget/posts/{id}/comments (embedding sites; guests, or linked to the signed-in user)
+	}
+	
+	// Server-rendered reading view
+	if cfg.ReadingView.Enabled {
+		renderer, err := views.NewRenderer(views.Options{
+			ThemesDir:    cfg.ReadingView.ThemesDir,
+			DefaultTheme: cfg.ReadingView.Theme,
+			HostThemes:   cfg.ReadingView.HostThemes,
+		})
+		if err != nil {
+			logger.Error(context.Background(), "failed to load reading view themes", "error", err.Error())
+		} else {
+			e.Renderer = renderer
+			readingHandler := handlers.NewReadingHandler(postService, redirectService, commentService, handlers.ReadingSettings{
+				SiteName: cfg.ReadingView.SiteName,
+				BaseURL:  cfg.Server.BaseURL,
+				FeedURL:  cfg.ReadingView.FeedURL,
+			}, logger)
+			e.GET("/p/:slug", readingHandler.ShowPost) // GET /p/{slug} (HTML)
+			if cfg.Widget.Enabled {
+				widgetRoutes.GET("/posts/:id/thread", widgetHandler.ShowThread) // GET /api/v1/widget/posts/{id}/thread (HTML for iframes on embedding sites)
+			}
+			assetHandler := handlers.NewAssetHandler(renderer, logger)
+			e.GET("/assets/:theme/*", assetHandler.ServeAsset) // GET /assets/{theme}/{path}
+		}
+	}
+	
+	// Token signing keys for other services
+	e.GET("/.well-known/jwks.json", jwksHandler.ServeJWKS) // GET /.well-known/jwks.json
+	
+	// Post feeds; filter with ?tag= and ?author=
+	e.GET("/feed.xml", feedHandler.Posts) // GET /feed.xml (RSS)
+	feeds := e.Group("/feeds")
+	feeds.GET("/posts.rss", feedHandler.Posts)          // GET /feeds/posts.rss (RSS)
+	feeds.GET("/posts.atom", feedHandler.PostsAtom)     // GET /feeds/posts.atom (Atom)
+	feeds.GET("/authors/:feed", feedHandler.AuthorFeed) // GET /feeds/authors/{id}.rss and /feeds/authors/{id}.atom
+	
+	// Stats badges for external sites, fetched without credentials
+	badges := e.Group("/badges", middleware.BadgeRateLimiterMiddleware(cfg, rateLimits, logger))
+	badges.GET("/posts/:id/views.svg", badgeHandler.PostViews) // GET /badges/posts/{id}/views.svg (SVG)
+	
+	// Uploads kept on the local disk; the s3 driver serves them from the bucket
+	if !strings.EqualFold(cfg.Storage.Driver, "s3") {
+		e.Static(storage.LocalPath, cfg.Storage.LocalDir) // GET /uploads/{key}
+	}
+	
+	// Crawl rules
+	e.GET("/robots.txt", robotsHandler.ServeRobots) // GET /robots.txt
+	if cfg.SearchPing.Enabled && cfg.SearchPing.IndexNowKey != "" {
+		e.GET("/"+cfg.SearchPing.IndexNowKey+".txt", robotsHandler.ServeIndexNowKey) // GET /{key}.txt (IndexNow key file)
+	}
+	
+	// SCIM 2.0 provisioning for organizations, authenticated by tenant tokens
+	if cfg.SCIM.Enabled {
+		scimGroup := e.Group("/scim/v2", middleware.SCIMAuth(infraauth.NewSCIMTenants(cfg.SCIM.Tenants), logger))
+		scimGroup.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig) // GET /scim/v2/ServiceProviderConfig
+		scimGroup.GET("/ResourceTypes", scimHandler.ResourceTypes)                 // GET /scim/v2/ResourceTypes
+		scimGroup.GET("/Users", scimHandler.ListUsers)                             // GET /scim/v2/Users
+		scimGroup.POST("/Users", scimHandler.CreateUser)                           // POST /scim/v2/Users
+		scimGroup.GET("/Users/:id", scimHandler.GetUser)                           // GET /scim/v2/Users/{id}
+		scimGroup.PUT("/Users/:id", scimHandler.ReplaceUser)                       // PUT /scim/v2/Users/{id}
+		scimGroup.PATCH("/Users/:id", scimHandler.PatchUser)                       // PATCH /scim/v2/Users/{id}
+		scimGroup.DELETE("/Users/:id", scimHandler.DeleteUser)                     // DELETE /scim/v2/Users/{id}
+		scimGroup.GET("/Groups", scimHandler.ListGroups)                           // GET /scim/v2/Groups
+		scimGroup.POST("/Groups", scimHandler.CreateGroup)                         // POST /scim/v2/Groups
+		scimGroup.GET("/Groups/:id", scimHandler.GetGroup)                         // GET /scim/v2/Groups/{id}
+		scimGroup.PUT("/Groups/:id", scimHandler.ReplaceGroup)                     // PUT /scim/v2/Groups/{id}
+		scimGroup.PATCH("/Groups/:id", scimHandler.PatchGroup)                     // PATCH /scim/v2/Groups/{id}
+		scimGroup.DELETE("/Groups/:id", scimHandler.DeleteGroup)                   // DELETE /scim/v2/Groups/{id}
+	}
+	
+	// Error catalog
+	v1.GET("/errors", docsHandler.ListErrors) // GET /api/v1/errors
 	
-	// Documentation route
-	e.GET("/docs/*", echoSwagger.WrapHandler)
+	// Swagger UI; doc.json is the OpenAPI document with the registered examples
+	if cfg.Docs.SwaggerEnabled {
+		e.GET("/swagger/doc.json", docsHandler.OpenAPI) // GET /swagger/doc.json
+		e.GET("/swagger/*", echoSwagger.WrapHandler)    // GET /swagger/index.html
+	}
 }
@@ -0,0 +1,324 @@
+package views
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// DefaultThemeName is the built-in theme every other theme falls back to
+const DefaultThemeName = "default"
+
+// maxTemplateSize bounds the size of a single override template
+const maxTemplateSize = 64 << 10
+
+//go:embed themes/default
+var defaultThemeFS embed.FS
+
+var (
+	// ErrInvalidThemeName is returned for theme names that are not a single
+	// lowercase path segment
+	ErrInvalidThemeName = errors.New("invalid theme name")
+	// ErrUnsafeTemplate is returned when an override template uses a
+	// construct outside the template sandbox
+	ErrUnsafeTemplate = errors.New("unsafe template")
+)
+
+// themeNamePattern keeps theme names usable as directory names and URL segments
+var themeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// pages lists the templates that can be rendered directly; every other
+// template in a theme is shared by all pages
+var pages = []string{"post.html", "widget.html", "not_found.html"}
+
+// Theme is a parsed set of page templates and static assets
+type Theme struct {
+	Name   string
+	pages  map[string]*template.Template
+	assets map[string]*Asset
+}
+
+// Asset is a static theme file held in memory
+type Asset struct {
+	Name        string
+	ContentType string
+	Content     []byte
+	// Hash fingerprints the content for ETags and cache-busting URLs
+	Hash string
+}
+
+// ETag returns the strong entity tag of the asset
+func (a *Asset) ETag() string {
+	return `"` + a.Hash + `"`
+}
+
+// LoadTheme loads a theme by name. Files in themesDir/<name>/templates and
+// themesDir/<name>/static override the files of the default theme with the
+// same name, so a theme only needs to contain what it changes.
+func LoadTheme(name, themesDir string) (*Theme, error) {
+	if !themeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidThemeName, name)
+	}
+
+	base, err := fs.Sub(defaultThemeFS, "themes/default")
+	if err != nil {
+		return nil, err
+	}
+	templates, err := readFiles(base, "templates")
+	if err != nil {
+		return nil, err
+	}
+	static, err := readFiles(base, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	if themesDir != "" {
+		dir := filepath.Join(themesDir, name)
+		if _, err := os.Stat(dir); err != nil {
+			if name != DefaultThemeName {
+				return nil, fmt.Errorf("theme %s: %w", name, ErrUnknownTheme)
+			}
+		} else {
+			overrides := os.DirFS(dir)
+			if err := overlayFiles(templates, overrides, "templates"); err != nil {
+				return nil, fmt.Errorf("theme %s: %w", name, err)
+			}
+			if err := overlayFiles(static, overrides, "static"); err != nil {
+				return nil, fmt.Errorf("theme %s: %w", name, err)
+			}
+		}
+	} else if name != DefaultThemeName {
+		return nil, fmt.Errorf("theme %s: %w", name, ErrUnknownTheme)
+	}
+
+	theme := &Theme{
+		Name:   name,
+		pages:  make(map[string]*template.Template, len(pages)),
+		assets: make(map[string]*Asset, len(static)),
+	}
+	for file, content := range static {
+		sum := sha256.Sum256(content)
+		contentType := mime.TypeByExtension(path.Ext(file))
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+		theme.assets[file] = &Asset{
+			Name:        file,
+			ContentType: contentType,
+			Content:     content,
+			Hash:        hex.EncodeToString(sum[:8]),
+		}
+	}
+
+	if err := theme.parsePages(templates); err != nil {
+		return nil, fmt.Errorf("theme %s: %w", name, err)
+	}
+	return theme, nil
+}
+
+// Asset returns a static file of the theme by its path
+func (t *Theme) Asset(name string) (*Asset, bool) {
+	asset, ok := t.assets[name]
+	return asset, ok
+}
+
+// AssetURL returns the URL of a static file, versioned with its content hash
+// so browsers can cache it indefinitely
+func (t *Theme) AssetURL(name string) string {
+	u := "/assets/" + t.Name + "/" + strings.TrimPrefix(name, "/")
+	if asset, ok := t.assets[name]; ok {
+		u += "?v=" + url.QueryEscape(asset.Hash)
+	}
+	return u
+}
+
+// parsePages parses each page together with the shared templates and
+// checks the result against the sandbox
+func (t *Theme) parsePages(templates map[string][]byte) error {
+	funcs := template.FuncMap{"asset": t.AssetURL}
+
+	shared := make([]string, 0, len(templates))
+	for file := range templates {
+		if !isPage(file) {
+			shared = append(shared, file)
+		}
+	}
+	sort.Strings(shared)
+
+	for _, page := range pages {
+		content, ok := templates[page]
+		if !ok {
+			return fmt.Errorf("missing template %s", page)
+		}
+
+		tmpl := template.New(page).Funcs(funcs)
+		for _, file := range shared {
+			if _, err := tmpl.New(file).Parse(string(templates[file])); err != nil {
+				return fmt.Errorf("failed to parse template %s: %w", file, err)
+			}
+		}
+		if _, err := tmpl.Parse(string(content)); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", page, err)
+		}
+		if err := checkSandbox(tmpl); err != nil {
+			return err
+		}
+
+		// Executing once runs the contextual escaper, so templates it rejects
+		// fail at load time instead of on the first request
+		if err := tmpl.ExecuteTemplate(io.Discard, "layout", PostPage{}); err != nil {
+			return fmt.Errorf("failed to render template %s: %w", page, err)
+		}
+		t.pages[page] = tmpl
+	}
+	return nil
+}
+
+// checkSandbox rejects templates that call functions passed in as data.
+// Together with the restricted function map this limits themes to
+// presenting the page data.
+func checkSandbox(tmpl *template.Template) error {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if name, ok := findForbidden(t.Tree.Root); ok {
+			return fmt.Errorf("%w: %s uses %q", ErrUnsafeTemplate, t.Name(), name)
+		}
+	}
+	return nil
+}
+
+// forbiddenIdentifiers are builtins a theme may not use
+var forbiddenIdentifiers = map[string]bool{"call": true}
+
+// findForbidden walks a template tree looking for forbidden identifiers
+func findForbidden(node parse.Node) (string, bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return "", false
+		}
+		for _, child := range n.Nodes {
+			if name, ok := findForbidden(child); ok {
+				return name, true
+			}
+		}
+	case *parse.ActionNode:
+		return findForbidden(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return "", false
+		}
+		for _, cmd := range n.Cmds {
+			if name, ok := findForbidden(cmd); ok {
+				return name, true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if name, ok := findForbidden(arg); ok {
+				return name, true
+			}
+		}
+	case *parse.IdentifierNode:
+		if forbiddenIdentifiers[n.Ident] {
+			return n.Ident, true
+		}
+	case *parse.IfNode:
+		return findForbiddenBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return findForbiddenBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return findForbiddenBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		return findForbidden(n.Pipe)
+	}
+	return "", false
+}
+
+// findForbiddenBranch checks the pipeline and both branches of a control node
+func findForbiddenBranch(n *parse.BranchNode) (string, bool) {
+	if name, ok := findForbidden(n.Pipe); ok {
+		return name, true
+	}
+	if name, ok := findForbidden(n.List); ok {
+		return name, true
+	}
+	return findForbidden(n.ElseList)
+}
+
+// isPage reports whether a template file is a directly rendered page
+func isPage(file string) bool {
+	for _, page := range pages {
+		if file == page {
+			return true
+		}
+	}
+	return false
+}
+
+// readFiles reads every regular file below dir, keyed by its path relative
+// to dir
+func readFiles(fsys fs.FS, dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(p, dir+"/")] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// overlayFiles replaces files with the overrides found below dir. Symlinks
+// and other non-regular files are ignored so a theme cannot expose files
+// outside its directory.
+func overlayFiles(files map[string][]byte, fsys fs.FS, dir string) error {
+	if _, err := fs.Stat(fsys, dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	overrides, err := readFiles(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for file, content := range overrides {
+		if dir == "templates" {
+			if path.Ext(file) != ".html" {
+				continue
+			}
+			if len(content) > maxTemplateSize {
+				return fmt.Errorf("%w: %s exceeds %d bytes", ErrUnsafeTemplate, file, maxTemplateSize)
+			}
+		}
+		files[file] = content
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Package views renders the server-side HTML reading view
+package views
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrUnknownTheme is returned when a theme has not been loaded
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Page holds the data shared by every page
+type Page struct {
+	SiteName    string
+	Title       string
+	Description string
+	// CanonicalURL is the absolute URL of the page, used for og:url
+	CanonicalURL string
+	// FeedURL is advertised through an RSS discovery link when set
+	FeedURL string
+	// NoIndex adds a robots meta tag that keeps the page out of search results
+	NoIndex bool
+	// Embedded pages are shown in iframes on other sites and leave out the
+	// site header
+	Embedded bool
+}
+
+// PostPage is the data for the post reading page
+type PostPage struct {
+	Page
+	Post     PostView
+	Comments []CommentView
+}
+
+// PostView is a post prepared for display
+type PostView struct {
+	Title       string
+	ContentHTML template.HTML
+	PublishedAt string
+	// PublishedISO is the machine-readable publication time
+	PublishedISO string
+	// Locked is set when ContentHTML is only an excerpt the reader is not
+	// entitled to read past
+	Locked bool
+}
+
+// CommentView is a comment prepared for display
+type CommentView struct {
+	// ID anchors the comment so feeds can link to it
+	ID         int
+	AuthorName string
+	Content    string
+	// ContentHTML is Content rendered with its basic formatting
+	ContentHTML template.HTML
+	CreatedAt   string
+	// Replies are only filled on pages showing comments as threads
+	Replies []CommentView
+}
+
+// Options configures which themes the renderer loads
+type Options struct {
+	// ThemesDir holds theme override directories, one per theme name
+	ThemesDir string
+	// DefaultTheme is used for requests without a host-specific theme
+	DefaultTheme string
+	// HostThemes selects a theme per request host, e.g. for organizations
+	// served from their own domain
+	HostThemes map[string]string
+}
+
+// Renderer renders pages with the theme selected for each request and
+// implements echo.Renderer
+type Renderer struct {
+	themes       map[string]*Theme
+	defaultTheme string
+	hostThemes   map[string]string
+}
+
+// NewRenderer loads the default theme and every theme referenced by the
+// options. Loading fails if a referenced theme is missing or contains an
+// unsafe template.
+func NewRenderer(opts Options) (*Renderer, error) {
+	if opts.DefaultTheme == "" {
+		opts.DefaultTheme = DefaultThemeName
+	}
+
+	r := &Renderer{
+		themes:       make(map[string]*Theme),
+		defaultTheme: opts.DefaultTheme,
+		hostThemes:   make(map[string]string, len(opts.HostThemes)),
+	}
+
+	names := []string{DefaultThemeName, opts.DefaultTheme}
+	for host, name := range opts.HostThemes {
+		r.hostThemes[strings.ToLower(host)] = name
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		if _, loaded := r.themes[name]; loaded {
+			continue
+		}
+		theme, err := LoadTheme(name, opts.ThemesDir)
+		if err != nil {
+			return nil, err
+		}
+		r.themes[name] = theme
+	}
+
+	return r, nil
+}
+
+// Render executes the named page template of the request's theme
+func (r *Renderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	theme := r.themes[r.themeFor(c.Request().Host)]
+	tmpl, ok := theme.pages[name]
+	if !ok {
+		return fmt.Errorf("template %s not found in theme %s", name, theme.Name)
+	}
+	return tmpl.ExecuteTemplate(w, "layout", data)
+}
+
+// Theme returns a loaded theme by name
+func (r *Renderer) Theme(name string) (*Theme, error) {
+	theme, ok := r.themes[name]
+	if !ok {
+		return nil, ErrUnknownTheme
+	}
+	return theme, nil
+}
+
+// themeFor selects the theme for a request host, ignoring the port
+func (r *Renderer) themeFor(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if name, ok := r.hostThemes[strings.ToLower(host)]; ok {
+		return name
+	}
+	return r.defaultTheme
+}
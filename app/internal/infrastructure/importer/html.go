@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spacePattern     = regexp.MustCompile(`[ \t\f\r]+`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToMarkdown converts the HTML of an exported post or comment to the
+// Markdown subset posts are written in. Markup without a Markdown form is
+// dropped and its text kept; scripts, styles and HTML comments, which
+// carry the block editor's annotations, are dropped entirely. Blank lines
+// separate paragraphs, as in WordPress content without paragraph tags.
+func htmlToMarkdown(source string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(source), body)
+	if err != nil {
+		return strings.TrimSpace(source)
+	}
+
+	var out strings.Builder
+	for _, n := range nodes {
+		out.WriteString(convertNode(n))
+	}
+	return tidy(out.String())
+}
+
+// convertNode converts a node and its children
+func convertNode(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return spacePattern.ReplaceAllString(n.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Iframe:
+		return ""
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level, _ := strconv.Atoi(n.Data[1:])
+		return block(strings.Repeat("#", level) + " " + inline(n))
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Figcaption:
+		return block(convertChildren(n))
+	case atom.Strong, atom.B:
+		return wrap("**", inline(n))
+	case atom.Em, atom.I:
+		return wrap("*", inline(n))
+	case atom.Code:
+		return wrap("`", textContent(n))
+	case atom.Pre:
+		return block("```\n" + strings.Trim(textContent(n), "\n") + "\n```")
+	case atom.A:
+		text, href := inline(n), attr(n, "href")
+		if text == "" {
+			text = href
+		}
+		if !isLinkTarget(href) {
+			return text
+		}
+		return "[" + text + "](" + href + ")"
+	case atom.Img:
+		src := attr(n, "src")
+		if !isLinkTarget(src) {
+			return ""
+		}
+		alt := strings.TrimSpace(attr(n, "alt"))
+		if alt == "" {
+			alt = "image"
+		}
+		return "[" + alt + "](" + src + ")"
+	case atom.Br:
+		return "\n"
+	case atom.Hr:
+		return block("---")
+	case atom.Ul, atom.Ol:
+		return block(list(n))
+	case atom.Blockquote:
+		lines := strings.Split(tidy(convertChildren(n)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace("> " + line)
+		}
+		return block(strings.Join(lines, "\n"))
+	}
+	return convertChildren(n)
+}
+
+// convertChildren converts the children of a node
+func convertChildren(n *html.Node) string {
+	var out strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		out.WriteString(convertNode(c))
+	}
+	return out.String()
+}
+
+// inline converts the children of a node onto a single line
+func inline(n *html.Node) string {
+	return strings.Join(strings.Fields(convertChildren(n)), " ")
+}
+
+// list converts the items of a list, numbering those of ordered lists
+func list(n *html.Node) string {
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(len(items)+1) + ". "
+		}
+		items = append(items, marker+inline(c))
+	}
+	return strings.Join(items, "\n")
+}
+
+// textContent returns the text of a node and its children as is
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var out strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		out.WriteString(textContent(c))
+	}
+	return out.String()
+}
+
+// block sets converted content apart from its surroundings
+func block(content string) string {
+	if strings.TrimSpace(content) == "" {
+		return ""
+	}
+	return "\n\n" + content + "\n\n"
+}
+
+// wrap surrounds inline content with a marker, leaving empty content out
+func wrap(marker, content string) string {
+	if strings.TrimSpace(content) == "" {
+		return ""
+	}
+	return marker + content + marker
+}
+
+// attr returns an attribute of a node
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// isLinkTarget checks if a URL can be the target of a Markdown link
+func isLinkTarget(target string) bool {
+	if target == "" || strings.ContainsAny(target, " ()") {
+		return false
+	}
+	lower := strings.ToLower(target)
+	if i := strings.Index(lower, ":"); i >= 0 && !strings.ContainsAny(lower[:i], "/?#") {
+		return strings.HasPrefix(lower, "http:") || strings.HasPrefix(lower, "https:") || strings.HasPrefix(lower, "mailto:")
+	}
+	return true
+}
+
+// tidy trims the lines of converted content, keeping the indentation of
+// code blocks, and collapses runs of blank lines
+func tidy(content string) string {
+	lines := strings.Split(content, "\n")
+	code := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			code = !code
+			lines[i] = strings.TrimSpace(line)
+			continue
+		}
+		if code {
+			lines[i] = strings.TrimRight(line, " \t\r")
+		} else {
+			lines[i] = strings.TrimSpace(line)
+		}
+	}
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,48 @@
+// Package importer reads the export files of other blogs into import
+// documents: WordPress exports (WXR) and zips of Markdown files with YAML
+// front matter
+package importer
+
+import (
+	"bytes"
+
+	"blog-platform/internal/domain/imports"
+)
+
+// zipMagic starts every zip archive
+var zipMagic = []byte("PK\x03\x04")
+
+// Parser implements the imports.Parser interface. Formats are told apart by
+// content rather than file name.
+type Parser struct{}
+
+// NewParser creates a new Parser instance
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads a WordPress export or a zip of Markdown files
+func (p *Parser) Parse(data []byte) (*imports.Document, error) {
+	var (
+		doc *imports.Document
+		err error
+	)
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		doc, err = parseMarkdownZip(data)
+	case bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), []byte("<")):
+		doc, err = parseWXR(data)
+	default:
+		return nil, imports.ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(doc.Posts) == 0 {
+		return nil, imports.ErrNoPosts
+	}
+	return doc, nil
+}
+
+var _ imports.Parser = (*Parser)(nil)
@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"blog-platform/internal/domain/imports"
+)
+
+// Limits on the Markdown files read from a zip, which expand well beyond
+// the size of the upload
+const (
+	maxMarkdownFiles    = 5000
+	maxMarkdownFileSize = 1 << 20
+)
+
+// frontMatter holds the fields read from the YAML front matter of a
+// Markdown file, named as static site generators name them
+type frontMatter struct {
+	Title       string    `yaml:"title"`
+	Slug        string    `yaml:"slug"`
+	Date        time.Time `yaml:"date"`
+	Draft       bool      `yaml:"draft"`
+	Tags        []string  `yaml:"tags"`
+	Author      string    `yaml:"author"`
+	AuthorEmail string    `yaml:"author_email"`
+}
+
+// parseMarkdownZip reads the .md and .markdown files of a zip, in the order
+// of their paths; other files are ignored. A post without a title in its
+// front matter takes the first heading of its content as title, and a post
+// without a slug takes its file name.
+func parseMarkdownZip(data []byte) (*imports.Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", imports.ErrInvalidFile, err)
+	}
+
+	var files []*zip.File
+	for _, f := range archive.File {
+		name := path.Base(f.Name)
+		ext := strings.ToLower(path.Ext(name))
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(f.Name, "__MACOSX/") || (ext != ".md" && ext != ".markdown") {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) > maxMarkdownFiles {
+		return nil, fmt.Errorf("%w: more than %d Markdown files", imports.ErrInvalidFile, maxMarkdownFiles)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	doc := &imports.Document{Source: imports.SourceMarkdown}
+	authors := make(map[string]bool)
+	for _, f := range files {
+		source, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		p, author, err := markdownPost(f.Name, source)
+		if err != nil {
+			return nil, err
+		}
+		if author.Login != "" && !authors[strings.ToLower(author.Login)] {
+			authors[strings.ToLower(author.Login)] = true
+			doc.Authors = append(doc.Authors, author)
+		}
+		doc.Posts = append(doc.Posts, p)
+	}
+	return doc, nil
+}
+
+// readZipFile reads a file of a zip up to maxMarkdownFileSize
+func readZipFile(f *zip.File) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", imports.ErrInvalidFile, f.Name, err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(io.LimitReader(r, maxMarkdownFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", imports.ErrInvalidFile, f.Name, err)
+	}
+	if len(content) > maxMarkdownFileSize {
+		return "", fmt.Errorf("%w: %s is larger than 1 MB", imports.ErrInvalidFile, f.Name)
+	}
+	return string(content), nil
+}
+
+// markdownPost maps a Markdown file with front matter, returning the
+// author it names
+func markdownPost(name, source string) (imports.Post, imports.Author, error) {
+	var meta frontMatter
+	content := strings.TrimPrefix(strings.ReplaceAll(source, "\r\n", "\n"), "\ufeff")
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		header, body, found := strings.Cut(rest, "\n---")
+		if !found {
+			return imports.Post{}, imports.Author{}, fmt.Errorf("%w: %s: unterminated front matter", imports.ErrInvalidFile, name)
+		}
+		if err := yaml.Unmarshal([]byte(header), &meta); err != nil {
+			return imports.Post{}, imports.Author{}, fmt.Errorf("%w: %s: %v", imports.ErrInvalidFile, name, err)
+		}
+		// The closing delimiter may be followed by the rest of its line
+		_, content, _ = strings.Cut(body, "\n")
+	}
+	content = strings.TrimSpace(content)
+
+	title := strings.TrimSpace(meta.Title)
+	if title == "" {
+		title, content = headingTitle(content)
+	}
+	slug := strings.TrimSpace(meta.Slug)
+	if slug == "" {
+		slug = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+
+	p := imports.Post{
+		Title:   title,
+		Slug:    slug,
+		Content: content,
+		Draft:   meta.Draft,
+		Tags:    meta.Tags,
+	}
+	if !meta.Date.IsZero() && !p.Draft {
+		published := meta.Date.UTC()
+		p.PublishedAt = &published
+	}
+
+	author := imports.Author{Name: strings.TrimSpace(meta.Author), Email: strings.TrimSpace(meta.AuthorEmail)}
+	author.Login = author.Email
+	if author.Login == "" {
+		author.Login = author.Name
+	}
+	p.AuthorLogin = author.Login
+	return p, author, nil
+}
+
+// headingTitle takes a leading heading of Markdown content as title,
+// removing it from the content
+func headingTitle(content string) (string, string) {
+	line, rest, _ := strings.Cut(content, "\n")
+	if title, ok := strings.CutPrefix(line, "# "); ok {
+		return strings.TrimSpace(title), strings.TrimSpace(rest)
+	}
+	return "", content
+}
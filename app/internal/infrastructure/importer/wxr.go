@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog-platform/internal/domain/imports"
+)
+
+// wxrDateLayout is the layout of the dates in a WordPress export
+const wxrDateLayout = "2006-01-02 15:04:05"
+
+// WordPress exports elements in the wp namespace, whose URL changes with
+// the export version, so elements are matched by local name
+type wxrRSS struct {
+	Channel wxrChannel `xml:"channel"`
+}
+
+type wxrChannel struct {
+	Version string      `xml:"wxr_version"`
+	Authors []wxrAuthor `xml:"author"`
+	Items   []wxrItem   `xml:"item"`
+}
+
+type wxrAuthor struct {
+	ID          string `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type wxrItem struct {
+	Title   string `xml:"title"`
+	Creator string `xml:"creator"`
+	// Content is content:encoded; excerpt:encoded shares its local name
+	Content    string        `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Name       string        `xml:"post_name"`
+	Type       string        `xml:"post_type"`
+	Status     string        `xml:"status"`
+	Date       string        `xml:"post_date"`
+	DateGMT    string        `xml:"post_date_gmt"`
+	Categories []wxrCategory `xml:"category"`
+	Comments   []wxrComment  `xml:"comment"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+type wxrComment struct {
+	ID       string `xml:"comment_id"`
+	Author   string `xml:"comment_author"`
+	Date     string `xml:"comment_date"`
+	DateGMT  string `xml:"comment_date_gmt"`
+	Content  string `xml:"comment_content"`
+	Approved string `xml:"comment_approved"`
+	Type     string `xml:"comment_type"`
+	Parent   string `xml:"comment_parent"`
+	UserID   string `xml:"comment_user_id"`
+}
+
+// parseWXR reads a WordPress export. Only posts are imported: pages,
+// attachments and menu items are left out, as are trashed posts and
+// comments, pingbacks and trackbacks. Post and comment bodies are
+// converted from HTML to Markdown; tags and categories both become tags.
+func parseWXR(data []byte) (*imports.Document, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// Exports declare UTF-8; entities such as &nbsp; come from HTML
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	root, err := rootElement(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", imports.ErrInvalidFile, err)
+	}
+	if root.Name.Local != "rss" {
+		return nil, imports.ErrUnsupportedFile
+	}
+	var rss wxrRSS
+	if err := decoder.DecodeElement(&rss, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", imports.ErrInvalidFile, err)
+	}
+	if rss.Channel.Version == "" {
+		return nil, imports.ErrUnsupportedFile
+	}
+
+	doc := &imports.Document{Source: imports.SourceWordPress}
+	loginsByID := make(map[string]string, len(rss.Channel.Authors))
+	for _, a := range rss.Channel.Authors {
+		login := strings.TrimSpace(a.Login)
+		if login == "" {
+			continue
+		}
+		loginsByID[strings.TrimSpace(a.ID)] = login
+		doc.Authors = append(doc.Authors, imports.Author{
+			Login: login,
+			Name:  strings.TrimSpace(a.DisplayName),
+			Email: strings.TrimSpace(a.Email),
+		})
+	}
+
+	for _, item := range rss.Channel.Items {
+		if item.Type != "post" || item.Status == "trash" || item.Status == "auto-draft" {
+			continue
+		}
+		doc.Posts = append(doc.Posts, wxrPost(item, loginsByID))
+	}
+	return doc, nil
+}
+
+// wxrPost maps an exported WordPress post
+func wxrPost(item wxrItem, loginsByID map[string]string) imports.Post {
+	p := imports.Post{
+		Title:       strings.TrimSpace(item.Title),
+		Slug:        strings.TrimSpace(item.Name),
+		Content:     htmlToMarkdown(item.Content),
+		AuthorLogin: strings.TrimSpace(item.Creator),
+		// Pending, private and scheduled posts come in as drafts
+		Draft: item.Status != "publish",
+	}
+	if !p.Draft {
+		if published, ok := wxrDate(item.DateGMT, item.Date); ok {
+			p.PublishedAt = &published
+		}
+	}
+
+	for _, c := range item.Categories {
+		if c.Domain == "post_tag" || c.Domain == "category" {
+			if name := strings.TrimSpace(c.Name); name != "" && name != "Uncategorized" {
+				p.Tags = append(p.Tags, name)
+			}
+		}
+	}
+
+	for _, c := range item.Comments {
+		if c.Approved == "trash" || (c.Type != "" && c.Type != "comment") {
+			continue
+		}
+		comment := imports.Comment{
+			ID:          strings.TrimSpace(c.ID),
+			AuthorName:  strings.TrimSpace(c.Author),
+			AuthorLogin: loginsByID[strings.TrimSpace(c.UserID)],
+			Content:     htmlToMarkdown(c.Content),
+			Approved:    c.Approved == "1",
+			Spam:        c.Approved == "spam",
+		}
+		if parent := strings.TrimSpace(c.Parent); parent != "0" {
+			comment.ParentID = parent
+		}
+		comment.CreatedAt, _ = wxrDate(c.DateGMT, c.Date)
+		p.Comments = append(p.Comments, comment)
+	}
+	return p
+}
+
+// wxrDate parses the GMT date of an exported post or comment, falling back
+// to its local date for exports without one. Unpublished posts carry the
+// invalid date 0000-00-00.
+func wxrDate(gmt, local string) (time.Time, bool) {
+	for _, value := range []string{gmt, local} {
+		t, err := time.Parse(wxrDateLayout, strings.TrimSpace(value))
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rootElement reads up to the root element of an XML document
+func rootElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
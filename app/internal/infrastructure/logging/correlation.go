@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"blog-platform/internal/application/service"
+)
+
+// CorrelationHandler adds the request and user IDs of the context to each
+// record, so every line logged while serving a request can be found by the
+// ID returned to the client and attributed to the user it was served for
+type CorrelationHandler struct {
+	slog.Handler
+}
+
+// NewCorrelationHandler wraps a handler to add correlation IDs to its records
+func NewCorrelationHandler(handler slog.Handler) *CorrelationHandler {
+	return &CorrelationHandler{Handler: handler}
+}
+
+// Handle adds the request_id and user_id attributes when the context
+// carries them
+func (h *CorrelationHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := service.RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if id, ok := service.UserIDFromContext(ctx); ok {
+		r.AddAttrs(slog.Int("user_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler that keeps adding correlation IDs
+func (h *CorrelationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewCorrelationHandler(h.Handler.WithAttrs(attrs))
+}
+
+// WithGroup returns a handler that keeps adding correlation IDs
+func (h *CorrelationHandler) WithGroup(name string) slog.Handler {
+	return NewCorrelationHandler(h.Handler.WithGroup(name))
+}
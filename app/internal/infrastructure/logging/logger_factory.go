@@ -65,8 +65,9 @@ func NewLogger(cfg *config.Config) service.Logger {
 		handler = slog.NewJSONHandler(output, opts)
 	}
 
-	// Create slog logger
-	slogger := slog.New(handler)
+	// Create slog logger, tagging lines logged for a request with its ID
+	// and the signed-in user
+	slogger := slog.New(NewCorrelationHandler(handler))
 
 	// Return wrapped logger
 	return NewOperationLogger(slogger)
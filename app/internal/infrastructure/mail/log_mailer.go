@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+
+	"blog-platform/internal/application/service"
+)
+
+// LogMailer implements the service.Mailer interface by logging messages
+// instead of delivering them, for local development and tests
+type LogMailer struct {
+	logger service.Logger
+}
+
+// NewLogMailer creates a new logging mailer
+func NewLogMailer(logger service.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+// Send logs the email
+func (m *LogMailer) Send(ctx context.Context, msg service.EmailMessage) error {
+	m.logger.Info(ctx, "email sent", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// Verify that LogMailer implements the Mailer interface
+var _ service.Mailer = (*LogMailer)(nil)
@@ -0,0 +1,19 @@
+package mail
+
+import (
+	"strings"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// NewMailer creates a mailer based on configuration
+func NewMailer(cfg *config.Config, logger service.Logger) service.Mailer {
+	switch strings.ToLower(cfg.Mail.Driver) {
+	case "smtp":
+		return NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	default:
+		// Development default: write emails to the log instead of sending them
+		return NewLogMailer(logger)
+	}
+}
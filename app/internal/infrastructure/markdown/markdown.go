@@ -0,0 +1,206 @@
+// Package markdown renders the Markdown subset used in post content to HTML.
+//
+// All source text is HTML-escaped before any markup is produced, so raw HTML
+// in the input is always displayed literally and the output is safe to embed
+// in a page.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	orderedItemPattern = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongPattern      = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	emphasisPattern    = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// listKind identifies the type of list currently being rendered
+type listKind int
+
+const (
+	noList listKind = iota
+	unorderedList
+	orderedList
+)
+
+// renderer accumulates HTML output while walking the source line by line
+type renderer struct {
+	out       strings.Builder
+	paragraph []string
+	list      listKind
+	quote     []string
+}
+
+// ToHTML renders Markdown source to HTML. Supported syntax: ATX headings,
+// paragraphs, unordered and ordered lists, blockquotes, fenced code blocks,
+// horizontal rules, inline code, strong and emphasis, and links with
+// http, https, mailto or relative targets.
+func ToHTML(source string) string {
+	r := &renderer{}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			r.flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			r.out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			r.flush()
+		case strings.HasPrefix(trimmed, ">"):
+			r.flushParagraph()
+			r.flushList()
+			r.quote = append(r.quote, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+		case trimmed == "---" || trimmed == "***":
+			r.flush()
+			r.out.WriteString("<hr>\n")
+		case headingPattern.MatchString(trimmed):
+			r.flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			r.out.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">\n")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			r.listItem(unorderedList, trimmed[2:])
+		case orderedItemPattern.MatchString(trimmed):
+			r.listItem(orderedList, orderedItemPattern.FindStringSubmatch(trimmed)[1])
+		default:
+			r.flushList()
+			r.flushQuote()
+			r.paragraph = append(r.paragraph, trimmed)
+		}
+	}
+	r.flush()
+
+	return r.out.String()
+}
+
+// listItem renders an item, opening or switching the list as needed
+func (r *renderer) listItem(kind listKind, text string) {
+	r.flushParagraph()
+	r.flushQuote()
+	if r.list != kind {
+		r.flushList()
+		r.list = kind
+		r.out.WriteString("<" + listTag(kind) + ">\n")
+	}
+	r.out.WriteString("<li>" + inline(strings.TrimSpace(text)) + "</li>\n")
+}
+
+// flush closes any open block
+func (r *renderer) flush() {
+	r.flushParagraph()
+	r.flushList()
+	r.flushQuote()
+}
+
+func (r *renderer) flushParagraph() {
+	if len(r.paragraph) == 0 {
+		return
+	}
+	r.out.WriteString("<p>" + inline(strings.Join(r.paragraph, " ")) + "</p>\n")
+	r.paragraph = nil
+}
+
+func (r *renderer) flushList() {
+	if r.list == noList {
+		return
+	}
+	r.out.WriteString("</" + listTag(r.list) + ">\n")
+	r.list = noList
+}
+
+func (r *renderer) flushQuote() {
+	if len(r.quote) == 0 {
+		return
+	}
+	r.out.WriteString("<blockquote><p>" + inline(strings.Join(r.quote, " ")) + "</p></blockquote>\n")
+	r.quote = nil
+}
+
+// listTag returns the HTML element for a list kind
+func listTag(kind listKind) string {
+	if kind == orderedList {
+		return "ol"
+	}
+	return "ul"
+}
+
+// inline escapes text and renders inline code, links, strong and emphasis.
+// Code spans are split out first so their content is never reinterpreted.
+func inline(text string) string {
+	var b strings.Builder
+	segments := strings.Split(text, "`")
+	for i, segment := range segments {
+		escaped := html.EscapeString(segment)
+		switch {
+		case i%2 == 1 && i < len(segments)-1:
+			b.WriteString("<code>" + escaped + "</code>")
+		case i%2 == 1:
+			// Unterminated code span: keep the backtick literally
+			b.WriteString("`" + formatSpans(escaped))
+		default:
+			b.WriteString(formatSpans(escaped))
+		}
+	}
+	return b.String()
+}
+
+// formatSpans renders links and emphasis in already escaped text. Links are
+// swapped for placeholders while emphasis is rendered, so asterisks in a
+// link target never become markup; escaped text has no "<", so the
+// placeholders cannot clash with it.
+func formatSpans(escaped string) string {
+	var links []string
+	escaped = linkPattern.ReplaceAllStringFunc(escaped, func(match string) string {
+		m := linkPattern.FindStringSubmatch(match)
+		if !isSafeURL(html.UnescapeString(m[2])) {
+			return m[1]
+		}
+		links = append(links, `<a href="`+m[2]+`" rel="nofollow noopener">`+emphasize(m[1])+`</a>`)
+		return "<" + strconv.Itoa(len(links)-1) + ">"
+	})
+
+	escaped = emphasize(escaped)
+	for i, link := range links {
+		escaped = strings.Replace(escaped, "<"+strconv.Itoa(i)+">", link, 1)
+	}
+	return escaped
+}
+
+// emphasize renders strong and emphasis in already escaped text
+func emphasize(escaped string) string {
+	escaped = strongPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	return emphasisPattern.ReplaceAllString(escaped, "<em>$1</em>")
+}
+
+// isSafeURL allows web, mail and relative links, rejecting schemes such as
+// javascript: or data:
+func isSafeURL(url string) bool {
+	// Browsers skip leading spaces and control characters, so "\x01//host"
+	// is protocol-relative
+	lower := strings.ToLower(strings.TrimFunc(url, func(r rune) bool { return r <= ' ' }))
+	for _, prefix := range []string{"http://", "https://", "mailto:", "/", "#"} {
+		if strings.HasPrefix(lower, prefix) {
+			return !strings.HasPrefix(lower, "//")
+		}
+	}
+	// Relative paths must not smuggle in a scheme before the first slash
+	colon := strings.Index(lower, ":")
+	slash := strings.Index(lower, "/")
+	return colon == -1 || (slash != -1 && slash < colon)
+}
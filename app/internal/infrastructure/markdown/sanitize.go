@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedElements lists the elements Sanitize keeps: those ToHTML produces,
+// and line breaks. Other elements are unwrapped, keeping their text.
+var allowedElements = map[atom.Atom]bool{
+	atom.P: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Blockquote: true, atom.Pre: true, atom.Code: true,
+	atom.Strong: true, atom.Em: true, atom.A: true, atom.Hr: true, atom.Br: true,
+}
+
+// droppedElements are removed along with their content, which is code or
+// markup rather than text to display
+var droppedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true, atom.Embed: true,
+	atom.Template: true, atom.Noscript: true, atom.Textarea: true, atom.Title: true, atom.Svg: true, atom.Math: true,
+}
+
+// Render runs Markdown source through the pipeline post reads use: it is
+// rendered with ToHTML, and the result passes Sanitize, so a flaw in the
+// renderer cannot let markup outside the allow-list through
+func Render(source string) string {
+	return Sanitize(ToHTML(source))
+}
+
+// RenderBasic renders the basic formatting subset comments use: blank lines
+// separate paragraphs, other line breaks are kept, and only inline code,
+// strong, emphasis and links are rendered. Block syntax such as headings or
+// lists is shown as written.
+func RenderBasic(source string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(paragraph, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, inline(line))
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>\n")
+		}
+	}
+	return Sanitize(b.String())
+}
+
+// Sanitize reduces an HTML fragment to an allow-list of text formatting
+// elements. Attributes are dropped except the href of links, which is kept
+// for web, mail and relative targets; links always get
+// rel="nofollow noopener". Comments are removed, and the text of other
+// elements is kept, escaped.
+func Sanitize(fragment string) string {
+	context := &nethtml.Node{Type: nethtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := nethtml.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		// Reading from a string cannot fail; escape everything to be safe
+		return html.EscapeString(fragment)
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		sanitizeNode(&b, n)
+	}
+	return b.String()
+}
+
+// sanitizeNode writes the allowed parts of a node and its children
+func sanitizeNode(b *strings.Builder, n *nethtml.Node) {
+	switch n.Type {
+	case nethtml.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case nethtml.ElementNode:
+	default:
+		return
+	}
+
+	if droppedElements[n.DataAtom] {
+		return
+	}
+	allowed := allowedElements[n.DataAtom]
+	if allowed {
+		b.WriteString("<" + n.Data)
+		if n.DataAtom == atom.A {
+			for _, attr := range n.Attr {
+				if attr.Namespace == "" && attr.Key == "href" && isSafeURL(attr.Val) {
+					b.WriteString(` href="` + html.EscapeString(attr.Val) + `"`)
+					break
+				}
+			}
+			b.WriteString(` rel="nofollow noopener"`)
+		}
+		b.WriteString(">")
+		if n.DataAtom == atom.Hr || n.DataAtom == atom.Br {
+			return
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sanitizeNode(b, child)
+	}
+	if allowed {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
@@ -0,0 +1,402 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Version is the OpenAPI version of converted documents
+const Version = "3.0.3"
+
+// defaultMediaType is used for bodies of operations that declare none
+const defaultMediaType = "application/json"
+
+// operationMethods are the keys of a path item holding operations
+var operationMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+}
+
+// schemaKeys are the keys of a Swagger 2.0 parameter or header that move
+// into its schema
+var schemaKeys = []string{
+	"type", "format", "items", "enum", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+// oauth2Flows maps Swagger 2.0 OAuth2 flows to their OpenAPI 3 names
+var oauth2Flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// Convert converts a Swagger 2.0 JSON document to OpenAPI 3. Bodies and
+// responses get a content entry per media type the operation consumes or
+// produces, definitions become component schemas and the host, base path
+// and schemes become servers; documents without a host are served relative
+// to where they are fetched from. Global parameters and responses, which
+// swag does not generate, are not supported.
+func Convert(swagger []byte) ([]byte, error) {
+	var src map[string]any
+	if err := json.Unmarshal(swagger, &src); err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger document: %w", err)
+	}
+	if version, _ := src["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("unsupported Swagger version %q", version)
+	}
+
+	doc := map[string]any{"openapi": Version, "info": src["info"]}
+	for key, value := range src {
+		if key == "tags" || key == "externalDocs" || key == "security" || strings.HasPrefix(key, "x-") {
+			doc[key] = value
+		}
+	}
+	if servers := convertServers(src); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+
+	consumes := stringList(src["consumes"])
+	produces := stringList(src["produces"])
+	paths := make(map[string]any)
+	srcPaths, _ := src["paths"].(map[string]any)
+	for path, value := range srcPaths {
+		item, _ := value.(map[string]any)
+		converted := make(map[string]any, len(item))
+		for key, value := range item {
+			switch {
+			case operationMethods[key]:
+				operation, _ := value.(map[string]any)
+				converted[key] = convertOperation(operation, consumes, produces)
+			case key == "parameters":
+				converted[key] = convertParameters(value)
+			default:
+				converted[key] = value
+			}
+		}
+		paths[path] = converted
+	}
+	doc["paths"] = paths
+
+	components := make(map[string]any)
+	if definitions, _ := src["definitions"].(map[string]any); len(definitions) > 0 {
+		components["schemas"] = definitions
+	}
+	if definitions, _ := src["securityDefinitions"].(map[string]any); len(definitions) > 0 {
+		schemes := make(map[string]any, len(definitions))
+		for name, value := range definitions {
+			definition, _ := value.(map[string]any)
+			schemes[name] = convertSecurityScheme(definition)
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+
+	rewriteRefs(doc)
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// convertServers builds the servers from the host, base path and schemes
+func convertServers(src map[string]any) []any {
+	host, _ := src["host"].(string)
+	basePath, _ := src["basePath"].(string)
+	basePath = strings.TrimRight(basePath, "/")
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	schemes := stringList(src["schemes"])
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]any{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+// convertOperation moves body and form parameters into the request body
+// and response schemas into content
+func convertOperation(operation map[string]any, consumes, produces []string) map[string]any {
+	if own := stringList(operation["consumes"]); len(own) > 0 {
+		consumes = own
+	}
+	if own := stringList(operation["produces"]); len(own) > 0 {
+		produces = own
+	}
+
+	converted := make(map[string]any, len(operation))
+	for key, value := range operation {
+		switch key {
+		case "parameters", "responses", "consumes", "produces", "schemes":
+		default:
+			converted[key] = value
+		}
+	}
+
+	var params []any
+	var form []map[string]any
+	for _, value := range list(operation["parameters"]) {
+		param, _ := value.(map[string]any)
+		switch param["in"] {
+		case "body":
+			converted["requestBody"] = convertBody(param, consumes)
+		case "formData":
+			form = append(form, param)
+		default:
+			params = append(params, convertParameter(param))
+		}
+	}
+	if len(params) > 0 {
+		converted["parameters"] = params
+	}
+	if len(form) > 0 {
+		converted["requestBody"] = convertForm(form, consumes)
+	}
+
+	responses := make(map[string]any)
+	srcResponses, _ := operation["responses"].(map[string]any)
+	for status, value := range srcResponses {
+		response, _ := value.(map[string]any)
+		responses[status] = convertResponse(response, produces)
+	}
+	converted["responses"] = responses
+	return converted
+}
+
+// convertParameters converts a list of non-body parameters
+func convertParameters(value any) []any {
+	params := make([]any, 0, len(list(value)))
+	for _, value := range list(value) {
+		param, _ := value.(map[string]any)
+		params = append(params, convertParameter(param))
+	}
+	return params
+}
+
+// convertParameter moves the type of a path, query, header or cookie
+// parameter into its schema and its collection format into its style
+func convertParameter(param map[string]any) map[string]any {
+	converted := map[string]any{"schema": extractSchema(param)}
+	for key, value := range param {
+		switch {
+		case key == "collectionFormat", slices.Contains(schemaKeys, key):
+		default:
+			converted[key] = value
+		}
+	}
+
+	if param["type"] == "array" {
+		switch param["collectionFormat"] {
+		case "multi":
+			converted["style"], converted["explode"] = "form", true
+		case "ssv":
+			converted["style"], converted["explode"] = "spaceDelimited", false
+		case "pipes":
+			converted["style"], converted["explode"] = "pipeDelimited", false
+		default:
+			// csv, the default of Swagger 2.0
+			converted["explode"] = false
+		}
+	}
+	return converted
+}
+
+// convertBody converts a body parameter to a request body
+func convertBody(param map[string]any, consumes []string) map[string]any {
+	body := map[string]any{"content": content(param["schema"], consumes)}
+	for _, key := range []string{"description", "required"} {
+		if value, ok := param[key]; ok {
+			body[key] = value
+		}
+	}
+	return body
+}
+
+// convertForm converts form parameters to a request body whose schema has
+// a property per parameter. Forms with files are sent as multipart.
+func convertForm(params []map[string]any, consumes []string) map[string]any {
+	properties := make(map[string]any, len(params))
+	var required []any
+	multipart := slices.Contains(consumes, "multipart/form-data")
+	for _, param := range params {
+		name, _ := param["name"].(string)
+		property := extractSchema(param)
+		if property["type"] == "file" {
+			property = map[string]any{"type": "string", "format": "binary"}
+			multipart = true
+		}
+		if description, ok := param["description"]; ok {
+			property["description"] = description
+		}
+		properties[name] = property
+		if param["required"] == true {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart {
+		mediaType = "multipart/form-data"
+	}
+	return map[string]any{
+		"required": len(required) > 0,
+		"content":  map[string]any{mediaType: map[string]any{"schema": schema}},
+	}
+}
+
+// convertResponse moves the schema and examples of a response into its
+// content and the types of its headers into their schemas
+func convertResponse(response map[string]any, produces []string) map[string]any {
+	converted := make(map[string]any, len(response))
+	for key, value := range response {
+		switch key {
+		case "schema", "examples", "headers":
+		default:
+			converted[key] = value
+		}
+	}
+	if _, ok := converted["description"]; !ok {
+		converted["description"] = ""
+	}
+
+	media := make(map[string]any)
+	if schema, ok := response["schema"].(map[string]any); ok {
+		if schema["type"] == "file" {
+			schema = map[string]any{"type": "string", "format": "binary"}
+		}
+		media = content(schema, produces)
+	}
+	examples, _ := response["examples"].(map[string]any)
+	for mediaType, example := range examples {
+		entry, _ := media[mediaType].(map[string]any)
+		if entry == nil {
+			entry = make(map[string]any)
+			media[mediaType] = entry
+		}
+		entry["example"] = example
+	}
+	if len(media) > 0 {
+		converted["content"] = media
+	}
+
+	if headers, _ := response["headers"].(map[string]any); len(headers) > 0 {
+		convertedHeaders := make(map[string]any, len(headers))
+		for name, value := range headers {
+			header, _ := value.(map[string]any)
+			convertedHeader := map[string]any{"schema": extractSchema(header)}
+			if description, ok := header["description"]; ok {
+				convertedHeader["description"] = description
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+	return converted
+}
+
+// convertSecurityScheme converts a security definition; basic
+// authentication becomes an HTTP scheme and OAuth2 flows are renamed
+func convertSecurityScheme(definition map[string]any) map[string]any {
+	scheme := make(map[string]any)
+	for key, value := range definition {
+		if key == "description" || strings.HasPrefix(key, "x-") {
+			scheme[key] = value
+		}
+	}
+
+	switch definition["type"] {
+	case "basic":
+		scheme["type"], scheme["scheme"] = "http", "basic"
+	case "oauth2":
+		flow := map[string]any{"scopes": map[string]any{}}
+		for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if value, ok := definition[key]; ok {
+				flow[key] = value
+			}
+		}
+		name, _ := definition["flow"].(string)
+		scheme["type"] = "oauth2"
+		scheme["flows"] = map[string]any{oauth2Flows[name]: flow}
+	default:
+		for _, key := range []string{"type", "name", "in"} {
+			if value, ok := definition[key]; ok {
+				scheme[key] = value
+			}
+		}
+	}
+	return scheme
+}
+
+// content returns the content of a body with the schema in each media type
+func content(schema any, mediaTypes []string) map[string]any {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{defaultMediaType}
+	}
+	media := make(map[string]any, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		media[mediaType] = map[string]any{"schema": schema}
+	}
+	return media
+}
+
+// extractSchema returns the schema keys of a parameter or header
+func extractSchema(param map[string]any) map[string]any {
+	schema := make(map[string]any)
+	for _, key := range schemaKeys {
+		if value, ok := param[key]; ok {
+			schema[key] = value
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema["items"] = extractSchema(items)
+	}
+	return schema
+}
+
+// rewriteRefs points references to definitions at the component schemas
+func rewriteRefs(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				v[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			rewriteRefs(child)
+		}
+	case []any:
+		for _, child := range v {
+			rewriteRefs(child)
+		}
+	}
+}
+
+// list returns a JSON array, or nil
+func list(value any) []any {
+	items, _ := value.([]any)
+	return items
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value any) []string {
+	var strs []string
+	for _, item := range list(value) {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
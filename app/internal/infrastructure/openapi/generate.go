@@ -0,0 +1,49 @@
+// Package openapi builds the OpenAPI 3 document of the API from the swag
+// annotations of the handlers, so clients can be generated from it
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/swaggo/swag"
+)
+
+// Default locations, relative to the module root
+const (
+	// MainFile holds the general API annotations: title, version and
+	// security schemes
+	MainFile = "cmd/server/main.go"
+	// OutputFile is where the document is written and embedded from
+	OutputFile = "docs/openapi.json"
+)
+
+// parseDepth bounds how deep swag follows imports when resolving types
+const parseDepth = 100
+
+// quiet discards the progress swag prints for every type it generates
+type quiet struct{}
+
+func (quiet) Printf(format string, v ...any) {}
+
+// Generate parses the annotations of the Go files below the module root dir,
+// starting with the general API info in mainFile, and returns the OpenAPI 3
+// document. Annotations are written in swag syntax, which describes Swagger
+// 2.0, and converted; tests are not parsed.
+func Generate(dir, mainFile string) ([]byte, error) {
+	parser := swag.New(
+		swag.SetDebugger(quiet{}),
+		swag.SetExcludedDirsAndFiles(filepath.Join(dir, "tests")),
+	)
+	parser.ParseInternal = true
+	if err := parser.ParseAPIMultiSearchDir([]string{dir}, mainFile, parseDepth); err != nil {
+		return nil, fmt.Errorf("failed to parse annotations: %w", err)
+	}
+
+	swagger, err := json.Marshal(parser.GetSwagger())
+	if err != nil {
+		return nil, err
+	}
+	return Convert(swagger)
+}
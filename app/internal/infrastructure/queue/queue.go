@@ -0,0 +1,236 @@
+// Package queue runs background jobs stored in the database, retrying
+// failed jobs with exponential backoff
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-platform/internal/application/service"
+)
+
+// Job statuses
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// ErrNoHandler is recorded on jobs whose kind has no registered handler
+var ErrNoHandler = errors.New("no handler registered for job kind")
+
+// Job is a unit of background work
+type Job struct {
+	ID        int             `db:"id"`
+	Kind      string          `db:"kind"`
+	Payload   json.RawMessage `db:"payload"`
+	Status    string          `db:"status"`
+	Attempts  int             `db:"attempts"`
+	LastError string          `db:"last_error"`
+	RunAt     time.Time       `db:"run_at"`
+	CreatedAt time.Time       `db:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
+
+// Store persists jobs
+type Store interface {
+	Create(ctx context.Context, job *Job) error
+	// ClaimDue marks up to limit due jobs as running for the lease duration
+	// and returns them with their attempt counted. Running jobs whose lease
+	// has expired are due again, so work survives a crashed worker.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Job, error)
+	Complete(ctx context.Context, id int) error
+	Retry(ctx context.Context, id int, runAt time.Time, lastError string) error
+	Fail(ctx context.Context, id int, lastError string) error
+	// Depths counts the unfinished jobs
+	Depths(ctx context.Context, now time.Time) (Depths, error)
+}
+
+// Depths are the numbers of unfinished jobs by status
+type Depths struct {
+	Pending int `json:"pending" db:"pending"`
+	// Due counts the pending jobs whose run time has come
+	Due     int `json:"due" db:"due"`
+	Running int `json:"running" db:"running"`
+	Failed  int `json:"failed" db:"failed"`
+}
+
+// Handler processes the payload of a job. Returning an error retries the job.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Options configures how jobs are processed
+type Options struct {
+	// BatchSize is the number of jobs claimed per run
+	BatchSize int
+	// MaxAttempts is the number of attempts before a job is marked failed
+	MaxAttempts int
+	// Lease is how long a claimed job is reserved for its worker
+	Lease time.Duration
+	// BaseDelay is the delay before the first retry; it doubles per attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the retry delay
+	MaxDelay time.Duration
+}
+
+// DefaultOptions are used for options left at zero
+var DefaultOptions = Options{
+	BatchSize:   10,
+	MaxAttempts: 8,
+	Lease:       5 * time.Minute,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    time.Hour,
+}
+
+// Queue enqueues jobs and runs them with the registered handlers
+type Queue struct {
+	store    Store
+	handlers map[string]Handler
+	opts     Options
+	logger   service.Logger
+}
+
+// New creates a new queue
+func New(store Store, opts Options, logger service.Logger) *Queue {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultOptions.BatchSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultOptions.MaxAttempts
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = DefaultOptions.Lease
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultOptions.BaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultOptions.MaxDelay
+	}
+
+	return &Queue{
+		store:    store,
+		handlers: make(map[string]Handler),
+		opts:     opts,
+		logger:   logger,
+	}
+}
+
+// Handle registers the handler for a job kind. Handlers must be registered
+// before jobs are run.
+func (q *Queue) Handle(kind string, handler Handler) {
+	q.handlers[kind] = handler
+}
+
+// Enqueue stores a job to run as soon as a worker is free
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		Kind:      kind,
+		Payload:   data,
+		Status:    StatusPending,
+		RunAt:     now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := q.store.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	q.logger.Debug(ctx, "job enqueued", "job", job.ID, "kind", kind)
+	return nil
+}
+
+// RunDue claims due jobs and runs them. It is meant to be called
+// periodically by the scheduler; failures of individual jobs are recorded
+// on the job rather than returned.
+func (q *Queue) RunDue(ctx context.Context) error {
+	jobs, err := q.store.ClaimDue(ctx, time.Now(), q.opts.Lease, q.opts.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			// Unfinished jobs become due again when their lease expires
+			return ctx.Err()
+		}
+		q.run(ctx, job)
+	}
+	return nil
+}
+
+// run executes a claimed job and records the outcome
+func (q *Queue) run(ctx context.Context, job *Job) {
+	handler, ok := q.handlers[job.Kind]
+	if !ok {
+		q.logger.Error(ctx, "job has no handler", "job", job.ID, "kind", job.Kind)
+		q.record(ctx, job, q.store.Fail(ctx, job.ID, ErrNoHandler.Error()))
+		return
+	}
+
+	started := time.Now()
+	err := handler(ctx, job.Payload)
+	if err == nil {
+		q.logger.Info(ctx, "job completed", "job", job.ID, "kind", job.Kind, "attempt", job.Attempts, "duration", time.Since(started).String())
+		q.record(ctx, job, q.store.Complete(ctx, job.ID))
+		return
+	}
+
+	if job.Attempts >= q.opts.MaxAttempts {
+		q.logger.Error(ctx, "job failed permanently", "job", job.ID, "kind", job.Kind, "attempts", job.Attempts, "error", err.Error())
+		q.record(ctx, job, q.store.Fail(ctx, job.ID, err.Error()))
+		return
+	}
+
+	delay := q.Backoff(job.Attempts)
+	q.logger.Warn(ctx, "job failed, retrying", "job", job.ID, "kind", job.Kind, "attempt", job.Attempts, "retryIn", delay.String(), "error", err.Error())
+	q.record(ctx, job, q.store.Retry(ctx, job.ID, time.Now().Add(delay), err.Error()))
+}
+
+// record logs a failure to store the outcome of a job; the job is run
+// again once its lease expires
+func (q *Queue) record(ctx context.Context, job *Job, err error) {
+	if err != nil {
+		q.logger.Error(ctx, "failed to record job outcome", "job", job.ID, "kind", job.Kind, "error", err.Error())
+	}
+}
+
+// Depths counts the unfinished jobs; a growing number of due jobs means the
+// workers are falling behind
+func (q *Queue) Depths(ctx context.Context) (Depths, error) {
+	depths, err := q.store.Depths(ctx, time.Now())
+	if err != nil {
+		return Depths{}, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return depths, nil
+}
+
+// MaxAttempts returns the number of attempts before a job is marked failed
+func (q *Queue) MaxAttempts() int {
+	return q.opts.MaxAttempts
+}
+
+// Backoff returns the delay before retrying a job that failed the given
+// attempt: BaseDelay doubled per earlier attempt, capped at MaxDelay
+func (q *Queue) Backoff(attempt int) time.Duration {
+	delay := q.opts.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= q.opts.MaxDelay {
+			return q.opts.MaxDelay
+		}
+	}
+	return delay
+}
+
+// Verify that Queue implements the service.JobQueue interface
+var _ service.JobQueue = (*Queue)(nil)
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTTL is how long an unused limiter is kept
+const limiterIdleTTL = time.Hour
+
+// limiterEntry is a token bucket and when it was last used
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// MemoryStore implements Store with a token bucket per key in process
+// memory. Budgets are not shared between instances; use the Redis store when
+// running more than one server.
+type MemoryStore struct {
+	mu       sync.Mutex
+	limiters map[string]*limiterEntry
+}
+
+// NewMemoryStore creates an in-memory store with a background cleanup of
+// idle limiters
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		limiters: make(map[string]*limiterEntry),
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.cleanup()
+		}
+	}()
+
+	return s
+}
+
+// Allow counts a request for key and reports whether it fits in limit
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, exists := s.limiters[key]
+	if !exists || now.Sub(entry.lastSeen) > limiterIdleTTL {
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)}
+		s.limiters[key] = entry
+	}
+	entry.lastSeen = now
+
+	if !entry.limiter.AllowN(now, 1) {
+		tokens := entry.limiter.TokensAt(now)
+		wait := time.Duration((1 - tokens) / limit.RequestsPerSecond * float64(time.Second))
+		return Result{Allowed: false, RetryAfter: wait}, nil
+	}
+	return Result{Allowed: true, Remaining: int(entry.limiter.TokensAt(now))}, nil
+}
+
+// cleanup removes idle limiters
+func (s *MemoryStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-limiterIdleTTL)
+	for key, entry := range s.limiters {
+		if entry.lastSeen.Before(cutoff) {
+			delete(s.limiters, key)
+		}
+	}
+}
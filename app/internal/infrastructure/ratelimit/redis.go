@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// redisKeyPrefix namespaces rate limit keys in Redis
+const redisKeyPrefix = "ratelimit:"
+
+// slidingWindowScript keeps the timestamps of the requests in the last window
+// in a sorted set and admits a request while there are fewer than the limit.
+// It runs atomically and uses the Redis clock, so every server shares the
+// same view regardless of clock drift. Timestamps are in microseconds.
+//
+// KEYS[1] key, ARGV[1] window, ARGV[2] limit, ARGV[3] unique member
+// Returns {allowed, remaining, retry after}
+const slidingWindowScript = `
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count >= limit then
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	return {0, 0, tonumber(oldest[2]) + window - now}
+end
+
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+return {1, limit - count - 1, 0}
+`
+
+// RedisStore implements Store on top of Redis with a sliding window log, so
+// budgets are shared by every server instance
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed rate limit store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Allow counts a request for key and reports whether it fits in limit. At
+// most limit.Burst requests are allowed in any limit.Window().
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	reply, err := s.client.Do(ctx, "EVAL", slidingWindowScript, "1", redisKeyPrefix+key,
+		strconv.FormatInt(limit.Window().Microseconds(), 10),
+		strconv.Itoa(limit.Burst),
+		strconv.FormatUint(rand.Uint64(), 36),
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("redis: unexpected rate limit reply %v", reply)
+	}
+	var numbers [3]int64
+	for i, value := range values {
+		if numbers[i], ok = value.(int64); !ok {
+			return Result{}, fmt.Errorf("redis: unexpected rate limit reply %v", reply)
+		}
+	}
+
+	return Result{
+		Allowed:    numbers[0] == 1,
+		Remaining:  int(numbers[1]),
+		RetryAfter: time.Duration(numbers[2]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// Limit is a request budget: RequestsPerSecond on average, with bursts of up
+// to Burst requests
+type Limit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// Window returns the period in which at most Burst requests are allowed
+func (l Limit) Window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.RequestsPerSecond * float64(time.Second))
+}
+
+// Result is the outcome of counting a request
+type Result struct {
+	// Allowed reports whether the request fits in the budget
+	Allowed bool
+	// Remaining is the number of requests left in the budget
+	Remaining int
+	// RetryAfter is how long to wait before the next request is allowed
+	RetryAfter time.Duration
+}
+
+// Store tracks request budgets per key
+type Store interface {
+	// Allow counts a request for key and reports whether it fits in limit
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// NewStore creates a rate limit store based on configuration
+func NewStore(cfg *config.Config, client *redis.Client) Store {
+	switch strings.ToLower(cfg.RateLimit.Backend) {
+	case "redis":
+		return NewRedisStore(client)
+	default:
+		return NewMemoryStore()
+	}
+}
@@ -0,0 +1,270 @@
+// Package realtime pushes messages to the WebSocket connections of
+// signed-in users
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// Hub errors
+var (
+	ErrTooManyConnections = errors.New("too many realtime connections")
+	ErrClosed             = errors.New("realtime hub closed")
+)
+
+// maxClientMessage bounds the frames read from clients, which have nothing
+// to send
+const maxClientMessage = 4 << 10
+
+// Settings holds the configurable behaviour of a hub
+type Settings struct {
+	// SendBuffer is how many messages are queued for a connection; a
+	// connection whose queue is full is too slow and is closed, so one
+	// client never holds up the others
+	SendBuffer int
+	// MaxConnections bounds the connections of each user
+	MaxConnections int
+	// PingInterval is how often idle connections are pinged, so proxies
+	// keep them open and dead peers are noticed
+	PingInterval time.Duration
+	// WriteTimeout bounds each write to a connection
+	WriteTimeout time.Duration
+}
+
+// SettingsFromConfig returns the configured hub settings
+func SettingsFromConfig(cfg *config.Config) Settings {
+	return Settings{
+		SendBuffer:     cfg.Realtime.SendBuffer,
+		MaxConnections: cfg.Realtime.MaxConnections,
+		PingInterval:   time.Duration(cfg.Realtime.PingInterval) * time.Second,
+		WriteTimeout:   time.Duration(cfg.Realtime.WriteTimeout) * time.Second,
+	}
+}
+
+// Stats describes the open connections and the messages pushed to them
+type Stats struct {
+	Users       int   `json:"users"`
+	Connections int   `json:"connections"`
+	Pushed      int64 `json:"pushed"`
+	// Dropped counts the connections closed for being too slow
+	Dropped int64 `json:"dropped"`
+}
+
+// Hub keeps the open connections of each user and pushes messages to them
+type Hub struct {
+	settings Settings
+	logger   service.Logger
+
+	mu     sync.Mutex
+	conns  map[int]map[*Session]struct{}
+	closed bool
+
+	serving sync.WaitGroup
+	pushed  atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewHub creates a hub without connections
+func NewHub(settings Settings, logger service.Logger) *Hub {
+	return &Hub{
+		settings: settings,
+		logger:   logger,
+		conns:    make(map[int]map[*Session]struct{}),
+	}
+}
+
+// Session is a connection of a user, registered with the hub before the
+// WebSocket handshake so excess connections are refused with an HTTP error
+type Session struct {
+	hub    *Hub
+	userID int
+	send   chan []byte
+	done   chan struct{}
+	once   sync.Once
+	// released tells Shutdown the session has ended
+	released sync.Once
+}
+
+// Accept registers a connection of a user. The session must be closed
+// once the connection ends, whether or not it was served.
+func (h *Hub) Accept(userID int) (*Session, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrClosed
+	}
+	if h.settings.MaxConnections > 0 && len(h.conns[userID]) >= h.settings.MaxConnections {
+		return nil, ErrTooManyConnections
+	}
+
+	s := &Session{
+		hub:    h,
+		userID: userID,
+		send:   make(chan []byte, max(h.settings.SendBuffer, 1)),
+		done:   make(chan struct{}),
+	}
+	if h.conns[userID] == nil {
+		h.conns[userID] = make(map[*Session]struct{})
+	}
+	h.conns[userID][s] = struct{}{}
+	h.serving.Add(1)
+	return s, nil
+}
+
+// Push queues a message for every connection of a user. Connections whose
+// queue is full are closed instead of waited for.
+func (h *Hub) Push(userID int, msg service.RealtimeMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sessions := h.conns[userID]
+	if len(sessions) == 0 {
+		return
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Error(context.Background(), "failed to encode realtime message", "userID", userID, "type", msg.Type, "error", err.Error())
+		return
+	}
+
+	for s := range sessions {
+		select {
+		case s.send <- body:
+			h.pushed.Add(1)
+		default:
+			h.dropped.Add(1)
+			h.remove(s)
+			h.logger.Warn(context.Background(), "realtime connection too slow, closed", "userID", userID, "type", msg.Type)
+		}
+	}
+}
+
+// Stats returns the open connections and the messages pushed so far
+func (h *Hub) Stats() Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := Stats{Users: len(h.conns), Pushed: h.pushed.Load(), Dropped: h.dropped.Load()}
+	for _, sessions := range h.conns {
+		stats.Connections += len(sessions)
+	}
+	return stats
+}
+
+// Shutdown closes every connection, refuses new ones and waits for their
+// sessions to be closed or ctx to be done
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for _, sessions := range h.conns {
+		for s := range sessions {
+			h.remove(s)
+		}
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.serving.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// remove unregisters a session and ends its connection; h.mu must be held
+func (h *Hub) remove(s *Session) {
+	sessions := h.conns[s.userID]
+	if _, ok := sessions[s]; !ok {
+		return
+	}
+	delete(sessions, s)
+	if len(sessions) == 0 {
+		delete(h.conns, s.userID)
+	}
+	s.once.Do(func() { close(s.done) })
+}
+
+// Close unregisters the session and ends its connection
+func (s *Session) Close() {
+	s.end()
+	s.released.Do(s.hub.serving.Done)
+}
+
+// end unregisters the session and ends its connection
+func (s *Session) end() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Serve writes the messages pushed to the session to a WebSocket
+// connection until either side closes it. Clients have nothing to send;
+// their frames are read only to notice when they go away.
+func (s *Session) Serve(ws *websocket.Conn) {
+	defer s.Close()
+	defer ws.Close()
+
+	// The connection may carry the deadlines of the HTTP server
+	ws.SetDeadline(time.Time{})
+	ws.MaxPayloadBytes = maxClientMessage
+
+	go func() {
+		var discard string
+		for {
+			if err := websocket.Message.Receive(ws, &discard); err != nil {
+				s.end()
+				return
+			}
+		}
+	}()
+
+	var pings <-chan time.Time
+	if s.hub.settings.PingInterval > 0 {
+		ticker := time.NewTicker(s.hub.settings.PingInterval)
+		defer ticker.Stop()
+		pings = ticker.C
+	}
+
+	for {
+		select {
+		case body := <-s.send:
+			if err := s.write(ws, websocket.TextFrame, body); err != nil {
+				return
+			}
+		case <-pings:
+			if err := s.write(ws, websocket.PingFrame, nil); err != nil {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// write sends a frame within the write timeout
+func (s *Session) write(ws *websocket.Conn, frameType byte, payload []byte) error {
+	if s.hub.settings.WriteTimeout > 0 {
+		ws.SetWriteDeadline(time.Now().Add(s.hub.settings.WriteTimeout))
+	}
+	ws.PayloadType = frameType
+	_, err := ws.Write(payload)
+	return err
+}
+
+var _ service.Pusher = (*Hub)(nil)
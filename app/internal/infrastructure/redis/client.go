@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrNil is returned when Redis replies with a nil bulk string or array
+var ErrNil = errors.New("redis: nil reply")
+
+// maxIdleConns bounds the number of pooled connections kept open
+const maxIdleConns = 10
+
+// ReplyError is an error reply sent by the Redis server
+type ReplyError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ReplyError) Error() string {
+	return "redis: " + e.Message
+}
+
+// Client wraps go-redis with the handful of commands the application needs.
+// Replies are read as RESP2, so scripts return the types callers expect.
+type Client struct {
+	rdb *goredis.Client
+}
+
+// NewClient creates a new Redis client. Connections are opened lazily on the
+// first command.
+func NewClient(addr, password string, db int) *Client {
+	return &Client{
+		rdb: goredis.NewClient(&goredis.Options{
+			Addr:            addr,
+			Password:        password,
+			DB:              db,
+			Protocol:        2,
+			DialTimeout:     5 * time.Second,
+			MaxIdleConns:    maxIdleConns,
+			DisableIdentity: true,
+			// Commands are bounded by the deadline of their context
+			ContextTimeoutEnabled: true,
+		}),
+	}
+}
+
+// Do sends a command and returns the parsed reply. Replies are returned as
+// string, int64, []any or nil; Redis error replies are returned as
+// *ReplyError.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	cmdArgs := make([]any, len(args))
+	for i, arg := range args {
+		cmdArgs[i] = arg
+	}
+
+	reply, err := c.rdb.Do(ctx, cmdArgs...).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		var redisErr goredis.Error
+		if errors.As(err, &redisErr) {
+			return nil, &ReplyError{Message: redisErr.Error()}
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Ping checks connectivity to the server
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Set stores a value with an optional time-to-live
+func (c *Client) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.wrap(c.rdb.Set(ctx, key, value, ttl).Err())
+}
+
+// Get returns the value stored at key, or ErrNil if it does not exist
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.rdb.Get(ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", ErrNil
+	}
+	return value, c.wrap(err)
+}
+
+// Exists reports whether the key exists
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.rdb.Exists(ctx, key).Result()
+	return n > 0, c.wrap(err)
+}
+
+// Del removes the given keys
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	return c.wrap(c.rdb.Del(ctx, keys...).Err())
+}
+
+// Int runs a command that replies with an integer
+func (c *Client) Int(ctx context.Context, args ...string) (int64, error) {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply type %T", reply)
+	}
+	return n, nil
+}
+
+// Close closes the connection pool
+func (c *Client) Close() error {
+	return c.rdb.Close()
+}
+
+// wrap returns Redis error replies as *ReplyError
+func (c *Client) wrap(err error) error {
+	var redisErr goredis.Error
+	if err != nil && errors.As(err, &redisErr) {
+		return &ReplyError{Message: redisErr.Error()}
+	}
+	return err
+}
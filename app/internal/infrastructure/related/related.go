@@ -0,0 +1,73 @@
+// Package related implements the strategies that find related posts.
+package related
+
+import (
+	"context"
+	"strings"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// New creates the relatedness strategy selected by configuration
+func New(cfg *config.Config, posts post.ReadRepository) post.RelatednessStrategy {
+	titles := NewTitleStrategy(posts, cfg.Related.TitlePool)
+	switch strings.ToLower(cfg.Related.Strategy) {
+	case "titles":
+		return titles
+	default:
+		// Shared tags are the stronger signal; titles fill the list for
+		// posts with few or rare tags
+		return Chain{NewTagStrategy(posts), titles}
+	}
+}
+
+// Chain combines strategies: posts are taken from each strategy in turn,
+// skipping those already found, until the limit is reached
+type Chain []post.RelatednessStrategy
+
+// Related lists the posts the strategies find, in the order of the chain
+func (c Chain) Related(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	seen := map[int]bool{p.ID: true}
+	var related []*post.Post
+	for _, strategy := range c {
+		found, err := strategy.Related(ctx, p, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range found {
+			if seen[candidate.ID] {
+				continue
+			}
+			seen[candidate.ID] = true
+			related = append(related, candidate)
+			if len(related) == limit {
+				return related, nil
+			}
+		}
+	}
+	return related, nil
+}
+
+// TagStrategy relates posts sharing tags, those sharing the most first
+type TagStrategy struct {
+	posts post.ReadRepository
+}
+
+// NewTagStrategy creates a new TagStrategy
+func NewTagStrategy(posts post.ReadRepository) *TagStrategy {
+	return &TagStrategy{posts: posts}
+}
+
+// Related lists the published posts sharing tags with the post
+func (s *TagStrategy) Related(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	if len(p.Tags) == 0 {
+		return nil, nil
+	}
+	return s.posts.ListSharingTags(ctx, p.ID, limit)
+}
+
+var (
+	_ post.RelatednessStrategy = Chain(nil)
+	_ post.RelatednessStrategy = (*TagStrategy)(nil)
+)
@@ -0,0 +1,137 @@
+package related
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/post"
+)
+
+// defaultTitlePool is used when no pool size is configured
+const defaultTitlePool = 500
+
+// stopWords are left out of title comparisons; they carry no topic
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "how": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "the": true, "this": true, "to": true, "what": true, "when": true, "why": true,
+	"with": true, "you": true, "your": true,
+}
+
+// TitleStrategy relates posts with similar titles. Titles are compared as
+// TF-IDF weighted word vectors by cosine similarity, so words that appear in
+// few titles count more than common ones. Only the latest published posts,
+// up to the pool size, are compared.
+type TitleStrategy struct {
+	posts post.ReadRepository
+	pool  int
+}
+
+// NewTitleStrategy creates a new TitleStrategy comparing titles with the
+// latest pool published posts
+func NewTitleStrategy(posts post.ReadRepository, pool int) *TitleStrategy {
+	if pool <= 0 {
+		pool = defaultTitlePool
+	}
+	return &TitleStrategy{posts: posts, pool: pool}
+}
+
+// Related lists the published posts whose titles are most similar to the
+// title of the post; posts sharing no words are left out
+func (s *TitleStrategy) Related(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	candidates, err := s.posts.ListPublished(ctx, s.pool, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Similar(p, candidates, limit), nil
+}
+
+// Similar ranks the candidates by the TF-IDF similarity of their titles to
+// the title of p. The candidates are the corpus word rarity is measured
+// over; ties keep their order.
+func Similar(p *post.Post, candidates []*post.Post, limit int) []*post.Post {
+	words := titleWords(p.Title)
+	if len(words) == 0 {
+		return nil
+	}
+
+	// Document frequencies over the candidates and the post itself
+	documents := make([]map[string]int, len(candidates))
+	frequency := make(map[string]int)
+	for term := range words {
+		frequency[term]++
+	}
+	for i, candidate := range candidates {
+		documents[i] = titleWords(candidate.Title)
+		for term := range documents[i] {
+			frequency[term]++
+		}
+	}
+	count := float64(len(candidates) + 1)
+	weigh := func(terms map[string]int) map[string]float64 {
+		weights := make(map[string]float64, len(terms))
+		for term, n := range terms {
+			// Smoothed IDF keeps words found in every title above zero
+			weights[term] = float64(n) * (math.Log((1+count)/(1+float64(frequency[term]))) + 1)
+		}
+		return weights
+	}
+	target := weigh(words)
+
+	type scored struct {
+		post  *post.Post
+		score float64
+	}
+	var matches []scored
+	for i, candidate := range candidates {
+		if candidate.ID == p.ID || !candidate.IsPublished() {
+			continue
+		}
+		if score := cosine(target, weigh(documents[i])); score > 0 {
+			matches = append(matches, scored{candidate, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	related := make([]*post.Post, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		related = append(related, m.post)
+	}
+	return related
+}
+
+// titleWords counts the words of a title, lowercased, leaving out stop
+// words and single characters
+func titleWords(title string) map[string]int {
+	words := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(word) > 1 && !stopWords[word] {
+			words[word]++
+		}
+	}
+	return words
+}
+
+// cosine returns the cosine similarity of two weight vectors
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+var _ post.RelatednessStrategy = (*TitleStrategy)(nil)
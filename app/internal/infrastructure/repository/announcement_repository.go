@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/announcement"
+)
+
+// AnnouncementRepository implements the announcement.Repository interface
+// using SQLX
+type AnnouncementRepository struct {
+	db *sqlx.DB
+}
+
+// NewAnnouncementRepository creates a new AnnouncementRepository instance
+func NewAnnouncementRepository(db *sqlx.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// announcementRow is the stored form of an announcement
+type announcementRow struct {
+	announcement.Announcement
+	SegmentJSON []byte `db:"segment"`
+}
+
+// Create inserts a new announcement
+func (r *AnnouncementRepository) Create(ctx context.Context, a *announcement.Announcement) error {
+	segment, err := json.Marshal(a.Segment)
+	if err != nil {
+		return fmt.Errorf("failed to encode announcement segment: %w", err)
+	}
+
+	query := `
+		INSERT INTO announcements (subject, body, segment, created_by, status, recipients, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, a.Subject, a.Body, segment, a.CreatedBy, a.Status, a.Recipients, a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create announcement: %w", err)
+	}
+
+	a.ID = id
+	return nil
+}
+
+// GetByID retrieves an announcement by ID
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id int) (*announcement.Announcement, error) {
+	query := `
+		SELECT id, subject, body, segment, created_by, status, recipients, sent, failed, skipped,
+			created_at, updated_at, completed_at
+		FROM announcements
+		WHERE id = ?
+	`
+
+	var row announcementRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, announcement.ErrAnnouncementNotFound
+		}
+		return nil, fmt.Errorf("failed to get announcement: %w", err)
+	}
+
+	a := row.Announcement
+	if err := json.Unmarshal(row.SegmentJSON, &a.Segment); err != nil {
+		return nil, fmt.Errorf("failed to decode announcement segment: %w", err)
+	}
+	return &a, nil
+}
+
+// Update stores the status and delivery counters of an announcement
+func (r *AnnouncementRepository) Update(ctx context.Context, a *announcement.Announcement) error {
+	query := `
+		UPDATE announcements
+		SET status = ?, sent = ?, failed = ?, skipped = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), a.Status, a.Sent, a.Failed, a.Skipped, a.CompletedAt, time.Now(), a.ID); err != nil {
+		return fmt.Errorf("failed to update announcement: %w", err)
+	}
+
+	return nil
+}
+
+// CountRecipients counts the active accounts in a segment
+func (r *AnnouncementRepository) CountRecipients(ctx context.Context, segment announcement.Segment) (int, error) {
+	where, args := segmentFilter(segment)
+	query := `SELECT COUNT(*) FROM users u WHERE ` + where
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(query), args...); err != nil {
+		return 0, fmt.Errorf("failed to count announcement recipients: %w", err)
+	}
+
+	return count, nil
+}
+
+// PendingRecipients returns the next recipients without a delivery
+func (r *AnnouncementRepository) PendingRecipients(ctx context.Context, a *announcement.Announcement, limit int) ([]*announcement.Recipient, error) {
+	where, args := segmentFilter(a.Segment)
+	query := `
+		SELECT u.id, u.name, u.email, (s.user_id IS NOT NULL) AS unsubscribed
+		FROM users u
+		LEFT JOIN announcement_unsubscribes s ON s.user_id = u.id
+		WHERE ` + where + `
+			AND NOT EXISTS (
+				SELECT 1 FROM announcement_deliveries d
+				WHERE d.announcement_id = ? AND d.user_id = u.id
+			)
+		ORDER BY u.id
+		LIMIT ?
+	`
+	args = append(args, a.ID, limit)
+
+	var recipients []*announcement.Recipient
+	if err := conn(ctx, r.db).SelectContext(ctx, &recipients, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get announcement recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// RecordDelivery stores the outcome of sending an announcement to a user;
+// a repeated delivery replaces the earlier outcome
+func (r *AnnouncementRepository) RecordDelivery(ctx context.Context, d *announcement.Delivery) error {
+	query := `
+		INSERT INTO announcement_deliveries (announcement_id, user_id, email, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + upsert(r.db, "announcement_id, user_id", "email", "status", "error", "created_at")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), d.AnnouncementID, d.UserID, d.Email, d.Status, d.Error, d.CreatedAt); err != nil {
+		return fmt.Errorf("failed to record announcement delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries returns deliveries of an announcement with a status
+func (r *AnnouncementRepository) ListDeliveries(ctx context.Context, announcementID int, status string, limit int) ([]*announcement.Delivery, error) {
+	query := `
+		SELECT announcement_id, user_id, email, status, error, created_at
+		FROM announcement_deliveries
+		WHERE announcement_id = ? AND status = ?
+		ORDER BY user_id
+		LIMIT ?
+	`
+
+	var deliveries []*announcement.Delivery
+	if err := conn(ctx, r.db).SelectContext(ctx, &deliveries, r.db.Rebind(query), announcementID, status, limit); err != nil {
+		return nil, fmt.Errorf("failed to list announcement deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Unsubscribe stops announcements to a user; unsubscribing twice is a no-op
+func (r *AnnouncementRepository) Unsubscribe(ctx context.Context, userID int) error {
+	query := insertIgnore(r.db, `INSERT INTO announcement_unsubscribes (user_id, created_at) VALUES (?, ?)`)
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to unsubscribe user: %w", err)
+	}
+
+	return nil
+}
+
+// segmentFilter builds the condition selecting the active accounts of a
+// segment from users aliased as u. Accounts scheduled for deletion and
+// emails shared with another account are never mailed.
+func segmentFilter(segment announcement.Segment) (string, []any) {
+	conditions := []string{
+		"u.deleted_at IS NULL",
+		"u.deletion_scheduled_at IS NULL",
+		"u.email_conflict = FALSE",
+	}
+	var args []any
+
+	if len(segment.Roles) > 0 {
+		placeholders := make([]string, len(segment.Roles))
+		for i, role := range segment.Roles {
+			placeholders[i] = "?"
+			args = append(args, string(role))
+		}
+		conditions = append(conditions, "u.role IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if segment.RegisteredBefore != nil {
+		conditions = append(conditions, "u.created_at < ?")
+		args = append(args, *segment.RegisteredBefore)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// Verify that AnnouncementRepository implements the announcement.Repository interface
+var _ announcement.Repository = (*AnnouncementRepository)(nil)
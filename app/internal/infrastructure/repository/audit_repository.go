@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/audit"
+)
+
+// AuditRepository implements the audit.Repository interface using SQLX
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditRepository creates a new AuditRepository instance
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// auditRow is the stored form of an audit log entry
+type auditRow struct {
+	audit.Entry
+	MetadataJSON []byte `db:"metadata"`
+}
+
+// Create inserts an audit log entry
+func (r *AuditRepository) Create(ctx context.Context, entry *audit.Entry) error {
+	var metadata []byte
+	if len(entry.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(entry.Metadata); err != nil {
+			return fmt.Errorf("failed to encode audit metadata: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO audit_logs (action, user_id, metadata, ip_address, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, entry.Action, entry.UserID, metadata, entry.IPAddress, entry.RequestID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// List returns the entries matching a filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter, limit, offset int) ([]*audit.Entry, error) {
+	var conditions []string
+	var args []any
+	if filter.UserID > 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.RequestID != "" {
+		conditions = append(conditions, "request_id = ?")
+		args = append(args, filter.RequestID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	query := `SELECT id, action, user_id, metadata, ip_address, request_id, created_at FROM audit_logs`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	var rows []auditRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	entries := make([]*audit.Entry, 0, len(rows))
+	for _, row := range rows {
+		entry := row.Entry
+		if len(row.MetadataJSON) > 0 {
+			if err := json.Unmarshal(row.MetadataJSON, &entry.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode audit metadata: %w", err)
+			}
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// Verify that AuditRepository implements the audit.Repository interface
+var _ audit.Repository = (*AuditRepository)(nil)
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/banner"
+)
+
+// bannerColumns are the columns selected for a banner; banners of deleted
+// admins have no creator
+const bannerColumns = `id, message, severity, starts_at, ends_at, dismissible, COALESCE(created_by, 0) AS created_by, created_at, updated_at`
+
+// BannerRepository implements the banner.Repository interface using SQLX
+type BannerRepository struct {
+	db *sqlx.DB
+}
+
+// NewBannerRepository creates a new BannerRepository instance
+func NewBannerRepository(db *sqlx.DB) *BannerRepository {
+	return &BannerRepository{db: db}
+}
+
+// Create inserts a new banner
+func (r *BannerRepository) Create(ctx context.Context, b *banner.Banner) error {
+	query := `
+		INSERT INTO banners (message, severity, starts_at, ends_at, dismissible, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, b.Message, b.Severity, b.StartsAt, b.EndsAt, b.Dismissible, b.CreatedBy, b.CreatedAt, b.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create banner: %w", err)
+	}
+	b.ID = id
+	return nil
+}
+
+// GetByID retrieves a banner by its ID
+func (r *BannerRepository) GetByID(ctx context.Context, id int) (*banner.Banner, error) {
+	var b banner.Banner
+	if err := conn(ctx, r.db).GetContext(ctx, &b, r.db.Rebind(`SELECT `+bannerColumns+` FROM banners WHERE id = ?`), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, banner.ErrBannerNotFound
+		}
+		return nil, fmt.Errorf("failed to get banner: %w", err)
+	}
+	return &b, nil
+}
+
+// List retrieves every banner, newest first
+func (r *BannerRepository) List(ctx context.Context) ([]*banner.Banner, error) {
+	var banners []*banner.Banner
+	if err := conn(ctx, r.db).SelectContext(ctx, &banners, r.db.Rebind(`SELECT `+bannerColumns+` FROM banners ORDER BY created_at DESC, id DESC`)); err != nil {
+		return nil, fmt.Errorf("failed to list banners: %w", err)
+	}
+	return banners, nil
+}
+
+// Update stores the fields of a banner
+func (r *BannerRepository) Update(ctx context.Context, b *banner.Banner) error {
+	query := `
+		UPDATE banners
+		SET message = ?, severity = ?, starts_at = ?, ends_at = ?, dismissible = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), b.Message, b.Severity, b.StartsAt, b.EndsAt, b.Dismissible, b.UpdatedAt, b.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update banner: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return banner.ErrBannerNotFound
+	}
+	return nil
+}
+
+// Delete removes a banner; its dismissals go with it
+func (r *BannerRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM banners WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete banner: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return banner.ErrBannerNotFound
+	}
+	return nil
+}
+
+// ListActive retrieves the banners shown at the given time, most severe
+// first, leaving out those the user dismissed
+func (r *BannerRepository) ListActive(ctx context.Context, now time.Time, userID int) ([]*banner.Banner, error) {
+	query := `
+		SELECT ` + bannerColumns + `
+		FROM banners b
+		WHERE b.starts_at <= ? AND (b.ends_at IS NULL OR b.ends_at > ?)
+			AND NOT EXISTS (
+				SELECT 1 FROM banner_dismissals d WHERE d.banner_id = b.id AND d.user_id = ?
+			)
+		ORDER BY CASE b.severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, b.starts_at DESC, b.id DESC
+	`
+
+	var banners []*banner.Banner
+	if err := conn(ctx, r.db).SelectContext(ctx, &banners, r.db.Rebind(query), now, now, userID); err != nil {
+		return nil, fmt.Errorf("failed to list active banners: %w", err)
+	}
+	return banners, nil
+}
+
+// Dismiss records that a user closed a banner
+func (r *BannerRepository) Dismiss(ctx context.Context, bannerID, userID int) error {
+	query := `
+		INSERT INTO banner_dismissals (banner_id, user_id)
+		VALUES (?, ?)
+	` + upsert(r.db, "banner_id, user_id")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), bannerID, userID); err != nil {
+		return fmt.Errorf("failed to dismiss banner: %w", err)
+	}
+	return nil
+}
+
+// Verify that BannerRepository implements the banner.Repository interface
+var _ banner.Repository = (*BannerRepository)(nil)
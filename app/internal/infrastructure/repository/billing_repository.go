@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/billing"
+)
+
+// subscriptionColumns lists the columns selected for a subscription
+const subscriptionColumns = `user_id, plan, status, stripe_customer_id, stripe_subscription_id, current_period_end, cancel_at_period_end, last_event_at, created_at, updated_at`
+
+// BillingRepository implements the billing.Repository interface using SQLX
+type BillingRepository struct {
+	db *sqlx.DB
+}
+
+// NewBillingRepository creates a new BillingRepository instance
+func NewBillingRepository(db *sqlx.DB) *BillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// GetByUserID retrieves the subscription of a user
+func (r *BillingRepository) GetByUserID(ctx context.Context, userID int) (*billing.Subscription, error) {
+	return r.get(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions WHERE user_id = ?`, userID)
+}
+
+// GetByStripeCustomerID retrieves the subscription of a Stripe customer
+func (r *BillingRepository) GetByStripeCustomerID(ctx context.Context, customerID string) (*billing.Subscription, error) {
+	return r.get(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions WHERE stripe_customer_id = ?`, customerID)
+}
+
+// get retrieves the subscription selected by a query
+func (r *BillingRepository) get(ctx context.Context, query string, arg any) (*billing.Subscription, error) {
+	var s billing.Subscription
+	if err := conn(ctx, r.db).GetContext(ctx, &s, r.db.Rebind(query), arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, billing.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return &s, nil
+}
+
+// Save creates or replaces the subscription of a user
+func (r *BillingRepository) Save(ctx context.Context, s *billing.Subscription) error {
+	query := `
+		INSERT INTO subscriptions (user_id, plan, status, stripe_customer_id, stripe_subscription_id, current_period_end, cancel_at_period_end, last_event_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	` + upsert(r.db, "user_id", "plan", "status", "stripe_customer_id", "stripe_subscription_id",
+		"current_period_end", "cancel_at_period_end", "last_event_at", "updated_at")
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), s.UserID, s.Plan, s.Status, s.StripeCustomerID, s.StripeSubscriptionID,
+		s.CurrentPeriodEnd, s.CancelAtPeriodEnd, s.LastEventAt, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save subscription: %w", err)
+	}
+
+	return nil
+}
+
+// CountPosts counts the posts of an author, drafts and scheduled posts included
+func (r *BillingRepository) CountPosts(ctx context.Context, userID int) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(`SELECT COUNT(*) FROM posts WHERE author_id = ?`), userID); err != nil {
+		return 0, fmt.Errorf("failed to count posts: %w", err)
+	}
+	return count, nil
+}
+
+// Verify that BillingRepository implements the billing.Repository interface
+var _ billing.Repository = (*BillingRepository)(nil)
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+)
+
+// BookmarkRepository implements the post.BookmarkRepository interface
+// using SQLX
+type BookmarkRepository struct {
+	db *sqlx.DB
+}
+
+// NewBookmarkRepository creates a new BookmarkRepository instance
+func NewBookmarkRepository(db *sqlx.DB) *BookmarkRepository {
+	return &BookmarkRepository{db: db}
+}
+
+// Add bookmarks a post, keeping the original bookmark time when it exists
+func (r *BookmarkRepository) Add(ctx context.Context, userID, postID int) error {
+	query := `
+		INSERT INTO bookmarks (user_id, post_id)
+		VALUES (?, ?)
+	` + upsert(r.db, "user_id, post_id")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), userID, postID); err != nil {
+		return fmt.Errorf("failed to bookmark post: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a bookmark
+func (r *BookmarkRepository) Remove(ctx context.Context, userID, postID int) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM bookmarks WHERE user_id = ? AND post_id = ?`), userID, postID); err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return nil
+}
+
+// ListByUser retrieves the bookmarked published posts of a user, most
+// recently bookmarked first; posts made private since are left out
+func (r *BookmarkRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*post.BookmarkEntry, error) {
+	query := `
+		SELECT b.user_id, b.post_id, b.created_at, p.title, p.slug, p.author_id
+		FROM bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		WHERE b.user_id = ? AND p.status = ? AND p.visibility <> ?
+		ORDER BY b.created_at DESC, b.post_id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var entries []*post.BookmarkEntry
+	if err := conn(ctx, r.db).SelectContext(ctx, &entries, r.db.Rebind(query), userID, post.StatusPublished, post.VisibilityPrivate, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify that BookmarkRepository implements the post.BookmarkRepository interface
+var _ post.BookmarkRepository = (*BookmarkRepository)(nil)
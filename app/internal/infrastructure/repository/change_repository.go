@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/post"
+)
+
+// ChangeRepository implements the change.Repository interface using SQLX
+type ChangeRepository struct {
+	db    *sqlx.DB
+	posts *PostRepository
+}
+
+// NewChangeRepository creates a new ChangeRepository instance
+func NewChangeRepository(db *sqlx.DB) *ChangeRepository {
+	return &ChangeRepository{db: db, posts: NewPostRepository(db)}
+}
+
+// ListPosts retrieves the published public posts changed after the
+// position with their tags
+func (r *ChangeRepository) ListPosts(ctx context.Context, after change.Position, limit int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = ? AND visibility = ? AND (updated_at > ? OR (updated_at = ? AND id > ?))
+		ORDER BY updated_at ASC, id ASC
+		LIMIT ?
+	`
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic, after.At, after.At, after.ID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list changed posts: %w", err)
+	}
+	if err := r.posts.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListComments retrieves the comments changed after the position, whatever
+// their status
+func (r *ChangeRepository) ListComments(ctx context.Context, after change.Position, limit int) ([]*change.CommentChange, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at, updated_at
+		FROM comments
+		WHERE updated_at > ? OR (updated_at = ? AND id > ?)
+		ORDER BY updated_at ASC, id ASC
+		LIMIT ?
+	`
+
+	var comments []*change.CommentChange
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), after.At, after.At, after.ID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list changed comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ListTombstones retrieves the deletions after the position
+func (r *ChangeRepository) ListTombstones(ctx context.Context, after change.Position, limit int) ([]*change.Tombstone, error) {
+	query := `
+		SELECT id, kind, entity_id, deleted_at
+		FROM tombstones
+		WHERE deleted_at > ? OR (deleted_at = ? AND id > ?)
+		ORDER BY deleted_at ASC, id ASC
+		LIMIT ?
+	`
+
+	var tombstones []*change.Tombstone
+	if err := conn(ctx, r.db).SelectContext(ctx, &tombstones, r.db.Rebind(query), after.At, after.At, after.ID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	return tombstones, nil
+}
+
+// addTombstones records the deletion of the posts or comments whose IDs the
+// query selects; call it in the transaction deleting them, before they are
+// gone
+func addTombstones(ctx context.Context, tx *scopedTx, kind change.Kind, selectIDs string, args ...any) error {
+	query := `INSERT INTO tombstones (kind, entity_id) SELECT ?, id FROM (` + selectIDs + `) deleted`
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), append([]any{kind}, args...)...); err != nil {
+		return fmt.Errorf("failed to record %s tombstones: %w", kind, err)
+	}
+	return nil
+}
+
+// Verify that ChangeRepository implements the change.Repository interface
+var _ change.Repository = (*ChangeRepository)(nil)
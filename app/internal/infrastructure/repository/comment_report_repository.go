@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/comment"
+)
+
+// CommentReportRepository implements the comment.ReportRepository
+// interface using SQLX
+type CommentReportRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommentReportRepository creates a new CommentReportRepository instance
+func NewCommentReportRepository(db *sqlx.DB) *CommentReportRepository {
+	return &CommentReportRepository{db: db}
+}
+
+// Create inserts a report; the unique key on the comment and reporter
+// turns repeated reports away
+func (r *CommentReportRepository) Create(ctx context.Context, report *comment.Report) (bool, error) {
+	query := `
+		INSERT INTO comment_reports (comment_id, reporter_id, reporter_key, reason, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, report.CommentID, report.ReporterID, report.ReporterKey, report.Reason, report.Details, report.CreatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create comment report: %w", err)
+	}
+	report.ID = id
+	return true, nil
+}
+
+// CountByComment counts the reports on a comment, one per reporter
+func (r *CommentReportRepository) CountByComment(ctx context.Context, commentID int) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(`SELECT COUNT(*) FROM comment_reports WHERE comment_id = ?`), commentID); err != nil {
+		return 0, fmt.Errorf("failed to count comment reports: %w", err)
+	}
+	return count, nil
+}
+
+// ListByComment retrieves the reports on a comment, oldest first
+func (r *CommentReportRepository) ListByComment(ctx context.Context, commentID int) ([]*comment.Report, error) {
+	query := `
+		SELECT id, comment_id, reporter_id, reporter_key, reason, details, created_at
+		FROM comment_reports
+		WHERE comment_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	var reports []*comment.Report
+	if err := conn(ctx, r.db).SelectContext(ctx, &reports, r.db.Rebind(query), commentID); err != nil {
+		return nil, fmt.Errorf("failed to list comment reports: %w", err)
+	}
+	return reports, nil
+}
+
+// Verify that CommentReportRepository implements the comment.ReportRepository interface
+var _ comment.ReportRepository = (*CommentReportRepository)(nil)
@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	"blog-platform/internal/domain/change"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/post"
 )
 
 // CommentRepository implements the comment.Repository interface using SQLX
@@ -24,34 +28,32 @@ func NewCommentRepository(db *sqlx.DB) *CommentRepository {
 // Create inserts a new comment into the database
 func (r *CommentRepository) Create(ctx context.Context, c *comment.Comment) error {
 	query := `
-		INSERT INTO comments (post_id, author_name, content, created_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO comments (client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	
-	result, err := r.db.ExecContext(ctx, query, c.PostID, c.AuthorName, c.Content, c.CreatedAt)
-	if err != nil {
-		return err
-	}
-	
-	id, err := result.LastInsertId()
+	id, err := insertID(ctx, conn(ctx, r.db), query, c.ClientID, c.PostID, c.ParentID, c.RootID, c.Depth, c.UserID, c.AuthorName, c.Content, c.Status, c.CreatedAt)
 	if err != nil {
+		if isDuplicateKeyOn(err, "client_id") {
+			return comment.ErrClientIDTaken
+		}
 		return err
 	}
 	
-	c.ID = int(id)
+	c.ID = id
 	return nil
 }
 
 // GetByID retrieves a comment by its ID
 func (r *CommentRepository) GetByID(ctx context.Context, id int) (*comment.Comment, error) {
 	query := `
-		SELECT id, post_id, author_name, content, created_at
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
 		FROM comments
 		WHERE id = ?
 	`
 	
 	var c comment.Comment
-	err := r.db.GetContext(ctx, &c, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &c, r.db.Rebind(query), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, comment.ErrCommentNotFound
@@ -62,18 +64,38 @@ func (r *CommentRepository) GetByID(ctx context.Context, id int) (*comment.Comme
 	return &c, nil
 }
 
+// GetByClientID retrieves a comment by the client ID it was created with
+func (r *CommentRepository) GetByClientID(ctx context.Context, clientID string) (*comment.Comment, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE client_id = ?
+	`
+
+	var c comment.Comment
+	err := conn(ctx, r.db).GetContext(ctx, &c, r.db.Rebind(query), clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, comment.ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
 // GetByPostID retrieves comments for a specific post with pagination
 func (r *CommentRepository) GetByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
 	query := `
-		SELECT id, post_id, author_name, content, created_at
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
 		FROM comments
-		WHERE post_id = ?
+		WHERE post_id = ? AND status = 'approved'
 		ORDER BY created_at ASC
 		LIMIT ? OFFSET ?
 	`
 	
 	var comments []*comment.Comment
-	err := r.db.SelectContext(ctx, &comments, query, postID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), postID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -81,15 +103,131 @@ func (r *CommentRepository) GetByPostID(ctx context.Context, postID int, limit,
 	return comments, nil
 }
 
+// GetByPostIDAfter retrieves the comments of a post after a cursor, oldest
+// first, breaking ties on created_at by ID
+func (r *CommentRepository) GetByPostIDAfter(ctx context.Context, postID int, after keyset.Cursor, limit int) ([]*comment.Comment, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE post_id = ? AND status = 'approved'
+	`
+	args := []any{postID}
+	if !after.IsZero() {
+		query += " AND (created_at > ? OR (created_at = ? AND id > ?))"
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+	query += " ORDER BY created_at ASC, id ASC LIMIT ?"
+	args = append(args, limit)
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetRootsByPostID retrieves the top-level comments of a post with
+// pagination, oldest first
+func (r *CommentRepository) GetRootsByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE post_id = ? AND parent_id IS NULL AND status = 'approved'
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), postID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetByRootIDs retrieves the replies in the threads of top-level comments,
+// oldest first
+func (r *CommentRepository) GetByRootIDs(ctx context.Context, rootIDs []int) ([]*comment.Comment, error) {
+	if len(rootIDs) == 0 {
+		return []*comment.Comment{}, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE root_id IN (?) AND status = 'approved'
+		ORDER BY created_at ASC, id ASC
+	`, rootIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// ListRecent retrieves the newest comments, optionally of a post or of the
+// public posts of an author
+func (r *CommentRepository) ListRecent(ctx context.Context, filter comment.RecentFilter, limit int) ([]*comment.Comment, error) {
+	query := `
+		SELECT c.id, c.client_id, c.post_id, c.parent_id, c.root_id, c.depth, c.user_id, c.author_name, c.content, c.status, c.created_at
+		FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		WHERE c.status = 'approved'
+	`
+	var args []any
+	if filter.PostID > 0 {
+		query += " AND c.post_id = ?"
+		args = append(args, filter.PostID)
+	}
+	if filter.PostAuthorID > 0 {
+		query += " AND p.author_id = ? AND p.status = ? AND p.visibility = ?"
+		args = append(args, filter.PostAuthorID, post.StatusPublished, post.VisibilityPublic)
+	}
+	query += " ORDER BY c.created_at DESC, c.id DESC LIMIT ?"
+	args = append(args, limit)
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// ListByStatus retrieves the comments with a moderation status with
+// pagination, oldest first
+func (r *CommentRepository) ListByStatus(ctx context.Context, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE status = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), status, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
 // Update modifies an existing comment in the database
 func (r *CommentRepository) Update(ctx context.Context, c *comment.Comment) error {
 	query := `
 		UPDATE comments
-		SET content = ?
+		SET content = ?, status = ?
 		WHERE id = ?
 	`
 	
-	result, err := r.db.ExecContext(ctx, query, c.Content, c.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), c.Content, c.Status, c.ID)
 	if err != nil {
 		return err
 	}
@@ -106,14 +244,33 @@ func (r *CommentRepository) Update(ctx context.Context, c *comment.Comment) erro
 	return nil
 }
 
-// Delete removes a comment from the database
+// Delete removes a comment and the replies to it from the database,
+// leaving tombstones for them
 func (r *CommentRepository) Delete(ctx context.Context, id int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	thread := `
+		WITH RECURSIVE thread (id) AS (
+			SELECT id FROM comments WHERE id = ?
+			UNION ALL
+			SELECT c.id FROM comments c JOIN thread t ON c.parent_id = t.id
+		)
+		SELECT id FROM thread
+	`
+	if err := addTombstones(ctx, tx, change.KindComment, thread, id); err != nil {
+		return err
+	}
+
 	query := `
 		DELETE FROM comments
 		WHERE id = ?
 	`
 	
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), id)
 	if err != nil {
 		return err
 	}
@@ -127,5 +284,100 @@ func (r *CommentRepository) Delete(ctx context.Context, id int) error {
 		return comment.ErrCommentNotFound
 	}
 	
-	return nil
+	return tx.Commit()
+}
+
+// DeleteByPostID removes the comments of a post in a single statement,
+// leaving tombstones for them
+func (r *CommentRepository) DeleteByPostID(ctx context.Context, postID int) (int, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := addTombstones(ctx, tx, change.KindComment, `SELECT id FROM comments WHERE post_id = ?`, postID); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM comments WHERE post_id = ?`), postID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), tx.Commit()
+}
+
+// LastCommentAt returns when a user last commented, whatever the status of
+// the comment; zero when the user never did
+func (r *CommentRepository) LastCommentAt(ctx context.Context, userID int) (time.Time, error) {
+	query := `
+		SELECT MAX(created_at)
+		FROM comments
+		WHERE user_id = ?
+	`
+
+	var last sql.NullTime
+	if err := conn(ctx, r.db).GetContext(ctx, &last, r.db.Rebind(query), userID); err != nil {
+		return time.Time{}, err
+	}
+	return last.Time, nil
+}
+
+// GetTrustLevel retrieves the trust level of a commenter; commenters not
+// evaluated yet are new
+func (r *CommentRepository) GetTrustLevel(ctx context.Context, userID int) (comment.TrustLevel, error) {
+	query := `
+		SELECT level
+		FROM commenter_trust
+		WHERE user_id = ?
+	`
+
+	var level comment.TrustLevel
+	err := conn(ctx, r.db).GetContext(ctx, &level, r.db.Rebind(query), userID)
+	if err == sql.ErrNoRows {
+		return comment.TrustNew, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return level, nil
+}
+
+// SetTrustLevel stores the trust level of a commenter
+func (r *CommentRepository) SetTrustLevel(ctx context.Context, userID int, level comment.TrustLevel) error {
+	query := `
+		INSERT INTO commenter_trust (user_id, level)
+		VALUES (?, ?)
+	` + upsert(r.db, "user_id", "level")
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), userID, level)
+	return err
+}
+
+// ListCommenterHistories aggregates the comments of every registered user
+// who commented, with the trust level they have now
+func (r *CommentRepository) ListCommenterHistories(ctx context.Context, flaggedSince time.Time) ([]*comment.CommenterHistory, error) {
+	query := `
+		SELECT u.id AS user_id, u.role, COALESCE(t.level, 'new') AS level, u.created_at AS account_created_at,
+			COUNT(CASE WHEN c.status = 'approved' THEN 1 END) AS approved,
+			COUNT(CASE WHEN c.status IN ('rejected', 'spam') AND c.created_at >= ? THEN 1 END) AS flagged
+		FROM users u
+		JOIN comments c ON c.user_id = u.id
+		LEFT JOIN commenter_trust t ON t.user_id = u.id
+		WHERE u.deleted_at IS NULL
+		GROUP BY u.id, u.role, t.level, u.created_at
+		ORDER BY u.id
+	`
+
+	var histories []*comment.CommenterHistory
+	if err := conn(ctx, r.db).SelectContext(ctx, &histories, r.db.Rebind(query), flaggedSince); err != nil {
+		return nil, err
+	}
+	return histories, nil
 }
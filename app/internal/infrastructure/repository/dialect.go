@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Queries are written for MySQL with ? placeholders and rebound for the
+// driver in use. The helpers below cover the statements MySQL, PostgreSQL
+// and SQLite spell differently; PostgreSQL and SQLite mostly agree.
+
+// isMySQL reports whether db talks to MySQL
+func isMySQL(db sqlx.ExtContext) bool {
+	return db.DriverName() == "mysql"
+}
+
+// isPostgres reports whether db talks to PostgreSQL
+func isPostgres(db sqlx.ExtContext) bool {
+	return sqlx.BindType(db.DriverName()) == sqlx.DOLLAR
+}
+
+// isSQLite reports whether db talks to SQLite
+func isSQLite(db sqlx.ExtContext) bool {
+	return db.DriverName() == "sqlite"
+}
+
+// insertID runs an INSERT and returns the ID of the row it created. The
+// PostgreSQL driver does not report insert IDs, so there the statement
+// returns the id column instead.
+func insertID(ctx context.Context, db sqlx.ExtContext, query string, args ...any) (int, error) {
+	if isPostgres(db) {
+		var id int
+		err := db.QueryRowxContext(ctx, db.Rebind(query+" RETURNING id"), args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := db.ExecContext(ctx, db.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get insert ID: %w", err)
+	}
+	return int(id), nil
+}
+
+// namedInsertID is insertID for queries with named parameters bound from arg
+func namedInsertID(ctx context.Context, db sqlx.ExtContext, query string, arg any) (int, error) {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return 0, err
+	}
+	return insertID(ctx, db, query, args...)
+}
+
+// onConflict starts the clause making an INSERT update the row it
+// conflicts with on the key columns, to be followed by the assignments
+func onConflict(db sqlx.ExtContext, key string) string {
+	if !isMySQL(db) {
+		return "ON CONFLICT (" + key + ") DO UPDATE SET"
+	}
+	return "ON DUPLICATE KEY UPDATE"
+}
+
+// inserted refers to the value an INSERT proposed for a column, in the
+// assignments of its onConflict clause
+func inserted(db sqlx.ExtContext, column string) string {
+	if !isMySQL(db) {
+		return "EXCLUDED." + column
+	}
+	return "VALUES(" + column + ")"
+}
+
+// upsert returns the clause making an INSERT replace the given columns of
+// the row it conflicts with on the key columns by the inserted values.
+// Without columns the conflicting row is kept as is.
+func upsert(db sqlx.ExtContext, key string, columns ...string) string {
+	if len(columns) == 0 {
+		if !isMySQL(db) {
+			return "ON CONFLICT (" + key + ") DO NOTHING"
+		}
+		first, _, _ := strings.Cut(key, ",")
+		return "ON DUPLICATE KEY UPDATE " + first + " = " + first
+	}
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = " + inserted(db, column)
+	}
+	return onConflict(db, key) + " " + strings.Join(assignments, ", ")
+}
+
+// insertIgnore makes an INSERT INTO query skip the rows that conflict with
+// a unique key instead of failing
+func insertIgnore(db sqlx.ExtContext, query string) string {
+	if !isMySQL(db) {
+		return query + " ON CONFLICT DO NOTHING"
+	}
+	return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+}
+
+// greatest names the function returning the largest of its arguments;
+// SQLite's MAX does so when given more than one
+func greatest(db sqlx.ExtContext) string {
+	if isSQLite(db) {
+		return "MAX"
+	}
+	return "GREATEST"
+}
+
+// forUpdate returns the clause locking the rows a SELECT in a transaction
+// reads. SQLite has none, as it runs one write transaction at a time.
+func forUpdate(db sqlx.ExtContext) string {
+	if isSQLite(db) {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// jsonArg passes a JSON document to a query. MySQL and PostgreSQL take it
+// as text; SQLite stores bytes as they are, which read back into
+// json.RawMessage where its text does not.
+func jsonArg(db sqlx.ExtContext, document []byte) any {
+	if isSQLite(db) {
+		return document
+	}
+	return string(document)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/user"
+)
+
+// EmailChangeRepository implements the user.EmailChangeRepository interface using SQLX
+type EmailChangeRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailChangeRepository creates a new EmailChangeRepository instance
+func NewEmailChangeRepository(db *sqlx.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// Create stores a pending email change, replacing any earlier request for the same user
+func (r *EmailChangeRepository) Create(ctx context.Context, c *user.EmailChange) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM email_change_requests WHERE user_id = ?`), c.UserID); err != nil {
+		return fmt.Errorf("failed to clear previous email change: %w", err)
+	}
+
+	query := `
+		INSERT INTO email_change_requests (user_id, new_email, new_email_normalized, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, tx, query, c.UserID, c.NewEmail, c.NewEmailNormalized, c.TokenHash, c.ExpiresAt, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create email change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit email change: %w", err)
+	}
+
+	c.ID = id
+	return nil
+}
+
+// GetByTokenHash retrieves a pending email change by its token hash
+func (r *EmailChangeRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.EmailChange, error) {
+	query := `
+		SELECT id, user_id, new_email, new_email_normalized, token_hash, expires_at, created_at
+		FROM email_change_requests
+		WHERE token_hash = ?
+	`
+
+	var c user.EmailChange
+	err := conn(ctx, r.db).GetContext(ctx, &c, r.db.Rebind(query), tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrEmailChangeNotFound
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Complete swaps the user's email to the confirmed address and removes the
+// request in a single transaction. The unique index on email_normalized
+// guarantees the new address is still free at commit time.
+func (r *EmailChangeRepository) Complete(ctx context.Context, c *user.EmailChange) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET email = ?, email_normalized = ?, email_conflict = FALSE, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), c.NewEmail, c.NewEmailNormalized, time.Now(), c.UserID)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return user.ErrUserExists
+		}
+		return fmt.Errorf("failed to update user email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM email_change_requests WHERE id = ?`), c.ID); err != nil {
+		return fmt.Errorf("failed to delete email change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit email change: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a pending email change
+func (r *EmailChangeRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM email_change_requests WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete email change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrEmailChangeNotFound
+	}
+
+	return nil
+}
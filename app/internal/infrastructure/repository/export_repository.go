@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/post"
+)
+
+// ExportRepository implements the export.Repository interface using SQLX
+type ExportRepository struct {
+	db    *sqlx.DB
+	posts *PostRepository
+}
+
+// NewExportRepository creates a new ExportRepository instance
+func NewExportRepository(db *sqlx.DB) *ExportRepository {
+	return &ExportRepository{db: db, posts: NewPostRepository(db)}
+}
+
+// ListPosts retrieves every post of an author with their tags
+func (r *ExportRepository) ListPosts(ctx context.Context, authorID int) ([]*post.Post, error) {
+	query := `
+		SELECT id, client_id, title, slug, content, author_id, noindex, access, status, published_at, timezone, view_count, tip_count, word_count, reading_time_minutes, excerpt, created_at, updated_at
+		FROM posts
+		WHERE author_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), authorID); err != nil {
+		return nil, fmt.Errorf("failed to list posts for export: %w", err)
+	}
+	if err := r.posts.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListComments retrieves every comment of a user, whatever their status
+func (r *ExportRepository) ListComments(ctx context.Context, userID int) ([]*comment.Comment, error) {
+	query := `
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE user_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), userID); err != nil {
+		return nil, fmt.Errorf("failed to list comments for export: %w", err)
+	}
+	return comments, nil
+}
+
+// ListSitePosts retrieves a page of the posts matching a site export
+// filter, drafts included, with their tags
+func (r *ExportRepository) ListSitePosts(ctx context.Context, filter export.SiteFilter, afterID, limit int) ([]*post.Post, error) {
+	conditions := []string{"id > ?"}
+	args := []any{afterID}
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.To)
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT id, client_id, title, slug, content, author_id, noindex, access, status, published_at, timezone, view_count, tip_count, word_count, reading_time_minutes, excerpt, created_at, updated_at
+		FROM posts
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list posts for site export: %w", err)
+	}
+	if err := r.posts.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPostComments retrieves every comment on the given posts, whatever
+// their status
+func (r *ExportRepository) ListPostComments(ctx context.Context, postIDs []int) ([]*comment.Comment, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, client_id, post_id, parent_id, root_id, depth, user_id, author_name, content, status, created_at
+		FROM comments
+		WHERE post_id IN (?)
+		ORDER BY created_at ASC, id ASC
+	`, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build export comments query: %w", err)
+	}
+
+	var comments []*comment.Comment
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list comments for site export: %w", err)
+	}
+	return comments, nil
+}
+
+// Verify that ExportRepository implements the export.Repository interface
+var _ export.Repository = (*ExportRepository)(nil)
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/user"
+)
+
+// FollowRepository implements the user.FollowRepository interface using SQLX
+type FollowRepository struct {
+	db *sqlx.DB
+}
+
+// NewFollowRepository creates a new FollowRepository instance
+func NewFollowRepository(db *sqlx.DB) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+// Follow records a follow, keeping the original follow time when it exists
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID int) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id)
+		VALUES (?, ?)
+	` + upsert(r.db, "follower_id, followee_id")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+	return nil
+}
+
+// Unfollow removes a follow
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM follows WHERE follower_id = ? AND followee_id = ?`), followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return nil
+}
+
+// ListFollowers retrieves the active users following a user, most recent
+// first
+func (r *FollowRepository) ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, error) {
+	query := `
+		SELECT u.id AS user_id, u.name, f.created_at AS followed_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = ? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var follows []*user.Follow
+	if err := conn(ctx, r.db).SelectContext(ctx, &follows, r.db.Rebind(query), userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list followers: %w", err)
+	}
+	return follows, nil
+}
+
+// ListFollowing retrieves the active users a user follows, most recent
+// first
+func (r *FollowRepository) ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, error) {
+	query := `
+		SELECT u.id AS user_id, u.name, f.created_at AS followed_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = ? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var follows []*user.Follow
+	if err := conn(ctx, r.db).SelectContext(ctx, &follows, r.db.Rebind(query), userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list followed users: %w", err)
+	}
+	return follows, nil
+}
+
+// Counts counts the active followers of a user and the active users they
+// follow
+func (r *FollowRepository) Counts(ctx context.Context, userID int) (*user.FollowCounts, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM follows f JOIN users u ON u.id = f.follower_id
+				WHERE f.followee_id = ? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL) AS followers,
+			(SELECT COUNT(*) FROM follows f JOIN users u ON u.id = f.followee_id
+				WHERE f.follower_id = ? AND u.deleted_at IS NULL AND u.deactivated_at IS NULL) AS following
+	`
+
+	var counts user.FollowCounts
+	if err := conn(ctx, r.db).GetContext(ctx, &counts, r.db.Rebind(query), userID, userID); err != nil {
+		return nil, fmt.Errorf("failed to count follows: %w", err)
+	}
+	return &counts, nil
+}
+
+// Verify that FollowRepository implements the user.FollowRepository interface
+var _ user.FollowRepository = (*FollowRepository)(nil)
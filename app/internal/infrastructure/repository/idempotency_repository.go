@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/idempotency"
+)
+
+// IdempotencyRepository implements the idempotency.Repository interface using SQLX
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyRepository creates a new IdempotencyRepository instance
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Create inserts a new record; the unique key on scope and key makes the
+// insert the claim of the key
+func (r *IdempotencyRepository) Create(ctx context.Context, record *idempotency.Record) error {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, status_code, content_type, response_body, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, record.Scope, record.Key, record.RequestHash, record.StatusCode, record.ContentType, record.Body, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return idempotency.ErrDuplicateKey
+		}
+		return fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+	record.ID = id
+	return nil
+}
+
+// Get retrieves the record of a key in a scope
+func (r *IdempotencyRepository) Get(ctx context.Context, scope, key string) (*idempotency.Record, error) {
+	query := `
+		SELECT id, scope, idempotency_key, request_hash, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = ? AND idempotency_key = ?
+	`
+
+	var record idempotency.Record
+	if err := conn(ctx, r.db).GetContext(ctx, &record, r.db.Rebind(query), scope, key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, idempotency.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &record, nil
+}
+
+// Complete stores the response of a record
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = ?, content_type = ?, response_body = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), record.StatusCode, record.ContentType, record.Body, record.ID)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return idempotency.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a record
+func (r *IdempotencyRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM idempotency_keys WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return idempotency.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the records that expired before the given time
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM idempotency_keys WHERE expires_at < ?`), before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// Verify that IdempotencyRepository implements the idempotency.Repository interface
+var _ idempotency.Repository = (*IdempotencyRepository)(nil)
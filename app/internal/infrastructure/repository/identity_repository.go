@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/user"
+)
+
+// IdentityRepository implements the user.IdentityRepository interface using SQLX
+type IdentityRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdentityRepository creates a new IdentityRepository instance
+func NewIdentityRepository(db *sqlx.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// Create links an external account to a user
+func (r *IdentityRepository) Create(ctx context.Context, i *user.Identity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, i.UserID, i.Provider, i.Subject, i.Email, i.CreatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return user.ErrIdentityExists
+		}
+		return fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	i.ID = id
+	return nil
+}
+
+// GetByProviderSubject retrieves the link for an external account
+func (r *IdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*user.Identity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE provider = ? AND subject = ?
+	`
+
+	var i user.Identity
+	err := conn(ctx, r.db).GetContext(ctx, &i, r.db.Rebind(query), provider, subject)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrIdentityNotFound
+		}
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	return &i, nil
+}
+
+// ListByUser retrieves all external accounts linked to a user
+func (r *IdentityRepository) ListByUser(ctx context.Context, userID int) ([]*user.Identity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = ?
+		ORDER BY created_at
+	`
+
+	var identities []*user.Identity
+	if err := conn(ctx, r.db).SelectContext(ctx, &identities, r.db.Rebind(query), userID); err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	return identities, nil
+}
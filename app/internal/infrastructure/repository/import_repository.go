@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/imports"
+)
+
+// ImportRepository implements the imports.Repository interface using SQLX
+type ImportRepository struct {
+	db *sqlx.DB
+}
+
+// NewImportRepository creates a new ImportRepository instance
+func NewImportRepository(db *sqlx.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// importRow is the stored form of an import
+type importRow struct {
+	imports.Import
+	DocumentJSON string `db:"document"`
+	ErrorsJSON   []byte `db:"errors"`
+}
+
+// Create inserts a new import with its document
+func (r *ImportRepository) Create(ctx context.Context, i *imports.Import) error {
+	document, err := json.Marshal(i.Document)
+	if err != nil {
+		return fmt.Errorf("failed to encode import document: %w", err)
+	}
+	errs, err := json.Marshal(i.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode import errors: %w", err)
+	}
+
+	query := `
+		INSERT INTO imports (source, dry_run, status, created_by, document, total, errors, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, i.Source, i.DryRun, i.Status, i.CreatedBy, string(document), i.Total, errs, i.CreatedAt, i.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create import: %w", err)
+	}
+
+	i.ID = id
+	return nil
+}
+
+// GetByID retrieves an import with its document by ID
+func (r *ImportRepository) GetByID(ctx context.Context, id int) (*imports.Import, error) {
+	query := `
+		SELECT id, source, dry_run, status, created_by, document, total, processed, users_created,
+			posts_created, comments_created, skipped, failed, errors, created_at, updated_at, completed_at
+		FROM imports
+		WHERE id = ?
+	`
+
+	var row importRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, imports.ErrImportNotFound
+		}
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	i := row.Import
+	if err := json.Unmarshal([]byte(row.DocumentJSON), &i.Document); err != nil {
+		return nil, fmt.Errorf("failed to decode import document: %w", err)
+	}
+	if err := json.Unmarshal(row.ErrorsJSON, &i.Errors); err != nil {
+		return nil, fmt.Errorf("failed to decode import errors: %w", err)
+	}
+	return &i, nil
+}
+
+// Update stores the status, progress counters and errors of an import
+func (r *ImportRepository) Update(ctx context.Context, i *imports.Import) error {
+	errs, err := json.Marshal(i.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode import errors: %w", err)
+	}
+
+	query := `
+		UPDATE imports
+		SET status = ?, processed = ?, users_created = ?, posts_created = ?, comments_created = ?,
+			skipped = ?, failed = ?, errors = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), i.Status, i.Processed, i.UsersCreated, i.PostsCreated,
+		i.CommentsCreated, i.Skipped, i.Failed, errs, i.CompletedAt, time.Now(), i.ID); err != nil {
+		return fmt.Errorf("failed to update import: %w", err)
+	}
+
+	return nil
+}
+
+var _ imports.Repository = (*ImportRepository)(nil)
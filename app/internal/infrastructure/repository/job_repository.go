@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/infrastructure/queue"
+)
+
+// maxJobErrorLength bounds the stored error of a failed attempt
+const maxJobErrorLength = 1000
+
+// JobRepository implements the queue.Store interface using SQLX
+type JobRepository struct {
+	db *sqlx.DB
+}
+
+// NewJobRepository creates a new JobRepository instance
+func NewJobRepository(db *sqlx.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Create inserts a new job
+func (r *JobRepository) Create(ctx context.Context, job *queue.Job) error {
+	query := `
+		INSERT INTO jobs (kind, payload, status, attempts, last_error, run_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, job.Kind, jsonArg(r.db, job.Payload), job.Status, job.Attempts, job.LastError, job.RunAt, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	job.ID = id
+	return nil
+}
+
+// ClaimDue reserves due jobs with a claim token in a single statement, so
+// concurrent workers never claim the same job, then loads the claimed jobs
+func (r *JobRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*queue.Job, error) {
+	token, err := claimToken()
+	if err != nil {
+		return nil, err
+	}
+
+	claim := `
+		UPDATE jobs
+		SET status = ?, claim_token = ?, locked_until = ?, attempts = attempts + 1, updated_at = ?
+		WHERE (status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)
+		ORDER BY run_at
+		LIMIT ?
+	`
+	if !isMySQL(r.db) {
+		// PostgreSQL and SQLite update no limited number of rows, so the
+		// jobs are picked in a subquery. On PostgreSQL it skips those other
+		// workers are claiming; SQLite runs one write at a time anyway.
+		skipLocked := "FOR UPDATE SKIP LOCKED"
+		if isSQLite(r.db) {
+			skipLocked = ""
+		}
+		claim = `
+			UPDATE jobs
+			SET status = ?, claim_token = ?, locked_until = ?, attempts = attempts + 1, updated_at = ?
+			WHERE id IN (
+				SELECT id FROM jobs
+				WHERE (status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)
+				ORDER BY run_at
+				LIMIT ?
+				` + skipLocked + `
+			)
+		`
+	}
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(claim),
+		queue.StatusRunning, token, now.Add(lease), now,
+		queue.StatusPending, now, queue.StatusRunning, now,
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	if claimed, err := result.RowsAffected(); err != nil || claimed == 0 {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, kind, payload, status, attempts, last_error, run_at, created_at, updated_at
+		FROM jobs
+		WHERE claim_token = ? AND status = ?
+		ORDER BY run_at
+	`
+
+	var jobs []*queue.Job
+	if err := conn(ctx, r.db).SelectContext(ctx, &jobs, r.db.Rebind(query), token, queue.StatusRunning); err != nil {
+		return nil, fmt.Errorf("failed to load claimed jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Complete marks a job as done
+func (r *JobRepository) Complete(ctx context.Context, id int) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, claim_token = NULL, locked_until = NULL, updated_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), queue.StatusDone, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+// Retry returns a job to the queue to run again at runAt
+func (r *JobRepository) Retry(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, run_at = ?, last_error = ?, claim_token = NULL, locked_until = NULL, updated_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), queue.StatusPending, runAt, truncateJobError(lastError), time.Now(), id); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	return nil
+}
+
+// Fail marks a job as permanently failed
+func (r *JobRepository) Fail(ctx context.Context, id int, lastError string) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, last_error = ?, claim_token = NULL, locked_until = NULL, updated_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), queue.StatusFailed, truncateJobError(lastError), time.Now(), id); err != nil {
+		return fmt.Errorf("failed to mark job failed: %w", err)
+	}
+	return nil
+}
+
+// Depths counts the unfinished jobs by status
+func (r *JobRepository) Depths(ctx context.Context, now time.Time) (queue.Depths, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(status = ?), 0) AS pending,
+			COALESCE(SUM(status = ? AND run_at <= ?), 0) AS due,
+			COALESCE(SUM(status = ?), 0) AS running,
+			COALESCE(SUM(status = ?), 0) AS failed
+		FROM jobs
+		WHERE status <> ?
+	`
+
+	var depths queue.Depths
+	err := conn(ctx, r.db).GetContext(ctx, &depths, r.db.Rebind(query),
+		queue.StatusPending, queue.StatusPending, now,
+		queue.StatusRunning, queue.StatusFailed, queue.StatusDone)
+	if err != nil {
+		return queue.Depths{}, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return depths, nil
+}
+
+// claimToken generates a random token identifying one claim
+func claimToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate claim token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// truncateJobError keeps stored errors within the column size
+func truncateJobError(msg string) string {
+	if len(msg) > maxJobErrorLength {
+		return msg[:maxJobErrorLength]
+	}
+	return msg
+}
+
+// Verify that JobRepository implements the queue.Store interface
+var _ queue.Store = (*JobRepository)(nil)
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/user"
+)
+
+// MagicLinkRepository implements the user.MagicLinkRepository interface using SQLX
+type MagicLinkRepository struct {
+	db *sqlx.DB
+}
+
+// NewMagicLinkRepository creates a new MagicLinkRepository instance
+func NewMagicLinkRepository(db *sqlx.DB) *MagicLinkRepository {
+	return &MagicLinkRepository{db: db}
+}
+
+// Create stores a magic link, removing the unused links of the same user
+func (r *MagicLinkRepository) Create(ctx context.Context, link *user.MagicLink) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM magic_links WHERE user_id = ? AND used_at IS NULL`), link.UserID); err != nil {
+		return fmt.Errorf("failed to clear previous magic links: %w", err)
+	}
+
+	query := `
+		INSERT INTO magic_links (user_id, token_hash, request_ip, request_user_agent, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, tx, query, link.UserID, link.TokenHash, link.RequestIP, link.RequestUserAgent, link.ExpiresAt, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit magic link: %w", err)
+	}
+
+	link.ID = id
+	return nil
+}
+
+// Consume marks an unused, unexpired magic link as used. The conditional
+// update lets only one of several concurrent requests use the link.
+func (r *MagicLinkRepository) Consume(ctx context.Context, tokenHash string, device user.Device) (*user.MagicLink, error) {
+	now := time.Now()
+	query := `
+		UPDATE magic_links
+		SET used_at = ?, used_ip = ?, used_user_agent = ?
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), now, device.IP, device.UserAgent, tokenHash, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, user.ErrInvalidMagicLink
+	}
+
+	var link user.MagicLink
+	query = `
+		SELECT id, user_id, token_hash, request_ip, request_user_agent, used_ip, used_user_agent, expires_at, created_at, used_at
+		FROM magic_links
+		WHERE token_hash = ?
+	`
+	if err := conn(ctx, r.db).GetContext(ctx, &link, r.db.Rebind(query), tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrInvalidMagicLink
+		}
+		return nil, fmt.Errorf("failed to get magic link: %w", err)
+	}
+
+	return &link, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/media"
+)
+
+// MediaRepository implements the media.Repository interface using SQLX
+type MediaRepository struct {
+	db *sqlx.DB
+}
+
+// NewMediaRepository creates a new MediaRepository instance
+func NewMediaRepository(db *sqlx.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// Create saves a new media record
+func (r *MediaRepository) Create(ctx context.Context, m *media.Media) error {
+	query := `
+		INSERT INTO media (user_id, storage_key, content_type, size, width, height, created_at)
+		VALUES (:user_id, :storage_key, :content_type, :size, :width, :height, :created_at)
+	`
+
+	id, err := namedInsertID(ctx, conn(ctx, r.db), query, m)
+	if err != nil {
+		return fmt.Errorf("failed to create media: %w", err)
+	}
+	m.ID = id
+	return nil
+}
+
+// UsageByUser returns the bytes taken by the media of a user
+func (r *MediaRepository) UsageByUser(ctx context.Context, userID int) (int64, error) {
+	var used int64
+	err := conn(ctx, r.db).GetContext(ctx, &used, r.db.Rebind(`SELECT COALESCE(SUM(size), 0) FROM media WHERE user_id = ?`), userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get media usage: %w", err)
+	}
+	return used, nil
+}
+
+// ListOrphans returns media created before the given time whose key no
+// post or post revision content contains. Keys hold no LIKE wildcards.
+func (r *MediaRepository) ListOrphans(ctx context.Context, before time.Time, limit int) ([]*media.Media, error) {
+	query := `
+		SELECT m.id, m.user_id, m.storage_key, m.content_type, m.size, m.width, m.height, m.created_at
+		FROM media m
+		WHERE m.created_at < ?
+			AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.content LIKE CONCAT('%', m.storage_key, '%'))
+			AND NOT EXISTS (SELECT 1 FROM post_revisions pr WHERE pr.content LIKE CONCAT('%', m.storage_key, '%'))
+		ORDER BY m.created_at ASC, m.id ASC
+		LIMIT ?
+	`
+
+	var orphans []*media.Media
+	if err := conn(ctx, r.db).SelectContext(ctx, &orphans, r.db.Rebind(query), before, limit); err != nil {
+		return nil, fmt.Errorf("failed to list orphaned media: %w", err)
+	}
+	return orphans, nil
+}
+
+// Delete removes a media record
+func (r *MediaRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM media WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return media.ErrMediaNotFound
+	}
+	return nil
+}
+
+// Verify that MediaRepository implements the media.Repository interface
+var _ media.Repository = (*MediaRepository)(nil)
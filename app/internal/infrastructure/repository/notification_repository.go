@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/notification"
+)
+
+// NotificationRepository implements the notification.Repository interface
+// using SQLX
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository instance
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create inserts a new notification
+func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, kind, post_id, comment_id, actor_name, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, n.UserID, n.Kind, n.PostID, n.CommentID, n.ActorName, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	n.ID = id
+	return nil
+}
+
+// ListByUser retrieves the notifications of a user, newest first
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*notification.Notification, error) {
+	query := `
+		SELECT id, user_id, kind, post_id, comment_id, actor_name, read_at, created_at
+		FROM notifications
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var notifications []*notification.Notification
+	if err := conn(ctx, r.db).SelectContext(ctx, &notifications, r.db.Rebind(query), userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// CountUnread counts the notifications of a user not marked read
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(`SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL`), userID); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification of the user read, keeping the first read
+// time
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID int, readAt time.Time) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`UPDATE notifications SET read_at = ? WHERE id = ? AND user_id = ? AND read_at IS NULL`), readAt, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	// Nothing changed: either it was read already or it is not the user's
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(`SELECT COUNT(*) FROM notifications WHERE id = ? AND user_id = ?`), id, userID); err != nil {
+		return fmt.Errorf("failed to get notification: %w", err)
+	}
+	if count == 0 {
+		return notification.ErrNotificationNotFound
+	}
+	return nil
+}
+
+// Verify that NotificationRepository implements the notification.Repository
+// interface
+var _ notification.Repository = (*NotificationRepository)(nil)
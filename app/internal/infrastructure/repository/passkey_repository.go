@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/auth"
+)
+
+// passkeyColumns lists the columns selected for a passkey
+const passkeyColumns = `id, user_id, credential_id, user_handle, public_key, sign_count, name, created_at, last_used_at`
+
+// PasskeyRepository implements the auth.PasskeyRepository interface using SQLX
+type PasskeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasskeyRepository creates a new PasskeyRepository instance
+func NewPasskeyRepository(db *sqlx.DB) *PasskeyRepository {
+	return &PasskeyRepository{db: db}
+}
+
+// Create stores a passkey
+func (r *PasskeyRepository) Create(ctx context.Context, p *auth.Passkey) error {
+	query := `
+		INSERT INTO passkeys (user_id, credential_id, user_handle, public_key, sign_count, name, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, p.UserID, p.CredentialID, p.UserHandle, p.PublicKey, p.SignCount, p.Name, p.CreatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return auth.ErrPasskeyExists
+		}
+		return fmt.Errorf("failed to create passkey: %w", err)
+	}
+
+	p.ID = id
+	return nil
+}
+
+// GetByCredentialID retrieves the passkey with a credential ID
+func (r *PasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*auth.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM passkeys WHERE credential_id = ?`
+
+	var p auth.Passkey
+	err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), credentialID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, auth.ErrPasskeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+
+	return &p, nil
+}
+
+// ListByUser retrieves the passkeys of a user, oldest first
+func (r *PasskeyRepository) ListByUser(ctx context.Context, userID int) ([]*auth.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM passkeys WHERE user_id = ? ORDER BY id ASC`
+
+	var passkeys []*auth.Passkey
+	if err := conn(ctx, r.db).SelectContext(ctx, &passkeys, r.db.Rebind(query), userID); err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	return passkeys, nil
+}
+
+// UpdateUsage stores the signature counter and last use of a passkey
+func (r *PasskeyRepository) UpdateUsage(ctx context.Context, p *auth.Passkey) error {
+	query := `UPDATE passkeys SET sign_count = ?, last_used_at = ? WHERE id = ?`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), p.SignCount, p.LastUsedAt, p.ID); err != nil {
+		return fmt.Errorf("failed to update passkey usage: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a passkey of a user
+func (r *PasskeyRepository) Delete(ctx context.Context, userID, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM passkeys WHERE id = ? AND user_id = ?`), id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return auth.ErrPasskeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/user"
+)
+
+// PasswordResetRepository implements the user.PasswordResetRepository interface using SQLX
+type PasswordResetRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasswordResetRepository creates a new PasswordResetRepository instance
+func NewPasswordResetRepository(db *sqlx.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create stores a pending password reset, replacing any earlier reset for the same user
+func (r *PasswordResetRepository) Create(ctx context.Context, reset *user.PasswordReset) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM password_resets WHERE user_id = ?`), reset.UserID); err != nil {
+		return fmt.Errorf("failed to clear previous password reset: %w", err)
+	}
+
+	query := `
+		INSERT INTO password_resets (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, tx, query, reset.UserID, reset.TokenHash, reset.ExpiresAt, reset.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit password reset: %w", err)
+	}
+
+	reset.ID = id
+	return nil
+}
+
+// GetByTokenHash retrieves a pending password reset by its token hash
+func (r *PasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.PasswordReset, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at
+		FROM password_resets
+		WHERE token_hash = ?
+	`
+
+	var reset user.PasswordReset
+	err := conn(ctx, r.db).GetContext(ctx, &reset, r.db.Rebind(query), tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrPasswordResetNotFound
+		}
+		return nil, fmt.Errorf("failed to get password reset: %w", err)
+	}
+
+	return &reset, nil
+}
+
+// Complete sets the new password, unlocks the account and removes the reset
+// in a single transaction
+func (r *PasswordResetRepository) Complete(ctx context.Context, reset *user.PasswordReset, passwordHash string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET password_hash = ?, password_reset_required_at = NULL, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), passwordHash, time.Now(), reset.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM password_resets WHERE id = ?`), reset.ID); err != nil {
+		return fmt.Errorf("failed to delete password reset: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit password reset: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a pending password reset
+func (r *PasswordResetRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM password_resets WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete password reset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrPasswordResetNotFound
+	}
+
+	return nil
+}
+
+// Verify that PasswordResetRepository implements the user.PasswordResetRepository interface
+var _ user.PasswordResetRepository = (*PasswordResetRepository)(nil)
@@ -4,11 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
 )
 
+// postColumns lists the columns selected when loading a post
+const postColumns = `id, client_id, title, slug, content, author_id, noindex, access, visibility, language, status, published_at, timezone, view_count, tip_count, word_count, reading_time_minutes, excerpt, pinned_at, featured_at, series_id, series_position, created_at, updated_at`
+
+// qualifiedPostColumns lists the post columns for queries joining the posts
+// table as p
+var qualifiedPostColumns = "p." + strings.ReplaceAll(postColumns, ", ", ", p.")
+
 // PostRepository implements the post.Repository interface using SQLX
 type PostRepository struct {
 	db *sqlx.DB
@@ -26,34 +37,49 @@ func (r *PostRepository) Create(ctx context.Context, p *post.Post) error {
 	}
 
 	query := `
-		INSERT INTO posts (title, content, author_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO posts (client_id, title, slug, content, author_id, noindex, access, visibility, language, status, published_at, timezone, word_count, reading_time_minutes, excerpt, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, p.Title, p.Content, p.AuthorID, p.CreatedAt, p.UpdatedAt)
+	// Another post may take the slug between the caller's check and this
+	// insert, so move on to the next numbered slug when it does. A taken
+	// client ID is a retried write, which another slug does not fix.
+	var id int
+	base := post.Slugify(p.Title)
+	err := retryOnDuplicate(maxSlugRetries, func() error {
+		var err error
+		id, err = insertID(ctx, conn(ctx, r.db), query, p.ClientID, p.Title, p.Slug, p.Content, p.AuthorID, p.NoIndex, p.Access, p.Visibility, p.Language, p.Status, p.PublishedAt, p.Timezone, p.WordCount, p.ReadingTime, p.Excerpt, p.CreatedAt, p.UpdatedAt)
+		if isDuplicateKeyOn(err, "client_id") {
+			return post.ErrClientIDTaken
+		}
+		return err
+	}, func() {
+		p.Slug = post.NextSlug(base, p.Slug)
+	})
 	if err != nil {
+		if err == post.ErrClientIDTaken {
+			return err
+		}
+		if isDuplicateKeyError(err) {
+			return post.ErrSlugTaken
+		}
 		return fmt.Errorf("failed to create post: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
-	}
-
-	p.ID = int(id)
+	p.ID = id
 	return nil
 }
 
 // GetByID retrieves a post by its ID
 func (r *PostRepository) GetByID(ctx context.Context, id int) (*post.Post, error) {
 	query := `
-		SELECT id, title, content, author_id, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
 		WHERE id = ?
 	`
 
 	var p post.Post
-	err := r.db.GetContext(ctx, &p, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, post.ErrPostNotFound
@@ -61,13 +87,108 @@ func (r *PostRepository) GetByID(ctx context.Context, id int) (*post.Post, error
 		return nil, fmt.Errorf("failed to get post by ID: %w", err)
 	}
 
+	if err := r.loadTags(ctx, &p); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetBySlug retrieves a post by its slug
+func (r *PostRepository) GetBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE slug = ?
+	`
+
+	var p post.Post
+	err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to get post by slug: %w", err)
+	}
+
+	if err := r.loadTags(ctx, &p); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetVisibleByID retrieves a post by its ID when the filter finds it
+func (r *PostRepository) GetVisibleByID(ctx context.Context, id int, filter post.ViewFilter) (*post.Post, error) {
+	return r.getVisible(ctx, "id = ?", id, filter)
+}
+
+// GetVisibleBySlug retrieves a post by its slug when the filter finds it
+func (r *PostRepository) GetVisibleBySlug(ctx context.Context, slug string, filter post.ViewFilter) (*post.Post, error) {
+	return r.getVisible(ctx, "slug = ?", slug, filter)
+}
+
+// getVisible retrieves the post matching a condition on one column, unless
+// it is unpublished or private and the filter leaves it out
+func (r *PostRepository) getVisible(ctx context.Context, condition string, value any, filter post.ViewFilter) (*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE ` + condition + ` AND ((status = ? AND visibility <> ?) OR author_id = ? OR ?)
+	`
+
+	var p post.Post
+	err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), value, post.StatusPublished, post.VisibilityPrivate, filter.AuthorID, filter.All)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to get visible post: %w", err)
+	}
+
+	if err := r.loadTags(ctx, &p); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetByClientID retrieves a post by the client ID it was created with
+func (r *PostRepository) GetByClientID(ctx context.Context, clientID string) (*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE client_id = ?
+	`
+
+	var p post.Post
+	err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to get post by client ID: %w", err)
+	}
+
+	if err := r.loadTags(ctx, &p); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, &p); err != nil {
+		return nil, err
+	}
 	return &p, nil
 }
 
 // GetByAuthorID retrieves posts by author ID with pagination
 func (r *PostRepository) GetByAuthorID(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
 	query := `
-		SELECT id, title, content, author_id, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
 		WHERE author_id = ?
 		ORDER BY created_at DESC
@@ -75,29 +196,364 @@ func (r *PostRepository) GetByAuthorID(ctx context.Context, authorID int, limit,
 	`
 
 	var posts []*post.Post
-	err := r.db.SelectContext(ctx, &posts, query, authorID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), authorID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get posts by author ID: %w", err)
 	}
 
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
 	return posts, nil
 }
 
-// List retrieves all posts with pagination
+// List retrieves all posts with pagination, pinned posts first
 func (r *PostRepository) List(ctx context.Context, limit, offset int) ([]*post.Post, error) {
 	query := `
-		SELECT id, title, content, author_id, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
-		ORDER BY created_at DESC
+		ORDER BY pinned_at IS NULL, pinned_at DESC, created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
 	var posts []*post.Post
-	err := r.db.SelectContext(ctx, &posts, query, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list posts: %w", err)
 	}
 
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListAfter retrieves the posts matching the filter after a cursor, most
+// recently created first. Ties on created_at are broken by ID, so pages
+// neither skip nor repeat posts created in the same second.
+func (r *PostRepository) ListAfter(ctx context.Context, filter post.ListFilter, after keyset.Cursor, limit int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + qualifiedPostColumns + `
+		FROM posts p
+		WHERE ((p.status = ? AND p.visibility = ?) OR p.author_id = ? OR ?)
+	`
+	args := []any{post.StatusPublished, post.VisibilityPublic, filter.AuthorID, filter.All}
+	if filter.Tag != "" {
+		query += `
+			AND EXISTS (
+				SELECT 1
+				FROM post_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = p.id AND t.name = ?
+			)
+		`
+		args = append(args, filter.Tag)
+	}
+	if !after.IsZero() {
+		query += " AND (p.created_at < ? OR (p.created_at = ? AND p.id < ?))"
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+	query += " ORDER BY p.created_at DESC, p.id DESC LIMIT ?"
+	args = append(args, limit)
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list posts after cursor: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// postListOrders maps the sorts of ListMatching to their ORDER BY clauses.
+// Clauses only come from this table, never from the request; ties are
+// broken by ID so pages are stable.
+var postListOrders = map[post.SortField]map[post.SortOrder]string{
+	post.SortCreatedAt: {
+		post.OrderAsc:  ` ORDER BY p.created_at ASC, p.id ASC`,
+		post.OrderDesc: ` ORDER BY p.created_at DESC, p.id DESC`,
+	},
+	post.SortUpdatedAt: {
+		post.OrderAsc:  ` ORDER BY p.updated_at ASC, p.id ASC`,
+		post.OrderDesc: ` ORDER BY p.updated_at DESC, p.id DESC`,
+	},
+	post.SortTitle: {
+		post.OrderAsc:  ` ORDER BY p.title ASC, p.id ASC`,
+		post.OrderDesc: ` ORDER BY p.title DESC, p.id DESC`,
+	},
+}
+
+// ListMatching retrieves the posts matching the filter and options with
+// pagination. Unset options match every post.
+func (r *PostRepository) ListMatching(ctx context.Context, filter post.ListFilter, options post.ListOptions, limit, offset int) ([]*post.Post, error) {
+	order, ok := postListOrders[options.Sort][options.Order]
+	if !ok {
+		return nil, post.ErrInvalidSort
+	}
+
+	query := `
+		SELECT ` + qualifiedPostColumns + `
+		FROM posts p
+		WHERE ((p.status = ? AND p.visibility = ?) OR p.author_id = ? OR ?)
+			AND (? = '' OR EXISTS (
+				SELECT 1
+				FROM post_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = p.id AND t.name = ?
+			))
+			AND (? = 0 OR p.author_id = ?)
+	`
+	args := []any{
+		post.StatusPublished, post.VisibilityPublic, filter.AuthorID, filter.All,
+		filter.Tag, filter.Tag,
+		options.AuthorID, options.AuthorID,
+	}
+	// PostgreSQL cannot type a parameter only tested for NULL, so the
+	// time bounds are added when set
+	if options.CreatedAfter != nil {
+		query += ` AND p.created_at >= ?`
+		args = append(args, *options.CreatedAfter)
+	}
+	if options.CreatedBefore != nil {
+		query += ` AND p.created_at < ?`
+		args = append(args, *options.CreatedBefore)
+	}
+	query += order + ` LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list matching posts: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPublished retrieves published public posts with pagination, pinned
+// posts first, then the most recently published
+func (r *PostRepository) ListPublished(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = ? AND visibility = ?
+		ORDER BY pinned_at IS NULL, pinned_at DESC, published_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list published posts: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPublishedBy retrieves the published public posts of an author,
+// carrying a tag, or both, most recently published first
+func (r *PostRepository) ListPublishedBy(ctx context.Context, filter post.PublishedFilter, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + qualifiedPostColumns + `
+		FROM posts p
+		WHERE p.status = ? AND p.visibility = ?
+			AND (? = 0 OR p.author_id = ?)
+			AND (? = '' OR EXISTS (
+				SELECT 1
+				FROM post_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = p.id AND t.name = ?
+			))
+			AND (? = 0 OR p.author_id IN (
+				SELECT f.followee_id FROM follows f WHERE f.follower_id = ?
+			))
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic, filter.AuthorID, filter.AuthorID, filter.Tag, filter.Tag, filter.FollowedBy, filter.FollowedBy, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list published posts: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListVisibleTo retrieves published public posts and every post of the
+// user's own with pagination, pinned posts first
+func (r *PostRepository) ListVisibleTo(ctx context.Context, userID int, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE (status = ? AND visibility = ?) OR author_id = ?
+		ORDER BY pinned_at IS NULL, pinned_at DESC, created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list visible posts: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListByTag retrieves the posts carrying a tag with pagination, pinned
+// posts first
+func (r *PostRepository) ListByTag(ctx context.Context, filter post.TagFilter, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + qualifiedPostColumns + `
+		FROM posts p
+		JOIN post_tags pt ON pt.post_id = p.id
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE t.name = ? AND ((p.status = ? AND p.visibility = ?) OR p.author_id = ? OR ?)
+		ORDER BY p.pinned_at IS NULL, p.pinned_at DESC, p.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), filter.Tag, post.StatusPublished, post.VisibilityPublic, filter.AuthorID, filter.All, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts by tag: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPublishingBetween retrieves the scheduled and published posts whose
+// publish time is within the range, earliest first
+func (r *PostRepository) ListPublishingBetween(ctx context.Context, from, to time.Time) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status IN (?, ?) AND published_at BETWEEN ? AND ?
+		ORDER BY published_at, id
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusScheduled, post.StatusPublished, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts by publish time: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListDueScheduled retrieves scheduled posts whose publish time has come
+func (r *PostRepository) ListDueScheduled(ctx context.Context, now time.Time) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE status = ? AND published_at <= ?
+		ORDER BY published_at
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusScheduled, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// ListSharingTags retrieves the published posts sharing tags with a post,
+// ranked by the number of tags they share
+func (r *PostRepository) ListSharingTags(ctx context.Context, postID, limit int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + qualifiedPostColumns + `
+		FROM posts p
+		JOIN (
+			SELECT pt.post_id, COUNT(*) AS shared
+			FROM post_tags pt
+			WHERE pt.tag_id IN (SELECT tag_id FROM post_tags WHERE post_id = ?) AND pt.post_id <> ?
+			GROUP BY pt.post_id
+		) s ON s.post_id = p.id
+		WHERE p.status = ? AND p.visibility = ?
+		ORDER BY s.shared DESC, p.published_at DESC, p.id DESC
+		LIMIT ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), postID, postID, post.StatusPublished, post.VisibilityPublic, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts sharing tags: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListMissingReadingStats retrieves posts whose reading stats were never
+// computed; any content takes at least a minute to read
+func (r *PostRepository) ListMissingReadingStats(ctx context.Context, afterID, limit int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE id > ? AND reading_time_minutes = 0 AND content <> ''
+		ORDER BY id
+		LIMIT ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts missing reading stats: %w", err)
+	}
+
 	return posts, nil
 }
 
@@ -107,13 +563,62 @@ func (r *PostRepository) Update(ctx context.Context, p *post.Post) error {
 		return post.ErrInvalidPostData
 	}
 
+	return updatePost(ctx, conn(ctx, r.db), p)
+}
+
+// UpdateWithRevision saves the post and a revision of its previous version
+// in a single transaction
+func (r *PostRepository) UpdateWithRevision(ctx context.Context, p *post.Post, rev *post.Revision) error {
+	if p == nil || rev == nil {
+		return post.ErrInvalidPostData
+	}
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updatePost(ctx, tx, p); err != nil {
+		return err
+	}
+
+	// The post row is locked by the update, so concurrent edits of the same
+	// post wait here and get the next number
+	var number int
+	if err := tx.GetContext(ctx, &number, tx.Rebind(`SELECT COALESCE(MAX(number), 0) + 1 FROM post_revisions WHERE post_id = ?`), p.ID); err != nil {
+		return fmt.Errorf("failed to number post revision: %w", err)
+	}
+
+	query := `
+		INSERT INTO post_revisions (post_id, number, title, content, edited_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, tx, query, p.ID, number, rev.Title, rev.Content, rev.EditedBy, rev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create post revision: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit post revision: %w", err)
+	}
+
+	rev.ID = id
+	rev.PostID = p.ID
+	rev.Number = number
+	return nil
+}
+
+// updatePost saves the editable columns of a post
+func updatePost(ctx context.Context, db sqlx.ExtContext, p *post.Post) error {
 	query := `
 		UPDATE posts
-		SET title = ?, content = ?, updated_at = ?
+		SET title = ?, content = ?, noindex = ?, access = ?, status = ?, published_at = ?, timezone = ?, word_count = ?, reading_time_minutes = ?, excerpt = ?, pinned_at = ?, featured_at = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, p.Title, p.Content, p.UpdatedAt, p.ID)
+	result, err := db.ExecContext(ctx, db.Rebind(query), p.Title, p.Content, p.NoIndex, p.Access, p.Status, p.PublishedAt, p.Timezone, p.WordCount, p.ReadingTime, p.Excerpt, p.PinnedAt, p.FeaturedAt, p.UpdatedAt, p.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update post: %w", err)
 	}
@@ -130,11 +635,447 @@ func (r *PostRepository) Update(ctx context.Context, p *post.Post) error {
 	return nil
 }
 
-// Delete removes a post from the database
+// ListRevisions lists the revisions of a post, newest first
+func (r *PostRepository) ListRevisions(ctx context.Context, postID int) ([]*post.Revision, error) {
+	query := `
+		SELECT id, post_id, number, title, content, edited_by, created_at
+		FROM post_revisions
+		WHERE post_id = ?
+		ORDER BY number DESC
+	`
+
+	var revisions []*post.Revision
+	if err := conn(ctx, r.db).SelectContext(ctx, &revisions, r.db.Rebind(query), postID); err != nil {
+		return nil, fmt.Errorf("failed to list post revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves a revision of a post by its number
+func (r *PostRepository) GetRevision(ctx context.Context, postID, number int) (*post.Revision, error) {
+	query := `
+		SELECT id, post_id, number, title, content, edited_by, created_at
+		FROM post_revisions
+		WHERE post_id = ? AND number = ?
+	`
+
+	var rev post.Revision
+	err := conn(ctx, r.db).GetContext(ctx, &rev, r.db.Rebind(query), postID, number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get post revision: %w", err)
+	}
+
+	return &rev, nil
+}
+
+// GetTranslation retrieves the translation of a post to a language
+func (r *PostRepository) GetTranslation(ctx context.Context, postID int, language string) (*post.Translation, error) {
+	query := `
+		SELECT id, post_id, language, title, content, translated_by, created_at, updated_at
+		FROM post_translations
+		WHERE post_id = ? AND language = ?
+	`
+
+	var t post.Translation
+	err := conn(ctx, r.db).GetContext(ctx, &t, r.db.Rebind(query), postID, language)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrTranslationNotFound
+		}
+		return nil, fmt.Errorf("failed to get post translation: %w", err)
+	}
+
+	return &t, nil
+}
+
+// SaveTranslation inserts the translation of a post, or replaces the one
+// to the same language
+func (r *PostRepository) SaveTranslation(ctx context.Context, t *post.Translation) error {
+	if t == nil {
+		return post.ErrInvalidPostData
+	}
+
+	query := `
+		INSERT INTO post_translations (post_id, language, title, content, translated_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	` + upsert(r.db, "post_id, language", "title", "content", "translated_by", "updated_at")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), t.PostID, t.Language, t.Title, t.Content, t.TranslatedBy, t.CreatedAt, t.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save post translation: %w", err)
+	}
+
+	return nil
+}
+
+// ChangeSlug sets the slug of a post and redirects its previous slug to it.
+// A redirect from the new slug is removed, since the post now owns it.
+func (r *PostRepository) ChangeSlug(ctx context.Context, id int, slug string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous string
+	if err := tx.GetContext(ctx, &previous, tx.Rebind(`SELECT slug FROM posts WHERE id = ?`+forUpdate(tx)), id); err != nil {
+		if err == sql.ErrNoRows {
+			return post.ErrPostNotFound
+		}
+		return fmt.Errorf("failed to get post slug: %w", err)
+	}
+	if previous == slug {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE posts SET slug = ? WHERE id = ?`), slug, id); err != nil {
+		if isDuplicateKeyError(err) {
+			return post.ErrSlugTaken
+		}
+		return fmt.Errorf("failed to change post slug: %w", err)
+	}
+
+	// Links to the previous slug were links to this post, so it takes over
+	// any redirect of the slug
+	query := `
+		INSERT INTO post_redirects (from_slug, post_id, automatic)
+		VALUES (?, ?, TRUE)
+	` + upsert(tx, "from_slug", "post_id", "automatic")
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), previous, id); err != nil {
+		return fmt.Errorf("failed to record slug redirect: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM post_redirects WHERE from_slug = ?`), slug); err != nil {
+		return fmt.Errorf("failed to delete slug redirect: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit slug change: %w", err)
+	}
+
+	return nil
+}
+
+// SetVisibility saves the visibility of a post. Synced clients got the post
+// while it was public, so a tombstone tells them to drop it when it stops
+// being public; it comes back as a change once public again.
+func (r *PostRepository) SetVisibility(ctx context.Context, p *post.Post) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if p.Visibility != post.VisibilityPublic {
+		if err := addTombstones(ctx, tx, change.KindPost, `SELECT id FROM posts WHERE id = ? AND status = ? AND visibility = ?`, p.ID, post.StatusPublished, post.VisibilityPublic); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE posts SET visibility = ?, updated_at = ? WHERE id = ?`), p.Visibility, p.UpdatedAt, p.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set post visibility: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return post.ErrPostNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit visibility change: %w", err)
+	}
+	return nil
+}
+
+// SetTags replaces the tags of a post, creating the tags that do not exist yet
+func (r *PostRepository) SetTags(ctx context.Context, postID int, tags []string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Posts list their tags, so the post changed for sync clients
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`), postID); err != nil {
+		return fmt.Errorf("failed to touch post: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM post_tags WHERE post_id = ?`), postID); err != nil {
+		return fmt.Errorf("failed to clear post tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		names := make([]any, len(tags))
+		for i, name := range tags {
+			names[i] = name
+		}
+
+		// Tags are shared between posts, so keep the ones that already exist
+		values := strings.TrimSuffix(strings.Repeat("(?), ", len(tags)), ", ")
+		if _, err := tx.ExecContext(ctx, tx.Rebind(insertIgnore(tx, `INSERT INTO tags (name) VALUES `+values)), names...); err != nil {
+			return fmt.Errorf("failed to create tags: %w", err)
+		}
+
+		query, args, err := sqlx.In(`
+			INSERT INTO post_tags (post_id, tag_id)
+			SELECT ?, id FROM tags WHERE name IN (?)
+		`, postID, tags)
+		if err != nil {
+			return fmt.Errorf("failed to build post tags query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return fmt.Errorf("failed to set post tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit post tags: %w", err)
+	}
+
+	return nil
+}
+
+// SetReadingStats saves the reading stats of a post. They are derived from
+// the content rather than edited, so updated_at is kept.
+func (r *PostRepository) SetReadingStats(ctx context.Context, p *post.Post) error {
+	query := `UPDATE posts SET word_count = ?, reading_time_minutes = ?, excerpt = ?, updated_at = updated_at WHERE id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), p.WordCount, p.ReadingTime, p.Excerpt, p.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set post reading stats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return post.ErrPostNotFound
+	}
+
+	return nil
+}
+
+// AddViews adds view counts to the daily and total views in a single
+// transaction, so a failed batch can be retried without counting twice
+func (r *PostRepository) AddViews(ctx context.Context, counts []post.ViewCount) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Selecting from posts drops the views of posts deleted since they were
+	// counted instead of failing the foreign key
+	daily := `
+		INSERT INTO post_daily_views (post_id, day, views)
+		SELECT id, ?, ? FROM posts WHERE id = ?
+	` + onConflict(tx, "post_id, day") + " views = post_daily_views.views + " + inserted(tx, "views")
+	// Counting views is not an edit, so updated_at is kept
+	total := `UPDATE posts SET view_count = view_count + ?, updated_at = updated_at WHERE id = ?`
+
+	for _, c := range counts {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(daily), c.Day.Format(time.DateOnly), c.Views, c.PostID); err != nil {
+			return fmt.Errorf("failed to add daily post views: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(total), c.Views, c.PostID); err != nil {
+			return fmt.Errorf("failed to add post views: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit post views: %w", err)
+	}
+
+	return nil
+}
+
+// ListPopular retrieves the published posts with the most views since a day
+func (r *PostRepository) ListPopular(ctx context.Context, since time.Time, limit int) ([]*post.PopularPost, error) {
+	query := `
+		SELECT ` + qualifiedPostColumns + `,
+			v.views AS window_views
+		FROM posts p
+		JOIN (
+			SELECT post_id, SUM(views) AS views
+			FROM post_daily_views
+			WHERE day >= ?
+			GROUP BY post_id
+		) v ON v.post_id = p.id
+		WHERE p.status = ? AND p.visibility = ?
+		ORDER BY v.views DESC, p.id DESC
+		LIMIT ?
+	`
+
+	var rows []struct {
+		post.Post
+		WindowViews int64 `db:"window_views"`
+	}
+	err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), since.Format(time.DateOnly), post.StatusPublished, post.VisibilityPublic, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list popular posts: %w", err)
+	}
+
+	popular := make([]*post.PopularPost, len(rows))
+	posts := make([]*post.Post, len(rows))
+	for i := range rows {
+		posts[i] = &rows[i].Post
+		popular[i] = &post.PopularPost{Post: posts[i], Views: rows[i].WindowViews}
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return popular, nil
+}
+
+// ListFeatured retrieves the featured published posts, most recently
+// featured first
+func (r *PostRepository) ListFeatured(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE featured_at IS NOT NULL AND status = ? AND visibility = ?
+		ORDER BY featured_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured posts: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPublishedByIDs retrieves the published posts with the given IDs
+func (r *PostRepository) ListPublishedByIDs(ctx context.Context, ids []int) ([]*post.Post, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE id IN (?) AND status = ? AND visibility = ?
+	`, ids, post.StatusPublished, post.VisibilityPublic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build posts by ID query: %w", err)
+	}
+
+	var posts []*post.Post
+	if err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list posts by ID: %w", err)
+	}
+
+	if err := r.loadTags(ctx, posts...); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(ctx, posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListActivity retrieves the daily views, bookmarks and approved comments
+// of published posts since a time
+func (r *PostRepository) ListActivity(ctx context.Context, since time.Time) ([]post.Activity, error) {
+	// Days are scanned as text: drivers return DATE columns as dates or
+	// strings, and both convert to text starting with the day
+	var views []struct {
+		PostID int    `db:"post_id"`
+		Day    string `db:"day"`
+		Views  int64  `db:"views"`
+	}
+	query := `
+		SELECT v.post_id, v.day, v.views
+		FROM post_daily_views v
+		JOIN posts p ON p.id = v.post_id
+		WHERE v.day >= ? AND p.status = ? AND p.visibility = ?
+	`
+	err := conn(ctx, r.db).SelectContext(ctx, &views, r.db.Rebind(query), post.ViewDay(since).Format(time.DateOnly), post.StatusPublished, post.VisibilityPublic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list post views: %w", err)
+	}
+
+	activity := make([]post.Activity, 0, len(views))
+	for _, v := range views {
+		if len(v.Day) < len(time.DateOnly) {
+			return nil, fmt.Errorf("failed to parse view day %q", v.Day)
+		}
+		day, err := time.Parse(time.DateOnly, v.Day[:len(time.DateOnly)])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse view day: %w", err)
+		}
+		activity = append(activity, post.Activity{PostID: v.PostID, Kind: post.ActivityView, At: day, Count: v.Views})
+	}
+
+	events := []struct {
+		kind  post.ActivityKind
+		query string
+	}{
+		{post.ActivityBookmark, `
+			SELECT b.post_id, b.created_at
+			FROM bookmarks b
+			JOIN posts p ON p.id = b.post_id
+			WHERE b.created_at >= ? AND p.status = ? AND p.visibility = ?
+		`},
+		{post.ActivityComment, `
+			SELECT c.post_id, c.created_at
+			FROM comments c
+			JOIN posts p ON p.id = c.post_id
+			WHERE c.created_at >= ? AND c.status = 'approved' AND p.status = ? AND p.visibility = ?
+		`},
+	}
+	for _, e := range events {
+		var rows []struct {
+			PostID    int       `db:"post_id"`
+			CreatedAt time.Time `db:"created_at"`
+		}
+		if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(e.query), since, post.StatusPublished, post.VisibilityPublic); err != nil {
+			return nil, fmt.Errorf("failed to list post %ss: %w", e.kind, err)
+		}
+		for _, row := range rows {
+			activity = append(activity, post.Activity{PostID: row.PostID, Kind: e.kind, At: row.CreatedAt, Count: 1})
+		}
+	}
+
+	return activity, nil
+}
+
+// Delete removes a post from the database, leaving tombstones for it and
+// its comments
 func (r *PostRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM posts WHERE id = ?`
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The comments go with the post. Sync clients never saw unpublished
+	// posts, so those leave no tombstone.
+	if err := addTombstones(ctx, tx, change.KindComment, `SELECT id FROM comments WHERE post_id = ?`, id); err != nil {
+		return err
+	}
+	if err := addTombstones(ctx, tx, change.KindPost, `SELECT id FROM posts WHERE id = ? AND status = ?`, id, post.StatusPublished); err != nil {
+		return err
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM posts WHERE id = ?`), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete post: %w", err)
 	}
@@ -148,5 +1089,87 @@ func (r *PostRepository) Delete(ctx context.Context, id int) error {
 		return post.ErrPostNotFound
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit post deletion: %w", err)
+	}
+
+	return nil
+}
+
+// loadTags fills in the tags of the posts with a single query
+func (r *PostRepository) loadTags(ctx context.Context, posts ...*post.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*post.Post, len(posts))
+	ids := make([]int, 0, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT pt.post_id, t.name
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id IN (?)
+		ORDER BY t.name
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build post tags query: %w", err)
+	}
+
+	var rows []struct {
+		PostID int    `db:"post_id"`
+		Name   string `db:"name"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load post tags: %w", err)
+	}
+
+	for _, row := range rows {
+		p := byID[row.PostID]
+		p.Tags = append(p.Tags, row.Name)
+	}
+	return nil
+}
+
+// loadTranslations fills in the languages the posts are translated to with
+// a single query
+func (r *PostRepository) loadTranslations(ctx context.Context, posts ...*post.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*post.Post, len(posts))
+	ids := make([]int, 0, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT post_id, language
+		FROM post_translations
+		WHERE post_id IN (?)
+		ORDER BY language
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build post translations query: %w", err)
+	}
+
+	var rows []struct {
+		PostID   int    `db:"post_id"`
+		Language string `db:"language"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load post translations: %w", err)
+	}
+
+	for _, row := range rows {
+		p := byID[row.PostID]
+		p.Translations = append(p.Translations, row.Language)
+	}
 	return nil
 }
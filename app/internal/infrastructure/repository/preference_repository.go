@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/preference"
+)
+
+// PreferenceRepository implements the preference.Repository interface using SQLX
+type PreferenceRepository struct {
+	db *sqlx.DB
+}
+
+// NewPreferenceRepository creates a new PreferenceRepository instance
+func NewPreferenceRepository(db *sqlx.DB) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+// GetFeed retrieves the feed preference of an author or tag
+func (r *PreferenceRepository) GetFeed(ctx context.Context, scope, key string) (*preference.Feed, error) {
+	query := `
+		SELECT scope, scope_key, title, description, updated_by, updated_at
+		FROM feed_preferences
+		WHERE scope = ? AND scope_key = ?
+	`
+
+	var f preference.Feed
+	err := conn(ctx, r.db).GetContext(ctx, &f, r.db.Rebind(query), scope, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, preference.ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get feed preference: %w", err)
+	}
+
+	return &f, nil
+}
+
+// SaveFeed creates or replaces the feed preference of its scope and key
+func (r *PreferenceRepository) SaveFeed(ctx context.Context, f *preference.Feed) error {
+	query := `
+		INSERT INTO feed_preferences (scope, scope_key, title, description, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + upsert(r.db, "scope, scope_key", "title", "description", "updated_by", "updated_at")
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), f.Scope, f.Key, f.Title, f.Description, f.UpdatedBy, f.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save feed preference: %w", err)
+	}
+
+	return nil
+}
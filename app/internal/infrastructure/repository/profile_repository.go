@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// ProfileRepository implements the user.ProfileRepository interface using
+// SQLX
+type ProfileRepository struct {
+	db *sqlx.DB
+}
+
+// NewProfileRepository creates a new ProfileRepository instance
+func NewProfileRepository(db *sqlx.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// GetProfiles retrieves the profiles of the active accounts with the IDs,
+// counting their published public posts
+func (r *ProfileRepository) GetProfiles(ctx context.Context, ids []int) ([]*user.Profile, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT u.id, u.name, u.bio, u.avatar_url, u.created_at AS joined_at,
+			(SELECT COUNT(*) FROM posts p WHERE p.author_id = u.id AND p.status = ? AND p.visibility = ?) AS post_count
+		FROM users u
+		WHERE u.id IN (?) AND u.deleted_at IS NULL AND u.deactivated_at IS NULL
+	`, post.StatusPublished, post.VisibilityPublic, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build profile query: %w", err)
+	}
+
+	var profiles []*user.Profile
+	if err := conn(ctx, r.db).SelectContext(ctx, &profiles, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// UpdateProfile sets the bio and avatar URL of an active account
+func (r *ProfileRepository) UpdateProfile(ctx context.Context, id int, bio, avatarURL string) error {
+	query := `
+		UPDATE users
+		SET bio = ?, avatar_url = ?
+		WHERE id = ? AND deleted_at IS NULL AND deactivated_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), bio, avatarURL, id)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// An unchanged profile affects no rows either, so check it exists
+	if rowsAffected == 0 {
+		profiles, err := r.GetProfiles(ctx, []int{id})
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			return user.ErrUserNotFound
+		}
+	}
+	return nil
+}
+
+// Verify that ProfileRepository implements the user.ProfileRepository
+// interface
+var _ user.ProfileRepository = (*ProfileRepository)(nil)
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+)
+
+// ReadingProgressRepository implements the post.ProgressRepository
+// interface using SQLX
+type ReadingProgressRepository struct {
+	db *sqlx.DB
+}
+
+// NewReadingProgressRepository creates a new ReadingProgressRepository
+// instance
+func NewReadingProgressRepository(db *sqlx.DB) *ReadingProgressRepository {
+	return &ReadingProgressRepository{db: db}
+}
+
+// Save stores a position unless the stored one was recorded at the same
+// time or later. The check happens in the upsert itself so concurrent
+// devices cannot overwrite a newer position; recorded_at is assigned last
+// as the other columns compare against its old value on MySQL.
+func (r *ReadingProgressRepository) Save(ctx context.Context, p *post.Progress) error {
+	ifLater := func(column string) string {
+		return "CASE WHEN " + inserted(r.db, "recorded_at") + " > reading_progress.recorded_at THEN " +
+			inserted(r.db, column) + " ELSE reading_progress." + column + " END"
+	}
+	query := `
+		INSERT INTO reading_progress (user_id, post_id, percent, anchor, recorded_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + onConflict(r.db, "user_id, post_id") + `
+			percent = ` + ifLater("percent") + `,
+			anchor = ` + ifLater("anchor") + `,
+			updated_at = ` + ifLater("updated_at") + `,
+			recorded_at = ` + greatest(r.db) + `(reading_progress.recorded_at, ` + inserted(r.db, "recorded_at") + `)`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), p.UserID, p.PostID, p.Percent, p.Anchor, p.RecordedAt, p.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save reading progress: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves the position of a user on a post
+func (r *ReadingProgressRepository) Get(ctx context.Context, userID, postID int) (*post.Progress, error) {
+	query := `
+		SELECT user_id, post_id, percent, anchor, recorded_at, updated_at
+		FROM reading_progress
+		WHERE user_id = ? AND post_id = ?
+	`
+
+	var p post.Progress
+	if err := conn(ctx, r.db).GetContext(ctx, &p, r.db.Rebind(query), userID, postID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrProgressNotFound
+		}
+		return nil, fmt.Errorf("failed to get reading progress: %w", err)
+	}
+	return &p, nil
+}
+
+// ListByUser retrieves the reading history of a user, most recently read
+// first; posts unpublished or made private since are left out
+func (r *ReadingProgressRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*post.ReadingEntry, error) {
+	query := `
+		SELECT rp.user_id, rp.post_id, rp.percent, rp.anchor, rp.recorded_at, rp.updated_at, p.title, p.slug
+		FROM reading_progress rp
+		JOIN posts p ON p.id = rp.post_id
+		WHERE rp.user_id = ? AND p.status = ? AND p.visibility <> ?
+		ORDER BY rp.recorded_at DESC, rp.post_id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var entries []*post.ReadingEntry
+	if err := conn(ctx, r.db).SelectContext(ctx, &entries, r.db.Rebind(query), userID, post.StatusPublished, post.VisibilityPrivate, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list reading history: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify that ReadingProgressRepository implements the post.ProgressRepository interface
+var _ post.ProgressRepository = (*ReadingProgressRepository)(nil)
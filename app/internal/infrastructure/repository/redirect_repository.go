@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+)
+
+// RedirectRepository implements the post.RedirectRepository interface using
+// SQLX
+type RedirectRepository struct {
+	db *sqlx.DB
+}
+
+// NewRedirectRepository creates a new RedirectRepository instance
+func NewRedirectRepository(db *sqlx.DB) *RedirectRepository {
+	return &RedirectRepository{db: db}
+}
+
+// Create saves a new redirect
+func (r *RedirectRepository) Create(ctx context.Context, rd *post.Redirect) error {
+	query := `
+		INSERT INTO post_redirects (from_slug, post_id, automatic, created_at)
+		VALUES (:from_slug, :post_id, :automatic, :created_at)
+	`
+
+	id, err := namedInsertID(ctx, conn(ctx, r.db), query, rd)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return post.ErrRedirectExists
+		}
+		return fmt.Errorf("failed to create redirect: %w", err)
+	}
+	rd.ID = id
+	return nil
+}
+
+// GetByID retrieves a redirect by its ID
+func (r *RedirectRepository) GetByID(ctx context.Context, id int) (*post.Redirect, error) {
+	return r.get(ctx, `WHERE id = ?`, id)
+}
+
+// GetBySlug retrieves the redirect of an old slug
+func (r *RedirectRepository) GetBySlug(ctx context.Context, slug string) (*post.Redirect, error) {
+	return r.get(ctx, `WHERE from_slug = ?`, slug)
+}
+
+// get retrieves the redirect matching the condition
+func (r *RedirectRepository) get(ctx context.Context, where string, arg any) (*post.Redirect, error) {
+	query := `
+		SELECT id, from_slug, post_id, automatic, created_at
+		FROM post_redirects
+		` + where
+
+	var rd post.Redirect
+	if err := conn(ctx, r.db).GetContext(ctx, &rd, r.db.Rebind(query), arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, post.ErrRedirectNotFound
+		}
+		return nil, fmt.Errorf("failed to get redirect: %w", err)
+	}
+	return &rd, nil
+}
+
+// List retrieves redirects, most recently created first
+func (r *RedirectRepository) List(ctx context.Context, limit, offset int) ([]*post.Redirect, error) {
+	query := `
+		SELECT id, from_slug, post_id, automatic, created_at
+		FROM post_redirects
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var redirects []*post.Redirect
+	if err := conn(ctx, r.db).SelectContext(ctx, &redirects, r.db.Rebind(query), limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list redirects: %w", err)
+	}
+	return redirects, nil
+}
+
+// Update saves the slug and post of a redirect
+func (r *RedirectRepository) Update(ctx context.Context, rd *post.Redirect) error {
+	query := `
+		UPDATE post_redirects
+		SET from_slug = :from_slug, post_id = :post_id, automatic = :automatic
+		WHERE id = :id
+	`
+
+	if _, err := conn(ctx, r.db).NamedExecContext(ctx, query, rd); err != nil {
+		if isDuplicateKeyError(err) {
+			return post.ErrRedirectExists
+		}
+		return fmt.Errorf("failed to update redirect: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a redirect
+func (r *RedirectRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM post_redirects WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete redirect: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return post.ErrRedirectNotFound
+	}
+	return nil
+}
+
+// Verify that RedirectRepository implements the post.RedirectRepository
+// interface
+var _ post.RedirectRepository = (*RedirectRepository)(nil)
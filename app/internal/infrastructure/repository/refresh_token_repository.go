@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/auth"
+)
+
+// RefreshTokenRepository implements the auth.RefreshTokenRepository interface using SQLX
+type RefreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository instance
+func NewRefreshTokenRepository(db *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create stores a new refresh token
+func (r *RefreshTokenRepository) Create(ctx context.Context, t *auth.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, t.UserID, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	t.ID = id
+	return nil
+}
+
+// GetByTokenHash retrieves a refresh token by its hash
+func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+	`
+
+	var t auth.RefreshToken
+	err := conn(ctx, r.db).GetContext(ctx, &t, r.db.Rebind(query), tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, auth.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &t, nil
+}
+
+// Revoke marks a refresh token as revoked; only the first caller succeeds
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id int) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return auth.ErrRefreshTokenRevoked
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every active refresh token of a user
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/scim"
+)
+
+// scimAccountColumns lists the columns selected for a provisioned account;
+// external IDs are stored as NULL when empty
+const scimAccountColumns = `tenant, user_id, user_name, COALESCE(external_id, '') AS external_id, created_at, updated_at`
+
+// scimGroupColumns lists the columns selected for a group
+const scimGroupColumns = `id, tenant, display_name, COALESCE(external_id, '') AS external_id, created_at, updated_at`
+
+// ScimAccountRepository implements the scim.AccountRepository interface using SQLX
+type ScimAccountRepository struct {
+	db *sqlx.DB
+}
+
+// NewScimAccountRepository creates a new ScimAccountRepository instance
+func NewScimAccountRepository(db *sqlx.DB) *ScimAccountRepository {
+	return &ScimAccountRepository{db: db}
+}
+
+// Create links a user to a tenant
+func (r *ScimAccountRepository) Create(ctx context.Context, account *scim.Account) error {
+	query := `
+		INSERT INTO scim_accounts (tenant, user_id, user_name, external_id, created_at, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), account.Tenant, account.UserID, account.UserName, account.ExternalID, account.CreatedAt, account.UpdatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return scim.ErrUserClaimed
+		}
+		return fmt.Errorf("failed to create provisioned account: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the account of a user in a tenant
+func (r *ScimAccountRepository) Get(ctx context.Context, tenant string, userID int) (*scim.Account, error) {
+	return r.get(ctx, `user_id = ?`, tenant, userID)
+}
+
+// GetByUserName retrieves the account with a user name in a tenant
+func (r *ScimAccountRepository) GetByUserName(ctx context.Context, tenant, userName string) (*scim.Account, error) {
+	return r.get(ctx, `user_name = ?`, tenant, userName)
+}
+
+// GetByExternalID retrieves the account with an external ID in a tenant
+func (r *ScimAccountRepository) GetByExternalID(ctx context.Context, tenant, externalID string) (*scim.Account, error) {
+	return r.get(ctx, `external_id = ?`, tenant, externalID)
+}
+
+// get retrieves the account of a tenant matching a condition
+func (r *ScimAccountRepository) get(ctx context.Context, condition, tenant string, value any) (*scim.Account, error) {
+	query := `SELECT ` + scimAccountColumns + ` FROM scim_accounts WHERE tenant = ? AND ` + condition
+
+	var account scim.Account
+	err := conn(ctx, r.db).GetContext(ctx, &account, r.db.Rebind(query), tenant, value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, scim.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get provisioned account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// List retrieves a page of the accounts of a tenant, oldest first, and the
+// number of accounts
+func (r *ScimAccountRepository) List(ctx context.Context, tenant string, offset, limit int) ([]*scim.Account, int, error) {
+	var total int
+	if err := conn(ctx, r.db).GetContext(ctx, &total, r.db.Rebind(`SELECT COUNT(*) FROM scim_accounts WHERE tenant = ?`), tenant); err != nil {
+		return nil, 0, fmt.Errorf("failed to count provisioned accounts: %w", err)
+	}
+
+	query := `SELECT ` + scimAccountColumns + ` FROM scim_accounts WHERE tenant = ? ORDER BY created_at ASC, user_id ASC LIMIT ? OFFSET ?`
+
+	var accounts []*scim.Account
+	if err := conn(ctx, r.db).SelectContext(ctx, &accounts, r.db.Rebind(query), tenant, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list provisioned accounts: %w", err)
+	}
+
+	return accounts, total, nil
+}
+
+// Update stores the user name and external ID of an account
+func (r *ScimAccountRepository) Update(ctx context.Context, account *scim.Account) error {
+	query := `
+		UPDATE scim_accounts SET user_name = ?, external_id = NULLIF(?, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE tenant = ? AND user_id = ?
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), account.UserName, account.ExternalID, account.Tenant, account.UserID); err != nil {
+		if isDuplicateKeyError(err) {
+			return scim.ErrUserClaimed
+		}
+		return fmt.Errorf("failed to update provisioned account: %w", err)
+	}
+
+	return nil
+}
+
+// Delete unlinks a user from a tenant and removes it from the groups of the
+// tenant
+func (r *ScimAccountRepository) Delete(ctx context.Context, tenant string, userID int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM scim_accounts WHERE tenant = ? AND user_id = ?`), tenant, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete provisioned account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return scim.ErrUserNotFound
+	}
+
+	query := `
+		DELETE FROM scim_group_members
+		WHERE user_id = ? AND group_id IN (SELECT id FROM scim_groups WHERE tenant = ?)
+	`
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), userID, tenant); err != nil {
+		return fmt.Errorf("failed to remove group memberships: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit provisioned account removal: %w", err)
+	}
+
+	return nil
+}
+
+// ScimGroupRepository implements the scim.GroupRepository interface using SQLX
+type ScimGroupRepository struct {
+	db *sqlx.DB
+}
+
+// NewScimGroupRepository creates a new ScimGroupRepository instance
+func NewScimGroupRepository(db *sqlx.DB) *ScimGroupRepository {
+	return &ScimGroupRepository{db: db}
+}
+
+// Create stores a group with its members
+func (r *ScimGroupRepository) Create(ctx context.Context, g *scim.Group) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO scim_groups (tenant, display_name, external_id, created_at, updated_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?)
+	`
+
+	id, err := insertID(ctx, tx, query, g.Tenant, g.DisplayName, g.ExternalID, g.CreatedAt, g.UpdatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return scim.ErrGroupExists
+		}
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	if err := insertGroupMembers(ctx, tx, id, g.Members); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group: %w", err)
+	}
+
+	g.ID = id
+	return nil
+}
+
+// GetByID retrieves a group of a tenant with its members
+func (r *ScimGroupRepository) GetByID(ctx context.Context, tenant string, id int) (*scim.Group, error) {
+	return r.get(ctx, `id = ?`, tenant, id)
+}
+
+// GetByDisplayName retrieves the group with a display name in a tenant
+func (r *ScimGroupRepository) GetByDisplayName(ctx context.Context, tenant, displayName string) (*scim.Group, error) {
+	return r.get(ctx, `display_name = ?`, tenant, displayName)
+}
+
+// get retrieves the group of a tenant matching a condition
+func (r *ScimGroupRepository) get(ctx context.Context, condition, tenant string, value any) (*scim.Group, error) {
+	query := `SELECT ` + scimGroupColumns + ` FROM scim_groups WHERE tenant = ? AND ` + condition
+
+	var g scim.Group
+	err := conn(ctx, r.db).GetContext(ctx, &g, r.db.Rebind(query), tenant, value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, scim.ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	if err := r.loadMembers(ctx, []*scim.Group{&g}); err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+// List retrieves a page of the groups of a tenant with their members, oldest
+// first, and the number of groups
+func (r *ScimGroupRepository) List(ctx context.Context, tenant string, offset, limit int) ([]*scim.Group, int, error) {
+	var total int
+	if err := conn(ctx, r.db).GetContext(ctx, &total, r.db.Rebind(`SELECT COUNT(*) FROM scim_groups WHERE tenant = ?`), tenant); err != nil {
+		return nil, 0, fmt.Errorf("failed to count groups: %w", err)
+	}
+
+	query := `SELECT ` + scimGroupColumns + ` FROM scim_groups WHERE tenant = ? ORDER BY id ASC LIMIT ? OFFSET ?`
+
+	var groups []*scim.Group
+	if err := conn(ctx, r.db).SelectContext(ctx, &groups, r.db.Rebind(query), tenant, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	if err := r.loadMembers(ctx, groups); err != nil {
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+// Update stores the attributes of a group and replaces its members
+func (r *ScimGroupRepository) Update(ctx context.Context, g *scim.Group) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE scim_groups SET display_name = ?, external_id = NULLIF(?, ''), updated_at = ?
+		WHERE tenant = ? AND id = ?
+	`
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), g.DisplayName, g.ExternalID, g.UpdatedAt, g.Tenant, g.ID); err != nil {
+		if isDuplicateKeyError(err) {
+			return scim.ErrGroupExists
+		}
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM scim_group_members WHERE group_id = ?`), g.ID); err != nil {
+		return fmt.Errorf("failed to clear group members: %w", err)
+	}
+	if err := insertGroupMembers(ctx, tx, g.ID, g.Members); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a group of a tenant; its memberships cascade
+func (r *ScimGroupRepository) Delete(ctx context.Context, tenant string, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM scim_groups WHERE tenant = ? AND id = ?`), tenant, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return scim.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// ListByMember retrieves the groups of a tenant a user is a member of
+func (r *ScimGroupRepository) ListByMember(ctx context.Context, tenant string, userID int) ([]scim.GroupRef, error) {
+	query := `
+		SELECT g.id, g.display_name FROM scim_groups g
+		JOIN scim_group_members m ON m.group_id = g.id
+		WHERE g.tenant = ? AND m.user_id = ?
+		ORDER BY g.id ASC
+	`
+
+	var refs []scim.GroupRef
+	if err := conn(ctx, r.db).SelectContext(ctx, &refs, r.db.Rebind(query), tenant, userID); err != nil {
+		return nil, fmt.Errorf("failed to list groups of user: %w", err)
+	}
+
+	return refs, nil
+}
+
+// loadMembers fills in the members of groups with a single query
+func (r *ScimGroupRepository) loadMembers(ctx context.Context, groups []*scim.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*scim.Group, len(groups))
+	ids := make([]int, 0, len(groups))
+	for _, g := range groups {
+		g.Members = []int{}
+		byID[g.ID] = g
+		ids = append(ids, g.ID)
+	}
+
+	query, args, err := sqlx.In(`SELECT group_id, user_id FROM scim_group_members WHERE group_id IN (?) ORDER BY user_id ASC`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build group members query: %w", err)
+	}
+
+	var rows []struct {
+		GroupID int `db:"group_id"`
+		UserID  int `db:"user_id"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to load group members: %w", err)
+	}
+
+	for _, row := range rows {
+		g := byID[row.GroupID]
+		g.Members = append(g.Members, row.UserID)
+	}
+
+	return nil
+}
+
+// insertGroupMembers adds users to a group
+func insertGroupMembers(ctx context.Context, tx *scopedTx, groupID int, members []int) error {
+	for _, userID := range members {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(`INSERT INTO scim_group_members (group_id, user_id) VALUES (?, ?)`), groupID, userID); err != nil {
+			return fmt.Errorf("failed to add group member: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+)
+
+// seriesColumns are the columns selected for a series
+const seriesColumns = `id, author_id, title, description, created_at, updated_at`
+
+// SeriesRepository implements the series.Repository interface using SQLX
+type SeriesRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeriesRepository creates a new SeriesRepository instance
+func NewSeriesRepository(db *sqlx.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+// Create inserts a new series
+func (r *SeriesRepository) Create(ctx context.Context, s *series.Series) error {
+	query := `
+		INSERT INTO series (author_id, title, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, s.AuthorID, s.Title, s.Description, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+	s.ID = id
+	return nil
+}
+
+// GetByID retrieves a series by its ID
+func (r *SeriesRepository) GetByID(ctx context.Context, id int) (*series.Series, error) {
+	var s series.Series
+	if err := conn(ctx, r.db).GetContext(ctx, &s, r.db.Rebind(`SELECT `+seriesColumns+` FROM series WHERE id = ?`), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, series.ErrSeriesNotFound
+		}
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+	return &s, nil
+}
+
+// ListByAuthor retrieves the series of an author, newest first
+func (r *SeriesRepository) ListByAuthor(ctx context.Context, authorID int) ([]*series.Series, error) {
+	query := `SELECT ` + seriesColumns + ` FROM series WHERE author_id = ? ORDER BY created_at DESC, id DESC`
+
+	var list []*series.Series
+	if err := conn(ctx, r.db).SelectContext(ctx, &list, r.db.Rebind(query), authorID); err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+	return list, nil
+}
+
+// Update stores the title and description of a series
+func (r *SeriesRepository) Update(ctx context.Context, s *series.Series) error {
+	query := `
+		UPDATE series
+		SET title = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), s.Title, s.Description, s.UpdatedAt, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return series.ErrSeriesNotFound
+	}
+	return nil
+}
+
+// Delete removes a series after taking its posts out of it. Callers run it
+// in a transaction so a failed delete leaves the posts in the series.
+func (r *SeriesRepository) Delete(ctx context.Context, id int) error {
+	if err := r.clearPosts(ctx, id); err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM series WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return series.ErrSeriesNotFound
+	}
+	return nil
+}
+
+// ListPosts retrieves the posts of a series the filter finds in series order
+func (r *SeriesRepository) ListPosts(ctx context.Context, id int, filter post.ViewFilter) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE series_id = ? AND ((status = ? AND visibility <> ?) OR author_id = ? OR ?)
+		ORDER BY series_position, id
+	`
+
+	var posts []*post.Post
+	err := conn(ctx, r.db).SelectContext(ctx, &posts, r.db.Rebind(query), id, post.StatusPublished, post.VisibilityPrivate, filter.AuthorID, filter.All)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series posts: %w", err)
+	}
+	return posts, nil
+}
+
+// SetPosts numbers the posts of a series from 1 in the given order. Callers
+// run it in a transaction so readers never see the series half ordered.
+func (r *SeriesRepository) SetPosts(ctx context.Context, id int, postIDs []int) error {
+	if err := r.clearPosts(ctx, id); err != nil {
+		return err
+	}
+
+	query := r.db.Rebind(`UPDATE posts SET series_id = ?, series_position = ? WHERE id = ?`)
+	for i, postID := range postIDs {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, i+1, postID); err != nil {
+			return fmt.Errorf("failed to add post to series: %w", err)
+		}
+	}
+	return nil
+}
+
+// clearPosts takes every post out of a series
+func (r *SeriesRepository) clearPosts(ctx context.Context, id int) error {
+	query := `UPDATE posts SET series_id = NULL, series_position = 0 WHERE series_id = ?`
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), id); err != nil {
+		return fmt.Errorf("failed to clear series posts: %w", err)
+	}
+	return nil
+}
+
+// Verify that SeriesRepository implements the series.Repository interface
+var _ series.Repository = (*SeriesRepository)(nil)
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/user"
+)
+
+// ssoConnectionColumns lists the columns selected for a connection
+const ssoConnectionColumns = `organization, protocol, enforcement, default_role, role_mappings, groups_claim, issuer, client_id, client_secret_encrypted, metadata, created_at, updated_at`
+
+// SSOConnectionRepository implements the sso.ConnectionRepository interface
+// using SQLX. Client secrets are encrypted with the cipher before they are
+// written.
+type SSOConnectionRepository struct {
+	db     *sqlx.DB
+	cipher SecretCipher
+}
+
+// NewSSOConnectionRepository creates a new SSOConnectionRepository instance
+func NewSSOConnectionRepository(db *sqlx.DB, cipher SecretCipher) *SSOConnectionRepository {
+	return &SSOConnectionRepository{db: db, cipher: cipher}
+}
+
+// ssoConnectionRow is the stored form of a connection
+type ssoConnectionRow struct {
+	Organization          string    `db:"organization"`
+	Protocol              string    `db:"protocol"`
+	Enforcement           string    `db:"enforcement"`
+	DefaultRole           string    `db:"default_role"`
+	RoleMappings          string    `db:"role_mappings"`
+	GroupsClaim           string    `db:"groups_claim"`
+	Issuer                string    `db:"issuer"`
+	ClientID              string    `db:"client_id"`
+	ClientSecretEncrypted string    `db:"client_secret_encrypted"`
+	Metadata              string    `db:"metadata"`
+	CreatedAt             time.Time `db:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at"`
+}
+
+// Save creates or replaces the connection of an organization and the
+// domains it claims
+func (r *SSOConnectionRepository) Save(ctx context.Context, c *sso.Connection) error {
+	secret := ""
+	if c.ClientSecret != "" {
+		encrypted, err := r.cipher.Encrypt(c.ClientSecret)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt sso client secret: %w", err)
+		}
+		secret = encrypted
+	}
+
+	mappings, err := json.Marshal(c.RoleMappings)
+	if err != nil {
+		return fmt.Errorf("failed to encode sso role mappings: %w", err)
+	}
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO sso_connections (organization, protocol, enforcement, default_role, role_mappings, groups_claim, issuer, client_id, client_secret_encrypted, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	` + upsert(tx, "organization", "protocol", "enforcement", "default_role", "role_mappings", "groups_claim",
+		"issuer", "client_id", "client_secret_encrypted", "metadata", "updated_at")
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), c.Organization, c.Protocol, c.Enforcement, c.DefaultRole, string(mappings), c.GroupsClaim,
+		c.Issuer, c.ClientID, secret, c.Metadata, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save sso connection: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM sso_domains WHERE organization = ?`), c.Organization); err != nil {
+		return fmt.Errorf("failed to replace sso domains: %w", err)
+	}
+	for _, domain := range c.Domains {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(`INSERT INTO sso_domains (domain, organization) VALUES (?, ?)`), domain, c.Organization); err != nil {
+			if isDuplicateKeyError(err) {
+				return sso.ErrDomainClaimed
+			}
+			return fmt.Errorf("failed to save sso domain: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByOrganization retrieves the connection of an organization
+func (r *SSOConnectionRepository) GetByOrganization(ctx context.Context, organization string) (*sso.Connection, error) {
+	query := `SELECT ` + ssoConnectionColumns + ` FROM sso_connections WHERE organization = ?`
+	return r.get(ctx, query, organization)
+}
+
+// GetByDomain retrieves the connection claiming an email domain
+func (r *SSOConnectionRepository) GetByDomain(ctx context.Context, domain string) (*sso.Connection, error) {
+	query := `
+		SELECT ` + ssoConnectionColumns + ` FROM sso_connections
+		WHERE organization = (SELECT organization FROM sso_domains WHERE domain = ?)
+	`
+	return r.get(ctx, query, domain)
+}
+
+// get retrieves the connection selected by a query
+func (r *SSOConnectionRepository) get(ctx context.Context, query string, arg any) (*sso.Connection, error) {
+	var row ssoConnectionRow
+	err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(query), arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sso.ErrConnectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get sso connection: %w", err)
+	}
+
+	connections, err := r.toConnections(ctx, []ssoConnectionRow{row})
+	if err != nil {
+		return nil, err
+	}
+	return connections[0], nil
+}
+
+// List retrieves all connections, ordered by organization
+func (r *SSOConnectionRepository) List(ctx context.Context) ([]*sso.Connection, error) {
+	query := `SELECT ` + ssoConnectionColumns + ` FROM sso_connections ORDER BY organization ASC`
+
+	var rows []ssoConnectionRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query)); err != nil {
+		return nil, fmt.Errorf("failed to list sso connections: %w", err)
+	}
+
+	return r.toConnections(ctx, rows)
+}
+
+// Delete removes the connection of an organization; its domains and
+// memberships are removed with it
+func (r *SSOConnectionRepository) Delete(ctx context.Context, organization string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM sso_connections WHERE organization = ?`), organization)
+	if err != nil {
+		return fmt.Errorf("failed to delete sso connection: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sso.ErrConnectionNotFound
+	}
+
+	return nil
+}
+
+// toConnections decodes stored connections and loads their domains
+func (r *SSOConnectionRepository) toConnections(ctx context.Context, rows []ssoConnectionRow) ([]*sso.Connection, error) {
+	connections := make([]*sso.Connection, 0, len(rows))
+	if len(rows) == 0 {
+		return connections, nil
+	}
+
+	organizations := make([]string, len(rows))
+	for i, row := range rows {
+		organizations[i] = row.Organization
+	}
+	query, args, err := sqlx.In(`SELECT domain, organization FROM sso_domains WHERE organization IN (?) ORDER BY domain ASC`, organizations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sso domain query: %w", err)
+	}
+	var domains []struct {
+		Domain       string `db:"domain"`
+		Organization string `db:"organization"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &domains, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to list sso domains: %w", err)
+	}
+	byOrganization := make(map[string][]string, len(rows))
+	for _, d := range domains {
+		byOrganization[d.Organization] = append(byOrganization[d.Organization], d.Domain)
+	}
+
+	for _, row := range rows {
+		c := &sso.Connection{
+			Organization: row.Organization,
+			Protocol:     sso.Protocol(row.Protocol),
+			Domains:      byOrganization[row.Organization],
+			Enforcement:  sso.Enforcement(row.Enforcement),
+			DefaultRole:  user.Role(row.DefaultRole),
+			GroupsClaim:  row.GroupsClaim,
+			Issuer:       row.Issuer,
+			ClientID:     row.ClientID,
+			Metadata:     row.Metadata,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+		}
+		if err := json.Unmarshal([]byte(row.RoleMappings), &c.RoleMappings); err != nil {
+			return nil, fmt.Errorf("failed to decode sso role mappings: %w", err)
+		}
+		if row.ClientSecretEncrypted != "" {
+			secret, err := r.cipher.Decrypt(row.ClientSecretEncrypted)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt sso client secret: %w", err)
+			}
+			c.ClientSecret = secret
+		}
+		connections = append(connections, c)
+	}
+
+	return connections, nil
+}
+
+// SSOMembershipRepository implements the sso.MembershipRepository interface
+// using SQLX
+type SSOMembershipRepository struct {
+	db *sqlx.DB
+}
+
+// NewSSOMembershipRepository creates a new SSOMembershipRepository instance
+func NewSSOMembershipRepository(db *sqlx.DB) *SSOMembershipRepository {
+	return &SSOMembershipRepository{db: db}
+}
+
+// Save creates the membership of a user or records another sign-in
+func (r *SSOMembershipRepository) Save(ctx context.Context, m *sso.Membership) error {
+	query := `
+		INSERT INTO sso_memberships (organization, user_id, role, created_at, last_login_at)
+		VALUES (?, ?, ?, ?, ?)
+	` + upsert(r.db, "organization, user_id", "role", "last_login_at")
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), m.Organization, m.UserID, m.Role, m.CreatedAt, m.LastLoginAt)
+	if err != nil {
+		return fmt.Errorf("failed to save sso membership: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves the memberships of a user
+func (r *SSOMembershipRepository) ListByUser(ctx context.Context, userID int) ([]*sso.Membership, error) {
+	query := `
+		SELECT organization, user_id, role, created_at, last_login_at
+		FROM sso_memberships
+		WHERE user_id = ?
+		ORDER BY organization ASC
+	`
+
+	memberships := []*sso.Membership{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &memberships, r.db.Rebind(query), userID); err != nil {
+		return nil, fmt.Errorf("failed to list sso memberships: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// Verify that the repositories implement the sso interfaces
+var (
+	_ sso.ConnectionRepository = (*SSOConnectionRepository)(nil)
+	_ sso.MembershipRepository = (*SSOMembershipRepository)(nil)
+)
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+)
+
+// TagRepository implements the tag.Repository interface using SQLX
+type TagRepository struct {
+	db *sqlx.DB
+}
+
+// NewTagRepository creates a new TagRepository instance
+func NewTagRepository(db *sqlx.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// ListUsed retrieves the tags of published public posts with their post
+// counts, most used first. Tags only found on drafts, scheduled, unlisted or
+// private posts are left out so hidden work is not revealed.
+func (r *TagRepository) ListUsed(ctx context.Context) ([]*tag.Tag, error) {
+	query := `
+		SELECT t.id, t.name, COUNT(*) AS post_count
+		FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id
+		WHERE p.status = ? AND p.visibility = ?
+		GROUP BY t.id, t.name
+		ORDER BY post_count DESC, t.name
+	`
+
+	var tags []*tag.Tag
+	if err := conn(ctx, r.db).SelectContext(ctx, &tags, r.db.Rebind(query), post.StatusPublished, post.VisibilityPublic); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// ListAll retrieves every tag with the number of posts of any status
+// carrying it, most used first
+func (r *TagRepository) ListAll(ctx context.Context) ([]*tag.Tag, error) {
+	query := `
+		SELECT t.id, t.name, COUNT(pt.post_id) AS post_count
+		FROM tags t
+		LEFT JOIN post_tags pt ON pt.tag_id = t.id
+		GROUP BY t.id, t.name
+		ORDER BY post_count DESC, t.name
+	`
+
+	var tags []*tag.Tag
+	if err := conn(ctx, r.db).SelectContext(ctx, &tags, r.db.Rebind(query)); err != nil {
+		return nil, fmt.Errorf("failed to list all tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetByName retrieves a tag with the number of posts of any status
+// carrying it
+func (r *TagRepository) GetByName(ctx context.Context, name string) (*tag.Tag, error) {
+	query := `
+		SELECT t.id, t.name, (SELECT COUNT(*) FROM post_tags pt WHERE pt.tag_id = t.id) AS post_count
+		FROM tags t
+		WHERE t.name = ?
+	`
+
+	var t tag.Tag
+	if err := conn(ctx, r.db).GetContext(ctx, &t, r.db.Rebind(query), name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, tag.ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &t, nil
+}
+
+// CountPosts counts the distinct posts carrying any of the tags
+func (r *TagRepository) CountPosts(ctx context.Context, ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT COUNT(DISTINCT post_id) FROM post_tags WHERE tag_id IN (?)`, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build tag post count query: %w", err)
+	}
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, r.db.Rebind(query), args...); err != nil {
+		return 0, fmt.Errorf("failed to count tag posts: %w", err)
+	}
+
+	return count, nil
+}
+
+// Rename changes the name of a tag, carrying its feed preference over to
+// the new name
+func (r *TagRepository) Rename(ctx context.Context, id int, name string) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldName string
+	if err := tx.GetContext(ctx, &oldName, tx.Rebind(`SELECT name FROM tags WHERE id = ?`+forUpdate(tx)), id); err != nil {
+		if err == sql.ErrNoRows {
+			return tag.ErrTagNotFound
+		}
+		return fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE tags SET name = ? WHERE id = ?`), name, id); err != nil {
+		if isDuplicateKeyError(err) {
+			return tag.ErrTagExists
+		}
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	// Posts list their tags, so they changed for sync clients
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = ?)`), id); err != nil {
+		return fmt.Errorf("failed to touch tag posts: %w", err)
+	}
+
+	// A preference already saved for the new name is kept
+	query := `UPDATE IGNORE feed_preferences SET scope_key = ? WHERE scope = ? AND scope_key = ?`
+	if isSQLite(tx) {
+		query = `UPDATE OR IGNORE feed_preferences SET scope_key = ? WHERE scope = ? AND scope_key = ?`
+	} else if isPostgres(tx) {
+		query = `
+			UPDATE feed_preferences SET scope_key = $1 WHERE scope = $2 AND scope_key = $3
+				AND NOT EXISTS (SELECT 1 FROM feed_preferences f WHERE f.scope = $2 AND f.scope_key = $1)
+		`
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), name, preference.ScopeTag, oldName); err != nil {
+		return fmt.Errorf("failed to rename tag feed preference: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tag rename: %w", err)
+	}
+
+	return nil
+}
+
+// Merge moves the posts of the source tags to the target tag and deletes
+// the source tags with their feed preferences in a single transaction
+func (r *TagRepository) Merge(ctx context.Context, sourceIDs []int, targetID int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Posts list their tags, so they changed for sync clients
+	query, args, err := sqlx.In(`UPDATE posts SET updated_at = CURRENT_TIMESTAMP WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id IN (?))`, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build tag posts query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to touch tag posts: %w", err)
+	}
+
+	// Posts carrying both a source and the target tag keep a single link
+	query, args, err = sqlx.In(insertIgnore(tx, `
+		INSERT INTO post_tags (post_id, tag_id)
+		SELECT post_id, ? FROM post_tags WHERE tag_id IN (?)
+	`), targetID, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build tag merge query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to move tag posts: %w", err)
+	}
+
+	query, args, err = sqlx.In(`
+		DELETE FROM feed_preferences
+		WHERE scope = ? AND scope_key IN (SELECT name FROM tags WHERE id IN (?))
+	`, preference.ScopeTag, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build tag feed preference query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to delete tag feed preferences: %w", err)
+	}
+
+	// Deleting the tags drops their remaining post links
+	query, args, err = sqlx.In(`DELETE FROM tags WHERE id IN (?)`, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build tag delete query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to delete merged tags: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tag merge: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUnused deletes the tags no post carries. Tags attached to a post
+// in the meantime are kept.
+func (r *TagRepository) DeleteUnused(ctx context.Context) (int, error) {
+	query := `
+		DELETE FROM tags
+		WHERE NOT EXISTS (SELECT 1 FROM post_tags pt WHERE pt.tag_id = tags.id)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unused tags: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+// Verify that TagRepository implements the tag.Repository interface
+var _ tag.Repository = (*TagRepository)(nil)
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/tip"
+)
+
+// TipRepository implements the tip.Repository interface using SQLX
+type TipRepository struct {
+	db *sqlx.DB
+}
+
+// NewTipRepository creates a new TipRepository instance
+func NewTipRepository(db *sqlx.DB) *TipRepository {
+	return &TipRepository{db: db}
+}
+
+// Create inserts a new tip
+func (r *TipRepository) Create(ctx context.Context, t *tip.Tip) error {
+	query := `
+		INSERT INTO tips (post_id, author_id, tipper_id, amount, currency, status, provider, payment_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, t.PostID, t.AuthorID, t.TipperID, t.Amount, t.Currency, t.Status,
+		t.Provider, t.PaymentID, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tip: %w", err)
+	}
+	t.ID = id
+
+	return nil
+}
+
+// GetByID retrieves a tip by its ID
+func (r *TipRepository) GetByID(ctx context.Context, id int) (*tip.Tip, error) {
+	query := `
+		SELECT id, post_id, author_id, tipper_id, amount, currency, status, provider, payment_id, created_at, updated_at
+		FROM tips
+		WHERE id = ?
+	`
+
+	var t tip.Tip
+	if err := conn(ctx, r.db).GetContext(ctx, &t, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, tip.ErrTipNotFound
+		}
+		return nil, fmt.Errorf("failed to get tip: %w", err)
+	}
+	return &t, nil
+}
+
+// SetPaymentID records the provider payment of a tip
+func (r *TipRepository) SetPaymentID(ctx context.Context, id int, paymentID string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`UPDATE tips SET payment_id = ?, updated_at = ? WHERE id = ?`), paymentID, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update tip: %w", err)
+	}
+	return nil
+}
+
+// Settle moves a pending tip to a final status. The status change and the
+// post's tip count are updated in one transaction, so a tip is counted at
+// most once however often its payment is reported.
+func (r *TipRepository) Settle(ctx context.Context, id int, status tip.Status) (bool, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE tips SET status = ?, updated_at = ? WHERE id = ? AND status = ?`), status, time.Now(), id, tip.StatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to settle tip: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if status == tip.StatusSucceeded {
+		query := `UPDATE posts SET tip_count = tip_count + 1, updated_at = updated_at WHERE id = (SELECT post_id FROM tips WHERE id = ?)`
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), id); err != nil {
+			return false, fmt.Errorf("failed to count tip: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetPayoutAccount retrieves the payout account of a user
+func (r *TipRepository) GetPayoutAccount(ctx context.Context, userID int) (*tip.PayoutAccount, error) {
+	query := `SELECT user_id, provider, account_id, created_at, updated_at FROM payout_accounts WHERE user_id = ?`
+
+	var a tip.PayoutAccount
+	if err := conn(ctx, r.db).GetContext(ctx, &a, r.db.Rebind(query), userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, tip.ErrPayoutAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get payout account: %w", err)
+	}
+	return &a, nil
+}
+
+// SavePayoutAccount creates or replaces the payout account of a user
+func (r *TipRepository) SavePayoutAccount(ctx context.Context, a *tip.PayoutAccount) error {
+	query := `
+		INSERT INTO payout_accounts (user_id, provider, account_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	` + upsert(r.db, "user_id", "provider", "account_id", "updated_at")
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), a.UserID, a.Provider, a.AccountID, a.CreatedAt, a.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save payout account: %w", err)
+	}
+	return nil
+}
+
+// DeletePayoutAccount removes the payout account of a user
+func (r *TipRepository) DeletePayoutAccount(ctx context.Context, userID int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM payout_accounts WHERE user_id = ?`), userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete payout account: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return tip.ErrPayoutAccountNotFound
+	}
+	return nil
+}
+
+// Verify that TipRepository implements the tip.Repository interface
+var _ tip.Repository = (*TipRepository)(nil)
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/application/service"
+)
+
+// txKey is the context key of the transaction of a unit of work
+type txKey struct{}
+
+// TxManager implements the service.TxManager interface using SQLX. The
+// transaction travels in the context, so repositories given that context
+// run their statements in it.
+type TxManager struct {
+	db *sqlx.DB
+}
+
+// NewTxManager creates a new TxManager instance
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx runs fn in a transaction, committing it when fn succeeds and
+// rolling it back when fn fails or panics. Units of work started within fn
+// join the transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// querier runs statements on the database or on a transaction
+type querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+}
+
+// conn returns the transaction of the unit of work in ctx, or db outside
+// of one
+func conn(ctx context.Context, db *sqlx.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// scopedTx is the transaction of a repository method. Inside a unit of
+// work it is the transaction of the unit, which commits or rolls back as a
+// whole, so Commit and Rollback leave it to the unit.
+type scopedTx struct {
+	*sqlx.Tx
+	joined bool
+}
+
+// begin starts the transaction of a repository method, joining the one of
+// the unit of work in ctx if any
+func begin(ctx context.Context, db *sqlx.DB) (*scopedTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return &scopedTx{Tx: tx, joined: true}, nil
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx}, nil
+}
+
+// Commit commits a transaction the method started
+func (t *scopedTx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls back a transaction the method started
+func (t *scopedTx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+// Verify that TxManager implements the service.TxManager interface
+var _ service.TxManager = (*TxManager)(nil)
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/auth"
+)
+
+// SecretCipher encrypts values before they are stored
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// TwoFactorRepository implements the auth.TwoFactorRepository interface
+// using SQLX. TOTP secrets are encrypted with the cipher before they are
+// written.
+type TwoFactorRepository struct {
+	db     *sqlx.DB
+	cipher SecretCipher
+}
+
+// NewTwoFactorRepository creates a new TwoFactorRepository instance
+func NewTwoFactorRepository(db *sqlx.DB, cipher SecretCipher) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db, cipher: cipher}
+}
+
+// twoFactorRow is the stored form of an enrollment
+type twoFactorRow struct {
+	auth.TwoFactor
+	SecretEncrypted string `db:"secret_encrypted"`
+}
+
+// Save creates or replaces the enrollment of a user
+func (r *TwoFactorRepository) Save(ctx context.Context, t *auth.TwoFactor) error {
+	secret, err := r.cipher.Encrypt(t.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt two-factor secret: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_two_factor (user_id, secret_encrypted, confirmed_at, last_used_step, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + upsert(r.db, "user_id", "secret_encrypted", "confirmed_at", "last_used_step", "updated_at")
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), t.UserID, secret, t.ConfirmedAt, t.LastUsedStep, t.CreatedAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor enrollment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID retrieves the enrollment of a user
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, userID int) (*auth.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret_encrypted, confirmed_at, last_used_step, created_at, updated_at
+		FROM user_two_factor
+		WHERE user_id = ?
+	`
+
+	var row twoFactorRow
+	err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(query), userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, auth.ErrTwoFactorNotFound
+		}
+		return nil, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+
+	secret, err := r.cipher.Decrypt(row.SecretEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt two-factor secret: %w", err)
+	}
+
+	t := row.TwoFactor
+	t.Secret = secret
+	return &t, nil
+}
+
+// Delete removes the enrollment of a user
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	query := `DELETE FROM user_two_factor WHERE user_id = ?`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor enrollment: %w", err)
+	}
+
+	return nil
+}
+
+// Verify that TwoFactorRepository implements the auth.TwoFactorRepository interface
+var _ auth.TwoFactorRepository = (*TwoFactorRepository)(nil)
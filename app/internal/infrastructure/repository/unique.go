@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for a unique constraint violation
+const mysqlDuplicateEntry = 1062
+
+// postgresUniqueViolation is the PostgreSQL error code for a unique
+// constraint violation
+const postgresUniqueViolation = "23505"
+
+// maxSlugRetries bounds how many slugs a post insert tries when another
+// request takes the chosen slug first
+const maxSlugRetries = 5
+
+// isDuplicateKeyError checks if the error is a duplicate key constraint violation
+func isDuplicateKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation
+	}
+
+	// Other drivers only describe the violation in the message
+	message := err.Error()
+	return strings.Contains(message, "Duplicate entry") ||
+		strings.Contains(message, "duplicate key") ||
+		strings.Contains(message, "UNIQUE constraint failed")
+}
+
+// isDuplicateKeyOn checks if the error is a duplicate key violation of the
+// unique key a name refers to, for tables with more than one. SQLite names
+// the columns of the key rather than the key, so name should appear in
+// both, such as the column of a key named after it.
+func isDuplicateKeyOn(err error, name string) bool {
+	return isDuplicateKeyError(err) && strings.Contains(err.Error(), name)
+}
+
+// retryOnDuplicate runs insert until it no longer violates a unique
+// constraint, calling next between attempts to move on to a new candidate
+// value. Checking for a free value before inserting still races concurrent
+// requests, so the insert itself is the final check. After the given number
+// of attempts the last duplicate key error is returned.
+func retryOnDuplicate(attempts int, insert func() error, next func()) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			next()
+		}
+		if err = insert(); !isDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
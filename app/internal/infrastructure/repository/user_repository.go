@@ -4,11 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/post"
 	"blog-platform/internal/domain/user"
 )
 
+// userColumns lists the columns selected when loading a user
+const userColumns = `id, name, email, COALESCE(email_normalized, '') AS email_normalized, email_conflict,
+		password_hash, role, deletion_scheduled_at, deleted_at, password_reset_required_at, deactivated_at, created_at, updated_at`
+
 // UserRepository implements the user.Repository interface using SQLX
 type UserRepository struct {
 	db *sqlx.DB
@@ -22,11 +29,11 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 // Create inserts a new user into the database
 func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 	query := `
-		INSERT INTO users (name, email, password_hash, created_at, updated_at)
-		VALUES (:name, :email, :password_hash, :created_at, :updated_at)
+		INSERT INTO users (name, email, email_normalized, password_hash, role, created_at, updated_at)
+		VALUES (:name, :email, NULLIF(:email_normalized, ''), :password_hash, :role, :created_at, :updated_at)
 	`
 	
-	result, err := r.db.NamedExecContext(ctx, query, u)
+	id, err := namedInsertID(ctx, conn(ctx, r.db), query, u)
 	if err != nil {
 		// Check for duplicate email constraint
 		if isDuplicateKeyError(err) {
@@ -35,25 +42,20 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
-	u.ID = int(id)
+	u.ID = id
 	return nil
 }
 
 // GetByID retrieves a user by their ID
 func (r *UserRepository) GetByID(ctx context.Context, id int) (*user.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = ?
 	`
 	
 	var u user.User
-	err := r.db.GetContext(ctx, &u, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &u, r.db.Rebind(query), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, user.ErrUserNotFound
@@ -64,16 +66,16 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*user.User, error
 	return &u, nil
 }
 
-// GetByEmail retrieves a user by their email address
+// GetByEmail retrieves a user by their normalized email address
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE email = ?
+		WHERE email_normalized = ?
 	`
 	
 	var u user.User
-	err := r.db.GetContext(ctx, &u, query, email)
+	err := conn(ctx, r.db).GetContext(ctx, &u, r.db.Rebind(query), email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, user.ErrUserNotFound
@@ -84,15 +86,40 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.Us
 	return &u, nil
 }
 
+// GetConflictedByEmail retrieves a user flagged for sharing its normalized
+// email with an older account, by the exact address it registered with
+func (r *UserRepository) GetConflictedByEmail(ctx context.Context, email string) (*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE email = ? AND email_conflict = TRUE
+	`
+
+	var u user.User
+	err := conn(ctx, r.db).GetContext(ctx, &u, r.db.Rebind(query), email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get conflicted user by email: %w", err)
+	}
+
+	return &u, nil
+}
+
 // Update modifies an existing user in the database
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	query := `
 		UPDATE users
-		SET name = :name, email = :email, password_hash = :password_hash, updated_at = :updated_at
+		SET name = :name, email = :email, email_normalized = NULLIF(:email_normalized, ''),
+			email_conflict = :email_conflict, password_hash = :password_hash, role = :role,
+			deletion_scheduled_at = :deletion_scheduled_at,
+			password_reset_required_at = :password_reset_required_at,
+			deactivated_at = :deactivated_at, updated_at = :updated_at
 		WHERE id = :id
 	`
 	
-	result, err := r.db.NamedExecContext(ctx, query, u)
+	result, err := conn(ctx, r.db).NamedExecContext(ctx, query, u)
 	if err != nil {
 		// Check for duplicate email constraint
 		if isDuplicateKeyError(err) {
@@ -113,11 +140,27 @@ func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	return nil
 }
 
-// Delete removes a user from the database
+// Delete removes a user from the database with their posts, leaving
+// tombstones for the posts and their comments
 func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	comments := `SELECT c.id FROM comments c JOIN posts p ON p.id = c.post_id WHERE p.author_id = ?`
+	if err := addTombstones(ctx, tx, change.KindComment, comments, id); err != nil {
+		return err
+	}
+	posts := `SELECT id FROM posts WHERE author_id = ? AND status = ?`
+	if err := addTombstones(ctx, tx, change.KindPost, posts, id, post.StatusPublished); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM users WHERE id = ?`
 	
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -131,20 +174,24 @@ func (r *UserRepository) Delete(ctx context.Context, id int) error {
 		return user.ErrUserNotFound
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user deletion: %w", err)
+	}
+
 	return nil
 }
 
 // List retrieves a paginated list of users
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.User, error) {
 	query := `
-		SELECT id, name, email, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
 	
 	var users []user.User
-	err := r.db.SelectContext(ctx, &users, query, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, r.db.Rebind(query), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -158,33 +205,81 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*user.U
 	return result, nil
 }
 
-// isDuplicateKeyError checks if the error is a duplicate key constraint violation
-func isDuplicateKeyError(err error) bool {
-	// MySQL error code 1062 is for duplicate entry
-	// This is a simplified check - in production, you might want to use a more robust method
-	return err != nil && (
-		// MySQL duplicate entry error
-		containsString(err.Error(), "Duplicate entry") ||
-		containsString(err.Error(), "duplicate key") ||
-		containsString(err.Error(), "UNIQUE constraint failed"))
-}
+// ListDueForPurge retrieves accounts whose deletion grace period ended before the given time
+func (r *UserRepository) ListDueForPurge(ctx context.Context, before time.Time, limit int) ([]*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ? AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at
+		LIMIT ?
+	`
 
-// containsString checks if a string contains a substring (case-insensitive)
-func containsString(s, substr string) bool {
-	return len(s) >= len(substr) && 
-		   (s == substr || 
-		    (len(s) > len(substr) && 
-		     (s[:len(substr)] == substr || 
-		      s[len(s)-len(substr):] == substr || 
-		      containsSubstring(s, substr))))
+	var users []user.User
+	err := conn(ctx, r.db).SelectContext(ctx, &users, r.db.Rebind(query), before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users due for purge: %w", err)
+	}
+
+	result := make([]*user.User, len(users))
+	for i := range users {
+		result[i] = &users[i]
+	}
+
+	return result, nil
 }
 
-// containsSubstring is a helper function for substring checking
-func containsSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+// Purge anonymizes an account pending deletion and its comments, and removes
+// its sessions and pending requests. The row is kept so authored content
+// stays consistent.
+func (r *UserRepository) Purge(ctx context.Context, id int) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The deletion_scheduled_at guard skips accounts restored by a login in the meantime
+	query := `
+		UPDATE users
+		SET name = 'Deleted user', bio = '', avatar_url = '', email = CONCAT('deleted-', id, '@deleted.invalid'),
+			email_normalized = NULL, email_conflict = FALSE, password_hash = '',
+			deletion_scheduled_at = NULL, password_reset_required_at = NULL, deleted_at = ?, updated_at = ?
+		WHERE id = ? AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return user.ErrDeletionNotScheduled
+	}
+
+	for _, table := range []string{"refresh_tokens", "email_change_requests", "password_resets", "user_identities"} {
+		if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM `+table+` WHERE user_id = ?`), id); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
 		}
 	}
-	return false
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM follows WHERE follower_id = ? OR followee_id = ?`), id, id); err != nil {
+		return fmt.Errorf("failed to delete follows: %w", err)
+	}
+
+	// Comments keep their place in threads but no longer carry the name
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`UPDATE comments SET author_name = 'Deleted user' WHERE user_id = ?`), id); err != nil {
+		return fmt.Errorf("failed to anonymize comments: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purge: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"blog-platform/internal/domain/webhook"
+)
+
+// webhookColumns are the columns selected for a webhook; webhooks of
+// deleted admins have no creator
+const webhookColumns = `id, url, events, secret_encrypted, active, COALESCE(created_by, 0) AS created_by, created_at, updated_at`
+
+// webhookDeliveryColumns are the columns selected for a delivery
+const webhookDeliveryColumns = `id, webhook_id, event, payload, status, attempts, response_status, error, created_at, delivered_at`
+
+// WebhookRepository implements the webhook.Repository and
+// webhook.DeliveryRepository interfaces using SQLX. Webhook secrets are
+// encrypted with the cipher before they are written.
+type WebhookRepository struct {
+	db     *sqlx.DB
+	cipher SecretCipher
+}
+
+// NewWebhookRepository creates a new WebhookRepository instance
+func NewWebhookRepository(db *sqlx.DB, cipher SecretCipher) *WebhookRepository {
+	return &WebhookRepository{db: db, cipher: cipher}
+}
+
+// webhookRow is the stored form of a webhook
+type webhookRow struct {
+	ID              int       `db:"id"`
+	URL             string    `db:"url"`
+	Events          string    `db:"events"`
+	SecretEncrypted string    `db:"secret_encrypted"`
+	Active          bool      `db:"active"`
+	CreatedBy       int       `db:"created_by"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, w *webhook.Webhook) error {
+	secret, err := r.cipher.Encrypt(w.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (url, events, secret_encrypted, active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, w.URL, strings.Join(w.Events, ","), secret, w.Active, w.CreatedBy, w.CreatedAt, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	w.ID = id
+	return nil
+}
+
+// GetByID retrieves a webhook by its ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*webhook.Webhook, error) {
+	var row webhookRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(`SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, webhook.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return r.toWebhook(row)
+}
+
+// List retrieves every webhook, newest first
+func (r *WebhookRepository) List(ctx context.Context) ([]*webhook.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at DESC, id DESC`)
+}
+
+// ListActive retrieves the webhooks that are sent events
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]*webhook.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE active = TRUE ORDER BY id`)
+}
+
+// list retrieves the webhooks selected by a query
+func (r *WebhookRepository) list(ctx context.Context, query string) ([]*webhook.Webhook, error) {
+	var rows []webhookRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query)); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	webhooks := make([]*webhook.Webhook, 0, len(rows))
+	for _, row := range rows {
+		w, err := r.toWebhook(row)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+// Update stores the URL, events and state of a webhook
+func (r *WebhookRepository) Update(ctx context.Context, w *webhook.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = ?, events = ?, active = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), w.URL, strings.Join(w.Events, ","), w.Active, w.UpdatedAt, w.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return webhook.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook; its deliveries go with it
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(`DELETE FROM webhooks WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return webhook.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// toWebhook converts a stored webhook, decrypting its secret
+func (r *WebhookRepository) toWebhook(row webhookRow) (*webhook.Webhook, error) {
+	secret, err := r.cipher.Decrypt(row.SecretEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	return &webhook.Webhook{
+		ID:        row.ID,
+		URL:       row.URL,
+		Events:    strings.Split(row.Events, ","),
+		Secret:    secret,
+		Active:    row.Active,
+		CreatedBy: row.CreatedBy,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// webhookDeliveryRow is the stored form of a delivery
+type webhookDeliveryRow struct {
+	ID             int        `db:"id"`
+	WebhookID      int        `db:"webhook_id"`
+	Event          string     `db:"event"`
+	Payload        string     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	ResponseStatus int        `db:"response_status"`
+	Error          string     `db:"error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+// toDelivery converts a stored delivery
+func (row webhookDeliveryRow) toDelivery() *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             row.ID,
+		WebhookID:      row.WebhookID,
+		Event:          row.Event,
+		Payload:        row.Payload,
+		Status:         webhook.DeliveryStatus(row.Status),
+		Attempts:       row.Attempts,
+		ResponseStatus: row.ResponseStatus,
+		Error:          row.Error,
+		CreatedAt:      row.CreatedAt,
+		DeliveredAt:    row.DeliveredAt,
+	}
+}
+
+// CreateDelivery inserts a new delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, response_status, error, created_at, delivered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := insertID(ctx, conn(ctx, r.db), query, d.WebhookID, d.Event, d.Payload, d.Status, d.Attempts, d.ResponseStatus, d.Error, d.CreatedAt, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	d.ID = id
+	return nil
+}
+
+// GetDelivery retrieves a delivery by its ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int) (*webhook.Delivery, error) {
+	var row webhookDeliveryRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, r.db.Rebind(`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = ?`), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, webhook.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return row.toDelivery(), nil
+}
+
+// UpdateDelivery stores the outcome of an attempt
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = ?, error = ?, delivered_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, r.db.Rebind(query), d.Status, d.Attempts, d.ResponseStatus, d.Error, d.DeliveredAt, d.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return webhook.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+// ListDeliveries retrieves a page of the deliveries to a webhook, newest
+// first, and their total count
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID, limit, offset int) ([]*webhook.Delivery, int, error) {
+	var total int
+	if err := conn(ctx, r.db).GetContext(ctx, &total, r.db.Rebind(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = ?`), webhookID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	var rows []webhookDeliveryRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, r.db.Rebind(query), webhookID, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*webhook.Delivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = row.toDelivery()
+	}
+	return deliveries, total, nil
+}
+
+var _ webhook.Repository = (*WebhookRepository)(nil)
+var _ webhook.DeliveryRepository = (*WebhookRepository)(nil)
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"blog-platform/internal/application/service"
+)
+
+// Job is a unit of periodic background work
+type Job func(ctx context.Context) error
+
+// entry is a registered job with its schedule
+type entry struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler runs registered jobs at fixed intervals until stopped
+type Scheduler struct {
+	logger  service.Logger
+	entries []entry
+	// stop ends the schedule; cancel also interrupts running jobs
+	stop     chan struct{}
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates a new scheduler
+func New(logger service.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Every registers a job to run at the given interval. Jobs must be
+// registered before Start is called.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.entries = append(s.entries, entry{name: name, interval: interval, job: job})
+}
+
+// Start launches one goroutine per registered job
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.stop = make(chan struct{})
+
+	for _, e := range s.entries {
+		if e.interval <= 0 {
+			s.logger.Warn(ctx, "scheduled job disabled", "job", e.name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.run(ctx, e)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Shutdown stops scheduling new runs and waits for running jobs to finish
+// their work. Jobs still running when ctx is done are cancelled.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// run executes a job on every tick until the context is cancelled
+func (s *Scheduler) run(ctx context.Context, e entry) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A tick can race the stop signal; never start a run after it
+			if s.stopped() {
+				return
+			}
+			started := time.Now()
+			if err := e.job(ctx); err != nil {
+				s.logger.Error(ctx, "scheduled job failed", "job", e.name, "error", err.Error())
+				continue
+			}
+			s.logger.Debug(ctx, "scheduled job completed", "job", e.name, "duration", time.Since(started).String())
+		}
+	}
+}
+
+// stopped reports whether the schedule has ended
+func (s *Scheduler) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+// Package searchping tells search engines about new and updated posts through
+// IndexNow and sitemap pings
+package searchping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// httpTimeout bounds each request to a search engine
+const httpTimeout = 10 * time.Second
+
+// Settings configures which search engines are notified
+type Settings struct {
+	// BaseURL is the public URL posts are served under at /p/{slug}
+	BaseURL string
+	// IndexNowKey verifies ownership of the host; it is served at /{key}.txt.
+	// IndexNow is disabled without a key.
+	IndexNowKey string
+	// IndexNowEndpoint receives IndexNow submissions
+	IndexNowEndpoint string
+	// SitemapURL is the public sitemap; sitemap pings are disabled without it
+	SitemapURL string
+	// SitemapPingURLs are ping endpoints the escaped sitemap URL is appended to
+	SitemapPingURLs []string
+}
+
+// Notifier delivers search pings queued as service.JobSearchPing jobs
+type Notifier struct {
+	settings   Settings
+	httpClient *http.Client
+	logger     service.Logger
+}
+
+// NewNotifier creates a new notifier
+func NewNotifier(settings Settings, httpClient *http.Client, logger service.Logger) *Notifier {
+	return &Notifier{
+		settings:   settings,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// NewNotifierFromConfig creates a notifier for the configured search engines
+func NewNotifierFromConfig(cfg *config.Config, logger service.Logger) *Notifier {
+	return NewNotifier(Settings{
+		BaseURL:          cfg.Server.BaseURL,
+		IndexNowKey:      cfg.SearchPing.IndexNowKey,
+		IndexNowEndpoint: cfg.SearchPing.IndexNowEndpoint,
+		SitemapURL:       cfg.SearchPing.SitemapURL,
+		SitemapPingURLs:  cfg.SearchPing.SitemapPingURLs,
+	}, &http.Client{Timeout: httpTimeout}, logger)
+}
+
+// Handle delivers a search ping to every configured engine. Each delivery
+// is logged; the job fails, and is retried, if any engine could not be
+// reached. Engines treat repeated pings for a URL as a single change.
+func (n *Notifier) Handle(ctx context.Context, payload json.RawMessage) error {
+	var ping service.SearchPingPayload
+	if err := json.Unmarshal(payload, &ping); err != nil {
+		return fmt.Errorf("invalid search ping payload: %w", err)
+	}
+	postURL := n.settings.BaseURL + "/p/" + url.PathEscape(ping.Slug)
+
+	var errs []error
+	if n.settings.IndexNowKey != "" && n.settings.IndexNowEndpoint != "" {
+		query := url.Values{"url": {postURL}, "key": {n.settings.IndexNowKey}}
+		errs = append(errs, n.deliver(ctx, "indexnow", n.settings.IndexNowEndpoint+"?"+query.Encode(), ping.PostID))
+	}
+	if n.settings.SitemapURL != "" {
+		for _, endpoint := range n.settings.SitemapPingURLs {
+			errs = append(errs, n.deliver(ctx, "sitemap", endpoint+url.QueryEscape(n.settings.SitemapURL), ping.PostID))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver sends one ping and logs the outcome
+func (n *Notifier) deliver(ctx context.Context, kind, target string, postID int) error {
+	started := time.Now()
+	status, err := n.get(ctx, target)
+	if err != nil {
+		n.logger.Warn(ctx, "search ping failed", "kind", kind, "target", target, "postID", postID, "status", status, "error", err.Error())
+		return fmt.Errorf("%s ping to %s: %w", kind, target, err)
+	}
+
+	n.logger.Info(ctx, "search ping delivered", "kind", kind, "target", target, "postID", postID, "status", status, "duration", time.Since(started).String())
+	return nil
+}
+
+// get requests a ping URL and returns the response status
+func (n *Notifier) get(ctx context.Context, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	// IndexNow answers 202 when the key has not been verified yet
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,112 @@
+// Package shutdown tears the application down in order when it stops, so
+// components finish their in-flight work before what they depend on closes
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"blog-platform/internal/application/service"
+)
+
+// Hook stops a component. It should return once the component's in-flight
+// work is done, or give up when ctx is done.
+type Hook func(ctx context.Context) error
+
+// hook is a registered hook with the name it is logged under
+type hook struct {
+	name string
+	fn   Hook
+}
+
+// Registry holds the shutdown hooks of the running components
+type Registry struct {
+	hookTimeout time.Duration
+	logger      service.Logger
+
+	mu    sync.Mutex
+	hooks []hook
+	done  bool
+}
+
+// New creates a registry giving each hook at most hookTimeout; zero bounds
+// hooks by the Shutdown deadline only
+func New(hookTimeout time.Duration, logger service.Logger) *Registry {
+	return &Registry{
+		hookTimeout: hookTimeout,
+		logger:      logger,
+	}
+}
+
+// Register adds a hook. Hooks run in reverse order of registration, like
+// deferred calls, so register a component after the components it uses.
+func (r *Registry) Register(name string, fn Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown runs the hooks one at a time, newest first. Each hook gets the
+// hook timeout within the deadline of ctx; a hook that has not returned by
+// then is abandoned and the next one runs. Hooks run only once: later calls
+// return nil. The errors of all hooks are joined.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return nil
+	}
+	r.done = true
+	hooks := r.hooks
+	r.mu.Unlock()
+
+	started := time.Now()
+	r.logger.Info(ctx, "shutting down", "hooks", len(hooks))
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := r.run(ctx, hooks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+		}
+	}
+
+	r.logger.Info(ctx, "shutdown complete", "duration", time.Since(started).String(), "failed", len(errs))
+	return errors.Join(errs...)
+}
+
+// run runs a hook within its timeout
+func (r *Registry) run(ctx context.Context, h hook) error {
+	hookCtx := ctx
+	if r.hookTimeout > 0 {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(ctx, r.hookTimeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	r.logger.Info(ctx, "running shutdown hook", "hook", h.name)
+
+	// The hook runs on its own goroutine so one that ignores its context
+	// cannot hold up the hooks after it
+	result := make(chan error, 1)
+	go func() {
+		result <- h.fn(hookCtx)
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			r.logger.Error(ctx, "shutdown hook failed", "hook", h.name, "duration", time.Since(started).String(), "error", err.Error())
+			return err
+		}
+		r.logger.Info(ctx, "shutdown hook finished", "hook", h.name, "duration", time.Since(started).String())
+		return nil
+	case <-hookCtx.Done():
+		r.logger.Warn(ctx, "shutdown hook timed out", "hook", h.name, "duration", time.Since(started).String())
+		return hookCtx.Err()
+	}
+}
@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-platform/internal/application/service"
+)
+
+// httpTimeout bounds each request to the storage service
+const httpTimeout = 30 * time.Second
+
+// ErrS3 is returned when the storage service cannot be reached or refuses
+// a request
+var ErrS3 = errors.New("object storage request failed")
+
+// S3Settings configures an S3-compatible storage service
+type S3Settings struct {
+	// Endpoint is the base URL of the service, e.g.
+	// https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket in the path rather than the host
+	// name, as MinIO and most self-hosted services expect
+	PathStyle bool
+	// PublicURL is the base URL objects are served from, e.g. a CDN in
+	// front of the bucket; it defaults to the bucket URL
+	PublicURL string
+}
+
+// S3Storage implements the service.ObjectStorage interface on an
+// S3-compatible service, signing requests with AWS Signature Version 4.
+// Objects are served straight from the bucket, which must allow public
+// reads, or from PublicURL.
+type S3Storage struct {
+	settings   S3Settings
+	bucketURL  *url.URL
+	publicURL  string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Storage creates a storage in the bucket of an S3-compatible service
+func NewS3Storage(settings S3Settings, httpClient *http.Client) (*S3Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(settings.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid storage endpoint %q", settings.Endpoint)
+	}
+	if settings.Bucket == "" {
+		return nil, errors.New("storage bucket is required")
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: httpTimeout}
+	}
+
+	bucketURL := *endpoint
+	if settings.PathStyle {
+		bucketURL.Path += "/" + settings.Bucket
+	} else {
+		bucketURL.Host = settings.Bucket + "." + bucketURL.Host
+	}
+	publicURL := strings.TrimRight(settings.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = bucketURL.String()
+	}
+
+	return &S3Storage{
+		settings:   settings,
+		bucketURL:  &bucketURL,
+		publicURL:  publicURL,
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// Put uploads the object with a single PUT request
+func (s *S3Storage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	body, err := io.ReadAll(io.LimitReader(content, size))
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+	if int64(len(body)) != size {
+		return fmt.Errorf("failed to read object: %w", io.ErrUnexpectedEOF)
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, body)
+}
+
+// Delete removes the object; S3 answers deletes of missing objects with
+// success too
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil)
+}
+
+// URL returns the public URL of the object
+func (s *S3Storage) URL(key string) string {
+	return s.publicURL + "/" + escapeKey(key)
+}
+
+// newRequest builds a request for the object stored under the key
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, ErrInvalidKey
+	}
+	u := *s.bucketURL
+	u.Path += "/" + key
+	u.RawPath = s.bucketURL.EscapedPath() + "/" + escapeKey(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage request: %w", err)
+	}
+	return req, nil
+}
+
+// do signs and sends a request, turning error responses into ErrS3
+func (s *S3Storage) do(req *http.Request, body []byte) error {
+	SignV4(req, body, s.settings.Region, s.settings.AccessKey, s.settings.SecretKey, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrS3, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrS3, req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// SignV4 signs a request to S3 with AWS Signature Version 4, covering the
+// host, the payload hash and every header already set on the request
+func SignV4(req *http.Request, body []byte, region, accessKey, secretKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, as signed
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapeKey encodes each segment of an object key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, as
+// Signature Version 4 requires
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// Verify that S3Storage implements the service.ObjectStorage interface
+var _ service.ObjectStorage = (*S3Storage)(nil)
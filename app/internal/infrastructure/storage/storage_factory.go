@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"strings"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// NewObjectStorage creates an object storage based on configuration
+func NewObjectStorage(cfg *config.Config) (service.ObjectStorage, error) {
+	switch strings.ToLower(cfg.Storage.Driver) {
+	case "s3":
+		return NewS3Storage(S3Settings{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+			PublicURL: cfg.Storage.PublicURL,
+		}, nil)
+	default:
+		// Development default: keep uploads on the local disk, served by
+		// the application below LocalPath
+		publicURL := cfg.Storage.PublicURL
+		if publicURL == "" {
+			publicURL = cfg.Server.BaseURL + LocalPath
+		}
+		return NewLocalStorage(cfg.Storage.LocalDir, publicURL), nil
+	}
+}
+
+// LocalPath is the path below which the application serves the uploads of
+// the local driver
+const LocalPath = "/uploads"
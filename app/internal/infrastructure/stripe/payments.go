@@ -0,0 +1,209 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	stripeapi "github.com/stripe/stripe-go/v82"
+
+	"blog-platform/internal/domain/tip"
+)
+
+// DefaultAPIURL is the base URL of the production Stripe API
+const DefaultAPIURL = stripeapi.APIURL
+
+// httpTimeout bounds each request to the Stripe API
+const httpTimeout = 15 * time.Second
+
+// Checkout session event types; other events decode to nil
+const (
+	eventCheckoutCompleted          = "checkout.session.completed"
+	eventCheckoutAsyncPaymentPassed = "checkout.session.async_payment_succeeded"
+	eventCheckoutAsyncPaymentFailed = "checkout.session.async_payment_failed"
+	eventCheckoutExpired            = "checkout.session.expired"
+)
+
+// tipIDMetadataKey is the checkout session metadata key holding the tip ID
+const tipIDMetadataKey = "tip_id"
+
+// ErrAPI is returned when the Stripe API cannot be reached or fails
+var ErrAPI = errors.New("stripe api request failed")
+
+// PaymentProvider takes tips through Stripe Checkout. Payments are made on
+// the platform account and transferred to the Stripe Connect account of
+// the author.
+type PaymentProvider struct {
+	client   *stripeapi.Client
+	webhooks *WebhookVerifier
+}
+
+// NewPaymentProvider creates a provider using the secret API key of the
+// platform account. webhooks verifies the events of the endpoint the
+// checkout session events are sent to.
+func NewPaymentProvider(secretKey, apiURL string, webhooks *WebhookVerifier, httpClient *http.Client) *PaymentProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: httpTimeout}
+	}
+	backend := stripeapi.GetBackendWithConfig(stripeapi.APIBackend, &stripeapi.BackendConfig{
+		URL:        stripeapi.String(strings.TrimRight(apiURL, "/")),
+		HTTPClient: httpClient,
+		// Failures are reported to the caller, which logs them
+		LeveledLogger: &stripeapi.LeveledLogger{Level: stripeapi.LevelNull},
+	})
+	return &PaymentProvider{
+		client:   stripeapi.NewClient(secretKey, stripeapi.WithBackends(&stripeapi.Backends{API: backend})),
+		webhooks: webhooks,
+	}
+}
+
+// Name identifies Stripe in tips and payout accounts
+func (p *PaymentProvider) Name() string {
+	return "stripe"
+}
+
+// VerifyAccount checks that a Connect account is reachable from the
+// platform and has completed onboarding
+func (p *PaymentProvider) VerifyAccount(ctx context.Context, accountID string) error {
+	if !strings.HasPrefix(accountID, "acct_") {
+		return fmt.Errorf("%w: Stripe account IDs start with acct_", tip.ErrInvalidAccount)
+	}
+
+	a, err := p.client.V1Accounts.GetByID(ctx, accountID, nil)
+	if status := statusCode(err); status == http.StatusNotFound || status == http.StatusForbidden {
+		return fmt.Errorf("%w: the account is not connected to the platform", tip.ErrInvalidAccount)
+	}
+	if err != nil {
+		return apiFailure(err)
+	}
+	if !a.ChargesEnabled {
+		return fmt.Errorf("%w: the account cannot accept payments yet", tip.ErrInvalidAccount)
+	}
+	return nil
+}
+
+// checkoutSession is the part of a checkout session the platform reads
+type checkoutSession struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url"`
+	PaymentStatus string            `json:"payment_status"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// CreateCheckout creates a checkout session for a tip. Retries for the same
+// tip reuse the session through the idempotency key.
+func (p *PaymentProvider) CreateCheckout(ctx context.Context, req tip.CheckoutRequest) (*tip.Checkout, error) {
+	tipID := strconv.Itoa(req.TipID)
+	params := &stripeapi.CheckoutSessionCreateParams{
+		Mode:       stripeapi.String(string(stripeapi.CheckoutSessionModePayment)),
+		SuccessURL: stripeapi.String(req.ReturnURL),
+		CancelURL:  stripeapi.String(req.ReturnURL),
+		LineItems: []*stripeapi.CheckoutSessionCreateLineItemParams{{
+			Quantity: stripeapi.Int64(1),
+			PriceData: &stripeapi.CheckoutSessionCreateLineItemPriceDataParams{
+				Currency:   stripeapi.String(strings.ToLower(req.Currency)),
+				UnitAmount: stripeapi.Int64(req.Amount),
+				ProductData: &stripeapi.CheckoutSessionCreateLineItemPriceDataProductDataParams{
+					Name: stripeapi.String(fmt.Sprintf("Tip for %q", req.PostTitle)),
+				},
+			},
+		}},
+		PaymentIntentData: &stripeapi.CheckoutSessionCreatePaymentIntentDataParams{
+			TransferData: &stripeapi.CheckoutSessionCreatePaymentIntentDataTransferDataParams{
+				Destination: stripeapi.String(req.Destination),
+			},
+			Metadata: map[string]string{tipIDMetadataKey: tipID},
+		},
+		Metadata: map[string]string{tipIDMetadataKey: tipID},
+	}
+	params.SetIdempotencyKey("tip-" + tipID)
+
+	session, err := p.client.V1CheckoutSessions.Create(ctx, params)
+	if err != nil {
+		return nil, apiFailure(err)
+	}
+	if session.ID == "" || session.URL == "" {
+		return nil, fmt.Errorf("%w: checkout session without an ID or URL", ErrAPI)
+	}
+	return &tip.Checkout{PaymentID: session.ID, URL: session.URL}, nil
+}
+
+// checkoutEvent is the envelope of a checkout session event
+type checkoutEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object checkoutSession `json:"object"`
+	} `json:"data"`
+}
+
+// ParseEvent verifies the signature of a payload and decodes the tip it
+// settles. Completed checkouts whose payment is still processing, and
+// sessions not created for tips, decode to nil.
+func (p *PaymentProvider) ParseEvent(payload []byte, signature string) (*tip.PaymentEvent, error) {
+	if err := p.webhooks.verify(payload, signature); err != nil {
+		return nil, tip.ErrInvalidSignature
+	}
+
+	var e checkoutEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", tip.ErrInvalidEvent, err)
+	}
+
+	session := e.Data.Object
+	var status tip.Status
+	switch e.Type {
+	case eventCheckoutCompleted:
+		if session.PaymentStatus != "paid" {
+			return nil, nil
+		}
+		status = tip.StatusSucceeded
+	case eventCheckoutAsyncPaymentPassed:
+		status = tip.StatusSucceeded
+	case eventCheckoutAsyncPaymentFailed, eventCheckoutExpired:
+		status = tip.StatusFailed
+	default:
+		return nil, nil
+	}
+
+	value, ok := session.Metadata[tipIDMetadataKey]
+	if !ok {
+		return nil, nil
+	}
+	tipID, err := strconv.Atoi(value)
+	if err != nil || tipID <= 0 {
+		return nil, fmt.Errorf("%w: invalid tip ID in metadata", tip.ErrInvalidEvent)
+	}
+	if e.ID == "" || session.ID == "" {
+		return nil, fmt.Errorf("%w: missing checkout session fields", tip.ErrInvalidEvent)
+	}
+
+	return &tip.PaymentEvent{EventID: e.ID, TipID: tipID, PaymentID: session.ID, Status: status}, nil
+}
+
+// statusCode returns the HTTP status of a failed API request, or zero when
+// the API was not reached
+func statusCode(err error) int {
+	var apiErr *stripeapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	return 0
+}
+
+// apiFailure wraps an error of the Stripe library in ErrAPI
+func apiFailure(err error) error {
+	var apiErr *stripeapi.Error
+	if errors.As(err, &apiErr) {
+		return fmt.Errorf("%w: status %d: %s", ErrAPI, apiErr.HTTPStatusCode, apiErr.Msg)
+	}
+	return fmt.Errorf("%w: %v", ErrAPI, err)
+}
+
+// Verify that PaymentProvider implements the tip.PaymentProvider interface
+var _ tip.PaymentProvider = (*PaymentProvider)(nil)
@@ -0,0 +1,162 @@
+// Package stripe receives the subscription events of Stripe webhooks and
+// takes tips through Stripe Checkout
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v82/webhook"
+
+	"blog-platform/internal/domain/billing"
+)
+
+// DefaultTolerance is how old a signed payload may be, which bounds replays
+const DefaultTolerance = 5 * time.Minute
+
+// Subscription event types; other events decode to nil
+const (
+	eventSubscriptionCreated = "customer.subscription.created"
+	eventSubscriptionUpdated = "customer.subscription.updated"
+	eventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// userIDMetadataKey is the subscription metadata key holding the user ID,
+// set through subscription_data.metadata when creating Checkout sessions
+const userIDMetadataKey = "user_id"
+
+// WebhookVerifier checks the Stripe-Signature header of webhook payloads
+// and decodes subscription events, mapping Stripe prices to plans
+type WebhookVerifier struct {
+	secret     string
+	tolerance  time.Duration
+	pricePlans map[string]billing.Plan
+	now        func() time.Time
+}
+
+// NewWebhookVerifier creates a verifier for the signing secret of a webhook
+// endpoint. pricePlans maps Stripe price IDs to plans; prices missing from
+// it are matched by their lookup key.
+func NewWebhookVerifier(secret string, tolerance time.Duration, pricePlans map[string]string) *WebhookVerifier {
+	plans := make(map[string]billing.Plan, len(pricePlans))
+	for price, plan := range pricePlans {
+		plans[price] = billing.Plan(plan)
+	}
+	return &WebhookVerifier{secret: secret, tolerance: tolerance, pricePlans: plans, now: time.Now}
+}
+
+// event is the envelope of a webhook event
+type event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object subscription `json:"object"`
+	} `json:"data"`
+}
+
+// subscription is the part of a Stripe subscription the platform reads.
+// Recent API versions moved current_period_end to the subscription items.
+type subscription struct {
+	ID                string            `json:"id"`
+	Customer          string            `json:"customer"`
+	Status            string            `json:"status"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID        string `json:"id"`
+				LookupKey string `json:"lookup_key"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// ParseEvent verifies the signature of a payload and decodes its
+// subscription event
+func (v *WebhookVerifier) ParseEvent(payload []byte, signature string) (*billing.SubscriptionEvent, error) {
+	if err := v.verify(payload, signature); err != nil {
+		return nil, err
+	}
+
+	var e event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", billing.ErrInvalidEvent, err)
+	}
+	switch e.Type {
+	case eventSubscriptionCreated, eventSubscriptionUpdated, eventSubscriptionDeleted:
+	default:
+		return nil, nil
+	}
+
+	sub := e.Data.Object
+	if e.ID == "" || sub.ID == "" || sub.Customer == "" || len(sub.Items.Data) == 0 {
+		return nil, fmt.Errorf("%w: missing subscription fields", billing.ErrInvalidEvent)
+	}
+	item := sub.Items.Data[0]
+	plan, ok := v.pricePlans[item.Price.ID]
+	if !ok {
+		plan = billing.Plan(item.Price.LookupKey)
+	}
+	if !plan.IsValid() {
+		return nil, fmt.Errorf("%w: price %s is not mapped to a plan", billing.ErrInvalidEvent, item.Price.ID)
+	}
+
+	result := &billing.SubscriptionEvent{
+		EventID:              e.ID,
+		OccurredAt:           time.Unix(e.Created, 0),
+		StripeCustomerID:     sub.Customer,
+		StripeSubscriptionID: sub.ID,
+		Plan:                 plan,
+		Status:               billing.Status(sub.Status),
+		CancelAtPeriodEnd:    sub.CancelAtPeriodEnd,
+	}
+	if e.Type == eventSubscriptionDeleted {
+		result.Status = billing.StatusCanceled
+	}
+	if value, ok := sub.Metadata[userIDMetadataKey]; ok {
+		userID, err := strconv.Atoi(value)
+		if err != nil || userID <= 0 {
+			return nil, fmt.Errorf("%w: invalid user ID in metadata", billing.ErrInvalidEvent)
+		}
+		result.UserID = userID
+	}
+	periodEnd := sub.CurrentPeriodEnd
+	if periodEnd == 0 {
+		periodEnd = item.CurrentPeriodEnd
+	}
+	if periodEnd != 0 {
+		end := time.Unix(periodEnd, 0)
+		result.CurrentPeriodEnd = &end
+	}
+
+	return result, nil
+}
+
+// verify checks the Stripe-Signature header with the Stripe library: one
+// of its v1 signatures must match, with a timestamp within the tolerance.
+// The library bounds the age of timestamps only, so timestamps ahead of the
+// clock are bounded here.
+func (v *WebhookVerifier) verify(payload []byte, header string) error {
+	if err := webhook.ValidatePayloadWithTolerance(payload, header, v.secret, v.tolerance); err != nil {
+		return billing.ErrInvalidSignature
+	}
+	for _, part := range strings.Split(header, ",") {
+		if timestamp, ok := strings.CutPrefix(strings.TrimSpace(part), "t="); ok {
+			seconds, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil || time.Unix(seconds, 0).Sub(v.now()) > v.tolerance {
+				return billing.ErrInvalidSignature
+			}
+		}
+	}
+	return nil
+}
+
+// Verify that WebhookVerifier implements the billing.WebhookVerifier interface
+var _ billing.WebhookVerifier = (*WebhookVerifier)(nil)
@@ -0,0 +1,115 @@
+// Package views counts post views in memory and writes them in batches, so
+// reading a post does not cost a database write
+package views
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+)
+
+// Store saves the view counts of a flush
+type Store interface {
+	AddViews(ctx context.Context, counts []post.ViewCount) error
+}
+
+// key identifies the views of a post on a day
+type key struct {
+	postID int
+	day    time.Time
+}
+
+// Counter buffers post views until they are flushed
+type Counter struct {
+	store  Store
+	logger service.Logger
+
+	// flushMu keeps flushes from overlapping, so counts put back by a failed
+	// flush are not written twice
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[key]int64
+}
+
+// Stats describes the views waiting to be flushed
+type Stats struct {
+	Posts int   `json:"posts"`
+	Views int64 `json:"views"`
+}
+
+// New creates a counter writing to store
+func New(store Store, logger service.Logger) *Counter {
+	return &Counter{
+		store:   store,
+		logger:  logger,
+		pending: make(map[key]int64),
+	}
+}
+
+// Record counts a view of a post on the current day
+func (c *Counter) Record(postID int) {
+	k := key{postID: postID, day: post.ViewDay(time.Now())}
+
+	c.mu.Lock()
+	c.pending[k]++
+	c.mu.Unlock()
+}
+
+// Flush writes the buffered views. Views that fail to be written are put
+// back and written by the next flush.
+func (c *Counter) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[key]int64)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	counts := make([]post.ViewCount, 0, len(pending))
+	for k, views := range pending {
+		counts = append(counts, post.ViewCount{PostID: k.postID, Day: k.day, Views: views})
+	}
+	// A fixed order makes concurrent writers lock rows in the same order
+	slices.SortFunc(counts, func(a, b post.ViewCount) int {
+		return cmp.Or(a.Day.Compare(b.Day), cmp.Compare(a.PostID, b.PostID))
+	})
+
+	if err := c.store.AddViews(ctx, counts); err != nil {
+		c.mu.Lock()
+		for k, views := range pending {
+			c.pending[k] += views
+		}
+		c.mu.Unlock()
+		return fmt.Errorf("failed to write %d view counts: %w", len(counts), err)
+	}
+
+	c.logger.Debug(ctx, "post views flushed", "counts", len(counts))
+	return nil
+}
+
+// Stats returns the views waiting to be flushed
+func (c *Counter) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	posts := make(map[int]struct{}, len(c.pending))
+	var stats Stats
+	for k, views := range c.pending {
+		posts[k.postID] = struct{}{}
+		stats.Views += views
+	}
+	stats.Posts = len(posts)
+	return stats
+}
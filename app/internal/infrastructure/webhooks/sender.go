@@ -0,0 +1,83 @@
+// Package webhooks sends the content events recorded for the webhooks
+// registered by admins
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-platform/internal/domain/webhook"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// Headers sent with every delivery
+const (
+	// HeaderEvent names the event of the payload
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderDelivery is the ID of the delivery; retries send the same ID,
+	// so receivers can drop duplicates
+	HeaderDelivery = "X-Webhook-Delivery"
+	// HeaderSignature signs the payload with the webhook secret, as
+	// produced by webhook.Sign
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// userAgent identifies deliveries to receivers
+const userAgent = "blog-platform-webhooks/1.0"
+
+// Sender posts deliveries to webhooks over HTTP
+type Sender struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewSender creates a sender making requests with httpClient
+func NewSender(httpClient *http.Client) *Sender {
+	return &Sender{httpClient: httpClient, now: time.Now}
+}
+
+// NewSenderFromConfig creates a sender with the configured timeout.
+// Redirects are not followed, so a delivery is only accepted by the
+// registered URL.
+func NewSenderFromConfig(cfg *config.Config) *Sender {
+	return NewSender(&http.Client{
+		Timeout: time.Duration(cfg.Webhooks.Timeout) * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+}
+
+// Send posts the payload of a delivery, signed at the time of the attempt,
+// and returns the response status. Statuses other than 2xx are errors.
+func (s *Sender) Send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, error) {
+	payload := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(d.ID))
+	req.Header.Set(HeaderSignature, webhook.Sign(w.Secret, s.now(), payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+var _ webhook.Sender = (*Sender)(nil)
@@ -0,0 +1,58 @@
+package fixtures
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"blog-platform/internal/domain/user"
+)
+
+// UserHeader names the user a test request is made as, in place of a token
+const UserHeader = "X-User"
+
+// AsUser stands in for the auth middleware: requests name their user in the
+// X-User header, and requests without one go through anonymously
+func AsUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return AsUserWithRole("")(next)
+}
+
+// AsUserWithRole is AsUser for routes that also check the role of the user
+func AsUserWithRole(role user.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if id, err := strconv.Atoi(c.Request().Header.Get(UserHeader)); err == nil {
+				c.Set("user_id", id)
+				if role != "" {
+					c.Set("user_role", role)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireUser is AsUser for routes behind the required auth middleware,
+// rejecting requests that name no user
+func RequireUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.Atoi(c.Request().Header.Get(UserHeader))
+		if err != nil {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+		c.Set("user_id", id)
+		return next(c)
+	}
+}
+
+// ActingAs stands in for the auth middleware with every request made as the
+// same user
+func ActingAs(userID int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user_id", userID)
+			return next(c)
+		}
+	}
+}
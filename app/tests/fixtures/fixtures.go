@@ -0,0 +1,87 @@
+// Package fixtures builds domain entities with stable IDs, content and
+// timestamps for tests, so responses rendered from them are the same on
+// every run and can be compared against golden files.
+package fixtures
+
+import (
+	"fmt"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// Password is the password of every fixture user
+const Password = "password123"
+
+// passwordHash is a low-cost bcrypt hash of Password, so fixtures do not
+// spend time hashing
+const passwordHash = "$2a$04$JfJWpshugjhPgrox54o4M.OQaac38Wb5w5blzOxtFlF2aLtW8SXWy"
+
+// Time is when every fixture was created
+var Time = time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+// User builds an author with the given ID, e.g. "User 1" <user1@example.com>
+func User(id int) *user.User {
+	return &user.User{
+		ID:              id,
+		Name:            fmt.Sprintf("User %d", id),
+		Email:           fmt.Sprintf("user%d@example.com", id),
+		NormalizedEmail: fmt.Sprintf("user%d@example.com", id),
+		PasswordHash:    passwordHash,
+		Role:            user.RoleAuthor,
+		CreatedAt:       Time,
+		UpdatedAt:       Time,
+	}
+}
+
+// Admin builds an admin with the given ID
+func Admin(id int) *user.User {
+	u := User(id)
+	u.Role = user.RoleAdmin
+	return u
+}
+
+// Post builds a published post by the author, e.g. "Post 1" at /p/post-1.
+// Later IDs are published a minute later each, so lists sort stably.
+func Post(id, authorID int) *post.Post {
+	title := fmt.Sprintf("Post %d", id)
+	createdAt := Time.Add(time.Duration(id) * time.Minute)
+	p := &post.Post{
+		ID:          id,
+		Title:       title,
+		Slug:        post.Slugify(title),
+		Content:     fmt.Sprintf("This is the content of post %d.", id),
+		AuthorID:    authorID,
+		Access:      post.AccessPublic,
+		Visibility:  post.VisibilityPublic,
+		Language:    post.DefaultLanguage,
+		Status:      post.StatusPublished,
+		PublishedAt: &createdAt,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	p.RefreshReadingStats()
+	return p
+}
+
+// Draft builds an unpublished post by the author
+func Draft(id, authorID int) *post.Post {
+	p := Post(id, authorID)
+	p.Status = post.StatusDraft
+	p.PublishedAt = nil
+	return p
+}
+
+// Comment builds a comment on the post, e.g. by "Reader 1"
+func Comment(id, postID int) *comment.Comment {
+	return &comment.Comment{
+		ID:         id,
+		PostID:     postID,
+		AuthorName: fmt.Sprintf("Reader %d", id),
+		Content:    fmt.Sprintf("This is comment %d.", id),
+		Status:     comment.StatusApproved,
+		CreatedAt:  Time.Add(time.Duration(id) * time.Hour),
+	}
+}
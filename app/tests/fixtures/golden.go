@@ -0,0 +1,40 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites golden files with the current responses:
+//
+//	go test ./tests/integration/http -run Golden -update
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// AssertGolden compares a JSON response with testdata/<name>.golden.json in
+// the package under test. Both are indented the same way first, so the
+// files stay readable and a failure shows which fields changed.
+func AssertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, bytes.TrimSpace(got), "", "  "), "response is not JSON")
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, indented.Bytes(), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file; run the test with -update to create it")
+	assert.Equal(t, string(want), indented.String(), "response differs from %s; run the test with -update if the change is intended", path)
+}
@@ -0,0 +1,59 @@
+package fixtures
+
+import (
+	"context"
+	"time"
+
+	"blog-platform/internal/domain/idempotency"
+)
+
+// IdempotencyRepository implements the idempotency.Repository interface in
+// memory, with its records by scope and key
+type IdempotencyRepository struct {
+	Records map[string]*idempotency.Record
+	nextID  int
+}
+
+// NewIdempotencyRepository creates an empty IdempotencyRepository
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{Records: make(map[string]*idempotency.Record), nextID: 1}
+}
+
+func (r *IdempotencyRepository) Create(ctx context.Context, record *idempotency.Record) error {
+	if _, ok := r.Records[record.Scope+" "+record.Key]; ok {
+		return idempotency.ErrDuplicateKey
+	}
+	record.ID = r.nextID
+	r.nextID++
+	copied := *record
+	r.Records[record.Scope+" "+record.Key] = &copied
+	return nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, scope, key string) (*idempotency.Record, error) {
+	if record, ok := r.Records[scope+" "+key]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	return nil, idempotency.ErrRecordNotFound
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	copied := *record
+	r.Records[record.Scope+" "+record.Key] = &copied
+	return nil
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, id int) error {
+	for k, record := range r.Records {
+		if record.ID == id {
+			delete(r.Records, k)
+			return nil
+		}
+	}
+	return idempotency.ErrRecordNotFound
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestExportRepository_Integration_SitePosts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	posts := repository.NewPostRepository(db.DB)
+	comments := repository.NewCommentRepository(db.DB)
+	repo := repository.NewExportRepository(db.DB)
+	ctx := context.Background()
+
+	var authors []*user.User
+	for _, email := range []string{"exporttest1@example.com", "exporttest2@example.com"} {
+		u, _ := user.NewUser("Test Author", email, "password123")
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("failed to create author: %v", err)
+		}
+		authors = append(authors, u)
+	}
+
+	// Five posts a day apart, alternating between the authors
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var ids []int
+	for i := 0; i < 5; i++ {
+		p, _ := post.NewPost("Exported Post", "Test content with sufficient length.", authors[i%2].ID)
+		p.CreatedAt = created.AddDate(0, 0, i)
+		p.UpdatedAt = p.CreatedAt
+		if err := posts.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		ids = append(ids, p.ID)
+	}
+	if err := posts.SetTags(ctx, ids[0], []string{"go"}); err != nil {
+		t.Fatalf("failed to tag post: %v", err)
+	}
+	for _, postID := range []int{ids[0], ids[0], ids[3]} {
+		c, _ := comment.NewComment(postID, "Reader", "Test comment content.")
+		if err := comments.Create(ctx, c); err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+
+	// Pages start past the posts seeded by the migrations
+	page, err := repo.ListSitePosts(ctx, export.SiteFilter{}, ids[0]-1, 3)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(page) != 3 || page[0].ID != ids[0] || page[2].ID != ids[2] {
+		t.Fatalf("expected the first page in ID order, got %d posts", len(page))
+	}
+	if len(page[0].Tags) != 1 || page[0].Tags[0] != "go" {
+		t.Errorf("expected the tags to be loaded, got %v", page[0].Tags)
+	}
+	page, err = repo.ListSitePosts(ctx, export.SiteFilter{}, page[2].ID, 3)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != ids[3] {
+		t.Errorf("expected the rest of the posts, got %d", len(page))
+	}
+
+	from, to := created.AddDate(0, 0, 1), created.AddDate(0, 0, 3)
+	page, err = repo.ListSitePosts(ctx, export.SiteFilter{AuthorID: authors[1].ID, From: &from, To: &to}, 0, 10)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != ids[1] || page[1].ID != ids[3] {
+		t.Errorf("expected the posts of the second author in range, got %d", len(page))
+	}
+
+	found, err := repo.ListPostComments(ctx, []int{ids[0], ids[3]})
+	if err != nil {
+		t.Fatalf("failed to list comments: %v", err)
+	}
+	if len(found) != 3 {
+		t.Errorf("expected 3 comments, got %d", len(found))
+	}
+}
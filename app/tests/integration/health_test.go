@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/infrastructure/database/migrations"
+	"blog-platform/internal/infrastructure/health"
+)
+
+func TestHealth_Integration_Checks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if err := health.DBCheck(db.DB.DB)(ctx); err != nil {
+		t.Fatalf("expected the database to be reachable, got %v", err)
+	}
+
+	check := health.MigrationsCheck(db.DB.DB, db.DriverName())
+	if err := check(ctx); err != nil {
+		t.Fatalf("expected every migration to be applied, got %v", err)
+	}
+}
+
+func TestHealth_Integration_PendingMigrations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	if db.DriverName() != "sqlite" {
+		t.Skip("reverts migrations of the shared test database")
+	}
+
+	migrator, err := migrations.New(db.DB.DB, db.DriverName())
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+	if _, err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("failed to revert migration: %v", err)
+	}
+
+	check := health.MigrationsCheck(db.DB.DB, db.DriverName())
+	if err := check(ctx); err == nil {
+		t.Fatal("expected a pending migration to fail the check")
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	if err := check(ctx); err != nil {
+		t.Errorf("expected the check to pass once migrated, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/logging"
+)
+
+// newAccessLogServer returns a server logging requests the way SetupRoutes
+// does into JSON lines kept in buf
+func newAccessLogServer(buf *bytes.Buffer) (*echo.Echo, service.Logger) {
+	logger := logging.NewOperationLogger(slog.New(logging.NewCorrelationHandler(slog.NewJSONHandler(buf, nil))))
+	e := echo.New()
+	e.HTTPErrorHandler = errors.HTTPErrorHandler
+	e.Use(middleware.RequestID())
+	e.Use(middleware.AccessLogger(logger))
+	return e, logger
+}
+
+// logLines decodes the JSON lines logged into buf
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+		lines = append(lines, decoded)
+	}
+	return lines
+}
+
+func TestAccessLogger_CorrelatesServiceLogs(t *testing.T) {
+	var buf bytes.Buffer
+	e, logger := newAccessLogServer(&buf)
+	e.GET("/posts/:id", func(c echo.Context) error {
+		// Stand in for Identify signing the user in
+		req := c.Request()
+		c.SetRequest(req.WithContext(service.WithUserID(req.Context(), 42)))
+		logger.Info(c.Request().Context(), "post retrieved")
+		return c.String(http.StatusOK, "hello")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/7", nil)
+	req.Header.Set(echo.HeaderXRequestID, "trace-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, "trace-1", line["request_id"])
+		assert.Equal(t, float64(42), line["user_id"])
+	}
+
+	access := lines[1]
+	assert.Equal(t, "HTTP request completed", access["msg"])
+	assert.Equal(t, "GET", access["method"])
+	assert.Equal(t, "/posts/:id", access["route"])
+	assert.Equal(t, float64(http.StatusOK), access["status"])
+	assert.Equal(t, float64(len("hello")), access["bytes_out"])
+	assert.Contains(t, access, "latency_ms")
+}
+
+func TestAccessLogger_LogsStatusOfReturnedErrors(t *testing.T) {
+	var buf bytes.Buffer
+	e, _ := newAccessLogServer(&buf)
+	e.POST("/login", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"password":"secret123"}`))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotContains(t, buf.String(), "secret123")
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, float64(http.StatusUnauthorized), lines[0]["status"])
+	assert.NotContains(t, lines[0], "user_id")
+}
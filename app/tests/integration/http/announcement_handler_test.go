@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/announcement"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// MockAnnouncementService implements announcement.Service for testing
+type MockAnnouncementService struct {
+	announcements map[int]*announcement.Announcement
+	unsubscribed  []string
+}
+
+func NewMockAnnouncementService() *MockAnnouncementService {
+	return &MockAnnouncementService{announcements: make(map[int]*announcement.Announcement)}
+}
+
+func (m *MockAnnouncementService) Create(ctx context.Context, adminID int, subject, body string, segment announcement.Segment) (*announcement.Announcement, error) {
+	a, err := announcement.NewAnnouncement(adminID, subject, body, segment)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = len(m.announcements) + 1
+	a.Recipients = 3
+	m.announcements[a.ID] = a
+	return a, nil
+}
+
+func (m *MockAnnouncementService) GetReport(ctx context.Context, id int) (*announcement.Report, error) {
+	a, ok := m.announcements[id]
+	if !ok {
+		return nil, announcement.ErrAnnouncementNotFound
+	}
+	return &announcement.Report{
+		Announcement: a,
+		Failures:     []*announcement.Delivery{{AnnouncementID: id, UserID: 2, Email: "bob@example.com", Status: announcement.DeliveryFailed, Error: "550 mailbox unavailable"}},
+	}, nil
+}
+
+func (m *MockAnnouncementService) Unsubscribe(ctx context.Context, token string) error {
+	if token != "valid-token" {
+		return announcement.ErrInvalidUnsubscribeToken
+	}
+	m.unsubscribed = append(m.unsubscribed, token)
+	return nil
+}
+
+func setupAnnouncementTestServer() (*echo.Echo, *handlers.AnnouncementHandler, *MockAnnouncementService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	announcementService := NewMockAnnouncementService()
+	return e, handlers.NewAnnouncementHandler(announcementService, NewMockLogger()), announcementService
+}
+
+func TestAnnouncementHandler_Create(t *testing.T) {
+	e, handler, announcementService := setupAnnouncementTestServer()
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	body, err := json.Marshal(handlers.CreateAnnouncementRequest{
+		Subject: "Maintenance window",
+		Body:    "The blog will be read-only on Sunday.",
+		Segment: handlers.AnnouncementSegmentRequest{Roles: []string{"author"}, RegisteredBefore: &before},
+	})
+	require.NoError(t, err)
+
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/admin/announcements", body)
+	require.NoError(t, handler.Create(c))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var response handlers.AnnouncementResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, announcement.StatusQueued, response.Status)
+	assert.Equal(t, 3, response.Recipients)
+	assert.Equal(t, []string{"author"}, response.Segment.Roles)
+
+	created := announcementService.announcements[response.ID]
+	require.NotNil(t, created)
+	assert.Equal(t, 1, created.CreatedBy)
+	assert.Equal(t, []user.Role{user.RoleAuthor}, created.Segment.Roles)
+	assert.Equal(t, before, *created.Segment.RegisteredBefore)
+}
+
+func TestAnnouncementHandler_Create_InvalidSegment(t *testing.T) {
+	e, handler, _ := setupAnnouncementTestServer()
+
+	body := []byte(`{"subject":"Hello","body":"World","segment":{"roles":["owner"]}}`)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/admin/announcements", body)
+	require.NoError(t, handler.Create(c))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAnnouncementHandler_GetReport(t *testing.T) {
+	e, handler, announcementService := setupAnnouncementTestServer()
+	_, err := announcementService.Create(context.Background(), 1, "Hello", "World", announcement.Segment{})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		id     string
+		status int
+	}{
+		{"1", http.StatusOK},
+		{"2", http.StatusNotFound},
+		{"abc", http.StatusBadRequest},
+	} {
+		rec, c := setupAuthenticatedRequest(e, http.MethodGet, "/api/v1/admin/announcements/"+tc.id, nil)
+		c.SetParamNames("id")
+		c.SetParamValues(tc.id)
+		require.NoError(t, handler.GetReport(c))
+		assert.Equal(t, tc.status, rec.Code, "announcement %s", tc.id)
+
+		if tc.status == http.StatusOK {
+			var response handlers.AnnouncementReportResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "Hello", response.Subject)
+			require.Len(t, response.Failures, 1)
+			assert.Equal(t, "bob@example.com", response.Failures[0].Email)
+		}
+	}
+}
+
+func TestAnnouncementHandler_Unsubscribe(t *testing.T) {
+	e, handler, announcementService := setupAnnouncementTestServer()
+
+	for _, tc := range []struct {
+		query  string
+		status int
+	}{
+		{"?token=valid-token", http.StatusOK},
+		{"?token=forged", http.StatusBadRequest},
+		{"", http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/announcements/unsubscribe"+tc.query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.Unsubscribe(e.NewContext(req, rec)))
+		assert.Equal(t, tc.status, rec.Code, "query %q", tc.query)
+	}
+
+	assert.Equal(t, []string{"valid-token"}, announcementService.unsubscribed)
+}
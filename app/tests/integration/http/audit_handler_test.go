@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/audit"
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+// MockAuditService implements audit.Service for testing
+type MockAuditService struct {
+	entries []*audit.Entry
+	filter  audit.Filter
+	limit   int
+	offset  int
+}
+
+func (m *MockAuditService) ListEntries(ctx context.Context, filter audit.Filter, limit, offset int) ([]*audit.Entry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	m.filter, m.limit, m.offset = filter, limit, offset
+	return m.entries, nil
+}
+
+func TestAuditHandler_ListAuditLogs(t *testing.T) {
+	auditService := &MockAuditService{entries: []*audit.Entry{{
+		ID:        3,
+		Action:    service.AuditActionUserLogin,
+		UserID:    7,
+		Metadata:  map[string]any{"method": "password"},
+		IPAddress: "203.0.113.7",
+		CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}}}
+	handler := handlers.NewAuditHandler(auditService, service.DefaultPaginationPolicy().AuditLogs, NewMockLogger())
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs?user_id=7&action=user.login&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z&limit=5", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.ListAuditLogs(e.NewContext(req, rec)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 7, auditService.filter.UserID)
+	assert.Equal(t, service.AuditActionUserLogin, auditService.filter.Action)
+	require.NotNil(t, auditService.filter.From)
+	require.NotNil(t, auditService.filter.To)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *auditService.filter.To)
+	assert.Equal(t, 5, auditService.limit)
+
+	var response handlers.AuditLogListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Entries, 1)
+	assert.Equal(t, "user.login", response.Entries[0].Action)
+	assert.Equal(t, "password", response.Entries[0].Metadata["method"])
+	assert.Equal(t, "203.0.113.7", response.Entries[0].IPAddress)
+	assert.Equal(t, "2024-01-15T10:30:00Z", response.Entries[0].CreatedAt)
+}
+
+func TestAuditHandler_ListAuditLogs_InvalidFilter(t *testing.T) {
+	handler := handlers.NewAuditHandler(&MockAuditService{}, service.DefaultPaginationPolicy().AuditLogs, NewMockLogger())
+	e := echo.New()
+
+	for _, query := range []string{
+		"user_id=abc",
+		"from=yesterday",
+		"from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.ListAuditLogs(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "query %s", query)
+	}
+}
@@ -6,28 +6,43 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/sso"
 	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/handlers"
 	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
 )
 
 // MockUserService implements the user.Service interface for testing
 type MockUserService struct {
 	users map[string]*user.User
 	nextID int
+	// lockedUntil simulates accounts locked after failed logins
+	lockedUntil map[string]time.Time
+	// lastClientIP is the client IP of the last login
+	lastClientIP string
+	// magicLinks maps unused magic link tokens to emails
+	magicLinks map[string]string
+	// lastDevice is the device of the last magic link request
+	lastDevice user.Device
 }
 
 func NewMockUserService() *MockUserService {
 	return &MockUserService{
-		users:  make(map[string]*user.User),
-		nextID: 1,
+		users:      make(map[string]*user.User),
+		nextID:     1,
+		magicLinks: make(map[string]string),
 	}
 }
 
@@ -48,6 +63,10 @@ func (m *MockUserService) Register(ctx context.Context, name, email, password st
 }
 
 func (m *MockUserService) Login(ctx context.Context, email, password string) (*user.User, error) {
+	m.lastClientIP = user.ClientIPFromContext(ctx)
+	if until, locked := m.lockedUntil[email]; locked {
+		return nil, &user.LockoutError{Until: until}
+	}
 	u, exists := m.users[email]
 	if !exists {
 		return nil, user.ErrInvalidCredentials
@@ -56,10 +75,59 @@ func (m *MockUserService) Login(ctx context.Context, email, password string) (*u
 	if !u.ValidatePassword(password) {
 		return nil, user.ErrInvalidCredentials
 	}
+	if u.IsPasswordResetRequired() {
+		return nil, user.ErrPasswordResetRequired
+	}
 	
 	return u, nil
 }
 
+func (m *MockUserService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, error) {
+	if !external.EmailVerified {
+		return nil, user.ErrUnverifiedEmail
+	}
+	if u, exists := m.users[external.Email]; exists {
+		return u, nil
+	}
+	return m.Register(ctx, external.Name, external.Email, "external-password")
+}
+
+func (m *MockUserService) RequestMagicLink(ctx context.Context, email string, device user.Device) error {
+	m.lastDevice = device
+	if _, exists := m.users[email]; exists {
+		m.magicLinks["mock-magic-"+email] = email
+	}
+	return nil
+}
+
+func (m *MockUserService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, error) {
+	email, ok := m.magicLinks[token]
+	if !ok {
+		return nil, user.ErrInvalidMagicLink
+	}
+	delete(m.magicLinks, token)
+	u := m.users[email]
+	if u.IsPasswordResetRequired() {
+		return nil, user.ErrPasswordResetRequired
+	}
+	return u, nil
+}
+
+func (m *MockUserService) RegisterPasswordless(ctx context.Context, name, email, method string) (*user.User, error) {
+	return m.Register(ctx, name, email, "random-Passw0rd!")
+}
+
+func (m *MockUserService) CompleteLogin(ctx context.Context, id int, method string) (*user.User, error) {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsPasswordResetRequired() {
+		return nil, user.ErrPasswordResetRequired
+	}
+	return u, nil
+}
+
 func (m *MockUserService) GetByID(ctx context.Context, id int) (*user.User, error) {
 	for _, u := range m.users {
 		if u.ID == id {
@@ -78,11 +146,70 @@ func (m *MockUserService) GetByEmail(ctx context.Context, email string) (*user.U
 }
 
 func (m *MockUserService) UpdateProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// A new email only takes effect once confirmed
+	if other, exists := m.users[email]; exists && other.ID != id {
+		return nil, user.ErrUserExists
+	}
+	u.Name = name
+	return u, nil
+}
+
+func (m *MockUserService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	return nil // Not needed for auth tests
+}
+
+func (m *MockUserService) ConfirmEmailChange(ctx context.Context, token string) (*user.User, error) {
+	return nil, nil // Not needed for auth tests
+}
+
+func (m *MockUserService) ScheduleDeletion(ctx context.Context, id int) (*user.User, error) {
 	return nil, nil // Not needed for auth tests
 }
 
 func (m *MockUserService) UpdatePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
-	return nil // Not needed for auth tests
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !u.ValidatePassword(currentPassword) {
+		return user.ErrInvalidCredentials
+	}
+	return u.UpdatePassword(newPassword)
+}
+
+func (m *MockUserService) SyncProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
+	return nil, nil // Not needed for auth tests
+}
+
+func (m *MockUserService) Deactivate(ctx context.Context, id int) error {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Deactivate()
+	return nil
+}
+
+func (m *MockUserService) Reactivate(ctx context.Context, id int) error {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Reactivate()
+	return nil
+}
+
+func (m *MockUserService) ChangeRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u.Role = role
+	return u, nil
 }
 
 func (m *MockUserService) Delete(ctx context.Context, id int) error {
@@ -96,11 +223,16 @@ func (m *MockUserService) List(ctx context.Context, limit, offset int) ([]*user.
 // MockAuthService implements the auth.AuthService interface for testing
 type MockAuthService struct{
 	userService *MockUserService
+	// twoFactorCodes holds the accepted code of users with two-factor authentication
+	twoFactorCodes map[int]string
+	// passkeys verifies passkey ceremonies; set by passkey tests
+	passkeys auth.PasskeyService
 }
 
 func NewMockAuthService(userService *MockUserService) *MockAuthService {
 	return &MockAuthService{
-		userService: userService,
+		userService:    userService,
+		twoFactorCodes: make(map[int]string),
 	}
 }
 
@@ -118,26 +250,155 @@ func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*aut
 	return nil, auth.ErrInvalidToken
 }
 
-func (m *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, string, error) {
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, *auth.TokenPair, error) {
 	u, err := m.userService.Login(ctx, email, password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	token, _ := m.GenerateToken(ctx, u)
-	return u, token, nil
+	if _, enabled := m.twoFactorCodes[u.ID]; enabled {
+		return nil, nil, &auth.TwoFactorChallenge{Token: "mock-2fa-token", ExpiresIn: 5 * time.Minute}
+	}
+	return u, m.mockTokenPair(ctx, u), nil
 }
 
-func (m *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, string, error) {
+func (m *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, *auth.TokenPair, error) {
 	u, err := m.userService.Register(ctx, name, email, password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	token, _ := m.GenerateToken(ctx, u)
-	return u, token, nil
+	return u, m.mockTokenPair(ctx, u), nil
 }
 
-func (m *MockAuthService) RefreshToken(ctx context.Context, token string) (string, error) {
-	return "mock-refreshed-token", nil
+func (m *MockAuthService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, *auth.TokenPair, error) {
+	u, err := m.userService.LoginWithIdentity(ctx, external)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, *auth.TokenPair, error) {
+	u, err := m.userService.LoginWithMagicLink(ctx, token, device)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, enabled := m.twoFactorCodes[u.ID]; enabled {
+		return nil, nil, &auth.TwoFactorChallenge{Token: "mock-2fa-token", ExpiresIn: 5 * time.Minute}
+	}
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) LoginWithSSO(ctx context.Context, organization, state string, response sso.Response) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, sso.ErrConnectionNotFound
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	if refreshToken != "mock-refresh-token" {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	return &auth.TokenPair{
+		AccessToken:  "mock-refreshed-token",
+		RefreshToken: "mock-rotated-refresh-token",
+		ExpiresIn:    15 * time.Minute,
+	}, nil
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	if refreshToken != "" && refreshToken != "mock-refresh-token" {
+		return auth.ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+func (m *MockAuthService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*user.User, *auth.TokenPair, error) {
+	if challengeToken != "mock-2fa-token" {
+		return nil, nil, auth.ErrInvalidToken
+	}
+	for id, expected := range m.twoFactorCodes {
+		if code == expected {
+			u, err := m.userService.GetByID(ctx, id)
+			if err != nil {
+				return nil, nil, err
+			}
+			return u, m.mockTokenPair(ctx, u), nil
+		}
+	}
+	return nil, nil, auth.ErrInvalidTwoFactorCode
+}
+
+func (m *MockAuthService) BeginPasskeyTwoFactor(ctx context.Context, challengeToken string) (*auth.RequestOptions, error) {
+	if challengeToken != "mock-2fa-token" {
+		return nil, auth.ErrInvalidToken
+	}
+	return m.passkeys.BeginTwoFactor(ctx, 1)
+}
+
+func (m *MockAuthService) LoginTwoFactorWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	userID, err := m.passkeys.FinishTwoFactor(ctx, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := m.userService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) SignupWithPasskey(ctx context.Context, credential *auth.AttestationResponse) (*user.User, *auth.TokenPair, error) {
+	u, err := m.passkeys.FinishSignup(ctx, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) LoginWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	userID, err := m.passkeys.FinishLogin(ctx, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := m.userService.CompleteLogin(ctx, userID, "passkey")
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) EnableTwoFactor(ctx context.Context, userID int) (*auth.TwoFactorEnrollment, error) {
+	if _, enabled := m.twoFactorCodes[userID]; enabled {
+		return nil, auth.ErrTwoFactorAlreadyEnabled
+	}
+	return &auth.TwoFactorEnrollment{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/Blog:test@example.com?secret=JBSWY3DPEHPK3PXP"}, nil
+}
+
+func (m *MockAuthService) ConfirmTwoFactor(ctx context.Context, userID int, code string) error {
+	if code != "123456" {
+		return auth.ErrInvalidTwoFactorCode
+	}
+	m.twoFactorCodes[userID] = code
+	return nil
+}
+
+func (m *MockAuthService) DisableTwoFactor(ctx context.Context, userID int, code string) error {
+	expected, enabled := m.twoFactorCodes[userID]
+	if !enabled {
+		return auth.ErrTwoFactorNotEnabled
+	}
+	if code != expected {
+		return auth.ErrInvalidTwoFactorCode
+	}
+	delete(m.twoFactorCodes, userID)
+	return nil
+}
+
+func (m *MockAuthService) mockTokenPair(ctx context.Context, u *user.User) *auth.TokenPair {
+	token, _ := m.GenerateToken(ctx, u)
+	return &auth.TokenPair{
+		AccessToken:  token,
+		RefreshToken: "mock-refresh-token",
+		ExpiresIn:    15 * time.Minute,
+	}
 }
 
 // MockLogger implements the service.Logger interface for testing
@@ -266,6 +527,52 @@ func TestAuthHandler_Register_UserExists(t *testing.T) {
 	assert.Equal(t, "conflict", response.Error)
 }
 
+func TestAuthHandler_Register_IdempotentRetry(t *testing.T) {
+	e, authHandler := setupTestServer()
+	repo := fixtures.NewIdempotencyRepository()
+	idempotencyService := service.NewIdempotencyService(repo, service.IdempotencySettings{TTL: time.Hour, LockTimeout: time.Minute}, NewMockLogger())
+	e.POST("/api/v1/auth/register", authHandler.Register, middleware.IdempotencyWithReplay(idempotencyService, authHandler.ReplayRegister))
+
+	register := func(body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := `{"name":"John Doe","email":"john@example.com","password":"password123"}`
+	first := register(body, "register-1")
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	var registered handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &registered))
+
+	// Only the new user is stored, not the tokens of the response
+	require.Len(t, repo.Records, 1)
+	for _, record := range repo.Records {
+		assert.Equal(t, strconv.Itoa(registered.User.ID), string(record.Body))
+	}
+
+	// The retry signs the registered user in instead of answering 409
+	retry := register(body, "register-1")
+	require.Equal(t, http.StatusCreated, retry.Code, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(middleware.HeaderIdempotentReplayed))
+	var replayed handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(retry.Body.Bytes(), &replayed))
+	assert.Equal(t, registered.User.ID, replayed.User.ID)
+	assert.Equal(t, "mock-jwt-token", replayed.Token)
+	assert.Equal(t, "mock-refresh-token", replayed.RefreshToken)
+
+	rec := register(`{"name":"John Doe","email":"john@example.com","password":"password456"}`, "register-1")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// Failed registrations release their key
+	rec = register(`{"name":"Jane Doe","email":"john@example.com","password":"password123"}`, "register-2")
+	require.Equal(t, http.StatusConflict, rec.Code)
+	assert.Len(t, repo.Records, 1)
+}
+
 func TestAuthHandler_Login_Success(t *testing.T) {
 	e, authHandler := setupTestServer()
 	
@@ -314,6 +621,8 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	assert.Equal(t, "John Doe", response.User.Name)
 	assert.Equal(t, "john@example.com", response.User.Email)
 	assert.Equal(t, "mock-jwt-token", response.Token)
+	assert.Equal(t, "mock-refresh-token", response.RefreshToken)
+	assert.Equal(t, 900, response.ExpiresIn)
 }
 
 func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
@@ -344,6 +653,51 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	assert.Equal(t, "invalid_credentials", response.Error)
 }
 
+func TestAuthHandler_Login_AccountLocked(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	userService := NewMockUserService()
+	userService.lockedUntil = map[string]time.Time{"locked@example.com": time.Now().Add(10 * time.Minute)}
+	authHandler := handlers.NewAuthHandler(userService, NewMockAuthService(userService), NewMockLogger())
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":"locked@example.com","password":"password123"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.7")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	
+	require.NoError(t, authHandler.Login(c))
+	
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Equal(t, "600", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "203.0.113.7", userService.lastClientIP)
+	
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "account_locked", response.Error)
+}
+
+func TestAuthHandler_Login_PasswordResetRequired(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	userService := NewMockUserService()
+	authHandler := handlers.NewAuthHandler(userService, NewMockAuthService(userService), NewMockLogger())
+	u, err := userService.Register(context.Background(), "Jane Doe", "reported@example.com", "password123")
+	require.NoError(t, err)
+	u.RequirePasswordReset()
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":"reported@example.com","password":"password123"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	
+	require.NoError(t, authHandler.Login(e.NewContext(req, rec)))
+	
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "password_reset_required", response.Error)
+}
+
 func TestAuthHandler_Login_ValidationError(t *testing.T) {
 	e, authHandler := setupTestServer()
 	
@@ -371,3 +725,117 @@ func TestAuthHandler_Login_ValidationError(t *testing.T) {
 	
 	assert.Equal(t, "validation_error", response.Error)
 }
+
+func TestAuthHandler_Refresh_Success(t *testing.T) {
+	e, authHandler := setupTestServer()
+	
+	reqBody, err := json.Marshal(handlers.RefreshRequest{RefreshToken: "mock-refresh-token"})
+	require.NoError(t, err)
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	
+	err = authHandler.Refresh(c)
+	require.NoError(t, err)
+	
+	assert.Equal(t, http.StatusOK, rec.Code)
+	
+	var response handlers.TokenResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	
+	assert.Equal(t, "mock-refreshed-token", response.Token)
+	assert.Equal(t, "mock-rotated-refresh-token", response.RefreshToken)
+}
+
+func TestAuthHandler_Refresh_InvalidToken(t *testing.T) {
+	e, authHandler := setupTestServer()
+	
+	reqBody, err := json.Marshal(handlers.RefreshRequest{RefreshToken: "unknown"})
+	require.NoError(t, err)
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	
+	err = authHandler.Refresh(c)
+	require.NoError(t, err)
+	
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	e, authHandler := setupTestServer()
+	
+	reqBody, err := json.Marshal(handlers.RefreshRequest{RefreshToken: "mock-refresh-token"})
+	require.NoError(t, err)
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", bytes.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	
+	err = authHandler.Logout(c)
+	require.NoError(t, err)
+	
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestAuthHandler_MagicLink(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	userService := NewMockUserService()
+	authHandler := handlers.NewAuthHandler(userService, NewMockAuthService(userService), NewMockLogger())
+	_, err := userService.Register(context.Background(), "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+	
+	// Known and unknown emails get the same response
+	for _, email := range []string{"john@example.com", "nobody@example.com"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderXRealIP, "203.0.113.7")
+		req.Header.Set("User-Agent", "Firefox")
+		rec := httptest.NewRecorder()
+		
+		require.NoError(t, authHandler.RequestMagicLink(e.NewContext(req, rec)))
+		
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), "If an account exists for this email")
+	}
+	assert.Equal(t, user.NewDevice("203.0.113.7", "Firefox"), userService.lastDevice)
+	
+	verify := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/magic-link/verify?token="+token, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, authHandler.VerifyMagicLink(c))
+		return rec
+	}
+	
+	rec := verify("mock-magic-john@example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "john@example.com", response.User.Email)
+	assert.NotEmpty(t, response.Token)
+	
+	// Links work once
+	rec = verify("mock-magic-john@example.com")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	
+	rec = verify("")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	
+	t.Run("rejects invalid emails", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link", strings.NewReader(`{"email":"not-an-email"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		
+		require.NoError(t, authHandler.RequestMagicLink(e.NewContext(req, rec)))
+		
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockAvatarService implements user.AvatarService for testing; it records
+// the size of each upload
+type MockAvatarService struct {
+	uploads map[int]int
+}
+
+func NewMockAvatarService() *MockAvatarService {
+	return &MockAvatarService{uploads: make(map[int]int)}
+}
+
+func (m *MockAvatarService) UploadAvatar(ctx context.Context, id int, r io.Reader) (*user.Avatar, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, user.ErrInvalidAvatar
+	}
+	m.uploads[id] = len(data)
+
+	avatar := &user.Avatar{Sizes: make(map[int]string)}
+	for _, size := range user.AvatarSizes {
+		avatar.Sizes[size] = fmt.Sprintf("https://cdn.example.com/avatars/%d/abc-%d.jpg", id, size)
+	}
+	avatar.URL = avatar.Sizes[256]
+	return avatar, nil
+}
+
+func setupAvatarTestServer() (*echo.Echo, *MockAvatarService) {
+	e := echo.New()
+
+	avatarService := NewMockAvatarService()
+	h := handlers.NewAvatarHandler(avatarService, NewMockLogger())
+	upload := middleware.ValidateUpload(middleware.UploadPolicy{
+		Field:   handlers.AvatarField,
+		MaxSize: 1024,
+		Types:   handlers.AvatarTypes,
+	}, NewMockLogger())
+
+	e.POST("/api/v1/users/me/avatar", h.UploadAvatar, fixtures.RequireUser, upload)
+
+	return e, avatarService
+}
+
+// avatarUpload sends a multipart upload of content in the field
+func avatarUpload(e *echo.Echo, field string, content []byte, userID string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile(field, "avatar.png")
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/me/avatar", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func smallPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8))))
+	return buf.Bytes()
+}
+
+func TestAvatarHandler_UploadAvatar(t *testing.T) {
+	e, avatarService := setupAvatarTestServer()
+	content := smallPNG(t)
+
+	rec := avatarUpload(e, "avatar", content, "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response handlers.AvatarResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "https://cdn.example.com/avatars/1/abc-256.jpg", response.AvatarURL)
+	assert.Equal(t, "https://cdn.example.com/avatars/1/abc-64.jpg", response.Sizes["64"])
+	assert.Len(t, response.Sizes, 3)
+	assert.Equal(t, len(content), avatarService.uploads[1])
+
+	rec = avatarUpload(e, "avatar", content, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAvatarHandler_UploadAvatar_Rejected(t *testing.T) {
+	e, avatarService := setupAvatarTestServer()
+
+	rec := avatarUpload(e, "avatar", []byte("<html><body>not an image</body></html>"), "1")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code, "the type is detected from the content")
+
+	rec = avatarUpload(e, "avatar", append(smallPNG(t), make([]byte, 2048)...), "1")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = avatarUpload(e, "picture", smallPNG(t), "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// A PNG signature without an image behind it passes the type check
+	rec = avatarUpload(e, "avatar", smallPNG(t)[:16], "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/me/avatar", bytes.NewReader(smallPNG(t)))
+	req.Header.Set(echo.HeaderContentType, "image/png")
+	req.Header.Set(fixtures.UserHeader, "1")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	assert.Empty(t, avatarService.uploads)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/badge"
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/ratelimit"
+	"blog-platform/tests/fixtures"
+)
+
+func setupBadgeTestServer(cacheTTL time.Duration, burst int) (*echo.Echo, *MockPostService, *badge.Renderer) {
+	e := echo.New()
+	postService := NewMockPostService()
+	published := fixtures.Post(1, 1)
+	published.ViewCount = 1250
+	postService.posts[1] = published
+	postService.posts[2] = fixtures.Draft(2, 1)
+
+	cfg := &config.Config{RateLimit: config.RateLimitConfig{BadgeRequestsPerSecond: 1, BadgeBurstSize: burst}}
+	renderer := badge.NewRenderer(postService, badge.Settings{CacheTTL: cacheTTL})
+	badgeHandler := handlers.NewBadgeHandler(renderer, NewMockLogger())
+	badges := e.Group("/badges", middleware.BadgeRateLimiterMiddleware(cfg, ratelimit.NewMemoryStore(), NewMockLogger()))
+	badges.GET("/posts/:id/views.svg", badgeHandler.PostViews)
+	return e, postService, renderer
+}
+
+func badgeRequest(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBadgeHandler_PostViews(t *testing.T) {
+	e, postService, renderer := setupBadgeTestServer(10*time.Minute, 10)
+
+	rec := badgeRequest(e, "/badges/posts/1/views.svg")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, badge.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "public, max-age=600", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Contains(t, rec.Body.String(), "<title>views: 1.2k</title>")
+
+	// The count is served from the cache until it expires
+	postService.posts[1].ViewCount = 2000
+	rec = badgeRequest(e, "/badges/posts/1/views.svg")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "views: 1.2k")
+	stats := renderer.CacheStats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, 1, stats.Entries)
+
+	// Drafts have no badge
+	rec = badgeRequest(e, "/badges/posts/2/views.svg")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = badgeRequest(e, "/badges/posts/9/views.svg")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = badgeRequest(e, "/badges/posts/abc/views.svg")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBadgeHandler_Uncached(t *testing.T) {
+	e, postService, _ := setupBadgeTestServer(0, 10)
+
+	rec := badgeRequest(e, "/badges/posts/1/views.svg")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderCacheControl))
+
+	postService.posts[1].ViewCount = 2000
+	rec = badgeRequest(e, "/badges/posts/1/views.svg")
+	assert.Contains(t, rec.Body.String(), "views: 2k")
+}
+
+func TestBadgeHandler_RateLimited(t *testing.T) {
+	e, _, _ := setupBadgeTestServer(10*time.Minute, 2)
+
+	for i := 0; i < 2; i++ {
+		rec := badgeRequest(e, "/badges/posts/1/views.svg")
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	rec := badgeRequest(e, "/badges/posts/1/views.svg")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/banner"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockBannerService implements banner.Service for testing
+type MockBannerService struct {
+	banners    map[int]*banner.Banner
+	dismissals map[int][]int
+}
+
+func NewMockBannerService() *MockBannerService {
+	return &MockBannerService{banners: make(map[int]*banner.Banner), dismissals: make(map[int][]int)}
+}
+
+func (m *MockBannerService) Create(ctx context.Context, adminID int, in banner.Input) (*banner.Banner, error) {
+	b, err := banner.NewBanner(adminID, in)
+	if err != nil {
+		return nil, err
+	}
+	b.ID = len(m.banners) + 1
+	m.banners[b.ID] = b
+	return b, nil
+}
+
+func (m *MockBannerService) Update(ctx context.Context, adminID, id int, in banner.Input) (*banner.Banner, error) {
+	b, ok := m.banners[id]
+	if !ok {
+		return nil, banner.ErrBannerNotFound
+	}
+	if err := b.Update(in); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (m *MockBannerService) Delete(ctx context.Context, adminID, id int) error {
+	if _, ok := m.banners[id]; !ok {
+		return banner.ErrBannerNotFound
+	}
+	delete(m.banners, id)
+	return nil
+}
+
+func (m *MockBannerService) List(ctx context.Context) ([]*banner.Banner, error) {
+	var banners []*banner.Banner
+	for id := len(m.banners); id > 0; id-- {
+		if b, ok := m.banners[id]; ok {
+			banners = append(banners, b)
+		}
+	}
+	return banners, nil
+}
+
+func (m *MockBannerService) ListActive(ctx context.Context, userID int) ([]*banner.Banner, error) {
+	var banners []*banner.Banner
+	all, _ := m.List(ctx)
+	for _, b := range all {
+		if b.IsActive(time.Now()) && !m.dismissed(b.ID, userID) {
+			banners = append(banners, b)
+		}
+	}
+	return banners, nil
+}
+
+func (m *MockBannerService) Dismiss(ctx context.Context, id, userID int) error {
+	b, ok := m.banners[id]
+	if !ok {
+		return banner.ErrBannerNotFound
+	}
+	if !b.Dismissible {
+		return banner.ErrNotDismissible
+	}
+	if !m.dismissed(id, userID) {
+		m.dismissals[userID] = append(m.dismissals[userID], id)
+	}
+	return nil
+}
+
+func (m *MockBannerService) dismissed(id, userID int) bool {
+	for _, dismissed := range m.dismissals[userID] {
+		if dismissed == id {
+			return true
+		}
+	}
+	return false
+}
+
+func setupBannerTestServer() (*echo.Echo, *MockBannerService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	bannerService := NewMockBannerService()
+	h := handlers.NewBannerHandler(bannerService, NewMockLogger())
+
+	e.GET("/api/v1/announcements/active", h.ListActive, fixtures.AsUser)
+	e.POST("/api/v1/announcements/:id/dismiss", h.Dismiss, fixtures.AsUser)
+	e.GET("/api/v1/admin/banners", h.List, fixtures.AsUser)
+	e.POST("/api/v1/admin/banners", h.Create, fixtures.AsUser)
+	e.PUT("/api/v1/admin/banners/:id", h.Update, fixtures.AsUser)
+	e.DELETE("/api/v1/admin/banners/:id", h.Delete, fixtures.AsUser)
+
+	return e, bannerService
+}
+
+func bannerRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBannerHandler_Manage(t *testing.T) {
+	e, bannerService := setupBannerTestServer()
+
+	rec := bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"Maintenance tonight","severity":"warning"}`, "1")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created handlers.BannerResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "warning", created.Severity)
+	assert.True(t, created.Dismissible, "banners are dismissible by default")
+	assert.True(t, created.Active)
+
+	rec = bannerRequest(e, http.MethodPut, "/api/v1/admin/banners/1", `{"message":"Maintenance now","severity":"critical","dismissible":false}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, bannerService.banners[1].Dismissible)
+	assert.Equal(t, banner.SeverityCritical, bannerService.banners[1].Severity)
+
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"Hello","severity":"loud"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"Hello","severity":"info","starts_at":"2024-03-02T00:00:00Z","ends_at":"2024-03-01T00:00:00Z"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = bannerRequest(e, http.MethodPut, "/api/v1/admin/banners/9", `{"message":"Hello","severity":"info"}`, "1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = bannerRequest(e, http.MethodGet, "/api/v1/admin/banners", "", "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.BannerListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+
+	rec = bannerRequest(e, http.MethodDelete, "/api/v1/admin/banners/1", "", "1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = bannerRequest(e, http.MethodDelete, "/api/v1/admin/banners/1", "", "1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBannerHandler_ActiveAndDismiss(t *testing.T) {
+	e, _ := setupBannerTestServer()
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"New feature","severity":"info"}`, "1")
+	bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"Outage","severity":"critical","dismissible":false}`, "1")
+	bannerRequest(e, http.MethodPost, "/api/v1/admin/banners", `{"message":"Later","severity":"info","starts_at":"`+future+`"}`, "1")
+
+	active := func(userID string) []handlers.BannerResponse {
+		rec := bannerRequest(e, http.MethodGet, "/api/v1/announcements/active", "", userID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.BannerListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Banners
+	}
+	assert.Len(t, active(""), 2, "scheduled banners are not shown yet")
+
+	rec := bannerRequest(e, http.MethodPost, "/api/v1/announcements/1/dismiss", "", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/announcements/1/dismiss", "", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "dismissing again has no effect")
+	if banners := active("7"); assert.Len(t, banners, 1) {
+		assert.Equal(t, "Outage", banners[0].Message)
+	}
+	assert.Len(t, active("8"), 2, "dismissals are per user")
+
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/announcements/2/dismiss", "", "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/announcements/9/dismiss", "", "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = bannerRequest(e, http.MethodPost, "/api/v1/announcements/1/dismiss", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
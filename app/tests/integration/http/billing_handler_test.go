@@ -0,0 +1,154 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/stripe"
+	"blog-platform/tests/fixtures"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// MockBillingRepository implements billing.Repository for testing
+type MockBillingRepository struct {
+	subscriptions map[int]*billing.Subscription
+	posts         map[int]int
+}
+
+func (m *MockBillingRepository) GetByUserID(ctx context.Context, userID int) (*billing.Subscription, error) {
+	s, ok := m.subscriptions[userID]
+	if !ok {
+		return nil, billing.ErrSubscriptionNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *MockBillingRepository) GetByStripeCustomerID(ctx context.Context, customerID string) (*billing.Subscription, error) {
+	for _, s := range m.subscriptions {
+		if s.StripeCustomerID == customerID {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, billing.ErrSubscriptionNotFound
+}
+
+func (m *MockBillingRepository) Save(ctx context.Context, s *billing.Subscription) error {
+	copied := *s
+	m.subscriptions[s.UserID] = &copied
+	return nil
+}
+
+func (m *MockBillingRepository) CountPosts(ctx context.Context, userID int) (int, error) {
+	return m.posts[userID], nil
+}
+
+// MockAuditRecorder discards audit events
+type MockAuditRecorder struct{}
+
+func (MockAuditRecorder) Record(ctx context.Context, event service.AuditEvent) {}
+
+func setupBillingTestServer() (*echo.Echo, *MockBillingRepository) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	repo := &MockBillingRepository{subscriptions: make(map[int]*billing.Subscription), posts: map[int]int{7: 3}}
+	billingService := service.NewBillingService(repo, MockAuditRecorder{}, NewMockLogger())
+	verifier := stripe.NewWebhookVerifier(testWebhookSecret, stripe.DefaultTolerance, map[string]string{"price_pro": "pro"})
+	h := handlers.NewBillingHandler(billingService, verifier, NewMockLogger())
+
+	signedIn := fixtures.ActingAs(7)
+	e.GET("/api/v1/users/me/subscription", h.GetSubscription, signedIn)
+	e.POST("/api/v1/billing/stripe/webhook", h.StripeWebhook)
+
+	return e, repo
+}
+
+// postWebhook sends a webhook payload signed with secret
+func postWebhook(e *echo.Echo, secret, payload string) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/billing/stripe/webhook", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Stripe-Signature", "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func getSubscription(t *testing.T, e *echo.Echo) handlers.SubscriptionResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/subscription", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response handlers.SubscriptionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response
+}
+
+func TestBillingHandler_Subscription(t *testing.T) {
+	e, _ := setupBillingTestServer()
+
+	// Users start on the free plan
+	subscription := getSubscription(t, e)
+	assert.Equal(t, billing.PlanFree, subscription.Plan)
+	assert.Empty(t, subscription.Status)
+	assert.Equal(t, billing.LimitsOf(billing.PlanFree), subscription.Limits)
+	assert.Equal(t, 3, subscription.Usage.Posts)
+
+	created := `{"id":"evt_1","type":"customer.subscription.created","created":` + strconv.FormatInt(time.Now().Unix(), 10) + `,
+		"data":{"object":{"id":"sub_1","customer":"cus_1","status":"active","metadata":{"user_id":"7"},
+		"items":{"data":[{"current_period_end":1707991200,"price":{"id":"price_pro"}}]}}}}`
+	rec := postWebhook(e, testWebhookSecret, created)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"received":true}`, rec.Body.String())
+
+	subscription = getSubscription(t, e)
+	assert.Equal(t, billing.PlanPro, subscription.Plan)
+	assert.Equal(t, billing.StatusActive, subscription.Status)
+	assert.True(t, subscription.Limits.CustomDomains)
+	require.NotNil(t, subscription.CurrentPeriodEnd)
+	assert.Equal(t, int64(1707991200), subscription.CurrentPeriodEnd.Unix())
+}
+
+func TestBillingHandler_StripeWebhook(t *testing.T) {
+	e, repo := setupBillingTestServer()
+
+	rec := postWebhook(e, testWebhookSecret, `{"id":"evt_1","type":"invoice.paid","data":{"object":{}}}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "other events are acknowledged")
+
+	event := `{"id":"evt_2","type":"customer.subscription.updated","created":1705312800,
+		"data":{"object":{"id":"sub_1","customer":"cus_1","status":"active","items":{"data":[{"price":{"id":"price_pro"}}]}}}}`
+	rec = postWebhook(e, "whsec_other", event)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid webhook signature")
+
+	// Events for customers not linked to a user are refused so Stripe retries them
+	rec = postWebhook(e, testWebhookSecret, event)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, repo.subscriptions)
+}
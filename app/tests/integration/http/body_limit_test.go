@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+func setupBodyLimitServer() *echo.Echo {
+	e := echo.New()
+	e.Use(middleware.BodyLimit(middleware.BodyLimitPolicy{
+		Default:  64,
+		Prefixes: map[string]int64{"/api/v1/auth": 16, "/api/v1/import": 0},
+	}, NewMockLogger()))
+	echoBody := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(body))
+	}
+	e.POST("/api/v1/posts", echoBody)
+	e.POST("/api/v1/auth/login", echoBody)
+	e.POST("/api/v1/authors", echoBody)
+	e.POST("/api/v1/import", echoBody)
+	return e
+}
+
+func TestBodyLimit(t *testing.T) {
+	e := setupBodyLimitServer()
+
+	tests := []struct {
+		name     string
+		path     string
+		size     int
+		chunked  bool
+		expected int
+	}{
+		{"within the default", "/api/v1/posts", 64, false, http.StatusOK},
+		{"over the default", "/api/v1/posts", 65, false, http.StatusRequestEntityTooLarge},
+		{"over the default without a length", "/api/v1/posts", 65, true, http.StatusRequestEntityTooLarge},
+		{"within the default without a length", "/api/v1/posts", 40, true, http.StatusOK},
+		{"over the group limit", "/api/v1/auth/login", 17, false, http.StatusRequestEntityTooLarge},
+		{"prefix matches whole segments", "/api/v1/authors", 40, false, http.StatusOK},
+		{"group without a limit", "/api/v1/import", 1024, true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.Repeat("a", tt.size)
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expected, rec.Code)
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, body, rec.Body.String())
+				return
+			}
+			var response errors.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, string(errors.ErrCodePayloadTooLarge), response.Error)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/tests/fixtures"
+)
+
+// MockBookmarkService implements post.BookmarkService for testing,
+// keeping bookmarks in the order they were made
+type MockBookmarkService struct {
+	posts     *MockPostService
+	bookmarks []post.Bookmark
+}
+
+func NewMockBookmarkService() *MockBookmarkService {
+	return &MockBookmarkService{posts: NewMockPostService()}
+}
+
+func (m *MockBookmarkService) Bookmark(ctx context.Context, userID, postID int) error {
+	if existing, ok := m.posts.posts[postID]; !ok || !existing.IsPublished() {
+		return post.ErrPostNotFound
+	}
+	for _, b := range m.bookmarks {
+		if b.UserID == userID && b.PostID == postID {
+			return nil
+		}
+	}
+	m.bookmarks = append(m.bookmarks, post.Bookmark{UserID: userID, PostID: postID, CreatedAt: time.Now()})
+	return nil
+}
+
+func (m *MockBookmarkService) Unbookmark(ctx context.Context, userID, postID int) error {
+	for i, b := range m.bookmarks {
+		if b.UserID == userID && b.PostID == postID {
+			m.bookmarks = append(m.bookmarks[:i], m.bookmarks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MockBookmarkService) ListBookmarks(ctx context.Context, userID int, limit, offset int) ([]*post.BookmarkEntry, error) {
+	var entries []*post.BookmarkEntry
+	for i := len(m.bookmarks) - 1; i >= 0; i-- {
+		b := m.bookmarks[i]
+		if b.UserID != userID {
+			continue
+		}
+		p := m.posts.posts[b.PostID]
+		entries = append(entries, &post.BookmarkEntry{Bookmark: b, Title: p.Title, Slug: p.Slug, AuthorID: p.AuthorID})
+	}
+	if offset >= len(entries) {
+		return nil, nil
+	}
+	entries = entries[offset:]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func setupBookmarkTestServer() *echo.Echo {
+	e := echo.New()
+
+	bookmarkService := NewMockBookmarkService()
+	bookmarkService.posts.posts[1] = fixtures.Post(1, 1)
+	bookmarkService.posts.posts[2] = fixtures.Draft(2, 1)
+	bookmarkService.posts.posts[3] = fixtures.Post(3, 4)
+	h := handlers.NewBookmarkHandler(bookmarkService, service.PageLimits{Default: 10, Max: 20}, NewMockLogger())
+
+	e.POST("/api/v1/posts/:id/bookmark", h.Bookmark, fixtures.AsUser)
+	e.DELETE("/api/v1/posts/:id/bookmark", h.Unbookmark, fixtures.AsUser)
+	e.GET("/api/v1/users/me/bookmarks", h.ListBookmarks, fixtures.AsUser)
+
+	return e
+}
+
+// bookmarkRequest sends a request as the user, or anonymously with an empty
+// userID
+func bookmarkRequest(e *echo.Echo, method, path, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBookmarkHandler_Bookmark(t *testing.T) {
+	e := setupBookmarkTestServer()
+
+	rec := bookmarkRequest(e, http.MethodPost, "/api/v1/posts/1/bookmark", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec = bookmarkRequest(e, http.MethodPost, "/api/v1/posts/1/bookmark", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "bookmarking again has no effect")
+
+	rec = bookmarkRequest(e, http.MethodPost, "/api/v1/posts/2/bookmark", "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "drafts cannot be bookmarked")
+	rec = bookmarkRequest(e, http.MethodPost, "/api/v1/posts/9/bookmark", "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = bookmarkRequest(e, http.MethodPost, "/api/v1/posts/abc/bookmark", "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = bookmarkRequest(e, http.MethodPost, "/api/v1/posts/1/bookmark", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = bookmarkRequest(e, http.MethodDelete, "/api/v1/posts/1/bookmark", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = bookmarkRequest(e, http.MethodDelete, "/api/v1/posts/1/bookmark", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "removing a missing bookmark has no effect")
+	rec = bookmarkRequest(e, http.MethodDelete, "/api/v1/posts/1/bookmark", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestBookmarkHandler_ListBookmarks(t *testing.T) {
+	e := setupBookmarkTestServer()
+	bookmarkRequest(e, http.MethodPost, "/api/v1/posts/1/bookmark", "7")
+	bookmarkRequest(e, http.MethodPost, "/api/v1/posts/3/bookmark", "7")
+
+	list := func(query, userID string) handlers.BookmarkListResponse {
+		rec := bookmarkRequest(e, http.MethodGet, "/api/v1/users/me/bookmarks"+query, userID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.BookmarkListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	response := list("", "7")
+	if assert.Len(t, response.Bookmarks, 2) {
+		assert.Equal(t, 3, response.Bookmarks[0].PostID, "the latest bookmark comes first")
+		assert.Equal(t, "Post 3", response.Bookmarks[0].Title)
+		assert.Equal(t, 4, response.Bookmarks[0].AuthorID)
+		assert.Equal(t, 1, response.Bookmarks[1].PostID)
+	}
+	assert.Equal(t, 10, response.Limit)
+
+	response = list("?limit=1&offset=1", "7")
+	if assert.Len(t, response.Bookmarks, 1) {
+		assert.Equal(t, 1, response.Bookmarks[0].PostID)
+	}
+	assert.Equal(t, 1, response.Limit)
+	assert.Equal(t, 1, response.Offset)
+
+	assert.Empty(t, list("", "8").Bookmarks, "reading lists are per user")
+
+	rec := bookmarkRequest(e, http.MethodGet, "/api/v1/users/me/bookmarks", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+const (
+	offlinePostID  = "6f1c2a8e-3b4d-4e5f-8a9b-0c1d2e3f4a5b"
+	offlineReplyID = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+)
+
+func setupClientIDTestServer() (*echo.Echo, *MockPostService, *MockCommentService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	postService := NewMockPostService()
+	postService.posts[1] = fixtures.Post(1, 1)
+	postService.nextID = 2
+	commentService := NewMockCommentService()
+	logger := NewMockLogger()
+
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, logger)
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, logger)
+
+	asAuthor := fixtures.AsUserWithRole(user.RoleAuthor)
+	posts := e.Group("/api/v1/posts", middleware.PostClientIDs(postService, logger))
+	posts.POST("", postHandler.CreatePost, asAuthor)
+	posts.GET("/:id", postHandler.GetPost, asAuthor)
+	posts.POST("/:id/comments", commentHandler.CreateComment, asAuthor)
+	e.PUT("/api/v1/comments/:id", commentHandler.UpdateComment, middleware.CommentClientIDs(commentService, logger), asAuthor)
+
+	return e, postService, commentService
+}
+
+func clientIDRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if userID != "" {
+		req.Header.Set(fixtures.UserHeader, userID)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestClientID_CreatePostRetry(t *testing.T) {
+	e, postService, _ := setupClientIDTestServer()
+	body := `{"title":"Offline Post","content":"Written on a plane without a network.","client_id":"` + offlinePostID + `"}`
+
+	rec := clientIDRequest(e, http.MethodPost, "/api/v1/posts", body, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, offlinePostID, created.ClientID)
+	assert.NotZero(t, created.ID)
+
+	// The client never saw the answer and resends the request
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts", body, "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var retried handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &retried))
+	assert.Equal(t, created.ID, retried.ID)
+	assert.Len(t, postService.posts, 2)
+
+	// Another author cannot take the client ID
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts", body, "2")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts", `{"title":"Offline Post","content":"Written on a plane without a network.","client_id":"42"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestClientID_PostRoutesAcceptClientIDs(t *testing.T) {
+	e, _, _ := setupClientIDTestServer()
+	body := `{"title":"Offline Post","content":"Written on a plane without a network.","client_id":"` + offlinePostID + `"}`
+	rec := clientIDRequest(e, http.MethodPost, "/api/v1/posts", body, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	for _, id := range []string{offlinePostID, strings.ToUpper(offlinePostID), strconv.Itoa(created.ID)} {
+		rec = clientIDRequest(e, http.MethodGet, "/api/v1/posts/"+id, "", "")
+		require.Equal(t, http.StatusOK, rec.Code, id)
+		var got handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, created.ID, got.ID)
+		assert.Equal(t, offlinePostID, got.ClientID)
+	}
+
+	rec = clientIDRequest(e, http.MethodGet, "/api/v1/posts/00000000-0000-0000-0000-000000000000", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Posts created without a client ID leave it out
+	rec = clientIDRequest(e, http.MethodGet, "/api/v1/posts/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "client_id")
+}
+
+func TestClientID_OfflineCommentThread(t *testing.T) {
+	e, _, commentService := setupClientIDTestServer()
+	rootID := "5d41402a-bc4b-4a76-b971-9d911017c592"
+
+	// The whole thread is queued offline: the post and the parent comment
+	// are only known by their client IDs
+	rec := clientIDRequest(e, http.MethodPost, "/api/v1/posts", `{"title":"Offline Post","content":"Written on a plane without a network.","client_id":"`+offlinePostID+`"}`, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts/"+offlinePostID+"/comments", `{"author_name":"John Doe","content":"First!","client_id":"`+rootID+`"}`, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var root handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &root))
+	assert.Equal(t, rootID, root.ClientID)
+
+	reply := `{"author_name":"John Doe","content":"Replying offline","client_id":"` + offlineReplyID + `","parent_client_id":"` + rootID + `"}`
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts/"+offlinePostID+"/comments", reply, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.NotNil(t, created.ParentID)
+	assert.Equal(t, root.ID, *created.ParentID)
+	assert.Equal(t, root.PostID, created.PostID)
+
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts/"+offlinePostID+"/comments", reply, "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, commentService.comments, 2)
+
+	rec = clientIDRequest(e, http.MethodPut, "/api/v1/comments/"+offlineReplyID, `{"content":"Edited once back online"}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Edited once back online", commentService.comments[created.ID].Content)
+
+	rec = clientIDRequest(e, http.MethodPost, "/api/v1/posts/"+offlinePostID+"/comments", `{"author_name":"John Doe","content":"Replying to nothing","parent_client_id":"00000000-0000-0000-0000-000000000000"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
 
@@ -14,7 +17,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/handlers"
 	"blog-platform/internal/infrastructure/http/middleware"
 )
@@ -23,20 +30,44 @@ import (
 type MockCommentService struct {
 	comments map[int]*comment.Comment
 	nextID   int
+	// postAuthors maps post IDs to author IDs for author comment feeds
+	postAuthors map[int]int
+	// requireModeration holds new comments as pending
+	requireModeration bool
+	// hiddenPosts maps the IDs of drafts and private posts to their author,
+	// the only user besides admins who sees their comments
+	hiddenPosts map[int]int
 }
 
 func NewMockCommentService() *MockCommentService {
 	return &MockCommentService{
-		comments: make(map[int]*comment.Comment),
-		nextID:   1,
+		comments:    make(map[int]*comment.Comment),
+		nextID:      1,
+		postAuthors: make(map[int]int),
+		hiddenPosts: make(map[int]int),
 	}
 }
 
-func (m *MockCommentService) AddComment(ctx context.Context, postID int, authorName, content string) (*comment.Comment, error) {
+// checkPostVisible hides the comments of hidden posts like the service
+func (m *MockCommentService) checkPostVisible(postID, userID int, role user.Role) error {
+	if author, hidden := m.hiddenPosts[postID]; hidden && author != userID && !role.IsAdmin() {
+		return post.ErrPostNotFound
+	}
+	return nil
+}
+
+func (m *MockCommentService) AddComment(ctx context.Context, postID, userID int, role user.Role, authorName, content string) (*comment.Comment, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, err
+	}
 	newComment, err := comment.NewComment(postID, authorName, content)
 	if err != nil {
 		return nil, err
 	}
+	newComment.PostedBy(userID)
+	if m.requireModeration {
+		newComment.Status = comment.StatusPending
+	}
 	
 	newComment.ID = m.nextID
 	m.comments[m.nextID] = newComment
@@ -45,6 +76,67 @@ func (m *MockCommentService) AddComment(ctx context.Context, postID int, authorN
 	return newComment, nil
 }
 
+// ReplyToComment adds a reply to a comment of the same post
+func (m *MockCommentService) ReplyToComment(ctx context.Context, postID, parentID, userID int, role user.Role, authorName, content string) (*comment.Comment, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, err
+	}
+	parent, exists := m.comments[parentID]
+	if !exists {
+		return nil, comment.ErrParentNotFound
+	}
+	reply, err := comment.NewComment(postID, authorName, content)
+	if err != nil {
+		return nil, err
+	}
+	reply.PostedBy(userID)
+	if err := reply.ReplyTo(parent); err != nil {
+		return nil, err
+	}
+
+	reply.ID = m.nextID
+	m.comments[m.nextID] = reply
+	m.nextID++
+
+	return reply, nil
+}
+
+// AddClientComment returns the comment already created under the client ID
+// by the same commenter, or adds a new one
+func (m *MockCommentService) AddClientComment(ctx context.Context, postID, parentID, userID int, role user.Role, clientID, authorName, content string) (*comment.Comment, bool, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, false, err
+	}
+	if existing, err := m.GetCommentByClientID(ctx, clientID); err == nil {
+		if existing.PostID != postID || !existing.IsPostedBy(userID) {
+			return nil, false, comment.ErrClientIDTaken
+		}
+		return existing, false, nil
+	}
+
+	var c *comment.Comment
+	var err error
+	if parentID > 0 {
+		c, err = m.ReplyToComment(ctx, postID, parentID, userID, role, authorName, content)
+	} else {
+		c, err = m.AddComment(ctx, postID, userID, role, authorName, content)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	c.ClientID = &clientID
+	return c, true, nil
+}
+
+func (m *MockCommentService) GetCommentByClientID(ctx context.Context, clientID string) (*comment.Comment, error) {
+	for _, c := range m.comments {
+		if c.ClientID != nil && *c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return nil, comment.ErrCommentNotFound
+}
+
 func (m *MockCommentService) GetComment(ctx context.Context, id int) (*comment.Comment, error) {
 	if comment, exists := m.comments[id]; exists {
 		return comment, nil
@@ -52,12 +144,15 @@ func (m *MockCommentService) GetComment(ctx context.Context, id int) (*comment.C
 	return nil, fmt.Errorf("comment not found")
 }
 
-func (m *MockCommentService) GetCommentsByPost(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
+func (m *MockCommentService) GetCommentsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*comment.Comment, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, err
+	}
 	var result []*comment.Comment
 	count := 0
 	
-	for _, c := range m.comments {
-		if c.PostID == postID {
+	for _, id := range slices.Sorted(maps.Keys(m.comments)) {
+		if c := m.comments[id]; c.PostID == postID && c.IsApproved() {
 			if count >= offset && len(result) < limit {
 				result = append(result, c)
 			}
@@ -68,9 +163,71 @@ func (m *MockCommentService) GetCommentsByPost(ctx context.Context, postID int,
 	return result, nil
 }
 
-func (m *MockCommentService) UpdateComment(ctx context.Context, id int, authorName, content string) (*comment.Comment, error) {
+// GetCommentsByPostAfter pages through comments in ID order, which is the
+// order the mock creates them in
+func (m *MockCommentService) GetCommentsByPostAfter(ctx context.Context, postID, userID int, role user.Role, after keyset.Cursor, limit int) ([]*comment.Comment, keyset.Cursor, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, keyset.Cursor{}, err
+	}
+	var result []*comment.Comment
+	for _, id := range slices.Sorted(maps.Keys(m.comments)) {
+		if c := m.comments[id]; c.PostID == postID && id > after.ID {
+			if len(result) == limit {
+				last := result[limit-1]
+				return result, keyset.After(last.CreatedAt, last.ID), nil
+			}
+			result = append(result, c)
+		}
+	}
+	return result, keyset.Cursor{}, nil
+}
+
+// GetThreadsByPost pages through the top-level comments in ID order
+func (m *MockCommentService) GetThreadsByPost(ctx context.Context, postID, userID int, role user.Role, limit, offset int) ([]*comment.Thread, error) {
+	if err := m.checkPostVisible(postID, userID, role); err != nil {
+		return nil, err
+	}
+	var roots, replies []*comment.Comment
+	for _, id := range slices.Sorted(maps.Keys(m.comments)) {
+		c := m.comments[id]
+		switch {
+		case c.PostID != postID:
+		case c.ParentID == nil:
+			roots = append(roots, c)
+		default:
+			replies = append(replies, c)
+		}
+	}
+	roots = roots[min(offset, len(roots)):]
+	return comment.BuildThreads(roots[:min(limit, len(roots))], replies), nil
+}
+
+func (m *MockCommentService) GetRecentComments(ctx context.Context, filter comment.RecentFilter, limit int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if filter.PostID > 0 && c.PostID != filter.PostID {
+			continue
+		}
+		if filter.PostAuthorID > 0 && m.postAuthors[c.PostID] != filter.PostAuthorID {
+			continue
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockCommentService) UpdateComment(ctx context.Context, id, userID int, role user.Role, content string) (*comment.Comment, error) {
 	if c, exists := m.comments[id]; exists {
-		if c.AuthorName != authorName {
+		if !c.CanModify(userID, role) {
 			return nil, fmt.Errorf("unauthorized")
 		}
 		err := c.Update(content)
@@ -82,9 +239,9 @@ func (m *MockCommentService) UpdateComment(ctx context.Context, id int, authorNa
 	return nil, fmt.Errorf("comment not found")
 }
 
-func (m *MockCommentService) DeleteComment(ctx context.Context, id int, authorName string) error {
+func (m *MockCommentService) DeleteComment(ctx context.Context, id, userID int, role user.Role) error {
 	if c, exists := m.comments[id]; exists {
-		if c.AuthorName != authorName {
+		if !c.CanModify(userID, role) {
 			return fmt.Errorf("unauthorized")
 		}
 		delete(m.comments, id)
@@ -93,6 +250,31 @@ func (m *MockCommentService) DeleteComment(ctx context.Context, id int, authorNa
 	return fmt.Errorf("comment not found")
 }
 
+func (m *MockCommentService) ListForModeration(ctx context.Context, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	if !status.IsValid() {
+		return nil, comment.ErrInvalidStatus
+	}
+	var result []*comment.Comment
+	for _, id := range slices.Sorted(maps.Keys(m.comments)) {
+		if c := m.comments[id]; c.Status == status {
+			result = append(result, c)
+		}
+	}
+	result = result[min(offset, len(result)):]
+	return result[:min(limit, len(result))], nil
+}
+
+func (m *MockCommentService) ModerateComment(ctx context.Context, id, adminID int, status comment.Status) (*comment.Comment, error) {
+	c, exists := m.comments[id]
+	if !exists {
+		return nil, comment.ErrCommentNotFound
+	}
+	if err := c.Moderate(status); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func setupCommentTestServer() (*echo.Echo, *handlers.CommentHandler) {
 	e := echo.New()
 	e.Validator = middleware.NewValidator()
@@ -100,7 +282,7 @@ func setupCommentTestServer() (*echo.Echo, *handlers.CommentHandler) {
 	commentService := NewMockCommentService()
 	logger := NewMockLogger()
 	
-	commentHandler := handlers.NewCommentHandler(commentService, logger)
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, logger)
 	
 	return e, commentHandler
 }
@@ -388,3 +570,351 @@ func TestCommentHandler_GetCommentsByPost_Pagination(t *testing.T) {
 	assert.Equal(t, 1, response.Offset)
 	assert.Len(t, response.Comments, 2)
 }
+
+func TestCommentHandler_GetCommentsByPost_Cursor(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	commentService := NewMockCommentService()
+	commentHandler := handlers.NewCommentHandler(commentService, service.PageLimits{}, NewMockLogger())
+	for i := 1; i <= 3; i++ {
+		_, err := commentService.AddComment(context.Background(), 1, 0, "", "Author", fmt.Sprintf("Comment number %d", i))
+		require.NoError(t, err)
+	}
+	
+	list := func(query string) (*httptest.ResponseRecorder, handlers.CommentListResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/1/comments?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/posts/:id/comments")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		require.NoError(t, commentHandler.GetCommentsByPost(c))
+		var response handlers.CommentListResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+	
+	rec, first := list("cursor=&limit=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, first.Comments, 2)
+	assert.Equal(t, "Comment number 1", first.Comments[0].Content)
+	assert.NotEmpty(t, first.NextCursor)
+	
+	rec, second := list("cursor=" + first.NextCursor + "&limit=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, second.Comments, 1)
+	assert.Equal(t, "Comment number 3", second.Comments[0].Content)
+	assert.Empty(t, second.NextCursor)
+	
+	rec, _ = list("cursor=garbage!")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCommentHandler_Threads(t *testing.T) {
+	e, commentHandler := setupCommentTestServer()
+
+	create := func(t *testing.T, postID string, parentID *int) *httptest.ResponseRecorder {
+		t.Helper()
+		reqBody, err := json.Marshal(handlers.CreateCommentRequest{AuthorName: "Author", Content: "A comment in a thread", ParentID: parentID})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/posts/"+postID+"/comments", bytes.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/posts/:id/comments")
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, commentHandler.CreateComment(c))
+		return rec
+	}
+	list := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/1/comments?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/posts/:id/comments")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		require.NoError(t, commentHandler.GetCommentsByPost(c))
+		return rec
+	}
+
+	rootID, replyID := 1, 2
+	require.Equal(t, http.StatusCreated, create(t, "1", nil).Code)
+	rec := create(t, "1", &rootID)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var reply handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reply))
+	require.NotNil(t, reply.ParentID)
+	assert.Equal(t, rootID, *reply.ParentID)
+	require.Equal(t, http.StatusCreated, create(t, "1", &replyID).Code)
+	require.Equal(t, http.StatusCreated, create(t, "1", nil).Code)
+
+	t.Run("invalid parents", func(t *testing.T) {
+		missing := 99
+		assert.Equal(t, http.StatusBadRequest, create(t, "1", &missing).Code)
+		assert.Equal(t, http.StatusBadRequest, create(t, "2", &rootID).Code, "parents must be on the same post")
+	})
+
+	t.Run("flat view lists parent IDs", func(t *testing.T) {
+		rec := list(t, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.CommentListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Comments, 4)
+		assert.Nil(t, response.Comments[0].ParentID)
+		assert.Equal(t, replyID, *response.Comments[2].ParentID)
+	})
+
+	t.Run("threaded view nests replies", func(t *testing.T) {
+		rec := list(t, "view=threaded")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.ThreadedCommentListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Comments, 2)
+		assert.Equal(t, 2, response.Total)
+
+		first := response.Comments[0]
+		assert.Equal(t, rootID, first.ID)
+		assert.Equal(t, 2, first.ReplyCount)
+		require.Len(t, first.Replies, 1)
+		assert.Equal(t, replyID, first.Replies[0].ID)
+		require.Len(t, first.Replies[0].Replies, 1)
+		assert.Equal(t, 3, first.Replies[0].Replies[0].ID)
+		assert.Empty(t, response.Comments[1].Replies)
+	})
+
+	t.Run("threaded view paginates top-level comments", func(t *testing.T) {
+		rec := list(t, "view=threaded&limit=1&offset=1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.ThreadedCommentListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Comments, 1)
+		assert.Equal(t, 4, response.Comments[0].ID)
+	})
+
+	t.Run("invalid views", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, list(t, "view=tree").Code)
+		assert.Equal(t, http.StatusBadRequest, list(t, "view=threaded&cursor=").Code, "threads use offset pagination")
+	})
+}
+
+// modifyComment runs an update or delete of a comment as a signed-in user
+func modifyComment(t *testing.T, e *echo.Echo, handler echo.HandlerFunc, method, id, body string, userID int, role user.Role) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/v1/comments/"+id, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v1/comments/:id")
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	c.Set("user_id", userID)
+	c.Set("user_role", role)
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func setupCommentModerationTest(t *testing.T) (*echo.Echo, *handlers.CommentHandler, *MockCommentService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	commentService := NewMockCommentService()
+	_, err := commentService.AddComment(context.Background(), 1, 1, "", "John Doe", "The original comment")
+	require.NoError(t, err)
+	_, err = commentService.AddComment(context.Background(), 1, 0, "", "John Doe", "A guest comment under the same name")
+	require.NoError(t, err)
+
+	return e, handlers.NewCommentHandler(commentService, service.PageLimits{}, NewMockLogger()), commentService
+}
+
+func TestCommentHandler_CreateComment_SignedIn(t *testing.T) {
+	e, commentHandler := setupCommentTestServer()
+
+	create := func(userID int) handlers.CommentResponse {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/posts/1/comments", strings.NewReader(`{"author_name":"John Doe","content":"A comment on the post"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/posts/:id/comments")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		if userID != 0 {
+			c.Set("user_id", userID) // set by the auth middleware for valid tokens
+		}
+		require.NoError(t, commentHandler.CreateComment(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var response handlers.CommentResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	signedIn := create(7)
+	require.NotNil(t, signedIn.UserID)
+	assert.Equal(t, 7, *signedIn.UserID)
+
+	guest := create(0)
+	assert.Nil(t, guest.UserID)
+}
+
+func TestCommentHandler_HiddenPost(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	commentService := NewMockCommentService()
+	commentService.hiddenPosts[1] = 7 // a draft or private post of user 7
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, NewMockLogger())
+
+	request := func(method, query string, userID int, role user.Role) int {
+		req := httptest.NewRequest(method, "/api/v1/posts/1/comments?"+query, strings.NewReader(`{"author_name":"John Doe","content":"A comment on the post"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/posts/:id/comments")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		if userID != 0 {
+			c.Set("user_id", userID)
+			c.Set("user_role", role)
+		}
+		handler := commentHandler.GetCommentsByPost
+		if method == http.MethodPost {
+			handler = commentHandler.CreateComment
+		}
+		require.NoError(t, handler(c))
+		return rec.Code
+	}
+
+	for _, caller := range []struct {
+		name   string
+		userID int
+	}{{"guest", 0}, {"another user", 8}} {
+		t.Run(caller.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusNotFound, request(http.MethodPost, "", caller.userID, user.RoleAuthor))
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "", caller.userID, user.RoleAuthor))
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "cursor=", caller.userID, user.RoleAuthor))
+			assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "view=threaded", caller.userID, user.RoleAuthor))
+		})
+	}
+
+	t.Run("author and admin", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, request(http.MethodPost, "", 7, user.RoleAuthor))
+		assert.Equal(t, http.StatusCreated, request(http.MethodPost, "", 9, user.RoleAdmin))
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "", 7, user.RoleAuthor))
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "view=threaded", 9, user.RoleAdmin))
+	})
+}
+
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	e, commentHandler, commentService := setupCommentModerationTest(t)
+
+	rec := modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "1", `{"content":"An edited comment"}`, 1, user.RoleReader)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "An edited comment", response.Content)
+	assert.Equal(t, "John Doe", response.AuthorName)
+
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "1", `{"content":"Not my comment"}`, 2, user.RoleReader)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "An edited comment", commentService.comments[1].Content)
+
+	// Matching author names do not make a guest comment yours
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "2", `{"content":"Claiming a guest comment"}`, 1, user.RoleReader)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "2", `{"content":"Moderated by an admin"}`, 2, user.RoleAdmin)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "1", `{"content":"<b>x</b>"}`, 1, user.RoleReader)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "abc", `{"content":"An edited comment"}`, 1, user.RoleReader)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.UpdateComment, http.MethodPut, "99", `{"content":"An edited comment"}`, 1, user.RoleReader)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCommentHandler_DeleteComment(t *testing.T) {
+	e, commentHandler, commentService := setupCommentModerationTest(t)
+
+	rec := modifyComment(t, e, commentHandler.DeleteComment, http.MethodDelete, "1", "", 2, user.RoleReader)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, commentService.comments, 1)
+
+	rec = modifyComment(t, e, commentHandler.DeleteComment, http.MethodDelete, "1", "", 1, user.RoleReader)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.NotContains(t, commentService.comments, 1)
+
+	rec = modifyComment(t, e, commentHandler.DeleteComment, http.MethodDelete, "1", "", 1, user.RoleReader)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.DeleteComment, http.MethodDelete, "2", "", 1, user.RoleReader)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = modifyComment(t, e, commentHandler.DeleteComment, http.MethodDelete, "2", "", 2, user.RoleAdmin)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestCommentHandler_ModerationQueue(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	commentService := NewMockCommentService()
+	commentService.requireModeration = true
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, NewMockLogger())
+	for _, content := range []string{"Waiting for review", "Buy cheap watches", "Off topic"} {
+		_, err := commentService.AddComment(context.Background(), 1, 0, "", "Guest", content)
+		require.NoError(t, err)
+	}
+
+	listQueue := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/comments"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, commentHandler.ListModerationQueue(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := listQueue("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var queue handlers.CommentListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(t, queue.Comments, 3)
+	assert.Equal(t, string(comment.StatusPending), queue.Comments[0].Status)
+
+	comments, err := commentService.GetCommentsByPost(context.Background(), 1, 0, "", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, comments, "pending comments are not public")
+
+	rec = modifyComment(t, e, commentHandler.ApproveComment, http.MethodPost, "1", "", 9, user.RoleAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var approved handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &approved))
+	assert.Equal(t, string(comment.StatusApproved), approved.Status)
+
+	rec = modifyComment(t, e, commentHandler.RejectComment, http.MethodPost, "2", `{"spam":true}`, 9, user.RoleAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, comment.StatusSpam, commentService.comments[2].Status)
+
+	rec = modifyComment(t, e, commentHandler.RejectComment, http.MethodPost, "3", "", 9, user.RoleAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, comment.StatusRejected, commentService.comments[3].Status)
+
+	comments, err = commentService.GetCommentsByPost(context.Background(), 1, 0, "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "Waiting for review", comments[0].Content)
+
+	rec = listQueue("?status=spam")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(t, queue.Comments, 1)
+	assert.Equal(t, "Buy cheap watches", queue.Comments[0].Content)
+
+	rec = listQueue("?status=hidden")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = modifyComment(t, e, commentHandler.ApproveComment, http.MethodPost, "99", "", 9, user.RoleAdmin)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
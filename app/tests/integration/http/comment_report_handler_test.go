@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockCommentReportService implements comment.ReportService for testing;
+// comment 1 exists
+type MockCommentReportService struct {
+	reports map[int][]*comment.Report
+}
+
+// NewMockCommentReportService creates a new mock report service
+func NewMockCommentReportService() *MockCommentReportService {
+	return &MockCommentReportService{reports: make(map[int][]*comment.Report)}
+}
+
+func (m *MockCommentReportService) ReportComment(ctx context.Context, commentID, reporterID int, clientIP string, reason comment.ReportReason, details string) (*comment.Report, error) {
+	r, err := comment.NewReport(commentID, reporterID, clientIP, reason, details)
+	if err != nil {
+		return nil, err
+	}
+	if commentID != 1 {
+		return nil, comment.ErrCommentNotFound
+	}
+	for _, existing := range m.reports[commentID] {
+		if existing.ReporterKey == r.ReporterKey {
+			return r, nil
+		}
+	}
+	r.ID = len(m.reports[commentID]) + 1
+	m.reports[commentID] = append(m.reports[commentID], r)
+	return r, nil
+}
+
+func (m *MockCommentReportService) ListReports(ctx context.Context, commentID int) ([]*comment.Report, error) {
+	if commentID != 1 {
+		return nil, comment.ErrCommentNotFound
+	}
+	return m.reports[commentID], nil
+}
+
+func setupCommentReportTestServer() (*echo.Echo, *MockCommentReportService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	reportService := NewMockCommentReportService()
+	h := handlers.NewCommentReportHandler(reportService, NewMockLogger())
+
+	e.POST("/api/v1/comments/:id/report", h.ReportComment, fixtures.AsUser)
+	e.GET("/api/v1/admin/comments/:id/reports", h.ListReports)
+
+	return e, reportService
+}
+
+func reportRequest(e *echo.Echo, path, body, userID, clientIP string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	req.RemoteAddr = clientIP + ":1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCommentReportHandler_ReportComment(t *testing.T) {
+	e, reportService := setupCommentReportTestServer()
+
+	rec := reportRequest(e, "/api/v1/comments/1/report", `{"reason":"spam","details":"Links to a shop"}`, "7", "192.0.2.1")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var response handlers.CommentReportResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.CommentID)
+	assert.Equal(t, "spam", response.Reason)
+	require.NotNil(t, response.ReporterID)
+	assert.Equal(t, 7, *response.ReporterID)
+
+	// Guests report too, each address counting once
+	rec = reportRequest(e, "/api/v1/comments/1/report", `{"reason":"abuse"}`, "", "192.0.2.2")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	rec = reportRequest(e, "/api/v1/comments/1/report", `{"reason":"other"}`, "", "192.0.2.2")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, reportService.reports[1], 2)
+
+	rec = reportRequest(e, "/api/v1/comments/1/report", `{"reason":"boring"}`, "7", "192.0.2.1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = reportRequest(e, "/api/v1/comments/1/report", `{}`, "7", "192.0.2.1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = reportRequest(e, "/api/v1/comments/2/report", `{"reason":"spam"}`, "7", "192.0.2.1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = reportRequest(e, "/api/v1/comments/abc/report", `{"reason":"spam"}`, "7", "192.0.2.1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCommentReportHandler_ListReports(t *testing.T) {
+	e, _ := setupCommentReportTestServer()
+	reportRequest(e, "/api/v1/comments/1/report", `{"reason":"spam"}`, "7", "192.0.2.1")
+	reportRequest(e, "/api/v1/comments/1/report", `{"reason":"abuse"}`, "", "192.0.2.2")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/comments/1/reports", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.CommentReportListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Total)
+	assert.Equal(t, "spam", response.Reports[0].Reason)
+	assert.Nil(t, response.Reports[1].ReporterID, "guest reports have no reporter")
+	assert.NotContains(t, rec.Body.String(), "reporter_key")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/comments/2/reports", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
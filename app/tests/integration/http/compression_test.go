@@ -1,6 +1,7 @@
 package http
 
 import (
+	"compress/flate"
 	"compress/gzip"
 	"io"
 	"net/http"
@@ -8,8 +9,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"blog-platform/internal/infrastructure/config"
 	"blog-platform/internal/infrastructure/http/middleware"
@@ -117,17 +120,9 @@ func TestCompressionMiddleware_SmallResponse(t *testing.T) {
 	e.ServeHTTP(rec, req)
 	
 	assert.Equal(t, http.StatusOK, rec.Code)
-	// Echo's gzip middleware compresses all responses when Accept-Encoding is present
-	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
-	
-	// Verify the response is compressed
-	reader, err := gzip.NewReader(rec.Body)
-	assert.NoError(t, err)
-	defer reader.Close()
-	
-	decompressed, err := io.ReadAll(reader)
-	assert.NoError(t, err)
-	assert.Equal(t, "Small response", string(decompressed))
+	// Responses shorter than MinLength are sent as they are
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Small response", rec.Body.String())
 }
 
 func TestCompressionMiddleware_NoAcceptEncoding(t *testing.T) {
@@ -161,3 +156,91 @@ func TestCompressionMiddleware_NoAcceptEncoding(t *testing.T) {
 	assert.Empty(t, rec.Header().Get("Content-Encoding"))
 	assert.Contains(t, rec.Body.String(), "This is a test response.")
 }
+
+func TestCompressionMiddleware_Negotiation(t *testing.T) {
+	e := echo.New()
+	largeResponse := strings.Repeat("This is a test response that should be compressed. ", 50)
+	e.Use(middleware.CompressionDefault())
+	e.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, largeResponse)
+	})
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{"brotli preferred on ties", "gzip, deflate, br", "br"},
+		{"gzip preferred to deflate", "deflate, gzip", "gzip"},
+		{"gzip with higher quality", "br;q=0.8, gzip", "gzip"},
+		{"deflate with higher quality", "gzip;q=0.5, deflate", "deflate"},
+		{"refused coding", "gzip;q=0, deflate;q=0.1", "deflate"},
+		{"wildcard", "*", "br"},
+		{"unsupported coding", "zstd", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expected, rec.Header().Get("Content-Encoding"))
+
+			var body io.Reader = rec.Body
+			switch tt.expected {
+			case "br":
+				body = brotli.NewReader(rec.Body)
+			case "gzip":
+				reader, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				body = reader
+			case "deflate":
+				body = flate.NewReader(rec.Body)
+			}
+			decompressed, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, largeResponse, string(decompressed))
+		})
+	}
+}
+
+func TestCompressionMiddleware_ContentTypes(t *testing.T) {
+	e := echo.New()
+	compression := middleware.DefaultCompressionConfig()
+	compression.ContentTypes = []string{"application/json"}
+	e.Use(middleware.CompressionWithConfig(compression))
+	payload := strings.Repeat("a", 2048)
+	e.GET("/json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"payload": payload})
+	})
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, payload)
+	})
+	e.GET("/error", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "missing")
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/json")
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+
+	rec = get("/text")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, payload, rec.Body.String())
+
+	// Errors returned by handlers are written after the middleware
+	rec = get("/error")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+}
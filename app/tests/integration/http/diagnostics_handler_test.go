@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/diagnostics"
+	"blog-platform/internal/infrastructure/feed"
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+func TestDiagnosticsHandler_GetDiagnostics(t *testing.T) {
+	postService := NewMockPostService()
+	generator := feed.NewGenerator(postService, NewMockCommentService(), NewMockUserService(), NewMockPreferenceService(), feed.Settings{CacheTTL: time.Minute})
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hello Feeds", "Content of the post")
+	require.NoError(t, err)
+	for range 4 {
+		_, err := generator.PostComments(ctx, p.ID)
+		require.NoError(t, err)
+	}
+
+	collector := diagnostics.NewCollector()
+	collector.Register("feed_cache", func(ctx context.Context) (any, error) {
+		return generator.CacheStats(), nil
+	})
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(collector, NewMockLogger())
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/diagnostics", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, diagnosticsHandler.GetDiagnostics(e.NewContext(req, rec)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+
+	var report struct {
+		Runtime    diagnostics.Runtime `json:"runtime"`
+		Subsystems struct {
+			FeedCache feed.CacheStats `json:"feed_cache"`
+		} `json:"subsystems"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Positive(t, report.Runtime.Goroutines)
+	assert.Equal(t, feed.CacheStats{Enabled: true, Entries: 1, Hits: 3, Misses: 1, HitRate: 0.75}, report.Subsystems.FeedCache)
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/announcement"
+	"blog-platform/internal/domain/audit"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/banner"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
+	infraauth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/diagnostics"
+	"blog-platform/internal/infrastructure/health"
+	apphttp "blog-platform/internal/infrastructure/http"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/ratelimit"
+)
+
+const testOpenAPIDocument = `{
+    "openapi": "3.0.3",
+    "paths": {
+        "/api/v1/posts/{id}": {
+            "get": {
+                "summary": "Get a blog post by ID",
+                "responses": {
+                    "200": {"description": "OK"}
+                }
+            }
+        }
+    }
+}`
+
+func newTestRouteRegistry() *handlers.RouteRegistry {
+	registry := handlers.NewRouteRegistry()
+	registry.Register(
+		handlers.RouteDoc{
+			Method:          http.MethodGet,
+			Path:            "/api/v1/posts/:id",
+			Summary:         "Get a blog post by ID",
+			ResponseStatus:  http.StatusOK,
+			ResponseExample: handlers.PostResponse{ID: 1, Title: "Example"},
+			Errors:          []errors.ErrorCode{errors.ErrCodeNotFound},
+		},
+		handlers.RouteDoc{
+			Method:         http.MethodPost,
+			Path:           "/api/v1/auth/refresh",
+			Summary:        "Refresh access token",
+			RequestExample: handlers.RefreshRequest{RefreshToken: "token"},
+			ResponseStatus: http.StatusOK,
+			Errors:         []errors.ErrorCode{errors.ErrCodeUnauthorized},
+		},
+	)
+	return registry
+}
+
+func TestDocsHandler_ListErrors(t *testing.T) {
+	e := echo.New()
+	docsHandler := handlers.NewDocsHandler(newTestRouteRegistry(), nil, NewMockLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/errors", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, docsHandler.ListErrors(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response handlers.ErrorCatalogResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	assert.Len(t, response.Errors, len(errors.Catalog()))
+	require.Len(t, response.Routes, 2)
+	assert.Equal(t, "/api/v1/auth/refresh", response.Routes[0].Path)
+	assert.Equal(t, "/api/v1/posts/{id}", response.Routes[1].Path)
+	assert.Equal(t, []errors.ErrorCode{errors.ErrCodeNotFound}, response.Routes[1].Errors)
+}
+
+func TestDocsHandler_OpenAPI(t *testing.T) {
+	e := echo.New()
+	docsHandler := handlers.NewDocsHandler(newTestRouteRegistry(), []byte(testOpenAPIDocument), NewMockLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, docsHandler.OpenAPI(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			ErrorCodes  []string `json:"x-error-codes"`
+			RequestBody struct {
+				Content map[string]struct {
+					Example map[string]any `json:"example"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+	// Existing operations keep their path and gain examples and error codes
+	getPost := doc.Paths["/api/v1/posts/{id}"]["get"]
+	assert.Equal(t, []string{"not_found"}, getPost.ErrorCodes)
+	assert.Contains(t, string(getPost.Responses["200"]), `"Example"`)
+	assert.Contains(t, string(getPost.Responses["404"]), "not_found")
+	assert.Contains(t, string(getPost.Responses["404"]), "#/components/schemas/handlers.ErrorResponse")
+
+	// Undocumented operations are added with a JSON request example
+	refresh := doc.Paths["/api/v1/auth/refresh"]["post"]
+	assert.Equal(t, []string{"unauthorized"}, refresh.ErrorCodes)
+	assert.Equal(t, "token", refresh.RequestBody.Content["application/json"].Example["refresh_token"])
+	assert.Contains(t, refresh.Responses, "401")
+}
+
+// setupAllRoutes registers every route, with the optional features turned
+// on, on a new server
+func setupAllRoutes(swaggerEnabled bool) *echo.Echo {
+	e := echo.New()
+	// Route setup never calls the user, auth, tag, preference, announcement, banner, audit or security services
+	var userService struct{ user.Service }
+	var authService struct{ auth.AuthService }
+	var tagService struct{ tag.Service }
+	var preferenceService struct{ preference.Service }
+	var announcementService struct{ announcement.Service }
+	var bannerService struct{ banner.Service }
+	var auditService struct{ audit.Service }
+	var securityService struct{ user.SecurityService }
+	cfg := &config.Config{
+		RateLimit:   config.RateLimitConfig{DefaultRequestsPerSecond: 100, DefaultBurstSize: 100},
+		SSO:         config.SSOConfig{Enabled: true},
+		Billing:     config.BillingConfig{Enabled: true},
+		Tips:        config.TipsConfig{Enabled: true},
+		Widget:      config.WidgetConfig{Enabled: true},
+		MagicLink:   config.MagicLinkConfig{Enabled: true},
+		WebAuthn:    config.WebAuthnConfig{Enabled: true},
+		ReadingView: config.ReadingViewConfig{Enabled: true, Theme: "default"},
+		Docs:        config.DocsConfig{SwaggerEnabled: swaggerEnabled},
+	}
+	apphttp.SetupRoutes(e, cfg, service.DefaultPaginationPolicy(), userService, authService, nil, nil, nil, NewMockPostService(), NewMockProgressService(), NewMockBookmarkService(), tagService, NewMockSeriesService(), preferenceService, NewMockCommentService(), NewMockCommentReportService(), NewMockNotificationService(), nil, NewMockFollowService(), NewMockProfileService(), NewMockAvatarService(), NewMockMediaService(), struct{ export.Service }{}, NewMockRedirectService(NewMockPostService()), NewMockSyncService(), nil, announcementService, nil, bannerService, nil, auditService, securityService, nil, nil, nil, nil, infraauth.JWKSet{}, ratelimit.NewMemoryStore(), health.NewChecker(), diagnostics.NewCollector(), NewMockLogger())
+	return e
+}
+
+func TestSetupRoutes_AllAPIRoutesDocumented(t *testing.T) {
+	e := setupAllRoutes(false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/errors", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response handlers.ErrorCatalogResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	documented := make(map[string]bool)
+	for _, route := range response.Routes {
+		documented[route.Method+" "+route.Path] = true
+		for _, code := range route.Errors {
+			_, ok := errors.LookupErrorCode(code)
+			assert.True(t, ok, "route %s %s lists unknown error code %s", route.Method, route.Path, code)
+		}
+	}
+
+	for _, route := range e.Routes() {
+		if route.Method == echo.RouteNotFound || !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		key := route.Method + " " + handlers.OpenAPIPath(route.Path)
+		assert.True(t, documented[key], "route %s is missing from the route registry", key)
+	}
+}
+
+func TestSetupRoutes_ServesOpenAPIDocument(t *testing.T) {
+	e := setupAllRoutes(true)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	// Every annotated operation is served; a wrong @Router shows up here
+	routes := make(map[string]bool)
+	for _, route := range e.Routes() {
+		routes[route.Method+" "+handlers.OpenAPIPath(route.Path)] = true
+	}
+	for path, item := range doc.Paths {
+		for method := range item {
+			key := strings.ToUpper(method) + " " + path
+			assert.True(t, routes[key], "documented operation %s is not routed", key)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Swagger can be turned off
+	req = httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	rec = httptest.NewRecorder()
+	setupAllRoutes(false).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,251 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/importer"
+	"blog-platform/tests/fixtures"
+)
+
+// MockExportService implements the export.Service interface for testing
+type MockExportService struct {
+	users    map[int]*user.User
+	posts    []*post.Post
+	comments []*comment.Comment
+}
+
+func (m *MockExportService) Export(ctx context.Context, userID int) (*export.Archive, error) {
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	archive := &export.Archive{ExportedAt: fixtures.Time, Account: u}
+	for _, p := range m.posts {
+		if p.AuthorID == userID {
+			archive.Posts = append(archive.Posts, p)
+		}
+	}
+	for _, c := range m.comments {
+		if c.UserID != nil && *c.UserID == userID {
+			archive.Comments = append(archive.Comments, c)
+		}
+	}
+	return archive, nil
+}
+
+func (m *MockExportService) ExportSite(ctx context.Context, adminID int, filter export.SiteFilter, write func(*export.Entry) error) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if _, ok := m.users[filter.AuthorID]; filter.AuthorID != 0 && !ok {
+		return user.ErrUserNotFound
+	}
+	for _, p := range m.posts {
+		if filter.AuthorID != 0 && p.AuthorID != filter.AuthorID {
+			continue
+		}
+		entry := &export.Entry{Post: p, Author: m.users[p.AuthorID]}
+		for _, c := range m.comments {
+			if c.PostID == p.ID {
+				entry.Comments = append(entry.Comments, c)
+			}
+		}
+		if err := write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupExportTestServer() *echo.Echo {
+	e := echo.New()
+	userID := 1
+	c := fixtures.Comment(1, 2)
+	c.UserID = &userID
+	exportService := &MockExportService{
+		users:    map[int]*user.User{1: fixtures.User(1)},
+		posts:    []*post.Post{fixtures.Post(1, 1), fixtures.Draft(2, 1), fixtures.Post(3, 2)},
+		comments: []*comment.Comment{c, fixtures.Comment(2, 2)},
+	}
+	exportHandler := handlers.NewExportHandler(exportService, NewMockLogger())
+
+	e.GET("/api/v1/users/me/export", exportHandler.Export, fixtures.AsUser)
+	e.GET("/api/v1/admin/export", exportHandler.ExportSite, fixtures.AsUser)
+	return e
+}
+
+func siteExportRequest(e *echo.Echo, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/export"+query, nil)
+	req.Header.Set(fixtures.UserHeader, "1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func exportRequest(e *echo.Echo, query, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/export"+query, nil)
+	if userID != "" {
+		req.Header.Set(fixtures.UserHeader, userID)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportHandler_JSON(t *testing.T) {
+	e := setupExportTestServer()
+
+	rec := exportRequest(e, "", "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `attachment; filename="export-1-`)
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+
+	var response handlers.ExportResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Account.ID)
+	assert.NotEmpty(t, response.Account.Email)
+	require.Len(t, response.Posts, 2)
+	assert.Equal(t, "draft", response.Posts[1].Status)
+	require.Len(t, response.Comments, 1)
+	assert.Equal(t, 1, response.Comments[0].ID)
+}
+
+func TestExportHandler_ZIP(t *testing.T) {
+	e := setupExportTestServer()
+
+	rec := exportRequest(e, "?format=zip", "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+	}
+	require.Contains(t, files, "account.json")
+	require.Contains(t, files, "posts.json")
+	require.Contains(t, files, "comments.json")
+
+	var posts []handlers.PostResponse
+	require.NoError(t, json.Unmarshal(files["posts.json"], &posts))
+	assert.Len(t, posts, 2)
+	markdown, ok := files["posts/"+strconv.Itoa(posts[0].ID)+"-"+posts[0].Slug+".md"]
+	require.True(t, ok, "expected a Markdown file per post")
+	assert.Contains(t, string(markdown), "# "+posts[0].Title)
+}
+
+func TestExportHandler_Errors(t *testing.T) {
+	e := setupExportTestServer()
+
+	rec := exportRequest(e, "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = exportRequest(e, "?format=csv", "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = exportRequest(e, "", "9")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportHandler_SiteNDJSON(t *testing.T) {
+	e := setupExportTestServer()
+
+	rec := siteExportRequest(e, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, handlers.MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `attachment; filename="site-export-`)
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 3, "one post per line")
+	var first, draft, last handlers.SiteExportPost
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &draft))
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &last))
+	assert.Equal(t, 1, first.Post.ID)
+	require.NotNil(t, first.Author)
+	assert.Equal(t, "user1@example.com", first.Author.Email)
+	assert.Empty(t, first.Comments)
+	assert.Equal(t, "draft", draft.Post.Status)
+	require.Len(t, draft.Comments, 2)
+	assert.Equal(t, 2, draft.Comments[1].ID)
+	assert.Nil(t, last.Author, "the author of post 3 is gone")
+
+	rec = siteExportRequest(e, "?author_id=1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), "\n"))
+}
+
+func TestExportHandler_SiteZIP(t *testing.T) {
+	e := setupExportTestServer()
+
+	rec := siteExportRequest(e, "?format=zip")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"posts/post-1.md", "posts/post-2.md", "comments/post-2.json", "posts/post-3.md"}, names)
+
+	// The archive reads back as an import
+	doc, err := importer.NewParser().Parse(rec.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, doc.Posts, 3)
+	assert.Equal(t, "Post 1", doc.Posts[0].Title)
+	assert.Equal(t, "post-1", doc.Posts[0].Slug)
+	assert.Equal(t, "This is the content of post 1.", doc.Posts[0].Content)
+	assert.Equal(t, "user1@example.com", doc.Posts[0].AuthorLogin)
+	require.NotNil(t, doc.Posts[0].PublishedAt)
+	assert.True(t, doc.Posts[0].PublishedAt.Equal(*fixtures.Post(1, 1).PublishedAt))
+	assert.True(t, doc.Posts[1].Draft)
+}
+
+func TestExportHandler_SiteErrors(t *testing.T) {
+	e := setupExportTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/export", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	for _, query := range []string{"?format=json", "?author_id=abc", "?from=yesterday"} {
+		rec = siteExportRequest(e, query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	rec = siteExportRequest(e, "?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "validation_error")
+
+	rec = siteExportRequest(e, "?author_id=9")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition), "errors before the first post are not downloads")
+}
@@ -0,0 +1,507 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/infrastructure/feed"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockPreferenceService is an in-memory preference.Service
+type MockPreferenceService struct {
+	feeds map[string]*preference.Feed
+}
+
+func NewMockPreferenceService() *MockPreferenceService {
+	return &MockPreferenceService{feeds: make(map[string]*preference.Feed)}
+}
+
+func (m *MockPreferenceService) AuthorFeed(ctx context.Context, authorID int) (*preference.Feed, error) {
+	return m.feed(preference.ScopeAuthor, preference.AuthorKey(authorID)), nil
+}
+
+func (m *MockPreferenceService) TagFeed(ctx context.Context, name string) (*preference.Feed, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.feed(preference.ScopeTag, normalized), nil
+}
+
+func (m *MockPreferenceService) SetAuthorFeed(ctx context.Context, userID int, title, description string) (*preference.Feed, error) {
+	return m.save(preference.ScopeAuthor, preference.AuthorKey(userID), title, description, userID)
+}
+
+func (m *MockPreferenceService) SetTagFeed(ctx context.Context, adminID int, name, title, description string) (*preference.Feed, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.save(preference.ScopeTag, normalized, title, description, adminID)
+}
+
+func (m *MockPreferenceService) feed(scope, key string) *preference.Feed {
+	if f, ok := m.feeds[scope+":"+key]; ok {
+		return f
+	}
+	return &preference.Feed{Scope: scope, Key: key}
+}
+
+func (m *MockPreferenceService) save(scope, key, title, description string, userID int) (*preference.Feed, error) {
+	f, err := preference.NewFeed(scope, key, title, description, userID)
+	if err != nil {
+		return nil, err
+	}
+	m.feeds[scope+":"+key] = f
+	return f, nil
+}
+
+type testFeed struct {
+	Channel struct {
+		Title       string `xml:"title"`
+		Description string `xml:"description"`
+		AtomLink    struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.w3.org/2005/Atom link"`
+		Items []struct {
+			Title      string   `xml:"title"`
+			Link       string   `xml:"link"`
+			Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Categories []string `xml:"category"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type feedTestServer struct {
+	posts       *MockPostService
+	comments    *MockCommentService
+	users       *MockUserService
+	preferences *MockPreferenceService
+	handler     *handlers.FeedHandler
+}
+
+func setupFeedTestServer(t *testing.T, settings feed.Settings) (*echo.Echo, *MockPostService, *MockCommentService, *MockUserService) {
+	e, server := setupPostFeedTestServer(t, settings)
+	return e, server.posts, server.comments, server.users
+}
+
+func setupPostFeedTestServer(t *testing.T, settings feed.Settings) (*echo.Echo, *feedTestServer) {
+	server := &feedTestServer{
+		posts:       NewMockPostService(),
+		comments:    NewMockCommentService(),
+		users:       NewMockUserService(),
+		preferences: NewMockPreferenceService(),
+	}
+
+	settings.SiteName = "Test Blog"
+	settings.BaseURL = "https://blog.example.com"
+	generator := feed.NewGenerator(server.posts, server.comments, server.users, server.preferences, settings)
+	server.handler = handlers.NewFeedHandler(generator, server.preferences, NewMockLogger())
+
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	e.GET("/feed.xml", server.handler.Posts)
+	e.GET("/feeds/posts.rss", server.handler.Posts)
+	e.GET("/feeds/posts.atom", server.handler.PostsAtom)
+	e.GET("/feeds/authors/:feed", server.handler.AuthorFeed)
+	e.GET("/api/v1/users/:id/feed.xml", server.handler.AuthorPosts)
+	e.GET("/api/v1/tags/:name/feed.xml", server.handler.TagPosts)
+	e.GET("/api/v1/posts/:id/comments/feed.xml", server.handler.PostComments)
+	e.GET("/api/v1/users/:id/comments/feed.xml", server.handler.AuthorComments)
+	return e, server
+}
+
+func getFeed(t *testing.T, e *echo.Echo, path string) (*httptest.ResponseRecorder, testFeed) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var doc testFeed
+	if rec.Code == http.StatusOK {
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	}
+	return rec, doc
+}
+
+func TestFeedHandler_PostComments(t *testing.T) {
+	e, postService, commentService, _ := setupFeedTestServer(t, feed.Settings{ItemLimit: 2, CacheTTL: time.Minute})
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hello Feeds", "Content of the post")
+	require.NoError(t, err)
+	for _, content := range []string{"First!", "Second comment", "Third comment"} {
+		_, err := commentService.AddComment(ctx, p.ID, 0, "", "Jane Smith", content)
+		require.NoError(t, err)
+	}
+
+	rec, doc := getFeed(t, e, "/api/v1/posts/1/comments/feed.xml")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, feed.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Equal(t, "Comments on Hello Feeds", doc.Channel.Title)
+	require.Len(t, doc.Channel.Items, 2, "feeds are capped at the item limit")
+	assert.Equal(t, "Jane Smith on Hello Feeds: Third comment", doc.Channel.Items[0].Title)
+	assert.Equal(t, "https://blog.example.com/p/hello-feeds#comment-3", doc.Channel.Items[0].Link)
+	assert.Equal(t, "Jane Smith", doc.Channel.Items[0].Creator)
+}
+
+func TestFeedHandler_PostComments_Cached(t *testing.T) {
+	e, postService, commentService, _ := setupFeedTestServer(t, feed.Settings{CacheTTL: time.Minute})
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hello Feeds", "Content of the post")
+	require.NoError(t, err)
+	_, err = commentService.AddComment(ctx, p.ID, 0, "", "Jane Smith", "First!")
+	require.NoError(t, err)
+
+	_, doc := getFeed(t, e, "/api/v1/posts/1/comments/feed.xml")
+	require.Len(t, doc.Channel.Items, 1)
+
+	_, err = commentService.AddComment(ctx, p.ID, 0, "", "John Doe", "Second!")
+	require.NoError(t, err)
+
+	_, doc = getFeed(t, e, "/api/v1/posts/1/comments/feed.xml")
+	assert.Len(t, doc.Channel.Items, 1, "cached feed is served until it expires")
+}
+
+func TestFeedHandler_PostComments_Errors(t *testing.T) {
+	e, postService, _, _ := setupFeedTestServer(t, feed.Settings{})
+
+	rec, _ := getFeed(t, e, "/api/v1/posts/abc/comments/feed.xml")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = getFeed(t, e, "/api/v1/posts/99/comments/feed.xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Drafts and private posts have no comment feed
+	postService.posts[2] = fixtures.Draft(2, 1)
+	rec, _ = getFeed(t, e, "/api/v1/posts/2/comments/feed.xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	postService.posts[3] = fixtures.Post(3, 1)
+	postService.posts[3].Visibility = post.VisibilityPrivate
+	rec, _ = getFeed(t, e, "/api/v1/posts/3/comments/feed.xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_AuthorComments(t *testing.T) {
+	e, postService, commentService, userService := setupFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	author, err := userService.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	first, err := postService.CreatePost(ctx, author.ID, "First Post", "Content of the first post")
+	require.NoError(t, err)
+	second, err := postService.CreatePost(ctx, author.ID, "Second Post", "Content of the second post")
+	require.NoError(t, err)
+	other, err := postService.CreatePost(ctx, author.ID+1, "Other Post", "Content of another author's post")
+	require.NoError(t, err)
+	for _, p := range []int{first.ID, second.ID, other.ID} {
+		commentService.postAuthors[p] = postService.posts[p].AuthorID
+		_, err := commentService.AddComment(ctx, p, 0, "", "Jane Smith", "Nice post")
+		require.NoError(t, err)
+	}
+
+	rec, doc := getFeed(t, e, "/api/v1/users/1/comments/feed.xml")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderCacheControl), "uncached feeds are not cacheable")
+	assert.Equal(t, "Comments on posts by Ada Author", doc.Channel.Title)
+	require.Len(t, doc.Channel.Items, 2)
+	assert.Equal(t, "https://blog.example.com/p/second-post#comment-2", doc.Channel.Items[0].Link)
+	assert.Equal(t, "https://blog.example.com/p/first-post#comment-1", doc.Channel.Items[1].Link)
+
+	rec, _ = getFeed(t, e, "/api/v1/users/42/comments/feed.xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_Posts(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	bob, err := server.users.Register(ctx, "Bob Writer", "bob@example.com", "password123")
+	require.NoError(t, err)
+
+	first, err := server.posts.CreatePost(ctx, ada.ID, "Go Generics", "Content about generics")
+	require.NoError(t, err)
+	_, err = server.posts.SetTags(ctx, ada.ID, "", first.ID, []string{"Go", "types"})
+	require.NoError(t, err)
+	second, err := server.posts.CreatePost(ctx, bob.ID, "Go Modules", "Content about modules")
+	require.NoError(t, err)
+	_, err = server.posts.SetTags(ctx, bob.ID, "", second.ID, []string{"go"})
+	require.NoError(t, err)
+	_, err = server.posts.CreatePost(ctx, ada.ID, "Rust Notes", "Content about Rust")
+	require.NoError(t, err)
+	_, err = server.posts.CreateDraft(ctx, ada.ID, "Go Draft", "Unpublished content")
+	require.NoError(t, err)
+
+	rec, doc := getFeed(t, e, "/feed.xml")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Test Blog", doc.Channel.Title)
+	assert.Equal(t, "https://blog.example.com/feed.xml", doc.Channel.AtomLink.Href)
+	require.Len(t, doc.Channel.Items, 3, "drafts are left out")
+	assert.Equal(t, "Rust Notes", doc.Channel.Items[0].Title)
+	assert.Equal(t, "https://blog.example.com/p/go-modules", doc.Channel.Items[1].Link)
+	assert.Equal(t, "Bob Writer", doc.Channel.Items[1].Creator)
+	assert.Equal(t, []string{"go", "types"}, doc.Channel.Items[2].Categories)
+
+	_, doc = getFeed(t, e, "/feed.xml?tag=Go")
+	assert.Equal(t, "Posts tagged go", doc.Channel.Title)
+	assert.Equal(t, "https://blog.example.com/api/v1/tags/go/feed.xml", doc.Channel.AtomLink.Href, "single filters point to their own feed URL")
+	assert.Len(t, doc.Channel.Items, 2)
+
+	_, doc = getFeed(t, e, "/feed.xml?tag=go&author=1")
+	assert.Equal(t, "Posts tagged go by Ada Author", doc.Channel.Title)
+	assert.Equal(t, "https://blog.example.com/feed.xml?author=1&tag=go", doc.Channel.AtomLink.Href)
+	require.Len(t, doc.Channel.Items, 1)
+	assert.Equal(t, "Go Generics", doc.Channel.Items[0].Title)
+
+	rec, _ = getFeed(t, e, "/feed.xml?author=abc")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = getFeed(t, e, "/feed.xml?tag=%3Cb%3E")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = getFeed(t, e, "/feed.xml?author=42")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFeedHandler_AuthorAndTagPosts(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	p, err := server.posts.CreatePost(ctx, ada.ID, "Go Generics", "Content about generics")
+	require.NoError(t, err)
+	_, err = server.posts.SetTags(ctx, ada.ID, "", p.ID, []string{"go"})
+	require.NoError(t, err)
+
+	rec, doc := getFeed(t, e, "/api/v1/users/1/feed.xml")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Posts by Ada Author", doc.Channel.Title)
+	assert.Equal(t, "The latest posts by Ada Author on Test Blog", doc.Channel.Description)
+	assert.Len(t, doc.Channel.Items, 1)
+
+	rec, doc = getFeed(t, e, "/api/v1/tags/go/feed.xml")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Posts tagged go", doc.Channel.Title)
+
+	rec, _ = getFeed(t, e, "/api/v1/users/abc/feed.xml")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = getFeed(t, e, "/api/v1/users/42/feed.xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+type testAtomFeed struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		ID     string `xml:"id"`
+		Title  string `xml:"title"`
+		Author struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+		Content string `xml:"content"`
+	} `xml:"entry"`
+}
+
+func getAtomFeed(t *testing.T, e *echo.Echo, path string) (*httptest.ResponseRecorder, testAtomFeed) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var doc testAtomFeed
+	if rec.Code == http.StatusOK {
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	}
+	return rec, doc
+}
+
+func TestFeedHandler_AtomAndFeedPaths(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{CacheTTL: time.Minute})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	p, err := server.posts.CreatePost(ctx, ada.ID, "Go Generics", "Content about **generics**")
+	require.NoError(t, err)
+	_, err = server.posts.SetTags(ctx, ada.ID, "", p.ID, []string{"go"})
+	require.NoError(t, err)
+
+	rec, doc := getAtomFeed(t, e, "/feeds/posts.atom")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, feed.AtomContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Equal(t, "https://blog.example.com/feeds/posts.atom", doc.ID)
+	assert.Equal(t, "Test Blog", doc.Title)
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, "https://blog.example.com/p/go-generics", doc.Entries[0].ID)
+	assert.Equal(t, "Ada Author", doc.Entries[0].Author.Name)
+	assert.Equal(t, "go", doc.Entries[0].Categories[0].Term)
+	assert.Contains(t, doc.Entries[0].Content, "<strong>generics</strong>")
+
+	_, doc = getAtomFeed(t, e, "/feeds/posts.atom?tag=go&author=1")
+	assert.Equal(t, "https://blog.example.com/feeds/posts.atom?author=1&tag=go", doc.ID)
+	assert.Equal(t, "Posts tagged go by Ada Author", doc.Title)
+
+	rec, doc = getAtomFeed(t, e, "/feeds/authors/1.atom")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://blog.example.com/feeds/authors/1.atom", doc.ID)
+	assert.Equal(t, "Posts by Ada Author", doc.Title)
+
+	// The RSS paths serve the same documents as the older ones
+	rec, rss := getFeed(t, e, "/feeds/authors/1.rss")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, feed.ContentType, rec.Header().Get(echo.HeaderContentType))
+	_, legacy := getFeed(t, e, "/api/v1/users/1/feed.xml")
+	assert.Equal(t, legacy, rss)
+	rec, rss = getFeed(t, e, "/feeds/posts.rss?tag=go")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Posts tagged go", rss.Channel.Title)
+
+	rec, _ = getFeed(t, e, "/feeds/authors/abc.rss")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = getFeed(t, e, "/feeds/authors/1.json")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = getFeed(t, e, "/feeds/authors/1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = getFeed(t, e, "/feeds/authors/42.atom")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = getFeed(t, e, "/feeds/posts.atom?author=abc")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFeedHandler_ConditionalRequests(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	_, err = server.posts.CreatePost(ctx, ada.ID, "Go Generics", "Content about generics")
+	require.NoError(t, err)
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/feeds/posts.atom", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = get("/feeds/posts.atom", etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.NotEqual(t, etag, get("/feeds/posts.rss", "").Header().Get("ETag"), "formats are tagged apart")
+
+	// A new post changes the feed
+	_, err = server.posts.CreatePost(ctx, ada.ID, "Go Modules", "Content about modules")
+	require.NoError(t, err)
+	rec = get("/feeds/posts.atom", etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestFeedHandler_RestrictedPosts(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	p, err := server.posts.CreatePost(ctx, ada.ID, "Members Post", "The opening paragraph.\n\nThe rest is for members only.")
+	require.NoError(t, err)
+	_, err = server.posts.SetAccess(ctx, ada.ID, "", p.ID, post.AccessMembers)
+	require.NoError(t, err)
+
+	// Feeds are shared documents, so they only carry the excerpt
+	rec, doc := getFeed(t, e, "/feed.xml")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, doc.Channel.Items, 1)
+	assert.Contains(t, rec.Body.String(), "The opening paragraph.")
+	assert.NotContains(t, rec.Body.String(), "for members only")
+}
+
+func TestFeedHandler_FeedPreferences(t *testing.T) {
+	e, server := setupPostFeedTestServer(t, feed.Settings{})
+	ctx := context.Background()
+
+	ada, err := server.users.Register(ctx, "Ada Author", "ada@example.com", "password123")
+	require.NoError(t, err)
+	p, err := server.posts.CreatePost(ctx, ada.ID, "Go Generics", "Content about generics")
+	require.NoError(t, err)
+	_, err = server.posts.SetTags(ctx, ada.ID, "", p.ID, []string{"go"})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(handlers.FeedPreferenceRequest{Title: "Ada on Systems", Description: "  Notes on Go  "})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/users/me/feed", body)
+	require.NoError(t, server.handler.SetAuthorFeed(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response handlers.FeedPreferenceResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, preference.ScopeAuthor, response.Scope)
+	assert.Equal(t, "1", response.Key)
+	assert.Equal(t, "Notes on Go", response.Description)
+
+	body, err = json.Marshal(handlers.FeedPreferenceRequest{Title: "Go on the Blog"})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/admin/tags/Go/feed", body)
+	c.SetParamNames("name")
+	c.SetParamValues("Go")
+	require.NoError(t, server.handler.SetTagFeed(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, doc := getFeed(t, e, "/api/v1/users/1/feed.xml")
+	assert.Equal(t, "Ada on Systems", doc.Channel.Title)
+	assert.Equal(t, "Notes on Go", doc.Channel.Description)
+
+	_, doc = getFeed(t, e, "/feed.xml?author=1")
+	assert.Equal(t, "Ada on Systems", doc.Channel.Title, "filtered and per-author feeds share the renderer")
+
+	_, doc = getFeed(t, e, "/api/v1/tags/go/feed.xml")
+	assert.Equal(t, "Go on the Blog", doc.Channel.Title)
+	assert.Equal(t, "The latest posts tagged go on Test Blog", doc.Channel.Description, "empty fields keep the generated text")
+
+	_, doc = getFeed(t, e, "/feed.xml?tag=go&author=1")
+	assert.Equal(t, "Posts tagged go by Ada Author", doc.Channel.Title, "combined filters keep the generated title")
+
+	// Invalid titles are rejected
+	body, err = json.Marshal(handlers.FeedPreferenceRequest{Title: "<b>Loud</b>"})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/users/me/feed", body)
+	require.NoError(t, server.handler.SetAuthorFeed(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/tests/fixtures"
+)
+
+// MockFollowService implements user.FollowService for testing. Follows are
+// kept in the following map of the post service mock so the feed sees them.
+type MockFollowService struct {
+	names map[int]string
+	posts *MockPostService
+}
+
+func NewMockFollowService() *MockFollowService {
+	posts := NewMockPostService()
+	posts.following = make(map[int][]int)
+	return &MockFollowService{names: make(map[int]string), posts: posts}
+}
+
+func (m *MockFollowService) Follow(ctx context.Context, followerID, followeeID int) error {
+	if followerID == followeeID {
+		return user.ErrCannotFollowSelf
+	}
+	if _, ok := m.names[followeeID]; !ok {
+		return user.ErrUserNotFound
+	}
+	if !slices.Contains(m.posts.following[followerID], followeeID) {
+		m.posts.following[followerID] = append(m.posts.following[followerID], followeeID)
+	}
+	return nil
+}
+
+func (m *MockFollowService) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	m.posts.following[followerID] = slices.DeleteFunc(m.posts.following[followerID], func(id int) bool { return id == followeeID })
+	return nil
+}
+
+func (m *MockFollowService) ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, *user.FollowCounts, error) {
+	if _, ok := m.names[userID]; !ok {
+		return nil, nil, user.ErrUserNotFound
+	}
+	var follows []*user.Follow
+	for follower, followees := range m.posts.following {
+		if slices.Contains(followees, userID) {
+			follows = append(follows, &user.Follow{UserID: follower, Name: m.names[follower], FollowedAt: time.Now()})
+		}
+	}
+	return follows, m.counts(userID), nil
+}
+
+func (m *MockFollowService) ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, *user.FollowCounts, error) {
+	if _, ok := m.names[userID]; !ok {
+		return nil, nil, user.ErrUserNotFound
+	}
+	var follows []*user.Follow
+	for _, followee := range slices.Backward(m.posts.following[userID]) {
+		follows = append(follows, &user.Follow{UserID: followee, Name: m.names[followee], FollowedAt: time.Now()})
+	}
+	return follows, m.counts(userID), nil
+}
+
+func (m *MockFollowService) counts(userID int) *user.FollowCounts {
+	counts := &user.FollowCounts{Following: len(m.posts.following[userID])}
+	for _, followees := range m.posts.following {
+		if slices.Contains(followees, userID) {
+			counts.Followers++
+		}
+	}
+	return counts
+}
+
+func setupFollowTestServer() (*echo.Echo, *MockFollowService) {
+	e := echo.New()
+
+	followService := NewMockFollowService()
+	followService.names = map[int]string{1: "Author One", 2: "Author Two", 3: "Jane Reader"}
+	pagination := service.DefaultPaginationPolicy()
+	h := handlers.NewFollowHandler(followService, pagination.Users, NewMockLogger())
+	postHandler := handlers.NewPostHandler(followService.posts, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(followService.posts), pagination.Posts, NewMockLogger())
+
+	e.POST("/api/v1/users/:id/follow", h.Follow, fixtures.AsUser)
+	e.DELETE("/api/v1/users/:id/follow", h.Unfollow, fixtures.AsUser)
+	e.GET("/api/v1/users/:id/followers", h.ListFollowers)
+	e.GET("/api/v1/users/:id/following", h.ListFollowing)
+	e.GET("/api/v1/feed", postHandler.Feed, fixtures.AsUser)
+
+	return e, followService
+}
+
+func followRequest(e *echo.Echo, method, path, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFollowHandler_FollowAndList(t *testing.T) {
+	e, _ := setupFollowTestServer()
+
+	rec := followRequest(e, http.MethodPost, "/api/v1/users/1/follow", "3")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = followRequest(e, http.MethodPost, "/api/v1/users/1/follow", "3")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "following again has no effect")
+	rec = followRequest(e, http.MethodPost, "/api/v1/users/2/follow", "3")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = followRequest(e, http.MethodGet, "/api/v1/users/3/following", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var following handlers.FollowListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &following))
+	require.Len(t, following.Users, 2)
+	assert.Equal(t, 2, following.Users[0].UserID)
+	assert.Equal(t, "Author Two", following.Users[0].Name)
+	assert.Equal(t, 2, following.Following)
+	assert.Equal(t, 0, following.Followers)
+
+	rec = followRequest(e, http.MethodGet, "/api/v1/users/1/followers", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var followers handlers.FollowListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &followers))
+	require.Len(t, followers.Users, 1)
+	assert.Equal(t, 3, followers.Users[0].UserID)
+	assert.Equal(t, 1, followers.Followers)
+
+	rec = followRequest(e, http.MethodDelete, "/api/v1/users/1/follow", "3")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = followRequest(e, http.MethodGet, "/api/v1/users/1/followers", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &followers))
+	assert.Empty(t, followers.Users)
+	assert.Equal(t, 0, followers.Followers)
+}
+
+func TestFollowHandler_Errors(t *testing.T) {
+	e, _ := setupFollowTestServer()
+
+	rec := followRequest(e, http.MethodPost, "/api/v1/users/3/follow", "3")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "users cannot follow themselves")
+	rec = followRequest(e, http.MethodPost, "/api/v1/users/99/follow", "3")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = followRequest(e, http.MethodPost, "/api/v1/users/abc/follow", "3")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = followRequest(e, http.MethodPost, "/api/v1/users/1/follow", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = followRequest(e, http.MethodGet, "/api/v1/users/99/followers", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPostHandler_Feed(t *testing.T) {
+	e, followService := setupFollowTestServer()
+	posts := followService.posts
+	posts.posts[1] = fixtures.Post(1, 1)
+	posts.posts[2] = fixtures.Post(2, 2)
+	posts.posts[3] = fixtures.Draft(3, 1)
+	posts.posts[4] = fixtures.Post(4, 1)
+
+	feed := func() handlers.PostListResponse {
+		rec := followRequest(e, http.MethodGet, "/api/v1/feed", "3")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "private", rec.Header().Get(echo.HeaderCacheControl))
+		var response handlers.PostListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Empty(t, feed().Posts, "the feed is empty until the reader follows someone")
+
+	require.Equal(t, http.StatusNoContent, followRequest(e, http.MethodPost, "/api/v1/users/1/follow", "3").Code)
+	response := feed()
+	require.Len(t, response.Posts, 2, "drafts and posts of other authors are left out")
+	assert.Equal(t, 4, response.Posts[0].ID)
+	assert.Equal(t, 1, response.Posts[1].ID)
+
+	rec := followRequest(e, http.MethodGet, "/api/v1/feed", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// Golden tests pin the JSON shape of handler responses built from fixtures.
+// After an intended response change, rewrite the files in testdata with:
+//
+//	go test ./tests/integration/http -run Golden -update
+
+// fixtureAuthService signs in the users of a MockUserService with fixed tokens
+type fixtureAuthService struct {
+	auth.AuthService
+	users *MockUserService
+}
+
+func (s fixtureAuthService) Login(ctx context.Context, email, password string) (*user.User, *auth.TokenPair, error) {
+	u, err := s.users.Login(ctx, email, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, &auth.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresIn: 15 * time.Minute}, nil
+}
+
+// serveGolden runs a handler and compares its response with a golden file
+func serveGolden(t *testing.T, e *echo.Echo, name string, status int, req *http.Request, handler echo.HandlerFunc, params ...string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if len(params) > 0 {
+		c.SetParamNames(params[0])
+		c.SetParamValues(params[1])
+	}
+	require.NoError(t, handler(c))
+	require.Equal(t, status, rec.Code, rec.Body.String())
+	fixtures.AssertGolden(t, name, rec.Body.Bytes())
+}
+
+func TestGolden_PostResponses(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	postService := NewMockPostService()
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, NewMockLogger())
+
+	tagged := fixtures.Post(1, 1)
+	tagged.Tags = []string{"golang", "web-development"}
+	hidden := fixtures.Post(2, 2)
+	hidden.NoIndex = true
+	for _, p := range []*post.Post{tagged, hidden, fixtures.Draft(3, 1)} {
+		postService.posts[p.ID] = p
+	}
+
+	serveGolden(t, e, "post", http.StatusOK,
+		httptest.NewRequest(http.MethodGet, "/api/v1/posts/1", nil), postHandler.GetPost, "id", "1")
+	serveGolden(t, e, "post_list", http.StatusOK,
+		httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil), postHandler.ListPosts)
+	serveGolden(t, e, "post_not_found", http.StatusNotFound,
+		httptest.NewRequest(http.MethodGet, "/api/v1/posts/3", nil), postHandler.GetPost, "id", "3")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/posts", bytes.NewReader([]byte(`{"title":"","content":"short"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", 1)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	fixtures.AssertGolden(t, "post_validation_error", rec.Body.Bytes())
+}
+
+func TestGolden_CommentResponses(t *testing.T) {
+	e := echo.New()
+	commentService := NewMockCommentService()
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, NewMockLogger())
+
+	for id := 1; id <= 2; id++ {
+		commentService.comments[id] = fixtures.Comment(id, 1)
+	}
+	commentService.comments[3] = fixtures.Comment(3, 2)
+
+	serveGolden(t, e, "comment_list", http.StatusOK,
+		httptest.NewRequest(http.MethodGet, "/api/v1/posts/1/comments", nil), commentHandler.GetCommentsByPost, "id", "1")
+}
+
+func TestGolden_TagResponses(t *testing.T) {
+	e := echo.New()
+	tagHandler := handlers.NewTagHandler(&MockTagService{tags: []*tag.Tag{
+		{ID: 1, Name: "golang", PostCount: 2},
+		{ID: 2, Name: "web-development", PostCount: 1},
+	}}, NewMockLogger())
+
+	serveGolden(t, e, "tag_list", http.StatusOK,
+		httptest.NewRequest(http.MethodGet, "/api/v1/tags", nil), tagHandler.ListTags)
+}
+
+func TestGolden_AuthResponses(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	userService := NewMockUserService()
+	author := fixtures.User(1)
+	userService.users[author.Email] = author
+	authHandler := handlers.NewAuthHandler(userService, fixtureAuthService{users: userService}, NewMockLogger())
+
+	login := func(password string) *http.Request {
+		body, err := json.Marshal(handlers.LoginRequest{Email: author.Email, Password: password})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		return req
+	}
+
+	serveGolden(t, e, "login", http.StatusOK, login(fixtures.Password), authHandler.Login)
+	serveGolden(t, e, "login_invalid_credentials", http.StatusUnauthorized, login("wrong-password"), authHandler.Login)
+}
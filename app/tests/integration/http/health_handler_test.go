@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/health"
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+func TestHealthHandler_Probes(t *testing.T) {
+	checker := health.NewChecker()
+	databaseErr := error(nil)
+	checker.Register("database", func(ctx context.Context) error {
+		return databaseErr
+	})
+	handler := handlers.NewHealthHandler(checker, NewMockLogger())
+	e := echo.New()
+
+	get := func(h echo.HandlerFunc) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		require.NoError(t, h(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)))
+		return rec
+	}
+
+	rec := get(handler.Readiness)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+
+	// A database blip takes the instance out of rotation without failing
+	// the liveness probe
+	databaseErr = errors.New("connection refused")
+	rec = get(handler.Readiness)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusError, report.Status)
+	assert.Equal(t, "unavailable", report.Checks["database"].Error)
+	assert.NotContains(t, rec.Body.String(), "connection refused")
+
+	rec = get(handler.Liveness)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","service":"blog-platform"}`, rec.Body.String())
+}
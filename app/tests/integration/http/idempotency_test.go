@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+func setupIdempotencyTestServer() (*echo.Echo, *MockPostService, *MockCommentService, *fixtures.IdempotencyRepository) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	postService := NewMockPostService()
+	postService.posts[1] = fixtures.Post(1, 1)
+	postService.nextID = 2
+	commentService := NewMockCommentService()
+	logger := NewMockLogger()
+	repo := fixtures.NewIdempotencyRepository()
+	idempotent := middleware.Idempotency(service.NewIdempotencyService(repo, service.IdempotencySettings{TTL: time.Hour, LockTimeout: time.Minute}, logger))
+
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, logger)
+	commentHandler := handlers.NewCommentHandler(commentService, service.DefaultPaginationPolicy().Comments, logger)
+
+	asAuthor := fixtures.AsUserWithRole(user.RoleAuthor)
+	e.POST("/api/v1/posts", postHandler.CreatePost, asAuthor, idempotent)
+	e.POST("/api/v1/posts/:id/comments", commentHandler.CreateComment, asAuthor, idempotent)
+
+	return e, postService, commentService, repo
+}
+
+func idempotentRequest(e *echo.Echo, path, body, userID, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if userID != "" {
+		req.Header.Set(fixtures.UserHeader, userID)
+	}
+	if key != "" {
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_CreatePostRetry(t *testing.T) {
+	e, postService, _, _ := setupIdempotencyTestServer()
+	body := `{"title":"Retried post","content":"This post is sent twice."}`
+
+	first := idempotentRequest(e, "/api/v1/posts", body, "1", "create-1")
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(middleware.HeaderIdempotentReplayed))
+
+	retry := idempotentRequest(e, "/api/v1/posts", body, "1", "create-1")
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(middleware.HeaderIdempotentReplayed))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Contains(t, retry.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	assert.Len(t, postService.posts, 2, "the retry creates no post")
+
+	// Keys are scoped to the user
+	rec := idempotentRequest(e, "/api/v1/posts", body, "2", "create-1")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get(middleware.HeaderIdempotentReplayed))
+	assert.Len(t, postService.posts, 3)
+
+	// Requests without a key are processed every time
+	idempotentRequest(e, "/api/v1/posts", body, "1", "")
+	idempotentRequest(e, "/api/v1/posts", body, "1", "")
+	assert.Len(t, postService.posts, 5)
+}
+
+func TestIdempotency_KeyReused(t *testing.T) {
+	e, postService, _, _ := setupIdempotencyTestServer()
+
+	rec := idempotentRequest(e, "/api/v1/posts", `{"title":"First post","content":"The first request."}`, "1", "create-1")
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = idempotentRequest(e, "/api/v1/posts", `{"title":"Other post","content":"Another request."}`, "1", "create-1")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, string(errors.ErrCodeIdempotencyKeyReused), response.Error)
+	assert.Len(t, postService.posts, 2)
+
+	rec = idempotentRequest(e, "/api/v1/posts", `{"title":"First post","content":"The first request."}`, "1", "bad\tkey")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIdempotency_FailedRequestReleasesKey(t *testing.T) {
+	e, _, commentService, repo := setupIdempotencyTestServer()
+
+	rec := idempotentRequest(e, "/api/v1/posts/1/comments", `{"content":""}`, "", "comment-1")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, repo.Records, "failed responses are not stored")
+
+	// The corrected request can use the key
+	body := `{"content":"A guest comment","author_name":"Guest"}`
+	first := idempotentRequest(e, "/api/v1/posts/1/comments", body, "", "comment-1")
+	require.Equal(t, http.StatusCreated, first.Code)
+	retry := idempotentRequest(e, "/api/v1/posts/1/comments", body, "", "comment-1")
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(middleware.HeaderIdempotentReplayed))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Len(t, commentService.comments, 1)
+}
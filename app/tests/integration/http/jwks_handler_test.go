@@ -0,0 +1,52 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	infraauth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+func TestJWKSHandler_ServeJWKS(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	key, err := infraauth.ParsePEMKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	jwtService, err := infraauth.NewJWTServiceWithKeys(key)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.GET("/.well-known/jwks.json", handlers.NewJWKSHandler(jwtService.JWKS(), NewMockLogger()).ServeJWKS)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=300", rec.Header().Get(echo.HeaderCacheControl))
+
+	var response struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Keys, 1)
+	assert.Equal(t, "OKP", response.Keys[0]["kty"])
+	assert.Equal(t, "Ed25519", response.Keys[0]["crv"])
+	assert.Equal(t, "EdDSA", response.Keys[0]["alg"])
+	assert.Equal(t, key.ID, response.Keys[0]["kid"])
+	assert.NotEmpty(t, response.Keys[0]["x"])
+	assert.NotContains(t, response.Keys[0], "d", "private key material is never published")
+}
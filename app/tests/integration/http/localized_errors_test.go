@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// newLocalizedErrorServer returns a server failing validation, domain and
+// routing errors the way handlers do
+func newLocalizedErrorServer() *echo.Echo {
+	e := newRequestIDServer()
+	e.Validator = middleware.NewValidator()
+	e.POST("/validate", func(c echo.Context) error {
+		req := struct {
+			Title   string `json:"title" validate:"required"`
+			Content string `json:"content" validate:"required,min=10"`
+		}{Content: "short"}
+		return errors.HandleError(c, c.Validate(&req))
+	})
+	e.GET("/post", func(c echo.Context) error {
+		return errors.HandleError(c, post.ErrPostNotFound)
+	})
+	return e
+}
+
+// requestError sends a request with the Accept-Language header and decodes
+// the error response
+func requestError(t *testing.T, e *echo.Echo, method, path, acceptLanguage string) (*httptest.ResponseRecorder, errors.ErrorResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var response errors.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec, response
+}
+
+func TestLocalizedErrors_Validation(t *testing.T) {
+	e := newLocalizedErrorServer()
+
+	tests := []struct {
+		acceptLanguage string
+		language       string
+		message        string
+		details        []string
+	}{
+		{"", "en", "Request validation failed", []string{"Title is required", "Content must be at least 10 characters long"}},
+		{"es", "es", "La validación de la solicitud falló", []string{"Title es obligatorio", "Content debe tener al menos 10 caracteres"}},
+		{"es-MX,es;q=0.9", "es", "La validación de la solicitud falló", []string{"Title es obligatorio", "Content debe tener al menos 10 caracteres"}},
+		{"ja-JP", "ja", "リクエストの検証に失敗しました", []string{"Title は必須です", "Content は 10 文字以上にしてください"}},
+		{"fr-CH, fr;q=0.9", "en", "Request validation failed", []string{"Title is required", "Content must be at least 10 characters long"}},
+		{"fr, ja;q=0.5", "ja", "リクエストの検証に失敗しました", []string{"Title は必須です", "Content は 10 文字以上にしてください"}},
+		{"not a header;;", "en", "Request validation failed", []string{"Title is required", "Content must be at least 10 characters long"}},
+	}
+	for _, tt := range tests {
+		rec, response := requestError(t, e, http.MethodPost, "/validate", tt.acceptLanguage)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "validation_error", response.Error, tt.acceptLanguage)
+		assert.Equal(t, tt.message, response.Message, tt.acceptLanguage)
+		assert.Equal(t, tt.details, response.Details, tt.acceptLanguage)
+		assert.Equal(t, tt.language, rec.Header().Get("Content-Language"), tt.acceptLanguage)
+		assert.Contains(t, rec.Header().Values("Vary"), "Accept-Language")
+	}
+}
+
+func TestLocalizedErrors_DomainAndRouting(t *testing.T) {
+	e := newLocalizedErrorServer()
+
+	rec, response := requestError(t, e, http.MethodGet, "/post", "es")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "not_found", response.Error)
+	assert.Equal(t, "publicación no encontrada", response.Message)
+
+	_, response = requestError(t, e, http.MethodGet, "/post", "ja")
+	assert.Equal(t, "投稿が見つかりません", response.Message)
+
+	_, response = requestError(t, e, http.MethodGet, "/post", "")
+	assert.Equal(t, "post not found", response.Message)
+
+	// Echo's own errors are translated too
+	_, response = requestError(t, e, http.MethodGet, "/missing", "ja")
+	assert.Equal(t, "not_found", response.Error)
+	assert.Equal(t, "見つかりません", response.Message)
+}
+
+func TestLocalizedErrors_UntranslatedMessagesFallBack(t *testing.T) {
+	e := newRequestIDServer()
+	e.GET("/test", func(c echo.Context) error {
+		return errors.HandleError(c, errors.NewAPIError(errors.ErrCodeConflict, "Something only English has", http.StatusConflict))
+	})
+
+	_, response := requestError(t, e, http.MethodGet, "/test", "es")
+	assert.Equal(t, "Something only English has", response.Message)
+}
+
+func TestLocalizer_CatalogsCoverEnglish(t *testing.T) {
+	assert.Equal(t, []string{"en", "es", "ja"}, errors.Languages())
+
+	// Each language translates the predefined errors rather than falling
+	// back to English
+	english := errors.NewLocalizer("en")
+	for _, lang := range errors.Languages()[1:] {
+		l := errors.NewLocalizer(lang)
+		assert.Equal(t, lang, l.Language())
+		for _, message := range []string{
+			errors.ErrUnauthorized.Message,
+			errors.ErrForbidden.Message,
+			errors.ErrNotFound.Message,
+			errors.ErrInternal.Message,
+			errors.ErrInvalidRequest.Message,
+			errors.ErrTooManyRequests.Message,
+		} {
+			assert.NotEqual(t, english.Message(message), l.Message(message), "%s: %s", lang, message)
+		}
+	}
+}
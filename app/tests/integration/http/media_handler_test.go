@@ -0,0 +1,147 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/media"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockMediaService implements media.Service for testing; it keeps the
+// uploads of each user within a quota
+type MockMediaService struct {
+	uploads map[int][]*media.Media
+	quota   int64
+}
+
+func NewMockMediaService() *MockMediaService {
+	return &MockMediaService{uploads: make(map[int][]*media.Media), quota: 1 << 20}
+}
+
+func (m *MockMediaService) Upload(ctx context.Context, userID int, r io.Reader) (*media.Media, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, media.ErrInvalidMedia
+	}
+	var used int64
+	for _, u := range m.uploads[userID] {
+		used += u.Size
+	}
+	if used+int64(len(data)) > m.quota {
+		return nil, media.ErrQuotaExceeded
+	}
+
+	id := len(m.uploads[userID]) + 1
+	upload := &media.Media{
+		ID:          id,
+		UserID:      userID,
+		Key:         fmt.Sprintf("media/%d/%d.%s", userID, id, format),
+		ContentType: "image/" + format,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		CreatedAt:   fixtures.Time,
+	}
+	upload.URL = "https://cdn.example.com/" + upload.Key
+	m.uploads[userID] = append(m.uploads[userID], upload)
+	return upload, nil
+}
+
+func (m *MockMediaService) CollectOrphans(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func setupMediaTestServer() (*echo.Echo, *MockMediaService) {
+	e := echo.New()
+
+	mediaService := NewMockMediaService()
+	h := handlers.NewMediaHandler(mediaService, NewMockLogger())
+	upload := middleware.ValidateUpload(middleware.UploadPolicy{
+		Field:   handlers.MediaField,
+		MaxSize: 1024,
+		Types:   handlers.MediaTypes,
+	}, NewMockLogger())
+
+	e.POST("/api/v1/media", h.UploadMedia, fixtures.RequireUser, upload)
+
+	return e, mediaService
+}
+
+// mediaUpload sends a multipart upload of content in the field
+func mediaUpload(e *echo.Echo, field string, content []byte, userID string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile(field, "image.png")
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/media", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMediaHandler_UploadMedia(t *testing.T) {
+	e, mediaService := setupMediaTestServer()
+	content := smallPNG(t)
+
+	rec := mediaUpload(e, "file", content, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var response handlers.MediaResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "https://cdn.example.com/media/1/1.png", response.URL)
+	assert.Equal(t, "image/png", response.ContentType)
+	assert.Equal(t, int64(len(content)), response.Size)
+	assert.Equal(t, 8, response.Width)
+	assert.Equal(t, 8, response.Height)
+	assert.Equal(t, "2024-01-15T10:30:00Z", response.CreatedAt)
+	assert.Len(t, mediaService.uploads[1], 1)
+
+	rec = mediaUpload(e, "file", content, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMediaHandler_UploadMedia_Rejected(t *testing.T) {
+	e, mediaService := setupMediaTestServer()
+
+	rec := mediaUpload(e, "file", []byte("<html><body>not an image</body></html>"), "1")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code, "the type is detected from the content")
+
+	rec = mediaUpload(e, "file", append(smallPNG(t), make([]byte, 2048)...), "1")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = mediaUpload(e, "image", smallPNG(t), "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = mediaUpload(e, "file", smallPNG(t)[:16], "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, mediaService.uploads)
+
+	mediaService.quota = 10
+	rec = mediaUpload(e, "file", smallPNG(t), "1")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "quota_exceeded", response.Error)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/tests/fixtures"
+)
+
+// MockNotificationService implements notification.Service for testing
+type MockNotificationService struct {
+	notifications []*notification.Notification
+}
+
+func NewMockNotificationService() *MockNotificationService {
+	return &MockNotificationService{}
+}
+
+func (m *MockNotificationService) add(n *notification.Notification) {
+	n.ID = len(m.notifications) + 1
+	m.notifications = append(m.notifications, n)
+}
+
+func (m *MockNotificationService) List(ctx context.Context, userID int, limit, offset int) ([]*notification.Notification, int, error) {
+	var notifications []*notification.Notification
+	unread := 0
+	for i := len(m.notifications) - 1; i >= 0; i-- {
+		if n := m.notifications[i]; n.UserID == userID {
+			notifications = append(notifications, n)
+			if !n.IsRead() {
+				unread++
+			}
+		}
+	}
+	return notifications, unread, nil
+}
+
+func (m *MockNotificationService) MarkRead(ctx context.Context, id, userID int) error {
+	for _, n := range m.notifications {
+		if n.ID == id && n.UserID == userID {
+			if n.ReadAt == nil {
+				now := time.Now()
+				n.ReadAt = &now
+			}
+			return nil
+		}
+	}
+	return notification.ErrNotificationNotFound
+}
+
+func setupNotificationTestServer() (*echo.Echo, *MockNotificationService) {
+	e := echo.New()
+
+	notificationService := NewMockNotificationService()
+	h := handlers.NewNotificationHandler(notificationService, service.DefaultPaginationPolicy().Comments, NewMockLogger())
+
+	e.GET("/api/v1/users/me/notifications", h.List, fixtures.AsUser)
+	e.POST("/api/v1/notifications/:id/read", h.MarkRead, fixtures.AsUser)
+
+	return e, notificationService
+}
+
+func notificationRequest(e *echo.Echo, method, path, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNotificationHandler_ListAndMarkRead(t *testing.T) {
+	e, notificationService := setupNotificationTestServer()
+	notificationService.add(notification.NewNotification(1, notification.KindComment, 1, 4, "Jane Reader"))
+	notificationService.add(notification.NewNotification(1, notification.KindReply, 1, 5, "John Reader"))
+	notificationService.add(notification.NewNotification(2, notification.KindReply, 1, 6, "Jane Reader"))
+
+	list := func(userID string) handlers.NotificationListResponse {
+		rec := notificationRequest(e, http.MethodGet, "/api/v1/users/me/notifications", userID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.NotificationListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	response := list("1")
+	require.Len(t, response.Notifications, 2)
+	assert.Equal(t, 2, response.Unread)
+	assert.Equal(t, "reply", response.Notifications[0].Kind)
+	assert.Equal(t, 5, response.Notifications[0].CommentID)
+	assert.Equal(t, "John Reader", response.Notifications[0].ActorName)
+	assert.False(t, response.Notifications[0].Read)
+
+	rec := notificationRequest(e, http.MethodPost, "/api/v1/notifications/2/read", "1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = notificationRequest(e, http.MethodPost, "/api/v1/notifications/2/read", "1")
+	assert.Equal(t, http.StatusNoContent, rec.Code, "marking read again has no effect")
+	response = list("1")
+	assert.Equal(t, 1, response.Unread)
+	assert.True(t, response.Notifications[0].Read)
+
+	rec = notificationRequest(e, http.MethodPost, "/api/v1/notifications/3/read", "1")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "notifications of other users are not found")
+	rec = notificationRequest(e, http.MethodPost, "/api/v1/notifications/abc/read", "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = notificationRequest(e, http.MethodGet, "/api/v1/users/me/notifications", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
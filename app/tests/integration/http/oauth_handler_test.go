@@ -0,0 +1,173 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/auth/oauth"
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+// fakeProvider returns a fixed identity for the code "good-code"
+type fakeProvider struct {
+	identity user.ExternalIdentity
+}
+
+func (p *fakeProvider) Name() string { return p.identity.Provider }
+
+func (p *fakeProvider) AuthCodeURL(state, redirectURL string) string {
+	return "https://provider.example/authorize?" + url.Values{"state": {state}, "redirect_uri": {redirectURL}}.Encode()
+}
+
+func (p *fakeProvider) Identity(ctx context.Context, code, redirectURL string) (*user.ExternalIdentity, error) {
+	if code != "good-code" {
+		return nil, oauth.ErrExchangeFailed
+	}
+	identity := p.identity
+	return &identity, nil
+}
+
+func setupOAuthTestServer(identity user.ExternalIdentity) *echo.Echo {
+	e := echo.New()
+	authService := NewMockAuthService(NewMockUserService())
+	oauthHandler := handlers.NewOAuthHandler(
+		authService,
+		oauth.NewRegistry(&fakeProvider{identity: identity}),
+		oauth.NewStateSigner("test-secret", time.Minute),
+		handlers.OAuthSettings{BaseURL: "https://blog.example"},
+		NewMockLogger(),
+	)
+	e.GET("/api/v1/auth/oauth/:provider", oauthHandler.Start)
+	e.GET("/api/v1/auth/oauth/:provider/callback", oauthHandler.Callback)
+	return e
+}
+
+// startOAuth begins a sign-in and returns the state cookie
+func startOAuth(t *testing.T, e *echo.Echo, provider string) *http.Cookie {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oauth/"+provider, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://blog.example/api/v1/auth/oauth/"+provider+"/callback", location.Query().Get("redirect_uri"))
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	assert.Equal(t, location.Query().Get("state"), cookies[0].Value)
+	return cookies[0]
+}
+
+func oauthCallback(e *echo.Echo, provider, code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	query := url.Values{"code": {code}, "state": {state}}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oauth/"+provider+"/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOAuthHandler_LoginFlow(t *testing.T) {
+	e := setupOAuthTestServer(user.ExternalIdentity{
+		Provider:      "google",
+		Subject:       "g-123",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	})
+
+	cookie := startOAuth(t, e, "google")
+	rec := oauthCallback(e, "google", "good-code", cookie.Value, cookie)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "jane@example.com", response.User.Email)
+	assert.Equal(t, "mock-jwt-token", response.Token)
+	assert.Equal(t, "mock-refresh-token", response.RefreshToken)
+}
+
+func TestOAuthHandler_CallbackErrors(t *testing.T) {
+	verified := user.ExternalIdentity{Provider: "google", Subject: "g-123", Email: "jane@example.com", EmailVerified: true}
+	unverified := verified
+	unverified.EmailVerified = false
+
+	tests := []struct {
+		name           string
+		identity       user.ExternalIdentity
+		code           string
+		tamper         func(state string, cookie *http.Cookie) (string, *http.Cookie)
+		expectedStatus int
+	}{
+		{
+			name:     "missing state cookie",
+			identity: verified,
+			code:     "good-code",
+			tamper: func(state string, cookie *http.Cookie) (string, *http.Cookie) {
+				return state, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "state does not match cookie",
+			identity: verified,
+			code:     "good-code",
+			tamper: func(state string, cookie *http.Cookie) (string, *http.Cookie) {
+				return state + "x", cookie
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "code rejected by provider",
+			identity:       verified,
+			code:           "bad-code",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unverified provider email",
+			identity:       unverified,
+			code:           "good-code",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupOAuthTestServer(tt.identity)
+			cookie := startOAuth(t, e, "google")
+			state := cookie.Value
+			if tt.tamper != nil {
+				state, cookie = tt.tamper(state, cookie)
+			}
+
+			rec := oauthCallback(e, "google", tt.code, state, cookie)
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestOAuthHandler_UnknownProvider(t *testing.T) {
+	e := setupOAuthTestServer(user.ExternalIdentity{Provider: "google"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oauth/facebook", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
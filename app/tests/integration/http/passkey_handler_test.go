@@ -0,0 +1,256 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockPasskeyService implements auth.PasskeyService for testing. Credentials
+// are accepted by ID; their signatures are not checked.
+type MockPasskeyService struct {
+	userService *MockUserService
+	passkeys    map[int]*auth.Passkey
+	nextID      int
+	// signup is the account of the pending signup
+	signup [2]string
+}
+
+func NewMockPasskeyService(userService *MockUserService) *MockPasskeyService {
+	return &MockPasskeyService{userService: userService, passkeys: make(map[int]*auth.Passkey), nextID: 1}
+}
+
+func (m *MockPasskeyService) add(userID int, credentialID, name string) *auth.Passkey {
+	p := &auth.Passkey{ID: m.nextID, UserID: userID, CredentialID: []byte(credentialID), Name: name, CreatedAt: time.Now()}
+	m.passkeys[p.ID] = p
+	m.nextID++
+	return p
+}
+
+func (m *MockPasskeyService) userOf(credential *auth.AssertionResponse) (int, error) {
+	for _, p := range m.passkeys {
+		if string(p.CredentialID) == string(credential.RawID) {
+			return p.UserID, nil
+		}
+	}
+	return 0, auth.ErrInvalidPasskeyAssertion
+}
+
+func (m *MockPasskeyService) BeginSignup(ctx context.Context, name, email string) (*auth.CreationOptions, error) {
+	if _, err := m.userService.GetByEmail(ctx, email); err == nil {
+		return nil, user.ErrUserExists
+	}
+	m.signup = [2]string{name, email}
+	return &auth.CreationOptions{Challenge: auth.Base64URL("signup"), User: auth.UserEntity{Name: email, DisplayName: name}}, nil
+}
+
+func (m *MockPasskeyService) FinishSignup(ctx context.Context, credential *auth.AttestationResponse) (*user.User, error) {
+	if m.signup[1] == "" {
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+	u, err := m.userService.RegisterPasswordless(ctx, m.signup[0], m.signup[1], "passkey")
+	if err != nil {
+		return nil, err
+	}
+	m.signup = [2]string{}
+	m.add(u.ID, string(credential.RawID), auth.DefaultPasskeyName)
+	return u, nil
+}
+
+func (m *MockPasskeyService) BeginRegistration(ctx context.Context, userID int, name string) (*auth.CreationOptions, error) {
+	return &auth.CreationOptions{Challenge: auth.Base64URL("registration")}, nil
+}
+
+func (m *MockPasskeyService) FinishRegistration(ctx context.Context, userID int, credential *auth.AttestationResponse) (*auth.Passkey, error) {
+	return m.add(userID, string(credential.RawID), "Laptop"), nil
+}
+
+func (m *MockPasskeyService) BeginLogin(ctx context.Context) (*auth.RequestOptions, error) {
+	return &auth.RequestOptions{Challenge: auth.Base64URL("login"), UserVerification: "required"}, nil
+}
+
+func (m *MockPasskeyService) FinishLogin(ctx context.Context, credential *auth.AssertionResponse) (int, error) {
+	return m.userOf(credential)
+}
+
+func (m *MockPasskeyService) BeginTwoFactor(ctx context.Context, userID int) (*auth.RequestOptions, error) {
+	return &auth.RequestOptions{Challenge: auth.Base64URL("two-factor"), UserVerification: "preferred"}, nil
+}
+
+func (m *MockPasskeyService) FinishTwoFactor(ctx context.Context, credential *auth.AssertionResponse) (int, error) {
+	return m.userOf(credential)
+}
+
+func (m *MockPasskeyService) HasPasskeys(ctx context.Context, userID int) (bool, error) {
+	passkeys, err := m.ListPasskeys(ctx, userID)
+	return len(passkeys) > 0, err
+}
+
+func (m *MockPasskeyService) ListPasskeys(ctx context.Context, userID int) ([]*auth.Passkey, error) {
+	var result []*auth.Passkey
+	for id := 1; id < m.nextID; id++ {
+		if p, ok := m.passkeys[id]; ok && p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPasskeyService) DeletePasskey(ctx context.Context, userID, id int) error {
+	if p, ok := m.passkeys[id]; !ok || p.UserID != userID {
+		return auth.ErrPasskeyNotFound
+	}
+	delete(m.passkeys, id)
+	return nil
+}
+
+func setupPasskeyTestServer(t *testing.T) (*echo.Echo, *MockPasskeyService, int) {
+	t.Helper()
+
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	userService := NewMockUserService()
+	passkeyService := NewMockPasskeyService(userService)
+	authService := NewMockAuthService(userService)
+	authService.passkeys = passkeyService
+	u, err := userService.Register(context.Background(), "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+
+	h := handlers.NewPasskeyHandler(passkeyService, authService, NewMockLogger())
+
+	signedIn := fixtures.ActingAs(u.ID)
+	e.POST("/api/v1/auth/webauthn/signup/begin", h.BeginSignup)
+	e.POST("/api/v1/auth/webauthn/signup/finish", h.FinishSignup)
+	e.POST("/api/v1/auth/webauthn/login/begin", h.BeginLogin)
+	e.POST("/api/v1/auth/webauthn/login/finish", h.FinishLogin)
+	e.POST("/api/v1/auth/webauthn/2fa/begin", h.BeginTwoFactor)
+	e.POST("/api/v1/auth/webauthn/2fa/finish", h.FinishTwoFactor)
+	e.POST("/api/v1/auth/webauthn/register/begin", h.BeginRegistration, signedIn)
+	e.POST("/api/v1/auth/webauthn/register/finish", h.FinishRegistration, signedIn)
+	e.GET("/api/v1/users/me/passkeys", h.ListPasskeys, signedIn)
+	e.DELETE("/api/v1/users/me/passkeys/:id", h.DeletePasskey, signedIn)
+
+	return e, passkeyService, u.ID
+}
+
+// credentialJSON is the body of a finish request for a credential
+func credentialJSON(rawID string) string {
+	id, _ := json.Marshal(auth.Base64URL(rawID))
+	return `{"id":` + string(id) + `,"rawId":` + string(id) + `,"type":"public-key","response":{}}`
+}
+
+func TestPasskeyHandler_Signup(t *testing.T) {
+	e, passkeyService, _ := setupPasskeyTestServer(t)
+
+	rec := postJSON(e, "/api/v1/auth/webauthn/signup/begin", `{"name":"Jane Doe","email":"john@example.com"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/signup/begin", `{"name":"Jane Doe","email":"not-an-email"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/signup/begin", `{"name":"Jane Doe","email":"jane@example.com"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var options map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &options))
+	assert.Equal(t, "c2lnbnVw", options["challenge"])
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/signup/finish", credentialJSON("jane-key"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var response handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "jane@example.com", response.User.Email)
+	assert.NotEmpty(t, response.Token)
+
+	passkeys, _ := passkeyService.ListPasskeys(context.Background(), response.User.ID)
+	assert.Len(t, passkeys, 1)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/signup/finish", credentialJSON("other-key"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestPasskeyHandler_Login(t *testing.T) {
+	e, passkeyService, userID := setupPasskeyTestServer(t)
+	passkeyService.add(userID, "john-key", "Laptop")
+
+	rec := postJSON(e, "/api/v1/auth/webauthn/login/begin", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"userVerification":"required"`)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/login/finish", credentialJSON("john-key"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, userID, response.User.ID)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/login/finish", credentialJSON("unknown-key"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/login/finish", `{"rawId":"not base64!"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPasskeyHandler_TwoFactor(t *testing.T) {
+	e, passkeyService, userID := setupPasskeyTestServer(t)
+	passkeyService.add(userID, "john-key", "Laptop")
+
+	rec := postJSON(e, "/api/v1/auth/webauthn/2fa/begin", `{"token":"invalid"}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/2fa/begin", `{"token":"mock-2fa-token"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"userVerification":"preferred"`)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/2fa/finish", credentialJSON("john-key"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"refresh_token"`)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/2fa/finish", credentialJSON("unknown-key"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestPasskeyHandler_ManagePasskeys(t *testing.T) {
+	e, _, _ := setupPasskeyTestServer(t)
+
+	rec := postJSON(e, "/api/v1/auth/webauthn/register/begin", `{"name":"Laptop"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = postJSON(e, "/api/v1/auth/webauthn/register/finish", credentialJSON("laptop-key"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var passkey handlers.PasskeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &passkey))
+	assert.Equal(t, "Laptop", passkey.Name)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/passkeys", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.PasskeyListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Passkeys, 1)
+	assert.Equal(t, passkey.ID, list.Passkeys[0].ID)
+
+	deletePasskey := func(id string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/me/passkeys/"+id, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusBadRequest, deletePasskey("abc"))
+	assert.Equal(t, http.StatusOK, deletePasskey(strconv.Itoa(passkey.ID)))
+	assert.Equal(t, http.StatusNotFound, deletePasskey(strconv.Itoa(passkey.ID)))
+}
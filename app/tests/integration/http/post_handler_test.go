@@ -2,12 +2,16 @@ package http
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,8 +19,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
 	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/http/handlers"
 	"blog-platform/internal/infrastructure/http/middleware"
@@ -24,14 +31,24 @@ import (
 
 // MockPostService implements the post.Service interface for testing
 type MockPostService struct {
-	posts  map[int]*post.Post
-	nextID int
+	posts     map[int]*post.Post
+	revisions map[int][]*post.Revision
+	// translations holds the translations of each post by language
+	translations map[int]map[string]*post.Translation
+	// following holds the authors each user follows, for the feed
+	following map[int][]int
+	// oldSlugs holds the posts of slugs replaced by ChangeSlug
+	oldSlugs  map[string]int
+	nextID    int
 }
 
 func NewMockPostService() *MockPostService {
 	return &MockPostService{
-		posts:  make(map[int]*post.Post),
-		nextID: 1,
+		posts:     make(map[int]*post.Post),
+		revisions: make(map[int][]*post.Revision),
+		translations: make(map[int]map[string]*post.Translation),
+		oldSlugs:  make(map[string]int),
+		nextID:    1,
 	}
 }
 
@@ -48,6 +65,38 @@ func (m *MockPostService) CreatePost(ctx context.Context, userID int, title, con
 	return p, nil
 }
 
+func (m *MockPostService) CreateDraft(ctx context.Context, userID int, title, content string) (*post.Post, error) {
+	p, err := m.CreatePost(ctx, userID, title, content)
+	if err != nil {
+		return nil, err
+	}
+	p.Status = post.StatusDraft
+	p.PublishedAt = nil
+	return p, nil
+}
+
+// CreateClientPost returns the post already created under the client ID by
+// the same author, or creates a new one
+func (m *MockPostService) CreateClientPost(ctx context.Context, userID int, clientID, title, content string, draft bool) (*post.Post, bool, error) {
+	if existing, err := m.GetPostByClientID(ctx, clientID); err == nil {
+		if !existing.IsAuthor(userID) {
+			return nil, false, post.ErrClientIDTaken
+		}
+		return existing, false, nil
+	}
+
+	create := m.CreatePost
+	if draft {
+		create = m.CreateDraft
+	}
+	p, err := create(ctx, userID, title, content)
+	if err != nil {
+		return nil, false, err
+	}
+	p.ClientID = &clientID
+	return p, true, nil
+}
+
 func (m *MockPostService) GetPost(ctx context.Context, id int) (*post.Post, error) {
 	if p, exists := m.posts[id]; exists {
 		return p, nil
@@ -55,6 +104,31 @@ func (m *MockPostService) GetPost(ctx context.Context, id int) (*post.Post, erro
 	return nil, post.ErrPostNotFound
 }
 
+func (m *MockPostService) GetVisiblePost(ctx context.Context, userID int, role user.Role, id int) (*post.Post, error) {
+	if p, exists := m.posts[id]; exists && p.IsVisibleTo(userID, role) {
+		return p, nil
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostService) GetPostBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostService) GetPostByClientID(ctx context.Context, clientID string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.ClientID != nil && *p.ClientID == clientID {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
 func (m *MockPostService) GetPostsByAuthor(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
 	var result []*post.Post
 	count := 0
@@ -70,9 +144,45 @@ func (m *MockPostService) GetPostsByAuthor(ctx context.Context, authorID int, li
 }
 
 func (m *MockPostService) ListPosts(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	return m.ListPostsFor(ctx, 0, "", limit, offset)
+}
+
+func (m *MockPostService) ListPublishedPosts(ctx context.Context, filter post.PublishedFilter, limit, offset int) ([]*post.Post, error) {
+	if filter.Tag != "" {
+		normalized, err := tag.Normalize(filter.Tag)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tag = normalized
+	}
+
+	// Newest first, like the repository
+	var result []*post.Post
+	for _, id := range slices.Backward(slices.Sorted(maps.Keys(m.posts))) {
+		p := m.posts[id]
+		if !p.IsPublished() || (filter.AuthorID != 0 && p.AuthorID != filter.AuthorID) || (filter.Tag != "" && !slices.Contains(p.Tags, filter.Tag)) ||
+			(filter.FollowedBy != 0 && !slices.Contains(m.following[filter.FollowedBy], p.AuthorID)) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(result) < limit {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPostService) ListPostsFor(ctx context.Context, userID int, role user.Role, limit, offset int) ([]*post.Post, error) {
 	var result []*post.Post
 	count := 0
-	for _, p := range m.posts {
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		p := m.posts[id]
+		if !p.IsVisibleTo(userID, role) {
+			continue
+		}
 		if count >= offset && len(result) < limit {
 			result = append(result, p)
 		}
@@ -84,14 +194,156 @@ func (m *MockPostService) ListPosts(ctx context.Context, limit, offset int) ([]*
 	return result, nil
 }
 
-func (m *MockPostService) UpdatePost(ctx context.Context, userID, postID int, title, content string) (*post.Post, error) {
+func (m *MockPostService) ListPostsByTag(ctx context.Context, userID int, role user.Role, name string, limit, offset int) ([]*post.Post, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*post.Post
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		p := m.posts[id]
+		if p.IsVisibleTo(userID, role) && slices.Contains(p.Tags, normalized) && len(result) < limit {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// ListPostsMatching filters like the repository; only the title sort is
+// honoured, every other listing is in ID order
+func (m *MockPostService) ListPostsMatching(ctx context.Context, userID int, role user.Role, name string, options post.ListOptions, limit, offset int) ([]*post.Post, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result []*post.Post
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		p := m.posts[id]
+		if !p.IsVisibleTo(userID, role) || (name != "" && !slices.Contains(p.Tags, name)) ||
+			(options.AuthorID != 0 && p.AuthorID != options.AuthorID) ||
+			(options.CreatedAfter != nil && p.CreatedAt.Before(*options.CreatedAfter)) ||
+			(options.CreatedBefore != nil && !p.CreatedAt.Before(*options.CreatedBefore)) {
+			continue
+		}
+		result = append(result, p)
+	}
+	if options.Sort == post.SortTitle {
+		slices.SortStableFunc(result, func(a, b *post.Post) int { return strings.Compare(a.Title, b.Title) })
+	}
+	result = result[min(offset, len(result)):]
+	return result[:min(limit, len(result))], nil
+}
+
+// ListPostsAfter pages through posts newest first by ID, which is the
+// order the mock creates them in
+func (m *MockPostService) ListPostsAfter(ctx context.Context, userID int, role user.Role, name string, after keyset.Cursor, limit int) ([]*post.Post, keyset.Cursor, error) {
+	var result []*post.Post
+	ids := slices.Sorted(maps.Keys(m.posts))
+	slices.Reverse(ids)
+	for _, id := range ids {
+		p := m.posts[id]
+		if !p.IsVisibleTo(userID, role) || (name != "" && !slices.Contains(p.Tags, name)) || (!after.IsZero() && id >= after.ID) {
+			continue
+		}
+		if len(result) == limit {
+			last := result[limit-1]
+			return result, keyset.After(last.CreatedAt, last.ID), nil
+		}
+		result = append(result, p)
+	}
+	return result, keyset.Cursor{}, nil
+}
+
+// ListPopularPosts ranks by total views; the mock counts views right away
+func (m *MockPostService) ListPopularPosts(ctx context.Context, days, limit int) ([]*post.PopularPost, error) {
+	if days < 1 || days > post.MaxPopularWindow {
+		return nil, post.ErrInvalidPopularWindow
+	}
+
+	var popular []*post.PopularPost
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		p := m.posts[id]
+		if p.IsPublished() && p.ViewCount > 0 {
+			popular = append(popular, &post.PopularPost{Post: p, Views: p.ViewCount})
+		}
+	}
+	slices.SortStableFunc(popular, func(a, b *post.PopularPost) int {
+		return int(b.Views - a.Views)
+	})
+	if len(popular) > limit {
+		popular = popular[:limit]
+	}
+	return popular, nil
+}
+
+func (m *MockPostService) ListFeaturedPosts(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	var featured []*post.Post
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		if p := m.posts[id]; p.IsPublished() && p.IsFeatured() {
+			featured = append(featured, p)
+		}
+	}
+	slices.SortStableFunc(featured, func(a, b *post.Post) int { return b.FeaturedAt.Compare(*a.FeaturedAt) })
+	featured = featured[min(offset, len(featured)):]
+	return featured[:min(limit, len(featured))], nil
+}
+
+func (m *MockPostService) ListTrendingPosts(ctx context.Context, limit int) ([]*post.TrendingPost, error) {
+	trending := []*post.TrendingPost{}
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		if p := m.posts[id]; p.IsPublished() && p.ViewCount > 0 {
+			trending = append(trending, &post.TrendingPost{Post: p, Score: float64(p.ViewCount)})
+		}
+	}
+	slices.SortStableFunc(trending, func(a, b *post.TrendingPost) int { return cmp.Compare(b.Score, a.Score) })
+	return trending[:min(limit, len(trending))], nil
+}
+
+func (m *MockPostService) ListRelatedPosts(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	related := []*post.Post{}
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		candidate := m.posts[id]
+		shares := slices.ContainsFunc(candidate.Tags, func(tag string) bool { return slices.Contains(p.Tags, tag) })
+		if id != p.ID && candidate.IsPublished() && shares && len(related) < limit {
+			related = append(related, candidate)
+		}
+	}
+	return related, nil
+}
+
+func (m *MockPostService) GatePost(ctx context.Context, userID int, role user.Role, p *post.Post) *post.Post {
+	return m.GatePosts(ctx, userID, role, []*post.Post{p})[0]
+}
+
+func (m *MockPostService) GatePosts(ctx context.Context, userID int, role user.Role, posts []*post.Post) []*post.Post {
+	// The mock has no plans, so nobody is a subscriber
+	reader := post.Reader{UserID: userID, Role: role}
+	gated := make([]*post.Post, len(posts))
+	for i, p := range posts {
+		gated[i] = p
+		if !p.IsReadableBy(reader) {
+			gated[i] = p.Teaser()
+		}
+	}
+	return gated
+}
+
+func (m *MockPostService) RecordView(ctx context.Context, p *post.Post) {
+	if p.IsPublished() {
+		p.ViewCount++
+	}
+}
+
+func (m *MockPostService) UpdatePost(ctx context.Context, userID int, role user.Role, postID int, title, content string) (*post.Post, error) {
 	p, exists := m.posts[postID]
 	if !exists {
 		return nil, post.ErrPostNotFound
 	}
-	if p.AuthorID != userID {
+	if !p.CanModify(userID, role) {
 		return nil, post.ErrUnauthorized
 	}
+	m.addRevision(p, userID)
 	err := p.Update(title, content)
 	if err != nil {
 		return nil, err
@@ -100,12 +352,257 @@ func (m *MockPostService) UpdatePost(ctx context.Context, userID, postID int, ti
 	return p, nil
 }
 
-func (m *MockPostService) DeletePost(ctx context.Context, userID, postID int) error {
+func (m *MockPostService) SetNoIndex(ctx context.Context, userID int, role user.Role, postID int, noindex bool) (*post.Post, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	p.NoIndex = noindex
+	return p, nil
+}
+
+func (m *MockPostService) SetPinned(ctx context.Context, adminID, postID int, pinned bool) (*post.Post, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if err := p.SetPinned(pinned, time.Now()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (m *MockPostService) SetFeatured(ctx context.Context, adminID, postID int, featured bool) (*post.Post, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if err := p.SetFeatured(featured, time.Now()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (m *MockPostService) ChangeSlug(ctx context.Context, userID int, role user.Role, postID int, slug string) (*post.Post, error) {
+	if !post.IsValidSlug(slug) {
+		return nil, post.ErrInvalidSlug
+	}
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	if other, err := m.GetPostBySlug(ctx, slug); err == nil && other.ID != postID {
+		return nil, post.ErrSlugTaken
+	}
+	if p.Slug != slug {
+		m.oldSlugs[p.Slug] = p.ID
+		delete(m.oldSlugs, slug)
+		p.Slug = slug
+	}
+	return p, nil
+}
+
+func (m *MockPostService) SetAccess(ctx context.Context, userID int, role user.Role, postID int, access post.Access) (*post.Post, error) {
+	if !access.IsValid() {
+		return nil, post.ErrInvalidAccess
+	}
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	p.Access = access
+	return p, nil
+}
+
+func (m *MockPostService) SetVisibility(ctx context.Context, userID int, role user.Role, postID int, visibility post.Visibility) (*post.Post, error) {
+	if !visibility.IsValid() {
+		return nil, post.ErrInvalidVisibility
+	}
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	p.Visibility = visibility
+	return p, nil
+}
+
+func (m *MockPostService) SetTags(ctx context.Context, userID int, role user.Role, postID int, tags []string) (*post.Post, error) {
+	normalized, err := tag.NormalizeNames(tags)
+	if err != nil {
+		return nil, err
+	}
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	p.Tags = slices.Sorted(slices.Values(normalized))
+	return p, nil
+}
+
+func (m *MockPostService) PublishPost(ctx context.Context, userID int, role user.Role, postID int) (*post.Post, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, post.ErrUnauthorized
+	}
+	if err := p.Publish(time.Now()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (m *MockPostService) SchedulePost(ctx context.Context, userID int, role user.Role, postID int, publishAt time.Time, timezone string) (*post.Post, []post.Conflict, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, nil, post.ErrUnauthorized
+	}
+	if err := p.Schedule(publishAt, timezone, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return p, m.conflicts(p, userID, role), nil
+}
+
+func (m *MockPostService) Calendar(ctx context.Context, userID int, role user.Role, from, to time.Time) ([]*post.CalendarEntry, error) {
+	if !to.After(from) || to.Sub(from) > post.MaxCalendarSpan {
+		return nil, post.ErrInvalidCalendarRange
+	}
+
+	var entries []*post.CalendarEntry
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		p := m.posts[id]
+		if p.PublishedAt == nil || p.PublishedAt.Before(from) || p.PublishedAt.After(to) || !p.IsVisibleTo(userID, role) {
+			continue
+		}
+		entry := &post.CalendarEntry{Post: p}
+		if p.Status == post.StatusScheduled {
+			entry.Conflicts = m.conflicts(p, userID, role)
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortStableFunc(entries, func(a, b *post.CalendarEntry) int {
+		return a.Post.PublishedAt.Compare(*b.Post.PublishedAt)
+	})
+	return entries, nil
+}
+
+// conflicts finds the posts due within an hour of a post
+func (m *MockPostService) conflicts(p *post.Post, userID int, role user.Role) []post.Conflict {
+	var conflicts []post.Conflict
+	for _, id := range slices.Sorted(maps.Keys(m.posts)) {
+		other := m.posts[id]
+		if other.Status == post.StatusDraft || len(post.FindConflicts(p, []*post.Post{other}, time.Hour)) == 0 {
+			continue
+		}
+		conflict := post.Conflict{PublishAt: *other.PublishedAt}
+		if other.IsVisibleTo(userID, role) {
+			conflict.PostID = other.ID
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+func (m *MockPostService) ListRevisions(ctx context.Context, userID int, role user.Role, postID int) (*post.Post, []*post.Revision, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, nil, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, nil, post.ErrUnauthorized
+	}
+	revisions := slices.Clone(m.revisions[postID])
+	slices.Reverse(revisions)
+	return p, revisions, nil
+}
+
+func (m *MockPostService) RestoreRevision(ctx context.Context, userID int, role user.Role, postID, number int) (*post.Post, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, post.ErrPostNotFound
+	}
+	if !p.IsAuthor(userID) {
+		return nil, post.ErrUnauthorized
+	}
+	revisions := m.revisions[postID]
+	if number < 1 || number > len(revisions) {
+		return nil, post.ErrRevisionNotFound
+	}
+	restored := revisions[number-1]
+	m.addRevision(p, userID)
+	if err := p.Update(restored.Title, restored.Content); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (m *MockPostService) TranslatePost(ctx context.Context, userID int, role user.Role, postID int, language, title, content string) (*post.Post, bool, error) {
+	p, exists := m.posts[postID]
+	if !exists {
+		return nil, false, post.ErrPostNotFound
+	}
+	if !p.CanModify(userID, role) {
+		return nil, false, post.ErrUnauthorized
+	}
+	t, err := post.NewTranslation(p, language, title, content, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if m.translations[postID] == nil {
+		m.translations[postID] = make(map[string]*post.Translation)
+	}
+	_, replaced := m.translations[postID][t.Language]
+	if !replaced {
+		p.Translations = append(p.Translations, t.Language)
+		slices.Sort(p.Translations)
+	}
+	m.translations[postID][t.Language] = t
+	return p.Translated(t), !replaced, nil
+}
+
+func (m *MockPostService) LocalizePost(ctx context.Context, p *post.Post, acceptLanguage string) (*post.Post, error) {
+	language := p.NegotiateLanguage(acceptLanguage)
+	if language == p.Language {
+		return p, nil
+	}
+	t, exists := m.translations[p.ID][language]
+	if !exists {
+		return nil, post.ErrTranslationNotFound
+	}
+	return p.Translated(t), nil
+}
+
+// addRevision keeps the current version of a post before it changes
+func (m *MockPostService) addRevision(p *post.Post, userID int) {
+	rev := post.NewRevision(p, userID)
+	rev.Number = len(m.revisions[p.ID]) + 1
+	m.revisions[p.ID] = append(m.revisions[p.ID], rev)
+}
+func (m *MockPostService) DeletePost(ctx context.Context, userID int, role user.Role, postID int) error {
 	p, exists := m.posts[postID]
 	if !exists {
 		return post.ErrPostNotFound
 	}
-	if p.AuthorID != userID {
+	if !p.CanModify(userID, role) {
 		return post.ErrUnauthorized
 	}
 	delete(m.posts, postID)
@@ -173,6 +670,58 @@ func (m *MockUserService) List(ctx context.Context, limit, offset int) ([]*user.
 	return nil, nil // Not needed for post tests
 }
 
+func (m *MockUserService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) RequestMagicLink(ctx context.Context, email string, device user.Device) error {
+	return nil // Not needed for post tests
+}
+
+func (m *MockUserService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) RegisterPasswordless(ctx context.Context, name, email, method string) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) CompleteLogin(ctx context.Context, id int, method string) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	return nil // Not needed for post tests
+}
+
+func (m *MockUserService) ConfirmEmailChange(ctx context.Context, token string) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) Delete(ctx context.Context, id int) error {
+	return nil // Not needed for post tests
+}
+
+func (m *MockUserService) ScheduleDeletion(ctx context.Context, id int) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) SyncProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
+func (m *MockUserService) Deactivate(ctx context.Context, id int) error {
+	return nil // Not needed for post tests
+}
+
+func (m *MockUserService) Reactivate(ctx context.Context, id int) error {
+	return nil // Not needed for post tests
+}
+
+func (m *MockUserService) ChangeRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	return nil, nil // Not needed for post tests
+}
+
 // MockAuthService for authentication
 type MockAuthService struct {
 	userService *MockUserService
@@ -198,26 +747,47 @@ func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*aut
 	return nil, auth.ErrInvalidToken
 }
 
-func (m *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, string, error) {
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, *auth.TokenPair, error) {
 	u, err := m.userService.Login(ctx, email, password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	token, _ := m.GenerateToken(ctx, u)
-	return u, token, nil
+	return u, m.mockTokenPair(ctx, u), nil
 }
 
-func (m *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, string, error) {
+func (m *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, *auth.TokenPair, error) {
 	u, err := m.userService.Register(ctx, name, email, password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	token, _ := m.GenerateToken(ctx, u)
-	return u, token, nil
+	return u, m.mockTokenPair(ctx, u), nil
+}
+
+func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	if refreshToken != "mock-refresh-token" {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	return &auth.TokenPair{
+		AccessToken:  "mock-refreshed-token",
+		RefreshToken: "mock-rotated-refresh-token",
+		ExpiresIn:    15 * time.Minute,
+	}, nil
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	if refreshToken != "" && refreshToken != "mock-refresh-token" {
+		return auth.ErrInvalidRefreshToken
+	}
+	return nil
 }
 
-func (m *MockAuthService) RefreshToken(ctx context.Context, token string) (string, error) {
-	return "mock-refreshed-token", nil
+func (m *MockAuthService) mockTokenPair(ctx context.Context, u *user.User) *auth.TokenPair {
+	token, _ := m.GenerateToken(ctx, u)
+	return &auth.TokenPair{
+		AccessToken:  token,
+		RefreshToken: "mock-refresh-token",
+		ExpiresIn:    15 * time.Minute,
+	}
 }
 
 // MockLogger implements the service.Logger interface for testing
@@ -239,7 +809,7 @@ func setupTestServer() (*echo.Echo, *handlers.PostHandler) {
 	postService := NewMockPostService()
 	logger := NewMockLogger()
 	
-	postHandler := handlers.NewPostHandler(postService, logger)
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, logger)
 	
 	return e, postHandler
 }
@@ -374,6 +944,65 @@ func TestPostHandler_GetPost_Success(t *testing.T) {
 	assert.Equal(t, "This is a test post content with more than 10 characters.", response.Content)
 }
 
+func TestPostHandler_GetPost_ConditionalRequests(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Polled Post",
+		Content: "This is a test post content with more than 10 characters.",
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	id := strconv.Itoa(created.ID)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+id, nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, postHandler.GetPost(c))
+		return rec
+	}
+
+	first := get("", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get(echo.HeaderLastModified)
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, lastModified)
+
+	notModified := get("If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.Bytes())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotModified, get(echo.HeaderIfModifiedSince, lastModified).Code)
+	assert.Equal(t, http.StatusOK, get("If-None-Match", `"stale"`).Code)
+	assert.Equal(t, http.StatusOK, get(echo.HeaderIfModifiedSince, "Mon, 01 Jan 2001 00:00:00 GMT").Code)
+
+	// Changed posts are sent again
+	updateBody, err := json.Marshal(handlers.UpdatePostRequest{
+		Title:   "Polled Post, Updated",
+		Content: "This is updated test post content with more than 10 characters.",
+	})
+	require.NoError(t, err)
+	_, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+id, updateBody)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	require.NoError(t, postHandler.UpdatePost(c))
+
+	changed := get("If-None-Match", etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
 func TestPostHandler_GetPost_NotFound(t *testing.T) {
 	e, postHandler := setupTestServer()
 	
@@ -404,6 +1033,62 @@ func TestPostHandler_GetPost_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestPostHandler_ContentFormat(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	// Markdown content may hold angle brackets and HTML, shown as text
+	content := "Use **a < b** when x<y.\n\n<script>alert(1)</script>"
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{Title: "Comparisons", Content: content})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, content, created.Content)
+
+	getPost := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+strconv.Itoa(created.ID)+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(created.ID))
+		require.NoError(t, postHandler.GetPost(c))
+		return rec
+	}
+
+	var response handlers.PostResponse
+	rec = getPost("?format=markdown")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, content, response.Content)
+	assert.Empty(t, response.ContentFormat)
+
+	rec = getPost("?format=html")
+	require.Equal(t, http.StatusOK, rec.Code)
+	response = handlers.PostResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, handlers.ContentFormatHTML, response.ContentFormat)
+	assert.Equal(t, "<p>Use <strong>a &lt; b</strong> when x&lt;y.</p>\n<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n", response.Content)
+
+	rec = getPost("?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Lists render every post
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?format=html", nil)
+	rec = httptest.NewRecorder()
+	require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.PostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.NotEmpty(t, list.Posts)
+	for _, p := range list.Posts {
+		assert.Equal(t, handlers.ContentFormatHTML, p.ContentFormat)
+		assert.True(t, strings.HasPrefix(p.Content, "<p>"), p.Content)
+	}
+}
+
 func TestPostHandler_ListPosts_Success(t *testing.T) {
 	e, postHandler := setupTestServer()
 	
@@ -441,6 +1126,157 @@ func TestPostHandler_ListPosts_Success(t *testing.T) {
 	assert.Equal(t, 0, response.Offset)
 }
 
+func TestPostHandler_ListPosts_ReadingStats(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	content := "A short introduction.\n\n" + strings.Repeat("More words follow here. ", 60)
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{Title: "Long Read", Content: content})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	rec = httptest.NewRecorder()
+	require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var list handlers.PostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Posts, 1)
+	assert.Equal(t, 243, list.Posts[0].WordCount)
+	assert.Equal(t, 2, list.Posts[0].ReadingTimeMinutes)
+	assert.Equal(t, "A short introduction.", list.Posts[0].Excerpt)
+}
+
+func TestPostHandler_ListPosts_Cursor(t *testing.T) {
+	e, postHandler := setupTestServer()
+	
+	for i := 1; i <= 3; i++ {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   fmt.Sprintf("Test Post %d", i),
+			Content: fmt.Sprintf("This is test post content %d with more than 10 characters.", i),
+		})
+		require.NoError(t, err)
+		_, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+	}
+	
+	list := func(query string) (*httptest.ResponseRecorder, handlers.PostListResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+		var response handlers.PostListResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+	
+	// An empty cursor opts into cursor pagination
+	rec, first := list("cursor=&limit=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, first.Posts, 2)
+	assert.Equal(t, "Test Post 3", first.Posts[0].Title)
+	assert.NotEmpty(t, first.NextCursor)
+	
+	rec, second := list("cursor=" + first.NextCursor + "&limit=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, second.Posts, 1)
+	assert.Equal(t, "Test Post 1", second.Posts[0].Title)
+	assert.Empty(t, second.NextCursor)
+	
+	// Offset pagination leaves next_cursor out
+	rec, _ = list("limit=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "next_cursor")
+	
+	rec, _ = list("cursor=garbage!")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPostHandler_ListPosts_SortAndFilter(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	for _, title := range []string{"Banana Bread", "Apple Pie", "Cherry Tart"} {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   title,
+			Content: "This is test post content with more than 10 characters.",
+		})
+		require.NoError(t, err)
+		_, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, []string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+		var response handlers.PostListResponse
+		var titles []string
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			for _, p := range response.Posts {
+				titles = append(titles, p.Title)
+			}
+		}
+		return rec, titles
+	}
+
+	rec, titles := list("sort=title&order=asc")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"Apple Pie", "Banana Bread", "Cherry Tart"}, titles)
+
+	rec, titles = list("author_id=1&created_after=2000-01-01T00:00:00Z&created_before=2100-01-01T00:00:00%2B02:00")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, titles, 3)
+
+	rec, titles = list("author_id=2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, titles)
+
+	for _, query := range []string{
+		"sort=views",
+		"order=up",
+		"author_id=abc",
+		"author_id=0",
+		"created_after=yesterday",
+		"created_after=2030-01-02T00:00:00Z&created_before=2030-01-01T00:00:00Z",
+		"cursor=&sort=title",
+	} {
+		rec, _ = list(query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestPostHandler_ListPosts_PageLimits(t *testing.T) {
+	e := echo.New()
+	postHandler := handlers.NewPostHandler(NewMockPostService(), NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(NewMockPostService()), service.PageLimits{Default: 5, Max: 20}, NewMockLogger())
+
+	tests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", 5, 0},
+		{"?limit=abc&offset=-1", 5, 0},
+		{"?limit=15&offset=3", 15, 3},
+		{"?limit=500", 20, 0},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response handlers.PostListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, tt.wantLimit, response.Limit, tt.query)
+		assert.Equal(t, tt.wantOffset, response.Offset, tt.query)
+	}
+}
+
 func TestPostHandler_UpdatePost_Success(t *testing.T) {
 	e, postHandler := setupTestServer()
 	
@@ -617,3 +1453,862 @@ func TestPostHandler_DeletePost_NotFound(t *testing.T) {
 	
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
+
+func TestPostHandler_DeletePost_AdminOverride(t *testing.T) {
+	e, postHandler := setupTestServer()
+	
+	// First create a post
+	createReq := handlers.CreatePostRequest{
+		Title:   "Test Post",
+		Content: "This is a test post content with more than 10 characters.",
+	}
+	
+	reqBody, err := json.Marshal(createReq)
+	require.NoError(t, err)
+	
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	err = postHandler.CreatePost(c)
+	require.NoError(t, err)
+	
+	var createResponse handlers.PostResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &createResponse)
+	require.NoError(t, err)
+	
+	// Delete as an admin who is not the author
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/posts/"+strconv.Itoa(createResponse.ID), nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.Set("user_id", 999)
+	c.Set("user_role", user.RoleAdmin)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(createResponse.ID))
+	
+	err = postHandler.DeletePost(c)
+	require.NoError(t, err)
+	
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestPostHandler_SetPostIndexing(t *testing.T) {
+	e, postHandler := setupTestServer()
+	
+	// First create a post
+	createReq := handlers.CreatePostRequest{
+		Title:   "Test Post",
+		Content: "This is a test post content with more than 10 characters.",
+	}
+	
+	reqBody, err := json.Marshal(createReq)
+	require.NoError(t, err)
+	
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	err = postHandler.CreatePost(c)
+	require.NoError(t, err)
+	
+	var createResponse handlers.PostResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &createResponse)
+	require.NoError(t, err)
+	assert.False(t, createResponse.NoIndex)
+	postID := strconv.Itoa(createResponse.ID)
+	
+	// Another user may not change the flag
+	reqBody, err = json.Marshal(handlers.PostIndexingRequest{NoIndex: true})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID+"/indexing", reqBody)
+	c.Set("user_id", 999)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.SetPostIndexing(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	
+	// The author flags the post noindex
+	rec, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID+"/indexing", reqBody)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.SetPostIndexing(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	
+	var response handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.NoIndex)
+	
+	// Reading the post through the API carries the crawl directive
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.GetPost(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+}
+
+func TestPostHandler_SetPostAccess(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Members Post",
+		Content: "The opening paragraph.\n\nThe rest is for members only.",
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "public", created.Access)
+	postID := strconv.Itoa(created.ID)
+
+	setAccess := func(userID int, access string) *httptest.ResponseRecorder {
+		reqBody, err := json.Marshal(handlers.PostAccessRequest{Access: access})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID+"/access", reqBody)
+		c.Set("user_id", userID)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.SetPostAccess(c))
+		return rec
+	}
+	assert.Equal(t, http.StatusForbidden, setAccess(999, "members").Code)
+	assert.Equal(t, http.StatusBadRequest, setAccess(1, "private").Code)
+	assert.Equal(t, http.StatusBadRequest, setAccess(1, "").Code)
+	require.Equal(t, http.StatusOK, setAccess(1, "members").Code)
+
+	getPost := func(userID int) (*httptest.ResponseRecorder, handlers.PostResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.GetPost(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec, response
+	}
+
+	// Anonymous readers get the excerpt and the lock indicator
+	rec, response := getPost(0)
+	assert.True(t, response.Locked)
+	assert.Equal(t, "members", response.Access)
+	assert.Equal(t, "The opening paragraph.", response.Content)
+	assert.Equal(t, "private", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "Authorization", rec.Header().Get("Vary"))
+
+	// Signed-in readers get the full content
+	_, response = getPost(2)
+	assert.False(t, response.Locked)
+	assert.Contains(t, response.Content, "for members only")
+
+	// Listings are gated too
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+	rec = httptest.NewRecorder()
+	require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+	var list handlers.PostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Posts, 1)
+	assert.True(t, list.Posts[0].Locked)
+	assert.Equal(t, "The opening paragraph.", list.Posts[0].Content)
+}
+
+func TestPostHandler_SetPostVisibility(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Quiet Post",
+		Content: "This is a test post content with more than 10 characters.",
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "public", created.Visibility)
+	postID := strconv.Itoa(created.ID)
+
+	setVisibility := func(userID int, visibility string) *httptest.ResponseRecorder {
+		reqBody, err := json.Marshal(handlers.PostVisibilityRequest{Visibility: visibility})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID+"/visibility", reqBody)
+		c.Set("user_id", userID)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.SetPostVisibility(c))
+		return rec
+	}
+	getPost := func(userID int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.GetPost(c))
+		return rec
+	}
+	assert.Equal(t, http.StatusForbidden, setVisibility(999, "private").Code)
+	assert.Equal(t, http.StatusBadRequest, setVisibility(1, "hidden").Code)
+	assert.Equal(t, http.StatusBadRequest, setVisibility(1, "").Code)
+
+	// Unlisted posts are read by link but kept out of search results
+	rec = setVisibility(1, "unlisted")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "unlisted", response.Visibility)
+	rec = getPost(0)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+
+	// Private posts are hidden from everyone but the author
+	require.Equal(t, http.StatusOK, setVisibility(1, "private").Code)
+	assert.Equal(t, http.StatusNotFound, getPost(0).Code)
+	assert.Equal(t, http.StatusNotFound, getPost(2).Code)
+	assert.Equal(t, http.StatusOK, getPost(1).Code)
+}
+
+func TestPostHandler_TranslatePost(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Hello World",
+		Content: "This is a test post content with more than 10 characters.",
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "en", created.Language)
+	assert.Equal(t, []string{}, created.Translations)
+	postID := strconv.Itoa(created.ID)
+
+	translate := func(userID int, language, title string) *httptest.ResponseRecorder {
+		reqBody, err := json.Marshal(handlers.TranslatePostRequest{
+			Language: language,
+			Title:    title,
+			Content:  "Ceci est le contenu traduit de plus de 10 caractères.",
+		})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/translations", reqBody)
+		c.Set("user_id", userID)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.TranslatePost(c))
+		return rec
+	}
+	getPost := func(acceptLanguage string) (*httptest.ResponseRecorder, handlers.PostResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.GetPost(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec, response
+	}
+	assert.Equal(t, http.StatusForbidden, translate(999, "fr", "Bonjour").Code)
+	assert.Equal(t, http.StatusBadRequest, translate(1, "not a language", "Bonjour").Code)
+	assert.Equal(t, http.StatusBadRequest, translate(1, "en", "Hello").Code)
+	assert.Equal(t, http.StatusBadRequest, translate(1, "", "Bonjour").Code)
+
+	rec = translate(1, "fr", "Bonjour")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "fr", rec.Header().Get("Content-Language"))
+	var translated handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &translated))
+	assert.Equal(t, "fr", translated.Language)
+	assert.Equal(t, "Bonjour", translated.Title)
+	assert.Equal(t, []string{"en"}, translated.Translations)
+
+	// Translating again replaces the translation
+	assert.Equal(t, http.StatusOK, translate(1, "fr", "Salut").Code)
+
+	// Readers get the language they prefer, or the post's own
+	rec, response := getPost("fr-CH, fr;q=0.9, en;q=0.8")
+	assert.Equal(t, "Salut", response.Title)
+	assert.Equal(t, "fr", response.Language)
+	assert.Equal(t, "fr", rec.Header().Get("Content-Language"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Accept-Language")
+
+	rec, response = getPost("")
+	assert.Equal(t, "Hello World", response.Title)
+	assert.Equal(t, []string{"fr"}, response.Translations)
+	assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+
+	_, response = getPost("ja, en-GB;q=0.5")
+	assert.Equal(t, "en", response.Language)
+}
+
+func TestPostHandler_DraftsAndPublishing(t *testing.T) {
+	e, postHandler := setupTestServer()
+	
+	// The author saves a draft
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Draft Post",
+		Content: "This is a draft post content with more than 10 characters.",
+		Draft:   true,
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	
+	var draft handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &draft))
+	assert.Equal(t, "draft", draft.Status)
+	assert.Empty(t, draft.PublishedAt)
+	postID := strconv.Itoa(draft.ID)
+	
+	get := func(userID int) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if userID != 0 {
+			c.Set("user_id", userID)
+		}
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.GetPost(c))
+		return rec.Code
+	}
+	list := func(userID int) []handlers.PostResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if userID != 0 {
+			c.Set("user_id", userID)
+		}
+		require.NoError(t, postHandler.ListPosts(c))
+		var response handlers.PostListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Posts
+	}
+	
+	// Only the author sees the draft
+	assert.Equal(t, http.StatusNotFound, get(0))
+	assert.Equal(t, http.StatusNotFound, get(999))
+	assert.Equal(t, http.StatusOK, get(1))
+	assert.Empty(t, list(0))
+	assert.Len(t, list(1), 1)
+	
+	// Scheduling needs a future time
+	reqBody, err = json.Marshal(handlers.SchedulePostRequest{PublishAt: time.Now().Add(-time.Hour).Format(time.RFC3339)})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/schedule", reqBody)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.SchedulePost(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	
+	publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	reqBody, err = json.Marshal(handlers.SchedulePostRequest{PublishAt: publishAt.Format(time.RFC3339)})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/schedule", reqBody)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.SchedulePost(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	
+	var scheduled handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scheduled))
+	assert.Equal(t, "scheduled", scheduled.Status)
+	assert.Equal(t, publishAt.Format(time.RFC3339), scheduled.PublishedAt)
+	assert.Equal(t, http.StatusNotFound, get(0))
+	
+	// Other users may not publish it
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/publish", nil)
+	c.Set("user_id", 999)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.PublishPost(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	
+	// The author publishes it right away
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/publish", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.PublishPost(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	
+	var published handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &published))
+	assert.Equal(t, "published", published.Status)
+	assert.Equal(t, http.StatusOK, get(0))
+	assert.Len(t, list(0), 1)
+	
+	// Publishing twice is a conflict
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/publish", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.PublishPost(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestPostHandler_ScheduleCalendar(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	// Two years ahead, in winter, so Berlin is at UTC+1
+	year := time.Now().Year() + 2
+	day := fmt.Sprintf("%d-01-15", year)
+
+	schedule := func(title, publishAt, timezone string) (*httptest.ResponseRecorder, handlers.SchedulePostResponse) {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   title,
+			Content: "This is a draft post content with more than 10 characters.",
+			Draft:   true,
+		})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var draft handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &draft))
+		postID := strconv.Itoa(draft.ID)
+
+		reqBody, err = json.Marshal(handlers.SchedulePostRequest{PublishAt: publishAt, Timezone: timezone})
+		require.NoError(t, err)
+		rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/schedule", reqBody)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.SchedulePost(c))
+		var response handlers.SchedulePostResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+
+	// Local times are placed in the post's time zone
+	rec, first := schedule("Morning Post", day+"T09:00", "Europe/Berlin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, day+"T08:00:00Z", first.PublishedAt)
+	assert.Equal(t, "Europe/Berlin", first.Timezone)
+	assert.Empty(t, first.Warnings)
+
+	// A post half an hour later is scheduled with a warning
+	rec, second := schedule("Second Post", day+"T08:30:00Z", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, second.Warnings, 1)
+	assert.Equal(t, first.ID, second.Warnings[0].ConflictsWith)
+	assert.Contains(t, second.Warnings[0].Message, "after post")
+
+	rec, _ = schedule("Bad Zone", day+"T09:00", "Mars/Olympus")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = schedule("Bad Time", "tomorrow", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	calendar := func(query string) (*httptest.ResponseRecorder, handlers.CalendarResponse) {
+		rec, c := setupAuthenticatedRequest(e, http.MethodGet, "/api/v1/posts/calendar?"+query, nil)
+		require.NoError(t, postHandler.Calendar(c))
+		var response handlers.CalendarResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+
+	rec, response := calendar("from=" + day + "&to=" + fmt.Sprintf("%d-01-16", year) + "&timezone=Europe/Berlin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, fmt.Sprintf("%d-01-14T23:00:00Z", year), response.From)
+	require.Len(t, response.Entries, 2)
+	assert.Equal(t, first.ID, response.Entries[0].ID)
+	assert.Equal(t, day+"T09:00:00+01:00", response.Entries[0].LocalPublishAt)
+	// Both scheduled posts are warned about
+	assert.Len(t, response.Warnings, 2)
+
+	rec, _ = calendar("from=" + day + "&to=" + fmt.Sprintf("%d-06-01", year))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = calendar("from=yesterday")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = calendar("timezone=Mars/Olympus")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPostHandler_Views(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	var ids []string
+	for _, title := range []string{"Quiet Post", "Popular Post"} {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   title,
+			Content: "This is a test post content with more than 10 characters.",
+		})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var created handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Zero(t, created.ViewCount)
+		ids = append(ids, strconv.Itoa(created.ID))
+	}
+
+	get := func(postID string) handlers.PostResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+postID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.GetPost(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	get(ids[0])
+	get(ids[1])
+	assert.Equal(t, int64(2), get(ids[1]).ViewCount)
+
+	popular := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/popular?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListPopularPosts(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := popular("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.PopularPostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 7, response.Days)
+	require.Len(t, response.Posts, 2)
+	assert.Equal(t, "Popular Post", response.Posts[0].Title)
+	assert.Equal(t, int64(2), response.Posts[0].Views)
+
+	rec = popular("days=30&limit=1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Posts, 1)
+
+	assert.Equal(t, http.StatusBadRequest, popular("days=week").Code)
+	assert.Equal(t, http.StatusBadRequest, popular("days=365").Code)
+
+	trending := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/trending?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListTrendingPosts(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec = trending("")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trendingResponse handlers.TrendingPostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trendingResponse))
+	assert.Equal(t, post.DefaultTrendingLimit, trendingResponse.Limit)
+	require.Len(t, trendingResponse.Posts, 2)
+	assert.Equal(t, "Popular Post", trendingResponse.Posts[0].Title)
+	assert.Greater(t, trendingResponse.Posts[0].Score, trendingResponse.Posts[1].Score)
+
+	rec = trending("limit=500")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trendingResponse))
+	assert.Equal(t, post.MaxTrendingLimit, trendingResponse.Limit)
+
+	assert.Equal(t, http.StatusBadRequest, trending("limit=0").Code)
+	assert.Equal(t, http.StatusBadRequest, trending("limit=all").Code)
+}
+
+func TestPostHandler_ListRelatedPosts(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	create := func(title string, draft bool, tags ...string) int {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   title,
+			Content: "This is a test post content with more than 10 characters.",
+			Tags:    tags,
+			Draft:   draft,
+		})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var created handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		return created.ID
+	}
+	source := create("Source Post", false, "go")
+	related := create("Related Post", false, "go")
+	create("Unrelated Post", false, "cooking")
+	draft := create("Draft Post", true, "go")
+
+	listAs := func(userID int, id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/"+id+"/related?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if userID != 0 {
+			c.Set("user_id", userID)
+		}
+		require.NoError(t, postHandler.ListRelatedPosts(c))
+		return rec
+	}
+	list := func(id, query string) *httptest.ResponseRecorder {
+		return listAs(0, id, query)
+	}
+
+	rec := list(strconv.Itoa(source), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response handlers.RelatedPostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Posts, 1)
+	assert.Equal(t, related, response.Posts[0].ID)
+	assert.Equal(t, post.DefaultRelatedLimit, response.Limit)
+
+	assert.Equal(t, http.StatusNotFound, list("999", "").Code)
+	assert.Equal(t, http.StatusBadRequest, list("invalid", "").Code)
+
+	// Drafts only have related posts for their author
+	assert.Equal(t, http.StatusNotFound, list(strconv.Itoa(draft), "").Code)
+	assert.Equal(t, http.StatusNotFound, listAs(2, strconv.Itoa(draft), "").Code)
+	assert.Equal(t, http.StatusOK, listAs(1, strconv.Itoa(draft), "").Code)
+	assert.Equal(t, http.StatusBadRequest, list(strconv.Itoa(source), "limit=0").Code)
+	assert.Equal(t, http.StatusBadRequest, list(strconv.Itoa(source), "limit=many").Code)
+}
+
+func TestPostHandler_Curation(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	create := func(title string, draft bool) string {
+		reqBody, err := json.Marshal(handlers.CreatePostRequest{
+			Title:   title,
+			Content: "This is a test post content with more than 10 characters.",
+			Draft:   draft,
+		})
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+		require.NoError(t, postHandler.CreatePost(c))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var created handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		return strconv.Itoa(created.ID)
+	}
+	first := create("First Post", false)
+	second := create("Second Post", false)
+	draft := create("Draft Post", true)
+
+	curate := func(method, id, change string, handle func(echo.Context) error) (*httptest.ResponseRecorder, handlers.PostResponse) {
+		rec, c := setupAuthenticatedRequest(e, method, "/api/v1/admin/posts/"+id+"/"+change, nil)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handle(c))
+		var response handlers.PostResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+
+	rec, response := curate(http.MethodPost, first, "pin", postHandler.PinPost)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, response.Pinned)
+	assert.False(t, response.Featured)
+
+	rec, response = curate(http.MethodDelete, first, "pin", postHandler.UnpinPost)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.False(t, response.Pinned)
+
+	for _, id := range []string{first, second} {
+		rec, response = curate(http.MethodPost, id, "feature", postHandler.FeaturePost)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.True(t, response.Featured)
+	}
+	rec, response = curate(http.MethodDelete, first, "feature", postHandler.UnfeaturePost)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.False(t, response.Featured)
+
+	// Drafts cannot be curated, and unknown posts are not found
+	rec, _ = curate(http.MethodPost, draft, "pin", postHandler.PinPost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = curate(http.MethodPost, draft, "feature", postHandler.FeaturePost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = curate(http.MethodPost, "999", "pin", postHandler.PinPost)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = curate(http.MethodPost, "invalid", "feature", postHandler.FeaturePost)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// The featured list holds only the posts still featured
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/featured", nil)
+	rec = httptest.NewRecorder()
+	require.NoError(t, postHandler.ListFeaturedPosts(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var list handlers.PostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Posts, 1)
+	assert.Equal(t, second, strconv.Itoa(list.Posts[0].ID))
+	assert.True(t, list.Posts[0].Featured)
+}
+
+func TestPostHandler_Tags(t *testing.T) {
+	e, postHandler := setupTestServer()
+
+	// Posts are created with normalized tags
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Tagged Post",
+		Content: "This is a tagged post content with more than 10 characters.",
+		Tags:    []string{"Web Development", "GoLang"},
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, []string{"golang", "web-development"}, created.Tags)
+	postID := strconv.Itoa(created.ID)
+
+	// Untagged posts list an empty array
+	reqBody, err = json.Marshal(handlers.CreatePostRequest{
+		Title:   "Untagged Post",
+		Content: "This is an untagged post content with more than 10 characters.",
+	})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"tags":[]`)
+
+	listByTag := func(name string) []handlers.PostResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?tag="+name, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.PostListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Posts
+	}
+
+	posts := listByTag("golang")
+	require.Len(t, posts, 1)
+	assert.Equal(t, created.ID, posts[0].ID)
+	assert.Empty(t, listByTag("rust"))
+
+	// Updates replace the tags only when they are sent
+	update := func(body map[string]any) *httptest.ResponseRecorder {
+		reqBody, err := json.Marshal(body)
+		require.NoError(t, err)
+		rec, c := setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID, reqBody)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.UpdatePost(c))
+		return rec
+	}
+	content := "This is the updated content with more than 10 characters."
+
+	rec = update(map[string]any{"title": "Tagged Post", "content": content})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, listByTag("golang"), 1)
+
+	rec = update(map[string]any{"title": "Tagged Post", "content": content, "tags": []string{"rust"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, listByTag("golang"))
+	assert.Len(t, listByTag("rust"), 1)
+
+	// Invalid tags are rejected before anything is saved
+	rec = update(map[string]any{"title": "Renamed Post", "content": content, "tags": []string{"c++"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Len(t, listByTag("rust"), 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts?tag=c%2B%2B", nil)
+	rec = httptest.NewRecorder()
+	require.NoError(t, postHandler.ListPosts(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPostHandler_Revisions(t *testing.T) {
+	e, postHandler := setupTestServer()
+	
+	reqBody, err := json.Marshal(handlers.CreatePostRequest{
+		Title:   "Original Title",
+		Content: "First line\nSecond line\nThird line",
+	})
+	require.NoError(t, err)
+	rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts", reqBody)
+	require.NoError(t, postHandler.CreatePost(c))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	
+	var created handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	postID := strconv.Itoa(created.ID)
+	
+	reqBody, err = json.Marshal(handlers.UpdatePostRequest{
+		Title:   "Original Title",
+		Content: "First line\nChanged line\nThird line",
+	})
+	require.NoError(t, err)
+	rec, c = setupAuthenticatedRequest(e, http.MethodPut, "/api/v1/posts/"+postID, reqBody)
+	c.SetParamNames("id")
+	c.SetParamValues(postID)
+	require.NoError(t, postHandler.UpdatePost(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	
+	listRevisions := func(userID int) *httptest.ResponseRecorder {
+		rec, c := setupAuthenticatedRequest(e, http.MethodGet, "/api/v1/posts/"+postID+"/revisions", nil)
+		c.Set("user_id", userID)
+		c.SetParamNames("id")
+		c.SetParamValues(postID)
+		require.NoError(t, postHandler.ListRevisions(c))
+		return rec
+	}
+	
+	// The revision diffs to the version that replaced it
+	rec = listRevisions(1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var history handlers.PostRevisionListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	assert.Equal(t, created.ID, history.PostID)
+	require.Len(t, history.Revisions, 1)
+	assert.Equal(t, 1, history.Revisions[0].Number)
+	assert.Equal(t, "First line\nSecond line\nThird line", history.Revisions[0].Content)
+	assert.False(t, history.Revisions[0].TitleChanged)
+	assert.Equal(t, []handlers.DiffLineResponse{
+		{Op: "=", Text: "First line"},
+		{Op: "-", Text: "Second line"},
+		{Op: "+", Text: "Changed line"},
+		{Op: "=", Text: "Third line"},
+	}, history.Revisions[0].Changes)
+	
+	assert.Equal(t, http.StatusForbidden, listRevisions(999).Code)
+	
+	restore := func(userID int, rev string) *httptest.ResponseRecorder {
+		rec, c := setupAuthenticatedRequest(e, http.MethodPost, "/api/v1/posts/"+postID+"/revisions/"+rev+"/restore", nil)
+		c.Set("user_id", userID)
+		c.SetParamNames("id", "rev")
+		c.SetParamValues(postID, rev)
+		require.NoError(t, postHandler.RestoreRevision(c))
+		return rec
+	}
+	
+	assert.Equal(t, http.StatusForbidden, restore(999, "1").Code)
+	assert.Equal(t, http.StatusNotFound, restore(1, "5").Code)
+	assert.Equal(t, http.StatusBadRequest, restore(1, "latest").Code)
+	
+	rec = restore(1, "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var restored handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &restored))
+	assert.Equal(t, "First line\nSecond line\nThird line", restored.Content)
+	
+	// The restore itself is a new revision
+	require.NoError(t, json.Unmarshal(listRevisions(1).Body.Bytes(), &history))
+	require.Len(t, history.Revisions, 2)
+	assert.Equal(t, "First line\nChanged line\nThird line", history.Revisions[0].Content)
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockProfileService implements user.ProfileService for testing
+type MockProfileService struct {
+	profiles map[int]*user.Profile
+}
+
+func NewMockProfileService() *MockProfileService {
+	return &MockProfileService{profiles: make(map[int]*user.Profile)}
+}
+
+func (m *MockProfileService) GetProfile(ctx context.Context, id int) (*user.Profile, error) {
+	if p, ok := m.profiles[id]; ok {
+		return p, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (m *MockProfileService) GetProfiles(ctx context.Context, ids []int) (map[int]*user.Profile, error) {
+	profiles := make(map[int]*user.Profile)
+	for _, id := range ids {
+		if p, ok := m.profiles[id]; ok {
+			profiles[id] = p
+		}
+	}
+	return profiles, nil
+}
+
+func (m *MockProfileService) UpdateProfile(ctx context.Context, id int, bio, avatarURL string) (*user.Profile, error) {
+	bio, avatarURL, err := user.NormalizeProfile(bio, avatarURL)
+	if err != nil {
+		return nil, err
+	}
+	p, err := m.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Bio, p.AvatarURL = bio, avatarURL
+	return p, nil
+}
+
+func setupProfileTestServer() (*echo.Echo, *MockProfileService, *MockPostService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	profileService := NewMockProfileService()
+	profileService.profiles[1] = &user.Profile{ID: 1, Name: "Author One", Bio: "Writes about Go.", JoinedAt: fixtures.Time, PostCount: 2}
+	postService := NewMockPostService()
+	postService.posts[1] = fixtures.Post(1, 1)
+	postService.posts[2] = fixtures.Post(2, 2)
+
+	h := handlers.NewProfileHandler(profileService, NewMockLogger())
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), profileService, NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, NewMockLogger())
+
+	e.GET("/api/v1/users/:id", h.GetProfile)
+	e.PUT("/api/v1/users/me/profile", h.UpdateProfile, fixtures.AsUser)
+	e.GET("/api/v1/posts", postHandler.ListPosts)
+	e.GET("/api/v1/posts/:id", postHandler.GetPost)
+
+	return e, profileService, postService
+}
+
+func profileRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProfileHandler_GetProfile(t *testing.T) {
+	e, _, _ := setupProfileTestServer()
+
+	rec := profileRequest(e, http.MethodGet, "/api/v1/users/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.ProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Author One", response.Name)
+	assert.Equal(t, "Writes about Go.", response.Bio)
+	assert.Equal(t, 2, response.PostCount)
+	assert.True(t, fixtures.Time.Equal(response.JoinedAt))
+	assert.NotContains(t, rec.Body.String(), "email", "profiles are public and leave out the email")
+
+	rec = profileRequest(e, http.MethodGet, "/api/v1/users/99", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = profileRequest(e, http.MethodGet, "/api/v1/users/abc", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProfileHandler_UpdateProfile(t *testing.T) {
+	e, _, _ := setupProfileTestServer()
+
+	rec := profileRequest(e, http.MethodPut, "/api/v1/users/me/profile", `{"bio":"  Go and coffee.  ","avatar_url":"https://images.example.com/1.png"}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.ProfileResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Go and coffee.", response.Bio)
+	assert.Equal(t, "https://images.example.com/1.png", response.AvatarURL)
+
+	rec = profileRequest(e, http.MethodPut, "/api/v1/users/me/profile", `{"avatar_url":"http://images.example.com/1.png"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "avatars must be https URLs")
+	rec = profileRequest(e, http.MethodPut, "/api/v1/users/me/profile", `{"bio":"`+strings.Repeat("a", 501)+`"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = profileRequest(e, http.MethodPut, "/api/v1/users/me/profile", `{}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestPostHandler_IncludeAuthor(t *testing.T) {
+	e, _, _ := setupProfileTestServer()
+
+	rec := profileRequest(e, http.MethodGet, "/api/v1/posts/1?include=author", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var single handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &single))
+	require.NotNil(t, single.Author)
+	assert.Equal(t, "Author One", single.Author.Name)
+
+	rec = profileRequest(e, http.MethodGet, "/api/v1/posts/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"author"`, "authors are only embedded on request")
+
+	rec = profileRequest(e, http.MethodGet, "/api/v1/posts?include=author", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.PostListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Posts, 2)
+	for _, p := range list.Posts {
+		if p.AuthorID == 1 {
+			require.NotNil(t, p.Author)
+			assert.Equal(t, 1, p.Author.ID)
+		} else {
+			assert.Nil(t, p.Author, "authors without a profile are left out")
+		}
+	}
+
+	rec = profileRequest(e, http.MethodGet, "/api/v1/posts?include=comments", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
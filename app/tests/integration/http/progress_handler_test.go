@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockProgressService implements post.ProgressService for testing,
+// keeping the latest position per user and post
+type MockProgressService struct {
+	posts    *MockPostService
+	progress map[[2]int]*post.Progress
+}
+
+func NewMockProgressService() *MockProgressService {
+	return &MockProgressService{posts: NewMockPostService(), progress: make(map[[2]int]*post.Progress)}
+}
+
+func (m *MockProgressService) SaveProgress(ctx context.Context, userID, postID int, percent float64, anchor string, recordedAt time.Time) (*post.Progress, error) {
+	p, err := post.NewProgress(userID, postID, percent, anchor, recordedAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := m.posts.posts[postID]; !ok || !existing.IsPublished() {
+		return nil, post.ErrPostNotFound
+	}
+	key := [2]int{userID, postID}
+	if existing, ok := m.progress[key]; !ok || p.RecordedAt.After(existing.RecordedAt) {
+		m.progress[key] = p
+	}
+	return m.progress[key], nil
+}
+
+func (m *MockProgressService) GetProgress(ctx context.Context, userID, postID int) (*post.Progress, error) {
+	p, ok := m.progress[[2]int{userID, postID}]
+	if !ok {
+		return nil, post.ErrProgressNotFound
+	}
+	return p, nil
+}
+
+func (m *MockProgressService) ListReadingHistory(ctx context.Context, userID int, limit, offset int) ([]*post.ReadingEntry, error) {
+	var entries []*post.ReadingEntry
+	for key, p := range m.progress {
+		if key[0] == userID {
+			entry := &post.ReadingEntry{Progress: *p}
+			if existing, ok := m.posts.posts[key[1]]; ok {
+				entry.Title, entry.Slug = existing.Title, existing.Slug
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func setupProgressTestServer() (*echo.Echo, *MockProgressService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	progressService := NewMockProgressService()
+	progressService.posts.posts[1] = fixtures.Post(1, 1)
+	progressService.posts.posts[2] = fixtures.Draft(2, 1)
+	pagination := service.DefaultPaginationPolicy().Posts
+	h := handlers.NewProgressHandler(progressService, pagination, NewMockLogger())
+	postHandler := handlers.NewPostHandler(progressService.posts, progressService, NewMockProfileService(), NewMockRedirectService(progressService.posts), pagination, NewMockLogger())
+
+	e.PUT("/api/v1/posts/:id/progress", h.SaveProgress, fixtures.AsUser)
+	e.GET("/api/v1/users/me/reading-history", h.ReadingHistory, fixtures.AsUser)
+	e.GET("/api/v1/posts/:id", postHandler.GetPost, fixtures.AsUser)
+
+	return e, progressService
+}
+
+func progressRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProgressHandler_SaveProgress(t *testing.T) {
+	e, _ := setupProgressTestServer()
+	now := time.Now().UTC()
+
+	save := func(body string) handlers.ReadingProgressResponse {
+		rec := progressRequest(e, http.MethodPut, "/api/v1/posts/1/progress", body, "7")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response handlers.ReadingProgressResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	saved := save(`{"percent":70,"anchor":"part-3","recorded_at":"` + now.Format(time.RFC3339Nano) + `"}`)
+	assert.Equal(t, 1, saved.PostID)
+	assert.Equal(t, 70.0, saved.Percent)
+	assert.Equal(t, "part-3", saved.Anchor)
+
+	// A device syncing late gets the newer position back
+	saved = save(`{"percent":20,"anchor":"part-1","recorded_at":"` + now.Add(-time.Hour).Format(time.RFC3339Nano) + `"}`)
+	assert.Equal(t, 70.0, saved.Percent)
+	assert.Equal(t, "part-3", saved.Anchor)
+
+	saved = save(`{"percent":0}`)
+	assert.Equal(t, 0.0, saved.Percent, "updates without a recording time are taken as now")
+
+	for _, body := range []string{`{}`, `{"percent":101}`, `{"percent":-1}`, `{"percent":"half"}`} {
+		rec := progressRequest(e, http.MethodPut, "/api/v1/posts/1/progress", body, "7")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+	rec := progressRequest(e, http.MethodPut, "/api/v1/posts/2/progress", `{"percent":10}`, "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "drafts have no reading progress")
+	rec = progressRequest(e, http.MethodPut, "/api/v1/posts/9/progress", `{"percent":10}`, "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = progressRequest(e, http.MethodPut, "/api/v1/posts/1/progress", `{"percent":10}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestProgressHandler_ReadingHistory(t *testing.T) {
+	e, _ := setupProgressTestServer()
+	progressRequest(e, http.MethodPut, "/api/v1/posts/1/progress", `{"percent":55.5,"anchor":"setup"}`, "7")
+
+	rec := progressRequest(e, http.MethodGet, "/api/v1/users/me/reading-history", "", "7")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var history handlers.ReadingHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	if assert.Len(t, history.Entries, 1) {
+		assert.Equal(t, "Post 1", history.Entries[0].Title)
+		assert.Equal(t, 55.5, history.Entries[0].Percent)
+		assert.Equal(t, "setup", history.Entries[0].Anchor)
+	}
+
+	rec = progressRequest(e, http.MethodGet, "/api/v1/users/me/reading-history", "", "8")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	assert.Empty(t, history.Entries, "reading history is per user")
+
+	rec = progressRequest(e, http.MethodGet, "/api/v1/users/me/reading-history", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestPostHandler_GetPost_IncludesProgress(t *testing.T) {
+	e, _ := setupProgressTestServer()
+	progressRequest(e, http.MethodPut, "/api/v1/posts/1/progress", `{"percent":30,"anchor":"intro"}`, "7")
+
+	getPost := func(userID string) (*httptest.ResponseRecorder, handlers.PostResponse) {
+		rec := progressRequest(e, http.MethodGet, "/api/v1/posts/1", "", userID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.PostResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec, response
+	}
+
+	rec, response := getPost("7")
+	if assert.NotNil(t, response.Progress) {
+		assert.Equal(t, 30.0, response.Progress.Percent)
+		assert.Equal(t, "intro", response.Progress.Anchor)
+	}
+	assert.Equal(t, "private", rec.Header().Get(echo.HeaderCacheControl))
+
+	_, response = getPost("8")
+	assert.Nil(t, response.Progress, "readers who have not started the post get no progress")
+
+	rec, response = getPost("")
+	assert.Nil(t, response.Progress)
+	assert.NotContains(t, rec.Body.String(), `"progress"`)
+}
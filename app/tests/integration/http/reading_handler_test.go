@@ -0,0 +1,232 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/views"
+)
+
+func setupReadingTestServer(t *testing.T) (*echo.Echo, *MockPostService, *MockCommentService) {
+	return setupReadingTestServerWithOptions(t, views.Options{})
+}
+
+func setupReadingTestServerWithOptions(t *testing.T, opts views.Options) (*echo.Echo, *MockPostService, *MockCommentService) {
+	renderer, err := views.NewRenderer(opts)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Renderer = renderer
+
+	postService := NewMockPostService()
+	commentService := NewMockCommentService()
+	readingHandler := handlers.NewReadingHandler(postService, NewMockRedirectService(postService), commentService, handlers.ReadingSettings{
+		SiteName: "Test Blog",
+		BaseURL:  "https://blog.example.com",
+		FeedURL:  "/feeds/posts.rss",
+	}, NewMockLogger())
+	e.GET("/p/:slug", readingHandler.ShowPost)
+	assetHandler := handlers.NewAssetHandler(renderer, NewMockLogger())
+	e.GET("/assets/:theme/*", assetHandler.ServeAsset)
+
+	return e, postService, commentService
+}
+
+func TestReadingHandler_ShowPost(t *testing.T) {
+	e, postService, commentService := setupReadingTestServer(t)
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hello Reader", "Some **bold** words and <script>alert(1)</script>.")
+	require.NoError(t, err)
+	_, err = commentService.AddComment(ctx, p.ID, 0, "", "Jane Smith", "Nice <b>post</b>")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/p/hello-reader", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "<title>Hello Reader - Test Blog</title>")
+	assert.Contains(t, body, `<meta property="og:title" content="Hello Reader">`)
+	assert.Contains(t, body, `<meta property="og:type" content="article">`)
+	assert.Contains(t, body, `<meta property="og:url" content="https://blog.example.com/p/hello-reader">`)
+	assert.Contains(t, body, `<link rel="alternate" type="application/rss+xml" title="Test Blog" href="/feeds/posts.rss">`)
+	assert.Contains(t, body, "<strong>bold</strong>")
+	assert.Contains(t, body, "Jane Smith")
+
+	// Neither post content nor comments may inject markup
+	assert.NotContains(t, body, "<script>")
+	assert.NotContains(t, body, "<b>post</b>")
+}
+
+func TestReadingHandler_ShowPost_NoIndex(t *testing.T) {
+	e, postService, _ := setupReadingTestServer(t)
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hidden Post", "Not meant for search engines.")
+	require.NoError(t, err)
+
+	// Indexed posts carry no robots directives
+	req := httptest.NewRequest(http.MethodGet, "/p/hidden-post", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-Robots-Tag"))
+	assert.NotContains(t, rec.Body.String(), `name="robots"`)
+
+	_, err = postService.SetNoIndex(ctx, 1, "", p.ID, true)
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodGet, "/p/hidden-post", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+	assert.Contains(t, rec.Body.String(), `<meta name="robots" content="noindex">`)
+}
+
+func TestReadingHandler_ShowPost_MembersOnly(t *testing.T) {
+	e, postService, _ := setupReadingTestServer(t)
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Members Post", "The opening paragraph.\n\nThe rest is for members only.")
+	require.NoError(t, err)
+	_, err = postService.SetAccess(ctx, 1, "", p.ID, post.AccessMembers)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/p/members-post", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "private", rec.Header().Get("Cache-Control"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "The opening paragraph.")
+	assert.NotContains(t, body, "for members only")
+	assert.Contains(t, body, `class="locked"`)
+}
+
+func TestReadingHandler_ShowPost_NotFound(t *testing.T) {
+	e, _, _ := setupReadingTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/p/missing-post", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<h1>Not found</h1>")
+}
+
+func TestReadingHandler_ShowPost_OldSlug(t *testing.T) {
+	e, postService, _ := setupReadingTestServer(t)
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "Hello Reader", "Some words.")
+	require.NoError(t, err)
+	_, err = postService.ChangeSlug(ctx, 1, user.RoleAuthor, p.ID, "hello-again")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/p/hello-reader", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/p/hello-again", rec.Header().Get(echo.HeaderLocation))
+}
+
+func TestReadingHandler_HostTheme(t *testing.T) {
+	themesDir := t.TempDir()
+	writeThemeFile(t, themesDir, "acme/templates/not_found.html", `{{define "content"}}<h1>Acme: nothing here</h1>{{end}}`)
+	writeThemeFile(t, themesDir, "acme/static/style.css", "body { color: red; }")
+
+	e, _, _ := setupReadingTestServerWithOptions(t, views.Options{
+		ThemesDir:  themesDir,
+		HostThemes: map[string]string{"blog.acme.com": "acme"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/p/missing-post", nil)
+	req.Host = "blog.acme.com:8080"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<h1>Acme: nothing here</h1>")
+	assert.Regexp(t, `href="/assets/acme/style.css\?v=[0-9a-f]+"`, rec.Body.String())
+
+	// Other hosts keep the default theme
+	req = httptest.NewRequest(http.MethodGet, "/p/missing-post", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Contains(t, rec.Body.String(), "<h1>Not found</h1>")
+	assert.Regexp(t, `href="/assets/default/style.css\?v=[0-9a-f]+"`, rec.Body.String())
+}
+
+func TestAssetHandler_ServeAsset(t *testing.T) {
+	e, _, _ := setupReadingTestServer(t)
+
+	// The page links the stylesheet with its content hash
+	req := httptest.NewRequest(http.MethodGet, "/p/missing-post", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	match := regexp.MustCompile(`href="(/assets/default/style.css\?v=([0-9a-f]+))"`).FindStringSubmatch(rec.Body.String())
+	require.Len(t, match, 3)
+
+	req = httptest.NewRequest(http.MethodGet, match[1], nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/css")
+	assert.Equal(t, `"`+match[2]+`"`, rec.Header().Get("ETag"))
+	assert.Contains(t, rec.Header().Get(echo.HeaderCacheControl), "immutable")
+	assert.Contains(t, rec.Body.String(), "font-family")
+
+	// Unversioned requests must revalidate
+	req = httptest.NewRequest(http.MethodGet, "/assets/default/style.css", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderCacheControl), "no-cache")
+
+	// A matching ETag is answered without a body
+	req = httptest.NewRequest(http.MethodGet, "/assets/default/style.css", nil)
+	req.Header.Set("If-None-Match", `"`+match[2]+`"`)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestAssetHandler_ServeAsset_NotFound(t *testing.T) {
+	e, _, _ := setupReadingTestServer(t)
+
+	for _, path := range []string{"/assets/default/missing.css", "/assets/unknown/style.css", "/assets/default/../templates/layout.html"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func writeThemeFile(t *testing.T, themesDir, name, content string) {
+	t.Helper()
+	path := filepath.Join(themesDir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
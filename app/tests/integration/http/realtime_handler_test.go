@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/realtime"
+)
+
+func setupRealtimeTestServer(t *testing.T) (*httptest.Server, *realtime.Hub) {
+	e := echo.New()
+
+	hub := realtime.NewHub(realtime.Settings{SendBuffer: 4, MaxConnections: 1, WriteTimeout: time.Second}, NewMockLogger())
+	h := handlers.NewRealtimeHandler(hub, NewMockLogger())
+	authMiddleware := middleware.NewAuthMiddleware(tokenAuthService{}, NewMockLogger())
+	e.GET("/api/v1/ws", h.Connect, authMiddleware.IdentifyWebSocket, authMiddleware.RequireAuth)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server, hub
+}
+
+// dialRealtime opens a WebSocket offering the given subprotocols
+func dialRealtime(server *httptest.Server, protocols ...string) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/ws", server.URL)
+	if err != nil {
+		return nil, err
+	}
+	config.Protocol = protocols
+	return websocket.DialConfig(config)
+}
+
+func TestRealtimeHandler_Connect(t *testing.T) {
+	server, hub := setupRealtimeTestServer(t)
+
+	// Browsers sign in with the bearer subprotocol
+	ws, err := dialRealtime(server, middleware.WebSocketProtocol, "mock-jwt-token")
+	require.NoError(t, err)
+	defer ws.Close()
+	assert.Equal(t, []string{middleware.WebSocketProtocol}, ws.Config().Protocol, "expected the bearer subprotocol answered, never the token")
+
+	deadline := time.Now().Add(time.Second)
+	for hub.Stats().Connections == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	hub.Push(1, service.RealtimeMessage{Type: service.RealtimeFollower, Data: service.RealtimeFollowerData{FollowerID: 2}})
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var msg struct {
+		Type string                       `json:"type"`
+		Data service.RealtimeFollowerData `json:"data"`
+	}
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, service.RealtimeFollower, msg.Type)
+	assert.Equal(t, 2, msg.Data.FollowerID)
+
+	// Each user has a bounded number of connections
+	_, err = dialRealtime(server, middleware.WebSocketProtocol, "mock-jwt-token")
+	assert.Error(t, err)
+}
+
+func TestRealtimeHandler_Rejections(t *testing.T) {
+	server, _ := setupRealtimeTestServer(t)
+
+	tests := []struct {
+		name         string
+		header       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "no token",
+			header:       map[string]string{"Connection": "Upgrade", "Upgrade": "websocket"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "invalid token",
+			header:       map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Protocol": "bearer, wrong-token"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "token outside the bearer subprotocol",
+			header:       map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Protocol": "mock-jwt-token"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "not a websocket handshake",
+			header:       map[string]string{"Authorization": "Bearer mock-jwt-token"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/ws", nil)
+			require.NoError(t, err)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockRedirectService implements post.RedirectService for testing; slugs
+// resolve against the posts and old slugs of the post service mock
+type MockRedirectService struct {
+	posts     *MockPostService
+	redirects map[int]*post.Redirect
+	nextID    int
+}
+
+func NewMockRedirectService(posts *MockPostService) *MockRedirectService {
+	return &MockRedirectService{posts: posts, redirects: make(map[int]*post.Redirect), nextID: 1}
+}
+
+func (m *MockRedirectService) Resolve(ctx context.Context, userID int, role user.Role, slug string) (*post.Post, error) {
+	if p, err := m.posts.GetPostBySlug(ctx, slug); err == nil {
+		return m.posts.GetVisiblePost(ctx, userID, role, p.ID)
+	}
+	if id, ok := m.posts.oldSlugs[slug]; ok {
+		return m.posts.GetVisiblePost(ctx, userID, role, id)
+	}
+	for _, rd := range m.redirects {
+		if rd.FromSlug == slug {
+			return m.posts.GetVisiblePost(ctx, userID, role, rd.PostID)
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockRedirectService) ListRedirects(ctx context.Context, limit, offset int) ([]*post.Redirect, error) {
+	var redirects []*post.Redirect
+	for id := m.nextID - 1; id > 0; id-- {
+		if rd, ok := m.redirects[id]; ok {
+			redirects = append(redirects, rd)
+		}
+	}
+	return redirects, nil
+}
+
+func (m *MockRedirectService) CreateRedirect(ctx context.Context, adminID int, fromSlug string, postID int) (*post.Redirect, error) {
+	if err := m.check(ctx, 0, fromSlug, postID); err != nil {
+		return nil, err
+	}
+	rd := &post.Redirect{ID: m.nextID, FromSlug: fromSlug, PostID: postID, CreatedAt: time.Now()}
+	m.nextID++
+	m.redirects[rd.ID] = rd
+	return rd, nil
+}
+
+func (m *MockRedirectService) UpdateRedirect(ctx context.Context, adminID, id int, fromSlug string, postID int) (*post.Redirect, error) {
+	rd, ok := m.redirects[id]
+	if !ok {
+		return nil, post.ErrRedirectNotFound
+	}
+	if err := m.check(ctx, id, fromSlug, postID); err != nil {
+		return nil, err
+	}
+	rd.FromSlug, rd.PostID, rd.Automatic = fromSlug, postID, false
+	return rd, nil
+}
+
+func (m *MockRedirectService) DeleteRedirect(ctx context.Context, adminID, id int) error {
+	if _, ok := m.redirects[id]; !ok {
+		return post.ErrRedirectNotFound
+	}
+	delete(m.redirects, id)
+	return nil
+}
+
+func (m *MockRedirectService) check(ctx context.Context, id int, fromSlug string, postID int) error {
+	if !post.IsValidSlug(fromSlug) {
+		return post.ErrInvalidSlug
+	}
+	if _, err := m.posts.GetPostBySlug(ctx, fromSlug); err == nil {
+		return post.ErrSlugTaken
+	}
+	for _, rd := range m.redirects {
+		if rd.FromSlug == fromSlug && rd.ID != id {
+			return post.ErrRedirectExists
+		}
+	}
+	_, err := m.posts.GetPost(ctx, postID)
+	return err
+}
+
+func setupRedirectTestServer() (*echo.Echo, *MockRedirectService, *MockPostService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	postService := NewMockPostService()
+	postService.posts[1] = fixtures.Post(1, 1)
+	postService.posts[2] = fixtures.Draft(2, 1)
+	redirectService := NewMockRedirectService(postService)
+
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), redirectService, service.DefaultPaginationPolicy().Posts, NewMockLogger())
+	h := handlers.NewRedirectHandler(redirectService, service.DefaultPaginationPolicy().Posts, NewMockLogger())
+
+	asAuthor := fixtures.AsUserWithRole(user.RoleAuthor)
+	e.GET("/api/v1/posts/slug/:slug", postHandler.GetPostBySlug, asAuthor)
+	e.PUT("/api/v1/posts/:id/slug", postHandler.ChangePostSlug, asAuthor)
+	e.GET("/api/v1/admin/redirects", h.List, asAuthor)
+	e.POST("/api/v1/admin/redirects", h.Create, asAuthor)
+	e.PUT("/api/v1/admin/redirects/:id", h.Update, asAuthor)
+	e.DELETE("/api/v1/admin/redirects/:id", h.Delete, asAuthor)
+
+	return e, redirectService, postService
+}
+
+func redirectRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPostHandler_GetPostBySlug(t *testing.T) {
+	e, _, postService := setupRedirectTestServer()
+	postService.oldSlugs["old-post-1"] = 1
+	postService.oldSlugs["old-post-2"] = 2
+
+	rec := redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/post-1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.ID)
+
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/old-post-1?include=author", "", "")
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/api/v1/posts/slug/post-1?include=author", rec.Header().Get(echo.HeaderLocation), "the query is kept")
+
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/old-post-2", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "old slugs of drafts do not reveal the current slug")
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/old-post-2", "", "1")
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code, "authors are redirected to their drafts")
+
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/missing", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPostHandler_ChangePostSlug(t *testing.T) {
+	e, _, postService := setupRedirectTestServer()
+	postService.posts[3] = fixtures.Post(3, 2)
+
+	rec := redirectRequest(e, http.MethodPut, "/api/v1/posts/1/slug", `{"slug":"a-better-slug"}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.PostResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "a-better-slug", response.Slug)
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/slug/post-1", "", "")
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code, "the old slug redirects")
+	assert.Equal(t, "/api/v1/posts/slug/a-better-slug", rec.Header().Get(echo.HeaderLocation))
+
+	rec = redirectRequest(e, http.MethodPut, "/api/v1/posts/1/slug", `{"slug":"Not A Slug"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = redirectRequest(e, http.MethodPut, "/api/v1/posts/1/slug", `{"slug":"post-3"}`, "1")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = redirectRequest(e, http.MethodPut, "/api/v1/posts/3/slug", `{"slug":"mine-now"}`, "1")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = redirectRequest(e, http.MethodPut, "/api/v1/posts/1/slug", `{"slug":"a-better-slug"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRedirectHandler_CRUD(t *testing.T) {
+	e, redirectService, _ := setupRedirectTestServer()
+
+	rec := redirectRequest(e, http.MethodPost, "/api/v1/admin/redirects", `{"from_slug":"legacy-post","post_id":1}`, "9")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created handlers.RedirectResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "legacy-post", created.FromSlug)
+	assert.False(t, created.Automatic)
+
+	rec = redirectRequest(e, http.MethodPost, "/api/v1/admin/redirects", `{"from_slug":"legacy-post","post_id":1}`, "9")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = redirectRequest(e, http.MethodPost, "/api/v1/admin/redirects", `{"from_slug":"post-1","post_id":2}`, "9")
+	assert.Equal(t, http.StatusConflict, rec.Code, "slugs of posts cannot redirect")
+	rec = redirectRequest(e, http.MethodPost, "/api/v1/admin/redirects", `{"from_slug":"elsewhere","post_id":99}`, "9")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = redirectRequest(e, http.MethodPost, "/api/v1/admin/redirects", `{"from_slug":"Legacy Post","post_id":1}`, "9")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	id := strconv.Itoa(created.ID)
+	rec = redirectRequest(e, http.MethodPut, "/api/v1/admin/redirects/"+id, `{"from_slug":"legacy-post-2","post_id":1}`, "9")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/admin/redirects", "", "9")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.RedirectListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Redirects, 1)
+	assert.Equal(t, "legacy-post-2", list.Redirects[0].FromSlug)
+
+	rec = redirectRequest(e, http.MethodDelete, "/api/v1/admin/redirects/"+id, "", "9")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, redirectService.redirects)
+	rec = redirectRequest(e, http.MethodDelete, "/api/v1/admin/redirects/"+id, "", "9")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = redirectRequest(e, http.MethodDelete, "/api/v1/admin/redirects/abc", "", "9")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
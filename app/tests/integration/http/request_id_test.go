@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// newRequestIDServer returns a server tagging requests with IDs the way
+// SetupRoutes does
+func newRequestIDServer() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = errors.HTTPErrorHandler
+	e.Use(middleware.RequestID())
+	return e
+}
+
+func TestRequestID_KeepsValidClientID(t *testing.T) {
+	e := newRequestIDServer()
+	var fromContext string
+	e.GET("/test", func(c echo.Context) error {
+		fromContext = service.RequestIDFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderXRequestID, "edge-7f3a:1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "edge-7f3a:1", rec.Header().Get(echo.HeaderXRequestID))
+	assert.Equal(t, "edge-7f3a:1", fromContext)
+}
+
+func TestRequestID_ReplacesUnsafeClientID(t *testing.T) {
+	e := newRequestIDServer()
+	e.GET("/test", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, id := range []string{"bad id\nforged=1", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(echo.HeaderXRequestID, id)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		got := rec.Header().Get(echo.HeaderXRequestID)
+		assert.NotEmpty(t, got)
+		assert.NotEqual(t, id, got)
+	}
+}
+
+func TestRequestID_InErrorBodies(t *testing.T) {
+	e := newRequestIDServer()
+	e.GET("/handled", func(c echo.Context) error {
+		return errors.HandleError(c, errors.ErrNotFound)
+	})
+	e.GET("/returned", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad input")
+	})
+	e.GET("/panics", func(c echo.Context) error {
+		panic("boom")
+	}, echomiddleware.Recover())
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedErr  errors.ErrorCode
+	}{
+		{path: "/handled", expectedCode: http.StatusNotFound, expectedErr: errors.ErrCodeNotFound},
+		{path: "/returned", expectedCode: http.StatusBadRequest, expectedErr: errors.ErrCodeInvalidRequest},
+		{path: "/panics", expectedCode: http.StatusInternalServerError, expectedErr: errors.ErrCodeInternal},
+		{path: "/unknown", expectedCode: http.StatusNotFound, expectedErr: errors.ErrCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			var response errors.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, string(tt.expectedErr), response.Error)
+			assert.NotEmpty(t, response.RequestID)
+			assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), response.RequestID)
+		})
+	}
+}
+
+func TestRequestID_InAuthErrors(t *testing.T) {
+	e := newRequestIDServer()
+	authMiddleware := middleware.NewAuthMiddleware(nil, NewMockLogger())
+	e.GET("/private", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, authMiddleware.RequireAuth)
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), response["request_id"])
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"blog-platform/internal/infrastructure/http/handlers"
+)
+
+func TestRobotsHandler_ServeRobots(t *testing.T) {
+	e := echo.New()
+	robotsHandler := handlers.NewRobotsHandler(handlers.RobotsSettings{
+		Disallow: []string{"/api/"},
+		HostDisallow: map[string][]string{
+			"Staging.Example.com": {"/"},
+			"open.example.com":    {},
+		},
+	}, NewMockLogger())
+	e.GET("/robots.txt", robotsHandler.ServeRobots)
+
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "default rules",
+			host:     "blog.example.com",
+			expected: "User-agent: *\nDisallow: /api/\n",
+		},
+		{
+			name:     "host override ignores case and port",
+			host:     "staging.example.com:8080",
+			expected: "User-agent: *\nDisallow: /\n",
+		},
+		{
+			name:     "empty host override allows everything",
+			host:     "open.example.com",
+			expected: "User-agent: *\nDisallow:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/plain")
+			assert.Equal(t, tt.expected, rec.Body.String())
+		})
+	}
+}
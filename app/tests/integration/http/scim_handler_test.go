@@ -0,0 +1,242 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/scim"
+	infraAuth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// MockSCIMService implements scim.Service for testing. Users are kept per
+// tenant; groups are not supported.
+type MockSCIMService struct {
+	users  map[string]map[int]*scim.User
+	nextID int
+}
+
+func NewMockSCIMService() *MockSCIMService {
+	return &MockSCIMService{users: make(map[string]map[int]*scim.User), nextID: 1}
+}
+
+func (m *MockSCIMService) CreateUser(ctx context.Context, tenant string, attrs scim.UserAttributes) (*scim.User, error) {
+	if err := attrs.Validate(); err != nil {
+		return nil, err
+	}
+	for _, u := range m.users[tenant] {
+		if u.UserName == attrs.UserName {
+			return nil, scim.ErrUserClaimed
+		}
+	}
+	if m.users[tenant] == nil {
+		m.users[tenant] = make(map[int]*scim.User)
+	}
+	now := time.Now()
+	u := &scim.User{ID: m.nextID, ExternalID: attrs.ExternalID, UserName: attrs.UserName, Name: attrs.Name, Email: attrs.Email, Active: attrs.Active, Created: now, Modified: now}
+	m.users[tenant][u.ID] = u
+	m.nextID++
+	return u, nil
+}
+
+func (m *MockSCIMService) GetUser(ctx context.Context, tenant string, id int) (*scim.User, error) {
+	if u, ok := m.users[tenant][id]; ok {
+		return u, nil
+	}
+	return nil, scim.ErrUserNotFound
+}
+
+func (m *MockSCIMService) ListUsers(ctx context.Context, tenant string, filter *scim.Filter, page scim.Page) ([]*scim.User, int, error) {
+	result := []*scim.User{}
+	for id := 1; id < m.nextID; id++ {
+		u, ok := m.users[tenant][id]
+		if ok && (filter == nil || filter.Attribute == scim.FilterUserName && u.UserName == filter.Value) {
+			result = append(result, u)
+		}
+	}
+	return result, len(result), nil
+}
+
+func (m *MockSCIMService) ReplaceUser(ctx context.Context, tenant string, id int, attrs scim.UserAttributes) (*scim.User, error) {
+	u, err := m.GetUser(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := attrs.Validate(); err != nil {
+		return nil, err
+	}
+	u.ExternalID, u.UserName, u.Name, u.Email, u.Active = attrs.ExternalID, attrs.UserName, attrs.Name, attrs.Email, attrs.Active
+	return u, nil
+}
+
+func (m *MockSCIMService) PatchUser(ctx context.Context, tenant string, id int, ops []scim.PatchOperation) (*scim.User, error) {
+	u, err := m.GetUser(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	attrs := scim.UserAttributes{ExternalID: u.ExternalID, UserName: u.UserName, Name: u.Name, Email: u.Email, Active: u.Active}
+	if err := attrs.ApplyPatch(ops); err != nil {
+		return nil, err
+	}
+	return m.ReplaceUser(ctx, tenant, id, attrs)
+}
+
+func (m *MockSCIMService) DeleteUser(ctx context.Context, tenant string, id int) error {
+	if _, err := m.GetUser(ctx, tenant, id); err != nil {
+		return err
+	}
+	delete(m.users[tenant], id)
+	return nil
+}
+
+func (m *MockSCIMService) CreateGroup(ctx context.Context, tenant, displayName, externalID string, members []int) (*scim.Group, error) {
+	return scim.NewGroup(tenant, displayName, externalID, members)
+}
+
+func (m *MockSCIMService) GetGroup(ctx context.Context, tenant string, id int) (*scim.Group, error) {
+	return nil, scim.ErrGroupNotFound
+}
+
+func (m *MockSCIMService) ListGroups(ctx context.Context, tenant string, filter *scim.Filter, page scim.Page) ([]*scim.Group, int, error) {
+	return []*scim.Group{}, 0, nil
+}
+
+func (m *MockSCIMService) ReplaceGroup(ctx context.Context, tenant string, id int, displayName, externalID string, members []int) (*scim.Group, error) {
+	return nil, scim.ErrGroupNotFound
+}
+
+func (m *MockSCIMService) PatchGroup(ctx context.Context, tenant string, id int, ops []scim.PatchOperation) (*scim.Group, error) {
+	return nil, scim.ErrGroupNotFound
+}
+
+func (m *MockSCIMService) DeleteGroup(ctx context.Context, tenant string, id int) error {
+	return scim.ErrGroupNotFound
+}
+
+func setupSCIMTestServer() *echo.Echo {
+	e := echo.New()
+	h := handlers.NewSCIMHandler(NewMockSCIMService(), handlers.SCIMSettings{BaseURL: "https://blog.example.com"}, NewMockLogger())
+
+	tenants := infraAuth.NewSCIMTenants(map[string]string{"acme": "acme-token", "globex": "globex-token"})
+	g := e.Group("/scim/v2", middleware.SCIMAuth(tenants, NewMockLogger()))
+	g.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+	g.GET("/Users", h.ListUsers)
+	g.POST("/Users", h.CreateUser)
+	g.GET("/Users/:id", h.GetUser)
+	g.PUT("/Users/:id", h.ReplaceUser)
+	g.PATCH("/Users/:id", h.PatchUser)
+	g.DELETE("/Users/:id", h.DeleteUser)
+	g.POST("/Groups", h.CreateGroup)
+	return e
+}
+
+// scimRequest sends a SCIM request as the tenant with the given token
+func scimRequest(e *echo.Echo, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "application/scim+json")
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSCIMHandler_Authentication(t *testing.T) {
+	e := setupSCIMTestServer()
+
+	for _, token := range []string{"", "wrong-token"} {
+		rec := scimRequest(e, http.MethodGet, "/scim/v2/Users", token, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "urn:ietf:params:scim:api:messages:2.0:Error")
+	}
+
+	rec := scimRequest(e, http.MethodGet, "/scim/v2/ServiceProviderConfig", "acme-token", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/scim+json", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"patch":{"supported":true}`)
+}
+
+func TestSCIMHandler_UserLifecycle(t *testing.T) {
+	e := setupSCIMTestServer()
+
+	body := `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "jane@acme.com",
+		"externalId": "00u1",
+		"name": {"givenName": "Jane", "familyName": "Doe"},
+		"emails": [{"value": "jane@acme.com", "primary": true}],
+		"active": true
+	}`
+	rec := scimRequest(e, http.MethodPost, "/scim/v2/Users", "acme-token", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created handlers.SCIMUser
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "Jane Doe", created.Name.Formatted)
+	assert.True(t, created.Active)
+	assert.Equal(t, "https://blog.example.com/scim/v2/Users/"+created.ID, rec.Header().Get(echo.HeaderLocation))
+
+	rec = scimRequest(e, http.MethodPost, "/scim/v2/Users", "acme-token", body)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scimType":"uniqueness"`)
+
+	// Other tenants do not see the user
+	rec = scimRequest(e, http.MethodGet, "/scim/v2/Users/"+created.ID, "globex-token", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = scimRequest(e, http.MethodGet, `/scim/v2/Users?filter=userName+eq+%22jane%40acme.com%22`, "acme-token", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.SCIMListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.TotalResults)
+
+	rec = scimRequest(e, http.MethodGet, `/scim/v2/Users?filter=title+sw+%22E%22`, "acme-token", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scimType":"invalidFilter"`)
+
+	patch := `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "Replace", "path": "active", "value": false}]}`
+	rec = scimRequest(e, http.MethodPatch, "/scim/v2/Users/"+created.ID, "acme-token", patch)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var patched handlers.SCIMUser
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &patched))
+	assert.False(t, patched.Active)
+
+	rec = scimRequest(e, http.MethodPatch, "/scim/v2/Users/"+created.ID, "acme-token", `{"Operations": [{"op": "replace", "path": "title", "value": "CEO"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scimType":"invalidPath"`)
+
+	rec = scimRequest(e, http.MethodPut, "/scim/v2/Users/"+created.ID, "acme-token", `{"userName":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scimType":"invalidSyntax"`)
+
+	rec = scimRequest(e, http.MethodDelete, "/scim/v2/Users/"+created.ID, "acme-token", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = scimRequest(e, http.MethodGet, "/scim/v2/Users/"+created.ID, "acme-token", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSCIMHandler_CreateGroup(t *testing.T) {
+	e := setupSCIMTestServer()
+
+	rec := scimRequest(e, http.MethodPost, "/scim/v2/Groups", "acme-token", `{"displayName": "Engineering", "members": [{"value": "abc"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scimType":"invalidValue"`)
+
+	rec = scimRequest(e, http.MethodPost, "/scim/v2/Groups", "acme-token", `{"displayName": "Engineering", "members": [{"value": "1"}, {"value": "2"}]}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var group handlers.SCIMGroup
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &group))
+	assert.Equal(t, "Engineering", group.DisplayName)
+	require.Len(t, group.Members, 2)
+	assert.Equal(t, "2", group.Members[1].Value)
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// MockSecurityService implements user.SecurityService for testing
+type MockSecurityService struct {
+	reported  []string
+	passwords map[string]string
+}
+
+func (m *MockSecurityService) ReportUnauthorizedChange(ctx context.Context, token string) error {
+	if token != "report-token" {
+		return user.ErrInvalidReportToken
+	}
+	m.reported = append(m.reported, token)
+	return nil
+}
+
+func (m *MockSecurityService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token != "reset-token" {
+		return user.ErrPasswordResetNotFound
+	}
+	m.passwords[token] = newPassword
+	return nil
+}
+
+func setupSecurityTestServer() (*echo.Echo, *handlers.SecurityHandler, *MockSecurityService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	securityService := &MockSecurityService{passwords: make(map[string]string)}
+	return e, handlers.NewSecurityHandler(securityService, NewMockLogger()), securityService
+}
+
+func TestSecurityHandler_ReportChange(t *testing.T) {
+	e, handler, securityService := setupSecurityTestServer()
+
+	for _, tc := range []struct {
+		query  string
+		status int
+	}{
+		{"?token=report-token", http.StatusOK},
+		{"?token=forged", http.StatusBadRequest},
+		{"", http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/security/report"+tc.query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.ReportChange(e.NewContext(req, rec)))
+		assert.Equal(t, tc.status, rec.Code, "query %q", tc.query)
+	}
+
+	assert.Equal(t, []string{"report-token"}, securityService.reported)
+}
+
+func TestSecurityHandler_ResetPassword(t *testing.T) {
+	e, handler, securityService := setupSecurityTestServer()
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		password string
+		status   int
+	}{
+		{"valid", "?token=reset-token", "N3w-Password!", http.StatusOK},
+		{"unknown token", "?token=other", "N3w-Password!", http.StatusNotFound},
+		{"missing token", "", "N3w-Password!", http.StatusBadRequest},
+		{"weak password", "?token=reset-token", "abc", http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(handlers.ResetPasswordRequest{Password: tc.password})
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users/password/reset"+tc.query, bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			require.NoError(t, handler.ResetPassword(e.NewContext(req, rec)))
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+
+	assert.Equal(t, map[string]string{"reset-token": "N3w-Password!"}, securityService.passwords)
+}
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,8 +12,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
 	"blog-platform/internal/infrastructure/config"
 	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/ratelimit"
 )
 
 func TestRateLimitingMiddleware(t *testing.T) {
@@ -172,3 +177,182 @@ func TestRequestIDMiddleware(t *testing.T) {
 	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
 	assert.Contains(t, rec.Body.String(), "request_id")
 }
+
+func TestRequireRoleMiddleware(t *testing.T) {
+	authMiddleware := middleware.NewAuthMiddleware(nil, NewMockLogger())
+	handler := authMiddleware.RequireRole(user.RoleAuthor, user.RoleAdmin)(func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		name         string
+		role         any
+		expectedCode int
+	}{
+		{name: "author allowed", role: user.RoleAuthor, expectedCode: http.StatusOK},
+		{name: "admin allowed", role: user.RoleAdmin, expectedCode: http.StatusOK},
+		{name: "reader forbidden", role: user.RoleReader, expectedCode: http.StatusForbidden},
+		{name: "missing role forbidden", role: nil, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/posts", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", 1)
+			if tt.role != nil {
+				c.Set("user_role", tt.role)
+			}
+
+			require.NoError(t, handler(c))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+// tokenAuthService accepts only "mock-jwt-token", issued to user 1
+type tokenAuthService struct {
+	auth.AuthService
+}
+
+func (s tokenAuthService) ValidateToken(ctx context.Context, token string) (*auth.TokenClaims, error) {
+	if token == "mock-jwt-token" {
+		return &auth.TokenClaims{UserID: 1, Email: "test@example.com"}, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func TestUserRateLimiting(t *testing.T) {
+	e := echo.New()
+
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"message": "success"})
+	}
+
+	// Anonymous clients get 2 requests per IP, signed-in users 4 each
+	authMiddleware := middleware.NewAuthMiddleware(tokenAuthService{}, NewMockLogger())
+	e.Use(authMiddleware.Identify)
+	e.Use(middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		RequestsPerSecond:              1,
+		BurstSize:                      2,
+		AuthenticatedRequestsPerSecond: 2,
+		AuthenticatedBurstSize:         4,
+		KeyGenerator:                   middleware.UserKeyGenerator,
+	}))
+	e.GET("/test", handler)
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, send("").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("").Code)
+
+	// The user shares the IP but not the anonymous budget
+	for i := 0; i < 4; i++ {
+		rec := send("mock-jwt-token")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("mock-jwt-token").Code)
+
+	// Invalid tokens are limited as anonymous traffic
+	assert.Equal(t, http.StatusTooManyRequests, send("invalid-token").Code)
+}
+
+func TestWriteRateLimiting(t *testing.T) {
+	e := echo.New()
+
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"message": "success"})
+	}
+
+	e.Use(middleware.WriteRateLimiterMiddleware(&config.Config{
+		RateLimit: config.RateLimitConfig{WriteRequestsPerSecond: 1, WriteBurstSize: 2},
+	}, ratelimit.NewMemoryStore(), NewMockLogger()))
+	e.GET("/test", handler)
+	e.POST("/test", handler)
+
+	send := func(method string) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, "/test", nil))
+		return rec.Code
+	}
+
+	// Reads do not count towards the write budget
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet))
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodPost))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet))
+}
+
+// failingRateLimitStore simulates an unreachable rate limit backend
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimiting_SharedStore(t *testing.T) {
+	// Two servers sharing a store share the budget of each client
+	store := ratelimit.NewMemoryStore()
+	cfg := &config.Config{RateLimit: config.RateLimitConfig{DefaultRequestsPerSecond: 1, DefaultBurstSize: 2}}
+
+	servers := make([]*echo.Echo, 2)
+	for i := range servers {
+		servers[i] = echo.New()
+		servers[i].Use(middleware.RateLimiterMiddleware(cfg, store, NewMockLogger()))
+		servers[i].GET("/test", func(c echo.Context) error {
+			return c.String(http.StatusOK, "ok")
+		})
+	}
+
+	send := func(e *echo.Echo) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, send(servers[0]).Code)
+	assert.Equal(t, http.StatusOK, send(servers[1]).Code)
+
+	rec := send(servers[0])
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimiting_StoreFailure(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		RequestsPerSecond: 1,
+		BurstSize:         1,
+		KeyGenerator:      middleware.UserKeyGenerator,
+		Store:             failingRateLimitStore{},
+		Logger:            NewMockLogger(),
+	}))
+	e.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	// Requests are let through while the store is unavailable
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
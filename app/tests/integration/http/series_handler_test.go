@@ -0,0 +1,235 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockSeriesService implements series.Service for testing
+type MockSeriesService struct {
+	series map[int]*series.Series
+	posts  map[int]*post.Post
+	order  map[int][]int
+}
+
+func NewMockSeriesService() *MockSeriesService {
+	return &MockSeriesService{series: make(map[int]*series.Series), posts: make(map[int]*post.Post), order: make(map[int][]int)}
+}
+
+func (m *MockSeriesService) Create(ctx context.Context, authorID int, in series.Input) (*series.Series, error) {
+	s, err := series.NewSeries(authorID, in)
+	if err != nil {
+		return nil, err
+	}
+	s.ID = len(m.series) + 1
+	m.series[s.ID] = s
+	return s, nil
+}
+
+func (m *MockSeriesService) Update(ctx context.Context, userID int, role user.Role, id int, in series.Input) (*series.Series, error) {
+	s, err := m.modifiable(userID, role, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Update(in); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m *MockSeriesService) Delete(ctx context.Context, userID int, role user.Role, id int) error {
+	if _, err := m.modifiable(userID, role, id); err != nil {
+		return err
+	}
+	delete(m.series, id)
+	delete(m.order, id)
+	return nil
+}
+
+func (m *MockSeriesService) Get(ctx context.Context, userID int, role user.Role, id int) (*series.Series, []series.Entry, error) {
+	s, ok := m.series[id]
+	if !ok {
+		return nil, nil, series.ErrSeriesNotFound
+	}
+	var visible []*post.Post
+	for _, postID := range m.order[id] {
+		if p := m.posts[postID]; p.IsVisibleTo(userID, role) {
+			visible = append(visible, p)
+		}
+	}
+	return s, series.Navigate(visible), nil
+}
+
+func (m *MockSeriesService) ListByAuthor(ctx context.Context, authorID int) ([]*series.Series, error) {
+	var list []*series.Series
+	for id := len(m.series); id > 0; id-- {
+		if s, ok := m.series[id]; ok && s.AuthorID == authorID {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func (m *MockSeriesService) SetPosts(ctx context.Context, userID int, role user.Role, id int, postIDs []int) (*series.Series, []series.Entry, error) {
+	s, err := m.modifiable(userID, role, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := series.CheckOrder(postIDs); err != nil {
+		return nil, nil, err
+	}
+	for i, postID := range postIDs {
+		p, ok := m.posts[postID]
+		if !ok || p.AuthorID != s.AuthorID {
+			return nil, nil, series.ErrForeignPost
+		}
+		p.SeriesID = &s.ID
+		p.SeriesPosition = i + 1
+	}
+	m.order[id] = postIDs
+	return m.Get(ctx, userID, role, id)
+}
+
+func (m *MockSeriesService) modifiable(userID int, role user.Role, id int) (*series.Series, error) {
+	s, ok := m.series[id]
+	if !ok {
+		return nil, series.ErrSeriesNotFound
+	}
+	if !s.CanModify(userID, role) {
+		return nil, series.ErrUnauthorized
+	}
+	return s, nil
+}
+
+func setupSeriesTestServer() (*echo.Echo, *MockSeriesService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	seriesService := NewMockSeriesService()
+	h := handlers.NewSeriesHandler(seriesService, NewMockLogger())
+
+	asAuthor := fixtures.AsUserWithRole(user.RoleAuthor)
+	e.GET("/api/v1/series/:id", h.GetSeries, asAuthor)
+	e.GET("/api/v1/users/:id/series", h.ListAuthorSeries, asAuthor)
+	e.POST("/api/v1/series", h.CreateSeries, asAuthor)
+	e.PUT("/api/v1/series/:id", h.UpdateSeries, asAuthor)
+	e.DELETE("/api/v1/series/:id", h.DeleteSeries, asAuthor)
+	e.PUT("/api/v1/series/:id/posts", h.SetSeriesPosts, asAuthor)
+
+	return e, seriesService
+}
+
+func seriesRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSeriesHandler_Manage(t *testing.T) {
+	e, seriesService := setupSeriesTestServer()
+
+	rec := seriesRequest(e, http.MethodPost, "/api/v1/series", `{"title":"Building a Blog","description":"Step by step."}`, "1")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created handlers.SeriesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "Building a Blog", created.Title)
+	assert.Equal(t, 1, created.AuthorID)
+
+	rec = seriesRequest(e, http.MethodPost, "/api/v1/series", `{"title":""}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = seriesRequest(e, http.MethodPost, "/api/v1/series", `{"title":"Building a Blog"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/1", `{"title":"Building a Blog in Go"}`, "2")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/1", `{"title":"Building a Blog in Go"}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Building a Blog in Go", seriesService.series[1].Title)
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/9", `{"title":"Missing"}`, "1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/invalid", `{"title":"Invalid"}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = seriesRequest(e, http.MethodGet, "/api/v1/users/1/series", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.SeriesListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+
+	rec = seriesRequest(e, http.MethodDelete, "/api/v1/series/1", "", "2")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = seriesRequest(e, http.MethodDelete, "/api/v1/series/1", "", "1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = seriesRequest(e, http.MethodGet, "/api/v1/series/1", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSeriesHandler_Posts(t *testing.T) {
+	e, seriesService := setupSeriesTestServer()
+	for id, title := range map[int]string{1: "First Part", 2: "Second Part", 3: "Third Part"} {
+		p, _ := post.NewPost(title, "Test content with sufficient length.", 1)
+		if id == 2 {
+			p, _ = post.NewDraft(title, "Test content with sufficient length.", 1)
+		}
+		p.ID = id
+		seriesService.posts[id] = p
+	}
+	foreign, _ := post.NewPost("Someone Else's Post", "Test content with sufficient length.", 2)
+	foreign.ID = 4
+	seriesService.posts[foreign.ID] = foreign
+	seriesRequest(e, http.MethodPost, "/api/v1/series", `{"title":"Building a Blog"}`, "1")
+
+	rec := seriesRequest(e, http.MethodPut, "/api/v1/series/1/posts", `{"post_ids":[1,4]}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/1/posts", `{"post_ids":[1,1]}`, "1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/1/posts", `{"post_ids":[4]}`, "2")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = seriesRequest(e, http.MethodPut, "/api/v1/series/1/posts", `{"post_ids":[3,2,1]}`, "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var detail handlers.SeriesDetailResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	require.Len(t, detail.Posts, 3, "the author sees the draft")
+	assert.Equal(t, 3, detail.Posts[0].ID)
+	assert.Equal(t, 1, detail.Posts[0].Position)
+	assert.Nil(t, detail.Posts[0].Previous)
+	require.NotNil(t, detail.Posts[0].Next)
+	assert.Equal(t, 2, detail.Posts[0].Next.ID)
+
+	// Readers move through the published posts only
+	rec = seriesRequest(e, http.MethodGet, "/api/v1/series/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	detail = handlers.SeriesDetailResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	require.Len(t, detail.Posts, 2)
+	assert.Equal(t, "Building a Blog", detail.Title)
+	assert.Equal(t, []int{3, 1}, []int{detail.Posts[0].ID, detail.Posts[1].ID})
+	assert.Equal(t, 3, detail.Posts[1].Position)
+	require.NotNil(t, detail.Posts[0].Next)
+	assert.Equal(t, "First Part", detail.Posts[0].Next.Title)
+	require.NotNil(t, detail.Posts[1].Previous)
+	assert.Equal(t, 3, detail.Posts[1].Previous.ID)
+	assert.Nil(t, detail.Posts[1].Next)
+
+	rec = seriesRequest(e, http.MethodGet, "/api/v1/series/invalid", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/tests/fixtures"
+)
+
+// MockSyncService implements change.Service for testing; it returns set and
+// records the checkpoint and limit it was called with
+type MockSyncService struct {
+	set   *change.Set
+	since change.Checkpoint
+	limit int
+}
+
+func NewMockSyncService() *MockSyncService {
+	return &MockSyncService{set: &change.Set{}}
+}
+
+func (m *MockSyncService) Changes(ctx context.Context, since change.Checkpoint, limit int) (*change.Set, error) {
+	m.since, m.limit = since, limit
+	set := *m.set
+	if set.Checkpoint == (change.Checkpoint{}) {
+		set.Checkpoint = since
+	}
+	return &set, nil
+}
+
+func setupSyncTestServer() (*echo.Echo, *MockSyncService) {
+	e := echo.New()
+
+	syncService := NewMockSyncService()
+	h := handlers.NewSyncHandler(syncService, NewMockPostService(), service.PageLimits{Default: 10, Max: 100}, NewMockLogger())
+
+	asReader := fixtures.AsUserWithRole(user.RoleReader)
+	e.GET("/api/v1/sync", h.Sync, asReader)
+
+	return e, syncService
+}
+
+func syncRequest(e *echo.Echo, query, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sync"+query, nil)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSyncHandler_Sync(t *testing.T) {
+	e, syncService := setupSyncTestServer()
+	checkpoint := change.Checkpoint{
+		Posts:     change.Position{At: fixtures.Time.Add(time.Minute), ID: 1},
+		Comments:  change.Position{At: fixtures.Time.Add(time.Hour), ID: 4},
+		Deletions: change.Position{At: fixtures.Time.Add(2 * time.Hour), ID: 9},
+	}
+	syncService.set = &change.Set{
+		Posts:           []*post.Post{fixtures.Post(1, 1)},
+		Comments:        []*comment.Comment{fixtures.Comment(4, 1)},
+		DeletedPosts:    []int{2},
+		DeletedComments: []int{3},
+		Checkpoint:      checkpoint,
+		HasMore:         true,
+	}
+
+	rec := syncRequest(e, "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, change.Start(), syncService.since, "clients that never synced start from the beginning")
+	assert.Equal(t, 10, syncService.limit)
+
+	var response handlers.SyncResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Posts, 1)
+	assert.Equal(t, 1, response.Posts[0].ID)
+	require.Len(t, response.Comments, 1)
+	assert.Equal(t, 4, response.Comments[0].ID)
+	assert.Equal(t, []int{2}, response.Deleted.Posts)
+	assert.Equal(t, []int{3}, response.Deleted.Comments)
+	assert.True(t, response.HasMore)
+
+	// The checkpoint returned resumes where the sync stopped
+	rec = syncRequest(e, "?since="+response.Checkpoint+"&limit=5", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, checkpoint, syncService.since)
+	assert.Equal(t, 5, syncService.limit)
+}
+
+func TestSyncHandler_Sync_Since(t *testing.T) {
+	e, syncService := setupSyncTestServer()
+
+	rec := syncRequest(e, "?since=2024-01-15T10:30:00Z", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, change.Since(fixtures.Time), syncService.since)
+
+	var response handlers.SyncResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotNil(t, response.Posts, "empty lists are returned as []")
+	assert.NotNil(t, response.Deleted.Comments)
+	assert.False(t, response.HasMore)
+
+	for _, since := range []string{"yesterday", "MTIz", "2024-01-15"} {
+		rec = syncRequest(e, "?since="+since, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, since)
+	}
+}
+
+func TestSyncHandler_Sync_GatesPosts(t *testing.T) {
+	e, syncService := setupSyncTestServer()
+	members := fixtures.Post(1, 1)
+	members.Access = post.AccessMembers
+	syncService.set = &change.Set{Posts: []*post.Post{members}}
+
+	rec := syncRequest(e, "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.SyncResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Posts, 1)
+	assert.True(t, response.Posts[0].Locked, "anonymous clients get a teaser")
+
+	rec = syncRequest(e, "", "2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Posts, 1)
+	assert.False(t, response.Posts[0].Locked)
+	assert.Equal(t, members.Content, response.Posts[0].Content)
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockTagService implements tag.Service for testing
+type MockTagService struct {
+	tags []*tag.Tag
+	err  error
+	// dryRuns records the dry_run flag of each tag change
+	dryRuns []bool
+}
+
+func (m *MockTagService) ListTags(ctx context.Context) ([]*tag.Tag, error) {
+	return m.tags, m.err
+}
+
+func (m *MockTagService) ListAllTags(ctx context.Context) ([]*tag.Tag, error) {
+	return m.tags, m.err
+}
+
+func (m *MockTagService) find(name string) (*tag.Tag, error) {
+	for _, t := range m.tags {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, tag.ErrTagNotFound
+}
+
+func (m *MockTagService) RenameTag(ctx context.Context, adminID int, name, newName string, dryRun bool) (*tag.Change, error) {
+	m.dryRuns = append(m.dryRuns, dryRun)
+	t, err := m.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.find(newName); err == nil {
+		return nil, tag.ErrTagExists
+	}
+	return &tag.Change{Tags: []*tag.Tag{t}, Target: &tag.Tag{Name: newName, PostCount: t.PostCount}, AffectedPosts: t.PostCount, DryRun: dryRun}, nil
+}
+
+func (m *MockTagService) MergeTags(ctx context.Context, adminID int, sources []string, target string, dryRun bool) (*tag.Change, error) {
+	m.dryRuns = append(m.dryRuns, dryRun)
+	into, err := m.find(target)
+	if err != nil {
+		return nil, err
+	}
+	change := &tag.Change{Target: into, DryRun: dryRun}
+	for _, name := range sources {
+		t, err := m.find(name)
+		if err != nil {
+			return nil, err
+		}
+		change.Tags = append(change.Tags, t)
+		change.AffectedPosts += t.PostCount
+	}
+	return change, nil
+}
+
+func (m *MockTagService) DeleteUnusedTags(ctx context.Context, adminID int, dryRun bool) (*tag.Change, error) {
+	m.dryRuns = append(m.dryRuns, dryRun)
+	change := &tag.Change{DryRun: dryRun}
+	for _, t := range m.tags {
+		if t.PostCount == 0 {
+			change.Tags = append(change.Tags, t)
+		}
+	}
+	return change, nil
+}
+
+func TestTagHandler_ListTags(t *testing.T) {
+	e := echo.New()
+	tagService := &MockTagService{tags: []*tag.Tag{
+		{ID: 2, Name: "golang", PostCount: 3},
+		{ID: 1, Name: "web-development", PostCount: 1},
+	}}
+	handler := handlers.NewTagHandler(tagService, NewMockLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tags", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.ListTags(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response handlers.TagListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []handlers.TagResponse{
+		{Name: "golang", PostCount: 3},
+		{Name: "web-development", PostCount: 1},
+	}, response.Tags)
+
+	// No tags is an empty list, not null
+	tagService.tags = nil
+	rec = httptest.NewRecorder()
+	require.NoError(t, handler.ListTags(e.NewContext(req, rec)))
+	assert.JSONEq(t, `{"tags":[]}`, rec.Body.String())
+
+	tagService.err = errors.New("database unavailable")
+	rec = httptest.NewRecorder()
+	require.NoError(t, handler.ListTags(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestTagHandler_AdminChanges(t *testing.T) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+	tagService := &MockTagService{tags: []*tag.Tag{
+		{ID: 1, Name: "golang", PostCount: 3},
+		{ID: 2, Name: "go-lang", PostCount: 1},
+		{ID: 3, Name: "stale", PostCount: 0},
+	}}
+	handler := handlers.NewTagHandler(tagService, NewMockLogger())
+
+	asAdmin := fixtures.ActingAs(1)
+	e.GET("/api/v1/admin/tags", handler.ListAllTags, asAdmin)
+	e.PUT("/api/v1/admin/tags/:name", handler.RenameTag, asAdmin)
+	e.POST("/api/v1/admin/tags/merge", handler.MergeTags, asAdmin)
+	e.DELETE("/api/v1/admin/tags/unused", handler.DeleteUnusedTags, asAdmin)
+
+	request := func(method, path, body string) (*httptest.ResponseRecorder, handlers.TagChangeResponse) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var response handlers.TagChangeResponse
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+
+	rec, _ := request(http.MethodGet, "/api/v1/admin/tags", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"name":"stale","post_count":0}`, "unused tags are listed to admins")
+
+	rec, change := request(http.MethodPut, "/api/v1/admin/tags/golang?dry_run=true", `{"name":"go"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, change.DryRun)
+	assert.Equal(t, 3, change.AffectedPosts)
+	assert.Equal(t, "go", change.Target.Name)
+
+	rec, _ = request(http.MethodPut, "/api/v1/admin/tags/golang", `{"name":"go-lang"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code, "renaming onto another tag asks for a merge")
+	rec, _ = request(http.MethodPut, "/api/v1/admin/tags/missing", `{"name":"go"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = request(http.MethodPut, "/api/v1/admin/tags/golang", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = request(http.MethodPut, "/api/v1/admin/tags/golang?dry_run=maybe", `{"name":"go"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, change = request(http.MethodPost, "/api/v1/admin/tags/merge", `{"sources":["go-lang"],"target":"golang"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, change.DryRun)
+	assert.Equal(t, []handlers.TagResponse{{Name: "go-lang", PostCount: 1}}, change.Tags)
+	assert.Equal(t, 1, change.AffectedPosts)
+	rec, _ = request(http.MethodPost, "/api/v1/admin/tags/merge", `{"sources":[],"target":"golang"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, change = request(http.MethodDelete, "/api/v1/admin/tags/unused?dry_run=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []handlers.TagResponse{{Name: "stale", PostCount: 0}}, change.Tags)
+
+	assert.Equal(t, []bool{true, false, false, false, true}, tagService.dryRuns)
+}
@@ -0,0 +1,205 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tip"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/stripe"
+	"blog-platform/tests/fixtures"
+)
+
+const testPaymentsSecret = "whsec_payments"
+
+// MockTipService implements tip.Service for testing; post 1 accepts tips
+type MockTipService struct {
+	tips     map[int]*tip.Tip
+	accounts map[int]*tip.PayoutAccount
+	events   []*tip.PaymentEvent
+}
+
+func (m *MockTipService) StartTip(ctx context.Context, postID, tipperID int, amount int64) (*tip.Tip, *tip.Checkout, error) {
+	if postID != 1 {
+		return nil, nil, post.ErrPostNotFound
+	}
+	if amount < 100 {
+		return nil, nil, tip.ErrInvalidAmount
+	}
+	t := &tip.Tip{ID: len(m.tips) + 1, PostID: postID, Amount: amount, Currency: "usd", Status: tip.StatusPending}
+	if tipperID > 0 {
+		t.TipperID = &tipperID
+	}
+	m.tips[t.ID] = t
+	return t, &tip.Checkout{PaymentID: "cs_" + strconv.Itoa(t.ID), URL: "https://checkout.example.com/" + strconv.Itoa(t.ID)}, nil
+}
+
+func (m *MockTipService) ApplyEvent(ctx context.Context, event *tip.PaymentEvent) error {
+	t, ok := m.tips[event.TipID]
+	if !ok {
+		return tip.ErrTipNotFound
+	}
+	t.Status = event.Status
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *MockTipService) GetPayoutAccount(ctx context.Context, userID int) (*tip.PayoutAccount, error) {
+	a, ok := m.accounts[userID]
+	if !ok {
+		return nil, tip.ErrPayoutAccountNotFound
+	}
+	return a, nil
+}
+
+func (m *MockTipService) LinkPayoutAccount(ctx context.Context, userID int, accountID string) (*tip.PayoutAccount, error) {
+	if !strings.HasPrefix(accountID, "acct_") {
+		return nil, tip.ErrInvalidAccount
+	}
+	a := &tip.PayoutAccount{UserID: userID, Provider: "stripe", AccountID: accountID, UpdatedAt: time.Now()}
+	m.accounts[userID] = a
+	return a, nil
+}
+
+func (m *MockTipService) UnlinkPayoutAccount(ctx context.Context, userID int) error {
+	if _, ok := m.accounts[userID]; !ok {
+		return tip.ErrPayoutAccountNotFound
+	}
+	delete(m.accounts, userID)
+	return nil
+}
+
+func setupTipTestServer() (*echo.Echo, *MockTipService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	tipService := &MockTipService{tips: make(map[int]*tip.Tip), accounts: make(map[int]*tip.PayoutAccount)}
+	payments := stripe.NewPaymentProvider("sk_test", stripe.DefaultAPIURL, stripe.NewWebhookVerifier(testPaymentsSecret, stripe.DefaultTolerance, nil), nil)
+	h := handlers.NewTipHandler(tipService, payments, NewMockLogger())
+
+	e.POST("/api/v1/posts/:id/tips", h.CreateTip, fixtures.AsUser)
+	e.GET("/api/v1/users/me/payout-account", h.GetPayoutAccount, fixtures.AsUser)
+	e.PUT("/api/v1/users/me/payout-account", h.LinkPayoutAccount, fixtures.AsUser)
+	e.DELETE("/api/v1/users/me/payout-account", h.UnlinkPayoutAccount, fixtures.AsUser)
+	e.POST("/api/v1/payments/stripe/webhook", h.StripeWebhook)
+
+	return e, tipService
+}
+
+func tipRequest(e *echo.Echo, method, path, body, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTipHandler_CreateTip(t *testing.T) {
+	e, tipService := setupTipTestServer()
+
+	rec := tipRequest(e, http.MethodPost, "/api/v1/posts/1/tips", `{"amount":500}`, "7")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var response handlers.TipResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, int64(500), response.Amount)
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, "https://checkout.example.com/1", response.CheckoutURL)
+	require.NotNil(t, tipService.tips[1].TipperID)
+	assert.Equal(t, 7, *tipService.tips[1].TipperID)
+
+	rec = tipRequest(e, http.MethodPost, "/api/v1/posts/1/tips", `{"amount":500}`, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Nil(t, tipService.tips[2].TipperID, "guests tip without a token")
+
+	rec = tipRequest(e, http.MethodPost, "/api/v1/posts/1/tips", `{"amount":50}`, "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = tipRequest(e, http.MethodPost, "/api/v1/posts/1/tips", `{}`, "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = tipRequest(e, http.MethodPost, "/api/v1/posts/2/tips", `{"amount":500}`, "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = tipRequest(e, http.MethodPost, "/api/v1/posts/abc/tips", `{"amount":500}`, "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTipHandler_PayoutAccount(t *testing.T) {
+	e, _ := setupTipTestServer()
+
+	rec := tipRequest(e, http.MethodGet, "/api/v1/users/me/payout-account", "", "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = tipRequest(e, http.MethodPut, "/api/v1/users/me/payout-account", `{"account_id":"acct_123"}`, "7")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.PayoutAccountResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "stripe", response.Provider)
+	assert.Equal(t, "acct_123", response.AccountID)
+
+	rec = tipRequest(e, http.MethodGet, "/api/v1/users/me/payout-account", "", "7")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = tipRequest(e, http.MethodPut, "/api/v1/users/me/payout-account", `{"account_id":"iban"}`, "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = tipRequest(e, http.MethodPut, "/api/v1/users/me/payout-account", `{}`, "7")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = tipRequest(e, http.MethodDelete, "/api/v1/users/me/payout-account", "", "7")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = tipRequest(e, http.MethodDelete, "/api/v1/users/me/payout-account", "", "7")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = tipRequest(e, http.MethodGet, "/api/v1/users/me/payout-account", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// postPaymentWebhook sends a payment webhook payload signed with secret
+func postPaymentWebhook(e *echo.Echo, secret, payload string) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/stripe/webhook", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Stripe-Signature", "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTipHandler_StripeWebhook(t *testing.T) {
+	e, tipService := setupTipTestServer()
+	tipRequest(e, http.MethodPost, "/api/v1/posts/1/tips", `{"amount":500}`, "7")
+
+	paid := `{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_status":"paid","metadata":{"tip_id":"1"}}}}`
+	rec := postPaymentWebhook(e, "whsec_other", paid)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, tip.StatusPending, tipService.tips[1].Status)
+
+	rec = postPaymentWebhook(e, testPaymentsSecret, paid)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"received":true}`, rec.Body.String())
+	assert.Equal(t, tip.StatusSucceeded, tipService.tips[1].Status)
+
+	rec = postPaymentWebhook(e, testPaymentsSecret, `{"id":"evt_2","type":"invoice.paid","data":{"object":{}}}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "other events are acknowledged")
+	assert.Len(t, tipService.events, 1)
+
+	// Unknown tips are refused so Stripe retries them
+	rec = postPaymentWebhook(e, testPaymentsSecret, strings.Replace(paid, `"tip_id":"1"`, `"tip_id":"9"`, 1))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+func setupTwoFactorTestServer(t *testing.T) (*echo.Echo, *MockAuthService, int) {
+	t.Helper()
+
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	userService := NewMockUserService()
+	authService := NewMockAuthService(userService)
+	u, err := userService.Register(context.Background(), "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+
+	logger := NewMockLogger()
+	authHandler := handlers.NewAuthHandler(userService, authService, logger)
+	twoFactorHandler := handlers.NewTwoFactorHandler(authService, logger)
+
+	signedIn := fixtures.ActingAs(u.ID)
+	e.POST("/api/v1/auth/login", authHandler.Login)
+	e.POST("/api/v1/auth/login/2fa", twoFactorHandler.Login)
+	e.POST("/api/v1/users/me/2fa/enable", twoFactorHandler.Enable, signedIn)
+	e.POST("/api/v1/users/me/2fa/confirm", twoFactorHandler.Confirm, signedIn)
+	e.POST("/api/v1/users/me/2fa/disable", twoFactorHandler.Disable, signedIn)
+
+	return e, authService, u.ID
+}
+
+func postJSON(e *echo.Echo, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTwoFactorHandler_Enrollment(t *testing.T) {
+	e, authService, userID := setupTwoFactorTestServer(t)
+
+	rec := postJSON(e, "/api/v1/users/me/2fa/enable", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var enrollment handlers.TwoFactorEnrollmentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &enrollment))
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/confirm", `{"code":"12345"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/confirm", `{"code":"654321"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/confirm", `{"code":"123456"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, authService.twoFactorCodes, userID)
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/enable", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/disable", `{"code":"123456"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, authService.twoFactorCodes, userID)
+
+	rec = postJSON(e, "/api/v1/users/me/2fa/disable", `{"code":"123456"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTwoFactorHandler_Login(t *testing.T) {
+	e, authService, userID := setupTwoFactorTestServer(t)
+	authService.twoFactorCodes[userID] = "123456"
+
+	rec := postJSON(e, "/api/v1/auth/login", `{"email":"john@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var challenge handlers.TwoFactorChallengeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &challenge))
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.Equal(t, "mock-2fa-token", challenge.TwoFactorToken)
+	assert.Equal(t, 300, challenge.ExpiresIn)
+	assert.NotContains(t, rec.Body.String(), "mock-jwt-token")
+
+	t.Run("wrong code", func(t *testing.T) {
+		rec := postJSON(e, "/api/v1/auth/login/2fa", `{"token":"mock-2fa-token","code":"000000"}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid_credentials")
+	})
+
+	t.Run("invalid challenge", func(t *testing.T) {
+		rec := postJSON(e, "/api/v1/auth/login/2fa", `{"token":"mock-jwt-token","code":"123456"}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unauthorized")
+	})
+
+	t.Run("valid code", func(t *testing.T) {
+		rec := postJSON(e, "/api/v1/auth/login/2fa", `{"token":"mock-2fa-token","code":"123456"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response handlers.AuthResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, userID, response.User.ID)
+		assert.Equal(t, "mock-jwt-token", response.Token)
+	})
+}
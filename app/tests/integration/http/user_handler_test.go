@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+func setupUserTestServer(t *testing.T) (*echo.Echo, *MockUserService, int) {
+	t.Helper()
+
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	userService := NewMockUserService()
+	u, err := userService.Register(context.Background(), "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+	_, err = userService.Register(context.Background(), "Jane Doe", "jane@example.com", "password123")
+	require.NoError(t, err)
+
+	userHandler := handlers.NewUserHandler(userService, NewMockLogger())
+
+	signedIn := fixtures.ActingAs(u.ID)
+	e.GET("/api/v1/users/me", userHandler.GetAccount, signedIn)
+	e.PUT("/api/v1/users/me", userHandler.UpdateAccount, signedIn)
+	e.PUT("/api/v1/users/me/password", userHandler.ChangePassword, signedIn)
+	e.GET("/api/v1/anonymous/me", userHandler.GetAccount)
+
+	return e, userService, u.ID
+}
+
+func putJSON(e *echo.Echo, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUserHandler_GetAccount(t *testing.T) {
+	e, _, userID := setupUserTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.UserResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, userID, response.ID)
+	assert.Equal(t, "john@example.com", response.Email)
+	assert.NotContains(t, rec.Body.String(), "password")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/anonymous/me", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestUserHandler_UpdateAccount(t *testing.T) {
+	e, _, _ := setupUserTestServer(t)
+
+	rec := putJSON(e, "/api/v1/users/me", `{"name":"Johnny Doe","email":"john@example.com"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response handlers.UserResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Johnny Doe", response.Name)
+
+	rec = putJSON(e, "/api/v1/users/me", `{"name":"John Doe","email":"john.doe@example.com"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "john@example.com", response.Email, "the current email stays until the change is confirmed")
+
+	rec = putJSON(e, "/api/v1/users/me", `{"name":"John Doe","email":"jane@example.com"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = putJSON(e, "/api/v1/users/me", `{"name":"","email":"john@example.com"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = putJSON(e, "/api/v1/users/me", `{"name":"John Doe","email":"not-an-email"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUserHandler_ChangePassword(t *testing.T) {
+	e, userService, userID := setupUserTestServer(t)
+
+	rec := putJSON(e, "/api/v1/users/me/password", `{"current_password":"wrong-password1","new_password":"newpassword456"}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = putJSON(e, "/api/v1/users/me/password", `{"current_password":"password123","new_password":"short"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = putJSON(e, "/api/v1/users/me/password", `{"current_password":"password123","new_password":"newpassword456"}`)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	u, err := userService.GetByID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.True(t, u.ValidatePassword("newpassword456"))
+	assert.False(t, u.ValidatePassword("password123"))
+}
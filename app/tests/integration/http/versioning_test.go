@@ -0,0 +1,120 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/http/errors"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// setupVersionedTestServer serves the post routes under both API versions
+func setupVersionedTestServer() (*echo.Echo, *MockPostService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	postService := NewMockPostService()
+	postService.posts[1] = fixtures.Post(1, 1)
+	postService.posts[2] = fixtures.Draft(2, 1)
+	postService.nextID = 3
+	postHandler := handlers.NewPostHandler(postService, NewMockProgressService(), NewMockProfileService(), NewMockRedirectService(postService), service.DefaultPaginationPolicy().Posts, NewMockLogger())
+
+	for _, mapper := range []handlers.ResponseMapper{handlers.V1Mapper{}, handlers.V2Mapper{}} {
+		h := postHandler.WithMapper(mapper)
+		g := e.Group(mapper.Version().Prefix()+"/posts", fixtures.AsUser)
+		g.GET("", h.ListPosts)
+		g.GET("/:id", h.GetPost)
+		g.GET("/slug/:slug", h.GetPostBySlug)
+		g.POST("", h.CreatePost)
+	}
+	return e, postService
+}
+
+func TestVersioning_V2Posts(t *testing.T) {
+	e, postService := setupVersionedTestServer()
+
+	rec := redirectRequest(e, http.MethodGet, "/api/v2/posts/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var single handlers.PostEnvelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &single))
+	assert.Equal(t, 1, single.Data.ID)
+	assert.True(t, fixtures.Time.Add(time.Minute).Equal(single.Data.CreatedAt))
+	require.NotNil(t, single.Data.PublishedAt)
+	assert.True(t, single.Data.CreatedAt.Equal(*single.Data.PublishedAt))
+	assert.NotEmpty(t, rec.Header().Get("ETag"), "v2 reads revalidate like v1")
+
+	// v1 keeps its flat representation
+	rec = redirectRequest(e, http.MethodGet, "/api/v1/posts/1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var flat map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &flat))
+	assert.Equal(t, float64(1), flat["id"])
+	assert.NotContains(t, flat, "data")
+
+	rec = redirectRequest(e, http.MethodPost, "/api/v2/posts", `{"title":"Drafted","content":"This is a draft post.","draft":true}`, "1")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var raw struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	assert.Contains(t, raw.Data, "published_at")
+	assert.Nil(t, raw.Data["published_at"], "unset timestamps are null")
+
+	rec = redirectRequest(e, http.MethodGet, "/api/v2/posts?limit=1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.JSONEq(t, `{"limit":1,"offset":0}`, string(list["meta"]))
+	var posts []handlers.PostV2Response
+	require.NoError(t, json.Unmarshal(list["data"], &posts))
+	assert.Len(t, posts, 1)
+
+	// Old slugs redirect within the version
+	postService.oldSlugs["old-post-1"] = 1
+	rec = redirectRequest(e, http.MethodGet, "/api/v2/posts/slug/old-post-1", "", "")
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/api/v2/posts/slug/post-1", rec.Header().Get(echo.HeaderLocation))
+
+	// Errors keep the format shared by all versions
+	rec = redirectRequest(e, http.MethodGet, "/api/v2/posts/99", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var errResponse handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResponse))
+	assert.Equal(t, string(errors.ErrCodeNotFound), errResponse.Error)
+}
+
+func TestRouteRegistry_VersionRouteDocs(t *testing.T) {
+	e, _ := setupVersionedTestServer()
+	e.GET("/api/v2/only-in-v2", func(c echo.Context) error { return nil })
+
+	registry := handlers.NewRouteRegistry()
+	registry.Register(handlers.RouteDoc{
+		Method:          http.MethodGet,
+		Path:            "/api/v1/posts/{id}",
+		Summary:         "Get a blog post by ID",
+		ResponseStatus:  http.StatusOK,
+		ResponseExample: handlers.PostResponse{ID: 1, CreatedAt: "2024-01-15T10:30:00Z"},
+		Errors:          []errors.ErrorCode{errors.ErrCodeNotFound},
+	})
+	registry.VersionRouteDocs(handlers.V2Mapper{}, e.Routes())
+
+	doc, ok := registry.Lookup(http.MethodGet, "/api/v2/posts/{id}")
+	require.True(t, ok)
+	assert.Equal(t, []errors.ErrorCode{errors.ErrCodeNotFound}, doc.Errors)
+	require.IsType(t, handlers.PostEnvelope{}, doc.ResponseExample)
+	assert.Equal(t, 1, doc.ResponseExample.(handlers.PostEnvelope).Data.ID)
+
+	_, ok = registry.Lookup(http.MethodGet, "/api/v2/posts")
+	assert.False(t, ok, "routes without v1 docs stay undocumented")
+	_, ok = registry.Lookup(http.MethodGet, "/api/v2/only-in-v2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/webhook"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/tests/fixtures"
+)
+
+// MockWebhookService implements webhook.Service for testing
+type MockWebhookService struct {
+	webhooks   map[int]*webhook.Webhook
+	deliveries map[int][]*webhook.Delivery
+}
+
+func NewMockWebhookService() *MockWebhookService {
+	return &MockWebhookService{webhooks: make(map[int]*webhook.Webhook), deliveries: make(map[int][]*webhook.Delivery)}
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, adminID int, in webhook.Input) (*webhook.Webhook, error) {
+	w, err := webhook.NewWebhook(adminID, "whsec_test", in)
+	if err != nil {
+		return nil, err
+	}
+	w.ID = len(m.webhooks) + 1
+	m.webhooks[w.ID] = w
+	return w, nil
+}
+
+func (m *MockWebhookService) Get(ctx context.Context, id int) (*webhook.Webhook, error) {
+	w, ok := m.webhooks[id]
+	if !ok {
+		return nil, webhook.ErrWebhookNotFound
+	}
+	return w, nil
+}
+
+func (m *MockWebhookService) Update(ctx context.Context, adminID, id int, in webhook.Input) (*webhook.Webhook, error) {
+	w, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Update(in); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, adminID, id int) error {
+	if _, ok := m.webhooks[id]; !ok {
+		return webhook.ErrWebhookNotFound
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *MockWebhookService) List(ctx context.Context) ([]*webhook.Webhook, error) {
+	var webhooks []*webhook.Webhook
+	for id := len(m.webhooks); id > 0; id-- {
+		if w, ok := m.webhooks[id]; ok {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, id, limit, offset int) ([]*webhook.Delivery, int, error) {
+	if _, err := m.Get(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	deliveries := m.deliveries[id]
+	total := len(deliveries)
+	if offset > total {
+		offset = total
+	}
+	deliveries = deliveries[offset:]
+	if limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, total, nil
+}
+
+func setupWebhookTestServer() (*echo.Echo, *MockWebhookService) {
+	e := echo.New()
+	e.Validator = middleware.NewValidator()
+
+	webhookService := NewMockWebhookService()
+	h := handlers.NewWebhookHandler(webhookService, service.PageLimits{Default: 10, Max: 50}, NewMockLogger())
+
+	e.GET("/api/v1/admin/webhooks", h.List, fixtures.AsUser)
+	e.POST("/api/v1/admin/webhooks", h.Create, fixtures.AsUser)
+	e.PUT("/api/v1/admin/webhooks/:id", h.Update, fixtures.AsUser)
+	e.DELETE("/api/v1/admin/webhooks/:id", h.Delete, fixtures.AsUser)
+	e.GET("/api/v1/admin/webhooks/:id/deliveries", h.ListDeliveries, fixtures.AsUser)
+
+	return e, webhookService
+}
+
+func webhookRequest(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(fixtures.UserHeader, "1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler_Manage(t *testing.T) {
+	e, webhookService := setupWebhookTestServer()
+
+	rec := webhookRequest(e, http.MethodPost, "/api/v1/admin/webhooks", `{"url":"https://hooks.example.com/blog","events":["post.published","user.registered"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created handlers.WebhookEndpointResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "whsec_test", created.Secret, "the secret is returned on creation")
+	assert.True(t, created.Active, "webhooks are active by default")
+	assert.Equal(t, []string{webhook.EventPostPublished, webhook.EventUserRegistered}, created.Events)
+
+	rec = webhookRequest(e, http.MethodPost, "/api/v1/admin/webhooks", `{"url":"https://hooks.example.com","events":["post.deleted"]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = webhookRequest(e, http.MethodPost, "/api/v1/admin/webhooks", `{"url":"not a url","events":["post.published"]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = webhookRequest(e, http.MethodPost, "/api/v1/admin/webhooks", `{"url":"https://hooks.example.com","events":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = webhookRequest(e, http.MethodPut, "/api/v1/admin/webhooks/1", `{"url":"https://hooks.example.com/v2","events":["comment.created"],"active":false}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, webhookService.webhooks[1].Active)
+	assert.NotContains(t, rec.Body.String(), "whsec_test", "the secret is not shown again")
+	rec = webhookRequest(e, http.MethodPut, "/api/v1/admin/webhooks/9", `{"url":"https://hooks.example.com","events":["comment.created"]}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = webhookRequest(e, http.MethodGet, "/api/v1/admin/webhooks", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.WebhookEndpointListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	assert.Empty(t, list.Webhooks[0].Secret)
+
+	rec = webhookRequest(e, http.MethodDelete, "/api/v1/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = webhookRequest(e, http.MethodDelete, "/api/v1/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	e, webhookService := setupWebhookTestServer()
+	webhookRequest(e, http.MethodPost, "/api/v1/admin/webhooks", `{"url":"https://hooks.example.com/blog","events":["post.published"]}`)
+
+	failed := webhook.NewDelivery(1, webhook.EventPostPublished, `{"event":"post.published","data":{"id":7}}`)
+	failed.ID, failed.Status, failed.Attempts, failed.ResponseStatus, failed.Error = 2, webhook.DeliveryFailed, 8, 500, "unexpected status 500"
+	delivered := webhook.NewDelivery(1, webhook.EventPostPublished, `{"event":"post.published","data":{"id":6}}`)
+	delivered.ID, delivered.Status, delivered.Attempts, delivered.ResponseStatus = 1, webhook.DeliveryDelivered, 1, 200
+	webhookService.deliveries[1] = []*webhook.Delivery{failed, delivered}
+
+	rec := webhookRequest(e, http.MethodGet, "/api/v1/admin/webhooks/1/deliveries?limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page handlers.WebhookDeliveryListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, 1, page.Limit)
+	require.Len(t, page.Deliveries, 1)
+	assert.Equal(t, "failed", page.Deliveries[0].Status)
+	assert.Equal(t, 500, page.Deliveries[0].ResponseStatus)
+	assert.JSONEq(t, failed.Payload, string(page.Deliveries[0].Payload), "payloads are returned as JSON")
+
+	rec = webhookRequest(e, http.MethodGet, "/api/v1/admin/webhooks/9/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = webhookRequest(e, http.MethodGet, "/api/v1/admin/webhooks/abc/deliveries", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/widget"
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/http/handlers"
+	"blog-platform/internal/infrastructure/http/middleware"
+	"blog-platform/internal/infrastructure/http/views"
+	"blog-platform/tests/fixtures"
+)
+
+const widgetOrigin = "https://friends.example.org"
+
+func setupWidgetTestServer(t *testing.T) (*echo.Echo, *MockPostService, *MockCommentService) {
+	renderer, err := views.NewRenderer(views.Options{})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Renderer = renderer
+	e.Validator = middleware.NewValidator()
+
+	cfg := &config.Config{
+		CORS:   config.CORSConfig{AllowedOrigins: []string{"https://blog.example.com"}},
+		Widget: config.WidgetConfig{Enabled: true},
+	}
+	sites := widget.NewRegistry(map[string][]string{"pk_friends": {widgetOrigin}})
+	e.Use(middleware.WidgetCORS(sites, NewMockLogger()))
+	e.Use(middleware.CORS(cfg))
+	e.Use(middleware.SecurityHeaders())
+
+	e.Use(fixtures.AsUser)
+
+	postService := NewMockPostService()
+	commentService := NewMockCommentService()
+	h := handlers.NewWidgetHandler(postService, commentService, service.DefaultPaginationPolicy().Comments, handlers.WidgetSettings{SiteName: "Test Blog"}, NewMockLogger())
+	e.GET("/api/v1/widget/posts/:id/comments", h.ListComments)
+	e.POST("/api/v1/widget/posts/:id/comments", h.CreateComment)
+	e.GET("/api/v1/widget/posts/:id/thread", h.ShowThread)
+
+	return e, postService, commentService
+}
+
+func widgetRequest(e *echo.Echo, method, path, origin, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	if origin != "" {
+		req.Header.Set(echo.HeaderOrigin, origin)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWidget_SiteAndOriginChecks(t *testing.T) {
+	e, postService, _ := setupWidgetTestServer(t)
+	p, err := postService.CreatePost(context.Background(), 1, "Embedded Post", "Content of the embedded post")
+	require.NoError(t, err)
+	path := "/api/v1/widget/posts/" + strconv.Itoa(p.ID) + "/comments"
+
+	rec := widgetRequest(e, http.MethodGet, path+"?token=pk_friends", widgetOrigin, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, widgetOrigin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+
+	// Preflight requests are answered for the site's origins only
+	rec = widgetRequest(e, http.MethodOptions, path+"?token=pk_friends", widgetOrigin, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, widgetOrigin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowMethods), http.MethodPost)
+	assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowHeaders), "Authorization")
+
+	rec = widgetRequest(e, http.MethodGet, path+"?token=pk_friends", "https://blog.example.com", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = widgetRequest(e, http.MethodGet, path+"?token=pk_unknown", widgetOrigin, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = widgetRequest(e, http.MethodGet, path, widgetOrigin, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Writes must come from a page of the site
+	rec = widgetRequest(e, http.MethodPost, path+"?token=pk_friends", "", `{"author_name":"Guest","content":"Hello there"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// The site-wide CORS rules still apply elsewhere
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/other", nil)
+	req.Header.Set(echo.HeaderOrigin, widgetOrigin)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
+
+func TestWidget_Comments(t *testing.T) {
+	e, postService, commentService := setupWidgetTestServer(t)
+	ctx := context.Background()
+	p, err := postService.CreatePost(ctx, 1, "Embedded Post", "Content of the embedded post")
+	require.NoError(t, err)
+	path := "/api/v1/widget/posts/" + strconv.Itoa(p.ID) + "/comments?token=pk_friends"
+
+	rec := widgetRequest(e, http.MethodPost, path, widgetOrigin, `{"author_name":"Guest","content":"Hello from the widget"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Nil(t, created.UserID)
+
+	// Signed-in users comment with their access token
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"author_name":"Jane","content":"A reply from Jane","parent_id":`+strconv.Itoa(created.ID)+`}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderOrigin, widgetOrigin)
+	req.Header.Set(fixtures.UserHeader, "7")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var reply handlers.CommentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reply))
+	require.NotNil(t, reply.UserID)
+	assert.Equal(t, 7, *reply.UserID)
+
+	rec = widgetRequest(e, http.MethodGet, path, widgetOrigin, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list handlers.ThreadedCommentListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Comments, 1)
+	assert.Equal(t, 1, list.Comments[0].ReplyCount)
+
+	// Drafts are not available to the widget
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Not published yet")
+	require.NoError(t, err)
+	draftPath := "/api/v1/widget/posts/" + strconv.Itoa(draft.ID) + "/comments?token=pk_friends"
+	rec = widgetRequest(e, http.MethodGet, draftPath, widgetOrigin, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = widgetRequest(e, http.MethodPost, draftPath, widgetOrigin, `{"author_name":"Guest","content":"Too early"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Len(t, commentService.comments, 2)
+}
+
+func TestWidget_ShowThread(t *testing.T) {
+	e, postService, commentService := setupWidgetTestServer(t)
+	ctx := context.Background()
+	p, err := postService.CreatePost(ctx, 1, "Embedded Post", "Content of the embedded post")
+	require.NoError(t, err)
+	parent, err := commentService.AddComment(ctx, p.ID, 0, "", "Jane Smith", "First <b>comment</b>")
+	require.NoError(t, err)
+	_, err = commentService.ReplyToComment(ctx, p.ID, parent.ID, 0, "", "John Doe", "A nested reply")
+	require.NoError(t, err)
+
+	// Iframes load the thread without an Origin header
+	rec := widgetRequest(e, http.MethodGet, "/api/v1/widget/posts/"+strconv.Itoa(p.ID)+"/thread?token=pk_friends", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+	assert.Empty(t, rec.Header().Get("X-Frame-Options"))
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "frame-ancestors "+widgetOrigin)
+	assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "Comments on Embedded Post")
+	assert.Contains(t, body, `<div class="reply">`)
+	assert.Contains(t, body, "A nested reply")
+	assert.NotContains(t, body, "<b>comment</b>")
+	assert.NotContains(t, body, "<header>")
+
+	rec = widgetRequest(e, http.MethodGet, "/api/v1/widget/posts/999/thread?token=pk_friends", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/idempotency"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestIdempotencyRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM idempotency_keys")
+
+	repo := repository.NewIdempotencyRepository(db.DB)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	record := &idempotency.Record{
+		Scope:       "user:1",
+		Key:         "create-post-1",
+		RequestHash: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	if err := repo.Create(ctx, record); err != nil {
+		t.Fatalf("failed to create record: %v", err)
+	}
+	if record.ID == 0 {
+		t.Error("expected the record to get an ID")
+	}
+
+	duplicate := *record
+	if err := repo.Create(ctx, &duplicate); !errors.Is(err, idempotency.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey for the same scope and key, got %v", err)
+	}
+	other := *record
+	other.Scope = "ip:192.0.2.1"
+	if err := repo.Create(ctx, &other); err != nil {
+		t.Errorf("expected keys of other scopes to be independent, got %v", err)
+	}
+
+	got, err := repo.Get(ctx, "user:1", "create-post-1")
+	if err != nil {
+		t.Fatalf("failed to get record: %v", err)
+	}
+	if got.Completed() || got.RequestHash != record.RequestHash {
+		t.Errorf("expected the in-progress record, got %+v", got)
+	}
+
+	record.StatusCode = 201
+	record.ContentType = "application/json"
+	record.Body = []byte(`{"id":7}`)
+	if err := repo.Complete(ctx, record); err != nil {
+		t.Fatalf("failed to complete record: %v", err)
+	}
+	got, _ = repo.Get(ctx, "user:1", "create-post-1")
+	if got.StatusCode != 201 || got.ContentType != "application/json" || string(got.Body) != `{"id":7}` {
+		t.Errorf("expected the stored response, got %+v", got)
+	}
+
+	other.ExpiresAt = now.Add(-time.Minute)
+	db.Exec(db.Rebind("UPDATE idempotency_keys SET expires_at = ? WHERE id = ?"), other.ExpiresAt, other.ID)
+	deleted, err := repo.DeleteExpired(ctx, now)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 expired record deleted, got %d, %v", deleted, err)
+	}
+	if _, err := repo.Get(ctx, other.Scope, other.Key); !errors.Is(err, idempotency.ErrRecordNotFound) {
+		t.Errorf("expected the expired record to be gone, got %v", err)
+	}
+
+	if err := repo.Delete(ctx, record.ID); err != nil {
+		t.Fatalf("failed to delete record: %v", err)
+	}
+	if err := repo.Delete(ctx, record.ID); !errors.Is(err, idempotency.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestImportRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+	defer db.Exec("DELETE FROM imports")
+
+	repo := repository.NewImportRepository(db.DB)
+	ctx := context.Background()
+
+	admin, _ := user.NewUser("Test Admin", "importtest@example.com", "password123")
+	if err := repository.NewUserRepository(db.DB).Create(ctx, admin); err != nil {
+		t.Fatalf("failed to create admin: %v", err)
+	}
+
+	published := time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC)
+	doc := &imports.Document{
+		Source:  imports.SourceWordPress,
+		Authors: []imports.Author{{Login: "jane", Name: "Jane Doe", Email: "jane@example.com"}},
+		Posts: []imports.Post{{
+			Title:       "Hello World",
+			Slug:        "hello-world",
+			Content:     "The first post.",
+			AuthorLogin: "jane",
+			PublishedAt: &published,
+			Tags:        []string{"news"},
+			Comments:    []imports.Comment{{ID: "1", AuthorName: "Alice", Content: "Nice!", Approved: true}},
+		}},
+	}
+	i, err := imports.NewImport(admin.ID, doc, true)
+	if err != nil {
+		t.Fatalf("failed to create import entity: %v", err)
+	}
+	if err := repo.Create(ctx, i); err != nil {
+		t.Fatalf("failed to create import: %v", err)
+	}
+	if i.ID == 0 {
+		t.Error("expected the import to get an ID")
+	}
+
+	got, err := repo.GetByID(ctx, i.ID)
+	if err != nil {
+		t.Fatalf("failed to get import: %v", err)
+	}
+	if got.Status != imports.StatusQueued || !got.DryRun || got.Total != 1 || got.CreatedBy != admin.ID {
+		t.Errorf("expected the queued dry run, got %+v", got)
+	}
+	if got.Document == nil || len(got.Document.Posts) != 1 || got.Document.Posts[0].Comments[0].AuthorName != "Alice" {
+		t.Fatalf("expected the stored document, got %+v", got.Document)
+	}
+	if !got.Document.Posts[0].PublishedAt.Equal(published) {
+		t.Errorf("expected the publication time to be kept, got %v", got.Document.Posts[0].PublishedAt)
+	}
+
+	got.Status = imports.StatusRunning
+	got.Processed, got.PostsCreated, got.CommentsCreated = 1, 1, 1
+	got.Fail("post 2: content is empty")
+	got.Complete()
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update import: %v", err)
+	}
+
+	updated, err := repo.GetByID(ctx, i.ID)
+	if err != nil {
+		t.Fatalf("failed to get import: %v", err)
+	}
+	if !updated.IsCompleted() || updated.CompletedAt == nil || updated.Processed != 1 || updated.PostsCreated != 1 || updated.Failed != 1 {
+		t.Errorf("expected the stored progress, got %+v", updated)
+	}
+	if len(updated.Errors) != 1 || updated.Errors[0] != "post 2: content is empty" {
+		t.Errorf("expected the stored errors, got %v", updated.Errors)
+	}
+
+	if _, err := repo.GetByID(ctx, i.ID+1000); !errors.Is(err, imports.ErrImportNotFound) {
+		t.Errorf("expected ErrImportNotFound, got %v", err)
+	}
+}
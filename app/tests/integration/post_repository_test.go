@@ -0,0 +1,587 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestPostRepository_Integration_SlugConflict(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "slugtest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+
+	// Both posts were given the same free slug, as concurrent requests would be
+	first, _ := post.NewPost("Racing Slugs", "Test content with sufficient length.", author.ID)
+	second, _ := post.NewPost("Racing Slugs", "Test content with sufficient length.", author.ID)
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("failed to create first post: %v", err)
+	}
+	if err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("failed to create second post: %v", err)
+	}
+
+	if first.Slug != "racing-slugs" {
+		t.Errorf("expected slug racing-slugs, got %s", first.Slug)
+	}
+	if second.Slug != "racing-slugs-2" {
+		t.Errorf("expected slug racing-slugs-2, got %s", second.Slug)
+	}
+
+	// Give up once every retry is taken
+	for n := 3; n <= 6; n++ {
+		p, _ := post.NewPost("Racing Slugs", "Test content with sufficient length.", author.ID)
+		p.Slug = post.NumberedSlug("racing-slugs", n)
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post %d: %v", n, err)
+		}
+	}
+	last, _ := post.NewPost("Racing Slugs", "Test content with sufficient length.", author.ID)
+	last.Slug = "racing-slugs"
+	if err := repo.Create(ctx, last); err != post.ErrSlugTaken {
+		t.Errorf("expected ErrSlugTaken, got %v", err)
+	}
+}
+
+func TestPostRepository_Integration_DeleteWithComments(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	comments := repository.NewCommentRepository(db.DB)
+	txManager := repository.NewTxManager(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "deletetest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	p, _ := post.NewPost("Doomed Post", "Test content with sufficient length.", author.ID)
+	if err := repo.Create(ctx, p); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		c, _ := comment.NewComment(p.ID, "Reader", "Test comment content.")
+		if err := comments.Create(ctx, c); err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := comments.DeleteByPostID(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		if deleted != 2 {
+			t.Errorf("expected 2 deleted comments, got %d", deleted)
+		}
+		return repo.Delete(ctx, p.ID)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := repo.GetByID(ctx, p.ID); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+	remaining, err := comments.GetByPostID(ctx, p.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list comments: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("expected no remaining comments, got %d", len(remaining))
+	}
+}
+
+func TestPostRepository_Integration_ListSharingTags(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "relatedtest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	create := func(p *post.Post, tags ...string) *post.Post {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		if err := repo.SetTags(ctx, p.ID, tags); err != nil {
+			t.Fatalf("failed to set tags: %v", err)
+		}
+		return p
+	}
+	source, _ := post.NewPost("Source Post", "Test content with sufficient length.", author.ID)
+	one, _ := post.NewPost("One Shared Tag", "Test content with sufficient length.", author.ID)
+	two, _ := post.NewPost("Two Shared Tags", "Test content with sufficient length.", author.ID)
+	draft, _ := post.NewDraft("Draft Post", "Test content with sufficient length.", author.ID)
+	unrelated, _ := post.NewPost("Unrelated Post", "Test content with sufficient length.", author.ID)
+	create(source, "go", "databases")
+	create(one, "go")
+	create(two, "go", "databases", "testing")
+	create(draft, "go", "databases")
+	create(unrelated, "cooking")
+
+	// Posts sharing the most tags come first; drafts and the post itself are left out
+	related, err := repo.ListSharingTags(ctx, source.ID, 10)
+	if err != nil {
+		t.Fatalf("failed to list posts sharing tags: %v", err)
+	}
+	if len(related) != 2 || related[0].ID != two.ID || related[1].ID != one.ID {
+		t.Fatalf("expected posts %d and %d, got %v", two.ID, one.ID, related)
+	}
+	if len(related[0].Tags) != 3 {
+		t.Errorf("expected the tags of related posts loaded, got %v", related[0].Tags)
+	}
+
+	if limited, err := repo.ListSharingTags(ctx, source.ID, 1); err != nil || len(limited) != 1 {
+		t.Errorf("expected the limit applied, got %d posts (%v)", len(limited), err)
+	}
+}
+
+func TestPostRepository_Integration_ListActivity(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	comments := repository.NewCommentRepository(db.DB)
+	bookmarks := repository.NewBookmarkRepository(db.DB)
+	ctx := context.Background()
+
+	reader, _ := user.NewUser("Test Reader", "activitytest@example.com", "password123")
+	if err := users.Create(ctx, reader); err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+	published, _ := post.NewPost("Active Post", "Test content with sufficient length.", reader.ID)
+	draft, _ := post.NewDraft("Draft Post", "Test content with sufficient length.", reader.ID)
+	for _, p := range []*post.Post{published, draft} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+	}
+
+	today := post.ViewDay(time.Now())
+	err := repo.AddViews(ctx, []post.ViewCount{
+		{PostID: published.ID, Day: today, Views: 4},
+		{PostID: published.ID, Day: today.AddDate(0, 0, -10), Views: 100},
+		{PostID: draft.ID, Day: today, Views: 7},
+	})
+	if err != nil {
+		t.Fatalf("failed to add views: %v", err)
+	}
+	for _, p := range []*post.Post{published, draft} {
+		if err := bookmarks.Add(ctx, reader.ID, p.ID); err != nil {
+			t.Fatalf("failed to bookmark post: %v", err)
+		}
+		c, _ := comment.NewComment(p.ID, "Reader", "Test comment content.")
+		if err := comments.Create(ctx, c); err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+	held, _ := comment.NewComment(published.ID, "Reader", "Held for moderation.")
+	held.Status = comment.StatusPending
+	if err := comments.Create(ctx, held); err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+
+	// Old views, drafts and comments held for moderation are left out; the
+	// seeded posts have comments of their own
+	activity, err := repo.ListActivity(ctx, time.Now().Add(-72*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to list activity: %v", err)
+	}
+	counts := make(map[post.ActivityKind]int64)
+	for _, a := range activity {
+		if a.PostID == draft.ID {
+			t.Errorf("expected no activity on draft %d, got %+v", draft.ID, a)
+		}
+		if a.PostID != published.ID {
+			continue
+		}
+		if a.Kind == post.ActivityView && !a.At.Equal(today) {
+			t.Errorf("expected views counted on %v, got %v", today, a.At)
+		}
+		counts[a.Kind] += a.Count
+	}
+	if counts[post.ActivityView] != 4 || counts[post.ActivityBookmark] != 1 || counts[post.ActivityComment] != 1 {
+		t.Errorf("expected 4 views, 1 bookmark and 1 comment, got %v", counts)
+	}
+
+	posts, err := repo.ListPublishedByIDs(ctx, []int{published.ID, draft.ID, 9999})
+	if err != nil {
+		t.Fatalf("failed to list posts by ID: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != published.ID {
+		t.Errorf("expected only post %d, got %v", published.ID, posts)
+	}
+
+	// The comment feed of the author leaves out the comments on drafts
+	recent, err := comments.ListRecent(ctx, comment.RecentFilter{PostAuthorID: reader.ID}, 10)
+	if err != nil {
+		t.Fatalf("failed to list recent comments: %v", err)
+	}
+	if len(recent) != 1 || recent[0].PostID != published.ID {
+		t.Errorf("expected only the comment on post %d, got %v", published.ID, recent)
+	}
+}
+
+func TestPostRepository_Integration_ReadingStats(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "statstest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	p, _ := post.NewPost("Stats Post", "Intro paragraph.\n\nTest content with sufficient length.", author.ID)
+	if err := repo.Create(ctx, p); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	stored, err := repo.GetByID(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if stored.WordCount != 7 || stored.ReadingTime != 1 || stored.Excerpt != "Intro paragraph." {
+		t.Errorf("expected the stats stored, got %d words, %d minutes, excerpt %q", stored.WordCount, stored.ReadingTime, stored.Excerpt)
+	}
+
+	// The seeded posts predate the stats; the new post is not listed
+	missing, err := repo.ListMissingReadingStats(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("failed to list posts missing reading stats: %v", err)
+	}
+	if len(missing) == 0 {
+		t.Fatal("expected the seeded posts to miss reading stats")
+	}
+	for _, m := range missing {
+		if m.ID == p.ID {
+			t.Errorf("expected post %d not to be listed", p.ID)
+		}
+	}
+
+	seeded := missing[0]
+	seeded.RefreshReadingStats()
+	if err := repo.SetReadingStats(ctx, seeded); err != nil {
+		t.Fatalf("failed to set reading stats: %v", err)
+	}
+	computed, err := repo.GetByID(ctx, seeded.ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if computed.ReadingTime == 0 || computed.Excerpt == "" {
+		t.Errorf("expected the stats saved, got %d minutes, excerpt %q", computed.ReadingTime, computed.Excerpt)
+	}
+	if !computed.UpdatedAt.Equal(seeded.UpdatedAt) {
+		t.Errorf("expected updated_at kept at %v, got %v", seeded.UpdatedAt, computed.UpdatedAt)
+	}
+
+	after, err := repo.ListMissingReadingStats(ctx, seeded.ID, 100)
+	if err != nil {
+		t.Fatalf("failed to list posts missing reading stats: %v", err)
+	}
+	if len(after) != len(missing)-1 {
+		t.Errorf("expected %d posts left after %d, got %d", len(missing)-1, seeded.ID, len(after))
+	}
+}
+
+func TestPostRepository_Integration_Curation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "curationtest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	older, _ := post.NewPost("Older Post", "Test content with sufficient length.", author.ID)
+	newer, _ := post.NewPost("Newer Post", "Test content with sufficient length.", author.ID)
+	for _, p := range []*post.Post{older, newer} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+	}
+
+	now := time.Now()
+	older.SetPinned(true, now)
+	older.SetFeatured(true, now.Add(-time.Hour))
+	newer.SetFeatured(true, now)
+	for _, p := range []*post.Post{older, newer} {
+		if err := repo.Update(ctx, p); err != nil {
+			t.Fatalf("failed to update post: %v", err)
+		}
+	}
+
+	stored, err := repo.GetByID(ctx, older.ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if !stored.IsPinned() || !stored.IsFeatured() {
+		t.Errorf("expected the post pinned and featured, got %v and %v", stored.PinnedAt, stored.FeaturedAt)
+	}
+
+	// The pinned post comes first though it is not the latest
+	published, err := repo.ListPublished(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list published posts: %v", err)
+	}
+	if len(published) < 2 || published[0].ID != older.ID || published[1].ID != newer.ID {
+		t.Errorf("expected post %d pinned before post %d", older.ID, newer.ID)
+	}
+	all, err := repo.List(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(all) == 0 || all[0].ID != older.ID {
+		t.Errorf("expected post %d listed first", older.ID)
+	}
+
+	featured, err := repo.ListFeatured(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list featured posts: %v", err)
+	}
+	if len(featured) != 2 || featured[0].ID != newer.ID || featured[1].ID != older.ID {
+		t.Errorf("expected posts %d and %d featured, latest first, got %v", newer.ID, older.ID, featured)
+	}
+
+	// Unpublishing keeps the mark but leaves the post out of the featured set
+	newer.Status = post.StatusDraft
+	if err := repo.Update(ctx, newer); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	featured, err = repo.ListFeatured(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list featured posts: %v", err)
+	}
+	if len(featured) != 1 || featured[0].ID != older.ID {
+		t.Errorf("expected only post %d featured, got %v", older.ID, featured)
+	}
+}
+
+func TestPostRepository_Integration_Visibility(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	changes := repository.NewChangeRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "visibilitytest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	public, _ := post.NewPost("Public Post", "Test content with sufficient length.", author.ID)
+	unlisted, _ := post.NewPost("Unlisted Post", "Test content with sufficient length.", author.ID)
+	private, _ := post.NewPost("Private Post", "Test content with sufficient length.", author.ID)
+	for _, p := range []*post.Post{public, unlisted, private} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+	}
+	unlisted.Visibility = post.VisibilityUnlisted
+	private.Visibility = post.VisibilityPrivate
+	for _, p := range []*post.Post{unlisted, private} {
+		if err := repo.SetVisibility(ctx, p); err != nil {
+			t.Fatalf("failed to set post visibility: %v", err)
+		}
+	}
+
+	// Lookups find every post
+	stored, err := repo.GetBySlug(ctx, unlisted.Slug)
+	if err != nil {
+		t.Fatalf("failed to get post by slug: %v", err)
+	}
+	if stored.Visibility != post.VisibilityUnlisted {
+		t.Errorf("expected visibility %q, got %q", post.VisibilityUnlisted, stored.Visibility)
+	}
+	if _, err := repo.GetByID(ctx, private.ID); err != nil {
+		t.Errorf("expected the private post to be found by ID, got %v", err)
+	}
+
+	// Visible lookups find unlisted posts by link, and private posts for
+	// their author and admins only
+	if _, err := repo.GetVisibleBySlug(ctx, unlisted.Slug, post.ViewFilter{}); err != nil {
+		t.Errorf("expected the unlisted post to be found by slug, got %v", err)
+	}
+	if _, err := repo.GetVisibleByID(ctx, private.ID, post.ViewFilter{AuthorID: author.ID + 1000}); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected the private post hidden from other users, got %v", err)
+	}
+	if _, err := repo.GetVisibleBySlug(ctx, private.Slug, post.ViewFilter{}); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected the private post hidden from anonymous readers, got %v", err)
+	}
+	if _, err := repo.GetVisibleBySlug(ctx, private.Slug, post.ViewFilter{AuthorID: author.ID}); err != nil {
+		t.Errorf("expected the author to find the private post, got %v", err)
+	}
+	if _, err := repo.GetVisibleByID(ctx, private.ID, post.ViewFilter{All: true}); err != nil {
+		t.Errorf("expected admins to find the private post, got %v", err)
+	}
+
+	ids := func(posts []*post.Post) map[int]bool {
+		found := make(map[int]bool, len(posts))
+		for _, p := range posts {
+			found[p.ID] = true
+		}
+		return found
+	}
+
+	published, err := repo.ListPublished(ctx, 20, 0)
+	if err != nil {
+		t.Fatalf("failed to list published posts: %v", err)
+	}
+	if listed := ids(published); !listed[public.ID] || listed[unlisted.ID] || listed[private.ID] {
+		t.Errorf("expected only post %d of the author listed, got %v", public.ID, listed)
+	}
+	byAuthor, err := repo.ListPublishedBy(ctx, post.PublishedFilter{AuthorID: author.ID}, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list published posts by author: %v", err)
+	}
+	if len(byAuthor) != 1 {
+		t.Errorf("expected 1 post of the author listed, got %d", len(byAuthor))
+	}
+
+	// The author and admins still list every post
+	own, err := repo.ListVisibleTo(ctx, author.ID, 20, 0)
+	if err != nil {
+		t.Fatalf("failed to list visible posts: %v", err)
+	}
+	if listed := ids(own); !listed[public.ID] || !listed[unlisted.ID] || !listed[private.ID] {
+		t.Errorf("expected the author to list every post, got %v", listed)
+	}
+	options := post.ListOptions{AuthorID: author.ID}.WithDefaults()
+	others, err := repo.ListMatching(ctx, post.ListFilter{AuthorID: author.ID + 1000}, options, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list matching posts: %v", err)
+	}
+	if len(others) != 1 {
+		t.Errorf("expected other users to list 1 post, got %d", len(others))
+	}
+	all, err := repo.ListMatching(ctx, post.ListFilter{All: true}, options, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list matching posts: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected admins to list 3 posts, got %d", len(all))
+	}
+
+	// Synced clients drop the posts that stopped being public
+	tombstones, err := changes.ListTombstones(ctx, change.Start().Deletions, 10)
+	if err != nil {
+		t.Fatalf("failed to list tombstones: %v", err)
+	}
+	if len(tombstones) != 2 {
+		t.Errorf("expected 2 tombstones, got %d", len(tombstones))
+	}
+	synced, err := changes.ListPosts(ctx, change.Start().Posts, 20)
+	if err != nil {
+		t.Fatalf("failed to list changed posts: %v", err)
+	}
+	if listed := ids(synced); !listed[public.ID] || listed[unlisted.ID] || listed[private.ID] {
+		t.Errorf("expected only post %d of the author synced, got %v", public.ID, listed)
+	}
+}
+
+func TestPostRepository_Integration_Translations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	users := repository.NewUserRepository(db.DB)
+	repo := repository.NewPostRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "translationtest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	p, _ := post.NewPost("Hello World", "Test content with sufficient length.", author.ID)
+	if err := repo.Create(ctx, p); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	if _, err := repo.GetTranslation(ctx, p.ID, "fr"); err != post.ErrTranslationNotFound {
+		t.Errorf("expected ErrTranslationNotFound, got %v", err)
+	}
+
+	for _, lang := range []string{"fr", "de"} {
+		tr, err := post.NewTranslation(p, lang, "Title "+lang, "Translated content of sufficient length.", author.ID)
+		if err != nil {
+			t.Fatalf("failed to create translation: %v", err)
+		}
+		if err := repo.SaveTranslation(ctx, tr); err != nil {
+			t.Fatalf("failed to save translation: %v", err)
+		}
+	}
+
+	// Saving to a language again replaces its translation
+	replacement, _ := post.NewTranslation(p, "fr", "Bonjour", "Contenu de test assez long.", author.ID)
+	if err := repo.SaveTranslation(ctx, replacement); err != nil {
+		t.Fatalf("failed to replace translation: %v", err)
+	}
+	stored, err := repo.GetTranslation(ctx, p.ID, "fr")
+	if err != nil {
+		t.Fatalf("failed to get translation: %v", err)
+	}
+	if stored.Title != "Bonjour" || stored.Content != "Contenu de test assez long." || stored.TranslatedBy != author.ID {
+		t.Errorf("expected the replaced translation, got %+v", stored)
+	}
+
+	// Posts come with their language and the languages of their translations
+	found, err := repo.GetByID(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if found.Language != post.DefaultLanguage || !slices.Equal(found.Translations, []string{"de", "fr"}) {
+		t.Errorf("expected language en with translations [de fr], got %q %v", found.Language, found.Translations)
+	}
+	posts, err := repo.GetByAuthorID(ctx, author.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(posts) != 1 || !slices.Equal(posts[0].Translations, []string{"de", "fr"}) {
+		t.Errorf("expected listed posts with their translations, got %v", posts)
+	}
+
+	// Translations go with their post
+	if err := repo.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("failed to delete post: %v", err)
+	}
+	if _, err := repo.GetTranslation(ctx, p.ID, "de"); err != post.ErrTranslationNotFound {
+		t.Errorf("expected the translations deleted with the post, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/ratelimit"
+	"blog-platform/internal/infrastructure/redis"
+)
+
+func setupTestRedis(t *testing.T) *redis.Client {
+	cfg := config.Load()
+	client := redis.NewClient(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		t.Skipf("redis not available at %s: %v", cfg.Redis.Addr, err)
+	}
+	return client
+}
+
+// testRateLimitKey returns a key not used by earlier runs
+func testRateLimitKey(t *testing.T) string {
+	return fmt.Sprintf("test:%s:%d", t.Name(), time.Now().UnixNano())
+}
+
+func TestRedisRateLimitStore_Integration_SlidingWindow(t *testing.T) {
+	store := ratelimit.NewRedisStore(setupTestRedis(t))
+	ctx := context.Background()
+	key := testRateLimitKey(t)
+	// 3 requests in any 300ms
+	limit := ratelimit.Limit{RequestsPerSecond: 10, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Allow(ctx, key, limit)
+		if err != nil || !result.Allowed {
+			t.Fatalf("expected request to be allowed, got %+v (%v)", result, err)
+		}
+		if result.Remaining != i {
+			t.Errorf("expected %d remaining, got %d", i, result.Remaining)
+		}
+	}
+
+	result, err := store.Allow(ctx, key, limit)
+	if err != nil || result.Allowed {
+		t.Fatalf("expected request to be denied, got %+v (%v)", result, err)
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > limit.Window() {
+		t.Errorf("expected to retry within the window, got %v", result.RetryAfter)
+	}
+
+	// Denied requests do not extend the window
+	time.Sleep(result.RetryAfter + 10*time.Millisecond)
+	if result, err := store.Allow(ctx, key, limit); err != nil || !result.Allowed {
+		t.Errorf("expected request to be allowed once the oldest left the window, got %+v (%v)", result, err)
+	}
+}
+
+func TestRedisRateLimitStore_Integration_SharedBetweenServers(t *testing.T) {
+	// Each store stands in for a server replica with its own connections
+	stores := []*ratelimit.RedisStore{
+		ratelimit.NewRedisStore(setupTestRedis(t)),
+		ratelimit.NewRedisStore(setupTestRedis(t)),
+	}
+	ctx := context.Background()
+	key := testRateLimitKey(t)
+	limit := ratelimit.Limit{RequestsPerSecond: 1, Burst: 10}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(store *ratelimit.RedisStore) {
+			defer wg.Done()
+			result, err := store.Allow(ctx, key, limit)
+			if err != nil {
+				t.Errorf("allow failed: %v", err)
+				return
+			}
+			if result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}(stores[i%2])
+	}
+	wg.Wait()
+
+	if allowed != limit.Burst {
+		t.Errorf("expected %d requests to be allowed across servers, got %d", limit.Burst, allowed)
+	}
+
+	// Other keys have their own budget
+	if result, err := stores[0].Allow(ctx, testRateLimitKey(t), limit); err != nil || !result.Allowed {
+		t.Errorf("expected other key to be allowed, got %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestSeriesRepository_Integration_Posts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+	defer db.Exec("DELETE FROM series")
+
+	users := repository.NewUserRepository(db.DB)
+	posts := repository.NewPostRepository(db.DB)
+	repo := repository.NewSeriesRepository(db.DB)
+	ctx := context.Background()
+
+	author, _ := user.NewUser("Test Author", "seriestest@example.com", "password123")
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("failed to create author: %v", err)
+	}
+	var parts []*post.Post
+	for _, title := range []string{"First Part", "Second Part", "Third Part"} {
+		p, _ := post.NewPost(title, "Test content with sufficient length.", author.ID)
+		if err := posts.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		parts = append(parts, p)
+	}
+
+	sr, _ := series.NewSeries(author.ID, series.Input{Title: "Building a Blog", Description: "Step by step."})
+	if err := repo.Create(ctx, sr); err != nil {
+		t.Fatalf("failed to create series: %v", err)
+	}
+	sr.Update(series.Input{Title: "Building a Blog in Go"})
+	if err := repo.Update(ctx, sr); err != nil {
+		t.Fatalf("failed to update series: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, sr.ID)
+	if err != nil {
+		t.Fatalf("failed to get series: %v", err)
+	}
+	if stored.Title != "Building a Blog in Go" || stored.Description != "" || stored.AuthorID != author.ID {
+		t.Errorf("expected the updated series, got %+v", stored)
+	}
+	list, err := repo.ListByAuthor(ctx, author.ID)
+	if err != nil || len(list) != 1 {
+		t.Errorf("expected 1 series of the author, got %d (%v)", len(list), err)
+	}
+
+	order := []int{parts[2].ID, parts[0].ID, parts[1].ID}
+	if err := repo.SetPosts(ctx, sr.ID, order); err != nil {
+		t.Fatalf("failed to set posts: %v", err)
+	}
+	listed, err := repo.ListPosts(ctx, sr.ID, post.ViewFilter{})
+	if err != nil {
+		t.Fatalf("failed to list series posts: %v", err)
+	}
+	if len(listed) != 3 {
+		t.Fatalf("expected 3 posts, got %d", len(listed))
+	}
+	for i, p := range listed {
+		if p.ID != order[i] || p.SeriesPosition != i+1 || p.SeriesID == nil || *p.SeriesID != sr.ID {
+			t.Errorf("expected post %d at position %d, got post %d at %d", order[i], i+1, p.ID, p.SeriesPosition)
+		}
+	}
+
+	// Private posts are only listed for their author and admins
+	parts[0].Visibility = post.VisibilityPrivate
+	if err := posts.SetVisibility(ctx, parts[0]); err != nil {
+		t.Fatalf("failed to set visibility: %v", err)
+	}
+	for _, tc := range []struct {
+		filter post.ViewFilter
+		want   int
+	}{{post.ViewFilter{}, 2}, {post.ViewFilter{AuthorID: author.ID + 1}, 2}, {post.ViewFilter{AuthorID: author.ID}, 3}, {post.ViewFilter{All: true}, 3}} {
+		if listed, err := repo.ListPosts(ctx, sr.ID, tc.filter); err != nil || len(listed) != tc.want {
+			t.Errorf("expected %d posts through %+v, got %d (%v)", tc.want, tc.filter, len(listed), err)
+		}
+	}
+
+	// Posts left out leave the series
+	if err := repo.SetPosts(ctx, sr.ID, []int{parts[1].ID}); err != nil {
+		t.Fatalf("failed to set posts: %v", err)
+	}
+	left, err := posts.GetByID(ctx, parts[2].ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if left.SeriesID != nil || left.SeriesPosition != 0 {
+		t.Errorf("expected post %d out of the series, got %v at %d", left.ID, left.SeriesID, left.SeriesPosition)
+	}
+
+	// Deleting the series keeps its posts outside any series
+	if err := repo.Delete(ctx, sr.ID); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, sr.ID); err != series.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+	kept, err := posts.GetByID(ctx, parts[1].ID)
+	if err != nil {
+		t.Fatalf("expected the post kept, got %v", err)
+	}
+	if kept.SeriesID != nil {
+		t.Errorf("expected post %d out of the deleted series, got %v", kept.ID, kept.SeriesID)
+	}
+	if err := repo.Delete(ctx, sr.ID); err != series.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestTxManager_Integration_CommitsAndRollsBack(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	repo := repository.NewUserRepository(db.DB)
+	txManager := repository.NewTxManager(db.DB)
+	ctx := context.Background()
+
+	committed, err := user.NewUser("Committed User", "committed-test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return repo.Create(ctx, committed)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(ctx, committed.ID); err != nil {
+		t.Errorf("expected committed user to be stored, got %v", err)
+	}
+
+	rolledBack, err := user.NewUser("Rolled Back User", "rolledback-test@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	errAbort := errors.New("abort")
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, rolledBack); err != nil {
+			return err
+		}
+		// Nested units of work join the outer transaction
+		return txManager.WithinTx(ctx, func(ctx context.Context) error {
+			rolledBack.Name = "Renamed User"
+			if err := repo.Update(ctx, rolledBack); err != nil {
+				return err
+			}
+			return errAbort
+		})
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected the error of the unit of work, got %v", err)
+	}
+	if _, err := repo.GetByID(ctx, rolledBack.ID); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected rolled back user to be absent, got %v", err)
+	}
+
+	// Repository methods running their own transaction join the unit of work
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.Delete(ctx, committed.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected the error of the unit of work, got %v", err)
+	}
+	if _, err := repo.GetByID(ctx, committed.ID); err != nil {
+		t.Errorf("expected deletion to be rolled back, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package integration
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -11,12 +13,23 @@ import (
 	"blog-platform/internal/infrastructure/repository"
 )
 
+// setupTestDB connects to the database DB_DRIVER and DB_DSN name, or
+// without DB_DRIVER to a fresh SQLite database migrated for the test
 func setupTestDB(t *testing.T) *database.Database {
 	cfg := config.Load()
+	if os.Getenv("DB_DRIVER") == "" {
+		cfg.Database.Driver = "sqlite"
+		cfg.Database.DSN = "file:" + filepath.Join(t.TempDir(), "blog.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+	}
 	db, err := database.NewDatabase(cfg)
 	if err != nil {
 		t.Fatalf("failed to connect to test database: %v", err)
 	}
+	if cfg.Database.Driver == "sqlite" {
+		if _, err := db.Migrate(context.Background()); err != nil {
+			t.Fatalf("failed to migrate test database: %v", err)
+		}
+	}
 	return db
 }
 
@@ -95,8 +108,10 @@ func TestUserRepository_Integration_GetByEmail(t *testing.T) {
 	repo := repository.NewUserRepository(db.DB)
 	ctx := context.Background()
 
-	// Create and store a user
+	// Create and store a user; lookups go by the canonical address the
+	// user service stores next to the email
 	u, _ := user.NewUser("Test User", "getbyemail@example.com", "password123")
+	u.NormalizedEmail = "getbyemail@example.com"
 	repo.Create(ctx, u)
 
 	// Test getting existing user by email
@@ -120,6 +135,49 @@ func TestUserRepository_Integration_GetByEmail(t *testing.T) {
 	}
 }
 
+func TestUserRepository_Integration_GetConflictedByEmail(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+
+	repo := repository.NewUserRepository(db.DB)
+	ctx := context.Background()
+
+	owner, _ := user.NewUser("Test User", "conflict-test@example.com", "password123")
+	owner.NormalizedEmail = "conflict-test@example.com"
+	if err := repo.Create(ctx, owner); err != nil {
+		t.Fatalf("failed to create owner: %v", err)
+	}
+
+	// Flagged accounts lose their canonical address, as the email
+	// normalization migration leaves them
+	flagged, _ := user.NewUser("Test User", "Conflict-Test@example.com", "password456")
+	if err := repo.Create(ctx, flagged); err != nil {
+		t.Fatalf("failed to create flagged user: %v", err)
+	}
+	flagged.EmailConflict = true
+	if err := repo.Update(ctx, flagged); err != nil {
+		t.Fatalf("failed to flag user: %v", err)
+	}
+
+	retrieved, err := repo.GetConflictedByEmail(ctx, "Conflict-Test@example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if retrieved.ID != flagged.ID || !retrieved.EmailConflict {
+		t.Errorf("expected flagged user %d, got %+v", flagged.ID, retrieved)
+	}
+
+	// Accounts that are not flagged are only found by their canonical address
+	if _, err := repo.GetConflictedByEmail(ctx, "conflict-test@example.com"); err != user.ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+	retrieved, err = repo.GetByEmail(ctx, "conflict-test@example.com")
+	if err != nil || retrieved.ID != owner.ID {
+		t.Errorf("expected owner %d, got %+v (%v)", owner.ID, retrieved, err)
+	}
+}
+
 func TestUserRepository_Integration_Update(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/domain/webhook"
+	infraauth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/repository"
+)
+
+func TestWebhookRepository_Integration_WebhooksAndDeliveries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	defer cleanupUsers(t, db)
+	defer db.Exec("DELETE FROM webhooks")
+
+	cipher, err := infraauth.NewSecretCipher("test-encryption-key")
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	repo := repository.NewWebhookRepository(db.DB, cipher)
+	ctx := context.Background()
+
+	admin, _ := user.NewUser("Test Admin", "webhooktest@example.com", "password123")
+	if err := repository.NewUserRepository(db.DB).Create(ctx, admin); err != nil {
+		t.Fatalf("failed to create admin: %v", err)
+	}
+
+	w, _ := webhook.NewWebhook(admin.ID, "whsec_test", webhook.Input{
+		URL:    "https://hooks.example.com/blog",
+		Events: []string{webhook.EventPostPublished, webhook.EventCommentCreated},
+		Active: true,
+	})
+	if err := repo.Create(ctx, w); err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+
+	var stored string
+	if err := db.Get(&stored, db.Rebind("SELECT secret_encrypted FROM webhooks WHERE id = ?"), w.ID); err != nil {
+		t.Fatalf("failed to read stored secret: %v", err)
+	}
+	if stored == "" || stored == w.Secret {
+		t.Errorf("expected the secret to be stored encrypted, got %q", stored)
+	}
+
+	got, err := repo.GetByID(ctx, w.ID)
+	if err != nil {
+		t.Fatalf("failed to get webhook: %v", err)
+	}
+	if got.Secret != "whsec_test" || len(got.Events) != 2 || got.Events[0] != webhook.EventCommentCreated || !got.Active || got.CreatedBy != admin.ID {
+		t.Errorf("expected the webhook as created, got %+v", got)
+	}
+
+	got.Update(webhook.Input{URL: got.URL, Events: got.Events, Active: false})
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update webhook: %v", err)
+	}
+	if active, _ := repo.ListActive(ctx); len(active) != 0 {
+		t.Errorf("expected no active webhooks, got %d", len(active))
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := repo.CreateDelivery(ctx, webhook.NewDelivery(w.ID, webhook.EventPostPublished, `{"event":"post.published"}`)); err != nil {
+			t.Fatalf("failed to create delivery: %v", err)
+		}
+	}
+	deliveries, total, err := repo.ListDeliveries(ctx, w.ID, 2, 0)
+	if err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	if total != 3 || len(deliveries) != 2 || deliveries[0].ID < deliveries[1].ID {
+		t.Fatalf("expected the newest 2 of 3 deliveries, got %d of %d", len(deliveries), total)
+	}
+
+	d := deliveries[0]
+	d.Attempts, d.ResponseStatus, d.Error = 1, 502, "unexpected status 502"
+	if err := repo.UpdateDelivery(ctx, d); err != nil {
+		t.Fatalf("failed to update delivery: %v", err)
+	}
+	updated, err := repo.GetDelivery(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("failed to get delivery: %v", err)
+	}
+	if updated.Status != webhook.DeliveryPending || updated.Attempts != 1 || updated.ResponseStatus != 502 || updated.DeliveredAt != nil {
+		t.Errorf("expected the attempt recorded, got %+v", updated)
+	}
+
+	// Deleting a webhook deletes its delivery log
+	if err := repo.Delete(ctx, w.ID); err != nil {
+		t.Fatalf("failed to delete webhook: %v", err)
+	}
+	if _, err := repo.GetDelivery(ctx, d.ID); err != webhook.ErrDeliveryNotFound {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+	if err := repo.Delete(ctx, w.ID); err != webhook.ErrWebhookNotFound {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
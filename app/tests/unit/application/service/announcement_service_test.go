@@ -0,0 +1,276 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/announcement"
+	"blog-platform/internal/domain/user"
+)
+
+// MockAnnouncementRepository implements announcement.Repository for testing.
+// Recipients are the users added with addUser.
+type MockAnnouncementRepository struct {
+	announcements map[int]*announcement.Announcement
+	users         []*user.User
+	unsubscribed  map[int]bool
+	deliveries    map[int]map[int]*announcement.Delivery
+	nextID        int
+}
+
+func NewMockAnnouncementRepository() *MockAnnouncementRepository {
+	return &MockAnnouncementRepository{
+		announcements: make(map[int]*announcement.Announcement),
+		unsubscribed:  make(map[int]bool),
+		deliveries:    make(map[int]map[int]*announcement.Delivery),
+		nextID:        1,
+	}
+}
+
+func (m *MockAnnouncementRepository) addUser(name string, role user.Role) *user.User {
+	u := &user.User{ID: len(m.users) + 1, Name: name, Email: strings.ToLower(name) + "@example.com", Role: role}
+	m.users = append(m.users, u)
+	return u
+}
+
+func (m *MockAnnouncementRepository) matches(u *user.User, segment announcement.Segment) bool {
+	if len(segment.Roles) == 0 {
+		return true
+	}
+	for _, role := range segment.Roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockAnnouncementRepository) Create(ctx context.Context, a *announcement.Announcement) error {
+	a.ID = m.nextID
+	m.nextID++
+	stored := *a
+	m.announcements[a.ID] = &stored
+	return nil
+}
+
+func (m *MockAnnouncementRepository) GetByID(ctx context.Context, id int) (*announcement.Announcement, error) {
+	a, ok := m.announcements[id]
+	if !ok {
+		return nil, announcement.ErrAnnouncementNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *MockAnnouncementRepository) Update(ctx context.Context, a *announcement.Announcement) error {
+	stored := *a
+	m.announcements[a.ID] = &stored
+	return nil
+}
+
+func (m *MockAnnouncementRepository) CountRecipients(ctx context.Context, segment announcement.Segment) (int, error) {
+	count := 0
+	for _, u := range m.users {
+		if m.matches(u, segment) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockAnnouncementRepository) PendingRecipients(ctx context.Context, a *announcement.Announcement, limit int) ([]*announcement.Recipient, error) {
+	var recipients []*announcement.Recipient
+	for _, u := range m.users {
+		if _, done := m.deliveries[a.ID][u.ID]; done || !m.matches(u, a.Segment) {
+			continue
+		}
+		recipients = append(recipients, &announcement.Recipient{UserID: u.ID, Name: u.Name, Email: u.Email, Unsubscribed: m.unsubscribed[u.ID]})
+		if len(recipients) == limit {
+			break
+		}
+	}
+	return recipients, nil
+}
+
+func (m *MockAnnouncementRepository) RecordDelivery(ctx context.Context, d *announcement.Delivery) error {
+	if m.deliveries[d.AnnouncementID] == nil {
+		m.deliveries[d.AnnouncementID] = make(map[int]*announcement.Delivery)
+	}
+	m.deliveries[d.AnnouncementID][d.UserID] = d
+	return nil
+}
+
+func (m *MockAnnouncementRepository) ListDeliveries(ctx context.Context, announcementID int, status string, limit int) ([]*announcement.Delivery, error) {
+	var result []*announcement.Delivery
+	for _, d := range m.deliveries[announcementID] {
+		if d.Status == status {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockAnnouncementRepository) Unsubscribe(ctx context.Context, userID int) error {
+	m.unsubscribed[userID] = true
+	return nil
+}
+
+// MockUnsubscribeTokens implements announcement.UnsubscribeTokens for testing
+type MockUnsubscribeTokens struct{}
+
+func (m *MockUnsubscribeTokens) Issue(userID int) string {
+	return fmt.Sprintf("token-%d", userID)
+}
+
+func (m *MockUnsubscribeTokens) Verify(token string) (int, error) {
+	var userID int
+	if _, err := fmt.Sscanf(token, "token-%d", &userID); err != nil {
+		return 0, announcement.ErrInvalidUnsubscribeToken
+	}
+	return userID, nil
+}
+
+// bouncingMailer fails for the given addresses and records the other emails
+type bouncingMailer struct {
+	MockMailer
+	bounce map[string]bool
+}
+
+func (m *bouncingMailer) Send(ctx context.Context, msg service.EmailMessage) error {
+	if m.bounce[msg.To] {
+		return errors.New("550 mailbox unavailable")
+	}
+	return m.MockMailer.Send(ctx, msg)
+}
+
+func newTestAnnouncementService(repo *MockAnnouncementRepository, mailer service.Mailer, jobs *MockJobQueue) *service.AnnouncementService {
+	return service.NewAnnouncementService(repo, &MockUnsubscribeTokens{}, mailer, jobs, &MockAuditLogger{}, service.AnnouncementSettings{
+		BaseURL:   "https://blog.example.com",
+		SiteName:  "Test Blog",
+		BatchSize: 2,
+	}, NewMockLogger())
+}
+
+// runQueuedBatches runs the queued announcement batches until none are left
+func runQueuedBatches(t *testing.T, svc *service.AnnouncementService, jobs *MockJobQueue) {
+	t.Helper()
+	for i := 0; i < len(jobs.payloads); i++ {
+		require.Equal(t, service.JobAnnouncementBatch, jobs.kinds[i])
+		payload, err := json.Marshal(jobs.payloads[i])
+		require.NoError(t, err)
+		require.NoError(t, svc.SendBatch(context.Background(), payload))
+	}
+}
+
+func TestAnnouncementService_Implementation(t *testing.T) {
+	var _ announcement.Service = newTestAnnouncementService(NewMockAnnouncementRepository(), &MockMailer{}, &MockJobQueue{})
+}
+
+func TestAnnouncementService_SendsInBatches(t *testing.T) {
+	repo := NewMockAnnouncementRepository()
+	for _, name := range []string{"Ada", "Bob", "Cy", "Dee", "Eve"} {
+		repo.addUser(name, user.RoleAuthor)
+	}
+	repo.unsubscribed[2] = true
+	mailer := &bouncingMailer{bounce: map[string]bool{"dee@example.com": true}}
+	jobs := &MockJobQueue{}
+	svc := newTestAnnouncementService(repo, mailer, jobs)
+	ctx := context.Background()
+
+	a, err := svc.Create(ctx, 99, "Hello", "News from the blog", announcement.Segment{})
+	require.NoError(t, err)
+	assert.Equal(t, announcement.StatusQueued, a.Status)
+	assert.Equal(t, 5, a.Recipients)
+
+	runQueuedBatches(t, svc, jobs)
+
+	// Five recipients in batches of two: three batches, the last one partial
+	assert.Len(t, jobs.kinds, 3)
+	require.Len(t, mailer.sent, 3)
+	assert.Equal(t, "ada@example.com", mailer.sent[0].To)
+	assert.Equal(t, "Hello", mailer.sent[0].Subject)
+	assert.Contains(t, mailer.sent[0].Body, "News from the blog")
+	assert.Equal(t, "https://blog.example.com/api/v1/announcements/unsubscribe?token=token-1", mailer.sent[0].UnsubscribeURL)
+	assert.Contains(t, mailer.sent[0].Body, mailer.sent[0].UnsubscribeURL)
+
+	report, err := svc.GetReport(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, announcement.StatusCompleted, report.Announcement.Status)
+	assert.NotNil(t, report.Announcement.CompletedAt)
+	assert.Equal(t, 3, report.Announcement.Sent)
+	assert.Equal(t, 1, report.Announcement.Failed)
+	assert.Equal(t, 1, report.Announcement.Skipped)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "dee@example.com", report.Failures[0].Email)
+	assert.Equal(t, "550 mailbox unavailable", report.Failures[0].Error)
+}
+
+func TestAnnouncementService_Segment(t *testing.T) {
+	repo := NewMockAnnouncementRepository()
+	repo.addUser("Ada", user.RoleAdmin)
+	repo.addUser("Bob", user.RoleReader)
+	repo.addUser("Cy", user.RoleAuthor)
+	mailer := &MockMailer{}
+	jobs := &MockJobQueue{}
+	svc := newTestAnnouncementService(repo, mailer, jobs)
+
+	a, err := svc.Create(context.Background(), 1, "Readers only", "Hello readers", announcement.Segment{Roles: []user.Role{user.RoleReader}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.Recipients)
+
+	runQueuedBatches(t, svc, jobs)
+
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "bob@example.com", mailer.sent[0].To)
+}
+
+func TestAnnouncementService_Create_Validation(t *testing.T) {
+	jobs := &MockJobQueue{}
+	svc := newTestAnnouncementService(NewMockAnnouncementRepository(), &MockMailer{}, jobs)
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, 1, "  ", "Body", announcement.Segment{})
+	assert.ErrorIs(t, err, announcement.ErrInvalidSubject)
+
+	_, err = svc.Create(ctx, 1, "Subject\r\nBcc: everyone@example.com", "Body", announcement.Segment{})
+	assert.ErrorIs(t, err, announcement.ErrInvalidSubject)
+
+	_, err = svc.Create(ctx, 1, "Subject", "", announcement.Segment{})
+	assert.ErrorIs(t, err, announcement.ErrInvalidBody)
+
+	_, err = svc.Create(ctx, 1, "Subject", "Body", announcement.Segment{Roles: []user.Role{"owner"}})
+	assert.ErrorIs(t, err, announcement.ErrInvalidSegment)
+
+	assert.Empty(t, jobs.kinds, "invalid announcements are not queued")
+}
+
+func TestAnnouncementService_Unsubscribe(t *testing.T) {
+	repo := NewMockAnnouncementRepository()
+	svc := newTestAnnouncementService(repo, &MockMailer{}, &MockJobQueue{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.Unsubscribe(ctx, "token-7"))
+	assert.True(t, repo.unsubscribed[7])
+
+	assert.ErrorIs(t, svc.Unsubscribe(ctx, "forged"), announcement.ErrInvalidUnsubscribeToken)
+}
+
+func TestAnnouncementService_GetReport_NotFound(t *testing.T) {
+	svc := newTestAnnouncementService(NewMockAnnouncementRepository(), &MockMailer{}, &MockJobQueue{})
+
+	_, err := svc.GetReport(context.Background(), 42)
+	assert.ErrorIs(t, err, announcement.ErrAnnouncementNotFound)
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/audit"
+	"blog-platform/internal/domain/user"
+)
+
+// MockAuditRepository implements audit.Repository for testing
+type MockAuditRepository struct {
+	entries []*audit.Entry
+	filter  audit.Filter
+	limit   int
+	offset  int
+	err     error
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *audit.Entry) error {
+	if m.err != nil {
+		return m.err
+	}
+	entry.ID = len(m.entries) + 1
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter audit.Filter, limit, offset int) ([]*audit.Entry, error) {
+	m.filter, m.limit, m.offset = filter, limit, offset
+	return m.entries, m.err
+}
+
+func TestAuditService_Implementation(t *testing.T) {
+	var _ service.AuditLogger = service.NewAuditService(&MockAuditRepository{}, service.AuditSettings{}, NewMockLogger())
+	var _ audit.Service = service.NewAuditService(&MockAuditRepository{}, service.AuditSettings{}, NewMockLogger())
+}
+
+func TestAuditService_Record(t *testing.T) {
+	repo := &MockAuditRepository{}
+	auditService := service.NewAuditService(repo, service.AuditSettings{}, NewMockLogger())
+	ctx := service.WithRequestID(user.WithClientIP(context.Background(), "203.0.113.7"), "req-1")
+
+	auditService.Record(ctx, service.AuditEvent{
+		Action:   service.AuditActionPostDeleted,
+		UserID:   1,
+		Metadata: map[string]any{"post_id": 7},
+	})
+
+	if len(repo.entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(repo.entries))
+	}
+	entry := repo.entries[0]
+	if entry.Action != service.AuditActionPostDeleted || entry.UserID != 1 || entry.Metadata["post_id"] != 7 {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if entry.IPAddress != "203.0.113.7" {
+		t.Errorf("expected client IP to be recorded, got %q", entry.IPAddress)
+	}
+	if entry.RequestID != "req-1" {
+		t.Errorf("expected request ID to be recorded, got %q", entry.RequestID)
+	}
+	if entry.CreatedAt.IsZero() {
+		t.Error("expected creation time to be set")
+	}
+
+	// Recording is best-effort
+	repo.err = errors.New("database unavailable")
+	auditService.Record(context.Background(), service.AuditEvent{Action: service.AuditActionUserLogin, UserID: 1})
+}
+
+func TestAuditService_ListEntries(t *testing.T) {
+	repo := &MockAuditRepository{}
+	auditService := service.NewAuditService(repo, service.AuditSettings{Pagination: service.PageLimits{Default: 20, Max: 50}}, NewMockLogger())
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	filter := audit.Filter{UserID: 1, Action: service.AuditActionUserLogin, From: &from, To: &to}
+	if _, err := auditService.ListEntries(ctx, filter, 500, -1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.filter.UserID != 1 || repo.filter.Action != service.AuditActionUserLogin {
+		t.Errorf("expected filter to be passed on, got %+v", repo.filter)
+	}
+	if repo.limit != 50 || repo.offset != 0 {
+		t.Errorf("expected page to be normalized to 50/0, got %d/%d", repo.limit, repo.offset)
+	}
+
+	// An inverted time range is rejected
+	inverted := audit.Filter{From: &to, To: &from}
+	if _, err := auditService.ListEntries(ctx, inverted, 10, 0); err != audit.ErrInvalidTimeRange {
+		t.Errorf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -56,6 +58,13 @@ func (m *MockUserService) Login(ctx context.Context, email, password string) (*u
 	return u, nil
 }
 
+func (m *MockUserService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, error) {
+	if u, exists := m.users[external.Email]; exists {
+		return u, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
 func (m *MockUserService) GetByID(ctx context.Context, id int) (*user.User, error) {
 	for _, u := range m.users {
 		if u.ID == id {
@@ -83,6 +92,76 @@ func (m *MockUserService) UpdateProfile(ctx context.Context, id int, name, email
 	return nil, user.ErrUserNotFound
 }
 
+func (m *MockUserService) RequestEmailChange(ctx context.Context, id int, newEmail string) error {
+	return nil
+}
+
+func (m *MockUserService) SyncProfile(ctx context.Context, id int, name, email string) (*user.User, error) {
+	return m.UpdateProfile(ctx, id, name, email)
+}
+
+func (m *MockUserService) Deactivate(ctx context.Context, id int) error {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Deactivate()
+	return nil
+}
+
+func (m *MockUserService) Reactivate(ctx context.Context, id int) error {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Reactivate()
+	return nil
+}
+
+func (m *MockUserService) ChangeRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u.Role = role
+	return u, nil
+}
+
+// RequestMagicLink is not used; tokens are "magic_" followed by the email
+func (m *MockUserService) RequestMagicLink(ctx context.Context, email string, device user.Device) error {
+	return nil
+}
+
+func (m *MockUserService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, error) {
+	if u, exists := m.users[strings.TrimPrefix(token, "magic_")]; exists && strings.HasPrefix(token, "magic_") {
+		return u, nil
+	}
+	return nil, user.ErrInvalidMagicLink
+}
+
+func (m *MockUserService) RegisterPasswordless(ctx context.Context, name, email, method string) (*user.User, error) {
+	return m.Register(ctx, name, email, "random-password")
+}
+
+func (m *MockUserService) CompleteLogin(ctx context.Context, id int, method string) (*user.User, error) {
+	u, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsPasswordResetRequired() {
+		return nil, user.ErrPasswordResetRequired
+	}
+	return u, nil
+}
+
+func (m *MockUserService) ConfirmEmailChange(ctx context.Context, token string) (*user.User, error) {
+	return nil, user.ErrEmailChangeNotFound
+}
+
+func (m *MockUserService) ScheduleDeletion(ctx context.Context, id int) (*user.User, error) {
+	return nil, user.ErrUserNotFound
+}
+
 func (m *MockUserService) UpdatePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
 	for _, u := range m.users {
 		if u.ID == id {
@@ -143,14 +222,17 @@ func (m *MockTokenService) SetError(shouldError bool) {
 	m.shouldError = shouldError
 }
 
-func (m *MockTokenService) GenerateToken(userID int, email string, duration time.Duration) (string, error) {
+func (m *MockTokenService) GenerateToken(userID int, email, role string, duration time.Duration) (string, error) {
 	if m.shouldError {
 		return "", errors.New("token generation failed")
 	}
 	token := "mock_token_" + email
 	claims := &auth.TokenClaims{
-		UserID: userID,
-		Email:  email,
+		ID:        "jti_" + email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		ExpiresAt: time.Now().Add(duration).Unix(),
 	}
 	m.tokens[token] = claims
 	return token, nil
@@ -179,6 +261,121 @@ func (m *MockTokenService) RefreshToken(token string) (string, error) {
 	return "", auth.ErrInvalidToken
 }
 
+func (m *MockTokenService) GenerateChallengeToken(userID int, duration time.Duration) (string, error) {
+	if m.shouldError {
+		return "", errors.New("token generation failed")
+	}
+	token := fmt.Sprintf("mock_challenge_%d", userID)
+	m.tokens[token] = &auth.TokenClaims{UserID: userID, ExpiresAt: time.Now().Add(duration).Unix()}
+	return token, nil
+}
+
+func (m *MockTokenService) ValidateChallengeToken(token string) (int, error) {
+	if claims, exists := m.tokens[token]; exists && strings.HasPrefix(token, "mock_challenge_") {
+		return claims.UserID, nil
+	}
+	return 0, auth.ErrInvalidToken
+}
+
+// MockRefreshTokenRepository implements auth.RefreshTokenRepository for testing
+type MockRefreshTokenRepository struct {
+	tokens map[string]*auth.RefreshToken
+	nextID int
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{
+		tokens: make(map[string]*auth.RefreshToken),
+		nextID: 1,
+	}
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, t *auth.RefreshToken) error {
+	t.ID = m.nextID
+	m.nextID++
+	m.tokens[t.TokenHash] = t
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	if t, exists := m.tokens[tokenHash]; exists {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, auth.ErrRefreshTokenNotFound
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int) error {
+	for _, t := range m.tokens {
+		if t.ID == id {
+			if t.RevokedAt != nil {
+				return auth.ErrRefreshTokenRevoked
+			}
+			now := time.Now()
+			t.RevokedAt = &now
+			return nil
+		}
+	}
+	return auth.ErrRefreshTokenNotFound
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// MockTokenBlacklist implements auth.TokenBlacklist for testing
+type MockTokenBlacklist struct {
+	entries map[string]time.Time
+}
+
+func NewMockTokenBlacklist() *MockTokenBlacklist {
+	return &MockTokenBlacklist{entries: make(map[string]time.Time)}
+}
+
+func (m *MockTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.entries[tokenID] = expiresAt
+	return nil
+}
+
+func (m *MockTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	_, exists := m.entries[tokenID]
+	return exists, nil
+}
+
+// MockTwoFactorRepository implements auth.TwoFactorRepository for testing
+type MockTwoFactorRepository struct {
+	enrollments map[int]*auth.TwoFactor
+}
+
+func NewMockTwoFactorRepository() *MockTwoFactorRepository {
+	return &MockTwoFactorRepository{enrollments: make(map[int]*auth.TwoFactor)}
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, t *auth.TwoFactor) error {
+	copied := *t
+	m.enrollments[t.UserID] = &copied
+	return nil
+}
+
+func (m *MockTwoFactorRepository) GetByUserID(ctx context.Context, userID int) (*auth.TwoFactor, error) {
+	if t, exists := m.enrollments[userID]; exists {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, auth.ErrTwoFactorNotFound
+}
+
+func (m *MockTwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	delete(m.enrollments, userID)
+	return nil
+}
+
 // MockLogger implements service.Logger for testing
 type MockLogger struct {
 	logs []LogEntry
@@ -217,7 +414,7 @@ func TestNewAuthService(t *testing.T) {
 	mockTokenService := NewMockTokenService()
 	mockLogger := NewMockLogger()
 
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
 	assert.NotNil(t, authService)
 }
@@ -229,7 +426,7 @@ func TestAuthService_GenerateToken_Success(t *testing.T) {
 
 	ctx := context.Background()
 	user := &user.User{ID: 1, Email: "test@example.com"}
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
 	token, err := authService.GenerateToken(ctx, user)
 
@@ -244,7 +441,7 @@ func TestAuthService_GenerateToken_Error(t *testing.T) {
 
 	mockTokenService.SetError(true)
 	testUser := &user.User{ID: 1, Email: "test@example.com"}
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
 	token, err := authService.GenerateToken(context.Background(), testUser)
 
@@ -260,7 +457,7 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 
 	ctx := context.Background()
 	testUser := &user.User{ID: 1, Email: "test@example.com"}
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
 	// First generate a token to validate
 	token, err := authService.GenerateToken(ctx, testUser)
@@ -280,7 +477,7 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	mockTokenService := NewMockTokenService()
 	mockLogger := NewMockLogger()
 
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
 	claims, err := authService.ValidateToken(context.Background(), "invalid_token")
 
@@ -289,21 +486,95 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	assert.Equal(t, auth.ErrInvalidToken, err)
 }
 
+func TestAuthService_Login_IssuesTokenPair(t *testing.T) {
+	mockUserService := NewMockUserService()
+	mockTokenService := NewMockTokenService()
+	mockLogger := NewMockLogger()
+
+	ctx := context.Background()
+	_, err := mockUserService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{AccessTokenTTL: 10 * time.Minute}, mockLogger)
+
+	u, tokens, err := authService.Login(ctx, "test@example.com", "password123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, u.ID)
+	assert.Equal(t, "mock_token_test@example.com", tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, 10*time.Minute, tokens.ExpiresIn)
+}
+
 func TestAuthService_RefreshToken_Success(t *testing.T) {
 	mockUserService := NewMockUserService()
 	mockTokenService := NewMockTokenService()
 	mockLogger := NewMockLogger()
 
-	// First generate a token
-	testUser := &user.User{ID: 1, Email: "test@example.com"}
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
-	token, err := authService.GenerateToken(context.Background(), testUser)
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
+	_, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	newTokens, err := authService.RefreshToken(ctx, tokens.RefreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mock_token_test@example.com", newTokens.AccessToken)
+	assert.NotEmpty(t, newTokens.RefreshToken)
+	assert.NotEqual(t, tokens.RefreshToken, newTokens.RefreshToken)
+}
+
+func TestAuthService_RefreshToken_ReuseRevokesAllTokens(t *testing.T) {
+	mockUserService := NewMockUserService()
+	mockTokenService := NewMockTokenService()
+	mockLogger := NewMockLogger()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
+	_, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	rotated, err := authService.RefreshToken(ctx, tokens.RefreshToken)
+	assert.NoError(t, err)
+
+	// Presenting the already rotated token again must fail...
+	_, err = authService.RefreshToken(ctx, tokens.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+
+	// ...and invalidate the token issued by the legitimate rotation too
+	_, err = authService.RefreshToken(ctx, rotated.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestAuthService_RefreshToken_Expired(t *testing.T) {
+	mockUserService := NewMockUserService()
+	mockTokenService := NewMockTokenService()
+	mockLogger := NewMockLogger()
+	refreshTokens := NewMockRefreshTokenRepository()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, mockTokenService, refreshTokens, NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
+	_, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
 	assert.NoError(t, err)
-	newToken, err := authService.RefreshToken(context.Background(), token)
+	refreshTokens.tokens[auth.HashRefreshToken(tokens.RefreshToken)].ExpiresAt = time.Now().Add(-time.Minute)
+
+	newTokens, err := authService.RefreshToken(ctx, tokens.RefreshToken)
 
+	assert.Nil(t, newTokens)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestAuthService_RefreshToken_PasswordResetRequired(t *testing.T) {
+	mockUserService := NewMockUserService()
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, NewMockTokenService(), NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, NewMockLogger())
+	u, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, newToken)
-	assert.Contains(t, newToken, "refreshed_")
+
+	u.RequirePasswordReset()
+	newTokens, err := authService.RefreshToken(ctx, tokens.RefreshToken)
+
+	assert.Nil(t, newTokens)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
 }
 
 func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
@@ -311,11 +582,139 @@ func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
 	mockTokenService := NewMockTokenService()
 	mockLogger := NewMockLogger()
 
-	authService := service.NewAuthService(mockUserService, mockTokenService, mockLogger)
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
 
-	newToken, err := authService.RefreshToken(context.Background(), "invalid_token")
+	newTokens, err := authService.RefreshToken(context.Background(), "invalid_token")
 
 	assert.Error(t, err)
-	assert.Empty(t, newToken)
-	assert.Equal(t, auth.ErrInvalidToken, err)
+	assert.Nil(t, newTokens)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	mockUserService := NewMockUserService()
+	mockTokenService := NewMockTokenService()
+	mockLogger := NewMockLogger()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
+	_, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	assert.NoError(t, authService.Logout(ctx, "", tokens.RefreshToken))
+	// Logging out twice is harmless
+	assert.NoError(t, authService.Logout(ctx, "", tokens.RefreshToken))
+
+	_, err = authService.RefreshToken(ctx, tokens.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestAuthService_Logout_RevokesAccessToken(t *testing.T) {
+	mockUserService := NewMockUserService()
+	mockTokenService := NewMockTokenService()
+	mockLogger := NewMockLogger()
+	blacklist := NewMockTokenBlacklist()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(mockUserService, mockTokenService, NewMockRefreshTokenRepository(), blacklist, NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, mockLogger)
+	_, tokens, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	_, err = authService.ValidateToken(ctx, tokens.AccessToken)
+	assert.NoError(t, err)
+
+	assert.NoError(t, authService.Logout(ctx, tokens.AccessToken, ""))
+	assert.Contains(t, blacklist.entries, "jti_test@example.com")
+
+	claims, err := authService.ValidateToken(ctx, tokens.AccessToken)
+	assert.Nil(t, claims)
+	assert.Equal(t, auth.ErrTokenRevoked, err)
+}
+
+func TestAuthService_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	mockUserService := NewMockUserService()
+	twoFactors := NewMockTwoFactorRepository()
+	events := &MockSecurityEvents{}
+	authService := service.NewAuthService(mockUserService, NewMockTokenService(), NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), twoFactors, nil, nil, events, service.AuthSettings{TwoFactorIssuer: "Test Blog"}, NewMockLogger())
+
+	u, _, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	enrollment, err := authService.EnableTwoFactor(ctx, u.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/Test%20Blog:test@example.com?"))
+
+	// Not required before the enrollment is confirmed
+	_, tokens, err := authService.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+	assert.NotNil(t, tokens)
+
+	assert.Equal(t, auth.ErrInvalidTwoFactorCode, authService.ConfirmTwoFactor(ctx, u.ID, "000000"))
+	code, err := auth.TOTPCode(enrollment.Secret, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, authService.ConfirmTwoFactor(ctx, u.ID, code))
+
+	_, err = authService.EnableTwoFactor(ctx, u.ID)
+	assert.Equal(t, auth.ErrTwoFactorAlreadyEnabled, err)
+
+	t.Run("login returns a challenge", func(t *testing.T) {
+		loggedIn, tokens, err := authService.Login(ctx, "test@example.com", "password123")
+
+		assert.Nil(t, loggedIn)
+		assert.Nil(t, tokens)
+		assert.ErrorIs(t, err, auth.ErrTwoFactorRequired)
+		var challenge *auth.TwoFactorChallenge
+		assert.True(t, errors.As(err, &challenge))
+		assert.Equal(t, 5*time.Minute, challenge.ExpiresIn)
+
+		// The confirmation code cannot be replayed
+		_, _, err = authService.LoginTwoFactor(ctx, challenge.Token, code)
+		assert.Equal(t, auth.ErrInvalidTwoFactorCode, err)
+
+		// Rewind the recorded step as if the next code period had started
+		twoFactors.enrollments[u.ID].LastUsedStep--
+		loggedIn, tokens, err = authService.LoginTwoFactor(ctx, challenge.Token, code)
+		assert.NoError(t, err)
+		assert.Equal(t, u.ID, loggedIn.ID)
+		assert.NotEmpty(t, tokens.AccessToken)
+	})
+
+	t.Run("rejects invalid challenge tokens", func(t *testing.T) {
+		_, _, err := authService.LoginTwoFactor(ctx, "mock_token_test@example.com", code)
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+
+	t.Run("disable requires a valid code", func(t *testing.T) {
+		assert.Equal(t, auth.ErrInvalidTwoFactorCode, authService.DisableTwoFactor(ctx, u.ID, "000000"))
+
+		assert.Empty(t, events.events)
+
+		twoFactors.enrollments[u.ID].LastUsedStep = 0
+		assert.NoError(t, authService.DisableTwoFactor(ctx, u.ID, code))
+		assert.Equal(t, auth.ErrTwoFactorNotEnabled, authService.DisableTwoFactor(ctx, u.ID, code))
+		assert.Equal(t, []service.SecurityEvent{{Kind: service.SecurityEventTwoFactorDisabled, UserID: u.ID}}, events.events)
+
+		_, tokens, err := authService.Login(ctx, "test@example.com", "password123")
+		assert.NoError(t, err)
+		assert.NotNil(t, tokens)
+	})
+}
+
+func TestAuthService_LoginWithMagicLink(t *testing.T) {
+	ctx := context.Background()
+	authService := service.NewAuthService(NewMockUserService(), NewMockTokenService(), NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, NewMockLogger())
+
+	u, _, err := authService.Register(ctx, "Test User", "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	loggedIn, tokens, err := authService.LoginWithMagicLink(ctx, "magic_test@example.com", user.NewDevice("203.0.113.7", "Firefox"))
+	assert.NoError(t, err)
+	assert.Equal(t, u.ID, loggedIn.ID)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	_, _, err = authService.LoginWithMagicLink(ctx, "magic_other@example.com", user.Device{})
+	assert.Equal(t, user.ErrInvalidMagicLink, err)
 }
@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+)
+
+// MockObjectStorage implements the service.ObjectStorage interface in
+// memory for testing
+type MockObjectStorage struct {
+	objects map[string][]byte
+	// failPut makes Put fail once that many objects are stored
+	failPut int
+}
+
+func NewMockObjectStorage() *MockObjectStorage {
+	return &MockObjectStorage{objects: make(map[string][]byte), failPut: -1}
+}
+
+func (m *MockObjectStorage) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if m.failPut == len(m.objects) {
+		return errors.New("storage unavailable")
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	m.objects[key] = data
+	return nil
+}
+
+func (m *MockObjectStorage) Delete(ctx context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *MockObjectStorage) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+// pngImage encodes a width by height PNG filled with a color
+func pngImage(t *testing.T, width, height int, fill color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newAvatarTestService() (*service.AvatarService, *MockProfileRepository, *MockObjectStorage) {
+	repo := &MockProfileRepository{profiles: map[int]*user.Profile{
+		1: {ID: 1, Name: "Author One", Bio: "Writes about Go."},
+	}}
+	storage := NewMockObjectStorage()
+	return service.NewAvatarService(repo, storage, NewMockLogger()), repo, storage
+}
+
+func TestAvatarService_UploadAvatar(t *testing.T) {
+	ctx := context.Background()
+	avatarService, repo, storage := newAvatarTestService()
+
+	avatar, err := avatarService.UploadAvatar(ctx, 1, bytes.NewReader(pngImage(t, 400, 300, color.NRGBA{R: 200, A: 255})))
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	if len(avatar.Sizes) != len(user.AvatarSizes) || len(storage.objects) != len(user.AvatarSizes) {
+		t.Fatalf("expected one image per size, got %v and %d objects", avatar.Sizes, len(storage.objects))
+	}
+	if avatar.URL != avatar.Sizes[256] {
+		t.Errorf("expected the avatar to be the largest size, got %s", avatar.URL)
+	}
+	if p := repo.profiles[1]; p.AvatarURL != avatar.URL || p.Bio != "Writes about Go." {
+		t.Errorf("expected the profile avatar to be set and the bio kept, got %+v", p)
+	}
+
+	for _, size := range user.AvatarSizes {
+		key := strings.TrimPrefix(avatar.Sizes[size], "https://cdn.example.com/")
+		img, err := jpeg.Decode(bytes.NewReader(storage.objects[key]))
+		if err != nil {
+			t.Fatalf("expected a JPEG for size %d: %v", size, err)
+		}
+		if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+			t.Errorf("expected a %dx%d image, got %dx%d", size, size, b.Dx(), b.Dy())
+		}
+		if r, g, _, _ := img.At(size/2, size/2).RGBA(); r>>8 < 180 || g>>8 > 40 {
+			t.Errorf("expected the image to keep its color, got r=%d g=%d", r>>8, g>>8)
+		}
+	}
+
+	// A new upload replaces the stored images
+	previous := avatar
+	avatar, err = avatarService.UploadAvatar(ctx, 1, bytes.NewReader(pngImage(t, 32, 32, color.White)))
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	if avatar.URL == previous.URL {
+		t.Error("expected each upload to get new URLs")
+	}
+	if len(storage.objects) != len(user.AvatarSizes) {
+		t.Errorf("expected the previous avatar to be deleted, got %d objects", len(storage.objects))
+	}
+}
+
+func TestAvatarService_UploadAvatar_KeepsOtherImages(t *testing.T) {
+	ctx := context.Background()
+	avatarService, repo, storage := newAvatarTestService()
+	storage.objects["avatars/2/abc-256.jpg"] = []byte("other account")
+	repo.profiles[1].AvatarURL = "https://cdn.example.com/avatars/2/abc-256.jpg"
+
+	if _, err := avatarService.UploadAvatar(ctx, 1, bytes.NewReader(pngImage(t, 10, 10, color.Black))); err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	if _, ok := storage.objects["avatars/2/abc-256.jpg"]; !ok {
+		t.Error("expected images of another account to be kept")
+	}
+}
+
+func TestAvatarService_UploadAvatar_Rejected(t *testing.T) {
+	ctx := context.Background()
+	avatarService, repo, storage := newAvatarTestService()
+
+	if _, err := avatarService.UploadAvatar(ctx, 1, strings.NewReader("not an image")); !errors.Is(err, user.ErrInvalidAvatar) {
+		t.Errorf("expected ErrInvalidAvatar, got %v", err)
+	}
+
+	// A GIF header claiming a 65535x65535 image
+	huge := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	if _, err := avatarService.UploadAvatar(ctx, 1, bytes.NewReader(huge)); !errors.Is(err, user.ErrAvatarTooLarge) {
+		t.Errorf("expected ErrAvatarTooLarge, got %v", err)
+	}
+
+	if _, err := avatarService.UploadAvatar(ctx, 9, bytes.NewReader(pngImage(t, 10, 10, color.Black))); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+
+	storage.failPut = 1
+	if _, err := avatarService.UploadAvatar(ctx, 1, bytes.NewReader(pngImage(t, 10, 10, color.Black))); err == nil {
+		t.Error("expected storage failures to fail the upload")
+	}
+	if len(storage.objects) != 0 {
+		t.Errorf("expected images stored before the failure to be deleted, got %d", len(storage.objects))
+	}
+	if repo.profiles[1].AvatarURL != "" {
+		t.Errorf("expected the profile to be unchanged, got %q", repo.profiles[1].AvatarURL)
+	}
+}
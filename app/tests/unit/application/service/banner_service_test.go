@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/banner"
+)
+
+// MockBannerRepository implements the banner.Repository interface for testing
+type MockBannerRepository struct {
+	banners    map[int]*banner.Banner
+	dismissals map[[2]int]bool
+}
+
+func NewMockBannerRepository() *MockBannerRepository {
+	return &MockBannerRepository{banners: make(map[int]*banner.Banner), dismissals: make(map[[2]int]bool)}
+}
+
+func (m *MockBannerRepository) Create(ctx context.Context, b *banner.Banner) error {
+	b.ID = len(m.banners) + 1
+	m.banners[b.ID] = b
+	return nil
+}
+
+func (m *MockBannerRepository) GetByID(ctx context.Context, id int) (*banner.Banner, error) {
+	b, ok := m.banners[id]
+	if !ok {
+		return nil, banner.ErrBannerNotFound
+	}
+	return b, nil
+}
+
+func (m *MockBannerRepository) List(ctx context.Context) ([]*banner.Banner, error) {
+	var banners []*banner.Banner
+	for _, b := range m.banners {
+		banners = append(banners, b)
+	}
+	return banners, nil
+}
+
+func (m *MockBannerRepository) Update(ctx context.Context, b *banner.Banner) error {
+	if _, ok := m.banners[b.ID]; !ok {
+		return banner.ErrBannerNotFound
+	}
+	m.banners[b.ID] = b
+	return nil
+}
+
+func (m *MockBannerRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.banners[id]; !ok {
+		return banner.ErrBannerNotFound
+	}
+	delete(m.banners, id)
+	return nil
+}
+
+func (m *MockBannerRepository) ListActive(ctx context.Context, now time.Time, userID int) ([]*banner.Banner, error) {
+	var banners []*banner.Banner
+	for _, b := range m.banners {
+		if b.IsActive(now) && !m.dismissals[[2]int{b.ID, userID}] {
+			banners = append(banners, b)
+		}
+	}
+	return banners, nil
+}
+
+func (m *MockBannerRepository) Dismiss(ctx context.Context, bannerID, userID int) error {
+	m.dismissals[[2]int{bannerID, userID}] = true
+	return nil
+}
+
+func TestBannerService_Lifecycle(t *testing.T) {
+	repo := NewMockBannerRepository()
+	audit := &MockAuditLogger{}
+	bannerService := service.NewBannerService(repo, audit, NewMockLogger())
+	ctx := context.Background()
+
+	b, err := bannerService.Create(ctx, 1, banner.Input{Message: "Maintenance tonight", Severity: banner.SeverityWarning})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := bannerService.Update(ctx, 1, b.ID, banner.Input{Message: "Maintenance now", Severity: banner.SeverityCritical}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := bannerService.Update(ctx, 1, b.ID, banner.Input{Message: "Maintenance now", Severity: "loud"}); err != banner.ErrInvalidSeverity {
+		t.Errorf("expected ErrInvalidSeverity, got %v", err)
+	}
+	if repo.banners[b.ID].Severity != banner.SeverityCritical {
+		t.Errorf("expected failed updates to leave the banner alone, got %q", repo.banners[b.ID].Severity)
+	}
+	if err := bannerService.Delete(ctx, 1, b.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := bannerService.Delete(ctx, 1, b.ID); err != banner.ErrBannerNotFound {
+		t.Errorf("expected ErrBannerNotFound, got %v", err)
+	}
+
+	actions := []string{service.AuditActionBannerCreated, service.AuditActionBannerUpdated, service.AuditActionBannerDeleted}
+	if len(audit.events) != len(actions) {
+		t.Fatalf("expected %d audit events, got %d", len(actions), len(audit.events))
+	}
+	for i, action := range actions {
+		if audit.events[i].Action != action || audit.events[i].UserID != 1 {
+			t.Errorf("expected audit event %q by admin 1, got %+v", action, audit.events[i])
+		}
+	}
+}
+
+func TestBannerService_Dismiss(t *testing.T) {
+	repo := NewMockBannerRepository()
+	bannerService := service.NewBannerService(repo, &MockAuditLogger{}, NewMockLogger())
+	ctx := context.Background()
+
+	news, _ := bannerService.Create(ctx, 1, banner.Input{Message: "New feature", Severity: banner.SeverityInfo, Dismissible: true})
+	outage, _ := bannerService.Create(ctx, 1, banner.Input{Message: "Outage", Severity: banner.SeverityCritical})
+
+	if err := bannerService.Dismiss(ctx, news.ID, 7); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := bannerService.Dismiss(ctx, outage.ID, 7); err != banner.ErrNotDismissible {
+		t.Errorf("expected ErrNotDismissible, got %v", err)
+	}
+	if err := bannerService.Dismiss(ctx, 9, 7); err != banner.ErrBannerNotFound {
+		t.Errorf("expected ErrBannerNotFound, got %v", err)
+	}
+
+	active, err := bannerService.ListActive(ctx, 7)
+	if err != nil || len(active) != 1 || active[0].ID != outage.ID {
+		t.Errorf("expected only the outage banner for the user, got %v (%v)", active, err)
+	}
+	if active, _ := bannerService.ListActive(ctx, 0); len(active) != 2 {
+		t.Errorf("expected guests to see both banners, got %d", len(active))
+	}
+}
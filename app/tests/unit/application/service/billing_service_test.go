@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/billing"
+)
+
+// MockBillingRepository implements billing.Repository for testing
+type MockBillingRepository struct {
+	subscriptions map[int]*billing.Subscription
+	posts         map[int]int
+}
+
+func NewMockBillingRepository() *MockBillingRepository {
+	return &MockBillingRepository{
+		subscriptions: make(map[int]*billing.Subscription),
+		posts:         make(map[int]int),
+	}
+}
+
+func (m *MockBillingRepository) GetByUserID(ctx context.Context, userID int) (*billing.Subscription, error) {
+	s, ok := m.subscriptions[userID]
+	if !ok {
+		return nil, billing.ErrSubscriptionNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *MockBillingRepository) GetByStripeCustomerID(ctx context.Context, customerID string) (*billing.Subscription, error) {
+	for _, s := range m.subscriptions {
+		if s.StripeCustomerID == customerID {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, billing.ErrSubscriptionNotFound
+}
+
+func (m *MockBillingRepository) Save(ctx context.Context, s *billing.Subscription) error {
+	copied := *s
+	m.subscriptions[s.UserID] = &copied
+	return nil
+}
+
+func (m *MockBillingRepository) CountPosts(ctx context.Context, userID int) (int, error) {
+	return m.posts[userID], nil
+}
+
+func subscriptionEvent(id string, at time.Time, status billing.Status) *billing.SubscriptionEvent {
+	return &billing.SubscriptionEvent{
+		EventID:              id,
+		OccurredAt:           at,
+		UserID:               7,
+		StripeCustomerID:     "cus_123",
+		StripeSubscriptionID: "sub_123",
+		Plan:                 billing.PlanPro,
+		Status:               status,
+	}
+}
+
+func TestBillingService_ApplyEvent(t *testing.T) {
+	repo := NewMockBillingRepository()
+	audit := &MockAuditLogger{}
+	billingService := service.NewBillingService(repo, audit, NewMockLogger())
+	ctx := context.Background()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, billingService.ApplyEvent(ctx, subscriptionEvent("evt_1", start, billing.StatusActive)))
+	overview, err := billingService.GetOverview(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, billing.PlanPro, overview.Plan)
+	assert.Equal(t, billing.LimitsOf(billing.PlanPro), overview.Limits)
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, service.AuditActionSubscriptionChanged, audit.events[0].Action)
+	assert.Equal(t, 7, audit.events[0].UserID)
+
+	// Later events find the user through the Stripe customer
+	canceled := subscriptionEvent("evt_3", start.Add(2*time.Hour), billing.StatusCanceled)
+	canceled.UserID = 0
+	require.NoError(t, billingService.ApplyEvent(ctx, canceled))
+	overview, err = billingService.GetOverview(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, billing.PlanFree, overview.Plan, "canceled subscriptions fall back to the free plan")
+	assert.Len(t, audit.events, 2)
+
+	// An event delivered late does not undo the cancellation
+	require.NoError(t, billingService.ApplyEvent(ctx, subscriptionEvent("evt_2", start.Add(time.Hour), billing.StatusActive)))
+	assert.Equal(t, billing.StatusCanceled, repo.subscriptions[7].Status)
+	assert.Len(t, audit.events, 2)
+}
+
+func TestBillingService_ApplyEvent_ReplacedSubscription(t *testing.T) {
+	repo := NewMockBillingRepository()
+	billingService := service.NewBillingService(repo, &MockAuditLogger{}, NewMockLogger())
+	ctx := context.Background()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	replacement := subscriptionEvent("evt_1", start, billing.StatusActive)
+	replacement.StripeSubscriptionID = "sub_456"
+	replacement.Plan = billing.PlanOrg
+	require.NoError(t, billingService.ApplyEvent(ctx, replacement))
+
+	// The old subscription ending does not downgrade the user
+	require.NoError(t, billingService.ApplyEvent(ctx, subscriptionEvent("evt_2", start.Add(time.Hour), billing.StatusCanceled)))
+	assert.Equal(t, "sub_456", repo.subscriptions[7].StripeSubscriptionID)
+	assert.Equal(t, billing.PlanOrg, repo.subscriptions[7].EffectivePlan())
+}
+
+func TestBillingService_ApplyEvent_UnknownCustomer(t *testing.T) {
+	billingService := service.NewBillingService(NewMockBillingRepository(), &MockAuditLogger{}, NewMockLogger())
+
+	event := subscriptionEvent("evt_1", time.Now(), billing.StatusActive)
+	event.UserID = 0
+	err := billingService.ApplyEvent(context.Background(), event)
+	assert.ErrorIs(t, err, billing.ErrUnknownCustomer)
+}
+
+func TestBillingService_Gates(t *testing.T) {
+	repo := NewMockBillingRepository()
+	billingService := service.NewBillingService(repo, &MockAuditLogger{}, NewMockLogger())
+	ctx := context.Background()
+	freePosts := billing.LimitsOf(billing.PlanFree).MaxPosts
+
+	// Users without a subscription are on the free plan
+	repo.posts[1] = freePosts - 1
+	assert.NoError(t, billingService.CheckPostLimit(ctx, 1))
+	repo.posts[1] = freePosts
+	err := billingService.CheckPostLimit(ctx, 1)
+	assert.ErrorIs(t, err, billing.ErrPlanLimit)
+	assert.ErrorIs(t, billingService.CheckCustomDomain(ctx, 1), billing.ErrCustomDomainUnavailable)
+	assert.NoError(t, billingService.CheckUploadQuota(ctx, 1, 0, billing.LimitsOf(billing.PlanFree).UploadQuota))
+	assert.ErrorIs(t, billingService.CheckUploadQuota(ctx, 1, 1, billing.LimitsOf(billing.PlanFree).UploadQuota), billing.ErrUploadQuotaExceeded)
+	assert.ErrorIs(t, billingService.CheckPaidPlan(ctx, 1), billing.ErrPaidPlanRequired)
+
+	// Paid plans lift the limits while the subscription grants them
+	repo.subscriptions[1] = &billing.Subscription{UserID: 1, Plan: billing.PlanPro, Status: billing.StatusPastDue}
+	assert.NoError(t, billingService.CheckPostLimit(ctx, 1))
+	assert.NoError(t, billingService.CheckCustomDomain(ctx, 1))
+	assert.NoError(t, billingService.CheckPaidPlan(ctx, 1))
+	assert.NoError(t, billingService.CheckUploadQuota(ctx, 1, 0, billing.LimitsOf(billing.PlanFree).UploadQuota+1))
+
+	repo.subscriptions[1].Status = billing.StatusUnpaid
+	assert.ErrorIs(t, billingService.CheckPostLimit(ctx, 1), billing.ErrPlanLimit)
+	assert.ErrorIs(t, billingService.CheckPaidPlan(ctx, 1), billing.ErrPlanLimit)
+}
@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+)
+
+// MockBookmarkRepository implements the post.BookmarkRepository interface
+// for testing, keeping the first bookmark time like the SQL upsert
+type MockBookmarkRepository struct {
+	posts     *MockPostRepository
+	bookmarks map[[2]int]time.Time
+}
+
+func NewMockBookmarkRepository(posts *MockPostRepository) *MockBookmarkRepository {
+	return &MockBookmarkRepository{posts: posts, bookmarks: make(map[[2]int]time.Time)}
+}
+
+func (m *MockBookmarkRepository) Add(ctx context.Context, userID, postID int) error {
+	key := [2]int{userID, postID}
+	if _, ok := m.bookmarks[key]; !ok {
+		m.bookmarks[key] = time.Now().Add(time.Duration(len(m.bookmarks)) * time.Millisecond)
+	}
+	return nil
+}
+
+func (m *MockBookmarkRepository) Remove(ctx context.Context, userID, postID int) error {
+	delete(m.bookmarks, [2]int{userID, postID})
+	return nil
+}
+
+func (m *MockBookmarkRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*post.BookmarkEntry, error) {
+	var entries []*post.BookmarkEntry
+	for key, createdAt := range m.bookmarks {
+		p, err := m.posts.GetByID(ctx, key[1])
+		if key[0] != userID || err != nil || !p.IsPublished() {
+			continue
+		}
+		entries = append(entries, &post.BookmarkEntry{
+			Bookmark: post.Bookmark{UserID: userID, PostID: p.ID, CreatedAt: createdAt},
+			Title:    p.Title,
+			Slug:     p.Slug,
+			AuthorID: p.AuthorID,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	if offset >= len(entries) {
+		return nil, nil
+	}
+	entries = entries[offset:]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func TestBookmarkService_Bookmark(t *testing.T) {
+	ctx := context.Background()
+	posts := NewMockPostRepository()
+	bookmarkService := service.NewBookmarkService(NewMockBookmarkRepository(posts), posts, NewMockLogger())
+
+	first, _ := post.NewPost("First Read", "Content of the first read", 1)
+	posts.Create(ctx, first)
+	second, _ := post.NewPost("Second Read", "Content of the second read", 1)
+	posts.Create(ctx, second)
+
+	for _, id := range []int{first.ID, second.ID, first.ID} {
+		if err := bookmarkService.Bookmark(ctx, 2, id); err != nil {
+			t.Fatalf("Bookmark failed: %v", err)
+		}
+	}
+
+	entries, err := bookmarkService.ListBookmarks(ctx, 2, 10, 0)
+	if err != nil {
+		t.Fatalf("ListBookmarks failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected bookmarking again to have no effect, got %d entries", len(entries))
+	}
+	if entries[0].PostID != second.ID || entries[0].Title != "Second Read" {
+		t.Errorf("expected the latest bookmark first, got %+v", entries[0])
+	}
+
+	if entries, _ := bookmarkService.ListBookmarks(ctx, 3, 10, 0); len(entries) != 0 {
+		t.Errorf("expected reading lists to be per user, got %d entries", len(entries))
+	}
+
+	if err := bookmarkService.Unbookmark(ctx, 2, first.ID); err != nil {
+		t.Fatalf("Unbookmark failed: %v", err)
+	}
+	if err := bookmarkService.Unbookmark(ctx, 2, first.ID); err != nil {
+		t.Errorf("expected removing a missing bookmark to succeed, got %v", err)
+	}
+	entries, _ = bookmarkService.ListBookmarks(ctx, 2, 10, 0)
+	if len(entries) != 1 || entries[0].PostID != second.ID {
+		t.Errorf("expected only the second post to remain, got %+v", entries)
+	}
+}
+
+func TestBookmarkService_Bookmark_Errors(t *testing.T) {
+	ctx := context.Background()
+	posts := NewMockPostRepository()
+	bookmarkService := service.NewBookmarkService(NewMockBookmarkRepository(posts), posts, NewMockLogger())
+
+	draft, _ := post.NewDraft("Unpublished", "Content of the draft", 1)
+	posts.Create(ctx, draft)
+
+	if err := bookmarkService.Bookmark(ctx, 2, 999); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+	if err := bookmarkService.Bookmark(ctx, 2, draft.ID); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected drafts to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// MockCache implements service.Cache in a map for testing
+type MockCache struct {
+	values map[string][]byte
+	hits   int
+	misses int
+	err    error
+}
+
+func NewMockCache() *MockCache {
+	return &MockCache{values: make(map[string][]byte)}
+}
+
+func (m *MockCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	value, ok := m.values[key]
+	if ok {
+		m.hits++
+	} else {
+		m.misses++
+	}
+	return value, ok, nil
+}
+
+func (m *MockCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.values[key] = value
+	return nil
+}
+
+func (m *MockCache) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func newCachedPostService(repo *MockPostRepository, cache *MockCache) *service.PostService {
+	settings := service.PostSettings{CacheTTL: time.Minute}
+	return service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, cache, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, settings, NewMockLogger())
+}
+
+func TestPostService_GetPostIsCached(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	postService := newCachedPostService(repo, cache)
+	ctx := context.Background()
+
+	created, err := postService.CreatePost(ctx, 1, "Cached Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.GetPost(ctx, created.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A change behind the service's back is not seen until invalidated
+	repo.posts[created.ID].Title = "Changed Elsewhere"
+	cached, err := postService.GetPost(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cached.Title != "Cached Post" {
+		t.Errorf("expected the cached title, got %q", cached.Title)
+	}
+	if cache.hits != 1 {
+		t.Errorf("expected 1 cache hit, got %d", cache.hits)
+	}
+
+	// Changes through the service invalidate the cached post
+	if _, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, created.ID, "Updated Post", "Updated content with sufficient length."); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	updated, err := postService.GetPost(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Title != "Updated Post" {
+		t.Errorf("expected the updated title, got %q", updated.Title)
+	}
+
+	if err := postService.DeletePost(ctx, 1, user.RoleAuthor, created.ID); err != nil {
+		t.Fatalf("failed to delete post: %v", err)
+	}
+	if _, err := postService.GetPost(ctx, created.ID); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound after deletion, got %v", err)
+	}
+}
+
+func TestPostService_GetVisiblePostChecksCachedPosts(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	postService := newCachedPostService(repo, cache)
+	ctx := context.Background()
+
+	created, err := postService.CreatePost(ctx, 1, "Private Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.SetVisibility(ctx, 1, user.RoleAuthor, created.ID, post.VisibilityPrivate); err != nil {
+		t.Fatalf("failed to set post visibility: %v", err)
+	}
+
+	// The author's read caches the post; others do not get it from there
+	if _, err := postService.GetVisiblePost(ctx, 1, user.RoleAuthor, created.ID); err != nil {
+		t.Fatalf("expected the author to get the post, got %v", err)
+	}
+	if _, err := postService.GetVisiblePost(ctx, 0, "", created.ID); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound for anonymous readers, got %v", err)
+	}
+	if _, err := postService.GetVisiblePost(ctx, 2, user.RoleAuthor, created.ID); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound for other authors, got %v", err)
+	}
+	if cache.hits != 2 {
+		t.Errorf("expected 2 cache hits, got %d", cache.hits)
+	}
+	if _, err := postService.GetVisiblePost(ctx, 9, user.RoleAdmin, created.ID); err != nil {
+		t.Errorf("expected admins to get the post, got %v", err)
+	}
+}
+
+func TestPostService_ListPostsCachesFirstPage(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	postService := newCachedPostService(repo, cache)
+	ctx := context.Background()
+
+	if _, err := postService.CreatePost(ctx, 1, "First Post", "Test content with sufficient length."); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.ListPosts(ctx, 10, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	hits := cache.hits
+	posts, err := postService.ListPosts(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(posts) != 1 || cache.hits <= hits {
+		t.Errorf("expected the cached first page with 1 post, got %d posts", len(posts))
+	}
+
+	// New posts invalidate the cached pages
+	if _, err := postService.CreatePost(ctx, 1, "Second Post", "Test content with sufficient length."); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	posts, err = postService.ListPosts(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("expected 2 posts after invalidation, got %d", len(posts))
+	}
+}
+
+func TestPostService_CacheFailuresFallBackToRepository(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	cache.err = errors.New("cache unavailable")
+	postService := newCachedPostService(repo, cache)
+	ctx := context.Background()
+
+	created, err := postService.CreatePost(ctx, 1, "Uncached Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	got, err := postService.GetPost(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Title != "Uncached Post" {
+		t.Errorf("expected the stored title, got %q", got.Title)
+	}
+}
+
+func TestUserService_GetByEmailIsCached(t *testing.T) {
+	repo := NewMockUserRepository()
+	cache := NewMockCache()
+	settings := service.UserSettings{CacheTTL: time.Minute}
+	userService := service.NewUserService(repo, NewMockEmailChangeRepository(repo), repo, NewMockIdentityRepository(), NewMockMagicLinkRepository(), NewMockLoginAttemptStore(), cache, user.NewEmailNormalizer(user.DefaultCanonicalProviders), &MockMailer{}, &MockAuditLogger{}, &MockSecurityEvents{}, &MockEventPublisher{}, settings, NewMockLogger())
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "Cached User", "cached@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if _, err := userService.GetByEmail(ctx, "cached@example.com"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cached, err := userService.GetByEmail(ctx, "Cached@Example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cache.hits != 1 {
+		t.Errorf("expected lookups of the same normalized address to hit the cache, got %d hits", cache.hits)
+	}
+	if cached.PasswordHash == "" {
+		t.Error("expected the cached user to keep its password hash")
+	}
+
+	// Changes through the service invalidate the cached user
+	if _, err := userService.ChangeRole(ctx, registered.ID, user.RoleAdmin); err != nil {
+		t.Fatalf("failed to change role: %v", err)
+	}
+	changed, err := userService.GetByEmail(ctx, "cached@example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if changed.Role != user.RoleAdmin {
+		t.Errorf("expected role %s after invalidation, got %s", user.RoleAdmin, changed.Role)
+	}
+
+	if err := userService.Delete(ctx, registered.ID); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+	if _, err := userService.GetByEmail(ctx, "cached@example.com"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound after deletion, got %v", err)
+	}
+}
+
+func TestUserService_AccountChangedInvalidatesCache(t *testing.T) {
+	repo := NewMockUserRepository()
+	cache := NewMockCache()
+	bus := service.NewEventBus(NewMockLogger())
+	settings := service.UserSettings{CacheTTL: time.Minute}
+	userService := service.NewUserService(repo, NewMockEmailChangeRepository(repo), repo, NewMockIdentityRepository(), NewMockMagicLinkRepository(), NewMockLoginAttemptStore(), cache, user.NewEmailNormalizer(user.DefaultCanonicalProviders), &MockMailer{}, &MockAuditLogger{}, &MockSecurityEvents{}, bus, settings, NewMockLogger())
+	userService.Subscribe(bus)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "Cached User", "cached@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if _, err := userService.GetByEmail(ctx, "cached@example.com"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Another service locks the account
+	registered.RequirePasswordReset()
+	if err := repo.Update(ctx, registered); err != nil {
+		t.Fatalf("failed to lock account: %v", err)
+	}
+	bus.Publish(ctx, service.AccountChanged{User: registered})
+
+	locked, err := userService.GetByEmail(ctx, "cached@example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !locked.IsPasswordResetRequired() {
+		t.Error("expected the locked account after invalidation")
+	}
+}
+
+func TestPostService_TrendingIsCachedBetweenRefreshes(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	settings := service.PostSettings{CacheTTL: time.Minute, TrendingRefresh: 10 * time.Minute}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, cache, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, settings, NewMockLogger())
+	ctx := context.Background()
+
+	first, _ := postService.CreatePost(ctx, 1, "First Post", "Test content with sufficient length.")
+	second, _ := postService.CreatePost(ctx, 1, "Second Post", "Test content with sufficient length.")
+	today := post.ViewDay(time.Now())
+	repo.AddViews(ctx, []post.ViewCount{{PostID: first.ID, Day: today, Views: 10}, {PostID: second.ID, Day: today, Views: 3}})
+
+	// The first request ranks the posts when no ranking is cached
+	trending, err := postService.ListTrendingPosts(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to list trending posts: %v", err)
+	}
+	if len(trending) != 2 || trending[0].Post.ID != first.ID || trending[0].Score <= trending[1].Score {
+		t.Fatalf("expected the most viewed post first, got %+v", trending)
+	}
+
+	// Later activity shows after the next refresh only
+	repo.AddViews(ctx, []post.ViewCount{{PostID: second.ID, Day: today, Views: 50}})
+	if trending, _ := postService.ListTrendingPosts(ctx, 0); trending[0].Post.ID != first.ID {
+		t.Errorf("expected the cached ranking until the next refresh, got post %d first", trending[0].Post.ID)
+	}
+	if _, err := postService.RefreshTrending(ctx); err != nil {
+		t.Fatalf("failed to refresh trending posts: %v", err)
+	}
+	if trending, _ := postService.ListTrendingPosts(ctx, 1); len(trending) != 1 || trending[0].Post.ID != second.ID {
+		t.Errorf("expected the refreshed ranking, got %+v", trending)
+	}
+
+	// Deleted posts drop out of the cached ranking at once
+	if err := postService.DeletePost(ctx, 1, user.RoleAuthor, second.ID); err != nil {
+		t.Fatalf("failed to delete post: %v", err)
+	}
+	if trending, _ := postService.ListTrendingPosts(ctx, 0); len(trending) != 1 || trending[0].Post.ID != first.ID {
+		t.Errorf("expected the deleted post left out, got %+v", trending)
+	}
+	if trending, _ := postService.ListTrendingPosts(ctx, 1); len(trending) != 1 || trending[0].Post.ID != first.ID {
+		t.Errorf("expected the next post to take the place of the deleted one, got %+v", trending)
+	}
+}
+
+func TestPostService_SeriesPostsChangedInvalidatesCache(t *testing.T) {
+	repo := NewMockPostRepository()
+	cache := NewMockCache()
+	bus := service.NewEventBus(NewMockLogger())
+	postService := newCachedPostService(repo, cache)
+	postService.Subscribe(bus)
+	ctx := context.Background()
+
+	created, err := postService.CreatePost(ctx, 1, "Cached Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.GetPost(ctx, created.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The series service adds the post to a series
+	seriesID := 7
+	repo.posts[created.ID].SeriesID = &seriesID
+	repo.posts[created.ID].SeriesPosition = 1
+	bus.Publish(ctx, service.SeriesPostsChanged{SeriesID: seriesID, PostIDs: []int{created.ID}})
+
+	fetched, err := postService.GetPost(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if fetched.SeriesID == nil || *fetched.SeriesID != seriesID {
+		t.Errorf("expected the post in series %d after invalidation, got %v", seriesID, fetched.SeriesID)
+	}
+}
@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+)
+
+// MockReportRepository implements the comment.ReportRepository interface
+// for testing
+type MockReportRepository struct {
+	reports []*comment.Report
+}
+
+func (m *MockReportRepository) Create(ctx context.Context, r *comment.Report) (bool, error) {
+	for _, existing := range m.reports {
+		if existing.CommentID == r.CommentID && existing.ReporterKey == r.ReporterKey {
+			return false, nil
+		}
+	}
+	r.ID = len(m.reports) + 1
+	m.reports = append(m.reports, r)
+	return true, nil
+}
+
+func (m *MockReportRepository) CountByComment(ctx context.Context, commentID int) (int, error) {
+	count := 0
+	for _, r := range m.reports {
+		if r.CommentID == commentID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockReportRepository) ListByComment(ctx context.Context, commentID int) ([]*comment.Report, error) {
+	var result []*comment.Report
+	for _, r := range m.reports {
+		if r.CommentID == commentID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func TestCommentReportService_HidesAtThreshold(t *testing.T) {
+	comments := NewMockCommentRepository()
+	reports := &MockReportRepository{}
+	reportService := service.NewCommentReportService(reports, comments, service.ReportSettings{HideThreshold: 2}, NewMockLogger())
+	ctx := context.Background()
+
+	c, _ := comment.NewComment(1, "John Doe", "A comment on the post")
+	comments.Create(ctx, c)
+
+	if _, err := reportService.ReportComment(ctx, c.ID, 7, "192.0.2.1", comment.ReasonSpam, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// The same reporter counts once
+	if _, err := reportService.ReportComment(ctx, c.ID, 7, "192.0.2.1", comment.ReasonSpam, ""); err != nil {
+		t.Fatalf("expected repeated reports to be accepted, got %v", err)
+	}
+	if !c.IsApproved() {
+		t.Fatalf("expected the comment to stay up below the threshold, got %q", c.Status)
+	}
+
+	if _, err := reportService.ReportComment(ctx, c.ID, 0, "192.0.2.1", comment.ReasonAbuse, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.Status != comment.StatusPending {
+		t.Errorf("expected the comment to be held for review, got %q", c.Status)
+	}
+	if _, err := reportService.ReportComment(ctx, c.ID, 8, "", comment.ReasonSpam, ""); err != comment.ErrCommentNotFound {
+		t.Errorf("expected hidden comments not to be reportable, got %v", err)
+	}
+
+	// Comments an admin approves again are not hidden by later reports
+	c.Status = comment.StatusApproved
+	if _, err := reportService.ReportComment(ctx, c.ID, 8, "", comment.ReasonSpam, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !c.IsApproved() {
+		t.Errorf("expected the approved comment to stay up, got %q", c.Status)
+	}
+
+	listed, err := reportService.ListReports(ctx, c.ID)
+	if err != nil || len(listed) != 3 {
+		t.Errorf("expected 3 reports, got %d (%v)", len(listed), err)
+	}
+}
+
+func TestCommentReportService_Validation(t *testing.T) {
+	comments := NewMockCommentRepository()
+	reportService := service.NewCommentReportService(&MockReportRepository{}, comments, service.ReportSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	if _, err := reportService.ReportComment(ctx, 1, 7, "", comment.ReasonSpam, ""); err != comment.ErrCommentNotFound {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+	if _, err := reportService.ReportComment(ctx, 1, 7, "", "boring", ""); err != comment.ErrInvalidReportReason {
+		t.Errorf("expected ErrInvalidReportReason, got %v", err)
+	}
+	if _, err := reportService.ListReports(ctx, 1); err != comment.ErrCommentNotFound {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+
+	// Without a threshold reported comments stay up
+	c, _ := comment.NewComment(1, "John Doe", "A comment on the post")
+	comments.Create(ctx, c)
+	for i := 1; i <= 5; i++ {
+		reportService.ReportComment(ctx, c.ID, i, "", comment.ReasonSpam, "")
+	}
+	if !c.IsApproved() {
+		t.Errorf("expected the comment to stay up, got %q", c.Status)
+	}
+}
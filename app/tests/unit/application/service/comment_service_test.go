@@ -2,16 +2,28 @@ package service_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"testing"
+	"time"
 
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/clientid"
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
 )
 
 // MockCommentRepository implements the comment.Repository interface for testing
 type MockCommentRepository struct {
 	comments map[int]*comment.Comment
 	nextID   int
+	levels   map[int]comment.TrustLevel
+	// histories is returned by ListCommenterHistories as is
+	histories []*comment.CommenterHistory
 }
 
 // NewMockCommentRepository creates a new mock repository
@@ -19,11 +31,17 @@ func NewMockCommentRepository() *MockCommentRepository {
 	return &MockCommentRepository{
 		comments: make(map[int]*comment.Comment),
 		nextID:   1,
+		levels:   make(map[int]comment.TrustLevel),
 	}
 }
 
 // Create adds a new comment to the mock repository
 func (m *MockCommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	if c.ClientID != nil {
+		if _, err := m.GetByClientID(ctx, *c.ClientID); err == nil {
+			return comment.ErrClientIDTaken
+		}
+	}
 	c.ID = m.nextID
 	m.comments[c.ID] = c
 	m.nextID++
@@ -39,13 +57,23 @@ func (m *MockCommentRepository) GetByID(ctx context.Context, id int) (*comment.C
 	return c, nil
 }
 
+// GetByClientID retrieves a comment by the client ID it was created with
+func (m *MockCommentRepository) GetByClientID(ctx context.Context, clientID string) (*comment.Comment, error) {
+	for _, c := range m.comments {
+		if c.ClientID != nil && *c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return nil, comment.ErrCommentNotFound
+}
+
 // GetByPostID retrieves comments for a specific post with pagination
 func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
 	var result []*comment.Comment
 	count := 0
 	
 	for _, c := range m.comments {
-		if c.PostID == postID {
+		if c.PostID == postID && c.IsApproved() {
 			if count >= offset {
 				result = append(result, c)
 				if len(result) >= limit {
@@ -59,6 +87,92 @@ func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID int, lim
 	return result, nil
 }
 
+// GetByPostIDAfter retrieves the comments of a post after the cursor, oldest first
+func (m *MockCommentRepository) GetByPostIDAfter(ctx context.Context, postID int, after keyset.Cursor, limit int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.PostID != postID || !c.IsApproved() {
+			continue
+		}
+		if after.IsZero() || c.CreatedAt.After(after.CreatedAt) || (c.CreatedAt.Equal(after.CreatedAt) && c.ID > after.ID) {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result[:min(limit, len(result))], nil
+}
+
+// GetRootsByPostID retrieves the top-level comments of a post, oldest first
+func (m *MockCommentRepository) GetRootsByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.PostID == postID && c.ParentID == nil && c.IsApproved() {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	return result[:min(limit, len(result))], nil
+}
+
+// GetByRootIDs retrieves the replies in the threads of top-level comments,
+// oldest first
+func (m *MockCommentRepository) GetByRootIDs(ctx context.Context, rootIDs []int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.RootID != nil && slices.Contains(rootIDs, *c.RootID) && c.IsApproved() {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// ListRecent retrieves the newest comments of a post. Post authors are not
+// known to the mock, so PostAuthorID is ignored.
+func (m *MockCommentRepository) ListRecent(ctx context.Context, filter comment.RecentFilter, limit int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if (filter.PostID == 0 || c.PostID == filter.PostID) && c.IsApproved() {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// ListByStatus retrieves the comments with a moderation status, oldest first
+func (m *MockCommentRepository) ListByStatus(ctx context.Context, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.Status == status {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	return result[:min(limit, len(result))], nil
+}
+
 // Update modifies an existing comment
 func (m *MockCommentRepository) Update(ctx context.Context, c *comment.Comment) error {
 	if _, exists := m.comments[c.ID]; !exists {
@@ -77,20 +191,73 @@ func (m *MockCommentRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// DeleteByPostID removes the comments of a post
+func (m *MockCommentRepository) DeleteByPostID(ctx context.Context, postID int) (int, error) {
+	deleted := 0
+	for id, c := range m.comments {
+		if c.PostID == postID {
+			delete(m.comments, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// LastCommentAt returns when a user last commented
+func (m *MockCommentRepository) LastCommentAt(ctx context.Context, userID int) (time.Time, error) {
+	var last time.Time
+	for _, c := range m.comments {
+		if c.IsPostedBy(userID) && c.CreatedAt.After(last) {
+			last = c.CreatedAt
+		}
+	}
+	return last, nil
+}
+
+// GetTrustLevel returns the trust level of a commenter, new by default
+func (m *MockCommentRepository) GetTrustLevel(ctx context.Context, userID int) (comment.TrustLevel, error) {
+	if level, ok := m.levels[userID]; ok {
+		return level, nil
+	}
+	return comment.TrustNew, nil
+}
+
+// SetTrustLevel stores the trust level of a commenter
+func (m *MockCommentRepository) SetTrustLevel(ctx context.Context, userID int, level comment.TrustLevel) error {
+	m.levels[userID] = level
+	return nil
+}
+
+// ListCommenterHistories returns the preset commenter histories
+func (m *MockCommentRepository) ListCommenterHistories(ctx context.Context, flaggedSince time.Time) ([]*comment.CommenterHistory, error) {
+	return m.histories, nil
+}
+
+// publishedPosts returns a post repository with the published posts 1 and 2
+// of user 1 to comment on
+func publishedPosts() *MockPostRepository {
+	posts := NewMockPostRepository()
+	for i := 0; i < 2; i++ {
+		p, _ := post.NewPost(fmt.Sprintf("Post %d", i+1), "Content of the post to comment on", 1)
+		posts.Create(context.Background(), p)
+	}
+	return posts
+}
+
 func TestCommentService_Implementation(t *testing.T) {
 	repo := NewMockCommentRepository()
 	
 	// Verify that CommentService implements the Service interface
-	var _ comment.Service = service.NewCommentService(repo, NewMockLogger())
+	var _ comment.Service = service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 }
 
 func TestCommentService_AddComment_Integration(t *testing.T) {
 	repo := NewMockCommentRepository()
-	commentService := service.NewCommentService(repo, NewMockLogger())
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Test successful comment creation
-	c, err := commentService.AddComment(ctx, 1, "John Doe", "Test comment content")
+	c, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Test comment content")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -116,7 +283,7 @@ func TestCommentService_AddComment_Integration(t *testing.T) {
 	}
 
 	// Test validation error
-	_, err = commentService.AddComment(ctx, 0, "John Doe", "Test comment content")
+	_, err = commentService.AddComment(ctx, 0, 1, "", "John Doe", "Test comment content")
 	if err == nil {
 		t.Error("expected error for invalid post ID")
 	}
@@ -124,7 +291,7 @@ func TestCommentService_AddComment_Integration(t *testing.T) {
 
 func TestCommentService_GetComment_Integration(t *testing.T) {
 	repo := NewMockCommentRepository()
-	commentService := service.NewCommentService(repo, NewMockLogger())
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Test getting non-existent comment
@@ -140,7 +307,7 @@ func TestCommentService_GetComment_Integration(t *testing.T) {
 	}
 
 	// Create and retrieve comment
-	created, err := commentService.AddComment(ctx, 1, "John Doe", "Test comment content")
+	created, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Test comment content")
 	if err != nil {
 		t.Fatalf("failed to create comment: %v", err)
 	}
@@ -157,7 +324,7 @@ func TestCommentService_GetComment_Integration(t *testing.T) {
 
 func TestCommentService_GetCommentsByPost_Integration(t *testing.T) {
 	repo := NewMockCommentRepository()
-	commentService := service.NewCommentService(repo, NewMockLogger())
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Create comments for different posts
@@ -167,14 +334,14 @@ func TestCommentService_GetCommentsByPost_Integration(t *testing.T) {
 			postID = 2
 		}
 		
-		_, err := commentService.AddComment(ctx, postID, "Author", "Comment content for testing")
+		_, err := commentService.AddComment(ctx, postID, 0, "", "Author", "Comment content for testing")
 		if err != nil {
 			t.Fatalf("failed to create comment %d: %v", i, err)
 		}
 	}
 
 	// Test successful retrieval
-	comments, err := commentService.GetCommentsByPost(ctx, 1, 10, 0)
+	comments, err := commentService.GetCommentsByPost(ctx, 1, 0, "", 10, 0)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -184,35 +351,97 @@ func TestCommentService_GetCommentsByPost_Integration(t *testing.T) {
 	}
 
 	// Test validation errors
-	_, err = commentService.GetCommentsByPost(ctx, 0, 10, 0)
+	_, err = commentService.GetCommentsByPost(ctx, 0, 0, "", 10, 0)
 	if err == nil {
 		t.Error("expected error for invalid post ID")
 	}
 
-	_, err = commentService.GetCommentsByPost(ctx, 1, 0, 0)
-	if err == nil {
-		t.Error("expected error for invalid limit")
+	// Test pagination defaults: a missing limit uses the default page size
+	// and negative offsets start at the first comment
+	comments, err = commentService.GetCommentsByPost(ctx, 1, 0, "", 0, -1)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(comments) != 3 {
+		t.Errorf("expected 3 comments for post 1, got %d", len(comments))
+	}
+}
+
+func TestCommentService_GetCommentsByPost_PageLimits(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{
+		Pagination: service.PageLimits{Default: 2, Max: 3},
+	}, NewMockLogger())
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		if _, err := commentService.AddComment(ctx, 1, 0, "", "Author", "Comment content for testing"); err != nil {
+			t.Fatalf("failed to create comment %d: %v", i, err)
+		}
+	}
+
+	comments, err := commentService.GetCommentsByPost(ctx, 1, 0, "", 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 2 {
+		t.Errorf("expected the default page size of 2, got %d", len(comments))
 	}
 
-	_, err = commentService.GetCommentsByPost(ctx, 1, 10, -1)
+	comments, err = commentService.GetCommentsByPost(ctx, 1, 0, "", 50, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 3 {
+		t.Errorf("expected the maximum page size of 3, got %d", len(comments))
+	}
+}
+
+func TestCommentService_GetRecentComments_Integration(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		_, err := commentService.AddComment(ctx, 1, 0, "", "Author", "Comment content for testing")
+		if err != nil {
+			t.Fatalf("failed to create comment %d: %v", i, err)
+		}
+	}
+
+	comments, err := commentService.GetRecentComments(ctx, comment.RecentFilter{PostID: 1}, 2)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %d", len(comments))
+	}
+	if comments[0].ID != 3 || comments[1].ID != 2 {
+		t.Errorf("expected newest comments first, got IDs %d and %d", comments[0].ID, comments[1].ID)
+	}
+
+	// Test validation errors
+	_, err = commentService.GetRecentComments(ctx, comment.RecentFilter{PostID: -1}, 10)
 	if err == nil {
-		t.Error("expected error for invalid offset")
+		t.Error("expected error for invalid post ID")
 	}
 }
 
 func TestCommentService_UpdateComment_Integration(t *testing.T) {
 	repo := NewMockCommentRepository()
-	commentService := service.NewCommentService(repo, NewMockLogger())
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Create a comment
-	created, err := commentService.AddComment(ctx, 1, "John Doe", "Original content")
+	created, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Original content")
 	if err != nil {
 		t.Fatalf("failed to create comment: %v", err)
 	}
 
 	// Test successful update
-	updated, err := commentService.UpdateComment(ctx, created.ID, "John Doe", "Updated content")
+	updated, err := commentService.UpdateComment(ctx, created.ID, 1, user.RoleAuthor, "Updated content")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -222,18 +451,18 @@ func TestCommentService_UpdateComment_Integration(t *testing.T) {
 	}
 
 	// Test authorization failure
-	_, err = commentService.UpdateComment(ctx, created.ID, "Jane Smith", "Unauthorized update")
+	_, err = commentService.UpdateComment(ctx, created.ID, 2, user.RoleAuthor, "Unauthorized update")
 	if err == nil {
 		t.Error("expected error for unauthorized update")
 	}
 
 	// Test validation errors
-	_, err = commentService.UpdateComment(ctx, 0, "John Doe", "Updated content")
+	_, err = commentService.UpdateComment(ctx, 0, 1, user.RoleAuthor, "Updated content")
 	if err == nil {
 		t.Error("expected error for invalid comment ID")
 	}
 
-	_, err = commentService.UpdateComment(ctx, 999, "John Doe", "Updated content")
+	_, err = commentService.UpdateComment(ctx, 999, 1, user.RoleAuthor, "Updated content")
 	if err != comment.ErrCommentNotFound {
 		t.Errorf("expected ErrCommentNotFound, got %v", err)
 	}
@@ -241,34 +470,34 @@ func TestCommentService_UpdateComment_Integration(t *testing.T) {
 
 func TestCommentService_DeleteComment_Integration(t *testing.T) {
 	repo := NewMockCommentRepository()
-	commentService := service.NewCommentService(repo, NewMockLogger())
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Create a comment
-	created, err := commentService.AddComment(ctx, 1, "John Doe", "Test comment content")
+	created, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Test comment content")
 	if err != nil {
 		t.Fatalf("failed to create comment: %v", err)
 	}
 
 	// Test authorization failure
-	err = commentService.DeleteComment(ctx, created.ID, "Jane Smith")
+	err = commentService.DeleteComment(ctx, created.ID, 2, user.RoleAuthor)
 	if err == nil {
 		t.Error("expected error for unauthorized deletion")
 	}
 
 	// Test validation errors
-	err = commentService.DeleteComment(ctx, 0, "John Doe")
+	err = commentService.DeleteComment(ctx, 0, 1, user.RoleAuthor)
 	if err == nil {
 		t.Error("expected error for invalid comment ID")
 	}
 
-	err = commentService.DeleteComment(ctx, 999, "John Doe")
+	err = commentService.DeleteComment(ctx, 999, 1, user.RoleAuthor)
 	if err != comment.ErrCommentNotFound {
 		t.Errorf("expected ErrCommentNotFound, got %v", err)
 	}
 
 	// Test successful deletion
-	err = commentService.DeleteComment(ctx, created.ID, "John Doe")
+	err = commentService.DeleteComment(ctx, created.ID, 1, user.RoleAuthor)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -279,3 +508,425 @@ func TestCommentService_DeleteComment_Integration(t *testing.T) {
 		t.Errorf("expected ErrCommentNotFound after deletion, got %v", err)
 	}
 }
+
+func TestCommentService_GuestComments(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	signedIn, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "A comment by a registered user")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	if signedIn.UserID == nil || *signedIn.UserID != 1 {
+		t.Errorf("expected the comment to be linked to user 1, got %v", signedIn.UserID)
+	}
+
+	guest, err := commentService.AddComment(ctx, 1, 0, "", "John Doe", "A guest comment")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	if guest.UserID != nil {
+		t.Errorf("expected a guest comment, got user %d", *guest.UserID)
+	}
+
+	// Sharing the author name does not make the guest comment the user's
+	if _, err := commentService.UpdateComment(ctx, guest.ID, 1, user.RoleAuthor, "Claimed"); err == nil {
+		t.Error("expected error for updating a guest comment")
+	}
+	if err := commentService.DeleteComment(ctx, guest.ID, 1, user.RoleAuthor); err == nil {
+		t.Error("expected error for deleting a guest comment")
+	}
+}
+
+func TestCommentService_AdminCanModifyAnyComment(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	created, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Original content")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+
+	updated, err := commentService.UpdateComment(ctx, created.ID, 3, user.RoleAdmin, "Moderated content")
+	if err != nil {
+		t.Fatalf("expected admin update to succeed, got %v", err)
+	}
+	if updated.AuthorName != "John Doe" {
+		t.Errorf("expected author to remain 'John Doe', got '%s'", updated.AuthorName)
+	}
+
+	if err := commentService.DeleteComment(ctx, created.ID, 3, user.RoleAdmin); err != nil {
+		t.Fatalf("expected admin delete to succeed, got %v", err)
+	}
+
+	if _, err := commentService.GetComment(ctx, created.ID); err != comment.ErrCommentNotFound {
+		t.Errorf("expected ErrCommentNotFound after deletion, got %v", err)
+	}
+}
+
+func TestCommentService_HiddenPosts(t *testing.T) {
+	ctx := context.Background()
+
+	draft, _ := post.NewDraft("Draft Post", "Content of the post to comment on", 1)
+	private, _ := post.NewPost("Private Post", "Content of the post to comment on", 1)
+	private.Visibility = post.VisibilityPrivate
+
+	for name, p := range map[string]*post.Post{"draft": draft, "private": private} {
+		t.Run(name, func(t *testing.T) {
+			posts := NewMockPostRepository()
+			posts.Create(ctx, p)
+			commentService := service.NewCommentService(NewMockCommentRepository(), posts, &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+
+			// The author and admins comment as on any other post
+			root, err := commentService.AddComment(ctx, p.ID, 1, user.RoleAuthor, "John Doe", "A comment by the author")
+			if err != nil {
+				t.Fatalf("expected the author to comment, got %v", err)
+			}
+			if _, err := commentService.ReplyToComment(ctx, p.ID, root.ID, 3, user.RoleAdmin, "Admin", "A reply by an admin"); err != nil {
+				t.Fatalf("expected an admin to reply, got %v", err)
+			}
+			comments, err := commentService.GetCommentsByPost(ctx, p.ID, 3, user.RoleAdmin, 10, 0)
+			if err != nil || len(comments) != 2 {
+				t.Fatalf("expected an admin to list 2 comments, got %d (%v)", len(comments), err)
+			}
+
+			// Guests and other users see neither the post nor its comments
+			for _, userID := range []int{0, 2} {
+				if _, err := commentService.AddComment(ctx, p.ID, userID, user.RoleAuthor, "Jane Doe", "A comment"); !errors.Is(err, post.ErrPostNotFound) {
+					t.Errorf("user %d: expected ErrPostNotFound for commenting, got %v", userID, err)
+				}
+				if _, err := commentService.ReplyToComment(ctx, p.ID, root.ID, userID, user.RoleAuthor, "Jane Doe", "A reply"); !errors.Is(err, post.ErrPostNotFound) {
+					t.Errorf("user %d: expected ErrPostNotFound for replying, got %v", userID, err)
+				}
+				if _, err := commentService.GetCommentsByPost(ctx, p.ID, userID, user.RoleAuthor, 10, 0); !errors.Is(err, post.ErrPostNotFound) {
+					t.Errorf("user %d: expected ErrPostNotFound for listing, got %v", userID, err)
+				}
+				if _, _, err := commentService.GetCommentsByPostAfter(ctx, p.ID, userID, user.RoleAuthor, keyset.Cursor{}, 10); !errors.Is(err, post.ErrPostNotFound) {
+					t.Errorf("user %d: expected ErrPostNotFound for listing after a cursor, got %v", userID, err)
+				}
+				if _, err := commentService.GetThreadsByPost(ctx, p.ID, userID, user.RoleAuthor, 10, 0); !errors.Is(err, post.ErrPostNotFound) {
+					t.Errorf("user %d: expected ErrPostNotFound for listing threads, got %v", userID, err)
+				}
+			}
+			if comments, _ := commentService.GetCommentsByPost(ctx, p.ID, 1, user.RoleAuthor, 10, 0); len(comments) != 2 {
+				t.Errorf("expected no comments to be added, got %d", len(comments))
+			}
+		})
+	}
+}
+
+func TestCommentService_GetCommentsByPostAfter(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		if _, err := commentService.AddComment(ctx, 1, 0, "", "Author", "Comment content for testing"); err != nil {
+			t.Fatalf("failed to create comment %d: %v", i, err)
+		}
+	}
+	if _, err := commentService.AddComment(ctx, 2, 0, "", "Author", "Comment on another post"); err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+
+	comments, next, err := commentService.GetCommentsByPostAfter(ctx, 1, 0, "", keyset.Cursor{}, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != 1 || comments[1].ID != 2 {
+		t.Fatalf("expected comments 1 and 2, got %v", comments)
+	}
+	if next.ID != 2 {
+		t.Fatalf("expected a cursor after comment 2, got %+v", next)
+	}
+
+	comments, next, err = commentService.GetCommentsByPostAfter(ctx, 1, 0, "", next, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != 3 {
+		t.Errorf("expected comment 3, got %v", comments)
+	}
+	if !next.IsZero() {
+		t.Errorf("expected no cursor on the last page, got %+v", next)
+	}
+
+	if _, _, err := commentService.GetCommentsByPostAfter(ctx, 0, 0, "", keyset.Cursor{}, 2); err == nil {
+		t.Error("expected an error for an invalid post ID")
+	}
+}
+
+func TestCommentService_ReplyToComment(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	root, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Top-level comment")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+
+	reply, err := commentService.ReplyToComment(ctx, 1, root.ID, 2, "", "Jane Smith", "A reply to the comment")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reply.ParentID == nil || *reply.ParentID != root.ID || reply.Depth != 1 {
+		t.Errorf("expected a reply to comment %d, got %+v", root.ID, reply)
+	}
+
+	if _, err := commentService.ReplyToComment(ctx, 1, 999, 2, "", "Jane Smith", "A reply to nothing"); err != comment.ErrParentNotFound {
+		t.Errorf("expected ErrParentNotFound, got %v", err)
+	}
+	if _, err := commentService.ReplyToComment(ctx, 2, root.ID, 2, "", "Jane Smith", "A reply on another post"); err != comment.ErrParentOtherPost {
+		t.Errorf("expected ErrParentOtherPost, got %v", err)
+	}
+
+	parent := reply
+	for depth := 2; depth <= comment.MaxDepth; depth++ {
+		if parent, err = commentService.ReplyToComment(ctx, 1, parent.ID, 2, "", "Jane Smith", "A nested reply"); err != nil {
+			t.Fatalf("expected a reply at depth %d, got %v", depth, err)
+		}
+	}
+	if _, err := commentService.ReplyToComment(ctx, 1, parent.ID, 2, "", "Jane Smith", "A reply nested too deep"); err != comment.ErrThreadTooDeep {
+		t.Errorf("expected ErrThreadTooDeep, got %v", err)
+	}
+}
+
+func TestCommentService_GetThreadsByPost(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{
+		Pagination: service.PageLimits{Default: 2, Max: 2},
+	}, NewMockLogger())
+	ctx := context.Background()
+
+	var roots []*comment.Comment
+	for i := 1; i <= 3; i++ {
+		root, err := commentService.AddComment(ctx, 1, 0, "", "Author", "Top-level comment")
+		if err != nil {
+			t.Fatalf("failed to create comment %d: %v", i, err)
+		}
+		roots = append(roots, root)
+	}
+	reply, err := commentService.ReplyToComment(ctx, 1, roots[0].ID, 0, "", "Author", "A reply to the first comment")
+	if err != nil {
+		t.Fatalf("failed to create reply: %v", err)
+	}
+	if _, err := commentService.ReplyToComment(ctx, 1, reply.ID, 0, "", "Author", "A nested reply"); err != nil {
+		t.Fatalf("failed to create reply: %v", err)
+	}
+
+	threads, err := commentService.GetThreadsByPost(ctx, 1, 0, "", 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("expected a page of 2 threads, got %d", len(threads))
+	}
+	if threads[0].Comment.ID != roots[0].ID || threads[0].ReplyCount() != 2 {
+		t.Errorf("expected the first thread to hold 2 replies, got %d", threads[0].ReplyCount())
+	}
+
+	threads, err = commentService.GetThreadsByPost(ctx, 1, 0, "", 2, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(threads) != 1 || threads[0].Comment.ID != roots[2].ID {
+		t.Errorf("expected the third thread on the second page, got %v", threads)
+	}
+
+	threads, err = commentService.GetThreadsByPost(ctx, 2, 0, "", 10, 0)
+	if err != nil || len(threads) != 0 {
+		t.Errorf("expected no threads for a post without comments, got %v, %v", threads, err)
+	}
+
+	if _, err := commentService.GetThreadsByPost(ctx, 0, 0, "", 10, 0); err == nil {
+		t.Error("expected an error for an invalid post ID")
+	}
+}
+
+func TestCommentService_RequireModeration(t *testing.T) {
+	repo := NewMockCommentRepository()
+	events := &MockEventPublisher{}
+	commentService := service.NewCommentService(repo, publishedPosts(), events, service.CommentSettings{RequireModeration: true}, NewMockLogger())
+	ctx := context.Background()
+
+	held, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Waiting for review")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	if held.Status != comment.StatusPending {
+		t.Errorf("expected a pending comment, got %q", held.Status)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected pending comments not to be published, got %d", len(events.events))
+	}
+	if comments, _ := commentService.GetCommentsByPost(ctx, 1, 0, "", 10, 0); len(comments) != 0 {
+		t.Errorf("expected pending comments to be hidden, got %d", len(comments))
+	}
+	if _, err := commentService.ReplyToComment(ctx, 1, held.ID, 2, "", "Jane Smith", "A reply to a pending comment"); err != comment.ErrParentNotFound {
+		t.Errorf("expected ErrParentNotFound, got %v", err)
+	}
+
+	queue, err := commentService.ListForModeration(ctx, comment.StatusPending, 10, 0)
+	if err != nil || len(queue) != 1 || queue[0].ID != held.ID {
+		t.Fatalf("expected the comment in the queue, got %v (%v)", queue, err)
+	}
+	if _, err := commentService.ListForModeration(ctx, "hidden", 10, 0); err != comment.ErrInvalidStatus {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+
+	approved, err := commentService.ModerateComment(ctx, held.ID, 9, comment.StatusApproved)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !approved.IsApproved() {
+		t.Errorf("expected an approved comment, got %q", approved.Status)
+	}
+	if added, ok := events.last().(service.CommentAdded); len(events.events) != 1 || !ok || added.Comment.ID != held.ID {
+		t.Errorf("expected the comment to be published once approved, got %v", events.events)
+	}
+	if comments, _ := commentService.GetCommentsByPost(ctx, 1, 0, "", 10, 0); len(comments) != 1 {
+		t.Errorf("expected the approved comment to be listed, got %d", len(comments))
+	}
+
+	// Edits by the author go back to the queue; edits by admins do not
+	edited, err := commentService.UpdateComment(ctx, held.ID, 1, user.RoleReader, "Edited after approval")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if edited.Status != comment.StatusPending {
+		t.Errorf("expected the edited comment to be pending, got %q", edited.Status)
+	}
+	if _, err := commentService.ModerateComment(ctx, held.ID, 9, comment.StatusApproved); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	edited, err = commentService.UpdateComment(ctx, held.ID, 9, user.RoleAdmin, "Edited by an admin")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !edited.IsApproved() {
+		t.Errorf("expected admin edits to keep the comment approved, got %q", edited.Status)
+	}
+
+	if _, err := commentService.ModerateComment(ctx, 999, 9, comment.StatusRejected); err != comment.ErrCommentNotFound {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_TrustLimits(t *testing.T) {
+	repo := NewMockCommentRepository()
+	trust := comment.DefaultTrustPolicy()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{Trust: &trust}, NewMockLogger())
+	ctx := context.Background()
+
+	if _, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "See https://example.com"); err != comment.ErrTooManyLinks {
+		t.Errorf("expected ErrTooManyLinks for a new commenter, got %v", err)
+	}
+	if _, err := commentService.AddComment(ctx, 1, 0, "", "Guest", "See www.example.com"); err != comment.ErrTooManyLinks {
+		t.Errorf("expected guests to be new commenters, got %v", err)
+	}
+
+	first, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "First comment")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "Second comment"); err != comment.ErrCommentCooldown {
+		t.Errorf("expected ErrCommentCooldown, got %v", err)
+	}
+	if _, err := commentService.ReplyToComment(ctx, 1, first.ID, 1, "", "John Doe", "A quick reply"); err != comment.ErrCommentCooldown {
+		t.Errorf("expected ErrCommentCooldown for replies, got %v", err)
+	}
+	if _, err := commentService.UpdateComment(ctx, first.ID, 1, user.RoleReader, "Edited right away"); err != nil {
+		t.Errorf("expected edits to skip the cooldown, got %v", err)
+	}
+
+	// Trusted commenters post links at their own pace
+	repo.levels[1] = comment.TrustTrusted
+	first.CreatedAt = time.Now().Add(-time.Hour)
+	if _, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "See https://example.com"); err != nil {
+		t.Errorf("expected no error for a trusted commenter, got %v", err)
+	}
+	if _, err := commentService.AddComment(ctx, 1, 1, "", "John Doe", "And https://example.org"); err != nil {
+		t.Errorf("expected no cooldown for a trusted commenter, got %v", err)
+	}
+}
+
+func TestCommentService_EvaluateTrustLevels(t *testing.T) {
+	repo := NewMockCommentRepository()
+	trust := comment.DefaultTrustPolicy()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{Trust: &trust}, NewMockLogger())
+	ctx := context.Background()
+
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	repo.histories = []*comment.CommenterHistory{
+		{UserID: 1, Role: user.RoleReader, Level: comment.TrustNew, AccountCreatedAt: longAgo, Approved: 5},
+		{UserID: 2, Role: user.RoleReader, Level: comment.TrustTrusted, AccountCreatedAt: longAgo, Approved: 40, Flagged: 2},
+		{UserID: 3, Role: user.RoleReader, Level: comment.TrustNew, AccountCreatedAt: time.Now(), Approved: 5},
+	}
+
+	changed, err := commentService.EvaluateTrustLevels(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("expected 2 commenters to change level, got %d", changed)
+	}
+	if repo.levels[1] != comment.TrustBasic {
+		t.Errorf("expected commenter 1 to be upgraded to basic, got %q", repo.levels[1])
+	}
+	if repo.levels[2] != comment.TrustNew {
+		t.Errorf("expected flagged commenter 2 to be downgraded to new, got %q", repo.levels[2])
+	}
+	if _, ok := repo.levels[3]; ok {
+		t.Error("expected the level of commenter 3 to be left alone")
+	}
+
+	off := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	if changed, err := off.EvaluateTrustLevels(ctx); err != nil || changed != 0 {
+		t.Errorf("expected nothing to change with trust levels off, got %d, %v", changed, err)
+	}
+}
+
+func TestCommentService_AddClientComment(t *testing.T) {
+	repo := NewMockCommentRepository()
+	commentService := service.NewCommentService(repo, publishedPosts(), &MockEventPublisher{}, service.CommentSettings{}, NewMockLogger())
+	ctx := context.Background()
+	rootID := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	replyID := "9b2d1d46-4c7b-4f0c-8f2e-3a6f0b7d5e11"
+
+	root, created, err := commentService.AddClientComment(ctx, 1, 0, 1, "", rootID, "John Doe", "Written offline")
+	if err != nil || !created {
+		t.Fatalf("expected a new comment, got %v (created %v)", err, created)
+	}
+	reply, created, err := commentService.AddClientComment(ctx, 1, root.ID, 2, "", replyID, "Jane Smith", "A reply written offline")
+	if err != nil || !created {
+		t.Fatalf("expected a new reply, got %v (created %v)", err, created)
+	}
+	if reply.ParentID == nil || *reply.ParentID != root.ID || reply.ClientID == nil || *reply.ClientID != replyID {
+		t.Errorf("expected a reply to comment %d under the client ID, got %+v", root.ID, reply)
+	}
+
+	// A retry returns the comment created the first time
+	again, created, err := commentService.AddClientComment(ctx, 1, 0, 1, "", rootID, "John Doe", "Written offline")
+	if err != nil || created || again.ID != root.ID || len(repo.comments) != 2 {
+		t.Errorf("expected the retry to return comment %d, got %+v, %v (created %v)", root.ID, again, err, created)
+	}
+
+	found, err := commentService.GetCommentByClientID(ctx, replyID)
+	if err != nil || found.ID != reply.ID {
+		t.Errorf("expected comment %d by client ID, got %v, %v", reply.ID, found, err)
+	}
+
+	// The client ID belongs to another commenter, or another post
+	if _, _, err := commentService.AddClientComment(ctx, 1, 0, 3, "", rootID, "Someone Else", "Written offline"); !errors.Is(err, comment.ErrClientIDTaken) {
+		t.Errorf("expected ErrClientIDTaken for another commenter, got %v", err)
+	}
+	if _, _, err := commentService.AddClientComment(ctx, 2, 0, 1, "", rootID, "John Doe", "Written offline"); !errors.Is(err, comment.ErrClientIDTaken) {
+		t.Errorf("expected ErrClientIDTaken on another post, got %v", err)
+	}
+	if _, _, err := commentService.AddClientComment(ctx, 1, 0, 1, "", "42", "John Doe", "Written offline"); !errors.Is(err, clientid.ErrInvalid) {
+		t.Errorf("expected clientid.ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// MockEventPublisher records the published events for testing
+type MockEventPublisher struct {
+	events []service.Event
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event service.Event) {
+	m.events = append(m.events, event)
+}
+
+// last returns the last published event, or nil
+func (m *MockEventPublisher) last() service.Event {
+	if len(m.events) == 0 {
+		return nil
+	}
+	return m.events[len(m.events)-1]
+}
+
+func TestEventBus_DispatchesToSubscribers(t *testing.T) {
+	bus := service.NewEventBus(NewMockLogger())
+	ctx := context.Background()
+
+	var calls []string
+	service.Subscribe(bus, func(ctx context.Context, e service.PostCreated) {
+		calls = append(calls, "first:"+e.Post.Title)
+	})
+	service.Subscribe(bus, func(ctx context.Context, e service.PostCreated) {
+		calls = append(calls, "second:"+e.Post.Title)
+	})
+	service.Subscribe(bus, func(ctx context.Context, e service.PostPublished) {
+		calls = append(calls, "published:"+e.Post.Title)
+	})
+
+	bus.Publish(ctx, service.PostCreated{Post: &post.Post{Title: "Hello"}})
+	bus.Publish(ctx, service.CommentAdded{Comment: &comment.Comment{ID: 1}})
+
+	want := []string{"first:Hello", "second:Hello"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("expected call %q, got %q", want[i], calls[i])
+		}
+	}
+}
+
+func TestEventBus_RecoversFromPanickingHandlers(t *testing.T) {
+	bus := service.NewEventBus(NewMockLogger())
+	ctx := context.Background()
+
+	delivered := false
+	service.Subscribe(bus, func(ctx context.Context, e service.CommentAdded) {
+		panic("broken subscriber")
+	})
+	service.Subscribe(bus, func(ctx context.Context, e service.CommentAdded) {
+		delivered = true
+	})
+
+	bus.Publish(ctx, service.CommentAdded{Comment: &comment.Comment{ID: 1}})
+	if !delivered {
+		t.Error("expected the event delivered to the other subscribers")
+	}
+}
+
+func TestServices_PublishDomainEvents(t *testing.T) {
+	events := &MockEventPublisher{}
+	ctx := context.Background()
+
+	posts := NewMockPostRepository()
+	postService := service.NewPostService(posts, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, events, nil, service.PostSettings{}, NewMockLogger())
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if created, ok := events.last().(service.PostCreated); !ok || created.Post.ID != draft.ID {
+		t.Errorf("expected PostCreated for the draft, got %v", events.last())
+	}
+	if _, err := postService.PublishPost(ctx, 1, user.RoleAuthor, draft.ID); err != nil {
+		t.Fatalf("failed to publish draft: %v", err)
+	}
+	if published, ok := events.last().(service.PostPublished); !ok || published.Post.ID != draft.ID {
+		t.Errorf("expected PostPublished for the draft, got %v", events.last())
+	}
+
+	commentService := service.NewCommentService(NewMockCommentRepository(), posts, events, service.CommentSettings{}, NewMockLogger())
+	c, err := commentService.AddComment(ctx, draft.ID, 2, "", "Reader", "A comment on the post")
+	if err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+	if added, ok := events.last().(service.CommentAdded); !ok || added.Comment.ID != c.ID {
+		t.Errorf("expected CommentAdded, got %v", events.last())
+	}
+
+	users := NewMockUserRepository()
+	userService := service.NewUserService(users, NewMockEmailChangeRepository(users), users, NewMockIdentityRepository(), NewMockMagicLinkRepository(), NewMockLoginAttemptStore(), nil, user.NewEmailNormalizer(user.DefaultCanonicalProviders), &MockMailer{}, &MockAuditLogger{}, &MockSecurityEvents{}, events, service.UserSettings{}, NewMockLogger())
+	u, err := userService.Register(ctx, "New User", "new@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if registered, ok := events.last().(service.UserRegistered); !ok || registered.User.ID != u.ID {
+		t.Errorf("expected UserRegistered, got %v", events.last())
+	}
+	if len(events.events) != 4 {
+		t.Errorf("expected 4 events, got %d", len(events.events))
+	}
+}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/export"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// MockExportRepository implements the export.Repository interface for
+// testing
+type MockExportRepository struct {
+	posts    []*post.Post
+	comments []*comment.Comment
+}
+
+func (m *MockExportRepository) ListPosts(ctx context.Context, authorID int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range m.posts {
+		if p.AuthorID == authorID {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (m *MockExportRepository) ListComments(ctx context.Context, userID int) ([]*comment.Comment, error) {
+	var comments []*comment.Comment
+	for _, c := range m.comments {
+		if c.UserID != nil && *c.UserID == userID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (m *MockExportRepository) ListSitePosts(ctx context.Context, filter export.SiteFilter, afterID, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range m.posts {
+		if p.ID <= afterID || (filter.AuthorID != 0 && p.AuthorID != filter.AuthorID) {
+			continue
+		}
+		if (filter.From != nil && p.CreatedAt.Before(*filter.From)) || (filter.To != nil && p.CreatedAt.After(*filter.To)) {
+			continue
+		}
+		if len(posts) == limit {
+			break
+		}
+		posts = append(posts, p)
+	}
+	return posts, nil
+}
+
+func (m *MockExportRepository) ListPostComments(ctx context.Context, postIDs []int) ([]*comment.Comment, error) {
+	var comments []*comment.Comment
+	for _, c := range m.comments {
+		for _, id := range postIDs {
+			if c.PostID == id {
+				comments = append(comments, c)
+			}
+		}
+	}
+	return comments, nil
+}
+
+func TestExportService_Export(t *testing.T) {
+	ctx := context.Background()
+	users := NewMockUserRepository()
+	users.users[1] = &user.User{ID: 1, Name: "John Doe", Email: "john@example.com", Role: user.RoleAuthor}
+	users.users[2] = &user.User{ID: 2, Name: "Jane Smith", Email: "jane@example.com", Role: user.RoleAuthor}
+	profiles := &MockProfileRepository{profiles: map[int]*user.Profile{
+		1: {ID: 1, Name: "John Doe", Bio: "Writing about Go."},
+	}}
+	authorID := 1
+	repo := &MockExportRepository{
+		posts: []*post.Post{
+			{ID: 1, Title: "Published", AuthorID: 1, Status: post.StatusPublished},
+			{ID: 2, Title: "Draft", AuthorID: 1, Status: post.StatusDraft},
+			{ID: 3, Title: "Someone else's", AuthorID: 2, Status: post.StatusPublished},
+		},
+		comments: []*comment.Comment{
+			{ID: 1, PostID: 3, UserID: &authorID, AuthorName: "John Doe", Status: comment.StatusPending},
+			{ID: 2, PostID: 3, AuthorName: "Guest"},
+		},
+	}
+	audit := &MockAuditLogger{}
+	exportService := service.NewExportService(repo, users, profiles, audit, NewMockLogger())
+
+	archive, err := exportService.Export(ctx, 1)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if archive.Account.Email != "john@example.com" {
+		t.Errorf("expected the account of user 1, got %+v", archive.Account)
+	}
+	if archive.Profile == nil || archive.Profile.Bio != "Writing about Go." {
+		t.Errorf("expected the profile to be exported, got %+v", archive.Profile)
+	}
+	if len(archive.Posts) != 2 || archive.Posts[1].Status != post.StatusDraft {
+		t.Errorf("expected both posts of the user, drafts included, got %d", len(archive.Posts))
+	}
+	if len(archive.Comments) != 1 || archive.Comments[0].ID != 1 {
+		t.Errorf("expected the pending comment of the user, got %d comments", len(archive.Comments))
+	}
+	if archive.ExportedAt.IsZero() {
+		t.Error("expected the export time to be set")
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != service.AuditActionDataExported || audit.events[0].UserID != 1 {
+		t.Errorf("expected the export to be audited, got %+v", audit.events)
+	}
+
+	// Accounts without a public profile still export
+	archive, err = exportService.Export(ctx, 2)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if archive.Profile != nil || len(archive.Posts) != 1 {
+		t.Errorf("expected one post and no profile, got %+v", archive)
+	}
+
+	if _, err := exportService.Export(ctx, 9); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestExportService_ExportSite(t *testing.T) {
+	ctx := context.Background()
+	users := NewMockUserRepository()
+	users.users[1] = &user.User{ID: 1, Name: "John Doe", Email: "john@example.com", Role: user.RoleAuthor}
+	users.users[2] = &user.User{ID: 2, Name: "Jane Smith", Email: "jane@example.com", Role: user.RoleAuthor}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// More posts than fit in a batch, alternating between the authors; the
+	// posts of author 3 outlived the account
+	repo := &MockExportRepository{}
+	for id := 1; id <= 250; id++ {
+		authorID := id%2 + 1
+		if id == 250 {
+			authorID = 3
+		}
+		repo.posts = append(repo.posts, &post.Post{
+			ID:        id,
+			Title:     fmt.Sprintf("Post %d", id),
+			AuthorID:  authorID,
+			Status:    post.StatusPublished,
+			CreatedAt: created.Add(time.Duration(id) * time.Hour),
+		})
+	}
+	repo.comments = []*comment.Comment{
+		{ID: 1, PostID: 1, AuthorName: "Guest", Status: comment.StatusSpam},
+		{ID: 2, PostID: 1, AuthorName: "Guest", Status: comment.StatusApproved},
+		{ID: 3, PostID: 150, AuthorName: "Guest", Status: comment.StatusPending},
+	}
+	audit := &MockAuditLogger{}
+	exportService := service.NewExportService(repo, users, &MockProfileRepository{}, audit, NewMockLogger())
+
+	var entries []*export.Entry
+	collect := func(entry *export.Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}
+
+	if err := exportService.ExportSite(ctx, 9, export.SiteFilter{}, collect); err != nil {
+		t.Fatalf("ExportSite failed: %v", err)
+	}
+	if len(entries) != 250 || entries[0].Post.ID != 1 || entries[249].Post.ID != 250 {
+		t.Fatalf("expected every post in ID order, got %d", len(entries))
+	}
+	if len(entries[0].Comments) != 2 || len(entries[149].Comments) != 1 {
+		t.Errorf("expected the comments of every status with their posts, got %d and %d", len(entries[0].Comments), len(entries[149].Comments))
+	}
+	if entries[0].Author == nil || entries[0].Author.Email != "jane@example.com" {
+		t.Errorf("expected the author of the post, got %+v", entries[0].Author)
+	}
+	if entries[249].Author != nil {
+		t.Errorf("expected no author for a deleted account, got %+v", entries[249].Author)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != service.AuditActionContentExported || audit.events[0].UserID != 9 ||
+		audit.events[0].Metadata["posts"] != 250 || audit.events[0].Metadata["comments"] != 3 {
+		t.Errorf("expected the export to be audited, got %+v", audit.events)
+	}
+
+	// Filters by author and creation time
+	entries = nil
+	from, to := created.Add(10*time.Hour), created.Add(20*time.Hour)
+	if err := exportService.ExportSite(ctx, 9, export.SiteFilter{AuthorID: 1, From: &from, To: &to}, collect); err != nil {
+		t.Fatalf("ExportSite failed: %v", err)
+	}
+	if len(entries) != 6 || entries[0].Post.ID != 10 || entries[5].Post.ID != 20 {
+		t.Errorf("expected the even posts from 10 to 20, got %d", len(entries))
+	}
+
+	// A failed write stops the export, which is not audited
+	writes := 0
+	failure := errors.New("connection closed")
+	err := exportService.ExportSite(ctx, 9, export.SiteFilter{}, func(*export.Entry) error {
+		writes++
+		if writes == 3 {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) || writes != 3 {
+		t.Errorf("expected the export to stop at the failed write, got %v after %d writes", err, writes)
+	}
+	if len(audit.events) != 2 {
+		t.Errorf("expected only completed exports to be audited, got %d events", len(audit.events))
+	}
+
+	if err := exportService.ExportSite(ctx, 9, export.SiteFilter{AuthorID: 7}, collect); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound for an unknown author, got %v", err)
+	}
+	if err := exportService.ExportSite(ctx, 9, export.SiteFilter{From: &to, To: &from}, collect); !errors.Is(err, export.ErrInvalidRange) {
+		t.Errorf("expected ErrInvalidRange, got %v", err)
+	}
+}
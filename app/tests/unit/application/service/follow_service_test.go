@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+)
+
+// MockFollowRepository implements the user.FollowRepository interface for
+// testing
+type MockFollowRepository struct {
+	// follows holds the followee IDs of each follower in follow order
+	follows map[int][]int
+}
+
+func (m *MockFollowRepository) Follow(ctx context.Context, followerID, followeeID int) error {
+	for _, id := range m.follows[followerID] {
+		if id == followeeID {
+			return nil
+		}
+	}
+	m.follows[followerID] = append(m.follows[followerID], followeeID)
+	return nil
+}
+
+func (m *MockFollowRepository) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	var kept []int
+	for _, id := range m.follows[followerID] {
+		if id != followeeID {
+			kept = append(kept, id)
+		}
+	}
+	m.follows[followerID] = kept
+	return nil
+}
+
+func (m *MockFollowRepository) ListFollowers(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, error) {
+	var follows []*user.Follow
+	for follower, followees := range m.follows {
+		for _, id := range followees {
+			if id == userID {
+				follows = append(follows, &user.Follow{UserID: follower, FollowedAt: time.Now()})
+			}
+		}
+	}
+	return follows, nil
+}
+
+func (m *MockFollowRepository) ListFollowing(ctx context.Context, userID int, limit, offset int) ([]*user.Follow, error) {
+	var follows []*user.Follow
+	for _, id := range m.follows[userID] {
+		follows = append(follows, &user.Follow{UserID: id, FollowedAt: time.Now()})
+	}
+	return follows, nil
+}
+
+func (m *MockFollowRepository) Counts(ctx context.Context, userID int) (*user.FollowCounts, error) {
+	followers, _ := m.ListFollowers(ctx, userID, 0, 0)
+	return &user.FollowCounts{Followers: len(followers), Following: len(m.follows[userID])}, nil
+}
+
+func TestFollowService_Follow(t *testing.T) {
+	ctx := context.Background()
+	users := NewMockUserRepository()
+	for _, name := range []string{"Reader", "Author", "Gone", "Off"} {
+		users.Create(ctx, &user.User{Name: name})
+	}
+	now := time.Now()
+	users.users[3].DeletedAt = &now
+	users.users[4].DeactivatedAt = &now
+
+	repo := &MockFollowRepository{follows: make(map[int][]int)}
+	followService := service.NewFollowService(repo, users, &MockEventPublisher{}, NewMockLogger())
+
+	if err := followService.Follow(ctx, 1, 2); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if err := followService.Follow(ctx, 1, 2); err != nil {
+		t.Errorf("expected following again to succeed, got %v", err)
+	}
+	following, counts, err := followService.ListFollowing(ctx, 1, 10, 0)
+	if err != nil {
+		t.Fatalf("ListFollowing failed: %v", err)
+	}
+	if len(following) != 1 || following[0].UserID != 2 || counts.Following != 1 {
+		t.Errorf("expected to follow user 2 once, got %d follows (%+v)", len(following), counts)
+	}
+	if _, counts, _ := followService.ListFollowers(ctx, 2, 10, 0); counts.Followers != 1 {
+		t.Errorf("expected user 2 to have 1 follower, got %+v", counts)
+	}
+
+	if err := followService.Follow(ctx, 1, 1); !errors.Is(err, user.ErrCannotFollowSelf) {
+		t.Errorf("expected ErrCannotFollowSelf, got %v", err)
+	}
+	for _, id := range []int{3, 4, 99} {
+		if err := followService.Follow(ctx, 1, id); !errors.Is(err, user.ErrUserNotFound) {
+			t.Errorf("expected following user %d to fail with ErrUserNotFound, got %v", id, err)
+		}
+	}
+	if _, _, err := followService.ListFollowers(ctx, 3, 10, 0); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected the followers of deleted accounts to be hidden, got %v", err)
+	}
+
+	if err := followService.Unfollow(ctx, 1, 2); err != nil {
+		t.Fatalf("Unfollow failed: %v", err)
+	}
+	if _, counts, _ := followService.ListFollowing(ctx, 1, 10, 0); counts.Following != 0 {
+		t.Errorf("expected no follows left, got %+v", counts)
+	}
+}
@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/idempotency"
+)
+
+// MockIdempotencyRepository implements the idempotency.Repository interface
+// for testing
+type MockIdempotencyRepository struct {
+	records map[int]*idempotency.Record
+	nextID  int
+}
+
+func NewMockIdempotencyRepository() *MockIdempotencyRepository {
+	return &MockIdempotencyRepository{records: make(map[int]*idempotency.Record), nextID: 1}
+}
+
+func (m *MockIdempotencyRepository) Create(ctx context.Context, record *idempotency.Record) error {
+	if _, err := m.Get(ctx, record.Scope, record.Key); err == nil {
+		return idempotency.ErrDuplicateKey
+	}
+	record.ID = m.nextID
+	m.nextID++
+	copied := *record
+	m.records[record.ID] = &copied
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Get(ctx context.Context, scope, key string) (*idempotency.Record, error) {
+	for _, record := range m.records {
+		if record.Scope == scope && record.Key == key {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, idempotency.ErrRecordNotFound
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	stored, ok := m.records[record.ID]
+	if !ok {
+		return idempotency.ErrRecordNotFound
+	}
+	stored.StatusCode, stored.ContentType, stored.Body = record.StatusCode, record.ContentType, record.Body
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.records[id]; !ok {
+		return idempotency.ErrRecordNotFound
+	}
+	delete(m.records, id)
+	return nil
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	for id, record := range m.records {
+		if record.ExpiresAt.Before(before) {
+			delete(m.records, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newTestIdempotencyService(repo *MockIdempotencyRepository) *service.IdempotencyService {
+	return service.NewIdempotencyService(repo, service.IdempotencySettings{TTL: time.Hour, LockTimeout: time.Minute}, NewMockLogger())
+}
+
+func TestIdempotencyService_BeginAndReplay(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockIdempotencyRepository()
+	idempotencyService := newTestIdempotencyService(repo)
+
+	record, replay, err := idempotencyService.Begin(ctx, "user:1", "key-1", "hash-a")
+	if err != nil || replay {
+		t.Fatalf("expected a new record, got %v, %v", replay, err)
+	}
+	if _, _, err := idempotencyService.Begin(ctx, "user:1", "key-1", "hash-a"); !errors.Is(err, idempotency.ErrKeyInUse) {
+		t.Errorf("expected ErrKeyInUse while the first request is processed, got %v", err)
+	}
+
+	if err := idempotencyService.Complete(ctx, record, 201, "application/json", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	replayed, replay, err := idempotencyService.Begin(ctx, "user:1", "key-1", "hash-a")
+	if err != nil || !replay {
+		t.Fatalf("expected a replay, got %v, %v", replay, err)
+	}
+	if replayed.StatusCode != 201 || string(replayed.Body) != `{"id":1}` {
+		t.Errorf("expected the stored response, got %d %q", replayed.StatusCode, replayed.Body)
+	}
+
+	if _, _, err := idempotencyService.Begin(ctx, "user:1", "key-1", "hash-b"); !errors.Is(err, idempotency.ErrKeyReused) {
+		t.Errorf("expected ErrKeyReused for a different request, got %v", err)
+	}
+	if _, replay, err := idempotencyService.Begin(ctx, "user:2", "key-1", "hash-b"); err != nil || replay {
+		t.Errorf("expected keys to be scoped, got %v, %v", replay, err)
+	}
+}
+
+func TestIdempotencyService_InvalidKey(t *testing.T) {
+	idempotencyService := newTestIdempotencyService(NewMockIdempotencyRepository())
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"empty", ""},
+		{"too long", string(make([]byte, idempotency.MaxKeyLength+1))},
+		{"control character", "key\n1"},
+		{"non-ASCII", "clé"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := idempotencyService.Begin(context.Background(), "user:1", tt.key, "hash"); !errors.Is(err, idempotency.ErrInvalidKey) {
+				t.Errorf("expected ErrInvalidKey, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_ReleaseAndTakeOver(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockIdempotencyRepository()
+	idempotencyService := newTestIdempotencyService(repo)
+
+	record, _, _ := idempotencyService.Begin(ctx, "ip:192.0.2.1", "key-1", "hash-a")
+	if err := idempotencyService.Release(ctx, record); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, replay, err := idempotencyService.Begin(ctx, "ip:192.0.2.1", "key-1", "hash-b"); err != nil || replay {
+		t.Errorf("expected a released key to be claimed again, got %v, %v", replay, err)
+	}
+
+	// A key held past the lock timeout is taken over
+	stale, _, _ := idempotencyService.Begin(ctx, "user:1", "key-2", "hash-a")
+	repo.records[stale.ID].CreatedAt = time.Now().Add(-2 * time.Minute)
+	if _, replay, err := idempotencyService.Begin(ctx, "user:1", "key-2", "hash-a"); err != nil || replay {
+		t.Errorf("expected the stale key to be taken over, got %v, %v", replay, err)
+	}
+
+	// An expired response is not replayed
+	done, _, _ := idempotencyService.Begin(ctx, "user:1", "key-3", "hash-a")
+	idempotencyService.Complete(ctx, done, 201, "application/json", []byte(`{}`))
+	repo.records[done.ID].ExpiresAt = time.Now().Add(-time.Second)
+	if _, replay, err := idempotencyService.Begin(ctx, "user:1", "key-3", "hash-b"); err != nil || replay {
+		t.Errorf("expected the expired key to be claimed again, got %v, %v", replay, err)
+	}
+}
+
+func TestIdempotencyService_DeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockIdempotencyRepository()
+	idempotencyService := newTestIdempotencyService(repo)
+
+	expired, _, _ := idempotencyService.Begin(ctx, "user:1", "key-1", "hash")
+	repo.records[expired.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	idempotencyService.Begin(ctx, "user:1", "key-2", "hash")
+
+	deleted, err := idempotencyService.DeleteExpired(ctx)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 expired key deleted, got %d, %v", deleted, err)
+	}
+	if len(repo.records) != 1 {
+		t.Errorf("expected the live key to remain, got %d keys", len(repo.records))
+	}
+}
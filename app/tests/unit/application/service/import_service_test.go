@@ -0,0 +1,298 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/domain/post"
+)
+
+// MockImportRepository implements imports.Repository for testing
+type MockImportRepository struct {
+	imports map[int]*imports.Import
+	nextID  int
+}
+
+func NewMockImportRepository() *MockImportRepository {
+	return &MockImportRepository{imports: make(map[int]*imports.Import), nextID: 1}
+}
+
+func (m *MockImportRepository) Create(ctx context.Context, i *imports.Import) error {
+	i.ID = m.nextID
+	m.nextID++
+	stored := *i
+	m.imports[i.ID] = &stored
+	return nil
+}
+
+func (m *MockImportRepository) GetByID(ctx context.Context, id int) (*imports.Import, error) {
+	i, ok := m.imports[id]
+	if !ok {
+		return nil, imports.ErrImportNotFound
+	}
+	copied := *i
+	copied.Errors = append([]string{}, i.Errors...)
+	return &copied, nil
+}
+
+func (m *MockImportRepository) Update(ctx context.Context, i *imports.Import) error {
+	stored := *i
+	m.imports[i.ID] = &stored
+	return nil
+}
+
+// MockImportParser returns its document for any file
+type MockImportParser struct {
+	doc *imports.Document
+	err error
+}
+
+func (m *MockImportParser) Parse(data []byte) (*imports.Document, error) {
+	return m.doc, m.err
+}
+
+type importTestDeps struct {
+	repo     *MockImportRepository
+	users    *MockUserService
+	posts    *MockPostRepository
+	comments *MockCommentRepository
+	jobs     *MockJobQueue
+	audit    *MockAuditLogger
+}
+
+func newTestImportService(doc *imports.Document) (*service.ImportService, *importTestDeps) {
+	deps := &importTestDeps{
+		repo:     NewMockImportRepository(),
+		users:    NewMockUserService(),
+		posts:    NewMockPostRepository(),
+		comments: NewMockCommentRepository(),
+		jobs:     &MockJobQueue{},
+		audit:    &MockAuditLogger{},
+	}
+	svc := service.NewImportService(deps.repo, &MockImportParser{doc: doc}, deps.users, deps.posts, deps.comments, &MockTxManager{}, deps.jobs, deps.audit, NewMockLogger())
+	return svc, deps
+}
+
+// runImport runs queued import jobs until none is left
+func runImport(t *testing.T, svc *service.ImportService, jobs *MockJobQueue) {
+	t.Helper()
+	for len(jobs.payloads) > 0 {
+		payload, err := json.Marshal(jobs.payloads[0])
+		require.NoError(t, err)
+		jobs.kinds, jobs.payloads = jobs.kinds[1:], jobs.payloads[1:]
+		require.NoError(t, svc.Run(context.Background(), payload))
+	}
+}
+
+func wordPressDocument() *imports.Document {
+	published := time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC)
+	return &imports.Document{
+		Source: imports.SourceWordPress,
+		Authors: []imports.Author{
+			{Login: "jane", Name: "Jane Doe", Email: "jane@example.com"},
+			{Login: "bob", Name: "Bob", Email: "bob@example.com"},
+			{Login: "ghost"},
+		},
+		Posts: []imports.Post{
+			{
+				Title:       "Hello World",
+				Slug:        "hello-world",
+				Content:     "The first post.",
+				AuthorLogin: "jane",
+				PublishedAt: &published,
+				Tags:        []string{"News", "news", "C++"},
+				Comments: []imports.Comment{
+					{ID: "3", ParentID: "2", AuthorName: "Carol", Content: "A reply to a reply.", Approved: true},
+					{ID: "1", AuthorName: "Alice", Content: "Nice post!", Approved: true},
+					{ID: "2", ParentID: "1", AuthorName: "Bob", AuthorLogin: "bob", Content: "Thanks!", Approved: true},
+					{ID: "4", AuthorName: "Spammer", Content: "Buy now", Spam: true},
+					{ID: "5", AuthorName: "", Content: "Pending question", Approved: false},
+				},
+			},
+			{Title: "Draft notes", Content: "Not ready yet.", AuthorLogin: "ghost", Draft: true},
+			{Title: "", Content: "A post without a title."},
+		},
+	}
+}
+
+func TestImportService_StartQueuesImport(t *testing.T) {
+	svc, deps := newTestImportService(wordPressDocument())
+
+	i, err := svc.Start(context.Background(), 99, []byte("<rss/>"), false)
+	require.NoError(t, err)
+	assert.Equal(t, imports.StatusQueued, i.Status)
+	assert.Equal(t, 3, i.Total)
+	assert.Equal(t, []string{service.JobContentImport}, deps.jobs.kinds)
+	assert.Equal(t, service.ContentImportPayload{ImportID: i.ID}, deps.jobs.payloads[0])
+
+	got, err := svc.Get(context.Background(), i.ID)
+	require.NoError(t, err)
+	assert.Equal(t, imports.SourceWordPress, got.Source)
+
+	_, err = svc.Get(context.Background(), 42)
+	assert.ErrorIs(t, err, imports.ErrImportNotFound)
+}
+
+func TestImportService_StartRejectsInvalidFiles(t *testing.T) {
+	deps := NewMockImportRepository()
+	jobs := &MockJobQueue{}
+	parser := &MockImportParser{err: imports.ErrUnsupportedFile}
+	svc := service.NewImportService(deps, parser, NewMockUserService(), NewMockPostRepository(), NewMockCommentRepository(), &MockTxManager{}, jobs, &MockAuditLogger{}, NewMockLogger())
+
+	_, err := svc.Start(context.Background(), 99, []byte("hello"), false)
+	assert.ErrorIs(t, err, imports.ErrInvalidFile)
+
+	parser.err = nil
+	parser.doc = &imports.Document{Source: imports.SourceMarkdown}
+	_, err = svc.Start(context.Background(), 99, []byte("PK"), false)
+	assert.ErrorIs(t, err, imports.ErrNoPosts)
+	assert.Empty(t, deps.imports)
+	assert.Empty(t, jobs.kinds)
+}
+
+func TestImportService_Run(t *testing.T) {
+	svc, deps := newTestImportService(wordPressDocument())
+	bob, _ := deps.users.Register(context.Background(), "Bob", "bob@example.com", "password")
+
+	i, err := svc.Start(context.Background(), 99, nil, false)
+	require.NoError(t, err)
+	runImport(t, svc, deps.jobs)
+
+	got, _ := svc.Get(context.Background(), i.ID)
+	assert.Equal(t, imports.StatusCompleted, got.Status)
+	assert.NotNil(t, got.CompletedAt)
+	assert.Equal(t, 3, got.Processed)
+	assert.Equal(t, 1, got.UsersCreated, "only jane has no account yet")
+	assert.Equal(t, 2, got.PostsCreated)
+	assert.Equal(t, 5, got.CommentsCreated)
+	assert.Equal(t, 1, got.Failed)
+	require.Len(t, got.Errors, 1)
+	assert.Contains(t, got.Errors[0], "post 3")
+
+	jane, err := deps.users.GetByEmail(context.Background(), "jane@example.com")
+	require.NoError(t, err)
+	hello, err := deps.posts.GetBySlug(context.Background(), "hello-world")
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, hello.AuthorID)
+	assert.Equal(t, post.StatusPublished, hello.Status)
+	assert.Equal(t, 2019, hello.PublishedAt.Year(), "the publication time is kept")
+	assert.Equal(t, []string{"news"}, hello.Tags, "invalid and duplicate tags are dropped")
+
+	draft, err := deps.posts.GetBySlug(context.Background(), "draft-notes")
+	require.NoError(t, err)
+	assert.Equal(t, 99, draft.AuthorID, "authors without an email are credited to the admin")
+	assert.Equal(t, post.StatusDraft, draft.Status)
+
+	byAuthor := make(map[string]*comment.Comment)
+	for _, c := range deps.comments.comments {
+		byAuthor[c.AuthorName] = c
+	}
+	require.Len(t, byAuthor, 5)
+	assert.Nil(t, byAuthor["Alice"].ParentID)
+	assert.Equal(t, byAuthor["Alice"].ID, *byAuthor["Bob"].ParentID)
+	assert.Equal(t, byAuthor["Bob"].ID, *byAuthor["Carol"].ParentID, "replies exported before their parent are threaded")
+	assert.True(t, byAuthor["Bob"].IsPostedBy(bob.ID), "comments by document authors are linked to their account")
+	assert.Equal(t, comment.StatusSpam, byAuthor["Spammer"].Status)
+	assert.Equal(t, comment.StatusPending, byAuthor["Anonymous"].Status)
+
+	require.Len(t, deps.audit.events, 1)
+	assert.Equal(t, service.AuditActionContentImported, deps.audit.events[0].Action)
+	assert.Equal(t, 99, deps.audit.events[0].UserID)
+
+	// Importing the same export again skips the posts already there
+	again, err := svc.Start(context.Background(), 99, nil, false)
+	require.NoError(t, err)
+	runImport(t, svc, deps.jobs)
+	got, _ = svc.Get(context.Background(), again.ID)
+	assert.Equal(t, 2, got.Skipped)
+	assert.Zero(t, got.PostsCreated)
+	assert.Zero(t, got.UsersCreated)
+	assert.Len(t, deps.posts.posts, 2)
+}
+
+func TestImportService_DryRun(t *testing.T) {
+	svc, deps := newTestImportService(wordPressDocument())
+
+	i, err := svc.Start(context.Background(), 99, nil, true)
+	require.NoError(t, err)
+	runImport(t, svc, deps.jobs)
+
+	got, _ := svc.Get(context.Background(), i.ID)
+	assert.True(t, got.DryRun)
+	assert.Equal(t, imports.StatusCompleted, got.Status)
+	assert.Equal(t, 2, got.UsersCreated)
+	assert.Equal(t, 2, got.PostsCreated)
+	assert.Equal(t, 5, got.CommentsCreated)
+	assert.Equal(t, 1, got.Failed)
+
+	assert.Empty(t, deps.users.users)
+	assert.Empty(t, deps.posts.posts)
+	assert.Empty(t, deps.comments.comments)
+	assert.Empty(t, deps.audit.events)
+}
+
+func TestImportService_RunInBatches(t *testing.T) {
+	doc := &imports.Document{Source: imports.SourceMarkdown}
+	for n := 1; n <= 60; n++ {
+		doc.Posts = append(doc.Posts, imports.Post{Title: fmt.Sprintf("Post %d", n), Content: "Imported content."})
+	}
+	svc, deps := newTestImportService(doc)
+
+	i, err := svc.Start(context.Background(), 99, nil, false)
+	require.NoError(t, err)
+
+	payload, _ := json.Marshal(deps.jobs.payloads[0])
+	deps.jobs.kinds, deps.jobs.payloads = nil, nil
+	require.NoError(t, svc.Run(context.Background(), payload))
+
+	got, _ := svc.Get(context.Background(), i.ID)
+	assert.Equal(t, imports.StatusRunning, got.Status)
+	assert.Equal(t, 25, got.Processed)
+	require.Equal(t, []string{service.JobContentImport}, deps.jobs.kinds, "the next batch is queued")
+
+	runImport(t, svc, deps.jobs)
+	got, _ = svc.Get(context.Background(), i.ID)
+	assert.Equal(t, imports.StatusCompleted, got.Status)
+	assert.Equal(t, 60, got.PostsCreated)
+	assert.Len(t, deps.posts.posts, 60)
+
+	// A completed import is not run again
+	require.NoError(t, svc.Run(context.Background(), payload))
+	assert.Len(t, deps.posts.posts, 60)
+}
+
+func TestImportService_DeepRepliesAreFlattened(t *testing.T) {
+	doc := &imports.Document{
+		Source: imports.SourceWordPress,
+		Posts: []imports.Post{{
+			Title:   "Long thread",
+			Content: "A post with a long discussion.",
+		}},
+	}
+	for n := 1; n <= comment.MaxDepth+2; n++ {
+		c := imports.Comment{ID: fmt.Sprint(n), AuthorName: fmt.Sprintf("User %d", n), Content: "Reply", Approved: true}
+		if n > 1 {
+			c.ParentID = fmt.Sprint(n - 1)
+		}
+		doc.Posts[0].Comments = append(doc.Posts[0].Comments, c)
+	}
+	svc, deps := newTestImportService(doc)
+
+	_, err := svc.Start(context.Background(), 99, nil, false)
+	require.NoError(t, err)
+	runImport(t, svc, deps.jobs)
+
+	require.Len(t, deps.comments.comments, comment.MaxDepth+2)
+	for _, c := range deps.comments.comments {
+		assert.LessOrEqual(t, c.Depth, comment.MaxDepth)
+	}
+}
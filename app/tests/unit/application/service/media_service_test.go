@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/media"
+)
+
+// MockMediaRepository implements the media.Repository interface for
+// testing; media whose key is in referenced count as used by a post
+type MockMediaRepository struct {
+	media      map[int]*media.Media
+	referenced map[string]bool
+	nextID     int
+}
+
+func NewMockMediaRepository() *MockMediaRepository {
+	return &MockMediaRepository{
+		media:      make(map[int]*media.Media),
+		referenced: make(map[string]bool),
+		nextID:     1,
+	}
+}
+
+func (m *MockMediaRepository) Create(ctx context.Context, item *media.Media) error {
+	item.ID = m.nextID
+	m.nextID++
+	m.media[item.ID] = item
+	return nil
+}
+
+func (m *MockMediaRepository) UsageByUser(ctx context.Context, userID int) (int64, error) {
+	var used int64
+	for _, item := range m.media {
+		if item.UserID == userID {
+			used += item.Size
+		}
+	}
+	return used, nil
+}
+
+func (m *MockMediaRepository) ListOrphans(ctx context.Context, before time.Time, limit int) ([]*media.Media, error) {
+	var orphans []*media.Media
+	for id := 1; id < m.nextID && len(orphans) < limit; id++ {
+		item, ok := m.media[id]
+		if ok && item.CreatedAt.Before(before) && !m.referenced[item.Key] {
+			orphans = append(orphans, item)
+		}
+	}
+	return orphans, nil
+}
+
+func (m *MockMediaRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.media[id]; !ok {
+		return media.ErrMediaNotFound
+	}
+	delete(m.media, id)
+	return nil
+}
+
+func TestMediaService_Upload(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockMediaRepository()
+	storage := NewMockObjectStorage()
+	mediaService := service.NewMediaService(repo, storage, nil, service.MediaSettings{}, NewMockLogger())
+	content := pngImage(t, 40, 30, color.Black)
+
+	m, err := mediaService.Upload(ctx, 1, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if m.ContentType != "image/png" || m.Width != 40 || m.Height != 30 || m.Size != int64(len(content)) {
+		t.Errorf("expected a 40x30 PNG of %d bytes, got %+v", len(content), m)
+	}
+	if !strings.HasPrefix(m.Key, "media/1/") || !strings.HasSuffix(m.Key, ".png") {
+		t.Errorf("expected the key to be below media/1/ with the PNG extension, got %s", m.Key)
+	}
+	if m.URL != "https://cdn.example.com/"+m.Key {
+		t.Errorf("expected the storage URL, got %s", m.URL)
+	}
+	if !bytes.Equal(storage.objects[m.Key], content) {
+		t.Error("expected the file to be stored as uploaded")
+	}
+	if repo.media[m.ID] == nil || repo.media[m.ID].UserID != 1 {
+		t.Errorf("expected the upload to be recorded for its owner, got %+v", repo.media[m.ID])
+	}
+
+	again, err := mediaService.Upload(ctx, 1, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if again.Key == m.Key {
+		t.Error("expected each upload to get a new key")
+	}
+
+	if _, err := mediaService.Upload(ctx, 1, strings.NewReader("not an image")); !errors.Is(err, media.ErrInvalidMedia) {
+		t.Errorf("expected ErrInvalidMedia, got %v", err)
+	}
+	// A GIF header claiming a 65535x65535 image
+	huge := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	if _, err := mediaService.Upload(ctx, 1, bytes.NewReader(huge)); !errors.Is(err, media.ErrMediaTooLarge) {
+		t.Errorf("expected ErrMediaTooLarge, got %v", err)
+	}
+	if len(storage.objects) != 2 {
+		t.Errorf("expected rejected uploads not to be stored, got %d objects", len(storage.objects))
+	}
+}
+
+func TestMediaService_Upload_Quota(t *testing.T) {
+	ctx := context.Background()
+	content := pngImage(t, 10, 10, color.White)
+
+	// Without billing the configured quota applies
+	repo := NewMockMediaRepository()
+	storage := NewMockObjectStorage()
+	settings := service.MediaSettings{Quota: int64(len(content))*2 + 1}
+	mediaService := service.NewMediaService(repo, storage, nil, settings, NewMockLogger())
+	for i := 0; i < 2; i++ {
+		if _, err := mediaService.Upload(ctx, 1, bytes.NewReader(content)); err != nil {
+			t.Fatalf("expected upload %d to fit the quota, got %v", i+1, err)
+		}
+	}
+	if _, err := mediaService.Upload(ctx, 1, bytes.NewReader(content)); !errors.Is(err, media.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := mediaService.Upload(ctx, 2, bytes.NewReader(content)); err != nil {
+		t.Errorf("expected the quota to be per user, got %v", err)
+	}
+
+	// With billing the plan sets the quota
+	repo = NewMockMediaRepository()
+	repo.media[1] = &media.Media{ID: 1, UserID: 1, Key: "media/1/old.png", Size: billing.LimitsOf(billing.PlanFree).UploadQuota}
+	repo.nextID = 2
+	plans := service.NewBillingService(NewMockBillingRepository(), &MockAuditLogger{}, NewMockLogger())
+	mediaService = service.NewMediaService(repo, NewMockObjectStorage(), plans, settings, NewMockLogger())
+	if _, err := mediaService.Upload(ctx, 1, bytes.NewReader(content)); !errors.Is(err, billing.ErrUploadQuotaExceeded) {
+		t.Errorf("expected ErrUploadQuotaExceeded, got %v", err)
+	}
+}
+
+func TestMediaService_CollectOrphans(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockMediaRepository()
+	storage := NewMockObjectStorage()
+	mediaService := service.NewMediaService(repo, storage, nil, service.MediaSettings{OrphanAge: time.Hour}, NewMockLogger())
+
+	old := time.Now().Add(-2 * time.Hour)
+	for i, key := range []string{"media/1/used.png", "media/1/unused.png", "media/2/fresh.png"} {
+		storage.objects[key] = []byte("image")
+		repo.media[i+1] = &media.Media{ID: i + 1, UserID: 1, Key: key, CreatedAt: old}
+	}
+	repo.nextID = 4
+	repo.media[3].CreatedAt = time.Now()
+	repo.referenced["media/1/used.png"] = true
+
+	collected, err := mediaService.CollectOrphans(ctx)
+	if err != nil {
+		t.Fatalf("CollectOrphans failed: %v", err)
+	}
+	if collected != 1 {
+		t.Errorf("expected 1 orphan to be collected, got %d", collected)
+	}
+	if _, ok := storage.objects["media/1/unused.png"]; ok || repo.media[2] != nil {
+		t.Error("expected the unreferenced upload to be deleted")
+	}
+	if _, ok := storage.objects["media/1/used.png"]; !ok || repo.media[1] == nil {
+		t.Error("expected uploads referenced by posts to be kept")
+	}
+	if _, ok := storage.objects["media/2/fresh.png"]; !ok || repo.media[3] == nil {
+		t.Error("expected recent uploads to be kept")
+	}
+}
@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/domain/post"
+)
+
+// MockNotificationRepository implements the notification.Repository
+// interface for testing
+type MockNotificationRepository struct {
+	notifications []*notification.Notification
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	n.ID = len(m.notifications) + 1
+	m.notifications = append(m.notifications, n)
+	return nil
+}
+
+func (m *MockNotificationRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*notification.Notification, error) {
+	var notifications []*notification.Notification
+	for i := len(m.notifications) - 1; i >= 0; i-- {
+		if m.notifications[i].UserID == userID {
+			notifications = append(notifications, m.notifications[i])
+		}
+	}
+	return notifications, nil
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	count := 0
+	for _, n := range m.notifications {
+		if n.UserID == userID && !n.IsRead() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, id, userID int, readAt time.Time) error {
+	for _, n := range m.notifications {
+		if n.ID == id && n.UserID == userID {
+			if n.ReadAt == nil {
+				n.ReadAt = &readAt
+			}
+			return nil
+		}
+	}
+	return notification.ErrNotificationNotFound
+}
+
+// recipients returns the kind of notification each user got
+func (m *MockNotificationRepository) recipients() map[int]notification.Kind {
+	recipients := make(map[int]notification.Kind)
+	for _, n := range m.notifications {
+		recipients[n.UserID] = n.Kind
+	}
+	return recipients
+}
+
+func TestNotificationService_CommentPublished(t *testing.T) {
+	ctx := context.Background()
+	posts := NewMockPostRepository()
+	p, _ := post.NewPost("Notified Post", "Content of the notified post", 1)
+	posts.Create(ctx, p)
+
+	repo := &MockNotificationRepository{}
+	comments := NewMockCommentRepository()
+	notificationService := service.NewNotificationService(repo, posts, comments, &MockEventPublisher{}, NewMockLogger())
+	bus := service.NewEventBus(NewMockLogger())
+	notificationService.Subscribe(bus)
+	commentService := service.NewCommentService(comments, posts, bus, service.CommentSettings{}, NewMockLogger())
+
+	// A comment notifies the post author
+	top, err := commentService.AddComment(ctx, p.ID, 2, "", "Reader Two", "First comment on the post")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if got := repo.recipients(); len(got) != 1 || got[1] != notification.KindComment {
+		t.Fatalf("expected the post author to be notified of the comment, got %v", got)
+	}
+	if n := repo.notifications[0]; n.PostID != p.ID || n.CommentID != top.ID || n.ActorName != "Reader Two" {
+		t.Errorf("unexpected notification %+v", n)
+	}
+
+	// A reply notifies the writer of the parent comment and the post author
+	repo.notifications = nil
+	reply, err := commentService.ReplyToComment(ctx, p.ID, top.ID, 3, "", "Reader Three", "A reply to the first comment")
+	if err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if got := repo.recipients(); len(got) != 2 || got[2] != notification.KindReply || got[1] != notification.KindComment {
+		t.Errorf("expected a reply and a comment notification, got %v", got)
+	}
+
+	// The post author replying is notified of nothing; the author replied
+	// to is notified once, of the reply
+	repo.notifications = nil
+	if _, err := commentService.ReplyToComment(ctx, p.ID, top.ID, 1, "", "Author", "The author answers"); err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if got := repo.recipients(); len(got) != 1 || got[2] != notification.KindReply {
+		t.Errorf("expected only the parent writer to be notified, got %v", got)
+	}
+	repo.notifications = nil
+	if _, err := commentService.ReplyToComment(ctx, p.ID, reply.ID, 1, "", "Author", "Thanks for reading"); err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if got := repo.recipients(); len(got) != 1 || got[3] != notification.KindReply {
+		t.Errorf("expected the reply writer to be notified, got %v", got)
+	}
+
+	// Guest comments have nobody to notify when replied to
+	guest := &comment.Comment{PostID: p.ID, AuthorName: "Guest", Content: "Guest comment", Status: comment.StatusApproved}
+	comments.Create(ctx, guest)
+	repo.notifications = nil
+	if _, err := commentService.ReplyToComment(ctx, p.ID, guest.ID, 1, "", "Author", "Welcome"); err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if len(repo.notifications) != 0 {
+		t.Errorf("expected no notifications, got %v", repo.recipients())
+	}
+}
+
+func TestNotificationService_ListAndMarkRead(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockNotificationRepository{}
+	notificationService := service.NewNotificationService(repo, NewMockPostRepository(), NewMockCommentRepository(), &MockEventPublisher{}, NewMockLogger())
+
+	repo.Create(ctx, notification.NewNotification(1, notification.KindComment, 1, 1, "Reader"))
+	repo.Create(ctx, notification.NewNotification(1, notification.KindReply, 1, 2, "Reader"))
+	repo.Create(ctx, notification.NewNotification(2, notification.KindReply, 1, 3, "Reader"))
+
+	list, unread, err := notificationService.List(ctx, 1, 10, 0)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 || unread != 2 || list[0].ID != 2 {
+		t.Errorf("expected 2 unread notifications newest first, got %d (%d unread)", len(list), unread)
+	}
+
+	if err := notificationService.MarkRead(ctx, 1, 1); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	if err := notificationService.MarkRead(ctx, 1, 1); err != nil {
+		t.Errorf("expected marking read again to succeed, got %v", err)
+	}
+	if _, unread, _ := notificationService.List(ctx, 1, 10, 0); unread != 1 {
+		t.Errorf("expected 1 unread notification, got %d", unread)
+	}
+	if err := notificationService.MarkRead(ctx, 3, 1); !errors.Is(err, notification.ErrNotificationNotFound) {
+		t.Errorf("expected notifications of other users to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"blog-platform/internal/application/service"
+)
+
+func TestPageLimits_Normalize(t *testing.T) {
+	limits := service.PageLimits{Default: 20, Max: 50}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		wantLimit     int
+		wantOffset    int
+	}{
+		{"missing limit uses default", 0, 0, 20, 0},
+		{"negative limit uses default", -5, 10, 20, 10},
+		{"limit within bounds", 30, 5, 30, 5},
+		{"limit above maximum is capped", 500, 0, 50, 0},
+		{"negative offset starts at zero", 10, -3, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset := limits.Normalize(tt.limit, tt.offset)
+			assert.Equal(t, tt.wantLimit, limit)
+			assert.Equal(t, tt.wantOffset, offset)
+		})
+	}
+}
+
+func TestPageLimits_Normalize_Unset(t *testing.T) {
+	limit, _ := service.PageLimits{}.Normalize(0, 0)
+	assert.Equal(t, 10, limit, "unset limits use the built-in default")
+
+	limit, _ = service.PageLimits{}.Normalize(1000, 0)
+	assert.Equal(t, 100, limit, "unset limits use the built-in maximum")
+
+	limit, _ = service.PageLimits{Default: 500, Max: 25}.Normalize(0, 0)
+	assert.Equal(t, 25, limit, "the default never exceeds the maximum")
+}
+
+func TestDefaultPaginationPolicy(t *testing.T) {
+	policy := service.DefaultPaginationPolicy()
+
+	for _, limits := range []service.PageLimits{policy.Posts, policy.Comments, policy.Users} {
+		assert.Equal(t, service.PageLimits{Default: 10, Max: 100}, limits)
+	}
+}
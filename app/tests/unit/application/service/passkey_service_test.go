@@ -0,0 +1,345 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+)
+
+var passkeyRP = auth.RelyingParty{ID: "blog.example.com", Name: "Blog", Origins: []string{"https://blog.example.com"}}
+
+// MockPasskeyRepository implements auth.PasskeyRepository for testing
+type MockPasskeyRepository struct {
+	passkeys map[int]*auth.Passkey
+	nextID   int
+}
+
+func NewMockPasskeyRepository() *MockPasskeyRepository {
+	return &MockPasskeyRepository{passkeys: make(map[int]*auth.Passkey), nextID: 1}
+}
+
+func (m *MockPasskeyRepository) Create(ctx context.Context, p *auth.Passkey) error {
+	for _, existing := range m.passkeys {
+		if bytes.Equal(existing.CredentialID, p.CredentialID) {
+			return auth.ErrPasskeyExists
+		}
+	}
+	p.ID = m.nextID
+	m.nextID++
+	m.passkeys[p.ID] = p
+	return nil
+}
+
+func (m *MockPasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*auth.Passkey, error) {
+	for _, p := range m.passkeys {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, auth.ErrPasskeyNotFound
+}
+
+func (m *MockPasskeyRepository) ListByUser(ctx context.Context, userID int) ([]*auth.Passkey, error) {
+	var result []*auth.Passkey
+	for id := 1; id < m.nextID; id++ {
+		if p, ok := m.passkeys[id]; ok && p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPasskeyRepository) UpdateUsage(ctx context.Context, p *auth.Passkey) error {
+	stored, ok := m.passkeys[p.ID]
+	if !ok {
+		return auth.ErrPasskeyNotFound
+	}
+	stored.SignCount = p.SignCount
+	stored.LastUsedAt = p.LastUsedAt
+	return nil
+}
+
+func (m *MockPasskeyRepository) Delete(ctx context.Context, userID, id int) error {
+	if p, ok := m.passkeys[id]; !ok || p.UserID != userID {
+		return auth.ErrPasskeyNotFound
+	}
+	delete(m.passkeys, id)
+	return nil
+}
+
+// MockPasskeyChallengeStore implements auth.PasskeyChallengeStore for testing
+type MockPasskeyChallengeStore struct {
+	sessions map[string]*auth.PasskeySession
+}
+
+func NewMockPasskeyChallengeStore() *MockPasskeyChallengeStore {
+	return &MockPasskeyChallengeStore{sessions: make(map[string]*auth.PasskeySession)}
+}
+
+func (m *MockPasskeyChallengeStore) Save(ctx context.Context, challenge string, session *auth.PasskeySession, ttl time.Duration) error {
+	m.sessions[challenge] = session
+	return nil
+}
+
+func (m *MockPasskeyChallengeStore) Take(ctx context.Context, challenge string) (*auth.PasskeySession, error) {
+	session, ok := m.sessions[challenge]
+	if !ok {
+		return nil, auth.ErrInvalidPasskeyChallenge
+	}
+	delete(m.sessions, challenge)
+	return session, nil
+}
+
+// virtualAuthenticator is an ES256 authenticator holding one passkey
+type virtualAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newVirtualAuthenticator(t *testing.T, credentialID string) *virtualAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &virtualAuthenticator{t: t, key: key, credentialID: []byte(credentialID)}
+}
+
+func (a *virtualAuthenticator) clientData(ceremonyType string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    passkeyRP.Origins[0],
+	})
+	require.NoError(a.t, err)
+	return data
+}
+
+func (a *virtualAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(passkeyRP.ID))
+	data := append(rpIDHash[:], flags|0x01|0x04)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+// create answers creation options with a new credential
+func (a *virtualAuthenticator) create(options *auth.CreationOptions) *auth.AttestationResponse {
+	a.userHandle = options.User.ID
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.PublicKey.X.FillBytes(x)
+	a.key.PublicKey.Y.FillBytes(y)
+	coseKey, err := cbor.Marshal(map[int]any{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+	require.NoError(a.t, err)
+
+	authData := append(a.authData(0x40), make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(append(authData, a.credentialID...), coseKey...)
+
+	object, err := cbor.Marshal(map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": authData})
+	require.NoError(a.t, err)
+
+	r := &auth.AttestationResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	r.Response.ClientDataJSON = a.clientData("webauthn.create", options.Challenge)
+	r.Response.AttestationObject = object
+	return r
+}
+
+// get answers request options with a signed assertion
+func (a *virtualAuthenticator) get(options *auth.RequestOptions) *auth.AssertionResponse {
+	a.signCount++
+	r := &auth.AssertionResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	r.Response.ClientDataJSON = a.clientData("webauthn.get", options.Challenge)
+	r.Response.AuthenticatorData = a.authData(0)
+	r.Response.UserHandle = a.userHandle
+
+	clientDataHash := sha256.Sum256(r.Response.ClientDataJSON)
+	digest := sha256.Sum256(append(bytes.Clone(r.Response.AuthenticatorData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+	r.Response.Signature = signature
+	return r
+}
+
+func newTestPasskeyService() (*service.PasskeyService, *MockUserService, *MockPasskeyRepository, *MockSecurityEvents) {
+	users := NewMockUserService()
+	passkeys := NewMockPasskeyRepository()
+	events := &MockSecurityEvents{}
+	passkeyService := service.NewPasskeyService(users, passkeys, NewMockPasskeyChallengeStore(), &MockAuditLogger{}, events, service.PasskeySettings{RelyingParty: passkeyRP}, NewMockLogger())
+	return passkeyService, users, passkeys, events
+}
+
+func TestPasskeyService_Signup(t *testing.T) {
+	ctx := context.Background()
+	passkeyService, _, passkeys, _ := newTestPasskeyService()
+
+	options, err := passkeyService.BeginSignup(ctx, "Jane", "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", options.User.Name)
+	assert.Len(t, options.User.ID, 32)
+
+	authenticator := newVirtualAuthenticator(t, "credential-1")
+	u, err := passkeyService.FinishSignup(ctx, authenticator.create(options))
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", u.Email)
+
+	stored, err := passkeys.ListByUser(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, auth.DefaultPasskeyName, stored[0].Name)
+
+	t.Run("refuses registered addresses", func(t *testing.T) {
+		_, err := passkeyService.BeginSignup(ctx, "Jane", "jane@example.com")
+		assert.Equal(t, user.ErrUserExists, err)
+	})
+
+	t.Run("challenges are answered once", func(t *testing.T) {
+		_, err := passkeyService.FinishSignup(ctx, authenticator.create(options))
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyChallenge)
+	})
+}
+
+func TestPasskeyService_Login(t *testing.T) {
+	ctx := context.Background()
+	passkeyService, _, passkeys, _ := newTestPasskeyService()
+
+	signup, err := passkeyService.BeginSignup(ctx, "Jane", "jane@example.com")
+	require.NoError(t, err)
+	authenticator := newVirtualAuthenticator(t, "credential-1")
+	u, err := passkeyService.FinishSignup(ctx, authenticator.create(signup))
+	require.NoError(t, err)
+
+	t.Run("returns the user of the passkey", func(t *testing.T) {
+		options, err := passkeyService.BeginLogin(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, options.AllowCredentials)
+		assert.Equal(t, "required", options.UserVerification)
+
+		userID, err := passkeyService.FinishLogin(ctx, authenticator.get(options))
+		require.NoError(t, err)
+		assert.Equal(t, u.ID, userID)
+
+		stored, _ := passkeys.ListByUser(ctx, u.ID)
+		assert.Equal(t, uint32(1), stored[0].SignCount)
+		assert.NotNil(t, stored[0].LastUsedAt)
+	})
+
+	t.Run("rejects unknown passkeys", func(t *testing.T) {
+		options, err := passkeyService.BeginLogin(ctx)
+		require.NoError(t, err)
+
+		_, err = passkeyService.FinishLogin(ctx, newVirtualAuthenticator(t, "unknown").get(options))
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+	})
+
+	t.Run("rejects challenges of another ceremony", func(t *testing.T) {
+		options, err := passkeyService.BeginTwoFactor(ctx, u.ID)
+		require.NoError(t, err)
+
+		_, err = passkeyService.FinishLogin(ctx, authenticator.get(options))
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyChallenge)
+	})
+}
+
+func TestPasskeyService_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	passkeyService, users, _, _ := newTestPasskeyService()
+
+	jane, err := users.Register(ctx, "Jane", "jane@example.com", "password123")
+	require.NoError(t, err)
+	john, err := users.Register(ctx, "John", "john@example.com", "password123")
+	require.NoError(t, err)
+
+	register := func(userID int, credentialID string) *virtualAuthenticator {
+		options, err := passkeyService.BeginRegistration(ctx, userID, "Laptop")
+		require.NoError(t, err)
+		authenticator := newVirtualAuthenticator(t, credentialID)
+		_, err = passkeyService.FinishRegistration(ctx, userID, authenticator.create(options))
+		require.NoError(t, err)
+		return authenticator
+	}
+	janeKey := register(jane.ID, "jane-key")
+	johnKey := register(john.ID, "john-key")
+
+	options, err := passkeyService.BeginTwoFactor(ctx, jane.ID)
+	require.NoError(t, err)
+	require.Len(t, options.AllowCredentials, 1)
+	assert.Equal(t, auth.Base64URL("jane-key"), options.AllowCredentials[0].ID)
+
+	_, err = passkeyService.FinishTwoFactor(ctx, johnKey.get(options))
+	assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion, "passkeys of other users are refused")
+
+	options, err = passkeyService.BeginTwoFactor(ctx, jane.ID)
+	require.NoError(t, err)
+	userID, err := passkeyService.FinishTwoFactor(ctx, janeKey.get(options))
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, userID)
+
+	_, err = passkeyService.BeginTwoFactor(ctx, 99)
+	assert.ErrorIs(t, err, auth.ErrPasskeyNotFound)
+}
+
+func TestPasskeyService_Registration(t *testing.T) {
+	ctx := context.Background()
+	passkeyService, users, _, events := newTestPasskeyService()
+
+	u, err := users.Register(ctx, "Jane", "jane@example.com", "password123")
+	require.NoError(t, err)
+
+	options, err := passkeyService.BeginRegistration(ctx, u.ID, "Laptop")
+	require.NoError(t, err)
+	passkey, err := passkeyService.FinishRegistration(ctx, u.ID, newVirtualAuthenticator(t, "laptop").create(options))
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", passkey.Name)
+	assert.Equal(t, []service.SecurityEvent{{Kind: service.SecurityEventPasskeyAdded, UserID: u.ID, Detail: "Laptop"}}, events.events)
+
+	t.Run("reuses the user handle and excludes existing passkeys", func(t *testing.T) {
+		options, err := passkeyService.BeginRegistration(ctx, u.ID, "Phone")
+		require.NoError(t, err)
+		assert.Equal(t, auth.Base64URL(passkey.UserHandle), options.User.ID)
+		require.Len(t, options.ExcludeCredentials, 1)
+		assert.Equal(t, auth.Base64URL("laptop"), options.ExcludeCredentials[0].ID)
+	})
+
+	t.Run("refuses challenges of another user", func(t *testing.T) {
+		options, err := passkeyService.BeginRegistration(ctx, u.ID, "Phone")
+		require.NoError(t, err)
+
+		_, err = passkeyService.FinishRegistration(ctx, u.ID+1, newVirtualAuthenticator(t, "phone").create(options))
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyChallenge)
+	})
+
+	t.Run("refuses long names", func(t *testing.T) {
+		_, err := passkeyService.BeginRegistration(ctx, u.ID, string(bytes.Repeat([]byte("a"), 101)))
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyName)
+	})
+
+	t.Run("deletes passkeys of the user only", func(t *testing.T) {
+		assert.ErrorIs(t, passkeyService.DeletePasskey(ctx, u.ID+1, passkey.ID), auth.ErrPasskeyNotFound)
+
+		require.NoError(t, passkeyService.DeletePasskey(ctx, u.ID, passkey.ID))
+		has, err := passkeyService.HasPasskeys(ctx, u.ID)
+		require.NoError(t, err)
+		assert.False(t, has)
+		assert.True(t, errors.Is(passkeyService.DeletePasskey(ctx, u.ID, passkey.ID), auth.ErrPasskeyNotFound))
+		assert.Equal(t, service.SecurityEventPasskeyRemoved, events.events[len(events.events)-1].Kind)
+	})
+}
@@ -1,25 +1,41 @@
 package service_test
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/domain/clientid"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tag"
+	"blog-platform/internal/domain/user"
 )
 
 // MockLogger is defined in auth_service_test.go to avoid duplication
 
 // MockPostRepository implements the PostRepository interface for testing
 type MockPostRepository struct {
-	posts  map[int]*post.Post
-	nextID int
+	posts     map[int]*post.Post
+	revisions map[int][]*post.Revision
+	translations map[int]map[string]*post.Translation
+	views     []post.ViewCount
+	nextID    int
 }
 
 func NewMockPostRepository() *MockPostRepository {
 	return &MockPostRepository{
-		posts:  make(map[int]*post.Post),
-		nextID: 1,
+		posts:     make(map[int]*post.Post),
+		revisions: make(map[int][]*post.Revision),
+		translations: make(map[int]map[string]*post.Translation),
+		nextID:    1,
 	}
 }
 
@@ -27,6 +43,11 @@ func (m *MockPostRepository) Create(ctx context.Context, p *post.Post) error {
 	if p == nil {
 		return post.ErrInvalidPostData
 	}
+	if p.ClientID != nil {
+		if _, err := m.GetByClientID(ctx, *p.ClientID); err == nil {
+			return post.ErrClientIDTaken
+		}
+	}
 	
 	p.ID = m.nextID
 	m.nextID++
@@ -41,6 +62,65 @@ func (m *MockPostRepository) GetByID(ctx context.Context, id int) (*post.Post, e
 	return nil, post.ErrPostNotFound
 }
 
+func (m *MockPostRepository) GetBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) GetVisibleByID(ctx context.Context, id int, filter post.ViewFilter) (*post.Post, error) {
+	if p, exists := m.posts[id]; exists && visibleThrough(p, filter) {
+		return p, nil
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) GetVisibleBySlug(ctx context.Context, slug string, filter post.ViewFilter) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.Slug == slug && visibleThrough(p, filter) {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+// visibleThrough reports whether the repository finds a post through the
+// view filter
+func visibleThrough(p *post.Post, filter post.ViewFilter) bool {
+	return (p.IsPublished() && !p.IsPrivate()) || p.AuthorID == filter.AuthorID || filter.All
+}
+
+func (m *MockPostRepository) GetByClientID(ctx context.Context, clientID string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.ClientID != nil && *p.ClientID == clientID {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) ChangeSlug(ctx context.Context, id int, slug string) error {
+	p, exists := m.posts[id]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	p.Slug = slug
+	return nil
+}
+
+func (m *MockPostRepository) SetVisibility(ctx context.Context, p *post.Post) error {
+	existing, exists := m.posts[p.ID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	existing.Visibility = p.Visibility
+	existing.UpdatedAt = p.UpdatedAt
+	return nil
+}
+
 func (m *MockPostRepository) GetByAuthorID(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
 	var posts []*post.Post
 	count := 0
@@ -83,6 +163,154 @@ func (m *MockPostRepository) List(ctx context.Context, limit, offset int) ([]*po
 	return posts, nil
 }
 
+// ListMatching lists the matching posts in the requested order, ties broken
+// by ID like the repository
+func (m *MockPostRepository) ListMatching(ctx context.Context, filter post.ListFilter, options post.ListOptions, limit, offset int) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		return visible &&
+			(filter.Tag == "" || slices.Contains(p.Tags, filter.Tag)) &&
+			(options.AuthorID == 0 || p.AuthorID == options.AuthorID) &&
+			(options.CreatedAfter == nil || !p.CreatedAt.Before(*options.CreatedAfter)) &&
+			(options.CreatedBefore == nil || p.CreatedAt.Before(*options.CreatedBefore))
+	}, len(m.posts), 0)
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		var c int
+		switch options.Sort {
+		case post.SortUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case post.SortTitle:
+			c = strings.Compare(a.Title, b.Title)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		c = cmp.Or(c, cmp.Compare(a.ID, b.ID))
+		if options.Order == post.OrderDesc {
+			return -c
+		}
+		return c
+	})
+	posts = posts[min(offset, len(posts)):]
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListPublished(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsPublished() }, limit, offset), nil
+}
+
+// ListAfter lists the matching posts after the cursor, newest first
+func (m *MockPostRepository) ListAfter(ctx context.Context, filter post.ListFilter, after keyset.Cursor, limit int) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		if !visible || (filter.Tag != "" && !slices.Contains(p.Tags, filter.Tag)) {
+			return false
+		}
+		return after.IsZero() || p.CreatedAt.Before(after.CreatedAt) || (p.CreatedAt.Equal(after.CreatedAt) && p.ID < after.ID)
+	}, len(m.posts), 0)
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListVisibleTo(ctx context.Context, userID int, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsPublished() || p.AuthorID == userID }, limit, offset), nil
+}
+
+func (m *MockPostRepository) ListDueScheduled(ctx context.Context, now time.Time) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsDue(now) }, len(m.posts), 0), nil
+}
+
+func (m *MockPostRepository) ListSharingTags(ctx context.Context, postID, limit int) ([]*post.Post, error) {
+	source, exists := m.posts[postID]
+	if !exists {
+		return nil, nil
+	}
+	shared := func(p *post.Post) int {
+		n := 0
+		for _, tag := range p.Tags {
+			if slices.Contains(source.Tags, tag) {
+				n++
+			}
+		}
+		return n
+	}
+	posts := m.filter(func(p *post.Post) bool { return p.ID != postID && p.IsPublished() && shared(p) > 0 }, len(m.posts), 0)
+	slices.SortStableFunc(posts, func(a, b *post.Post) int { return shared(b) - shared(a) })
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListFeatured(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	featured := m.filter(func(p *post.Post) bool { return p.IsPublished() && p.IsFeatured() }, len(m.posts), 0)
+	slices.SortStableFunc(featured, func(a, b *post.Post) int { return b.FeaturedAt.Compare(*a.FeaturedAt) })
+	featured = featured[min(offset, len(featured)):]
+	return featured[:min(limit, len(featured))], nil
+}
+
+func (m *MockPostRepository) ListPublishedByIDs(ctx context.Context, ids []int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, id := range ids {
+		if p, exists := m.posts[id]; exists && p.IsPublished() {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (m *MockPostRepository) ListActivity(ctx context.Context, since time.Time) ([]post.Activity, error) {
+	var activity []post.Activity
+	for _, c := range m.views {
+		if p, exists := m.posts[c.PostID]; exists && p.IsPublished() && !c.Day.Before(post.ViewDay(since)) {
+			activity = append(activity, post.Activity{PostID: c.PostID, Kind: post.ActivityView, At: c.Day, Count: c.Views})
+		}
+	}
+	return activity, nil
+}
+
+func (m *MockPostRepository) ListMissingReadingStats(ctx context.Context, afterID, limit int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.ID > afterID && p.ReadingTime == 0 && p.Content != "" }, limit, 0), nil
+}
+
+func (m *MockPostRepository) ListPublishingBetween(ctx context.Context, from, to time.Time) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		return p.Status != post.StatusDraft && p.PublishedAt != nil && !p.PublishedAt.Before(from) && !p.PublishedAt.After(to)
+	}, len(m.posts), 0)
+	slices.SortStableFunc(posts, func(a, b *post.Post) int { return a.PublishedAt.Compare(*b.PublishedAt) })
+	return posts, nil
+}
+
+func (m *MockPostRepository) ListByTag(ctx context.Context, filter post.TagFilter, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		return visible && slices.Contains(p.Tags, filter.Tag)
+	}, limit, offset), nil
+}
+
+func (m *MockPostRepository) ListPublishedBy(ctx context.Context, filter post.PublishedFilter, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool {
+		return p.IsPublished() &&
+			(filter.AuthorID == 0 || p.AuthorID == filter.AuthorID) &&
+			(filter.Tag == "" || slices.Contains(p.Tags, filter.Tag))
+	}, limit, offset), nil
+}
+
+// filter returns a page of the posts matching keep, in ID order
+func (m *MockPostRepository) filter(keep func(p *post.Post) bool, limit, offset int) []*post.Post {
+	var posts []*post.Post
+	for id := 1; id < m.nextID && len(posts) < limit; id++ {
+		p, exists := m.posts[id]
+		if !exists || !keep(p) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		posts = append(posts, p)
+	}
+	return posts
+}
+
 func (m *MockPostRepository) Update(ctx context.Context, p *post.Post) error {
 	if p == nil {
 		return post.ErrInvalidPostData
@@ -96,6 +324,109 @@ func (m *MockPostRepository) Update(ctx context.Context, p *post.Post) error {
 	return nil
 }
 
+func (m *MockPostRepository) UpdateWithRevision(ctx context.Context, p *post.Post, rev *post.Revision) error {
+	if err := m.Update(ctx, p); err != nil {
+		return err
+	}
+
+	rev.PostID = p.ID
+	rev.Number = len(m.revisions[p.ID]) + 1
+	m.revisions[p.ID] = append(m.revisions[p.ID], rev)
+	return nil
+}
+
+func (m *MockPostRepository) ListRevisions(ctx context.Context, postID int) ([]*post.Revision, error) {
+	revisions := slices.Clone(m.revisions[postID])
+	slices.Reverse(revisions)
+	return revisions, nil
+}
+
+func (m *MockPostRepository) GetRevision(ctx context.Context, postID, number int) (*post.Revision, error) {
+	revisions := m.revisions[postID]
+	if number < 1 || number > len(revisions) {
+		return nil, post.ErrRevisionNotFound
+	}
+	return revisions[number-1], nil
+}
+
+func (m *MockPostRepository) GetTranslation(ctx context.Context, postID int, language string) (*post.Translation, error) {
+	if t, exists := m.translations[postID][language]; exists {
+		return t, nil
+	}
+	return nil, post.ErrTranslationNotFound
+}
+
+func (m *MockPostRepository) SaveTranslation(ctx context.Context, t *post.Translation) error {
+	p, exists := m.posts[t.PostID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	if m.translations[t.PostID] == nil {
+		m.translations[t.PostID] = make(map[string]*post.Translation)
+	}
+	if _, exists := m.translations[t.PostID][t.Language]; !exists {
+		p.Translations = append(p.Translations, t.Language)
+		slices.Sort(p.Translations)
+	}
+	m.translations[t.PostID][t.Language] = t
+	return nil
+}
+
+func (m *MockPostRepository) SetTags(ctx context.Context, postID int, tags []string) error {
+	p, exists := m.posts[postID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+
+	p.Tags = slices.Sorted(slices.Values(tags))
+	return nil
+}
+
+func (m *MockPostRepository) SetReadingStats(ctx context.Context, p *post.Post) error {
+	stored, exists := m.posts[p.ID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	stored.WordCount, stored.ReadingTime, stored.Excerpt = p.WordCount, p.ReadingTime, p.Excerpt
+	return nil
+}
+
+func (m *MockPostRepository) AddViews(ctx context.Context, counts []post.ViewCount) error {
+	for _, c := range counts {
+		if p, exists := m.posts[c.PostID]; exists {
+			p.ViewCount += c.Views
+			m.views = append(m.views, c)
+		}
+	}
+	return nil
+}
+
+func (m *MockPostRepository) ListPopular(ctx context.Context, since time.Time, limit int) ([]*post.PopularPost, error) {
+	views := make(map[int]int64)
+	for _, c := range m.views {
+		if !c.Day.Before(since) {
+			views[c.PostID] += c.Views
+		}
+	}
+
+	var popular []*post.PopularPost
+	for id, count := range views {
+		if p, exists := m.posts[id]; exists && p.IsPublished() {
+			popular = append(popular, &post.PopularPost{Post: p, Views: count})
+		}
+	}
+	slices.SortFunc(popular, func(a, b *post.PopularPost) int {
+		if a.Views != b.Views {
+			return int(b.Views - a.Views)
+		}
+		return b.Post.ID - a.Post.ID
+	})
+	if len(popular) > limit {
+		popular = popular[:limit]
+	}
+	return popular, nil
+}
+
 func (m *MockPostRepository) Delete(ctx context.Context, id int) error {
 	if _, exists := m.posts[id]; !exists {
 		return post.ErrPostNotFound
@@ -105,15 +436,40 @@ func (m *MockPostRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// MockJobQueue records enqueued jobs for testing
+type MockJobQueue struct {
+	kinds    []string
+	payloads []any
+}
+
+func (m *MockJobQueue) Enqueue(ctx context.Context, kind string, payload any) error {
+	m.kinds = append(m.kinds, kind)
+	m.payloads = append(m.payloads, payload)
+	return nil
+}
+
+// MockViewCounter records the posts whose views were counted
+type MockViewCounter struct {
+	postIDs []int
+}
+
+func (m *MockViewCounter) Record(postID int) {
+	m.postIDs = append(m.postIDs, postID)
+}
+
+func newTestPostService(repo *MockPostRepository) *service.PostService {
+	return service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+}
+
 func TestPostService_Implementation(t *testing.T) {
 	// Test that our concrete service implements the interface
 	repo := NewMockPostRepository()
-	var _ post.Service = service.NewPostService(repo, NewMockLogger())
+	var _ post.Service = newTestPostService(repo)
 }
 
 func TestPostService_CreatePost_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	postService := newTestPostService(repo)
 	ctx := context.Background()
 
 	// Test successful post creation
@@ -148,7 +504,7 @@ func TestPostService_CreatePost_Integration(t *testing.T) {
 
 func TestPostService_GetPost_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	postService := newTestPostService(repo)
 	ctx := context.Background()
 
 	// Create a post first
@@ -176,7 +532,7 @@ func TestPostService_GetPost_Integration(t *testing.T) {
 
 func TestPostService_UpdatePost_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	postService := newTestPostService(repo)
 	ctx := context.Background()
 
 	// Create a post first
@@ -186,7 +542,7 @@ func TestPostService_UpdatePost_Integration(t *testing.T) {
 	}
 
 	// Test successful update by author
-	updatedPost, err := postService.UpdatePost(ctx, 1, createdPost.ID, "Updated Title", "Updated content with sufficient length.")
+	updatedPost, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, createdPost.ID, "Updated Title", "Updated content with sufficient length.")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -196,13 +552,13 @@ func TestPostService_UpdatePost_Integration(t *testing.T) {
 	}
 
 	// Test unauthorized update
-	_, err = postService.UpdatePost(ctx, 2, createdPost.ID, "Unauthorized Update", "Unauthorized content with sufficient length.")
+	_, err = postService.UpdatePost(ctx, 2, user.RoleAuthor, createdPost.ID, "Unauthorized Update", "Unauthorized content with sufficient length.")
 	if err != post.ErrUnauthorized {
 		t.Errorf("expected ErrUnauthorized, got %v", err)
 	}
 
 	// Test update of non-existent post
-	_, err = postService.UpdatePost(ctx, 1, 999, "Non-existent", "Non-existent content with sufficient length.")
+	_, err = postService.UpdatePost(ctx, 1, user.RoleAuthor, 999, "Non-existent", "Non-existent content with sufficient length.")
 	if err != post.ErrPostNotFound {
 		t.Errorf("expected ErrPostNotFound, got %v", err)
 	}
@@ -210,7 +566,8 @@ func TestPostService_UpdatePost_Integration(t *testing.T) {
 
 func TestPostService_DeletePost_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	audit := &MockAuditLogger{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, audit, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
 	ctx := context.Background()
 
 	// Create a post first
@@ -220,13 +577,13 @@ func TestPostService_DeletePost_Integration(t *testing.T) {
 	}
 
 	// Test unauthorized deletion
-	err = postService.DeletePost(ctx, 2, createdPost.ID)
+	err = postService.DeletePost(ctx, 2, user.RoleAuthor, createdPost.ID)
 	if err != post.ErrUnauthorized {
 		t.Errorf("expected ErrUnauthorized, got %v", err)
 	}
 
 	// Test successful deletion by author
-	err = postService.DeletePost(ctx, 1, createdPost.ID)
+	err = postService.DeletePost(ctx, 1, user.RoleAuthor, createdPost.ID)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -237,16 +594,95 @@ func TestPostService_DeletePost_Integration(t *testing.T) {
 		t.Error("expected post to be deleted")
 	}
 
+	// Only the successful deletion is audited
+	if len(audit.events) != 1 || audit.events[0].Action != service.AuditActionPostDeleted || audit.events[0].UserID != 1 {
+		t.Errorf("expected one post deletion audit event by user 1, got %+v", audit.events)
+	}
+
 	// Test deletion of non-existent post
-	err = postService.DeletePost(ctx, 1, 999)
+	err = postService.DeletePost(ctx, 1, user.RoleAuthor, 999)
 	if err != post.ErrPostNotFound {
 		t.Errorf("expected ErrPostNotFound, got %v", err)
 	}
 }
 
+func TestPostService_DeletePost_DeletesComments(t *testing.T) {
+	repo := NewMockPostRepository()
+	comments := NewMockCommentRepository()
+	tx := &MockTxManager{}
+	postService := service.NewPostService(repo, comments, tx, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	deleted, err := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	kept, err := postService.CreatePost(ctx, 1, "Other Post", "Other content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	for _, postID := range []int{deleted.ID, deleted.ID, kept.ID} {
+		if err := comments.Create(ctx, &comment.Comment{PostID: postID, AuthorName: "Reader", Content: "Nice post"}); err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+
+	if err := postService.DeletePost(ctx, 1, user.RoleAuthor, deleted.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tx.units != 1 {
+		t.Errorf("expected the post and its comments to be deleted in one unit of work, got %d", tx.units)
+	}
+
+	// Only the comments of the deleted post are removed
+	if len(comments.comments) != 1 {
+		t.Fatalf("expected 1 remaining comment, got %d", len(comments.comments))
+	}
+	for _, c := range comments.comments {
+		if c.PostID != kept.ID {
+			t.Errorf("expected the comment of post %d to remain, got post %d", kept.ID, c.PostID)
+		}
+	}
+}
+
+func TestPostService_SetNoIndex_Integration(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	createdPost, err := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	// Test unauthorized change
+	_, err = postService.SetNoIndex(ctx, 2, user.RoleAuthor, createdPost.ID, true)
+	if err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+
+	// Test successful change by author
+	updatedPost, err := postService.SetNoIndex(ctx, 1, user.RoleAuthor, createdPost.ID, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !updatedPost.NoIndex {
+		t.Error("expected post to be flagged noindex")
+	}
+
+	// Verify the flag is stored
+	storedPost, err := postService.GetPost(ctx, createdPost.ID)
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if !storedPost.NoIndex {
+		t.Error("expected stored post to be flagged noindex")
+	}
+}
+
 func TestPostService_GetPostsByAuthor_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	postService := newTestPostService(repo)
 	ctx := context.Background()
 
 	// Create posts by different authors
@@ -288,7 +724,7 @@ func TestPostService_GetPostsByAuthor_Integration(t *testing.T) {
 
 func TestPostService_ListPosts_Integration(t *testing.T) {
 	repo := NewMockPostRepository()
-	postService := service.NewPostService(repo, NewMockLogger())
+	postService := newTestPostService(repo)
 	ctx := context.Background()
 
 	// Create multiple posts
@@ -326,3 +762,1016 @@ func TestPostService_ListPosts_Integration(t *testing.T) {
 		t.Errorf("expected 3 posts with offset, got %d", len(posts))
 	}
 }
+
+func TestPostService_AdminCanModifyAnyPost(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	createdPost, err := postService.CreatePost(ctx, 1, "Original Title", "Original content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	// Readers have no extra rights over other users' posts
+	_, err = postService.UpdatePost(ctx, 2, user.RoleReader, createdPost.ID, "Reader Update", "Reader content with sufficient length.")
+	if err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+
+	updatedPost, err := postService.UpdatePost(ctx, 2, user.RoleAdmin, createdPost.ID, "Moderated Title", "Moderated content with sufficient length.")
+	if err != nil {
+		t.Fatalf("expected admin update to succeed, got %v", err)
+	}
+	if updatedPost.AuthorID != 1 {
+		t.Errorf("expected author to remain 1, got %d", updatedPost.AuthorID)
+	}
+
+	if err := postService.DeletePost(ctx, 2, user.RoleAdmin, createdPost.ID); err != nil {
+		t.Fatalf("expected admin delete to succeed, got %v", err)
+	}
+
+	if _, err := postService.GetPost(ctx, createdPost.ID); err != post.ErrPostNotFound {
+		t.Error("expected post to be deleted")
+	}
+}
+
+func TestPostService_CreatePost_UniqueSlug(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	first, err := postService.CreatePost(ctx, 1, "Same Title", "First content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	second, err := postService.CreatePost(ctx, 2, "Same Title", "Second content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	if first.Slug != "same-title" {
+		t.Errorf("expected slug 'same-title', got '%s'", first.Slug)
+	}
+	if second.Slug != "same-title-2" {
+		t.Errorf("expected slug 'same-title-2', got '%s'", second.Slug)
+	}
+
+	found, err := postService.GetPostBySlug(ctx, "same-title-2")
+	if err != nil {
+		t.Fatalf("expected post by slug, got %v", err)
+	}
+	if found.ID != second.ID {
+		t.Errorf("expected post %d, got %d", second.ID, found.ID)
+	}
+
+	if _, err := postService.GetPostBySlug(ctx, "missing"); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestPostService_NotifiesSearchEngines(t *testing.T) {
+	repo := NewMockPostRepository()
+	jobs := &MockJobQueue{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, jobs, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{NotifySearchEngines: true}, NewMockLogger())
+	ctx := context.Background()
+
+	createdPost, err := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if len(jobs.kinds) != 1 || jobs.kinds[0] != service.JobSearchPing {
+		t.Fatalf("expected a search ping job on publish, got %v", jobs.kinds)
+	}
+	payload, ok := jobs.payloads[0].(service.SearchPingPayload)
+	if !ok || payload.PostID != createdPost.ID || payload.Slug != "test-post" {
+		t.Errorf("unexpected payload %+v", jobs.payloads[0])
+	}
+
+	if _, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, createdPost.ID, "Test Post", "Updated content with sufficient length."); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if len(jobs.kinds) != 2 {
+		t.Fatalf("expected a search ping job on update, got %d jobs", len(jobs.kinds))
+	}
+
+	// Hidden posts are not announced
+	if _, err := postService.SetNoIndex(ctx, 1, user.RoleAuthor, createdPost.ID, true); err != nil {
+		t.Fatalf("failed to set noindex: %v", err)
+	}
+	if _, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, createdPost.ID, "Test Post", "Another update with sufficient length."); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if len(jobs.kinds) != 2 {
+		t.Errorf("expected no search ping for a noindex post, got %d jobs", len(jobs.kinds))
+	}
+
+	// Disabled pings queue nothing
+	quietJobs := &MockJobQueue{}
+	quietService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, quietJobs, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	if _, err := quietService.CreatePost(ctx, 1, "Quiet Post", "Test content with sufficient length."); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if len(quietJobs.kinds) != 0 {
+		t.Errorf("expected no jobs when search pings are disabled, got %v", quietJobs.kinds)
+	}
+}
+
+func TestPostService_Drafts(t *testing.T) {
+	repo := NewMockPostRepository()
+	jobs := &MockJobQueue{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, jobs, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{NotifySearchEngines: true}, NewMockLogger())
+	ctx := context.Background()
+
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Draft content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if draft.Status != post.StatusDraft || draft.PublishedAt != nil {
+		t.Errorf("expected an unpublished draft, got %s", draft.Status)
+	}
+	if len(jobs.kinds) != 0 {
+		t.Errorf("expected no search ping for a draft, got %v", jobs.kinds)
+	}
+	if _, err := postService.CreatePost(ctx, 2, "Published Post", "Published content with sufficient length."); err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	// Drafts are listed only for their author and admins
+	cases := []struct {
+		name   string
+		userID int
+		role   user.Role
+		want   int
+	}{
+		{"anonymous", 0, "", 1},
+		{"other author", 2, user.RoleAuthor, 1},
+		{"author", 1, user.RoleAuthor, 2},
+		{"admin", 3, user.RoleAdmin, 2},
+	}
+	for _, tc := range cases {
+		posts, err := postService.ListPostsFor(ctx, tc.userID, tc.role, 10, 0)
+		if err != nil {
+			t.Fatalf("%s: failed to list posts: %v", tc.name, err)
+		}
+		if len(posts) != tc.want {
+			t.Errorf("%s: expected %d posts, got %d", tc.name, tc.want, len(posts))
+		}
+	}
+
+	posts, err := postService.ListPosts(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list posts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID == draft.ID {
+		t.Errorf("expected only the published post, got %d posts", len(posts))
+	}
+}
+
+func TestPostService_PublishPost(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Draft content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+
+	if _, err := postService.PublishPost(ctx, 2, user.RoleAuthor, draft.ID); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+
+	published, err := postService.PublishPost(ctx, 1, user.RoleAuthor, draft.ID)
+	if err != nil {
+		t.Fatalf("failed to publish post: %v", err)
+	}
+	if !published.IsPublished() || published.PublishedAt == nil {
+		t.Errorf("expected a published post, got %s", published.Status)
+	}
+
+	if _, err := postService.PublishPost(ctx, 1, user.RoleAuthor, draft.ID); err != post.ErrAlreadyPublished {
+		t.Errorf("expected ErrAlreadyPublished, got %v", err)
+	}
+	if _, _, err := postService.SchedulePost(ctx, 1, user.RoleAuthor, draft.ID, time.Now().Add(time.Hour), ""); err != post.ErrAlreadyPublished {
+		t.Errorf("expected ErrAlreadyPublished, got %v", err)
+	}
+}
+
+func TestPostService_SchedulePost(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Draft content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+
+	if _, _, err := postService.SchedulePost(ctx, 1, user.RoleAuthor, draft.ID, time.Now().Add(-time.Minute), ""); err != post.ErrInvalidPublishTime {
+		t.Errorf("expected ErrInvalidPublishTime, got %v", err)
+	}
+
+	later, err := postService.CreateDraft(ctx, 1, "Later Post", "Later content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if _, _, err := postService.SchedulePost(ctx, 1, user.RoleAuthor, draft.ID, time.Now().Add(time.Hour), ""); err != nil {
+		t.Fatalf("failed to schedule post: %v", err)
+	}
+	if _, _, err := postService.SchedulePost(ctx, 1, user.RoleAuthor, later.ID, time.Now().Add(2*time.Hour), ""); err != nil {
+		t.Fatalf("failed to schedule post: %v", err)
+	}
+
+	// Nothing is due yet
+	if n, err := postService.PublishScheduledPosts(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no posts published, got %d (%v)", n, err)
+	}
+
+	// Only the post whose time has come is published
+	due := time.Now().Add(-time.Second)
+	repo.posts[draft.ID].PublishedAt = &due
+	n, err := postService.PublishScheduledPosts(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one post published, got %d (%v)", n, err)
+	}
+	if !repo.posts[draft.ID].IsPublished() {
+		t.Errorf("expected the due post to be published, got %s", repo.posts[draft.ID].Status)
+	}
+	if repo.posts[later.ID].Status != post.StatusScheduled {
+		t.Errorf("expected the later post to stay scheduled, got %s", repo.posts[later.ID].Status)
+	}
+}
+
+func TestPostService_ReadingStats(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	created, err := postService.CreatePost(ctx, 1, "Stats Post", "First paragraph of the post.\n\nSecond paragraph.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if created.WordCount != 7 || created.ReadingTime != 1 || created.Excerpt != "First paragraph of the post." {
+		t.Errorf("expected stats computed on create, got %d words, %d minutes, excerpt %q", created.WordCount, created.ReadingTime, created.Excerpt)
+	}
+
+	longContent := strings.Repeat("word ", 450)
+	updated, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, created.ID, "Stats Post", longContent)
+	if err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if updated.WordCount != 450 || updated.ReadingTime != 3 {
+		t.Errorf("expected stats recomputed on update, got %d words, %d minutes", updated.WordCount, updated.ReadingTime)
+	}
+
+	// Posts stored before the stats were kept get them computed
+	old, err := postService.CreatePost(ctx, 1, "Old Post", "Content stored without reading stats.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	old.WordCount, old.ReadingTime, old.Excerpt = 0, 0, ""
+
+	n, err := postService.ComputeMissingReadingStats(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one post computed, got %d (%v)", n, err)
+	}
+	if stored := repo.posts[old.ID]; stored.WordCount != 5 || stored.ReadingTime != 1 || stored.Excerpt != "Content stored without reading stats." {
+		t.Errorf("expected the old post's stats computed, got %d words, %d minutes, excerpt %q", stored.WordCount, stored.ReadingTime, stored.Excerpt)
+	}
+	if n, err := postService.ComputeMissingReadingStats(ctx); err != nil || n != 0 {
+		t.Errorf("expected nothing left to compute, got %d (%v)", n, err)
+	}
+}
+
+func TestPostService_Views(t *testing.T) {
+	repo := NewMockPostRepository()
+	counter := &MockViewCounter{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, counter, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	published, err := postService.CreatePost(ctx, 1, "Published", "Published content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	draft, err := postService.CreateDraft(ctx, 1, "Draft", "Draft content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	other, err := postService.CreatePost(ctx, 2, "Other", "Other content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	// Drafts previewed by their author are not counted
+	postService.RecordView(ctx, published)
+	postService.RecordView(ctx, draft)
+	if !slices.Equal(counter.postIDs, []int{published.ID}) {
+		t.Errorf("expected only the published post to be counted, got %v", counter.postIDs)
+	}
+
+	today := post.ViewDay(time.Now())
+	repo.AddViews(ctx, []post.ViewCount{
+		{PostID: published.ID, Day: today, Views: 3},
+		{PostID: other.ID, Day: today.AddDate(0, 0, -1), Views: 5},
+		{PostID: published.ID, Day: today.AddDate(0, 0, -10), Views: 10},
+	})
+
+	popular, err := postService.ListPopularPosts(ctx, 7, 10)
+	if err != nil {
+		t.Fatalf("failed to list popular posts: %v", err)
+	}
+	if len(popular) != 2 || popular[0].Post.ID != other.ID || popular[0].Views != 5 || popular[1].Views != 3 {
+		t.Errorf("expected the other post first with 5 views, got %v", popular)
+	}
+
+	// Today only
+	popular, err = postService.ListPopularPosts(ctx, 1, 10)
+	if err != nil || len(popular) != 1 || popular[0].Post.ID != published.ID {
+		t.Errorf("expected only today's views, got %v (%v)", popular, err)
+	}
+
+	for _, days := range []int{0, post.MaxPopularWindow + 1} {
+		if _, err := postService.ListPopularPosts(ctx, days, 10); err != post.ErrInvalidPopularWindow {
+			t.Errorf("expected ErrInvalidPopularWindow for %d days, got %v", days, err)
+		}
+	}
+}
+
+func TestPostService_ScheduleConflicts(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{ConflictWindow: time.Hour}, NewMockLogger())
+	ctx := context.Background()
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	schedule := func(userID int, title string, at time.Time, timezone string) (*post.Post, []post.Conflict) {
+		t.Helper()
+		draft, err := postService.CreateDraft(ctx, userID, title, "Draft content with sufficient length.")
+		if err != nil {
+			t.Fatalf("failed to create draft: %v", err)
+		}
+		p, conflicts, err := postService.SchedulePost(ctx, userID, user.RoleAuthor, draft.ID, at, timezone)
+		if err != nil {
+			t.Fatalf("failed to schedule post: %v", err)
+		}
+		return p, conflicts
+	}
+
+	first, conflicts := schedule(1, "First", base, "Europe/Berlin")
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if first.Timezone != "Europe/Berlin" {
+		t.Errorf("expected the time zone to be kept, got %q", first.Timezone)
+	}
+
+	// Another author's post is reported without revealing it
+	_, conflicts = schedule(2, "Second", base.Add(30*time.Minute), "")
+	if len(conflicts) != 1 || conflicts[0].PostID != 0 || !conflicts[0].PublishAt.Equal(base) {
+		t.Errorf("expected an anonymous conflict at %v, got %v", base, conflicts)
+	}
+
+	// Posts exactly a window apart do not conflict
+	_, conflicts = schedule(1, "Third", base.Add(-time.Hour), "")
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+
+	if _, _, err := postService.SchedulePost(ctx, 1, user.RoleAuthor, first.ID, base, "Mars/Olympus"); err != post.ErrInvalidTimezone {
+		t.Errorf("expected ErrInvalidTimezone, got %v", err)
+	}
+
+	// The calendar lists the author's own and published posts; conflicts
+	// with the other author's post show for both
+	entries, err := postService.Calendar(ctx, 1, user.RoleAuthor, base.Add(-2*time.Hour), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to list calendar: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Post.Title != "Third" || entries[1].Post.Title != "First" {
+		t.Fatalf("expected Third and First, got %d entries", len(entries))
+	}
+	if len(entries[1].Conflicts) != 1 || entries[1].Conflicts[0].PostID != 0 {
+		t.Errorf("expected an anonymous conflict for First, got %v", entries[1].Conflicts)
+	}
+
+	entries, err = postService.Calendar(ctx, 3, user.RoleAdmin, base.Add(-2*time.Hour), base.Add(2*time.Hour))
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected every post for admins, got %d entries (%v)", len(entries), err)
+	}
+	if len(entries[1].Conflicts) != 1 || entries[1].Conflicts[0].PostID != entries[2].Post.ID {
+		t.Errorf("expected admins to see the conflicting post, got %v", entries[1].Conflicts)
+	}
+
+	if _, err := postService.Calendar(ctx, 1, user.RoleAuthor, base, base.Add(-time.Hour)); err != post.ErrInvalidCalendarRange {
+		t.Errorf("expected ErrInvalidCalendarRange, got %v", err)
+	}
+	if _, err := postService.Calendar(ctx, 1, user.RoleAuthor, base, base.Add(post.MaxCalendarSpan+time.Hour)); err != post.ErrInvalidCalendarRange {
+		t.Errorf("expected ErrInvalidCalendarRange, got %v", err)
+	}
+}
+
+func TestPostService_Tags(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	published, err := postService.CreatePost(ctx, 1, "Go Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	draft, err := postService.CreateDraft(ctx, 1, "Go Draft", "Draft content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+
+	tagged, err := postService.SetTags(ctx, 1, user.RoleAuthor, published.ID, []string{"Web Development", "Golang", "golang"})
+	if err != nil {
+		t.Fatalf("failed to set tags: %v", err)
+	}
+	if strings.Join(tagged.Tags, ",") != "golang,web-development" {
+		t.Errorf("expected normalized, sorted tags, got %v", tagged.Tags)
+	}
+	if _, err := postService.SetTags(ctx, 1, user.RoleAuthor, draft.ID, []string{"golang"}); err != nil {
+		t.Fatalf("failed to set tags: %v", err)
+	}
+
+	if _, err := postService.SetTags(ctx, 2, user.RoleAuthor, published.ID, []string{"spam"}); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := postService.SetTags(ctx, 1, user.RoleAuthor, published.ID, []string{"c++"}); err != tag.ErrInvalidTag {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+
+	// The draft is only listed for its author and admins
+	cases := []struct {
+		name   string
+		userID int
+		role   user.Role
+		want   int
+	}{
+		{"anonymous", 0, "", 1},
+		{"other author", 2, user.RoleAuthor, 1},
+		{"author", 1, user.RoleAuthor, 2},
+		{"admin", 3, user.RoleAdmin, 2},
+	}
+	for _, tc := range cases {
+		posts, err := postService.ListPostsByTag(ctx, tc.userID, tc.role, "GoLang", 10, 0)
+		if err != nil {
+			t.Fatalf("%s: failed to list posts by tag: %v", tc.name, err)
+		}
+		if len(posts) != tc.want {
+			t.Errorf("%s: expected %d posts, got %d", tc.name, tc.want, len(posts))
+		}
+	}
+
+	posts, err := postService.ListPostsByTag(ctx, 0, "", "web-development", 10, 0)
+	if err != nil || len(posts) != 1 || posts[0].ID != published.ID {
+		t.Errorf("expected the published post, got %d posts (%v)", len(posts), err)
+	}
+
+	// Feeds list published posts only, filtered by tag and author
+	posts, err = postService.ListPublishedPosts(ctx, post.PublishedFilter{Tag: "GoLang", AuthorID: 1}, 10, 0)
+	if err != nil || len(posts) != 1 || posts[0].ID != published.ID {
+		t.Errorf("expected the published post, got %d posts (%v)", len(posts), err)
+	}
+	posts, err = postService.ListPublishedPosts(ctx, post.PublishedFilter{Tag: "golang", AuthorID: 2}, 10, 0)
+	if err != nil || len(posts) != 0 {
+		t.Errorf("expected no posts of another author, got %d posts (%v)", len(posts), err)
+	}
+	if _, err := postService.ListPublishedPosts(ctx, post.PublishedFilter{Tag: "c++"}, 10, 0); err != tag.ErrInvalidTag {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+
+	// An empty list clears the tags
+	cleared, err := postService.SetTags(ctx, 1, user.RoleAuthor, published.ID, []string{})
+	if err != nil {
+		t.Fatalf("failed to clear tags: %v", err)
+	}
+	if len(cleared.Tags) != 0 {
+		t.Errorf("expected no tags, got %v", cleared.Tags)
+	}
+}
+
+func TestPostService_Revisions(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	p, err := postService.CreatePost(ctx, 1, "First Title", "First version of the content.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.UpdatePost(ctx, 1, user.RoleAuthor, p.ID, "Second Title", "Second version of the content."); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if _, err := postService.UpdatePost(ctx, 3, user.RoleAdmin, p.ID, "Third Title", "Third version of the content."); err != nil {
+		t.Fatalf("failed to update post as admin: %v", err)
+	}
+
+	// Every update keeps the version it replaced, newest first
+	current, revisions, err := postService.ListRevisions(ctx, 1, user.RoleAuthor, p.ID)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	if current.Title != "Third Title" || len(revisions) != 2 {
+		t.Fatalf("expected the current post and 2 revisions, got %q and %d", current.Title, len(revisions))
+	}
+	if revisions[0].Number != 2 || revisions[0].Title != "Second Title" || revisions[0].EditedBy != 3 {
+		t.Errorf("unexpected newest revision %+v", revisions[0])
+	}
+	if revisions[1].Number != 1 || revisions[1].Content != "First version of the content." || revisions[1].EditedBy != 1 {
+		t.Errorf("unexpected oldest revision %+v", revisions[1])
+	}
+
+	if _, _, err := postService.ListRevisions(ctx, 2, user.RoleAuthor, p.ID); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another user, got %v", err)
+	}
+	if _, _, err := postService.ListRevisions(ctx, 3, user.RoleAdmin, p.ID); err != nil {
+		t.Errorf("expected admins to see revisions, got %v", err)
+	}
+
+	// Only the author restores, even over admins
+	if _, err := postService.RestoreRevision(ctx, 3, user.RoleAdmin, p.ID, 1); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for an admin, got %v", err)
+	}
+	if _, err := postService.RestoreRevision(ctx, 1, user.RoleAuthor, p.ID, 9); err != post.ErrRevisionNotFound {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+
+	restored, err := postService.RestoreRevision(ctx, 1, user.RoleAuthor, p.ID, 1)
+	if err != nil {
+		t.Fatalf("failed to restore revision: %v", err)
+	}
+	if restored.Title != "First Title" || restored.Content != "First version of the content." {
+		t.Errorf("expected the first version, got %q: %q", restored.Title, restored.Content)
+	}
+
+	// The replaced version is kept, so the restore can be undone
+	_, revisions, err = postService.ListRevisions(ctx, 1, user.RoleAuthor, p.ID)
+	if err != nil {
+		t.Fatalf("failed to list revisions: %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].Title != "Third Title" {
+		t.Errorf("expected the replaced version as revision 3, got %d revisions", len(revisions))
+	}
+}
+
+func TestPostService_ListPostsAfter(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	// Posts created in the same second are told apart by ID
+	createdAt := time.Date(2030, 1, 15, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		p, err := postService.CreatePost(ctx, 1, "Post Title", "Post content with sufficient length for validation.")
+		if err != nil {
+			t.Fatalf("failed to create post %d: %v", i, err)
+		}
+		p.CreatedAt = createdAt
+	}
+	draft, err := postService.CreateDraft(ctx, 2, "Draft Title", "Draft content with sufficient length for validation.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	draft.CreatedAt = createdAt.Add(time.Hour)
+
+	var pages [][]int
+	var cursor keyset.Cursor
+	for {
+		posts, next, err := postService.ListPostsAfter(ctx, 0, "", "", cursor, 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		ids := make([]int, len(posts))
+		for i, p := range posts {
+			ids[i] = p.ID
+		}
+		pages = append(pages, ids)
+		if next.IsZero() {
+			break
+		}
+		// Clients get the cursor back as a string
+		if cursor, err = keyset.Decode(next.Encode()); err != nil {
+			t.Fatalf("failed to decode cursor: %v", err)
+		}
+	}
+	if want := [][]int{{5, 4}, {3, 2}, {1}}; !slices.EqualFunc(pages, want, slices.Equal) {
+		t.Errorf("expected pages %v, got %v", want, pages)
+	}
+
+	// Authors also page through their drafts
+	posts, _, err := postService.ListPostsAfter(ctx, 2, user.RoleAuthor, "", keyset.Cursor{}, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != draft.ID {
+		t.Errorf("expected the draft first, got %v", posts)
+	}
+}
+
+func TestPostService_ListPostsMatching(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	createdAt := time.Date(2030, 1, 15, 9, 0, 0, 0, time.UTC)
+	for i, title := range []string{"Banana Bread", "Apple Pie", "Cherry Tart"} {
+		p, err := postService.CreatePost(ctx, i%2+1, title, "Post content with sufficient length for validation.")
+		if err != nil {
+			t.Fatalf("failed to create post %q: %v", title, err)
+		}
+		p.CreatedAt = createdAt.Add(time.Duration(i) * time.Hour)
+	}
+	draft, err := postService.CreateDraft(ctx, 2, "Draft Title", "Draft content with sufficient length for validation.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	draft.CreatedAt = createdAt.Add(5 * time.Hour)
+
+	titles := func(options post.ListOptions) []string {
+		t.Helper()
+		posts, err := postService.ListPostsMatching(ctx, 0, "", "", options, 10, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var result []string
+		for _, p := range posts {
+			result = append(result, p.Title)
+		}
+		return result
+	}
+
+	// Newest first by default; drafts stay hidden from anonymous readers
+	if got, want := titles(post.ListOptions{AuthorID: 1}), []string{"Cherry Tart", "Banana Bread"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := titles(post.ListOptions{Sort: post.SortTitle}), []string{"Apple Pie", "Banana Bread", "Cherry Tart"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	after, before := createdAt.Add(time.Hour), createdAt.Add(2*time.Hour)
+	if got, want := titles(post.ListOptions{CreatedAfter: &after, CreatedBefore: &before}), []string{"Apple Pie"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// Authors see their drafts
+	posts, err := postService.ListPostsMatching(ctx, 2, user.RoleAuthor, "", post.ListOptions{AuthorID: 2}, 10, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != draft.ID {
+		t.Errorf("expected the draft first, got %v", posts)
+	}
+
+	if _, err := postService.ListPostsMatching(ctx, 0, "", "", post.ListOptions{Sort: "views"}, 10, 0); err != post.ErrInvalidSort {
+		t.Errorf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestPostService_CreatePost_PlanLimit(t *testing.T) {
+	repo := NewMockPostRepository()
+	plans := NewMockBillingRepository()
+	billingService := service.NewBillingService(plans, &MockAuditLogger{}, NewMockLogger())
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, billingService, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	plans.posts[1] = billing.LimitsOf(billing.PlanFree).MaxPosts
+	if _, err := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length."); !errors.Is(err, billing.ErrPlanLimit) {
+		t.Fatalf("expected ErrPlanLimit, got %v", err)
+	}
+	if len(repo.posts) != 0 {
+		t.Errorf("expected no post to be created, got %d", len(repo.posts))
+	}
+
+	plans.subscriptions[1] = &billing.Subscription{UserID: 1, Plan: billing.PlanPro, Status: billing.StatusActive}
+	if _, err := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length."); err != nil {
+		t.Errorf("expected the pro plan to lift the limit, got %v", err)
+	}
+}
+
+func TestPostService_GatePosts(t *testing.T) {
+	repo := NewMockPostRepository()
+	plans := NewMockBillingRepository()
+	billingService := service.NewBillingService(plans, &MockAuditLogger{}, NewMockLogger())
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, billingService, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	content := "The opening paragraph.\n\nThe rest of the post."
+	public, _ := postService.CreatePost(ctx, 1, "Public", content)
+	members, _ := postService.CreatePost(ctx, 1, "Members", content)
+	subscribers, _ := postService.CreatePost(ctx, 1, "Subscribers", content)
+	if _, err := postService.SetAccess(ctx, 1, user.RoleAuthor, members.ID, post.AccessMembers); err != nil {
+		t.Fatalf("failed to set members access: %v", err)
+	}
+	if _, err := postService.SetAccess(ctx, 1, user.RoleAuthor, subscribers.ID, post.AccessSubscribers); err != nil {
+		t.Fatalf("failed to set subscribers access: %v", err)
+	}
+	posts := []*post.Post{public, members, subscribers}
+
+	locked := func(gated []*post.Post) []bool {
+		result := make([]bool, len(gated))
+		for i, p := range gated {
+			result[i] = p.Locked
+		}
+		return result
+	}
+	plans.subscriptions[3] = &billing.Subscription{UserID: 3, Plan: billing.PlanPro, Status: billing.StatusActive}
+	tests := []struct {
+		name   string
+		userID int
+		role   user.Role
+		want   []bool
+	}{
+		{"anonymous", 0, "", []bool{false, true, true}},
+		{"free member", 2, user.RoleReader, []bool{false, false, true}},
+		{"subscriber", 3, user.RoleReader, []bool{false, false, false}},
+		{"author", 1, user.RoleAuthor, []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gated := postService.GatePosts(ctx, tt.userID, tt.role, posts)
+			if got := locked(gated); !slices.Equal(got, tt.want) {
+				t.Errorf("expected locked %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	teaser := postService.GatePost(ctx, 0, "", subscribers)
+	if teaser.Content != "The opening paragraph." {
+		t.Errorf("expected the teaser to hold the first paragraph, got %q", teaser.Content)
+	}
+	if subscribers.Locked || subscribers.Content != content {
+		t.Error("expected gating to leave the post untouched")
+	}
+}
+
+func TestPostService_SetAccess(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+
+	createdPost, _ := postService.CreatePost(ctx, 1, "Test Post", "Test content with sufficient length.")
+	if createdPost.Access != post.AccessPublic {
+		t.Errorf("expected new posts to be public, got %q", createdPost.Access)
+	}
+
+	if _, err := postService.SetAccess(ctx, 2, user.RoleAuthor, createdPost.ID, post.AccessMembers); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := postService.SetAccess(ctx, 1, user.RoleAuthor, createdPost.ID, "private"); err != post.ErrInvalidAccess {
+		t.Errorf("expected ErrInvalidAccess, got %v", err)
+	}
+	// Without billing nobody could read subscriber-only posts
+	if _, err := postService.SetAccess(ctx, 1, user.RoleAuthor, createdPost.ID, post.AccessSubscribers); err != post.ErrSubscribersUnavailable {
+		t.Errorf("expected ErrSubscribersUnavailable, got %v", err)
+	}
+
+	updatedPost, err := postService.SetAccess(ctx, 1, user.RoleAuthor, createdPost.ID, post.AccessMembers)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.posts[createdPost.ID].Access != post.AccessMembers || updatedPost.Access != post.AccessMembers {
+		t.Errorf("expected members access to be saved, got %q", repo.posts[createdPost.ID].Access)
+	}
+}
+
+func TestPostService_CreateClientPost(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := newTestPostService(repo)
+	ctx := context.Background()
+	clientID := "123e4567-e89b-12d3-a456-426614174000"
+
+	p, created, err := postService.CreateClientPost(ctx, 1, "123E4567-E89B-12D3-A456-426614174000", "Offline Post", "Written on a plane without a network.", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !created || p.ClientID == nil || *p.ClientID != clientID {
+		t.Errorf("expected a new post under the lowercased client ID, got %+v (created %v)", p, created)
+	}
+	if p.Status != post.StatusDraft {
+		t.Errorf("expected a draft, got status %q", p.Status)
+	}
+
+	// A retry returns the post created the first time
+	again, created, err := postService.CreateClientPost(ctx, 1, clientID, "Offline Post", "Written on a plane without a network.", true)
+	if err != nil {
+		t.Fatalf("expected no error on retry, got %v", err)
+	}
+	if created || again.ID != p.ID || len(repo.posts) != 1 {
+		t.Errorf("expected the retry to return post %d, got %d (created %v, %d posts)", p.ID, again.ID, created, len(repo.posts))
+	}
+
+	found, err := postService.GetPostByClientID(ctx, clientID)
+	if err != nil || found.ID != p.ID {
+		t.Errorf("expected post %d by client ID, got %v, %v", p.ID, found, err)
+	}
+
+	if _, _, err := postService.CreateClientPost(ctx, 2, clientID, "Another Post", "Someone else's post content.", false); !errors.Is(err, post.ErrClientIDTaken) {
+		t.Errorf("expected ErrClientIDTaken for another author, got %v", err)
+	}
+	if _, _, err := postService.CreateClientPost(ctx, 1, "not-a-uuid", "Offline Post", "Written on a plane without a network.", false); !errors.Is(err, clientid.ErrInvalid) {
+		t.Errorf("expected clientid.ErrInvalid, got %v", err)
+	}
+	if _, err := postService.GetPostByClientID(ctx, "00000000-0000-0000-0000-000000000000"); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+}
+
+// stubRelatedness returns the same posts whatever post is asked about
+type stubRelatedness struct {
+	posts []*post.Post
+	calls int
+}
+
+func (s *stubRelatedness) Related(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	s.calls++
+	return s.posts, nil
+}
+
+func TestPostService_RelatedPosts(t *testing.T) {
+	repo := NewMockPostRepository()
+	ctx := context.Background()
+
+	source, _ := post.NewPost("Source", "Content", 1)
+	other, _ := post.NewPost("Other", "Content", 1)
+	draft, _ := post.NewDraft("Draft", "Content", 1)
+	for _, p := range []*post.Post{source, other, draft} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+	}
+
+	// Without a strategy no posts are related
+	if related, err := newTestPostService(repo).ListRelatedPosts(ctx, source, 0); err != nil || len(related) != 0 {
+		t.Fatalf("expected no related posts without a strategy, got %d (%v)", len(related), err)
+	}
+
+	// The post itself and unpublished posts are dropped whatever the strategy returns
+	strategy := &stubRelatedness{posts: []*post.Post{source, draft, other}}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, strategy, service.PostSettings{}, NewMockLogger())
+	related, err := postService.ListRelatedPosts(ctx, source, 0)
+	if err != nil {
+		t.Fatalf("failed to list related posts: %v", err)
+	}
+	if len(related) != 1 || related[0].ID != other.ID {
+		t.Errorf("expected only the other published post, got %v", related)
+	}
+
+	strategy.posts = []*post.Post{other, other, other}
+	if related, _ := postService.ListRelatedPosts(ctx, source, 2); len(related) != 2 {
+		t.Errorf("expected the limit applied, got %d posts", len(related))
+	}
+}
+
+func TestPostService_SetVisibility(t *testing.T) {
+	repo := NewMockPostRepository()
+	publisher := &MockEventPublisher{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, publisher, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	p, _ := postService.CreatePost(ctx, 1, "Quiet Post", "Test content with sufficient length.")
+
+	if _, err := postService.SetVisibility(ctx, 1, user.RoleAuthor, p.ID, "hidden"); err != post.ErrInvalidVisibility {
+		t.Errorf("expected ErrInvalidVisibility, got %v", err)
+	}
+	if _, err := postService.SetVisibility(ctx, 2, user.RoleAuthor, p.ID, post.VisibilityPrivate); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another author, got %v", err)
+	}
+	if _, err := postService.SetVisibility(ctx, 1, user.RoleAuthor, 999, post.VisibilityPrivate); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+
+	updated, err := postService.SetVisibility(ctx, 1, user.RoleAuthor, p.ID, post.VisibilityPrivate)
+	if err != nil {
+		t.Fatalf("failed to set visibility: %v", err)
+	}
+	if updated.Visibility != post.VisibilityPrivate || repo.posts[p.ID].Visibility != post.VisibilityPrivate {
+		t.Errorf("expected the post saved as private, got %q", repo.posts[p.ID].Visibility)
+	}
+	if updated.IsVisibleTo(0, "") || updated.IsVisibleTo(2, user.RoleReader) || !updated.IsVisibleTo(9, user.RoleAdmin) {
+		t.Error("expected the private post visible to its author and admins only")
+	}
+
+	// Making a published post public announces it; hiding it does not
+	events := len(publisher.events)
+	if _, err := postService.SetVisibility(ctx, 9, user.RoleAdmin, p.ID, post.VisibilityUnlisted); err != nil {
+		t.Fatalf("failed to set visibility as admin: %v", err)
+	}
+	if len(publisher.events) != events {
+		t.Errorf("expected no event for an unlisted post, got %v", publisher.last())
+	}
+	if _, err := postService.SetVisibility(ctx, 1, user.RoleAuthor, p.ID, post.VisibilityPublic); err != nil {
+		t.Fatalf("failed to set visibility: %v", err)
+	}
+	if e, ok := publisher.last().(service.PostPublished); !ok || e.Post.ID != p.ID {
+		t.Errorf("expected PostPublished for the post made public, got %v", publisher.last())
+	}
+}
+
+func TestPostService_TranslatePost(t *testing.T) {
+	repo := NewMockPostRepository()
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	p, _ := postService.CreatePost(ctx, 1, "Hello World", "Test content with sufficient length.")
+
+	if _, _, err := postService.TranslatePost(ctx, 2, user.RoleAuthor, p.ID, "fr", "Bonjour", "Contenu de test assez long."); err != post.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another author, got %v", err)
+	}
+	if _, _, err := postService.TranslatePost(ctx, 1, user.RoleAuthor, 999, "fr", "Bonjour", "Contenu de test assez long."); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, _, err := postService.TranslatePost(ctx, 1, user.RoleAuthor, p.ID, "en", "Hello", "Test content with sufficient length."); err != post.ErrSameLanguage {
+		t.Errorf("expected ErrSameLanguage, got %v", err)
+	}
+	if _, _, err := postService.TranslatePost(ctx, 1, user.RoleAuthor, p.ID, "!!", "Bonjour", "Contenu de test assez long."); err != post.ErrInvalidLanguage {
+		t.Errorf("expected ErrInvalidLanguage, got %v", err)
+	}
+
+	translated, created, err := postService.TranslatePost(ctx, 1, user.RoleAuthor, p.ID, "FR", "Bonjour", "Contenu de test assez long.")
+	if err != nil {
+		t.Fatalf("failed to translate post: %v", err)
+	}
+	if !created || translated.Language != "fr" || translated.Title != "Bonjour" || !slices.Equal(translated.Translations, []string{"en"}) {
+		t.Errorf("expected a new French translation, got created %v, %+v", created, translated)
+	}
+
+	// Translating to the same language again replaces the translation;
+	// admins may translate any post
+	translated, created, err = postService.TranslatePost(ctx, 9, user.RoleAdmin, p.ID, "fr", "Salut", "Contenu de test assez long.")
+	if err != nil {
+		t.Fatalf("failed to translate post as admin: %v", err)
+	}
+	if created || translated.Title != "Salut" || repo.translations[p.ID]["fr"].TranslatedBy != 9 {
+		t.Errorf("expected the translation replaced, got created %v, %+v", created, translated)
+	}
+
+	stored, _ := postService.GetPost(ctx, p.ID)
+	if stored.Title != "Hello World" || !slices.Equal(stored.Translations, []string{"fr"}) {
+		t.Errorf("expected the post untouched and translated to fr, got %q %v", stored.Title, stored.Translations)
+	}
+
+	localized, err := postService.LocalizePost(ctx, stored, "fr-CA, en;q=0.5")
+	if err != nil {
+		t.Fatalf("failed to localize post: %v", err)
+	}
+	if localized.Language != "fr" || localized.Title != "Salut" {
+		t.Errorf("expected the French translation, got %q %q", localized.Language, localized.Title)
+	}
+	localized, err = postService.LocalizePost(ctx, stored, "de")
+	if err != nil || localized != stored {
+		t.Errorf("expected the post itself for an unknown language, got %+v, %v", localized, err)
+	}
+}
+
+func TestPostService_Curation(t *testing.T) {
+	repo := NewMockPostRepository()
+	audit := &MockAuditLogger{}
+	postService := service.NewPostService(repo, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, audit, nil, &MockEventPublisher{}, nil, service.PostSettings{}, NewMockLogger())
+	ctx := context.Background()
+
+	first, _ := postService.CreatePost(ctx, 1, "First Post", "Test content with sufficient length.")
+	second, _ := postService.CreatePost(ctx, 1, "Second Post", "Test content with sufficient length.")
+	draft, _ := postService.CreateDraft(ctx, 1, "Draft Post", "Test content with sufficient length.")
+
+	pinned, err := postService.SetPinned(ctx, 9, first.ID, true)
+	if err != nil || !pinned.IsPinned() || !repo.posts[first.ID].IsPinned() {
+		t.Fatalf("expected the post pinned and saved, got %v", err)
+	}
+	if _, err := postService.SetPinned(ctx, 9, draft.ID, true); err != post.ErrNotPublished {
+		t.Errorf("expected ErrNotPublished for a draft, got %v", err)
+	}
+	if _, err := postService.SetFeatured(ctx, 9, 999, true); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+
+	for _, p := range []*post.Post{second, first} {
+		if _, err := postService.SetFeatured(ctx, 9, p.ID, true); err != nil {
+			t.Fatalf("failed to feature post: %v", err)
+		}
+	}
+	featured, err := postService.ListFeaturedPosts(ctx, 10, 0)
+	if err != nil || len(featured) != 2 {
+		t.Fatalf("expected 2 featured posts, got %d (%v)", len(featured), err)
+	}
+
+	if _, err := postService.SetFeatured(ctx, 9, second.ID, false); err != nil {
+		t.Fatalf("failed to unfeature post: %v", err)
+	}
+	if featured, _ := postService.ListFeaturedPosts(ctx, 10, 0); len(featured) != 1 || featured[0].ID != first.ID {
+		t.Errorf("expected only the first post featured, got %v", featured)
+	}
+
+	// Each change is audited under the admin who made it
+	actions := make([]string, 0, len(audit.events))
+	for _, event := range audit.events {
+		if event.UserID != 9 {
+			t.Errorf("expected events recorded for admin 9, got %d", event.UserID)
+		}
+		actions = append(actions, event.Action)
+	}
+	expected := []string{service.AuditActionPostPinned, service.AuditActionPostFeatured, service.AuditActionPostFeatured, service.AuditActionPostUnfeatured}
+	if !slices.Equal(actions, expected) {
+		t.Errorf("expected audit actions %v, got %v", expected, actions)
+	}
+}
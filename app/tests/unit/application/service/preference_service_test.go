@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/preference"
+	"blog-platform/internal/domain/tag"
+)
+
+// MockPreferenceRepository implements preference.Repository for testing
+type MockPreferenceRepository struct {
+	feeds map[string]*preference.Feed
+}
+
+func NewMockPreferenceRepository() *MockPreferenceRepository {
+	return &MockPreferenceRepository{feeds: make(map[string]*preference.Feed)}
+}
+
+func (m *MockPreferenceRepository) GetFeed(ctx context.Context, scope, key string) (*preference.Feed, error) {
+	f, ok := m.feeds[scope+":"+key]
+	if !ok {
+		return nil, preference.ErrFeedNotFound
+	}
+	return f, nil
+}
+
+func (m *MockPreferenceRepository) SaveFeed(ctx context.Context, f *preference.Feed) error {
+	m.feeds[f.Scope+":"+f.Key] = f
+	return nil
+}
+
+func TestPreferenceService_Implementation(t *testing.T) {
+	var _ preference.Service = service.NewPreferenceService(NewMockPreferenceRepository(), NewMockLogger())
+}
+
+func TestPreferenceService_AuthorFeed(t *testing.T) {
+	svc := service.NewPreferenceService(NewMockPreferenceRepository(), NewMockLogger())
+	ctx := context.Background()
+
+	f, err := svc.AuthorFeed(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, &preference.Feed{Scope: preference.ScopeAuthor, Key: "3"}, f, "authors without a preference get an empty one")
+
+	_, err = svc.SetAuthorFeed(ctx, 3, "Ada on Systems", "Notes on Go")
+	require.NoError(t, err)
+
+	f, err = svc.AuthorFeed(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada on Systems", f.Title)
+	assert.Equal(t, 3, f.UpdatedBy)
+
+	_, err = svc.SetAuthorFeed(ctx, 3, "Ada\non Systems", "")
+	assert.Equal(t, preference.ErrInvalidFeedTitle, err)
+}
+
+func TestPreferenceService_TagFeed(t *testing.T) {
+	svc := service.NewPreferenceService(NewMockPreferenceRepository(), NewMockLogger())
+	ctx := context.Background()
+
+	// Tags are stored under their normalized name
+	f, err := svc.SetTagFeed(ctx, 1, "Web Development", "Building the web", "")
+	require.NoError(t, err)
+	assert.Equal(t, "web-development", f.Key)
+
+	f, err = svc.TagFeed(ctx, "web_development")
+	require.NoError(t, err)
+	assert.Equal(t, "Building the web", f.Title)
+
+	_, err = svc.TagFeed(ctx, "c++")
+	assert.Equal(t, tag.ErrInvalidTag, err)
+}
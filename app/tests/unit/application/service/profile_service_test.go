@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/user"
+)
+
+// MockProfileRepository implements the user.ProfileRepository interface
+// for testing
+type MockProfileRepository struct {
+	profiles map[int]*user.Profile
+	// requested holds the IDs of every GetProfiles call
+	requested [][]int
+}
+
+func (m *MockProfileRepository) GetProfiles(ctx context.Context, ids []int) ([]*user.Profile, error) {
+	m.requested = append(m.requested, ids)
+	var profiles []*user.Profile
+	for _, id := range ids {
+		if p, ok := m.profiles[id]; ok {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles, nil
+}
+
+func (m *MockProfileRepository) UpdateProfile(ctx context.Context, id int, bio, avatarURL string) error {
+	p, ok := m.profiles[id]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	p.Bio, p.AvatarURL = bio, avatarURL
+	return nil
+}
+
+func TestProfileService_GetProfiles(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockProfileRepository{profiles: map[int]*user.Profile{
+		1: {ID: 1, Name: "Author One", PostCount: 3},
+		2: {ID: 2, Name: "Author Two"},
+	}}
+	profileService := service.NewProfileService(repo, NewMockLogger())
+
+	profiles, err := profileService.GetProfiles(ctx, []int{1, 2, 1, 9, 1})
+	if err != nil {
+		t.Fatalf("GetProfiles failed: %v", err)
+	}
+	if len(profiles) != 2 || profiles[1].PostCount != 3 {
+		t.Errorf("expected the profiles of users 1 and 2, got %v", profiles)
+	}
+	if got := repo.requested[0]; len(got) != 3 {
+		t.Errorf("expected each author to be fetched once, got %v", got)
+	}
+
+	if _, err := profileService.GetProfile(ctx, 9); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockProfileRepository{profiles: map[int]*user.Profile{1: {ID: 1, Name: "Author One"}}}
+	profileService := service.NewProfileService(repo, NewMockLogger())
+
+	profile, err := profileService.UpdateProfile(ctx, 1, " Writes about Go. ", "https://images.example.com/1.png")
+	if err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	if profile.Bio != "Writes about Go." || profile.AvatarURL != "https://images.example.com/1.png" {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	if _, err := profileService.UpdateProfile(ctx, 1, "", "ftp://images.example.com/1.png"); !errors.Is(err, user.ErrInvalidAvatarURL) {
+		t.Errorf("expected ErrInvalidAvatarURL, got %v", err)
+	}
+	if _, err := profileService.UpdateProfile(ctx, 9, "", ""); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
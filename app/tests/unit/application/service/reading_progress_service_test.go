@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+)
+
+// MockProgressRepository implements the post.ProgressRepository interface
+// for testing, keeping the latest position like the SQL upsert
+type MockProgressRepository struct {
+	progress map[[2]int]*post.Progress
+}
+
+func NewMockProgressRepository() *MockProgressRepository {
+	return &MockProgressRepository{progress: make(map[[2]int]*post.Progress)}
+}
+
+func (m *MockProgressRepository) Save(ctx context.Context, p *post.Progress) error {
+	key := [2]int{p.UserID, p.PostID}
+	if existing, ok := m.progress[key]; ok && !p.RecordedAt.After(existing.RecordedAt) {
+		return nil
+	}
+	stored := *p
+	m.progress[key] = &stored
+	return nil
+}
+
+func (m *MockProgressRepository) Get(ctx context.Context, userID, postID int) (*post.Progress, error) {
+	p, ok := m.progress[[2]int{userID, postID}]
+	if !ok {
+		return nil, post.ErrProgressNotFound
+	}
+	stored := *p
+	return &stored, nil
+}
+
+func (m *MockProgressRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*post.ReadingEntry, error) {
+	var entries []*post.ReadingEntry
+	for key, p := range m.progress {
+		if key[0] == userID {
+			entries = append(entries, &post.ReadingEntry{Progress: *p})
+		}
+	}
+	return entries, nil
+}
+
+func TestReadingProgressService_SaveProgress(t *testing.T) {
+	ctx := context.Background()
+	posts := NewMockPostRepository()
+	progressService := service.NewReadingProgressService(NewMockProgressRepository(), posts, NewMockLogger())
+
+	p, _ := post.NewPost("Long Read", "Content of a long read", 1)
+	posts.Create(ctx, p)
+
+	now := time.Now()
+	saved, err := progressService.SaveProgress(ctx, 2, p.ID, 40, "part-2", now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("SaveProgress failed: %v", err)
+	}
+	if saved.Percent != 40 || saved.Anchor != "part-2" {
+		t.Errorf("unexpected progress %+v", saved)
+	}
+
+	// A phone syncing late loses to the position read since on a laptop
+	if _, err := progressService.SaveProgress(ctx, 2, p.ID, 70, "part-3", now); err != nil {
+		t.Fatalf("SaveProgress failed: %v", err)
+	}
+	saved, err = progressService.SaveProgress(ctx, 2, p.ID, 10, "part-1", now.Add(-2*time.Minute))
+	if err != nil {
+		t.Fatalf("SaveProgress failed: %v", err)
+	}
+	if saved.Percent != 70 || saved.Anchor != "part-3" {
+		t.Errorf("expected the newer position to be kept, got %+v", saved)
+	}
+
+	// Positions from the future are taken as now, so other devices can
+	// still move the position on afterwards
+	saved, _ = progressService.SaveProgress(ctx, 2, p.ID, 80, "", now.Add(24*time.Hour))
+	if saved.RecordedAt.After(time.Now()) {
+		t.Errorf("expected the recording time to be clamped, got %v", saved.RecordedAt)
+	}
+	time.Sleep(time.Millisecond)
+	saved, _ = progressService.SaveProgress(ctx, 2, p.ID, 85, "", time.Time{})
+	if saved.Percent != 85 {
+		t.Errorf("expected a later update to win after a clamped one, got %+v", saved)
+	}
+
+	// Moving back is allowed, as long as it is the latest position
+	time.Sleep(time.Millisecond)
+	saved, _ = progressService.SaveProgress(ctx, 2, p.ID, 5, "", time.Time{})
+	if saved.Percent != 5 {
+		t.Errorf("expected the latest position to win, got %+v", saved)
+	}
+}
+
+func TestReadingProgressService_SaveProgress_Errors(t *testing.T) {
+	ctx := context.Background()
+	posts := NewMockPostRepository()
+	progressService := service.NewReadingProgressService(NewMockProgressRepository(), posts, NewMockLogger())
+
+	p, _ := post.NewPost("Long Read", "Content of a long read", 1)
+	posts.Create(ctx, p)
+	draft, _ := post.NewDraft("Unpublished", "Content of the draft", 1)
+	posts.Create(ctx, draft)
+
+	for _, percent := range []float64{-1, 100.5} {
+		if _, err := progressService.SaveProgress(ctx, 2, p.ID, percent, "", time.Time{}); !errors.Is(err, post.ErrInvalidPercent) {
+			t.Errorf("percent %v: expected ErrInvalidPercent, got %v", percent, err)
+		}
+	}
+	if _, err := progressService.SaveProgress(ctx, 2, 999, 10, "", time.Time{}); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := progressService.SaveProgress(ctx, 2, draft.ID, 10, "", time.Time{}); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected drafts to be hidden, got %v", err)
+	}
+	if _, err := progressService.GetProgress(ctx, 2, p.ID); !errors.Is(err, post.ErrProgressNotFound) {
+		t.Errorf("expected ErrProgressNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/notification"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// MockPusher records the pushed messages by user for testing
+type MockPusher struct {
+	pushed map[int][]service.RealtimeMessage
+}
+
+func (m *MockPusher) Push(userID int, msg service.RealtimeMessage) {
+	if m.pushed == nil {
+		m.pushed = make(map[int][]service.RealtimeMessage)
+	}
+	m.pushed[userID] = append(m.pushed[userID], msg)
+}
+
+func TestSubscribeRealtime_PushesNotificationsAndFollowers(t *testing.T) {
+	ctx := context.Background()
+	bus := service.NewEventBus(NewMockLogger())
+	pusher := &MockPusher{}
+	service.SubscribeRealtime(bus, pusher)
+
+	// Saved notifications are pushed to their recipient
+	posts := NewMockPostRepository()
+	p, _ := post.NewPost("Pushed Post", "Content of the pushed post", 1)
+	posts.Create(ctx, p)
+	repo := &MockNotificationRepository{}
+	notificationService := service.NewNotificationService(repo, posts, NewMockCommentRepository(), bus, NewMockLogger())
+	notificationService.Subscribe(bus)
+	commentService := service.NewCommentService(NewMockCommentRepository(), posts, bus, service.CommentSettings{}, NewMockLogger())
+	c, err := commentService.AddComment(ctx, p.ID, 2, "", "Reader Two", "A comment worth pushing")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	if len(pusher.pushed[1]) != 1 {
+		t.Fatalf("expected one message pushed to the post author, got %v", pusher.pushed)
+	}
+	msg := pusher.pushed[1][0]
+	data, ok := msg.Data.(service.RealtimeNotificationData)
+	if msg.Type != service.RealtimeNotification || !ok {
+		t.Fatalf("expected a notification message, got %+v", msg)
+	}
+	if data.ID != repo.notifications[0].ID || data.Kind != string(notification.KindComment) || data.PostID != p.ID || data.CommentID != c.ID || data.ActorName != "Reader Two" || data.Read {
+		t.Errorf("unexpected notification data %+v", data)
+	}
+
+	// New followers are pushed to the followed user only
+	users := NewMockUserRepository()
+	for _, name := range []string{"One", "Two"} {
+		users.Create(ctx, &user.User{Name: name})
+	}
+	followService := service.NewFollowService(&MockFollowRepository{follows: make(map[int][]int)}, users, bus, NewMockLogger())
+	if err := followService.Follow(ctx, 1, 2); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if len(pusher.pushed[2]) != 1 || len(pusher.pushed[1]) != 1 {
+		t.Fatalf("expected one message pushed to the followed user, got %v", pusher.pushed)
+	}
+	msg = pusher.pushed[2][0]
+	if follower, ok := msg.Data.(service.RealtimeFollowerData); msg.Type != service.RealtimeFollower || !ok || follower.FollowerID != 1 || follower.FollowedAt.IsZero() {
+		t.Errorf("unexpected follower message %+v", msg)
+	}
+
+	// A failed follow pushes nothing
+	if err := followService.Follow(ctx, 2, 2); err == nil {
+		t.Fatal("expected following oneself to fail")
+	}
+	if len(pusher.pushed[2]) != 1 {
+		t.Errorf("expected no message for a failed follow, got %v", pusher.pushed[2])
+	}
+}
+
+func TestFollowService_PublishesUserFollowed(t *testing.T) {
+	ctx := context.Background()
+	users := NewMockUserRepository()
+	for _, name := range []string{"One", "Two"} {
+		users.Create(ctx, &user.User{Name: name})
+	}
+	events := &MockEventPublisher{}
+	followService := service.NewFollowService(&MockFollowRepository{follows: make(map[int][]int)}, users, events, NewMockLogger())
+
+	if err := followService.Follow(ctx, 2, 1); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if followed, ok := events.last().(service.UserFollowed); !ok || followed.FollowerID != 2 || followed.FolloweeID != 1 {
+		t.Errorf("expected UserFollowed, got %v", events.last())
+	}
+	if err := followService.Unfollow(ctx, 2, 1); err != nil {
+		t.Fatalf("Unfollow failed: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Errorf("expected unfollowing to publish nothing, got %v", events.events)
+	}
+}
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+// MockRedirectRepository implements the post.RedirectRepository interface
+// for testing
+type MockRedirectRepository struct {
+	redirects map[int]*post.Redirect
+	nextID    int
+}
+
+func NewMockRedirectRepository() *MockRedirectRepository {
+	return &MockRedirectRepository{redirects: make(map[int]*post.Redirect), nextID: 1}
+}
+
+func (m *MockRedirectRepository) Create(ctx context.Context, rd *post.Redirect) error {
+	if _, err := m.GetBySlug(ctx, rd.FromSlug); err == nil {
+		return post.ErrRedirectExists
+	}
+	rd.ID = m.nextID
+	m.nextID++
+	m.redirects[rd.ID] = rd
+	return nil
+}
+
+func (m *MockRedirectRepository) GetByID(ctx context.Context, id int) (*post.Redirect, error) {
+	if rd, ok := m.redirects[id]; ok {
+		copied := *rd
+		return &copied, nil
+	}
+	return nil, post.ErrRedirectNotFound
+}
+
+func (m *MockRedirectRepository) GetBySlug(ctx context.Context, slug string) (*post.Redirect, error) {
+	for _, rd := range m.redirects {
+		if rd.FromSlug == slug {
+			return rd, nil
+		}
+	}
+	return nil, post.ErrRedirectNotFound
+}
+
+func (m *MockRedirectRepository) List(ctx context.Context, limit, offset int) ([]*post.Redirect, error) {
+	var redirects []*post.Redirect
+	for _, rd := range m.redirects {
+		redirects = append(redirects, rd)
+	}
+	return redirects, nil
+}
+
+func (m *MockRedirectRepository) Update(ctx context.Context, rd *post.Redirect) error {
+	if other, err := m.GetBySlug(ctx, rd.FromSlug); err == nil && other.ID != rd.ID {
+		return post.ErrRedirectExists
+	}
+	m.redirects[rd.ID] = rd
+	return nil
+}
+
+func (m *MockRedirectRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.redirects[id]; !ok {
+		return post.ErrRedirectNotFound
+	}
+	delete(m.redirects, id)
+	return nil
+}
+
+func newRedirectTestPosts() *MockPostRepository {
+	repo := NewMockPostRepository()
+	now := time.Now()
+	repo.posts[1] = &post.Post{ID: 1, Title: "Hello", Slug: "hello", AuthorID: 1, Status: post.StatusPublished, PublishedAt: &now}
+	repo.posts[2] = &post.Post{ID: 2, Title: "Other", Slug: "other", AuthorID: 2, Status: post.StatusPublished, PublishedAt: &now}
+	return repo
+}
+
+func TestRedirectService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	redirects := NewMockRedirectRepository()
+	redirects.redirects[1] = &post.Redirect{ID: 1, FromSlug: "hello-world", PostID: 1, Automatic: true}
+	redirectService := service.NewRedirectService(redirects, newRedirectTestPosts(), &MockAuditLogger{}, NewMockLogger())
+
+	p, err := redirectService.Resolve(ctx, 0, "", "hello")
+	if err != nil || p.ID != 1 {
+		t.Fatalf("expected the post with the slug, got %v, %v", p, err)
+	}
+	p, err = redirectService.Resolve(ctx, 0, "", "hello-world")
+	if err != nil || p.ID != 1 || p.Slug != "hello" {
+		t.Errorf("expected the old slug to resolve to post 1, got %v, %v", p, err)
+	}
+	if _, err := redirectService.Resolve(ctx, 0, "", "missing"); !errors.Is(err, post.ErrPostNotFound) {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestRedirectService_ResolveHidesPostsFromOthers(t *testing.T) {
+	ctx := context.Background()
+	posts := newRedirectTestPosts()
+	posts.posts[3] = &post.Post{ID: 3, Title: "Draft", Slug: "draft", AuthorID: 2, Status: post.StatusDraft}
+	posts.posts[2].Visibility = post.VisibilityPrivate
+	redirects := NewMockRedirectRepository()
+	redirects.redirects[1] = &post.Redirect{ID: 1, FromSlug: "old-draft", PostID: 3}
+	redirectService := service.NewRedirectService(redirects, posts, &MockAuditLogger{}, NewMockLogger())
+
+	for _, slug := range []string{"other", "draft", "old-draft"} {
+		if _, err := redirectService.Resolve(ctx, 0, "", slug); !errors.Is(err, post.ErrPostNotFound) {
+			t.Errorf("%s: expected ErrPostNotFound for anonymous readers, got %v", slug, err)
+		}
+		if _, err := redirectService.Resolve(ctx, 1, user.RoleAuthor, slug); !errors.Is(err, post.ErrPostNotFound) {
+			t.Errorf("%s: expected ErrPostNotFound for other authors, got %v", slug, err)
+		}
+		if _, err := redirectService.Resolve(ctx, 2, user.RoleAuthor, slug); err != nil {
+			t.Errorf("%s: expected the author to find the post, got %v", slug, err)
+		}
+		if _, err := redirectService.Resolve(ctx, 9, user.RoleAdmin, slug); err != nil {
+			t.Errorf("%s: expected admins to find the post, got %v", slug, err)
+		}
+	}
+}
+
+func TestRedirectService_ManageRedirects(t *testing.T) {
+	ctx := context.Background()
+	redirects := NewMockRedirectRepository()
+	audit := &MockAuditLogger{}
+	redirectService := service.NewRedirectService(redirects, newRedirectTestPosts(), audit, NewMockLogger())
+
+	rd, err := redirectService.CreateRedirect(ctx, 9, "legacy-hello", 1)
+	if err != nil {
+		t.Fatalf("CreateRedirect failed: %v", err)
+	}
+	if rd.Automatic {
+		t.Error("expected redirects added by admins to be manual")
+	}
+
+	tests := []struct {
+		name     string
+		fromSlug string
+		postID   int
+		want     error
+	}{
+		{"invalid slug", "Legacy Hello", 1, post.ErrInvalidSlug},
+		{"slug of a post", "other", 1, post.ErrSlugTaken},
+		{"missing post", "legacy-missing", 99, post.ErrPostNotFound},
+		{"existing redirect", "legacy-hello", 2, post.ErrRedirectExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := redirectService.CreateRedirect(ctx, 9, tt.fromSlug, tt.postID); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	redirects.redirects[rd.ID].Automatic = true
+	updated, err := redirectService.UpdateRedirect(ctx, 9, rd.ID, "legacy-other", 2)
+	if err != nil {
+		t.Fatalf("UpdateRedirect failed: %v", err)
+	}
+	if updated.FromSlug != "legacy-other" || updated.PostID != 2 || updated.Automatic {
+		t.Errorf("expected a manual redirect from legacy-other to post 2, got %+v", updated)
+	}
+
+	if err := redirectService.DeleteRedirect(ctx, 9, rd.ID); err != nil {
+		t.Fatalf("DeleteRedirect failed: %v", err)
+	}
+	if err := redirectService.DeleteRedirect(ctx, 9, rd.ID); !errors.Is(err, post.ErrRedirectNotFound) {
+		t.Errorf("expected ErrRedirectNotFound, got %v", err)
+	}
+
+	actions := make([]string, len(audit.events))
+	for i, event := range audit.events {
+		actions[i] = event.Action
+		if event.UserID != 9 {
+			t.Errorf("expected events by admin 9, got %d", event.UserID)
+		}
+	}
+	want := []string{service.AuditActionRedirectCreated, service.AuditActionRedirectUpdated, service.AuditActionRedirectDeleted}
+	if len(actions) != len(want) || actions[0] != want[0] || actions[1] != want[1] || actions[2] != want[2] {
+		t.Errorf("expected audit actions %v, got %v", want, actions)
+	}
+}
+
+func TestPostService_ChangeSlug(t *testing.T) {
+	ctx := context.Background()
+	repo := newRedirectTestPosts()
+	postService := newTestPostService(repo)
+
+	p, err := postService.ChangeSlug(ctx, 1, user.RoleAuthor, 1, "hello-again")
+	if err != nil {
+		t.Fatalf("ChangeSlug failed: %v", err)
+	}
+	if p.Slug != "hello-again" || repo.posts[1].Slug != "hello-again" {
+		t.Errorf("expected the slug to change, got %q", p.Slug)
+	}
+
+	tests := []struct {
+		name   string
+		userID int
+		slug   string
+		want   error
+	}{
+		{"invalid slug", 1, "Hello Again", post.ErrInvalidSlug},
+		{"slug of another post", 1, "other", post.ErrSlugTaken},
+		{"another author", 2, "mine-now", post.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := postService.ChangeSlug(ctx, tt.userID, user.RoleAuthor, 1, tt.slug); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/scim"
+)
+
+// MockTxManager implements service.TxManager for testing, running units of
+// work without a transaction
+type MockTxManager struct {
+	units  int
+	failed int
+}
+
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.units++
+	if err := fn(ctx); err != nil {
+		m.failed++
+		return err
+	}
+	return nil
+}
+
+// MockScimAccountRepository implements scim.AccountRepository for testing
+type MockScimAccountRepository struct {
+	accounts []*scim.Account
+}
+
+func (m *MockScimAccountRepository) Create(ctx context.Context, account *scim.Account) error {
+	for _, existing := range m.accounts {
+		if existing.UserID == account.UserID {
+			return scim.ErrUserClaimed
+		}
+	}
+	copied := *account
+	m.accounts = append(m.accounts, &copied)
+	return nil
+}
+
+func (m *MockScimAccountRepository) find(match func(*scim.Account) bool) (*scim.Account, error) {
+	for _, account := range m.accounts {
+		if match(account) {
+			copied := *account
+			return &copied, nil
+		}
+	}
+	return nil, scim.ErrUserNotFound
+}
+
+func (m *MockScimAccountRepository) Get(ctx context.Context, tenant string, userID int) (*scim.Account, error) {
+	return m.find(func(a *scim.Account) bool { return a.Tenant == tenant && a.UserID == userID })
+}
+
+func (m *MockScimAccountRepository) GetByUserName(ctx context.Context, tenant, userName string) (*scim.Account, error) {
+	return m.find(func(a *scim.Account) bool { return a.Tenant == tenant && a.UserName == userName })
+}
+
+func (m *MockScimAccountRepository) GetByExternalID(ctx context.Context, tenant, externalID string) (*scim.Account, error) {
+	return m.find(func(a *scim.Account) bool { return a.Tenant == tenant && a.ExternalID == externalID })
+}
+
+func (m *MockScimAccountRepository) List(ctx context.Context, tenant string, offset, limit int) ([]*scim.Account, int, error) {
+	var result []*scim.Account
+	for _, account := range m.accounts {
+		if account.Tenant == tenant {
+			result = append(result, account)
+		}
+	}
+	total := len(result)
+	result = result[min(offset, total):min(offset+limit, total)]
+	return result, total, nil
+}
+
+func (m *MockScimAccountRepository) Update(ctx context.Context, account *scim.Account) error {
+	for i, existing := range m.accounts {
+		if existing.Tenant == account.Tenant && existing.UserID == account.UserID {
+			copied := *account
+			m.accounts[i] = &copied
+			return nil
+		}
+	}
+	return scim.ErrUserNotFound
+}
+
+func (m *MockScimAccountRepository) Delete(ctx context.Context, tenant string, userID int) error {
+	before := len(m.accounts)
+	m.accounts = slices.DeleteFunc(m.accounts, func(a *scim.Account) bool { return a.Tenant == tenant && a.UserID == userID })
+	if len(m.accounts) == before {
+		return scim.ErrUserNotFound
+	}
+	return nil
+}
+
+// MockScimGroupRepository implements scim.GroupRepository for testing
+type MockScimGroupRepository struct {
+	groups []*scim.Group
+	nextID int
+}
+
+func (m *MockScimGroupRepository) Create(ctx context.Context, g *scim.Group) error {
+	if _, err := m.GetByDisplayName(ctx, g.Tenant, g.DisplayName); err == nil {
+		return scim.ErrGroupExists
+	}
+	m.nextID++
+	g.ID = m.nextID
+	copied := *g
+	m.groups = append(m.groups, &copied)
+	return nil
+}
+
+func (m *MockScimGroupRepository) GetByID(ctx context.Context, tenant string, id int) (*scim.Group, error) {
+	for _, g := range m.groups {
+		if g.Tenant == tenant && g.ID == id {
+			copied := *g
+			return &copied, nil
+		}
+	}
+	return nil, scim.ErrGroupNotFound
+}
+
+func (m *MockScimGroupRepository) GetByDisplayName(ctx context.Context, tenant, displayName string) (*scim.Group, error) {
+	for _, g := range m.groups {
+		if g.Tenant == tenant && g.DisplayName == displayName {
+			copied := *g
+			return &copied, nil
+		}
+	}
+	return nil, scim.ErrGroupNotFound
+}
+
+func (m *MockScimGroupRepository) List(ctx context.Context, tenant string, offset, limit int) ([]*scim.Group, int, error) {
+	var result []*scim.Group
+	for _, g := range m.groups {
+		if g.Tenant == tenant {
+			result = append(result, g)
+		}
+	}
+	return result, len(result), nil
+}
+
+func (m *MockScimGroupRepository) Update(ctx context.Context, g *scim.Group) error {
+	for i, existing := range m.groups {
+		if existing.ID == g.ID {
+			copied := *g
+			m.groups[i] = &copied
+			return nil
+		}
+	}
+	return scim.ErrGroupNotFound
+}
+
+func (m *MockScimGroupRepository) Delete(ctx context.Context, tenant string, id int) error {
+	if _, err := m.GetByID(ctx, tenant, id); err != nil {
+		return err
+	}
+	m.groups = slices.DeleteFunc(m.groups, func(g *scim.Group) bool { return g.ID == id })
+	return nil
+}
+
+func (m *MockScimGroupRepository) ListByMember(ctx context.Context, tenant string, userID int) ([]scim.GroupRef, error) {
+	var refs []scim.GroupRef
+	for _, g := range m.groups {
+		if g.Tenant == tenant && slices.Contains(g.Members, userID) {
+			refs = append(refs, scim.GroupRef{ID: g.ID, DisplayName: g.DisplayName})
+		}
+	}
+	return refs, nil
+}
+
+func newTestSCIMService(t *testing.T) (*service.SCIMService, *MockUserService, *MockRefreshTokenRepository, *MockAuditLogger) {
+	t.Helper()
+	scimService, userService, refreshTokens, audit, _ := newTestSCIMServiceWithTx(t)
+	return scimService, userService, refreshTokens, audit
+}
+
+func newTestSCIMServiceWithTx(t *testing.T) (*service.SCIMService, *MockUserService, *MockRefreshTokenRepository, *MockAuditLogger, *MockTxManager) {
+	t.Helper()
+	userService := NewMockUserService()
+	refreshTokens := NewMockRefreshTokenRepository()
+	audit := &MockAuditLogger{}
+	tx := &MockTxManager{}
+	scimService := service.NewSCIMService(userService, &MockScimAccountRepository{}, &MockScimGroupRepository{}, refreshTokens, audit, tx, service.SCIMSettings{}, NewMockLogger())
+	return scimService, userService, refreshTokens, audit, tx
+}
+
+func TestSCIMService_Implementation(t *testing.T) {
+	var _ scim.Service = (*service.SCIMService)(nil)
+}
+
+func TestSCIMService_CreateUser(t *testing.T) {
+	scimService, userService, _, audit := newTestSCIMService(t)
+	ctx := context.Background()
+
+	created, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", ExternalID: "00u1", Name: "Jane Doe", Active: true})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@acme.com", created.Email, "the email defaults to the user name")
+	assert.Equal(t, "00u1", created.ExternalID)
+	assert.True(t, created.Active)
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, service.AuditActionUserProvisioned, audit.events[0].Action)
+
+	_, err = scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", Email: "jane.doe@acme.com", Active: true})
+	assert.ErrorIs(t, err, scim.ErrUserClaimed, "user names are unique per tenant")
+
+	_, err = userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+	_, err = scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "john@example.com", Active: true})
+	assert.ErrorIs(t, err, scim.ErrUserClaimed, "existing accounts are not taken over")
+
+	_, err = scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "not an email", Active: true})
+	assert.ErrorIs(t, err, scim.ErrInvalidValue)
+
+	inactive, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "bob@acme.com", Active: false})
+	require.NoError(t, err)
+	assert.False(t, inactive.Active)
+}
+
+func TestSCIMService_CreateUserRunsInOneTransaction(t *testing.T) {
+	scimService, userService, _, audit, tx := newTestSCIMServiceWithTx(t)
+	ctx := context.Background()
+
+	_, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "bob@acme.com", Active: false})
+	require.NoError(t, err)
+	assert.Equal(t, 1, tx.units, "registration, link and deactivation share a unit of work")
+	assert.Zero(t, tx.failed)
+
+	_, err = userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+	_, err = scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "john@example.com", Active: true})
+	assert.ErrorIs(t, err, scim.ErrUserClaimed)
+	assert.Equal(t, 1, tx.failed, "a refused provisioning rolls back")
+	assert.Len(t, audit.events, 1, "only the committed provisioning is audited")
+}
+
+func TestSCIMService_TenantIsolation(t *testing.T) {
+	scimService, _, _, _ := newTestSCIMService(t)
+	ctx := context.Background()
+
+	created, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", Active: true})
+	require.NoError(t, err)
+
+	_, err = scimService.GetUser(ctx, "globex", created.ID)
+	assert.ErrorIs(t, err, scim.ErrUserNotFound)
+	assert.ErrorIs(t, scimService.DeleteUser(ctx, "globex", created.ID), scim.ErrUserNotFound)
+
+	users, total, err := scimService.ListUsers(ctx, "globex", nil, scim.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Zero(t, total)
+
+	_, err = scimService.CreateGroup(ctx, "globex", "Engineering", "", []int{created.ID})
+	assert.ErrorIs(t, err, scim.ErrInvalidValue, "groups only hold users of their tenant")
+}
+
+func TestSCIMService_ListUsersWithFilter(t *testing.T) {
+	scimService, _, _, _ := newTestSCIMService(t)
+	ctx := context.Background()
+
+	jane, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", ExternalID: "00u1", Active: true})
+	require.NoError(t, err)
+	_, err = scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "john@acme.com", Active: true})
+	require.NoError(t, err)
+
+	for _, filter := range []scim.Filter{
+		{Attribute: scim.FilterUserName, Value: "jane@acme.com"},
+		{Attribute: scim.FilterExternalID, Value: "00u1"},
+		{Attribute: scim.FilterEmail, Value: "jane@acme.com"},
+	} {
+		users, total, err := scimService.ListUsers(ctx, "acme", &filter, scim.Page{})
+		require.NoError(t, err)
+		require.Equal(t, 1, total, "filter %s", filter.Attribute)
+		assert.Equal(t, jane.ID, users[0].ID)
+	}
+
+	users, total, err := scimService.ListUsers(ctx, "acme", &scim.Filter{Attribute: scim.FilterUserName, Value: "nobody@acme.com"}, scim.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Zero(t, total)
+
+	_, _, err = scimService.ListUsers(ctx, "acme", &scim.Filter{Attribute: scim.FilterDisplayName, Value: "Jane"}, scim.Page{})
+	assert.ErrorIs(t, err, scim.ErrInvalidFilter)
+
+	users, total, err = scimService.ListUsers(ctx, "acme", nil, scim.Page{StartIndex: 2, Count: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "john@acme.com", users[0].UserName)
+}
+
+func TestSCIMService_DeactivateAndDelete(t *testing.T) {
+	scimService, userService, refreshTokens, audit := newTestSCIMService(t)
+	ctx := context.Background()
+
+	created, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", Active: true})
+	require.NoError(t, err)
+	require.NoError(t, refreshTokens.Create(ctx, &auth.RefreshToken{UserID: created.ID, TokenHash: "session", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	patched, err := scimService.PatchUser(ctx, "acme", created.ID, []scim.PatchOperation{
+		{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+	})
+	require.NoError(t, err)
+	assert.False(t, patched.Active)
+	assert.NotNil(t, refreshTokens.tokens["session"].RevokedAt, "sessions end on deactivation")
+
+	replaced, err := scimService.ReplaceUser(ctx, "acme", created.ID, scim.UserAttributes{UserName: "jane@acme.com", Name: "Jane Roe", Active: true})
+	require.NoError(t, err)
+	assert.True(t, replaced.Active)
+	assert.Equal(t, "Jane Roe", replaced.Name)
+
+	require.NoError(t, scimService.DeleteUser(ctx, "acme", created.ID))
+	_, err = scimService.GetUser(ctx, "acme", created.ID)
+	assert.ErrorIs(t, err, scim.ErrUserNotFound)
+
+	u, err := userService.GetByID(ctx, created.ID)
+	require.NoError(t, err, "the account is kept")
+	assert.True(t, u.IsDeactivated())
+	assert.Equal(t, service.AuditActionUserDeprovisioned, audit.events[len(audit.events)-1].Action)
+}
+
+func TestSCIMService_Groups(t *testing.T) {
+	scimService, _, _, _ := newTestSCIMService(t)
+	ctx := context.Background()
+
+	jane, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "jane@acme.com", Active: true})
+	require.NoError(t, err)
+	john, err := scimService.CreateUser(ctx, "acme", scim.UserAttributes{UserName: "john@acme.com", Active: true})
+	require.NoError(t, err)
+
+	g, err := scimService.CreateGroup(ctx, "acme", "Engineering", "g1", []int{jane.ID})
+	require.NoError(t, err)
+	_, err = scimService.CreateGroup(ctx, "acme", "Engineering", "", nil)
+	assert.ErrorIs(t, err, scim.ErrGroupExists)
+
+	g, err = scimService.PatchGroup(ctx, "acme", g.ID, []scim.PatchOperation{
+		{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + strconv.Itoa(john.ID) + `"}]`)},
+		{Op: "remove", Path: `members[value eq "` + strconv.Itoa(jane.ID) + `"]`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{john.ID}, g.Members)
+
+	u, err := scimService.GetUser(ctx, "acme", john.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []scim.GroupRef{{ID: g.ID, DisplayName: "Engineering"}}, u.Groups)
+
+	groups, total, err := scimService.ListGroups(ctx, "acme", &scim.Filter{Attribute: scim.FilterDisplayName, Value: "Engineering"}, scim.Page{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, g.ID, groups[0].ID)
+
+	require.NoError(t, scimService.DeleteGroup(ctx, "acme", g.ID))
+	_, err = scimService.GetGroup(ctx, "acme", g.ID)
+	assert.ErrorIs(t, err, scim.ErrGroupNotFound)
+}
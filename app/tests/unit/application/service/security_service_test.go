@@ -0,0 +1,224 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/user"
+)
+
+// MockPasswordResetRepository implements user.PasswordResetRepository for testing
+type MockPasswordResetRepository struct {
+	users  *MockUserRepository
+	resets map[int]*user.PasswordReset
+	nextID int
+}
+
+func NewMockPasswordResetRepository(users *MockUserRepository) *MockPasswordResetRepository {
+	return &MockPasswordResetRepository{
+		users:  users,
+		resets: make(map[int]*user.PasswordReset),
+		nextID: 1,
+	}
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, r *user.PasswordReset) error {
+	for id, existing := range m.resets {
+		if existing.UserID == r.UserID {
+			delete(m.resets, id)
+		}
+	}
+	r.ID = m.nextID
+	m.nextID++
+	m.resets[r.ID] = r
+	return nil
+}
+
+func (m *MockPasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.PasswordReset, error) {
+	for _, r := range m.resets {
+		if r.TokenHash == tokenHash {
+			return r, nil
+		}
+	}
+	return nil, user.ErrPasswordResetNotFound
+}
+
+func (m *MockPasswordResetRepository) Complete(ctx context.Context, r *user.PasswordReset, passwordHash string) error {
+	u, exists := m.users.users[r.UserID]
+	if !exists {
+		return user.ErrUserNotFound
+	}
+	u.PasswordHash = passwordHash
+	u.PasswordResetRequiredAt = nil
+	delete(m.resets, r.ID)
+	return nil
+}
+
+func (m *MockPasswordResetRepository) Delete(ctx context.Context, id int) error {
+	if _, exists := m.resets[id]; !exists {
+		return user.ErrPasswordResetNotFound
+	}
+	delete(m.resets, id)
+	return nil
+}
+
+// MockReportTokens implements user.ReportTokens for testing
+type MockReportTokens struct{}
+
+func (m *MockReportTokens) Issue(userID int, expiresAt time.Time) string {
+	return fmt.Sprintf("report-%d", userID)
+}
+
+func (m *MockReportTokens) Verify(token string) (int, error) {
+	var userID int
+	if _, err := fmt.Sscanf(token, "report-%d", &userID); err != nil {
+		return 0, user.ErrInvalidReportToken
+	}
+	return userID, nil
+}
+
+type securityTestEnv struct {
+	svc           *service.SecurityService
+	users         *MockUserRepository
+	resets        *MockPasswordResetRepository
+	refreshTokens *MockRefreshTokenRepository
+	mailer        *MockMailer
+	jobs          *MockJobQueue
+	audit         *MockAuditLogger
+	events        *MockEventPublisher
+}
+
+func newSecurityTestEnv() *securityTestEnv {
+	env := &securityTestEnv{
+		users:         NewMockUserRepository(),
+		refreshTokens: NewMockRefreshTokenRepository(),
+		mailer:        &MockMailer{},
+		jobs:          &MockJobQueue{},
+		audit:         &MockAuditLogger{},
+		events:        &MockEventPublisher{},
+	}
+	env.resets = NewMockPasswordResetRepository(env.users)
+	env.svc = service.NewSecurityService(env.users, env.resets, &MockReportTokens{}, env.refreshTokens, env.mailer, env.jobs, env.audit, env.events, service.SecuritySettings{
+		BaseURL: "https://blog.example.com",
+	}, NewMockLogger())
+	return env
+}
+
+func (env *securityTestEnv) addUser(t *testing.T, email, password string) *user.User {
+	t.Helper()
+	u, err := user.NewUser("Jane", email, password)
+	require.NoError(t, err)
+	require.NoError(t, env.users.Create(context.Background(), u))
+	return u
+}
+
+// runQueuedNotifications runs the queued security notification jobs
+func runQueuedNotifications(t *testing.T, env *securityTestEnv) {
+	t.Helper()
+	for i, kind := range env.jobs.kinds {
+		require.Equal(t, service.JobSecurityNotification, kind)
+		payload, err := json.Marshal(env.jobs.payloads[i])
+		require.NoError(t, err)
+		require.NoError(t, env.svc.SendNotification(context.Background(), payload))
+	}
+}
+
+func TestSecurityService_Implementation(t *testing.T) {
+	env := newSecurityTestEnv()
+	var _ user.SecurityService = env.svc
+	var _ service.SecurityEvents = env.svc
+}
+
+func TestSecurityService_SendsNotifications(t *testing.T) {
+	env := newSecurityTestEnv()
+	u := env.addUser(t, "jane@example.com", "password123")
+	ctx := context.Background()
+
+	env.svc.Publish(ctx, service.SecurityEvent{Kind: service.SecurityEventPasswordChanged, UserID: u.ID})
+	env.svc.Publish(ctx, service.SecurityEvent{Kind: service.SecurityEventEmailChanged, UserID: u.ID, Email: "old@example.com", Detail: "jane@example.com"})
+	env.svc.Publish(ctx, service.SecurityEvent{Kind: service.SecurityEventTwoFactorDisabled, UserID: u.ID})
+	assert.Empty(t, env.mailer.sent, "notifications are sent by the queued jobs")
+
+	runQueuedNotifications(t, env)
+
+	require.Len(t, env.mailer.sent, 3)
+	reportURL := "https://blog.example.com/api/v1/users/security/report?token=report-1"
+
+	assert.Equal(t, "jane@example.com", env.mailer.sent[0].To)
+	assert.Equal(t, "Your password was changed", env.mailer.sent[0].Subject)
+	assert.Contains(t, env.mailer.sent[0].Body, reportURL)
+
+	assert.Equal(t, "old@example.com", env.mailer.sent[1].To)
+	assert.Contains(t, env.mailer.sent[1].Body, "changed to jane@example.com")
+	assert.Contains(t, env.mailer.sent[1].Body, reportURL)
+
+	assert.Equal(t, "Two-factor authentication was turned off", env.mailer.sent[2].Subject)
+}
+
+func TestSecurityService_SendNotification_DeletedUser(t *testing.T) {
+	env := newSecurityTestEnv()
+
+	env.svc.Publish(context.Background(), service.SecurityEvent{Kind: service.SecurityEventPasswordChanged, UserID: 42})
+	runQueuedNotifications(t, env)
+
+	assert.Empty(t, env.mailer.sent)
+}
+
+func TestSecurityService_ReportUnauthorizedChange(t *testing.T) {
+	env := newSecurityTestEnv()
+	u := env.addUser(t, "jane@example.com", "password123")
+	ctx := context.Background()
+	session, _, err := auth.NewRefreshToken(u.ID, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, env.refreshTokens.Create(ctx, session))
+
+	assert.ErrorIs(t, env.svc.ReportUnauthorizedChange(ctx, "forged"), user.ErrInvalidReportToken)
+	assert.False(t, u.IsPasswordResetRequired())
+
+	require.NoError(t, env.svc.ReportUnauthorizedChange(ctx, "report-1"))
+
+	assert.True(t, u.IsPasswordResetRequired())
+	assert.True(t, session.IsRevoked(), "sessions are ended")
+	require.Len(t, env.audit.events, 1)
+	assert.Equal(t, service.AuditActionChangeReported, env.audit.events[0].Action)
+	assert.Equal(t, service.AccountChanged{User: u}, env.events.last(), "cached lookups of the locked account are invalidated")
+
+	require.Len(t, env.mailer.sent, 1)
+	assert.Equal(t, "jane@example.com", env.mailer.sent[0].To)
+	assert.Contains(t, env.mailer.sent[0].Body, "https://blog.example.com/api/v1/users/password/reset?token=")
+	token := tokenFromEmail(env.mailer.sent[0].Body)
+	require.NotEmpty(t, token)
+
+	// The new password is set and the account unlocked
+	require.NoError(t, env.svc.ResetPassword(ctx, token, "N3w-Password!"))
+	assert.False(t, u.IsPasswordResetRequired())
+	assert.True(t, u.ValidatePassword("N3w-Password!"))
+	assert.Equal(t, service.AuditActionPasswordReset, env.audit.events[len(env.audit.events)-1].Action)
+
+	// Reset tokens are single-use
+	assert.ErrorIs(t, env.svc.ResetPassword(ctx, token, "An0ther-Password!"), user.ErrPasswordResetNotFound)
+}
+
+func TestSecurityService_ResetPassword_Expired(t *testing.T) {
+	env := newSecurityTestEnv()
+	u := env.addUser(t, "jane@example.com", "password123")
+	ctx := context.Background()
+
+	require.NoError(t, env.svc.ReportUnauthorizedChange(ctx, "report-1"))
+	token := tokenFromEmail(env.mailer.sent[0].Body)
+	for _, r := range env.resets.resets {
+		r.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	assert.ErrorIs(t, env.svc.ResetPassword(ctx, token, "N3w-Password!"), user.ErrPasswordResetExpired)
+	assert.True(t, u.IsPasswordResetRequired())
+	assert.Empty(t, env.resets.resets, "expired resets are removed")
+}
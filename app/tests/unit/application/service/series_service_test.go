@@ -0,0 +1,216 @@
+package service_test
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+)
+
+// MockSeriesRepository implements the series.Repository interface for
+// testing, keeping the series of posts on the posts of a MockPostRepository
+type MockSeriesRepository struct {
+	series map[int]*series.Series
+	posts  *MockPostRepository
+}
+
+func NewMockSeriesRepository(posts *MockPostRepository) *MockSeriesRepository {
+	return &MockSeriesRepository{series: make(map[int]*series.Series), posts: posts}
+}
+
+func (m *MockSeriesRepository) Create(ctx context.Context, s *series.Series) error {
+	s.ID = len(m.series) + 1
+	m.series[s.ID] = s
+	return nil
+}
+
+func (m *MockSeriesRepository) GetByID(ctx context.Context, id int) (*series.Series, error) {
+	s, ok := m.series[id]
+	if !ok {
+		return nil, series.ErrSeriesNotFound
+	}
+	return s, nil
+}
+
+func (m *MockSeriesRepository) ListByAuthor(ctx context.Context, authorID int) ([]*series.Series, error) {
+	var list []*series.Series
+	for id := len(m.series); id > 0; id-- {
+		if s, ok := m.series[id]; ok && s.AuthorID == authorID {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func (m *MockSeriesRepository) Update(ctx context.Context, s *series.Series) error {
+	if _, ok := m.series[s.ID]; !ok {
+		return series.ErrSeriesNotFound
+	}
+	m.series[s.ID] = s
+	return nil
+}
+
+func (m *MockSeriesRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.series[id]; !ok {
+		return series.ErrSeriesNotFound
+	}
+	m.clearPosts(id)
+	delete(m.series, id)
+	return nil
+}
+
+func (m *MockSeriesRepository) ListPosts(ctx context.Context, id int, filter post.ViewFilter) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range m.posts.posts {
+		if p.SeriesID != nil && *p.SeriesID == id && visibleThrough(p, filter) {
+			posts = append(posts, p)
+		}
+	}
+	slices.SortFunc(posts, func(a, b *post.Post) int { return cmp.Compare(a.SeriesPosition, b.SeriesPosition) })
+	return posts, nil
+}
+
+func (m *MockSeriesRepository) SetPosts(ctx context.Context, id int, postIDs []int) error {
+	m.clearPosts(id)
+	for i, postID := range postIDs {
+		p := m.posts.posts[postID]
+		p.SeriesID = &id
+		p.SeriesPosition = i + 1
+	}
+	return nil
+}
+
+func (m *MockSeriesRepository) clearPosts(id int) {
+	for _, p := range m.posts.posts {
+		if p.SeriesID != nil && *p.SeriesID == id {
+			p.SeriesID = nil
+			p.SeriesPosition = 0
+		}
+	}
+}
+
+func TestSeriesService_Lifecycle(t *testing.T) {
+	repo := NewMockSeriesRepository(NewMockPostRepository())
+	seriesService := service.NewSeriesService(repo, repo.posts, &MockTxManager{}, &MockEventPublisher{}, NewMockLogger())
+	ctx := context.Background()
+
+	created, err := seriesService.Create(ctx, 1, series.Input{Title: "Building a Blog"})
+	if err != nil {
+		t.Fatalf("failed to create series: %v", err)
+	}
+
+	if _, err := seriesService.Update(ctx, 2, user.RoleAuthor, created.ID, series.Input{Title: "Taken Over"}); err != series.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another author, got %v", err)
+	}
+	updated, err := seriesService.Update(ctx, 2, user.RoleAdmin, created.ID, series.Input{Title: "Building a Blog in Go", Description: "Step by step."})
+	if err != nil || updated.Title != "Building a Blog in Go" {
+		t.Fatalf("expected admins to update the series, got %v", err)
+	}
+
+	list, err := seriesService.ListByAuthor(ctx, 1)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected 1 series of the author, got %d (%v)", len(list), err)
+	}
+
+	if err := seriesService.Delete(ctx, 2, user.RoleAuthor, created.ID); err != series.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another author, got %v", err)
+	}
+	if err := seriesService.Delete(ctx, 1, user.RoleAuthor, created.ID); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+	if _, _, err := seriesService.Get(ctx, 0, "", created.ID); err != series.ErrSeriesNotFound {
+		t.Errorf("expected ErrSeriesNotFound, got %v", err)
+	}
+}
+
+func TestSeriesService_SetPosts(t *testing.T) {
+	posts := NewMockPostRepository()
+	repo := NewMockSeriesRepository(posts)
+	publisher := &MockEventPublisher{}
+	seriesService := service.NewSeriesService(repo, posts, &MockTxManager{}, publisher, NewMockLogger())
+	ctx := context.Background()
+
+	create := func(title string, authorID int, draft bool) *post.Post {
+		p, _ := post.NewPost(title, "Test content with sufficient length.", authorID)
+		if draft {
+			p, _ = post.NewDraft(title, "Test content with sufficient length.", authorID)
+		}
+		if err := posts.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		return p
+	}
+	first := create("First Part", 1, false)
+	second := create("Second Part", 1, true)
+	third := create("Third Part", 1, false)
+	foreign := create("Someone Else's Post", 2, false)
+
+	sr, _ := seriesService.Create(ctx, 1, series.Input{Title: "Building a Blog"})
+
+	if _, _, err := seriesService.SetPosts(ctx, 1, user.RoleAuthor, sr.ID, []int{first.ID, foreign.ID}); err != series.ErrForeignPost {
+		t.Errorf("expected ErrForeignPost for another author's post, got %v", err)
+	}
+	if _, _, err := seriesService.SetPosts(ctx, 1, user.RoleAuthor, sr.ID, []int{first.ID, 999}); err != series.ErrForeignPost {
+		t.Errorf("expected ErrForeignPost for a missing post, got %v", err)
+	}
+	if _, _, err := seriesService.SetPosts(ctx, 1, user.RoleAuthor, sr.ID, []int{first.ID, first.ID}); err != series.ErrDuplicatePost {
+		t.Errorf("expected ErrDuplicatePost, got %v", err)
+	}
+	if _, _, err := seriesService.SetPosts(ctx, 2, user.RoleAuthor, sr.ID, []int{foreign.ID}); err != series.ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized for another author, got %v", err)
+	}
+
+	_, entries, err := seriesService.SetPosts(ctx, 1, user.RoleAuthor, sr.ID, []int{third.ID, second.ID, first.ID})
+	if err != nil {
+		t.Fatalf("failed to set posts: %v", err)
+	}
+	// The author sees the draft in its place
+	if len(entries) != 3 || entries[0].Post.ID != third.ID || entries[1].Post.ID != second.ID || entries[2].Next != nil {
+		t.Fatalf("expected the posts in the given order, got %+v", entries)
+	}
+	if third.SeriesPosition != 1 || first.SeriesPosition != 3 {
+		t.Errorf("expected positions counted from 1, got %d and %d", third.SeriesPosition, first.SeriesPosition)
+	}
+
+	// Readers skip the draft
+	_, entries, err = seriesService.Get(ctx, 0, "", sr.ID)
+	if err != nil {
+		t.Fatalf("failed to get series: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Next != first || entries[1].Previous != third {
+		t.Errorf("expected readers to move from post %d to post %d, got %+v", third.ID, first.ID, entries)
+	}
+
+	// Posts left out leave the series, and every post touched is reported
+	if _, _, err := seriesService.SetPosts(ctx, 1, user.RoleAuthor, sr.ID, []int{first.ID}); err != nil {
+		t.Fatalf("failed to set posts: %v", err)
+	}
+	if third.SeriesID != nil || third.SeriesPosition != 0 {
+		t.Errorf("expected post %d out of the series, got %v", third.ID, third.SeriesID)
+	}
+	changed, ok := publisher.last().(service.SeriesPostsChanged)
+	if !ok {
+		t.Fatalf("expected SeriesPostsChanged, got %v", publisher.last())
+	}
+	for _, id := range []int{first.ID, second.ID, third.ID} {
+		if !slices.Contains(changed.PostIDs, id) {
+			t.Errorf("expected post %d among the changed posts %v", id, changed.PostIDs)
+		}
+	}
+
+	// Deleting the series keeps its posts
+	if err := seriesService.Delete(ctx, 1, user.RoleAuthor, sr.ID); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+	if first.SeriesID != nil {
+		t.Errorf("expected post %d out of the deleted series", first.ID)
+	}
+	if _, err := posts.GetByID(ctx, first.ID); err != nil {
+		t.Errorf("expected the post kept, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/user"
+)
+
+// MockSSOConnectionRepository implements sso.ConnectionRepository for testing
+type MockSSOConnectionRepository struct {
+	connections []*sso.Connection
+}
+
+func (m *MockSSOConnectionRepository) Save(ctx context.Context, c *sso.Connection) error {
+	for _, existing := range m.connections {
+		if existing.Organization == c.Organization {
+			continue
+		}
+		for _, domain := range c.Domains {
+			if slices.Contains(existing.Domains, domain) {
+				return sso.ErrDomainClaimed
+			}
+		}
+	}
+	copied := *c
+	m.connections = slices.DeleteFunc(m.connections, func(existing *sso.Connection) bool { return existing.Organization == c.Organization })
+	m.connections = append(m.connections, &copied)
+	return nil
+}
+
+func (m *MockSSOConnectionRepository) find(match func(*sso.Connection) bool) (*sso.Connection, error) {
+	for _, c := range m.connections {
+		if match(c) {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, sso.ErrConnectionNotFound
+}
+
+func (m *MockSSOConnectionRepository) GetByOrganization(ctx context.Context, organization string) (*sso.Connection, error) {
+	return m.find(func(c *sso.Connection) bool { return c.Organization == organization })
+}
+
+func (m *MockSSOConnectionRepository) GetByDomain(ctx context.Context, domain string) (*sso.Connection, error) {
+	return m.find(func(c *sso.Connection) bool { return slices.Contains(c.Domains, domain) })
+}
+
+func (m *MockSSOConnectionRepository) List(ctx context.Context) ([]*sso.Connection, error) {
+	return m.connections, nil
+}
+
+func (m *MockSSOConnectionRepository) Delete(ctx context.Context, organization string) error {
+	if _, err := m.GetByOrganization(ctx, organization); err != nil {
+		return err
+	}
+	m.connections = slices.DeleteFunc(m.connections, func(c *sso.Connection) bool { return c.Organization == organization })
+	return nil
+}
+
+// MockSSOMembershipRepository implements sso.MembershipRepository for testing
+type MockSSOMembershipRepository struct {
+	memberships []*sso.Membership
+}
+
+func (m *MockSSOMembershipRepository) Save(ctx context.Context, membership *sso.Membership) error {
+	m.memberships = slices.DeleteFunc(m.memberships, func(existing *sso.Membership) bool {
+		return existing.Organization == membership.Organization && existing.UserID == membership.UserID
+	})
+	copied := *membership
+	m.memberships = append(m.memberships, &copied)
+	return nil
+}
+
+func (m *MockSSOMembershipRepository) ListByUser(ctx context.Context, userID int) ([]*sso.Membership, error) {
+	var result []*sso.Membership
+	for _, membership := range m.memberships {
+		if membership.UserID == userID {
+			result = append(result, membership)
+		}
+	}
+	return result, nil
+}
+
+// MockSSOProvider vouches for a fixed assertion when the code is "valid"
+type MockSSOProvider struct {
+	assertion *sso.Assertion
+}
+
+func (p *MockSSOProvider) AuthRequestURL(ctx context.Context, state string) (string, error) {
+	return "https://idp.example.com/authorize?state=" + state, nil
+}
+
+func (p *MockSSOProvider) Verify(ctx context.Context, state string, response sso.Response) (*sso.Assertion, error) {
+	if response.Code != "valid" {
+		return nil, sso.ErrInvalidResponse
+	}
+	return p.assertion, nil
+}
+
+// MockSSOProviderFactory returns the same provider for every connection and
+// refuses SAML metadata that is not "<valid/>"
+type MockSSOProviderFactory struct {
+	provider *MockSSOProvider
+}
+
+func (f *MockSSOProviderFactory) Provider(c *sso.Connection) (sso.Provider, error) {
+	if c.Protocol == sso.ProtocolSAML && c.Metadata != "<valid/>" {
+		return nil, sso.ErrInvalidMetadata
+	}
+	return f.provider, nil
+}
+
+type ssoTestFixture struct {
+	service     *service.SSOService
+	users       *MockUserService
+	connections *MockSSOConnectionRepository
+	memberships *MockSSOMembershipRepository
+	provider    *MockSSOProvider
+	audit       *MockAuditLogger
+}
+
+func newTestSSOService(t *testing.T) *ssoTestFixture {
+	t.Helper()
+	f := &ssoTestFixture{
+		users:       NewMockUserService(),
+		connections: &MockSSOConnectionRepository{},
+		memberships: &MockSSOMembershipRepository{},
+		provider:    &MockSSOProvider{},
+		audit:       &MockAuditLogger{},
+	}
+	f.service = service.NewSSOService(f.users, f.connections, f.memberships, &MockSSOProviderFactory{provider: f.provider}, f.audit, NewMockLogger())
+	return f
+}
+
+func acmeConnection(enforcement sso.Enforcement) *sso.Connection {
+	return &sso.Connection{
+		Organization: "acme",
+		Protocol:     sso.ProtocolOIDC,
+		Domains:      []string{"acme.com"},
+		Enforcement:  enforcement,
+		RoleMappings: map[string]user.Role{"blog-admins": user.RoleAdmin},
+		Issuer:       "https://login.acme.com",
+		ClientID:     "blog",
+		ClientSecret: "secret",
+	}
+}
+
+func TestSSOService_SaveConnection(t *testing.T) {
+	f := newTestSSOService(t)
+	ctx := context.Background()
+
+	saved, err := f.service.SaveConnection(ctx, acmeConnection(""))
+	require.NoError(t, err)
+	assert.Equal(t, sso.EnforcementOptional, saved.Enforcement)
+	assert.False(t, saved.CreatedAt.IsZero())
+	require.Len(t, f.audit.events, 1)
+	assert.Equal(t, service.AuditActionSSOConnectionSaved, f.audit.events[0].Action)
+
+	update := acmeConnection(sso.EnforcementRequired)
+	update.ClientSecret = ""
+	saved, err = f.service.SaveConnection(ctx, update)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", saved.ClientSecret, "an empty secret keeps the stored one")
+
+	globex := acmeConnection("")
+	globex.Organization = "globex"
+	_, err = f.service.SaveConnection(ctx, globex)
+	assert.ErrorIs(t, err, sso.ErrDomainClaimed)
+
+	saml := &sso.Connection{Organization: "initech", Protocol: sso.ProtocolSAML, Domains: []string{"initech.com"}, Metadata: "<broken"}
+	_, err = f.service.SaveConnection(ctx, saml)
+	assert.ErrorIs(t, err, sso.ErrInvalidMetadata, "unusable metadata is refused before saving")
+}
+
+func TestSSOService_Resolve(t *testing.T) {
+	f := newTestSSOService(t)
+	ctx := context.Background()
+	_, err := f.service.SaveConnection(ctx, acmeConnection(""))
+	require.NoError(t, err)
+
+	conn, err := f.service.Resolve(ctx, "", "Jane@Acme.com")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", conn.Organization)
+
+	_, err = f.service.Resolve(ctx, "", "jane@example.com")
+	assert.ErrorIs(t, err, sso.ErrConnectionNotFound)
+	_, err = f.service.Resolve(ctx, "globex", "jane@acme.com")
+	assert.ErrorIs(t, err, sso.ErrConnectionNotFound, "the organization takes precedence over the email")
+}
+
+func TestSSOService_Authenticate(t *testing.T) {
+	f := newTestSSOService(t)
+	ctx := context.Background()
+	_, err := f.service.SaveConnection(ctx, acmeConnection(""))
+	require.NoError(t, err)
+	jane, err := f.users.Register(ctx, "Jane Doe", "jane@acme.com", "password123")
+	require.NoError(t, err)
+	jane.Role = user.RoleAuthor
+
+	f.provider.assertion = &sso.Assertion{Subject: "00u1", Email: "jane@acme.com", Name: "Jane Doe", Groups: []string{"blog-admins"}}
+	u, err := f.service.Authenticate(ctx, "acme", "state", sso.Response{Code: "valid"})
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, u.ID)
+	assert.Equal(t, user.RoleAdmin, u.Role, "the mapped role is applied")
+
+	memberships, err := f.memberships.ListByUser(ctx, jane.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, "acme", memberships[0].Organization)
+	assert.Equal(t, user.RoleAdmin, memberships[0].Role)
+
+	f.provider.assertion = &sso.Assertion{Subject: "00u2", Email: "john@example.com", Name: "John Doe"}
+	_, err = f.service.Authenticate(ctx, "acme", "state", sso.Response{Code: "valid"})
+	assert.ErrorIs(t, err, sso.ErrForeignEmail, "accounts outside the domains are not taken over")
+	assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+
+	_, err = f.service.Authenticate(ctx, "acme", "state", sso.Response{Code: "forged"})
+	assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+
+	_, err = f.service.Authenticate(ctx, "globex", "state", sso.Response{Code: "valid"})
+	assert.ErrorIs(t, err, sso.ErrConnectionNotFound)
+}
+
+func TestSSOService_CheckLogin(t *testing.T) {
+	f := newTestSSOService(t)
+	ctx := context.Background()
+
+	_, err := f.service.SaveConnection(ctx, acmeConnection(sso.EnforcementRequiredExceptAdmins))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, f.service.CheckLogin(ctx, 1, "jane@acme.com", user.RoleAuthor), sso.ErrSSORequired)
+	assert.ErrorIs(t, f.service.CheckLogin(ctx, 0, "new@acme.com", user.DefaultRole), sso.ErrSSORequired, "registrations are refused too")
+	assert.NoError(t, f.service.CheckLogin(ctx, 1, "jane@acme.com", user.RoleAdmin), "admins keep the other methods")
+	assert.NoError(t, f.service.CheckLogin(ctx, 2, "john@example.com", user.RoleAuthor))
+
+	// Members whose address is outside the domains are still bound by the
+	// organization they signed in through
+	require.NoError(t, f.memberships.Save(ctx, &sso.Membership{Organization: "acme", UserID: 2}))
+	assert.ErrorIs(t, f.service.CheckLogin(ctx, 2, "john@example.com", user.RoleAuthor), sso.ErrSSORequired)
+
+	require.NoError(t, f.service.DeleteConnection(ctx, "acme"))
+	assert.NoError(t, f.service.CheckLogin(ctx, 2, "john@example.com", user.RoleAuthor))
+	assert.NoError(t, f.service.CheckLogin(ctx, 1, "jane@acme.com", user.RoleAuthor))
+}
+
+func TestAuthService_SSO(t *testing.T) {
+	f := newTestSSOService(t)
+	ctx := context.Background()
+	_, err := f.service.SaveConnection(ctx, acmeConnection(sso.EnforcementRequired))
+	require.NoError(t, err)
+	_, err = f.users.Register(ctx, "Jane Doe", "jane@acme.com", "password123")
+	require.NoError(t, err)
+	_, err = f.users.Register(ctx, "John Doe", "john@example.com", "password123")
+	require.NoError(t, err)
+
+	authService := service.NewAuthService(f.users, NewMockTokenService(), NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, f.service, &MockSecurityEvents{}, service.AuthSettings{}, NewMockLogger())
+
+	_, _, err = authService.Login(ctx, "jane@acme.com", "password123")
+	assert.ErrorIs(t, err, sso.ErrSSORequired)
+	_, _, err = authService.Register(ctx, "Bob", "bob@acme.com", "password123")
+	assert.ErrorIs(t, err, sso.ErrSSORequired)
+	_, _, err = authService.Login(ctx, "john@example.com", "password123")
+	assert.NoError(t, err, "other domains are not affected")
+
+	f.provider.assertion = &sso.Assertion{Subject: "00u1", Email: "jane@acme.com", Name: "Jane Doe"}
+	u, tokens, err := authService.LoginWithSSO(ctx, "acme", "state", sso.Response{Code: "valid"})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@acme.com", u.Email)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	disabled := service.NewAuthService(f.users, NewMockTokenService(), NewMockRefreshTokenRepository(), NewMockTokenBlacklist(), NewMockTwoFactorRepository(), nil, nil, &MockSecurityEvents{}, service.AuthSettings{}, NewMockLogger())
+	_, _, err = disabled.LoginWithSSO(ctx, "acme", "state", sso.Response{Code: "valid"})
+	assert.ErrorIs(t, err, sso.ErrConnectionNotFound)
+	_, _, err = disabled.Login(ctx, "jane@acme.com", "password123")
+	assert.NoError(t, err, "nothing is enforced when SSO is disabled")
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/change"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/post"
+)
+
+// MockChangeRepository implements the change.Repository interface for
+// testing; each slice is kept in (time, ID) order
+type MockChangeRepository struct {
+	posts      []*post.Post
+	comments   []*change.CommentChange
+	tombstones []*change.Tombstone
+}
+
+func changedAfter(at time.Time, id int, pos change.Position) bool {
+	return at.After(pos.At) || (at.Equal(pos.At) && id > pos.ID)
+}
+
+func (m *MockChangeRepository) ListPosts(ctx context.Context, pos change.Position, limit int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, p := range m.posts {
+		if changedAfter(p.UpdatedAt, p.ID, pos) && len(posts) < limit {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (m *MockChangeRepository) ListComments(ctx context.Context, pos change.Position, limit int) ([]*change.CommentChange, error) {
+	var comments []*change.CommentChange
+	for _, c := range m.comments {
+		if changedAfter(c.UpdatedAt, c.ID, pos) && len(comments) < limit {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (m *MockChangeRepository) ListTombstones(ctx context.Context, pos change.Position, limit int) ([]*change.Tombstone, error) {
+	var tombstones []*change.Tombstone
+	for _, t := range m.tombstones {
+		if changedAfter(t.DeletedAt, t.ID, pos) && len(tombstones) < limit {
+			tombstones = append(tombstones, t)
+		}
+	}
+	return tombstones, nil
+}
+
+func newSyncTestRepository() *MockChangeRepository {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	return &MockChangeRepository{
+		posts: []*post.Post{
+			{ID: 2, Status: post.StatusPublished, UpdatedAt: at},
+			{ID: 1, Status: post.StatusPublished, UpdatedAt: at.Add(time.Minute)},
+		},
+		comments: []*change.CommentChange{
+			{Comment: comment.Comment{ID: 1, PostID: 1, Status: comment.StatusApproved}, UpdatedAt: at},
+			{Comment: comment.Comment{ID: 2, PostID: 1, Status: comment.StatusRejected}, UpdatedAt: at},
+		},
+		tombstones: []*change.Tombstone{
+			{ID: 1, Kind: change.KindPost, EntityID: 3, DeletedAt: at},
+			{ID: 2, Kind: change.KindComment, EntityID: 5, DeletedAt: at},
+		},
+	}
+}
+
+func TestSyncService_Changes(t *testing.T) {
+	svc := service.NewSyncService(newSyncTestRepository(), NewMockLogger())
+
+	set, err := svc.Changes(context.Background(), change.Start(), 10)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(set.Posts) != 2 || set.Posts[0].ID != 2 || set.Posts[1].ID != 1 {
+		t.Errorf("Posts = %v, want posts 2 and 1 in change order", set.Posts)
+	}
+	if len(set.Comments) != 1 || set.Comments[0].ID != 1 {
+		t.Errorf("Comments = %v, want only the approved comment", set.Comments)
+	}
+	if len(set.DeletedPosts) != 1 || set.DeletedPosts[0] != 3 {
+		t.Errorf("DeletedPosts = %v, want [3]", set.DeletedPosts)
+	}
+	if len(set.DeletedComments) != 2 || set.DeletedComments[0] != 2 || set.DeletedComments[1] != 5 {
+		t.Errorf("DeletedComments = %v, want the rejected comment and the tombstone [2 5]", set.DeletedComments)
+	}
+	if set.HasMore {
+		t.Error("HasMore = true, want false")
+	}
+	if set.Checkpoint.Posts.ID != 1 || set.Checkpoint.Comments.ID != 2 || set.Checkpoint.Deletions.ID != 2 {
+		t.Errorf("Checkpoint = %+v, want the last change of each kind", set.Checkpoint)
+	}
+
+	// Nothing changed since the checkpoint
+	set, err = svc.Changes(context.Background(), set.Checkpoint, 10)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(set.Posts) != 0 || len(set.Comments) != 0 || len(set.DeletedPosts) != 0 || len(set.DeletedComments) != 0 {
+		t.Errorf("Changes() after the checkpoint = %+v, want nothing", set)
+	}
+}
+
+func TestSyncService_Changes_Pages(t *testing.T) {
+	svc := service.NewSyncService(newSyncTestRepository(), NewMockLogger())
+
+	set, err := svc.Changes(context.Background(), change.Start(), 1)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if !set.HasMore {
+		t.Error("HasMore = false, want true")
+	}
+	if len(set.Posts) != 1 || set.Posts[0].ID != 2 {
+		t.Errorf("Posts = %v, want post 2", set.Posts)
+	}
+
+	set, err = svc.Changes(context.Background(), set.Checkpoint, 1)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if set.HasMore {
+		t.Error("HasMore = true on the last page, want false")
+	}
+	if len(set.Posts) != 1 || set.Posts[0].ID != 1 {
+		t.Errorf("Posts = %v, want post 1", set.Posts)
+	}
+	if len(set.DeletedComments) != 2 {
+		t.Errorf("DeletedComments = %v, want the rejected comment and the tombstone", set.DeletedComments)
+	}
+}
@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/tag"
+)
+
+// MockTagRepository implements the tag.Repository interface for testing
+type MockTagRepository struct {
+	names map[int]string
+	// posts holds the IDs of the posts carrying each tag
+	posts map[int]map[int]bool
+}
+
+func NewMockTagRepository(tags map[string][]int) *MockTagRepository {
+	m := &MockTagRepository{names: make(map[int]string), posts: make(map[int]map[int]bool)}
+	id := 0
+	for name, postIDs := range tags {
+		id++
+		m.names[id] = name
+		m.posts[id] = make(map[int]bool)
+		for _, postID := range postIDs {
+			m.posts[id][postID] = true
+		}
+	}
+	return m
+}
+
+func (m *MockTagRepository) ListUsed(ctx context.Context) ([]*tag.Tag, error) {
+	return nil, nil
+}
+
+func (m *MockTagRepository) ListAll(ctx context.Context) ([]*tag.Tag, error) {
+	var tags []*tag.Tag
+	for id, name := range m.names {
+		tags = append(tags, &tag.Tag{ID: id, Name: name, PostCount: len(m.posts[id])})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (m *MockTagRepository) GetByName(ctx context.Context, name string) (*tag.Tag, error) {
+	for id, n := range m.names {
+		if n == name {
+			return &tag.Tag{ID: id, Name: n, PostCount: len(m.posts[id])}, nil
+		}
+	}
+	return nil, tag.ErrTagNotFound
+}
+
+func (m *MockTagRepository) CountPosts(ctx context.Context, ids []int) (int, error) {
+	posts := make(map[int]bool)
+	for _, id := range ids {
+		for postID := range m.posts[id] {
+			posts[postID] = true
+		}
+	}
+	return len(posts), nil
+}
+
+func (m *MockTagRepository) Rename(ctx context.Context, id int, name string) error {
+	m.names[id] = name
+	return nil
+}
+
+func (m *MockTagRepository) Merge(ctx context.Context, sourceIDs []int, targetID int) error {
+	for _, id := range sourceIDs {
+		for postID := range m.posts[id] {
+			m.posts[targetID][postID] = true
+		}
+		delete(m.names, id)
+		delete(m.posts, id)
+	}
+	return nil
+}
+
+func (m *MockTagRepository) DeleteUnused(ctx context.Context) (int, error) {
+	deleted := 0
+	for id := range m.names {
+		if len(m.posts[id]) == 0 {
+			delete(m.names, id)
+			delete(m.posts, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestTagService_RenameTag(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockTagRepository(map[string][]int{"golang": {1, 2}, "go": {3}})
+	audit := &MockAuditLogger{}
+	tagService := service.NewTagService(repo, audit, NewMockLogger())
+
+	preview, err := tagService.RenameTag(ctx, 9, "golang", "Go Lang", true)
+	if err != nil {
+		t.Fatalf("RenameTag failed: %v", err)
+	}
+	if !preview.DryRun || preview.AffectedPosts != 2 || preview.Target.Name != "go-lang" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if _, err := repo.GetByName(ctx, "golang"); err != nil || len(audit.events) != 0 {
+		t.Fatalf("expected a dry run to change nothing")
+	}
+
+	if _, err := tagService.RenameTag(ctx, 9, "golang", "Go Lang", false); err != nil {
+		t.Fatalf("RenameTag failed: %v", err)
+	}
+	if _, err := repo.GetByName(ctx, "go-lang"); err != nil {
+		t.Errorf("expected the tag to be renamed, got %v", err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != service.AuditActionTagRenamed || audit.events[0].UserID != 9 {
+		t.Errorf("expected the rename to be audited, got %+v", audit.events)
+	}
+
+	if _, err := tagService.RenameTag(ctx, 9, "go-lang", "go", false); !errors.Is(err, tag.ErrTagExists) {
+		t.Errorf("expected ErrTagExists, got %v", err)
+	}
+	if _, err := tagService.RenameTag(ctx, 9, "missing", "other", false); !errors.Is(err, tag.ErrTagNotFound) {
+		t.Errorf("expected ErrTagNotFound, got %v", err)
+	}
+	if _, err := tagService.RenameTag(ctx, 9, "go", "not a tag!", false); !errors.Is(err, tag.ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+}
+
+func TestTagService_MergeTags(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockTagRepository(map[string][]int{"golang": {1, 2}, "go-lang": {2, 3}, "go-language": {4}, "rust": {5}})
+	audit := &MockAuditLogger{}
+	tagService := service.NewTagService(repo, audit, NewMockLogger())
+
+	preview, err := tagService.MergeTags(ctx, 9, []string{"go-lang", "Go Language", "go-lang"}, "golang", true)
+	if err != nil {
+		t.Fatalf("MergeTags failed: %v", err)
+	}
+	if len(preview.Tags) != 2 || preview.AffectedPosts != 3 || preview.Target.Name != "golang" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(audit.events) != 0 {
+		t.Fatalf("expected a dry run to change nothing")
+	}
+
+	if _, err := tagService.MergeTags(ctx, 9, []string{"go-lang", "go-language"}, "golang", false); err != nil {
+		t.Fatalf("MergeTags failed: %v", err)
+	}
+	if merged, _ := repo.GetByName(ctx, "golang"); merged.PostCount != 4 {
+		t.Errorf("expected the posts to be moved, got %d", merged.PostCount)
+	}
+	if _, err := repo.GetByName(ctx, "go-lang"); !errors.Is(err, tag.ErrTagNotFound) {
+		t.Errorf("expected the duplicate to be deleted, got %v", err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != service.AuditActionTagsMerged {
+		t.Errorf("expected the merge to be audited, got %+v", audit.events)
+	}
+
+	if _, err := tagService.MergeTags(ctx, 9, []string{"golang"}, "golang", false); !errors.Is(err, tag.ErrMergeSelf) {
+		t.Errorf("expected ErrMergeSelf, got %v", err)
+	}
+	if _, err := tagService.MergeTags(ctx, 9, []string{"missing"}, "golang", false); !errors.Is(err, tag.ErrTagNotFound) {
+		t.Errorf("expected ErrTagNotFound, got %v", err)
+	}
+}
+
+func TestTagService_DeleteUnusedTags(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockTagRepository(map[string][]int{"golang": {1}, "stale": nil, "typo": nil})
+	audit := &MockAuditLogger{}
+	tagService := service.NewTagService(repo, audit, NewMockLogger())
+
+	preview, err := tagService.DeleteUnusedTags(ctx, 9, true)
+	if err != nil {
+		t.Fatalf("DeleteUnusedTags failed: %v", err)
+	}
+	if len(preview.Tags) != 2 || len(repo.names) != 3 {
+		t.Errorf("expected 2 tags previewed and none deleted, got %d previewed and %d left", len(preview.Tags), len(repo.names))
+	}
+
+	if _, err := tagService.DeleteUnusedTags(ctx, 9, false); err != nil {
+		t.Fatalf("DeleteUnusedTags failed: %v", err)
+	}
+	if len(repo.names) != 1 {
+		t.Errorf("expected only the used tag to be left, got %v", repo.names)
+	}
+	if len(audit.events) != 1 || audit.events[0].Metadata["deleted"] != 2 {
+		t.Errorf("expected the deletion to be audited, got %+v", audit.events)
+	}
+
+	// Nothing left to delete is not audited
+	if _, err := tagService.DeleteUnusedTags(ctx, 9, false); err != nil || len(audit.events) != 1 {
+		t.Errorf("expected no further audit events, got %+v (%v)", audit.events, err)
+	}
+}
@@ -0,0 +1,291 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/tip"
+)
+
+// MockTipRepository implements tip.Repository for testing
+type MockTipRepository struct {
+	tips     map[int]*tip.Tip
+	accounts map[int]*tip.PayoutAccount
+	posts    *MockPostRepository
+	nextID   int
+}
+
+func NewMockTipRepository(posts *MockPostRepository) *MockTipRepository {
+	return &MockTipRepository{
+		tips:     make(map[int]*tip.Tip),
+		accounts: make(map[int]*tip.PayoutAccount),
+		posts:    posts,
+		nextID:   1,
+	}
+}
+
+func (m *MockTipRepository) Create(ctx context.Context, t *tip.Tip) error {
+	t.ID = m.nextID
+	m.nextID++
+	copied := *t
+	m.tips[t.ID] = &copied
+	return nil
+}
+
+func (m *MockTipRepository) GetByID(ctx context.Context, id int) (*tip.Tip, error) {
+	t, ok := m.tips[id]
+	if !ok {
+		return nil, tip.ErrTipNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (m *MockTipRepository) SetPaymentID(ctx context.Context, id int, paymentID string) error {
+	m.tips[id].PaymentID = paymentID
+	return nil
+}
+
+func (m *MockTipRepository) Settle(ctx context.Context, id int, status tip.Status) (bool, error) {
+	t, ok := m.tips[id]
+	if !ok || t.Status != tip.StatusPending {
+		return false, nil
+	}
+	t.Status = status
+	if status == tip.StatusSucceeded {
+		m.posts.posts[t.PostID].TipCount++
+	}
+	return true, nil
+}
+
+func (m *MockTipRepository) GetPayoutAccount(ctx context.Context, userID int) (*tip.PayoutAccount, error) {
+	a, ok := m.accounts[userID]
+	if !ok {
+		return nil, tip.ErrPayoutAccountNotFound
+	}
+	return a, nil
+}
+
+func (m *MockTipRepository) SavePayoutAccount(ctx context.Context, a *tip.PayoutAccount) error {
+	m.accounts[a.UserID] = a
+	return nil
+}
+
+func (m *MockTipRepository) DeletePayoutAccount(ctx context.Context, userID int) error {
+	if _, ok := m.accounts[userID]; !ok {
+		return tip.ErrPayoutAccountNotFound
+	}
+	delete(m.accounts, userID)
+	return nil
+}
+
+// MockPaymentProvider records checkouts and accepts accounts starting with acct_
+type MockPaymentProvider struct {
+	checkouts []tip.CheckoutRequest
+	fail      bool
+}
+
+func (m *MockPaymentProvider) Name() string { return "mock" }
+
+func (m *MockPaymentProvider) VerifyAccount(ctx context.Context, accountID string) error {
+	if m.fail {
+		return errors.New("provider down")
+	}
+	if len(accountID) < 5 || accountID[:5] != "acct_" {
+		return tip.ErrInvalidAccount
+	}
+	return nil
+}
+
+func (m *MockPaymentProvider) CreateCheckout(ctx context.Context, req tip.CheckoutRequest) (*tip.Checkout, error) {
+	if m.fail {
+		return nil, errors.New("provider down")
+	}
+	m.checkouts = append(m.checkouts, req)
+	id := fmt.Sprintf("cs_%d", req.TipID)
+	return &tip.Checkout{PaymentID: id, URL: "https://pay.example.com/" + id}, nil
+}
+
+func (m *MockPaymentProvider) ParseEvent(payload []byte, signature string) (*tip.PaymentEvent, error) {
+	return nil, nil
+}
+
+// setupTipService creates a tip service with a published post by author 1,
+// who linked a payout account
+func setupTipService(t *testing.T) (*service.TipService, *MockTipRepository, *MockPostRepository, *MockPaymentProvider, *post.Post) {
+	t.Helper()
+	posts := NewMockPostRepository()
+	postService := newTestPostService(posts)
+	p, err := postService.CreatePost(context.Background(), 1, "Worth a coffee", "Content of the post")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+
+	repo := NewMockTipRepository(posts)
+	repo.accounts[1] = &tip.PayoutAccount{UserID: 1, Provider: "mock", AccountID: "acct_author"}
+	provider := &MockPaymentProvider{}
+	tipService := service.NewTipService(repo, postService, provider, &MockAuditLogger{}, service.TipSettings{
+		BaseURL:  "https://blog.example.com",
+		Currency: "usd",
+		Limits:   tip.Limits{MinAmount: 100, MaxAmount: 10000},
+	}, NewMockLogger())
+	return tipService, repo, posts, provider, p
+}
+
+func TestTipService_Implementation(t *testing.T) {
+	tipService, _, _, _, _ := setupTipService(t)
+	var _ tip.Service = tipService
+}
+
+func TestTipService_StartTip(t *testing.T) {
+	tipService, repo, _, provider, p := setupTipService(t)
+	ctx := context.Background()
+
+	started, checkout, err := tipService.StartTip(ctx, p.ID, 2, 500)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if started.Status != tip.StatusPending || started.AuthorID != 1 || started.TipperID == nil || *started.TipperID != 2 {
+		t.Errorf("expected a pending tip from user 2 to author 1, got %+v", started)
+	}
+	if checkout.URL == "" || repo.tips[started.ID].PaymentID != checkout.PaymentID {
+		t.Errorf("expected the checkout payment to be recorded, got %+v", repo.tips[started.ID])
+	}
+	req := provider.checkouts[0]
+	if req.Destination != "acct_author" || req.Amount != 500 || req.Currency != "usd" {
+		t.Errorf("expected a checkout paying the author's account, got %+v", req)
+	}
+	if req.ReturnURL != fmt.Sprintf("https://blog.example.com/p/%s?tip=%d", p.Slug, started.ID) {
+		t.Errorf("expected readers to return to the post, got %q", req.ReturnURL)
+	}
+
+	guest, _, err := tipService.StartTip(ctx, p.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("expected guests to tip, got %v", err)
+	}
+	if guest.TipperID != nil {
+		t.Errorf("expected a guest tip, got tipper %d", *guest.TipperID)
+	}
+}
+
+func TestTipService_StartTip_Rejects(t *testing.T) {
+	tipService, repo, posts, provider, p := setupTipService(t)
+	ctx := context.Background()
+
+	for _, amount := range []int64{0, 99, 10001} {
+		if _, _, err := tipService.StartTip(ctx, p.ID, 2, amount); !errors.Is(err, tip.ErrInvalidAmount) {
+			t.Errorf("amount %d: expected ErrInvalidAmount, got %v", amount, err)
+		}
+	}
+	if _, _, err := tipService.StartTip(ctx, p.ID, 1, 500); err != tip.ErrOwnPost {
+		t.Errorf("expected ErrOwnPost, got %v", err)
+	}
+	if _, _, err := tipService.StartTip(ctx, 999, 2, 500); err != post.ErrPostNotFound {
+		t.Errorf("expected ErrPostNotFound, got %v", err)
+	}
+
+	draft, _ := post.NewDraft("Unpublished", "Content of the draft", 1)
+	posts.Create(ctx, draft)
+	if _, _, err := tipService.StartTip(ctx, draft.ID, 2, 500); err != post.ErrPostNotFound {
+		t.Errorf("expected drafts to be hidden, got %v", err)
+	}
+
+	delete(repo.accounts, 1)
+	if _, _, err := tipService.StartTip(ctx, p.ID, 2, 500); err != tip.ErrNotPayable {
+		t.Errorf("expected ErrNotPayable, got %v", err)
+	}
+	if len(repo.tips) != 0 {
+		t.Errorf("expected no tips to be recorded, got %d", len(repo.tips))
+	}
+
+	repo.accounts[1] = &tip.PayoutAccount{UserID: 1, Provider: "mock", AccountID: "acct_author"}
+	provider.fail = true
+	if _, _, err := tipService.StartTip(ctx, p.ID, 2, 500); err != tip.ErrProviderUnavailable {
+		t.Errorf("expected ErrProviderUnavailable, got %v", err)
+	}
+	for _, recorded := range repo.tips {
+		if recorded.Status != tip.StatusFailed {
+			t.Errorf("expected the tip to fail with its checkout, got %q", recorded.Status)
+		}
+	}
+}
+
+func TestTipService_ApplyEvent(t *testing.T) {
+	tipService, repo, posts, _, p := setupTipService(t)
+	ctx := context.Background()
+
+	paid, checkout, _ := tipService.StartTip(ctx, p.ID, 2, 500)
+	event := &tip.PaymentEvent{EventID: "evt_1", TipID: paid.ID, PaymentID: checkout.PaymentID, Status: tip.StatusSucceeded}
+	if err := tipService.ApplyEvent(ctx, event); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.tips[paid.ID].Status != tip.StatusSucceeded || posts.posts[p.ID].TipCount != 1 {
+		t.Errorf("expected a succeeded tip counted on the post, got %q and %d", repo.tips[paid.ID].Status, posts.posts[p.ID].TipCount)
+	}
+
+	// Redelivered events and late failures change nothing
+	if err := tipService.ApplyEvent(ctx, event); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	late := &tip.PaymentEvent{EventID: "evt_2", TipID: paid.ID, PaymentID: checkout.PaymentID, Status: tip.StatusFailed}
+	if err := tipService.ApplyEvent(ctx, late); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.tips[paid.ID].Status != tip.StatusSucceeded || posts.posts[p.ID].TipCount != 1 {
+		t.Errorf("expected the tip to be counted once, got %q and %d", repo.tips[paid.ID].Status, posts.posts[p.ID].TipCount)
+	}
+
+	expired, _, _ := tipService.StartTip(ctx, p.ID, 2, 500)
+	if err := tipService.ApplyEvent(ctx, &tip.PaymentEvent{EventID: "evt_3", TipID: expired.ID, Status: tip.StatusFailed}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.tips[expired.ID].Status != tip.StatusFailed || posts.posts[p.ID].TipCount != 1 {
+		t.Errorf("expected a failed tip that is not counted, got %q and %d", repo.tips[expired.ID].Status, posts.posts[p.ID].TipCount)
+	}
+
+	other, _, _ := tipService.StartTip(ctx, p.ID, 2, 500)
+	if err := tipService.ApplyEvent(ctx, &tip.PaymentEvent{EventID: "evt_4", TipID: other.ID, PaymentID: "cs_other", Status: tip.StatusSucceeded}); err != tip.ErrInvalidEvent {
+		t.Errorf("expected ErrInvalidEvent for another payment, got %v", err)
+	}
+	if err := tipService.ApplyEvent(ctx, &tip.PaymentEvent{EventID: "evt_5", TipID: 999, Status: tip.StatusSucceeded}); err != tip.ErrTipNotFound {
+		t.Errorf("expected ErrTipNotFound, got %v", err)
+	}
+}
+
+func TestTipService_PayoutAccount(t *testing.T) {
+	tipService, repo, _, provider, _ := setupTipService(t)
+	ctx := context.Background()
+
+	account, err := tipService.LinkPayoutAccount(ctx, 3, " acct_new ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if account.AccountID != "acct_new" || account.Provider != "mock" {
+		t.Errorf("expected the trimmed account of the provider, got %+v", account)
+	}
+	if got, err := tipService.GetPayoutAccount(ctx, 3); err != nil || got.AccountID != "acct_new" {
+		t.Errorf("expected the linked account, got %+v (%v)", got, err)
+	}
+
+	if _, err := tipService.LinkPayoutAccount(ctx, 3, "not-an-account"); !errors.Is(err, tip.ErrInvalidAccount) {
+		t.Errorf("expected ErrInvalidAccount, got %v", err)
+	}
+	if repo.accounts[3].AccountID != "acct_new" {
+		t.Error("expected a rejected account to leave the linked one in place")
+	}
+	provider.fail = true
+	if _, err := tipService.LinkPayoutAccount(ctx, 3, "acct_other"); err != tip.ErrProviderUnavailable {
+		t.Errorf("expected ErrProviderUnavailable, got %v", err)
+	}
+
+	if err := tipService.UnlinkPayoutAccount(ctx, 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := tipService.UnlinkPayoutAccount(ctx, 3); err != tip.ErrPayoutAccountNotFound {
+		t.Errorf("expected ErrPayoutAccountNotFound, got %v", err)
+	}
+}
@@ -2,7 +2,11 @@ package service_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"blog-platform/internal/application/service"
 	"blog-platform/internal/domain/user"
@@ -37,7 +41,16 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*user.User, e
 
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	for _, u := range m.users {
-		if u.Email == email {
+		if u.NormalizedEmail == email {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (m *MockUserRepository) GetConflictedByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range m.users {
+		if u.EmailConflict && u.Email == email {
 			return u, nil
 		}
 	}
@@ -80,15 +93,292 @@ func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*us
 	return users, nil
 }
 
+func (m *MockUserRepository) ListDueForPurge(ctx context.Context, before time.Time, limit int) ([]*user.User, error) {
+	var due []*user.User
+	for _, u := range m.users {
+		if u.IsPendingDeletion() && !u.DeletionScheduledAt.After(before) && len(due) < limit {
+			due = append(due, u)
+		}
+	}
+	return due, nil
+}
+
+func (m *MockUserRepository) Purge(ctx context.Context, id int) error {
+	u, exists := m.users[id]
+	if !exists || !u.IsPendingDeletion() {
+		return user.ErrDeletionNotScheduled
+	}
+	now := time.Now()
+	u.Name = "Deleted user"
+	u.Email = ""
+	u.NormalizedEmail = ""
+	u.PasswordHash = ""
+	u.DeletionScheduledAt = nil
+	u.DeletedAt = &now
+	return nil
+}
+
+// MockEmailChangeRepository implements the EmailChangeRepository interface for testing
+type MockEmailChangeRepository struct {
+	users   *MockUserRepository
+	changes map[int]*user.EmailChange
+	nextID  int
+}
+
+func NewMockEmailChangeRepository(users *MockUserRepository) *MockEmailChangeRepository {
+	return &MockEmailChangeRepository{
+		users:   users,
+		changes: make(map[int]*user.EmailChange),
+		nextID:  1,
+	}
+}
+
+func (m *MockEmailChangeRepository) Create(ctx context.Context, c *user.EmailChange) error {
+	for id, existing := range m.changes {
+		if existing.UserID == c.UserID {
+			delete(m.changes, id)
+		}
+	}
+	c.ID = m.nextID
+	m.nextID++
+	m.changes[c.ID] = c
+	return nil
+}
+
+func (m *MockEmailChangeRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*user.EmailChange, error) {
+	for _, c := range m.changes {
+		if c.TokenHash == tokenHash {
+			return c, nil
+		}
+	}
+	return nil, user.ErrEmailChangeNotFound
+}
+
+func (m *MockEmailChangeRepository) Complete(ctx context.Context, c *user.EmailChange) error {
+	for _, u := range m.users.users {
+		if u.NormalizedEmail == c.NewEmailNormalized && u.ID != c.UserID {
+			return user.ErrUserExists
+		}
+	}
+	u, exists := m.users.users[c.UserID]
+	if !exists {
+		return user.ErrUserNotFound
+	}
+	u.Email = c.NewEmail
+	u.NormalizedEmail = c.NewEmailNormalized
+	delete(m.changes, c.ID)
+	return nil
+}
+
+func (m *MockEmailChangeRepository) Delete(ctx context.Context, id int) error {
+	if _, exists := m.changes[id]; !exists {
+		return user.ErrEmailChangeNotFound
+	}
+	delete(m.changes, id)
+	return nil
+}
+
+// MockIdentityRepository implements the IdentityRepository interface for testing
+type MockIdentityRepository struct {
+	identities []*user.Identity
+	nextID     int
+}
+
+func NewMockIdentityRepository() *MockIdentityRepository {
+	return &MockIdentityRepository{nextID: 1}
+}
+
+func (m *MockIdentityRepository) Create(ctx context.Context, identity *user.Identity) error {
+	for _, existing := range m.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return user.ErrIdentityExists
+		}
+	}
+	identity.ID = m.nextID
+	m.nextID++
+	m.identities = append(m.identities, identity)
+	return nil
+}
+
+func (m *MockIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*user.Identity, error) {
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, user.ErrIdentityNotFound
+}
+
+func (m *MockIdentityRepository) ListByUser(ctx context.Context, userID int) ([]*user.Identity, error) {
+	var identities []*user.Identity
+	for _, identity := range m.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+// MockMagicLinkRepository implements the MagicLinkRepository interface for testing
+type MockMagicLinkRepository struct {
+	links  map[string]*user.MagicLink
+	nextID int
+}
+
+func NewMockMagicLinkRepository() *MockMagicLinkRepository {
+	return &MockMagicLinkRepository{links: make(map[string]*user.MagicLink), nextID: 1}
+}
+
+func (m *MockMagicLinkRepository) Create(ctx context.Context, link *user.MagicLink) error {
+	for hash, existing := range m.links {
+		if existing.UserID == link.UserID && existing.UsedAt == nil {
+			delete(m.links, hash)
+		}
+	}
+	link.ID = m.nextID
+	m.nextID++
+	m.links[link.TokenHash] = link
+	return nil
+}
+
+func (m *MockMagicLinkRepository) Consume(ctx context.Context, tokenHash string, device user.Device) (*user.MagicLink, error) {
+	link, exists := m.links[tokenHash]
+	if !exists || link.UsedAt != nil || !time.Now().Before(link.ExpiresAt) {
+		return nil, user.ErrInvalidMagicLink
+	}
+	now := time.Now()
+	link.UsedAt = &now
+	link.UsedIP = device.IP
+	link.UsedUserAgent = device.UserAgent
+	return link, nil
+}
+
+// MockMailer records sent emails for testing
+type MockMailer struct {
+	sent []service.EmailMessage
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg service.EmailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// MockAuditLogger records audit events for testing
+type MockAuditLogger struct {
+	events []service.AuditEvent
+}
+
+func (m *MockAuditLogger) Record(ctx context.Context, event service.AuditEvent) {
+	m.events = append(m.events, event)
+}
+
+// MockSecurityEvents records published security events for testing
+type MockSecurityEvents struct {
+	events []service.SecurityEvent
+}
+
+func (m *MockSecurityEvents) Publish(ctx context.Context, event service.SecurityEvent) {
+	m.events = append(m.events, event)
+}
+
+// MockLoginAttemptStore implements user.LoginAttemptStore for testing
+type MockLoginAttemptStore struct {
+	counts    map[string]int
+	expiresAt map[string]time.Time
+}
+
+func NewMockLoginAttemptStore() *MockLoginAttemptStore {
+	return &MockLoginAttemptStore{
+		counts:    make(map[string]int),
+		expiresAt: make(map[string]time.Time),
+	}
+}
+
+func (m *MockLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	m.counts[key]++
+	m.expiresAt[key] = time.Now().Add(window)
+	return m.counts[key], nil
+}
+
+func (m *MockLoginAttemptStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	return m.counts[key], m.expiresAt[key], nil
+}
+
+func (m *MockLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	delete(m.counts, key)
+	delete(m.expiresAt, key)
+	return nil
+}
+
+// tokenFromEmail extracts the confirmation token from an email body
+func tokenFromEmail(body string) string {
+	idx := strings.Index(body, "token=")
+	if idx < 0 {
+		return ""
+	}
+	token := body[idx+len("token="):]
+	if end := strings.IndexAny(token, " \n"); end >= 0 {
+		token = token[:end]
+	}
+	return token
+}
+
+func newTestUserServiceWithMailer(repo *MockUserRepository, mailer *MockMailer) *service.UserService {
+	return newTestUserServiceWithAudit(repo, mailer, &MockAuditLogger{})
+}
+
+func newTestUserServiceWithAudit(repo *MockUserRepository, mailer *MockMailer, audit *MockAuditLogger) *service.UserService {
+	return newTestUserServiceWithIdentities(repo, NewMockIdentityRepository(), mailer, audit)
+}
+
+func newTestUserServiceWithIdentities(repo *MockUserRepository, identities *MockIdentityRepository, mailer *MockMailer, audit *MockAuditLogger) *service.UserService {
+	return newTestUserServiceWithEvents(repo, identities, mailer, audit, &MockSecurityEvents{})
+}
+
+func newTestUserServiceWithEvents(repo *MockUserRepository, identities *MockIdentityRepository, mailer *MockMailer, audit *MockAuditLogger, events *MockSecurityEvents) *service.UserService {
+	settings := service.UserSettings{
+		BaseURL:             "http://localhost:8080",
+		EmailChangeTTL:      time.Hour,
+		DeletionGracePeriod: 24 * time.Hour,
+	}
+	return service.NewUserService(repo, NewMockEmailChangeRepository(repo), repo, identities, NewMockMagicLinkRepository(), NewMockLoginAttemptStore(), nil, user.NewEmailNormalizer(user.DefaultCanonicalProviders), mailer, audit, events, &MockEventPublisher{}, settings, NewMockLogger())
+}
+
+// newTestUserServiceWithLockout locks logins after three failures
+func newTestUserServiceWithLockout(repo *MockUserRepository, attempts *MockLoginAttemptStore, audit *MockAuditLogger) *service.UserService {
+	settings := service.UserSettings{
+		BaseURL:             "http://localhost:8080",
+		EmailChangeTTL:      time.Hour,
+		DeletionGracePeriod: 24 * time.Hour,
+		MaxLoginAttempts:    3,
+		LockoutWindow:       15 * time.Minute,
+	}
+	return service.NewUserService(repo, NewMockEmailChangeRepository(repo), repo, NewMockIdentityRepository(), NewMockMagicLinkRepository(), attempts, nil, user.NewEmailNormalizer(user.DefaultCanonicalProviders), &MockMailer{}, audit, &MockSecurityEvents{}, &MockEventPublisher{}, settings, NewMockLogger())
+}
+
+// newTestUserServiceWithMagicLinks sends at most two magic links per hour
+func newTestUserServiceWithMagicLinks(repo *MockUserRepository, links *MockMagicLinkRepository, mailer *MockMailer, audit *MockAuditLogger) *service.UserService {
+	settings := service.UserSettings{
+		BaseURL:              "http://localhost:8080",
+		MagicLinkTTL:         15 * time.Minute,
+		MaxMagicLinkRequests: 2,
+	}
+	return service.NewUserService(repo, NewMockEmailChangeRepository(repo), repo, NewMockIdentityRepository(), links, NewMockLoginAttemptStore(), nil, user.NewEmailNormalizer(user.DefaultCanonicalProviders), mailer, audit, &MockSecurityEvents{}, &MockEventPublisher{}, settings, NewMockLogger())
+}
+
+func newTestUserService(repo *MockUserRepository) *service.UserService {
+	return newTestUserServiceWithMailer(repo, &MockMailer{})
+}
+
 func TestUserService_Implementation(t *testing.T) {
 	// Test that our concrete service implements the interface
 	repo := NewMockUserRepository()
-	var _ user.Service = service.NewUserService(repo, NewMockLogger())
+	var _ user.Service = newTestUserService(repo)
 }
 
 func TestUserService_Register_Integration(t *testing.T) {
 	repo := NewMockUserRepository()
-	userService := service.NewUserService(repo, NewMockLogger())
+	userService := newTestUserService(repo)
 	ctx := context.Background()
 
 	// Test successful registration
@@ -125,9 +415,34 @@ func TestUserService_Register_Integration(t *testing.T) {
 	}
 }
 
+func TestUserService_Register_NormalizedDuplicate(t *testing.T) {
+	repo := NewMockUserRepository()
+	userService := newTestUserService(repo)
+	ctx := context.Background()
+
+	_, err := userService.Register(ctx, "John Doe", "John.Doe@Gmail.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	// Same mailbox with different case, dots and plus-tag
+	duplicates := []string{"john.doe@gmail.com", "JOHNDOE@gmail.com", "johndoe+blog@googlemail.com"}
+	for _, email := range duplicates {
+		_, err = userService.Register(ctx, "Jane Doe", email, "password456")
+		if err != user.ErrUserExists {
+			t.Errorf("expected ErrUserExists for %s, got %v", email, err)
+		}
+	}
+
+	// Login works with any equivalent address
+	if _, err := userService.Login(ctx, "johndoe+other@gmail.com", "password123"); err != nil {
+		t.Errorf("expected login with canonical equivalent to succeed, got %v", err)
+	}
+}
+
 func TestUserService_Login_Integration(t *testing.T) {
 	repo := NewMockUserRepository()
-	userService := service.NewUserService(repo, NewMockLogger())
+	userService := newTestUserService(repo)
 	ctx := context.Background()
 
 	// Register a user first
@@ -159,9 +474,127 @@ func TestUserService_Login_Integration(t *testing.T) {
 	}
 }
 
+func TestUserService_Login_EmailConflict(t *testing.T) {
+	repo := NewMockUserRepository()
+	userService := newTestUserService(repo)
+	ctx := context.Background()
+
+	owner, err := userService.Register(ctx, "Jane Doe", "jane@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	// An account the email normalization migration flagged for sharing the
+	// canonical address of the older account
+	flagged, _ := user.NewUser("Jane Doe", "Jane@Example.com", "password456")
+	flagged.EmailConflict = true
+	repo.Create(ctx, flagged)
+
+	u, err := userService.Login(ctx, "Jane@Example.com", "password456")
+	if err != nil {
+		t.Fatalf("expected flagged account to log in, got %v", err)
+	}
+	if u.ID != flagged.ID {
+		t.Errorf("expected user %d, got %d", flagged.ID, u.ID)
+	}
+
+	// The canonical address still belongs to the older account
+	u, err = userService.Login(ctx, "JANE@example.com", "password123")
+	if err != nil {
+		t.Fatalf("expected owner to log in, got %v", err)
+	}
+	if u.ID != owner.ID {
+		t.Errorf("expected user %d, got %d", owner.ID, u.ID)
+	}
+
+	if _, err := userService.Login(ctx, "Jane@Example.com", "password123"); err != user.ErrInvalidCredentials {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestUserService_Login_Lockout(t *testing.T) {
+	repo := NewMockUserRepository()
+	attempts := NewMockLoginAttemptStore()
+	audit := &MockAuditLogger{}
+	userService := newTestUserServiceWithLockout(repo, attempts, audit)
+	ctx := user.WithClientIP(context.Background(), "203.0.113.7")
+
+	registeredUser, err := userService.Register(ctx, "John Doe", "lockout@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	accountKey := user.AccountAttemptKey(registeredUser.ID)
+
+	// A successful login clears earlier failures of the account
+	userService.Login(context.Background(), "lockout@example.com", "wrongpassword")
+	userService.Login(context.Background(), "lockout@example.com", "wrongpassword")
+	if _, err := userService.Login(context.Background(), "lockout@example.com", "password123"); err != nil {
+		t.Fatalf("expected login to succeed, got %v", err)
+	}
+	if attempts.counts[accountKey] != 0 {
+		t.Errorf("expected failures to be reset, got %d", attempts.counts[accountKey])
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := userService.Login(ctx, "lockout@example.com", "wrongpassword"); err != user.ErrInvalidCredentials {
+			t.Fatalf("attempt %d: expected ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+	locks := 0
+	for _, e := range audit.events {
+		if e.Action == service.AuditActionUserLocked {
+			locks++
+		}
+	}
+	if locks != 1 {
+		t.Errorf("expected one lock audit event, got %+v", audit.events)
+	}
+
+	// Locked even with the right password, from any client
+	_, err = userService.Login(context.Background(), "lockout@example.com", "password123")
+	var lockout *user.LockoutError
+	if !errors.As(err, &lockout) || !errors.Is(err, user.ErrAccountLocked) {
+		t.Fatalf("expected lockout error, got %v", err)
+	}
+	if lockout.Until.Before(time.Now().Add(14 * time.Minute)) {
+		t.Errorf("expected lockout to last the window, until %v", lockout.Until)
+	}
+
+	// The client IP is locked too, including for other accounts
+	_, err = userService.Login(ctx, "someone@example.com", "password123")
+	if !errors.Is(err, user.ErrAccountLocked) {
+		t.Errorf("expected client lockout, got %v", err)
+	}
+
+	// The lockout ends with the window
+	attempts.Reset(ctx, accountKey)
+	if _, err := userService.Login(context.Background(), "lockout@example.com", "password123"); err != nil {
+		t.Errorf("expected login after the window to succeed, got %v", err)
+	}
+}
+
+func TestUserService_Login_LockoutCountsUnknownEmails(t *testing.T) {
+	repo := NewMockUserRepository()
+	attempts := NewMockLoginAttemptStore()
+	userService := newTestUserServiceWithLockout(repo, attempts, &MockAuditLogger{})
+	ctx := user.WithClientIP(context.Background(), "203.0.113.8")
+
+	for i := 0; i < 3; i++ {
+		userService.Login(ctx, fmt.Sprintf("guess%d@example.com", i), "password123")
+	}
+
+	if attempts.counts[user.ClientAttemptKey("203.0.113.8")] != 3 {
+		t.Errorf("expected 3 failures for the client, got %v", attempts.counts)
+	}
+	if _, err := userService.Login(ctx, "guess@example.com", "password123"); !errors.Is(err, user.ErrAccountLocked) {
+		t.Errorf("expected client lockout, got %v", err)
+	}
+}
+
 func TestUserService_UpdateProfile_Integration(t *testing.T) {
 	repo := NewMockUserRepository()
-	userService := service.NewUserService(repo, NewMockLogger())
+	mailer := &MockMailer{}
+	userService := newTestUserServiceWithMailer(repo, mailer)
 	ctx := context.Background()
 
 	// Register a user first
@@ -180,8 +613,9 @@ func TestUserService_UpdateProfile_Integration(t *testing.T) {
 		t.Errorf("expected name 'Jane Doe', got %s", updatedUser.Name)
 	}
 
-	if updatedUser.Email != "jane@example.com" {
-		t.Errorf("expected email 'jane@example.com', got %s", updatedUser.Email)
+	// The email change requires confirmation, so the old address stays active
+	if updatedUser.Email != "update@example.com" {
+		t.Errorf("expected email to stay 'update@example.com' until confirmed, got %s", updatedUser.Email)
 	}
 
 	// Verify the update persisted
@@ -193,11 +627,164 @@ func TestUserService_UpdateProfile_Integration(t *testing.T) {
 	if retrievedUser.Name != "Jane Doe" {
 		t.Errorf("expected persisted name 'Jane Doe', got %s", retrievedUser.Name)
 	}
+
+	// Confirmation goes to the new address, a notice to the old one
+	if len(mailer.sent) != 2 {
+		t.Fatalf("expected 2 emails, got %d", len(mailer.sent))
+	}
+	if mailer.sent[0].To != "jane@example.com" {
+		t.Errorf("expected confirmation sent to new address, got %s", mailer.sent[0].To)
+	}
+	if mailer.sent[1].To != "update@example.com" {
+		t.Errorf("expected notice sent to old address, got %s", mailer.sent[1].To)
+	}
+}
+
+func TestUserService_ConfirmEmailChange(t *testing.T) {
+	repo := NewMockUserRepository()
+	mailer := &MockMailer{}
+	events := &MockSecurityEvents{}
+	userService := newTestUserServiceWithEvents(repo, NewMockIdentityRepository(), mailer, &MockAuditLogger{}, events)
+	ctx := context.Background()
+
+	registeredUser, err := userService.Register(ctx, "John Doe", "old@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	if err := userService.RequestEmailChange(ctx, registeredUser.ID, "new@example.com"); err != nil {
+		t.Fatalf("failed to request email change: %v", err)
+	}
+
+	// Old address keeps working until confirmation
+	if _, err := userService.Login(ctx, "old@example.com", "password123"); err != nil {
+		t.Errorf("expected old email to keep working, got %v", err)
+	}
+
+	token := tokenFromEmail(mailer.sent[0].Body)
+	if token == "" {
+		t.Fatal("expected confirmation token in email body")
+	}
+
+	// Unknown token is rejected
+	if _, err := userService.ConfirmEmailChange(ctx, "bogus"); err != user.ErrEmailChangeNotFound {
+		t.Errorf("expected ErrEmailChangeNotFound, got %v", err)
+	}
+
+	confirmed, err := userService.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if confirmed.Email != "new@example.com" {
+		t.Errorf("expected email 'new@example.com', got %s", confirmed.Email)
+	}
+
+	// New address works, old one no longer does
+	if _, err := userService.Login(ctx, "new@example.com", "password123"); err != nil {
+		t.Errorf("expected new email to work, got %v", err)
+	}
+	if _, err := userService.Login(ctx, "old@example.com", "password123"); err != user.ErrInvalidCredentials {
+		t.Errorf("expected old email to stop working, got %v", err)
+	}
+
+	// Token is single-use
+	if _, err := userService.ConfirmEmailChange(ctx, token); err != user.ErrEmailChangeNotFound {
+		t.Errorf("expected used token to be rejected, got %v", err)
+	}
+
+	// The previous address is notified of the completed change
+	expected := service.SecurityEvent{Kind: service.SecurityEventEmailChanged, UserID: registeredUser.ID, Email: "old@example.com", Detail: "new@example.com"}
+	if len(events.events) != 1 || events.events[0] != expected {
+		t.Errorf("expected email change event %+v, got %+v", expected, events.events)
+	}
+}
+
+func TestUserService_RequestEmailChange_Conflict(t *testing.T) {
+	repo := NewMockUserRepository()
+	userService := newTestUserService(repo)
+	ctx := context.Background()
+
+	first, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if _, err := userService.Register(ctx, "Jane Doe", "jane@example.com", "password123"); err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	if err := userService.RequestEmailChange(ctx, first.ID, "JANE@example.com"); err != user.ErrUserExists {
+		t.Errorf("expected ErrUserExists, got %v", err)
+	}
+
+	if err := userService.RequestEmailChange(ctx, first.ID, "John@Example.com"); err != user.ErrEmailUnchanged {
+		t.Errorf("expected ErrEmailUnchanged, got %v", err)
+	}
+}
+
+func TestUserService_RequestEmailChange_InvalidEmail(t *testing.T) {
+	repo := NewMockUserRepository()
+	mailer := &MockMailer{}
+	userService := newTestUserServiceWithMailer(repo, mailer)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	sent := len(mailer.sent)
+
+	for _, email := range []string{"not-an-email", "john@", "John <john@example.org>", "john@example."} {
+		if err := userService.RequestEmailChange(ctx, registered.ID, email); err != user.ErrInvalidEmail {
+			t.Errorf("RequestEmailChange(%q): expected ErrInvalidEmail, got %v", email, err)
+		}
+		if _, err := userService.UpdateProfile(ctx, registered.ID, "John Doe", email); err != user.ErrInvalidEmail {
+			t.Errorf("UpdateProfile(%q): expected ErrInvalidEmail, got %v", email, err)
+		}
+	}
+	if len(mailer.sent) != sent {
+		t.Errorf("expected no confirmation emails for invalid addresses, got %d", len(mailer.sent)-sent)
+	}
+}
+
+func TestUserService_AuditEvents(t *testing.T) {
+	repo := NewMockUserRepository()
+	audit := &MockAuditLogger{}
+	userService := newTestUserServiceWithAudit(repo, &MockMailer{}, audit)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "John Doe", "audit@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	userService.Login(ctx, "audit@example.com", "wrongpassword")
+	userService.Login(ctx, "audit@example.com", "password123")
+	if err := userService.UpdatePassword(ctx, registered.ID, "password123", "newpassword123"); err != nil {
+		t.Fatalf("failed to update password: %v", err)
+	}
+	if err := userService.Delete(ctx, registered.ID); err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	expected := []string{
+		service.AuditActionUserRegistered,
+		service.AuditActionUserLoginFailed,
+		service.AuditActionUserLogin,
+		service.AuditActionPasswordChanged,
+		service.AuditActionUserDeleted,
+	}
+	if len(audit.events) != len(expected) {
+		t.Fatalf("expected audit events %v, got %+v", expected, audit.events)
+	}
+	for i, e := range audit.events {
+		if e.Action != expected[i] || e.UserID != registered.ID {
+			t.Errorf("event %d: expected %s for user %d, got %+v", i, expected[i], registered.ID, e)
+		}
+	}
 }
 
 func TestUserService_UpdatePassword_Integration(t *testing.T) {
 	repo := NewMockUserRepository()
-	userService := service.NewUserService(repo, NewMockLogger())
+	userService := newTestUserService(repo)
 	ctx := context.Background()
 
 	// Register a user first
@@ -231,9 +818,136 @@ func TestUserService_UpdatePassword_Integration(t *testing.T) {
 	}
 }
 
+func TestUserService_UpdatePassword_PublishesEvent(t *testing.T) {
+	repo := NewMockUserRepository()
+	events := &MockSecurityEvents{}
+	userService := newTestUserServiceWithEvents(repo, NewMockIdentityRepository(), &MockMailer{}, &MockAuditLogger{}, events)
+	ctx := context.Background()
+
+	registeredUser, err := userService.Register(ctx, "John Doe", "events@example.com", "oldpassword123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	if err := userService.UpdatePassword(ctx, registeredUser.ID, "wrongpassword", "newpassword123"); err != user.ErrInvalidCredentials {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected no event for a rejected change, got %+v", events.events)
+	}
+
+	if err := userService.UpdatePassword(ctx, registeredUser.ID, "oldpassword123", "newpassword123"); err != nil {
+		t.Fatalf("failed to update password: %v", err)
+	}
+	expected := service.SecurityEvent{Kind: service.SecurityEventPasswordChanged, UserID: registeredUser.ID}
+	if len(events.events) != 1 || events.events[0] != expected {
+		t.Errorf("expected password change event %+v, got %+v", expected, events.events)
+	}
+}
+
+func TestUserService_Login_PasswordResetRequired(t *testing.T) {
+	repo := NewMockUserRepository()
+	identities := NewMockIdentityRepository()
+	userService := newTestUserServiceWithIdentities(repo, identities, &MockMailer{}, &MockAuditLogger{})
+	ctx := context.Background()
+
+	registeredUser, err := userService.Register(ctx, "John Doe", "locked@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	registeredUser.RequirePasswordReset()
+
+	// A wrong password does not reveal the lock
+	if _, err := userService.Login(ctx, "locked@example.com", "wrongpassword"); err != user.ErrInvalidCredentials {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := userService.Login(ctx, "locked@example.com", "password123"); err != user.ErrPasswordResetRequired {
+		t.Errorf("expected ErrPasswordResetRequired, got %v", err)
+	}
+
+	external := user.ExternalIdentity{Provider: "google", Subject: "123", Email: "locked@example.com", EmailVerified: true}
+	if _, err := userService.LoginWithIdentity(ctx, external); err != user.ErrPasswordResetRequired {
+		t.Errorf("expected ErrPasswordResetRequired for external login, got %v", err)
+	}
+	if len(identities.identities) != 0 {
+		t.Errorf("expected no identity linked to a locked account, got %d", len(identities.identities))
+	}
+}
+
+func TestUserService_Deactivate(t *testing.T) {
+	repo := NewMockUserRepository()
+	audit := &MockAuditLogger{}
+	userService := newTestUserServiceWithAudit(repo, &MockMailer{}, audit)
+	ctx := context.Background()
+
+	registeredUser, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	if err := userService.Deactivate(ctx, registeredUser.ID); err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+	if err := userService.Deactivate(ctx, registeredUser.ID); err != nil {
+		t.Errorf("expected deactivating twice to succeed, got %v", err)
+	}
+	if _, err := userService.Login(ctx, "john@example.com", "password123"); err != user.ErrAccountDeactivated {
+		t.Errorf("expected ErrAccountDeactivated, got %v", err)
+	}
+	if _, err := userService.CompleteLogin(ctx, registeredUser.ID, "passkey"); err != user.ErrAccountDeactivated {
+		t.Errorf("expected ErrAccountDeactivated for passwordless login, got %v", err)
+	}
+
+	if err := userService.Reactivate(ctx, registeredUser.ID); err != nil {
+		t.Fatalf("failed to reactivate user: %v", err)
+	}
+	if _, err := userService.Login(ctx, "john@example.com", "password123"); err != nil {
+		t.Errorf("expected login after reactivation, got %v", err)
+	}
+
+	var actions []string
+	for _, event := range audit.events {
+		if event.Action == service.AuditActionUserDeactivated || event.Action == service.AuditActionUserReactivated {
+			actions = append(actions, event.Action)
+		}
+	}
+	if len(actions) != 2 {
+		t.Errorf("expected one deactivation and one reactivation event, got %v", actions)
+	}
+}
+
+func TestUserService_SyncProfile(t *testing.T) {
+	repo := NewMockUserRepository()
+	userService := newTestUserService(repo)
+	ctx := context.Background()
+
+	john, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	if _, err := userService.Register(ctx, "Jane Doe", "jane@example.com", "password123"); err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	if _, err := userService.SyncProfile(ctx, john.ID, "John Doe", "Jane@Example.com"); err != user.ErrUserExists {
+		t.Errorf("expected ErrUserExists for a taken address, got %v", err)
+	}
+
+	synced, err := userService.SyncProfile(ctx, john.ID, "Johnny Doe", "johnny@example.com")
+	if err != nil {
+		t.Fatalf("failed to sync profile: %v", err)
+	}
+	if synced.Name != "Johnny Doe" || synced.Email != "johnny@example.com" {
+		t.Errorf("expected synced profile, got %q <%s>", synced.Name, synced.Email)
+	}
+	if _, err := userService.Login(ctx, "johnny@example.com", "password123"); err != nil {
+		t.Errorf("expected login with the synced address without confirmation, got %v", err)
+	}
+}
+
 func TestUserService_List_Integration(t *testing.T) {
 	repo := NewMockUserRepository()
-	userService := service.NewUserService(repo, NewMockLogger())
+	userService := newTestUserService(repo)
 	ctx := context.Background()
 
 	// Register multiple users
@@ -277,3 +991,340 @@ func TestUserService_List_Integration(t *testing.T) {
 		t.Errorf("expected 5 users, got %d", len(users))
 	}
 }
+
+func TestUserService_ScheduleDeletion_LoginRestores(t *testing.T) {
+	repo := NewMockUserRepository()
+	mailer := &MockMailer{}
+	audit := &MockAuditLogger{}
+	userService := newTestUserServiceWithAudit(repo, mailer, audit)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	u, err := userService.ScheduleDeletion(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !u.IsPendingDeletion() {
+		t.Fatal("expected account to be pending deletion")
+	}
+	purgeAt := *u.DeletionScheduledAt
+
+	// Scheduling again keeps the original date
+	u, err = userService.ScheduleDeletion(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !u.DeletionScheduledAt.Equal(purgeAt) {
+		t.Errorf("expected purge date %v to be kept, got %v", purgeAt, *u.DeletionScheduledAt)
+	}
+
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "john@example.com" {
+		t.Errorf("expected one deletion notice to john@example.com, got %+v", mailer.sent)
+	}
+
+	if _, err := userService.Login(ctx, "john@example.com", "password123"); err != nil {
+		t.Fatalf("expected login to succeed, got %v", err)
+	}
+	if repo.users[registered.ID].IsPendingDeletion() {
+		t.Error("expected login to restore the account")
+	}
+
+	actions := make([]string, len(audit.events))
+	for i, e := range audit.events {
+		actions[i] = e.Action
+	}
+	expected := []string{service.AuditActionUserRegistered, service.AuditActionUserDeletionRequested, service.AuditActionUserDeletionCancelled, service.AuditActionUserLogin}
+	if strings.Join(actions, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected audit events %v, got %v", expected, actions)
+	}
+}
+
+func TestUserService_PurgeDeletedAccounts(t *testing.T) {
+	repo := NewMockUserRepository()
+	audit := &MockAuditLogger{}
+	userService := newTestUserServiceWithAudit(repo, &MockMailer{}, audit)
+	ctx := context.Background()
+
+	due, err := userService.Register(ctx, "Due User", "due@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+	pending, err := userService.Register(ctx, "Pending User", "pending@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	due.DeletionScheduledAt = &past
+	if _, err := userService.ScheduleDeletion(ctx, pending.ID); err != nil {
+		t.Fatalf("failed to schedule deletion: %v", err)
+	}
+
+	purged, err := userService.PurgeDeletedAccounts(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 account purged, got %d", purged)
+	}
+
+	if repo.users[due.ID].DeletedAt == nil || repo.users[due.ID].Email != "" {
+		t.Error("expected due account to be anonymized")
+	}
+	if !repo.users[pending.ID].IsPendingDeletion() {
+		t.Error("expected account within grace period to be kept")
+	}
+
+	last := audit.events[len(audit.events)-1]
+	if last.Action != service.AuditActionUserPurged || last.UserID != due.ID {
+		t.Errorf("expected purge audit event for user %d, got %+v", due.ID, last)
+	}
+
+	// Purged accounts can no longer log in
+	if _, err := userService.Login(ctx, "due@example.com", "password123"); err != user.ErrInvalidCredentials {
+		t.Errorf("expected invalid credentials after purge, got %v", err)
+	}
+}
+
+func TestUserService_LoginWithIdentity(t *testing.T) {
+	ctx := context.Background()
+	google := user.ExternalIdentity{
+		Provider:      "google",
+		Subject:       "g-123",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}
+
+	t.Run("registers a new user and links the identity", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		identities := NewMockIdentityRepository()
+		audit := &MockAuditLogger{}
+		userService := newTestUserServiceWithIdentities(repo, identities, &MockMailer{}, audit)
+
+		u, err := userService.LoginWithIdentity(ctx, google)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if u.Name != "Jane Doe" || u.Email != "jane@example.com" {
+			t.Errorf("unexpected user %q <%s>", u.Name, u.Email)
+		}
+		if len(identities.identities) != 1 || identities.identities[0].UserID != u.ID {
+			t.Fatalf("expected identity linked to user %d, got %+v", u.ID, identities.identities)
+		}
+		actions := make([]string, len(audit.events))
+		for i, e := range audit.events {
+			actions[i] = e.Action
+		}
+		expected := []string{service.AuditActionUserRegistered, service.AuditActionIdentityLinked, service.AuditActionUserLogin}
+		if strings.Join(actions, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected audit events %v, got %v", expected, actions)
+		}
+	})
+
+	t.Run("links to the existing user with the same email", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		identities := NewMockIdentityRepository()
+		mailer := &MockMailer{}
+		userService := newTestUserServiceWithIdentities(repo, identities, mailer, &MockAuditLogger{})
+
+		existing, err := userService.Register(ctx, "Jane", "Jane@Example.com", "password123")
+		if err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+
+		u, err := userService.LoginWithIdentity(ctx, google)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if u.ID != existing.ID {
+			t.Errorf("expected user %d, got %d", existing.ID, u.ID)
+		}
+		if len(repo.users) != 1 {
+			t.Errorf("expected no new user, got %d users", len(repo.users))
+		}
+		if len(mailer.sent) == 0 || mailer.sent[len(mailer.sent)-1].To != existing.Email {
+			t.Errorf("expected link notice sent to %s", existing.Email)
+		}
+	})
+
+	t.Run("signs in through an existing link", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		identities := NewMockIdentityRepository()
+		userService := newTestUserServiceWithIdentities(repo, identities, &MockMailer{}, &MockAuditLogger{})
+
+		first, err := userService.LoginWithIdentity(ctx, google)
+		if err != nil {
+			t.Fatalf("failed first login: %v", err)
+		}
+
+		// The provider email may change after the link is created
+		changed := google
+		changed.Email = "other@example.com"
+		changed.EmailVerified = false
+		u, err := userService.LoginWithIdentity(ctx, changed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if u.ID != first.ID {
+			t.Errorf("expected user %d, got %d", first.ID, u.ID)
+		}
+		if len(identities.identities) != 1 {
+			t.Errorf("expected a single identity, got %d", len(identities.identities))
+		}
+	})
+
+	t.Run("rejects an unverified email", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		userService := newTestUserServiceWithIdentities(repo, NewMockIdentityRepository(), &MockMailer{}, &MockAuditLogger{})
+
+		unverified := google
+		unverified.EmailVerified = false
+		if _, err := userService.LoginWithIdentity(ctx, unverified); err != user.ErrUnverifiedEmail {
+			t.Errorf("expected ErrUnverifiedEmail, got %v", err)
+		}
+		if len(repo.users) != 0 {
+			t.Errorf("expected no user to be created, got %d", len(repo.users))
+		}
+	})
+
+	t.Run("rejects a malformed email", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		userService := newTestUserServiceWithIdentities(repo, NewMockIdentityRepository(), &MockMailer{}, &MockAuditLogger{})
+
+		malformed := google
+		malformed.Email = "jane.example.com"
+		malformed.Name = ""
+		if _, err := userService.LoginWithIdentity(ctx, malformed); err != user.ErrInvalidEmail {
+			t.Errorf("expected ErrInvalidEmail, got %v", err)
+		}
+		if len(repo.users) != 0 {
+			t.Errorf("expected no user to be created, got %d", len(repo.users))
+		}
+	})
+}
+
+func TestUserService_MagicLink(t *testing.T) {
+	ctx := context.Background()
+	laptop := user.NewDevice("203.0.113.7", "Firefox")
+	phone := user.NewDevice("198.51.100.2", "Safari")
+
+	t.Run("emails a single-use link", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		mailer := &MockMailer{}
+		audit := &MockAuditLogger{}
+		userService := newTestUserServiceWithMagicLinks(repo, NewMockMagicLinkRepository(), mailer, audit)
+
+		registeredUser, err := userService.Register(ctx, "John Doe", "john@example.com", "password123")
+		if err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+		if err := userService.RequestMagicLink(ctx, "John@Example.com", laptop); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(mailer.sent) != 1 || mailer.sent[0].To != "john@example.com" {
+			t.Fatalf("expected one email to john@example.com, got %+v", mailer.sent)
+		}
+		if !strings.Contains(mailer.sent[0].Body, "http://localhost:8080/api/v1/auth/magic-link/verify?token=") ||
+			!strings.Contains(mailer.sent[0].Body, "203.0.113.7 (Firefox)") {
+			t.Errorf("expected link and device in email, got %q", mailer.sent[0].Body)
+		}
+
+		token := tokenFromEmail(mailer.sent[0].Body)
+		u, err := userService.LoginWithMagicLink(ctx, token, phone)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if u.ID != registeredUser.ID {
+			t.Errorf("expected user %d, got %d", registeredUser.ID, u.ID)
+		}
+		last := audit.events[len(audit.events)-1]
+		if last.Action != service.AuditActionUserLogin || last.Metadata["method"] != "magic_link" ||
+			last.Metadata["ip"] != "198.51.100.2" || last.Metadata["request_ip"] != "203.0.113.7" {
+			t.Errorf("unexpected login audit event %+v", last)
+		}
+
+		if _, err := userService.LoginWithMagicLink(ctx, token, phone); err != user.ErrInvalidMagicLink {
+			t.Errorf("expected ErrInvalidMagicLink on reuse, got %v", err)
+		}
+	})
+
+	t.Run("a new link replaces the previous one", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		mailer := &MockMailer{}
+		userService := newTestUserServiceWithMagicLinks(repo, NewMockMagicLinkRepository(), mailer, &MockAuditLogger{})
+
+		if _, err := userService.Register(ctx, "John Doe", "john@example.com", "password123"); err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := userService.RequestMagicLink(ctx, "john@example.com", laptop); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if _, err := userService.LoginWithMagicLink(ctx, tokenFromEmail(mailer.sent[0].Body), laptop); err != user.ErrInvalidMagicLink {
+			t.Errorf("expected ErrInvalidMagicLink for the replaced link, got %v", err)
+		}
+		if _, err := userService.LoginWithMagicLink(ctx, tokenFromEmail(mailer.sent[1].Body), laptop); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects expired links", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		links := NewMockMagicLinkRepository()
+		mailer := &MockMailer{}
+		userService := newTestUserServiceWithMagicLinks(repo, links, mailer, &MockAuditLogger{})
+
+		if _, err := userService.Register(ctx, "John Doe", "john@example.com", "password123"); err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+		if err := userService.RequestMagicLink(ctx, "john@example.com", laptop); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, link := range links.links {
+			link.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+		if _, err := userService.LoginWithMagicLink(ctx, tokenFromEmail(mailer.sent[0].Body), laptop); err != user.ErrInvalidMagicLink {
+			t.Errorf("expected ErrInvalidMagicLink, got %v", err)
+		}
+	})
+
+	t.Run("sends nothing for unknown, locked or throttled accounts", func(t *testing.T) {
+		repo := NewMockUserRepository()
+		mailer := &MockMailer{}
+		userService := newTestUserServiceWithMagicLinks(repo, NewMockMagicLinkRepository(), mailer, &MockAuditLogger{})
+
+		if err := userService.RequestMagicLink(ctx, "nobody@example.com", laptop); err != nil {
+			t.Errorf("expected no error for unknown email, got %v", err)
+		}
+
+		locked, err := userService.Register(ctx, "Locked", "locked@example.com", "password123")
+		if err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+		locked.RequirePasswordReset()
+		if err := userService.RequestMagicLink(ctx, "locked@example.com", laptop); err != nil {
+			t.Errorf("expected no error for locked account, got %v", err)
+		}
+		if len(mailer.sent) != 0 {
+			t.Fatalf("expected no emails, got %d", len(mailer.sent))
+		}
+
+		if _, err := userService.Register(ctx, "John Doe", "john@example.com", "password123"); err != nil {
+			t.Fatalf("failed to register user: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := userService.RequestMagicLink(ctx, "john@example.com", laptop); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}
+		if len(mailer.sent) != 2 {
+			t.Errorf("expected 2 emails within the limit, got %d", len(mailer.sent))
+		}
+	})
+}
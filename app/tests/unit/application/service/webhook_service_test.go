@@ -0,0 +1,364 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/user"
+	"blog-platform/internal/domain/webhook"
+)
+
+// MockWebhookRepository implements the webhook.Repository and
+// webhook.DeliveryRepository interfaces for testing
+type MockWebhookRepository struct {
+	webhooks   map[int]*webhook.Webhook
+	deliveries map[int]*webhook.Delivery
+}
+
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{webhooks: make(map[int]*webhook.Webhook), deliveries: make(map[int]*webhook.Delivery)}
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, w *webhook.Webhook) error {
+	w.ID = len(m.webhooks) + 1
+	m.webhooks[w.ID] = w
+	return nil
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id int) (*webhook.Webhook, error) {
+	w, ok := m.webhooks[id]
+	if !ok {
+		return nil, webhook.ErrWebhookNotFound
+	}
+	return w, nil
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]*webhook.Webhook, error) {
+	var webhooks []*webhook.Webhook
+	for _, w := range m.webhooks {
+		webhooks = append(webhooks, w)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID > webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) ListActive(ctx context.Context) ([]*webhook.Webhook, error) {
+	var webhooks []*webhook.Webhook
+	for _, w := range m.webhooks {
+		if w.Active {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, w *webhook.Webhook) error {
+	if _, ok := m.webhooks[w.ID]; !ok {
+		return webhook.ErrWebhookNotFound
+	}
+	m.webhooks[w.ID] = w
+	return nil
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int) error {
+	if _, ok := m.webhooks[id]; !ok {
+		return webhook.ErrWebhookNotFound
+	}
+	delete(m.webhooks, id)
+	for deliveryID, d := range m.deliveries {
+		if d.WebhookID == id {
+			delete(m.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	d.ID = len(m.deliveries) + 1
+	m.deliveries[d.ID] = d
+	return nil
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id int) (*webhook.Delivery, error) {
+	d, ok := m.deliveries[id]
+	if !ok {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	if _, ok := m.deliveries[d.ID]; !ok {
+		return webhook.ErrDeliveryNotFound
+	}
+	m.deliveries[d.ID] = d
+	return nil
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, webhookID, limit, offset int) ([]*webhook.Delivery, int, error) {
+	var deliveries []*webhook.Delivery
+	for id := len(m.deliveries); id > 0; id-- {
+		if d, ok := m.deliveries[id]; ok && d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	total := len(deliveries)
+	if offset > total {
+		offset = total
+	}
+	deliveries = deliveries[offset:]
+	if limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, total, nil
+}
+
+// MockWebhookSender records the deliveries sent and answers with status
+type MockWebhookSender struct {
+	sent   []*webhook.Delivery
+	status int
+	err    error
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, error) {
+	m.sent = append(m.sent, d)
+	return m.status, m.err
+}
+
+func newWebhookService(repo *MockWebhookRepository, sender *MockWebhookSender, jobs *MockJobQueue) *service.WebhookService {
+	return service.NewWebhookService(repo, repo, sender, jobs, &MockAuditLogger{}, service.WebhookSettings{MaxAttempts: 3}, NewMockLogger())
+}
+
+func TestWebhookService_Lifecycle(t *testing.T) {
+	repo := NewMockWebhookRepository()
+	audit := &MockAuditLogger{}
+	webhookService := service.NewWebhookService(repo, repo, &MockWebhookSender{}, &MockJobQueue{}, audit, service.WebhookSettings{MaxAttempts: 3}, NewMockLogger())
+	ctx := context.Background()
+
+	w, err := webhookService.Create(ctx, 1, webhook.Input{
+		URL:    "https://hooks.example.com/blog",
+		Events: []string{webhook.EventPostPublished, webhook.EventCommentCreated, webhook.EventPostPublished},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(w.Secret) < 32 {
+		t.Errorf("expected a generated secret, got %q", w.Secret)
+	}
+	if len(w.Events) != 2 || w.Events[0] != webhook.EventCommentCreated {
+		t.Errorf("expected the events deduplicated and sorted, got %v", w.Events)
+	}
+
+	if _, err := webhookService.Create(ctx, 1, webhook.Input{URL: "ftp://hooks.example.com", Events: []string{webhook.EventPostPublished}}); err != webhook.ErrInvalidURL {
+		t.Errorf("expected ErrInvalidURL, got %v", err)
+	}
+	if _, err := webhookService.Create(ctx, 1, webhook.Input{URL: "https://hooks.example.com", Events: []string{"post.deleted"}}); err != webhook.ErrInvalidEvents {
+		t.Errorf("expected ErrInvalidEvents, got %v", err)
+	}
+
+	secret := w.Secret
+	updated, err := webhookService.Update(ctx, 1, w.ID, webhook.Input{URL: "https://hooks.example.com/v2", Events: []string{webhook.EventUserRegistered}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Active || updated.Secret != secret {
+		t.Errorf("expected the webhook deactivated with its secret kept, got %+v", updated)
+	}
+	if err := webhookService.Delete(ctx, 1, w.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := webhookService.Delete(ctx, 1, w.ID); err != webhook.ErrWebhookNotFound {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+
+	actions := []string{service.AuditActionWebhookCreated, service.AuditActionWebhookUpdated, service.AuditActionWebhookDeleted}
+	if len(audit.events) != len(actions) {
+		t.Fatalf("expected %d audit events, got %d", len(actions), len(audit.events))
+	}
+	for i, action := range actions {
+		if audit.events[i].Action != action || audit.events[i].UserID != 1 {
+			t.Errorf("expected audit event %q by admin 1, got %+v", action, audit.events[i])
+		}
+	}
+}
+
+func TestWebhookService_PublishQueuesSubscribedWebhooks(t *testing.T) {
+	repo := NewMockWebhookRepository()
+	jobs := &MockJobQueue{}
+	webhookService := newWebhookService(repo, &MockWebhookSender{}, jobs)
+	ctx := context.Background()
+
+	posts, _ := webhookService.Create(ctx, 1, webhook.Input{URL: "https://a.example.com", Events: []string{webhook.EventPostPublished}, Active: true})
+	webhookService.Create(ctx, 1, webhook.Input{URL: "https://b.example.com", Events: []string{webhook.EventUserRegistered}, Active: true})
+	webhookService.Create(ctx, 1, webhook.Input{URL: "https://c.example.com", Events: []string{webhook.EventPostPublished}, Active: false})
+
+	webhookService.Publish(ctx, webhook.EventPostPublished, map[string]any{"id": 7})
+
+	if len(jobs.kinds) != 1 || jobs.kinds[0] != service.JobWebhookDelivery {
+		t.Fatalf("expected one webhook delivery job, got %v", jobs.kinds)
+	}
+	payload := jobs.payloads[0].(service.WebhookDeliveryPayload)
+	d := repo.deliveries[payload.DeliveryID]
+	if d == nil || d.WebhookID != posts.ID || d.Status != webhook.DeliveryPending {
+		t.Fatalf("expected a pending delivery to the subscribed webhook, got %+v", d)
+	}
+
+	var envelope struct {
+		Event string         `json:"event"`
+		Data  map[string]int `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(d.Payload), &envelope); err != nil {
+		t.Fatalf("expected a JSON payload, got %v", err)
+	}
+	if envelope.Event != webhook.EventPostPublished || envelope.Data["id"] != 7 {
+		t.Errorf("expected the event and its data in the payload, got %s", d.Payload)
+	}
+}
+
+func TestWebhookService_DeliverRecordsAttempts(t *testing.T) {
+	repo := NewMockWebhookRepository()
+	sender := &MockWebhookSender{status: 503, err: errors.New("unexpected status 503")}
+	jobs := &MockJobQueue{}
+	webhookService := newWebhookService(repo, sender, jobs)
+	ctx := context.Background()
+
+	w, _ := webhookService.Create(ctx, 1, webhook.Input{URL: "https://a.example.com", Events: []string{webhook.EventUserRegistered}, Active: true})
+	webhookService.Publish(ctx, webhook.EventUserRegistered, map[string]any{"id": 3})
+	job, _ := json.Marshal(jobs.payloads[0])
+
+	// Failed attempts fail the job so it is retried
+	if err := webhookService.Deliver(ctx, job); err == nil {
+		t.Fatal("expected a failed attempt to fail the job")
+	}
+	deliveries, total, err := webhookService.ListDeliveries(ctx, w.ID, 10, 0)
+	if err != nil || total != 1 {
+		t.Fatalf("expected one delivery in the log, got %d (%v)", total, err)
+	}
+	if d := deliveries[0]; d.Status != webhook.DeliveryPending || d.Attempts != 1 || d.ResponseStatus != 503 || d.Error == "" {
+		t.Errorf("expected the failed attempt recorded on the pending delivery, got %+v", d)
+	}
+
+	// A successful retry marks the delivery delivered; later runs do nothing
+	sender.status, sender.err = 200, nil
+	if err := webhookService.Deliver(ctx, job); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := webhookService.Deliver(ctx, job); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sender.sent) != 2 {
+		t.Errorf("expected delivered deliveries not to be sent again, got %d requests", len(sender.sent))
+	}
+	deliveries, _, _ = webhookService.ListDeliveries(ctx, w.ID, 10, 0)
+	if d := deliveries[0]; d.Status != webhook.DeliveryDelivered || d.Attempts != 2 || d.Error != "" || d.DeliveredAt == nil {
+		t.Errorf("expected the delivery delivered on the second attempt, got %+v", d)
+	}
+
+	if _, _, err := webhookService.ListDeliveries(ctx, 9, 10, 0); err != webhook.ErrWebhookNotFound {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestWebhookService_DeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	repo := NewMockWebhookRepository()
+	sender := &MockWebhookSender{err: errors.New("connection refused")}
+	jobs := &MockJobQueue{}
+	webhookService := newWebhookService(repo, sender, jobs)
+	ctx := context.Background()
+
+	webhookService.Create(ctx, 1, webhook.Input{URL: "https://a.example.com", Events: []string{webhook.EventUserRegistered}, Active: true})
+	webhookService.Publish(ctx, webhook.EventUserRegistered, map[string]any{"id": 3})
+	job, _ := json.Marshal(jobs.payloads[0])
+
+	for attempt := 1; attempt < 3; attempt++ {
+		if err := webhookService.Deliver(ctx, job); err == nil {
+			t.Fatalf("expected attempt %d to fail the job", attempt)
+		}
+	}
+	if err := webhookService.Deliver(ctx, job); err != nil {
+		t.Errorf("expected the last attempt to end the job, got %v", err)
+	}
+	if d := repo.deliveries[1]; d.Status != webhook.DeliveryFailed || d.Attempts != 3 {
+		t.Errorf("expected the delivery failed after 3 attempts, got %+v", d)
+	}
+
+	// Deliveries of deleted webhooks are dropped
+	webhookService.Publish(ctx, webhook.EventUserRegistered, map[string]any{"id": 4})
+	job, _ = json.Marshal(jobs.payloads[1])
+	webhookService.Delete(ctx, 1, 1)
+	if err := webhookService.Deliver(ctx, job); err != nil {
+		t.Errorf("expected deliveries of deleted webhooks to be dropped, got %v", err)
+	}
+}
+
+func TestWebhookService_SubscribesToContentEvents(t *testing.T) {
+	repo := NewMockWebhookRepository()
+	jobs := &MockJobQueue{}
+	webhookService := newWebhookService(repo, &MockWebhookSender{}, jobs)
+	bus := service.NewEventBus(NewMockLogger())
+	webhookService.Subscribe(bus)
+	ctx := context.Background()
+
+	if _, err := webhookService.Create(ctx, 1, webhook.Input{URL: "https://example.com/hook", Events: webhook.Events, Active: true}); err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+
+	posts := NewMockPostRepository()
+	postService := service.NewPostService(posts, NewMockCommentRepository(), &MockTxManager{}, nil, &MockJobQueue{}, &MockViewCounter{}, &MockAuditLogger{}, nil, bus, nil, service.PostSettings{}, NewMockLogger())
+	draft, err := postService.CreateDraft(ctx, 1, "Draft Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create draft: %v", err)
+	}
+	if len(repo.deliveries) != 0 {
+		t.Errorf("expected drafts not to be sent, got %d deliveries", len(repo.deliveries))
+	}
+	p, err := postService.CreatePost(ctx, 1, "Published Post", "Test content with sufficient length.")
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if _, err := postService.PublishPost(ctx, 1, user.RoleAuthor, draft.ID); err != nil {
+		t.Fatalf("failed to publish draft: %v", err)
+	}
+
+	commentService := service.NewCommentService(NewMockCommentRepository(), posts, bus, service.CommentSettings{RequireModeration: true}, NewMockLogger())
+	pending, err := commentService.AddComment(ctx, p.ID, 0, "", "Reader", "A comment held for moderation")
+	if err != nil {
+		t.Fatalf("failed to add comment: %v", err)
+	}
+	if _, err := commentService.ModerateComment(ctx, pending.ID, 1, comment.StatusApproved); err != nil {
+		t.Fatalf("failed to approve comment: %v", err)
+	}
+
+	users := NewMockUserRepository()
+	userService := service.NewUserService(users, NewMockEmailChangeRepository(users), users, NewMockIdentityRepository(), NewMockMagicLinkRepository(), NewMockLoginAttemptStore(), nil, user.NewEmailNormalizer(user.DefaultCanonicalProviders), &MockMailer{}, &MockAuditLogger{}, &MockSecurityEvents{}, bus, service.UserSettings{}, NewMockLogger())
+	if _, err := userService.Register(ctx, "New User", "new@example.com", "password123"); err != nil {
+		t.Fatalf("failed to register user: %v", err)
+	}
+
+	events := []string{webhook.EventPostPublished, webhook.EventPostPublished, webhook.EventCommentCreated, webhook.EventUserRegistered}
+	if len(repo.deliveries) != len(events) || len(jobs.kinds) != len(events) {
+		t.Fatalf("expected %d queued deliveries, got %d (%d jobs)", len(events), len(repo.deliveries), len(jobs.kinds))
+	}
+	for i, event := range events {
+		if d := repo.deliveries[i+1]; d.Event != event {
+			t.Errorf("expected delivery %d of %q, got %q", i+1, event, d.Event)
+		}
+	}
+
+	var envelope struct {
+		Data service.WebhookPost `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(repo.deliveries[1].Payload), &envelope); err != nil || envelope.Data.ID != p.ID || envelope.Data.Slug != p.Slug {
+		t.Errorf("expected the post in the payload, got %s (%v)", repo.deliveries[1].Payload, err)
+	}
+	if err := json.Unmarshal([]byte(repo.deliveries[2].Payload), &envelope); err != nil || envelope.Data.ID != draft.ID {
+		t.Errorf("expected the published draft in the payload, got %s (%v)", repo.deliveries[2].Payload, err)
+	}
+}
@@ -3,8 +3,10 @@ package auth_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"blog-platform/internal/domain/auth"
+	"blog-platform/internal/domain/sso"
 	"blog-platform/internal/domain/user"
 )
 
@@ -51,6 +53,15 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*use
 	return u, nil
 }
 
+// GetConflictedByEmail retrieves a flagged user by exact email
+func (m *MockUserRepository) GetConflictedByEmail(ctx context.Context, email string) (*user.User, error) {
+	u, exists := m.emails[email]
+	if !exists || !u.EmailConflict {
+		return nil, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
 // Update modifies an existing user
 func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
 	if _, exists := m.users[u.ID]; !exists {
@@ -92,15 +103,17 @@ func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
 
 // MockAuthService implements the auth.AuthService interface for testing
 type MockAuthService struct {
-	userRepo    user.Repository
-	tokenService auth.TokenService
+	userRepo      user.Repository
+	tokenService  auth.TokenService
+	refreshTokens map[string]int
 }
 
 // NewMockAuthService creates a new mock auth service
 func NewMockAuthService(userRepo user.Repository, tokenService auth.TokenService) *MockAuthService {
 	return &MockAuthService{
-		userRepo:     userRepo,
-		tokenService: tokenService,
+		userRepo:      userRepo,
+		tokenService:  tokenService,
+		refreshTokens: make(map[string]int),
 	}
 }
 
@@ -109,7 +122,7 @@ func (s *MockAuthService) GenerateToken(ctx context.Context, u *user.User) (stri
 	if u == nil {
 		return "", auth.ErrInvalidCredentials
 	}
-	return s.tokenService.GenerateToken(u.ID, u.Email, 24*3600) // 24 hours in seconds
+	return s.tokenService.GenerateToken(u.ID, u.Email, string(u.Role), 24*3600) // 24 hours in seconds
 }
 
 // ValidateToken validates a JWT token
@@ -118,84 +131,192 @@ func (s *MockAuthService) ValidateToken(ctx context.Context, token string) (*aut
 }
 
 // Login authenticates a user with email and password
-func (s *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, string, error) {
+func (s *MockAuthService) Login(ctx context.Context, email, password string) (*user.User, *auth.TokenPair, error) {
 	// Validate inputs
 	if email == "" {
-		return nil, "", auth.ErrInvalidEmail
+		return nil, nil, auth.ErrInvalidEmail
 	}
 	if password == "" {
-		return nil, "", auth.ErrInvalidCredentials
+		return nil, nil, auth.ErrInvalidCredentials
 	}
 
 	// Get user by email
 	u, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if err == user.ErrUserNotFound {
-			return nil, "", auth.ErrUserNotFound
+			return nil, nil, auth.ErrUserNotFound
 		}
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Check password
 	if !u.ValidatePassword(password) {
-		return nil, "", auth.ErrInvalidCredentials
+		return nil, nil, auth.ErrInvalidCredentials
 	}
 
-	// Generate token
-	token, err := s.GenerateToken(ctx, u)
+	// Generate tokens
+	tokens, err := s.issueTokens(ctx, u)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	return u, token, nil
+	return u, tokens, nil
 }
 
 // Register creates a new user account
-func (s *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, string, error) {
+func (s *MockAuthService) Register(ctx context.Context, name, email, password string) (*user.User, *auth.TokenPair, error) {
 	// Validate inputs
 	if name == "" {
-		return nil, "", user.ErrInvalidUserData
+		return nil, nil, user.ErrInvalidUserData
 	}
 	if email == "" {
-		return nil, "", auth.ErrInvalidEmail
+		return nil, nil, auth.ErrInvalidEmail
 	}
 	if password == "" {
-		return nil, "", auth.ErrWeakPassword
+		return nil, nil, auth.ErrWeakPassword
 	}
 
 	// Check if user already exists
 	_, err := s.userRepo.GetByEmail(ctx, email)
 	if err == nil {
-		return nil, "", auth.ErrEmailAlreadyExists
+		return nil, nil, auth.ErrEmailAlreadyExists
 	}
 	if err != user.ErrUserNotFound {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Create new user
 	u, err := user.NewUser(name, email, password)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Save user
 	err = s.userRepo.Create(ctx, u)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
+	}
+
+	// Generate tokens
+	tokens, err := s.issueTokens(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u, tokens, nil
+}
+
+// RefreshToken rotates a refresh token into a new token pair
+func (s *MockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	userID, ok := s.refreshTokens[refreshToken]
+	if !ok {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	delete(s.refreshTokens, refreshToken)
+
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	return s.issueTokens(ctx, u)
+}
+
+// Logout revokes a refresh token; access tokens are not tracked by the mock
+func (s *MockAuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	if _, ok := s.refreshTokens[refreshToken]; !ok {
+		return auth.ErrInvalidRefreshToken
+	}
+	delete(s.refreshTokens, refreshToken)
+	return nil
+}
+
+// LoginWithIdentity signs in the user whose email matches the external account
+func (s *MockAuthService) LoginWithIdentity(ctx context.Context, external user.ExternalIdentity) (*user.User, *auth.TokenPair, error) {
+	u, err := s.userRepo.GetByEmail(ctx, external.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(ctx, u)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Generate token
-	token, err := s.GenerateToken(ctx, u)
+	return u, tokens, nil
+}
+
+// LoginWithMagicLink is not supported by the mock; links are never sent
+func (s *MockAuthService) LoginWithMagicLink(ctx context.Context, token string, device user.Device) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, user.ErrInvalidMagicLink
+}
+
+// LoginWithSSO is not supported by the mock; no organization has a connection
+func (s *MockAuthService) LoginWithSSO(ctx context.Context, organization, state string, response sso.Response) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, sso.ErrConnectionNotFound
+}
+
+// LoginTwoFactor is not supported by the mock; no user has two-factor authentication
+func (s *MockAuthService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, auth.ErrInvalidToken
+}
+
+// BeginPasskeyTwoFactor is not supported by the mock; no user has passkeys
+func (s *MockAuthService) BeginPasskeyTwoFactor(ctx context.Context, challengeToken string) (*auth.RequestOptions, error) {
+	return nil, auth.ErrInvalidToken
+}
+
+// LoginTwoFactorWithPasskey is not supported by the mock; no user has passkeys
+func (s *MockAuthService) LoginTwoFactorWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, auth.ErrInvalidPasskeyChallenge
+}
+
+// SignupWithPasskey is not supported by the mock; no challenge is issued
+func (s *MockAuthService) SignupWithPasskey(ctx context.Context, credential *auth.AttestationResponse) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, auth.ErrInvalidPasskeyChallenge
+}
+
+// LoginWithPasskey is not supported by the mock; no challenge is issued
+func (s *MockAuthService) LoginWithPasskey(ctx context.Context, credential *auth.AssertionResponse) (*user.User, *auth.TokenPair, error) {
+	return nil, nil, auth.ErrInvalidPasskeyChallenge
+}
+
+// EnableTwoFactor starts an enrollment without storing it
+func (s *MockAuthService) EnableTwoFactor(ctx context.Context, userID int) (*auth.TwoFactorEnrollment, error) {
+	t, err := auth.NewTwoFactor(userID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
+	return &auth.TwoFactorEnrollment{Secret: t.Secret, URI: t.ProvisioningURI("Test", "test@example.com")}, nil
+}
 
-	return u, token, nil
+// ConfirmTwoFactor fails as enrollments are not stored
+func (s *MockAuthService) ConfirmTwoFactor(ctx context.Context, userID int, code string) error {
+	return auth.ErrTwoFactorNotFound
 }
 
-// RefreshToken generates a new token from an existing token
-func (s *MockAuthService) RefreshToken(ctx context.Context, token string) (string, error) {
-	return s.tokenService.RefreshToken(token)
+// DisableTwoFactor fails as no user has two-factor authentication
+func (s *MockAuthService) DisableTwoFactor(ctx context.Context, userID int, code string) error {
+	return auth.ErrTwoFactorNotEnabled
+}
+
+// issueTokens creates an access token and a refresh token for a user
+func (s *MockAuthService) issueTokens(ctx context.Context, u *user.User) (*auth.TokenPair, error) {
+	accessToken, err := s.GenerateToken(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	_, refreshToken, err := auth.NewRefreshToken(u.ID, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshTokens[refreshToken] = u.ID
+
+	return &auth.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: 15 * time.Minute}, nil
 }
 
 func TestAuthService_Login(t *testing.T) {
@@ -260,7 +381,7 @@ func TestAuthService_Login(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u, token, err := authService.Login(ctx, tt.email, tt.password)
+			u, tokens, err := authService.Login(ctx, tt.email, tt.password)
 
 			if tt.expectError {
 				if err == nil {
@@ -283,8 +404,8 @@ func TestAuthService_Login(t *testing.T) {
 				return
 			}
 
-			if token == "" {
-				t.Error("expected token to be generated, got empty string")
+			if tokens == nil || tokens.AccessToken == "" || tokens.RefreshToken == "" {
+				t.Error("expected token pair to be generated, got empty tokens")
 			}
 
 			if u.Email != tt.email {
@@ -362,7 +483,7 @@ func TestAuthService_Register(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u, token, err := authService.Register(ctx, tt.userName, tt.email, tt.password)
+			u, tokens, err := authService.Register(ctx, tt.userName, tt.email, tt.password)
 
 			if tt.expectError {
 				if err == nil {
@@ -385,8 +506,8 @@ func TestAuthService_Register(t *testing.T) {
 				return
 			}
 
-			if token == "" {
-				t.Error("expected token to be generated, got empty string")
+			if tokens == nil || tokens.AccessToken == "" || tokens.RefreshToken == "" {
+				t.Error("expected token pair to be generated, got empty tokens")
 			}
 
 			if u.Name != tt.userName {
@@ -474,7 +595,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 	ctx := context.Background()
 
 	// Generate a valid token first
-	validToken, err := tokenService.GenerateToken(1, "test@example.com", 24*3600)
+	validToken, err := tokenService.GenerateToken(1, "test@example.com", "author", 24*3600)
 	if err != nil {
 		t.Fatalf("failed to generate test token: %v", err)
 	}
@@ -526,16 +647,26 @@ func TestAuthService_ValidateToken(t *testing.T) {
 }
 
 func TestAuthService_RefreshToken(t *testing.T) {
+	userRepo := NewMockUserRepository()
 	tokenService := NewMockTokenService("test-secret")
-	authService := NewMockAuthService(nil, tokenService)
+	authService := NewMockAuthService(userRepo, tokenService)
 	ctx := context.Background()
 
-	// Generate a valid token first
-	validToken, err := tokenService.GenerateToken(1, "test@example.com", 24*3600)
+	testUser, err := user.NewUser("John Doe", "john@example.com", "password123")
 	if err != nil {
-		t.Fatalf("failed to generate test token: %v", err)
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	if err := userRepo.Create(ctx, testUser); err != nil {
+		t.Fatalf("failed to save test user: %v", err)
 	}
 
+	// Log in to obtain a refresh token
+	_, tokens, err := authService.Login(ctx, "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("failed to log in: %v", err)
+	}
+	validToken := tokens.RefreshToken
+
 	tests := []struct {
 		name        string
 		token       string
@@ -546,6 +677,11 @@ func TestAuthService_RefreshToken(t *testing.T) {
 			token:       validToken,
 			expectError: false,
 		},
+		{
+			name:        "reusing a rotated token should fail",
+			token:       validToken,
+			expectError: true,
+		},
 		{
 			name:        "invalid token refresh should fail",
 			token:       "invalid_token",
@@ -560,7 +696,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			newToken, err := authService.RefreshToken(ctx, tt.token)
+			newTokens, err := authService.RefreshToken(ctx, tt.token)
 
 			if tt.expectError {
 				if err == nil {
@@ -575,9 +711,13 @@ func TestAuthService_RefreshToken(t *testing.T) {
 				return
 			}
 
-			if newToken == "" {
+			if newTokens.AccessToken == "" {
 				t.Error("expected new token to be generated, got empty string")
 			}
+
+			if newTokens.RefreshToken == tt.token {
+				t.Error("expected refresh token to be rotated")
+			}
 		})
 	}
 }
@@ -1,6 +1,8 @@
 package auth_test
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,7 +22,7 @@ func NewMockTokenService(secretKey string) *MockTokenService {
 }
 
 // GenerateToken creates a JWT token with the given claims
-func (m *MockTokenService) GenerateToken(userID int, email string, duration time.Duration) (string, error) {
+func (m *MockTokenService) GenerateToken(userID int, email, role string, duration time.Duration) (string, error) {
 	// For testing, we'll create a simple mock token
 	// In real implementation, this would use JWT library
 	if userID <= 0 {
@@ -75,7 +77,27 @@ func (m *MockTokenService) RefreshToken(token string) (string, error) {
 	}
 
 	// Generate new token with same user info but extended expiry
-	return m.GenerateToken(claims.UserID, claims.Email, 24*time.Hour)
+	return m.GenerateToken(claims.UserID, claims.Email, claims.Role, 24*time.Hour)
+}
+
+// GenerateChallengeToken creates a token for the second step of a two-factor login
+func (m *MockTokenService) GenerateChallengeToken(userID int, duration time.Duration) (string, error) {
+	if userID <= 0 {
+		return "", auth.ErrInvalidUserID
+	}
+	if duration <= 0 {
+		return "", auth.ErrInvalidDuration
+	}
+	return "mock_challenge_" + strconv.Itoa(userID), nil
+}
+
+// ValidateChallengeToken validates a challenge token and returns its user ID
+func (m *MockTokenService) ValidateChallengeToken(token string) (int, error) {
+	userID, err := strconv.Atoi(strings.TrimPrefix(token, "mock_challenge_"))
+	if err != nil || !strings.HasPrefix(token, "mock_challenge_") {
+		return 0, auth.ErrInvalidToken
+	}
+	return userID, nil
 }
 
 func TestTokenService_GenerateToken(t *testing.T) {
@@ -124,7 +146,7 @@ func TestTokenService_GenerateToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := service.GenerateToken(tt.userID, tt.email, tt.duration)
+			token, err := service.GenerateToken(tt.userID, tt.email, "author", tt.duration)
 
 			if tt.expectError {
 				if err == nil {
@@ -296,7 +318,7 @@ func TestTokenService_InvalidSecretKey(t *testing.T) {
 	service := NewMockTokenService("")
 
 	// Test that operations fail with invalid secret key
-	_, err := service.GenerateToken(1, "test@example.com", 24*time.Hour)
+	_, err := service.GenerateToken(1, "test@example.com", "author", 24*time.Hour)
 	if err != auth.ErrInvalidSecretKey {
 		t.Errorf("expected ErrInvalidSecretKey, got %v", err)
 	}
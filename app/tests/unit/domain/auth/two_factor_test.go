@@ -0,0 +1,85 @@
+package auth_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/auth"
+)
+
+// rfcSecret is the base32 form of the RFC 6238 SHA-1 test key "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; these are their last 6 digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := auth.TOTPCode(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestTwoFactor_Verify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := auth.TOTPCode(rfcSecret, now)
+	require.NoError(t, err)
+
+	t.Run("accepts the current code once", func(t *testing.T) {
+		tf := &auth.TwoFactor{UserID: 1, Secret: rfcSecret}
+
+		assert.True(t, tf.Verify(code, now))
+		assert.False(t, tf.Verify(code, now), "codes must not be replayed")
+	})
+
+	t.Run("tolerates one step of clock drift", func(t *testing.T) {
+		tf := &auth.TwoFactor{UserID: 1, Secret: rfcSecret}
+		assert.True(t, tf.Verify(code, now.Add(30*time.Second)))
+
+		tf = &auth.TwoFactor{UserID: 1, Secret: rfcSecret}
+		assert.False(t, tf.Verify(code, now.Add(90*time.Second)))
+	})
+
+	t.Run("rejects malformed codes", func(t *testing.T) {
+		tf := &auth.TwoFactor{UserID: 1, Secret: rfcSecret}
+		assert.False(t, tf.Verify("", now))
+		assert.False(t, tf.Verify(code+"0", now))
+		assert.False(t, tf.Verify("000000", now))
+	})
+}
+
+func TestNewTwoFactor(t *testing.T) {
+	tf, err := auth.NewTwoFactor(1)
+	require.NoError(t, err)
+	assert.Len(t, tf.Secret, 32)
+	assert.False(t, tf.IsEnabled())
+
+	other, err := auth.NewTwoFactor(1)
+	require.NoError(t, err)
+	assert.NotEqual(t, tf.Secret, other.Secret)
+
+	_, err = auth.NewTwoFactor(0)
+	assert.Equal(t, auth.ErrInvalidUserID, err)
+}
+
+func TestTwoFactor_ProvisioningURI(t *testing.T) {
+	tf := &auth.TwoFactor{UserID: 1, Secret: rfcSecret}
+
+	uri, err := url.Parse(tf.ProvisioningURI("Blog Platform", "john@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Blog Platform:john@example.com", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Blog Platform", uri.Query().Get("issuer"))
+}
@@ -0,0 +1,286 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/auth"
+)
+
+const testOrigin = "https://blog.example.com"
+
+var testRP = &auth.RelyingParty{ID: "blog.example.com", Name: "Blog", Origins: []string{testOrigin}}
+
+// ctap2 encodes CBOR the canonical way authenticators do
+var ctap2, _ = cbor.CTAP2EncOptions().EncMode()
+
+// encodeCBOR encodes a value with the canonical CTAP2 encoding
+func encodeCBOR(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := ctap2.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+// authenticator is a virtual ES256 authenticator
+type authenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	rpID         string
+	origin       string
+	flags        byte
+	signCount    uint32
+	// countless authenticators keep their signature counter at zero
+	countless bool
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &authenticator{
+		t:            t,
+		key:          key,
+		credentialID: []byte("credential-1"),
+		userHandle:   []byte("user-handle"),
+		rpID:         testRP.ID,
+		origin:       testOrigin,
+		flags:        0x01 | 0x04,
+	}
+}
+
+// coseKey encodes the public key as a COSE_Key
+func (a *authenticator) coseKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.PublicKey.X.FillBytes(x)
+	a.key.PublicKey.Y.FillBytes(y)
+	return encodeCBOR(a.t, map[int]any{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+}
+
+func (a *authenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	flags := a.flags
+	if attested {
+		flags |= 0x40
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+func (a *authenticator) clientData(ceremonyType, challenge string) []byte {
+	data, err := json.Marshal(map[string]string{"type": ceremonyType, "challenge": challenge, "origin": a.origin})
+	require.NoError(a.t, err)
+	return data
+}
+
+// create answers a registration challenge
+func (a *authenticator) create(challenge string) *auth.AttestationResponse {
+	r := &auth.AttestationResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	r.Response.ClientDataJSON = a.clientData("webauthn.create", challenge)
+	r.Response.AttestationObject = encodeCBOR(a.t, map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(true),
+	})
+	return r
+}
+
+// get answers a login challenge
+func (a *authenticator) get(challenge string) *auth.AssertionResponse {
+	if !a.countless {
+		a.signCount++
+	}
+	r := &auth.AssertionResponse{ID: base64.RawURLEncoding.EncodeToString(a.credentialID), RawID: a.credentialID, Type: "public-key"}
+	r.Response.ClientDataJSON = a.clientData("webauthn.get", challenge)
+	r.Response.AuthenticatorData = a.authData(false)
+	r.Response.UserHandle = a.userHandle
+
+	clientDataHash := sha256.Sum256(r.Response.ClientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, r.Response.AuthenticatorData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+	r.Response.Signature = signature
+	return r
+}
+
+// register registers the authenticator and returns its passkey
+func (a *authenticator) register() *auth.Passkey {
+	credential, err := testRP.VerifyAttestation(a.create("registration"), "registration", true)
+	require.NoError(a.t, err)
+	passkey, err := auth.NewPasskey(1, a.userHandle, credential, "Laptop")
+	require.NoError(a.t, err)
+	return passkey
+}
+
+func TestBase64URL_JSON(t *testing.T) {
+	data, err := json.Marshal(auth.Base64URL{0xfb, 0xff})
+	require.NoError(t, err)
+	assert.Equal(t, `"-_8"`, string(data))
+
+	var decoded auth.Base64URL
+	require.NoError(t, json.Unmarshal([]byte(`"-_8="`), &decoded))
+	assert.Equal(t, auth.Base64URL{0xfb, 0xff}, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`"not base64!"`), &decoded))
+}
+
+func TestRelyingParty_CreationOptions(t *testing.T) {
+	existing := &auth.Passkey{CredentialID: []byte("old")}
+	options := testRP.CreationOptions([]byte("challenge"), []byte("handle"), "jane@example.com", "Jane", []*auth.Passkey{existing}, 5*time.Minute)
+
+	assert.Equal(t, "blog.example.com", options.RP.ID)
+	assert.Equal(t, "jane@example.com", options.User.Name)
+	assert.Equal(t, int64(300000), options.Timeout)
+	assert.Equal(t, "required", options.AuthenticatorSelection.ResidentKey)
+	assert.Equal(t, "required", options.AuthenticatorSelection.UserVerification)
+	require.Len(t, options.ExcludeCredentials, 1)
+	assert.Equal(t, auth.Base64URL("old"), options.ExcludeCredentials[0].ID)
+}
+
+func TestRelyingParty_VerifyAttestation(t *testing.T) {
+	t.Run("returns the new credential", func(t *testing.T) {
+		a := newAuthenticator(t)
+		response := a.create("challenge")
+
+		challenge, err := response.Challenge()
+		require.NoError(t, err)
+		assert.Equal(t, "challenge", challenge)
+
+		credential, err := testRP.VerifyAttestation(response, "challenge", true)
+		require.NoError(t, err)
+		assert.Equal(t, a.credentialID, credential.ID)
+		assert.Equal(t, a.coseKey(), credential.PublicKey)
+	})
+
+	t.Run("rejects another challenge", func(t *testing.T) {
+		a := newAuthenticator(t)
+		_, err := testRP.VerifyAttestation(a.create("challenge"), "other", true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyRegistration)
+	})
+
+	t.Run("rejects another origin", func(t *testing.T) {
+		a := newAuthenticator(t)
+		a.origin = "https://evil.example.com"
+		_, err := testRP.VerifyAttestation(a.create("challenge"), "challenge", true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyRegistration)
+	})
+
+	t.Run("rejects another relying party", func(t *testing.T) {
+		a := newAuthenticator(t)
+		a.rpID = "evil.example.com"
+		_, err := testRP.VerifyAttestation(a.create("challenge"), "challenge", true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyRegistration)
+	})
+
+	t.Run("requires user verification", func(t *testing.T) {
+		a := newAuthenticator(t)
+		a.flags = 0x01
+		_, err := testRP.VerifyAttestation(a.create("challenge"), "challenge", true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyRegistration)
+
+		_, err = testRP.VerifyAttestation(a.create("challenge"), "challenge", false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects malformed attestation objects", func(t *testing.T) {
+		a := newAuthenticator(t)
+		response := a.create("challenge")
+		response.Response.AttestationObject = response.Response.AttestationObject[:20]
+		_, err := testRP.VerifyAttestation(response, "challenge", true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyRegistration)
+	})
+}
+
+func TestRelyingParty_VerifyAssertion(t *testing.T) {
+	t.Run("returns the new signature counter", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+
+		signCount, err := testRP.VerifyAssertion(a.get("login"), "login", passkey, true)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), signCount)
+	})
+
+	t.Run("rejects signatures of another key", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+
+		other := newAuthenticator(t)
+		_, err := testRP.VerifyAssertion(other.get("login"), "login", passkey, true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+	})
+
+	t.Run("rejects tampered authenticator data", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+
+		response := a.get("login")
+		response.Response.AuthenticatorData[36]++
+		_, err := testRP.VerifyAssertion(response, "login", passkey, true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+	})
+
+	t.Run("rejects another user handle", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+
+		response := a.get("login")
+		response.Response.UserHandle = []byte("someone-else")
+		_, err := testRP.VerifyAssertion(response, "login", passkey, true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+	})
+
+	t.Run("rejects counters that did not increase", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+		passkey.SignCount = 5
+
+		_, err := testRP.VerifyAssertion(a.get("login"), "login", passkey, true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+	})
+
+	t.Run("accepts authenticators that do not count", func(t *testing.T) {
+		a := newAuthenticator(t)
+		a.countless = true
+		passkey := a.register()
+
+		for i := 0; i < 2; i++ {
+			signCount, err := testRP.VerifyAssertion(a.get("login"), "login", passkey, true)
+			require.NoError(t, err)
+			assert.Zero(t, signCount)
+		}
+	})
+
+	t.Run("verifies users only when required", func(t *testing.T) {
+		a := newAuthenticator(t)
+		passkey := a.register()
+		a.flags = 0x01
+
+		_, err := testRP.VerifyAssertion(a.get("login"), "login", passkey, true)
+		assert.ErrorIs(t, err, auth.ErrInvalidPasskeyAssertion)
+
+		_, err = testRP.VerifyAssertion(a.get("login"), "login", passkey, false)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,64 @@
+package banner_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/banner"
+)
+
+func TestNewBanner(t *testing.T) {
+	b, err := banner.NewBanner(1, banner.Input{Message: "  Maintenance tonight ", Severity: banner.SeverityWarning, Dismissible: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if b.Message != "Maintenance tonight" {
+		t.Errorf("expected a trimmed message, got %q", b.Message)
+	}
+	if !b.IsActive(time.Now()) {
+		t.Error("expected banners without a start time to start right away")
+	}
+	if b.EndsAt != nil {
+		t.Errorf("expected no end time, got %v", b.EndsAt)
+	}
+}
+
+func TestNewBanner_Validation(t *testing.T) {
+	start := time.Now()
+	cases := map[string]struct {
+		in   banner.Input
+		want error
+	}{
+		"empty message":    {banner.Input{Message: " ", Severity: banner.SeverityInfo}, banner.ErrInvalidMessage},
+		"long message":     {banner.Input{Message: strings.Repeat("a", 501), Severity: banner.SeverityInfo}, banner.ErrInvalidMessage},
+		"unknown severity": {banner.Input{Message: "Hello", Severity: "loud"}, banner.ErrInvalidSeverity},
+		"ends at start":    {banner.Input{Message: "Hello", Severity: banner.SeverityInfo, StartsAt: &start, EndsAt: &start}, banner.ErrInvalidSchedule},
+	}
+	for name, tc := range cases {
+		if _, err := banner.NewBanner(1, tc.in); err != tc.want {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestBanner_IsActive(t *testing.T) {
+	start := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	b, err := banner.NewBanner(1, banner.Input{Message: "Maintenance", Severity: banner.SeverityWarning, StartsAt: &start, EndsAt: &end})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cases := map[time.Time]bool{
+		start.Add(-time.Minute): false,
+		start:                   true,
+		start.Add(time.Hour):    true,
+		end:                     false,
+	}
+	for now, want := range cases {
+		if got := b.IsActive(now); got != want {
+			t.Errorf("IsActive(%v) = %v, want %v", now, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package billing_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/billing"
+)
+
+func TestSubscription_EffectivePlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription *billing.Subscription
+		expected     billing.Plan
+	}{
+		{"no subscription", nil, billing.PlanFree},
+		{"active", &billing.Subscription{Plan: billing.PlanPro, Status: billing.StatusActive}, billing.PlanPro},
+		{"trialing", &billing.Subscription{Plan: billing.PlanOrg, Status: billing.StatusTrialing}, billing.PlanOrg},
+		{"past due", &billing.Subscription{Plan: billing.PlanPro, Status: billing.StatusPastDue}, billing.PlanPro},
+		{"canceled", &billing.Subscription{Plan: billing.PlanPro, Status: billing.StatusCanceled}, billing.PlanFree},
+		{"unpaid", &billing.Subscription{Plan: billing.PlanOrg, Status: billing.StatusUnpaid}, billing.PlanFree},
+		{"incomplete", &billing.Subscription{Plan: billing.PlanPro, Status: billing.StatusIncomplete}, billing.PlanFree},
+		{"unknown plan", &billing.Subscription{Plan: "enterprise", Status: billing.StatusActive}, billing.PlanFree},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if plan := tt.subscription.EffectivePlan(); plan != tt.expected {
+				t.Errorf("expected plan %q, got %q", tt.expected, plan)
+			}
+		})
+	}
+}
+
+func TestLimitsOf(t *testing.T) {
+	free := billing.LimitsOf(billing.PlanFree)
+	if free.MaxPosts == 0 || free.CustomDomains {
+		t.Errorf("expected the free plan to limit posts and exclude custom domains, got %+v", free)
+	}
+
+	pro := billing.LimitsOf(billing.PlanPro)
+	if pro.MaxPosts != 0 || !pro.CustomDomains || pro.UploadQuota <= free.UploadQuota {
+		t.Errorf("expected the pro plan to lift the free limits, got %+v", pro)
+	}
+
+	if org := billing.LimitsOf(billing.PlanOrg); org.UploadQuota <= pro.UploadQuota {
+		t.Errorf("expected the org plan to store more than the pro plan, got %+v", org)
+	}
+
+	if unknown := billing.LimitsOf("enterprise"); unknown != free {
+		t.Errorf("expected unknown plans to get the free limits, got %+v", unknown)
+	}
+}
@@ -0,0 +1,56 @@
+package change_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/change"
+)
+
+func TestCheckpoint_EncodeDecode(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC)
+	checkpoint := change.Checkpoint{
+		Posts:     change.Position{At: at, ID: 4},
+		Comments:  change.Position{At: at.Add(time.Hour), ID: 12},
+		Deletions: change.Position{At: at.Add(2 * time.Hour), ID: 0},
+	}
+
+	got, err := change.DecodeCheckpoint(checkpoint.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCheckpoint() error = %v", err)
+	}
+	if got != checkpoint {
+		t.Errorf("DecodeCheckpoint() = %+v, want %+v", got, checkpoint)
+	}
+
+	start, err := change.DecodeCheckpoint(change.Start().Encode())
+	if err != nil {
+		t.Fatalf("DecodeCheckpoint() error = %v", err)
+	}
+	if start != change.Start() {
+		t.Errorf("DecodeCheckpoint() = %+v, want the start", start)
+	}
+}
+
+func TestDecodeCheckpoint_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not base64", input: "not a checkpoint!"},
+		{name: "too few positions", input: base64.RawURLEncoding.EncodeToString([]byte("1:1,2:2"))},
+		{name: "missing id", input: base64.RawURLEncoding.EncodeToString([]byte("1:1,2,3:3"))},
+		{name: "bad time", input: base64.RawURLEncoding.EncodeToString([]byte("x:1,2:2,3:3"))},
+		{name: "negative id", input: base64.RawURLEncoding.EncodeToString([]byte("1:1,2:-2,3:3"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := change.DecodeCheckpoint(tt.input); !errors.Is(err, change.ErrInvalidCheckpoint) {
+				t.Errorf("DecodeCheckpoint(%q) error = %v, want ErrInvalidCheckpoint", tt.input, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package clientid_test
+
+import (
+	"errors"
+	"testing"
+
+	"blog-platform/internal/domain/clientid"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := clientid.Normalize("123E4567-E89B-12D3-A456-426614174000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("expected the client ID lowercased, got %q", got)
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"42",
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400",
+		"123e4567-e89b-12d3-a456-4266141740000",
+		"123e4567_e89b-12d3-a456-426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+	} {
+		if _, err := clientid.Normalize(s); !errors.Is(err, clientid.ErrInvalid) {
+			t.Errorf("expected ErrInvalid for %q, got %v", s, err)
+		}
+	}
+}
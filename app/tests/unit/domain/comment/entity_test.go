@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/user"
 )
 
 func TestNewComment(t *testing.T) {
@@ -231,6 +232,35 @@ func TestComment_IsAuthor(t *testing.T) {
 	}
 }
 
+func TestComment_CanModify(t *testing.T) {
+	signedIn, _ := comment.NewComment(1, "John Doe", "Test comment content")
+	signedIn.PostedBy(1)
+	guest, _ := comment.NewComment(1, "John Doe", "Test comment content")
+	guest.PostedBy(0)
+
+	tests := []struct {
+		name     string
+		comment  *comment.Comment
+		userID   int
+		role     user.Role
+		expected bool
+	}{
+		{"registered author", signedIn, 1, user.RoleReader, true},
+		{"other user", signedIn, 2, user.RoleAuthor, false},
+		{"admin", signedIn, 2, user.RoleAdmin, true},
+		{"guest comment", guest, 1, user.RoleAuthor, false},
+		{"guest comment by admin", guest, 2, user.RoleAdmin, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comment.CanModify(tt.userID, tt.role); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestComment_Validation(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,51 @@
+package comment_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/comment"
+)
+
+func TestNewReport(t *testing.T) {
+	r, err := comment.NewReport(1, 7, "192.0.2.1", comment.ReasonSpam, "  Links to a shop ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if r.ReporterID == nil || *r.ReporterID != 7 {
+		t.Errorf("expected reporter 7, got %v", r.ReporterID)
+	}
+	if r.ReporterKey != "user:7" {
+		t.Errorf("expected users to be keyed by ID, got %q", r.ReporterKey)
+	}
+	if r.Details != "Links to a shop" {
+		t.Errorf("expected trimmed details, got %q", r.Details)
+	}
+
+	guest, err := comment.NewReport(1, 0, "192.0.2.1", comment.ReasonAbuse, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if guest.ReporterID != nil {
+		t.Errorf("expected no reporter for guests, got %v", *guest.ReporterID)
+	}
+	if !strings.HasPrefix(guest.ReporterKey, "ip:") || strings.Contains(guest.ReporterKey, "192.0.2.1") {
+		t.Errorf("expected guests to be keyed by a hash of their address, got %q", guest.ReporterKey)
+	}
+	other, _ := comment.NewReport(1, 0, "192.0.2.2", comment.ReasonAbuse, "")
+	if other.ReporterKey == guest.ReporterKey {
+		t.Error("expected guests from other addresses to have other keys")
+	}
+}
+
+func TestNewReport_Validation(t *testing.T) {
+	if _, err := comment.NewReport(0, 7, "", comment.ReasonSpam, ""); err == nil {
+		t.Error("expected an error for an invalid comment ID")
+	}
+	if _, err := comment.NewReport(1, 7, "", "boring", ""); err != comment.ErrInvalidReportReason {
+		t.Errorf("expected ErrInvalidReportReason, got %v", err)
+	}
+	if _, err := comment.NewReport(1, 7, "", comment.ReasonOther, strings.Repeat("a", comment.MaxReportDetailsLength+1)); err != comment.ErrReportDetailsTooLong {
+		t.Errorf("expected ErrReportDetailsTooLong, got %v", err)
+	}
+}
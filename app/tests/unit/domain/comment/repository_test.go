@@ -2,9 +2,13 @@ package comment_test
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"testing"
+	"time"
 
 	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/keyset"
 )
 
 // MockCommentRepository implements the comment.Repository interface for testing
@@ -38,6 +42,16 @@ func (m *MockCommentRepository) GetByID(ctx context.Context, id int) (*comment.C
 	return c, nil
 }
 
+// GetByClientID retrieves a comment by the client ID it was created with
+func (m *MockCommentRepository) GetByClientID(ctx context.Context, clientID string) (*comment.Comment, error) {
+	for _, c := range m.comments {
+		if c.ClientID != nil && *c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return nil, comment.ErrCommentNotFound
+}
+
 // GetByPostID retrieves comments for a specific post with pagination
 func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
 	var result []*comment.Comment
@@ -58,6 +72,92 @@ func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID int, lim
 	return result, nil
 }
 
+// GetByPostIDAfter retrieves the comments of a post after the cursor, oldest first
+func (m *MockCommentRepository) GetByPostIDAfter(ctx context.Context, postID int, after keyset.Cursor, limit int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.PostID != postID {
+			continue
+		}
+		if after.IsZero() || c.CreatedAt.After(after.CreatedAt) || (c.CreatedAt.Equal(after.CreatedAt) && c.ID > after.ID) {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result[:min(limit, len(result))], nil
+}
+
+// GetRootsByPostID retrieves the top-level comments of a post, oldest first
+func (m *MockCommentRepository) GetRootsByPostID(ctx context.Context, postID int, limit, offset int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.PostID == postID && c.ParentID == nil {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	return result[:min(limit, len(result))], nil
+}
+
+// GetByRootIDs retrieves the replies in the threads of top-level comments,
+// oldest first
+func (m *MockCommentRepository) GetByRootIDs(ctx context.Context, rootIDs []int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.RootID != nil && slices.Contains(rootIDs, *c.RootID) {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// ListRecent retrieves the newest comments of a post. Post authors are not
+// known to the mock, so PostAuthorID is ignored.
+func (m *MockCommentRepository) ListRecent(ctx context.Context, filter comment.RecentFilter, limit int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if filter.PostID == 0 || c.PostID == filter.PostID {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// ListByStatus retrieves the comments with a moderation status, oldest first
+func (m *MockCommentRepository) ListByStatus(ctx context.Context, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range m.comments {
+		if c.Status == status {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	return result[:min(limit, len(result))], nil
+}
+
 // Update modifies an existing comment
 func (m *MockCommentRepository) Update(ctx context.Context, c *comment.Comment) error {
 	if _, exists := m.comments[c.ID]; !exists {
@@ -76,6 +176,44 @@ func (m *MockCommentRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// DeleteByPostID removes the comments of a post
+func (m *MockCommentRepository) DeleteByPostID(ctx context.Context, postID int) (int, error) {
+	deleted := 0
+	for id, c := range m.comments {
+		if c.PostID == postID {
+			delete(m.comments, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// LastCommentAt returns when a user last commented
+func (m *MockCommentRepository) LastCommentAt(ctx context.Context, userID int) (time.Time, error) {
+	var last time.Time
+	for _, c := range m.comments {
+		if c.IsPostedBy(userID) && c.CreatedAt.After(last) {
+			last = c.CreatedAt
+		}
+	}
+	return last, nil
+}
+
+// GetTrustLevel returns new, trust levels are not stored by the mock
+func (m *MockCommentRepository) GetTrustLevel(ctx context.Context, userID int) (comment.TrustLevel, error) {
+	return comment.TrustNew, nil
+}
+
+// SetTrustLevel does nothing, trust levels are not stored by the mock
+func (m *MockCommentRepository) SetTrustLevel(ctx context.Context, userID int, level comment.TrustLevel) error {
+	return nil
+}
+
+// ListCommenterHistories returns no histories
+func (m *MockCommentRepository) ListCommenterHistories(ctx context.Context, flaggedSince time.Time) ([]*comment.CommenterHistory, error) {
+	return nil, nil
+}
+
 func TestCommentRepository_Create(t *testing.T) {
 	repo := NewMockCommentRepository()
 	ctx := context.Background()
@@ -0,0 +1,48 @@
+package comment_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/comment"
+)
+
+func TestStatus_IsValid(t *testing.T) {
+	for _, status := range []comment.Status{comment.StatusPending, comment.StatusApproved, comment.StatusRejected, comment.StatusSpam} {
+		if !status.IsValid() {
+			t.Errorf("expected %q to be valid", status)
+		}
+	}
+	for _, status := range []comment.Status{"", "hidden", "Approved"} {
+		if status.IsValid() {
+			t.Errorf("expected %q to be invalid", status)
+		}
+	}
+}
+
+func TestComment_Moderate(t *testing.T) {
+	c, err := comment.NewComment(1, "John Doe", "A comment on the post")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	if !c.IsApproved() {
+		t.Errorf("expected new comments to be approved, got %q", c.Status)
+	}
+
+	if err := c.Moderate(comment.StatusSpam); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.IsApproved() || c.Status != comment.StatusSpam {
+		t.Errorf("expected the comment to be spam, got %q", c.Status)
+	}
+
+	// Retried decisions succeed
+	if err := c.Moderate(comment.StatusSpam); err != nil {
+		t.Errorf("expected moderating to the same status to succeed, got %v", err)
+	}
+	if err := c.Moderate("hidden"); err != comment.ErrInvalidStatus {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+	if c.Status != comment.StatusSpam {
+		t.Errorf("expected an invalid status to be ignored, got %q", c.Status)
+	}
+}
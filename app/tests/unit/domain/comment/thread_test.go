@@ -0,0 +1,81 @@
+package comment_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/comment"
+)
+
+func newReply(t *testing.T, id int, parent *comment.Comment) *comment.Comment {
+	t.Helper()
+
+	c, err := comment.NewComment(parent.PostID, "Author", "A reply to the comment")
+	if err != nil {
+		t.Fatalf("failed to create comment: %v", err)
+	}
+	if err := c.ReplyTo(parent); err != nil {
+		t.Fatalf("failed to reply: %v", err)
+	}
+	c.ID = id
+	return c
+}
+
+func TestComment_ReplyTo(t *testing.T) {
+	root := &comment.Comment{ID: 1, PostID: 1}
+
+	reply := newReply(t, 2, root)
+	if reply.ParentID == nil || *reply.ParentID != 1 || reply.RootID == nil || *reply.RootID != 1 || reply.Depth != 1 {
+		t.Fatalf("expected a reply to comment 1 at depth 1, got %+v", reply)
+	}
+	if !reply.IsReply() || root.IsReply() {
+		t.Error("expected only the reply to be a reply")
+	}
+
+	nested := newReply(t, 3, reply)
+	if *nested.ParentID != 2 || *nested.RootID != 1 || nested.Depth != 2 {
+		t.Errorf("expected a reply to comment 2 in thread 1 at depth 2, got %+v", nested)
+	}
+
+	deepest := newReply(t, 4, nested)
+	if deepest.Depth != comment.MaxDepth {
+		t.Fatalf("expected depth %d, got %d", comment.MaxDepth, deepest.Depth)
+	}
+	tooDeep, _ := comment.NewComment(1, "Author", "A reply nested too deep")
+	if err := tooDeep.ReplyTo(deepest); err != comment.ErrThreadTooDeep {
+		t.Errorf("expected ErrThreadTooDeep, got %v", err)
+	}
+
+	otherPost, _ := comment.NewComment(2, "Author", "A reply on another post")
+	if err := otherPost.ReplyTo(root); err != comment.ErrParentOtherPost {
+		t.Errorf("expected ErrParentOtherPost, got %v", err)
+	}
+	if otherPost.ParentID != nil {
+		t.Error("expected a refused reply to stay top-level")
+	}
+}
+
+func TestBuildThreads(t *testing.T) {
+	first := &comment.Comment{ID: 1, PostID: 1}
+	second := &comment.Comment{ID: 2, PostID: 1}
+	reply := newReply(t, 3, first)
+	nested := newReply(t, 4, reply)
+	laterReply := newReply(t, 5, first)
+	orphan := &comment.Comment{ID: 6, PostID: 1, ParentID: new(int)}
+
+	threads := comment.BuildThreads([]*comment.Comment{first, second}, []*comment.Comment{reply, nested, laterReply, orphan})
+
+	if len(threads) != 2 || threads[0].Comment != first || threads[1].Comment != second {
+		t.Fatalf("expected threads for comments 1 and 2, got %v", threads)
+	}
+	if threads[0].ReplyCount() != 3 || threads[1].ReplyCount() != 0 {
+		t.Errorf("expected 3 and 0 replies, got %d and %d", threads[0].ReplyCount(), threads[1].ReplyCount())
+	}
+
+	replies := threads[0].Replies
+	if len(replies) != 2 || replies[0].Comment != reply || replies[1].Comment != laterReply {
+		t.Fatalf("expected replies 3 and 5 in order, got %v", replies)
+	}
+	if len(replies[0].Replies) != 1 || replies[0].Replies[0].Comment != nested {
+		t.Errorf("expected reply 4 nested under reply 3, got %v", replies[0].Replies)
+	}
+}
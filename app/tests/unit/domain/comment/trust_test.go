@@ -0,0 +1,90 @@
+package comment_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/comment"
+	"blog-platform/internal/domain/user"
+)
+
+func TestCountLinks(t *testing.T) {
+	cases := map[string]int{
+		"no links here":                           0,
+		"see https://example.com":                 1,
+		"HTTP://a.example and www.b.example":      2,
+		"http://a.example, https://b.example/x?y": 2,
+		"email me at someone@example.com":         0,
+	}
+	for content, want := range cases {
+		if got := comment.CountLinks(content); got != want {
+			t.Errorf("CountLinks(%q) = %d, want %d", content, got, want)
+		}
+	}
+}
+
+func TestTrustLimits_CheckContent(t *testing.T) {
+	noLinks := comment.TrustLimits{MaxLinks: -1, MaxLength: 10}
+	if err := noLinks.CheckContent("short"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := noLinks.CheckContent("www.a.b"); err != comment.ErrTooManyLinks {
+		t.Errorf("expected ErrTooManyLinks, got %v", err)
+	}
+	if err := noLinks.CheckContent(strings.Repeat("é", 11)); err == nil || !strings.Contains(err.Error(), "cannot exceed 10 characters") {
+		t.Errorf("expected a length error, got %v", err)
+	}
+
+	unlimited := comment.TrustLimits{}
+	if err := unlimited.CheckContent(strings.Repeat("https://a.example ", 50)); err != nil {
+		t.Errorf("expected zero limits not to limit, got %v", err)
+	}
+}
+
+func TestTrustLimits_CheckCooldown(t *testing.T) {
+	limits := comment.TrustLimits{Cooldown: time.Minute}
+	now := time.Now()
+
+	if err := limits.CheckCooldown(time.Time{}, now); err != nil {
+		t.Errorf("expected first comments to pass, got %v", err)
+	}
+	if err := limits.CheckCooldown(now.Add(-30*time.Second), now); err != comment.ErrCommentCooldown {
+		t.Errorf("expected ErrCommentCooldown, got %v", err)
+	}
+	if err := limits.CheckCooldown(now.Add(-2*time.Minute), now); err != nil {
+		t.Errorf("expected no error after the cooldown, got %v", err)
+	}
+}
+
+func TestTrustPolicy_Evaluate(t *testing.T) {
+	policy := comment.DefaultTrustPolicy()
+	now := time.Now()
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	cases := []struct {
+		name    string
+		history comment.CommenterHistory
+		want    comment.TrustLevel
+	}{
+		{"fresh account", comment.CommenterHistory{AccountCreatedAt: days(1), Approved: 50}, comment.TrustNew},
+		{"few approved comments", comment.CommenterHistory{AccountCreatedAt: days(10), Approved: 2}, comment.TrustNew},
+		{"basic", comment.CommenterHistory{AccountCreatedAt: days(10), Approved: 3}, comment.TrustBasic},
+		{"trusted", comment.CommenterHistory{AccountCreatedAt: days(40), Approved: 20}, comment.TrustTrusted},
+		{"flagged once", comment.CommenterHistory{AccountCreatedAt: days(40), Approved: 20, Flagged: 1}, comment.TrustBasic},
+		{"flagged too often", comment.CommenterHistory{AccountCreatedAt: days(400), Approved: 200, Flagged: 2}, comment.TrustNew},
+		{"admin", comment.CommenterHistory{Role: user.RoleAdmin, AccountCreatedAt: now}, comment.TrustTrusted},
+	}
+	for _, tc := range cases {
+		if got := policy.Evaluate(tc.history, now); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestTrustPolicy_LimitsFor(t *testing.T) {
+	policy := comment.DefaultTrustPolicy()
+	if policy.LimitsFor("unknown") != policy.LimitsFor(comment.TrustNew) {
+		t.Error("expected unknown levels to get the limits of new commenters")
+	}
+}
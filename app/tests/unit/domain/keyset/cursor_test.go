@@ -0,0 +1,46 @@
+package keyset_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/keyset"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := keyset.After(time.Date(2030, 1, 15, 9, 30, 15, 0, time.UTC), 42)
+
+	encoded := cursor.Encode()
+	if encoded == "" {
+		t.Fatal("expected an encoded cursor")
+	}
+	decoded, err := keyset.Decode(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.ID != 42 || !decoded.CreatedAt.Equal(cursor.CreatedAt) {
+		t.Errorf("expected %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestCursor_Zero(t *testing.T) {
+	if encoded := (keyset.Cursor{}).Encode(); encoded != "" {
+		t.Errorf("expected the zero cursor to encode as empty, got %q", encoded)
+	}
+	cursor, err := keyset.Decode("")
+	if err != nil || !cursor.IsZero() {
+		t.Errorf("expected the zero cursor, got %+v, %v", cursor, err)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	for _, s := range []string{"not base64!", encode("1700000000"), encode("soon:1"), encode("1700000000:0"), encode("1700000000:x")} {
+		if _, err := keyset.Decode(s); err != keyset.ErrInvalidCursor {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", s, err)
+		}
+	}
+}
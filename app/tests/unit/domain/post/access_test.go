@@ -0,0 +1,89 @@
+package post_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+func TestAccess_IsValid(t *testing.T) {
+	for _, access := range []post.Access{post.AccessPublic, post.AccessMembers, post.AccessSubscribers} {
+		if !access.IsValid() {
+			t.Errorf("expected %q to be valid", access)
+		}
+	}
+	for _, access := range []post.Access{"", "private", "Members"} {
+		if access.IsValid() {
+			t.Errorf("expected %q to be invalid", access)
+		}
+	}
+}
+
+func TestPost_IsReadableBy(t *testing.T) {
+	anonymous := post.Reader{}
+	member := post.Reader{UserID: 2, Role: user.RoleReader}
+	subscriber := post.Reader{UserID: 3, Role: user.RoleReader, Subscriber: true}
+	author := post.Reader{UserID: 1, Role: user.RoleAuthor}
+	admin := post.Reader{UserID: 4, Role: user.RoleAdmin}
+
+	tests := []struct {
+		access post.Access
+		reader post.Reader
+		want   bool
+	}{
+		{post.AccessPublic, anonymous, true},
+		{"", anonymous, true},
+		{post.AccessMembers, anonymous, false},
+		{post.AccessMembers, member, true},
+		{post.AccessSubscribers, anonymous, false},
+		{post.AccessSubscribers, post.Reader{Subscriber: true}, false},
+		{post.AccessSubscribers, member, false},
+		{post.AccessSubscribers, subscriber, true},
+		{post.AccessSubscribers, author, true},
+		{post.AccessSubscribers, admin, true},
+	}
+	for _, tt := range tests {
+		p := &post.Post{AuthorID: 1, Access: tt.access}
+		if got := p.IsReadableBy(tt.reader); got != tt.want {
+			t.Errorf("access %q, reader %+v: expected %v, got %v", tt.access, tt.reader, tt.want, got)
+		}
+	}
+}
+
+func TestPost_Teaser(t *testing.T) {
+	p := &post.Post{ID: 1, Title: "Members only", Access: post.AccessMembers, Content: "The opening paragraph.\n\nThe rest of the post."}
+
+	teaser := p.Teaser()
+	if teaser.Content != "The opening paragraph." {
+		t.Errorf("expected the first paragraph, got %q", teaser.Content)
+	}
+	if !teaser.Locked || teaser.ID != p.ID || teaser.Title != p.Title {
+		t.Errorf("expected a locked copy of the post, got %+v", teaser)
+	}
+	if p.Locked || !strings.Contains(p.Content, "The rest") {
+		t.Error("expected the post itself to be left untouched")
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := post.Excerpt("  Short content.  "); got != "Short content." {
+		t.Errorf("expected short content to be kept, got %q", got)
+	}
+
+	long := strings.Repeat("wörd ", 100)
+	got := post.Excerpt(long)
+	if !strings.HasSuffix(got, "wörd…") {
+		t.Errorf("expected the excerpt to be cut at a word boundary, got %q", got)
+	}
+	if n := utf8.RuneCountInString(got); n > post.ExcerptLength+1 {
+		t.Errorf("expected at most %d runes, got %d", post.ExcerptLength+1, n)
+	}
+
+	unbroken := strings.Repeat("é", post.ExcerptLength+10)
+	if got := post.Excerpt(unbroken); got != strings.Repeat("é", post.ExcerptLength)+"…" {
+		t.Errorf("expected content without spaces to be cut at the limit, got %q", got)
+	}
+}
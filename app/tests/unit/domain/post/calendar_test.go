@@ -0,0 +1,95 @@
+package post_test
+
+import (
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestParsePublishTime(t *testing.T) {
+	// Local times follow daylight saving: 09:00 in Berlin is 08:00 UTC in
+	// winter and 07:00 UTC in summer
+	winter, err := post.ParsePublishTime("2030-01-15T09:00", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2030, 1, 15, 8, 0, 0, 0, time.UTC); !winter.Equal(want) {
+		t.Errorf("expected %v, got %v", want, winter.UTC())
+	}
+	summer, err := post.ParsePublishTime("2030-07-15T09:00:00", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2030, 7, 15, 7, 0, 0, 0, time.UTC); !summer.Equal(want) {
+		t.Errorf("expected %v, got %v", want, summer.UTC())
+	}
+
+	// Explicit offsets win over the time zone
+	offset, err := post.ParsePublishTime("2030-07-15T09:00:00+02:00", "America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2030, 7, 15, 7, 0, 0, 0, time.UTC); !offset.Equal(want) {
+		t.Errorf("expected %v, got %v", want, offset.UTC())
+	}
+
+	// Without a time zone local times are UTC
+	utc, err := post.ParsePublishTime("2030-07-15T09:00", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2030, 7, 15, 9, 0, 0, 0, time.UTC); !utc.Equal(want) {
+		t.Errorf("expected %v, got %v", want, utc)
+	}
+
+	if _, err := post.ParsePublishTime("15/07/2030 09:00", ""); err != post.ErrInvalidPublishTimeFormat {
+		t.Errorf("expected ErrInvalidPublishTimeFormat, got %v", err)
+	}
+	for _, timezone := range []string{"Mars/Olympus", "Local"} {
+		if _, err := post.ParsePublishTime("2030-07-15T09:00", timezone); err != post.ErrInvalidTimezone {
+			t.Errorf("expected ErrInvalidTimezone for %q, got %v", timezone, err)
+		}
+	}
+}
+
+func TestPost_ScheduleTimezone(t *testing.T) {
+	p, err := post.NewDraft("Title", "Some content here.", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2030, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	if err := p.Schedule(at, "Mars/Olympus", now); err != post.ErrInvalidTimezone {
+		t.Errorf("expected ErrInvalidTimezone, got %v", err)
+	}
+	if err := p.Schedule(at, "Asia/Tokyo", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Timezone != "Asia/Tokyo" {
+		t.Errorf("expected Asia/Tokyo, got %q", p.Timezone)
+	}
+	if local := p.LocalPublishedAt(); local == nil || local.Hour() != 17 {
+		t.Errorf("expected 17:00 local time, got %v", local)
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	base := time.Date(2030, 1, 15, 9, 0, 0, 0, time.UTC)
+	at := func(id int, offset time.Duration) *post.Post {
+		publishAt := base.Add(offset)
+		return &post.Post{ID: id, PublishedAt: &publishAt}
+	}
+
+	p := at(1, 0)
+	others := []*post.Post{p, at(2, 30*time.Minute), at(3, -59*time.Minute), at(4, time.Hour), at(5, -2*time.Hour), {ID: 6}}
+
+	conflicts := post.FindConflicts(p, others, time.Hour)
+	if len(conflicts) != 2 || conflicts[0].ID != 2 || conflicts[1].ID != 3 {
+		t.Errorf("expected posts 2 and 3, got %v", conflicts)
+	}
+	if conflicts := post.FindConflicts(p, others, 0); conflicts != nil {
+		t.Errorf("expected no conflicts without a window, got %v", conflicts)
+	}
+}
@@ -0,0 +1,52 @@
+package post_test
+
+import (
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestPost_SetPinned(t *testing.T) {
+	p, _ := post.NewPost("Pinned Post", "Test content with sufficient length.", 1)
+	pinnedAt := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	if err := p.SetPinned(true, pinnedAt); err != nil || !p.IsPinned() {
+		t.Fatalf("expected the post pinned, got %v", err)
+	}
+	// Pinning again keeps the post's place among pinned posts
+	if err := p.SetPinned(true, pinnedAt.Add(time.Hour)); err != nil || !p.PinnedAt.Equal(pinnedAt) {
+		t.Errorf("expected the pin time kept at %v, got %v (%v)", pinnedAt, p.PinnedAt, err)
+	}
+	if err := p.SetPinned(false, pinnedAt); err != nil || p.IsPinned() {
+		t.Errorf("expected the post unpinned, got %v", err)
+	}
+
+	draft, _ := post.NewDraft("Draft Post", "Test content with sufficient length.", 1)
+	if err := draft.SetPinned(true, pinnedAt); err != post.ErrNotPublished {
+		t.Errorf("expected ErrNotPublished, got %v", err)
+	}
+	if err := draft.SetPinned(false, pinnedAt); err != nil {
+		t.Errorf("expected unpinning a draft to succeed, got %v", err)
+	}
+}
+
+func TestPost_SetFeatured(t *testing.T) {
+	p, _ := post.NewPost("Featured Post", "Test content with sufficient length.", 1)
+	featuredAt := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	if err := p.SetFeatured(true, featuredAt); err != nil || !p.IsFeatured() || p.IsPinned() {
+		t.Fatalf("expected the post featured only, got %v", err)
+	}
+	if err := p.SetFeatured(true, featuredAt.Add(time.Hour)); err != nil || !p.FeaturedAt.Equal(featuredAt) {
+		t.Errorf("expected the feature time kept at %v, got %v (%v)", featuredAt, p.FeaturedAt, err)
+	}
+	if err := p.SetFeatured(false, featuredAt); err != nil || p.IsFeatured() {
+		t.Errorf("expected the post no longer featured, got %v", err)
+	}
+
+	draft, _ := post.NewDraft("Draft Post", "Test content with sufficient length.", 1)
+	if err := draft.SetFeatured(true, featuredAt); err != post.ErrNotPublished {
+		t.Errorf("expected ErrNotPublished, got %v", err)
+	}
+}
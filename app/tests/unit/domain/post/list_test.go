@@ -0,0 +1,57 @@
+package post_test
+
+import (
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestListOptions_Validate(t *testing.T) {
+	earlier := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		options post.ListOptions
+		want    error
+	}{
+		{"zero", post.ListOptions{}, nil},
+		{"title ascending", post.ListOptions{Sort: post.SortTitle, Order: post.OrderAsc}, nil},
+		{"order only", post.ListOptions{Order: post.OrderAsc}, nil},
+		{"range", post.ListOptions{CreatedAfter: &earlier, CreatedBefore: &later}, nil},
+		{"unknown sort", post.ListOptions{Sort: "id; DROP TABLE posts"}, post.ErrInvalidSort},
+		{"unknown order", post.ListOptions{Order: "DESC"}, post.ErrInvalidSort},
+		{"negative author", post.ListOptions{AuthorID: -1}, post.ErrInvalidPostData},
+		{"inverted range", post.ListOptions{CreatedAfter: &later, CreatedBefore: &earlier}, post.ErrInvalidCreatedRange},
+		{"empty range", post.ListOptions{CreatedAfter: &earlier, CreatedBefore: &earlier}, post.ErrInvalidCreatedRange},
+	}
+
+	for _, tt := range tests {
+		if err := tt.options.Validate(); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestListOptions_WithDefaults(t *testing.T) {
+	tests := []struct {
+		options   post.ListOptions
+		wantSort  post.SortField
+		wantOrder post.SortOrder
+	}{
+		{post.ListOptions{}, post.SortCreatedAt, post.OrderDesc},
+		{post.ListOptions{AuthorID: 3}, post.SortCreatedAt, post.OrderDesc},
+		{post.ListOptions{Sort: post.SortUpdatedAt}, post.SortUpdatedAt, post.OrderDesc},
+		{post.ListOptions{Sort: post.SortTitle}, post.SortTitle, post.OrderAsc},
+		{post.ListOptions{Sort: post.SortTitle, Order: post.OrderDesc}, post.SortTitle, post.OrderDesc},
+		{post.ListOptions{Order: post.OrderAsc}, post.SortCreatedAt, post.OrderAsc},
+	}
+
+	for _, tt := range tests {
+		got := tt.options.WithDefaults()
+		if got.Sort != tt.wantSort || got.Order != tt.wantOrder {
+			t.Errorf("%+v: expected %s %s, got %s %s", tt.options, tt.wantSort, tt.wantOrder, got.Sort, got.Order)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package post_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestNewProgress(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	p, err := post.NewProgress(2, 1, 42.5, "  part-2 ", now.Add(-time.Minute), now)
+	if err != nil {
+		t.Fatalf("NewProgress failed: %v", err)
+	}
+	if p.Anchor != "part-2" || p.Percent != 42.5 || !p.RecordedAt.Equal(now.Add(-time.Minute)) || !p.UpdatedAt.Equal(now) {
+		t.Errorf("unexpected progress %+v", p)
+	}
+
+	// Missing and future recording times are taken as now
+	for _, recordedAt := range []time.Time{{}, now.Add(time.Hour)} {
+		p, err := post.NewProgress(2, 1, 10, "", recordedAt, now)
+		if err != nil {
+			t.Fatalf("NewProgress failed: %v", err)
+		}
+		if !p.RecordedAt.Equal(now) {
+			t.Errorf("recordedAt %v: expected now, got %v", recordedAt, p.RecordedAt)
+		}
+	}
+
+	for _, percent := range []float64{-0.1, 100.01} {
+		if _, err := post.NewProgress(2, 1, percent, "", now, now); err != post.ErrInvalidPercent {
+			t.Errorf("percent %v: expected ErrInvalidPercent, got %v", percent, err)
+		}
+	}
+	for _, percent := range []float64{0, 100} {
+		if _, err := post.NewProgress(2, 1, percent, "", now, now); err != nil {
+			t.Errorf("percent %v: unexpected error %v", percent, err)
+		}
+	}
+	if _, err := post.NewProgress(2, 1, 10, strings.Repeat("a", 256), now, now); err != post.ErrInvalidAnchor {
+		t.Errorf("expected ErrInvalidAnchor, got %v", err)
+	}
+}
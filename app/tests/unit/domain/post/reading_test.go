@@ -0,0 +1,63 @@
+package post_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"", 0},
+		{"   \n\t", 0},
+		{"one", 1},
+		{"Hello, **world**!\n\nA second  paragraph.", 5},
+		{"Привет мир", 2},
+	}
+
+	for _, tt := range tests {
+		if got := post.CountWords(tt.content); got != tt.expected {
+			t.Errorf("expected %d words in %q, got %d", tt.expected, tt.content, got)
+		}
+	}
+}
+
+func TestReadingTimeMinutes(t *testing.T) {
+	tests := []struct {
+		words    int
+		expected int
+	}{
+		{0, 0},
+		{1, 1},
+		{post.WordsPerMinute, 1},
+		{post.WordsPerMinute + 1, 2},
+		{10 * post.WordsPerMinute, 10},
+	}
+
+	for _, tt := range tests {
+		if got := post.ReadingTimeMinutes(tt.words); got != tt.expected {
+			t.Errorf("expected %d minutes for %d words, got %d", tt.expected, tt.words, got)
+		}
+	}
+}
+
+func TestPost_ReadingStats(t *testing.T) {
+	p, err := post.NewPost("Title", "Short intro.\n\n"+strings.Repeat("word ", 300), 1)
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if p.WordCount != 302 || p.ReadingTime != 2 || p.Excerpt != "Short intro." {
+		t.Errorf("expected stats computed by NewPost, got %d words, %d minutes, excerpt %q", p.WordCount, p.ReadingTime, p.Excerpt)
+	}
+
+	if err := p.Update("Title", "Rewritten."); err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if p.WordCount != 1 || p.ReadingTime != 1 || p.Excerpt != "Rewritten." {
+		t.Errorf("expected stats recomputed by Update, got %d words, %d minutes, excerpt %q", p.WordCount, p.ReadingTime, p.Excerpt)
+	}
+}
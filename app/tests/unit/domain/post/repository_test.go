@@ -1,22 +1,32 @@
 package post_test
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"blog-platform/internal/domain/keyset"
 	"blog-platform/internal/domain/post"
 )
 
 // MockPostRepository implements the PostRepository interface for testing
 type MockPostRepository struct {
-	posts  map[int]*post.Post
-	nextID int
+	posts     map[int]*post.Post
+	revisions map[int][]*post.Revision
+	translations map[int]map[string]*post.Translation
+	views     []post.ViewCount
+	nextID    int
 }
 
 func NewMockPostRepository() *MockPostRepository {
 	return &MockPostRepository{
-		posts:  make(map[int]*post.Post),
-		nextID: 1,
+		posts:     make(map[int]*post.Post),
+		revisions: make(map[int][]*post.Revision),
+		translations: make(map[int]map[string]*post.Translation),
+		nextID:    1,
 	}
 }
 
@@ -38,6 +48,65 @@ func (m *MockPostRepository) GetByID(ctx context.Context, id int) (*post.Post, e
 	return nil, post.ErrPostNotFound
 }
 
+func (m *MockPostRepository) GetBySlug(ctx context.Context, slug string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) GetVisibleByID(ctx context.Context, id int, filter post.ViewFilter) (*post.Post, error) {
+	if p, exists := m.posts[id]; exists && visibleThrough(p, filter) {
+		return p, nil
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) GetVisibleBySlug(ctx context.Context, slug string, filter post.ViewFilter) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.Slug == slug && visibleThrough(p, filter) {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+// visibleThrough reports whether the repository finds a post through the
+// view filter
+func visibleThrough(p *post.Post, filter post.ViewFilter) bool {
+	return (p.IsPublished() && !p.IsPrivate()) || p.AuthorID == filter.AuthorID || filter.All
+}
+
+func (m *MockPostRepository) GetByClientID(ctx context.Context, clientID string) (*post.Post, error) {
+	for _, p := range m.posts {
+		if p.ClientID != nil && *p.ClientID == clientID {
+			return p, nil
+		}
+	}
+	return nil, post.ErrPostNotFound
+}
+
+func (m *MockPostRepository) ChangeSlug(ctx context.Context, id int, slug string) error {
+	p, exists := m.posts[id]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	p.Slug = slug
+	return nil
+}
+
+func (m *MockPostRepository) SetVisibility(ctx context.Context, p *post.Post) error {
+	existing, exists := m.posts[p.ID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	existing.Visibility = p.Visibility
+	existing.UpdatedAt = p.UpdatedAt
+	return nil
+}
+
 func (m *MockPostRepository) GetByAuthorID(ctx context.Context, authorID int, limit, offset int) ([]*post.Post, error) {
 	var posts []*post.Post
 	count := 0
@@ -80,6 +149,154 @@ func (m *MockPostRepository) List(ctx context.Context, limit, offset int) ([]*po
 	return posts, nil
 }
 
+// ListMatching lists the matching posts in the requested order, ties broken
+// by ID like the repository
+func (m *MockPostRepository) ListMatching(ctx context.Context, filter post.ListFilter, options post.ListOptions, limit, offset int) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		return visible &&
+			(filter.Tag == "" || slices.Contains(p.Tags, filter.Tag)) &&
+			(options.AuthorID == 0 || p.AuthorID == options.AuthorID) &&
+			(options.CreatedAfter == nil || !p.CreatedAt.Before(*options.CreatedAfter)) &&
+			(options.CreatedBefore == nil || p.CreatedAt.Before(*options.CreatedBefore))
+	}, len(m.posts), 0)
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		var c int
+		switch options.Sort {
+		case post.SortUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case post.SortTitle:
+			c = strings.Compare(a.Title, b.Title)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		c = cmp.Or(c, cmp.Compare(a.ID, b.ID))
+		if options.Order == post.OrderDesc {
+			return -c
+		}
+		return c
+	})
+	posts = posts[min(offset, len(posts)):]
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListPublished(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsPublished() }, limit, offset), nil
+}
+
+// ListAfter lists the matching posts after the cursor, newest first
+func (m *MockPostRepository) ListAfter(ctx context.Context, filter post.ListFilter, after keyset.Cursor, limit int) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		if !visible || (filter.Tag != "" && !slices.Contains(p.Tags, filter.Tag)) {
+			return false
+		}
+		return after.IsZero() || p.CreatedAt.Before(after.CreatedAt) || (p.CreatedAt.Equal(after.CreatedAt) && p.ID < after.ID)
+	}, len(m.posts), 0)
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListVisibleTo(ctx context.Context, userID int, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsPublished() || p.AuthorID == userID }, limit, offset), nil
+}
+
+func (m *MockPostRepository) ListDueScheduled(ctx context.Context, now time.Time) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.IsDue(now) }, len(m.posts), 0), nil
+}
+
+func (m *MockPostRepository) ListSharingTags(ctx context.Context, postID, limit int) ([]*post.Post, error) {
+	source, exists := m.posts[postID]
+	if !exists {
+		return nil, nil
+	}
+	shared := func(p *post.Post) int {
+		n := 0
+		for _, tag := range p.Tags {
+			if slices.Contains(source.Tags, tag) {
+				n++
+			}
+		}
+		return n
+	}
+	posts := m.filter(func(p *post.Post) bool { return p.ID != postID && p.IsPublished() && shared(p) > 0 }, len(m.posts), 0)
+	slices.SortStableFunc(posts, func(a, b *post.Post) int { return shared(b) - shared(a) })
+	return posts[:min(limit, len(posts))], nil
+}
+
+func (m *MockPostRepository) ListFeatured(ctx context.Context, limit, offset int) ([]*post.Post, error) {
+	featured := m.filter(func(p *post.Post) bool { return p.IsPublished() && p.IsFeatured() }, len(m.posts), 0)
+	slices.SortStableFunc(featured, func(a, b *post.Post) int { return b.FeaturedAt.Compare(*a.FeaturedAt) })
+	featured = featured[min(offset, len(featured)):]
+	return featured[:min(limit, len(featured))], nil
+}
+
+func (m *MockPostRepository) ListPublishedByIDs(ctx context.Context, ids []int) ([]*post.Post, error) {
+	var posts []*post.Post
+	for _, id := range ids {
+		if p, exists := m.posts[id]; exists && p.IsPublished() {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (m *MockPostRepository) ListActivity(ctx context.Context, since time.Time) ([]post.Activity, error) {
+	var activity []post.Activity
+	for _, c := range m.views {
+		if p, exists := m.posts[c.PostID]; exists && p.IsPublished() && !c.Day.Before(post.ViewDay(since)) {
+			activity = append(activity, post.Activity{PostID: c.PostID, Kind: post.ActivityView, At: c.Day, Count: c.Views})
+		}
+	}
+	return activity, nil
+}
+
+func (m *MockPostRepository) ListMissingReadingStats(ctx context.Context, afterID, limit int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool { return p.ID > afterID && p.ReadingTime == 0 && p.Content != "" }, limit, 0), nil
+}
+
+func (m *MockPostRepository) ListPublishingBetween(ctx context.Context, from, to time.Time) ([]*post.Post, error) {
+	posts := m.filter(func(p *post.Post) bool {
+		return p.Status != post.StatusDraft && p.PublishedAt != nil && !p.PublishedAt.Before(from) && !p.PublishedAt.After(to)
+	}, len(m.posts), 0)
+	slices.SortStableFunc(posts, func(a, b *post.Post) int { return a.PublishedAt.Compare(*b.PublishedAt) })
+	return posts, nil
+}
+
+func (m *MockPostRepository) ListByTag(ctx context.Context, filter post.TagFilter, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool {
+		visible := p.IsPublished() || p.AuthorID == filter.AuthorID || filter.All
+		return visible && slices.Contains(p.Tags, filter.Tag)
+	}, limit, offset), nil
+}
+
+func (m *MockPostRepository) ListPublishedBy(ctx context.Context, filter post.PublishedFilter, limit, offset int) ([]*post.Post, error) {
+	return m.filter(func(p *post.Post) bool {
+		return p.IsPublished() &&
+			(filter.AuthorID == 0 || p.AuthorID == filter.AuthorID) &&
+			(filter.Tag == "" || slices.Contains(p.Tags, filter.Tag))
+	}, limit, offset), nil
+}
+
+// filter returns a page of the posts matching keep, in ID order
+func (m *MockPostRepository) filter(keep func(p *post.Post) bool, limit, offset int) []*post.Post {
+	var posts []*post.Post
+	for id := 1; id < m.nextID && len(posts) < limit; id++ {
+		p, exists := m.posts[id]
+		if !exists || !keep(p) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		posts = append(posts, p)
+	}
+	return posts
+}
+
 func (m *MockPostRepository) Update(ctx context.Context, p *post.Post) error {
 	if p == nil {
 		return post.ErrInvalidPostData
@@ -93,6 +310,109 @@ func (m *MockPostRepository) Update(ctx context.Context, p *post.Post) error {
 	return nil
 }
 
+func (m *MockPostRepository) UpdateWithRevision(ctx context.Context, p *post.Post, rev *post.Revision) error {
+	if err := m.Update(ctx, p); err != nil {
+		return err
+	}
+
+	rev.PostID = p.ID
+	rev.Number = len(m.revisions[p.ID]) + 1
+	m.revisions[p.ID] = append(m.revisions[p.ID], rev)
+	return nil
+}
+
+func (m *MockPostRepository) ListRevisions(ctx context.Context, postID int) ([]*post.Revision, error) {
+	revisions := slices.Clone(m.revisions[postID])
+	slices.Reverse(revisions)
+	return revisions, nil
+}
+
+func (m *MockPostRepository) GetRevision(ctx context.Context, postID, number int) (*post.Revision, error) {
+	revisions := m.revisions[postID]
+	if number < 1 || number > len(revisions) {
+		return nil, post.ErrRevisionNotFound
+	}
+	return revisions[number-1], nil
+}
+
+func (m *MockPostRepository) GetTranslation(ctx context.Context, postID int, language string) (*post.Translation, error) {
+	if t, exists := m.translations[postID][language]; exists {
+		return t, nil
+	}
+	return nil, post.ErrTranslationNotFound
+}
+
+func (m *MockPostRepository) SaveTranslation(ctx context.Context, t *post.Translation) error {
+	p, exists := m.posts[t.PostID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	if m.translations[t.PostID] == nil {
+		m.translations[t.PostID] = make(map[string]*post.Translation)
+	}
+	if _, exists := m.translations[t.PostID][t.Language]; !exists {
+		p.Translations = append(p.Translations, t.Language)
+		slices.Sort(p.Translations)
+	}
+	m.translations[t.PostID][t.Language] = t
+	return nil
+}
+
+func (m *MockPostRepository) SetTags(ctx context.Context, postID int, tags []string) error {
+	p, exists := m.posts[postID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+
+	p.Tags = slices.Sorted(slices.Values(tags))
+	return nil
+}
+
+func (m *MockPostRepository) SetReadingStats(ctx context.Context, p *post.Post) error {
+	stored, exists := m.posts[p.ID]
+	if !exists {
+		return post.ErrPostNotFound
+	}
+	stored.WordCount, stored.ReadingTime, stored.Excerpt = p.WordCount, p.ReadingTime, p.Excerpt
+	return nil
+}
+
+func (m *MockPostRepository) AddViews(ctx context.Context, counts []post.ViewCount) error {
+	for _, c := range counts {
+		if p, exists := m.posts[c.PostID]; exists {
+			p.ViewCount += c.Views
+			m.views = append(m.views, c)
+		}
+	}
+	return nil
+}
+
+func (m *MockPostRepository) ListPopular(ctx context.Context, since time.Time, limit int) ([]*post.PopularPost, error) {
+	views := make(map[int]int64)
+	for _, c := range m.views {
+		if !c.Day.Before(since) {
+			views[c.PostID] += c.Views
+		}
+	}
+
+	var popular []*post.PopularPost
+	for id, count := range views {
+		if p, exists := m.posts[id]; exists && p.IsPublished() {
+			popular = append(popular, &post.PopularPost{Post: p, Views: count})
+		}
+	}
+	slices.SortFunc(popular, func(a, b *post.PopularPost) int {
+		if a.Views != b.Views {
+			return int(b.Views - a.Views)
+		}
+		return b.Post.ID - a.Post.ID
+	})
+	if len(popular) > limit {
+		popular = popular[:limit]
+	}
+	return popular, nil
+}
+
 func (m *MockPostRepository) Delete(ctx context.Context, id int) error {
 	if _, exists := m.posts[id]; !exists {
 		return post.ErrPostNotFound
@@ -0,0 +1,74 @@
+package post_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestNewRevision(t *testing.T) {
+	p, err := post.NewPost("Title", "Some content here.", 1)
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	p.ID = 7
+
+	rev := post.NewRevision(p, 2)
+	if rev.PostID != 7 || rev.Title != "Title" || rev.Content != "Some content here." || rev.EditedBy != 2 {
+		t.Errorf("unexpected revision %+v", rev)
+	}
+	if rev.Number != 0 {
+		t.Errorf("expected the repository to number revisions, got %d", rev.Number)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	eq := func(text string) post.DiffLine { return post.DiffLine{Op: post.DiffEqual, Text: text} }
+	ins := func(text string) post.DiffLine { return post.DiffLine{Op: post.DiffInsert, Text: text} }
+	del := func(text string) post.DiffLine { return post.DiffLine{Op: post.DiffDelete, Text: text} }
+
+	tests := []struct {
+		name     string
+		from, to string
+		expected []post.DiffLine
+	}{
+		{name: "unchanged", from: "a\nb", to: "a\nb", expected: []post.DiffLine{eq("a"), eq("b")}},
+		{name: "line added", from: "a\nc", to: "a\nb\nc", expected: []post.DiffLine{eq("a"), ins("b"), eq("c")}},
+		{name: "line removed", from: "a\nb\nc", to: "a\nc", expected: []post.DiffLine{eq("a"), del("b"), eq("c")}},
+		{name: "line changed", from: "a\nb\nc", to: "a\nx\nc", expected: []post.DiffLine{eq("a"), del("b"), ins("x"), eq("c")}},
+		{name: "from empty", from: "", to: "a", expected: []post.DiffLine{ins("a")}},
+		{name: "to empty", from: "a", to: "", expected: []post.DiffLine{del("a")}},
+		{name: "windows line endings", from: "a\r\nb", to: "a\nb", expected: []post.DiffLine{eq("a"), eq("b")}},
+		{
+			name:     "moved line",
+			from:     "a\nb\nc\nd",
+			to:       "b\nc\na\nd",
+			expected: []post.DiffLine{del("a"), eq("b"), eq("c"), ins("a"), eq("d")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := post.DiffLines(tt.from, tt.to); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiffLines_LargeChange(t *testing.T) {
+	from := strings.Repeat("old line\n", 2000)
+	to := strings.Repeat("new line\n", 2000)
+
+	// Too large to compare line by line, so everything between the common
+	// first and (empty) last line is replaced
+	diff := post.DiffLines("start\n"+from, "start\n"+to)
+	if expected := 1 + 2000 + 2000 + 1; len(diff) != expected {
+		t.Fatalf("expected %d lines, got %d", expected, len(diff))
+	}
+	if diff[0].Op != post.DiffEqual || diff[1].Op != post.DiffDelete || diff[len(diff)-1].Op != post.DiffEqual {
+		t.Errorf("unexpected diff ends %v ... %v", diff[:2], diff[len(diff)-1])
+	}
+}
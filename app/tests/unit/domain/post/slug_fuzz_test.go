@@ -0,0 +1,39 @@
+package post_test
+
+import (
+	"regexp"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+)
+
+// slugPattern matches lowercase ASCII words separated by single hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func FuzzSlugify(f *testing.F) {
+	for _, title := range []string{
+		"My First Post",
+		"Café au lait — 東京 ☕",
+		"Привет, мир",
+		"null\x00byte",
+		"<script>alert(1)</script>",
+		"--- ---",
+		"\xff\xfe invalid utf-8",
+	} {
+		f.Add(title)
+	}
+
+	f.Fuzz(func(t *testing.T, title string) {
+		slug := post.Slugify(title)
+
+		if !slugPattern.MatchString(slug) {
+			t.Fatalf("invalid slug %q for title %q", slug, title)
+		}
+		if len(slug) > 100 {
+			t.Fatalf("slug of %d bytes for title %q", len(slug), title)
+		}
+		if again := post.Slugify(slug); again != slug {
+			t.Fatalf("slug %q is not stable, got %q", slug, again)
+		}
+	})
+}
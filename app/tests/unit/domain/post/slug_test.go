@@ -0,0 +1,99 @@
+package post_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{name: "simple title", title: "My First Post", expected: "my-first-post"},
+		{name: "punctuation collapses", title: "Hello, World!  Again...", expected: "hello-world-again"},
+		{name: "leading and trailing separators", title: "  --Go 1.24--  ", expected: "go-1-24"},
+		{name: "non-ascii letters are separators", title: "Café au lait", expected: "caf-au-lait"},
+		{name: "no letters or digits", title: "!!!", expected: "post"},
+		{name: "empty title", title: "", expected: "post"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := post.Slugify(tt.title); got != tt.expected {
+				t.Errorf("expected slug %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSlugify_TruncatesLongTitles(t *testing.T) {
+	slug := post.Slugify(strings.Repeat("word ", 50))
+
+	if len(slug) > 100 {
+		t.Errorf("expected slug of at most 100 characters, got %d", len(slug))
+	}
+	if strings.HasSuffix(slug, "-") {
+		t.Errorf("expected slug not to end with a hyphen, got %q", slug)
+	}
+}
+
+func TestNewPost_SetsSlug(t *testing.T) {
+	p, err := post.NewPost("A Valid Title", "Some valid content here.", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Slug != "a-valid-title" {
+		t.Errorf("expected slug 'a-valid-title', got %q", p.Slug)
+	}
+}
+
+func TestNextSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		expected string
+	}{
+		{name: "base slug", slug: "top-10", expected: "top-10-2"},
+		{name: "numbered slug", slug: "top-10-2", expected: "top-10-3"},
+		{name: "double digits", slug: "top-10-11", expected: "top-10-12"},
+		{name: "unrelated slug", slug: "other", expected: "top-10-2"},
+		{name: "non-numeric suffix", slug: "top-10-draft", expected: "top-10-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := post.NextSlug("top-10", tt.slug); got != tt.expected {
+				t.Errorf("expected slug %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsValidSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		expected bool
+	}{
+		{name: "slugified title", slug: "my-first-post", expected: true},
+		{name: "digits", slug: "go-1-24", expected: true},
+		{name: "empty", slug: "", expected: false},
+		{name: "uppercase", slug: "My-First-Post", expected: false},
+		{name: "double hyphen", slug: "my--post", expected: false},
+		{name: "trailing hyphen", slug: "my-post-", expected: false},
+		{name: "too long", slug: strings.Repeat("a", 101), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := post.IsValidSlug(tt.slug); got != tt.expected {
+				t.Errorf("expected IsValidSlug(%q) to be %v, got %v", tt.slug, tt.expected, got)
+			}
+		})
+	}
+}
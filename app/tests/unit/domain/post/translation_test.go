@@ -0,0 +1,102 @@
+package post_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestParseLanguage(t *testing.T) {
+	valid := map[string]string{
+		"en":      "en",
+		"fr":      "fr",
+		"pt-br":   "pt-BR",
+		" de-AT":  "de-AT",
+		"zh-Hant": "zh-Hant",
+	}
+	for code, want := range valid {
+		got, err := post.ParseLanguage(code)
+		if err != nil || got != want {
+			t.Errorf("ParseLanguage(%q) = %q, %v; want %q", code, got, err, want)
+		}
+	}
+	for _, code := range []string{"", "und", "english!", "e", "en_US_POSIX_extra_long_tag"} {
+		if _, err := post.ParseLanguage(code); err != post.ErrInvalidLanguage {
+			t.Errorf("ParseLanguage(%q): expected ErrInvalidLanguage, got %v", code, err)
+		}
+	}
+}
+
+func TestNewTranslation(t *testing.T) {
+	p, _ := post.NewPost("Hello", "Test content with sufficient length.", 1)
+	p.ID = 7
+	if p.Language != post.DefaultLanguage {
+		t.Errorf("expected new posts in %q, got %q", post.DefaultLanguage, p.Language)
+	}
+
+	tr, err := post.NewTranslation(p, "FR", " Bonjour ", " Contenu de test assez long. ", 2)
+	if err != nil {
+		t.Fatalf("failed to create translation: %v", err)
+	}
+	if tr.PostID != 7 || tr.Language != "fr" || tr.Title != "Bonjour" || tr.Content != "Contenu de test assez long." || tr.TranslatedBy != 2 {
+		t.Errorf("unexpected translation %+v", tr)
+	}
+
+	if _, err := post.NewTranslation(p, "en", "Hello", "Test content with sufficient length.", 1); err != post.ErrSameLanguage {
+		t.Errorf("expected ErrSameLanguage, got %v", err)
+	}
+	if _, err := post.NewTranslation(p, "??", "Hello", "Test content with sufficient length.", 1); err != post.ErrInvalidLanguage {
+		t.Errorf("expected ErrInvalidLanguage, got %v", err)
+	}
+}
+
+func TestPost_NegotiateLanguage(t *testing.T) {
+	p := &post.Post{Language: "en", Translations: []string{"de", "fr", "pt-BR"}}
+
+	tests := map[string]string{
+		"":                          "en",
+		"fr":                        "fr",
+		"fr-CH, fr;q=0.9, en;q=0.8": "fr",
+		"de;q=0.5, fr;q=0.7":        "fr",
+		"pt":                        "pt-BR",
+		"en-GB":                     "en",
+		"ja":                        "en",
+		"ja, de;q=0.1":              "de",
+		"*":                         "en",
+		"not a header;;":            "en",
+	}
+	for header, want := range tests {
+		if got := p.NegotiateLanguage(header); got != want {
+			t.Errorf("NegotiateLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+
+	untranslated := &post.Post{Language: "en"}
+	if got := untranslated.NegotiateLanguage("fr"); got != "en" {
+		t.Errorf("expected posts without translations in their own language, got %q", got)
+	}
+}
+
+func TestPost_Translated(t *testing.T) {
+	p, _ := post.NewPost("Hello", "First paragraph.\n\nSecond paragraph.", 1)
+	p.Translations = []string{"de", "fr"}
+	later := p.UpdatedAt.Add(time.Hour)
+	tr := &post.Translation{Language: "fr", Title: "Bonjour", Content: "Un deux trois.\n\nQuatre.", UpdatedAt: later}
+
+	translated := p.Translated(tr)
+	if translated.Title != "Bonjour" || translated.Language != "fr" || translated.Excerpt != "Un deux trois." || translated.WordCount != 4 {
+		t.Errorf("unexpected translated post %+v", translated)
+	}
+	if !slices.Equal(translated.Translations, []string{"de", "en"}) {
+		t.Errorf("expected the other languages de and en, got %v", translated.Translations)
+	}
+	if !translated.UpdatedAt.Equal(later) {
+		t.Errorf("expected the update time of the translation, got %v", translated.UpdatedAt)
+	}
+	// The post itself is untouched
+	if p.Title != "Hello" || p.Language != "en" || !slices.Equal(p.Translations, []string{"de", "fr"}) {
+		t.Errorf("expected the post untouched, got %+v", p)
+	}
+}
@@ -0,0 +1,50 @@
+package post_test
+
+import (
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/post"
+)
+
+func TestRankTrending(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	halfLife := 24 * time.Hour
+	activity := []post.Activity{
+		// Post 1: many views, two days ago
+		{PostID: 1, Kind: post.ActivityView, At: post.ViewDay(now).AddDate(0, 0, -2), Count: 40},
+		// Post 2: a few views and a comment today
+		{PostID: 2, Kind: post.ActivityView, At: post.ViewDay(now), Count: 5},
+		{PostID: 2, Kind: post.ActivityComment, At: now.Add(-time.Hour), Count: 1},
+		// Post 3: one bookmark a week ago
+		{PostID: 3, Kind: post.ActivityBookmark, At: now.AddDate(0, 0, -7), Count: 1},
+		// Post 4: a bookmark stamped ahead of the clock counts as current
+		{PostID: 4, Kind: post.ActivityBookmark, At: now.Add(time.Hour), Count: 1},
+	}
+
+	ranked := post.RankTrending(activity, now, halfLife, 10)
+	if len(ranked) != 4 {
+		t.Fatalf("expected 4 ranked posts, got %d", len(ranked))
+	}
+	expected := []int{2, 1, 4, 3}
+	for i, id := range expected {
+		if ranked[i].PostID != id {
+			t.Fatalf("expected posts ranked %v, got %+v", expected, ranked)
+		}
+	}
+
+	// Views two days old count a quarter; a day of views is taken at its middle
+	if got := ranked[1].Score; got != 10 {
+		t.Errorf("expected a decayed score of 10, got %v", got)
+	}
+	if got := ranked[2].Score; got != 5 {
+		t.Errorf("expected future activity not to grow, got %v", got)
+	}
+
+	if limited := post.RankTrending(activity, now, halfLife, 1); len(limited) != 1 || limited[0].PostID != 2 {
+		t.Errorf("expected only the top post, got %+v", limited)
+	}
+	if empty := post.RankTrending(nil, now, halfLife, 10); len(empty) != 0 {
+		t.Errorf("expected no ranked posts without activity, got %+v", empty)
+	}
+}
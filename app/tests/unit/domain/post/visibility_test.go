@@ -0,0 +1,65 @@
+package post_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/user"
+)
+
+func TestVisibility_IsValid(t *testing.T) {
+	for _, visibility := range []post.Visibility{post.VisibilityPublic, post.VisibilityUnlisted, post.VisibilityPrivate} {
+		if !visibility.IsValid() {
+			t.Errorf("expected %q to be valid", visibility)
+		}
+	}
+	for _, visibility := range []post.Visibility{"", "hidden", "Private"} {
+		if visibility.IsValid() {
+			t.Errorf("expected %q to be invalid", visibility)
+		}
+	}
+}
+
+func TestPost_Visibility(t *testing.T) {
+	tests := []struct {
+		visibility post.Visibility
+		status     post.Status
+		listed     bool
+		anonymous  bool
+		reader     bool
+		author     bool
+		admin      bool
+	}{
+		{post.VisibilityPublic, post.StatusPublished, true, true, true, true, true},
+		{post.VisibilityUnlisted, post.StatusPublished, false, true, true, true, true},
+		{post.VisibilityPrivate, post.StatusPublished, false, false, false, true, true},
+		{post.VisibilityPublic, post.StatusDraft, false, false, false, true, true},
+		{post.VisibilityUnlisted, post.StatusDraft, false, false, false, true, true},
+	}
+	for _, tt := range tests {
+		p := &post.Post{AuthorID: 1, Visibility: tt.visibility, Status: tt.status}
+		if got := p.IsListed(); got != tt.listed {
+			t.Errorf("%s %s post: expected listed %v, got %v", tt.visibility, tt.status, tt.listed, got)
+		}
+		for _, c := range []struct {
+			name   string
+			userID int
+			role   user.Role
+			want   bool
+		}{
+			{"anonymous", 0, "", tt.anonymous},
+			{"reader", 2, user.RoleReader, tt.reader},
+			{"author", 1, user.RoleAuthor, tt.author},
+			{"admin", 3, user.RoleAdmin, tt.admin},
+		} {
+			if got := p.IsVisibleTo(c.userID, c.role); got != c.want {
+				t.Errorf("%s %s post, %s: expected visible %v, got %v", tt.visibility, tt.status, c.name, c.want, got)
+			}
+		}
+	}
+
+	p, _ := post.NewPost("Title", "Test content with sufficient length.", 1)
+	if p.Visibility != post.VisibilityPublic {
+		t.Errorf("expected new posts to be public, got %q", p.Visibility)
+	}
+}
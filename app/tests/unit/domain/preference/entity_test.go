@@ -0,0 +1,44 @@
+package preference_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/preference"
+)
+
+func TestNewFeed(t *testing.T) {
+	f, err := preference.NewFeed(preference.ScopeAuthor, preference.AuthorKey(7), "  Ada on Systems ", "\nNotes on Go\n", 7)
+	if err != nil {
+		t.Fatalf("expected feed preference, got %v", err)
+	}
+	if f.Key != "7" || f.Title != "Ada on Systems" || f.Description != "Notes on Go" || f.UpdatedBy != 7 {
+		t.Errorf("unexpected feed preference %+v", f)
+	}
+
+	// Empty fields keep the generated texts
+	if _, err := preference.NewFeed(preference.ScopeTag, "go", "", "", 1); err != nil {
+		t.Errorf("expected empty preference to be valid, got %v", err)
+	}
+}
+
+func TestNewFeed_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       string
+		description string
+		want        error
+	}{
+		{name: "title too long", title: strings.Repeat("é", 201), want: preference.ErrInvalidFeedTitle},
+		{name: "multi-line title", title: "Ada\nSystems", want: preference.ErrInvalidFeedTitle},
+		{name: "description too long", description: strings.Repeat("a", 1001), want: preference.ErrInvalidFeedDescription},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := preference.NewFeed(preference.ScopeAuthor, "1", tt.title, tt.description, 1); err != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
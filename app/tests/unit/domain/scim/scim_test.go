@@ -0,0 +1,104 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *scim.Filter
+	}{
+		{name: "user name", input: `userName eq "jane@acme.com"`, expected: &scim.Filter{Attribute: scim.FilterUserName, Value: "jane@acme.com"}},
+		{name: "case-insensitive", input: `USERNAME EQ "jane@acme.com"`, expected: &scim.Filter{Attribute: scim.FilterUserName, Value: "jane@acme.com"}},
+		{name: "external ID", input: `externalId eq "00u1"`, expected: &scim.Filter{Attribute: scim.FilterExternalID, Value: "00u1"}},
+		{name: "email", input: `emails.value eq "jane@acme.com"`, expected: &scim.Filter{Attribute: scim.FilterEmail, Value: "jane@acme.com"}},
+		{name: "escaped quotes", input: `displayName eq "R\"D"`, expected: &scim.Filter{Attribute: scim.FilterDisplayName, Value: `R"D`}},
+		{name: "value with spaces", input: `displayName eq "Research and Development"`, expected: &scim.Filter{Attribute: scim.FilterDisplayName, Value: "Research and Development"}},
+		{name: "unsupported operator", input: `userName sw "jane"`},
+		{name: "unsupported attribute", input: `title eq "Engineer"`},
+		{name: "unquoted value", input: `userName eq jane`},
+		{name: "combined comparisons", input: `userName eq "jane" and active eq true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := scim.ParseFilter(tt.input)
+			if tt.expected == nil {
+				assert.ErrorIs(t, err, scim.ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestUserAttributes_Validate(t *testing.T) {
+	attrs := scim.UserAttributes{UserName: " jane@acme.com "}
+	require.NoError(t, attrs.Validate())
+	assert.Equal(t, "jane@acme.com", attrs.Email, "the email defaults to the user name")
+	assert.Equal(t, "jane@acme.com", attrs.Name, "the name defaults to the email")
+
+	for _, invalid := range []scim.UserAttributes{
+		{},
+		{UserName: "jane"},
+		{UserName: "jane", Email: "Jane <jane@acme.com>"},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), scim.ErrInvalidValue, "%+v", invalid)
+	}
+}
+
+func TestUserAttributes_ApplyPatch(t *testing.T) {
+	attrs := scim.UserAttributes{UserName: "jane@acme.com", ExternalID: "00u1", Name: "Jane Doe", Email: "jane@acme.com", Active: true}
+
+	var ops []scim.PatchOperation
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "Replace", "path": "active", "value": "False"},
+		{"op": "replace", "value": {"name": {"givenName": "Jane", "familyName": "Roe"}, "emails": [{"value": "old@acme.com"}, {"value": "jane.roe@acme.com", "primary": true}]}},
+		{"op": "remove", "path": "externalId"}
+	]`), &ops))
+
+	require.NoError(t, attrs.ApplyPatch(ops))
+	assert.False(t, attrs.Active)
+	assert.Equal(t, "Jane Roe", attrs.Name)
+	assert.Equal(t, "jane.roe@acme.com", attrs.Email)
+	assert.Empty(t, attrs.ExternalID)
+
+	err := attrs.ApplyPatch([]scim.PatchOperation{{Op: "replace", Path: "title", Value: json.RawMessage(`"Engineer"`)}})
+	assert.ErrorIs(t, err, scim.ErrInvalidPath)
+	err = attrs.ApplyPatch([]scim.PatchOperation{{Op: "remove", Path: "userName"}})
+	assert.ErrorIs(t, err, scim.ErrInvalidPath)
+	err = attrs.ApplyPatch([]scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}})
+	assert.ErrorIs(t, err, scim.ErrInvalidValue)
+}
+
+func TestGroup_ApplyPatch(t *testing.T) {
+	g, err := scim.NewGroup("acme", "Engineering", "", []int{1, 2, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, g.Members, "duplicate members are dropped")
+
+	require.NoError(t, g.ApplyPatch([]scim.PatchOperation{
+		{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "3"}]`)},
+		{Op: "remove", Path: `members[value eq "1"]`},
+		{Op: "replace", Value: json.RawMessage(`{"displayName": "R&D"}`)},
+	}))
+	assert.Equal(t, []int{2, 3}, g.Members)
+	assert.Equal(t, "R&D", g.DisplayName)
+
+	require.NoError(t, g.ApplyPatch([]scim.PatchOperation{{Op: "remove", Path: "members"}}))
+	assert.Empty(t, g.Members)
+
+	err = g.ApplyPatch([]scim.PatchOperation{{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "abc"}]`)}})
+	assert.ErrorIs(t, err, scim.ErrInvalidValue)
+	err = g.ApplyPatch([]scim.PatchOperation{{Op: "replace", Path: "displayName", Value: json.RawMessage(`""`)}})
+	assert.ErrorIs(t, err, scim.ErrInvalidValue)
+	assert.Equal(t, "R&D", g.DisplayName, "failed patches leave the group unchanged")
+}
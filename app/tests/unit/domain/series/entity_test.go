@@ -0,0 +1,78 @@
+package series_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/domain/series"
+	"blog-platform/internal/domain/user"
+)
+
+func TestNewSeries(t *testing.T) {
+	s, err := series.NewSeries(1, series.Input{Title: "  Building a Blog ", Description: " Step by step. "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if s.Title != "Building a Blog" || s.Description != "Step by step." {
+		t.Errorf("expected trimmed fields, got %q and %q", s.Title, s.Description)
+	}
+	if !s.CanModify(1, user.RoleAuthor) || !s.CanModify(2, user.RoleAdmin) || s.CanModify(2, user.RoleAuthor) {
+		t.Error("expected only the author and admins to modify the series")
+	}
+}
+
+func TestNewSeries_Validation(t *testing.T) {
+	cases := map[string]struct {
+		in   series.Input
+		want error
+	}{
+		"empty title":      {series.Input{Title: " "}, series.ErrInvalidTitle},
+		"long title":       {series.Input{Title: strings.Repeat("a", 256)}, series.ErrInvalidTitle},
+		"long description": {series.Input{Title: "Series", Description: strings.Repeat("a", 1001)}, series.ErrInvalidDescription},
+	}
+	for name, tc := range cases {
+		if _, err := series.NewSeries(1, tc.in); err != tc.want {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestCheckOrder(t *testing.T) {
+	if err := series.CheckOrder([]int{3, 1, 2}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := series.CheckOrder(nil); err != nil {
+		t.Errorf("expected an empty series to be allowed, got %v", err)
+	}
+	if err := series.CheckOrder([]int{1, 2, 1}); err != series.ErrDuplicatePost {
+		t.Errorf("expected ErrDuplicatePost, got %v", err)
+	}
+	tooMany := make([]int, series.MaxPosts+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	if err := series.CheckOrder(tooMany); err != series.ErrTooManyPosts {
+		t.Errorf("expected ErrTooManyPosts, got %v", err)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	first, second, third := &post.Post{ID: 1}, &post.Post{ID: 2}, &post.Post{ID: 3}
+	entries := series.Navigate([]*post.Post{first, second, third})
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Previous != nil || entries[0].Next != second {
+		t.Errorf("expected the first post to lead to the second, got %+v", entries[0])
+	}
+	if entries[1].Previous != first || entries[1].Next != third {
+		t.Errorf("expected the second post between the others, got %+v", entries[1])
+	}
+	if entries[2].Previous != second || entries[2].Next != nil {
+		t.Errorf("expected the last post to end the series, got %+v", entries[2])
+	}
+	if entries := series.Navigate(nil); len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
@@ -0,0 +1,106 @@
+package sso_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/domain/user"
+)
+
+func validOIDCConnection() *sso.Connection {
+	return &sso.Connection{
+		Organization: "acme",
+		Protocol:     sso.ProtocolOIDC,
+		Domains:      []string{"acme.com"},
+		Issuer:       "https://login.acme.com",
+		ClientID:     "blog",
+		ClientSecret: "secret",
+	}
+}
+
+func TestConnection_Validate(t *testing.T) {
+	c := validOIDCConnection()
+	c.Domains = []string{" ACME.com ", "acme.com", "eu.acme.com"}
+	c.Issuer = "https://login.acme.com/"
+	c.Metadata = "<EntityDescriptor/>"
+	require.NoError(t, c.Validate())
+	assert.Equal(t, []string{"acme.com", "eu.acme.com"}, c.Domains, "domains are normalized and deduplicated")
+	assert.Equal(t, "https://login.acme.com", c.Issuer)
+	assert.Equal(t, sso.EnforcementOptional, c.Enforcement)
+	assert.Empty(t, c.Metadata, "settings of the other protocol are dropped")
+
+	saml := &sso.Connection{Organization: "acme", Protocol: sso.ProtocolSAML, Domains: []string{"acme.com"}, Metadata: "<EntityDescriptor/>", ClientSecret: "secret"}
+	require.NoError(t, saml.Validate())
+	assert.Empty(t, saml.ClientSecret)
+
+	tests := []struct {
+		name     string
+		modify   func(c *sso.Connection)
+		expected error
+	}{
+		{name: "organization with capitals", modify: func(c *sso.Connection) { c.Organization = "Acme" }, expected: sso.ErrInvalidOrganization},
+		{name: "organization too long", modify: func(c *sso.Connection) { c.Organization = strings.Repeat("a", 65) }, expected: sso.ErrInvalidOrganization},
+		{name: "no domains", modify: func(c *sso.Connection) { c.Domains = nil }, expected: sso.ErrInvalidDomain},
+		{name: "single label domain", modify: func(c *sso.Connection) { c.Domains = []string{"localhost"} }, expected: sso.ErrInvalidDomain},
+		{name: "domain with an address", modify: func(c *sso.Connection) { c.Domains = []string{"jane@acme.com"} }, expected: sso.ErrInvalidDomain},
+		{name: "unknown enforcement", modify: func(c *sso.Connection) { c.Enforcement = "always" }, expected: sso.ErrInvalidEnforcement},
+		{name: "unknown default role", modify: func(c *sso.Connection) { c.DefaultRole = "owner" }, expected: user.ErrInvalidRole},
+		{name: "unknown mapped role", modify: func(c *sso.Connection) { c.RoleMappings = map[string]user.Role{"admins": "owner"} }, expected: sso.ErrInvalidRoleMapping},
+		{name: "empty group", modify: func(c *sso.Connection) { c.RoleMappings = map[string]user.Role{"": user.RoleAdmin} }, expected: sso.ErrInvalidRoleMapping},
+		{name: "issuer without scheme", modify: func(c *sso.Connection) { c.Issuer = "login.acme.com" }, expected: sso.ErrIncompleteOIDC},
+		{name: "missing client secret", modify: func(c *sso.Connection) { c.ClientSecret = "" }, expected: sso.ErrIncompleteOIDC},
+		{name: "saml without metadata", modify: func(c *sso.Connection) { c.Protocol = sso.ProtocolSAML }, expected: sso.ErrIncompleteSAML},
+		{name: "unknown protocol", modify: func(c *sso.Connection) { c.Protocol = "ldap" }, expected: sso.ErrInvalidProtocol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validOIDCConnection()
+			tt.modify(c)
+			assert.ErrorIs(t, c.Validate(), tt.expected)
+		})
+	}
+}
+
+func TestConnection_Covers(t *testing.T) {
+	c := validOIDCConnection()
+	c.Domains = []string{"acme.com", "eu.acme.com"}
+
+	assert.True(t, c.Covers("jane@acme.com"))
+	assert.True(t, c.Covers("Jane@ACME.com"))
+	assert.True(t, c.Covers("jean@eu.acme.com"))
+	assert.False(t, c.Covers("jane@us.acme.com"), "subdomains must be listed")
+	assert.False(t, c.Covers("jane@acme.com.evil.com"))
+	assert.False(t, c.Covers("acme.com"))
+}
+
+func TestConnection_Requires(t *testing.T) {
+	c := validOIDCConnection()
+
+	c.Enforcement = sso.EnforcementOptional
+	assert.False(t, c.Requires(user.RoleAuthor))
+
+	c.Enforcement = sso.EnforcementRequired
+	assert.True(t, c.Requires(user.RoleAuthor))
+	assert.True(t, c.Requires(user.RoleAdmin))
+
+	c.Enforcement = sso.EnforcementRequiredExceptAdmins
+	assert.True(t, c.Requires(user.RoleReader))
+	assert.False(t, c.Requires(user.RoleAdmin))
+}
+
+func TestConnection_RoleFor(t *testing.T) {
+	c := validOIDCConnection()
+	c.RoleMappings = map[string]user.Role{"blog-admins": user.RoleAdmin, "writers": user.RoleAuthor, "everyone": user.RoleReader}
+
+	assert.Equal(t, user.RoleAdmin, c.RoleFor([]string{"everyone", "blog-admins", "writers"}), "the most privileged role wins")
+	assert.Equal(t, user.RoleAuthor, c.RoleFor([]string{"writers", "everyone"}))
+	assert.Equal(t, user.Role(""), c.RoleFor([]string{"sales"}), "without a default the role is left alone")
+
+	c.DefaultRole = user.RoleReader
+	assert.Equal(t, user.RoleReader, c.RoleFor(nil))
+}
@@ -0,0 +1,62 @@
+package tag_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/tag"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "lowercased", input: "GoLang", expected: "golang"},
+		{name: "words are hyphenated", input: "  Web   Development ", expected: "web-development"},
+		{name: "underscores and hyphens collapse", input: "web__development--tips", expected: "web-development-tips"},
+		{name: "non-ascii letters", input: "Café", expected: "café"},
+		{name: "empty", input: "   ", wantErr: true},
+		{name: "punctuation", input: "c++", wantErr: true},
+		{name: "too long", input: strings.Repeat("a", 51), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tag.Normalize(tt.input)
+			if tt.wantErr {
+				if err != tag.ErrInvalidTag {
+					t.Errorf("expected ErrInvalidTag, got %q (%v)", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("expected tag %q, got %q (%v)", tt.expected, got, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeNames(t *testing.T) {
+	names, err := tag.NormalizeNames([]string{"Golang", "web development", "golang", "Web-Development"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "golang,web-development" {
+		t.Errorf("expected duplicates to be dropped in order, got %v", names)
+	}
+
+	if _, err := tag.NormalizeNames([]string{"golang", ""}); err != tag.ErrInvalidTag {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+
+	tooMany := make([]string, tag.MaxPerPost+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	if _, err := tag.NormalizeNames(tooMany); err != tag.ErrTooManyTags {
+		t.Errorf("expected ErrTooManyTags, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package user_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/user"
+)
+
+func TestEmailNormalizer_Normalize(t *testing.T) {
+	normalizer := user.NewEmailNormalizer(user.DefaultCanonicalProviders)
+
+	tests := []struct {
+		name     string
+		email    string
+		expected string
+	}{
+		{"lowercases address", "John@Example.COM", "john@example.com"},
+		{"trims whitespace", "  john@example.com  ", "john@example.com"},
+		{"keeps dots for other providers", "john.doe@example.com", "john.doe@example.com"},
+		{"keeps plus-tags for other providers", "john+tag@example.com", "john+tag@example.com"},
+		{"strips gmail dots", "John.Doe@gmail.com", "johndoe@gmail.com"},
+		{"strips gmail plus-tag", "johndoe+news@gmail.com", "johndoe@gmail.com"},
+		{"maps googlemail to gmail", "j.doe+x@googlemail.com", "jdoe@gmail.com"},
+		{"leaves empty local part alone", "+tag@gmail.com", "+tag@gmail.com"},
+		{"leaves malformed address alone", "Not-An-Email", "not-an-email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizer.Normalize(tt.email); got != tt.expected {
+				t.Errorf("Normalize(%q) = %q, expected %q", tt.email, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmailNormalizer_NoProviders(t *testing.T) {
+	normalizer := user.NewEmailNormalizer(nil)
+
+	if got := normalizer.Normalize("John.Doe+x@Gmail.com"); got != "john.doe+x@gmail.com" {
+		t.Errorf("expected only lowercasing without providers, got %q", got)
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		valid bool
+	}{
+		{"john@example.com", true},
+		{"john.doe+tag@mail.example.co.uk", true},
+		{"", false},
+		{"not-an-email", false},
+		{"john@", false},
+		{"@example.com", false},
+		{"john@localhost", false},
+		{"john@example.", false},
+		{"john@.example.com", false},
+		{"John <john@example.com>", false},
+		{" john@example.com", false},
+	}
+
+	for _, tt := range tests {
+		err := user.ValidateEmail(tt.email)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateEmail(%q) = %v, expected nil", tt.email, err)
+		}
+		if !tt.valid && err != user.ErrInvalidEmail {
+			t.Errorf("ValidateEmail(%q) = %v, expected ErrInvalidEmail", tt.email, err)
+		}
+	}
+}
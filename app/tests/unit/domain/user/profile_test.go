@@ -0,0 +1,39 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/user"
+)
+
+func TestNormalizeProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		bio         string
+		avatarURL   string
+		expectedBio string
+		expectedURL string
+		expectedErr error
+	}{
+		{name: "empty profile"},
+		{name: "trimmed", bio: "  Writes about Go.\n", avatarURL: " https://images.example.com/1.png ", expectedBio: "Writes about Go.", expectedURL: "https://images.example.com/1.png"},
+		{name: "longest bio", bio: strings.Repeat("é", user.MaxBioLength), expectedBio: strings.Repeat("é", user.MaxBioLength)},
+		{name: "bio too long", bio: strings.Repeat("a", user.MaxBioLength+1), expectedErr: user.ErrBioTooLong},
+		{name: "http avatar", avatarURL: "http://images.example.com/1.png", expectedErr: user.ErrInvalidAvatarURL},
+		{name: "relative avatar", avatarURL: "/avatars/1.png", expectedErr: user.ErrInvalidAvatarURL},
+		{name: "script avatar", avatarURL: "javascript:alert(1)", expectedErr: user.ErrInvalidAvatarURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bio, avatarURL, err := user.NormalizeProfile(tt.bio, tt.avatarURL)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if bio != tt.expectedBio || avatarURL != tt.expectedURL {
+				t.Errorf("expected %q and %q, got %q and %q", tt.expectedBio, tt.expectedURL, bio, avatarURL)
+			}
+		})
+	}
+}
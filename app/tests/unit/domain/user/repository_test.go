@@ -43,6 +43,15 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*use
 	return nil, user.ErrUserNotFound
 }
 
+func (m *MockUserRepository) GetConflictedByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range m.users {
+		if u.EmailConflict && u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
 	if _, exists := m.users[u.ID]; !exists {
 		return user.ErrUserNotFound
@@ -0,0 +1,48 @@
+package user_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/user"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    user.Role
+		expectedErr error
+	}{
+		{name: "reader", input: "reader", expected: user.RoleReader},
+		{name: "author", input: "author", expected: user.RoleAuthor},
+		{name: "admin", input: "admin", expected: user.RoleAdmin},
+		{name: "unknown role", input: "owner", expectedErr: user.ErrInvalidRole},
+		{name: "empty role", input: "", expectedErr: user.ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := user.ParseRole(tt.input)
+			if err != tt.expectedErr {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if role != tt.expected {
+				t.Errorf("expected role %q, got %q", tt.expected, role)
+			}
+		})
+	}
+}
+
+func TestNewUser_DefaultRole(t *testing.T) {
+	u, err := user.NewUser("John Doe", "john@example.com", "password123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if u.Role != user.DefaultRole {
+		t.Errorf("expected role %q, got %q", user.DefaultRole, u.Role)
+	}
+	if u.Role.IsAdmin() {
+		t.Error("expected new users not to be admins")
+	}
+}
@@ -0,0 +1,58 @@
+package widget_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/widget"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   string
+		ok     bool
+	}{
+		{"https://example.com", "https://example.com", true},
+		{"HTTPS://Example.COM/", "https://example.com", true},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"https://example.com/page", "", false},
+		{"https://example.com?x=1", "", false},
+		{"https://user@example.com", "", false},
+		{"ftp://example.com", "", false},
+		{"example.com", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := widget.NormalizeOrigin(tt.origin)
+		assert.Equal(t, tt.ok, ok, tt.origin)
+		assert.Equal(t, tt.want, got, tt.origin)
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := widget.NewRegistry(map[string][]string{
+		"pk_blog":   {"https://Blog.example.com/", "not an origin"},
+		"pk_broken": {"https://example.com/page"},
+		"":          {"https://example.com"},
+	})
+
+	site, ok := r.Lookup("pk_blog")
+	require.True(t, ok)
+	assert.Equal(t, []string{"https://blog.example.com"}, site.Origins)
+	assert.True(t, site.AllowsOrigin("https://blog.example.com"))
+	assert.True(t, site.AllowsOrigin("https://BLOG.example.com"))
+	assert.False(t, site.AllowsOrigin("http://blog.example.com"))
+	assert.False(t, site.AllowsOrigin("https://blog.example.com.evil.test"))
+	assert.False(t, site.AllowsOrigin("null"))
+
+	// Sites left without a valid origin are dropped
+	_, ok = r.Lookup("pk_broken")
+	assert.False(t, ok)
+	_, ok = r.Lookup("")
+	assert.False(t, ok)
+	_, ok = r.Lookup("pk_unknown")
+	assert.False(t, ok)
+}
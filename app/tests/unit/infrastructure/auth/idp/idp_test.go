@@ -0,0 +1,372 @@
+package idp_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/sso"
+	"blog-platform/internal/infrastructure/auth/idp"
+)
+
+const (
+	idpEntityID = "https://idp.acme.com"
+	spEntityID  = "https://blog.example.com/api/v1/auth/sso/acme/metadata"
+	acsURL      = "https://blog.example.com/api/v1/auth/sso/acme/acs"
+	samlState   = "signed-state"
+)
+
+// samlIdentityProvider signs SAML responses the way an identity provider
+// does. Documents are written in canonical form, so the digest is taken over
+// the text as written.
+type samlIdentityProvider struct {
+	key      *rsa.PrivateKey
+	metadata string
+}
+
+func newSAMLIdentityProvider(t *testing.T) *samlIdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.acme.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	metadata := `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="` + idpEntityID + `">` +
+		`<md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">` +
+		`<md:KeyDescriptor use="signing"><ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data>` +
+		`<ds:X509Certificate>` + base64.StdEncoding.EncodeToString(der) + `</ds:X509Certificate>` +
+		`</ds:X509Data></ds:KeyInfo></md:KeyDescriptor>` +
+		`<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.acme.com/sso"/>` +
+		`</md:IDPSSODescriptor></md:EntityDescriptor>`
+
+	return &samlIdentityProvider{key: key, metadata: metadata}
+}
+
+// assertionOptions vary the assertion of a response
+type assertionOptions struct {
+	inResponseTo string
+	audience     string
+	notOnOrAfter time.Time
+}
+
+// response returns a base64 encoded response with a signed assertion
+func (p *samlIdentityProvider) response(t *testing.T, opts assertionOptions) string {
+	t.Helper()
+
+	if opts.notOnOrAfter.IsZero() {
+		opts.notOnOrAfter = time.Now().Add(5 * time.Minute)
+	}
+	notBefore := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	notOnOrAfter := opts.notOnOrAfter.UTC().Format(time.RFC3339)
+
+	issuer := `<saml:Issuer>` + idpEntityID + `</saml:Issuer>`
+	body := `<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">jane@acme.com</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">` +
+		`<saml:SubjectConfirmationData InResponseTo="` + opts.inResponseTo + `" NotOnOrAfter="` + notOnOrAfter + `" Recipient="` + acsURL + `"></saml:SubjectConfirmationData>` +
+		`</saml:SubjectConfirmation></saml:Subject>` +
+		`<saml:Conditions NotBefore="` + notBefore + `" NotOnOrAfter="` + notOnOrAfter + `">` +
+		`<saml:AudienceRestriction><saml:Audience>` + opts.audience + `</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AttributeStatement>` +
+		`<saml:Attribute Name="email"><saml:AttributeValue>jane@acme.com</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="displayName"><saml:AttributeValue>Jane Doe</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="groups"><saml:AttributeValue>blog-admins</saml:AttributeValue><saml:AttributeValue>everyone</saml:AttributeValue></saml:Attribute>` +
+		`</saml:AttributeStatement>`
+	open := `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_assertion" IssueInstant="` + notBefore + `" Version="2.0">`
+
+	digest := sha256.Sum256([]byte(open + issuer + body + `</saml:Assertion>`))
+	signedInfo := `<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#_assertion"><ds:Transforms>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"></ds:Transform>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:Transform>` +
+		`</ds:Transforms><ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue></ds:Reference>`
+	signed := sha256.Sum256([]byte(`<ds:SignedInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` + signedInfo + `</ds:SignedInfo>`))
+	value, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, signed[:])
+	require.NoError(t, err)
+	signature := `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:SignedInfo>` + signedInfo + `</ds:SignedInfo>` +
+		`<ds:SignatureValue>` + base64.StdEncoding.EncodeToString(value) + `</ds:SignatureValue></ds:Signature>`
+
+	response := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"` +
+		` Destination="` + acsURL + `" ID="_response" InResponseTo="` + opts.inResponseTo + `" IssueInstant="` + notBefore + `" Version="2.0">` +
+		issuer + `<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"></samlp:StatusCode></samlp:Status>` +
+		open + issuer + signature + body + `</saml:Assertion></samlp:Response>`
+	return base64.StdEncoding.EncodeToString([]byte(response))
+}
+
+// samlRequestID reads the ID of the AuthnRequest sent to the identity provider
+func samlRequestID(t *testing.T, provider *idp.SAMLProvider, state string) string {
+	t.Helper()
+
+	authURL, err := provider.AuthRequestURL(context.Background(), state)
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "idp.acme.com", parsed.Host)
+	assert.Equal(t, state, parsed.Query().Get("RelayState"))
+
+	deflated, err := base64.StdEncoding.DecodeString(parsed.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	request, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `AssertionConsumerServiceURL="`+acsURL+`"`)
+
+	match := regexp.MustCompile(` ID="([^"]+)"`).FindStringSubmatch(string(request))
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+func TestNewSAMLProvider_RejectsInvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+	}{
+		{name: "not xml", metadata: "metadata"},
+		{name: "no identity provider", metadata: `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.acme.com"></md:EntityDescriptor>`},
+		{name: "no signing certificate", metadata: `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.acme.com"><md:IDPSSODescriptor>` +
+			`<md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.acme.com/sso"/></md:IDPSSODescriptor></md:EntityDescriptor>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := idp.NewSAMLProvider(tt.metadata, "", spEntityID, acsURL)
+			assert.ErrorIs(t, err, sso.ErrInvalidMetadata)
+		})
+	}
+}
+
+func TestSAMLProvider_Verify(t *testing.T) {
+	identityProvider := newSAMLIdentityProvider(t)
+	provider, err := idp.NewSAMLProvider(identityProvider.metadata, "groups", spEntityID, acsURL)
+	require.NoError(t, err)
+	id := samlRequestID(t, provider, samlState)
+
+	t.Run("signed assertion", func(t *testing.T) {
+		response := identityProvider.response(t, assertionOptions{inResponseTo: id, audience: spEntityID})
+
+		assertion, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: response})
+		require.NoError(t, err)
+		assert.Equal(t, "jane@acme.com", assertion.Subject)
+		assert.Equal(t, "jane@acme.com", assertion.Email)
+		assert.Equal(t, "Jane Doe", assertion.Name)
+		assert.Equal(t, []string{"blog-admins", "everyone"}, assertion.Groups)
+	})
+
+	t.Run("tampered assertion", func(t *testing.T) {
+		response := identityProvider.response(t, assertionOptions{inResponseTo: id, audience: spEntityID})
+		raw, _ := base64.StdEncoding.DecodeString(response)
+		tampered := strings.Replace(string(raw), "<saml:AttributeValue>everyone", "<saml:AttributeValue>admins", 1)
+
+		_, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: base64.StdEncoding.EncodeToString([]byte(tampered))})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other := newSAMLIdentityProvider(t)
+		response := other.response(t, assertionOptions{inResponseTo: id, audience: spEntityID})
+
+		_, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: response})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+
+	t.Run("response to another sign-in", func(t *testing.T) {
+		response := identityProvider.response(t, assertionOptions{inResponseTo: id, audience: spEntityID})
+
+		_, err := provider.Verify(context.Background(), "other-state", sso.Response{SAMLResponse: response})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+
+	t.Run("assertion for another audience", func(t *testing.T) {
+		response := identityProvider.response(t, assertionOptions{inResponseTo: id, audience: "https://other.example.com"})
+
+		_, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: response})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+
+	t.Run("expired assertion", func(t *testing.T) {
+		response := identityProvider.response(t, assertionOptions{inResponseTo: id, audience: spEntityID, notOnOrAfter: time.Now().Add(-10 * time.Minute)})
+
+		_, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: response})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+
+	t.Run("malformed response", func(t *testing.T) {
+		_, err := provider.Verify(context.Background(), samlState, sso.Response{SAMLResponse: "not base64!"})
+		assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+	})
+}
+
+func TestServiceProviderMetadata(t *testing.T) {
+	metadata := string(idp.ServiceProviderMetadata(spEntityID, acsURL))
+
+	assert.Contains(t, metadata, `entityID="`+spEntityID+`"`)
+	assert.Contains(t, metadata, `Location="`+acsURL+`"`)
+	assert.Contains(t, metadata, `WantAssertionsSigned="true"`)
+}
+
+// oidcIdentityProvider serves discovery, token and key set endpoints. The
+// token endpoint answers every code with the ID token of the test.
+type oidcIdentityProvider struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	idToken string
+}
+
+func newOIDCIdentityProvider(t *testing.T) *oidcIdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p := &oidcIdentityProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "blog" || secret != "secret" || r.FormValue("code") != "valid" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// sign sets the ID token returned by the token endpoint
+func (p *oidcIdentityProvider) sign(t *testing.T, claims jwt.MapClaims) {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(p.key)
+	require.NoError(t, err)
+	p.idToken = signed
+}
+
+func oidcNonce(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestOIDCProvider_AuthRequestURL(t *testing.T) {
+	identityProvider := newOIDCIdentityProvider(t)
+	provider := idp.NewOIDCProvider(identityProvider.server.URL, "blog", "secret", "", "https://blog.example.com/callback", identityProvider.server.Client())
+
+	authURL, err := provider.AuthRequestURL(context.Background(), "state")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", parsed.Path)
+	assert.Equal(t, "blog", parsed.Query().Get("client_id"))
+	assert.Equal(t, "state", parsed.Query().Get("state"))
+	assert.Equal(t, oidcNonce("state"), parsed.Query().Get("nonce"))
+	assert.Equal(t, "https://blog.example.com/callback", parsed.Query().Get("redirect_uri"))
+}
+
+func TestOIDCProvider_Verify(t *testing.T) {
+	identityProvider := newOIDCIdentityProvider(t)
+	provider := idp.NewOIDCProvider(identityProvider.server.URL, "blog", "secret", "roles", "https://blog.example.com/callback", identityProvider.server.Client())
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            identityProvider.server.URL,
+			"aud":            "blog",
+			"sub":            "00u1",
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          oidcNonce("state"),
+			"email":          "jane@acme.com",
+			"email_verified": true,
+			"name":           "Jane Doe",
+			"roles":          []string{"blog-admins"},
+		}
+	}
+
+	t.Run("valid ID token", func(t *testing.T) {
+		identityProvider.sign(t, claims())
+
+		assertion, err := provider.Verify(context.Background(), "state", sso.Response{Code: "valid"})
+		require.NoError(t, err)
+		assert.Equal(t, "00u1", assertion.Subject)
+		assert.Equal(t, "jane@acme.com", assertion.Email)
+		assert.Equal(t, "Jane Doe", assertion.Name)
+		assert.Equal(t, []string{"blog-admins"}, assertion.Groups)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(c jwt.MapClaims)
+		code   string
+	}{
+		{name: "rejected code", modify: func(c jwt.MapClaims) {}, code: "invalid"},
+		{name: "another sign-in", modify: func(c jwt.MapClaims) { c["nonce"] = oidcNonce("other-state") }},
+		{name: "another client", modify: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "another issuer", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "unverified email", modify: func(c jwt.MapClaims) { c["email_verified"] = false }},
+		{name: "no email", modify: func(c jwt.MapClaims) { delete(c, "email") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := claims()
+			tt.modify(c)
+			identityProvider.sign(t, c)
+			code := tt.code
+			if code == "" {
+				code = "valid"
+			}
+
+			_, err := provider.Verify(context.Background(), "state", sso.Response{Code: code})
+			assert.ErrorIs(t, err, sso.ErrInvalidResponse)
+		})
+	}
+}
@@ -54,7 +54,7 @@ func TestJWTService_GenerateToken_Integration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := service.GenerateToken(tt.userID, tt.email, tt.duration)
+			token, err := service.GenerateToken(tt.userID, tt.email, "author", tt.duration)
 
 			if tt.expectError {
 				if err == nil {
@@ -97,7 +97,7 @@ func TestJWTService_ValidateToken_Integration(t *testing.T) {
 	service := infraAuth.NewJWTService("test-secret-key-for-jwt")
 
 	// Generate a valid token first
-	validToken, err := service.GenerateToken(1, "test@example.com", 24*time.Hour)
+	validToken, err := service.GenerateToken(1, "test@example.com", "author", 24*time.Hour)
 	if err != nil {
 		t.Fatalf("failed to generate test token: %v", err)
 	}
@@ -184,7 +184,7 @@ func TestJWTService_RefreshToken_Integration(t *testing.T) {
 	service := infraAuth.NewJWTService("test-secret-key-for-jwt")
 
 	// Generate a valid token first
-	validToken, err := service.GenerateToken(1, "test@example.com", 24*time.Hour)
+	validToken, err := service.GenerateToken(1, "test@example.com", "author", 24*time.Hour)
 	if err != nil {
 		t.Fatalf("failed to generate test token: %v", err)
 	}
@@ -262,7 +262,7 @@ func TestJWTService_InvalidSecretKey_Integration(t *testing.T) {
 	service := infraAuth.NewJWTService("")
 
 	// Test that operations fail with invalid secret key
-	_, err := service.GenerateToken(1, "test@example.com", 24*time.Hour)
+	_, err := service.GenerateToken(1, "test@example.com", "author", 24*time.Hour)
 	if err != auth.ErrInvalidSecretKey {
 		t.Errorf("expected ErrInvalidSecretKey, got %v", err)
 	}
@@ -278,7 +278,7 @@ func TestJWTService_CrossValidation_Integration(t *testing.T) {
 	service2 := infraAuth.NewJWTService("secret-key-2")
 
 	// Generate token with service1
-	token, err := service1.GenerateToken(1, "test@example.com", 24*time.Hour)
+	token, err := service1.GenerateToken(1, "test@example.com", "author", 24*time.Hour)
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -290,6 +290,36 @@ func TestJWTService_CrossValidation_Integration(t *testing.T) {
 	}
 }
 
+func TestJWTService_ChallengeToken_Integration(t *testing.T) {
+	service := infraAuth.NewJWTService("test-secret-key-for-jwt")
+
+	challenge, err := service.GenerateChallengeToken(7, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("failed to generate challenge token: %v", err)
+	}
+
+	userID, err := service.ValidateChallengeToken(challenge)
+	if err != nil || userID != 7 {
+		t.Errorf("expected user 7, got %d (%v)", userID, err)
+	}
+
+	// A challenge must not pass as an access token, nor the other way round
+	if _, err := service.ValidateToken(challenge); err != auth.ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for challenge used as access token, got %v", err)
+	}
+	access, err := service.GenerateToken(7, "test@example.com", "author", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	if _, err := service.ValidateChallengeToken(access); err != auth.ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for access token used as challenge, got %v", err)
+	}
+
+	if _, err := service.GenerateChallengeToken(0, time.Minute); err != auth.ErrInvalidUserID {
+		t.Errorf("expected ErrInvalidUserID, got %v", err)
+	}
+}
+
 func TestJWTService_Interface_Integration(t *testing.T) {
 	// Verify that JWTService implements the TokenService interface
 	var _ auth.TokenService = (*infraAuth.JWTService)(nil)
@@ -0,0 +1,60 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestMemoryLoginAttemptStore(t *testing.T) {
+	store := infraAuth.NewMemoryLoginAttemptStore()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		failures, err := store.RecordFailure(ctx, "user:1", time.Hour)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if failures != i {
+			t.Errorf("expected %d failures, got %d", i, failures)
+		}
+	}
+
+	failures, expiresAt, err := store.Failures(ctx, "user:1")
+	if err != nil || failures != 3 {
+		t.Errorf("expected 3 failures, got %d (%v)", failures, err)
+	}
+	if time.Until(expiresAt) < 59*time.Minute {
+		t.Errorf("expected count to expire a window after the last failure, got %v", expiresAt)
+	}
+
+	if failures, _, _ := store.Failures(ctx, "user:2"); failures != 0 {
+		t.Errorf("expected no failures for another key, got %d", failures)
+	}
+
+	if err := store.Reset(ctx, "user:1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if failures, _, _ := store.Failures(ctx, "user:1"); failures != 0 {
+		t.Errorf("expected failures to be reset, got %d", failures)
+	}
+}
+
+func TestMemoryLoginAttemptStore_Expiry(t *testing.T) {
+	store := infraAuth.NewMemoryLoginAttemptStore()
+	ctx := context.Background()
+
+	store.RecordFailure(ctx, "ip:203.0.113.7", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if failures, _, _ := store.Failures(ctx, "ip:203.0.113.7"); failures != 0 {
+		t.Errorf("expected expired count to be ignored, got %d", failures)
+	}
+
+	// Counting starts over after the window
+	if failures, _ := store.RecordFailure(ctx, "ip:203.0.113.7", time.Hour); failures != 1 {
+		t.Errorf("expected count to start over, got %d", failures)
+	}
+}
@@ -0,0 +1,145 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/auth/oauth"
+)
+
+// newProviderServer serves a token endpoint that accepts "good-code" and the
+// given API resources for the issued access token
+func newProviderServer(t *testing.T, resources map[string]any) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		if r.PostForm.Get("code") != "good-code" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "access-token", "token_type": "bearer"})
+	})
+	for path, body := range resources {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(body)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func endpoints(server *httptest.Server) oauth.Endpoints {
+	return oauth.Endpoints{
+		AuthURL:     server.URL + "/authorize",
+		TokenURL:    server.URL + "/token",
+		UserInfoURL: server.URL + "/user",
+		EmailsURL:   server.URL + "/user/emails",
+	}
+}
+
+var credentials = oauth.Credentials{ClientID: "client-id", ClientSecret: "client-secret"}
+
+func TestGoogleProvider_Identity(t *testing.T) {
+	server := newProviderServer(t, map[string]any{
+		"/user": map[string]any{"sub": "g-123", "email": "jane@example.com", "email_verified": true, "name": "Jane Doe"},
+	})
+	provider := oauth.NewGoogleProvider(credentials, endpoints(server), server.Client())
+
+	identity, err := provider.Identity(context.Background(), "good-code", "http://localhost/callback")
+	require.NoError(t, err)
+	assert.Equal(t, "google", identity.Provider)
+	assert.Equal(t, "g-123", identity.Subject)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Jane Doe", identity.Name)
+
+	_, err = provider.Identity(context.Background(), "bad-code", "http://localhost/callback")
+	assert.True(t, errors.Is(err, oauth.ErrExchangeFailed))
+}
+
+func TestGitHubProvider_Identity(t *testing.T) {
+	server := newProviderServer(t, map[string]any{
+		"/user": map[string]any{"id": 42, "login": "janedoe", "name": ""},
+		"/user/emails": []map[string]any{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "jane@example.com", "primary": true, "verified": false},
+		},
+	})
+	provider := oauth.NewGitHubProvider(credentials, endpoints(server), server.Client())
+
+	identity, err := provider.Identity(context.Background(), "good-code", "http://localhost/callback")
+	require.NoError(t, err)
+	assert.Equal(t, "github", identity.Provider)
+	assert.Equal(t, "42", identity.Subject)
+	assert.Equal(t, "janedoe", identity.Name)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.False(t, identity.EmailVerified, "only the primary email's verification counts")
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	provider := oauth.NewGoogleProvider(credentials, oauth.GoogleEndpoints, http.DefaultClient)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("the-state", "http://localhost/callback"))
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.google.com", authURL.Host)
+
+	query := authURL.Query()
+	assert.Equal(t, "client-id", query.Get("client_id"))
+	assert.Equal(t, "the-state", query.Get("state"))
+	assert.Equal(t, "http://localhost/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+}
+
+func TestStateSigner(t *testing.T) {
+	signer := oauth.NewStateSigner("test-secret", time.Minute)
+
+	state, err := signer.Issue("google")
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(state, "google"))
+
+	assert.ErrorIs(t, signer.Verify(state, "github"), oauth.ErrInvalidState, "state is bound to the provider")
+	assert.ErrorIs(t, oauth.NewStateSigner("other-secret", time.Minute).Verify(state, "google"), oauth.ErrInvalidState)
+	assert.ErrorIs(t, signer.Verify(state+"x", "google"), oauth.ErrInvalidState)
+	assert.ErrorIs(t, signer.Verify("garbage", "google"), oauth.ErrInvalidState)
+
+	expired, err := oauth.NewStateSigner("test-secret", -time.Minute).Issue("google")
+	require.NoError(t, err)
+	assert.ErrorIs(t, signer.Verify(expired, "google"), oauth.ErrInvalidState)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := oauth.NewRegistry(
+		oauth.NewGitHubProvider(credentials, oauth.GitHubEndpoints, http.DefaultClient),
+		oauth.NewGoogleProvider(credentials, oauth.GoogleEndpoints, http.DefaultClient),
+	)
+
+	assert.Equal(t, []string{"github", "google"}, registry.Names())
+
+	provider, err := registry.Get("github")
+	require.NoError(t, err)
+	assert.Equal(t, "github", provider.Name())
+
+	_, err = registry.Get("facebook")
+	assert.ErrorIs(t, err, oauth.ErrUnknownProvider)
+}
@@ -0,0 +1,58 @@
+package auth_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-platform/internal/domain/user"
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestReportSigner_RoundTrip(t *testing.T) {
+	signer := infraAuth.NewReportSigner("signing-key")
+	expiresAt := time.Now().Add(time.Hour)
+
+	token := signer.Issue(42, expiresAt)
+	userID, err := signer.Verify(token)
+	if err != nil || userID != 42 {
+		t.Errorf("expected user 42, got %d (%v)", userID, err)
+	}
+
+	if signer.Issue(43, expiresAt) == token {
+		t.Error("expected tokens to differ per user")
+	}
+}
+
+func TestReportSigner_RejectsExpiredTokens(t *testing.T) {
+	signer := infraAuth.NewReportSigner("signing-key")
+
+	token := signer.Issue(42, time.Now().Add(-time.Minute))
+	if _, err := signer.Verify(token); err != user.ErrInvalidReportToken {
+		t.Errorf("expected ErrInvalidReportToken, got %v", err)
+	}
+}
+
+func TestReportSigner_RejectsForgedTokens(t *testing.T) {
+	signer := infraAuth.NewReportSigner("signing-key")
+	other := infraAuth.NewReportSigner("other-key")
+	expiresAt := time.Now().Add(time.Hour)
+
+	valid := signer.Issue(42, expiresAt)
+	// Reusing a signature for another user or a later expiry must not verify
+	signature := valid[strings.LastIndex(valid, ".")+1:]
+	later := strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10)
+	tokens := []string{
+		other.Issue(42, expiresAt),
+		"43." + strings.Split(valid, ".")[1] + "." + signature,
+		"42." + later + "." + signature,
+		"42", "42.", "", "0." + signature,
+	}
+
+	for _, token := range tokens {
+		if _, err := signer.Verify(token); err != user.ErrInvalidReportToken {
+			t.Errorf("expected ErrInvalidReportToken for %q, got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package auth_test
+
+import (
+	"testing"
+
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestSCIMTenants_Authenticate(t *testing.T) {
+	tenants := infraAuth.NewSCIMTenants(map[string]string{"acme": "acme-token", "globex": "globex-token", "initech": ""})
+
+	if tenant, ok := tenants.Authenticate("globex-token"); !ok || tenant != "globex" {
+		t.Errorf("expected tenant globex, got %q (%v)", tenant, ok)
+	}
+	for _, token := range []string{"", "unknown", "acme-token "} {
+		if tenant, ok := tenants.Authenticate(token); ok {
+			t.Errorf("expected token %q to be rejected, got tenant %q", token, tenant)
+		}
+	}
+}
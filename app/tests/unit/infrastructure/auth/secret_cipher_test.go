@@ -0,0 +1,56 @@
+package auth_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/domain/auth"
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestSecretCipher_RoundTrip(t *testing.T) {
+	cipher, err := infraAuth.NewSecretCipher("encryption-key")
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+
+	encrypted, err := cipher.Encrypt("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+	if encrypted == "JBSWY3DPEHPK3PXP" {
+		t.Error("expected ciphertext to differ from plaintext")
+	}
+
+	// Every encryption uses a fresh nonce
+	again, _ := cipher.Encrypt("JBSWY3DPEHPK3PXP")
+	if again == encrypted {
+		t.Error("expected repeated encryptions to differ")
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil || decrypted != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("expected original secret, got %q (%v)", decrypted, err)
+	}
+}
+
+func TestSecretCipher_RejectsForeignCiphertext(t *testing.T) {
+	cipher, _ := infraAuth.NewSecretCipher("encryption-key")
+	other, _ := infraAuth.NewSecretCipher("other-key")
+
+	encrypted, err := other.Encrypt("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	for _, ciphertext := range []string{encrypted, "not base64!", ""} {
+		if _, err := cipher.Decrypt(ciphertext); err != infraAuth.ErrInvalidCiphertext {
+			t.Errorf("expected ErrInvalidCiphertext for %q, got %v", ciphertext, err)
+		}
+	}
+}
+
+func TestSecretCipher_RequiresKey(t *testing.T) {
+	if _, err := infraAuth.NewSecretCipher(""); err != auth.ErrInvalidSecretKey {
+		t.Errorf("expected ErrInvalidSecretKey, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"blog-platform/internal/domain/auth"
+	infraAuth "blog-platform/internal/infrastructure/auth"
+	"blog-platform/internal/infrastructure/config"
+)
+
+// pemKey encodes a private key, or its public half, as PKCS#8/PKIX PEM
+func pemKey(t *testing.T, key any, public bool) []byte {
+	t.Helper()
+	var block *pem.Block
+	if public {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatalf("failed to encode public key: %v", err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("failed to encode private key: %v", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+	return pem.EncodeToMemory(block)
+}
+
+func newEd25519Key(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return pub, priv
+}
+
+func TestParsePEMKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	edPub, edPriv := newEd25519Key(t)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		alg     string
+		canSign bool
+	}{
+		{"RSA private key", pemKey(t, rsaKey, false), "RS256", true},
+		{"RSA PKCS#1 private key", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), "RS256", true},
+		{"RSA public key", pemKey(t, &rsaKey.PublicKey, true), "RS256", false},
+		{"Ed25519 private key", pemKey(t, edPriv, false), "EdDSA", true},
+		{"Ed25519 public key", pemKey(t, edPub, true), "EdDSA", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := infraAuth.ParsePEMKey(tt.data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if key.Method.Alg() != tt.alg || key.CanSign() != tt.canSign {
+				t.Errorf("expected %s key (can sign: %v), got %s (%v)", tt.alg, tt.canSign, key.Method.Alg(), key.CanSign())
+			}
+			if key.ID == "" || key.JWK().KeyID != key.ID {
+				t.Errorf("expected key ID in JWK, got %q", key.JWK().KeyID)
+			}
+		})
+	}
+
+	// Private and public halves share the key ID
+	private, _ := infraAuth.ParsePEMKey(pemKey(t, edPriv, false))
+	public, _ := infraAuth.ParsePEMKey(pemKey(t, edPub, true))
+	if private.ID != public.ID {
+		t.Errorf("expected matching key IDs, got %q and %q", private.ID, public.ID)
+	}
+}
+
+func TestParsePEMKey_Rejected(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"not PEM":     []byte("secret"),
+		"certificate": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}),
+		"weak RSA":    pemKey(t, weak, false),
+	} {
+		if _, err := infraAuth.ParsePEMKey(data); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
+
+func TestJWTService_AsymmetricSigning(t *testing.T) {
+	_, edPriv := newEd25519Key(t)
+	signing, _ := infraAuth.ParsePEMKey(pemKey(t, edPriv, false))
+	service, err := infraAuth.NewJWTServiceWithKeys(signing)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	token, err := service.GenerateToken(1, "test@example.com", "author", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if parsed.Method.Alg() != "EdDSA" || parsed.Header["kid"] != signing.ID {
+		t.Errorf("expected EdDSA token with kid %s, got %s %v", signing.ID, parsed.Method.Alg(), parsed.Header["kid"])
+	}
+
+	claims, err := service.ValidateToken(token)
+	if err != nil || claims.UserID != 1 {
+		t.Errorf("expected valid token for user 1, got %+v (%v)", claims, err)
+	}
+
+	// A public key cannot sign
+	public, _ := infraAuth.ParsePEMKey(pemKey(t, edPriv.Public(), true))
+	if _, err := infraAuth.NewJWTServiceWithKeys(public); err != auth.ErrInvalidSecretKey {
+		t.Errorf("expected ErrInvalidSecretKey, got %v", err)
+	}
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	_, oldPriv := newEd25519Key(t)
+	_, newPriv := newEd25519Key(t)
+	oldKey, _ := infraAuth.ParsePEMKey(pemKey(t, oldPriv, false))
+	oldPublic, _ := infraAuth.ParsePEMKey(pemKey(t, oldPriv.Public(), true))
+	newKey, _ := infraAuth.ParsePEMKey(pemKey(t, newPriv, false))
+
+	before, _ := infraAuth.NewJWTServiceWithKeys(oldKey)
+	after, _ := infraAuth.NewJWTServiceWithKeys(newKey, oldPublic)
+	oldToken, _ := before.GenerateToken(1, "test@example.com", "author", time.Hour)
+
+	// Tokens signed with the previous key stay valid after the rotation
+	if _, err := after.ValidateToken(oldToken); err != nil {
+		t.Errorf("expected old token to be accepted, got %v", err)
+	}
+
+	// Once the old key is dropped its tokens are rejected
+	dropped, _ := infraAuth.NewJWTServiceWithKeys(newKey)
+	if _, err := dropped.ValidateToken(oldToken); err != auth.ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+
+	jwks := after.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].KeyID != newKey.ID || jwks.Keys[1].KeyID != oldKey.ID {
+		t.Errorf("expected signing key then previous key, got %+v", jwks.Keys)
+	}
+}
+
+func TestJWTService_RejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	publicPEM := pemKey(t, &rsaKey.PublicKey, true)
+	signing, _ := infraAuth.ParsePEMKey(pemKey(t, rsaKey, false))
+	service, _ := infraAuth.NewJWTServiceWithKeys(signing, infraAuth.NewHMACKey("secret"))
+
+	// An HS256 token keyed with the published public key must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{"blog-platform-api"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	forged.Header["kid"] = signing.ID
+	token, err := forged.SignedString(publicPEM)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	if _, err := service.ValidateToken(token); err != auth.ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+
+	// HMAC secrets are never published
+	if keys := service.JWKS().Keys; len(keys) != 1 || keys[0].KeyType != "RSA" {
+		t.Errorf("expected only the RSA key, got %+v", keys)
+	}
+}
+
+func TestNewJWTServiceFromConfig(t *testing.T) {
+	_, priv := newEd25519Key(t)
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "signing.pem")
+	if err := os.WriteFile(keyFile, pemKey(t, priv, false), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	legacy := infraAuth.NewJWTService("jwt-secret")
+	legacyToken, _ := legacy.GenerateToken(1, "test@example.com", "author", time.Hour)
+
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "jwt-secret", SigningKeyFile: keyFile}}
+	service, err := infraAuth.NewJWTServiceFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	if _, err := service.ValidateToken(legacyToken); err != auth.ErrInvalidToken {
+		t.Errorf("expected HS256 tokens to be rejected, got %v", err)
+	}
+
+	cfg.JWT.AcceptHS256 = true
+	service, _ = infraAuth.NewJWTServiceFromConfig(cfg)
+	if _, err := service.ValidateToken(legacyToken); err != nil {
+		t.Errorf("expected HS256 token to be accepted, got %v", err)
+	}
+
+	cfg.JWT.SigningKeyFile = filepath.Join(dir, "missing.pem")
+	if _, err := infraAuth.NewJWTServiceFromConfig(cfg); err == nil {
+		t.Error("expected error for missing key file")
+	}
+}
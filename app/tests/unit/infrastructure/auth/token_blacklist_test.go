@@ -0,0 +1,72 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestMemoryTokenBlacklist(t *testing.T) {
+	blacklist := infraAuth.NewMemoryTokenBlacklist()
+	ctx := context.Background()
+
+	if err := blacklist.Add(ctx, "active", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := blacklist.Add(ctx, "expired", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tokenID  string
+		expected bool
+	}{
+		{name: "blacklisted token", tokenID: "active", expected: true},
+		{name: "already expired token is not kept", tokenID: "expired", expected: false},
+		{name: "unknown token", tokenID: "unknown", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := blacklist.Contains(ctx, tt.tokenID)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if found != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, found)
+			}
+		})
+	}
+}
+
+func TestJWTService_TokenID(t *testing.T) {
+	service := infraAuth.NewJWTService("test-secret-key-for-jwt")
+
+	first, err := service.GenerateToken(1, "test@example.com", "author", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	second, err := service.GenerateToken(1, "test@example.com", "author", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	firstClaims, err := service.ValidateToken(first)
+	if err != nil {
+		t.Fatalf("failed to validate token: %v", err)
+	}
+	secondClaims, err := service.ValidateToken(second)
+	if err != nil {
+		t.Fatalf("failed to validate token: %v", err)
+	}
+
+	if firstClaims.ID == "" {
+		t.Error("expected token ID to be set")
+	}
+	if firstClaims.ID == secondClaims.ID {
+		t.Error("expected each token to get a unique ID")
+	}
+}
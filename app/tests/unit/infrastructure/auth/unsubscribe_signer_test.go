@@ -0,0 +1,39 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/domain/announcement"
+	infraAuth "blog-platform/internal/infrastructure/auth"
+)
+
+func TestUnsubscribeSigner_RoundTrip(t *testing.T) {
+	signer := infraAuth.NewUnsubscribeSigner("signing-key")
+
+	token := signer.Issue(42)
+	userID, err := signer.Verify(token)
+	if err != nil || userID != 42 {
+		t.Errorf("expected user 42, got %d (%v)", userID, err)
+	}
+
+	if signer.Issue(43) == token {
+		t.Error("expected tokens to differ per user")
+	}
+}
+
+func TestUnsubscribeSigner_RejectsForgedTokens(t *testing.T) {
+	signer := infraAuth.NewUnsubscribeSigner("signing-key")
+	other := infraAuth.NewUnsubscribeSigner("other-key")
+
+	valid := signer.Issue(42)
+	// Reusing a signature for another user must not verify
+	_, signature, _ := strings.Cut(valid, ".")
+	tokens := []string{other.Issue(42), "43." + signature, "42", "42.", "", "0." + signature}
+
+	for _, token := range tokens {
+		if _, err := signer.Verify(token); err != announcement.ErrInvalidUnsubscribeToken {
+			t.Errorf("expected ErrInvalidUnsubscribeToken for %q, got %v", token, err)
+		}
+	}
+}
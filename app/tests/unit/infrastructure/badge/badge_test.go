@@ -0,0 +1,52 @@
+package badge_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/badge"
+)
+
+func TestCount(t *testing.T) {
+	cases := map[int64]string{
+		0:          "0",
+		999:        "999",
+		1000:       "1k",
+		1250:       "1.2k",
+		1999:       "1.9k",
+		99_999:     "99.9k",
+		100_000:    "100k",
+		999_999:    "999k",
+		1_000_000:  "1M",
+		3_450_000:  "3.4M",
+		25_000_000: "25M",
+	}
+	for n, want := range cases {
+		assert.Equal(t, want, badge.Count(n), "count %d", n)
+	}
+}
+
+func TestRender(t *testing.T) {
+	body := badge.Render("views", "1.2k")
+
+	var svg struct {
+		XMLName xml.Name `xml:"svg"`
+		Width   int      `xml:"width,attr"`
+		Title   string   `xml:"title"`
+		Texts   []string `xml:"g>text"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &svg))
+	assert.Equal(t, "views: 1.2k", svg.Title)
+	assert.Equal(t, []string{"views", "1.2k"}, svg.Texts)
+
+	// Longer values widen the badge
+	wider := badge.Render("views", "999.9k")
+	var other struct {
+		Width int `xml:"width,attr"`
+	}
+	require.NoError(t, xml.Unmarshal(wider, &other))
+	assert.Greater(t, other.Width, svg.Width)
+}
@@ -0,0 +1,90 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-platform/internal/infrastructure/cache"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := cache.NewMemoryCache(10)
+	ctx := context.Background()
+
+	if _, found, err := c.Get(ctx, "post:1"); found || err != nil {
+		t.Fatalf("expected a miss, got found=%v (%v)", found, err)
+	}
+
+	value := []byte("cached")
+	if err := c.Set(ctx, "post:1", value, time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	value[0] = 'X'
+	got, found, err := c.Get(ctx, "post:1")
+	if !found || err != nil {
+		t.Fatalf("expected a hit, got found=%v (%v)", found, err)
+	}
+	if string(got) != "cached" {
+		t.Errorf("expected the stored copy, got %q", got)
+	}
+
+	if err := c.Delete(ctx, "post:1", "post:2"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, found, _ := c.Get(ctx, "post:1"); found {
+		t.Error("expected deleted key to miss")
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c := cache.NewMemoryCache(10)
+	ctx := context.Background()
+
+	c.Set(ctx, "post:1", []byte("cached"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, found, _ := c.Get(ctx, "post:1"); found {
+		t.Error("expected expired key to miss")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected expired entry to be evicted, got %d entries", c.Len())
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewMemoryCache(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, found, _ := c.Get(ctx, "b"); found {
+		t.Error("expected the least recently used key to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found, _ := c.Get(ctx, key); !found {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestMetered_CountsHitsAndMissesPerGroup(t *testing.T) {
+	c := cache.NewMetered(cache.NewMemoryCache(10))
+	ctx := context.Background()
+
+	c.Get(ctx, "post:1")
+	c.Set(ctx, "post:1", []byte("cached"), time.Minute)
+	c.Get(ctx, "post:1")
+	c.Get(ctx, "post:1")
+	c.Get(ctx, "user:email:jane@example.com")
+
+	stats := c.Stats()
+	if stats["post"] != (cache.Counts{Hits: 2, Misses: 1}) {
+		t.Errorf("expected 2 hits and 1 miss for posts, got %+v", stats["post"])
+	}
+	if stats["user"] != (cache.Counts{Misses: 1}) {
+		t.Errorf("expected 1 miss for users, got %+v", stats["user"])
+	}
+}
@@ -0,0 +1,185 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"blog-platform/internal/infrastructure/database/migrations"
+)
+
+func TestLoad_EmbeddedFiles(t *testing.T) {
+	loaded, err := migrations.Load(migrations.Files)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	assert.Equal(t, 1, loaded[0].Version)
+	assert.Equal(t, "000001_create_users_table", loaded[0].String())
+	for i, m := range loaded {
+		assert.Equal(t, i+1, m.Version, "versions must have no gaps")
+		assert.NotEmpty(t, migrations.Split(m.Up), "migration %s has no up statements", m)
+		assert.NotEmpty(t, migrations.Split(m.Down), "migration %s has no down statements", m)
+	}
+}
+
+func TestLoad_PostgresFilesMirrorMySQL(t *testing.T) {
+	mysql, err := migrations.Load(migrations.Files)
+	require.NoError(t, err)
+	postgres, err := migrations.Load(migrations.PostgresFiles)
+	require.NoError(t, err)
+
+	require.Len(t, postgres, len(mysql), "every migration needs a PostgreSQL counterpart")
+	for i, m := range postgres {
+		assert.Equal(t, mysql[i].String(), m.String())
+		assert.NotEmpty(t, migrations.Split(m.Up), "migration %s has no up statements", m)
+		assert.NotEmpty(t, migrations.Split(m.Down), "migration %s has no down statements", m)
+	}
+}
+
+func TestLoad_SQLiteFilesMirrorMySQL(t *testing.T) {
+	mysql, err := migrations.Load(migrations.Files)
+	require.NoError(t, err)
+	sqlite, err := migrations.Load(migrations.SQLiteFiles)
+	require.NoError(t, err)
+
+	require.Len(t, sqlite, len(mysql), "every migration needs a SQLite counterpart")
+	for i, m := range sqlite {
+		assert.Equal(t, mysql[i].String(), m.String())
+		assert.NotEmpty(t, migrations.Split(m.Up), "migration %s has no up statements", m)
+		assert.NotEmpty(t, migrations.Split(m.Down), "migration %s has no down statements", m)
+	}
+}
+
+func TestMigrator_SQLiteUpDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "blog.db")+"?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrations.New(db, "sqlite")
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	all, err := migrations.Load(migrations.SQLiteFiles)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	// Every down migration undoes its up migration, so the schema can be
+	// built again
+	reverted, err := migrator.Down(ctx, len(all))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(all))
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	// Edits touch updated_at; counting views does not
+	_, err = db.ExecContext(ctx, `UPDATE posts SET updated_at = '2000-01-01 00:00:00' WHERE id = 1`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE posts SET view_count = view_count + 1 WHERE id = 1`)
+	require.NoError(t, err)
+	var updatedAt string
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT updated_at FROM posts WHERE id = 1`).Scan(&updatedAt))
+	assert.Equal(t, "2000-01-01T00:00:00Z", updatedAt)
+	_, err = db.ExecContext(ctx, `UPDATE posts SET title = 'Edited' WHERE id = 1`)
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT updated_at FROM posts WHERE id = 1`).Scan(&updatedAt))
+	assert.NotEqual(t, "2000-01-01T00:00:00Z", updatedAt)
+}
+
+func TestLoad_Validation(t *testing.T) {
+	valid := fstest.MapFS{
+		"000002_add_posts.up.sql":      {Data: []byte("CREATE TABLE posts (id INT);")},
+		"000002_add_posts.down.sql":    {Data: []byte("DROP TABLE posts;")},
+		"000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);")},
+		"000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+	loaded, err := migrations.Load(valid)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "create_users", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE posts;", loaded[1].Down)
+
+	cases := map[string]fstest.MapFS{
+		"missing down": {
+			"000001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id INT);")},
+		},
+		"bad version": {
+			"first_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);")},
+			"first_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		},
+		"bad direction": {
+			"000001_create_users.sql": {Data: []byte("CREATE TABLE users (id INT);")},
+		},
+		"mismatched names": {
+			"000001_create_users.up.sql":    {Data: []byte("CREATE TABLE users (id INT);")},
+			"000001_create_people.down.sql": {Data: []byte("DROP TABLE users;")},
+		},
+	}
+	for name, fsys := range cases {
+		_, err := migrations.Load(fsys)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	script := `-- Users; with a semicolon in a comment
+CREATE TABLE users (
+    id INT PRIMARY KEY, -- the ID; unique
+    name VARCHAR(255) DEFAULT 'a;b' # hash comment; too
+);
+/* block; comment */
+INSERT INTO users (id, name) VALUES (1, 'O''Brien; Jr.'), (2, "say \"hi\"; bye");
+UPDATE users SET name = ` + "`name`" + `;
+`
+	statements := migrations.Split(script)
+	require.Len(t, statements, 3)
+	assert.True(t, strings.HasPrefix(statements[0], "CREATE TABLE users ("))
+	assert.Contains(t, statements[0], "DEFAULT 'a;b'")
+	assert.NotContains(t, statements[0], "comment")
+	assert.Equal(t, `INSERT INTO users (id, name) VALUES (1, 'O''Brien; Jr.'), (2, "say \"hi\"; bye")`, statements[1])
+	assert.Equal(t, "UPDATE users SET name = `name`", statements[2])
+
+	assert.Empty(t, migrations.Split("-- only a comment\n;\n"))
+}
+
+func TestSplit_DollarQuotes(t *testing.T) {
+	script := `CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+SELECT $body$a;b$body$, $1;
+`
+	statements := migrations.Split(script)
+	require.Len(t, statements, 2)
+	assert.True(t, strings.HasSuffix(statements[0], "$$ LANGUAGE plpgsql"))
+	assert.Contains(t, statements[0], "RETURN NEW;\nEND;")
+	assert.Equal(t, "SELECT $body$a;b$body$, $1", statements[1])
+}
+
+func TestSplit_TriggerBodies(t *testing.T) {
+	script := `CREATE TRIGGER users_touch AFTER UPDATE ON users
+    FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid;
+END;
+CREATE TRIGGER jobs_touch BEFORE UPDATE ON jobs
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+DROP TRIGGER users_touch;
+`
+	statements := migrations.Split(script)
+	require.Len(t, statements, 3)
+	assert.True(t, strings.HasSuffix(statements[0], "NEW.rowid;\nEND"))
+	assert.True(t, strings.HasSuffix(statements[1], "set_updated_at()"))
+	assert.Equal(t, "DROP TRIGGER users_touch", statements[2])
+}
@@ -0,0 +1,108 @@
+package diagnostics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/config"
+	"blog-platform/internal/infrastructure/diagnostics"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	collector := diagnostics.NewCollector()
+	collector.Register("cache", func(ctx context.Context) (any, error) {
+		return map[string]int{"hits": 1}, nil
+	})
+	collector.Register("queue", func(ctx context.Context) (any, error) {
+		return nil, errors.New("database unavailable")
+	})
+	collector.Register("deadline", func(ctx context.Context) (any, error) {
+		_, ok := ctx.Deadline()
+		return ok, nil
+	})
+
+	report := collector.Collect(context.Background())
+
+	assert.Equal(t, map[string]int{"hits": 1}, report.Subsystems["cache"])
+	assert.Equal(t, true, report.Subsystems["deadline"], "sources are given a deadline")
+	assert.NotContains(t, report.Subsystems, "queue")
+	assert.Equal(t, map[string]string{"queue": "database unavailable"}, report.Errors)
+	assert.Positive(t, report.Runtime.Goroutines)
+	assert.Positive(t, report.Runtime.Memory.Sys)
+	assert.NotEmpty(t, report.Uptime)
+}
+
+func TestCollector_Register_Replaces(t *testing.T) {
+	collector := diagnostics.NewCollector()
+	collector.Register("cache", func(ctx context.Context) (any, error) { return "old", nil })
+	collector.Register("cache", func(ctx context.Context) (any, error) { return "new", nil })
+
+	report := collector.Collect(context.Background())
+	assert.Equal(t, map[string]any{"cache": "new"}, report.Subsystems)
+}
+
+func TestConfigFingerprints(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Port: "8080"},
+		Database: config.DatabaseConfig{Host: "db", Password: "hunter2", DSN: "root:hunter2@tcp(db:3306)/blog"},
+		JWT:      config.JWTConfig{Secret: "jwt-secret", AccessTokenTTL: 15},
+	}
+
+	fingerprints, err := diagnostics.ConfigFingerprints(cfg)
+	require.NoError(t, err)
+	assert.Len(t, fingerprints["Server"], 12)
+	assert.Contains(t, fingerprints, "Publishing", "every section is fingerprinted")
+
+	// Settings change the fingerprint of their section only
+	changed := *cfg
+	changed.JWT.AccessTokenTTL = 30
+	other, err := diagnostics.ConfigFingerprints(&changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprints["JWT"], other["JWT"])
+	assert.Equal(t, fingerprints["Server"], other["Server"])
+
+	// Secret values do not affect fingerprints, only whether they are set
+	changed = *cfg
+	changed.JWT.Secret = "another-secret"
+	changed.Database.Password = "hunter3"
+	other, err = diagnostics.ConfigFingerprints(&changed)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprints, other)
+
+	changed.Database.Password = ""
+	other, err = diagnostics.ConfigFingerprints(&changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprints["Database"], other["Database"])
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := &config.Config{
+		Database:  config.DatabaseConfig{User: "root", Password: "hunter2", DSN: "root:hunter2@tcp(db:3306)/blog"},
+		JWT:       config.JWTConfig{Secret: "jwt-secret"},
+		Mail:      config.MailConfig{SMTPUsername: "mailer", SMTPPassword: "smtp-password"},
+		OAuth:     config.OAuthConfig{GoogleClientID: "google-id", GoogleClientSecret: "google-secret"},
+		TwoFactor: config.TwoFactorConfig{EncryptionKey: "totp-key"},
+		SCIM:      config.SCIMConfig{Tenants: map[string]string{"acme": "scim-token"}},
+		Billing:   config.BillingConfig{StripeWebhookSecret: "whsec-secret"},
+		Tips:      config.TipsConfig{StripeSecretKey: "sk-secret", StripeWebhookSecret: "whsec-tips"},
+	}
+
+	redacted := cfg.Redacted()
+	data, err := json.Marshal(redacted)
+	require.NoError(t, err)
+
+	for _, secret := range []string{"hunter2", "jwt-secret", "smtp-password", "google-secret", "totp-key", "scim-token", "whsec-secret", "sk-secret", "whsec-tips"} {
+		assert.NotContains(t, string(data), secret)
+	}
+	assert.Equal(t, "root", redacted.Database.User)
+	assert.Equal(t, "google-id", redacted.OAuth.GoogleClientID)
+	assert.Empty(t, redacted.Redis.Password, "unset secrets stay empty")
+	assert.Contains(t, redacted.SCIM.Tenants, "acme")
+	assert.Equal(t, "hunter2", cfg.Database.Password, "the configuration itself is unchanged")
+	assert.Equal(t, "scim-token", cfg.SCIM.Tenants["acme"], "the configuration itself is unchanged")
+}
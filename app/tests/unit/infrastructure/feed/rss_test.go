@@ -0,0 +1,100 @@
+package feed_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/feed"
+)
+
+func TestRender(t *testing.T) {
+	published := time.Date(2024, 1, 15, 11, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	body, err := feed.Render(feed.Channel{
+		Title:       "Comments on Hello & Welcome",
+		Link:        "https://blog.example.com/p/hello",
+		Description: "The latest comments",
+		SelfURL:     "https://blog.example.com/api/v1/posts/1/comments/feed.xml",
+		Items: []feed.Item{{
+			Title:       "Jane on Hello: <b>Great</b>",
+			Link:        "https://blog.example.com/p/hello#comment-1",
+			GUID:        "https://blog.example.com/p/hello#comment-1",
+			Author:      "Jane",
+			Description: "<b>Great</b> post",
+			PublishedAt: published,
+		}},
+	})
+	require.NoError(t, err)
+
+	doc := string(body)
+	assert.True(t, strings.HasPrefix(doc, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, doc, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+	assert.Contains(t, doc, `<title>Comments on Hello &amp; Welcome</title>`)
+	assert.Contains(t, doc, `<atom:link href="https://blog.example.com/api/v1/posts/1/comments/feed.xml" rel="self" type="application/rss+xml"></atom:link>`)
+	assert.Contains(t, doc, `<lastBuildDate>Mon, 15 Jan 2024 10:00:00 +0000</lastBuildDate>`)
+	assert.Contains(t, doc, `<guid isPermaLink="true">https://blog.example.com/p/hello#comment-1</guid>`)
+	assert.Contains(t, doc, `<dc:creator>Jane</dc:creator>`)
+	assert.Contains(t, doc, `<description>&lt;b&gt;Great&lt;/b&gt; post</description>`)
+	assert.Contains(t, doc, `<pubDate>Mon, 15 Jan 2024 10:00:00 +0000</pubDate>`)
+}
+
+func TestRender_Empty(t *testing.T) {
+	body, err := feed.Render(feed.Channel{Title: "Comments", Link: "https://blog.example.com/"})
+	require.NoError(t, err)
+
+	doc := string(body)
+	assert.NotContains(t, doc, "atom:link")
+	assert.NotContains(t, doc, "lastBuildDate")
+	assert.NotContains(t, doc, "<item>")
+}
+
+func TestRenderAtom(t *testing.T) {
+	published := time.Date(2024, 1, 15, 11, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	body, err := feed.RenderAtom(feed.Channel{
+		Title:       "Posts by Ada & Co",
+		Link:        "https://blog.example.com/",
+		Description: "The latest posts",
+		SelfURL:     "https://blog.example.com/feeds/authors/1.atom",
+		Items: []feed.Item{{
+			Title:       "Hello",
+			Link:        "https://blog.example.com/p/hello",
+			GUID:        "https://blog.example.com/p/hello",
+			Author:      "Ada",
+			Description: "<p>Hello <b>world</b></p>",
+			Categories:  []string{"go"},
+			PublishedAt: published,
+		}},
+	})
+	require.NoError(t, err)
+
+	doc := string(body)
+	assert.True(t, strings.HasPrefix(doc, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, doc, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, doc, `<id>https://blog.example.com/feeds/authors/1.atom</id>`)
+	assert.Contains(t, doc, `<title>Posts by Ada &amp; Co</title>`)
+	assert.Contains(t, doc, `<subtitle>The latest posts</subtitle>`)
+	assert.Contains(t, doc, `<link href="https://blog.example.com/" rel="alternate" type="text/html"></link>`)
+	assert.Contains(t, doc, `<link href="https://blog.example.com/feeds/authors/1.atom" rel="self" type="application/atom+xml"></link>`)
+	assert.Contains(t, doc, `<updated>2024-01-15T10:00:00Z</updated>`)
+	assert.Contains(t, doc, `<id>https://blog.example.com/p/hello</id>`)
+	assert.Contains(t, doc, `<author>`+"\n"+`      <name>Ada</name>`)
+	assert.Contains(t, doc, `<category term="go"></category>`)
+	assert.Contains(t, doc, `<published>2024-01-15T10:00:00Z</published>`)
+	assert.Contains(t, doc, `<content type="html">&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</content>`)
+}
+
+func TestRenderAtom_Empty(t *testing.T) {
+	body, err := feed.RenderAtom(feed.Channel{Title: "Posts", Link: "https://blog.example.com/"})
+	require.NoError(t, err)
+
+	doc := string(body)
+	assert.Contains(t, doc, `<id>https://blog.example.com/</id>`, "feeds without a self URL are identified by their link")
+	assert.NotContains(t, doc, `rel="self"`)
+	assert.Contains(t, doc, "<updated>")
+	assert.NotContains(t, doc, "<entry>")
+}
@@ -0,0 +1,59 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/health"
+)
+
+func TestChecker_Check(t *testing.T) {
+	checker := health.NewChecker()
+	checker.Register("database", func(ctx context.Context) error {
+		return nil
+	})
+	checker.Register("redis", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+
+	assert.False(t, report.Ready())
+	assert.Equal(t, health.StatusError, report.Status)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+	assert.Empty(t, report.Checks["database"].Error)
+
+	redis := report.Checks["redis"]
+	assert.Equal(t, health.StatusError, redis.Status)
+	assert.Equal(t, "unavailable", redis.Error, "errors are not exposed")
+	assert.EqualError(t, redis.Err(), "connection refused")
+
+	slow := report.Checks["slow"]
+	assert.Equal(t, "timeout", slow.Error)
+	assert.GreaterOrEqual(t, slow.LatencyMs, float64(900), "checks are bounded by a timeout")
+}
+
+func TestChecker_Ready(t *testing.T) {
+	checker := health.NewChecker()
+	assert.True(t, checker.Check(context.Background()).Ready(), "an instance without checks is ready")
+
+	checker.Register("database", func(ctx context.Context) error {
+		return errors.New("down")
+	})
+	checker.Register("database", func(ctx context.Context) error {
+		return nil
+	})
+
+	report := checker.Check(context.Background())
+	assert.True(t, report.Ready(), "registering a name again replaces its check")
+	assert.Len(t, report.Checks, 1)
+}
@@ -0,0 +1,102 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+// trickyInputs seed the fuzzers with inputs that have broken naive
+// sanitizers: nested and split tags, null bytes, mixed scripts, bidi
+// overrides and invalid UTF-8
+var trickyInputs = []string{
+	"",
+	"Hello, World!",
+	"<script>alert(1)</script>",
+	"<<script>script>alert(1)<</script>/script>",
+	"<scr<script>ipt>alert(1)</script>",
+	"<img src=x onerror=alert(1)",
+	"a < b and c > d",
+	"1 <2> 3",
+	"<\x00script>",
+	"null\x00byte",
+	"\x00\x00 padded \x00",
+	"  \t\n spaced \r\n ",
+	"Привет мир 你好 مرحبا",
+	"‮gnp.exe",
+	"zero​width",
+	"\xff\xfe invalid utf-8",
+	"title with　wide spaces",
+}
+
+// sanitizedInput is the request payload the validators run against
+type sanitizedInput struct {
+	Title string `validate:"no_html,safe_string"`
+}
+
+func FuzzSanitizeInput(f *testing.F) {
+	for _, input := range trickyInputs {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		sanitized := middleware.SanitizeInput(input)
+
+		if strings.ContainsRune(sanitized, 0) {
+			t.Errorf("null byte kept in %q", sanitized)
+		}
+		if sanitized != strings.TrimSpace(sanitized) {
+			t.Errorf("surrounding whitespace kept in %q", sanitized)
+		}
+		if again := middleware.SanitizeInput(sanitized); again != sanitized {
+			t.Errorf("sanitizing twice changed %q to %q", sanitized, again)
+		}
+	})
+}
+
+func FuzzValidateNoHTML(f *testing.F) {
+	v := middleware.NewValidator()
+	for _, input := range trickyInputs {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		type payload struct {
+			Content string `validate:"no_html"`
+		}
+		accepted := v.Validate(payload{Content: input}) == nil
+
+		// Anything that could close a tag after opening one is rejected
+		open := strings.IndexByte(input, '<')
+		hasTag := open != -1 && strings.IndexByte(input[open:], '>') != -1
+		if accepted == hasTag {
+			t.Errorf("no_html accepted=%v for %q", accepted, input)
+		}
+	})
+}
+
+func FuzzValidateSafeString(f *testing.F) {
+	v := middleware.NewValidator()
+	for _, input := range trickyInputs {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		title := middleware.SanitizeInput(input)
+		if v.Validate(sanitizedInput{Title: title}) != nil {
+			return
+		}
+
+		// Accepted titles are printable ASCII apart from line breaks and tabs
+		for _, r := range title {
+			if r > unicode.MaxASCII {
+				t.Fatalf("safe_string accepted non-ASCII %q", title)
+			}
+			if unicode.IsControl(r) && !strings.ContainsRune("\t\n\f\r", r) {
+				t.Fatalf("safe_string accepted control character %q in %q", r, title)
+			}
+		}
+	})
+}
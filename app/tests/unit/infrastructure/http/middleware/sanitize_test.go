@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"blog-platform/internal/infrastructure/http/middleware"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		policy   middleware.SanitizePolicy
+		expected string
+	}{
+		{"strict collapses whitespace", "  My \t first\r\n\r\npost  ", middleware.PolicyStrict, "My first post"},
+		{"strict removes control characters", "ti\x00tle\x07", middleware.PolicyStrict, "title"},
+		{"unknown policies are strict", "a\nb", middleware.SanitizePolicy("other"), "a b"},
+		{"basic keeps paragraphs", "First line  \r\nsecond\r\n\r\nThird\x00", middleware.PolicyBasic, "First line\nsecond\n\nThird"},
+		{"basic removes control characters", "a\x1b[31m\tb", middleware.PolicyBasic, "a[31m\tb"},
+		{"markdown keeps indentation", "# Title\r\n\r\n    code\n\nline  \nbreak\x00\n", middleware.PolicyMarkdown, "# Title\n\n    code\n\nline  \nbreak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := middleware.Sanitize(tt.input, tt.policy); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeFields(t *testing.T) {
+	type request struct {
+		Title   string `sanitize:"strict"`
+		Content string `sanitize:"markdown"`
+		Token   string
+		Count   int `sanitize:"strict"`
+	}
+	req := request{Title: " A\ntitle ", Content: "Body  \r\n", Token: " raw ", Count: 3}
+
+	middleware.SanitizeFields(&req)
+
+	expected := request{Title: "A title", Content: "Body", Token: " raw ", Count: 3}
+	if req != expected {
+		t.Errorf("expected %+v, got %+v", expected, req)
+	}
+
+	// Values other than struct pointers are ignored
+	middleware.SanitizeFields(req)
+	middleware.SanitizeFields(nil)
+}
+
+func FuzzSanitizePolicies(f *testing.F) {
+	policies := []middleware.SanitizePolicy{middleware.PolicyStrict, middleware.PolicyBasic, middleware.PolicyMarkdown}
+	for _, input := range trickyInputs {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, policy := range policies {
+			sanitized := middleware.Sanitize(input, policy)
+
+			if strings.ContainsRune(sanitized, 0) || strings.ContainsRune(sanitized, '\r') {
+				t.Errorf("%s kept a null byte or carriage return in %q", policy, sanitized)
+			}
+			if again := middleware.Sanitize(sanitized, policy); again != sanitized {
+				t.Errorf("%s sanitizing twice changed %q to %q", policy, sanitized, again)
+			}
+			if policy == middleware.PolicyMarkdown {
+				continue
+			}
+			for _, r := range sanitized {
+				if unicode.IsControl(r) && (policy == middleware.PolicyStrict || r != '\n' && r != '\t') {
+					t.Errorf("%s kept control character %q in %q", policy, r, sanitized)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package views_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/http/views"
+)
+
+func TestLoadTheme_Default(t *testing.T) {
+	theme, err := views.LoadTheme(views.DefaultThemeName, "")
+	require.NoError(t, err)
+
+	asset, ok := theme.Asset("style.css")
+	require.True(t, ok)
+	assert.Contains(t, asset.ContentType, "text/css")
+	assert.Equal(t, "/assets/default/style.css?v="+asset.Hash, theme.AssetURL("style.css"))
+}
+
+func TestLoadTheme_OverrideChangesHash(t *testing.T) {
+	themesDir := t.TempDir()
+	writeFile(t, themesDir, "dark/static/style.css", "body { background: #000; }")
+
+	base, err := views.LoadTheme(views.DefaultThemeName, "")
+	require.NoError(t, err)
+	dark, err := views.LoadTheme("dark", themesDir)
+	require.NoError(t, err)
+
+	baseCSS, _ := base.Asset("style.css")
+	darkCSS, ok := dark.Asset("style.css")
+	require.True(t, ok)
+	assert.Equal(t, "body { background: #000; }", string(darkCSS.Content))
+	assert.NotEqual(t, baseCSS.Hash, darkCSS.Hash)
+}
+
+func TestLoadTheme_Errors(t *testing.T) {
+	themesDir := t.TempDir()
+	writeFile(t, themesDir, "calls/templates/not_found.html", `{{define "content"}}{{call .Title}}{{end}}`)
+	writeFile(t, themesDir, "broken/templates/not_found.html", `{{define "content"}}{{.Title{{end}}`)
+	writeFile(t, themesDir, "funcs/templates/not_found.html", `{{define "content"}}{{exec "ls"}}{{end}}`)
+	writeFile(t, themesDir, "huge/templates/not_found.html", strings.Repeat("x", 65<<10))
+
+	tests := []struct {
+		name  string
+		theme string
+		err   error
+	}{
+		{name: "unknown theme", theme: "missing", err: views.ErrUnknownTheme},
+		{name: "path traversal", theme: "../etc", err: views.ErrInvalidThemeName},
+		{name: "call builtin", theme: "calls", err: views.ErrUnsafeTemplate},
+		{name: "oversized template", theme: "huge", err: views.ErrUnsafeTemplate},
+		{name: "parse error", theme: "broken"},
+		{name: "undefined function", theme: "funcs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := views.LoadTheme(tt.theme, themesDir)
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewRenderer_UnknownHostTheme(t *testing.T) {
+	_, err := views.NewRenderer(views.Options{
+		HostThemes: map[string]string{"blog.acme.com": "acme"},
+	})
+	assert.ErrorIs(t, err, views.ErrUnknownTheme)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
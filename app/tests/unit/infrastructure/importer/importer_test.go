@@ -0,0 +1,215 @@
+package importer_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/imports"
+	"blog-platform/internal/infrastructure/importer"
+)
+
+const wxrExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>My Old Blog</title>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<wp:author>
+		<wp:author_id>1</wp:author_id>
+		<wp:author_login><![CDATA[jane]]></wp:author_login>
+		<wp:author_email><![CDATA[jane@example.com]]></wp:author_email>
+		<wp:author_display_name><![CDATA[Jane Doe]]></wp:author_display_name>
+	</wp:author>
+	<item>
+		<title>Hello &amp; welcome</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[<!-- wp:paragraph -->
+<p>Welcome to <strong>my blog</strong>. Read the <a href="https://example.com/about">about page</a>&nbsp;first.</p>
+<!-- /wp:paragraph -->
+<h2>Topics</h2>
+<ul><li>Go</li><li>Databases</li></ul>
+<pre><code>func main() {
+	fmt.Println("hi")
+}</code></pre>
+<script>alert(1)</script>
+Plain text paragraph.]]></content:encoded>
+		<excerpt:encoded><![CDATA[An excerpt]]></excerpt:encoded>
+		<wp:post_date><![CDATA[2019-05-01 10:30:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2019-05-01 08:30:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[hello-welcome]]></wp:post_name>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="uncategorized"><![CDATA[Uncategorized]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+		<wp:comment>
+			<wp:comment_id>7</wp:comment_id>
+			<wp:comment_author><![CDATA[Alice]]></wp:comment_author>
+			<wp:comment_date_gmt><![CDATA[2019-05-02 09:00:00]]></wp:comment_date_gmt>
+			<wp:comment_content><![CDATA[Great <em>post</em>!]]></wp:comment_content>
+			<wp:comment_approved><![CDATA[1]]></wp:comment_approved>
+			<wp:comment_type><![CDATA[comment]]></wp:comment_type>
+			<wp:comment_parent>0</wp:comment_parent>
+			<wp:comment_user_id>0</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>8</wp:comment_id>
+			<wp:comment_author><![CDATA[Jane Doe]]></wp:comment_author>
+			<wp:comment_date_gmt><![CDATA[2019-05-02 10:00:00]]></wp:comment_date_gmt>
+			<wp:comment_content><![CDATA[Thanks!]]></wp:comment_content>
+			<wp:comment_approved><![CDATA[0]]></wp:comment_approved>
+			<wp:comment_type><![CDATA[]]></wp:comment_type>
+			<wp:comment_parent>7</wp:comment_parent>
+			<wp:comment_user_id>1</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>9</wp:comment_id>
+			<wp:comment_author><![CDATA[Other Blog]]></wp:comment_author>
+			<wp:comment_content><![CDATA[Pingback]]></wp:comment_content>
+			<wp:comment_approved><![CDATA[1]]></wp:comment_approved>
+			<wp:comment_type><![CDATA[pingback]]></wp:comment_type>
+			<wp:comment_parent>0</wp:comment_parent>
+		</wp:comment>
+	</item>
+	<item>
+		<title>Work in progress</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[Not done yet.]]></content:encoded>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[]]></wp:post_name>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+	<item>
+		<title>About</title>
+		<content:encoded><![CDATA[About me]]></content:encoded>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[page]]></wp:post_type>
+	</item>
+	<item>
+		<title>Deleted</title>
+		<content:encoded><![CDATA[Gone]]></content:encoded>
+		<wp:status><![CDATA[trash]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestParse_WordPress(t *testing.T) {
+	doc, err := importer.NewParser().Parse([]byte(wxrExport))
+	require.NoError(t, err)
+
+	assert.Equal(t, imports.SourceWordPress, doc.Source)
+	assert.Equal(t, []imports.Author{{Login: "jane", Name: "Jane Doe", Email: "jane@example.com"}}, doc.Authors)
+	require.Len(t, doc.Posts, 2, "pages and trashed posts are left out")
+
+	hello := doc.Posts[0]
+	assert.Equal(t, "Hello & welcome", hello.Title)
+	assert.Equal(t, "hello-welcome", hello.Slug)
+	assert.Equal(t, "jane", hello.AuthorLogin)
+	assert.False(t, hello.Draft)
+	require.NotNil(t, hello.PublishedAt)
+	assert.Equal(t, time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC), *hello.PublishedAt)
+	assert.Equal(t, []string{"Go"}, hello.Tags)
+	assert.Equal(t, "Welcome to **my blog**. Read the [about page](https://example.com/about) first.\n\n"+
+		"## Topics\n\n"+
+		"- Go\n- Databases\n\n"+
+		"```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n"+
+		"Plain text paragraph.", hello.Content)
+
+	require.Len(t, hello.Comments, 2, "pingbacks are left out")
+	assert.Equal(t, imports.Comment{
+		ID:         "7",
+		AuthorName: "Alice",
+		Content:    "Great *post*!",
+		Approved:   true,
+		CreatedAt:  time.Date(2019, 5, 2, 9, 0, 0, 0, time.UTC),
+	}, hello.Comments[0])
+	assert.Equal(t, "7", hello.Comments[1].ParentID)
+	assert.Equal(t, "jane", hello.Comments[1].AuthorLogin)
+	assert.False(t, hello.Comments[1].Approved)
+
+	draft := doc.Posts[1]
+	assert.True(t, draft.Draft)
+	assert.Nil(t, draft.PublishedAt)
+	assert.Empty(t, draft.Slug)
+}
+
+func markdownZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		f.Write([]byte(content))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParse_MarkdownZip(t *testing.T) {
+	data := markdownZip(t, map[string]string{
+		"posts/2024-01-15-first.md": "---\ntitle: First post\ndate: 2024-01-15T10:00:00+01:00\ntags: [go, web]\nauthor: Jane Doe\nauthor_email: jane@example.com\n---\n\nHello **world**.\n",
+		"posts/second.markdown":     "# Second post\n\nNo front matter here.\n",
+		"posts/third.md":            "---\ntitle: Third\nslug: custom-slug\ndraft: true\nauthor: Jane Doe\nauthor_email: jane@example.com\n---\nDraft content.",
+		"posts/image.png":           "not markdown",
+		"__MACOSX/posts/._third.md": "resource fork",
+	})
+
+	doc, err := importer.NewParser().Parse(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, imports.SourceMarkdown, doc.Source)
+	assert.Equal(t, []imports.Author{{Login: "jane@example.com", Name: "Jane Doe", Email: "jane@example.com"}}, doc.Authors)
+	require.Len(t, doc.Posts, 3)
+
+	first := doc.Posts[0]
+	assert.Equal(t, "First post", first.Title)
+	assert.Equal(t, "2024-01-15-first", first.Slug)
+	assert.Equal(t, "Hello **world**.", first.Content)
+	assert.Equal(t, []string{"go", "web"}, first.Tags)
+	assert.Equal(t, "jane@example.com", first.AuthorLogin)
+	require.NotNil(t, first.PublishedAt)
+	assert.Equal(t, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC), *first.PublishedAt)
+
+	second := doc.Posts[1]
+	assert.Equal(t, "Second post", second.Title, "the first heading is the title")
+	assert.Equal(t, "No front matter here.", second.Content)
+	assert.Empty(t, second.AuthorLogin)
+
+	third := doc.Posts[2]
+	assert.Equal(t, "custom-slug", third.Slug)
+	assert.True(t, third.Draft)
+	assert.Nil(t, third.PublishedAt)
+}
+
+func TestParse_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"plain text", []byte("just some text"), imports.ErrUnsupportedFile},
+		{"other XML", []byte(`<?xml version="1.0"?><feed></feed>`), imports.ErrUnsupportedFile},
+		{"RSS feed", []byte(`<rss><channel><item><title>x</title></item></channel></rss>`), imports.ErrUnsupportedFile},
+		{"truncated export", []byte(wxrExport[:len(wxrExport)/2]), imports.ErrInvalidFile},
+		{"export without posts", []byte(`<rss><channel><wp:wxr_version>1.2</wp:wxr_version></channel></rss>`), imports.ErrNoPosts},
+		{"zip without Markdown", markdownZip(t, map[string]string{"notes.txt": "hello"}), imports.ErrNoPosts},
+		{"unterminated front matter", markdownZip(t, map[string]string{"a.md": "---\ntitle: A\n\nBody"}), imports.ErrInvalidFile},
+		{"malformed front matter", markdownZip(t, map[string]string{"a.md": "---\ntitle: [A\n---\nBody"}), imports.ErrInvalidFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.NewParser().Parse(tt.data)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package markdown_test
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+var (
+	// tagPattern finds the tags in rendered HTML
+	tagPattern = regexp.MustCompile(`<[^>]*>`)
+	// allowedTagPattern lists every tag the renderer may produce
+	allowedTagPattern = regexp.MustCompile(`^<(/?(p|h[1-6]|ul|ol|li|blockquote|pre|code|strong|em|a)|hr|a href="[^"<>]*" rel="nofollow noopener")>$`)
+	// hrefPattern finds link targets in rendered HTML
+	hrefPattern = regexp.MustCompile(`href="([^"]*)"`)
+	// schemePattern matches the scheme of an absolute URL
+	schemePattern = regexp.MustCompile(`^([a-z][a-z0-9+.\-]*):`)
+)
+
+func FuzzToHTML(f *testing.F) {
+	for _, source := range []string{
+		"# Title\n\nSome **bold**, *italic* and `code`.",
+		"<script>alert(1)</script>",
+		"<<script>script>alert(1)<</script>/script>",
+		"**<b>nested</b> *tags***",
+		"[click](javascript:alert(1))",
+		"[click](JaVaScRiPt:alert(1))",
+		"[click]( javascript:alert(1))",
+		"[click](//evil.example.com)",
+		"[click](\x01javascript:alert(1))",
+		"[click](\x01//evil.example.com)",
+		"[top](#section:1)",
+		"[click](data:text/html,<script>alert(1)</script>)",
+		"[a*b](http://example.com/*x*)",
+		"[`code`](http://example.com)",
+		"[x](http://example.com\" onmouseover=\"alert(1))",
+		"```\n<script>alert(1)</script>\n```",
+		"`unterminated <b>",
+		"> quote\n> > nested\n- item\n1. first",
+		"null\x00byte",
+		"Привет мир 你好 مرحبا ‮",
+		"\xff\xfe invalid utf-8",
+		"####### too deep",
+	} {
+		f.Add(source)
+	}
+
+	f.Fuzz(func(t *testing.T, source string) {
+		rendered := markdown.ToHTML(source)
+
+		// Source markup is always escaped: only the renderer's own tags appear
+		for _, tag := range tagPattern.FindAllString(rendered, -1) {
+			if !allowedTagPattern.MatchString(tag) {
+				t.Fatalf("unexpected tag %q rendering %q:\n%s", tag, source, rendered)
+			}
+		}
+		if strings.Count(rendered, "<") != len(tagPattern.FindAllString(rendered, -1)) {
+			t.Fatalf("unescaped < rendering %q:\n%s", source, rendered)
+		}
+
+		// Browsers skip leading spaces and control characters before the scheme
+		for _, m := range hrefPattern.FindAllStringSubmatch(rendered, -1) {
+			target := strings.ToLower(strings.TrimLeftFunc(html.UnescapeString(m[1]), func(r rune) bool { return r <= ' ' }))
+			scheme := schemePattern.FindStringSubmatch(target)
+			if strings.HasPrefix(target, "//") || scheme != nil && scheme[1] != "http" && scheme[1] != "https" && scheme[1] != "mailto" {
+				t.Fatalf("unsafe link target %q rendering %q", m[1], source)
+			}
+		}
+	})
+}
+
+// sanitizedTagPattern lists every tag Sanitize may produce
+var sanitizedTagPattern = regexp.MustCompile(`^<(/?(p|h[1-6]|ul|ol|li|blockquote|pre|code|strong|em|a)|hr|br|a( href="[^"<>]*")? rel="nofollow noopener")>$`)
+
+func FuzzSanitize(f *testing.F) {
+	for _, fragment := range []string{
+		"<p>Some <strong>bold</strong> text</p>",
+		"<script>alert(1)</script>",
+		"<scr<script>ipt>alert(1)</script>",
+		`<img src=x onerror="alert(1)">`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`<a href=" &#106;avascript:alert(1)">x</a>`,
+		`<svg><script>alert(1)</script></svg>`,
+		`<math><mi xlink:href="javascript:alert(1)">x</mi></math>`,
+		"<!--><script>alert(1)</script>-->",
+		"<textarea><script>alert(1)</script></textarea>",
+		"<p title=\"</p><script>\">x</p>",
+		markdown.ToHTML("# Title\n\n[link](https://example.com)"),
+	} {
+		f.Add(fragment)
+	}
+
+	f.Fuzz(func(t *testing.T, fragment string) {
+		sanitized := markdown.Sanitize(fragment)
+
+		for _, tag := range tagPattern.FindAllString(sanitized, -1) {
+			if !sanitizedTagPattern.MatchString(tag) {
+				t.Fatalf("unexpected tag %q sanitizing %q:\n%s", tag, fragment, sanitized)
+			}
+		}
+		if strings.Count(sanitized, "<") != len(tagPattern.FindAllString(sanitized, -1)) {
+			t.Fatalf("unescaped < sanitizing %q:\n%s", fragment, sanitized)
+		}
+
+		for _, m := range hrefPattern.FindAllStringSubmatch(sanitized, -1) {
+			target := strings.ToLower(strings.TrimLeftFunc(html.UnescapeString(m[1]), func(r rune) bool { return r <= ' ' }))
+			scheme := schemePattern.FindStringSubmatch(target)
+			if strings.HasPrefix(target, "//") || scheme != nil && scheme[1] != "http" && scheme[1] != "https" && scheme[1] != "mailto" {
+				t.Fatalf("unsafe link target %q sanitizing %q", m[1], fragment)
+			}
+		}
+	})
+}
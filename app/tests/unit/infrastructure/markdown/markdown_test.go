@@ -0,0 +1,101 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "paragraphs",
+			source:   "First line\ncontinues here.\n\nSecond paragraph.",
+			expected: "<p>First line continues here.</p>\n<p>Second paragraph.</p>\n",
+		},
+		{
+			name:     "heading",
+			source:   "## Section title",
+			expected: "<h2>Section title</h2>\n",
+		},
+		{
+			name:     "inline formatting",
+			source:   "Some **bold**, *italic* and `code`.",
+			expected: "<p>Some <strong>bold</strong>, <em>italic</em> and <code>code</code>.</p>\n",
+		},
+		{
+			name:     "unordered list",
+			source:   "- one\n- two",
+			expected: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n",
+		},
+		{
+			name:     "ordered list",
+			source:   "1. first\n2. second",
+			expected: "<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			name:     "blockquote",
+			source:   "> quoted\n> text",
+			expected: "<blockquote><p>quoted text</p></blockquote>\n",
+		},
+		{
+			name:     "fenced code block",
+			source:   "```\nif a < b {\n\treturn\n}\n```",
+			expected: "<pre><code>if a &lt; b {\n\treturn\n}</code></pre>\n",
+		},
+		{
+			name:     "link",
+			source:   "See [the docs](https://example.com/docs?a=1&b=2).",
+			expected: "<p>See <a href=\"https://example.com/docs?a=1&amp;b=2\" rel=\"nofollow noopener\">the docs</a>.</p>\n",
+		},
+		{
+			name:     "code spans are not formatted",
+			source:   "`**not bold**`",
+			expected: "<p><code>**not bold**</code></p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.ToHTML(tt.source); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestToHTML_EscapesHTML(t *testing.T) {
+	got := markdown.ToHTML(`<script>alert("x")</script> and <b onclick="x">bold</b>`)
+
+	if strings.Contains(got, "<script>") || strings.Contains(got, "<b ") {
+		t.Errorf("expected raw HTML to be escaped, got %q", got)
+	}
+	if !strings.Contains(got, "&lt;script&gt;") {
+		t.Errorf("expected escaped script tag, got %q", got)
+	}
+}
+
+func TestToHTML_RejectsUnsafeLinks(t *testing.T) {
+	sources := []string{
+		"[click](javascript:alert(1))",
+		"[click](JavaScript:alert(1))",
+		"[click](data:text/html;base64,PHNjcmlwdD4=)",
+		"[click](//evil.example.com)",
+	}
+
+	for _, source := range sources {
+		got := markdown.ToHTML(source)
+		if strings.Contains(got, "<a ") {
+			t.Errorf("expected unsafe link in %q to be dropped, got %q", source, got)
+		}
+	}
+
+	if got := markdown.ToHTML("[next](/p/next-post)"); !strings.Contains(got, `<a href="/p/next-post"`) {
+		t.Errorf("expected relative link to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"blog-platform/internal/infrastructure/markdown"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		expected string
+	}{
+		{
+			name:     "allowed elements are kept",
+			fragment: "<h2>Title</h2>\n<p>Some <strong>bold</strong> and <em>italic</em> text<br>with a break.</p>\n<hr>",
+			expected: "<h2>Title</h2>\n<p>Some <strong>bold</strong> and <em>italic</em> text<br>with a break.</p>\n<hr>",
+		},
+		{
+			name:     "scripts and styles are removed with their content",
+			fragment: `<p>Hi<script>alert(1)</script><style>p{}</style></p>`,
+			expected: "<p>Hi</p>",
+		},
+		{
+			name:     "other elements are unwrapped",
+			fragment: `<div class="x"><span>Text</span> <img src="x" onerror="alert(1)"></div>`,
+			expected: "Text ",
+		},
+		{
+			name:     "attributes are dropped",
+			fragment: `<p onclick="alert(1)" style="color:red">Text</p>`,
+			expected: "<p>Text</p>",
+		},
+		{
+			name:     "links keep safe targets",
+			fragment: `<a href="https://example.com/?a=1&amp;b=2" target="_blank" onclick="x">docs</a>`,
+			expected: `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow noopener">docs</a>`,
+		},
+		{
+			name:     "links lose unsafe targets",
+			fragment: `<a href="javascript:alert(1)">click</a>`,
+			expected: `<a rel="nofollow noopener">click</a>`,
+		},
+		{
+			name:     "comments are removed and text stays escaped",
+			fragment: "<!-- hidden --><p>a &lt; b &amp;&amp; c</p>",
+			expected: "<p>a &lt; b &amp;&amp; c</p>",
+		},
+		{
+			name:     "unclosed elements are closed",
+			fragment: "<p><strong>open",
+			expected: "<p><strong>open</strong></p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.Sanitize(tt.fragment); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRender_KeepsRendererOutput(t *testing.T) {
+	source := "# Title\n\nSome **bold**, *italic* and `a < b`.\n\n- one\n- [two](https://example.com/?q=\"x\")\n\n> quoted\n\n```\n<div>code</div>\n```\n\n---"
+
+	if got, expected := markdown.Render(source), markdown.ToHTML(source); got != expected {
+		t.Errorf("expected the sanitizer to keep the rendered HTML\nexpected %q\ngot      %q", expected, got)
+	}
+}
+
+func TestRenderBasic(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "inline formatting is rendered",
+			source:   "Some **bold**, *italic*, `code` and a [link](https://example.com).",
+			expected: "<p>Some <strong>bold</strong>, <em>italic</em>, <code>code</code> and a <a href=\"https://example.com\" rel=\"nofollow noopener\">link</a>.</p>\n",
+		},
+		{
+			name:     "paragraphs and line breaks are kept",
+			source:   "First line\nsecond line\n\nNew paragraph",
+			expected: "<p>First line<br>second line</p>\n<p>New paragraph</p>\n",
+		},
+		{
+			name:     "block syntax is shown as written",
+			source:   "# Not a heading\n- not a list",
+			expected: "<p># Not a heading<br>- not a list</p>\n",
+		},
+		{
+			name:     "HTML is shown as text",
+			source:   "<b>x</b><script>alert(1)</script>",
+			expected: "<p>&lt;b&gt;x&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		},
+		{
+			name:     "unsafe links are not linked",
+			source:   "[click](javascript:alert(1))",
+			expected: "<p>click)</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.RenderBasic(tt.source); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/openapi"
+)
+
+const testSwagger = `{
+    "swagger": "2.0",
+    "info": {"title": "Blog", "version": "1.0"},
+    "host": "api.example.com",
+    "basePath": "/api/v1/",
+    "schemes": ["https"],
+    "paths": {
+        "/posts": {
+            "get": {
+                "produces": ["application/json"],
+                "parameters": [
+                    {"type": "array", "items": {"type": "string"}, "collectionFormat": "multi", "name": "tag", "in": "query"},
+                    {"type": "integer", "default": 10, "name": "limit", "in": "query"}
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {"type": "array", "items": {"$ref": "#/definitions/post.Post"}},
+                        "headers": {"X-Total-Count": {"type": "integer", "description": "Total posts"}}
+                    }
+                }
+            },
+            "post": {
+                "security": [{"BearerAuth": []}],
+                "consumes": ["application/json"],
+                "parameters": [
+                    {"description": "Post", "name": "post", "in": "body", "required": true, "schema": {"$ref": "#/definitions/post.CreateRequest"}}
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {"$ref": "#/definitions/post.Post"},
+                        "examples": {"application/json": {"id": 1}}
+                    }
+                }
+            }
+        },
+        "/media": {
+            "post": {
+                "parameters": [
+                    {"type": "file", "description": "File", "name": "file", "in": "formData", "required": true},
+                    {"type": "string", "name": "alt", "in": "formData"}
+                ],
+                "responses": {"201": {"description": "Created"}}
+            }
+        }
+    },
+    "definitions": {
+        "post.Post": {"type": "object", "properties": {"author": {"$ref": "#/definitions/user.User"}}},
+        "post.CreateRequest": {"type": "object"},
+        "user.User": {"type": "object"}
+    },
+    "securityDefinitions": {
+        "BearerAuth": {"type": "apiKey", "name": "Authorization", "in": "header"},
+        "Basic": {"type": "basic"},
+        "OAuth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://example.com/auth", "tokenUrl": "https://example.com/token"}
+    }
+}`
+
+// convert converts the test document and decodes the result
+func convert(t *testing.T) map[string]any {
+	t.Helper()
+	body, err := openapi.Convert([]byte(testSwagger))
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(body, &doc))
+	return doc
+}
+
+// get follows a path of keys and indexes into a decoded document
+func get(t *testing.T, value any, path ...any) any {
+	t.Helper()
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			m, ok := value.(map[string]any)
+			require.True(t, ok, "expected an object at %q", k)
+			value = m[k]
+		case int:
+			l, ok := value.([]any)
+			require.True(t, ok, "expected an array at %d", k)
+			require.Greater(t, len(l), k)
+			value = l[k]
+		}
+	}
+	return value
+}
+
+func TestConvert(t *testing.T) {
+	doc := convert(t)
+
+	assert.Equal(t, openapi.Version, doc["openapi"])
+	assert.Equal(t, "Blog", get(t, doc, "info", "title"))
+	assert.Equal(t, "https://api.example.com/api/v1", get(t, doc, "servers", 0, "url"))
+	assert.NotContains(t, doc, "definitions")
+
+	t.Run("parameters", func(t *testing.T) {
+		tag := get(t, doc, "paths", "/posts", "get", "parameters", 0).(map[string]any)
+		assert.Equal(t, "tag", tag["name"])
+		assert.Equal(t, "form", tag["style"])
+		assert.Equal(t, true, tag["explode"])
+		assert.NotContains(t, tag, "type")
+		assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, tag["schema"])
+
+		limit := get(t, doc, "paths", "/posts", "get", "parameters", 1).(map[string]any)
+		assert.Equal(t, map[string]any{"type": "integer", "default": float64(10)}, limit["schema"])
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		ok := get(t, doc, "paths", "/posts", "get", "responses", "200")
+		assert.Equal(t, "#/components/schemas/post.Post", get(t, ok, "content", "application/json", "schema", "items", "$ref"))
+		assert.Equal(t, map[string]any{"type": "integer"}, get(t, ok, "headers", "X-Total-Count", "schema"))
+		assert.Equal(t, "Total posts", get(t, ok, "headers", "X-Total-Count", "description"))
+
+		created := get(t, doc, "paths", "/posts", "post", "responses", "201")
+		assert.Equal(t, map[string]any{"id": float64(1)}, get(t, created, "content", "application/json", "example"))
+		assert.NotContains(t, created, "examples")
+
+		// Responses without a schema have no content
+		assert.NotContains(t, get(t, doc, "paths", "/media", "post", "responses", "201"), "content")
+	})
+
+	t.Run("request bodies", func(t *testing.T) {
+		operation := get(t, doc, "paths", "/posts", "post").(map[string]any)
+		assert.NotContains(t, operation, "parameters")
+		assert.Equal(t, true, get(t, operation, "requestBody", "required"))
+		assert.Equal(t, "#/components/schemas/post.CreateRequest", get(t, operation, "requestBody", "content", "application/json", "schema", "$ref"))
+		assert.Equal(t, []any{map[string]any{"BearerAuth": []any{}}}, operation["security"])
+
+		// Forms with files are sent as multipart
+		schema := get(t, doc, "paths", "/media", "post", "requestBody", "content", "multipart/form-data", "schema")
+		assert.Equal(t, map[string]any{"type": "string", "format": "binary", "description": "File"}, get(t, schema, "properties", "file"))
+		assert.Equal(t, map[string]any{"type": "string"}, get(t, schema, "properties", "alt"))
+		assert.Equal(t, []any{"file"}, get(t, schema, "required"))
+	})
+
+	t.Run("components", func(t *testing.T) {
+		assert.Equal(t, "#/components/schemas/user.User", get(t, doc, "components", "schemas", "post.Post", "properties", "author", "$ref"))
+		assert.Equal(t, map[string]any{"type": "apiKey", "name": "Authorization", "in": "header"}, get(t, doc, "components", "securitySchemes", "BearerAuth"))
+		assert.Equal(t, map[string]any{"type": "http", "scheme": "basic"}, get(t, doc, "components", "securitySchemes", "Basic"))
+		assert.Equal(t, "https://example.com/token", get(t, doc, "components", "securitySchemes", "OAuth", "flows", "authorizationCode", "tokenUrl"))
+	})
+}
+
+func TestConvert_RelativeDocument(t *testing.T) {
+	body, err := openapi.Convert([]byte(`{"swagger": "2.0", "info": {}, "paths": {}}`))
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(body, &doc))
+	assert.NotContains(t, doc, "servers", "expected documents without a host served relative to where they are fetched")
+	assert.NotContains(t, doc, "components")
+}
+
+func TestConvert_Invalid(t *testing.T) {
+	_, err := openapi.Convert([]byte(`{"openapi": "3.0.0"}`))
+	assert.Error(t, err)
+
+	_, err = openapi.Convert([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+// The committed document must match the annotations, as genspec -check
+// verifies in CI
+func TestGenerate_DocumentUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("parses the whole module")
+	}
+	root := filepath.Join("..", "..", "..", "..")
+
+	doc, err := openapi.Generate(root, openapi.MainFile)
+	require.NoError(t, err)
+	current, err := os.ReadFile(filepath.Join(root, openapi.OutputFile))
+	require.NoError(t, err)
+	assert.True(t, string(current) == string(doc)+"\n", "%s is out of date; run go run ./cmd/genspec", openapi.OutputFile)
+}
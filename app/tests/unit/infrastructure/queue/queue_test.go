@@ -0,0 +1,172 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/queue"
+)
+
+// MockStore keeps jobs in memory for testing
+type MockStore struct {
+	jobs   map[int]*queue.Job
+	nextID int
+}
+
+func NewMockStore() *MockStore {
+	return &MockStore{jobs: make(map[int]*queue.Job), nextID: 1}
+}
+
+func (m *MockStore) Create(ctx context.Context, job *queue.Job) error {
+	job.ID = m.nextID
+	m.nextID++
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *MockStore) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*queue.Job, error) {
+	var claimed []*queue.Job
+	for id := 1; id < m.nextID && len(claimed) < limit; id++ {
+		job, ok := m.jobs[id]
+		if !ok || job.Status != queue.StatusPending || job.RunAt.After(now) {
+			continue
+		}
+		job.Status = queue.StatusRunning
+		job.Attempts++
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+func (m *MockStore) Complete(ctx context.Context, id int) error {
+	m.jobs[id].Status = queue.StatusDone
+	return nil
+}
+
+func (m *MockStore) Retry(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	m.jobs[id].Status = queue.StatusPending
+	m.jobs[id].RunAt = runAt
+	m.jobs[id].LastError = lastError
+	return nil
+}
+
+func (m *MockStore) Fail(ctx context.Context, id int, lastError string) error {
+	m.jobs[id].Status = queue.StatusFailed
+	m.jobs[id].LastError = lastError
+	return nil
+}
+
+func (m *MockStore) Depths(ctx context.Context, now time.Time) (queue.Depths, error) {
+	var depths queue.Depths
+	for _, job := range m.jobs {
+		switch job.Status {
+		case queue.StatusPending:
+			depths.Pending++
+			if !job.RunAt.After(now) {
+				depths.Due++
+			}
+		case queue.StatusRunning:
+			depths.Running++
+		case queue.StatusFailed:
+			depths.Failed++
+		}
+	}
+	return depths, nil
+}
+
+// MockLogger implements the service.Logger interface for testing
+type MockLogger struct{}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestQueue_RunDue_Success(t *testing.T) {
+	store := NewMockStore()
+	q := queue.New(store, queue.Options{}, &MockLogger{})
+	ctx := context.Background()
+
+	var received []string
+	q.Handle("greet", func(ctx context.Context, payload json.RawMessage) error {
+		var g greeting
+		require.NoError(t, json.Unmarshal(payload, &g))
+		received = append(received, g.Name)
+		return nil
+	})
+
+	require.NoError(t, q.Enqueue(ctx, "greet", greeting{Name: "Jane"}))
+	require.NoError(t, q.RunDue(ctx))
+
+	assert.Equal(t, []string{"Jane"}, received)
+	assert.Equal(t, queue.StatusDone, store.jobs[1].Status)
+	assert.Equal(t, 1, store.jobs[1].Attempts)
+
+	// Done jobs are not run again
+	require.NoError(t, q.RunDue(ctx))
+	assert.Len(t, received, 1)
+}
+
+func TestQueue_RunDue_RetriesWithBackoff(t *testing.T) {
+	store := NewMockStore()
+	q := queue.New(store, queue.Options{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}, &MockLogger{})
+	ctx := context.Background()
+
+	q.Handle("flaky", func(ctx context.Context, payload json.RawMessage) error {
+		return errors.New("engine unavailable")
+	})
+	require.NoError(t, q.Enqueue(ctx, "flaky", nil))
+
+	before := time.Now()
+	require.NoError(t, q.RunDue(ctx))
+
+	job := store.jobs[1]
+	assert.Equal(t, queue.StatusPending, job.Status)
+	assert.Equal(t, "engine unavailable", job.LastError)
+	assert.WithinDuration(t, before.Add(time.Minute), job.RunAt, 5*time.Second)
+
+	// Not due until the backoff has passed
+	require.NoError(t, q.RunDue(ctx))
+	assert.Equal(t, 1, job.Attempts)
+
+	// The last attempt marks the job failed
+	for job.Status == queue.StatusPending {
+		job.RunAt = time.Now()
+		require.NoError(t, q.RunDue(ctx))
+	}
+	assert.Equal(t, queue.StatusFailed, job.Status)
+	assert.Equal(t, 3, job.Attempts)
+}
+
+func TestQueue_RunDue_UnknownKind(t *testing.T) {
+	store := NewMockStore()
+	q := queue.New(store, queue.Options{}, &MockLogger{})
+	ctx := context.Background()
+
+	require.NoError(t, q.Enqueue(ctx, "unknown", nil))
+	require.NoError(t, q.RunDue(ctx))
+
+	assert.Equal(t, queue.StatusFailed, store.jobs[1].Status)
+	assert.Equal(t, queue.ErrNoHandler.Error(), store.jobs[1].LastError)
+}
+
+func TestQueue_Backoff(t *testing.T) {
+	q := queue.New(NewMockStore(), queue.Options{BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}, &MockLogger{})
+
+	assert.Equal(t, 30*time.Second, q.Backoff(1))
+	assert.Equal(t, time.Minute, q.Backoff(2))
+	assert.Equal(t, 2*time.Minute, q.Backoff(3))
+	assert.Equal(t, 4*time.Minute, q.Backoff(4))
+	assert.Equal(t, 5*time.Minute, q.Backoff(5))
+	assert.Equal(t, 5*time.Minute, q.Backoff(20))
+}
@@ -0,0 +1,63 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-platform/internal/infrastructure/ratelimit"
+)
+
+func TestLimit_Window(t *testing.T) {
+	limit := ratelimit.Limit{RequestsPerSecond: 10, Burst: 20}
+	if limit.Window() != 2*time.Second {
+		t.Errorf("expected a 2s window, got %v", limit.Window())
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{RequestsPerSecond: 1, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Allow(ctx, "client", limit)
+		if err != nil || !result.Allowed {
+			t.Fatalf("expected request to be allowed, got %+v (%v)", result, err)
+		}
+		if result.Remaining != i {
+			t.Errorf("expected %d remaining, got %d", i, result.Remaining)
+		}
+	}
+
+	result, err := store.Allow(ctx, "client", limit)
+	if err != nil || result.Allowed {
+		t.Fatalf("expected request to be denied, got %+v (%v)", result, err)
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > time.Second {
+		t.Errorf("expected to retry within a second, got %v", result.RetryAfter)
+	}
+
+	// Other keys have their own budget
+	if result, _ := store.Allow(ctx, "other", limit); !result.Allowed {
+		t.Error("expected other key to be allowed")
+	}
+}
+
+func TestMemoryStore_Refill(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{RequestsPerSecond: 20, Burst: 1}
+
+	if result, _ := store.Allow(ctx, "client", limit); !result.Allowed {
+		t.Fatal("expected first request to be allowed")
+	}
+	if result, _ := store.Allow(ctx, "client", limit); result.Allowed {
+		t.Fatal("expected second request to be denied")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if result, _ := store.Allow(ctx, "client", limit); !result.Allowed {
+		t.Error("expected request to be allowed after the bucket refilled")
+	}
+}
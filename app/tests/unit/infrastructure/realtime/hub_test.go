@@ -0,0 +1,166 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/realtime"
+)
+
+// MockLogger implements the service.Logger interface for testing
+type MockLogger struct{}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
+// serve starts a server whose WebSocket connections are sessions of the
+// given user and dials it
+func serve(t *testing.T, hub *realtime.Hub, userID int) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		session, err := hub.Accept(userID)
+		if err != nil {
+			ws.Close()
+			return
+		}
+		session.Serve(ws)
+	}))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHub_PushesToConnectedUsers(t *testing.T) {
+	hub := realtime.NewHub(realtime.Settings{SendBuffer: 4, WriteTimeout: time.Second}, &MockLogger{})
+	ws := serve(t, hub, 1)
+	waitFor(t, func() bool { return hub.Stats().Connections == 1 })
+
+	hub.Push(2, service.RealtimeMessage{Type: service.RealtimeFollower, Data: service.RealtimeFollowerData{FollowerID: 3}})
+	hub.Push(1, service.RealtimeMessage{Type: service.RealtimeFollower, Data: service.RealtimeFollowerData{FollowerID: 2}})
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var got struct {
+		Type string                       `json:"type"`
+		Data service.RealtimeFollowerData `json:"data"`
+	}
+	require.NoError(t, websocket.JSON.Receive(ws, &got))
+	assert.Equal(t, service.RealtimeFollower, got.Type)
+	assert.Equal(t, 2, got.Data.FollowerID)
+
+	stats := hub.Stats()
+	assert.Equal(t, realtime.Stats{Users: 1, Connections: 1, Pushed: 1}, stats)
+
+	// Closing the client ends the session
+	ws.Close()
+	waitFor(t, func() bool { return hub.Stats().Connections == 0 })
+}
+
+func TestHub_LimitsConnectionsPerUser(t *testing.T) {
+	hub := realtime.NewHub(realtime.Settings{MaxConnections: 2}, &MockLogger{})
+
+	first, err := hub.Accept(1)
+	require.NoError(t, err)
+	_, err = hub.Accept(1)
+	require.NoError(t, err)
+	_, err = hub.Accept(1)
+	assert.ErrorIs(t, err, realtime.ErrTooManyConnections)
+	_, err = hub.Accept(2)
+	assert.NoError(t, err, "the limit applies to each user")
+
+	first.Close()
+	_, err = hub.Accept(1)
+	assert.NoError(t, err, "closed sessions free their slot")
+	assert.Equal(t, 3, hub.Stats().Connections)
+}
+
+func TestHub_DropsSlowConnections(t *testing.T) {
+	hub := realtime.NewHub(realtime.Settings{SendBuffer: 2}, &MockLogger{})
+
+	// The session is never served, so its queue fills up
+	slow, err := hub.Accept(1)
+	require.NoError(t, err)
+	defer slow.Close()
+
+	msg := service.RealtimeMessage{Type: service.RealtimeFollower, Data: service.RealtimeFollowerData{FollowerID: 2}}
+	for range 3 {
+		hub.Push(1, msg)
+	}
+	assert.Equal(t, realtime.Stats{Pushed: 2, Dropped: 1}, hub.Stats())
+
+	// Later messages are not queued for the dropped connection
+	hub.Push(1, msg)
+	assert.Equal(t, int64(2), hub.Stats().Pushed)
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := realtime.NewHub(realtime.Settings{}, &MockLogger{})
+	ws := serve(t, hub, 1)
+	waitFor(t, func() bool { return hub.Stats().Connections == 1 })
+
+	// Sessions accepted but never served hold up shutdown until closed
+	pending, err := hub.Accept(2)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, hub.Shutdown(ctx), context.DeadlineExceeded)
+
+	pending.Close()
+	require.NoError(t, hub.Shutdown(context.Background()))
+	assert.Equal(t, 0, hub.Stats().Connections)
+
+	// The client sees its connection closed and new ones are refused
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var discard string
+	assert.Error(t, websocket.Message.Receive(ws, &discard))
+	_, err = hub.Accept(1)
+	assert.ErrorIs(t, err, realtime.ErrClosed)
+}
+
+func TestHub_PushEncodesMessagesAsJSON(t *testing.T) {
+	hub := realtime.NewHub(realtime.Settings{SendBuffer: 1}, &MockLogger{})
+	ws := serve(t, hub, 1)
+	waitFor(t, func() bool { return hub.Stats().Connections == 1 })
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hub.Push(1, service.RealtimeMessage{Type: service.RealtimeNotification, Data: service.RealtimeNotificationData{ID: 7, Kind: "reply", PostID: 3, CommentID: 9, ActorName: "Reader", CreatedAt: created}})
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var body string
+	require.NoError(t, websocket.Message.Receive(ws, &body))
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(body), &got))
+	assert.Equal(t, map[string]any{
+		"type": "notification",
+		"data": map[string]any{
+			"id": float64(7), "kind": "reply", "post_id": float64(3), "comment_id": float64(9),
+			"actor_name": "Reader", "read": false, "created_at": "2024-01-02T03:04:05Z",
+		},
+	}, got)
+}
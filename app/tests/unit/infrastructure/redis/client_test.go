@@ -0,0 +1,183 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"blog-platform/internal/infrastructure/redis"
+)
+
+// fakeServer is a tiny in-process Redis server supporting the commands used
+// by the application
+type fakeServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	commands [][]string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeServer{listener: listener, data: make(map[string]string)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			reply = "+PONG\r\n"
+		case "AUTH":
+			if args[1] == "secret" {
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case "SET":
+			s.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "GET":
+			if v, ok := s.data[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case "EXISTS":
+			if _, ok := s.data[args[1]]; ok {
+				reply = ":1\r\n"
+			} else {
+				reply = ":0\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		value, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(value, "\r\n")
+	}
+	return args, nil
+}
+
+func TestClient_SetGetExists(t *testing.T) {
+	server := newFakeServer(t)
+	client := redis.NewClient(server.listener.Addr().String(), "", 0)
+	defer client.Close()
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+
+	if err := client.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	value, err := client.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if value != "value" {
+		t.Errorf("expected value, got %q", value)
+	}
+
+	if _, err := client.Get(ctx, "missing"); err != redis.ErrNil {
+		t.Errorf("expected ErrNil, got %v", err)
+	}
+
+	exists, err := client.Exists(ctx, "key")
+	if err != nil || !exists {
+		t.Errorf("expected key to exist, got %v (err %v)", exists, err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	var set []string
+	for _, command := range server.commands {
+		if strings.EqualFold(command[0], "SET") {
+			set = command
+		}
+	}
+	if len(set) != 5 || !strings.EqualFold(set[3], "EX") || set[4] != "60" {
+		t.Errorf("expected SET with EX 60, got %v", set)
+	}
+}
+
+func TestClient_ErrorReplies(t *testing.T) {
+	server := newFakeServer(t)
+	ctx := context.Background()
+
+	client := redis.NewClient(server.listener.Addr().String(), "", 0)
+	defer client.Close()
+
+	_, err := client.Do(ctx, "FLUSHALL")
+	if _, ok := err.(*redis.ReplyError); !ok {
+		t.Fatalf("expected reply error, got %v", err)
+	}
+
+	// The connection stays usable after an error reply
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("expected ping to succeed, got %v", err)
+	}
+
+	badAuth := redis.NewClient(server.listener.Addr().String(), "wrong", 0)
+	defer badAuth.Close()
+	if err := badAuth.Ping(ctx); err == nil {
+		t.Error("expected authentication to fail")
+	}
+}
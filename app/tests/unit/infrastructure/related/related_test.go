@@ -0,0 +1,72 @@
+package related_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/related"
+)
+
+func publishedPost(id int, title string) *post.Post {
+	return &post.Post{ID: id, Title: title, Status: post.StatusPublished}
+}
+
+func ids(posts []*post.Post) []int {
+	result := make([]int, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, p.ID)
+	}
+	return result
+}
+
+func TestSimilar(t *testing.T) {
+	source := publishedPost(1, "Getting started with Go generics")
+	candidates := []*post.Post{
+		source,
+		publishedPost(2, "Baking sourdough bread"),
+		publishedPost(3, "Go generics in depth"),
+		publishedPost(4, "Getting into gardening"),
+		publishedPost(5, "Advanced Go concurrency"),
+		{ID: 6, Title: "Go generics draft", Status: post.StatusDraft},
+	}
+
+	// Rarer shared words count more; unrelated posts, drafts and the post
+	// itself are left out
+	assert.Equal(t, []int{3, 4, 5}, ids(related.Similar(source, candidates, 10)))
+	assert.Equal(t, []int{3}, ids(related.Similar(source, candidates, 1)))
+
+	// Titles of stop words only relate to nothing
+	assert.Empty(t, related.Similar(publishedPost(7, "How to"), candidates, 10))
+}
+
+// fixedStrategy returns the same posts whatever post is asked about
+type fixedStrategy []*post.Post
+
+func (s fixedStrategy) Related(ctx context.Context, p *post.Post, limit int) ([]*post.Post, error) {
+	return s, nil
+}
+
+func TestChain(t *testing.T) {
+	source := publishedPost(1, "Source")
+	a, b, c := publishedPost(2, "A"), publishedPost(3, "B"), publishedPost(4, "C")
+	chain := related.Chain{fixedStrategy{a, b}, fixedStrategy{source, b, c}}
+
+	found, err := chain.Related(context.Background(), source, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4}, ids(found), "duplicates and the post itself are skipped")
+
+	found, err = chain.Related(context.Background(), source, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, ids(found), "later strategies are not needed once the limit is reached")
+}
+
+func TestTagStrategy_Untagged(t *testing.T) {
+	// Untagged posts share no tags; the repository is not consulted
+	found, err := related.NewTagStrategy(nil).Related(context.Background(), publishedPost(1, "Untagged"), 5)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -0,0 +1,75 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/scheduler"
+)
+
+// MockLogger implements the service.Logger interface for testing
+type MockLogger struct{}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
+func TestScheduler_Shutdown_FinishesRunningJobs(t *testing.T) {
+	s := scheduler.New(&MockLogger{})
+
+	started := make(chan struct{}, 1)
+	var finished, interrupted atomic.Bool
+	s.Every("slow", time.Millisecond, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-time.After(50 * time.Millisecond):
+			finished.Store(true)
+		case <-ctx.Done():
+			interrupted.Store(true)
+		}
+		return nil
+	})
+	s.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, s.Shutdown(ctx))
+
+	assert.True(t, finished.Load(), "the running job completes")
+	assert.False(t, interrupted.Load())
+}
+
+func TestScheduler_Shutdown_CancelsAtDeadline(t *testing.T) {
+	s := scheduler.New(&MockLogger{})
+
+	started := make(chan struct{}, 1)
+	cancelled := make(chan struct{})
+	s.Every("stuck", time.Millisecond, func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+	s.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Shutdown(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the running job to be cancelled")
+	}
+}
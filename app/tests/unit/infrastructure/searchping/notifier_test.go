@@ -0,0 +1,87 @@
+package searchping_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/application/service"
+	"blog-platform/internal/infrastructure/searchping"
+)
+
+// MockLogger implements the service.Logger interface for testing
+type MockLogger struct{}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
+func pingPayload(t *testing.T) json.RawMessage {
+	t.Helper()
+	payload, err := json.Marshal(service.SearchPingPayload{PostID: 1, Slug: "hello-world"})
+	require.NoError(t, err)
+	return payload
+}
+
+func TestNotifier_Handle(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := searchping.NewNotifier(searchping.Settings{
+		BaseURL:          "https://blog.example.com",
+		IndexNowKey:      "abc123",
+		IndexNowEndpoint: server.URL + "/indexnow",
+		SitemapURL:       "https://blog.example.com/sitemap.xml",
+		SitemapPingURLs:  []string{server.URL + "/ping?sitemap="},
+	}, server.Client(), &MockLogger{})
+
+	require.NoError(t, notifier.Handle(context.Background(), pingPayload(t)))
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, "/indexnow", requests[0].URL.Path)
+	assert.Equal(t, "https://blog.example.com/p/hello-world", requests[0].URL.Query().Get("url"))
+	assert.Equal(t, "abc123", requests[0].URL.Query().Get("key"))
+
+	assert.Equal(t, "/ping", requests[1].URL.Path)
+	assert.Equal(t, "https://blog.example.com/sitemap.xml", requests[1].URL.Query().Get("sitemap"))
+}
+
+func TestNotifier_Handle_FailureIsRetried(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ping" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := searchping.NewNotifier(searchping.Settings{
+		BaseURL:          "https://blog.example.com",
+		IndexNowKey:      "abc123",
+		IndexNowEndpoint: server.URL + "/indexnow",
+		SitemapURL:       "https://blog.example.com/sitemap.xml",
+		SitemapPingURLs:  []string{server.URL + "/ping?sitemap="},
+	}, server.Client(), &MockLogger{})
+
+	err := notifier.Handle(context.Background(), pingPayload(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sitemap ping")
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestNotifier_Handle_NothingConfigured(t *testing.T) {
+	notifier := searchping.NewNotifier(searchping.Settings{BaseURL: "https://blog.example.com"}, http.DefaultClient, &MockLogger{})
+
+	assert.NoError(t, notifier.Handle(context.Background(), pingPayload(t)))
+}
@@ -0,0 +1,96 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/shutdown"
+)
+
+// MockLogger records the messages logged during shutdown
+type MockLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (m *MockLogger) record(msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  { m.record(msg) }
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) { m.record(msg) }
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  { m.record(msg) }
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) { m.record(msg) }
+
+func TestRegistry_Shutdown_ReverseOrder(t *testing.T) {
+	logger := &MockLogger{}
+	registry := shutdown.New(time.Second, logger)
+
+	var order []string
+	for _, name := range []string{"database", "scheduler", "http-server"} {
+		registry.Register(name, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	require.NoError(t, registry.Shutdown(context.Background()))
+	assert.Equal(t, []string{"http-server", "scheduler", "database"}, order)
+	assert.Contains(t, logger.messages, "shutdown hook finished")
+
+	// Hooks run once
+	require.NoError(t, registry.Shutdown(context.Background()))
+	assert.Len(t, order, 3)
+}
+
+func TestRegistry_Shutdown_ContinuesAfterFailures(t *testing.T) {
+	registry := shutdown.New(time.Second, &MockLogger{})
+
+	ran := false
+	registry.Register("database", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	registry.Register("cache", func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+
+	err := registry.Shutdown(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "cache: flush failed", err.Error())
+	assert.True(t, ran, "later hooks still run")
+}
+
+func TestRegistry_Shutdown_HookTimeout(t *testing.T) {
+	logger := &MockLogger{}
+	registry := shutdown.New(20*time.Millisecond, logger)
+
+	var deadline bool
+	registry.Register("database", func(ctx context.Context) error {
+		_, deadline = ctx.Deadline()
+		return nil
+	})
+	// A hook ignoring its context is abandoned rather than waited for
+	block := make(chan struct{})
+	defer close(block)
+	registry.Register("worker", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	started := time.Now()
+	err := registry.Shutdown(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.True(t, deadline, "hooks are given the hook timeout")
+	assert.Contains(t, logger.messages, "shutdown hook timed out")
+}
@@ -0,0 +1,46 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/infrastructure/storage"
+)
+
+func TestLocalStorage_PutDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := storage.NewLocalStorage(dir, "http://localhost:8080/uploads/")
+
+	require.NoError(t, s.Put(ctx, "avatars/1/abc-64.jpg", strings.NewReader("image"), 5, "image/jpeg"))
+	data, err := os.ReadFile(filepath.Join(dir, "avatars", "1", "abc-64.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+	assert.Equal(t, "http://localhost:8080/uploads/avatars/1/abc-64.jpg", s.URL("avatars/1/abc-64.jpg"))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "avatars", "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+
+	require.NoError(t, s.Delete(ctx, "avatars/1/abc-64.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "avatars", "1", "abc-64.jpg"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.NoError(t, s.Delete(ctx, "avatars/1/abc-64.jpg"), "missing objects are not an error")
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	s := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/uploads")
+
+	for _, key := range []string{"", "../escape.jpg", "avatars/../../escape.jpg", "/absolute.jpg", "avatars/"} {
+		assert.ErrorIs(t, s.Put(ctx, key, strings.NewReader("x"), 1, "image/jpeg"), storage.ErrInvalidKey, key)
+		assert.ErrorIs(t, s.Delete(ctx, key), storage.ErrInvalidKey, key)
+	}
+}
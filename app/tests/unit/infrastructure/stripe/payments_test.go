@@ -0,0 +1,135 @@
+package stripe_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/tip"
+	"blog-platform/internal/infrastructure/stripe"
+)
+
+// newPaymentProvider creates a provider against a fake Stripe API
+func newPaymentProvider(t *testing.T, handler http.HandlerFunc) *stripe.PaymentProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	webhooks := stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, nil)
+	return stripe.NewPaymentProvider("sk_test", server.URL, webhooks, server.Client())
+}
+
+func TestPaymentProvider_CreateCheckout(t *testing.T) {
+	provider := newPaymentProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/checkout/sessions", r.URL.Path)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		assert.Equal(t, "tip-42", r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "payment", r.PostForm.Get("mode"))
+		assert.Equal(t, "500", r.PostForm.Get("line_items[0][price_data][unit_amount]"))
+		assert.Equal(t, "eur", r.PostForm.Get("line_items[0][price_data][currency]"))
+		assert.Equal(t, "acct_author", r.PostForm.Get("payment_intent_data[transfer_data][destination]"))
+		assert.Equal(t, "42", r.PostForm.Get("metadata[tip_id]"))
+		assert.Equal(t, "https://blog.example.com/p/post?tip=42", r.PostForm.Get("success_url"))
+		fmt.Fprint(w, `{"id": "cs_test_1", "url": "https://checkout.stripe.com/c/pay/cs_test_1"}`)
+	})
+
+	checkout, err := provider.CreateCheckout(context.Background(), tip.CheckoutRequest{
+		TipID:       42,
+		PostTitle:   "Post",
+		Amount:      500,
+		Currency:    "EUR",
+		Destination: "acct_author",
+		ReturnURL:   "https://blog.example.com/p/post?tip=42",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cs_test_1", checkout.PaymentID)
+	assert.Equal(t, "https://checkout.stripe.com/c/pay/cs_test_1", checkout.URL)
+}
+
+func TestPaymentProvider_CreateCheckout_APIError(t *testing.T) {
+	provider := newPaymentProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": {"type": "invalid_request_error", "message": "No such destination"}}`)
+	})
+
+	_, err := provider.CreateCheckout(context.Background(), tip.CheckoutRequest{TipID: 1, Amount: 500, Currency: "usd"})
+	assert.ErrorIs(t, err, stripe.ErrAPI)
+	assert.Contains(t, err.Error(), "No such destination")
+}
+
+func TestPaymentProvider_VerifyAccount(t *testing.T) {
+	accounts := map[string]string{
+		"acct_ready":   `{"id": "acct_ready", "charges_enabled": true}`,
+		"acct_pending": `{"id": "acct_pending", "charges_enabled": false}`,
+	}
+	provider := newPaymentProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		body, ok := accounts[r.URL.Path[len("/v1/accounts/"):]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"message": "No such account"}}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	})
+
+	assert.NoError(t, provider.VerifyAccount(context.Background(), "acct_ready"))
+	for _, accountID := range []string{"acct_pending", "acct_missing", "ba_123"} {
+		assert.ErrorIs(t, provider.VerifyAccount(context.Background(), accountID), tip.ErrInvalidAccount, accountID)
+	}
+}
+
+// checkoutPayload builds a checkout session event
+func checkoutPayload(eventType, paymentStatus, metadata string) string {
+	return fmt.Sprintf(`{
+		"id": "evt_1",
+		"type": %q,
+		"data": {"object": {"id": "cs_test_1", "payment_status": %q, "metadata": %s}}
+	}`, eventType, paymentStatus, metadata)
+}
+
+func TestPaymentProvider_ParseEvent(t *testing.T) {
+	provider := stripe.NewPaymentProvider("sk_test", stripe.DefaultAPIURL, stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, nil), nil)
+	parse := func(payload string) (*tip.PaymentEvent, error) {
+		return provider.ParseEvent([]byte(payload), sign(testSecret, time.Now(), payload))
+	}
+
+	event, err := parse(checkoutPayload("checkout.session.completed", "paid", `{"tip_id": "42"}`))
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, tip.PaymentEvent{EventID: "evt_1", TipID: 42, PaymentID: "cs_test_1", Status: tip.StatusSucceeded}, *event)
+
+	event, err = parse(checkoutPayload("checkout.session.async_payment_succeeded", "paid", `{"tip_id": "42"}`))
+	require.NoError(t, err)
+	assert.Equal(t, tip.StatusSucceeded, event.Status)
+
+	for _, eventType := range []string{"checkout.session.async_payment_failed", "checkout.session.expired"} {
+		event, err = parse(checkoutPayload(eventType, "unpaid", `{"tip_id": "42"}`))
+		require.NoError(t, err)
+		assert.Equal(t, tip.StatusFailed, event.Status, eventType)
+	}
+
+	ignored := map[string]string{
+		"processing payment": checkoutPayload("checkout.session.completed", "unpaid", `{"tip_id": "42"}`),
+		"not a tip":          checkoutPayload("checkout.session.completed", "paid", `{}`),
+		"other event":        `{"id": "evt_2", "type": "invoice.paid", "data": {"object": {}}}`,
+	}
+	for name, payload := range ignored {
+		event, err := parse(payload)
+		assert.NoError(t, err, name)
+		assert.Nil(t, event, name)
+	}
+
+	_, err = parse(checkoutPayload("checkout.session.completed", "paid", `{"tip_id": "abc"}`))
+	assert.ErrorIs(t, err, tip.ErrInvalidEvent)
+
+	payload := checkoutPayload("checkout.session.completed", "paid", `{"tip_id": "42"}`)
+	_, err = provider.ParseEvent([]byte(payload), sign("whsec_other", time.Now(), payload))
+	assert.ErrorIs(t, err, tip.ErrInvalidSignature)
+}
@@ -0,0 +1,124 @@
+package stripe_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/billing"
+	"blog-platform/internal/infrastructure/stripe"
+)
+
+const testSecret = "whsec_test"
+
+// sign builds a Stripe-Signature header for a payload
+func sign(secret string, at time.Time, payload string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// subscriptionPayload builds a subscription event with one price
+func subscriptionPayload(eventType, price, lookupKey, metadata string) string {
+	return fmt.Sprintf(`{
+		"id": "evt_1",
+		"type": %q,
+		"created": 1705312800,
+		"data": {"object": {
+			"id": "sub_1",
+			"customer": "cus_1",
+			"status": "active",
+			"cancel_at_period_end": true,
+			"metadata": %s,
+			"items": {"data": [{"current_period_end": 1707991200, "price": {"id": %q, "lookup_key": %q}}]}
+		}}
+	}`, eventType, metadata, price, lookupKey)
+}
+
+func TestWebhookVerifier_ParseEvent(t *testing.T) {
+	verifier := stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, map[string]string{"price_pro": "pro"})
+	payload := subscriptionPayload("customer.subscription.updated", "price_pro", "", `{"user_id": "7"}`)
+
+	event, err := verifier.ParseEvent([]byte(payload), sign(testSecret, time.Now(), payload))
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, "evt_1", event.EventID)
+	assert.Equal(t, 7, event.UserID)
+	assert.Equal(t, "cus_1", event.StripeCustomerID)
+	assert.Equal(t, "sub_1", event.StripeSubscriptionID)
+	assert.Equal(t, billing.PlanPro, event.Plan)
+	assert.Equal(t, billing.StatusActive, event.Status)
+	assert.True(t, event.CancelAtPeriodEnd)
+	assert.Equal(t, time.Unix(1705312800, 0), event.OccurredAt)
+	require.NotNil(t, event.CurrentPeriodEnd)
+	assert.Equal(t, time.Unix(1707991200, 0), *event.CurrentPeriodEnd)
+}
+
+func TestWebhookVerifier_ParseEvent_LookupKeyAndDeletion(t *testing.T) {
+	verifier := stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, nil)
+	payload := subscriptionPayload("customer.subscription.deleted", "price_unmapped", "org", `{}`)
+
+	event, err := verifier.ParseEvent([]byte(payload), sign(testSecret, time.Now(), payload))
+	require.NoError(t, err)
+	assert.Equal(t, billing.PlanOrg, event.Plan)
+	assert.Equal(t, billing.StatusCanceled, event.Status, "deleted subscriptions are canceled")
+	assert.Zero(t, event.UserID)
+}
+
+func TestWebhookVerifier_ParseEvent_IgnoresOtherEvents(t *testing.T) {
+	verifier := stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, nil)
+	payload := `{"id": "evt_2", "type": "invoice.paid", "data": {"object": {}}}`
+
+	event, err := verifier.ParseEvent([]byte(payload), sign(testSecret, time.Now(), payload))
+	require.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestWebhookVerifier_ParseEvent_Rejects(t *testing.T) {
+	verifier := stripe.NewWebhookVerifier(testSecret, stripe.DefaultTolerance, map[string]string{"price_pro": "pro"})
+	payload := subscriptionPayload("customer.subscription.created", "price_pro", "", `{}`)
+	now := time.Now()
+
+	signatures := map[string]string{
+		"missing":     "",
+		"no v1":       "t=" + strconv.FormatInt(now.Unix(), 10),
+		"wrong key":   sign("whsec_other", now, payload),
+		"other body":  sign(testSecret, now, payload+" "),
+		"too old":     sign(testSecret, now.Add(-stripe.DefaultTolerance-time.Minute), payload),
+		"future":      sign(testSecret, now.Add(stripe.DefaultTolerance+time.Minute), payload),
+		"not hex":     "t=" + strconv.FormatInt(now.Unix(), 10) + ",v1=zz",
+		"bad seconds": "t=soon,v1=00",
+	}
+	for name, signature := range signatures {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.ParseEvent([]byte(payload), signature)
+			assert.ErrorIs(t, err, billing.ErrInvalidSignature)
+		})
+	}
+
+	// Rotated secrets send several signatures; one match is enough
+	rotated := sign(testSecret, now, payload) + ",v1=" + hex.EncodeToString([]byte("stale"))
+	_, err := verifier.ParseEvent([]byte(payload), rotated)
+	assert.NoError(t, err)
+
+	events := map[string]string{
+		"unmapped price": subscriptionPayload("customer.subscription.created", "price_other", "", `{}`),
+		"bad user ID":    subscriptionPayload("customer.subscription.created", "price_pro", "", `{"user_id": "abc"}`),
+		"no items":       `{"id": "evt_3", "type": "customer.subscription.created", "data": {"object": {"id": "sub_1", "customer": "cus_1", "items": {"data": []}}}}`,
+		"not json":       `{`,
+	}
+	for name, body := range events {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.ParseEvent([]byte(body), sign(testSecret, now, body))
+			assert.ErrorIs(t, err, billing.ErrInvalidEvent)
+		})
+	}
+}
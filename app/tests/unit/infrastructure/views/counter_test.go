@@ -0,0 +1,82 @@
+package views_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/post"
+	"blog-platform/internal/infrastructure/views"
+)
+
+type MockLogger struct{}
+
+func (m *MockLogger) Info(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Error(ctx context.Context, msg string, args ...any) {}
+func (m *MockLogger) Warn(ctx context.Context, msg string, args ...any)  {}
+func (m *MockLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
+// MockStore records the batches written, failing while err is set
+type MockStore struct {
+	batches [][]post.ViewCount
+	err     error
+}
+
+func (m *MockStore) AddViews(ctx context.Context, counts []post.ViewCount) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.batches = append(m.batches, counts)
+	return nil
+}
+
+func TestCounter_FlushWritesOneBatch(t *testing.T) {
+	store := &MockStore{}
+	counter := views.New(store, &MockLogger{})
+
+	// Nothing to write
+	require.NoError(t, counter.Flush(context.Background()))
+	assert.Empty(t, store.batches)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			counter.Record(i%2 + 1)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, views.Stats{Posts: 2, Views: 100}, counter.Stats())
+
+	require.NoError(t, counter.Flush(context.Background()))
+	require.Len(t, store.batches, 1)
+	today := post.ViewDay(time.Now())
+	assert.Equal(t, []post.ViewCount{
+		{PostID: 1, Day: today, Views: 50},
+		{PostID: 2, Day: today, Views: 50},
+	}, store.batches[0])
+	assert.Equal(t, views.Stats{}, counter.Stats())
+}
+
+func TestCounter_FailedFlushKeepsViews(t *testing.T) {
+	store := &MockStore{err: errors.New("database is down")}
+	counter := views.New(store, &MockLogger{})
+
+	counter.Record(1)
+	counter.Record(1)
+	require.Error(t, counter.Flush(context.Background()))
+	assert.Equal(t, views.Stats{Posts: 1, Views: 2}, counter.Stats())
+
+	// Views counted meanwhile are written with the ones put back
+	counter.Record(1)
+	store.err = nil
+	require.NoError(t, counter.Flush(context.Background()))
+	require.Len(t, store.batches, 1)
+	assert.Equal(t, int64(3), store.batches[0][0].Views)
+}
@@ -0,0 +1,72 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"blog-platform/internal/domain/webhook"
+	"blog-platform/internal/infrastructure/webhooks"
+)
+
+func TestSender_SendsSignedPayload(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := &webhook.Webhook{ID: 1, URL: server.URL + "/hooks", Secret: "whsec_test"}
+	delivery := &webhook.Delivery{ID: 42, Event: webhook.EventPostPublished, Payload: `{"event":"post.published","data":{"id":7}}`}
+
+	status, err := webhooks.NewSender(server.Client()).Send(context.Background(), hook, delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/hooks", got.URL.Path)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, webhook.EventPostPublished, got.Header.Get(webhooks.HeaderEvent))
+	assert.Equal(t, "42", got.Header.Get(webhooks.HeaderDelivery))
+	assert.Equal(t, delivery.Payload, string(body))
+
+	// Receivers verify the signature by recomputing it for the timestamp sent
+	signature := got.Header.Get(webhooks.HeaderSignature)
+	timestamp, _, ok := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	require.True(t, ok, "expected t=...,v1=... in %q", signature)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(unix, 0), time.Minute)
+	assert.Equal(t, webhook.Sign("whsec_test", time.Unix(unix, 0), body), signature)
+	assert.NotEqual(t, webhook.Sign("whsec_other", time.Unix(unix, 0), body), signature)
+}
+
+func TestSender_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := &webhook.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test"}
+	status, err := webhooks.NewSender(server.Client()).Send(context.Background(), hook, &webhook.Delivery{ID: 1, Payload: `{}`})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", webhook.Sign("secret", at, []byte("{}")))
+}